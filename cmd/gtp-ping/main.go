@@ -0,0 +1,248 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Command gtp-ping sends the GTPv1-U, GTPv1-C or GTPv2-C Echo Requests to a peer and
+// reports the round-trip time of the Echo Responses, like ping(8) does with ICMP.
+//
+// It is useful to validate the firewall and routing on the paths like S1-U and S11
+// without setting up any session.
+//
+//	// GTPv2-C Echo to the S-GW, 5 times.
+//	gtp-ping -c 5 192.168.0.2
+//
+//	// GTPv1-U Echo to the eNB.
+//	gtp-ping -p v1u 192.168.0.3
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/wmnsk/go-gtp/internal/echo"
+)
+
+// command-line arguments
+var (
+	proto    = flag.String("p", "v2c", "Protocol to send Echo Request with: v1u, v1c or v2c.")
+	port     = flag.Int("port", 0, "Destination port. The well-known port of the protocol is used if 0.")
+	count    = flag.Int("c", 0, "Number of Echo Requests to send. Keeps sending until interrupted if 0.")
+	interval = flag.Duration("i", time.Second, "Interval between Echo Requests.")
+	timeout  = flag.Duration("W", 3*time.Second, "Time to wait for Echo Response after the last Echo Request.")
+	laddr    = flag.String("l", "", "Local IP Address:Port to send Echo Requests from.")
+	recovery = flag.Uint("r", 0, "Restart Counter in the Recovery IE of GTPv2-C Echo Request.")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] host\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	log.SetFlags(0)
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	p, err := echo.ParseProtocol(*proto)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *port == 0 {
+		*port = p.Port()
+	}
+
+	raddr, err := net.ResolveUDPAddr("udp", net.JoinHostPort(flag.Arg(0), strconv.Itoa(*port)))
+	if err != nil {
+		log.Fatal(err)
+	}
+	var local *net.UDPAddr
+	if *laddr != "" {
+		if local, err = net.ResolveUDPAddr("udp", *laddr); err != nil {
+			log.Fatal(err)
+		}
+	}
+	conn, err := net.DialUDP("udp", local, raddr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+
+	pinger := &pinger{proto: p, conn: conn, sent: map[uint32]time.Time{}}
+	go pinger.receive()
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+
+	log.Printf("%s ECHO %s from %s", p, raddr, conn.LocalAddr())
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+loop:
+	for seq := uint32(1); *count == 0 || int(seq) <= *count; seq++ {
+		if err := pinger.send(seq % (p.MaxSequence() + 1)); err != nil {
+			log.Printf("failed to send Echo Request: %v", err)
+		}
+		if *count != 0 && int(seq) == *count {
+			break
+		}
+		select {
+		case <-ticker.C:
+		case <-sig:
+			break loop
+		}
+	}
+
+	select {
+	case <-pinger.waitAll(*timeout):
+	case <-sig:
+	}
+	pinger.summary(raddr)
+
+	if pinger.received() == 0 {
+		os.Exit(1)
+	}
+}
+
+type pinger struct {
+	proto echo.Protocol
+	conn  *net.UDPConn
+
+	mu   sync.Mutex
+	sent map[uint32]time.Time
+	rtts []time.Duration
+	nTx  int
+}
+
+func (p *pinger) send(seq uint32) error {
+	b, err := echo.Request(p.proto, seq, uint8(*recovery))
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.sent[seq] = time.Now()
+	p.nTx++
+	p.mu.Unlock()
+
+	_, err = p.conn.Write(b)
+	return err
+}
+
+func (p *pinger) receive() {
+	buf := make([]byte, 1500)
+	for {
+		n, err := p.conn.Read(buf)
+		if err != nil {
+			// ICMP Port Unreachable from the peer is reported as the error on the
+			// connected socket, which should not stop receiving.
+			if errors.Is(err, syscall.ECONNREFUSED) {
+				log.Printf("from %s: port unreachable", p.conn.RemoteAddr())
+				continue
+			}
+			return
+		}
+		arrived := time.Now()
+
+		rsp, err := echo.ParseResponse(p.proto, buf[:n])
+		if err != nil {
+			log.Printf("ignored %d bytes: %v", n, err)
+			continue
+		}
+
+		p.mu.Lock()
+		sentAt, ok := p.sent[rsp.Sequence]
+		if ok {
+			delete(p.sent, rsp.Sequence)
+			p.rtts = append(p.rtts, arrived.Sub(sentAt))
+		}
+		p.mu.Unlock()
+
+		if !ok {
+			log.Printf("ignored Echo Response with unknown seq=%d", rsp.Sequence)
+			continue
+		}
+		line := fmt.Sprintf("%d bytes from %s: seq=%d time=%s", n, p.conn.RemoteAddr(), rsp.Sequence, rtt(arrived.Sub(sentAt)))
+		if rsp.HasRecovery {
+			line += fmt.Sprintf(" recovery=%d", rsp.Recovery)
+		}
+		log.Print(line)
+	}
+}
+
+// waitAll returns the channel that is closed when all the Echo Requests are answered
+// or timeout expires.
+func (p *pinger) waitAll(timeout time.Duration) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		deadline := time.Now().Add(timeout)
+		for time.Now().Before(deadline) {
+			p.mu.Lock()
+			n := len(p.sent)
+			p.mu.Unlock()
+			if n == 0 {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+	return done
+}
+
+func (p *pinger) received() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.rtts)
+}
+
+func (p *pinger) summary(raddr net.Addr) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	nRx := len(p.rtts)
+	loss := 0.0
+	if p.nTx > 0 {
+		loss = float64(p.nTx-nRx) / float64(p.nTx) * 100
+	}
+	log.Printf("\n--- %s %s echo statistics ---", raddr, p.proto)
+	log.Printf("%d requests transmitted, %d responses received, %.1f%% loss", p.nTx, nRx, loss)
+	if nRx == 0 {
+		return
+	}
+
+	min, max := p.rtts[0], p.rtts[0]
+	var sum float64
+	for _, d := range p.rtts {
+		if d < min {
+			min = d
+		}
+		if d > max {
+			max = d
+		}
+		sum += float64(d)
+	}
+	avg := sum / float64(nRx)
+	var sq float64
+	for _, d := range p.rtts {
+		sq += (float64(d) - avg) * (float64(d) - avg)
+	}
+	mdev := math.Sqrt(sq / float64(nRx))
+	log.Printf("rtt min/avg/max/mdev = %s/%s/%s/%s", rtt(min), rtt(time.Duration(avg)), rtt(max), rtt(time.Duration(mdev)))
+}
+
+// rtt formats d in milliseconds like ping(8).
+func rtt(d time.Duration) string {
+	return fmt.Sprintf("%.3fms", float64(d)/float64(time.Millisecond))
+}
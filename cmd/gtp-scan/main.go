@@ -0,0 +1,228 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Command gtp-scan probes the hosts in a subnet with the GTPv1-U, GTPv1-C or GTPv2-C
+// Echo Request, and lists the ones that respond with the Echo Response.
+//
+// It is useful to find out which nodes are reachable over GTP through the firewall
+// and routing on the paths like S1-U and S11. Only scan the networks you are
+// authorized to.
+//
+//	// GTPv2-C Echo to the hosts in 192.168.0.0/24, 100 requests per second.
+//	gtp-scan 192.168.0.0/24
+//
+//	// GTPv1-U Echo to the hosts in 10.0.0.0/28.
+//	gtp-scan -p v1u 10.0.0.0/28
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/go-gtp/internal/echo"
+)
+
+// maxHosts is the maximum number of hosts to be scanned at once, which is the size
+// of /16 in IPv4.
+const maxHosts = 1 << 16
+
+// command-line arguments
+var (
+	proto    = flag.String("p", "v2c", "Protocol to send Echo Request with: v1u, v1c or v2c.")
+	port     = flag.Int("port", 0, "Destination port. The well-known port of the protocol is used if 0.")
+	rate     = flag.Int("rate", 100, "Number of Echo Requests to send per second.")
+	timeout  = flag.Duration("W", 3*time.Second, "Time to wait for Echo Responses after the last Echo Request.")
+	laddr    = flag.String("l", "", "Local IP Address:Port to send Echo Requests from.")
+	recovery = flag.Uint("r", 0, "Restart Counter in the Recovery IE of GTPv2-C Echo Request.")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] cidr\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	log.SetFlags(0)
+
+	if flag.NArg() != 1 || *rate <= 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	p, err := echo.ParseProtocol(*proto)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *port == 0 {
+		*port = p.Port()
+	}
+	hosts, err := hostsIn(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	local := &net.UDPAddr{}
+	if *laddr != "" {
+		if local, err = net.ResolveUDPAddr("udp", *laddr); err != nil {
+			log.Fatal(err)
+		}
+	}
+	conn, err := net.ListenUDP("udp", local)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+
+	s := &scanner{proto: p, conn: conn, sent: map[string]time.Time{}, found: map[string]*result{}}
+	go s.receive()
+
+	log.Printf("Scanning %d hosts in %s with %s Echo on port %d", len(hosts), flag.Arg(0), p, *port)
+	ticker := time.NewTicker(time.Second / time.Duration(*rate))
+	defer ticker.Stop()
+	for n, ip := range hosts {
+		raddr := &net.UDPAddr{IP: ip, Port: *port}
+		if err := s.send(raddr, uint32(n)%(p.MaxSequence()+1)); err != nil {
+			log.Printf("failed to send Echo Request to %s: %v", raddr, err)
+		}
+		<-ticker.C
+	}
+	time.Sleep(*timeout)
+
+	results := s.results()
+	for _, r := range results {
+		line := fmt.Sprintf("%s\ttime=%.3fms", r.addr, float64(r.rtt)/float64(time.Millisecond))
+		if r.hasRecovery {
+			line += fmt.Sprintf("\trecovery=%d", r.recovery)
+		}
+		fmt.Println(line)
+	}
+	log.Printf("%d of %d hosts responded", len(results), len(hosts))
+}
+
+// hostsIn returns the IP addresses in the subnet given in CIDR notation, except the
+// network and broadcast addresses of IPv4 subnets larger than /31. A single IP
+// address is also accepted.
+func hostsIn(cidr string) ([]net.IP, error) {
+	if ip := net.ParseIP(cidr); ip != nil {
+		return []net.IP{ip}, nil
+	}
+	ip, ipnet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, err
+	}
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+
+	ones, bits := ipnet.Mask.Size()
+	if bits-ones > 16 {
+		return nil, fmt.Errorf("%s has too many hosts: should be up to %d", cidr, maxHosts)
+	}
+
+	var hosts []net.IP
+	for cur := ip.Mask(ipnet.Mask); ipnet.Contains(cur); cur = next(cur) {
+		hosts = append(hosts, cur)
+		if len(hosts) == maxHosts {
+			break
+		}
+	}
+	if bits == 32 && bits-ones > 1 {
+		hosts = hosts[1 : len(hosts)-1]
+	}
+	return hosts, nil
+}
+
+// next returns the IP address next to ip. It wraps around to all zeros after the
+// largest one.
+func next(ip net.IP) net.IP {
+	n := make(net.IP, len(ip))
+	copy(n, ip)
+	for i := len(n) - 1; i >= 0; i-- {
+		n[i]++
+		if n[i] != 0 {
+			break
+		}
+	}
+	return n
+}
+
+type result struct {
+	addr        *net.UDPAddr
+	rtt         time.Duration
+	recovery    uint8
+	hasRecovery bool
+}
+
+type scanner struct {
+	proto echo.Protocol
+	conn  *net.UDPConn
+
+	mu    sync.Mutex
+	sent  map[string]time.Time
+	found map[string]*result
+}
+
+func (s *scanner) send(raddr *net.UDPAddr, seq uint32) error {
+	b, err := echo.Request(s.proto, seq, uint8(*recovery))
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.sent[raddr.IP.String()] = time.Now()
+	s.mu.Unlock()
+
+	_, err = s.conn.WriteToUDP(b, raddr)
+	return err
+}
+
+func (s *scanner) receive() {
+	buf := make([]byte, 1500)
+	for {
+		n, raddr, err := s.conn.ReadFromUDP(buf)
+		if err != nil {
+			return
+		}
+		arrived := time.Now()
+
+		// the hosts are identified by the IP address, as the Echo Response may come
+		// from the port other than the one Echo Request is sent to.
+		rsp, err := echo.ParseResponse(s.proto, buf[:n])
+		if err != nil {
+			continue
+		}
+		key := raddr.IP.String()
+
+		s.mu.Lock()
+		sentAt, ok := s.sent[key]
+		if ok {
+			delete(s.sent, key)
+			s.found[key] = &result{
+				addr: raddr, rtt: arrived.Sub(sentAt), recovery: rsp.Recovery, hasRecovery: rsp.HasRecovery,
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// results returns the hosts that responded, sorted by the IP address.
+func (s *scanner) results() []*result {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var results []*result
+	for _, r := range s.found {
+		results = append(results, r)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return bytes.Compare(results[i].addr.IP.To16(), results[j].addr.IP.To16()) < 0
+	})
+	return results
+}
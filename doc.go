@@ -7,7 +7,7 @@
 //
 // Examples for specific node are available in examples directory, which can be  as it is
 // in the following way.
-// As for the detailed usage as a package, see v0/v1/v2 directory for what you can do with
+// As for the detailed usage as a package, see v0/v1/v2/prime directory for what you can do with
 // the current implementation.
 //
 // 1. Open four terminals on the same machine and start capturing on loopback interface.
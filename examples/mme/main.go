@@ -30,7 +30,6 @@ import (
 	"fmt"
 	"log"
 	"net"
-	"strings"
 	"sync"
 	"time"
 
@@ -78,6 +77,9 @@ func main() {
 	defer s11Conn.Close()
 	log.Printf("Connection established with %s", raddr.String())
 
+	// let Conn set the F-TEIDs in the responses from S-GW to the sessions.
+	s11Conn.EnableTEIDLearning()
+
 	// register handlers for ALL the messages you expect remote endpoint to send.
 	// by default, Echo and VersionNotsupported is handled without explicit declaration.
 	s11Conn.AddHandlers(map[uint8]v2.HandlerFunc{
@@ -128,9 +130,9 @@ func main() {
 		case sub := <-attachCh:
 			log.Printf("Started creating session for subscriber: %s", sub.IMSI)
 			go func() {
-				bearer.APN = "some-apn-1.example"
+				bearer.SetAPN("some-apn-1.example")
 				if sub.TAI%2 == 0 {
-					bearer.APN = "some-apn-2.example"
+					bearer.SetAPN("some-apn-2.example")
 				}
 				if err := handleAttach(raddr, s11Conn, sub, bearer); err != nil {
 					errCh <- err
@@ -145,7 +147,11 @@ func main() {
 					return
 				}
 
-				enbIP := strings.Split(*s1enb, ":")[0]
+				enbIP, _, err := net.SplitHostPort(*s1enb)
+				if err != nil {
+					errCh <- err
+					return
+				}
 				enbFTEID := s11Conn.NewFTEID(v2.IFTypeS1UeNodeBGTPU, enbIP, "")
 				teid, err := sess.GetTEID(v2.IFTypeS11S4SGWGTPC)
 				if err != nil {
@@ -177,7 +183,12 @@ func main() {
 			}()
 		// delete all the sessions after 30 seconds
 		case <-time.After(30 * time.Second):
-			for _, sess := range s11Conn.Sessions {
+			var sessions []*v2.Session
+			s11Conn.RangeSessions(func(sess *v2.Session) bool {
+				sessions = append(sessions, sess)
+				return true
+			})
+			for _, sess := range sessions {
 				teid, err := sess.GetTEID(v2.IFTypeS11S4SGWGTPC)
 				if err != nil {
 					errCh <- v2.ErrTEIDNotFound
@@ -187,7 +198,7 @@ func main() {
 					log.Printf("Warning: %s", err)
 				}
 				delWG.Add(1)
-				log.Printf("Sent Delete Session Request for %s", sess.IMSI)
+				log.Printf("Sent Delete Session Request for %s", sess.GetIMSI())
 			}
 
 			// invoke goroutine to let the logger work
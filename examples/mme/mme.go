@@ -22,7 +22,6 @@ func handleCreateSessionResponse(c *v2.Conn, sgwAddr net.Addr, msg messages.Mess
 		c.RemoveSession(session)
 		return err
 	}
-	bearer := session.GetDefaultBearer()
 
 	// assert type to refer to the struct field specific to the message.
 	// in general, no need to check if it can be type-asserted, as long as the MessageType is
@@ -40,29 +39,15 @@ func handleCreateSessionResponse(c *v2.Conn, sgwAddr net.Addr, msg messages.Mess
 			return &v2.CauseNotOKError{
 				MsgType: csRspFromSGW.MessageTypeName(),
 				Cause:   cause,
-				Msg:     fmt.Sprintf("subscriber: %s", session.IMSI),
+				Msg:     fmt.Sprintf("subscriber: %s", session.GetIMSI()),
 			}
 		}
 	} else {
 		return &v2.RequiredIEMissingError{Type: msg.MessageType()}
 	}
 
-	if ie := csRspFromSGW.PAA; ie != nil {
-		bearer.SubscriberIP, err = ie.IPAddress()
-		if err != nil {
-			return err
-		}
-	}
-	if ie := csRspFromSGW.SenderFTEIDC; ie != nil {
-		teid, err := ie.TEID()
-		if err != nil {
-			return err
-		}
-		session.AddTEID(v2.IFTypeS11S4SGWGTPC, teid)
-	} else {
-		return &v2.RequiredIEMissingError{Type: ies.FullyQualifiedTEID}
-	}
-
+	// the F-TEIDs, EBI and PAA in the response are set to session by Conn, as
+	// TEID learning is enabled.
 	s11sgwTEID, err := session.GetTEID(v2.IFTypeS11S4SGWGTPC)
 	if err != nil {
 		c.RemoveSession(session)
@@ -74,42 +59,15 @@ func handleCreateSessionResponse(c *v2.Conn, sgwAddr net.Addr, msg messages.Mess
 		return err
 	}
 
-	if brCtxIE := csRspFromSGW.BearerContextsCreated; brCtxIE != nil {
-		for _, ie := range brCtxIE.ChildIEs {
-			switch ie.Type {
-			case ies.EPSBearerID:
-				bearer.EBI, err = ie.EPSBearerID()
-				if err != nil {
-					return err
-				}
-			case ies.FullyQualifiedTEID:
-				if ie.Instance() != 0 {
-					continue
-				}
-				it, err := ie.InterfaceType()
-				if err != nil {
-					return err
-				}
-				teid, err := ie.TEID()
-				if err != nil {
-					return err
-				}
-				session.AddTEID(it, teid)
-			}
-		}
-	} else {
-		return &v2.RequiredIEMissingError{Type: ies.BearerContext}
-	}
-
 	if err := session.Activate(); err != nil {
 		c.RemoveSession(session)
 		return err
 	}
 
-	createdCh <- session.Subscriber.IMSI
+	createdCh <- session.GetIMSI()
 	loggerCh <- fmt.Sprintf(
 		"Session created with S-GW for Subscriber: %s;\n\tS11 S-GW: %s, TEID->: %#x, TEID<-: %#x",
-		session.Subscriber.IMSI, sgwAddr, s11sgwTEID, s11mmeTEID,
+		session.GetIMSI(), sgwAddr, s11sgwTEID, s11mmeTEID,
 	)
 	return nil
 }
@@ -132,53 +90,28 @@ func handleModifyBearerResponse(c *v2.Conn, sgwAddr net.Addr, msg messages.Messa
 			return &v2.CauseNotOKError{
 				MsgType: msg.MessageTypeName(),
 				Cause:   cause,
-				Msg:     fmt.Sprintf("subscriber: %s", session.IMSI),
+				Msg:     fmt.Sprintf("subscriber: %s", session.GetIMSI()),
 			}
 		}
 	} else {
 		return &v2.RequiredIEMissingError{Type: ies.Cause}
 	}
 
+	// the S1-U SGW F-TEID in the response is set to the default bearer by Conn.
+	bearer := session.GetDefaultBearer()
+	if bearer.RemoteAddress() == nil {
+		return &v2.RequiredIEMissingError{Type: ies.FullyQualifiedTEID}
+	}
 	mock := &mockUEeNB{
-		subscriberIP: session.GetDefaultBearer().SubscriberIP,
+		subscriberIP: bearer.GetSubscriberIP(),
 		payload:      payload,
-	}
-	if brCtxIE := mbRspFromSGW.BearerContextsModified; brCtxIE != nil {
-		for _, ie := range brCtxIE.ChildIEs {
-			switch ie.Type {
-			case ies.FullyQualifiedTEID:
-				if ie.Instance() != 0 {
-					continue
-				}
-				it, err := ie.InterfaceType()
-				if err != nil {
-					return err
-				}
-				teid, err := ie.TEID()
-				if err != nil {
-					return err
-				}
-				session.AddTEID(it, teid)
-
-				ip, err := ie.IPAddress()
-				if err != nil {
-					return err
-				}
-				sgwUAddr, err := net.ResolveUDPAddr("udp", ip+":2152")
-				if err != nil {
-					return err
-				}
-				mock.raddr = sgwUAddr
-				mock.teidOut = teid
-			}
-		}
-	} else {
-		return &v2.RequiredIEMissingError{Type: ies.BearerContext}
+		raddr:        bearer.RemoteAddress(),
+		teidOut:      bearer.OutgoingTEID(),
 	}
 
 	go mock.run(errCh)
 
-	loggerCh <- fmt.Sprintf("Bearer modified with S-GW for Subscriber: %s", session.IMSI)
+	loggerCh <- fmt.Sprintf("Bearer modified with S-GW for Subscriber: %s", session.GetIMSI())
 	return nil
 }
 
@@ -192,6 +125,6 @@ func handleDeleteSessionResponse(c *v2.Conn, sgwAddr net.Addr, msg messages.Mess
 
 	c.RemoveSession(session)
 	delWG.Done()
-	loggerCh <- fmt.Sprintf("Session deleted with S-GW for Subscriber: %s", session.IMSI)
+	loggerCh <- fmt.Sprintf("Session deleted with S-GW for Subscriber: %s", session.GetIMSI())
 	return nil
 }
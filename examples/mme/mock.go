@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"log"
 	"net"
-	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -73,19 +72,23 @@ func handleAttach(raddr net.Addr, c *v2.Conn, sub *v2.Subscriber, br *v2.Bearer)
 		c.RemoveSession(sess)
 	}
 
-	pgwAddr, err := getPGWIP(br.APN)
+	pgwAddr, err := getPGWIP(br.GetAPN())
 	if err != nil {
 		return err
 	}
 
+	qos := br.GetQoSProfile()
 	var pci, pvi uint8
-	if br.PCI {
+	if qos.PCI {
 		pci = 1
 	}
-	if br.PVI {
+	if qos.PVI {
 		pvi = 1
 	}
-	localIP := strings.Split(c.LocalAddr().String(), ":")[0]
+	localIP, _, err := net.SplitHostPort(c.LocalAddr().String())
+	if err != nil {
+		return err
+	}
 	session, _, err := c.CreateSession(
 		raddr,
 		ies.NewIMSI(sub.IMSI),
@@ -99,15 +102,15 @@ func handleAttach(raddr net.Addr, c *v2.Conn, sub *v2.Subscriber, br *v2.Bearer)
 		ies.NewIndicationFromOctets(0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00),
 		c.NewFTEID(v2.IFTypeS11MMEGTPC, localIP, ""),
 		c.NewFTEID(v2.IFTypeS5S8PGWGTPC, pgwAddr, "").WithInstance(1),
-		ies.NewAccessPointName(br.APN),
+		ies.NewAccessPointName(br.GetAPN()),
 		ies.NewSelectionMode(v2.SelectionModeMSorNetworkProvidedAPNSubscribedVerified),
 		ies.NewPDNType(v2.PDNTypeIPv4),
 		ies.NewPDNAddressAllocation("0.0.0.0"),
 		ies.NewAPNRestriction(v2.APNRestrictionNoExistingContextsorRestriction),
 		ies.NewAggregateMaximumBitRate(0, 0),
 		ies.NewBearerContext(
-			ies.NewEPSBearerID(br.EBI),
-			ies.NewBearerQoS(pci, br.PL, pvi, br.QCI, br.MBRUL, br.MBRDL, br.GBRUL, br.GBRDL),
+			ies.NewEPSBearerID(br.GetEBI()),
+			ies.NewBearerQoS(pci, qos.PL, pvi, qos.QCI, qos.MBRUL, qos.MBRDL, qos.GBRUL, qos.GBRDL),
 		),
 		ies.NewFullyQualifiedCSID(localIP, 1),
 		ies.NewServingNetwork(sub.MCC, sub.MNC),
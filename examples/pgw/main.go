@@ -65,12 +65,12 @@ func main() {
 			log.Printf("Warning: %s", err)
 		case <-time.After(10 * time.Second):
 			var activeIMSIs []string
-			for _, sess := range s5cConn.Sessions {
-				if !sess.IsActive() {
-					continue
+			s5cConn.RangeSessions(func(sess *v2.Session) bool {
+				if sess.IsActive() {
+					activeIMSIs = append(activeIMSIs, sess.GetIMSI())
 				}
-				activeIMSIs = append(activeIMSIs, sess.IMSI)
-			}
+				return true
+			})
 			if len(activeIMSIs) == 0 {
 				continue
 			}
@@ -7,7 +7,6 @@ package main
 import (
 	"fmt"
 	"net"
-	"strings"
 
 	v1 "github.com/wmnsk/go-gtp/v1"
 
@@ -53,7 +52,8 @@ func handleCreateSessionRequest(c *v2.Conn, sgwAddr net.Addr, msg messages.Messa
 	csReqFromSGW := msg.(*messages.CreateSessionRequest)
 
 	// keep session information retrieved from the message.
-	session := v2.NewSession(sgwAddr, &v2.Subscriber{Location: &v2.Location{}})
+	sub := &v2.Subscriber{Location: &v2.Location{}}
+	session := v2.NewSession(sgwAddr, sub)
 	bearer := session.GetDefaultBearer()
 	var err error
 	if ie := csReqFromSGW.IMSI; ie != nil {
@@ -61,7 +61,7 @@ func handleCreateSessionRequest(c *v2.Conn, sgwAddr net.Addr, msg messages.Messa
 		if err != nil {
 			return err
 		}
-		session.IMSI = imsi
+		sub.IMSI = imsi
 
 		// remove previous session for the same subscriber if exists.
 		sess, err := c.GetSessionByIMSI(imsi)
@@ -79,7 +79,7 @@ func handleCreateSessionRequest(c *v2.Conn, sgwAddr net.Addr, msg messages.Messa
 		return &v2.RequiredIEMissingError{Type: ies.IMSI}
 	}
 	if ie := csReqFromSGW.MSISDN; ie != nil {
-		session.MSISDN, err = ie.MSISDN()
+		sub.MSISDN, err = ie.MSISDN()
 		if err != nil {
 			return err
 		}
@@ -87,7 +87,7 @@ func handleCreateSessionRequest(c *v2.Conn, sgwAddr net.Addr, msg messages.Messa
 		return &v2.RequiredIEMissingError{Type: ies.MSISDN}
 	}
 	if ie := csReqFromSGW.MEI; ie != nil {
-		session.IMEI, err = ie.MobileEquipmentIdentity()
+		sub.IMEI, err = ie.MobileEquipmentIdentity()
 		if err != nil {
 			return err
 		}
@@ -95,19 +95,20 @@ func handleCreateSessionRequest(c *v2.Conn, sgwAddr net.Addr, msg messages.Messa
 		return &v2.RequiredIEMissingError{Type: ies.MobileEquipmentIdentity}
 	}
 	if ie := csReqFromSGW.APN; ie != nil {
-		bearer.APN, err = ie.AccessPointName()
+		apn, err := ie.AccessPointName()
 		if err != nil {
 			return err
 		}
+		bearer.SetAPN(apn)
 	} else {
 		return &v2.RequiredIEMissingError{Type: ies.AccessPointName}
 	}
 	if ie := csReqFromSGW.ServingNetwork; ie != nil {
-		session.MCC, err = ie.MCC()
+		sub.MCC, err = ie.MCC()
 		if err != nil {
 			return err
 		}
-		session.MNC, err = ie.MNC()
+		sub.MNC, err = ie.MNC()
 		if err != nil {
 			return err
 		}
@@ -115,7 +116,7 @@ func handleCreateSessionRequest(c *v2.Conn, sgwAddr net.Addr, msg messages.Messa
 		return &v2.RequiredIEMissingError{Type: ies.ServingNetwork}
 	}
 	if ie := csReqFromSGW.RATType; ie != nil {
-		session.RATType, err = ie.RATType()
+		sub.RATType, err = ie.RATType()
 		if err != nil {
 			return err
 		}
@@ -137,10 +138,11 @@ func handleCreateSessionRequest(c *v2.Conn, sgwAddr net.Addr, msg messages.Messa
 		for _, ie := range brCtxIE.ChildIEs {
 			switch ie.Type {
 			case ies.EPSBearerID:
-				bearer.EBI, err = ie.EPSBearerID()
+				ebi, err := ie.EPSBearerID()
 				if err != nil {
 					return err
 				}
+				bearer.SetEBI(ebi)
 			case ies.FullyQualifiedTEID:
 				it, err := ie.InterfaceType()
 				if err != nil {
@@ -157,13 +159,20 @@ func handleCreateSessionRequest(c *v2.Conn, sgwAddr net.Addr, msg messages.Messa
 		return &v2.RequiredIEMissingError{Type: ies.BearerContext}
 	}
 
-	bearer.SubscriberIP, err = getSubscriberIP(session.Subscriber)
+	subIP, err := getSubscriberIP(sub)
 	if err != nil {
 		return err
 	}
+	bearer.SetSubscriberIP(subIP)
 
-	cIP := strings.Split(c.LocalAddr().String(), ":")[0]
-	uIP := strings.Split(*s5u, ":")[0]
+	cIP, _, err := net.SplitHostPort(c.LocalAddr().String())
+	if err != nil {
+		return err
+	}
+	uIP, _, err := net.SplitHostPort(*s5u)
+	if err != nil {
+		return err
+	}
 	s5cFTEID := c.NewFTEID(v2.IFTypeS5S8PGWGTPC, cIP, "").WithInstance(1)
 	s5uFTEID := c.NewFTEID(v2.IFTypeS5S8PGWGTPU, uIP, "").WithInstance(2)
 	s5sgwTEID, err := session.GetTEID(v2.IFTypeS5S8SGWGTPC)
@@ -174,13 +183,13 @@ func handleCreateSessionRequest(c *v2.Conn, sgwAddr net.Addr, msg messages.Messa
 		s5sgwTEID, 0,
 		ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
 		s5cFTEID,
-		ies.NewPDNAddressAllocation(bearer.SubscriberIP),
+		ies.NewPDNAddressAllocation(bearer.GetSubscriberIP()),
 		ies.NewAPNRestriction(v2.APNRestrictionPublic2),
 		ies.NewBearerContext(
 			ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
-			ies.NewEPSBearerID(bearer.EBI),
+			ies.NewEPSBearerID(bearer.GetEBI()),
 			s5uFTEID,
-			ies.NewChargingID(bearer.ChargingID),
+			ies.NewChargingID(bearer.GetChargingID()),
 		),
 	)
 	if csReqFromSGW.SGWFQCSID != nil {
@@ -240,7 +249,7 @@ func handleCreateSessionRequest(c *v2.Conn, sgwAddr net.Addr, msg messages.Messa
 	}()
 
 	loggerCh <- fmt.Sprintf("Session created with S-GW for subscriber: %s;\n\tS5C S-GW: %s, TEID->: %#x, TEID<-: %#x",
-		session.Subscriber.IMSI, sgwAddr, s5sgwTEID, s5pgwTEID,
+		session.GetIMSI(), sgwAddr, s5sgwTEID, s5pgwTEID,
 	)
 	return nil
 }
@@ -278,7 +287,7 @@ func handleDeleteSessionRequest(c *v2.Conn, sgwAddr net.Addr, msg messages.Messa
 		return err
 	}
 
-	loggerCh <- fmt.Sprintf("Session deleted for Subscriber: %s", session.IMSI)
+	loggerCh <- fmt.Sprintf("Session deleted for Subscriber: %s", session.GetIMSI())
 	c.RemoveSession(session)
 	return nil
 }
@@ -102,12 +102,12 @@ func (s *sGateway) run() error {
 			log.Printf("Warning: %s", errors.WithStack(err))
 		case <-time.After(10 * time.Second):
 			var activeIMSIs []string
-			for _, sess := range s.s11Conn.Sessions {
-				if !sess.IsActive() {
-					continue
+			s.s11Conn.RangeSessions(func(sess *v2.Session) bool {
+				if sess.IsActive() {
+					activeIMSIs = append(activeIMSIs, sess.GetIMSI())
 				}
-				activeIMSIs = append(activeIMSIs, sess.IMSI)
-			}
+				return true
+			})
 			if len(activeIMSIs) == 0 {
 				continue
 			}
@@ -19,7 +19,8 @@ import (
 func handleCreateSessionRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages.Message) error {
 	sgw.loggerCh <- fmt.Sprintf("Received %s from %s", msg.MessageTypeName(), mmeAddr)
 
-	s11Session := v2.NewSession(mmeAddr, &v2.Subscriber{Location: &v2.Location{}})
+	sub := &v2.Subscriber{Location: &v2.Location{}}
+	s11Session := v2.NewSession(mmeAddr, sub)
 	s11Bearer := s11Session.GetDefaultBearer()
 
 	// assert type to refer to the struct field specific to the message.
@@ -33,7 +34,7 @@ func handleCreateSessionRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages
 		if err != nil {
 			return err
 		}
-		pgwAddrString = ip + ":2123"
+		pgwAddrString = net.JoinHostPort(ip, "2123")
 
 		teid, err := ie.TEID()
 		if err != nil {
@@ -83,12 +84,12 @@ func handleCreateSessionRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages
 			s11Conn.RemoveSession(sess)
 		}
 
-		s11Session.IMSI = imsi
+		sub.IMSI = imsi
 	} else {
 		return &v2.RequiredIEMissingError{Type: ies.IMSI}
 	}
 	if ie := csReqFromMME.MSISDN; ie != nil {
-		s11Session.MSISDN, err = ie.MSISDN()
+		sub.MSISDN, err = ie.MSISDN()
 		if err != nil {
 			return err
 		}
@@ -96,7 +97,7 @@ func handleCreateSessionRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages
 		return &v2.RequiredIEMissingError{Type: ies.MSISDN}
 	}
 	if ie := csReqFromMME.MEI; ie != nil {
-		s11Session.IMEI, err = ie.MobileEquipmentIdentity()
+		sub.IMEI, err = ie.MobileEquipmentIdentity()
 		if err != nil {
 			return err
 		}
@@ -104,19 +105,20 @@ func handleCreateSessionRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages
 		return &v2.RequiredIEMissingError{Type: ies.MobileEquipmentIdentity}
 	}
 	if ie := csReqFromMME.APN; ie != nil {
-		s11Bearer.APN, err = ie.AccessPointName()
+		apn, err := ie.AccessPointName()
 		if err != nil {
 			return err
 		}
+		s11Bearer.SetAPN(apn)
 	} else {
 		return &v2.RequiredIEMissingError{Type: ies.AccessPointName}
 	}
 	if ie := csReqFromMME.ServingNetwork; ie != nil {
-		s11Session.MCC, err = ie.MCC()
+		sub.MCC, err = ie.MCC()
 		if err != nil {
 			return err
 		}
-		s11Session.MNC, err = ie.MNC()
+		sub.MNC, err = ie.MNC()
 		if err != nil {
 			return err
 		}
@@ -124,7 +126,7 @@ func handleCreateSessionRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages
 		return &v2.RequiredIEMissingError{Type: ies.ServingNetwork}
 	}
 	if ie := csReqFromMME.RATType; ie != nil {
-		s11Session.RATType, err = ie.RATType()
+		sub.RATType, err = ie.RATType()
 		if err != nil {
 			return err
 		}
@@ -161,7 +163,7 @@ func handleCreateSessionRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages
 	s5Session.AddTEID(s5uFTEID.MustInterfaceType(), s5uFTEID.MustTEID())
 	sgw.s5cConn.AddSession(s5Session)
 
-	sgw.loggerCh <- fmt.Sprintf("Sent Create Session Request to %s for %s", pgwAddrString, s5Session.IMSI)
+	sgw.loggerCh <- fmt.Sprintf("Sent Create Session Request to %s for %s", pgwAddrString, s5Session.GetIMSI())
 
 	var csRspFromSGW *messages.CreateSessionResponse
 	s11mmeTEID, err := s11Session.GetTEID(v2.IFTypeS11MMEGTPC)
@@ -182,7 +184,7 @@ func handleCreateSessionRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages
 		}
 		sgw.loggerCh <- fmt.Sprintf(
 			"Sent %s with failure code: %d, target subscriber: %s",
-			csRspFromSGW.MessageTypeName(), v2.CausePGWNotResponding, s11Session.IMSI,
+			csRspFromSGW.MessageTypeName(), v2.CausePGWNotResponding, s11Session.GetIMSI(),
 		)
 		s11Conn.RemoveSession(s11Session)
 		return err
@@ -248,7 +250,7 @@ func handleCreateSessionRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages
 
 	sgw.loggerCh <- fmt.Sprintf(
 		"Session created with MME and P-GW for Subscriber: %s;\n\tS11 MME:  %s, TEID->: %#x, TEID<-: %#x\n\tS5C P-GW: %s, TEID->: %#x, TEID<-: %#x",
-		s5Session.Subscriber.IMSI, mmeAddr, s11mmeTEID, s11sgwTEID, pgwAddrString, s5cpgwTEID, s5csgwTEID,
+		s5Session.GetIMSI(), mmeAddr, s11mmeTEID, s11sgwTEID, pgwAddrString, s5cpgwTEID, s5csgwTEID,
 	)
 	return nil
 }
@@ -260,7 +262,7 @@ func handleModifyBearerRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages.
 	if err != nil {
 		return err
 	}
-	s5cSession, err := sgw.s5cConn.GetSessionByIMSI(s11Session.IMSI)
+	s5cSession, err := sgw.s5cConn.GetSessionByIMSI(s11Session.GetIMSI())
 	if err != nil {
 		return err
 	}
@@ -319,7 +321,7 @@ func handleModifyBearerRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages.
 		ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
 		ies.NewBearerContext(
 			ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
-			ies.NewEPSBearerID(s1uBearer.EBI),
+			ies.NewEPSBearerID(s1uBearer.GetEBI()),
 			ies.NewFullyQualifiedTEID(v2.IFTypeS1USGWGTPU, s1usgwTEID, s1uIP, ""),
 		),
 	)
@@ -330,7 +332,7 @@ func handleModifyBearerRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages.
 
 	sgw.loggerCh <- fmt.Sprintf(
 		"Started listening on U-Plane for Subscriber: %s;\n\tS1-U: %s\n\tS5-U: %s",
-		s11Session.IMSI, *s1u, *s5u,
+		s11Session.GetIMSI(), *s1u, *s5u,
 	)
 	return nil
 }
@@ -348,7 +350,7 @@ func handleDeleteSessionRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages
 		return err
 	}
 
-	s5Session, err := sgw.s5cConn.GetSessionByIMSI(s11Session.IMSI)
+	s5Session, err := sgw.s5cConn.GetSessionByIMSI(s11Session.GetIMSI())
 	if err != nil {
 		return err
 	}
@@ -385,7 +387,7 @@ func handleDeleteSessionRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages
 		}
 		sgw.loggerCh <- fmt.Sprintf(
 			"Sent %s with failure code: %d, target subscriber: %s",
-			dsRspFromSGW.MessageTypeName(), v2.CausePGWNotResponding, s11Session.IMSI,
+			dsRspFromSGW.MessageTypeName(), v2.CausePGWNotResponding, s11Session.GetIMSI(),
 		)
 		return err
 	}
@@ -404,7 +406,7 @@ func handleDeleteSessionRequest(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages
 		return err
 	}
 
-	sgw.loggerCh <- fmt.Sprintf("Session deleted for Subscriber: %s", s11Session.IMSI)
+	sgw.loggerCh <- fmt.Sprintf("Session deleted for Subscriber: %s", s11Session.GetIMSI())
 	s11Conn.RemoveSession(s11Session)
 
 	return nil
@@ -418,7 +420,7 @@ func handleDeleteBearerResponse(s11Conn *v2.Conn, mmeAddr net.Addr, msg messages
 		return err
 	}
 
-	s5Session, err := sgw.s5cConn.GetSessionByIMSI(s11Session.IMSI)
+	s5Session, err := sgw.s5cConn.GetSessionByIMSI(s11Session.GetIMSI())
 	if err != nil {
 		return err
 	}
@@ -441,7 +443,7 @@ func handleFTEIDU(ie *ies.IE, session *v2.Session, bearer *v2.Bearer) error {
 	if err != nil {
 		return err
 	}
-	addr, err := net.ResolveUDPAddr("udp", ip+":2152")
+	addr, err := net.ResolveUDPAddr("udp", net.JoinHostPort(ip, "2152"))
 	if err != nil {
 		return err
 	}
@@ -37,7 +37,7 @@ func handleCreateSessionResponse(s5cConn *v2.Conn, pgwAddr net.Addr, msg message
 			return &v2.CauseNotOKError{
 				MsgType: csRspFromPGW.MessageTypeName(),
 				Cause:   cause,
-				Msg:     fmt.Sprintf("subscriber: %s", s5Session.IMSI),
+				Msg:     fmt.Sprintf("subscriber: %s", s5Session.GetIMSI()),
 			}
 		}
 	} else {
@@ -54,7 +54,7 @@ func handleCreateSessionResponse(s5cConn *v2.Conn, pgwAddr net.Addr, msg message
 		if err != nil {
 			return err
 		}
-		bearer.SubscriberIP = ip
+		bearer.SetSubscriberIP(ip)
 	} else {
 		s5cConn.RemoveSession(s5Session)
 		return &v2.RequiredIEMissingError{Type: ies.PDNAddressAllocation}
@@ -87,7 +87,7 @@ func handleCreateSessionResponse(s5cConn *v2.Conn, pgwAddr net.Addr, msg message
 					return &v2.CauseNotOKError{
 						MsgType: csRspFromPGW.MessageTypeName(),
 						Cause:   cause,
-						Msg:     fmt.Sprintf("subscriber: %s", s5Session.IMSI),
+						Msg:     fmt.Sprintf("subscriber: %s", s5Session.GetIMSI()),
 					}
 				}
 			case ies.EPSBearerID:
@@ -95,7 +95,7 @@ func handleCreateSessionResponse(s5cConn *v2.Conn, pgwAddr net.Addr, msg message
 				if err != nil {
 					return err
 				}
-				bearer.EBI = ebi
+				bearer.SetEBI(ebi)
 			case ies.FullyQualifiedTEID:
 				if err := handleFTEIDU(ie, s5Session, bearer); err != nil {
 					return err
@@ -105,7 +105,7 @@ func handleCreateSessionResponse(s5cConn *v2.Conn, pgwAddr net.Addr, msg message
 				if err != nil {
 					return err
 				}
-				bearer.ChargingID = cid
+				bearer.SetChargingID(cid)
 			}
 		}
 	} else {
@@ -118,7 +118,7 @@ func handleCreateSessionResponse(s5cConn *v2.Conn, pgwAddr net.Addr, msg message
 		return err
 	}
 
-	s11Session, err := sgw.s11Conn.GetSessionByIMSI(s5Session.IMSI)
+	s11Session, err := sgw.s11Conn.GetSessionByIMSI(s5Session.GetIMSI())
 	if err != nil {
 		return err
 	}
@@ -138,7 +138,7 @@ func handleDeleteSessionResponse(s5cConn *v2.Conn, pgwAddr net.Addr, msg message
 		return err
 	}
 
-	s11Session, err := sgw.s11Conn.GetSessionByIMSI(s5Session.IMSI)
+	s11Session, err := sgw.s11Conn.GetSessionByIMSI(s5Session.GetIMSI())
 	if err != nil {
 		return err
 	}
@@ -148,7 +148,7 @@ func handleDeleteSessionResponse(s5cConn *v2.Conn, pgwAddr net.Addr, msg message
 	}
 
 	// even the cause indicates failure, session should be removed locally.
-	sgw.loggerCh <- fmt.Sprintf("Session deleted for Subscriber: %s", s5Session.IMSI)
+	sgw.loggerCh <- fmt.Sprintf("Session deleted for Subscriber: %s", s5Session.GetIMSI())
 	s5cConn.RemoveSession(s5Session)
 	return nil
 }
@@ -161,7 +161,7 @@ func handleDeleteBearerRequest(s5cConn *v2.Conn, pgwAddr net.Addr, msg messages.
 		return err
 	}
 
-	s11Session, err := sgw.s11Conn.GetSessionByIMSI(s5Session.IMSI)
+	s11Session, err := sgw.s11Conn.GetSessionByIMSI(s5Session.GetIMSI())
 	if err != nil {
 		return err
 	}
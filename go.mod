@@ -2,11 +2,12 @@ module github.com/wmnsk/go-gtp
 
 require (
 	github.com/google/go-cmp v0.2.0
+	github.com/google/gopacket v1.1.19
 	github.com/pascaldekloe/goe v0.0.0-20180627143212-57f6aae5913c
 	github.com/pkg/errors v0.8.1
 	github.com/vishvananda/netlink v1.0.0
 	github.com/vishvananda/netns v0.0.0-20190625233234-7109fa855b0f // indirect
-	golang.org/x/sys v0.0.0-20190804053845-51ab0e2deafa // indirect
+	golang.org/x/sys v0.0.0-20190804053845-51ab0e2deafa
 )
 
 go 1.13
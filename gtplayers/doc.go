@@ -0,0 +1,27 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+/*
+Package gtplayers provides the gopacket layers of GTPv1 and GTPv2 backed by the
+parsers in go-gtp, so that the pcap-based tools can decode and re-encode GTP-C and
+GTP-U with the full set of messages and IEs in this module.
+
+This is the only package in this module that imports github.com/google/gopacket, so
+the programs that do not import it do not depend on gopacket.
+
+Call RegisterPorts to decode the UDP datagrams on the well-known GTP ports with the
+layers, instead of the built-in GTPv1U layer of gopacket.
+
+	gtplayers.RegisterPorts()
+
+	pkt := gopacket.NewPacket(data, layers.LayerTypeEthernet, gopacket.Default)
+	if l := pkt.Layer(gtplayers.LayerTypeGTPv2); l != nil {
+		msg := l.(*gtplayers.GTPv2).Message
+		fmt.Print(messages.Dump(msg))
+	}
+
+The T-PDU in GTPv1-U is decoded as the IPv4 or IPv6 layer next to GTPv1, and the
+piggybacked message in GTPv2 as the next GTPv2 layer.
+*/
+package gtplayers
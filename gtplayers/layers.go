@@ -0,0 +1,246 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtplayers
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+
+	v1msg "github.com/wmnsk/go-gtp/v1/messages"
+	v2 "github.com/wmnsk/go-gtp/v2"
+	v2msg "github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// ErrUnsupportedVersion indicates that the version in the GTP header is neither 1 nor 2.
+var ErrUnsupportedVersion = errors.New("unsupported version of GTP")
+
+// Layer type numbers registered to gopacket, which are out of the range used by
+// gopacket/layers.
+const (
+	LayerTypeNumGTPv1 = 2152
+	LayerTypeNumGTPv2 = 2123
+	LayerTypeNumGTPC  = 2124
+)
+
+// Layer type definitions.
+var (
+	// LayerTypeGTPv1 is the layer of GTPv1-C and GTPv1-U messages.
+	LayerTypeGTPv1 gopacket.LayerType
+	// LayerTypeGTPv2 is the layer of GTPv2-C messages.
+	LayerTypeGTPv2 gopacket.LayerType
+	// LayerTypeGTPC is the pseudo layer type to decode GTP-C port, which carries both
+	// GTPv1-C and GTPv2-C. It decodes the datagram as GTPv1 or GTPv2 by the version
+	// in the header, and never appears in the packets.
+	LayerTypeGTPC gopacket.LayerType
+)
+
+// the layer types are registered in init, as the decoders refer to them.
+func init() {
+	LayerTypeGTPv1 = gopacket.RegisterLayerType(
+		LayerTypeNumGTPv1,
+		gopacket.LayerTypeMetadata{Name: "GTPv1", Decoder: gopacket.DecodeFunc(decodeGTPv1)},
+	)
+	LayerTypeGTPv2 = gopacket.RegisterLayerType(
+		LayerTypeNumGTPv2,
+		gopacket.LayerTypeMetadata{Name: "GTPv2", Decoder: gopacket.DecodeFunc(decodeGTPv2)},
+	)
+	LayerTypeGTPC = gopacket.RegisterLayerType(
+		LayerTypeNumGTPC,
+		gopacket.LayerTypeMetadata{Name: "GTP-C", Decoder: gopacket.DecodeFunc(decodeGTPC)},
+	)
+}
+
+// RegisterPorts registers the layers to the UDP ports of GTP-C (2123) and GTP-U (2152)
+// in gopacket/layers, which replaces the built-in GTPv1U layer of gopacket.
+func RegisterPorts() {
+	layers.RegisterUDPPortLayerType(layers.UDPPort(v2.GTPCPort), LayerTypeGTPC)
+	layers.RegisterUDPPortLayerType(layers.UDPPort(v2.GTPUPort), LayerTypeGTPv1)
+}
+
+// GTPv1 is the gopacket layer of GTPv1 message.
+//
+// For T-PDU, LayerContents is the GTPv1 header, and LayerPayload is the user data
+// which is decoded as the next layer.
+type GTPv1 struct {
+	layers.BaseLayer
+	Message v1msg.Message
+}
+
+// LayerType returns LayerTypeGTPv1.
+func (g *GTPv1) LayerType() gopacket.LayerType {
+	return LayerTypeGTPv1
+}
+
+// CanDecode returns LayerTypeGTPv1.
+func (g *GTPv1) CanDecode() gopacket.LayerClass {
+	return LayerTypeGTPv1
+}
+
+// NextLayerType returns IPv4 or IPv6 by the version of the user data in T-PDU, or
+// LayerTypeZero for the other messages.
+func (g *GTPv1) NextLayerType() gopacket.LayerType {
+	if len(g.Payload) == 0 {
+		return gopacket.LayerTypeZero
+	}
+
+	switch g.Payload[0] >> 4 {
+	case 4:
+		return layers.LayerTypeIPv4
+	case 6:
+		return layers.LayerTypeIPv6
+	default:
+		return gopacket.LayerTypePayload
+	}
+}
+
+// DecodeFromBytes decodes the given bytes as GTPv1 message with v1/messages.Parse.
+func (g *GTPv1) DecodeFromBytes(data []byte, df gopacket.DecodeFeedback) error {
+	msg, err := v1msg.Parse(data)
+	if err != nil {
+		df.SetTruncated()
+		return err
+	}
+	g.Message = msg
+
+	n := msg.MarshalLen()
+	if n > len(data) {
+		n = len(data)
+	}
+	if tpdu, ok := msg.(*v1msg.TPDU); ok {
+		n -= len(tpdu.Decapsulate())
+	}
+	g.BaseLayer = layers.BaseLayer{Contents: data[:n], Payload: data[n:]}
+	return nil
+}
+
+// SerializeTo prepends the GTPv1 message to b.
+//
+// For T-PDU, the bytes already in b, which are the ones serialized by the next layer,
+// are used as the user data. The Length field is updated if opts.FixLengths is true.
+func (g *GTPv1) SerializeTo(b gopacket.SerializeBuffer, opts gopacket.SerializeOptions) error {
+	msg := g.Message
+	if tpdu, ok := msg.(*v1msg.TPDU); ok {
+		if payload := b.Bytes(); len(payload) > 0 {
+			h := *tpdu.Header
+			h.Payload = append([]byte{}, payload...)
+			if opts.FixLengths {
+				h.SetLength()
+			}
+
+			hdr, err := b.PrependBytes(h.MarshalLen() - len(payload))
+			if err != nil {
+				return err
+			}
+			h.Payload = nil
+			return h.MarshalTo(hdr)
+		}
+	}
+
+	buf, err := b.PrependBytes(msg.MarshalLen())
+	if err != nil {
+		return err
+	}
+	return msg.MarshalTo(buf)
+}
+
+func decodeGTPv1(data []byte, p gopacket.PacketBuilder) error {
+	g := &GTPv1{}
+	if err := g.DecodeFromBytes(data, p); err != nil {
+		return err
+	}
+	p.AddLayer(g)
+	return p.NextDecoder(g.NextLayerType())
+}
+
+// GTPv2 is the gopacket layer of GTPv2 message.
+//
+// If the message is piggybacking another one, LayerPayload is the piggybacked
+// message, which is decoded as the next GTPv2 layer.
+type GTPv2 struct {
+	layers.BaseLayer
+	Message v2msg.Message
+}
+
+// LayerType returns LayerTypeGTPv2.
+func (g *GTPv2) LayerType() gopacket.LayerType {
+	return LayerTypeGTPv2
+}
+
+// CanDecode returns LayerTypeGTPv2.
+func (g *GTPv2) CanDecode() gopacket.LayerClass {
+	return LayerTypeGTPv2
+}
+
+// NextLayerType returns LayerTypeGTPv2 if the message is piggybacking another one,
+// or LayerTypeZero otherwise.
+func (g *GTPv2) NextLayerType() gopacket.LayerType {
+	if len(g.Payload) == 0 {
+		return gopacket.LayerTypeZero
+	}
+	return LayerTypeGTPv2
+}
+
+// DecodeFromBytes decodes the given bytes as GTPv2 message with v2/messages.Parse.
+func (g *GTPv2) DecodeFromBytes(data []byte, df gopacket.DecodeFeedback) error {
+	if len(data) < 4 {
+		df.SetTruncated()
+		return v2msg.ErrTooShortToParse
+	}
+
+	// the piggybacked message follows the one of the length in the header.
+	n := len(data)
+	if data[0]&0x10 != 0 {
+		if l := int(binary.BigEndian.Uint16(data[2:4])) + 4; l < n {
+			n = l
+		}
+	}
+
+	msg, err := v2msg.Parse(data[:n])
+	if err != nil {
+		df.SetTruncated()
+		return err
+	}
+	g.Message = msg
+	g.BaseLayer = layers.BaseLayer{Contents: data[:n], Payload: data[n:]}
+	return nil
+}
+
+// SerializeTo prepends the GTPv2 message to b. The piggybacked message, if any, is
+// expected to be serialized by the next layer.
+func (g *GTPv2) SerializeTo(b gopacket.SerializeBuffer, opts gopacket.SerializeOptions) error {
+	buf, err := b.PrependBytes(g.Message.MarshalLen())
+	if err != nil {
+		return err
+	}
+	return g.Message.MarshalTo(buf)
+}
+
+func decodeGTPv2(data []byte, p gopacket.PacketBuilder) error {
+	g := &GTPv2{}
+	if err := g.DecodeFromBytes(data, p); err != nil {
+		return err
+	}
+	p.AddLayer(g)
+	return p.NextDecoder(g.NextLayerType())
+}
+
+func decodeGTPC(data []byte, p gopacket.PacketBuilder) error {
+	if len(data) == 0 {
+		p.SetTruncated()
+		return v2msg.ErrTooShortToParse
+	}
+
+	switch data[0] >> 5 {
+	case 1:
+		return decodeGTPv1(data, p)
+	case 2:
+		return decodeGTPv2(data, p)
+	default:
+		return ErrUnsupportedVersion
+	}
+}
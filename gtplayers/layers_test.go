@@ -0,0 +1,186 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtplayers_test
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+
+	"github.com/wmnsk/go-gtp/gtplayers"
+	v1ies "github.com/wmnsk/go-gtp/v1/ies"
+	v1msg "github.com/wmnsk/go-gtp/v1/messages"
+	v2ies "github.com/wmnsk/go-gtp/v2/ies"
+	v2msg "github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func init() {
+	gtplayers.RegisterPorts()
+}
+
+var serializeOpts = gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+
+// udpPacket serializes the IPv4/UDP packet to port with the layers given after UDP.
+func udpPacket(t *testing.T, port uint16, ls ...gopacket.SerializableLayer) []byte {
+	t.Helper()
+
+	ip := &layers.IPv4{
+		Version: 4, TTL: 64, Protocol: layers.IPProtocolUDP,
+		SrcIP: net.IPv4(127, 0, 0, 1), DstIP: net.IPv4(127, 0, 0, 2),
+	}
+	udp := &layers.UDP{SrcPort: layers.UDPPort(port), DstPort: layers.UDPPort(port)}
+	if err := udp.SetNetworkLayerForChecksum(ip); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := gopacket.NewSerializeBuffer()
+	if err := gopacket.SerializeLayers(buf, serializeOpts, append([]gopacket.SerializableLayer{ip, udp}, ls...)...); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// reserialize serializes the layers decoded in pkt again, with the checksums decoded.
+func reserialize(t *testing.T, pkt gopacket.Packet) []byte {
+	t.Helper()
+
+	var ls []gopacket.SerializableLayer
+	for _, l := range pkt.Layers() {
+		sl, ok := l.(gopacket.SerializableLayer)
+		if !ok {
+			t.Fatalf("layer %s is not serializable", l.LayerType())
+		}
+		ls = append(ls, sl)
+	}
+	buf := gopacket.NewSerializeBuffer()
+	if err := gopacket.SerializeLayers(buf, gopacket.SerializeOptions{FixLengths: true}, ls...); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func decode(t *testing.T, data []byte) gopacket.Packet {
+	t.Helper()
+
+	pkt := gopacket.NewPacket(data, layers.LayerTypeIPv4, gopacket.Default)
+	if err := pkt.ErrorLayer(); err != nil {
+		t.Fatal(err.Error())
+	}
+	return pkt
+}
+
+func TestGTPv1UTPDU(t *testing.T) {
+	inner := udpPacket(t, 9999, gopacket.Payload("deadbeef"))
+	data := udpPacket(t, 2152, &gtplayers.GTPv1{Message: v1msg.NewTPDU(0x11223344, nil)}, gopacket.Payload(inner))
+
+	pkt := decode(t, data)
+	l := pkt.Layer(gtplayers.LayerTypeGTPv1)
+	if l == nil {
+		t.Fatal("GTPv1 layer not found")
+	}
+	g := l.(*gtplayers.GTPv1)
+	if g.Message.MessageType() != v1msg.MsgTypeTPDU || g.Message.TEID() != 0x11223344 {
+		t.Errorf("unexpected message: %v", g.Message)
+	}
+	if !bytes.Equal(g.LayerPayload(), inner) {
+		t.Errorf("unexpected payload: %x", g.LayerPayload())
+	}
+
+	// the user data is decoded as the next layers.
+	if n := len(pkt.Layers()); n != 6 {
+		t.Fatalf("unexpected number of layers: %d", n)
+	}
+	if pkt.Layers()[3].LayerType() != layers.LayerTypeIPv4 {
+		t.Errorf("unexpected layer after GTPv1: %s", pkt.Layers()[3].LayerType())
+	}
+
+	if got := reserialize(t, pkt); !bytes.Equal(got, data) {
+		t.Errorf("unexpected bytes serialized:\ngot:  %x\nwant: %x", got, data)
+	}
+}
+
+func TestGTPv1CEcho(t *testing.T) {
+	msg := v1msg.NewEchoRequest(1, v1ies.NewRecovery(2))
+	data := udpPacket(t, 2123, &gtplayers.GTPv1{Message: msg})
+
+	pkt := decode(t, data)
+	l := pkt.Layer(gtplayers.LayerTypeGTPv1)
+	if l == nil {
+		t.Fatal("GTPv1 layer not found")
+	}
+	got := l.(*gtplayers.GTPv1).Message
+	if got.MessageType() != v1msg.MsgTypeEchoRequest || got.Sequence() != 1 {
+		t.Errorf("unexpected message: %v", got)
+	}
+	if b := reserialize(t, pkt); !bytes.Equal(b, data) {
+		t.Errorf("unexpected bytes serialized:\ngot:  %x\nwant: %x", b, data)
+	}
+}
+
+func TestGTPv2Piggybacked(t *testing.T) {
+	csRsp := v2msg.NewCreateSessionResponse(0x11111111, 1,
+		v2ies.NewCause(16, 0, 0, 0, nil),
+	)
+	cbReq := v2msg.NewCreateBearerRequest(0x11111111, 2,
+		v2ies.NewEPSBearerID(5),
+	)
+	raw, err := v2msg.MarshalMultiMessages(csRsp, cbReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := udpPacket(t, 2123, gopacket.Payload(raw))
+
+	pkt := decode(t, data)
+	var msgs []v2msg.Message
+	for _, l := range pkt.Layers() {
+		if g, ok := l.(*gtplayers.GTPv2); ok {
+			msgs = append(msgs, g.Message)
+		}
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("unexpected number of GTPv2 layers: %d", len(msgs))
+	}
+	if msgs[0].MessageType() != v2msg.MsgTypeCreateSessionResponse || msgs[1].MessageType() != v2msg.MsgTypeCreateBearerRequest {
+		t.Errorf("unexpected messages: %s, %s", msgs[0].MessageTypeName(), msgs[1].MessageTypeName())
+	}
+	if msgs[1].Sequence() != 2 {
+		t.Errorf("unexpected Sequence of piggybacked message: %d", msgs[1].Sequence())
+	}
+
+	if got := reserialize(t, pkt); !bytes.Equal(got, data) {
+		t.Errorf("unexpected bytes serialized:\ngot:  %x\nwant: %x", got, data)
+	}
+}
+
+func TestDecodeFromBytes(t *testing.T) {
+	msg := v2msg.NewEchoRequest(1, v2ies.NewRecovery(2))
+	b, err := v2msg.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := &gtplayers.GTPv2{}
+	if err := g.DecodeFromBytes(b, gopacket.NilDecodeFeedback); err != nil {
+		t.Fatal(err)
+	}
+	if g.Message.MessageType() != v2msg.MsgTypeEchoRequest || len(g.LayerPayload()) != 0 {
+		t.Errorf("unexpected layer: %v, payload: %x", g.Message, g.LayerPayload())
+	}
+
+	buf := gopacket.NewSerializeBuffer()
+	if err := g.SerializeTo(buf, serializeOpts); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), b) {
+		t.Errorf("unexpected bytes serialized:\ngot:  %x\nwant: %x", buf.Bytes(), b)
+	}
+
+	if err := g.DecodeFromBytes(b[:3], gopacket.NilDecodeFeedback); err == nil {
+		t.Error("truncated bytes decoded without error")
+	}
+}
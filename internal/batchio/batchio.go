@@ -0,0 +1,110 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package batchio provides the batched I/O of UDP datagrams, which reads and writes
+// multiple datagrams with a single system call using recvmmsg(2) and sendmmsg(2) on
+// Linux.
+//
+// On the other platforms, or with the net.PacketConn other than *net.UDPConn, it
+// falls back to ReadFrom and WriteTo for each datagram.
+package batchio
+
+import (
+	"errors"
+	"net"
+)
+
+// Error definitions.
+var (
+	ErrEmptyBuffer        = errors.New("empty buffer given to read")
+	ErrUnsupportedAddress = errors.New("unsupported address for the socket")
+)
+
+// Message is a datagram read or written in a batch.
+type Message struct {
+	// Buf is the buffer to read the datagram into, or the datagram to be written.
+	Buf []byte
+	// N is the number of bytes read or written.
+	N int
+	// Addr is the source address of the datagram read, or the destination address
+	// of the one to be written.
+	Addr net.Addr
+}
+
+// NewMessages creates n Messages with the buffers of size bytes for reading.
+func NewMessages(n, size int) []Message {
+	ms := make([]Message, n)
+	for i := range ms {
+		ms[i].Buf = make([]byte, size)
+	}
+	return ms
+}
+
+// batcher is the platform-specific implementation of batched I/O.
+type batcher interface {
+	readBatch(ms []Message) (int, error)
+	writeBatch(ms []Message) (int, error)
+}
+
+// Conn reads and writes the datagrams in batches on net.PacketConn.
+type Conn struct {
+	pktConn net.PacketConn
+	b       batcher
+}
+
+// New creates a new Conn over pktConn.
+func New(pktConn net.PacketConn) *Conn {
+	return &Conn{pktConn: pktConn, b: newBatcher(pktConn)}
+}
+
+// Batched reports whether the system calls for batched I/O are used.
+func (c *Conn) Batched() bool {
+	return c.b != nil
+}
+
+// ReadBatch reads the datagrams into ms, and returns the number of Messages filled.
+// It blocks until at least one datagram is read, and never waits for the others.
+//
+// Without the batched I/O, it reads only one datagram at a time.
+func (c *Conn) ReadBatch(ms []Message) (int, error) {
+	if len(ms) == 0 {
+		return 0, nil
+	}
+	for i := range ms {
+		if len(ms[i].Buf) == 0 {
+			return 0, ErrEmptyBuffer
+		}
+	}
+	if c.b != nil {
+		return c.b.readBatch(ms)
+	}
+
+	n, addr, err := c.pktConn.ReadFrom(ms[0].Buf)
+	if err != nil {
+		return 0, err
+	}
+	ms[0].N, ms[0].Addr = n, addr
+	return 1, nil
+}
+
+// WriteBatch writes the datagrams in ms, and returns the number of Messages written.
+// The error is returned with the first Message that failed to be written, and the
+// ones after it are not written.
+func (c *Conn) WriteBatch(ms []Message) (int, error) {
+	if len(ms) == 0 {
+		return 0, nil
+	}
+	if c.b != nil {
+		return c.b.writeBatch(ms)
+	}
+
+	for i := range ms {
+		n, err := c.pktConn.WriteTo(ms[i].Buf, ms[i].Addr)
+		if err != nil {
+			return i, err
+		}
+		ms[i].N = n
+	}
+	return len(ms), nil
+}
@@ -0,0 +1,278 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package batchio
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// mmsghdr is struct mmsghdr in C, which is padded to the alignment of Msghdr.
+type mmsghdr struct {
+	hdr unix.Msghdr
+	len uint32
+}
+
+// scratch is the buffers given to the system calls, kept not to allocate them for
+// each batch.
+type scratch struct {
+	hdrs  []mmsghdr
+	iovs  []unix.Iovec
+	names []unix.RawSockaddrAny
+}
+
+func (s *scratch) prepare(n int) {
+	if len(s.hdrs) < n {
+		s.hdrs = make([]mmsghdr, n)
+		s.iovs = make([]unix.Iovec, n)
+		s.names = make([]unix.RawSockaddrAny, n)
+	}
+}
+
+// set sets the i-th header to point to buf and the name.
+func (s *scratch) set(i int, buf []byte, namelen uint32) {
+	s.iovs[i] = unix.Iovec{}
+	if len(buf) != 0 {
+		s.iovs[i].Base = &buf[0]
+	}
+	s.iovs[i].SetLen(len(buf))
+
+	s.hdrs[i] = mmsghdr{}
+	h := &s.hdrs[i].hdr
+	h.Name = (*byte)(unsafe.Pointer(&s.names[i]))
+	h.Namelen = namelen
+	h.Iov = &s.iovs[i]
+	h.Iovlen = 1
+}
+
+type mmsgBatcher struct {
+	rawConn syscall.RawConn
+	family  int
+
+	rmu sync.Mutex
+	r   scratch
+	wmu sync.Mutex
+	w   scratch
+}
+
+func newBatcher(pktConn net.PacketConn) batcher {
+	uc, ok := pktConn.(*net.UDPConn)
+	if !ok {
+		return nil
+	}
+	rc, err := uc.SyscallConn()
+	if err != nil {
+		return nil
+	}
+
+	var (
+		family int
+		serr   error
+	)
+	if err := rc.Control(func(fd uintptr) {
+		family, serr = unix.GetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_DOMAIN)
+	}); err != nil || serr != nil {
+		return nil
+	}
+	if family != unix.AF_INET && family != unix.AF_INET6 {
+		return nil
+	}
+	return &mmsgBatcher{rawConn: rc, family: family}
+}
+
+func (b *mmsgBatcher) readBatch(ms []Message) (int, error) {
+	b.rmu.Lock()
+	defer b.rmu.Unlock()
+
+	b.r.prepare(len(ms))
+	for i := range ms {
+		b.r.set(i, ms[i].Buf, unix.SizeofSockaddrAny)
+	}
+
+	var (
+		n     int
+		operr error
+	)
+	if err := b.rawConn.Read(func(fd uintptr) bool {
+		for {
+			r, _, errno := unix.Syscall6(
+				unix.SYS_RECVMMSG, fd, uintptr(unsafe.Pointer(&b.r.hdrs[0])), uintptr(len(ms)), 0, 0, 0,
+			)
+			switch errno {
+			case 0:
+				n = int(r)
+			case unix.EINTR:
+				continue
+			case unix.EAGAIN:
+				return false
+			default:
+				operr = os.NewSyscallError("recvmmsg", errno)
+			}
+			return true
+		}
+	}); err != nil {
+		return 0, err
+	}
+	if operr != nil {
+		return 0, operr
+	}
+
+	for i := 0; i < n; i++ {
+		ms[i].N = int(b.r.hdrs[i].len)
+		ms[i].Addr = udpAddr(&b.r.names[i])
+	}
+	return n, nil
+}
+
+func (b *mmsgBatcher) writeBatch(ms []Message) (int, error) {
+	b.wmu.Lock()
+	defer b.wmu.Unlock()
+
+	b.w.prepare(len(ms))
+	for i := range ms {
+		namelen, err := putSockaddr(&b.w.names[i], b.family, ms[i].Addr)
+		if err != nil {
+			// write the ones before it, and then report the error.
+			sent, werr := b.send(ms[:i])
+			if werr != nil {
+				return sent, werr
+			}
+			return sent, err
+		}
+		b.w.set(i, ms[i].Buf, namelen)
+	}
+	return b.send(ms)
+}
+
+// send sends ms with the headers prepared, until all of them are sent or an error
+// occurs, as sendmmsg(2) may send only some of them.
+func (b *mmsgBatcher) send(ms []Message) (int, error) {
+	sent := 0
+	for sent < len(ms) {
+		var (
+			n     int
+			operr error
+		)
+		if err := b.rawConn.Write(func(fd uintptr) bool {
+			for {
+				r, _, errno := unix.Syscall6(
+					unix.SYS_SENDMMSG, fd, uintptr(unsafe.Pointer(&b.w.hdrs[sent])), uintptr(len(ms)-sent), 0, 0, 0,
+				)
+				switch errno {
+				case 0:
+					n = int(r)
+				case unix.EINTR:
+					continue
+				case unix.EAGAIN:
+					return false
+				default:
+					operr = os.NewSyscallError("sendmmsg", errno)
+				}
+				return true
+			}
+		}); err != nil {
+			return sent, err
+		}
+		if operr != nil {
+			return sent, operr
+		}
+
+		for i := sent; i < sent+n; i++ {
+			ms[i].N = int(b.w.hdrs[i].len)
+		}
+		sent += n
+	}
+	return sent, nil
+}
+
+// putSockaddr puts addr into rsa in the family of the socket, and returns the length.
+func putSockaddr(rsa *unix.RawSockaddrAny, family int, addr net.Addr) (uint32, error) {
+	ua, ok := addr.(*net.UDPAddr)
+	if !ok {
+		return 0, ErrUnsupportedAddress
+	}
+
+	switch family {
+	case unix.AF_INET:
+		ip := ua.IP.To4()
+		if ip == nil {
+			return 0, ErrUnsupportedAddress
+		}
+		sa := (*unix.RawSockaddrInet4)(unsafe.Pointer(rsa))
+		*sa = unix.RawSockaddrInet4{Family: unix.AF_INET}
+		putPort(&sa.Port, ua.Port)
+		copy(sa.Addr[:], ip)
+		return unix.SizeofSockaddrInet4, nil
+	case unix.AF_INET6:
+		// IPv4 address is given in the IPv4-mapped form for the dual-stack socket.
+		ip := ua.IP.To16()
+		if ip == nil {
+			return 0, ErrUnsupportedAddress
+		}
+		sa := (*unix.RawSockaddrInet6)(unsafe.Pointer(rsa))
+		*sa = unix.RawSockaddrInet6{Family: unix.AF_INET6, Scope_id: zoneIndex(ua.Zone)}
+		putPort(&sa.Port, ua.Port)
+		copy(sa.Addr[:], ip)
+		return unix.SizeofSockaddrInet6, nil
+	default:
+		return 0, ErrUnsupportedAddress
+	}
+}
+
+// udpAddr returns the address in rsa, which is filled by the kernel.
+func udpAddr(rsa *unix.RawSockaddrAny) *net.UDPAddr {
+	switch rsa.Addr.Family {
+	case unix.AF_INET:
+		sa := (*unix.RawSockaddrInet4)(unsafe.Pointer(rsa))
+		ip := make(net.IP, net.IPv4len)
+		copy(ip, sa.Addr[:])
+		return &net.UDPAddr{IP: ip, Port: port(&sa.Port)}
+	case unix.AF_INET6:
+		sa := (*unix.RawSockaddrInet6)(unsafe.Pointer(rsa))
+		ip := make(net.IP, net.IPv6len)
+		copy(ip, sa.Addr[:])
+		return &net.UDPAddr{IP: ip, Port: port(&sa.Port), Zone: zoneName(sa.Scope_id)}
+	default:
+		return nil
+	}
+}
+
+// the port in sockaddr is in network byte order regardless of the host.
+func putPort(p *uint16, port int) {
+	b := (*[2]byte)(unsafe.Pointer(p))
+	b[0], b[1] = byte(port>>8), byte(port)
+}
+
+func port(p *uint16) int {
+	b := (*[2]byte)(unsafe.Pointer(p))
+	return int(b[0])<<8 | int(b[1])
+}
+
+func zoneIndex(zone string) uint32 {
+	if zone == "" {
+		return 0
+	}
+	if ifi, err := net.InterfaceByName(zone); err == nil {
+		return uint32(ifi.Index)
+	}
+	n, _ := strconv.Atoi(zone)
+	return uint32(n)
+}
+
+func zoneName(index uint32) string {
+	if index == 0 {
+		return ""
+	}
+	if ifi, err := net.InterfaceByIndex(int(index)); err == nil {
+		return ifi.Name
+	}
+	return strconv.Itoa(int(index))
+}
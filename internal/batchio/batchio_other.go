@@ -0,0 +1,13 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// +build !linux
+
+package batchio
+
+import "net"
+
+func newBatcher(pktConn net.PacketConn) batcher {
+	return nil
+}
@@ -0,0 +1,144 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package batchio_test
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/wmnsk/go-gtp/internal/batchio"
+)
+
+func listen(t testing.TB, network, addr string) *net.UDPConn {
+	t.Helper()
+	pc, err := net.ListenPacket(network, addr)
+	if err != nil {
+		t.Skip(err)
+	}
+	return pc.(*net.UDPConn)
+}
+
+func TestBatch(t *testing.T) {
+	cases := []struct {
+		description   string
+		network, addr string
+	}{
+		{"IPv4", "udp4", "127.0.0.1:0"},
+		{"IPv6", "udp6", "[::1]:0"},
+		{"Dual-stack", "udp", ":0"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			srv := listen(t, c.network, c.addr)
+			defer srv.Close()
+			cli := listen(t, c.network, c.addr)
+			defer cli.Close()
+
+			dst := srv.LocalAddr().(*net.UDPAddr)
+			if dst.IP.IsUnspecified() {
+				dst = &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: dst.Port}
+			}
+
+			payloads := [][]byte{{0x01}, {0x02, 0x02}, {0x03, 0x03, 0x03}}
+			out := make([]batchio.Message, len(payloads))
+			for i, p := range payloads {
+				out[i] = batchio.Message{Buf: p, Addr: dst}
+			}
+			n, err := batchio.New(cli).WriteBatch(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != len(out) {
+				t.Fatalf("wrong number of messages written: %d", n)
+			}
+
+			if err := srv.SetReadDeadline(time.Now().Add(3 * time.Second)); err != nil {
+				t.Fatal(err)
+			}
+			bc := batchio.New(srv)
+			in := batchio.NewMessages(len(payloads)+1, 1500)
+			var got [][]byte
+			for len(got) < len(payloads) {
+				n, err := bc.ReadBatch(in)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for _, m := range in[:n] {
+					got = append(got, append([]byte(nil), m.Buf[:m.N]...))
+					if m.Addr.(*net.UDPAddr).Port != cli.LocalAddr().(*net.UDPAddr).Port {
+						t.Errorf("wrong source address: %s", m.Addr)
+					}
+				}
+			}
+			for i, p := range payloads {
+				if !bytes.Equal(got[i], p) {
+					t.Errorf("wrong payload. want: %x, got: %x", p, got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReadBatchEmptyBuffer(t *testing.T) {
+	pc := listen(t, "udp4", "127.0.0.1:0")
+	defer pc.Close()
+
+	if _, err := batchio.New(pc).ReadBatch(make([]batchio.Message, 1)); err != batchio.ErrEmptyBuffer {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func benchmarkWrite(b *testing.B, batchSize int, write func(*batchio.Conn, net.PacketConn, []batchio.Message) error) {
+	srv := listen(b, "udp4", "127.0.0.1:0")
+	defer srv.Close()
+	cli := listen(b, "udp4", "127.0.0.1:0")
+	defer cli.Close()
+
+	// drain the datagrams not to fill the socket buffer.
+	go func() {
+		bc := batchio.New(srv)
+		ms := batchio.NewMessages(64, 1500)
+		for {
+			if _, err := bc.ReadBatch(ms); err != nil {
+				return
+			}
+		}
+	}()
+
+	ms := make([]batchio.Message, batchSize)
+	for i := range ms {
+		ms[i] = batchio.Message{Buf: make([]byte, 100), Addr: srv.LocalAddr()}
+	}
+	bc := batchio.New(cli)
+
+	b.SetBytes(int64(100 * batchSize))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := write(bc, cli, ms); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkWriteTo(b *testing.B) {
+	benchmarkWrite(b, 32, func(_ *batchio.Conn, pc net.PacketConn, ms []batchio.Message) error {
+		for _, m := range ms {
+			if _, err := pc.WriteTo(m.Buf, m.Addr); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func BenchmarkWriteBatch(b *testing.B) {
+	benchmarkWrite(b, 32, func(bc *batchio.Conn, _ net.PacketConn, ms []batchio.Message) error {
+		_, err := bc.WriteBatch(ms)
+		return err
+	})
+}
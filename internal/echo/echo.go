@@ -0,0 +1,137 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package echo builds the Echo Requests and matches the Echo Responses of GTPv1-U,
+// GTPv1-C and GTPv2-C, which is shared by the diagnostic commands.
+package echo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	gtp "github.com/wmnsk/go-gtp"
+	v1msg "github.com/wmnsk/go-gtp/v1/messages"
+	v2ies "github.com/wmnsk/go-gtp/v2/ies"
+	v2msg "github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// Protocol is the version and plane of GTP that Echo is sent with.
+type Protocol int
+
+// Protocol definitions.
+const (
+	GTPv1U Protocol = iota
+	GTPv1C
+	GTPv2C
+)
+
+// Port definitions.
+const (
+	GTPCPort = 2123
+	GTPUPort = 2152
+)
+
+// ErrNotEchoResponse indicates that the message received is not the Echo Response of
+// the Protocol.
+var ErrNotEchoResponse = errors.New("not an Echo Response")
+
+// ParseProtocol returns the Protocol from the name, which is one of "v1u", "v1c" and
+// "v2c".
+func ParseProtocol(s string) (Protocol, error) {
+	switch strings.ToLower(s) {
+	case "v1u":
+		return GTPv1U, nil
+	case "v1c":
+		return GTPv1C, nil
+	case "v2c":
+		return GTPv2C, nil
+	default:
+		return 0, fmt.Errorf("unknown protocol %q: should be one of v1u, v1c and v2c", s)
+	}
+}
+
+// String returns the name of Protocol.
+func (p Protocol) String() string {
+	switch p {
+	case GTPv1U:
+		return "GTPv1-U"
+	case GTPv1C:
+		return "GTPv1-C"
+	case GTPv2C:
+		return "GTPv2-C"
+	default:
+		return fmt.Sprintf("Protocol(%d)", int(p))
+	}
+}
+
+// Port returns the well-known port of Protocol.
+func (p Protocol) Port() int {
+	if p == GTPv1U {
+		return GTPUPort
+	}
+	return GTPCPort
+}
+
+// MaxSequence returns the largest Sequence Number of Protocol.
+func (p Protocol) MaxSequence() uint32 {
+	if p == GTPv2C {
+		return 0xffffff
+	}
+	return 0xffff
+}
+
+// Request returns the serialized Echo Request of Protocol with seq.
+//
+// GTPv2-C Echo Request has Recovery IE with restartCounter, as it is mandatory.
+func Request(p Protocol, seq uint32, restartCounter uint8) ([]byte, error) {
+	switch p {
+	case GTPv1U, GTPv1C:
+		return v1msg.NewEchoRequest(uint16(seq)).Marshal()
+	case GTPv2C:
+		return v2msg.NewEchoRequest(seq&0xffffff, v2ies.NewRecovery(restartCounter)).Marshal()
+	default:
+		return nil, fmt.Errorf("unknown protocol: %d", int(p))
+	}
+}
+
+// Response is the Echo Response received.
+type Response struct {
+	Sequence uint32
+
+	// Recovery is the restart counter of the peer, which is only available in
+	// GTPv2-C.
+	Recovery    uint8
+	HasRecovery bool
+}
+
+// ParseResponse decodes b as the Echo Response of Protocol. It returns
+// ErrNotEchoResponse if b is any other message.
+func ParseResponse(p Protocol, b []byte) (*Response, error) {
+	msg, err := gtp.Parse(b)
+	if err != nil {
+		return nil, err
+	}
+
+	switch m := msg.(type) {
+	case *v1msg.EchoResponse:
+		if p == GTPv2C {
+			return nil, ErrNotEchoResponse
+		}
+		return &Response{Sequence: uint32(m.Sequence())}, nil
+	case *v2msg.EchoResponse:
+		if p != GTPv2C {
+			return nil, ErrNotEchoResponse
+		}
+		r := &Response{Sequence: m.Sequence()}
+		if m.Recovery != nil {
+			if v, err := m.Recovery.Recovery(); err == nil {
+				r.Recovery, r.HasRecovery = v, true
+			}
+		}
+		return r, nil
+	default:
+		return nil, ErrNotEchoResponse
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package echo_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/wmnsk/go-gtp/internal/echo"
+	v1msg "github.com/wmnsk/go-gtp/v1/messages"
+	v2ies "github.com/wmnsk/go-gtp/v2/ies"
+	v2msg "github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func TestRequest(t *testing.T) {
+	cases := []struct {
+		description string
+		proto       echo.Protocol
+		want        []byte
+	}{
+		{"GTPv1-U", echo.GTPv1U, []byte{0x32, 0x01, 0x00, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00}},
+		{"GTPv1-C", echo.GTPv1C, []byte{0x32, 0x01, 0x00, 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00}},
+		{"GTPv2-C", echo.GTPv2C, []byte{0x40, 0x01, 0x00, 0x09, 0x00, 0x00, 0x01, 0x00, 0x03, 0x00, 0x01, 0x00, 0x05}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			got, err := echo.Request(c.proto, 1, 5)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(got, c.want); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseResponse(t *testing.T) {
+	v1rsp, err := v1msg.NewEchoResponse(10).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	v2rsp, err := v2msg.NewEchoResponse(0x123456, v2ies.NewRecovery(5)).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	v2req, err := echo.Request(echo.GTPv2C, 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		description string
+		proto       echo.Protocol
+		b           []byte
+		want        *echo.Response
+		err         error
+	}{
+		{"GTPv1-U", echo.GTPv1U, v1rsp, &echo.Response{Sequence: 10}, nil},
+		{"GTPv2-C", echo.GTPv2C, v2rsp, &echo.Response{Sequence: 0x123456, Recovery: 5, HasRecovery: true}, nil},
+		{"VersionMismatch", echo.GTPv1U, v2rsp, nil, echo.ErrNotEchoResponse},
+		{"Request", echo.GTPv2C, v2req, nil, echo.ErrNotEchoResponse},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			got, err := echo.ParseResponse(c.proto, c.b)
+			if err != c.err {
+				t.Fatalf("unexpected error: got %v, want %v", err, c.err)
+			}
+			if diff := cmp.Diff(got, c.want); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
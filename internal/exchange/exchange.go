@@ -0,0 +1,188 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package exchange correlates the GTPv2-C responses with the requests waiting for
+// them, and provides the other helpers shared by the node packages built on v2.Conn.
+package exchange
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// unclaimedTimeout is the duration to keep the responses nobody is waiting for, which
+// arrive either before Wait is called or after it is given up.
+const unclaimedTimeout = 30 * time.Second
+
+type key struct {
+	peer string
+	seq  uint32
+}
+
+type entry struct {
+	ch      chan messages.Message
+	created time.Time
+}
+
+// Table is a set of the requests waiting for the responses, identified by the peer
+// and the Sequence Number.
+type Table struct {
+	mu      sync.Mutex
+	entries map[key]*entry
+}
+
+// New creates a new Table.
+func New() *Table {
+	return &Table{entries: map[key]*entry{}}
+}
+
+func (t *Table) entry(k key) *entry {
+	e, ok := t.entries[k]
+	if !ok {
+		e = &entry{ch: make(chan messages.Message, 1), created: time.Now()}
+		t.entries[k] = e
+	}
+	return e
+}
+
+// Deliver passes the response from raddr to the request waiting for it. The response
+// is kept for a while if nobody is waiting for it yet.
+func (t *Table) Deliver(raddr net.Addr, rsp messages.Message) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	for k, e := range t.entries {
+		if now.Sub(e.created) > unclaimedTimeout {
+			delete(t.entries, k)
+		}
+	}
+
+	e := t.entry(key{raddr.String(), rsp.Sequence()})
+	select {
+	case e.ch <- rsp:
+	default:
+		// the retransmitted response is ignored.
+	}
+}
+
+// Wait waits for the response from raddr to the request sent with seq, until ctx is
+// done.
+func (t *Table) Wait(ctx context.Context, raddr net.Addr, seq uint32) (messages.Message, error) {
+	k := key{raddr.String(), seq}
+	t.mu.Lock()
+	e := t.entry(k)
+	t.mu.Unlock()
+
+	defer func() {
+		t.mu.Lock()
+		delete(t.entries, k)
+		t.mu.Unlock()
+	}()
+
+	select {
+	case rsp := <-e.ch:
+		return rsp, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Handle registers the HandlerFuncs that deliver the responses of the types given to
+// the Table.
+func (t *Table) Handle(c *v2.Conn, msgTypes ...uint8) {
+	handlers := map[uint8]v2.HandlerFunc{}
+	for _, typ := range msgTypes {
+		handlers[typ] = func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			t.Deliver(senderAddr, msg)
+			return nil
+		}
+	}
+	c.AddHandlers(handlers)
+}
+
+// Call sends req to raddr with c, and waits for the response until ctx is done. The
+// response is returned as it is, regardless of the Cause in it.
+func (t *Table) Call(ctx context.Context, c *v2.Conn, raddr net.Addr, req messages.Message) (messages.Message, error) {
+	seq, err := c.SendMessageTo(req, raddr)
+	if err != nil {
+		return nil, err
+	}
+	return t.Wait(ctx, raddr, seq)
+}
+
+// CreateSession creates a Session from the IEs given and adds it to c, and then sends
+// Create Session Request with them to raddr. Unlike (*v2.Conn) CreateSession, the
+// Session is added before the request is sent, as the response with the TEID unknown
+// to c is discarded. The Session is removed if it fails to send the request.
+func CreateSession(c *v2.Conn, raddr net.Addr, ie ...*ies.IE) (*v2.Session, uint32, error) {
+	sess, err := v2.NewSessionFromIEs(raddr, ie...)
+	if err != nil {
+		return nil, 0, err
+	}
+	c.AddSession(sess)
+
+	msg := messages.NewCreateSessionRequest(0, 0, ie...)
+	seq, err := c.SendMessageTo(msg, raddr)
+	if err != nil {
+		c.RemoveSession(sess)
+		return nil, 0, err
+	}
+	sess.RecordMessage(v2.DirectionOutgoing, raddr, msg)
+	return sess, seq, nil
+}
+
+// CallWithRetransmission is the same as Call, except that req is retransmitted
+// following policy until the response is received.
+func (t *Table) CallWithRetransmission(ctx context.Context, c *v2.Conn, raddr net.Addr, req messages.Message, policy *v2.RetransmitPolicy) (messages.Message, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	seq, err := c.SendRequestTo(ctx, req, raddr, policy)
+	if err != nil {
+		return nil, err
+	}
+	return t.Wait(ctx, raddr, seq)
+}
+
+// LocalIP returns the IP address of c as string.
+func LocalIP(c *v2.Conn) string {
+	if addr, ok := c.LocalAddr().(*net.UDPAddr); ok {
+		return addr.IP.String()
+	}
+	host, _, err := net.SplitHostPort(c.LocalAddr().String())
+	if err != nil {
+		return ""
+	}
+	return host
+}
+
+// NewFTEID creates a new F-TEID of ifType with the TEID unique within c, setting ip
+// as IPv4 or IPv6 address depending on its family.
+func NewFTEID(c *v2.Conn, ifType uint8, ip string) *ies.IE {
+	if parsed := net.ParseIP(ip); parsed != nil && parsed.To4() == nil {
+		return c.NewFTEID(ifType, "", ip)
+	}
+	return c.NewFTEID(ifType, ip, "")
+}
+
+// AddFTEID adds the TEID in the F-TEID IE to Session.
+func AddFTEID(sess *v2.Session, fteid *ies.IE) error {
+	it, err := fteid.InterfaceType()
+	if err != nil {
+		return err
+	}
+	teid, err := fteid.TEID()
+	if err != nil {
+		return err
+	}
+	sess.AddTEID(it, teid)
+	return nil
+}
@@ -0,0 +1,270 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package pcap provides the minimal reader and writer of the UDP datagrams in the
+// classic pcap format, which is shared by gtpmon and testutils.
+package pcap
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// Error definitions.
+var (
+	ErrInvalidHeader       = errors.New("invalid pcap header")
+	ErrUnsupportedLinkType = errors.New("unsupported link type of pcap")
+)
+
+// Link type definitions of pcap.
+const (
+	LinkTypeEthernet uint32 = 1
+	LinkTypeRaw      uint32 = 101
+	LinkTypeLinuxSLL uint32 = 113
+)
+
+// ReadUDP reads the packets in the classic pcap format from r until EOF, and calls fn
+// with the UDP datagrams over IPv4 or IPv6 in them.
+//
+// The link types supported are LinkTypeEthernet (with 802.1Q tags), LinkTypeRaw and
+// LinkTypeLinuxSLL. The packets that cannot be decoded are skipped. The pcapng format
+// is not supported.
+func ReadUDP(r io.Reader, fn func(ts time.Time, src, dst *net.UDPAddr, payload []byte)) error {
+	hdr := make([]byte, 24)
+	if _, err := io.ReadFull(r, hdr); err != nil {
+		return err
+	}
+
+	var (
+		order binary.ByteOrder
+		nano  bool
+	)
+	switch binary.LittleEndian.Uint32(hdr[0:4]) {
+	case 0xa1b2c3d4:
+		order = binary.LittleEndian
+	case 0xa1b23c4d:
+		order, nano = binary.LittleEndian, true
+	case 0xd4c3b2a1:
+		order = binary.BigEndian
+	case 0x4d3cb2a1:
+		order, nano = binary.BigEndian, true
+	default:
+		return ErrInvalidHeader
+	}
+
+	linkType := order.Uint32(hdr[20:24])
+	switch linkType {
+	case LinkTypeEthernet, LinkTypeRaw, LinkTypeLinuxSLL:
+	default:
+		return ErrUnsupportedLinkType
+	}
+
+	rec := make([]byte, 16)
+	for {
+		if _, err := io.ReadFull(r, rec); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		sec, frac := int64(order.Uint32(rec[0:4])), int64(order.Uint32(rec[4:8]))
+		if !nano {
+			frac *= 1000
+		}
+		data := make([]byte, order.Uint32(rec[8:12]))
+		if _, err := io.ReadFull(r, data); err != nil {
+			return err
+		}
+
+		src, dst, payload, ok := decodeUDP(data, linkType)
+		if !ok {
+			continue
+		}
+		fn(time.Unix(sec, frac), src, dst, payload)
+	}
+}
+
+// decodeUDP returns the addresses and the payload of the UDP datagram in the frame.
+func decodeUDP(b []byte, linkType uint32) (src, dst *net.UDPAddr, payload []byte, ok bool) {
+	var etherType uint16
+	switch linkType {
+	case LinkTypeEthernet:
+		if len(b) < 14 {
+			return nil, nil, nil, false
+		}
+		etherType, b = binary.BigEndian.Uint16(b[12:14]), b[14:]
+		// 802.1Q and 802.1ad tags.
+		for etherType == 0x8100 || etherType == 0x88a8 {
+			if len(b) < 4 {
+				return nil, nil, nil, false
+			}
+			etherType, b = binary.BigEndian.Uint16(b[2:4]), b[4:]
+		}
+	case LinkTypeLinuxSLL:
+		if len(b) < 16 {
+			return nil, nil, nil, false
+		}
+		etherType, b = binary.BigEndian.Uint16(b[14:16]), b[16:]
+	case LinkTypeRaw:
+		if len(b) == 0 {
+			return nil, nil, nil, false
+		}
+		switch b[0] >> 4 {
+		case 4:
+			etherType = 0x0800
+		case 6:
+			etherType = 0x86dd
+		}
+	}
+
+	var srcIP, dstIP net.IP
+	switch etherType {
+	case 0x0800:
+		if len(b) < 20 || b[9] != 17 {
+			return nil, nil, nil, false
+		}
+		// the fragments other than the first one do not have the UDP header.
+		if binary.BigEndian.Uint16(b[6:8])&0x1fff != 0 {
+			return nil, nil, nil, false
+		}
+		ihl := int(b[0]&0x0f) * 4
+		if ihl < 20 || len(b) < ihl {
+			return nil, nil, nil, false
+		}
+		srcIP, dstIP, b = net.IP(b[12:16]), net.IP(b[16:20]), b[ihl:]
+	case 0x86dd:
+		// the extension headers are not supported.
+		if len(b) < 40 || b[6] != 17 {
+			return nil, nil, nil, false
+		}
+		srcIP, dstIP, b = net.IP(b[8:24]), net.IP(b[24:40]), b[40:]
+	default:
+		return nil, nil, nil, false
+	}
+
+	if len(b) < 8 {
+		return nil, nil, nil, false
+	}
+	l := int(binary.BigEndian.Uint16(b[4:6]))
+	if l < 8 || len(b) < l {
+		return nil, nil, nil, false
+	}
+	src = &net.UDPAddr{IP: srcIP, Port: int(binary.BigEndian.Uint16(b[0:2]))}
+	dst = &net.UDPAddr{IP: dstIP, Port: int(binary.BigEndian.Uint16(b[2:4]))}
+	return src, dst, b[8:l], true
+}
+
+// Writer writes the UDP datagrams in the classic pcap format with LinkTypeRaw.
+//
+// The IP and UDP headers are built from the addresses given, so that the file can be
+// read with ReadUDP and the packet analyzers. It is safe for concurrent use.
+type Writer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriter writes the pcap header to w and returns the Writer.
+func NewWriter(w io.Writer) (*Writer, error) {
+	hdr := make([]byte, 24)
+	binary.LittleEndian.PutUint32(hdr[0:4], 0xa1b2c3d4)
+	binary.LittleEndian.PutUint16(hdr[4:6], 2)
+	binary.LittleEndian.PutUint16(hdr[6:8], 4)
+	binary.LittleEndian.PutUint32(hdr[16:20], 0xffff)
+	binary.LittleEndian.PutUint32(hdr[20:24], LinkTypeRaw)
+	if _, err := w.Write(hdr); err != nil {
+		return nil, err
+	}
+	return &Writer{w: w}, nil
+}
+
+// WriteUDP writes the UDP datagram sent from src to dst at ts. The addresses must be
+// of the same IP version; IPv4 is used if either of them is IPv4.
+func (w *Writer) WriteUDP(ts time.Time, src, dst *net.UDPAddr, payload []byte) error {
+	pkt := buildUDP(src, dst, payload)
+
+	rec := make([]byte, 16, 16+len(pkt))
+	binary.LittleEndian.PutUint32(rec[0:4], uint32(ts.Unix()))
+	binary.LittleEndian.PutUint32(rec[4:8], uint32(ts.Nanosecond()/1000))
+	binary.LittleEndian.PutUint32(rec[8:12], uint32(len(pkt)))
+	binary.LittleEndian.PutUint32(rec[12:16], uint32(len(pkt)))
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err := w.w.Write(append(rec, pkt...))
+	return err
+}
+
+// buildUDP returns the IP packet that carries the UDP datagram.
+func buildUDP(src, dst *net.UDPAddr, payload []byte) []byte {
+	udp := make([]byte, 8+len(payload))
+	binary.BigEndian.PutUint16(udp[0:2], uint16(src.Port))
+	binary.BigEndian.PutUint16(udp[2:4], uint16(dst.Port))
+	binary.BigEndian.PutUint16(udp[4:6], uint16(len(udp)))
+	copy(udp[8:], payload)
+
+	srcIP, dstIP := src.IP.To4(), dst.IP.To4()
+	if srcIP != nil || dstIP != nil {
+		if srcIP == nil {
+			srcIP = net.IPv4zero.To4()
+		}
+		if dstIP == nil {
+			dstIP = net.IPv4zero.To4()
+		}
+
+		// the UDP checksum is optional in IPv4, and left 0.
+		ip := make([]byte, 20, 20+len(udp))
+		ip[0] = 0x45
+		binary.BigEndian.PutUint16(ip[2:4], uint16(20+len(udp)))
+		ip[8] = 64
+		ip[9] = 17
+		copy(ip[12:16], srcIP)
+		copy(ip[16:20], dstIP)
+		binary.BigEndian.PutUint16(ip[10:12], ^sum(ip, 0))
+		return append(ip, udp...)
+	}
+
+	srcIP, dstIP = src.IP.To16(), dst.IP.To16()
+	if srcIP == nil {
+		srcIP = net.IPv6zero
+	}
+	if dstIP == nil {
+		dstIP = net.IPv6zero
+	}
+	ip := make([]byte, 40, 40+len(udp))
+	ip[0] = 0x60
+	binary.BigEndian.PutUint16(ip[4:6], uint16(len(udp)))
+	ip[6] = 17
+	ip[7] = 64
+	copy(ip[8:24], srcIP)
+	copy(ip[24:40], dstIP)
+
+	// the UDP checksum is mandatory in IPv6, calculated with the pseudo header.
+	pseudo := uint32(len(udp)) + 17
+	cs := ^sum(udp, sum(ip[8:40], uint16(pseudo>>16)+uint16(pseudo)))
+	if cs == 0 {
+		cs = 0xffff
+	}
+	binary.BigEndian.PutUint16(udp[6:8], cs)
+	return append(ip, udp...)
+}
+
+// sum returns the one's complement sum of b added to initial.
+func sum(b []byte, initial uint16) uint16 {
+	s := uint32(initial)
+	for i := 0; i+1 < len(b); i += 2 {
+		s += uint32(binary.BigEndian.Uint16(b[i : i+2]))
+	}
+	if len(b)%2 == 1 {
+		s += uint32(b[len(b)-1]) << 8
+	}
+	for s > 0xffff {
+		s = (s >> 16) + (s & 0xffff)
+	}
+	return uint16(s)
+}
@@ -0,0 +1,72 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package pcap_test
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/wmnsk/go-gtp/internal/pcap"
+)
+
+func TestWriteReadUDP(t *testing.T) {
+	cases := []struct {
+		description string
+		src, dst    *net.UDPAddr
+	}{
+		{
+			"IPv4",
+			&net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 2123},
+			&net.UDPAddr{IP: net.IPv4(127, 0, 0, 2), Port: 2123},
+		}, {
+			"IPv6",
+			&net.UDPAddr{IP: net.ParseIP("2001:db8::1"), Port: 2123},
+			&net.UDPAddr{IP: net.ParseIP("2001:db8::2"), Port: 2152},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			w, err := pcap.NewWriter(buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ts := time.Unix(1500000000, 123456000)
+			payload := []byte{0x48, 0x01, 0x00, 0x04, 0x00, 0x00, 0x01, 0x00}
+			if err := w.WriteUDP(ts, c.src, c.dst, payload); err != nil {
+				t.Fatal(err)
+			}
+
+			var n int
+			if err := pcap.ReadUDP(buf, func(got time.Time, src, dst *net.UDPAddr, b []byte) {
+				n++
+				if !got.Equal(ts) {
+					t.Errorf("wrong timestamp. want %v, got: %v", ts, got)
+				}
+				if src.String() != c.src.String() || dst.String() != c.dst.String() {
+					t.Errorf("wrong addresses. want %s > %s, got: %s > %s", c.src, c.dst, src, dst)
+				}
+				if !bytes.Equal(b, payload) {
+					t.Errorf("wrong payload. want %x, got: %x", payload, b)
+				}
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if n != 1 {
+				t.Errorf("wrong number of datagrams: %d", n)
+			}
+		})
+	}
+}
+
+func TestReadUDPInvalid(t *testing.T) {
+	if err := pcap.ReadUDP(bytes.NewReader(make([]byte, 24)), nil); err != pcap.ErrInvalidHeader {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
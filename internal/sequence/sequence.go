@@ -0,0 +1,22 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package sequence provides the helpers for the SequenceNumbers shared by the
+// versions of GTP.
+package sequence
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+)
+
+// Random returns a random SequenceNumber within mask, e.g., 0xffff for GTPv1-C and
+// 0xffffff for GTPv2-C. It returns 0 if the random value cannot be read.
+func Random(mask uint32) uint32 {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(b) & mask
+}
@@ -0,0 +1,21 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sequence_test
+
+import (
+	"testing"
+
+	"github.com/wmnsk/go-gtp/internal/sequence"
+)
+
+func TestRandom(t *testing.T) {
+	for _, mask := range []uint32{0, 0xffff, 0xffffff} {
+		for i := 0; i < 100; i++ {
+			if got := sequence.Random(mask); got&^mask != 0 {
+				t.Errorf("SequenceNumber exceeds the mask %#x: %#x", mask, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package sockopt provides the creation of UDP sockets with the socket options set,
+// such as DSCP and SO_REUSEPORT, which cannot be set after the socket is bound, and
+// the retrieval of the path MTU the kernel has discovered.
+//
+// The options other than the buffer sizes are supported only on Linux.
+package sockopt
+
+import (
+	"context"
+	"errors"
+	"net"
+	"syscall"
+)
+
+// Error definitions.
+var (
+	ErrUnsupported = errors.New("socket option not supported on this platform")
+	ErrInvalidDSCP = errors.New("DSCP should be in the range of 0 to 63")
+)
+
+// Options is the socket options set on the socket created by ListenPacket.
+// The zero value of each field leaves the default of the system unchanged.
+type Options struct {
+	// DSCP is the Differentiated Services Code Point set in the IP header of the
+	// packets sent, i.e., the upper 6 bits of Type of Service in IPv4 and Traffic
+	// Class in IPv6.
+	DSCP uint8
+	// ReusePort sets SO_REUSEPORT, which lets multiple sockets bind the same address.
+	ReusePort bool
+	// DontFragment sets the DF bit in IPv4 and disables the fragmentation by the
+	// kernel in IPv6, i.e., IP_PMTUDISC_DO for IP_MTU_DISCOVER and IPV6_MTU_DISCOVER.
+	// The packets larger than the path MTU known to the kernel fail to be sent with
+	// EMSGSIZE instead of being fragmented.
+	DontFragment bool
+	// ReadBuffer and WriteBuffer are the sizes of the receive and send buffers of
+	// the socket, i.e., SO_RCVBUF and SO_SNDBUF.
+	ReadBuffer, WriteBuffer int
+}
+
+// ListenPacket creates a net.PacketConn bound to address with the options given.
+// Giving nil as o is the same as net.ListenPacket.
+func ListenPacket(network, address string, o *Options) (net.PacketConn, error) {
+	if o == nil {
+		return net.ListenPacket(network, address)
+	}
+	if o.DSCP > 0x3f {
+		return nil, ErrInvalidDSCP
+	}
+
+	lc := &net.ListenConfig{
+		Control: func(network, address string, c syscall.RawConn) error {
+			if !o.ReusePort && !o.DontFragment && o.DSCP == 0 {
+				return nil
+			}
+			var err error
+			if cerr := c.Control(func(fd uintptr) {
+				err = o.control(fd)
+			}); cerr != nil {
+				return cerr
+			}
+			return err
+		},
+	}
+	pktConn, err := lc.ListenPacket(context.Background(), network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := o.setBuffers(pktConn); err != nil {
+		pktConn.Close()
+		return nil, err
+	}
+	return pktConn, nil
+}
+
+func (o *Options) setBuffers(pktConn net.PacketConn) error {
+	if o.ReadBuffer <= 0 && o.WriteBuffer <= 0 {
+		return nil
+	}
+	udpConn, ok := pktConn.(*net.UDPConn)
+	if !ok {
+		return ErrUnsupported
+	}
+	if o.ReadBuffer > 0 {
+		if err := udpConn.SetReadBuffer(o.ReadBuffer); err != nil {
+			return err
+		}
+	}
+	if o.WriteBuffer > 0 {
+		if err := udpConn.SetWriteBuffer(o.WriteBuffer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsMessageTooLong reports whether err is caused by sending the packet larger than
+// the path MTU with DontFragment, i.e., EMSGSIZE.
+func IsMessageTooLong(err error) bool {
+	return errors.Is(err, syscall.EMSGSIZE)
+}
@@ -0,0 +1,96 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sockopt
+
+import (
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+// control sets the options on the socket before it is bound.
+func (o *Options) control(fd uintptr) error {
+	s := int(fd)
+	if o.ReusePort {
+		if err := unix.SetsockoptInt(s, unix.SOL_SOCKET, unix.SO_REUSEPORT, 1); err != nil {
+			return err
+		}
+	}
+
+	if !o.DontFragment && o.DSCP == 0 {
+		return nil
+	}
+	family, err := unix.GetsockoptInt(s, unix.SOL_SOCKET, unix.SO_DOMAIN)
+	if err != nil {
+		return err
+	}
+
+	if o.DontFragment {
+		if err := setDontFragment(s, family); err != nil {
+			return err
+		}
+	}
+	if o.DSCP == 0 {
+		return nil
+	}
+	tos := int(o.DSCP) << 2
+	if family == unix.AF_INET6 {
+		if err := unix.SetsockoptInt(s, unix.IPPROTO_IPV6, unix.IPV6_TCLASS, tos); err != nil {
+			return err
+		}
+		// the dual-stack socket sends IPv4 packets to the IPv4-mapped addresses,
+		// which use IP_TOS instead. This fails with the IPv6-only socket.
+		_ = unix.SetsockoptInt(s, unix.IPPROTO_IP, unix.IP_TOS, tos)
+		return nil
+	}
+	return unix.SetsockoptInt(s, unix.IPPROTO_IP, unix.IP_TOS, tos)
+}
+
+func setDontFragment(s, family int) error {
+	if family == unix.AF_INET6 {
+		if err := unix.SetsockoptInt(s, unix.IPPROTO_IPV6, unix.IPV6_MTU_DISCOVER, unix.IPV6_PMTUDISC_DO); err != nil {
+			return err
+		}
+		// same as DSCP, the IPv4 packets sent from the dual-stack socket.
+		_ = unix.SetsockoptInt(s, unix.IPPROTO_IP, unix.IP_MTU_DISCOVER, unix.IP_PMTUDISC_DO)
+		return nil
+	}
+	return unix.SetsockoptInt(s, unix.IPPROTO_IP, unix.IP_MTU_DISCOVER, unix.IP_PMTUDISC_DO)
+}
+
+// PathMTU returns the path MTU toward raddr known to the kernel, which is updated
+// with ICMP "fragmentation needed" and ICMPv6 "packet too big" received on the path.
+// It is the MTU of the outgoing interface if no such ICMP has been received.
+//
+// The connected socket created to look up the route is closed before returning, and
+// no packets are sent from it.
+func PathMTU(raddr net.Addr) (int, error) {
+	uaddr, err := net.ResolveUDPAddr("udp", raddr.String())
+	if err != nil {
+		return 0, err
+	}
+	conn, err := net.DialUDP("udp", nil, uaddr)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	rc, err := conn.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+	level, opt := unix.IPPROTO_IP, unix.IP_MTU
+	if uaddr.IP.To4() == nil {
+		level, opt = unix.IPPROTO_IPV6, unix.IPV6_MTU
+	}
+
+	var mtu int
+	if cerr := rc.Control(func(fd uintptr) {
+		mtu, err = unix.GetsockoptInt(int(fd), level, opt)
+	}); cerr != nil {
+		return 0, cerr
+	}
+	return mtu, err
+}
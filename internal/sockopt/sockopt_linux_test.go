@@ -0,0 +1,104 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sockopt_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/wmnsk/go-gtp/internal/sockopt"
+	"golang.org/x/sys/unix"
+)
+
+func getsockopt(t *testing.T, pc net.PacketConn, level, opt int) int {
+	t.Helper()
+	rc, err := pc.(*net.UDPConn).SyscallConn()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var v int
+	var gerr error
+	if err := rc.Control(func(fd uintptr) {
+		v, gerr = unix.GetsockoptInt(int(fd), level, opt)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if gerr != nil {
+		t.Fatal(gerr)
+	}
+	return v
+}
+
+func TestListenPacket(t *testing.T) {
+	cases := []struct {
+		description   string
+		network, addr string
+		level, opt    int
+		dfOpt         int
+	}{
+		{"IPv4", "udp4", "127.0.0.1:0", unix.IPPROTO_IP, unix.IP_TOS, unix.IP_MTU_DISCOVER},
+		{"IPv6", "udp6", "[::1]:0", unix.IPPROTO_IPV6, unix.IPV6_TCLASS, unix.IPV6_MTU_DISCOVER},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			o := &sockopt.Options{DSCP: 46, ReusePort: true, DontFragment: true, ReadBuffer: 65536}
+			pc, err := sockopt.ListenPacket(c.network, c.addr, o)
+			if err != nil {
+				t.Skip(err)
+			}
+			defer pc.Close()
+
+			if got := getsockopt(t, pc, c.level, c.opt); got != 46<<2 {
+				t.Errorf("wrong DSCP: got %#x, want %#x", got, 46<<2)
+			}
+			if got := getsockopt(t, pc, unix.SOL_SOCKET, unix.SO_REUSEPORT); got != 1 {
+				t.Errorf("SO_REUSEPORT not set: %d", got)
+			}
+			// IP_PMTUDISC_DO and IPV6_PMTUDISC_DO are the same value.
+			if got := getsockopt(t, pc, c.level, c.dfOpt); got != unix.IP_PMTUDISC_DO {
+				t.Errorf("DontFragment not set: %d", got)
+			}
+			// the kernel doubles the value set.
+			if got := getsockopt(t, pc, unix.SOL_SOCKET, unix.SO_RCVBUF); got < 65536 {
+				t.Errorf("wrong SO_RCVBUF: %d", got)
+			}
+
+			// another socket can bind the same address with SO_REUSEPORT.
+			pc2, err := sockopt.ListenPacket(c.network, pc.LocalAddr().String(), o)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pc2.Close()
+		})
+	}
+}
+
+func TestPathMTU(t *testing.T) {
+	lo, err := net.InterfaceByName("lo")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	mtu, err := sockopt.PathMTU(&net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 2152})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the IPv4 packet cannot exceed 65535 bytes even if the MTU of lo is larger.
+	want := lo.MTU
+	if want > 0xffff {
+		want = 0xffff
+	}
+	if mtu != want {
+		t.Errorf("wrong path MTU: got %d, want %d", mtu, want)
+	}
+}
+
+func TestListenPacketInvalidDSCP(t *testing.T) {
+	if _, err := sockopt.ListenPacket("udp4", "127.0.0.1:0", &sockopt.Options{DSCP: 64}); err != sockopt.ErrInvalidDSCP {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
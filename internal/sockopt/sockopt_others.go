@@ -0,0 +1,18 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// +build !linux
+
+package sockopt
+
+import "net"
+
+func (o *Options) control(fd uintptr) error {
+	return ErrUnsupported
+}
+
+// PathMTU returns ErrUnsupported on the platforms other than Linux.
+func PathMTU(raddr net.Addr) (int, error) {
+	return 0, ErrUnsupported
+}
@@ -0,0 +1,478 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package prime
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/wmnsk/go-gtp/prime/ies"
+	"github.com/wmnsk/go-gtp/prime/messages"
+)
+
+// Conn represents a GTP' connection, over which the data records are transferred
+// from the GSNs to the CGF.
+//
+// Conn works over either UDP or TCP, which is determined by the network of the
+// address given when creating it. Over TCP, the messages are framed with the Length
+// field in the header, and the ones to be sent are written to the TCP connection
+// established with the peer.
+type Conn struct {
+	mu      sync.Mutex
+	network string
+
+	// pktConn is used over UDP.
+	pktConn net.PacketConn
+	// listener and streams are used over TCP. streams are the TCP connections
+	// established with the peers, keyed by the address of them.
+	listener net.Listener
+	streams  map[string]net.Conn
+	*msgHandlerMap
+
+	closeCh chan struct{}
+	errCh   chan error
+
+	// sequence is the last SequenceNumber used in the request.
+	sequence uint16
+
+	// pending is the Data Record Transfer Requests sent with the data records and not
+	// responded yet, keyed by the SequenceNumber.
+	pending map[uint16]*pendingRequest
+
+	// RestartCounter is the RestartCounter value in Recovery IE, which represents how many
+	// times the GTP' endpoint is restarted.
+	RestartCounter uint8
+}
+
+func newConn(network string, counter uint8, errCh chan error) *Conn {
+	return &Conn{
+		mu:            sync.Mutex{},
+		network:       network,
+		streams:       map[string]net.Conn{},
+		msgHandlerMap: newDefaultMsgHandlerMap(),
+
+		closeCh: make(chan struct{}),
+		errCh:   errCh,
+
+		pending: map[uint16]*pendingRequest{},
+
+		RestartCounter: counter,
+	}
+}
+
+// isStream reports whether network is stream-oriented, which requires the messages
+// to be framed.
+func isStream(network string) bool {
+	return strings.HasPrefix(network, "tcp")
+}
+
+// Dial sends Echo Request to raddr to check if the endpoint is alive and keep
+// connection information. If raddr is a TCP address, the TCP connection is
+// established with the endpoint before that.
+func Dial(laddr, raddr net.Addr, counter uint8, errCh chan error) (*Conn, error) {
+	c := newConn(raddr.Network(), counter, errCh)
+
+	var rd func() ([]byte, error)
+	if isStream(c.network) {
+		d := &net.Dialer{LocalAddr: laddr}
+		conn, err := d.Dial(raddr.Network(), raddr.String())
+		if err != nil {
+			return nil, err
+		}
+		c.streams[conn.RemoteAddr().String()] = conn
+
+		// if no response coming within 5 seconds, returns error.
+		if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		defer conn.SetReadDeadline(time.Time{})
+
+		rd = func() ([]byte, error) {
+			return readFrame(conn)
+		}
+	} else {
+		var err error
+		c.pktConn, err = net.ListenPacket(laddr.Network(), laddr.String())
+		if err != nil {
+			return nil, err
+		}
+
+		// if no response coming within 5 seconds, returns error.
+		if err := c.pktConn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+			c.pktConn.Close()
+			return nil, err
+		}
+		defer c.pktConn.SetReadDeadline(time.Time{})
+
+		buf := make([]byte, 1600)
+		rd = func() ([]byte, error) {
+			n, _, err := c.pktConn.ReadFrom(buf)
+			if err != nil {
+				return nil, err
+			}
+			return buf[:n], nil
+		}
+	}
+
+	// send EchoRequest to raddr.
+	if err := c.EchoRequest(raddr); err != nil {
+		c.Close()
+		return nil, err
+	}
+
+	for {
+		b, err := rd()
+		if err != nil {
+			c.Close()
+			return nil, err
+		}
+
+		// decode incoming message and wait for the EchoResponse.
+		msg, err := messages.Parse(b)
+		if err != nil {
+			c.Close()
+			return nil, err
+		}
+		if _, ok := msg.(*messages.EchoResponse); !ok {
+			continue
+		}
+
+		break
+	}
+
+	if isStream(c.network) {
+		for _, conn := range c.streams {
+			go c.serveStream(conn)
+		}
+	} else {
+		go c.serve()
+	}
+	return c, nil
+}
+
+// ListenAndServe creates a new GTP' *Conn and start serving. If laddr is a TCP
+// address, the TCP connections from the peers are accepted and served.
+func ListenAndServe(laddr net.Addr, counter uint8, errCh chan error) (*Conn, error) {
+	c := newConn(laddr.Network(), counter, errCh)
+
+	var err error
+	if isStream(c.network) {
+		c.listener, err = net.Listen(laddr.Network(), laddr.String())
+		if err != nil {
+			return nil, err
+		}
+
+		go c.accept()
+		return c, nil
+	}
+
+	c.pktConn, err = net.ListenPacket(laddr.Network(), laddr.String())
+	if err != nil {
+		return nil, err
+	}
+
+	go c.serve()
+	return c, nil
+}
+
+func (c *Conn) serve() {
+	buf := make([]byte, 1600)
+	for {
+		select {
+		case <-c.closed():
+			return
+		default:
+			// do nothing and go forward.
+		}
+
+		n, raddr, err := c.pktConn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+
+		// copy buffer, as the message is handled in another goroutine.
+		b := make([]byte, n)
+		copy(b, buf[:n])
+
+		msg, err := messages.Parse(b)
+		if err != nil {
+			go c.notifyError(errors.Wrapf(err, "failed to parse the message from %s", raddr))
+			continue
+		}
+
+		if err := c.handleMessage(raddr, msg); err != nil {
+			// errors should be handled by user
+			go c.notifyError(err)
+		}
+	}
+}
+
+func (c *Conn) accept() {
+	for {
+		conn, err := c.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		c.mu.Lock()
+		c.streams[conn.RemoteAddr().String()] = conn
+		c.mu.Unlock()
+
+		go c.serveStream(conn)
+	}
+}
+
+func (c *Conn) serveStream(conn net.Conn) {
+	defer func() {
+		c.mu.Lock()
+		delete(c.streams, conn.RemoteAddr().String())
+		c.mu.Unlock()
+		conn.Close()
+	}()
+
+	raddr := conn.RemoteAddr()
+	for {
+		select {
+		case <-c.closed():
+			return
+		default:
+			// do nothing and go forward.
+		}
+
+		b, err := readFrame(conn)
+		if err != nil {
+			return
+		}
+
+		msg, err := messages.Parse(b)
+		if err != nil {
+			go c.notifyError(errors.Wrapf(err, "failed to parse the message from %s", raddr))
+			continue
+		}
+
+		if err := c.handleMessage(raddr, msg); err != nil {
+			// errors should be handled by user
+			go c.notifyError(err)
+		}
+	}
+}
+
+// readFrame reads a GTP' message from the stream, using the Length field in the
+// header to determine the end of the message.
+func readFrame(r io.Reader) ([]byte, error) {
+	h := make([]byte, 6)
+	if _, err := io.ReadFull(r, h); err != nil {
+		return nil, err
+	}
+
+	l := 6
+	if h[0]&0x01 == 0 {
+		l = 20
+	}
+	b := make([]byte, l+int(binary.BigEndian.Uint16(h[2:4])))
+	copy(b, h)
+	if _, err := io.ReadFull(r, b[6:]); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// ReadFrom reads a packet from the connection,
+// copying the payload into p. It returns the number of
+// bytes copied into p and the return address that
+// was on the packet.
+//
+// This is available only over UDP, as the incoming messages are always read and
+// handled by Conn over TCP.
+func (c *Conn) ReadFrom(p []byte) (n int, addr net.Addr, err error) {
+	if c.pktConn == nil {
+		return 0, nil, ErrConnNotOpened
+	}
+	return c.pktConn.ReadFrom(p)
+}
+
+// WriteTo writes a packet with payload p to addr.
+//
+// Over TCP, p is written to the TCP connection established with addr, and it
+// fails with ErrConnNotOpened if there is no such connection.
+func (c *Conn) WriteTo(p []byte, addr net.Addr) (n int, err error) {
+	if !isStream(c.network) {
+		return c.pktConn.WriteTo(p, addr)
+	}
+
+	c.mu.Lock()
+	conn, ok := c.streams[addr.String()]
+	c.mu.Unlock()
+	if !ok {
+		return 0, ErrConnNotOpened
+	}
+	return conn.Write(p)
+}
+
+// closed would be used in multiple goroutines.
+// never send struct{}{} to it; instead, use close(c.closeCh).
+func (c *Conn) closed() <-chan struct{} {
+	return c.closeCh
+}
+
+// Close closes the connection.
+// Any blocked Read or Write operations will be unblocked and return errors.
+func (c *Conn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	close(c.closeCh)
+	c.pending = map[uint16]*pendingRequest{}
+
+	var err error
+	for _, conn := range c.streams {
+		if e := conn.Close(); e != nil {
+			err = e
+		}
+	}
+	if c.listener != nil {
+		if e := c.listener.Close(); e != nil {
+			err = e
+		}
+	}
+	if c.pktConn != nil {
+		if e := c.pktConn.Close(); e != nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// LocalAddr returns the local network address.
+//
+// Over TCP, it returns the address of the listener, or the local address of the
+// TCP connection established with Dial.
+func (c *Conn) LocalAddr() net.Addr {
+	if c.pktConn != nil {
+		return c.pktConn.LocalAddr()
+	}
+	if c.listener != nil {
+		return c.listener.Addr()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, conn := range c.streams {
+		return conn.LocalAddr()
+	}
+	return nil
+}
+
+// AddHandler adds a message handler to *Conn.
+//
+// By adding HandlerFuncs, *Conn will handle the specified type of message with
+// it's paired HandlerFunc when receiving. Messages without registered handlers are just
+// ignored and discarded and the user will get ErrNoHandlersFound error.
+//
+// HandlerFuncs for EchoRequest, EchoResponse, NodeAliveRequest, NodeAliveResponse and
+// DataRecordTransferResponse are registered by default. The pending requests are
+// updated with DataRecordTransferResponse before the HandlerFunc is called. They can be
+// overwritten by specifying the message type.
+func (c *Conn) AddHandler(msgType uint8, fn HandlerFunc) {
+	c.msgHandlerMap.store(msgType, fn)
+}
+
+// AddHandlers adds multiple handler funcs at a time.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) AddHandlers(funcs map[uint8]HandlerFunc) {
+	for msgType, fn := range funcs {
+		c.msgHandlerMap.store(msgType, fn)
+	}
+}
+
+func (c *Conn) handleMessage(senderAddr net.Addr, msg messages.Message) error {
+	// update pending requests first, so that HandlerFunc can see the latest state of them.
+	if err := c.updatePending(msg); err != nil {
+		go c.notifyError(err)
+	}
+
+	handle, ok := c.msgHandlerMap.load(msg.MessageType())
+	if !ok {
+		return ErrNoHandlersFound
+	}
+	go func() {
+		if err := handle(c, senderAddr, msg); err != nil {
+			c.notifyError(err)
+		}
+	}()
+
+	return nil
+}
+
+// notifyError passes err to errCh. If errCh is not available, err is just discarded
+// not to block the caller forever.
+func (c *Conn) notifyError(err error) {
+	if c.errCh == nil {
+		return
+	}
+	c.errCh <- err
+}
+
+// EchoRequest sends a EchoRequest.
+func (c *Conn) EchoRequest(raddr net.Addr) error {
+	_, err := c.SendMessageTo(messages.NewEchoRequest(0), raddr)
+	return err
+}
+
+// NodeAliveRequest sends a NodeAliveRequest with the address of the node given,
+// which is expected to be sent when the node is started.
+func (c *Conn) NodeAliveRequest(raddr net.Addr, nodeAddr string) error {
+	_, err := c.SendMessageTo(messages.NewNodeAliveRequest(0, ies.NewNodeAddress(nodeAddr)), raddr)
+	return err
+}
+
+// SendMessageTo sends a message to addr.
+// Unlike WriteTo, it sets the SequenceNumber properly and returns the one used in
+// the message.
+func (c *Conn) SendMessageTo(msg messages.Message, addr net.Addr) (uint16, error) {
+	c.mu.Lock()
+	c.sequence++
+	seq := c.sequence
+	c.mu.Unlock()
+
+	msg.SetSequenceNumber(seq)
+	b, err := messages.Marshal(msg)
+	if err != nil {
+		return seq, errors.Wrapf(err, "failed to send %T", msg)
+	}
+
+	if _, err := c.WriteTo(b, addr); err != nil {
+		return seq, errors.Wrapf(err, "failed to send %T", msg)
+	}
+	return seq, nil
+}
+
+// RespondTo sends a message(specified with "toBeSent" param) in response to
+// a message(specified with "received" param).
+//
+// This is to make it easier to handle SequenceNumber.
+func (c *Conn) RespondTo(raddr net.Addr, received, toBeSent messages.Message) error {
+	toBeSent.SetSequenceNumber(received.Sequence())
+	b := make([]byte, toBeSent.MarshalLen())
+	if err := toBeSent.MarshalTo(b); err != nil {
+		return err
+	}
+
+	if _, err := c.WriteTo(b, raddr); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Restarts returns the number of restarts in uint8.
+func (c *Conn) Restarts() uint8 {
+	return c.RestartCounter
+}
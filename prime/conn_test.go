@@ -0,0 +1,159 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package prime_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/wmnsk/go-gtp/prime"
+	"github.com/wmnsk/go-gtp/prime/ies"
+	"github.com/wmnsk/go-gtp/prime/messages"
+)
+
+func TestDataRecordTransfer(t *testing.T) {
+	cases := []struct {
+		description string
+		cgfAddr     net.Addr
+		gsnAddr     net.Addr
+	}{
+		{
+			"UDP",
+			&net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: prime.DefaultPort},
+			&net.UDPAddr{IP: net.IPv4(127, 0, 0, 2), Port: prime.DefaultPort},
+		}, {
+			"TCP",
+			&net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: prime.DefaultPort},
+			&net.TCPAddr{IP: net.IPv4(127, 0, 0, 2)},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			testDataRecordTransfer(t, c.cgfAddr, c.gsnAddr)
+		})
+	}
+}
+
+func testDataRecordTransfer(t *testing.T, cgfAddr, gsnAddr net.Addr) {
+	t.Helper()
+	errCh := make(chan error, 1)
+
+	cgfConn, err := prime.ListenAndServe(cgfAddr, 0, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cgfConn.Close()
+
+	// the CGF does not respond to the first request, so that the GSN sends it again
+	// as possibly duplicated one.
+	reqCh := make(chan *messages.DataRecordTransferRequest, 1)
+	cgfConn.AddHandler(
+		messages.MsgTypeDataRecordTransferRequest,
+		func(c *prime.Conn, senderAddr net.Addr, msg messages.Message) error {
+			req := msg.(*messages.DataRecordTransferRequest)
+			reqCh <- req
+			if req.PacketTransferCommand.MustPacketTransferCommand() == prime.PacketTransferCommandSendDataRecordPacket {
+				return nil
+			}
+
+			return c.RespondTo(senderAddr, msg, messages.NewDataRecordTransferResponse(
+				0,
+				ies.NewCause(prime.ResCauseRequestAccepted),
+				ies.NewRequestsResponded(msg.Sequence()),
+			))
+		},
+	)
+
+	gsnConn, err := prime.Dial(gsnAddr, cgfAddr, 0, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gsnConn.Close()
+
+	resCh := make(chan messages.Message, 1)
+	gsnConn.AddHandler(
+		messages.MsgTypeDataRecordTransferResponse,
+		func(c *prime.Conn, senderAddr net.Addr, msg messages.Message) error {
+			resCh <- msg
+			return nil
+		},
+	)
+
+	waitRequest := func(cmd uint8) *messages.DataRecordTransferRequest {
+		t.Helper()
+		select {
+		case req := <-reqCh:
+			if got := req.PacketTransferCommand.MustPacketTransferCommand(); got != cmd {
+				t.Fatalf("unexpected Packet Transfer Command: got %d, want %d", got, cmd)
+			}
+			return req
+		case err := <-errCh:
+			t.Fatal(err)
+		case <-time.After(3 * time.Second):
+			t.Fatal("timed out waiting for the request")
+		}
+		return nil
+	}
+	waitResponse := func(seq uint16) {
+		t.Helper()
+		select {
+		case res := <-resCh:
+			if res.Sequence() != seq {
+				t.Errorf("unexpected sequence in %T: got %d, want %d", res, res.Sequence(), seq)
+			}
+		case err := <-errCh:
+			t.Fatal(err)
+		case <-time.After(3 * time.Second):
+			t.Fatal("timed out waiting for the response")
+		}
+	}
+
+	records := [][]byte{{0xde, 0xad}, {0xbe, 0xef}}
+	seq1, err := gsnConn.SendDataRecordPacket(
+		cgfAddr, ies.NewDataRecordPacket(prime.DataRecordFormatBER, 0x1234, records...),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := waitRequest(prime.PacketTransferCommandSendDataRecordPacket)
+	if diff := cmp.Diff(req.DataRecordPacket.MustDataRecords(), records); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff(gsnConn.PendingRequests(cgfAddr), []uint16{seq1}); diff != "" {
+		t.Error(diff)
+	}
+
+	seq2, err := gsnConn.ResendDataRecordPacket(cgfAddr, seq1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seq2 == seq1 {
+		t.Errorf("sequence is not incremented: %d", seq2)
+	}
+	req = waitRequest(prime.PacketTransferCommandSendPossiblyDuplicatedDataRecordPacket)
+	if diff := cmp.Diff(req.DataRecordPacket.MustDataRecords(), records); diff != "" {
+		t.Error(diff)
+	}
+	waitResponse(seq2)
+	if got := gsnConn.PendingRequests(nil); len(got) != 0 {
+		t.Errorf("requests still pending: %v", got)
+	}
+	if _, err := gsnConn.ResendDataRecordPacket(cgfAddr, seq1); err != prime.ErrRequestNotFound {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	seq3, err := gsnConn.ReleaseDataRecordPackets(cgfAddr, seq2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req = waitRequest(prime.PacketTransferCommandReleaseDataRecordPacket)
+	if diff := cmp.Diff(req.SequenceNumbersOfReleasedPackets.MustSequenceNumbers(), []uint16{seq2}); diff != "" {
+		t.Error(diff)
+	}
+	waitResponse(seq3)
+}
@@ -0,0 +1,64 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package prime
+
+// Cause definitions.
+const (
+	ReqCauseSystemFailure uint8 = iota + 59
+	ReqCauseTransmitBuffersBecomingFull
+	ReqCauseReceiveBuffersBecomingFull
+	ReqCauseAnotherNodeAboutToGoDown
+	ReqCauseThisNodeAboutToGoDown
+)
+
+// Cause definitions.
+const (
+	ResCauseRequestAccepted  uint8 = 128
+	ResCauseCDRDecodingError uint8 = 177
+)
+
+// Cause definitions.
+const (
+	ResCauseInvalidMessageFormat uint8 = iota + 193
+	_
+	_
+	_
+	_
+	ResCauseVersionNotSupported
+	ResCauseNoResourcesAvailable
+	ResCauseServiceNotSupported
+	ResCauseMandatoryIEIncorrect
+	ResCauseMandatoryIEMissing
+	ResCauseOptionalIEIncorrect
+	ResCauseSystemFailure
+)
+
+// Cause definitions.
+const (
+	ResCauseRequestRelatedToPossiblyDuplicatedPacketsAlreadyFulfilled uint8 = iota + 252
+	ResCauseRequestAlreadyFulfilled
+	ResCauseSequenceNumbersOfReleasedCancelledPacketsIEIncorrect
+	ResCauseRequestNotFulfilled
+)
+
+// PacketTransferCommand definitions.
+const (
+	_ uint8 = iota
+	PacketTransferCommandSendDataRecordPacket
+	PacketTransferCommandSendPossiblyDuplicatedDataRecordPacket
+	PacketTransferCommandCancelDataRecordPacket
+	PacketTransferCommandReleaseDataRecordPacket
+)
+
+// DataRecordFormat definitions.
+const (
+	_ uint8 = iota
+	DataRecordFormatBER
+	DataRecordFormatUnalignedPER
+	DataRecordFormatAlignedPER
+)
+
+// DefaultPort is the port number registered for GTP'.
+const DefaultPort = 3386
@@ -0,0 +1,163 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package prime
+
+import (
+	"net"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/wmnsk/go-gtp/prime/ies"
+	"github.com/wmnsk/go-gtp/prime/messages"
+)
+
+// pendingRequest is a Data Record Transfer Request waiting for the response.
+type pendingRequest struct {
+	raddr  net.Addr
+	packet *ies.IE
+}
+
+// SendDataRecordPacket sends Data Record Transfer Request to raddr with the Data Record
+// Packet IE given, and returns the SequenceNumber used in the message.
+//
+// The request is kept in Conn until the Data Record Transfer Response is received
+// with the SequenceNumber in Requests Responded IE, so that the data records can be
+// sent again with ResendDataRecordPacket if the peer does not respond.
+func (c *Conn) SendDataRecordPacket(raddr net.Addr, packet *ies.IE, ie ...*ies.IE) (uint16, error) {
+	return c.sendDataRecordPacket(raddr, PacketTransferCommandSendDataRecordPacket, packet, ie...)
+}
+
+// ResendDataRecordPacket sends the data records in the Data Record Transfer Request
+// with the SequenceNumber given, which is not responded yet, to raddr with the Packet
+// Transfer Command "Send possibly duplicated Data Record Packet". raddr can be the
+// same peer as the original request or another one, e.g., the secondary CGF.
+//
+// It returns the new SequenceNumber used in the message, which replaces the original
+// one in the pending requests. The peer that received the original one should be
+// notified later with ReleaseDataRecordPackets or CancelDataRecordPackets to tell
+// whether the possibly duplicated data records should be used or not.
+func (c *Conn) ResendDataRecordPacket(raddr net.Addr, seq uint16, ie ...*ies.IE) (uint16, error) {
+	c.mu.Lock()
+	req, ok := c.pending[seq]
+	if ok {
+		delete(c.pending, seq)
+	}
+	c.mu.Unlock()
+	if !ok {
+		return 0, ErrRequestNotFound
+	}
+
+	return c.sendDataRecordPacket(raddr, PacketTransferCommandSendPossiblyDuplicatedDataRecordPacket, req.packet, ie...)
+}
+
+func (c *Conn) sendDataRecordPacket(raddr net.Addr, cmd uint8, packet *ies.IE, ie ...*ies.IE) (uint16, error) {
+	if packet == nil || packet.Type != ies.DataRecordPacket {
+		return 0, &RequiredIEMissingError{Type: ies.DataRecordPacket}
+	}
+
+	ie = append([]*ies.IE{ies.NewPacketTransferCommand(cmd), packet}, ie...)
+	msg := messages.NewDataRecordTransferRequest(0, ie...)
+
+	// the request should be marked as pending before sending, as the response may
+	// arrive earlier than returning from WriteTo.
+	c.mu.Lock()
+	c.sequence++
+	seq := c.sequence
+	c.pending[seq] = &pendingRequest{raddr: raddr, packet: packet}
+	c.mu.Unlock()
+
+	msg.SetSequenceNumber(seq)
+	b, err := messages.Marshal(msg)
+	if err == nil {
+		_, err = c.WriteTo(b, raddr)
+	}
+	if err != nil {
+		c.mu.Lock()
+		delete(c.pending, seq)
+		c.mu.Unlock()
+		return seq, errors.Wrapf(err, "failed to send %T", msg)
+	}
+	return seq, nil
+}
+
+// ReleaseDataRecordPackets sends Data Record Transfer Request to raddr with the Packet
+// Transfer Command "Release Data Record Packet", which tells the peer that the possibly
+// duplicated data records sent with the SequenceNumbers given should be used.
+func (c *Conn) ReleaseDataRecordPackets(raddr net.Addr, seqs ...uint16) (uint16, error) {
+	return c.SendMessageTo(messages.NewDataRecordTransferRequest(
+		0,
+		ies.NewPacketTransferCommand(PacketTransferCommandReleaseDataRecordPacket),
+		ies.NewSequenceNumbersOfReleasedPackets(seqs...),
+	), raddr)
+}
+
+// CancelDataRecordPackets sends Data Record Transfer Request to raddr with the Packet
+// Transfer Command "Cancel Data Record Packet", which tells the peer that the possibly
+// duplicated data records sent with the SequenceNumbers given should be discarded.
+func (c *Conn) CancelDataRecordPackets(raddr net.Addr, seqs ...uint16) (uint16, error) {
+	return c.SendMessageTo(messages.NewDataRecordTransferRequest(
+		0,
+		ies.NewPacketTransferCommand(PacketTransferCommandCancelDataRecordPacket),
+		ies.NewSequenceNumbersOfCancelledPackets(seqs...),
+	), raddr)
+}
+
+// PendingRequests returns the SequenceNumbers of the Data Record Transfer Requests
+// sent to raddr and not responded yet, in ascending order. Giving nil as raddr returns
+// the ones for all the peers.
+func (c *Conn) PendingRequests(raddr net.Addr) []uint16 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var seqs []uint16
+	for seq, req := range c.pending {
+		if raddr != nil && req.raddr.String() != raddr.String() {
+			continue
+		}
+		seqs = append(seqs, seq)
+	}
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+	return seqs
+}
+
+// updatePending removes the requests responded with Data Record Transfer Response from
+// the pending ones. The other messages are just ignored.
+//
+// The requests are regarded as responded if the Cause is the one of acceptance, or
+// the one telling that it has already been fulfilled. Otherwise, they are left as
+// pending, so that they can be sent again with ResendDataRecordPacket.
+func (c *Conn) updatePending(msg messages.Message) error {
+	res, ok := msg.(*messages.DataRecordTransferResponse)
+	if !ok {
+		return nil
+	}
+
+	if res.Cause == nil {
+		return &RequiredIEMissingError{Type: ies.Cause}
+	}
+	switch res.Cause.MustCause() {
+	case ResCauseRequestAccepted,
+		ResCauseRequestAlreadyFulfilled,
+		ResCauseRequestRelatedToPossiblyDuplicatedPacketsAlreadyFulfilled:
+	default:
+		return nil
+	}
+
+	seqs := []uint16{res.Sequence()}
+	if res.RequestsResponded != nil {
+		var err error
+		seqs, err = res.RequestsResponded.SequenceNumbers()
+		if err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, seq := range seqs {
+		delete(c.pending, seq)
+	}
+	return nil
+}
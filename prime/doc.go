@@ -0,0 +1,10 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package prime provides the simple and painless handling of GTP' protocol in pure Golang,
+// which is used to transfer the CDRs from the GSNs to the CGF(Charging Gateway Function).
+//
+// See messages and ies directory for the encoding/decoding feature, and Conn for the
+// networking feature over UDP and TCP.
+package prime
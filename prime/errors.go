@@ -0,0 +1,39 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package prime
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNoHandlersFound indicates that the handler func is not registered in *Conn
+	// for the incoming GTP' message. In usual cases this error should not be taken
+	// as fatal, as the other endpoint can make your program stop working just by
+	// sending unregistered messages.
+	ErrNoHandlersFound = errors.New("no handlers found for incoming message, ignoring")
+
+	// ErrUnexpectedType indicates that the type of incoming message is not expected.
+	ErrUnexpectedType = errors.New("got unexpected type of message")
+
+	// ErrConnNotOpened indicates that some operation is failed due to the status of
+	// Conn is not valid, e.g., no TCP connection is established with the peer.
+	ErrConnNotOpened = errors.New("connection is not opened")
+
+	// ErrRequestNotFound indicates that no Data Record Transfer Request waiting for the
+	// response is found with the SequenceNumber given.
+	ErrRequestNotFound = errors.New("request not found")
+)
+
+// RequiredIEMissingError indicates that the IE required is missing.
+type RequiredIEMissingError struct {
+	Type uint8
+}
+
+// Error returns error with missing IE type.
+func (e *RequiredIEMissingError) Error() string {
+	return fmt.Sprintf("required IE missing: %d", e.Type)
+}
@@ -0,0 +1,109 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package prime
+
+import (
+	"net"
+	"sync"
+
+	"github.com/wmnsk/go-gtp/prime/ies"
+	"github.com/wmnsk/go-gtp/prime/messages"
+)
+
+// HandlerFunc is a handler for specific GTP' message.
+type HandlerFunc func(c *Conn, senderAddr net.Addr, msg messages.Message) error
+
+type msgHandlerMap struct {
+	syncMap sync.Map
+}
+
+func (m *msgHandlerMap) store(msgType uint8, handler HandlerFunc) {
+	m.syncMap.Store(msgType, handler)
+}
+
+func (m *msgHandlerMap) load(msgType uint8) (HandlerFunc, bool) {
+	handler, ok := m.syncMap.Load(msgType)
+	if !ok {
+		return nil, false
+	}
+
+	return handler.(HandlerFunc), true
+}
+
+func newMsgHandlerMap(m map[uint8]HandlerFunc) *msgHandlerMap {
+	mhm := &msgHandlerMap{syncMap: sync.Map{}}
+	for k, v := range m {
+		mhm.store(k, v)
+	}
+
+	return mhm
+}
+
+// newDefaultMsgHandlerMap returns the msgHandlerMap with the default HandlerFuncs,
+// which is created for each Conn.
+func newDefaultMsgHandlerMap() *msgHandlerMap {
+	return newMsgHandlerMap(
+		map[uint8]HandlerFunc{
+			messages.MsgTypeEchoRequest:                handleEchoRequest,
+			messages.MsgTypeEchoResponse:               handleEchoResponse,
+			messages.MsgTypeNodeAliveRequest:           handleNodeAliveRequest,
+			messages.MsgTypeNodeAliveResponse:          handleNodeAliveResponse,
+			messages.MsgTypeDataRecordTransferResponse: handleDataRecordTransferResponse,
+		},
+	)
+}
+
+func handleEchoRequest(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+	// this should never happen, as the type should have been assured by
+	// msgHandlerMap before this function is called.
+	if _, ok := msg.(*messages.EchoRequest); !ok {
+		return ErrUnexpectedType
+	}
+
+	return c.RespondTo(senderAddr, msg, messages.NewEchoResponse(0, ies.NewRecovery(c.RestartCounter)))
+}
+
+func handleEchoResponse(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+	// this should never happen, as the type should have been assured by
+	// msgHandlerMap before this function is called.
+	if _, ok := msg.(*messages.EchoResponse); !ok {
+		return ErrUnexpectedType
+	}
+
+	// do nothing.
+	return nil
+}
+
+func handleNodeAliveRequest(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+	// this should never happen, as the type should have been assured by
+	// msgHandlerMap before this function is called.
+	if _, ok := msg.(*messages.NodeAliveRequest); !ok {
+		return ErrUnexpectedType
+	}
+
+	return c.RespondTo(senderAddr, msg, messages.NewNodeAliveResponse(0))
+}
+
+func handleNodeAliveResponse(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+	// this should never happen, as the type should have been assured by
+	// msgHandlerMap before this function is called.
+	if _, ok := msg.(*messages.NodeAliveResponse); !ok {
+		return ErrUnexpectedType
+	}
+
+	// do nothing.
+	return nil
+}
+
+func handleDataRecordTransferResponse(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+	// this should never happen, as the type should have been assured by
+	// msgHandlerMap before this function is called.
+	if _, ok := msg.(*messages.DataRecordTransferResponse); !ok {
+		return ErrUnexpectedType
+	}
+
+	// do nothing, as the pending requests have already been updated in Conn.
+	return nil
+}
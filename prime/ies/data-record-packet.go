@@ -0,0 +1,122 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// NewDataRecordPacket creates a new DataRecordPacket IE that contains the data records,
+// which are the CDRs encoded in the format and version given.
+func NewDataRecordPacket(format uint8, version uint16, records ...[]byte) *IE {
+	l := 4
+	for _, r := range records {
+		l += 2 + len(r)
+	}
+
+	i := New(DataRecordPacket, make([]byte, l))
+	i.Payload[0] = uint8(len(records))
+	i.Payload[1] = format
+	binary.BigEndian.PutUint16(i.Payload[2:4], version)
+
+	offset := 4
+	for _, r := range records {
+		binary.BigEndian.PutUint16(i.Payload[offset:offset+2], uint16(len(r)))
+		copy(i.Payload[offset+2:], r)
+		offset += 2 + len(r)
+	}
+	return i
+}
+
+// NumberOfDataRecords returns NumberOfDataRecords in uint8 if type matches.
+func (i *IE) NumberOfDataRecords() (uint8, error) {
+	if i.Type != DataRecordPacket {
+		return 0, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) < 1 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	return i.Payload[0], nil
+}
+
+// MustNumberOfDataRecords returns NumberOfDataRecords in uint8 if type matches.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustNumberOfDataRecords() uint8 {
+	v, _ := i.NumberOfDataRecords()
+	return v
+}
+
+// DataRecordFormat returns DataRecordFormat in uint8 if type matches.
+func (i *IE) DataRecordFormat() (uint8, error) {
+	if i.Type != DataRecordPacket {
+		return 0, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) < 2 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	return i.Payload[1], nil
+}
+
+// MustDataRecordFormat returns DataRecordFormat in uint8 if type matches.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustDataRecordFormat() uint8 {
+	v, _ := i.DataRecordFormat()
+	return v
+}
+
+// DataRecordFormatVersion returns DataRecordFormatVersion in uint16 if type matches.
+func (i *IE) DataRecordFormatVersion() (uint16, error) {
+	if i.Type != DataRecordPacket {
+		return 0, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) < 4 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	return binary.BigEndian.Uint16(i.Payload[2:4]), nil
+}
+
+// MustDataRecordFormatVersion returns DataRecordFormatVersion in uint16 if type matches.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustDataRecordFormatVersion() uint16 {
+	v, _ := i.DataRecordFormatVersion()
+	return v
+}
+
+// DataRecords returns the data records in DataRecordPacket if type matches.
+func (i *IE) DataRecords() ([][]byte, error) {
+	n, err := i.NumberOfDataRecords()
+	if err != nil {
+		return nil, err
+	}
+	if len(i.Payload) < 4 {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	var records [][]byte
+	offset := 4
+	for x := 0; x < int(n); x++ {
+		if len(i.Payload) < offset+2 {
+			return nil, io.ErrUnexpectedEOF
+		}
+		l := int(binary.BigEndian.Uint16(i.Payload[offset : offset+2]))
+		if len(i.Payload) < offset+2+l {
+			return nil, io.ErrUnexpectedEOF
+		}
+		records = append(records, i.Payload[offset+2:offset+2+l])
+		offset += 2 + l
+	}
+	return records, nil
+}
+
+// MustDataRecords returns DataRecords in [][]byte if type matches.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustDataRecords() [][]byte {
+	v, _ := i.DataRecords()
+	return v
+}
@@ -0,0 +1,126 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/wmnsk/go-gtp/prime"
+	"github.com/wmnsk/go-gtp/prime/ies"
+)
+
+func TestIE(t *testing.T) {
+	cases := []struct {
+		description string
+		structured  *ies.IE
+		Serialized  []byte
+	}{
+		{
+			"Cause",
+			ies.NewCause(prime.ResCauseRequestAccepted),
+			[]byte{0x01, 0x80},
+		}, {
+			"Recovery",
+			ies.NewRecovery(0x80),
+			[]byte{0x0e, 0x80},
+		}, {
+			"PacketTransferCommand",
+			ies.NewPacketTransferCommand(prime.PacketTransferCommandSendDataRecordPacket),
+			[]byte{0x7e, 0x01},
+		}, {
+			"SequenceNumbersOfReleasedPackets",
+			ies.NewSequenceNumbersOfReleasedPackets(1, 2),
+			[]byte{0xf9, 0x00, 0x04, 0x00, 0x01, 0x00, 0x02},
+		}, {
+			"SequenceNumbersOfCancelledPackets",
+			ies.NewSequenceNumbersOfCancelledPackets(0xffff),
+			[]byte{0xfa, 0x00, 0x02, 0xff, 0xff},
+		}, {
+			"NodeAddress/v4",
+			ies.NewNodeAddress("1.1.1.1"),
+			[]byte{0xfb, 0x00, 0x04, 0x01, 0x01, 0x01, 0x01},
+		}, {
+			"NodeAddress/v6",
+			ies.NewNodeAddress("2001::1"),
+			[]byte{
+				// Type, Length
+				0xfb, 0x00, 0x10,
+				// Value
+				0x20, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01,
+			},
+		}, {
+			"DataRecordPacket",
+			ies.NewDataRecordPacket(
+				prime.DataRecordFormatBER, 0x1234,
+				[]byte{0xde, 0xad}, []byte{0xbe, 0xef, 0x00},
+			),
+			[]byte{
+				// Type, Length
+				0xfc, 0x00, 0x0d,
+				// Number of Data Records, Format, Format Version
+				0x02, 0x01, 0x12, 0x34,
+				// Data Record 1
+				0x00, 0x02, 0xde, 0xad,
+				// Data Record 2
+				0x00, 0x03, 0xbe, 0xef, 0x00,
+			},
+		}, {
+			"RequestsResponded",
+			ies.NewRequestsResponded(1, 2, 3),
+			[]byte{0xfd, 0x00, 0x06, 0x00, 0x01, 0x00, 0x02, 0x00, 0x03},
+		}, {
+			"AddressOfRecommendedNode",
+			ies.NewAddressOfRecommendedNode("2.2.2.2"),
+			[]byte{0xfe, 0x00, 0x04, 0x02, 0x02, 0x02, 0x02},
+		}, {
+			"PrivateExtension",
+			ies.NewPrivateExtension(0x0080, []byte{0xde, 0xad, 0xbe, 0xef}),
+			[]byte{
+				// Type, Length
+				0xff, 0x00, 0x06,
+				// Value
+				0x00, 0x80, 0xde, 0xad, 0xbe, 0xef,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run("Marshal/"+c.description, func(t *testing.T) {
+			got, err := c.structured.Marshal()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(got, c.Serialized); diff != "" {
+				t.Error(diff)
+			}
+		})
+
+		t.Run("Parse/"+c.description, func(t *testing.T) {
+			got, err := ies.Parse(c.Serialized)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(got, c.structured); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestDataRecords(t *testing.T) {
+	i := ies.NewDataRecordPacket(prime.DataRecordFormatBER, 0x1234, []byte{0xde, 0xad}, []byte{0xbe, 0xef, 0x00})
+
+	got, err := i.DataRecords()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, [][]byte{{0xde, 0xad}, {0xbe, 0xef, 0x00}}); diff != "" {
+		t.Error(diff)
+	}
+}
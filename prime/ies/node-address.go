@@ -0,0 +1,73 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import (
+	"io"
+	"net"
+)
+
+// NewNodeAddress creates a new NodeAddress IE from string.
+//
+// This is encoded in the same format as Charging Gateway Address IE in GTP, and is
+// also used as Alternative Node Address IE.
+func NewNodeAddress(addr string) *IE {
+	return newIPAddressIE(NodeAddress, addr)
+}
+
+// NodeAddress returns NodeAddress value if type matches.
+func (i *IE) NodeAddress() (string, error) {
+	if i.Type != NodeAddress {
+		return "", &InvalidTypeError{Type: i.Type}
+	}
+	return i.ipAddress()
+}
+
+// MustNodeAddress returns NodeAddress in string if type matches.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustNodeAddress() string {
+	v, _ := i.NodeAddress()
+	return v
+}
+
+// NewAddressOfRecommendedNode creates a new AddressOfRecommendedNode IE from string.
+func NewAddressOfRecommendedNode(addr string) *IE {
+	return newIPAddressIE(AddressOfRecommendedNode, addr)
+}
+
+// AddressOfRecommendedNode returns AddressOfRecommendedNode value if type matches.
+func (i *IE) AddressOfRecommendedNode() (string, error) {
+	if i.Type != AddressOfRecommendedNode {
+		return "", &InvalidTypeError{Type: i.Type}
+	}
+	return i.ipAddress()
+}
+
+// MustAddressOfRecommendedNode returns AddressOfRecommendedNode in string if type matches.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustAddressOfRecommendedNode() string {
+	v, _ := i.AddressOfRecommendedNode()
+	return v
+}
+
+func newIPAddressIE(t uint8, addr string) *IE {
+	ip := net.ParseIP(addr)
+	v4 := ip.To4()
+
+	// IPv4
+	if v4 != nil {
+		return New(t, v4)
+	}
+	//IPv6
+	return New(t, ip)
+}
+
+func (i *IE) ipAddress() (string, error) {
+	if len(i.Payload) < 4 {
+		return "", io.ErrUnexpectedEOF
+	}
+
+	return net.IP(i.Payload).String(), nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// NewRequestsResponded creates a new RequestsResponded IE with the SequenceNumbers
+// of the requests that are responded.
+func NewRequestsResponded(seqs ...uint16) *IE {
+	return newSequenceNumbersIE(RequestsResponded, seqs)
+}
+
+// NewSequenceNumbersOfReleasedPackets creates a new SequenceNumbersOfReleasedPackets IE
+// with the SequenceNumbers of the packets to be released.
+func NewSequenceNumbersOfReleasedPackets(seqs ...uint16) *IE {
+	return newSequenceNumbersIE(SequenceNumbersOfReleasedPackets, seqs)
+}
+
+// NewSequenceNumbersOfCancelledPackets creates a new SequenceNumbersOfCancelledPackets IE
+// with the SequenceNumbers of the packets to be cancelled.
+func NewSequenceNumbersOfCancelledPackets(seqs ...uint16) *IE {
+	return newSequenceNumbersIE(SequenceNumbersOfCancelledPackets, seqs)
+}
+
+func newSequenceNumbersIE(t uint8, seqs []uint16) *IE {
+	i := New(t, make([]byte, 2*len(seqs)))
+	for n, seq := range seqs {
+		binary.BigEndian.PutUint16(i.Payload[2*n:2*n+2], seq)
+	}
+	return i
+}
+
+// SequenceNumbers returns the list of SequenceNumbers if type matches.
+func (i *IE) SequenceNumbers() ([]uint16, error) {
+	switch i.Type {
+	case RequestsResponded, SequenceNumbersOfReleasedPackets, SequenceNumbersOfCancelledPackets:
+	default:
+		return nil, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload)%2 != 0 {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	seqs := make([]uint16, len(i.Payload)/2)
+	for n := range seqs {
+		seqs[n] = binary.BigEndian.Uint16(i.Payload[2*n : 2*n+2])
+	}
+	return seqs, nil
+}
+
+// MustSequenceNumbers returns SequenceNumbers in []uint16 if type matches.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustSequenceNumbers() []uint16 {
+	v, _ := i.SequenceNumbers()
+	return v
+}
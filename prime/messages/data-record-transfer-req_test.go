@@ -0,0 +1,46 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/wmnsk/go-gtp/prime"
+	"github.com/wmnsk/go-gtp/prime/ies"
+	"github.com/wmnsk/go-gtp/prime/messages"
+	"github.com/wmnsk/go-gtp/prime/testutils"
+)
+
+func TestDataRecordTransferRequest(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "send-data-record-packet",
+			Structured: messages.NewDataRecordTransferRequest(
+				testutils.TestFlow.Seq,
+				ies.NewPacketTransferCommand(prime.PacketTransferCommandSendDataRecordPacket),
+				ies.NewDataRecordPacket(prime.DataRecordFormatBER, 0x1234, []byte{0xde, 0xad, 0xbe, 0xef}),
+			),
+			Serialized: []byte{
+				// Header
+				0x4f, 0xf0, 0x00, 0x0f,
+				// SequenceNumber
+				0x00, 0x01,
+				// PacketTransferCommand
+				0x7e, 0x01,
+				// DataRecordPacket
+				0xfc, 0x00, 0x0a, 0x01, 0x01, 0x12, 0x34, 0x00, 0x04, 0xde, 0xad, 0xbe, 0xef,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializable, error) {
+		v, err := messages.ParseDataRecordTransferRequest(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
@@ -0,0 +1,42 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/wmnsk/go-gtp/prime/ies"
+	"github.com/wmnsk/go-gtp/prime/messages"
+	"github.com/wmnsk/go-gtp/prime/testutils"
+)
+
+func TestEchoResponse(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "with-recovery",
+			Structured: messages.NewEchoResponse(
+				testutils.TestFlow.Seq,
+				ies.NewRecovery(0x80),
+			),
+			Serialized: []byte{
+				// Header
+				0x4f, 0x02, 0x00, 0x02,
+				// SequenceNumber
+				0x00, 0x01,
+				// Recovery
+				0x0e, 0x80,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializable, error) {
+		v, err := messages.ParseEchoResponse(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
@@ -0,0 +1,80 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/wmnsk/go-gtp/prime/messages"
+	"github.com/wmnsk/go-gtp/prime/testutils"
+)
+
+func TestHeader(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "6-octet",
+			Structured: messages.NewHeader(
+				messages.HeaderFlags(
+					2, // version
+					1, // Header Type
+				), //Flags
+				0xf0, // Message type
+				testutils.TestFlow.Seq,
+				[]byte{ // Payload
+					0xde, 0xad, 0xbe, 0xef,
+				},
+			),
+			Serialized: []byte{
+				// Flags
+				0x4f,
+				// MessageType
+				0xf0,
+				// Length
+				0x00, 0x04,
+				// SequenceNumber
+				0x00, 0x01,
+				// dummy Payload
+				0xde, 0xad, 0xbe, 0xef,
+			},
+		}, {
+			Description: "20-octet",
+			Structured: messages.NewHeader(
+				messages.HeaderFlags(
+					0, // version
+					0, // Header Type
+				), //Flags
+				0xf0, // Message type
+				testutils.TestFlow.Seq,
+				[]byte{ // Payload
+					0xde, 0xad, 0xbe, 0xef,
+				},
+			),
+			Serialized: []byte{
+				// Flags
+				0x0e,
+				// MessageType
+				0xf0,
+				// Length
+				0x00, 0x04,
+				// SequenceNumber
+				0x00, 0x01,
+				// Unused
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				// dummy Payload
+				0xde, 0xad, 0xbe, 0xef,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializable, error) {
+		v, err := messages.ParseHeader(b)
+		if err != nil {
+			return nil, err
+		}
+
+		return v, nil
+	})
+}
@@ -0,0 +1,86 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+/*
+Package messages provides encoding/decoding feature of GTP' protocol.
+*/
+package messages
+
+import (
+	"github.com/pkg/errors"
+)
+
+// MessageType definitions.
+const (
+	_ uint8 = iota
+	MsgTypeEchoRequest
+	MsgTypeEchoResponse
+	MsgTypeVersionNotSupported
+	MsgTypeNodeAliveRequest
+	MsgTypeNodeAliveResponse
+	MsgTypeRedirectionRequest
+	MsgTypeRedirectionResponse
+	MsgTypeDataRecordTransferRequest  uint8 = 240
+	MsgTypeDataRecordTransferResponse uint8 = 241
+)
+
+// Message is an interface that defines GTP' messages.
+type Message interface {
+	MarshalTo([]byte) error
+	UnmarshalBinary(b []byte) error
+	MarshalLen() int
+	String() string
+	Version() int
+	MessageType() uint8
+	MessageTypeName() string
+	Sequence() uint16
+	SetSequenceNumber(uint16)
+}
+
+// Marshal returns the byte sequence generated from a Message instance.
+// Better to use MarshalXxx instead if you know the name of message to be Serialized.
+func Marshal(g Message) ([]byte, error) {
+	b := make([]byte, g.MarshalLen())
+	if err := g.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// Parse Parses the given bytes as Message.
+func Parse(b []byte) (Message, error) {
+	if len(b) < 2 {
+		return nil, ErrTooShortToParse
+	}
+
+	var g Message
+	switch b[1] {
+	case MsgTypeEchoRequest:
+		g = &EchoRequest{}
+	case MsgTypeEchoResponse:
+		g = &EchoResponse{}
+	case MsgTypeVersionNotSupported:
+		g = &VersionNotSupported{}
+	case MsgTypeNodeAliveRequest:
+		g = &NodeAliveRequest{}
+	case MsgTypeNodeAliveResponse:
+		g = &NodeAliveResponse{}
+	case MsgTypeRedirectionRequest:
+		g = &RedirectionRequest{}
+	case MsgTypeRedirectionResponse:
+		g = &RedirectionResponse{}
+	case MsgTypeDataRecordTransferRequest:
+		g = &DataRecordTransferRequest{}
+	case MsgTypeDataRecordTransferResponse:
+		g = &DataRecordTransferResponse{}
+	default:
+		g = &Generic{}
+	}
+
+	if err := g.UnmarshalBinary(b); err != nil {
+		return nil, errors.Wrap(err, "failed to Parse Message:")
+	}
+	return g, nil
+}
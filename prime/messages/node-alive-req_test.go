@@ -0,0 +1,45 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/wmnsk/go-gtp/prime/ies"
+	"github.com/wmnsk/go-gtp/prime/messages"
+	"github.com/wmnsk/go-gtp/prime/testutils"
+)
+
+func TestNodeAliveRequest(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "with-alternative",
+			Structured: messages.NewNodeAliveRequest(
+				testutils.TestFlow.Seq,
+				ies.NewNodeAddress("1.1.1.1"),
+				ies.NewNodeAddress("2.2.2.2"),
+			),
+			Serialized: []byte{
+				// Header
+				0x4f, 0x04, 0x00, 0x0e,
+				// SequenceNumber
+				0x00, 0x01,
+				// NodeAddress
+				0xfb, 0x00, 0x04, 0x01, 0x01, 0x01, 0x01,
+				// AlternativeNodeAddress
+				0xfb, 0x00, 0x04, 0x02, 0x02, 0x02, 0x02,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializable, error) {
+		v, err := messages.ParseNodeAliveRequest(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
@@ -0,0 +1,42 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/wmnsk/go-gtp/prime/ies"
+	"github.com/wmnsk/go-gtp/prime/messages"
+	"github.com/wmnsk/go-gtp/prime/testutils"
+)
+
+func TestNodeAliveResponse(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "with-private-extension",
+			Structured: messages.NewNodeAliveResponse(
+				testutils.TestFlow.Seq,
+				ies.NewPrivateExtension(0x0080, []byte{0xde, 0xad, 0xbe, 0xef}),
+			),
+			Serialized: []byte{
+				// Header
+				0x4f, 0x05, 0x00, 0x09,
+				// SequenceNumber
+				0x00, 0x01,
+				// PrivateExtension
+				0xff, 0x00, 0x06, 0x00, 0x80, 0xde, 0xad, 0xbe, 0xef,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializable, error) {
+		v, err := messages.ParseNodeAliveResponse(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
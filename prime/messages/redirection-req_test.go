@@ -0,0 +1,46 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/wmnsk/go-gtp/prime"
+	"github.com/wmnsk/go-gtp/prime/ies"
+	"github.com/wmnsk/go-gtp/prime/messages"
+	"github.com/wmnsk/go-gtp/prime/testutils"
+)
+
+func TestRedirectionRequest(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "normal",
+			Structured: messages.NewRedirectionRequest(
+				testutils.TestFlow.Seq,
+				ies.NewCause(prime.ReqCauseThisNodeAboutToGoDown),
+				ies.NewAddressOfRecommendedNode("3.3.3.3"),
+			),
+			Serialized: []byte{
+				// Header
+				0x4f, 0x06, 0x00, 0x09,
+				// SequenceNumber
+				0x00, 0x01,
+				// Cause
+				0x01, 0x3f,
+				// AddressOfRecommendedNode
+				0xfe, 0x00, 0x04, 0x03, 0x03, 0x03, 0x03,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializable, error) {
+		v, err := messages.ParseRedirectionRequest(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
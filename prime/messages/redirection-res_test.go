@@ -0,0 +1,46 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/wmnsk/go-gtp/prime"
+	"github.com/wmnsk/go-gtp/prime/ies"
+	"github.com/wmnsk/go-gtp/prime/messages"
+	"github.com/wmnsk/go-gtp/prime/testutils"
+)
+
+func TestRedirectionResponse(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "normal",
+			Structured: messages.NewRedirectionResponse(
+				testutils.TestFlow.Seq,
+				ies.NewCause(prime.ResCauseRequestAccepted),
+				ies.NewRequestsResponded(1),
+			),
+			Serialized: []byte{
+				// Header
+				0x4f, 0x07, 0x00, 0x07,
+				// SequenceNumber
+				0x00, 0x01,
+				// Cause
+				0x01, 0x80,
+				// RequestsResponded
+				0xfd, 0x00, 0x02, 0x00, 0x01,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializable, error) {
+		v, err := messages.ParseRedirectionResponse(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
@@ -0,0 +1,38 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/wmnsk/go-gtp/prime/messages"
+	"github.com/wmnsk/go-gtp/prime/testutils"
+)
+
+func TestVersionNotSupported(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "normal",
+			Structured: messages.NewVersionNotSupported(
+				testutils.TestFlow.Seq,
+			),
+			Serialized: []byte{
+				// Header
+				0x4f, 0x03, 0x00, 0x00,
+				// SequenceNumber
+				0x00, 0x01,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializable, error) {
+		v, err := messages.ParseVersionNotSupported(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
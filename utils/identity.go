@@ -0,0 +1,221 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IMSI is the International Mobile Subscriber Identity in digits, which consists
+// of MCC, MNC and MSIN and is 6 to 15 digits long.
+type IMSI string
+
+// MSISDN is the Mobile Station International ISDN Number in digits, which is the
+// E.164 number including the country code and is 1 to 15 digits long.
+type MSISDN string
+
+// IMEI is the International Mobile Equipment Identity in digits, which is either
+// the 15-digit IMEI that ends with the Luhn check digit or the 16-digit IMEISV
+// that ends with the 2-digit Software Version Number.
+type IMEI string
+
+// InvalidIdentityError indicates that the identity is malformed.
+type InvalidIdentityError struct {
+	Type, Value, Reason string
+}
+
+// Error returns message with the type and value of the identity and the reason.
+func (e *InvalidIdentityError) Error() string {
+	return fmt.Sprintf("invalid %s %q: %s", e.Type, e.Value, e.Reason)
+}
+
+// ParseIMSI returns s as IMSI if it is valid.
+func ParseIMSI(s string) (IMSI, error) {
+	i := IMSI(s)
+	if err := i.Validate(); err != nil {
+		return "", err
+	}
+	return i, nil
+}
+
+// DecodeIMSI decodes the TBCD-encoded bytes into IMSI.
+func DecodeIMSI(b []byte) (IMSI, error) {
+	s, err := decodeTBCD("IMSI", b)
+	if err != nil {
+		return "", err
+	}
+	return ParseIMSI(s)
+}
+
+// Validate returns InvalidIdentityError if i is not a valid IMSI.
+func (i IMSI) Validate() error {
+	return validateDigits("IMSI", string(i), 6, 15)
+}
+
+// TBCD returns i encoded in TBCD with the filler "f" for the odd number of digits.
+func (i IMSI) TBCD() ([]byte, error) {
+	if err := i.Validate(); err != nil {
+		return nil, err
+	}
+	return StrToSwappedBytes(string(i), "f")
+}
+
+// MCC returns the first 3 digits of i, which is the Mobile Country Code.
+func (i IMSI) MCC() string {
+	if len(i) < 3 {
+		return ""
+	}
+	return string(i[:3])
+}
+
+// String returns i in string.
+func (i IMSI) String() string {
+	return string(i)
+}
+
+// ParseMSISDN returns s as MSISDN if it is valid.
+func ParseMSISDN(s string) (MSISDN, error) {
+	m := MSISDN(s)
+	if err := m.Validate(); err != nil {
+		return "", err
+	}
+	return m, nil
+}
+
+// DecodeMSISDN decodes the TBCD-encoded bytes into MSISDN.
+func DecodeMSISDN(b []byte) (MSISDN, error) {
+	s, err := decodeTBCD("MSISDN", b)
+	if err != nil {
+		return "", err
+	}
+	return ParseMSISDN(s)
+}
+
+// Validate returns InvalidIdentityError if m is not a valid MSISDN.
+func (m MSISDN) Validate() error {
+	return validateDigits("MSISDN", string(m), 1, 15)
+}
+
+// TBCD returns m encoded in TBCD with the filler "f" for the odd number of digits.
+func (m MSISDN) TBCD() ([]byte, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return StrToSwappedBytes(string(m), "f")
+}
+
+// String returns m in string.
+func (m MSISDN) String() string {
+	return string(m)
+}
+
+// ParseIMEI returns s as IMEI if it is a valid IMEI or IMEISV.
+func ParseIMEI(s string) (IMEI, error) {
+	i := IMEI(s)
+	if err := i.Validate(); err != nil {
+		return "", err
+	}
+	return i, nil
+}
+
+// DecodeIMEI decodes the TBCD-encoded bytes into IMEI.
+func DecodeIMEI(b []byte) (IMEI, error) {
+	s, err := decodeTBCD("IMEI", b)
+	if err != nil {
+		return "", err
+	}
+	return ParseIMEI(s)
+}
+
+// Validate returns InvalidIdentityError if i is neither a valid IMEI nor IMEISV.
+// The check digit is verified if i is IMEI.
+func (i IMEI) Validate() error {
+	s := string(i)
+	if err := validateDigits("IMEI", s, 15, 16); err != nil {
+		return err
+	}
+	if i.IsIMEISV() {
+		return nil
+	}
+	if cd := luhnCheckDigit(s[:14]); s[14] != cd {
+		return &InvalidIdentityError{
+			Type: "IMEI", Value: s, Reason: fmt.Sprintf("check digit must be %c", cd),
+		}
+	}
+	return nil
+}
+
+// IsIMEISV reports whether i is IMEISV, i.e., 16 digits long.
+func (i IMEI) IsIMEISV() bool {
+	return len(i) == 16
+}
+
+// TAC returns the first 8 digits of i, which is the Type Allocation Code.
+func (i IMEI) TAC() string {
+	if len(i) < 8 {
+		return ""
+	}
+	return string(i[:8])
+}
+
+// TBCD returns i encoded in TBCD with the filler "f" for the odd number of digits.
+func (i IMEI) TBCD() ([]byte, error) {
+	if err := i.Validate(); err != nil {
+		return nil, err
+	}
+	return StrToSwappedBytes(string(i), "f")
+}
+
+// String returns i in string.
+func (i IMEI) String() string {
+	return string(i)
+}
+
+func validateDigits(typ, s string, min, max int) error {
+	if len(s) < min || len(s) > max {
+		reason := fmt.Sprintf("must be %d to %d digits", min, max)
+		if min == max {
+			reason = fmt.Sprintf("must be %d digits", min)
+		}
+		return &InvalidIdentityError{Type: typ, Value: s, Reason: reason}
+	}
+	for n := 0; n < len(s); n++ {
+		if s[n] < '0' || s[n] > '9' {
+			return &InvalidIdentityError{
+				Type: typ, Value: s, Reason: fmt.Sprintf("non-digit character %q at %d", s[n], n),
+			}
+		}
+	}
+	return nil
+}
+
+// decodeTBCD decodes b into digits, allowing the filler only as the last one.
+func decodeTBCD(typ string, b []byte) (string, error) {
+	s := strings.TrimSuffix(SwappedBytesToStr(b, false), "f")
+	for n := 0; n < len(s); n++ {
+		if s[n] < '0' || s[n] > '9' {
+			return "", &InvalidIdentityError{
+				Type: typ, Value: s, Reason: fmt.Sprintf("non-digit TBCD value %q at %d", s[n], n),
+			}
+		}
+	}
+	return s, nil
+}
+
+// luhnCheckDigit computes the check digit of the 14-digit IMEI without it.
+func luhnCheckDigit(s string) byte {
+	var sum int
+	for n := 0; n < len(s); n++ {
+		d := int(s[len(s)-1-n] - '0')
+		if n%2 == 0 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
@@ -8,6 +8,7 @@ package utils
 import (
 	"encoding/binary"
 	"encoding/hex"
+	"io"
 )
 
 // StrToSwappedBytes returns swapped bits from a byte.
@@ -87,40 +88,50 @@ func Uint64To40(n uint64) []byte {
 }
 
 // EncodePLMN encodes MCC and MNC as BCD-encoded bytes.
+//
+// The MCC must be 3 digits and the MNC must be 2 or 3 digits. The filler "f" is
+// set in the place of the 3rd digit of MNC if it is 2 digits.
 func EncodePLMN(mcc, mnc string) ([]byte, error) {
-	c, err := StrToSwappedBytes(mcc, "f")
-	if err != nil {
+	if err := validateDigits("MCC", mcc, 3, 3); err != nil {
 		return nil, err
 	}
-	n, err := StrToSwappedBytes(mnc, "f")
-	if err != nil {
+	if err := validateDigits("MNC", mnc, 2, 3); err != nil {
 		return nil, err
 	}
 
-	// 2-digit
 	b := make([]byte, 3)
-	if len(mnc) == 2 {
-		b = append(c, n...)
-		return b, nil
+	b[0] = (mcc[1]-'0')<<4 | (mcc[0] - '0')
+	b[1] = 0xf0 | (mcc[2] - '0')
+	if len(mnc) == 3 {
+		b[1] = (mnc[2]-'0')<<4 | (mcc[2] - '0')
 	}
-
-	// 3-digit
-	b[0] = c[0]
-	b[1] = (c[1] & 0x0f) | (n[1] << 4 & 0xf0)
-	b[2] = n[0]
+	b[2] = (mnc[1]-'0')<<4 | (mnc[0] - '0')
 
 	return b, nil
 }
 
 // DecodePLMN decodes BCD-encoded bytes into MCC and MNC.
+//
+// The MNC is 2 digits if the 3rd digit of it is the filler "f", and otherwise 3
+// digits. It returns InvalidIdentityError if any of the digits is not 0-9.
 func DecodePLMN(b []byte) (mcc, mnc string, err error) {
-	raw := hex.EncodeToString(b)
+	if len(b) < 3 {
+		return "", "", io.ErrUnexpectedEOF
+	}
+
+	raw := hex.EncodeToString(b[:3])
 	mcc = string(raw[1]) + string(raw[0]) + string(raw[3])
 	mnc = string(raw[5]) + string(raw[4])
 	if string(raw[2]) != "f" {
 		mnc += string(raw[2])
 	}
 
+	if err := validateDigits("MCC", mcc, 3, 3); err != nil {
+		return "", "", err
+	}
+	if err := validateDigits("MNC", mnc, 2, 3); err != nil {
+		return "", "", err
+	}
 	return
 }
 
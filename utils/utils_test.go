@@ -127,6 +127,17 @@ func TestPLMN(t *testing.T) {
 		},
 	}
 
+	t.Run("invalid", func(t *testing.T) {
+		for _, c := range [][2]string{{"12", "45"}, {"123", "4"}, {"123", "4567"}, {"12a", "45"}} {
+			if _, err := utils.EncodePLMN(c[0], c[1]); err == nil {
+				t.Errorf("malformed PLMN is encoded: %v", c)
+			}
+		}
+		if _, _, err := utils.DecodePLMN([]byte{0x21, 0xf3}); err == nil {
+			t.Error("short PLMN is decoded")
+		}
+	})
+
 	for _, c := range cases {
 		t.Run("serialize/"+c.description, func(t *testing.T) {
 			encoded, err := utils.EncodePLMN(c.mcc, c.mnc)
@@ -154,3 +165,55 @@ func TestPLMN(t *testing.T) {
 		})
 	}
 }
+
+func TestIdentity(t *testing.T) {
+	cases := []struct {
+		description string
+		validate    func() error
+		valid       bool
+	}{
+		{"IMSI", utils.IMSI("123451234567890").Validate, true},
+		{"IMSI/too-long", utils.IMSI("1234512345678901").Validate, false},
+		{"IMSI/too-short", utils.IMSI("12345").Validate, false},
+		{"IMSI/non-digit", utils.IMSI("12345123456789a").Validate, false},
+		{"MSISDN", utils.MSISDN("819012345678").Validate, true},
+		{"MSISDN/empty", utils.MSISDN("").Validate, false},
+		{"IMEI", utils.IMEI("490154203237518").Validate, true},
+		{"IMEI/check-digit", utils.IMEI("490154203237517").Validate, false},
+		{"IMEISV", utils.IMEI("4901542032375101").Validate, true},
+		{"IMEI/too-short", utils.IMEI("49015420323751").Validate, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			err := c.validate()
+			if c.valid && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if !c.valid {
+				if _, ok := err.(*utils.InvalidIdentityError); !ok {
+					t.Errorf("InvalidIdentityError is not returned: %v", err)
+				}
+			}
+		})
+	}
+
+	t.Run("TBCD", func(t *testing.T) {
+		for _, s := range []string{"123451234567890", "819012345678"} {
+			b, err := utils.MSISDN(s).TBCD()
+			if err != nil {
+				t.Fatal(err)
+			}
+			m, err := utils.DecodeMSISDN(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(m.String(), s); diff != "" {
+				t.Error(diff)
+			}
+		}
+		if _, err := utils.DecodeIMSI([]byte{0x21, 0x43, 0x15, 0x32, 0x54, 0x76, 0x98, 0xfa}); err == nil {
+			t.Error("malformed TBCD is decoded")
+		}
+	})
+}
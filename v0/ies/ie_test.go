@@ -46,29 +46,31 @@ func TestIE(t *testing.T) {
 			"ReorderingRequired",
 			ies.NewReorderingRequired(false),
 			[]byte{0x08, 0xfe},
-		},
-		/* XXX - not implemented
-		{
+		}, {
 			"AuthenticationTriplet",
-			ies.NewAuthenticationTriplet(),
-			[]byte{},
+			ies.NewAuthenticationTriplet(
+				[]byte{0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99, 0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff},
+				[]byte{0xde, 0xad, 0xbe, 0xef},
+				[]byte{0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77},
+			),
+			[]byte{
+				0x09,
+				0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99, 0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff,
+				0xde, 0xad, 0xbe, 0xef,
+				0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77,
+			},
 		}, {
 			"MAPCause",
-			ies.NewMAPCause(),
-			[]byte{},
+			ies.NewMAPCause(1),
+			[]byte{0x0b, 0x01},
 		}, {
-			"PacketTMSISignature",
-			ies.NewPacketTMSISignature(),
-			[]byte{},
-		}, {
-			"MSValidated",
-			ies.NewMSValidated(),
-			[]byte{},
-		},*/
-		{
 			"PTMSISignature",
 			ies.NewPTMSISignature(0xbeebee),
 			[]byte{0x0c, 0xbe, 0xeb, 0xee},
+		}, {
+			"MSValidated",
+			ies.NewMSValidated(true),
+			[]byte{0x0d, 0xff},
 		}, {
 			"Recovery",
 			ies.NewRecovery(0x80),
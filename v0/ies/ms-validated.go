@@ -0,0 +1,25 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+// NewMSValidated creates a new MSValidated IE.
+func NewMSValidated(validated bool) *IE {
+	if validated {
+		return New(MSValidated, []byte{0xff})
+	}
+	return New(MSValidated, []byte{0xfe})
+}
+
+// MSValidated returns MSValidated value in bool if type matches.
+func (i *IE) MSValidated() bool {
+	if i.Type != MSValidated {
+		return false
+	}
+	if len(i.Payload) == 0 {
+		return false
+	}
+
+	return i.Payload[0]&0x01 == 1
+}
@@ -0,0 +1,151 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"github.com/wmnsk/go-gtp/v0/ies"
+)
+
+// ErrorIndication is a ErrorIndication Header and its AdditionalIEs above.
+type ErrorIndication struct {
+	*Header
+	PrivateExtension *ies.IE
+	AdditionalIEs    []*ies.IE
+}
+
+// NewErrorIndication creates a new ErrorIndication.
+func NewErrorIndication(seq, label uint16, tid uint64, ie ...*ies.IE) *ErrorIndication {
+	e := &ErrorIndication{
+		Header: NewHeader(
+			0x1e, MsgTypeErrorIndication, seq, label, tid, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.PrivateExtension:
+			e.PrivateExtension = i
+		default:
+			e.AdditionalIEs = append(e.AdditionalIEs, i)
+		}
+	}
+
+	e.SetLength()
+	return e
+}
+
+// Marshal returns the byte sequence generated from a ErrorIndication.
+func (e *ErrorIndication) Marshal() ([]byte, error) {
+	b := make([]byte, e.MarshalLen())
+	if err := e.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (e *ErrorIndication) MarshalTo(b []byte) error {
+	if len(b) < e.MarshalLen() {
+		return ErrTooShortToMarshal
+	}
+	e.Header.Payload = make([]byte, e.MarshalLen()-e.Header.MarshalLen())
+
+	offset := 0
+	if ie := e.PrivateExtension; ie != nil {
+		if err := ie.MarshalTo(e.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	for _, ie := range e.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(e.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	e.Header.SetLength()
+	return e.Header.MarshalTo(b)
+}
+
+// ParseErrorIndication parses a given byte sequence as a ErrorIndication.
+func ParseErrorIndication(b []byte) (*ErrorIndication, error) {
+	e := &ErrorIndication{}
+	if err := e.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// UnmarshalBinary parses a given byte sequence as a ErrorIndication.
+func (e *ErrorIndication) UnmarshalBinary(b []byte) error {
+	var err error
+	e.Header, err = ParseHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(e.Header.Payload) < 2 {
+		return nil
+	}
+
+	ie, err := ies.ParseMultiIEs(e.Header.Payload)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.PrivateExtension:
+			e.PrivateExtension = i
+		default:
+			e.AdditionalIEs = append(e.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length of Data.
+func (e *ErrorIndication) MarshalLen() int {
+	l := e.Header.MarshalLen() - len(e.Header.Payload)
+
+	if ie := e.PrivateExtension; ie != nil {
+		l += ie.MarshalLen()
+	}
+
+	for _, ie := range e.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (e *ErrorIndication) SetLength() {
+	e.Header.Length = uint16(e.MarshalLen() - 20)
+}
+
+// MessageTypeName returns the name of protocol.
+func (e *ErrorIndication) MessageTypeName() string {
+	return "Error Indication"
+}
+
+// TID returns the TID in human-readable string.
+func (e *ErrorIndication) TID() string {
+	return e.tid()
+}
@@ -140,43 +140,17 @@ func Parse(b []byte) (Message, error) {
 		g = &DeleteAAPDPContextReq{}
 	case MsgTypeDeleteAAPDPContextResponse:
 		g = &DeleteAAPDPContextRes{}
+	*/
 	case MsgTypeErrorIndication:
-		g = &ErrorInd{}
+		g = &ErrorIndication{}
 	case MsgTypePDUNotificationRequest:
-		g = &PDUNotificationReq{}
+		g = &PDUNotificationRequest{}
 	case MsgTypePDUNotificationResponse:
-		g = &PDUNotificationRes{}
+		g = &PDUNotificationResponse{}
 	case MsgTypePDUNotificationRejectRequest:
-		g = &PDUNotificationRejectReq{}
+		g = &PDUNotificationRejectRequest{}
 	case MsgTypePDUNotificationRejectResponse:
-		g = &PDUNotificationRejectRes{}
-	case MsgTypeSendRouteingInformationforGPRSRequest:
-		g = &SendRouteingInformationforGPRSReq{}
-	case MsgTypeSendRouteingInformationforGPRSResponse:
-		g = &SendRouteingInformationforGPRSRes{}
-	case MsgTypeFailureReportRequest:
-		g = &FailureReportReq{}
-	case MsgTypeFailureReportResponse:
-		g = &FailureReportRes{}
-	case MsgTypeNoteMSGPRSPresentRequest:
-		g = &NoteMSGPRSPresentReq{}
-	case MsgTypeNoteMSGPRSPresentResponse:
-		g = &NoteMSGPRSPresentRes{}
-	case MsgTypeIdentificationRequest:
-		g = &IdentificationReq{}
-	case MsgTypeIdentificationResponse:
-		g = &IdentificationRes{}
-	case MsgTypeSGSNContextRequest:
-		g = &SGSNContextReq{}
-	case MsgTypeSGSNContextResponse:
-		g = &SGSNContextRes{}
-	case MsgTypeSGSNContextAcknowledge:
-		g = &SGSNContextAck{}
-	case MsgTypeDataRecordTransferRequest:
-		g = &DataRecordTransferReq{}
-	case MsgTypeDataRecordTransferResponse:
-		g = &DataRecordTransferRes{}
-	*/
+		g = &PDUNotificationRejectResponse{}
 	case MsgTypeTPDU:
 		g = &TPDU{}
 	default:
@@ -0,0 +1,55 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	v0 "github.com/wmnsk/go-gtp/v0"
+	"github.com/wmnsk/go-gtp/v0/ies"
+	"github.com/wmnsk/go-gtp/v0/messages"
+	"github.com/wmnsk/go-gtp/v0/testutils"
+)
+
+func TestPDUNotificationRejectRequest(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "ms-not-responding",
+			Structured: messages.NewPDUNotificationRejectRequest(
+				testutils.TestFlow.Seq, testutils.TestFlow.Label, testutils.TestFlow.TID,
+				ies.NewCause(v0.CauseMSIsNotGPRSResponding),
+				ies.NewEndUserAddress("1.1.1.1"),
+				ies.NewAccessPointName("some.apn.example"),
+			),
+			Serialized: []byte{
+				// Header
+				0x1e, 0x1d, 0x00, 0x1f,
+				// SequenceNumber
+				0x00, 0x01, 0x00, 0x00,
+				// Sndpd
+				0xff, 0xff, 0xff, 0xff,
+				// TID
+				0x21, 0x43, 0x65, 0x87, 0x09, 0x21, 0x43, 0x55,
+				// Cause
+				0x01, 0xc4,
+				// EndUserAddress
+				0x80, 0x00, 0x06, 0xf1, 0x21, 0x01, 0x01, 0x01, 0x01,
+				// APN
+				0x83, 0x00, 0x11, 0x04, 0x73, 0x6f, 0x6d, 0x65,
+				0x03, 0x61, 0x70, 0x6e, 0x07, 0x65, 0x78, 0x61,
+				0x6d, 0x70, 0x6c, 0x65,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializable, error) {
+		v, err := messages.ParsePDUNotificationRejectRequest(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
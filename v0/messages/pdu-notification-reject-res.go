@@ -0,0 +1,165 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"github.com/wmnsk/go-gtp/v0/ies"
+)
+
+// PDUNotificationRejectResponse is a PDUNotificationRejectResponse Header and its AdditionalIEs above.
+type PDUNotificationRejectResponse struct {
+	*Header
+	Cause            *ies.IE
+	PrivateExtension *ies.IE
+	AdditionalIEs    []*ies.IE
+}
+
+// NewPDUNotificationRejectResponse creates a new PDUNotificationRejectResponse.
+func NewPDUNotificationRejectResponse(seq, label uint16, tid uint64, ie ...*ies.IE) *PDUNotificationRejectResponse {
+	p := &PDUNotificationRejectResponse{
+		Header: NewHeader(
+			0x1e, MsgTypePDUNotificationRejectResponse, seq, label, tid, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			p.Cause = i
+		case ies.PrivateExtension:
+			p.PrivateExtension = i
+		default:
+			p.AdditionalIEs = append(p.AdditionalIEs, i)
+		}
+	}
+
+	p.SetLength()
+	return p
+}
+
+// Marshal returns the byte sequence generated from a PDUNotificationRejectResponse.
+func (p *PDUNotificationRejectResponse) Marshal() ([]byte, error) {
+	b := make([]byte, p.MarshalLen())
+	if err := p.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (p *PDUNotificationRejectResponse) MarshalTo(b []byte) error {
+	if len(b) < p.MarshalLen() {
+		return ErrTooShortToMarshal
+	}
+	p.Header.Payload = make([]byte, p.MarshalLen()-p.Header.MarshalLen())
+
+	offset := 0
+	if ie := p.Cause; ie != nil {
+		if err := ie.MarshalTo(p.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := p.PrivateExtension; ie != nil {
+		if err := ie.MarshalTo(p.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	for _, ie := range p.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(p.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	p.Header.SetLength()
+	return p.Header.MarshalTo(b)
+}
+
+// ParsePDUNotificationRejectResponse parses a given byte sequence as a PDUNotificationRejectResponse.
+func ParsePDUNotificationRejectResponse(b []byte) (*PDUNotificationRejectResponse, error) {
+	p := &PDUNotificationRejectResponse{}
+	if err := p.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// UnmarshalBinary parses a given byte sequence as a PDUNotificationRejectResponse.
+func (p *PDUNotificationRejectResponse) UnmarshalBinary(b []byte) error {
+	var err error
+	p.Header, err = ParseHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(p.Header.Payload) < 2 {
+		return nil
+	}
+
+	ie, err := ies.ParseMultiIEs(p.Header.Payload)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			p.Cause = i
+		case ies.PrivateExtension:
+			p.PrivateExtension = i
+		default:
+			p.AdditionalIEs = append(p.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length of Data.
+func (p *PDUNotificationRejectResponse) MarshalLen() int {
+	l := p.Header.MarshalLen() - len(p.Header.Payload)
+
+	if ie := p.Cause; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := p.PrivateExtension; ie != nil {
+		l += ie.MarshalLen()
+	}
+
+	for _, ie := range p.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (p *PDUNotificationRejectResponse) SetLength() {
+	p.Header.Length = uint16(p.MarshalLen() - 20)
+}
+
+// MessageTypeName returns the name of protocol.
+func (p *PDUNotificationRejectResponse) MessageTypeName() string {
+	return "PDU Notification Reject Response"
+}
+
+// TID returns the TID in human-readable string.
+func (p *PDUNotificationRejectResponse) TID() string {
+	return p.tid()
+}
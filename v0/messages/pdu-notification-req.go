@@ -0,0 +1,193 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"github.com/wmnsk/go-gtp/v0/ies"
+)
+
+// PDUNotificationRequest is a PDUNotificationRequest Header and its AdditionalIEs above.
+type PDUNotificationRequest struct {
+	*Header
+	EndUserAddress           *ies.IE
+	APN                      *ies.IE
+	GGSNAddressForSignalling *ies.IE
+	PrivateExtension         *ies.IE
+	AdditionalIEs            []*ies.IE
+}
+
+// NewPDUNotificationRequest creates a new PDUNotificationRequest.
+func NewPDUNotificationRequest(seq, label uint16, tid uint64, ie ...*ies.IE) *PDUNotificationRequest {
+	p := &PDUNotificationRequest{
+		Header: NewHeader(
+			0x1e, MsgTypePDUNotificationRequest, seq, label, tid, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.EndUserAddress:
+			p.EndUserAddress = i
+		case ies.AccessPointName:
+			p.APN = i
+		case ies.GSNAddress:
+			p.GGSNAddressForSignalling = i
+		case ies.PrivateExtension:
+			p.PrivateExtension = i
+		default:
+			p.AdditionalIEs = append(p.AdditionalIEs, i)
+		}
+	}
+
+	p.SetLength()
+	return p
+}
+
+// Marshal returns the byte sequence generated from a PDUNotificationRequest.
+func (p *PDUNotificationRequest) Marshal() ([]byte, error) {
+	b := make([]byte, p.MarshalLen())
+	if err := p.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (p *PDUNotificationRequest) MarshalTo(b []byte) error {
+	if len(b) < p.MarshalLen() {
+		return ErrTooShortToMarshal
+	}
+	p.Header.Payload = make([]byte, p.MarshalLen()-p.Header.MarshalLen())
+
+	offset := 0
+	if ie := p.EndUserAddress; ie != nil {
+		if err := ie.MarshalTo(p.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := p.APN; ie != nil {
+		if err := ie.MarshalTo(p.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := p.GGSNAddressForSignalling; ie != nil {
+		if err := ie.MarshalTo(p.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := p.PrivateExtension; ie != nil {
+		if err := ie.MarshalTo(p.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	for _, ie := range p.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(p.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	p.Header.SetLength()
+	return p.Header.MarshalTo(b)
+}
+
+// ParsePDUNotificationRequest parses a given byte sequence as a PDUNotificationRequest.
+func ParsePDUNotificationRequest(b []byte) (*PDUNotificationRequest, error) {
+	p := &PDUNotificationRequest{}
+	if err := p.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// UnmarshalBinary parses a given byte sequence as a PDUNotificationRequest.
+func (p *PDUNotificationRequest) UnmarshalBinary(b []byte) error {
+	var err error
+	p.Header, err = ParseHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(p.Header.Payload) < 2 {
+		return nil
+	}
+
+	ie, err := ies.ParseMultiIEs(p.Header.Payload)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.EndUserAddress:
+			p.EndUserAddress = i
+		case ies.AccessPointName:
+			p.APN = i
+		case ies.GSNAddress:
+			p.GGSNAddressForSignalling = i
+		case ies.PrivateExtension:
+			p.PrivateExtension = i
+		default:
+			p.AdditionalIEs = append(p.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length of Data.
+func (p *PDUNotificationRequest) MarshalLen() int {
+	l := p.Header.MarshalLen() - len(p.Header.Payload)
+
+	if ie := p.EndUserAddress; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := p.APN; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := p.GGSNAddressForSignalling; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := p.PrivateExtension; ie != nil {
+		l += ie.MarshalLen()
+	}
+
+	for _, ie := range p.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (p *PDUNotificationRequest) SetLength() {
+	p.Header.Length = uint16(p.MarshalLen() - 20)
+}
+
+// MessageTypeName returns the name of protocol.
+func (p *PDUNotificationRequest) MessageTypeName() string {
+	return "PDU Notification Request"
+}
+
+// TID returns the TID in human-readable string.
+func (p *PDUNotificationRequest) TID() string {
+	return p.tid()
+}
@@ -0,0 +1,112 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1
+
+import (
+	"encoding/binary"
+
+	"github.com/wmnsk/go-gtp/internal/batchio"
+	"github.com/wmnsk/go-gtp/v1/messages"
+)
+
+// SetBatchSize sets the maximum number of datagrams read from the socket at once.
+// Giving 0 or 1 disables it, which is the default.
+//
+// On Linux, the datagrams are read with a single recvmmsg(2), and the T-PDUs in them
+// to be relayed with RelayTo are written with a single sendmmsg(2) for each
+// UPlaneConn to relay them from. On the other platforms, or if the UPlaneConn is not
+// over *net.UDPConn, it falls back to reading and writing one datagram at a time.
+// The change takes effect after the read currently blocked returns.
+func (u *UPlaneConn) SetBatchSize(n int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if n <= 1 {
+		u.batchSize = 0
+		return
+	}
+	u.batchSize = n
+}
+
+// batch returns the batchio.Conn and the size if the batched read is enabled.
+func (u *UPlaneConn) batch() (*batchio.Conn, int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.batchSize <= 1 {
+		return nil, 0
+	}
+	return u.batchConnLocked(), u.batchSize
+}
+
+// batchWriter returns the batchio.Conn to write the datagrams in batches.
+func (u *UPlaneConn) batchWriter() *batchio.Conn {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.batchConnLocked()
+}
+
+func (u *UPlaneConn) batchConnLocked() *batchio.Conn {
+	if u.batchConn == nil {
+		u.batchConn = batchio.New(u.pktConn)
+	}
+	return u.batchConn
+}
+
+// relayBatch is the T-PDUs to be relayed from the same UPlaneConn.
+type relayBatch struct {
+	conn *UPlaneConn
+	ms   []batchio.Message
+}
+
+// serveBatch reads the datagrams into ms with bc, relays the T-PDUs and handles the
+// others. The error is returned only when reading fails.
+func (u *UPlaneConn) serveBatch(bc *batchio.Conn, ms []batchio.Message) error {
+	n, err := bc.ReadBatch(ms)
+	if err != nil {
+		return err
+	}
+
+	var relays []*relayBatch
+	for _, m := range ms[:n] {
+		b := m.Buf[:m.N]
+		peer, ok := u.relayPeer(m.Addr, b)
+		if !ok {
+			// the message may refer to the buffer, which is reused for the next batch.
+			u.handleRaw(m.Addr, append([]byte(nil), b...))
+			continue
+		}
+		if peer == nil {
+			continue
+		}
+
+		var rb *relayBatch
+		for _, r := range relays {
+			if r.conn == peer.srcConn {
+				rb = r
+				break
+			}
+		}
+		if rb == nil {
+			rb = &relayBatch{conn: peer.srcConn}
+			relays = append(relays, rb)
+		}
+		rb.ms = append(rb.ms, batchio.Message{Buf: b, Addr: peer.addr})
+	}
+
+	for _, rb := range relays {
+		sent, err := rb.conn.batchWriter().WriteBatch(rb.ms)
+		for _, m := range rb.ms[:sent] {
+			rb.conn.stats.messageSent(m.Addr, m.Buf[1])
+			if m.Buf[1] == messages.MsgTypeTPDU {
+				rb.conn.stats.tpduSent(m.Addr, binary.BigEndian.Uint32(m.Buf[4:8]), len(m.Buf))
+			}
+		}
+		if err != nil {
+			go u.notifyError(err)
+		}
+	}
+	return nil
+}
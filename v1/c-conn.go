@@ -0,0 +1,513 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/wmnsk/go-gtp/internal/sequence"
+	"github.com/wmnsk/go-gtp/v1/ies"
+	"github.com/wmnsk/go-gtp/v1/messages"
+)
+
+// CPlaneConn represents a C-Plane Connection of GTPv1.
+type CPlaneConn struct {
+	mu      sync.Mutex
+	pktConn net.PacketConn
+	*msgHandlerMap
+
+	closeCh    chan struct{}
+	errCh      chan error
+	errHandler ErrorHandler
+
+	// sessions is the PDP contexts added with AddSession or created with
+	// CreatePDPContext.
+	sessions []*Session
+
+	// sequence is the last SequenceNumber used in the request.
+	sequence uint16
+
+	// RestartCounter is the RestartCounter value in Recovery IE, which represents how many
+	// times the GTPv1-C endpoint is restarted.
+	RestartCounter uint8
+}
+
+func newCPlaneConn(counter uint8, errCh chan error) *CPlaneConn {
+	return &CPlaneConn{
+		mu:            sync.Mutex{},
+		msgHandlerMap: newDefaultCPlaneHandlerMap(),
+
+		closeCh: make(chan struct{}),
+		errCh:   errCh,
+
+		RestartCounter: counter,
+	}
+}
+
+// DialCPlane sends Echo Request to raddr to check if the endpoint is alive and
+// keep connection information. It returns error if the Echo Response to it does not
+// arrive within 5 seconds.
+func DialCPlane(laddr, raddr net.Addr, counter uint8, errCh chan error) (*CPlaneConn, error) {
+	return DialCPlaneWithSocketOptions(laddr, raddr, counter, errCh, nil)
+}
+
+// DialCPlaneWithSocketOptions works the same as DialCPlane, with the SocketOptions
+// set on the socket created. Giving nil as so is the same as DialCPlane.
+func DialCPlaneWithSocketOptions(laddr, raddr net.Addr, counter uint8, errCh chan error, so *SocketOptions) (*CPlaneConn, error) {
+	c := newCPlaneConn(counter, errCh)
+
+	// setup UDPConn first.
+	var err error
+	c.pktConn, err = so.listenPacket(laddr.Network(), laddr.String())
+	if err != nil {
+		return nil, err
+	}
+
+	// if no response coming within 5 seconds, returns error.
+	if err := c.pktConn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		return nil, err
+	}
+
+	// send EchoRequest to raddr.
+	seq, err := c.SendMessageTo(messages.NewEchoRequest(0, ies.NewRecovery(c.RestartCounter)), raddr)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 1600)
+	for {
+		n, _, err := c.pktConn.ReadFrom(buf)
+		if err != nil {
+			return nil, err
+		}
+
+		// the other messages are discarded until the EchoResponse to the request
+		// arrives, keeping the deadline.
+		msg, err := messages.Parse(buf[:n])
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := msg.(*messages.EchoResponse); ok && msg.Sequence() == seq {
+			break
+		}
+	}
+	if err := c.pktConn.SetReadDeadline(time.Time{}); err != nil {
+		return nil, err
+	}
+
+	go c.serve()
+	return c, nil
+}
+
+// ListenAndServeCPlane creates a new GTPv1-C *CPlaneConn and start serving.
+func ListenAndServeCPlane(laddr net.Addr, counter uint8, errCh chan error) (*CPlaneConn, error) {
+	return ListenAndServeCPlaneWithSocketOptions(laddr, counter, errCh, nil)
+}
+
+// ListenAndServeCPlaneWithSocketOptions works the same as ListenAndServeCPlane, with
+// the SocketOptions set on the socket created. Giving nil as so is the same as
+// ListenAndServeCPlane.
+func ListenAndServeCPlaneWithSocketOptions(laddr net.Addr, counter uint8, errCh chan error, so *SocketOptions) (*CPlaneConn, error) {
+	c := newCPlaneConn(counter, errCh)
+
+	var err error
+	c.pktConn, err = so.listenPacket(laddr.Network(), laddr.String())
+	if err != nil {
+		return nil, err
+	}
+
+	go c.serve()
+	return c, nil
+}
+
+// NewCPlaneConn creates a new GTPv1-C *CPlaneConn over existing net.PacketConn and
+// starts serving, without sending Echo Request unlike DialCPlane.
+//
+// This is for the special situation that the user already has a net.PacketConn to be
+// used for GTPv1-C, e.g., the one returned by VersionConn of GTPv2-C Conn to serve
+// both versions on the same socket. Closing CPlaneConn closes pktConn.
+func NewCPlaneConn(pktConn net.PacketConn, counter uint8, errCh chan error) *CPlaneConn {
+	c := newCPlaneConn(counter, errCh)
+	c.pktConn = pktConn
+
+	go c.serve()
+	return c
+}
+
+func (c *CPlaneConn) serve() {
+	buf := make([]byte, 1600)
+	for {
+		select {
+		case <-c.closed():
+			return
+		default:
+			// do nothing and go forward.
+		}
+
+		n, raddr, err := c.pktConn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+
+		msg, err := messages.Parse(buf[:n])
+		if err != nil {
+			if fn := c.errorHandler(); fn != nil {
+				fn(errors.Wrapf(err, "failed to parse the message from %s", raddr))
+			}
+			continue
+		}
+
+		if err := c.handleMessage(raddr, msg); err != nil {
+			// errors should be handled by user
+			go c.notifyError(err)
+		}
+	}
+}
+
+// ReadFrom reads a packet from the connection,
+// copying the payload into p. It returns the number of
+// bytes copied into p and the return address that
+// was on the packet.
+// It returns the number of bytes read (0 <= n <= len(p))
+// and any error encountered. Callers should always process
+// the n > 0 bytes returned before considering the error err.
+// ReadFrom can be made to time out and return
+// an Error with Timeout() == true after a fixed time limit;
+// see SetDeadline and SetReadDeadline.
+func (c *CPlaneConn) ReadFrom(p []byte) (n int, addr net.Addr, err error) {
+	return c.pktConn.ReadFrom(p)
+}
+
+// WriteTo writes a packet with payload p to addr.
+// WriteTo can be made to time out and return
+// an Error with Timeout() == true after a fixed time limit;
+// see SetDeadline and SetWriteDeadline.
+// On packet-oriented connections, write timeouts are rare.
+func (c *CPlaneConn) WriteTo(p []byte, addr net.Addr) (n int, err error) {
+	return c.pktConn.WriteTo(p, addr)
+}
+
+// closed would be used in multiple goroutines.
+// never send struct{}{} to it; instead, use close(c.closeCh).
+func (c *CPlaneConn) closed() <-chan struct{} {
+	return c.closeCh
+}
+
+// Close closes the connection.
+// Any blocked Read or Write operations will be unblocked and return errors.
+func (c *CPlaneConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	close(c.closeCh)
+	c.sessions = nil
+
+	return c.pktConn.Close()
+}
+
+// LocalAddr returns the local network address.
+func (c *CPlaneConn) LocalAddr() net.Addr {
+	return c.pktConn.LocalAddr()
+}
+
+// SetDeadline sets the read and write deadlines associated
+// with the connection. It is equivalent to calling both
+// SetReadDeadline and SetWriteDeadline.
+//
+// A deadline is an absolute time after which I/O operations
+// fail with a timeout (see type Error) instead of
+// blocking. The deadline applies to all future and pending
+// I/O, not just the immediately following call to Read or
+// Write. After a deadline has been exceeded, the connection
+// can be refreshed by setting a deadline in the future.
+//
+// An idle timeout can be implemented by repeatedly extending
+// the deadline after successful Read or Write calls.
+//
+// A zero value for t means I/O operations will not time out.
+func (c *CPlaneConn) SetDeadline(t time.Time) error {
+	return c.pktConn.SetDeadline(t)
+}
+
+// SetReadDeadline sets the deadline for future Read calls
+// and any currently-blocked Read call.
+// A zero value for t means Read will not time out.
+func (c *CPlaneConn) SetReadDeadline(t time.Time) error {
+	return c.pktConn.SetReadDeadline(t)
+}
+
+// SetWriteDeadline sets the deadline for future Write calls
+// and any currently-blocked Write call.
+// Even if write times out, it may return n > 0, indicating that
+// some of the data was successfully written.
+// A zero value for t means Write will not time out.
+func (c *CPlaneConn) SetWriteDeadline(t time.Time) error {
+	return c.pktConn.SetWriteDeadline(t)
+}
+
+// AddHandler adds a message handler to *CPlaneConn.
+//
+// By adding HandlerFuncs, *CPlaneConn will handle the specified type of message with
+// it's paired HandlerFunc when receiving. Messages without registered handlers are just
+// ignored and discarded and the user will get ErrNoHandlersFound error.
+//
+// HandlerFuncs for EchoRequest, EchoResponse and the responses to CreatePDPContext,
+// UpdatePDPContext and DeletePDPContext are registered by default. The Sessions are
+// updated with the responses before the HandlerFunc is called, and the default ones
+// just do nothing after it. They can be overwritten by specifying the message type,
+// e.g., to know when PDP context is established with the response.
+func (c *CPlaneConn) AddHandler(msgType uint8, fn HandlerFunc) {
+	c.msgHandlerMap.store(msgType, fn)
+}
+
+// AddHandlers adds multiple handler funcs at a time.
+//
+// See AddHandler for detailed usage.
+func (c *CPlaneConn) AddHandlers(funcs map[uint8]HandlerFunc) {
+	for msgType, fn := range funcs {
+		c.msgHandlerMap.store(msgType, fn)
+	}
+}
+
+func (c *CPlaneConn) handleMessage(senderAddr net.Addr, msg messages.Message) error {
+	// update Sessions first, so that HandlerFunc can see the latest state of them.
+	if err := c.updateSession(msg); err != nil {
+		go c.notifyError(err)
+	}
+
+	handle, ok := c.msgHandlerMap.load(msg.MessageType())
+	if !ok {
+		return ErrNoHandlersFound
+	}
+	go func() {
+		if err := handle(c, senderAddr, msg); err != nil {
+			c.notifyError(err)
+		}
+	}()
+
+	return nil
+}
+
+// SetErrorHandler registers the ErrorHandler to be called with the errors that occur
+// in the background process of CPlaneConn, including the ones returned from
+// HandlerFuncs and the failures in parsing the incoming messages.
+//
+// Once the ErrorHandler is set, the errors are no longer sent to the errCh given
+// when creating CPlaneConn. Giving nil restores the errCh-based behavior.
+func (c *CPlaneConn) SetErrorHandler(fn ErrorHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.errHandler = fn
+}
+
+func (c *CPlaneConn) errorHandler() ErrorHandler {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.errHandler
+}
+
+// notifyError passes err to the ErrorHandler if registered, or to errCh otherwise.
+// If neither is available, err is just discarded not to block the caller forever.
+func (c *CPlaneConn) notifyError(err error) {
+	if fn := c.errorHandler(); fn != nil {
+		fn(err)
+		return
+	}
+
+	if c.errCh == nil {
+		return
+	}
+	c.errCh <- err
+}
+
+// EchoRequest sends a EchoRequest.
+func (c *CPlaneConn) EchoRequest(raddr net.Addr) error {
+	_, err := c.SendMessageTo(messages.NewEchoRequest(0, ies.NewRecovery(c.RestartCounter)), raddr)
+	return err
+}
+
+// EchoResponse sends a EchoResponse in response to the EchoRequest.
+func (c *CPlaneConn) EchoResponse(raddr net.Addr, req messages.Message) error {
+	return c.RespondTo(raddr, req, messages.NewEchoResponse(0, ies.NewRecovery(c.RestartCounter)))
+}
+
+// SendMessageTo sends a message to addr.
+// Unlike WriteTo, it sets the SequenceNumber properly and returns the one used in
+// the message.
+func (c *CPlaneConn) SendMessageTo(msg messages.Message, addr net.Addr) (uint16, error) {
+	c.mu.Lock()
+	c.sequence++
+	seq := c.sequence
+	c.mu.Unlock()
+
+	msg.SetSequenceNumber(seq)
+	b, err := messages.Marshal(msg)
+	if err != nil {
+		return seq, errors.Wrapf(err, "failed to send %T", msg)
+	}
+
+	if _, err := c.pktConn.WriteTo(b, addr); err != nil {
+		return seq, errors.Wrapf(err, "failed to send %T", msg)
+	}
+	return seq, nil
+}
+
+// SequenceNumber returns the last SequenceNumber used in the request sent from
+// CPlaneConn.
+func (c *CPlaneConn) SequenceNumber() uint16 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.sequence
+}
+
+// SetInitialSequenceNumber sets the SequenceNumber to be used in the next request
+// sent from CPlaneConn, and the following requests use the ones incremented from it.
+//
+// This is useful to avoid collisions with the outstanding transactions at the peers
+// when the node restarts quickly.
+func (c *CPlaneConn) SetInitialSequenceNumber(seq uint16) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// c.sequence holds the last used one.
+	c.sequence = seq - 1
+}
+
+// RandomizeSequenceNumber sets the SequenceNumber to be used in the next request sent
+// from CPlaneConn to a random value, and returns it.
+func (c *CPlaneConn) RandomizeSequenceNumber() uint16 {
+	seq := uint16(sequence.Random(0xffff))
+	c.SetInitialSequenceNumber(seq)
+	return seq
+}
+
+// RespondTo sends a message(specified with "toBeSent" param) in response to
+// a message(specified with "received" param).
+//
+// This is to make it easier to handle SequenceNumber.
+func (c *CPlaneConn) RespondTo(raddr net.Addr, received, toBeSent messages.Message) error {
+	toBeSent.SetSequenceNumber(received.Sequence())
+	b := make([]byte, toBeSent.MarshalLen())
+	if err := toBeSent.MarshalTo(b); err != nil {
+		return err
+	}
+
+	if _, err := c.WriteTo(b, raddr); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Restarts returns the number of restarts in uint8.
+func (c *CPlaneConn) Restarts() uint8 {
+	return c.RestartCounter
+}
+
+// AddSession adds a Session to CPlaneConn. The existing Session with the same IMSI and
+// NSAPI is replaced.
+func (c *CPlaneConn) AddSession(session *Session) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var sessions []*Session
+	for _, sess := range c.sessions {
+		if sess.IMSI == session.IMSI && sess.NSAPI == session.NSAPI {
+			continue
+		}
+		sessions = append(sessions, sess)
+	}
+	c.sessions = append(sessions, session)
+}
+
+// RemoveSession removes a Session from CPlaneConn.
+func (c *CPlaneConn) RemoveSession(session *Session) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var sessions []*Session
+	for _, sess := range c.sessions {
+		if sess == session {
+			continue
+		}
+		sessions = append(sessions, sess)
+	}
+	c.sessions = sessions
+}
+
+// GetSessionByTEID returns the Session that has teid as the local TEID for either
+// Control Plane or Data I.
+func (c *CPlaneConn) GetSessionByTEID(teid uint32) (*Session, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, sess := range c.sessions {
+		if sess.hasTEID(teid) {
+			return sess, nil
+		}
+	}
+	return nil, ErrSessionNotFound
+}
+
+// GetSessionByIMSI returns the Session that has the IMSI and NSAPI given.
+func (c *CPlaneConn) GetSessionByIMSI(imsi string, nsapi uint8) (*Session, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, sess := range c.sessions {
+		if sess.IMSI == imsi && sess.NSAPI == nsapi {
+			return sess, nil
+		}
+	}
+	return nil, ErrSessionNotFound
+}
+
+// SessionCount returns the number of active Sessions in CPlaneConn.
+func (c *CPlaneConn) SessionCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var count int
+	for _, sess := range c.sessions {
+		if sess.IsActive() {
+			count++
+		}
+	}
+	return count
+}
+
+// NewTEID returns a random TEID that is not used as the local TEID of any Sessions
+// in CPlaneConn, which is expected to be used for a new Session.
+func (c *CPlaneConn) NewTEID() uint32 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b := make([]byte, 4)
+	for {
+		if _, err := rand.Read(b); err != nil {
+			return 0
+		}
+
+		teid := binary.BigEndian.Uint32(b)
+		if teid == 0 {
+			continue
+		}
+
+		used := false
+		for _, sess := range c.sessions {
+			if sess.hasTEID(teid) {
+				used = true
+				break
+			}
+		}
+		if !used {
+			return teid
+		}
+	}
+}
@@ -0,0 +1,301 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	v1 "github.com/wmnsk/go-gtp/v1"
+	"github.com/wmnsk/go-gtp/v1/ies"
+	"github.com/wmnsk/go-gtp/v1/messages"
+)
+
+func TestPDPContext(t *testing.T) {
+	sgsnAddr := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 71), Port: 2123}
+	ggsnAddr := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 72), Port: 2123}
+	errCh := make(chan error, 1)
+
+	ggsnConn, err := v1.ListenAndServeCPlane(ggsnAddr, 0, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ggsnConn.Close()
+
+	ggsnConn.AddHandlers(map[uint8]v1.HandlerFunc{
+		messages.MsgTypeCreatePDPContextRequest: func(c v1.Conn, senderAddr net.Addr, msg messages.Message) error {
+			req := msg.(*messages.CreatePDPContextRequest)
+			sess := v1.NewSession(senderAddr, req.IMSI.MustIMSI())
+			sess.NSAPI = req.NSAPI.MustNSAPI()
+			sess.RemoteTEIDC = req.TEIDCPlane.MustTEID()
+			sess.RemoteTEIDU = req.TEIDDataI.MustTEID()
+			sess.LocalTEIDC = 0x11111111
+			sess.LocalTEIDU = 0x22222222
+			if err := sess.Activate(); err != nil {
+				return err
+			}
+			ggsnConn.AddSession(sess)
+
+			return c.RespondTo(senderAddr, msg, messages.NewCreatePDPContextResponse(
+				sess.RemoteTEIDC, 0,
+				ies.NewCause(v1.ResCauseRequestAccepted),
+				ies.NewTEIDDataI(sess.LocalTEIDU),
+				ies.NewTEIDCPlane(sess.LocalTEIDC),
+				ies.NewNSAPI(sess.NSAPI),
+				ies.NewEndUserAddress("10.0.0.1"),
+				ies.NewGSNAddress("127.0.0.72"),
+				ies.NewGSNAddress("127.0.0.73"),
+			))
+		},
+		messages.MsgTypeUpdatePDPContextRequest: func(c v1.Conn, senderAddr net.Addr, msg messages.Message) error {
+			sess, err := ggsnConn.GetSessionByTEID(msg.TEID())
+			if err != nil {
+				return err
+			}
+			return c.RespondTo(senderAddr, msg, messages.NewUpdatePDPContextResponse(
+				sess.RemoteTEIDC, 0,
+				ies.NewCause(v1.ResCauseRequestAccepted),
+				ies.NewTEIDDataI(0x33333333),
+			))
+		},
+		messages.MsgTypeDeletePDPContextRequest: func(c v1.Conn, senderAddr net.Addr, msg messages.Message) error {
+			sess, err := ggsnConn.GetSessionByTEID(msg.TEID())
+			if err != nil {
+				return err
+			}
+			ggsnConn.RemoveSession(sess)
+			return c.RespondTo(senderAddr, msg, messages.NewDeletePDPContextResponse(
+				sess.RemoteTEIDC, 0,
+				ies.NewCause(v1.ResCauseRequestAccepted),
+			))
+		},
+	})
+
+	sgsnConn, err := v1.DialCPlane(sgsnAddr, ggsnAddr, 0, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sgsnConn.Close()
+
+	resCh := make(chan messages.Message, 1)
+	notify := func(c v1.Conn, senderAddr net.Addr, msg messages.Message) error {
+		resCh <- msg
+		return nil
+	}
+	sgsnConn.AddHandlers(map[uint8]v1.HandlerFunc{
+		messages.MsgTypeCreatePDPContextResponse: notify,
+		messages.MsgTypeUpdatePDPContextResponse: notify,
+		messages.MsgTypeDeletePDPContextResponse: notify,
+	})
+
+	wait := func(seq uint16) {
+		t.Helper()
+		select {
+		case msg := <-resCh:
+			if msg.Sequence() != seq {
+				t.Errorf("unexpected sequence in %T: got %d, want %d", msg, msg.Sequence(), seq)
+			}
+		case err := <-errCh:
+			t.Fatal(err)
+		case <-time.After(3 * time.Second):
+			t.Fatal("timed out waiting for the response")
+		}
+	}
+
+	sess, seq, err := sgsnConn.CreatePDPContext(
+		ggsnAddr,
+		ies.NewIMSI("123451234567890"),
+		ies.NewNSAPI(5),
+		ies.NewAccessPointName("some.apn.example"),
+		ies.NewTEIDCPlane(0xaaaaaaaa),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sess.IsActive() {
+		t.Error("Session is activated before the response")
+	}
+	if sess.LocalTEIDU == 0 {
+		t.Error("TEID for Data I is not generated")
+	}
+	wait(seq)
+
+	if !sess.IsActive() {
+		t.Fatal("Session is not activated with the response")
+	}
+	if got, want := sess.RemoteTEIDC, uint32(0x11111111); got != want {
+		t.Errorf("unexpected RemoteTEIDC: got %#x, want %#x", got, want)
+	}
+	if got, want := sess.RemoteTEIDU, uint32(0x22222222); got != want {
+		t.Errorf("unexpected RemoteTEIDU: got %#x, want %#x", got, want)
+	}
+	if got, want := sess.PeerUPlaneAddr, "127.0.0.73"; got != want {
+		t.Errorf("unexpected PeerUPlaneAddr: got %s, want %s", got, want)
+	}
+	if got, want := sess.MSAddress, "10.0.0.1"; got != want {
+		t.Errorf("unexpected MSAddress: got %s, want %s", got, want)
+	}
+	if got, err := sgsnConn.GetSessionByTEID(0xaaaaaaaa); err != nil || got != sess {
+		t.Errorf("Session not found with local TEID: %v", err)
+	}
+	if got, err := sgsnConn.GetSessionByIMSI("123451234567890", 5); err != nil || got != sess {
+		t.Errorf("Session not found with IMSI: %v", err)
+	}
+
+	seq2, err := sgsnConn.UpdatePDPContext(sess, ies.NewTEIDDataI(0xbbbbbbbb))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seq2 == seq {
+		t.Errorf("sequence is not incremented: %d", seq2)
+	}
+	wait(seq2)
+
+	if got, want := sess.RemoteTEIDU, uint32(0x33333333); got != want {
+		t.Errorf("unexpected RemoteTEIDU after update: got %#x, want %#x", got, want)
+	}
+	if got, want := sess.LocalTEIDU, uint32(0xbbbbbbbb); got != want {
+		t.Errorf("unexpected LocalTEIDU after update: got %#x, want %#x", got, want)
+	}
+
+	seq3, err := sgsnConn.DeletePDPContext(sess)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wait(seq3)
+
+	if sess.IsActive() {
+		t.Error("Session is still active after deletion")
+	}
+	if n := sgsnConn.SessionCount(); n != 0 {
+		t.Errorf("unexpected number of Sessions: %d", n)
+	}
+	if _, err := sgsnConn.GetSessionByTEID(0xaaaaaaaa); err != v1.ErrSessionNotFound {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestDialCPlane(t *testing.T) {
+	// echoPeer responds to the EchoRequest from CPlaneConn with the messages given,
+	// which are built with the SequenceNumber of the request.
+	echoPeer := func(addr *net.UDPAddr, responses func(seq uint16) []messages.Message) net.PacketConn {
+		t.Helper()
+		peer, err := net.ListenPacket("udp", addr.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		go func() {
+			buf := make([]byte, 1600)
+			n, raddr, err := peer.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			req, err := messages.Parse(buf[:n])
+			if err != nil {
+				return
+			}
+			for _, msg := range responses(req.Sequence()) {
+				b, err := messages.Marshal(msg)
+				if err != nil {
+					return
+				}
+				if _, err := peer.WriteTo(b, raddr); err != nil {
+					return
+				}
+			}
+		}()
+		return peer
+	}
+
+	t.Run("matching EchoResponse", func(t *testing.T) {
+		peerAddr := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 74), Port: 2123}
+		peer := echoPeer(peerAddr, func(seq uint16) []messages.Message {
+			return []messages.Message{
+				messages.NewEchoRequest(0x1234, ies.NewRecovery(1)),
+				messages.NewEchoResponse(seq+1, ies.NewRecovery(1)),
+				messages.NewEchoResponse(seq, ies.NewRecovery(1)),
+			}
+		})
+		defer peer.Close()
+
+		errCh := make(chan error, 1)
+		conn, err := v1.DialCPlane(&net.UDPAddr{IP: net.IPv4(127, 0, 0, 75), Port: 2123}, peerAddr, 0, errCh)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		conn.AddHandler(messages.MsgTypeEchoRequest, func(c v1.Conn, senderAddr net.Addr, msg messages.Message) error {
+			return conn.EchoResponse(senderAddr, msg)
+		})
+
+		// the EchoResponse has the SequenceNumber of the EchoRequest.
+		b, err := messages.Marshal(messages.NewEchoRequest(0x5678, ies.NewRecovery(1)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := peer.WriteTo(b, conn.LocalAddr()); err != nil {
+			t.Fatal(err)
+		}
+		if err := peer.SetReadDeadline(time.Now().Add(3 * time.Second)); err != nil {
+			t.Fatal(err)
+		}
+		buf := make([]byte, 1600)
+		n, _, err := peer.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rsp, err := messages.Parse(buf[:n])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := rsp.(*messages.EchoResponse); !ok {
+			t.Fatalf("unexpected message: %T", rsp)
+		}
+		if got := rsp.Sequence(); got != 0x5678 {
+			t.Errorf("unexpected sequence in EchoResponse: got %#x, want %#x", got, 0x5678)
+		}
+	})
+
+	t.Run("no EchoResponse", func(t *testing.T) {
+		peerAddr := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 76), Port: 2123}
+		peer := echoPeer(peerAddr, func(seq uint16) []messages.Message {
+			return []messages.Message{messages.NewEchoRequest(0x1234, ies.NewRecovery(1))}
+		})
+		defer peer.Close()
+
+		done := make(chan error, 1)
+		go func() {
+			conn, err := v1.DialCPlane(&net.UDPAddr{IP: net.IPv4(127, 0, 0, 77), Port: 2123}, peerAddr, 0, nil)
+			if err == nil {
+				conn.Close()
+			}
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			if err == nil {
+				t.Error("DialCPlane should fail without EchoResponse")
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatal("DialCPlane is blocked after the deadline")
+		}
+	})
+}
+
+func TestCPlaneConnSequenceNumber(t *testing.T) {
+	conn := &v1.CPlaneConn{}
+	for _, seq := range []uint16{0, 1, 0x1234, 0xffff} {
+		conn.SetInitialSequenceNumber(seq)
+		if got := conn.SequenceNumber() + 1; got != seq {
+			t.Errorf("wrong SequenceNumber. want: %#x, got: %#x", seq, got)
+		}
+	}
+
+	seq := conn.RandomizeSequenceNumber()
+	if got := conn.SequenceNumber() + 1; got != seq {
+		t.Errorf("wrong SequenceNumber. want: %#x, got: %#x", seq, got)
+	}
+}
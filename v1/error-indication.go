@@ -0,0 +1,121 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1
+
+import (
+	"net"
+
+	"github.com/wmnsk/go-gtp/v1/ies"
+	"github.com/wmnsk/go-gtp/v1/messages"
+)
+
+// ErrorIndicationHandler is called when an Error Indication is received, with the TEID
+// and the GTP-U Peer Address in it, which identify the tunnel that the sender does not
+// know. This is where the control plane should tear down the stale bearer.
+type ErrorIndicationHandler func(senderAddr net.Addr, teid uint32, peer string)
+
+// SetAutoErrorIndication enables or disables sending Error Indication automatically
+// in response to the T-PDUs with unknown TEIDs, which is disabled by default.
+//
+// The TEID of a T-PDU is taken as unknown if any of the following is true, and the
+// T-PDU is discarded after sending Error Indication.
+//
+//   - RelayTo is used and the TEID is not relayed.
+//   - Kernel GTP-U is used, as the kernel passes the T-PDUs with unknown TEIDs.
+//   - The function set with SetTEIDValidator returns false.
+func (u *UPlaneConn) SetAutoErrorIndication(enabled bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.autoErrInd = enabled
+}
+
+// SetTEIDValidator sets the function to determine if the TEID of the T-PDU received
+// is known, which is used when sending Error Indication automatically is enabled with
+// SetAutoErrorIndication. Giving nil makes all the TEIDs known, which is the default.
+func (u *UPlaneConn) SetTEIDValidator(fn func(teid uint32) bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.teidValidator = fn
+}
+
+// SetErrorIndicationHandler registers the ErrorIndicationHandler to be called when an
+// Error Indication is received.
+//
+// Without ErrorIndicationHandler, the Error Indication is notified as an
+// *ErrorIndicatedError via ErrorHandler or errCh. This is the same as
+// overwriting the HandlerFunc for messages.MsgTypeErrorIndication with AddHandler,
+// but the IEs are validated and decoded before fn is called.
+func (u *UPlaneConn) SetErrorIndicationHandler(fn ErrorIndicationHandler) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.errIndHandler = fn
+}
+
+func (u *UPlaneConn) errorIndicationHandler() ErrorIndicationHandler {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.errIndHandler
+}
+
+// unknownTEID determines if the TEID of the T-PDU passed to the userspace is unknown,
+// only when sending Error Indication automatically is enabled.
+func (u *UPlaneConn) unknownTEID(teid uint32) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if !u.autoErrInd {
+		return false
+	}
+	if u.kernGTPEnabled {
+		return true
+	}
+	if u.teidValidator != nil {
+		return !u.teidValidator(teid)
+	}
+	return false
+}
+
+// ErrorIndication just sends ErrorIndication message.
+//
+// The GTP-U Peer Address is the local address of UPlaneConn, which is the destination
+// address of the message received. If UPlaneConn is bound to the unspecified address,
+// the address of raddr is used instead.
+func (u *UPlaneConn) ErrorIndication(raddr net.Addr, received messages.Message) error {
+	return u.sendErrorIndication(raddr, received.TEID(), received.Sequence())
+}
+
+func (u *UPlaneConn) sendErrorIndication(raddr net.Addr, teid uint32, seq uint16) error {
+	errInd, err := messages.NewErrorIndication(
+		0, seq,
+		ies.NewTEIDDataI(teid),
+		ies.NewGSNAddress(u.peerAddress(raddr)),
+	).Marshal()
+	if err != nil {
+		return err
+	}
+
+	if _, err := u.WriteTo(errInd, raddr); err != nil {
+		return err
+	}
+	u.stats.messageSent(raddr, messages.MsgTypeErrorIndication)
+	u.stats.errorIndicationSent(raddr, teid)
+	return nil
+}
+
+// peerAddress returns the IP address put in GTP-U Peer Address IE in Error Indication.
+func (u *UPlaneConn) peerAddress(raddr net.Addr) string {
+	if laddr, ok := u.pktConn.LocalAddr().(*net.UDPAddr); ok {
+		if laddr.IP != nil && !laddr.IP.IsUnspecified() {
+			return laddr.IP.String()
+		}
+	}
+	if ua, ok := raddr.(*net.UDPAddr); ok {
+		return ua.IP.String()
+	}
+	if host, _, err := net.SplitHostPort(raddr.String()); err == nil {
+		return host
+	}
+	return raddr.String()
+}
@@ -7,6 +7,8 @@ package v1
 import (
 	"errors"
 	"fmt"
+
+	"github.com/wmnsk/go-gtp/internal/sockopt"
 )
 
 var (
@@ -26,6 +28,20 @@ var (
 	// ErrConnNotOpened indicates that some operation is failed due to the status of
 	// Conn is not valid.
 	ErrConnNotOpened = errors.New("connection is not opened")
+
+	// ErrRouteNotFound indicates that no route is found with the TEID given.
+	ErrRouteNotFound = errors.New("route not found")
+
+	// ErrSessionNotFound indicates that no Session is found with the TEID or IMSI
+	// given.
+	ErrSessionNotFound = errors.New("session not found")
+
+	// ErrSocketOptionUnsupported indicates that the SocketOptions given is not
+	// supported on the platform.
+	ErrSocketOptionUnsupported = sockopt.ErrUnsupported
+
+	// ErrInvalidDSCP indicates that the DSCP in SocketOptions is out of range.
+	ErrInvalidDSCP = sockopt.ErrInvalidDSCP
 )
 
 // ErrorIndicatedError indicates that Error Indication message is received on U-Plane Connection.
@@ -37,3 +53,27 @@ type ErrorIndicatedError struct {
 func (e *ErrorIndicatedError) Error() string {
 	return fmt.Sprintf("error received from %s, TEIDDataI: %#x", e.Peer, e.TEID)
 }
+
+// PacketTooBigError indicates that the GTP-U packet is not sent as it is larger than
+// the path MTU toward the peer, which is discovered with DontFragment in SocketOptions.
+type PacketTooBigError struct {
+	Peer string
+	// MTU is the path MTU, which is zero if it cannot be retrieved.
+	MTU int
+	// Size is the length of the GTP-U packet that is failed to be sent.
+	Size int
+}
+
+func (e *PacketTooBigError) Error() string {
+	return fmt.Sprintf("packet too big for the path to %s: size %d, MTU %d", e.Peer, e.Size, e.MTU)
+}
+
+// RequiredIEMissingError indicates that the IE required is missing.
+type RequiredIEMissingError struct {
+	Type uint8
+}
+
+// Error returns error with missing IE type.
+func (e *RequiredIEMissingError) Error() string {
+	return fmt.Sprintf("required IE missing: %d", e.Type)
+}
@@ -16,6 +16,14 @@ import (
 // HandlerFunc is a handler for specific GTPv1 message.
 type HandlerFunc func(c Conn, senderAddr net.Addr, msg messages.Message) error
 
+// ErrorHandler is a handler for the errors that occur in the background process
+// of UPlaneConn and CPlaneConn, such as the ones returned from HandlerFunc or the
+// failures in parsing the incoming messages.
+//
+// The failures in parsing are passed synchronously in the goroutine that reads the
+// incoming messages, so ErrorHandler should return quickly not to block receiving.
+type ErrorHandler func(err error)
+
 type msgHandlerMap struct {
 	syncMap sync.Map
 }
@@ -48,9 +56,24 @@ var defaultHandlerMap = newMsgHandlerMap(
 		messages.MsgTypeEchoRequest:     handleEchoRequest,
 		messages.MsgTypeEchoResponse:    handleEchoResponse,
 		messages.MsgTypeErrorIndication: handleErrorIndication,
+		messages.MsgTypeEndMarker:       handleEndMarker,
 	},
 )
 
+// newDefaultCPlaneHandlerMap returns the msgHandlerMap with the default HandlerFuncs
+// for CPlaneConn, which is created for each CPlaneConn.
+func newDefaultCPlaneHandlerMap() *msgHandlerMap {
+	return newMsgHandlerMap(
+		map[uint8]HandlerFunc{
+			messages.MsgTypeEchoRequest:              handleEchoRequest,
+			messages.MsgTypeEchoResponse:             handleEchoResponse,
+			messages.MsgTypeCreatePDPContextResponse: handlePDPContextResponse,
+			messages.MsgTypeUpdatePDPContextResponse: handlePDPContextResponse,
+			messages.MsgTypeDeletePDPContextResponse: handlePDPContextResponse,
+		},
+	)
+}
+
 func handleTPDU(c Conn, senderAddr net.Addr, msg messages.Message) error {
 	// this should never happen, as the type should have been assured by
 	// msgHandlerMap before this function is called.
@@ -64,10 +87,19 @@ func handleTPDU(c Conn, senderAddr net.Addr, msg messages.Message) error {
 		return ErrInvalidConnection
 	}
 
+	u.stats.tpduReceived(senderAddr, pdu.TEID(), pdu.MarshalLen())
+
+	// discard the T-PDU with unknown TEID after telling the sender.
+	if u.unknownTEID(pdu.TEID()) {
+		u.stats.tpduDropped(senderAddr, pdu.TEID())
+		return u.ErrorIndication(senderAddr, pdu)
+	}
+
 	tpdu := &tpduSet{
 		raddr:   senderAddr,
 		teid:    pdu.TEID(),
 		seq:     pdu.Sequence(),
+		exts:    pdu.ExtensionHeaders,
 		payload: pdu.Payload,
 	}
 
@@ -78,6 +110,7 @@ func handleTPDU(c Conn, senderAddr net.Addr, msg messages.Message) error {
 		case u.tpduCh <- tpdu:
 			return
 		case <-time.After(3 * time.Second):
+			u.stats.tpduDropped(senderAddr, tpdu.teid)
 			return
 		}
 	}()
@@ -116,9 +149,46 @@ func handleErrorIndication(c Conn, senderAddr net.Addr, msg messages.Message) er
 		return ErrUnexpectedType
 	}
 
+	if ind.TEIDDataI == nil {
+		return &RequiredIEMissingError{Type: ies.TEIDDataI}
+	}
+	if ind.GTPUPeerAddress == nil {
+		return &RequiredIEMissingError{Type: ies.GSNAddress}
+	}
+	teid, peer := ind.TEIDDataI.MustTEID(), ind.GTPUPeerAddress.MustIPAddress()
+
+	if u, ok := c.(*UPlaneConn); ok {
+		u.stats.errorIndicationReceived(senderAddr, teid)
+		if fn := u.errorIndicationHandler(); fn != nil {
+			fn(senderAddr, teid, peer)
+			return nil
+		}
+	}
+
 	// let's just return err anyway.
 	return &ErrorIndicatedError{
-		TEID: ind.TEIDDataI.MustTEID(),
-		Peer: ind.GTPUPeerAddress.MustIPAddress(),
+		TEID: teid,
+		Peer: peer,
 	}
 }
+
+func handleEndMarker(c Conn, senderAddr net.Addr, msg messages.Message) error {
+	// this should never happen, as the type should have been assured by
+	// msgHandlerMap before this function is called.
+	if _, ok := msg.(*messages.EndMarker); !ok {
+		return ErrUnexpectedType
+	}
+
+	// just discard it, as nothing is buffered by default.
+	return nil
+}
+
+func handlePDPContextResponse(c Conn, senderAddr net.Addr, msg messages.Message) error {
+	// this should never happen, as it is registered only in CPlaneConn.
+	if _, ok := c.(*CPlaneConn); !ok {
+		return ErrInvalidConnection
+	}
+
+	// do nothing, as the Session has already been updated by CPlaneConn.
+	return nil
+}
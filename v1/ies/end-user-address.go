@@ -14,6 +14,13 @@ const (
 	pdpTypeIETF
 )
 
+// PDP Type Number definitions for IETF.
+const (
+	pdpTypeNumberIPv4   uint8 = 0x21
+	pdpTypeNumberIPv6   uint8 = 0x57
+	pdpTypeNumberIPv4v6 uint8 = 0x8d
+)
+
 // NewEndUserAddress creates a new EndUserAddress IE from the given IP Address in string.
 //
 // The addr can be either IPv4 or IPv6. If the address type is PPP,
@@ -59,7 +66,7 @@ func newEUAddrV4(v4 []byte) *IE {
 		make([]byte, 6),
 	)
 	e.Payload[0] = pdpTypeIETF
-	e.Payload[1] = 0x21
+	e.Payload[1] = pdpTypeNumberIPv4
 	copy(e.Payload[2:], v4)
 
 	return e
@@ -70,13 +77,39 @@ func newEUAddrV6(v6 []byte) *IE {
 		EndUserAddress,
 		make([]byte, 18),
 	)
-	e.Payload = make([]byte, 18)
-	e.Payload[1] = 0x57
+	e.Payload[0] = pdpTypeIETF
+	e.Payload[1] = pdpTypeNumberIPv6
 	copy(e.Payload[2:], v6)
 
 	return e
 }
 
+// NewEndUserAddressIPv4v6 creates a new EndUserAddress IE with both IPv4 and IPv6.
+//
+// If either of them cannot be converted, it is the same as the one created with
+// NewEndUserAddressIPv4 or NewEndUserAddressIPv6 with the other.
+func NewEndUserAddressIPv4v6(v4addr, v6addr string) *IE {
+	v4 := net.ParseIP(v4addr).To4()
+	v6 := net.ParseIP(v6addr).To16()
+	if v6 == nil || v6.To4() != nil {
+		return NewEndUserAddressIPv4(v4addr)
+	}
+	if v4 == nil {
+		return newEUAddrV6(v6)
+	}
+
+	e := New(
+		EndUserAddress,
+		make([]byte, 22),
+	)
+	e.Payload[0] = pdpTypeIETF
+	e.Payload[1] = pdpTypeNumberIPv4v6
+	copy(e.Payload[2:6], v4)
+	copy(e.Payload[6:], v6)
+
+	return e
+}
+
 // NewEndUserAddressPPP creates a new EndUserAddress IE with PPP.
 func NewEndUserAddressPPP() *IE {
 	e := New(EndUserAddress, make([]byte, 2))
@@ -150,7 +183,16 @@ func (i *IE) IPAddress() (string, error) {
 		if i.MustPDPTypeOrganization() != pdpTypeIETF {
 			return "", ErrMalformed
 		}
-		return net.IP(i.Payload[2:]).String(), nil
+		switch i.Payload[1] {
+		case pdpTypeNumberIPv4v6:
+			// the IPv4 address comes first with both.
+			if len(i.Payload) < 6 {
+				return "", io.ErrUnexpectedEOF
+			}
+			return net.IP(i.Payload[2:6]).String(), nil
+		default:
+			return net.IP(i.Payload[2:]).String(), nil
+		}
 	case GSNAddress:
 		return net.IP(i.Payload).String(), nil
 	default:
@@ -164,3 +206,44 @@ func (i *IE) MustIPAddress() string {
 	v, _ := i.IPAddress()
 	return v
 }
+
+// IPv6Address returns the IPv6 address in string if type matches and it has IPv6
+// address. Unlike IPAddress, this works with EndUserAddress with both IPv4 and IPv6.
+func (i *IE) IPv6Address() (string, error) {
+	switch i.Type {
+	case EndUserAddress:
+		if len(i.Payload) < 2 {
+			return "", io.ErrUnexpectedEOF
+		}
+		if i.Payload[0] != pdpTypeIETF {
+			return "", ErrMalformed
+		}
+
+		offset := 2
+		switch i.Payload[1] {
+		case pdpTypeNumberIPv6:
+		case pdpTypeNumberIPv4v6:
+			offset += 4
+		default:
+			return "", ErrMalformed
+		}
+		if len(i.Payload) < offset+16 {
+			return "", io.ErrUnexpectedEOF
+		}
+		return net.IP(i.Payload[offset : offset+16]).String(), nil
+	case GSNAddress:
+		if len(i.Payload) != net.IPv6len {
+			return "", ErrMalformed
+		}
+		return net.IP(i.Payload).String(), nil
+	default:
+		return "", &InvalidTypeError{Type: i.Type}
+	}
+}
+
+// MustIPv6Address returns IPv6Address in string if type matches.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustIPv6Address() string {
+	v, _ := i.IPv6Address()
+	return v
+}
@@ -23,6 +23,10 @@ func TestIEs(t *testing.T) {
 			"IMSI",
 			ies.NewIMSI("123451234567890"),
 			[]byte{0x02, 0x21, 0x43, 0x15, 0x32, 0x54, 0x76, 0x98, 0xf0},
+		}, {
+			"TemporaryLogicalLinkIdentity",
+			ies.NewTemporaryLogicalLinkIdentity(0xdeadbeef),
+			[]byte{0x04, 0xde, 0xad, 0xbe, 0xef},
 		}, {
 			"PacketTMSI",
 			ies.NewPacketTMSI(0xbeebee),
@@ -84,6 +88,22 @@ func TestIEs(t *testing.T) {
 			"RANAPCause",
 			ies.NewRANAPCause(v1.MAPCauseUnknownSubscriber),
 			[]byte{0x15, 0x01},
+		}, {
+			"RABContext",
+			ies.NewRABContext(5, 0x1111, 0x2222, 0x3333, 0x4444),
+			[]byte{0x16, 0x05, 0x11, 0x11, 0x22, 0x22, 0x33, 0x33, 0x44, 0x44},
+		}, {
+			"RadioPrioritySMS",
+			ies.NewRadioPrioritySMS(2),
+			[]byte{0x17, 0x02},
+		}, {
+			"RadioPriority",
+			ies.NewRadioPriority(5, 4),
+			[]byte{0x18, 0x54},
+		}, {
+			"PacketFlowID",
+			ies.NewPacketFlowID(5, 0x7f),
+			[]byte{0x19, 0x05, 0x7f},
 		}, {
 			"EndUserAddress/v4",
 			ies.NewEndUserAddress("1.1.1.1"),
@@ -92,9 +112,16 @@ func TestIEs(t *testing.T) {
 			"EndUserAddress/v6",
 			ies.NewEndUserAddress("2001::1"),
 			[]byte{
-				0x80, 0x00, 0x12, 0x00,
+				0x80, 0x00, 0x12, 0xf1,
 				0x57, 0x20, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01,
 			},
+		}, {
+			"EndUserAddress/v4v6",
+			ies.NewEndUserAddressIPv4v6("1.1.1.1", "2001::1"),
+			[]byte{
+				0x80, 0x00, 0x16, 0xf1, 0x8d, 0x01, 0x01, 0x01, 0x01,
+				0x20, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01,
+			},
 		}, {
 			"AccessPointName",
 			ies.NewAccessPointName("some.apn.example"),
@@ -136,6 +163,14 @@ func TestIEs(t *testing.T) {
 				0x10,
 				0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77, 0x88, 0x99, 0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff,
 			},
+		}, {
+			"RANTransparentContainer",
+			ies.NewRANTransparentContainer([]byte{0xde, 0xad, 0xbe, 0xef}),
+			[]byte{0x90, 0x00, 0x04, 0xde, 0xad, 0xbe, 0xef},
+		}, {
+			"SGSNNumber",
+			ies.NewSGSNNumber("819012345678"),
+			[]byte{0x93, 0x00, 0x07, 0x91, 0x18, 0x09, 0x21, 0x43, 0x65, 0x87},
 		}, {
 			"CommonFlags",
 			ies.NewCommonFlags(0, 1, 0, 0, 0, 0, 0, 0),
@@ -144,6 +179,10 @@ func TestIEs(t *testing.T) {
 			"APNRestriction",
 			ies.NewAPNRestriction(v1.APNRestrictionPrivate1),
 			[]byte{0x95, 0x00, 0x01, 0x03},
+		}, {
+			"RadioPriorityLCS",
+			ies.NewRadioPriorityLCS(1),
+			[]byte{0x96, 0x00, 0x01, 0x01},
 		}, {
 			"RATType",
 			ies.NewRATType(v1.RatTypeEUTRAN),
@@ -168,6 +207,18 @@ func TestIEs(t *testing.T) {
 			"IMEISV",
 			ies.NewIMEISV("123450123456789"),
 			[]byte{0x9a, 0x00, 0x08, 0x21, 0x43, 0x05, 0x21, 0x43, 0x65, 0x87, 0xf9},
+		}, {
+			"RIMRoutingAddress",
+			ies.NewRIMRoutingAddress([]byte{0x21, 0xf3, 0x54, 0x00, 0xff, 0x01}),
+			[]byte{0x9e, 0x00, 0x06, 0x21, 0xf3, 0x54, 0x00, 0xff, 0x01},
+		}, {
+			"HopCounter",
+			ies.NewHopCounter(3),
+			[]byte{0xa3, 0x00, 0x01, 0x03},
+		}, {
+			"RIMRoutingAddressDiscriminator",
+			ies.NewRIMRoutingAddressDiscriminator(1),
+			[]byte{0xb2, 0x00, 0x01, 0x01},
 		}, {
 			"ULITimestamp",
 			ies.NewULITimestamp(time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)),
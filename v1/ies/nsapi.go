@@ -12,15 +12,28 @@ func NewNSAPI(nsapi uint8) *IE {
 }
 
 // NSAPI returns NSAPI value if type matches.
+//
+// This works with RABContext, RadioPriority and PacketFlowID as well.
 func (i *IE) NSAPI() (uint8, error) {
-	if i.Type != NSAPI {
+	switch i.Type {
+	case NSAPI:
+		if len(i.Payload) == 0 {
+			return 0, io.ErrUnexpectedEOF
+		}
+		return i.Payload[0], nil
+	case RABContext, PacketFlowID:
+		if len(i.Payload) == 0 {
+			return 0, io.ErrUnexpectedEOF
+		}
+		return i.Payload[0] & 0x0f, nil
+	case RadioPriority:
+		if len(i.Payload) == 0 {
+			return 0, io.ErrUnexpectedEOF
+		}
+		return i.Payload[0] >> 4, nil
+	default:
 		return 0, &InvalidTypeError{Type: i.Type}
 	}
-	if len(i.Payload) == 0 {
-		return 0, io.ErrUnexpectedEOF
-	}
-
-	return i.Payload[0], nil
 }
 
 // MustNSAPI returns NSAPI in uint8 if type matches.
@@ -0,0 +1,31 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import "io"
+
+// NewPacketFlowID creates a new PacketFlowID IE.
+func NewPacketFlowID(nsapi, pfi uint8) *IE {
+	return New(PacketFlowID, []byte{nsapi & 0x0f, pfi & 0x7f})
+}
+
+// PacketFlowID returns PacketFlowID value in uint8 if type matches.
+func (i *IE) PacketFlowID() (uint8, error) {
+	if i.Type != PacketFlowID {
+		return 0, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) < 2 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	return i.Payload[1] & 0x7f, nil
+}
+
+// MustPacketFlowID returns PacketFlowID in uint8 if type matches.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustPacketFlowID() uint8 {
+	v, _ := i.PacketFlowID()
+	return v
+}
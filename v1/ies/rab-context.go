@@ -0,0 +1,82 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// NewRABContext creates a new RABContext IE.
+func NewRABContext(nsapi uint8, dlGTPUSeq, ulGTPUSeq, dlPDCPSeq, ulPDCPSeq uint16) *IE {
+	i := New(RABContext, make([]byte, 9))
+
+	i.Payload[0] = nsapi & 0x0f
+	binary.BigEndian.PutUint16(i.Payload[1:3], dlGTPUSeq)
+	binary.BigEndian.PutUint16(i.Payload[3:5], ulGTPUSeq)
+	binary.BigEndian.PutUint16(i.Payload[5:7], dlPDCPSeq)
+	binary.BigEndian.PutUint16(i.Payload[7:9], ulPDCPSeq)
+	return i
+}
+
+// rabContextSequence returns the sequence number at the offset of RABContext.
+func (i *IE) rabContextSequence(offset int) (uint16, error) {
+	if i.Type != RABContext {
+		return 0, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) < offset+2 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	return binary.BigEndian.Uint16(i.Payload[offset : offset+2]), nil
+}
+
+// DLGTPUSequence returns DL GTP-U Sequence Number in uint16 if type matches.
+func (i *IE) DLGTPUSequence() (uint16, error) {
+	return i.rabContextSequence(1)
+}
+
+// MustDLGTPUSequence returns DLGTPUSequence in uint16 if type matches.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustDLGTPUSequence() uint16 {
+	v, _ := i.DLGTPUSequence()
+	return v
+}
+
+// ULGTPUSequence returns UL GTP-U Sequence Number in uint16 if type matches.
+func (i *IE) ULGTPUSequence() (uint16, error) {
+	return i.rabContextSequence(3)
+}
+
+// MustULGTPUSequence returns ULGTPUSequence in uint16 if type matches.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustULGTPUSequence() uint16 {
+	v, _ := i.ULGTPUSequence()
+	return v
+}
+
+// DLPDCPSequence returns DL PDCP Sequence Number in uint16 if type matches.
+func (i *IE) DLPDCPSequence() (uint16, error) {
+	return i.rabContextSequence(5)
+}
+
+// MustDLPDCPSequence returns DLPDCPSequence in uint16 if type matches.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustDLPDCPSequence() uint16 {
+	v, _ := i.DLPDCPSequence()
+	return v
+}
+
+// ULPDCPSequence returns UL PDCP Sequence Number in uint16 if type matches.
+func (i *IE) ULPDCPSequence() (uint16, error) {
+	return i.rabContextSequence(7)
+}
+
+// MustULPDCPSequence returns ULPDCPSequence in uint16 if type matches.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustULPDCPSequence() uint16 {
+	v, _ := i.ULPDCPSequence()
+	return v
+}
@@ -0,0 +1,44 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import "io"
+
+// NewRadioPrioritySMS creates a new RadioPrioritySMS IE.
+func NewRadioPrioritySMS(prio uint8) *IE {
+	return newUint8ValIE(RadioPrioritySMS, prio&0x07)
+}
+
+// NewRadioPriority creates a new RadioPriority IE.
+func NewRadioPriority(nsapi, prio uint8) *IE {
+	return newUint8ValIE(RadioPriority, (nsapi&0x0f)<<4|prio&0x07)
+}
+
+// NewRadioPriorityLCS creates a new RadioPriorityLCS IE.
+func NewRadioPriorityLCS(prio uint8) *IE {
+	return New(RadioPriorityLCS, []byte{prio & 0x07})
+}
+
+// RadioPriority returns RadioPriority value in uint8 if type matches.
+//
+// This works with RadioPrioritySMS, RadioPriority and RadioPriorityLCS.
+func (i *IE) RadioPriority() (uint8, error) {
+	switch i.Type {
+	case RadioPrioritySMS, RadioPriority, RadioPriorityLCS:
+		if len(i.Payload) == 0 {
+			return 0, io.ErrUnexpectedEOF
+		}
+		return i.Payload[0] & 0x07, nil
+	default:
+		return 0, &InvalidTypeError{Type: i.Type}
+	}
+}
+
+// MustRadioPriority returns RadioPriority in uint8 if type matches.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustRadioPriority() uint8 {
+	v, _ := i.RadioPriority()
+	return v
+}
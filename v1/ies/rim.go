@@ -0,0 +1,75 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import "io"
+
+// NewRANTransparentContainer creates a new RANTransparentContainer IE.
+//
+// The container is the BSSGP RIM PDU given as it is.
+func NewRANTransparentContainer(container []byte) *IE {
+	return New(RANTransparentContainer, container)
+}
+
+// RANTransparentContainer returns RANTransparentContainer in []byte if type matches.
+func (i *IE) RANTransparentContainer() ([]byte, error) {
+	if i.Type != RANTransparentContainer {
+		return nil, &InvalidTypeError{Type: i.Type}
+	}
+	return i.Payload, nil
+}
+
+// MustRANTransparentContainer returns RANTransparentContainer in []byte if type matches.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustRANTransparentContainer() []byte {
+	v, _ := i.RANTransparentContainer()
+	return v
+}
+
+// NewRIMRoutingAddress creates a new RIMRoutingAddress IE.
+//
+// The format of addr is specified by RIMRoutingAddressDiscriminator.
+func NewRIMRoutingAddress(addr []byte) *IE {
+	return New(RIMRoutingAddress, addr)
+}
+
+// RIMRoutingAddress returns RIMRoutingAddress in []byte if type matches.
+func (i *IE) RIMRoutingAddress() ([]byte, error) {
+	if i.Type != RIMRoutingAddress {
+		return nil, &InvalidTypeError{Type: i.Type}
+	}
+	return i.Payload, nil
+}
+
+// MustRIMRoutingAddress returns RIMRoutingAddress in []byte if type matches.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustRIMRoutingAddress() []byte {
+	v, _ := i.RIMRoutingAddress()
+	return v
+}
+
+// NewRIMRoutingAddressDiscriminator creates a new RIMRoutingAddressDiscriminator IE.
+func NewRIMRoutingAddressDiscriminator(disc uint8) *IE {
+	return New(RIMRoutingAddressDiscriminator, []byte{disc & 0x0f})
+}
+
+// RIMRoutingAddressDiscriminator returns RIMRoutingAddressDiscriminator in uint8 if type matches.
+func (i *IE) RIMRoutingAddressDiscriminator() (uint8, error) {
+	if i.Type != RIMRoutingAddressDiscriminator {
+		return 0, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) == 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	return i.Payload[0] & 0x0f, nil
+}
+
+// MustRIMRoutingAddressDiscriminator returns RIMRoutingAddressDiscriminator in uint8 if type matches.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustRIMRoutingAddressDiscriminator() uint8 {
+	v, _ := i.RIMRoutingAddressDiscriminator()
+	return v
+}
@@ -0,0 +1,41 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import (
+	"io"
+
+	"github.com/wmnsk/go-gtp/utils"
+)
+
+// NewSGSNNumber creates a new SGSNNumber IE.
+//
+// The number is encoded as ISDN-AddressString in the same way as MSISDN.
+func NewSGSNNumber(number string) *IE {
+	i, err := utils.StrToSwappedBytes("19"+number, "f")
+	if err != nil {
+		return nil
+	}
+	return New(SGSNNumber, i)
+}
+
+// SGSNNumber returns SGSNNumber value if type matches.
+func (i *IE) SGSNNumber() (string, error) {
+	if i.Type != SGSNNumber {
+		return "", &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) < 2 {
+		return "", io.ErrUnexpectedEOF
+	}
+
+	return utils.SwappedBytesToStr(i.Payload[1:], false), nil
+}
+
+// MustSGSNNumber returns SGSNNumber in string if type matches.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustSGSNNumber() string {
+	v, _ := i.SGSNNumber()
+	return v
+}
@@ -0,0 +1,33 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/wmnsk/go-gtp/v1/messages"
+	"github.com/wmnsk/go-gtp/v1/testutils"
+)
+
+func TestEndMarker(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "Normal",
+			Structured:  messages.NewEndMarker(testutils.TestBearerInfo.TEID),
+			Serialized: []byte{
+				0x30, 0xfe, 0x00, 0x00, 0x11, 0x22, 0x33, 0x44,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializable, error) {
+		v, err := messages.ParseEndMarker(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
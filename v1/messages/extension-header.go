@@ -0,0 +1,217 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Extension Header Type definitions.
+const (
+	ExtHeaderTypeNoMoreExtensionHeaders       uint8 = 0x00
+	ExtHeaderTypeMBMSSupportIndication        uint8 = 0x01
+	ExtHeaderTypeMSInfoChangeReportingSupport uint8 = 0x02
+	ExtHeaderTypeLongPDCPPDUNumber            uint8 = 0x03
+	ExtHeaderTypeServiceClassIndicator        uint8 = 0x20
+	ExtHeaderTypeUDPPort                      uint8 = 0x40
+	ExtHeaderTypeRANContainer                 uint8 = 0x81
+	ExtHeaderTypeLongPDCPPDUNumberRequired    uint8 = 0x82
+	ExtHeaderTypeXwRANContainer               uint8 = 0x83
+	ExtHeaderTypeNRRANContainer               uint8 = 0x84
+	ExtHeaderTypePDUSessionContainer          uint8 = 0x85
+	ExtHeaderTypePDCPPDUNumber                uint8 = 0xc0
+	ExtHeaderTypeSuspendRequest               uint8 = 0xc1
+	ExtHeaderTypeSuspendResponse              uint8 = 0xc2
+)
+
+// PDU Type definitions used in PDU Session Container.
+const (
+	PDUTypeDLPDUSessionInformation uint8 = 0
+	PDUTypeULPDUSessionInformation uint8 = 1
+)
+
+// ExtensionHeader is a GTP-U Extension Header.
+//
+// The Length field and the Next Extension Header Type field are not kept in the
+// struct, as they are determined when the Header is marshaled: Length from the length
+// of Content, and Next Extension Header Type from the next ExtensionHeader in the
+// Header.ExtensionHeaders.
+type ExtensionHeader struct {
+	Type    uint8
+	Content []byte
+}
+
+// NewExtensionHeader creates a new ExtensionHeader.
+//
+// Content is padded with zeros when marshaling, so that the length of the Extension
+// Header is a multiple of 4 octets.
+func NewExtensionHeader(typ uint8, content []byte) *ExtensionHeader {
+	return &ExtensionHeader{
+		Type:    typ,
+		Content: content,
+	}
+}
+
+// marshalTo puts the byte sequence in the byte array given as b, with the next
+// Extension Header Type given.
+func (e *ExtensionHeader) marshalTo(b []byte, next uint8) error {
+	l := e.MarshalLen()
+	if len(b) < l {
+		return ErrTooShortToMarshal
+	}
+	if l/4 > 0xff {
+		return ErrInvalidLength
+	}
+
+	b[0] = uint8(l / 4)
+	n := copy(b[1:l-1], e.Content)
+	for i := 1 + n; i < l-1; i++ {
+		b[i] = 0
+	}
+	b[l-1] = next
+	return nil
+}
+
+// parseExtensionHeader decodes the Extension Header of the type given at the head of b,
+// and returns it with the next Extension Header Type and the length decoded.
+func parseExtensionHeader(typ uint8, b []byte) (*ExtensionHeader, uint8, int, error) {
+	if len(b) < 1 {
+		return nil, 0, 0, ErrTooShortToParse
+	}
+	l := int(b[0]) * 4
+	if l == 0 {
+		return nil, 0, 0, ErrInvalidLength
+	}
+	if len(b) < l {
+		return nil, 0, 0, ErrTooShortToParse
+	}
+
+	return &ExtensionHeader{
+		Type:    typ,
+		Content: b[1 : l-1],
+	}, b[l-1], l, nil
+}
+
+// MarshalLen returns the serial length of ExtensionHeader including the Length field,
+// the Next Extension Header Type field and the padding.
+func (e *ExtensionHeader) MarshalLen() int {
+	return (len(e.Content) + 2 + 3) / 4 * 4
+}
+
+// String returns the ExtensionHeader values in human readable format.
+func (e *ExtensionHeader) String() string {
+	return fmt.Sprintf("{Type: %#x, Content: %#v}", e.Type, e.Content)
+}
+
+// InvalidExtensionHeaderTypeError indicates the type of Extension Header is invalid.
+type InvalidExtensionHeaderTypeError struct {
+	Type uint8
+}
+
+// Error returns message with the invalid type given.
+func (e *InvalidExtensionHeaderTypeError) Error() string {
+	return fmt.Sprintf("got invalid extension header type: %v", e.Type)
+}
+
+// NewPDUSessionContainer creates a new PDU Session Container Extension Header.
+//
+// The Content is the DL PDU SESSION INFORMATION or UL PDU SESSION INFORMATION in
+// TS 38.415 without any optional fields, depending on pduType. rqi is only valid with
+// PDUTypeDLPDUSessionInformation.
+func NewPDUSessionContainer(pduType, qfi uint8, rqi bool) *ExtensionHeader {
+	content := []byte{(pduType & 0x0f) << 4, qfi & 0x3f}
+	if rqi && pduType == PDUTypeDLPDUSessionInformation {
+		content[1] |= 0x40
+	}
+	return NewExtensionHeader(ExtHeaderTypePDUSessionContainer, content)
+}
+
+// PDUType returns PDUType in uint8 if the type of Extension Header is PDU Session
+// Container.
+func (e *ExtensionHeader) PDUType() (uint8, error) {
+	if e.Type != ExtHeaderTypePDUSessionContainer {
+		return 0, &InvalidExtensionHeaderTypeError{Type: e.Type}
+	}
+	if len(e.Content) < 1 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	return e.Content[0] >> 4, nil
+}
+
+// MustPDUType returns PDUType in uint8, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (e *ExtensionHeader) MustPDUType() uint8 {
+	v, _ := e.PDUType()
+	return v
+}
+
+// QFI returns QoS Flow Identifier in uint8 if the type of Extension Header is PDU
+// Session Container.
+func (e *ExtensionHeader) QFI() (uint8, error) {
+	if e.Type != ExtHeaderTypePDUSessionContainer {
+		return 0, &InvalidExtensionHeaderTypeError{Type: e.Type}
+	}
+	if len(e.Content) < 2 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	return e.Content[1] & 0x3f, nil
+}
+
+// MustQFI returns QFI in uint8, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (e *ExtensionHeader) MustQFI() uint8 {
+	v, _ := e.QFI()
+	return v
+}
+
+// RQI returns Reflective QoS Indicator in bool if the type of Extension Header is PDU
+// Session Container. It is always false in UL PDU SESSION INFORMATION.
+func (e *ExtensionHeader) RQI() (bool, error) {
+	pduType, err := e.PDUType()
+	if err != nil {
+		return false, err
+	}
+	if len(e.Content) < 2 {
+		return false, io.ErrUnexpectedEOF
+	}
+	if pduType != PDUTypeDLPDUSessionInformation {
+		return false, nil
+	}
+	return e.Content[1]&0x40 != 0, nil
+}
+
+// MustRQI returns RQI in bool, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (e *ExtensionHeader) MustRQI() bool {
+	v, _ := e.RQI()
+	return v
+}
+
+// NewUDPPortExtensionHeader creates a new UDP Port Extension Header.
+func NewUDPPortExtensionHeader(port uint16) *ExtensionHeader {
+	content := make([]byte, 2)
+	binary.BigEndian.PutUint16(content, port)
+	return NewExtensionHeader(ExtHeaderTypeUDPPort, content)
+}
+
+// UDPPort returns UDP Port in uint16 if the type of Extension Header is UDP Port.
+func (e *ExtensionHeader) UDPPort() (uint16, error) {
+	if e.Type != ExtHeaderTypeUDPPort {
+		return 0, &InvalidExtensionHeaderTypeError{Type: e.Type}
+	}
+	if len(e.Content) < 2 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	return binary.BigEndian.Uint16(e.Content[0:2]), nil
+}
+
+// MustUDPPort returns UDPPort in uint16, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (e *ExtensionHeader) MustUDPPort() uint16 {
+	v, _ := e.UDPPort()
+	return v
+}
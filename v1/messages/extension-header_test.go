@@ -0,0 +1,65 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/wmnsk/go-gtp/v1/messages"
+)
+
+func TestExtensionHeader(t *testing.T) {
+	t.Run("PDUSessionContainer", func(t *testing.T) {
+		b, err := messages.NewTPDUWithExtensionHeaders(
+			0xdeadbeef, []byte{0xde, 0xad, 0xbe, 0xef},
+			messages.NewPDUSessionContainer(messages.PDUTypeULPDUSessionInformation, 5, true),
+		).Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pdu, err := messages.ParseTPDU(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := len(pdu.ExtensionHeaders); n != 1 {
+			t.Fatalf("wrong number of extension headers: %d", n)
+		}
+		e := pdu.ExtensionHeaders[0]
+		if got := e.MustPDUType(); got != messages.PDUTypeULPDUSessionInformation {
+			t.Errorf("wrong PDU Type: %d", got)
+		}
+		if got := e.MustQFI(); got != 5 {
+			t.Errorf("wrong QFI: %d", got)
+		}
+		if e.MustRQI() {
+			t.Error("RQI should not be set in UL PDU SESSION INFORMATION")
+		}
+		if _, err := e.UDPPort(); err == nil {
+			t.Error("UDPPort should fail with PDU Session Container")
+		}
+		if got := pdu.Decapsulate(); len(got) != 4 {
+			t.Errorf("wrong payload: %x", got)
+		}
+	})
+
+	t.Run("Padding", func(t *testing.T) {
+		e := messages.NewExtensionHeader(messages.ExtHeaderTypeNRRANContainer, []byte{0x01, 0x02, 0x03})
+		if got := e.MarshalLen(); got != 8 {
+			t.Errorf("wrong length: %d", got)
+		}
+	})
+
+	t.Run("InvalidLength", func(t *testing.T) {
+		b := []byte{
+			0x34, 0xff, 0x00, 0x08, 0xde, 0xad, 0xbe, 0xef,
+			0x00, 0x00, 0x00, 0x85,
+			0x00, 0x00, 0x00, 0x00,
+		}
+		if _, err := messages.ParseTPDU(b); err != messages.ErrInvalidLength {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
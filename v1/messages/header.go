@@ -17,7 +17,13 @@ type Header struct {
 	TEID           uint32
 	SequenceNumber uint16
 	Reserved       uint16
-	Payload        []byte
+	NPDUNumber     uint8
+
+	// ExtensionHeaders is the chain of Extension Headers, which is marshaled only if
+	// the E flag is set. Use AddExtensionHeaders to set the flag together.
+	ExtensionHeaders []*ExtensionHeader
+
+	Payload []byte
 }
 
 // NewHeader creates a new Header.
@@ -62,13 +68,27 @@ func (h *Header) MarshalTo(b []byte) error {
 	binary.BigEndian.PutUint16(b[2:4], h.Length)
 	binary.BigEndian.PutUint32(b[4:8], h.TEID)
 	offset := 8
-	if h.HasSequence() {
+	if h.hasOptionalFields() {
 		binary.BigEndian.PutUint16(b[offset:offset+2], h.SequenceNumber)
-		// two bytes of padding before payload.
+		b[offset+2] = h.NPDUNumber
+		b[offset+3] = ExtHeaderTypeNoMoreExtensionHeaders
 		offset += 4
 	}
 
-	// two bytes of padding before payload.
+	if h.HasExtensionHeader() {
+		for i, e := range h.ExtensionHeaders {
+			b[offset-1] = e.Type
+			next := ExtHeaderTypeNoMoreExtensionHeaders
+			if i+1 < len(h.ExtensionHeaders) {
+				next = h.ExtensionHeaders[i+1].Type
+			}
+			if err := e.marshalTo(b[offset:], next); err != nil {
+				return err
+			}
+			offset += e.MarshalLen()
+		}
+	}
+
 	copy(b[offset:], h.Payload)
 	return nil
 }
@@ -85,7 +105,7 @@ func ParseHeader(b []byte) (*Header, error) {
 // UnmarshalBinary sets the values retrieved from byte sequence in GTPv1 header.
 func (h *Header) UnmarshalBinary(b []byte) error {
 	l := len(b)
-	if l < 11 {
+	if l < 8 {
 		return ErrTooShortToParse
 	}
 	var offset = 4
@@ -95,10 +115,25 @@ func (h *Header) UnmarshalBinary(b []byte) error {
 
 	h.TEID = binary.BigEndian.Uint32(b[4:8])
 	offset += 4
-	if h.HasSequence() {
+	if h.hasOptionalFields() {
+		if l < offset+4 {
+			return ErrTooShortToParse
+		}
 		h.SequenceNumber = binary.BigEndian.Uint16(b[offset : offset+2])
-		// two bytes of padding before payload.
+		h.NPDUNumber = b[offset+2]
+		next := b[offset+3]
 		offset += 4
+
+		h.ExtensionHeaders = nil
+		for h.HasExtensionHeader() && next != ExtHeaderTypeNoMoreExtensionHeaders {
+			e, nxt, n, err := parseExtensionHeader(next, b[offset:])
+			if err != nil {
+				return err
+			}
+			h.ExtensionHeaders = append(h.ExtensionHeaders, e)
+			next = nxt
+			offset += n
+		}
 	}
 
 	if int(h.Length)+8 != l {
@@ -115,6 +150,36 @@ func (h *Header) SetTEID(teid uint32) {
 	h.TEID = teid
 }
 
+// HasExtensionHeader determines whether a GTP Header has Extension Headers by checking
+// the flag.
+func (h *Header) HasExtensionHeader() bool {
+	return ((int(h.Flags) >> 2) & 0x1) == 1
+}
+
+// HasNPDUNumber determines whether a GTP Header has N-PDU Number by checking the flag.
+func (h *Header) HasNPDUNumber() bool {
+	return (int(h.Flags) & 0x1) == 1
+}
+
+// hasOptionalFields determines whether a GTP Header has the optional fields, i.e.,
+// Sequence Number, N-PDU Number and Next Extension Header Type, which are present if
+// any of the E, S and PN flags are set.
+func (h *Header) hasOptionalFields() bool {
+	return h.Flags&0x07 != 0
+}
+
+// AddExtensionHeaders sets the E flag to 1 and appends the Extension Headers given.
+func (h *Header) AddExtensionHeaders(exts ...*ExtensionHeader) {
+	h.Flags |= (1 << 2)
+	h.ExtensionHeaders = append(h.ExtensionHeaders, exts...)
+}
+
+// SetNPDUNumber sets the PN flag to 1 and puts the N-PDU Number given.
+func (h *Header) SetNPDUNumber(n uint8) {
+	h.Flags |= 1
+	h.NPDUNumber = n
+}
+
 // HasSequence determines whether a GTP Header has TEID inside by checking the flag.
 func (h *Header) HasSequence() bool {
 	return ((int(h.Flags) >> 1) & 0x1) == 1
@@ -133,9 +198,14 @@ func (h *Header) SetSequenceNumber(seq uint16) {
 // MarshalLen returns the serial length of Header.
 func (h *Header) MarshalLen() int {
 	l := len(h.Payload) + 8
-	if h.HasSequence() {
+	if h.hasOptionalFields() {
 		l += 4
 	}
+	if h.HasExtensionHeader() {
+		for _, e := range h.ExtensionHeaders {
+			l += e.MarshalLen()
+		}
+	}
 
 	return l
 }
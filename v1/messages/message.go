@@ -57,21 +57,15 @@ const (
 	_
 	_
 	_
-	_
-	_
-	_
-	_
-	_
-	_
-	_
-	_
 	MsgTypeIdentificationRequest // 48
 	MsgTypeIdentificationResponse
 	MsgTypeSGSNContextRequest
 	MsgTypeSGSNContextResponse
 	MsgTypeSGSNContextAcknowledge
+	MsgTypeRANInformationRelay        uint8 = 70
 	MsgTypeDataRecordTransferRequest  uint8 = 240
 	MsgTypeDataRecordTransferResponse uint8 = 241
+	MsgTypeEndMarker                  uint8 = 254
 	MsgTypeTPDU                       uint8 = 255
 )
 
@@ -172,17 +166,21 @@ func Parse(b []byte) (Message, error) {
 		m = &IdentificationReq{}
 	case MsgTypeIdentificationResponse:
 		m = &IdentificationRes{}
-	case MsgTypeSgsnContextRequest:
-		m = &SgsnContextReq{}
-	case MsgTypeSgsnContextResponse:
-		m = &SgsnContextRes{}
-	case MsgTypeSgsnContextAcknowledge:
-		m = &SgsnContextAck{}
 	case MsgTypeDataRecordTransferRequest:
 		m = &DataRecordTransferReq{}
 	case MsgTypeDataRecordTransferResponse:
 		m = &DataRecordTransferRes{}
 	*/
+	case MsgTypeSGSNContextRequest:
+		m = &SGSNContextRequest{}
+	case MsgTypeSGSNContextResponse:
+		m = &SGSNContextResponse{}
+	case MsgTypeSGSNContextAcknowledge:
+		m = &SGSNContextAcknowledge{}
+	case MsgTypeRANInformationRelay:
+		m = &RANInformationRelay{}
+	case MsgTypeEndMarker:
+		m = &EndMarker{}
 	case MsgTypeTPDU:
 		m = &TPDU{}
 	default:
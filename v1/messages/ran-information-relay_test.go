@@ -0,0 +1,47 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/wmnsk/go-gtp/v1/ies"
+	"github.com/wmnsk/go-gtp/v1/messages"
+	"github.com/wmnsk/go-gtp/v1/testutils"
+)
+
+func TestRANInformationRelay(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "Normal",
+			Structured: messages.NewRANInformationRelay(
+				0, testutils.TestBearerInfo.Seq,
+				ies.NewRANTransparentContainer([]byte{0xde, 0xad, 0xbe, 0xef}),
+				ies.NewRIMRoutingAddress([]byte{0x21, 0xf3, 0x54, 0x00, 0xff, 0x01}),
+				ies.NewRIMRoutingAddressDiscriminator(1),
+			),
+			Serialized: []byte{
+				// Header
+				0x32, 0x46, 0x00, 0x18, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x01, 0x00, 0x00,
+				// RAN Transparent Container
+				0x90, 0x00, 0x04, 0xde, 0xad, 0xbe, 0xef,
+				// RIM Routing Address
+				0x9e, 0x00, 0x06, 0x21, 0xf3, 0x54, 0x00, 0xff, 0x01,
+				// RIM Routing Address Discriminator
+				0xb2, 0x00, 0x01, 0x01,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializable, error) {
+		v, err := messages.ParseRANInformationRelay(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
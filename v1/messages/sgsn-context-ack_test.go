@@ -0,0 +1,48 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	v1 "github.com/wmnsk/go-gtp/v1"
+	"github.com/wmnsk/go-gtp/v1/ies"
+	"github.com/wmnsk/go-gtp/v1/messages"
+	"github.com/wmnsk/go-gtp/v1/testutils"
+)
+
+func TestSGSNContextAcknowledge(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "Normal",
+			Structured: messages.NewSGSNContextAcknowledge(
+				testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+				ies.NewCause(v1.ResCauseRequestAccepted),
+				ies.NewTEIDDataII(0xdeadbeef),
+				ies.NewGSNAddress("2.2.2.2"),
+			),
+			Serialized: []byte{
+				// Header
+				0x32, 0x34, 0x00, 0x12, 0x11, 0x22, 0x33, 0x44,
+				0x00, 0x01, 0x00, 0x00,
+				// Cause
+				0x01, 0x80,
+				// TEID Data II
+				0x12, 0xde, 0xad, 0xbe, 0xef,
+				// SGSN Address for User Traffic
+				0x85, 0x00, 0x04, 0x02, 0x02, 0x02, 0x02,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializable, error) {
+		v, err := messages.ParseSGSNContextAcknowledge(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
@@ -0,0 +1,320 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"github.com/wmnsk/go-gtp/v1/ies"
+)
+
+// SGSNContextRequest is a SGSNContextRequest Header and its IEs above.
+type SGSNContextRequest struct {
+	*Header
+	IMSI                            *ies.IE
+	RAI                             *ies.IE
+	TLLI                            *ies.IE
+	PTMSI                           *ies.IE
+	PTMSISignature                  *ies.IE
+	MSValidated                     *ies.IE
+	TEIDCPlane                      *ies.IE
+	SGSNAddressForCPlane            *ies.IE
+	AlternativeSGSNAddressForCPlane *ies.IE
+	SGSNNumber                      *ies.IE
+	RATType                         *ies.IE
+	HopCounter                      *ies.IE
+	PrivateExtension                *ies.IE
+	AdditionalIEs                   []*ies.IE
+}
+
+// NewSGSNContextRequest creates a new GTPv1 SGSNContextRequest.
+func NewSGSNContextRequest(teid uint32, seq uint16, ie ...*ies.IE) *SGSNContextRequest {
+	s := &SGSNContextRequest{
+		Header: NewHeader(0x32, MsgTypeSGSNContextRequest, teid, seq, nil),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.IMSI:
+			s.IMSI = i
+		case ies.RouteingAreaIdentity:
+			s.RAI = i
+		case ies.TemporaryLogicalLinkIdentity:
+			s.TLLI = i
+		case ies.PacketTMSI:
+			s.PTMSI = i
+		case ies.PTMSISignature:
+			s.PTMSISignature = i
+		case ies.MSValidated:
+			s.MSValidated = i
+		case ies.TEIDCPlane:
+			s.TEIDCPlane = i
+		case ies.GSNAddress:
+			if s.SGSNAddressForCPlane == nil {
+				s.SGSNAddressForCPlane = i
+			} else if s.AlternativeSGSNAddressForCPlane == nil {
+				s.AlternativeSGSNAddressForCPlane = i
+			}
+		case ies.SGSNNumber:
+			s.SGSNNumber = i
+		case ies.RATType:
+			s.RATType = i
+		case ies.HopCounter:
+			s.HopCounter = i
+		case ies.PrivateExtension:
+			s.PrivateExtension = i
+		default:
+			s.AdditionalIEs = append(s.AdditionalIEs, i)
+		}
+	}
+
+	s.SetLength()
+	return s
+}
+
+// Marshal returns the byte sequence generated from a SGSNContextRequest.
+func (s *SGSNContextRequest) Marshal() ([]byte, error) {
+	b := make([]byte, s.MarshalLen())
+	if err := s.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (s *SGSNContextRequest) MarshalTo(b []byte) error {
+	if len(b) < s.MarshalLen() {
+		return ErrTooShortToMarshal
+	}
+	s.Header.Payload = make([]byte, s.MarshalLen()-s.Header.MarshalLen())
+
+	offset := 0
+	if ie := s.IMSI; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.RAI; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.TLLI; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.PTMSI; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.PTMSISignature; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.MSValidated; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.TEIDCPlane; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.SGSNAddressForCPlane; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.AlternativeSGSNAddressForCPlane; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.SGSNNumber; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.RATType; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.HopCounter; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.PrivateExtension; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	for _, ie := range s.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(s.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	s.Header.SetLength()
+	return s.Header.MarshalTo(b)
+}
+
+// ParseSGSNContextRequest decodes a given byte sequence as a SGSNContextRequest.
+func ParseSGSNContextRequest(b []byte) (*SGSNContextRequest, error) {
+	s := &SGSNContextRequest{}
+	if err := s.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// UnmarshalBinary decodes a given byte sequence as a SGSNContextRequest.
+func (s *SGSNContextRequest) UnmarshalBinary(b []byte) error {
+	var err error
+	s.Header, err = ParseHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(s.Header.Payload) < 2 {
+		return nil
+	}
+
+	ie, err := ies.ParseMultiIEs(s.Header.Payload)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.IMSI:
+			s.IMSI = i
+		case ies.RouteingAreaIdentity:
+			s.RAI = i
+		case ies.TemporaryLogicalLinkIdentity:
+			s.TLLI = i
+		case ies.PacketTMSI:
+			s.PTMSI = i
+		case ies.PTMSISignature:
+			s.PTMSISignature = i
+		case ies.MSValidated:
+			s.MSValidated = i
+		case ies.TEIDCPlane:
+			s.TEIDCPlane = i
+		case ies.GSNAddress:
+			if s.SGSNAddressForCPlane == nil {
+				s.SGSNAddressForCPlane = i
+			} else if s.AlternativeSGSNAddressForCPlane == nil {
+				s.AlternativeSGSNAddressForCPlane = i
+			}
+		case ies.SGSNNumber:
+			s.SGSNNumber = i
+		case ies.RATType:
+			s.RATType = i
+		case ies.HopCounter:
+			s.HopCounter = i
+		case ies.PrivateExtension:
+			s.PrivateExtension = i
+		default:
+			s.AdditionalIEs = append(s.AdditionalIEs, i)
+		}
+	}
+	return nil
+}
+
+// MarshalLen returns the serial length of Data.
+func (s *SGSNContextRequest) MarshalLen() int {
+	l := s.Header.MarshalLen() - len(s.Header.Payload)
+
+	if ie := s.IMSI; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.RAI; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.TLLI; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.PTMSI; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.PTMSISignature; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.MSValidated; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.TEIDCPlane; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.SGSNAddressForCPlane; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.AlternativeSGSNAddressForCPlane; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.SGSNNumber; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.RATType; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.HopCounter; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.PrivateExtension; ie != nil {
+		l += ie.MarshalLen()
+	}
+
+	for _, ie := range s.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (s *SGSNContextRequest) SetLength() {
+	s.Length = uint16(s.MarshalLen() - 8)
+}
+
+// MessageTypeName returns the name of protocol.
+func (s *SGSNContextRequest) MessageTypeName() string {
+	return "SGSN Context Request"
+}
+
+// TEID returns the TEID in human-readable string.
+func (s *SGSNContextRequest) TEID() uint32 {
+	return s.Header.TEID
+}
@@ -0,0 +1,60 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	v1 "github.com/wmnsk/go-gtp/v1"
+	"github.com/wmnsk/go-gtp/v1/ies"
+	"github.com/wmnsk/go-gtp/v1/messages"
+	"github.com/wmnsk/go-gtp/v1/testutils"
+)
+
+func TestSGSNContextRequest(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "Normal",
+			Structured: messages.NewSGSNContextRequest(
+				testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+				ies.NewIMSI("123450123456789"),
+				ies.NewRouteingAreaIdentity("123", "45", 0x1111, 0x22),
+				ies.NewPacketTMSI(0xbeebee),
+				ies.NewTEIDCPlane(0xdeadbeef),
+				ies.NewGSNAddress("1.1.1.1"),
+				ies.NewRATType(v1.RatTypeUTRAN),
+				ies.NewHopCounter(3),
+			),
+			Serialized: []byte{
+				// Header
+				0x32, 0x32, 0x00, 0x2d, 0x11, 0x22, 0x33, 0x44,
+				0x00, 0x01, 0x00, 0x00,
+				// IMSI
+				0x02, 0x21, 0x43, 0x05, 0x21, 0x43, 0x65, 0x87, 0xf9,
+				// RAI
+				0x03, 0x21, 0xf3, 0x54, 0x11, 0x11, 0x22,
+				// P-TMSI
+				0x05, 0x00, 0xbe, 0xeb, 0xee,
+				// TEID-C
+				0x11, 0xde, 0xad, 0xbe, 0xef,
+				// SGSN Address for Control Plane
+				0x85, 0x00, 0x04, 0x01, 0x01, 0x01, 0x01,
+				// RAT Type
+				0x97, 0x00, 0x01, 0x01,
+				// Hop Counter
+				0xa3, 0x00, 0x01, 0x03,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializable, error) {
+		v, err := messages.ParseSGSNContextRequest(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
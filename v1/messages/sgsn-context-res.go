@@ -0,0 +1,632 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"github.com/wmnsk/go-gtp/v1/ies"
+)
+
+// SGSNContextResponse is a SGSNContextResponse Header and its IEs above.
+type SGSNContextResponse struct {
+	*Header
+	Cause                                  *ies.IE
+	IMSI                                   *ies.IE
+	TEIDCPlane                             *ies.IE
+	RABContexts                            []*ies.IE
+	RadioPrioritySMS                       *ies.IE
+	RadioPriorities                        []*ies.IE
+	PacketFlowIDs                          []*ies.IE
+	ChargingCharacteristics                *ies.IE
+	RadioPriorityLCS                       *ies.IE
+	MMContext                              *ies.IE
+	PDPContexts                            []*ies.IE
+	SGSNAddressForCPlane                   *ies.IE
+	PDPContextPrioritization               *ies.IE
+	MBMSUEContexts                         []*ies.IE
+	SubscribedRFSPIndex                    *ies.IE
+	RFSPIndexInUse                         *ies.IE
+	CoLocatedGGSNPGWFQDN                   *ies.IE
+	EvolvedARPII                           *ies.IE
+	ExtendedCommonFlags                    *ies.IE
+	UENetworkCapability                    *ies.IE
+	UEAMBR                                 *ies.IE
+	APNAMBRWithNSAPIs                      []*ies.IE
+	SignallingPriorityIndicationWithNSAPIs []*ies.IE
+	HigherBitratesThan16MbpsFlag           *ies.IE
+	SelectionModeWithNSAPIs                []*ies.IE
+	LHNIDWithNSAPIs                        []*ies.IE
+	UEUsageType                            *ies.IE
+	ExtendedCommonFlagsII                  *ies.IE
+	SCEFPDNConnections                     []*ies.IE
+	IOVUpdatesCounter                      *ies.IE
+	PrivateExtension                       *ies.IE
+	AdditionalIEs                          []*ies.IE
+}
+
+// NewSGSNContextResponse creates a new GTPv1 SGSNContextResponse.
+func NewSGSNContextResponse(teid uint32, seq uint16, ie ...*ies.IE) *SGSNContextResponse {
+	s := &SGSNContextResponse{
+		Header: NewHeader(0x32, MsgTypeSGSNContextResponse, teid, seq, nil),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			s.Cause = i
+		case ies.IMSI:
+			s.IMSI = i
+		case ies.TEIDCPlane:
+			s.TEIDCPlane = i
+		case ies.RABContext:
+			s.RABContexts = append(s.RABContexts, i)
+		case ies.RadioPrioritySMS:
+			s.RadioPrioritySMS = i
+		case ies.RadioPriority:
+			s.RadioPriorities = append(s.RadioPriorities, i)
+		case ies.PacketFlowID:
+			s.PacketFlowIDs = append(s.PacketFlowIDs, i)
+		case ies.ChargingCharacteristics:
+			s.ChargingCharacteristics = i
+		case ies.RadioPriorityLCS:
+			s.RadioPriorityLCS = i
+		case ies.MMContext:
+			s.MMContext = i
+		case ies.PDPContext:
+			s.PDPContexts = append(s.PDPContexts, i)
+		case ies.GSNAddress:
+			s.SGSNAddressForCPlane = i
+		case ies.PDPContextPrioritization:
+			s.PDPContextPrioritization = i
+		case ies.MBMSUEContext:
+			s.MBMSUEContexts = append(s.MBMSUEContexts, i)
+		case ies.RFSPIndex:
+			if s.SubscribedRFSPIndex == nil {
+				s.SubscribedRFSPIndex = i
+			} else if s.RFSPIndexInUse == nil {
+				s.RFSPIndexInUse = i
+			}
+		case ies.FullyQualifiedDomainName:
+			s.CoLocatedGGSNPGWFQDN = i
+		case ies.EvolvedAllocationRetentionPriorityII:
+			s.EvolvedARPII = i
+		case ies.ExtendedCommonFlags:
+			s.ExtendedCommonFlags = i
+		case ies.UENetworkCapability:
+			s.UENetworkCapability = i
+		case ies.UEAMBR:
+			s.UEAMBR = i
+		case ies.APNAMBRWithNSAPI:
+			s.APNAMBRWithNSAPIs = append(s.APNAMBRWithNSAPIs, i)
+		case ies.SignallingPriorityIndicationWithNSAPI:
+			s.SignallingPriorityIndicationWithNSAPIs = append(s.SignallingPriorityIndicationWithNSAPIs, i)
+		case ies.HigherBitratesThan16MbpsFlag:
+			s.HigherBitratesThan16MbpsFlag = i
+		case ies.SelectionModeWithNSAPI:
+			s.SelectionModeWithNSAPIs = append(s.SelectionModeWithNSAPIs, i)
+		case ies.LHNIDWithNSAPI:
+			s.LHNIDWithNSAPIs = append(s.LHNIDWithNSAPIs, i)
+		case ies.UEUsageType:
+			s.UEUsageType = i
+		case ies.ExtendedCommonFlagsII:
+			s.ExtendedCommonFlagsII = i
+		case ies.SCEFPDNConnection:
+			s.SCEFPDNConnections = append(s.SCEFPDNConnections, i)
+		case ies.IOVUpdatesCounter:
+			s.IOVUpdatesCounter = i
+		case ies.PrivateExtension:
+			s.PrivateExtension = i
+		default:
+			s.AdditionalIEs = append(s.AdditionalIEs, i)
+		}
+	}
+
+	s.SetLength()
+	return s
+}
+
+// Marshal returns the byte sequence generated from a SGSNContextResponse.
+func (s *SGSNContextResponse) Marshal() ([]byte, error) {
+	b := make([]byte, s.MarshalLen())
+	if err := s.MarshalTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// MarshalTo puts the byte sequence in the byte array given as b.
+func (s *SGSNContextResponse) MarshalTo(b []byte) error {
+	if len(b) < s.MarshalLen() {
+		return ErrTooShortToMarshal
+	}
+	s.Header.Payload = make([]byte, s.MarshalLen()-s.Header.MarshalLen())
+
+	offset := 0
+	if ie := s.Cause; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.IMSI; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.TEIDCPlane; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	for _, ie := range s.RABContexts {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.RadioPrioritySMS; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	for _, ie := range s.RadioPriorities {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	for _, ie := range s.PacketFlowIDs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.ChargingCharacteristics; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.RadioPriorityLCS; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.MMContext; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	for _, ie := range s.PDPContexts {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.SGSNAddressForCPlane; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.PDPContextPrioritization; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	for _, ie := range s.MBMSUEContexts {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.SubscribedRFSPIndex; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.RFSPIndexInUse; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.CoLocatedGGSNPGWFQDN; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.EvolvedARPII; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.ExtendedCommonFlags; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.UENetworkCapability; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.UEAMBR; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	for _, ie := range s.APNAMBRWithNSAPIs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	for _, ie := range s.SignallingPriorityIndicationWithNSAPIs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.HigherBitratesThan16MbpsFlag; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	for _, ie := range s.SelectionModeWithNSAPIs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	for _, ie := range s.LHNIDWithNSAPIs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.UEUsageType; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.ExtendedCommonFlagsII; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	for _, ie := range s.SCEFPDNConnections {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.IOVUpdatesCounter; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.PrivateExtension; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	for _, ie := range s.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(s.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	s.Header.SetLength()
+	return s.Header.MarshalTo(b)
+}
+
+// ParseSGSNContextResponse decodes a given byte sequence as a SGSNContextResponse.
+func ParseSGSNContextResponse(b []byte) (*SGSNContextResponse, error) {
+	s := &SGSNContextResponse{}
+	if err := s.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// UnmarshalBinary decodes a given byte sequence as a SGSNContextResponse.
+func (s *SGSNContextResponse) UnmarshalBinary(b []byte) error {
+	var err error
+	s.Header, err = ParseHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(s.Header.Payload) < 2 {
+		return nil
+	}
+
+	ie, err := ies.ParseMultiIEs(s.Header.Payload)
+	if err != nil {
+		return err
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			s.Cause = i
+		case ies.IMSI:
+			s.IMSI = i
+		case ies.TEIDCPlane:
+			s.TEIDCPlane = i
+		case ies.RABContext:
+			s.RABContexts = append(s.RABContexts, i)
+		case ies.RadioPrioritySMS:
+			s.RadioPrioritySMS = i
+		case ies.RadioPriority:
+			s.RadioPriorities = append(s.RadioPriorities, i)
+		case ies.PacketFlowID:
+			s.PacketFlowIDs = append(s.PacketFlowIDs, i)
+		case ies.ChargingCharacteristics:
+			s.ChargingCharacteristics = i
+		case ies.RadioPriorityLCS:
+			s.RadioPriorityLCS = i
+		case ies.MMContext:
+			s.MMContext = i
+		case ies.PDPContext:
+			s.PDPContexts = append(s.PDPContexts, i)
+		case ies.GSNAddress:
+			s.SGSNAddressForCPlane = i
+		case ies.PDPContextPrioritization:
+			s.PDPContextPrioritization = i
+		case ies.MBMSUEContext:
+			s.MBMSUEContexts = append(s.MBMSUEContexts, i)
+		case ies.RFSPIndex:
+			if s.SubscribedRFSPIndex == nil {
+				s.SubscribedRFSPIndex = i
+			} else if s.RFSPIndexInUse == nil {
+				s.RFSPIndexInUse = i
+			}
+		case ies.FullyQualifiedDomainName:
+			s.CoLocatedGGSNPGWFQDN = i
+		case ies.EvolvedAllocationRetentionPriorityII:
+			s.EvolvedARPII = i
+		case ies.ExtendedCommonFlags:
+			s.ExtendedCommonFlags = i
+		case ies.UENetworkCapability:
+			s.UENetworkCapability = i
+		case ies.UEAMBR:
+			s.UEAMBR = i
+		case ies.APNAMBRWithNSAPI:
+			s.APNAMBRWithNSAPIs = append(s.APNAMBRWithNSAPIs, i)
+		case ies.SignallingPriorityIndicationWithNSAPI:
+			s.SignallingPriorityIndicationWithNSAPIs = append(s.SignallingPriorityIndicationWithNSAPIs, i)
+		case ies.HigherBitratesThan16MbpsFlag:
+			s.HigherBitratesThan16MbpsFlag = i
+		case ies.SelectionModeWithNSAPI:
+			s.SelectionModeWithNSAPIs = append(s.SelectionModeWithNSAPIs, i)
+		case ies.LHNIDWithNSAPI:
+			s.LHNIDWithNSAPIs = append(s.LHNIDWithNSAPIs, i)
+		case ies.UEUsageType:
+			s.UEUsageType = i
+		case ies.ExtendedCommonFlagsII:
+			s.ExtendedCommonFlagsII = i
+		case ies.SCEFPDNConnection:
+			s.SCEFPDNConnections = append(s.SCEFPDNConnections, i)
+		case ies.IOVUpdatesCounter:
+			s.IOVUpdatesCounter = i
+		case ies.PrivateExtension:
+			s.PrivateExtension = i
+		default:
+			s.AdditionalIEs = append(s.AdditionalIEs, i)
+		}
+	}
+	return nil
+}
+
+// MarshalLen returns the serial length of Data.
+func (s *SGSNContextResponse) MarshalLen() int {
+	l := s.Header.MarshalLen() - len(s.Header.Payload)
+
+	if ie := s.Cause; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.IMSI; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.TEIDCPlane; ie != nil {
+		l += ie.MarshalLen()
+	}
+	for _, ie := range s.RABContexts {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	if ie := s.RadioPrioritySMS; ie != nil {
+		l += ie.MarshalLen()
+	}
+	for _, ie := range s.RadioPriorities {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	for _, ie := range s.PacketFlowIDs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	if ie := s.ChargingCharacteristics; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.RadioPriorityLCS; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.MMContext; ie != nil {
+		l += ie.MarshalLen()
+	}
+	for _, ie := range s.PDPContexts {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	if ie := s.SGSNAddressForCPlane; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.PDPContextPrioritization; ie != nil {
+		l += ie.MarshalLen()
+	}
+	for _, ie := range s.MBMSUEContexts {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	if ie := s.SubscribedRFSPIndex; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.RFSPIndexInUse; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.CoLocatedGGSNPGWFQDN; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.EvolvedARPII; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.ExtendedCommonFlags; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.UENetworkCapability; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.UEAMBR; ie != nil {
+		l += ie.MarshalLen()
+	}
+	for _, ie := range s.APNAMBRWithNSAPIs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	for _, ie := range s.SignallingPriorityIndicationWithNSAPIs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	if ie := s.HigherBitratesThan16MbpsFlag; ie != nil {
+		l += ie.MarshalLen()
+	}
+	for _, ie := range s.SelectionModeWithNSAPIs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	for _, ie := range s.LHNIDWithNSAPIs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	if ie := s.UEUsageType; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.ExtendedCommonFlagsII; ie != nil {
+		l += ie.MarshalLen()
+	}
+	for _, ie := range s.SCEFPDNConnections {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	if ie := s.IOVUpdatesCounter; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.PrivateExtension; ie != nil {
+		l += ie.MarshalLen()
+	}
+
+	for _, ie := range s.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (s *SGSNContextResponse) SetLength() {
+	s.Length = uint16(s.MarshalLen() - 8)
+}
+
+// MessageTypeName returns the name of protocol.
+func (s *SGSNContextResponse) MessageTypeName() string {
+	return "SGSN Context Response"
+}
+
+// TEID returns the TEID in human-readable string.
+func (s *SGSNContextResponse) TEID() uint32 {
+	return s.Header.TEID
+}
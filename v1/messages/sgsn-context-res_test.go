@@ -0,0 +1,69 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	v1 "github.com/wmnsk/go-gtp/v1"
+	"github.com/wmnsk/go-gtp/v1/ies"
+	"github.com/wmnsk/go-gtp/v1/messages"
+	"github.com/wmnsk/go-gtp/v1/testutils"
+)
+
+func TestSGSNContextResponse(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "Normal",
+			Structured: messages.NewSGSNContextResponse(
+				testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+				ies.NewCause(v1.ResCauseRequestAccepted),
+				ies.NewIMSI("123450123456789"),
+				ies.NewTEIDCPlane(0xdeadbeef),
+				ies.NewRABContext(5, 0x1111, 0x2222, 0x3333, 0x4444),
+				ies.NewRadioPrioritySMS(2),
+				ies.NewRadioPriority(5, 4),
+				ies.NewPacketFlowID(5, 0x7f),
+				ies.New(ies.MMContext, []byte{0xde, 0xad, 0xbe, 0xef}),
+				ies.New(ies.PDPContext, []byte{0xde, 0xad, 0xbe, 0xef}),
+				ies.NewGSNAddress("1.1.1.1"),
+			),
+			Serialized: []byte{
+				// Header
+				0x32, 0x33, 0x00, 0x3a, 0x11, 0x22, 0x33, 0x44,
+				0x00, 0x01, 0x00, 0x00,
+				// Cause
+				0x01, 0x80,
+				// IMSI
+				0x02, 0x21, 0x43, 0x05, 0x21, 0x43, 0x65, 0x87, 0xf9,
+				// TEID-C
+				0x11, 0xde, 0xad, 0xbe, 0xef,
+				// RAB Context
+				0x16, 0x05, 0x11, 0x11, 0x22, 0x22, 0x33, 0x33, 0x44, 0x44,
+				// Radio Priority SMS
+				0x17, 0x02,
+				// Radio Priority
+				0x18, 0x54,
+				// Packet Flow ID
+				0x19, 0x05, 0x7f,
+				// MM Context
+				0x81, 0x00, 0x04, 0xde, 0xad, 0xbe, 0xef,
+				// PDP Context
+				0x82, 0x00, 0x04, 0xde, 0xad, 0xbe, 0xef,
+				// SGSN Address for Control Plane
+				0x85, 0x00, 0x04, 0x01, 0x01, 0x01, 0x01,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializable, error) {
+		v, err := messages.ParseSGSNContextResponse(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
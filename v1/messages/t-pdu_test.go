@@ -27,6 +27,22 @@ func TestTPDU(t *testing.T) {
 				0x32, 0xff, 0x00, 0x08, 0xde, 0xad, 0xbe, 0xef,
 				0x00, 0x01, 0x00, 0x00, 0xde, 0xad, 0xbe, 0xef,
 			},
+		}, {
+			Description: "With-ExtensionHeaders",
+			Structured: messages.NewTPDUWithExtensionHeaders(
+				0xdeadbeef, []byte{0xde, 0xad, 0xbe, 0xef},
+				messages.NewUDPPortExtensionHeader(2152),
+				messages.NewPDUSessionContainer(messages.PDUTypeDLPDUSessionInformation, 9, true),
+			),
+			Serialized: []byte{
+				0x34, 0xff, 0x00, 0x10, 0xde, 0xad, 0xbe, 0xef,
+				0x00, 0x00, 0x00, 0x40,
+				// UDP Port
+				0x01, 0x08, 0x68, 0x85,
+				// PDU Session Container
+				0x01, 0x00, 0x49, 0x00,
+				0xde, 0xad, 0xbe, 0xef,
+			},
 		},
 	}
 
@@ -0,0 +1,291 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/wmnsk/go-gtp/v1/messages"
+)
+
+// MetricsCollector receives the events that occur on UPlaneConn, so that they can
+// be exported to the monitoring systems such as Prometheus.
+//
+// The methods are called synchronously in the goroutines that send and receive
+// the messages, and thus they should return quickly.
+type MetricsCollector interface {
+	// MessageSent is called when a message is sent.
+	MessageSent(msgType uint8)
+	// MessageReceived is called when a message is received.
+	MessageReceived(msgType uint8)
+	// ParseError is called when the received bytes cannot be parsed.
+	ParseError()
+	// EchoRTT is called when an Echo Response is received from peer.
+	EchoRTT(peer net.Addr, rtt time.Duration)
+}
+
+// Stats is a snapshot of the statistics of UPlaneConn.
+type Stats struct {
+	// MessagesSent is the number of messages sent, keyed by message type.
+	// T-PDUs forwarded by the relay are also counted.
+	MessagesSent map[uint8]uint64
+	// MessagesReceived is the number of messages received, keyed by message type.
+	// T-PDUs forwarded by the relay are also counted.
+	MessagesReceived map[uint8]uint64
+	// ParseErrors is the number of received datagrams that failed to be parsed.
+	ParseErrors uint64
+	// Relays is the number of TEIDs configured to be relayed.
+	Relays int
+	// EchoRTT is the last round-trip time of Echo, keyed by the address of peer.
+	EchoRTT map[string]time.Duration
+	// Tunnels is the traffic of T-PDUs, keyed by the TEID in the GTP header. The
+	// TEID of the ones received is the local one, and the one of the ones sent is
+	// the remote one.
+	Tunnels map[uint32]TrafficStats
+	// Peers is the traffic of T-PDUs, keyed by the address of peer.
+	Peers map[string]TrafficStats
+}
+
+// TrafficStats is the traffic of T-PDUs on a tunnel or with a peer. The bytes are the
+// length of the T-PDUs including GTP header.
+type TrafficStats struct {
+	// PacketsSent and BytesSent are the T-PDUs sent, including the relayed ones.
+	PacketsSent, BytesSent uint64
+	// PacketsReceived and BytesReceived are the T-PDUs received, including the
+	// relayed ones and the dropped ones.
+	PacketsReceived, BytesReceived uint64
+	// Dropped is the number of T-PDUs received and discarded, as the TEID is unknown,
+	// they exceed the Policer of the route, or they are not read with ReadFromGTP in
+	// time.
+	Dropped uint64
+	// ErrorIndicationsSent and ErrorIndicationsReceived are the number of Error
+	// Indications, which is counted for the TEID in TEID Data I.
+	ErrorIndicationsSent, ErrorIndicationsReceived uint64
+}
+
+type collectorHolder struct {
+	MetricsCollector
+}
+
+// connStats is the statistics of UPlaneConn. The counters are placed first to be
+// 64-bit aligned for atomic operations.
+type connStats struct {
+	sent        [256]uint64
+	received    [256]uint64
+	parseErrors uint64
+
+	collector atomic.Value
+
+	mu       sync.Mutex
+	echoSent map[string]time.Time
+	echoRTT  map[string]time.Duration
+	tunnels  map[uint32]*TrafficStats
+	peers    map[string]*TrafficStats
+}
+
+func newConnStats() *connStats {
+	return &connStats{
+		echoSent: map[string]time.Time{},
+		echoRTT:  map[string]time.Duration{},
+		tunnels:  map[uint32]*TrafficStats{},
+		peers:    map[string]*TrafficStats{},
+	}
+}
+
+func (s *connStats) metricsCollector() MetricsCollector {
+	if h, ok := s.collector.Load().(collectorHolder); ok {
+		return h.MetricsCollector
+	}
+	return nil
+}
+
+func (s *connStats) messageSent(raddr net.Addr, msgType uint8) {
+	if s == nil {
+		return
+	}
+	atomic.AddUint64(&s.sent[msgType], 1)
+	if msgType == messages.MsgTypeEchoRequest {
+		s.mu.Lock()
+		s.echoSent[raddr.String()] = time.Now()
+		s.mu.Unlock()
+	}
+
+	if mc := s.metricsCollector(); mc != nil {
+		mc.MessageSent(msgType)
+	}
+}
+
+func (s *connStats) messageReceived(raddr net.Addr, msgType uint8) {
+	if s == nil {
+		return
+	}
+	atomic.AddUint64(&s.received[msgType], 1)
+	mc := s.metricsCollector()
+	if mc != nil {
+		mc.MessageReceived(msgType)
+	}
+	if msgType != messages.MsgTypeEchoResponse {
+		return
+	}
+
+	s.mu.Lock()
+	sentAt, ok := s.echoSent[raddr.String()]
+	if !ok {
+		s.mu.Unlock()
+		return
+	}
+	delete(s.echoSent, raddr.String())
+	rtt := time.Since(sentAt)
+	s.echoRTT[raddr.String()] = rtt
+	s.mu.Unlock()
+
+	if mc != nil {
+		mc.EchoRTT(raddr, rtt)
+	}
+}
+
+func (s *connStats) parseError() {
+	if s == nil {
+		return
+	}
+	atomic.AddUint64(&s.parseErrors, 1)
+	if mc := s.metricsCollector(); mc != nil {
+		mc.ParseError()
+	}
+}
+
+// traffic calls fn with the TrafficStats of the tunnel with teid and of the peer.
+func (s *connStats) traffic(raddr net.Addr, teid uint32, fn func(t *TrafficStats)) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tunnels[teid]
+	if !ok {
+		t = &TrafficStats{}
+		s.tunnels[teid] = t
+	}
+	fn(t)
+
+	p, ok := s.peers[raddr.String()]
+	if !ok {
+		p = &TrafficStats{}
+		s.peers[raddr.String()] = p
+	}
+	fn(p)
+}
+
+func (s *connStats) tpduSent(raddr net.Addr, teid uint32, n int) {
+	s.traffic(raddr, teid, func(t *TrafficStats) {
+		t.PacketsSent++
+		t.BytesSent += uint64(n)
+	})
+}
+
+func (s *connStats) tpduReceived(raddr net.Addr, teid uint32, n int) {
+	s.traffic(raddr, teid, func(t *TrafficStats) {
+		t.PacketsReceived++
+		t.BytesReceived += uint64(n)
+	})
+}
+
+func (s *connStats) tpduDropped(raddr net.Addr, teid uint32) {
+	s.traffic(raddr, teid, func(t *TrafficStats) {
+		t.Dropped++
+	})
+}
+
+func (s *connStats) errorIndicationSent(raddr net.Addr, teid uint32) {
+	s.traffic(raddr, teid, func(t *TrafficStats) {
+		t.ErrorIndicationsSent++
+	})
+}
+
+func (s *connStats) errorIndicationReceived(raddr net.Addr, teid uint32) {
+	s.traffic(raddr, teid, func(t *TrafficStats) {
+		t.ErrorIndicationsReceived++
+	})
+}
+
+// SetMetricsCollector registers the MetricsCollector to be notified of the events on
+// UPlaneConn. Giving nil unregisters it.
+//
+// The statistics are always counted regardless of MetricsCollector and can be
+// retrieved with Stats.
+func (u *UPlaneConn) SetMetricsCollector(mc MetricsCollector) {
+	if u.stats == nil {
+		return
+	}
+	u.stats.collector.Store(collectorHolder{mc})
+}
+
+// Stats returns the snapshot of the statistics of UPlaneConn.
+//
+// Note that the packets handled by Kernel GTP-U are not counted.
+func (u *UPlaneConn) Stats() *Stats {
+	relays := len(u.routeTable())
+
+	st := &Stats{
+		MessagesSent:     map[uint8]uint64{},
+		MessagesReceived: map[uint8]uint64{},
+		EchoRTT:          map[string]time.Duration{},
+		Tunnels:          map[uint32]TrafficStats{},
+		Peers:            map[string]TrafficStats{},
+		Relays:           relays,
+	}
+
+	s := u.stats
+	if s == nil {
+		return st
+	}
+
+	for i := range s.sent {
+		if n := atomic.LoadUint64(&s.sent[i]); n != 0 {
+			st.MessagesSent[uint8(i)] = n
+		}
+		if n := atomic.LoadUint64(&s.received[i]); n != 0 {
+			st.MessagesReceived[uint8(i)] = n
+		}
+	}
+	st.ParseErrors = atomic.LoadUint64(&s.parseErrors)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for peer, rtt := range s.echoRTT {
+		st.EchoRTT[peer] = rtt
+	}
+	for teid, t := range s.tunnels {
+		st.Tunnels[teid] = *t
+	}
+	for peer, t := range s.peers {
+		st.Peers[peer] = *t
+	}
+	return st
+}
+
+// ResetStats resets the counters in the statistics of UPlaneConn to zero. EchoRTT is
+// kept as it is not a counter.
+func (u *UPlaneConn) ResetStats() {
+	s := u.stats
+	if s == nil {
+		return
+	}
+
+	for i := range s.sent {
+		atomic.StoreUint64(&s.sent[i], 0)
+		atomic.StoreUint64(&s.received[i], 0)
+	}
+	atomic.StoreUint64(&s.parseErrors, 0)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tunnels = map[uint32]*TrafficStats{}
+	s.peers = map[string]*TrafficStats{}
+}
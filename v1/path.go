@@ -0,0 +1,211 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1
+
+import (
+	"net"
+	"time"
+)
+
+// PathState is the state of the path between UPlaneConn and the peer.
+type PathState int
+
+// PathState definitions.
+const (
+	// PathStateUnknown means no Echo Response has been received from the peer yet.
+	PathStateUnknown PathState = iota
+	// PathStateUp means the peer responded to the last Echo Request.
+	PathStateUp
+	// PathStateDown means the peer did not respond to the Echo Requests.
+	PathStateDown
+)
+
+// String returns the name of PathState.
+func (p PathState) String() string {
+	switch p {
+	case PathStateUp:
+		return "Up"
+	case PathStateDown:
+		return "Down"
+	default:
+		return "Unknown"
+	}
+}
+
+// Default values used in PathSupervision.
+const (
+	DefaultEchoInterval   = 60 * time.Second
+	DefaultEchoTimeout    = 3 * time.Second
+	DefaultEchoMaxRetries = 3
+)
+
+// PathSupervision is the configuration of the supervision of the path to a peer with
+// Echo Request and Response. The zero value of each field is replaced with the
+// default one.
+//
+// TS 29.281 7.2.1 Echo Request;
+// An Echo Request should not be sent more often than every 60 s on each path.
+type PathSupervision struct {
+	// Interval is the interval to send Echo Request while the peer is responding.
+	Interval time.Duration
+	// Timeout is the duration to wait for the Echo Response to each Echo Request.
+	Timeout time.Duration
+	// MaxRetries is the number of times to resend Echo Request without response
+	// before the path is considered to be down.
+	MaxRetries int
+}
+
+// PathFailureHandler is called when the path to the peer goes down.
+type PathFailureHandler func(raddr net.Addr)
+
+type path struct {
+	addr   net.Addr
+	config PathSupervision
+	state  PathState
+
+	// respCh is notified when the Echo Response is received from the peer, and
+	// stopCh is closed when the supervision is stopped.
+	respCh chan struct{}
+	stopCh chan struct{}
+}
+
+// SupervisePath starts sending Echo Request to raddr periodically in background to
+// supervise the path, until StopPathSupervision is called or UPlaneConn is closed.
+// Giving nil as ps uses the default values.
+//
+// The state of the path can be retrieved with PathState, and the PathFailureHandler
+// set with SetPathFailureHandler is called when it goes down. The path is kept
+// supervised after it goes down, so that it is detected when the peer is back.
+func (u *UPlaneConn) SupervisePath(raddr net.Addr, ps *PathSupervision) {
+	p := &path{
+		addr:   raddr,
+		respCh: make(chan struct{}, 1),
+		stopCh: make(chan struct{}),
+	}
+	if ps != nil {
+		p.config = *ps
+	}
+	if p.config.Interval <= 0 {
+		p.config.Interval = DefaultEchoInterval
+	}
+	if p.config.Timeout <= 0 {
+		p.config.Timeout = DefaultEchoTimeout
+	}
+	if p.config.MaxRetries <= 0 {
+		p.config.MaxRetries = DefaultEchoMaxRetries
+	}
+
+	u.mu.Lock()
+	if u.paths == nil {
+		u.paths = map[string]*path{}
+	}
+	if old, ok := u.paths[raddr.String()]; ok {
+		close(old.stopCh)
+	}
+	u.paths[raddr.String()] = p
+	u.mu.Unlock()
+
+	go u.supervise(p)
+}
+
+// StopPathSupervision stops the supervision of the path to raddr started with
+// SupervisePath.
+func (u *UPlaneConn) StopPathSupervision(raddr net.Addr) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if p, ok := u.paths[raddr.String()]; ok {
+		close(p.stopCh)
+		delete(u.paths, raddr.String())
+	}
+}
+
+// PathState returns the state of the path to raddr supervised with SupervisePath.
+// It returns PathStateUnknown if the path is not supervised.
+func (u *UPlaneConn) PathState(raddr net.Addr) PathState {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if p, ok := u.paths[raddr.String()]; ok {
+		return p.state
+	}
+	return PathStateUnknown
+}
+
+// SetPathFailureHandler registers the PathFailureHandler to be called when any of
+// the paths supervised with SupervisePath goes down. Giving nil unregisters it.
+func (u *UPlaneConn) SetPathFailureHandler(fn PathFailureHandler) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.pathFailureHandler = fn
+}
+
+func (u *UPlaneConn) supervise(p *path) {
+	retries := 0
+	for {
+		// discard the response that came too late for the previous request.
+		select {
+		case <-p.respCh:
+		default:
+		}
+
+		if err := u.EchoRequest(p.addr); err != nil {
+			go u.notifyError(err)
+		}
+
+		select {
+		case <-u.closed():
+			return
+		case <-p.stopCh:
+			return
+		case <-p.respCh:
+			retries = 0
+			u.setPathState(p, PathStateUp)
+		case <-time.After(p.config.Timeout):
+			if retries < p.config.MaxRetries {
+				retries++
+				continue
+			}
+			retries = 0
+			u.setPathState(p, PathStateDown)
+		}
+
+		select {
+		case <-u.closed():
+			return
+		case <-p.stopCh:
+			return
+		case <-time.After(p.config.Interval):
+		}
+	}
+}
+
+func (u *UPlaneConn) setPathState(p *path, state PathState) {
+	u.mu.Lock()
+	prev := p.state
+	p.state = state
+	fn := u.pathFailureHandler
+	u.mu.Unlock()
+
+	if state == PathStateDown && prev != PathStateDown && fn != nil {
+		fn(p.addr)
+	}
+}
+
+// echoResponded notifies the supervision of the path to raddr that the Echo Response
+// is received.
+func (u *UPlaneConn) echoResponded(raddr net.Addr) {
+	u.mu.Lock()
+	p, ok := u.paths[raddr.String()]
+	u.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	select {
+	case p.respCh <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1
+
+import (
+	"net"
+
+	"github.com/wmnsk/go-gtp/v1/ies"
+	"github.com/wmnsk/go-gtp/v1/messages"
+)
+
+// CreatePDPContext sends Create PDP Context Request to raddr with the IEs given and
+// returns a new Session together with the SequenceNumber used in the message.
+//
+// The Session is added to CPlaneConn with the IMSI, MSISDN, IMEI, APN and NSAPI taken
+// from the IEs given, and the TEIDs for Control Plane and Data I. If the TEID IEs are
+// not given, the TEIDs are generated with NewTEID and added to the message.
+//
+// The Session is activated with the TEIDs of the peer when the Create PDP Context
+// Response with the Cause of acceptance is received, or removed from CPlaneConn if it
+// is rejected. The HandlerFunc for Create PDP Context Response is called after that.
+func (c *CPlaneConn) CreatePDPContext(raddr net.Addr, ie ...*ies.IE) (*Session, uint16, error) {
+	sess := NewSession(raddr, "")
+	var hasTEIDC, hasTEIDU bool
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+
+		var err error
+		switch i.Type {
+		case ies.IMSI:
+			sess.IMSI, err = i.IMSI()
+		case ies.MSISDN:
+			sess.MSISDN, err = i.MSISDN()
+		case ies.IMEISV:
+			sess.IMEI, err = i.IMEISV()
+		case ies.AccessPointName:
+			sess.APN, err = i.AccessPointName()
+		case ies.NSAPI:
+			sess.NSAPI, err = i.NSAPI()
+		case ies.TEIDCPlane:
+			sess.LocalTEIDC, err = i.TEID()
+			hasTEIDC = true
+		case ies.TEIDDataI:
+			sess.LocalTEIDU, err = i.TEID()
+			hasTEIDU = true
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if sess.IMSI == "" {
+		return nil, 0, &RequiredIEMissingError{Type: ies.IMSI}
+	}
+	if !hasTEIDC {
+		sess.LocalTEIDC = c.NewTEID()
+		ie = append(ie, ies.NewTEIDCPlane(sess.LocalTEIDC))
+	}
+	if !hasTEIDU {
+		sess.LocalTEIDU = c.NewTEID()
+		ie = append(ie, ies.NewTEIDDataI(sess.LocalTEIDU))
+	}
+
+	c.AddSession(sess)
+	seq, err := c.SendMessageTo(messages.NewCreatePDPContextRequest(0, 0, ie...), raddr)
+	if err != nil {
+		c.RemoveSession(sess)
+		return nil, seq, err
+	}
+
+	return sess, seq, nil
+}
+
+// UpdatePDPContext sends Update PDP Context Request to the peer of the Session with
+// the IEs given, and returns the SequenceNumber used in the message. The NSAPI IE is
+// added if not given.
+//
+// The local TEIDs of the Session are updated with the TEID IEs given, if any, and the
+// ones of the peer are updated when the Update PDP Context Response with the Cause of
+// acceptance is received.
+func (c *CPlaneConn) UpdatePDPContext(sess *Session, ie ...*ies.IE) (uint16, error) {
+	var hasNSAPI bool
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+
+		var err error
+		switch i.Type {
+		case ies.NSAPI:
+			hasNSAPI = true
+		case ies.TEIDCPlane:
+			sess.LocalTEIDC, err = i.TEID()
+		case ies.TEIDDataI:
+			sess.LocalTEIDU, err = i.TEID()
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	if !hasNSAPI {
+		ie = append(ie, ies.NewNSAPI(sess.NSAPI))
+	}
+
+	return c.SendMessageTo(messages.NewUpdatePDPContextRequest(sess.RemoteTEIDC, 0, ie...), sess.PeerAddr())
+}
+
+// DeletePDPContext sends Delete PDP Context Request to the peer of the Session with
+// the IEs given, and returns the SequenceNumber used in the message. The NSAPI IE is
+// added if not given.
+//
+// The Session is removed from CPlaneConn when the Delete PDP Context Response with the
+// Cause of acceptance or "Non-existent" is received.
+func (c *CPlaneConn) DeletePDPContext(sess *Session, ie ...*ies.IE) (uint16, error) {
+	var hasNSAPI bool
+	for _, i := range ie {
+		if i != nil && i.Type == ies.NSAPI {
+			hasNSAPI = true
+		}
+	}
+	if !hasNSAPI {
+		ie = append(ie, ies.NewNSAPI(sess.NSAPI))
+	}
+
+	return c.SendMessageTo(messages.NewDeletePDPContextRequest(sess.RemoteTEIDC, 0, ie...), sess.PeerAddr())
+}
+
+// updateSession updates the Session with the response to CreatePDPContext,
+// UpdatePDPContext or DeletePDPContext. The other messages are just ignored.
+func (c *CPlaneConn) updateSession(msg messages.Message) error {
+	switch m := msg.(type) {
+	case *messages.CreatePDPContextResponse:
+		sess, err := c.GetSessionByTEID(m.TEID())
+		if err != nil {
+			return nil
+		}
+		if m.Cause == nil {
+			c.RemoveSession(sess)
+			return &RequiredIEMissingError{Type: ies.Cause}
+		}
+		if !isCauseAccepted(m.Cause.MustCause()) {
+			c.RemoveSession(sess)
+			return nil
+		}
+
+		sess.mu.Lock()
+		if m.TEIDCPlane != nil {
+			sess.RemoteTEIDC = m.TEIDCPlane.MustTEID()
+		}
+		if m.TEIDDataI != nil {
+			sess.RemoteTEIDU = m.TEIDDataI.MustTEID()
+		}
+		if m.GGSNAddressForUserTraffic != nil {
+			sess.PeerUPlaneAddr = m.GGSNAddressForUserTraffic.MustIPAddress()
+		}
+		if m.EndUserAddress != nil {
+			sess.MSAddress = m.EndUserAddress.MustIPAddress()
+		}
+		sess.mu.Unlock()
+		return sess.Activate()
+	case *messages.UpdatePDPContextResponse:
+		sess, err := c.GetSessionByTEID(m.TEID())
+		if err != nil {
+			return nil
+		}
+		if m.Cause == nil {
+			return &RequiredIEMissingError{Type: ies.Cause}
+		}
+		if !isCauseAccepted(m.Cause.MustCause()) {
+			return nil
+		}
+
+		sess.mu.Lock()
+		defer sess.mu.Unlock()
+		if m.TEIDCPlane != nil {
+			sess.RemoteTEIDC = m.TEIDCPlane.MustTEID()
+		}
+		if m.TEIDDataI != nil {
+			sess.RemoteTEIDU = m.TEIDDataI.MustTEID()
+		}
+		if m.GGSNAddressForUserTraffic != nil {
+			sess.PeerUPlaneAddr = m.GGSNAddressForUserTraffic.MustIPAddress()
+		}
+		return nil
+	case *messages.DeletePDPContextResponse:
+		sess, err := c.GetSessionByTEID(m.TEID())
+		if err != nil {
+			return nil
+		}
+		if m.Cause == nil {
+			return &RequiredIEMissingError{Type: ies.Cause}
+		}
+		if cause := m.Cause.MustCause(); !isCauseAccepted(cause) && cause != ResCauseNonExistent {
+			return nil
+		}
+
+		c.RemoveSession(sess)
+		return sess.Deactivate()
+	default:
+		return nil
+	}
+}
+
+// isCauseAccepted reports whether cause is the one of acceptance in the response.
+//
+// TS 29.060 7.7.1 Cause; the values from 128 to 191 indicate the acceptance.
+func isCauseAccepted(cause uint8) bool {
+	return cause >= ResCauseRequestAccepted && cause < ResCauseNonExistent
+}
@@ -0,0 +1,72 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1
+
+import (
+	"net"
+
+	"github.com/wmnsk/go-gtp/internal/sockopt"
+)
+
+// the length of the headers put before the payload of T-PDU sent by WriteToGTP,
+// i.e., IPv4 or IPv6, UDP and GTP header without optional fields.
+const (
+	ipv4HeaderLen = 20
+	ipv6HeaderLen = 40
+	udpHeaderLen  = 8
+	gtpHeaderLen  = 8
+)
+
+// PathMTU returns the path MTU toward raddr known to the kernel, in the length of IP
+// packet. It is updated with ICMP "fragmentation needed" and ICMPv6 "packet too big"
+// received on the path, and is the MTU of the outgoing interface until then.
+//
+// The path MTU is discovered only with DontFragment in SocketOptions, as otherwise
+// the large packets are fragmented and the ICMP is not sent back. This is supported
+// only on Linux, and ErrSocketOptionUnsupported is returned on the other platforms.
+func (u *UPlaneConn) PathMTU(raddr net.Addr) (int, error) {
+	return sockopt.PathMTU(raddr)
+}
+
+// MaxPayloadSize returns the largest payload of T-PDU that can be sent to raddr with
+// WriteToGTP without exceeding PathMTU, which can be used to clamp the TCP MSS or to
+// fragment the inner packets beforehand. The length of the Extension Headers should
+// be subtracted further when using WriteToGTPWithExtensionHeaders.
+func (u *UPlaneConn) MaxPayloadSize(raddr net.Addr) (int, error) {
+	mtu, err := u.PathMTU(raddr)
+	if err != nil {
+		return 0, err
+	}
+
+	size := mtu - udpHeaderLen - gtpHeaderLen
+	if isIPv6(raddr) {
+		return size - ipv6HeaderLen, nil
+	}
+	return size - ipv4HeaderLen, nil
+}
+
+// packetTooBig converts err returned in sending the packet of size to raddr into
+// PacketTooBigError if it is caused by the path MTU.
+func packetTooBig(err error, raddr net.Addr, size int) error {
+	if !sockopt.IsMessageTooLong(err) {
+		return err
+	}
+	mtu, merr := sockopt.PathMTU(raddr)
+	if merr != nil {
+		mtu = 0
+	}
+	return &PacketTooBigError{Peer: raddr.String(), MTU: mtu, Size: size}
+}
+
+func isIPv6(addr net.Addr) bool {
+	uaddr, ok := addr.(*net.UDPAddr)
+	if !ok {
+		var err error
+		if uaddr, err = net.ResolveUDPAddr("udp", addr.String()); err != nil {
+			return false
+		}
+	}
+	return uaddr.IP.To4() == nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultPolicerBurstDuration is the duration of the traffic at the rate of Policer
+// that is allowed in burst, which is used if the burst size is not given.
+const DefaultPolicerBurstDuration = 100 * time.Millisecond
+
+// minPolicerBurst is the smallest burst size of Policer, which lets a T-PDU of the
+// usual MTU pass even with the very low rate.
+const minPolicerBurst = 1500
+
+// Policer is the token bucket that limits the rate of the T-PDUs relayed on a route,
+// which is attached with SetRoutePolicer. The T-PDUs exceeding the rate are dropped,
+// not delayed, as the relay should not hold the T-PDUs of the other routes.
+//
+// It is safe to use Policer from multiple goroutines, and the same Policer can be
+// attached to multiple routes to limit the aggregated rate of them.
+type Policer struct {
+	mu     sync.Mutex
+	rate   float64 // bytes per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewPolicer creates a new Policer with the rate in kbps, which is the unit of MBR and
+// GBR in the QoS Profile of GTPv1 and the Bearer QoS of GTPv2, and the burst size in
+// bytes. Giving 0 or negative burst uses the size of DefaultPolicerBurstDuration at the
+// rate, which is at least 1500 bytes.
+//
+// The bucket is full when created.
+func NewPolicer(kbps uint64, burst int) *Policer {
+	rate := float64(kbps) * 1000 / 8
+	b := float64(burst)
+	if burst <= 0 {
+		b = rate * DefaultPolicerBurstDuration.Seconds()
+		if b < minPolicerBurst {
+			b = minPolicerBurst
+		}
+	}
+	return &Policer{rate: rate, burst: b, tokens: b, last: time.Now()}
+}
+
+// NewBearerPolicer creates a new Policer with the MBR of the bearer in kbps, or the
+// GBR if MBR is zero, with the default burst size. It returns nil if both are zero,
+// which does not police the route when given to SetRoutePolicer.
+//
+// The T-PDUs within GBR are not prioritized over the others, as it requires to
+// schedule the T-PDUs of all the routes on the path.
+func NewBearerPolicer(mbr, gbr uint64) *Policer {
+	switch {
+	case mbr != 0:
+		return NewPolicer(mbr, 0)
+	case gbr != 0:
+		return NewPolicer(gbr, 0)
+	default:
+		return nil
+	}
+}
+
+// Allow reports whether the packet of n bytes conforms to the rate, and consumes the
+// tokens for it if so.
+func (p *Policer) Allow(n int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	p.tokens += now.Sub(p.last).Seconds() * p.rate
+	if p.tokens > p.burst {
+		p.tokens = p.burst
+	}
+	p.last = now
+
+	if float64(n) > p.tokens {
+		return false
+	}
+	p.tokens -= float64(n)
+	return true
+}
+
+type policerHolder struct {
+	*Policer
+}
+
+// SetRoutePolicer attaches the Policer to the route with teidIn added with AddRoute or
+// RelayTo, which polices the T-PDUs relayed on it. Giving nil detaches it.
+//
+// The T-PDUs dropped by Policer are counted as Dropped in Route and TrafficStats, and
+// the Policer is detached when the route is replaced or removed.
+func (u *UPlaneConn) SetRoutePolicer(teidIn uint32, p *Policer) error {
+	peer, ok := u.routeTable()[teidIn]
+	if !ok {
+		return ErrRouteNotFound
+	}
+	peer.policer.Store(policerHolder{p})
+	return nil
+}
+
+// conform reports whether the T-PDU of n bytes conforms to the Policer of the peer, if
+// any.
+func (p *peer) conform(n int) bool {
+	h, ok := p.policer.Load().(policerHolder)
+	if !ok || h.Policer == nil {
+		return true
+	}
+	return h.Allow(n)
+}
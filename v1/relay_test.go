@@ -5,10 +5,14 @@
 package v1_test
 
 import (
+	"bytes"
+	"encoding/binary"
 	"net"
 	"testing"
+	"time"
 
 	v1 "github.com/wmnsk/go-gtp/v1"
+	"github.com/wmnsk/go-gtp/v1/messages"
 )
 
 func TestRelay(t *testing.T) {
@@ -42,3 +46,241 @@ func TestRelay(t *testing.T) {
 
 	// TODO: add tests to check if the traffic goes through conns.
 }
+
+func TestRelayBatch(t *testing.T) {
+	errCh := make(chan error)
+	leftConn, err := v1.ListenAndServeUPlane(&net.UDPAddr{IP: net.IPv4(127, 0, 0, 21), Port: 2152}, 0, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer leftConn.Close()
+	rightConn, err := v1.ListenAndServeUPlane(&net.UDPAddr{IP: net.IPv4(127, 0, 0, 22), Port: 2152}, 0, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rightConn.Close()
+
+	sender, err := net.ListenPacket("udp", "127.0.0.23:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sender.Close()
+	receiver, err := net.ListenPacket("udp", "127.0.0.24:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer receiver.Close()
+
+	leftConn.SetBatchSize(8)
+	if err := leftConn.RelayTo(rightConn, 0x11111111, 0x22222222, receiver.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+
+	const count = 20
+	payload := []byte{0xde, 0xad, 0xbe, 0xef}
+	b, err := v1.Encapsulate(0x11111111, payload).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for n := 0; n < count; n++ {
+		if _, err := sender.WriteTo(b, leftConn.LocalAddr()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := receiver.SetReadDeadline(time.Now().Add(3 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1500)
+	for n := 0; n < count; n++ {
+		l, _, err := receiver.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("failed to receive %d/%d: %v", n, count, err)
+		}
+		msg, err := messages.Parse(buf[:l])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if msg.TEID() != 0x22222222 {
+			t.Errorf("wrong TEID: %#x", msg.TEID())
+		}
+		if pdu, ok := msg.(*messages.TPDU); !ok || !bytes.Equal(pdu.Payload, payload) {
+			t.Errorf("unexpected message: %v", msg)
+		}
+	}
+
+	if n := rightConn.Stats().MessagesSent[messages.MsgTypeTPDU]; n != count {
+		t.Errorf("wrong number of T-PDU sent. want: %d, got: %d", count, n)
+	}
+}
+
+func TestRoute(t *testing.T) {
+	errCh := make(chan error, 1)
+	conn, err := v1.ListenAndServeUPlane(&net.UDPAddr{IP: net.IPv4(127, 0, 0, 25), Port: 2152}, 0, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	sender, err := net.ListenPacket("udp", "127.0.0.26:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sender.Close()
+	receiver, err := net.ListenPacket("udp", "127.0.0.27:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer receiver.Close()
+
+	if err := conn.AddRoute(0x11111111, 0x22222222, receiver.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.AddRoute(0x33333333, 0x44444444, receiver.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+
+	const count = 5
+	b, err := v1.Encapsulate(0x11111111, []byte{0xde, 0xad, 0xbe, 0xef}).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for n := 0; n < count; n++ {
+		if _, err := sender.WriteTo(b, conn.LocalAddr()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := receiver.SetReadDeadline(time.Now().Add(3 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1500)
+	for n := 0; n < count; n++ {
+		l, _, err := receiver.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("failed to receive %d/%d: %v", n, count, err)
+		}
+		if teid := binary.BigEndian.Uint32(buf[4:8]); teid != 0x22222222 || l != len(b) {
+			t.Errorf("unexpected packet: %x", buf[:l])
+		}
+	}
+
+	r, err := conn.Route(0x11111111)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.OutgoingTEID != 0x22222222 || r.Packets != count || r.Bytes != uint64(count*len(b)) {
+		t.Errorf("unexpected route: %+v", r)
+	}
+	if routes := conn.Routes(); len(routes) != 2 || routes[0].IncomingTEID != 0x11111111 {
+		t.Errorf("unexpected routes: %v", routes)
+	}
+
+	if err := conn.RemoveRoute(0x11111111); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.RemoveRoute(0x11111111); err != v1.ErrRouteNotFound {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if n := conn.RemoveRoutesTo(receiver.LocalAddr()); n != 1 {
+		t.Errorf("wrong number of routes removed: %d", n)
+	}
+	if n := conn.Stats().Relays; n != 0 {
+		t.Errorf("routes should be empty: %d", n)
+	}
+}
+
+func TestPolicer(t *testing.T) {
+	// 8 kbps is 1000 bytes per second, which is too slow to refill in the test.
+	p := v1.NewPolicer(8, 1000)
+	if !p.Allow(600) {
+		t.Error("the first packet within burst should be allowed")
+	}
+	if p.Allow(600) {
+		t.Error("the packet exceeding the tokens should not be allowed")
+	}
+	if !p.Allow(400) {
+		t.Error("the packet within the tokens left should be allowed")
+	}
+
+	if p := v1.NewBearerPolicer(0, 0); p != nil {
+		t.Error("Policer should not be created without MBR and GBR")
+	}
+	// the default burst is at least 1500 bytes.
+	p = v1.NewBearerPolicer(0, 8)
+	if !p.Allow(1500) || p.Allow(1500) {
+		t.Error("unexpected burst of Policer with GBR")
+	}
+}
+
+func TestRoutePolicer(t *testing.T) {
+	errCh := make(chan error, 1)
+	conn, err := v1.ListenAndServeUPlane(&net.UDPAddr{IP: net.IPv4(127, 0, 0, 28), Port: 2152}, 0, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	sender, err := net.ListenPacket("udp", "127.0.0.29:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sender.Close()
+	receiver, err := net.ListenPacket("udp", "127.0.0.30:2152")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer receiver.Close()
+
+	if err := conn.SetRoutePolicer(0x11111111, v1.NewPolicer(8, 0)); err != v1.ErrRouteNotFound {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := conn.AddRoute(0x11111111, 0x22222222, receiver.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+
+	// only 2 of the T-PDUs of 1000 bytes fit in the burst.
+	b, err := v1.Encapsulate(0x11111111, make([]byte, 992)).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.SetRoutePolicer(0x11111111, v1.NewPolicer(8, 2*len(b))); err != nil {
+		t.Fatal(err)
+	}
+
+	const count = 5
+	for n := 0; n < count; n++ {
+		if _, err := sender.WriteTo(b, conn.LocalAddr()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := receiver.SetReadDeadline(time.Now().Add(3 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1500)
+	for n := 0; n < 2; n++ {
+		if _, _, err := receiver.ReadFrom(buf); err != nil {
+			t.Fatalf("failed to receive %d/2: %v", n, err)
+		}
+	}
+
+	// wait for the rest to be dropped.
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		r, err := conn.Route(0x11111111)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Packets == 2 && r.Dropped == count-2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("unexpected route: %+v", r)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := conn.Stats().Tunnels[0x11111111].Dropped; n != count-2 {
+		t.Errorf("wrong number of T-PDUs dropped: %d", n)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1
+
+import (
+	"errors"
+	"net"
+	"sort"
+	"sync/atomic"
+)
+
+// Route is the snapshot of a route of the T-PDUs relayed by UPlaneConn, which is
+// added with AddRoute or RelayTo.
+type Route struct {
+	// IncomingTEID is the TEID of the T-PDUs to be relayed.
+	IncomingTEID uint32
+	// OutgoingTEID is the TEID set in the T-PDUs relayed.
+	OutgoingTEID uint32
+	// PeerAddr is the address the T-PDUs are relayed to.
+	PeerAddr net.Addr
+	// Packets and Bytes are the number and size of the packets relayed.
+	Packets, Bytes uint64
+	// Dropped is the number of the T-PDUs dropped by the Policer of the route.
+	Dropped uint64
+}
+
+// routeTable is the TEIDs to be relayed and their peers. It is never modified once
+// stored in UPlaneConn, and replaced with the updated copy instead, so that the
+// lookup for each T-PDU can be done without locking.
+type routeTable map[uint32]*peer
+
+// AddRoute adds the route to relay the T-PDUs with teidIn to raddr from UPlaneConn
+// itself, with the TEID replaced with teidOut. The existing route with teidIn is
+// replaced.
+//
+// This is the same as RelayTo with the UPlaneConn itself, which is for the nodes that
+// use the same GTP-U endpoint on both sides, such as SGW-U.
+func (u *UPlaneConn) AddRoute(teidIn, teidOut uint32, raddr net.Addr) error {
+	return u.RelayTo(u, teidIn, teidOut, raddr)
+}
+
+// RemoveRoute removes the route with teidIn added with AddRoute or RelayTo.
+func (u *UPlaneConn) RemoveRoute(teidIn uint32) error {
+	if u.kernGTPEnabled {
+		return errors.New("cannot call RemoveRoute when using Kernel GTP-U")
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if _, ok := u.routeTable()[teidIn]; !ok {
+		return ErrRouteNotFound
+	}
+	u.updateRoutes(func(t routeTable) {
+		delete(t, teidIn)
+	})
+	return nil
+}
+
+// RemoveRoutesTo removes all the routes to raddr, e.g., when the path to the peer is
+// down. It returns the number of the routes removed.
+func (u *UPlaneConn) RemoveRoutesTo(raddr net.Addr) int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	n := 0
+	u.updateRoutes(func(t routeTable) {
+		for teid, p := range t {
+			if p.addr.String() == raddr.String() {
+				delete(t, teid)
+				n++
+			}
+		}
+	})
+	return n
+}
+
+// Route returns the snapshot of the route with teidIn.
+func (u *UPlaneConn) Route(teidIn uint32) (*Route, error) {
+	p, ok := u.routeTable()[teidIn]
+	if !ok {
+		return nil, ErrRouteNotFound
+	}
+	return p.route(teidIn), nil
+}
+
+// Routes returns the snapshots of all the routes, sorted by IncomingTEID.
+func (u *UPlaneConn) Routes() []*Route {
+	t := u.routeTable()
+	routes := make([]*Route, 0, len(t))
+	for teid, p := range t {
+		routes = append(routes, p.route(teid))
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		return routes[i].IncomingTEID < routes[j].IncomingTEID
+	})
+	return routes
+}
+
+// routeTable returns the current routeTable, which must not be modified.
+func (u *UPlaneConn) routeTable() routeTable {
+	t, _ := u.routes.Load().(routeTable)
+	return t
+}
+
+// updateRoutes applies fn to the copy of the current routeTable and stores it.
+// This must be called with u.mu locked.
+func (u *UPlaneConn) updateRoutes(fn func(t routeTable)) {
+	old := u.routeTable()
+	t := make(routeTable, len(old)+1)
+	for teid, p := range old {
+		t[teid] = p
+	}
+	fn(t)
+	u.routes.Store(t)
+}
+
+func (p *peer) relayed(n int) {
+	atomic.AddUint64(&p.packets, 1)
+	atomic.AddUint64(&p.bytes, uint64(n))
+}
+
+func (p *peer) policed() {
+	atomic.AddUint64(&p.dropped, 1)
+}
+
+func (p *peer) route(teidIn uint32) *Route {
+	return &Route{
+		IncomingTEID: teidIn,
+		OutgoingTEID: p.teid,
+		PeerAddr:     p.addr,
+		Packets:      atomic.LoadUint64(&p.packets),
+		Bytes:        atomic.LoadUint64(&p.bytes),
+		Dropped:      atomic.LoadUint64(&p.dropped),
+	}
+}
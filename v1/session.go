@@ -0,0 +1,97 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1
+
+import (
+	"net"
+	"sync"
+
+	"github.com/wmnsk/go-gtp/v1/ies"
+)
+
+// Session is a PDP context of GTPv1-C established over CPlaneConn.
+type Session struct {
+	mu       sync.Mutex
+	isActive bool
+
+	// peerAddr is a net.Addr of the peer associated with Session.
+	peerAddr net.Addr
+
+	// IMSI, MSISDN and IMEI are the identities of the subscriber.
+	IMSI, MSISDN, IMEI string
+	// APN is the Access Point Name of the PDP context.
+	APN string
+	// NSAPI is the NSAPI that identifies the PDP context among the ones of the IMSI.
+	NSAPI uint8
+
+	// LocalTEIDC and LocalTEIDU are the TEIDs of the local node for Control Plane and
+	// Data I, which are set in the messages from the peer.
+	LocalTEIDC, LocalTEIDU uint32
+	// RemoteTEIDC and RemoteTEIDU are the TEIDs of the peer for Control Plane and
+	// Data I, which are set in the messages to the peer.
+	RemoteTEIDC, RemoteTEIDU uint32
+
+	// PeerUPlaneAddr is the GSN Address for user traffic of the peer.
+	PeerUPlaneAddr string
+	// MSAddress is the End User Address allocated to the MS.
+	MSAddress string
+}
+
+// NewSession creates a new Session with the IMSI of the subscriber.
+//
+// This is expected to be used by server-like nodes. Otherwise, use CreatePDPContext,
+// which sends Create PDP Context Request and returns a new Session.
+func NewSession(peerAddr net.Addr, imsi string) *Session {
+	return &Session{
+		mu:       sync.Mutex{},
+		peerAddr: peerAddr,
+		IMSI:     imsi,
+	}
+}
+
+// Activate marks a Session active.
+func (s *Session) Activate() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.IMSI == "" {
+		return &RequiredIEMissingError{Type: ies.IMSI}
+	}
+
+	s.isActive = true
+	return nil
+}
+
+// Deactivate marks a Session inactive.
+func (s *Session) Deactivate() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.isActive = false
+	return nil
+}
+
+// IsActive reports whether a Session is active or not.
+func (s *Session) IsActive() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.isActive
+}
+
+// PeerAddr returns the address of the peer node associated with Session.
+func (s *Session) PeerAddr() net.Addr {
+	return s.peerAddr
+}
+
+// UpdatePeerAddr updates the address of the peer node associated with Session.
+func (s *Session) UpdatePeerAddr(peer net.Addr) {
+	s.peerAddr = peer
+}
+
+// hasTEID reports whether teid is one of the local TEIDs of Session.
+func (s *Session) hasTEID(teid uint32) bool {
+	return teid == s.LocalTEIDC || teid == s.LocalTEIDU
+}
@@ -0,0 +1,49 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v1
+
+import (
+	"net"
+
+	"github.com/wmnsk/go-gtp/internal/sockopt"
+)
+
+// SocketOptions is the options of the socket created by DialUPlaneWithSocketOptions and ListenAndServeUPlaneWithSocketOptions,
+// which cannot be set on the net.PacketConn after it is created by the UPlaneConn.
+// The zero value of each field leaves the default of the system unchanged.
+//
+// DSCP, ReusePort and DontFragment are supported only on Linux, and ErrSocketOptionUnsupported is
+// returned on the other platforms.
+type SocketOptions struct {
+	// DSCP is the Differentiated Services Code Point set in the packets sent, which
+	// should be in the range of 0 to 63. It is set in Type of Service with IPv4, and
+	// Traffic Class with IPv6.
+	DSCP uint8
+	// ReusePort sets SO_REUSEPORT on the socket, which lets multiple sockets bind
+	// the same address, e.g., to distribute the load to the processes.
+	ReusePort bool
+	// DontFragment sets the DF bit in IPv4 and disables the fragmentation by the
+	// kernel in IPv6, so that the GTP-U packets larger than the path MTU are not
+	// fragmented. Once the ICMP "fragmentation needed" or ICMPv6 "packet too big"
+	// is received on the path, WriteToGTP fails with PacketTooBigError for such
+	// packets, and the path MTU discovered can be retrieved with PathMTU.
+	DontFragment bool
+	// ReadBuffer and WriteBuffer are the sizes of the receive and send buffers of
+	// the socket in bytes.
+	ReadBuffer, WriteBuffer int
+}
+
+func (so *SocketOptions) listenPacket(network, address string) (net.PacketConn, error) {
+	if so == nil {
+		return sockopt.ListenPacket(network, address, nil)
+	}
+	return sockopt.ListenPacket(network, address, &sockopt.Options{
+		DSCP:         so.DSCP,
+		ReusePort:    so.ReusePort,
+		DontFragment: so.DontFragment,
+		ReadBuffer:   so.ReadBuffer,
+		WriteBuffer:  so.WriteBuffer,
+	})
+}
@@ -7,12 +7,20 @@ package v1
 import (
 	"errors"
 	"net"
+	"sync/atomic"
 )
 
 type peer struct {
+	// packets, bytes and dropped are placed first to be 64-bit aligned for atomic
+	// operations.
+	packets, bytes, dropped uint64
+
 	teid    uint32
 	addr    net.Addr
 	srcConn *UPlaneConn
+
+	// policer is the Policer set with SetRoutePolicer.
+	policer atomic.Value
 }
 
 // RelayTo relays T-PDU type of packet to peer node(specified by raddr) from the UPlaneConn given.
@@ -25,10 +33,9 @@ func (u *UPlaneConn) RelayTo(c *UPlaneConn, teidIn, teidOut uint32, raddr net.Ad
 
 	u.mu.Lock()
 	defer u.mu.Unlock()
-	if u.relayMap == nil {
-		u.relayMap = map[uint32]*peer{}
-	}
-	u.relayMap[teidIn] = &peer{teid: teidOut, addr: raddr, srcConn: c}
+	u.updateRoutes(func(t routeTable) {
+		t[teidIn] = &peer{teid: teidOut, addr: raddr, srcConn: c}
+	})
 	return nil
 }
 
@@ -39,7 +46,9 @@ func (u *UPlaneConn) CloseRelay(teidIn uint32) error {
 	}
 
 	u.mu.Lock()
-	delete(u.relayMap, teidIn)
+	u.updateRoutes(func(t routeTable) {
+		delete(t, teidIn)
+	})
 	u.mu.Unlock()
 	return nil
 }
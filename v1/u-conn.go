@@ -7,12 +7,13 @@ package v1
 import (
 	"encoding/binary"
 	"net"
-	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/vishvananda/netlink"
+	"github.com/wmnsk/go-gtp/internal/batchio"
 	"github.com/wmnsk/go-gtp/v1/ies"
 	"github.com/wmnsk/go-gtp/v1/messages"
 )
@@ -30,6 +31,7 @@ type tpduSet struct {
 	raddr   net.Addr
 	teid    uint32
 	seq     uint16
+	exts    []*messages.ExtensionHeader
 	payload []byte
 }
 
@@ -39,11 +41,32 @@ type UPlaneConn struct {
 	pktConn net.PacketConn
 	*msgHandlerMap
 
-	tpduCh  chan *tpduSet
-	closeCh chan struct{}
-	errCh   chan error
+	tpduCh     chan *tpduSet
+	closeCh    chan struct{}
+	errCh      chan error
+	errHandler ErrorHandler
 
-	relayMap map[uint32]*peer
+	// stats is the statistics of UPlaneConn, which is nil in zero-value UPlaneConn.
+	stats *connStats
+
+	// routes is the routeTable of the T-PDUs to be relayed.
+	routes atomic.Value
+
+	// autoErrInd enables sending Error Indication for the T-PDUs with unknown TEIDs,
+	// which are determined with teidValidator, and errIndHandler is called with the
+	// Error Indications received.
+	autoErrInd    bool
+	teidValidator func(teid uint32) bool
+	errIndHandler ErrorIndicationHandler
+
+	// paths is the paths supervised with Echo, keyed by the address of the peer.
+	paths              map[string]*path
+	pathFailureHandler PathFailureHandler
+
+	// batchConn is used to read the datagrams in batches of batchSize if it is
+	// larger than 1, and to write the T-PDUs relayed from the other UPlaneConns.
+	batchConn *batchio.Conn
+	batchSize int
 
 	// for Linux kernel GTP with netlink
 	kernGTPEnabled bool
@@ -57,6 +80,12 @@ type UPlaneConn struct {
 // DialUPlane sends Echo Request to raddr to check if the endpoint is alive and
 // keep connection information.
 func DialUPlane(laddr, raddr net.Addr, counter uint8, errCh chan error) (*UPlaneConn, error) {
+	return DialUPlaneWithSocketOptions(laddr, raddr, counter, errCh, nil)
+}
+
+// DialUPlaneWithSocketOptions works the same as DialUPlane, with the SocketOptions
+// set on the socket created. Giving nil as so is the same as DialUPlane.
+func DialUPlaneWithSocketOptions(laddr, raddr net.Addr, counter uint8, errCh chan error, so *SocketOptions) (*UPlaneConn, error) {
 	u := &UPlaneConn{
 		mu:            sync.Mutex{},
 		msgHandlerMap: defaultHandlerMap,
@@ -64,13 +93,14 @@ func DialUPlane(laddr, raddr net.Addr, counter uint8, errCh chan error) (*UPlane
 		tpduCh:  make(chan *tpduSet),
 		closeCh: make(chan struct{}),
 		errCh:   errCh,
+		stats:   newConnStats(),
 
 		RestartCounter: counter,
 	}
 
 	// setup UDPConn first.
 	var err error
-	u.pktConn, err = net.ListenPacket(laddr.Network(), laddr.String())
+	u.pktConn, err = so.listenPacket(laddr.Network(), laddr.String())
 	if err != nil {
 		return nil, err
 	}
@@ -87,7 +117,7 @@ func DialUPlane(laddr, raddr net.Addr, counter uint8, errCh chan error) (*UPlane
 			return nil, err
 		}
 
-		n, _, err := u.pktConn.ReadFrom(buf)
+		n, from, err := u.pktConn.ReadFrom(buf)
 		if err != nil {
 			return nil, err
 		}
@@ -103,6 +133,7 @@ func DialUPlane(laddr, raddr net.Addr, counter uint8, errCh chan error) (*UPlane
 		if _, ok := msg.(*messages.EchoResponse); !ok {
 			continue
 		}
+		u.stats.messageReceived(from, msg.MessageType())
 
 		break
 	}
@@ -113,6 +144,13 @@ func DialUPlane(laddr, raddr net.Addr, counter uint8, errCh chan error) (*UPlane
 
 // ListenAndServeUPlane creates a new GTPv2-C *Conn and start serving.
 func ListenAndServeUPlane(laddr net.Addr, counter uint8, errCh chan error) (*UPlaneConn, error) {
+	return ListenAndServeUPlaneWithSocketOptions(laddr, counter, errCh, nil)
+}
+
+// ListenAndServeUPlaneWithSocketOptions works the same as ListenAndServeUPlane, with
+// the SocketOptions set on the socket created. Giving nil as so is the same as
+// ListenAndServeUPlane.
+func ListenAndServeUPlaneWithSocketOptions(laddr net.Addr, counter uint8, errCh chan error, so *SocketOptions) (*UPlaneConn, error) {
 	u := &UPlaneConn{
 		mu:            sync.Mutex{},
 		msgHandlerMap: defaultHandlerMap,
@@ -120,12 +158,13 @@ func ListenAndServeUPlane(laddr net.Addr, counter uint8, errCh chan error) (*UPl
 		tpduCh:  make(chan *tpduSet),
 		closeCh: make(chan struct{}),
 		errCh:   errCh,
+		stats:   newConnStats(),
 
 		RestartCounter: counter,
 	}
 
 	var err error
-	u.pktConn, err = net.ListenPacket(laddr.Network(), laddr.String())
+	u.pktConn, err = so.listenPacket(laddr.Network(), laddr.String())
 	if err != nil {
 		return nil, err
 	}
@@ -144,6 +183,7 @@ func DialUPlaneKernel(devname string, role Role, laddr, raddr net.Addr, counter
 		tpduCh:  make(chan *tpduSet),
 		closeCh: make(chan struct{}),
 		errCh:   errCh,
+		stats:   newConnStats(),
 
 		RestartCounter: counter,
 	}
@@ -167,7 +207,7 @@ func DialUPlaneKernel(devname string, role Role, laddr, raddr net.Addr, counter
 			return nil, err
 		}
 
-		n, _, err := u.pktConn.ReadFrom(buf)
+		n, from, err := u.pktConn.ReadFrom(buf)
 		if err != nil {
 			return nil, err
 		}
@@ -183,6 +223,7 @@ func DialUPlaneKernel(devname string, role Role, laddr, raddr net.Addr, counter
 		if _, ok := msg.(*messages.EchoResponse); !ok {
 			continue
 		}
+		u.stats.messageReceived(from, msg.MessageType())
 
 		break
 	}
@@ -220,6 +261,7 @@ func ListenAndServeUPlaneKernel(devname string, role Role, laddr net.Addr, count
 		tpduCh:  make(chan *tpduSet),
 		closeCh: make(chan struct{}),
 		errCh:   errCh,
+		stats:   newConnStats(),
 
 		RestartCounter: counter,
 	}
@@ -255,6 +297,7 @@ func ListenAndServeUPlaneKernel(devname string, role Role, laddr net.Addr, count
 
 func (u *UPlaneConn) serve() {
 	buf := make([]byte, 1500)
+	var batch []batchio.Message
 	for {
 		select {
 		case <-u.closed():
@@ -263,6 +306,16 @@ func (u *UPlaneConn) serve() {
 			// do nothing and go forward.
 		}
 
+		if bc, size := u.batch(); bc != nil {
+			if len(batch) != size {
+				batch = batchio.NewMessages(size, 1500)
+			}
+			if err := u.serveBatch(bc, batch); err != nil {
+				return
+			}
+			continue
+		}
+
 		n, raddr, err := u.pktConn.ReadFrom(buf)
 		if err != nil {
 			return
@@ -270,41 +323,102 @@ func (u *UPlaneConn) serve() {
 
 		// just forward T-PDU instead of passing it to reader if relayer is
 		// configured and the message type is T-PDU.
-		if len(u.relayMap) != 0 && buf[1] == messages.MsgTypeTPDU {
-			// ignore if the packet size is smaller than minimum header size
-			if n < 11 {
+		if peer, ok := u.relayPeer(raddr, buf[:n]); ok {
+			if peer == nil {
 				continue
 			}
-
-			u.mu.Lock()
-			peer, ok := u.relayMap[binary.BigEndian.Uint32(buf[4:8])]
-			u.mu.Unlock()
-			if !ok {
+			if _, err := peer.srcConn.WriteTo(buf[:n], peer.addr); err != nil {
+				go u.notifyError(packetTooBig(err, peer.addr, n))
 				continue
 			}
-
-			// just use original packet not to get it slow.
-			binary.BigEndian.PutUint32(buf[4:8], peer.teid)
-			if _, err := peer.srcConn.WriteTo(buf, peer.addr); err != nil {
-				go func() {
-					u.errCh <- err
-				}()
+			peer.srcConn.stats.messageSent(peer.addr, buf[1])
+			if buf[1] == messages.MsgTypeTPDU {
+				peer.srcConn.stats.tpduSent(peer.addr, peer.teid, n)
 			}
 			continue
 		}
 
-		msg, err := messages.Parse(buf[:n])
-		if err != nil {
-			continue
+		u.handleRaw(raddr, buf[:n])
+	}
+}
+
+// relayPeer returns the peer to relay b to, with the TEID in b replaced with the one
+// of the peer. ok is false if b is not the T-PDU or End Marker to be relayed, and the
+// peer is nil if b is to be discarded.
+//
+// End Markers are relayed as well as T-PDUs, so that the node next to the relay can
+// know the end of the old path on handover.
+func (u *UPlaneConn) relayPeer(raddr net.Addr, b []byte) (p *peer, ok bool) {
+	routes := u.routeTable()
+	if len(routes) == 0 || len(b) < 2 {
+		return nil, false
+	}
+	switch b[1] {
+	case messages.MsgTypeTPDU:
+		// ignore if the packet size is smaller than minimum header size
+		if len(b) < 11 {
+			return nil, true
 		}
+	case messages.MsgTypeEndMarker:
+		if len(b) < 8 {
+			return nil, true
+		}
+	default:
+		return nil, false
+	}
+
+	teid := binary.BigEndian.Uint32(b[4:8])
+	p, found := routes[teid]
+	if !found {
+		u.mu.Lock()
+		autoErrInd := u.autoErrInd
+		u.mu.Unlock()
+		if b[1] == messages.MsgTypeTPDU {
+			u.stats.tpduReceived(raddr, teid, len(b))
+			u.stats.tpduDropped(raddr, teid)
+		}
+		if autoErrInd && b[1] == messages.MsgTypeTPDU {
+			u.stats.messageReceived(raddr, messages.MsgTypeTPDU)
+			var seq uint16
+			if b[0]&0x02 != 0 {
+				seq = binary.BigEndian.Uint16(b[8:10])
+			}
+			if err := u.sendErrorIndication(raddr, teid, seq); err != nil {
+				go u.notifyError(err)
+			}
+		}
+		return nil, true
+	}
+	u.stats.messageReceived(raddr, b[1])
+	if b[1] == messages.MsgTypeTPDU {
+		u.stats.tpduReceived(raddr, teid, len(b))
+		if !p.conform(len(b)) {
+			p.policed()
+			u.stats.tpduDropped(raddr, teid)
+			return nil, true
+		}
+	}
+	p.relayed(len(b))
 
-		if err := u.handleMessage(raddr, msg); err != nil {
-			// errors should be handled by user
-			go func() {
-				u.errCh <- err
-			}()
-			continue
+	// just use original packet not to get it slow.
+	binary.BigEndian.PutUint32(b[4:8], p.teid)
+	return p, true
+}
+
+// handleRaw parses b and lets the message be handled.
+func (u *UPlaneConn) handleRaw(raddr net.Addr, b []byte) {
+	msg, err := messages.Parse(b)
+	if err != nil {
+		u.stats.parseError()
+		if fn := u.errorHandler(); fn != nil {
+			fn(errors.Wrapf(err, "failed to parse the message from %s", raddr))
 		}
+		return
+	}
+
+	if err := u.handleMessage(raddr, msg); err != nil {
+		// errors should be handled by user
+		go u.notifyError(err)
 	}
 }
 
@@ -330,6 +444,16 @@ func (u *UPlaneConn) ReadFrom(p []byte) (n int, addr net.Addr, err error) {
 //
 // Note that valid GTP-U packets handled by Kernel can NOT be retrieved by this.
 func (u *UPlaneConn) ReadFromGTP(p []byte) (n int, addr net.Addr, teid uint32, err error) {
+	n, addr, teid, _, err = u.ReadFromGTPWithExtensionHeaders(p)
+	return
+}
+
+// ReadFromGTPWithExtensionHeaders reads a packet from the connection as ReadFromGTP
+// does, and returns the Extension Headers in the GTP header together, e.g., the PDU
+// Session Container to get the QFI on N3/N9.
+//
+// Note that valid GTP-U packets handled by Kernel can NOT be retrieved by this.
+func (u *UPlaneConn) ReadFromGTPWithExtensionHeaders(p []byte) (n int, addr net.Addr, teid uint32, exts []*messages.ExtensionHeader, err error) {
 	select {
 	case <-u.closed():
 		return
@@ -341,6 +465,7 @@ func (u *UPlaneConn) ReadFromGTP(p []byte) (n int, addr net.Addr, teid uint32, e
 		n = copy(p, tpdu.payload)
 		addr = tpdu.raddr
 		teid = tpdu.teid
+		exts = tpdu.exts
 		return
 	}
 }
@@ -356,14 +481,26 @@ func (u *UPlaneConn) WriteTo(p []byte, addr net.Addr) (n int, err error) {
 
 // WriteToGTP writes a packet with TEID and payload to addr.
 func (u *UPlaneConn) WriteToGTP(teid uint32, p []byte, addr net.Addr) (n int, err error) {
-	b, err := Encapsulate(teid, p).Marshal()
+	return u.WriteToGTPWithExtensionHeaders(teid, p, addr)
+}
+
+// WriteToGTPWithExtensionHeaders writes a packet with TEID, Extension Headers and
+// payload to addr. Without exts, it is the same as WriteToGTP.
+//
+// It returns PacketTooBigError if the packet is larger than the path MTU toward addr
+// discovered with DontFragment in SocketOptions.
+func (u *UPlaneConn) WriteToGTPWithExtensionHeaders(teid uint32, p []byte, addr net.Addr, exts ...*messages.ExtensionHeader) (n int, err error) {
+	b, err := Encapsulate(teid, p, exts...).Marshal()
 	if err != nil {
 		return
 	}
 
 	if _, err = u.pktConn.WriteTo(b, addr); err != nil {
+		err = packetTooBig(err, addr, len(b))
 		return
 	}
+	u.stats.messageSent(addr, messages.MsgTypeTPDU)
+	u.stats.tpduSent(addr, teid, len(b))
 	return len(b), nil
 }
 
@@ -380,7 +517,7 @@ func (u *UPlaneConn) Close() error {
 	defer u.mu.Unlock()
 	u.msgHandlerMap = defaultHandlerMap
 	close(u.closeCh)
-	u.relayMap = nil
+	u.routes.Store(routeTable(nil))
 
 	if u.kernGTPEnabled {
 		_ = netlink.LinkDel(u.GTPLink)
@@ -441,9 +578,11 @@ func (u *UPlaneConn) SetWriteDeadline(t time.Time) error {
 // any values, which is in most cases vital to continue working as a node, from the incoming
 // messages.
 //
-// HandlerFuncs for EchoResponse and ErrorIndication are registered by default.
-// These HandlerFuncs can be overwritten by specifying messages.MsgTypeEchoResponse and/or
-// messages.MsgTypeErrorIndication as msgType parameter.
+// HandlerFuncs for EchoResponse, ErrorIndication and EndMarker are registered by default.
+// These HandlerFuncs can be overwritten by specifying messages.MsgTypeEchoResponse,
+// messages.MsgTypeErrorIndication and/or messages.MsgTypeEndMarker as msgType parameter.
+// The default one for EndMarker just discards it, so add the one to know when the
+// last T-PDU has arrived on the old path, e.g., to flush the buffered T-PDUs on handover.
 func (u *UPlaneConn) AddHandler(msgType uint8, fn HandlerFunc) {
 	u.msgHandlerMap.store(msgType, fn)
 }
@@ -458,19 +597,57 @@ func (u *UPlaneConn) AddHandlers(funcs map[uint8]HandlerFunc) {
 }
 
 func (u *UPlaneConn) handleMessage(senderAddr net.Addr, msg messages.Message) error {
+	u.stats.messageReceived(senderAddr, msg.MessageType())
+	if msg.MessageType() == messages.MsgTypeEchoResponse {
+		u.echoResponded(senderAddr)
+	}
+
 	handle, ok := u.msgHandlerMap.load(msg.MessageType())
 	if !ok {
 		return ErrNoHandlersFound
 	}
 	go func() {
 		if err := handle(u, senderAddr, msg); err != nil {
-			u.errCh <- err
+			u.notifyError(err)
 		}
 	}()
 
 	return nil
 }
 
+// SetErrorHandler registers the ErrorHandler to be called with the errors that occur
+// in the background process of UPlaneConn, including the ones returned from
+// HandlerFuncs and the failures in parsing the incoming messages.
+//
+// Once the ErrorHandler is set, the errors are no longer sent to the errCh given
+// when creating UPlaneConn, and the caller does not need to keep monitoring it.
+// Giving nil restores the errCh-based behavior.
+func (u *UPlaneConn) SetErrorHandler(fn ErrorHandler) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.errHandler = fn
+}
+
+func (u *UPlaneConn) errorHandler() ErrorHandler {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.errHandler
+}
+
+// notifyError passes err to the ErrorHandler if registered, or to errCh otherwise.
+// If neither is available, err is just discarded not to block the caller forever.
+func (u *UPlaneConn) notifyError(err error) {
+	if fn := u.errorHandler(); fn != nil {
+		fn(err)
+		return
+	}
+
+	if u.errCh == nil {
+		return
+	}
+	u.errCh <- err
+}
+
 // EchoRequest sends a EchoRequest.
 func (u *UPlaneConn) EchoRequest(raddr net.Addr) error {
 	b, err := messages.NewEchoRequest(0, ies.NewRecovery(u.RestartCounter)).Marshal()
@@ -481,6 +658,7 @@ func (u *UPlaneConn) EchoRequest(raddr net.Addr) error {
 	if _, err := u.pktConn.WriteTo(b, raddr); err != nil {
 		return err
 	}
+	u.stats.messageSent(raddr, messages.MsgTypeEchoRequest)
 	return nil
 }
 
@@ -494,24 +672,22 @@ func (u *UPlaneConn) EchoResponse(raddr net.Addr) error {
 	if _, err := u.pktConn.WriteTo(b, raddr); err != nil {
 		return err
 	}
+	u.stats.messageSent(raddr, messages.MsgTypeEchoResponse)
 	return nil
 }
 
-// ErrorIndication just sends ErrorIndication message.
-func (u *UPlaneConn) ErrorIndication(raddr net.Addr, received messages.Message) error {
-	addr := strings.Split(raddr.String(), ":")[0]
-	errInd, err := messages.NewErrorIndication(
-		0, received.Sequence(),
-		ies.NewTEIDDataI(received.TEID()),
-		ies.NewGSNAddress(addr),
-	).Marshal()
+// SendEndMarker sends an End Marker with the TEID given to raddr, which should be sent
+// after the last T-PDU on the tunnel when the path is switched.
+func (u *UPlaneConn) SendEndMarker(teid uint32, raddr net.Addr) error {
+	b, err := messages.NewEndMarker(teid).Marshal()
 	if err != nil {
 		return err
 	}
 
-	if _, err := u.WriteTo(errInd, raddr); err != nil {
+	if _, err := u.pktConn.WriteTo(b, raddr); err != nil {
 		return err
 	}
+	u.stats.messageSent(raddr, messages.MsgTypeEndMarker)
 	return nil
 }
 
@@ -529,6 +705,7 @@ func (u *UPlaneConn) RespondTo(raddr net.Addr, received, toBeSent messages.Messa
 	if _, err := u.WriteTo(b, raddr); err != nil {
 		return err
 	}
+	u.stats.messageSent(raddr, toBeSent.MessageType())
 	return nil
 }
 
@@ -7,12 +7,15 @@ package v1_test
 import (
 	"errors"
 	"net"
+	"runtime"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 
 	v1 "github.com/wmnsk/go-gtp/v1"
+	"github.com/wmnsk/go-gtp/v1/messages"
 )
 
 type testVal struct {
@@ -103,6 +106,38 @@ func TestClientWrite(t *testing.T) {
 
 	select {
 	case <-okCh:
+		cliStats := cliConn.Stats()
+		if n := cliStats.MessagesSent[messages.MsgTypeTPDU]; n != 1 {
+			t.Errorf("wrong number of T-PDU sent. want: 1, got: %d", n)
+		}
+		if _, ok := cliStats.EchoRTT[srvConn.LocalAddr().String()]; !ok {
+			t.Error("EchoRTT should be measured with Echo in DialUPlane")
+		}
+		if n := srvConn.Stats().MessagesReceived[messages.MsgTypeTPDU]; n != 1 {
+			t.Errorf("wrong number of T-PDU received. want: 1, got: %d", n)
+		}
+
+		// 8 bytes of GTP header and the payload.
+		want := v1.TrafficStats{PacketsSent: 1, BytesSent: 12}
+		if diff := cmp.Diff(cliStats.Tunnels[tv.teidOut], want); diff != "" {
+			t.Error(diff)
+		}
+		if diff := cmp.Diff(cliStats.Peers[srvConn.LocalAddr().String()], want); diff != "" {
+			t.Error(diff)
+		}
+		want = v1.TrafficStats{PacketsReceived: 1, BytesReceived: 12}
+		if diff := cmp.Diff(srvConn.Stats().Tunnels[tv.teidOut], want); diff != "" {
+			t.Error(diff)
+		}
+
+		cliConn.ResetStats()
+		cliStats = cliConn.Stats()
+		if len(cliStats.MessagesSent) != 0 || len(cliStats.Tunnels) != 0 || len(cliStats.Peers) != 0 {
+			t.Errorf("Stats not reset: %+v", cliStats)
+		}
+		if _, ok := cliStats.EchoRTT[srvConn.LocalAddr().String()]; !ok {
+			t.Error("EchoRTT should be kept after ResetStats")
+		}
 		return
 	case err := <-errCh:
 		t.Fatal(err)
@@ -110,3 +145,356 @@ func TestClientWrite(t *testing.T) {
 		t.Fatal("timed out while waiting for response to come")
 	}
 }
+
+func TestEndMarker(t *testing.T) {
+	cliAddr := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 31), Port: 2152}
+	srvAddr := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 32), Port: 2152}
+	errCh := make(chan error, 1)
+
+	srvConn, err := v1.ListenAndServeUPlane(srvAddr, 0, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+
+	teidCh := make(chan uint32, 1)
+	srvConn.AddHandler(messages.MsgTypeEndMarker, func(c v1.Conn, senderAddr net.Addr, msg messages.Message) error {
+		if _, ok := msg.(*messages.EndMarker); !ok {
+			return errors.New("unexpected type of message")
+		}
+		teidCh <- msg.TEID()
+		return nil
+	})
+
+	cliConn, err := v1.DialUPlane(cliAddr, srvAddr, 0, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cliConn.Close()
+
+	if err := cliConn.SendEndMarker(0x11111111, srvAddr); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case teid := <-teidCh:
+		if teid != 0x11111111 {
+			t.Errorf("wrong TEID: %#x", teid)
+		}
+		if n := cliConn.Stats().MessagesSent[messages.MsgTypeEndMarker]; n != 1 {
+			t.Errorf("wrong number of End Marker sent. want: 1, got: %d", n)
+		}
+	case err := <-errCh:
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out while waiting for End Marker to come")
+	}
+}
+
+func TestErrorIndication(t *testing.T) {
+	cliAddr := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 41), Port: 2152}
+	srvAddr := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 42), Port: 2152}
+	errCh := make(chan error, 1)
+
+	srvConn, err := v1.ListenAndServeUPlane(srvAddr, 0, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+	srvConn.SetAutoErrorIndication(true)
+	srvConn.SetTEIDValidator(func(teid uint32) bool {
+		return teid == 0x11111111
+	})
+
+	cliConn, err := v1.DialUPlane(cliAddr, srvAddr, 0, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cliConn.Close()
+
+	type indicated struct {
+		teid uint32
+		peer string
+	}
+	indCh := make(chan indicated, 1)
+	cliConn.SetErrorIndicationHandler(func(senderAddr net.Addr, teid uint32, peer string) {
+		indCh <- indicated{teid, peer}
+	})
+
+	if _, err := cliConn.WriteToGTP(0x22222222, []byte{0xde, 0xad, 0xbe, 0xef}, srvAddr); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case got := <-indCh:
+		if got.teid != 0x22222222 {
+			t.Errorf("wrong TEID: %#x", got.teid)
+		}
+		if got.peer != "127.0.0.42" {
+			t.Errorf("wrong peer address: %s", got.peer)
+		}
+		if n := srvConn.Stats().MessagesSent[messages.MsgTypeErrorIndication]; n != 1 {
+			t.Errorf("wrong number of Error Indication sent. want: 1, got: %d", n)
+		}
+		want := v1.TrafficStats{PacketsReceived: 1, BytesReceived: 12, Dropped: 1, ErrorIndicationsSent: 1}
+		if diff := cmp.Diff(srvConn.Stats().Tunnels[0x22222222], want); diff != "" {
+			t.Error(diff)
+		}
+		if n := cliConn.Stats().Tunnels[0x22222222].ErrorIndicationsReceived; n != 1 {
+			t.Errorf("wrong number of Error Indication received. want: 1, got: %d", n)
+		}
+	case err := <-errCh:
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out while waiting for Error Indication to come")
+	}
+}
+
+func TestPathSupervision(t *testing.T) {
+	cliAddr := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 51), Port: 2152}
+	srvAddr := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 52), Port: 2152}
+	deadAddr := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 53), Port: 2152}
+	errCh := make(chan error, 1)
+
+	srvConn, err := v1.ListenAndServeUPlane(srvAddr, 0, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+
+	cliConn, err := v1.DialUPlane(cliAddr, srvAddr, 0, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cliConn.Close()
+
+	failedCh := make(chan net.Addr, 1)
+	cliConn.SetPathFailureHandler(func(raddr net.Addr) {
+		failedCh <- raddr
+	})
+
+	ps := &v1.PathSupervision{
+		Interval:   50 * time.Millisecond,
+		Timeout:    50 * time.Millisecond,
+		MaxRetries: 1,
+	}
+	cliConn.SupervisePath(srvAddr, ps)
+	cliConn.SupervisePath(deadAddr, ps)
+
+	select {
+	case raddr := <-failedCh:
+		if raddr.String() != deadAddr.String() {
+			t.Errorf("wrong peer failed: %s", raddr)
+		}
+	case err := <-errCh:
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out while waiting for path failure")
+	}
+
+	if got := cliConn.PathState(srvAddr); got != v1.PathStateUp {
+		t.Errorf("wrong state of the path to %s: %s", srvAddr, got)
+	}
+	if got := cliConn.PathState(deadAddr); got != v1.PathStateDown {
+		t.Errorf("wrong state of the path to %s: %s", deadAddr, got)
+	}
+
+	cliConn.StopPathSupervision(deadAddr)
+	if got := cliConn.PathState(deadAddr); got != v1.PathStateUnknown {
+		t.Errorf("path to %s should not be supervised: %s", deadAddr, got)
+	}
+}
+
+// echoCounter is the peer that counts the Echo Requests received without responding.
+type echoCounter struct {
+	conn *net.UDPConn
+	mu   sync.Mutex
+	n    int
+}
+
+func newEchoCounter(t *testing.T, addr *net.UDPAddr) *echoCounter {
+	t.Helper()
+	conn, err := net.ListenUDP("udp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := &echoCounter{conn: conn}
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			n, _, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if n > 1 && buf[1] == messages.MsgTypeEchoRequest {
+				e.mu.Lock()
+				e.n++
+				e.mu.Unlock()
+			}
+		}
+	}()
+	return e
+}
+
+func (e *echoCounter) count() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.n
+}
+
+func TestPathSupervisionRetries(t *testing.T) {
+	cliAddr := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 54), Port: 2152}
+	peer := newEchoCounter(t, &net.UDPAddr{IP: net.IPv4(127, 0, 0, 55), Port: 2152})
+	defer peer.conn.Close()
+
+	cliConn, err := v1.ListenAndServeUPlane(cliAddr, 0, make(chan error, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cliConn.Close()
+
+	// the count of Echo Requests is taken when the path goes down.
+	failedCh := make(chan int, 1)
+	cliConn.SetPathFailureHandler(func(raddr net.Addr) {
+		failedCh <- peer.count()
+	})
+
+	const maxRetries = 2
+	cliConn.SupervisePath(peer.conn.LocalAddr(), &v1.PathSupervision{
+		Interval:   time.Hour,
+		Timeout:    50 * time.Millisecond,
+		MaxRetries: maxRetries,
+	})
+	defer cliConn.StopPathSupervision(peer.conn.LocalAddr())
+
+	select {
+	case n := <-failedCh:
+		if n != maxRetries+1 {
+			t.Errorf("path failed after %d Echo Requests, want %d", n, maxRetries+1)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out while waiting for path failure")
+	}
+	if got := cliConn.PathState(peer.conn.LocalAddr()); got != v1.PathStateDown {
+		t.Errorf("wrong state of the path: %s", got)
+	}
+}
+
+func TestStopPathSupervision(t *testing.T) {
+	cliAddr := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 56), Port: 2152}
+	peer := newEchoCounter(t, &net.UDPAddr{IP: net.IPv4(127, 0, 0, 57), Port: 2152})
+	defer peer.conn.Close()
+
+	cliConn, err := v1.ListenAndServeUPlane(cliAddr, 0, make(chan error, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cliConn.Close()
+
+	cliConn.SupervisePath(peer.conn.LocalAddr(), &v1.PathSupervision{
+		Interval:   10 * time.Millisecond,
+		Timeout:    10 * time.Millisecond,
+		MaxRetries: 1,
+	})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for peer.count() < 3 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out while waiting for Echo Requests")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cliConn.StopPathSupervision(peer.conn.LocalAddr())
+
+	// the one in flight when stopped may still arrive.
+	time.Sleep(50 * time.Millisecond)
+	n := peer.count()
+	time.Sleep(200 * time.Millisecond)
+	if got := peer.count(); got != n {
+		t.Errorf("Echo Requests sent after stopped: %d", got-n)
+	}
+	if got := cliConn.PathState(peer.conn.LocalAddr()); got != v1.PathStateUnknown {
+		t.Errorf("path should not be supervised: %s", got)
+	}
+}
+
+func TestSocketOptions(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("socket options are supported only on Linux")
+	}
+
+	cliAddr := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 61), Port: 2152}
+	srvAddr := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 62), Port: 2152}
+	errCh := make(chan error, 1)
+	so := &v1.SocketOptions{DSCP: 46, ReusePort: true, ReadBuffer: 1 << 20}
+
+	srvConn, err := v1.ListenAndServeUPlaneWithSocketOptions(srvAddr, 0, errCh, so)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+
+	// the Echo is exchanged over the sockets with the options set.
+	cliConn, err := v1.DialUPlaneWithSocketOptions(cliAddr, srvAddr, 0, errCh, so)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cliConn.Close()
+
+	// another UPlaneConn can bind the same address with ReusePort.
+	anotherConn, err := v1.ListenAndServeUPlaneWithSocketOptions(srvAddr, 0, errCh, so)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer anotherConn.Close()
+
+	if _, err := v1.ListenAndServeUPlaneWithSocketOptions(srvAddr, 0, errCh, &v1.SocketOptions{DSCP: 64}); err != v1.ErrInvalidDSCP {
+		t.Errorf("unexpected error with invalid DSCP: %v", err)
+	}
+}
+
+func TestPathMTU(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("path MTU discovery is supported only on Linux")
+	}
+
+	cliAddr := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 63), Port: 2152}
+	srvAddr := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 64), Port: 2152}
+	errCh := make(chan error, 1)
+	so := &v1.SocketOptions{DontFragment: true}
+
+	srvConn, err := v1.ListenAndServeUPlaneWithSocketOptions(srvAddr, 0, errCh, so)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+
+	cliConn, err := v1.DialUPlaneWithSocketOptions(cliAddr, srvAddr, 0, errCh, so)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cliConn.Close()
+
+	mtu, err := cliConn.PathMTU(srvAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	size, err := cliConn.MaxPayloadSize(srvAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// IPv4, UDP and GTP header.
+	if want := mtu - 20 - 8 - 8; size != want {
+		t.Errorf("wrong MaxPayloadSize: got %d, want %d", size, want)
+	}
+
+	// the UDP payload cannot exceed 65507 bytes with IPv4 regardless of the MTU.
+	_, err = cliConn.WriteToGTP(0x11111111, make([]byte, 65507), srvAddr)
+	var tooBig *v1.PacketTooBigError
+	if !errors.As(err, &tooBig) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tooBig.MTU != mtu || tooBig.Size != 65507+8 {
+		t.Errorf("unexpected PacketTooBigError: %+v", tooBig)
+	}
+}
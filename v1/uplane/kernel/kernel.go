@@ -0,0 +1,249 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package kernel programs the tunnels of the Linux kernel GTP-U device (gtp) from the
+// state of GTPv2-C Sessions and Bearers, so that the T-PDUs are encapsulated and
+// decapsulated in the kernel instead of being relayed in userspace.
+//
+// The tunnels are added and removed with the GTP_CMD_NEWPDP and GTP_CMD_DELPDP of the
+// generic netlink family "gtp", as libgtpnl does. This requires the gtp module to be
+// loaded and CAP_NET_ADMIN. On the other platforms, all the operations fail with
+// ErrUnsupported.
+//
+//	dev, err := kernel.Create("gtp-sgw", uConn, v1.RoleSGSN)
+//	// ...
+//	conn.SetUEEventHandler(dev.UEEventHandler(nil))
+package kernel
+
+import (
+	"errors"
+	"net"
+	"sync"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+)
+
+// Error definitions.
+var (
+	ErrUnsupported         = errors.New("kernel GTP-U is not supported on this platform")
+	ErrNotGTPDevice        = errors.New("not a GTP device")
+	ErrTunnelNotFound      = errors.New("tunnel not found")
+	ErrMissingPeerAddress  = errors.New("no remote address in Bearer")
+	ErrMissingSubscriberIP = errors.New("no valid SubscriberIP in Bearer")
+	ErrMissingTEID         = errors.New("no incoming or outgoing TEID in Bearer")
+	ErrDeviceAlreadyClosed = errors.New("device already closed")
+	ErrUnsupportedAddress  = errors.New("unsupported type of remote address")
+)
+
+// Tunnel is a GTP-U tunnel of a Bearer programmed into the kernel, which is called
+// PDP context in the kernel.
+type Tunnel struct {
+	// PeerIP is the IP address of the remote GTP-U endpoint.
+	PeerIP net.IP
+	// MSIP is the IP address of the subscriber, i.e., the destination address of
+	// the downlink packets routed to the device.
+	MSIP net.IP
+	// ITEI and OTEI are the incoming and outgoing TEIDs.
+	ITEI, OTEI uint32
+}
+
+// TunnelFromBearer returns the Tunnel for the Bearer, built from its remote address,
+// SubscriberIP, incoming TEID and outgoing TEID.
+func TunnelFromBearer(b *v2.Bearer) (*Tunnel, error) {
+	raddr := b.RemoteAddress()
+	if raddr == nil {
+		return nil, ErrMissingPeerAddress
+	}
+	peerIP, err := ipOf(raddr)
+	if err != nil {
+		return nil, err
+	}
+
+	msIP := net.ParseIP(b.SubscriberIP)
+	if msIP == nil {
+		return nil, ErrMissingSubscriberIP
+	}
+
+	if b.IncomingTEID() == 0 || b.OutgoingTEID() == 0 {
+		return nil, ErrMissingTEID
+	}
+
+	return &Tunnel{
+		PeerIP: peerIP,
+		MSIP:   msIP,
+		ITEI:   b.IncomingTEID(),
+		OTEI:   b.OutgoingTEID(),
+	}, nil
+}
+
+func ipOf(addr net.Addr) (net.IP, error) {
+	switch a := addr.(type) {
+	case *net.UDPAddr:
+		return a.IP, nil
+	case *net.IPAddr:
+		return a.IP, nil
+	}
+
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		host = addr.String()
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return ip, nil
+	}
+	return nil, ErrUnsupportedAddress
+}
+
+// Device is a Linux kernel GTP-U device.
+type Device struct {
+	mu     sync.Mutex
+	dev    device
+	closed bool
+
+	// tunnels is the Tunnels added through Device, keyed by ITEI.
+	tunnels map[uint32]*Tunnel
+}
+
+func newDevice(dev device) *Device {
+	return &Device{dev: dev, tunnels: map[uint32]*Tunnel{}}
+}
+
+// Tunnels returns the Tunnels added through Device.
+func (d *Device) Tunnels() []*Tunnel {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	tunnels := make([]*Tunnel, 0, len(d.tunnels))
+	for _, t := range d.tunnels {
+		tt := *t
+		tunnels = append(tunnels, &tt)
+	}
+	return tunnels
+}
+
+// AddTunnel adds t to the device. The existing tunnel that has the same ITEI and/or
+// MSIP is replaced.
+func (d *Device) AddTunnel(t *Tunnel) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return ErrDeviceAlreadyClosed
+	}
+	if err := d.dev.addTunnel(t); err != nil {
+		return err
+	}
+
+	for itei, old := range d.tunnels {
+		if old.MSIP.Equal(t.MSIP) {
+			delete(d.tunnels, itei)
+		}
+	}
+	tt := *t
+	d.tunnels[t.ITEI] = &tt
+	return nil
+}
+
+// DeleteTunnel deletes the tunnel with the ITEI given from the device.
+func (d *Device) DeleteTunnel(itei uint32) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return ErrDeviceAlreadyClosed
+	}
+	if err := d.dev.deleteTunnel(itei); err != nil {
+		return err
+	}
+	delete(d.tunnels, itei)
+	return nil
+}
+
+// AddBearer adds the tunnel of the Bearer to the device. Call this again after the
+// TEIDs or the remote address of the Bearer are updated, e.g., by Modify Bearer
+// Request, to replace the tunnel.
+func (d *Device) AddBearer(b *v2.Bearer) error {
+	t, err := TunnelFromBearer(b)
+	if err != nil {
+		return err
+	}
+	return d.AddTunnel(t)
+}
+
+// DeleteBearer deletes the tunnel of the Bearer from the device.
+func (d *Device) DeleteBearer(b *v2.Bearer) error {
+	return d.DeleteTunnel(b.IncomingTEID())
+}
+
+// AddSession adds the tunnels of all the Bearers in the Session to the device. It
+// tries all the Bearers even if some of them fail, and returns the first error.
+func (d *Device) AddSession(sess *v2.Session) error {
+	var first error
+	for _, b := range sess.Bearers() {
+		if err := d.AddBearer(b); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// DeleteSession deletes the tunnels of all the Bearers in the Session from the device.
+// It tries all the Bearers even if some of them fail, and returns the first error.
+func (d *Device) DeleteSession(sess *v2.Session) error {
+	var first error
+	for _, b := range sess.Bearers() {
+		if err := d.DeleteBearer(b); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// UEEventHandler returns the v2.UEEventHandler that adds the tunnels of the Sessions
+// added to Conn, and deletes the ones of the Sessions removed. The errors are given to
+// fn, and ignored if fn is nil.
+//
+// The Bearers should have the TEIDs, the remote address and SubscriberIP set before
+// the Session is added to Conn. Otherwise, call AddSession after they are set.
+func (d *Device) UEEventHandler(fn v2.ErrorHandler) v2.UEEventHandler {
+	return func(ue *v2.UE, sess *v2.Session, event v2.UEEvent) {
+		var err error
+		switch event {
+		case v2.UEEventSessionAdded:
+			err = d.AddSession(sess)
+		case v2.UEEventSessionRemoved:
+			err = d.DeleteSession(sess)
+		default:
+			return
+		}
+		if err != nil && fn != nil {
+			fn(err)
+		}
+	}
+}
+
+// Close deletes the tunnels added through Device, and the device itself if it is
+// created with Create.
+func (d *Device) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return ErrDeviceAlreadyClosed
+	}
+	d.closed = true
+
+	var first error
+	for itei := range d.tunnels {
+		if err := d.dev.deleteTunnel(itei); err != nil && first == nil {
+			first = err
+		}
+	}
+	d.tunnels = nil
+
+	if err := d.dev.close(); err != nil && first == nil {
+		first = err
+	}
+	return first
+}
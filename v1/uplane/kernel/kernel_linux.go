@@ -0,0 +1,123 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package kernel
+
+import (
+	"net"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	v1 "github.com/wmnsk/go-gtp/v1"
+)
+
+type device struct {
+	link *netlink.GTP
+
+	// file is the duplicated socket given to the kernel, kept open while the device
+	// is in use. It is nil if the device is opened with Open.
+	file *os.File
+}
+
+// Create creates a new GTP device with the name given, which uses the socket of conn
+// for GTPv1-U, and brings it up. The device is deleted when Device is closed.
+//
+// The T-PDUs are handled by the kernel once the tunnels are added, and conn receives
+// the other messages and the T-PDUs that do not match any of them.
+func Create(name string, conn *net.UDPConn, role v1.Role) (*Device, error) {
+	f, err := conn.File()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the file of conn")
+	}
+
+	link := &netlink.GTP{
+		LinkAttrs: netlink.LinkAttrs{
+			Name: name,
+		},
+		FD1:  int(f.Fd()),
+		Role: int(role),
+	}
+	if err := netlink.LinkAdd(link); err != nil {
+		_ = f.Close()
+		return nil, errors.Wrapf(err, "failed to add device: %s", name)
+	}
+	if err := netlink.LinkSetUp(link); err != nil {
+		_ = netlink.LinkDel(link)
+		_ = f.Close()
+		return nil, errors.Wrapf(err, "failed to setup device: %s", name)
+	}
+	if err := netlink.LinkSetMTU(link, 1500); err != nil {
+		_ = netlink.LinkDel(link)
+		_ = f.Close()
+		return nil, errors.Wrapf(err, "failed to set MTU for device: %s", name)
+	}
+
+	return newDevice(device{link: link, file: f}), nil
+}
+
+// Open opens the existing GTP device with the name given, e.g., the one created with
+// gtp-link of libgtpnl. The device is not deleted when Device is closed.
+func Open(name string) (*Device, error) {
+	l, err := netlink.LinkByName(name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find device: %s", name)
+	}
+	link, ok := l.(*netlink.GTP)
+	if !ok {
+		return nil, ErrNotGTPDevice
+	}
+	return newDevice(device{link: link}), nil
+}
+
+// Link returns the netlink.GTP of the device.
+func (d *Device) Link() *netlink.GTP {
+	return d.dev.link
+}
+
+func (d device) addTunnel(t *Tunnel) error {
+	// replace the existing ones, as the kernel refuses to add the duplicated one.
+	if pdp, _ := netlink.GTPPDPByMSAddress(d.link, t.MSIP); pdp != nil {
+		_ = netlink.GTPPDPDel(d.link, pdp)
+	}
+	if pdp, _ := netlink.GTPPDPByITEI(d.link, int(t.ITEI)); pdp != nil {
+		_ = netlink.GTPPDPDel(d.link, pdp)
+	}
+
+	pdp := &netlink.PDP{
+		Version:     1,
+		PeerAddress: t.PeerIP,
+		MSAddress:   t.MSIP,
+		OTEI:        t.OTEI,
+		ITEI:        t.ITEI,
+	}
+	if err := netlink.GTPPDPAdd(d.link, pdp); err != nil {
+		return errors.Wrapf(err, "failed to add tunnel for %s with %s", t.MSIP, t.PeerIP)
+	}
+	return nil
+}
+
+func (d device) deleteTunnel(itei uint32) error {
+	pdp, err := netlink.GTPPDPByITEI(d.link, int(itei))
+	if err != nil || pdp == nil {
+		return ErrTunnelNotFound
+	}
+	if err := netlink.GTPPDPDel(d.link, pdp); err != nil {
+		return errors.Wrapf(err, "failed to delete tunnel for %s", pdp)
+	}
+	return nil
+}
+
+func (d device) close() error {
+	if d.file == nil {
+		return nil
+	}
+
+	err := netlink.LinkDel(d.link)
+	if cerr := d.file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,37 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// +build !linux
+
+package kernel
+
+import (
+	"net"
+
+	v1 "github.com/wmnsk/go-gtp/v1"
+)
+
+type device struct{}
+
+// Create is not supported on this platform and returns ErrUnsupported.
+func Create(name string, conn *net.UDPConn, role v1.Role) (*Device, error) {
+	return nil, ErrUnsupported
+}
+
+// Open is not supported on this platform and returns ErrUnsupported.
+func Open(name string) (*Device, error) {
+	return nil, ErrUnsupported
+}
+
+func (d device) addTunnel(t *Tunnel) error {
+	return ErrUnsupported
+}
+
+func (d device) deleteTunnel(itei uint32) error {
+	return ErrUnsupported
+}
+
+func (d device) close() error {
+	return ErrUnsupported
+}
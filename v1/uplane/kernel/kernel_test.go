@@ -0,0 +1,75 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package kernel_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wmnsk/go-gtp/v1/uplane/kernel"
+	v2 "github.com/wmnsk/go-gtp/v2"
+)
+
+func TestTunnelFromBearer(t *testing.T) {
+	newBearer := func(raddr net.Addr, msIP string, itei, otei uint32) *v2.Bearer {
+		b := v2.NewBearer(5, "some.apn.example", &v2.QoSProfile{})
+		if raddr != nil {
+			b.SetRemoteAddress(raddr)
+		}
+		b.SubscriberIP = msIP
+		b.SetIncomingTEID(itei)
+		b.SetOutgoingTEID(otei)
+		return b
+	}
+	peer := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 2), Port: 2152}
+
+	cases := []struct {
+		description string
+		bearer      *v2.Bearer
+		tunnel      *kernel.Tunnel
+		err         error
+	}{
+		{
+			"Normal",
+			newBearer(peer, "10.0.0.1", 0x11111111, 0x22222222),
+			&kernel.Tunnel{
+				PeerIP: peer.IP,
+				MSIP:   net.ParseIP("10.0.0.1"),
+				ITEI:   0x11111111,
+				OTEI:   0x22222222,
+			},
+			nil,
+		}, {
+			"NoRemoteAddress",
+			newBearer(nil, "10.0.0.1", 0x11111111, 0x22222222),
+			nil,
+			kernel.ErrMissingPeerAddress,
+		}, {
+			"NoSubscriberIP",
+			newBearer(peer, "", 0x11111111, 0x22222222),
+			nil,
+			kernel.ErrMissingSubscriberIP,
+		}, {
+			"NoOutgoingTEID",
+			newBearer(peer, "10.0.0.1", 0x11111111, 0),
+			nil,
+			kernel.ErrMissingTEID,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			got, err := kernel.TunnelFromBearer(c.bearer)
+			if err != c.err {
+				t.Fatalf("unexpected error: got %v, want %v", err, c.err)
+			}
+			if diff := cmp.Diff(got, c.tunnel); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
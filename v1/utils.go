@@ -7,7 +7,11 @@ package v1
 import "github.com/wmnsk/go-gtp/v1/messages"
 
 // Encapsulate encapsulates given bytes with GTPv1-U Header and returns in message.TPDU.
-func Encapsulate(teid uint32, payload []byte) *messages.TPDU {
+// The Extension Headers are added to the header if given.
+func Encapsulate(teid uint32, payload []byte, exts ...*messages.ExtensionHeader) *messages.TPDU {
+	if len(exts) > 0 {
+		return messages.NewTPDUWithExtensionHeaders(teid, payload, exts...)
+	}
 	pdu := messages.NewTPDU(teid, payload)
 	return pdu
 }
@@ -0,0 +1,209 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// accessIFTypes is the InterfaceTypes of the F-TEIDs on the access side, which are
+// released by Release Access Bearers.
+var accessIFTypes = []uint8{IFTypeS1UeNodeBGTPU, IFTypeS12RNCGTPU, IFTypeS4SGSNGTPU}
+
+func isAccessIFType(ifType uint8) bool {
+	for _, it := range accessIFTypes {
+		if ifType == it {
+			return true
+		}
+	}
+	return false
+}
+
+// ReleaseAccessBearers removes the F-TEIDs on the access side, e.g., the S1-U F-TEID
+// of eNodeB, from the Session and marks all the Bearers in it released.
+//
+// This is typically used when the UE goes to ECM-IDLE, and is called by (*Conn)
+// ReleaseAccessBearersResponse automatically. The sender of the Release Access
+// Bearers Request can use this after the request is accepted.
+func (s *Session) ReleaseAccessBearers() {
+	for _, it := range accessIFTypes {
+		s.teidMap.delete(it)
+	}
+
+	var brs []*Bearer
+	s.bearerMap.rangeWithFunc(func(k, v interface{}) bool {
+		br := v.(*Bearer)
+		br.mu.Lock()
+		br.accessReleased = true
+		br.mu.Unlock()
+		brs = append(brs, br)
+		return true
+	})
+
+	for _, br := range brs {
+		s.notifyBearer(br, BearerChangeUpdated)
+	}
+}
+
+// ModifyAccessBearers updates the Bearers in Session with the Bearer Context IEs
+// given in Modify Access Bearers Request.
+//
+// The Bearer Contexts with instance 0 are the ones to be modified: the Bearers are
+// looked up by the EBI, and the F-TEIDs on the access side are set to the Session
+// and the Bearer, which is no longer marked released. The remote address of the
+// Bearer is set to the IP address in the F-TEID with GTPUPort. The Bearer Contexts
+// with instance 1 are the ones to be removed, and the Bearers are removed from the
+// Session. It returns BearerNotFoundError if any of the Bearers to be modified
+// cannot be found.
+//
+// This is called by (*Conn) ModifyAccessBearersResponse automatically. The sender
+// of the Modify Access Bearers Request can use this after the request is accepted.
+func (s *Session) ModifyAccessBearers(bearerContexts ...*ies.IE) error {
+	for _, bc := range bearerContexts {
+		if bc == nil || bc.Type != ies.BearerContext {
+			continue
+		}
+
+		var ebi uint8
+		var fteids []*ies.IE
+		for _, child := range bc.ChildIEs {
+			switch child.Type {
+			case ies.EPSBearerID:
+				ebi = child.MustEPSBearerID()
+			case ies.FullyQualifiedTEID:
+				fteids = append(fteids, child)
+			}
+		}
+
+		if bc.Instance() == 1 {
+			s.RemoveBearerByEBI(ebi)
+			continue
+		}
+
+		br, err := s.LookupBearerByEBI(ebi)
+		if err != nil {
+			return err
+		}
+		for _, fteid := range fteids {
+			if err := s.modifyAccessBearer(br, fteid); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// modifyAccessBearer sets the F-TEID on the access side to the Session and br.
+func (s *Session) modifyAccessBearer(br *Bearer, fteid *ies.IE) error {
+	it, err := fteid.InterfaceType()
+	if err != nil {
+		return err
+	}
+	if !isAccessIFType(it) {
+		return nil
+	}
+	teid, err := fteid.TEID()
+	if err != nil {
+		return err
+	}
+	ip, err := fteid.IPAddress()
+	if err != nil {
+		return err
+	}
+
+	s.AddTEID(it, teid)
+
+	br.mu.Lock()
+	br.teidOut = teid
+	br.raddr = &net.UDPAddr{IP: net.ParseIP(ip), Port: GTPUPort}
+	br.accessReleased = false
+	br.mu.Unlock()
+
+	s.notifyBearer(br, BearerChangeUpdated)
+	return nil
+}
+
+// ReleaseAccessBearers sends a ReleaseAccessBearersRequest with TEID and IEs given.
+func (c *Conn) ReleaseAccessBearers(teid uint32, raddr net.Addr, ie ...*ies.IE) (uint32, error) {
+	sess, err := c.GetSessionByTEID(teid, raddr)
+	if err != nil {
+		return 0, err
+	}
+
+	msg := messages.NewReleaseAccessBearersRequest(teid, 0, ie...)
+
+	seq, err := c.SendMessageTo(msg, sess.PeerAddr())
+	if err != nil {
+		return 0, err
+	}
+	return seq, nil
+}
+
+// ReleaseAccessBearersResponse sends a ReleaseAccessBearersResponse with TEID and
+// IEs given in response to the ReleaseAccessBearersRequest, and releases the access
+// side of the Bearers in the Session if the Cause given is an acceptance.
+//
+// The Session is looked up by the TEID in req and raddr. See (*Session)
+// ReleaseAccessBearers for what is released.
+func (c *Conn) ReleaseAccessBearersResponse(teid uint32, raddr net.Addr, req messages.Message, ie ...*ies.IE) error {
+	sess, err := c.GetSessionByTEID(req.TEID(), raddr)
+	if err != nil {
+		return err
+	}
+
+	if err := c.RespondTo(raddr, req, messages.NewReleaseAccessBearersResponse(teid, 0, ie...)); err != nil {
+		return err
+	}
+
+	if !isAccepted(ie...) {
+		return nil
+	}
+	sess.ReleaseAccessBearers()
+	return nil
+}
+
+// ModifyAccessBearers sends a ModifyAccessBearersRequest with TEID and IEs given.
+func (c *Conn) ModifyAccessBearers(teid uint32, raddr net.Addr, ie ...*ies.IE) (uint32, error) {
+	sess, err := c.GetSessionByTEID(teid, raddr)
+	if err != nil {
+		return 0, err
+	}
+
+	msg := messages.NewModifyAccessBearersRequest(teid, 0, ie...)
+
+	seq, err := c.SendMessageTo(msg, sess.PeerAddr())
+	if err != nil {
+		return 0, err
+	}
+	return seq, nil
+}
+
+// ModifyAccessBearersResponse sends a ModifyAccessBearersResponse with TEID and IEs
+// given in response to the ModifyAccessBearersRequest, and updates the Bearers in the
+// Session with the Bearer Contexts in req if the Cause given is an acceptance.
+//
+// The Session is looked up by the TEID in req and raddr. See (*Session)
+// ModifyAccessBearers for how the Bearers are updated.
+func (c *Conn) ModifyAccessBearersResponse(teid uint32, raddr net.Addr, req messages.Message, ie ...*ies.IE) error {
+	sess, err := c.GetSessionByTEID(req.TEID(), raddr)
+	if err != nil {
+		return err
+	}
+
+	if err := c.RespondTo(raddr, req, messages.NewModifyAccessBearersResponse(teid, 0, ie...)); err != nil {
+		return err
+	}
+
+	if !isAccepted(ie...) {
+		return nil
+	}
+	if mabr, ok := req.(*messages.ModifyAccessBearersRequest); ok {
+		return sess.ModifyAccessBearers(mabr.BearerContextsToBeModified, mabr.BearerContextsToBeRemoved)
+	}
+	return nil
+}
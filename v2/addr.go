@@ -0,0 +1,88 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+	"strconv"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+)
+
+// addrString returns the string of addr used to identify the peer in Conn.
+//
+// Unlike addr.String(), the same peer always results in the same string regardless
+// of how the address is given: the IPv4-mapped IPv6 address is given as IPv4, and
+// the zone of IPv6 address given by the index of the interface is given by its name,
+// which is the one given by the packets received on the interface.
+func addrString(addr net.Addr) string {
+	if addr == nil {
+		return ""
+	}
+
+	switch a := addr.(type) {
+	case *net.UDPAddr:
+		return net.JoinHostPort(ipString(a.IP, a.Zone), strconv.Itoa(a.Port))
+	default:
+		return addr.String()
+	}
+}
+
+// ipString returns the string of ip with the zone in the form of "ip%zone".
+// The zone is ignored if ip is not IPv6.
+func ipString(ip net.IP, zone string) string {
+	if v4 := ip.To4(); v4 != nil {
+		return v4.String()
+	}
+	if zone == "" {
+		return ip.String()
+	}
+	return ip.String() + "%" + zoneName(zone)
+}
+
+// zoneName returns the name of the interface if zone is the index of it.
+func zoneName(zone string) string {
+	idx, err := strconv.Atoi(zone)
+	if err != nil {
+		return zone
+	}
+	ifi, err := net.InterfaceByIndex(idx)
+	if err != nil {
+		return zone
+	}
+	return ifi.Name
+}
+
+// PeerAddrFromFTEID returns the address of the control plane peer with the IP
+// address in F-TEID given and the GTPv2-C port.
+//
+// The IPv6 address is preferred if F-TEID has both IPv4 and IPv6 address. As F-TEID
+// cannot carry the zone of IPv6 address, the zone of the local address of Conn is
+// set if the address is link-local.
+func (c *Conn) PeerAddrFromFTEID(fteid *ies.IE) (*net.UDPAddr, error) {
+	ipStr, err := fteid.IPv6Address()
+	if err != nil {
+		if ipStr, err = fteid.IPAddress(); err != nil {
+			return nil, err
+		}
+	}
+
+	raddr := &net.UDPAddr{IP: net.ParseIP(ipStr), Port: GTPCPort}
+	if raddr.IP.To4() == nil && (raddr.IP.IsLinkLocalUnicast() || raddr.IP.IsLinkLocalMulticast()) {
+		if laddr, ok := c.localUDPAddr(); ok {
+			raddr.Zone = laddr.Zone
+		}
+	}
+	return raddr, nil
+}
+
+// localUDPAddr returns the local address of Conn as *net.UDPAddr.
+func (c *Conn) localUDPAddr() (*net.UDPAddr, bool) {
+	if c.pktConn == nil {
+		return nil, false
+	}
+	laddr, ok := c.LocalAddr().(*net.UDPAddr)
+	return laddr, ok
+}
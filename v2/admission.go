@@ -0,0 +1,199 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// AdmissionAction is the action taken for the requests that exceed the limits of
+// AdmissionControl.
+type AdmissionAction int
+
+// AdmissionAction definitions.
+const (
+	// AdmissionActionReject responds to the request with Cause "No resources
+	// available". The request is discarded if it is not the one that this package
+	// can respond to. This is the default.
+	AdmissionActionReject AdmissionAction = iota
+	// AdmissionActionDiscard discards the request silently, expecting the peer to
+	// retransmit it later.
+	AdmissionActionDiscard
+)
+
+// AdmissionControl is the limits of the rate of incoming requests processed by Conn,
+// which protects the node from the signaling storms.
+//
+// The rates are applied with the token bucket algorithm, to the requests from all
+// the peers and from each peer respectively. The request is admitted only when both
+// of them allow it. The zero Rate means no limit.
+//
+// Only the Initial messages except Echo Request are subject to the limits, and the
+// retransmitted requests that are answered from the cache of responses do not
+// consume the tokens.
+type AdmissionControl struct {
+	// Rate is the number of requests per second from all the peers, and Burst is
+	// the maximum number of requests that can be processed at once.
+	Rate  float64
+	Burst int
+
+	// PeerRate is the number of requests per second from each peer, and PeerBurst
+	// is the maximum number of requests that can be processed at once.
+	PeerRate  float64
+	PeerBurst int
+
+	// Action is the action taken for the requests that exceed the limits.
+	Action AdmissionAction
+}
+
+// SetAdmissionControl sets the AdmissionControl to be applied to the incoming
+// requests. Giving nil removes the limits, which is the default.
+//
+// The requests that are not admitted are not passed to the HandlerFuncs, and are
+// counted as Throttled in Stats.
+func (c *Conn) SetAdmissionControl(ac *AdmissionControl) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ac == nil {
+		c.admission = nil
+		return
+	}
+	c.admission = newAdmitter(*ac)
+}
+
+func (c *Conn) admitter() *admitter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.admission
+}
+
+// admit reports whether msg from senderAddr is to be processed, and takes the action
+// for the one that is not admitted.
+func (c *Conn) admit(senderAddr net.Addr, msg messages.Message) bool {
+	if msg.MessageType() == messages.MsgTypeCreateSessionRequest && c.isShuttingDown() {
+		c.log().Debug("request rejected while shutting down", msgFields(senderAddr, msg)...)
+		c.rejectRequest(senderAddr, msg)
+		return false
+	}
+
+	a := c.admitter()
+	if a == nil {
+		return true
+	}
+	if msg.MessageType() == messages.MsgTypeEchoRequest || !isInitialMessage(msg.MessageType()) {
+		return true
+	}
+	if a.allow(addrString(senderAddr), time.Now()) {
+		return true
+	}
+
+	c.stats.throttle()
+	c.log().Debug("request throttled", msgFields(senderAddr, msg)...)
+	if a.config.Action == AdmissionActionDiscard {
+		return false
+	}
+
+	c.rejectRequest(senderAddr, msg)
+	return false
+}
+
+// rejectRequest responds to msg with Cause "No resources available".
+func (c *Conn) rejectRequest(senderAddr net.Addr, msg messages.Message) {
+	present, err := presentIEs(msg)
+	if err != nil {
+		return
+	}
+	res := c.newRejectResponse(senderAddr, msg, present, ies.NewCause(CauseNoResourcesAvailable, 0, 0, 0, nil))
+	if res == nil {
+		return
+	}
+	if err := c.RespondTo(senderAddr, msg, res); err != nil {
+		c.notifyError(err)
+	}
+}
+
+// admitter keeps the token buckets for AdmissionControl.
+type admitter struct {
+	config AdmissionControl
+
+	mu     sync.Mutex
+	global *tokenBucket
+	peers  map[string]*tokenBucket
+}
+
+func newAdmitter(ac AdmissionControl) *admitter {
+	a := &admitter{config: ac, peers: map[string]*tokenBucket{}}
+	if ac.Rate > 0 {
+		a.global = newTokenBucket(ac.Rate, ac.Burst)
+	}
+	return a
+}
+
+func (a *admitter) allow(peer string, now time.Time) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var pb *tokenBucket
+	if a.config.PeerRate > 0 {
+		pb = a.peers[peer]
+		if pb == nil {
+			pb = newTokenBucket(a.config.PeerRate, a.config.PeerBurst)
+			a.peers[peer] = pb
+		}
+	}
+
+	// the tokens are consumed only when both of the buckets allow it.
+	if a.global != nil && !a.global.available(now) {
+		return false
+	}
+	if pb != nil && !pb.available(now) {
+		return false
+	}
+	if a.global != nil {
+		a.global.take()
+	}
+	if pb != nil {
+		pb.take()
+	}
+	return true
+}
+
+// tokenBucket is a token bucket that is filled with rate tokens per second up to
+// burst tokens.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst)}
+}
+
+// available fills the bucket up to now and reports whether a token is available.
+func (b *tokenBucket) available(now time.Time) bool {
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+	return b.tokens >= 1
+}
+
+func (b *tokenBucket) take() {
+	b.tokens--
+}
@@ -0,0 +1,61 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"time"
+
+	"github.com/wmnsk/go-gtp/internal/batchio"
+)
+
+// SetBatchSize sets the maximum number of datagrams read from the socket at once.
+// Giving 0 or 1 disables it, which is the default.
+//
+// On Linux, the datagrams are read with a single recvmmsg(2), which reduces the cost
+// of the system calls on the busy nodes. On the other platforms, or if the Conn is
+// not over *net.UDPConn, it falls back to reading one datagram at a time. The change
+// takes effect after the read currently blocked returns.
+//
+// The messages sent are not batched, as they are sent one by one by the callers.
+func (c *Conn) SetBatchSize(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if n <= 1 {
+		c.batchConn, c.batchSize = nil, 0
+		return
+	}
+	if c.batchConn == nil {
+		c.batchConn = batchio.New(c.pktConn)
+	}
+	c.batchSize = n
+}
+
+func (c *Conn) batch() (*batchio.Conn, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.batchConn, c.batchSize
+}
+
+// serveBatch reads the datagrams into ms with bc and dispatches them.
+func (c *Conn) serveBatch(bc *batchio.Conn, ms []batchio.Message) {
+	n, err := bc.ReadBatch(ms)
+	if err != nil {
+		select {
+		case <-c.closed():
+			return
+		default:
+		}
+		c.log().Warn("failed to read from conn", "local", c.LocalAddr().String(), "error", err)
+		return
+	}
+
+	arrivedAt := time.Now()
+	for _, m := range ms[:n] {
+		raw := make([]byte, m.N)
+		copy(raw, m.Buf)
+		c.dispatchDatagram(&datagram{raddr: m.Addr, raw: raw, arrivedAt: arrivedAt})
+	}
+}
@@ -6,27 +6,46 @@ package v2
 
 import (
 	"net"
+	"sync"
 )
 
 // QoSProfile represents a QoS-related information that belongs to a Bearer.
 type QoSProfile struct {
 	PCI, PVI bool
 	PL, QCI  uint8
-	// Max bit rate for Uplink and Donwlink
+	// Max bit rate for Uplink and Donwlink in kbps, as encoded in Bearer QoS IE.
+	// Use (ies.BitRate) Kbps to set the ones in bps.
 	MBRUL, MBRDL uint64
-	// Guaranteed bit rate for Uplink and Donwlink
+	// Guaranteed bit rate for Uplink and Donwlink in kbps.
 	GBRUL, GBRDL uint64
 }
 
 // Bearer represents a GTPv2 bearer.
+//
+// The fields are updated by Conn while handling the messages of the Session that
+// Bearer belongs to. Use the Get and Set methods to read or write them; the exported
+// fields are kept only for compatibility.
 type Bearer struct {
+	mu              sync.Mutex
 	raddr           net.Addr
 	teidIn, teidOut uint32
 
-	EBI               uint8
-	SubscriberIP, APN string
-	ChargingID        uint32
+	// Deprecated: use GetEBI and SetEBI instead.
+	EBI uint8
+	// Deprecated: use GetSubscriberIP and SetSubscriberIP instead.
+	SubscriberIP string
+	// Deprecated: use GetAPN and SetAPN instead.
+	APN string
+	// Deprecated: use GetChargingID and SetChargingID instead.
+	ChargingID uint32
+	// Deprecated: use GetQoSProfile and SetQoSProfile instead.
 	*QoSProfile
+
+	// suspended is set while the Session that Bearer belongs to is suspended.
+	suspended bool
+	// accessReleased is set while the access side of Bearer is released by Release
+	// Access Bearers, until it is re-established by Modify Access Bearers.
+	accessReleased bool
 }
 
 // NewBearer creates a new Bearer.
@@ -36,32 +55,172 @@ func NewBearer(ebi uint8, apn string, qos *QoSProfile) *Bearer {
 	}
 }
 
+// GetEBI returns the EBI of Bearer.
+func (b *Bearer) GetEBI() uint8 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.EBI
+}
+
+// SetEBI sets the EBI of Bearer.
+func (b *Bearer) SetEBI(ebi uint8) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.EBI = ebi
+}
+
+// GetAPN returns the APN of Bearer.
+func (b *Bearer) GetAPN() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.APN
+}
+
+// SetAPN sets the APN of Bearer.
+func (b *Bearer) SetAPN(apn string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.APN = apn
+}
+
+// GetSubscriberIP returns the IP address of the subscriber associated with Bearer.
+func (b *Bearer) GetSubscriberIP() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.SubscriberIP
+}
+
+// SetSubscriberIP sets the IP address of the subscriber associated with Bearer.
+func (b *Bearer) SetSubscriberIP(ip string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.SubscriberIP = ip
+}
+
+// GetChargingID returns the Charging ID of Bearer.
+func (b *Bearer) GetChargingID() uint32 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.ChargingID
+}
+
+// SetChargingID sets the Charging ID of Bearer.
+func (b *Bearer) SetChargingID(id uint32) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.ChargingID = id
+}
+
+// GetQoSProfile returns the copy of the QoSProfile of Bearer, or nil if not set.
+func (b *Bearer) GetQoSProfile() *QoSProfile {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.QoSProfile == nil {
+		return nil
+	}
+	qos := *b.QoSProfile
+	return &qos
+}
+
+// SetQoSProfile sets the copy of qos to Bearer, so modifying it after calling this
+// does not affect Bearer.
+func (b *Bearer) SetQoSProfile(qos *QoSProfile) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if qos == nil {
+		b.QoSProfile = nil
+		return
+	}
+	q := *qos
+	b.QoSProfile = &q
+}
+
 // RemoteAddress returns the remote address associated with Bearer.
 func (b *Bearer) RemoteAddress() net.Addr {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
 	return b.raddr
 }
 
 // SetRemoteAddress sets the remote address associated with Bearer.
 func (b *Bearer) SetRemoteAddress(raddr net.Addr) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
 	b.raddr = raddr
 }
 
 // IncomingTEID returns the incoming TEID associated with Bearer.
 func (b *Bearer) IncomingTEID() uint32 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
 	return b.teidIn
 }
 
 // SetIncomingTEID sets the incoming TEID associated with Bearer.
 func (b *Bearer) SetIncomingTEID(teid uint32) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
 	b.teidIn = teid
 }
 
 // OutgoingTEID returns the outgoing TEID associated with Bearer.
 func (b *Bearer) OutgoingTEID() uint32 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
 	return b.teidOut
 }
 
 // SetOutgoingTEID sets the outgoing TEID associated with Bearer.
 func (b *Bearer) SetOutgoingTEID(teid uint32) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
 	b.teidOut = teid
 }
+
+// IsSuspended reports whether Bearer is suspended by Suspend Notification.
+func (b *Bearer) IsSuspended() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.suspended
+}
+
+// IsAccessReleased reports whether the access side of Bearer, e.g., the S1-U F-TEID
+// of eNodeB, is released by Release Access Bearers.
+func (b *Bearer) IsAccessReleased() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.accessReleased
+}
+
+func (b *Bearer) setSuspended(suspended bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.suspended = suspended
+}
+
+// hasTEID reports whether teid is either the incoming or outgoing TEID of Bearer.
+func (b *Bearer) hasTEID(teid uint32) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return teid == b.teidIn || teid == b.teidOut
+}
@@ -0,0 +1,57 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"sync"
+
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// pooledBufferSize is the size of the buffers kept in bufferPool. The larger ones are
+// allocated each time and not put back to the pool.
+const pooledBufferSize = 1600
+
+// bufferPool is the pool of the buffers to marshal the messages to be sent, which
+// are not retained after being written to the socket.
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, pooledBufferSize)
+		return &b
+	},
+}
+
+// marshalBuffer marshals msg into the buffer from bufferPool with the IEs arranged
+// in order. The buffer should be given to releaseBuffer after use.
+func marshalBuffer(msg messages.Message, order messages.IEOrder) (*[]byte, error) {
+	n := msg.MarshalLen()
+
+	var bp *[]byte
+	if n <= pooledBufferSize {
+		bp = bufferPool.Get().(*[]byte)
+		*bp = (*bp)[:n]
+	} else {
+		b := make([]byte, n)
+		bp = &b
+	}
+
+	if err := msg.MarshalTo(*bp); err != nil {
+		releaseBuffer(bp)
+		return nil, err
+	}
+	if err := messages.ArrangeIEs(*bp, order); err != nil {
+		releaseBuffer(bp)
+		return nil, err
+	}
+	return bp, nil
+}
+
+func releaseBuffer(bp *[]byte) {
+	if cap(*bp) != pooledBufferSize {
+		return
+	}
+	*bp = (*bp)[:pooledBufferSize]
+	bufferPool.Put(bp)
+}
@@ -0,0 +1,233 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// DefaultResponseCacheTTL is the default duration to keep the responses sent by Conn
+// in order to detect the retransmitted requests.
+//
+// TS29.274 7.6 Reliable Delivery of Signalling Messages;
+// The duration should be long enough to cover the retransmissions by the peer,
+// which is typically T3-RESPONSE * N3-REQUESTS.
+const DefaultResponseCacheTTL = 10 * time.Second
+
+// isInitialMessage reports whether the type of message is an Initial message, which
+// is retransmitted by the sender when the Triggered message is not received.
+func isInitialMessage(msgType uint8) bool {
+	switch msgType {
+	case messages.MsgTypeEchoRequest,
+		messages.MsgTypeCreateSessionRequest,
+		messages.MsgTypeModifyBearerRequest,
+		messages.MsgTypeDeleteSessionRequest,
+		messages.MsgTypeChangeNotificationRequest,
+		messages.MsgTypeModifyBearerCommand,
+		messages.MsgTypeDeleteBearerCommand,
+		messages.MsgTypeBearerResourceCommand,
+		messages.MsgTypeCreateBearerRequest,
+		messages.MsgTypeUpdateBearerRequest,
+		messages.MsgTypeDeleteBearerRequest,
+		messages.MsgTypeDeletePDNConnectionSetRequest,
+		messages.MsgTypeIdentificationRequest,
+		messages.MsgTypeContextRequest,
+		messages.MsgTypeForwardRelocationRequest,
+		messages.MsgTypeForwardRelocationCompleteNotification,
+		messages.MsgTypeForwardAccessContextNotification,
+		messages.MsgTypeRelocationCancelRequest,
+		messages.MsgTypeDetachNotification,
+		messages.MsgTypeAlertMMENotification,
+		messages.MsgTypeUEActivityNotification,
+		messages.MsgTypeUERegistrationQueryRequest,
+		messages.MsgTypeCreateForwardingTunnelRequest,
+		messages.MsgTypeSuspendNotification,
+		messages.MsgTypeResumeNotification,
+		messages.MsgTypeCreateIndirectDataForwardingTunnelRequest,
+		messages.MsgTypeDeleteIndirectDataForwardingTunnelRequest,
+		messages.MsgTypeReleaseAccessBearersRequest,
+		messages.MsgTypeDownlinkDataNotification,
+		messages.MsgTypePGWRestartNotification,
+		messages.MsgTypeUpdatePDNConnectionSetRequest,
+		messages.MsgTypeModifyAccessBearersRequest,
+		messages.MsgTypeMBMSSessionStartRequest,
+		messages.MsgTypeMBMSSessionUpdateRequest,
+		messages.MsgTypeMBMSSessionStopRequest:
+		return true
+	default:
+		return false
+	}
+}
+
+type cacheKey struct {
+	peer    string
+	msgType uint8
+	seq     uint32
+}
+
+type cacheEntry struct {
+	expiresAt time.Time
+	// response is nil until the response is sent.
+	response []byte
+	// handled is set if the request is handled without the response cached, e.g.,
+	// the HandlerFunc responds with WriteTo or does not respond at all.
+	handled bool
+}
+
+// responseCache keeps the requests received recently and the responses to them.
+type responseCache struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	entries   map[cacheKey]*cacheEntry
+	lastSweep time.Time
+}
+
+func newResponseCache(ttl time.Duration) *responseCache {
+	return &responseCache{
+		ttl:     ttl,
+		entries: map[cacheKey]*cacheEntry{},
+	}
+}
+
+// check registers the request if it is seen for the first time, or if the previous
+// one is handled without the response cached.
+// If it is a retransmitted one, it returns true with the response if already sent.
+func (r *responseCache) check(peer net.Addr, msg messages.Message) (dup bool, response []byte) {
+	if r == nil {
+		return false, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.sweep(now)
+
+	key := cacheKey{addrString(peer), msg.MessageType(), msg.Sequence()}
+	if e, ok := r.entries[key]; ok && now.Before(e.expiresAt) && !(e.handled && e.response == nil) {
+		return true, e.response
+	}
+
+	r.entries[key] = &cacheEntry{expiresAt: now.Add(r.ttl)}
+	return false, nil
+}
+
+// store stores the response to the request if the request is registered.
+func (r *responseCache) store(peer net.Addr, req messages.Message, response []byte) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := cacheKey{addrString(peer), req.MessageType(), req.Sequence()}
+	e, ok := r.entries[key]
+	if !ok {
+		return
+	}
+	// copied as the caller may reuse the buffer.
+	e.response = append([]byte(nil), response...)
+}
+
+// handled marks the request registered as handled, so that the retransmitted one is
+// handled again if no response to it is stored.
+func (r *responseCache) handled(peer net.Addr, req messages.Message) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if e, ok := r.entries[cacheKey{addrString(peer), req.MessageType(), req.Sequence()}]; ok {
+		e.handled = true
+	}
+}
+
+// forget removes the request from the cache, so that the retransmitted one is
+// handled as a new request.
+func (r *responseCache) forget(peer net.Addr, req messages.Message) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.entries, cacheKey{addrString(peer), req.MessageType(), req.Sequence()})
+}
+
+// sweep removes the expired entries at most once in ttl.
+func (r *responseCache) sweep(now time.Time) {
+	if now.Sub(r.lastSweep) < r.ttl {
+		return
+	}
+
+	for k, e := range r.entries {
+		if !now.Before(e.expiresAt) {
+			delete(r.entries, k)
+		}
+	}
+	r.lastSweep = now
+}
+
+// SetResponseCacheTTL sets the duration to keep the requests received and the
+// responses sent in response to them.
+//
+// When Conn receives the same Initial message (detected by the sender, message type
+// and Sequence Number) within the duration, it re-sends the response sent previously
+// instead of calling the HandlerFunc again. If the response has not been sent yet,
+// the retransmitted message is just discarded while the request is being handled or
+// the response is deferred with ErrPending. Only the responses sent with RespondTo
+// or Transaction are cached, and the retransmitted message is handled as a new one
+// if the HandlerFunc has returned without the response cached, e.g., when it responds
+// with WriteTo or does not respond at all.
+//
+// It is DefaultResponseCacheTTL by default, and giving 0 disables the detection.
+func (c *Conn) SetResponseCacheTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ttl <= 0 {
+		c.respCache = nil
+		return
+	}
+	c.respCache = newResponseCache(ttl)
+}
+
+func (c *Conn) responseCache() *responseCache {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.respCache
+}
+
+// handleRetransmission reports whether msg is a retransmitted request, and re-sends
+// the response cached if any.
+func (c *Conn) handleRetransmission(senderAddr net.Addr, msg messages.Message) (bool, error) {
+	if !isInitialMessage(msg.MessageType()) {
+		return false, nil
+	}
+
+	dup, response := c.responseCache().check(senderAddr, msg)
+	if !dup {
+		return false, nil
+	}
+	c.stats.retransmission(msg.MessageType())
+	c.log().Debug("received retransmitted request", msgFields(senderAddr, msg, "resent", response != nil)...)
+	if response == nil {
+		return true, nil
+	}
+
+	if _, err := c.writeTo(response, senderAddr, msg, nil); err != nil {
+		return true, err
+	}
+	return true, nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// RejectionHandler is a handler for the responses to the requests sent from Conn,
+// which have the Cause other than acceptance. req is the request sent, rsp is the
+// response to it, and err is the Cause in rsp.
+type RejectionHandler func(c *Conn, senderAddr net.Addr, req, rsp messages.Message, err *CauseError) error
+
+// CheckCause checks the Cause IE at the top level of msg, and returns CauseError if
+// the Cause is not the one of acceptance(=from 16 to 63 defined in TS 29.274 8.4).
+//
+// It returns RequiredIEMissingError if msg has no Cause IE. The Causes in the grouped
+// IEs, e.g., the ones in Bearer Contexts, are not checked.
+func CheckCause(msg messages.Message) error {
+	i, err := messages.FindIE(msg, ies.Cause, 0)
+	if err != nil {
+		return &RequiredIEMissingError{Type: ies.Cause}
+	}
+	if isAccepted(i) {
+		return nil
+	}
+	return newCauseError(msg, i)
+}
+
+func newCauseError(msg messages.Message, i *ies.IE) *CauseError {
+	e := &CauseError{MsgType: msg.MessageTypeName(), Cause: Cause(i.MustCause())}
+
+	// the flags are placed in the same way as NewCause.
+	if len(i.Payload) >= 2 {
+		e.PCE = i.Payload[1]&0x04 != 0
+		e.BCE = i.Payload[1]&0x02 != 0
+		e.CS = i.Payload[1]&0x01 != 0
+	}
+	if len(i.Payload) >= 3 {
+		e.OffendingIEType = i.Payload[2]
+	}
+	if len(i.Payload) >= 6 {
+		e.OffendingIEInstance = i.Payload[5] & 0x0f
+	}
+	return e
+}
+
+// SetRejectionHandler registers fn to be called with the responses to the requests
+// sent from Conn that have the Cause other than acceptance, instead of the HandlerFunc
+// for the response. This lets the HandlerFuncs for the responses handle only the
+// accepted ones, without checking the Cause in each of them.
+//
+// The error returned from fn is passed to the background error handling. The
+// responses without Cause IE, and the ones to the requests not sent from Conn or
+// already expired, are passed to the HandlerFuncs as usual. Giving nil removes fn.
+func (c *Conn) SetRejectionHandler(fn RejectionHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.rejectionFn = fn
+}
+
+func (c *Conn) rejectionHandler() RejectionHandler {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.rejectionFn
+}
+
+// handleRejection passes rsp to the RejectionHandler if rsp is the rejection of req,
+// and reports whether it is handled.
+func (c *Conn) handleRejection(senderAddr net.Addr, req, rsp messages.Message) bool {
+	fn := c.rejectionHandler()
+	if fn == nil || req == nil {
+		return false
+	}
+
+	ce, ok := CheckCause(rsp).(*CauseError)
+	if !ok {
+		return false
+	}
+	if err := fn(c, senderAddr, req, rsp, ce); err != nil {
+		c.notifyError(err)
+	}
+	return true
+}
@@ -0,0 +1,118 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// GetChargingCharacteristics returns the Charging Characteristics of the PDN
+// connection of Session, or 0 if not known.
+func (s *Session) GetChargingCharacteristics() uint16 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.chargingCharacteristics
+}
+
+// SetChargingCharacteristics sets the Charging Characteristics of the PDN connection
+// of Session.
+//
+// This is set by NewSessionFromIEs and CreateSession if the IEs given contain the
+// Charging Characteristics.
+func (s *Session) SetChargingCharacteristics(chr uint16) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.chargingCharacteristics = chr
+}
+
+// GetAPNAMBR returns the APN-AMBR of the PDN connection of Session for Uplink and
+// Downlink in kbps, or 0 if not known.
+func (s *Session) GetAPNAMBR() (up, down uint32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.apnAMBRUp, s.apnAMBRDown
+}
+
+// SetAPNAMBR sets the APN-AMBR of the PDN connection of Session for Uplink and
+// Downlink in kbps.
+//
+// This is set by NewSessionFromIEs and CreateSession if the IEs given contain the
+// APN-AMBR, and updated when the Create Session Request, Modify Bearer Request or
+// Update Bearer Request with APN-AMBR is accepted, in the same way as the Bearers
+// are updated.
+func (s *Session) SetAPNAMBR(up, down uint32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.apnAMBRUp, s.apnAMBRDown = up, down
+}
+
+// updateAPNAMBR sets the APN-AMBR in the first AMBR IE in ie to Session, if any.
+func (s *Session) updateAPNAMBR(ie ...*ies.IE) error {
+	for _, i := range ie {
+		if i == nil || i.Type != ies.AggregateMaximumBitRate {
+			continue
+		}
+		up, err := i.AggregateMaximumBitRateUp()
+		if err != nil {
+			return err
+		}
+		down, err := i.AggregateMaximumBitRateDown()
+		if err != nil {
+			return err
+		}
+		s.SetAPNAMBR(up, down)
+		return nil
+	}
+	return nil
+}
+
+// updateChargingIDs sets the Charging IDs in the Bearer Contexts given to the Bearers
+// with the same EBI in Session. The Bearer Contexts without Charging ID, or the ones
+// whose Bearer is not found, are ignored.
+func (s *Session) updateChargingIDs(bearerContexts ...*ies.IE) error {
+	for _, bc := range bearerContexts {
+		for _, child := range bc.ChildIEs {
+			if child.Type != ies.ChargingID {
+				continue
+			}
+			id, err := child.ChargingID()
+			if err != nil {
+				return err
+			}
+			br, err := s.LookupBearerByEBI(ebiIn(bc))
+			if err != nil {
+				break
+			}
+			br.SetChargingID(id)
+			s.notifyBearer(br, BearerChangeUpdated)
+		}
+	}
+	return nil
+}
+
+// ModifyBearerResponse sends a ModifyBearerResponse with TEID and IEs given in
+// response to the ModifyBearerRequest, and updates the APN-AMBR of the Session with
+// the one in req if the Cause given is an acceptance.
+//
+// The Session is looked up by the TEID in req and raddr.
+func (c *Conn) ModifyBearerResponse(teid uint32, raddr net.Addr, req messages.Message, ie ...*ies.IE) error {
+	sess, err := c.GetSessionByTEID(req.TEID(), raddr)
+	if err != nil {
+		return err
+	}
+
+	rsp := messages.NewModifyBearerResponse(teid, 0, ie...)
+	if err := sess.applyBearerResponse(req, rsp); err != nil {
+		return err
+	}
+	return c.RespondTo(raddr, req, rsp)
+}
@@ -0,0 +1,269 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package conformance provides a corpus of GTPv2-C messages with the expected
+// results of decoding, and the API to verify the parsers in messages and ies
+// packages against it.
+//
+// The messages in the corpus are modeled after the ones captured on the real
+// networks, with the subscriber and network identities anonymized. They are
+// intended to be run in the tests of the packages that extend or modify the IEs,
+// to make sure that the decoding of the traffic of other vendors is not broken.
+//
+//	func TestConformance(t *testing.T) {
+//		for _, f := range conformance.Run(conformance.Corpus()) {
+//			t.Error(f)
+//		}
+//	}
+package conformance
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// Sample is a message in the corpus with the expected results of decoding.
+type Sample struct {
+	Name        string
+	Description string
+
+	// Raw is the message in the wire format.
+	Raw []byte
+
+	MessageType uint8
+	TEID        uint32
+	Sequence    uint32
+
+	// IEs is the tree of the top-level IEs in Raw, in the order they appear.
+	IEs []*ExpectedIE
+
+	// Fields is the IEs expected in the fields of the decoded message, keyed by the
+	// field name. The elements of the slice fields are given as "Name.N".
+	Fields map[string]ExpectedField
+}
+
+// ExpectedIE is an IE expected in the Sample.
+//
+// Payload is compared for the IEs that are not grouped, and Children for the
+// grouped ones.
+type ExpectedIE struct {
+	Type     uint8
+	Instance uint8
+	Payload  []byte
+	Children []*ExpectedIE
+}
+
+// ExpectedField is the type and instance of the IE expected in a field of the
+// decoded message.
+type ExpectedField struct {
+	Type     uint8
+	Instance uint8
+}
+
+// Failure is the Sample that failed in the verification and the reason.
+type Failure struct {
+	Sample *Sample
+	Err    error
+}
+
+// Error returns the Failure in string.
+func (f *Failure) Error() string {
+	return fmt.Sprintf("%s: %v", f.Sample.Name, f.Err)
+}
+
+// Run verifies all the samples given, and returns the Failures. It returns nil if
+// all of them pass.
+func Run(samples []*Sample) []*Failure {
+	var failures []*Failure
+	for _, s := range samples {
+		if err := s.Verify(); err != nil {
+			failures = append(failures, &Failure{Sample: s, Err: err})
+		}
+	}
+	return failures
+}
+
+// Verify decodes the Raw of Sample with the current parsers and checks if the result
+// matches the expected one.
+//
+// The message should be decoded into the type specific to MessageType without error,
+// with the IEs and the fields expected. It also checks if the decoded message is
+// serialized back into the same set of IEs, ignoring the order of them, as the
+// messages are serialized in the order of the fields.
+func (s *Sample) Verify() error {
+	msg, err := messages.Parse(s.Raw)
+	if err != nil {
+		return fmt.Errorf("failed to parse: %v", err)
+	}
+	if _, ok := msg.(*messages.Generic); ok {
+		return fmt.Errorf("decoded as Generic: %d", msg.MessageType())
+	}
+
+	if got := msg.MessageType(); got != s.MessageType {
+		return fmt.Errorf("wrong MessageType. want %d, got: %d", s.MessageType, got)
+	}
+	if got := msg.TEID(); got != s.TEID {
+		return fmt.Errorf("wrong TEID. want %#x, got: %#x", s.TEID, got)
+	}
+	if got := msg.Sequence(); got != s.Sequence {
+		return fmt.Errorf("wrong Sequence. want %#x, got: %#x", s.Sequence, got)
+	}
+
+	decoded, err := topLevelIEs(s.Raw)
+	if err != nil {
+		return fmt.Errorf("failed to parse IEs: %v", err)
+	}
+	if err := compareIEs("", s.IEs, decoded); err != nil {
+		return err
+	}
+
+	for name, want := range s.Fields {
+		ie, err := fieldIE(msg, name)
+		if err != nil {
+			return err
+		}
+		if ie.Type != want.Type || ie.Instance() != want.Instance {
+			return fmt.Errorf(
+				"wrong IE in %s. want %d/%d, got: %d/%d",
+				name, want.Type, want.Instance, ie.Type, ie.Instance(),
+			)
+		}
+	}
+
+	b, err := messages.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal: %v", err)
+	}
+	reencoded, err := topLevelIEs(b)
+	if err != nil {
+		return fmt.Errorf("failed to parse IEs marshaled: %v", err)
+	}
+	if err := compareIESets(decoded, reencoded); err != nil {
+		return err
+	}
+	return nil
+}
+
+func topLevelIEs(b []byte) ([]*ies.IE, error) {
+	h, err := messages.ParseHeader(b)
+	if err != nil {
+		return nil, err
+	}
+	return ies.ParseMultiIEs(h.Payload)
+}
+
+func compareIEs(path string, want []*ExpectedIE, got []*ies.IE) error {
+	if len(want) != len(got) {
+		return fmt.Errorf("wrong number of IEs in %s. want %d, got: %d", pathOrTop(path), len(want), len(got))
+	}
+
+	for n, w := range want {
+		g := got[n]
+		p := path + "/" + strconv.Itoa(n)
+		if g.Type != w.Type || g.Instance() != w.Instance {
+			return fmt.Errorf("wrong IE at %s. want %d/%d, got: %d/%d", p, w.Type, w.Instance, g.Type, g.Instance())
+		}
+
+		if w.Children != nil {
+			if !g.IsGrouped() {
+				return fmt.Errorf("IE at %s is not decoded as grouped: %d", p, g.Type)
+			}
+			if err := compareIEs(p, w.Children, g.ChildIEs); err != nil {
+				return err
+			}
+			continue
+		}
+		if !bytes.Equal(g.Payload, w.Payload) {
+			return fmt.Errorf("wrong payload of IE at %s. want %x, got: %x", p, w.Payload, g.Payload)
+		}
+	}
+	return nil
+}
+
+func pathOrTop(path string) string {
+	if path == "" {
+		return "message"
+	}
+	return path
+}
+
+func compareIESets(want, got []*ies.IE) error {
+	w, err := serializedIEs(want)
+	if err != nil {
+		return err
+	}
+	g, err := serializedIEs(got)
+	if err != nil {
+		return err
+	}
+	if strings.Join(w, ",") != strings.Join(g, ",") {
+		return fmt.Errorf("IEs changed by marshaling. want %v, got: %v", w, g)
+	}
+	return nil
+}
+
+func serializedIEs(ie []*ies.IE) ([]string, error) {
+	var ss []string
+	for _, i := range ie {
+		b, err := i.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		ss = append(ss, hex.EncodeToString(b))
+	}
+	sort.Strings(ss)
+	return ss, nil
+}
+
+// fieldIE returns the IE in the field of msg given by name.
+func fieldIE(msg messages.Message, name string) (*ies.IE, error) {
+	fieldName, index := name, -1
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		n, err := strconv.Atoi(name[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid field name: %s", name)
+		}
+		fieldName, index = name[:i], n
+	}
+
+	v := reflect.ValueOf(msg)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	f := v.FieldByName(fieldName)
+	if !f.IsValid() {
+		return nil, fmt.Errorf("no such field in %T: %s", msg, fieldName)
+	}
+
+	switch ie := f.Interface().(type) {
+	case *ies.IE:
+		if ie == nil || index >= 0 {
+			return nil, fmt.Errorf("IE not found in %s", name)
+		}
+		return ie, nil
+	case []*ies.IE:
+		if index < 0 || index >= len(ie) || ie[index] == nil {
+			return nil, fmt.Errorf("IE not found in %s", name)
+		}
+		return ie[index], nil
+	default:
+		return nil, fmt.Errorf("field %s is not an IE", name)
+	}
+}
+
+func mustDecodeHex(s string) []byte {
+	b, err := hex.DecodeString(strings.Join(strings.Fields(s), ""))
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
@@ -0,0 +1,56 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package conformance_test
+
+import (
+	"testing"
+
+	"github.com/wmnsk/go-gtp/v2/conformance"
+	"github.com/wmnsk/go-gtp/v2/ies"
+)
+
+func TestCorpus(t *testing.T) {
+	for _, f := range conformance.Run(conformance.Corpus()) {
+		t.Error(f)
+	}
+}
+
+func TestVerifyDetectsMismatch(t *testing.T) {
+	cases := []struct {
+		description string
+		modify      func(s *conformance.Sample)
+	}{
+		{
+			"wrong payload",
+			func(s *conformance.Sample) { s.IEs[0].Payload = []byte{0xff} },
+		}, {
+			"missing IE",
+			func(s *conformance.Sample) { s.IEs = s.IEs[1:] },
+		}, {
+			"wrong field",
+			func(s *conformance.Sample) {
+				s.Fields["Recovery"] = conformance.ExpectedField{Type: ies.Cause}
+			},
+		}, {
+			"unknown field",
+			func(s *conformance.Sample) {
+				s.Fields["NoSuchField"] = conformance.ExpectedField{Type: ies.Recovery}
+			},
+		}, {
+			"malformed message",
+			func(s *conformance.Sample) { s.Raw = s.Raw[:len(s.Raw)-1] },
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			s := conformance.Corpus()[0]
+			c.modify(s)
+			if err := s.Verify(); err == nil {
+				t.Error("Verify should fail")
+			}
+		})
+	}
+}
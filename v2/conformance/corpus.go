@@ -0,0 +1,257 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package conformance
+
+import (
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// Corpus returns the Samples in the corpus.
+//
+// The Samples are created every time it is called, so that modifying them does not
+// affect the others.
+func Corpus() []*Sample {
+	return []*Sample{
+		{
+			Name:        "echo-request",
+			Description: "Echo Request with Recovery sent by PGW.",
+			Raw: mustDecodeHex(
+				"40 01 00 09 00 00 01 00 03 00 01 00 2a",
+			),
+			MessageType: messages.MsgTypeEchoRequest,
+			TEID:        0x00000000,
+			Sequence:    0x000001,
+			IEs: []*ExpectedIE{
+				{Type: ies.Recovery, Instance: 0, Payload: []byte{0x2a}},
+			},
+			Fields: map[string]ExpectedField{
+				"Recovery": {ies.Recovery, 0},
+			},
+		},
+		{
+			Name:        "create-session-request-mme",
+			Description: "Create Session Request sent by MME on the initial attach over E-UTRAN.",
+			Raw: mustDecodeHex(
+				"48 20 00 e1 00 00 00 00 1c 2f 3a 00 01 00 08 00 " +
+					"00 01 01 00 00 00 00 f1 4c 00 06 00 18 00 00 00 " +
+					"00 10 4b 00 08 00 53 00 00 00 00 00 00 10 56 00 " +
+					"0d 00 18 00 f1 10 00 01 00 f1 10 00 00 01 01 53 " +
+					"00 03 00 00 f1 10 52 00 01 00 06 4d 00 04 00 00 " +
+					"08 00 00 57 00 09 00 8a 00 00 ab cd c0 00 02 01 " +
+					"57 00 09 01 87 00 00 00 00 c0 00 02 03 47 00 1c " +
+					"00 08 69 6e 74 65 72 6e 65 74 06 6d 6e 63 30 30 " +
+					"31 06 6d 63 63 30 30 31 04 67 70 72 73 80 00 01 " +
+					"00 00 63 00 01 00 01 4f 00 05 00 01 00 00 00 00 " +
+					"7f 00 01 00 00 48 00 08 00 00 00 c3 50 00 02 49 " +
+					"f0 5d 00 1f 00 49 00 01 00 05 50 00 16 00 65 09 " +
+					"00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 " +
+					"00 00 00 00 03 00 01 00 11 72 00 02 00 63 00 5f " +
+					"00 02 00 08 00",
+			),
+			MessageType: messages.MsgTypeCreateSessionRequest,
+			TEID:        0x00000000,
+			Sequence:    0x1c2f3a,
+			IEs: []*ExpectedIE{
+				{Type: ies.IMSI, Instance: 0, Payload: []byte{0x00, 0x01, 0x01, 0x00, 0x00, 0x00, 0x00, 0xf1}},
+				{Type: ies.MSISDN, Instance: 0, Payload: []byte{0x18, 0x00, 0x00, 0x00, 0x00, 0x10}},
+				{Type: ies.MobileEquipmentIdentity, Instance: 0, Payload: []byte{0x53, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10}},
+				{Type: ies.UserLocationInformation, Instance: 0, Payload: []byte{0x18, 0x00, 0xf1, 0x10, 0x00, 0x01, 0x00, 0xf1, 0x10, 0x00, 0x00, 0x01, 0x01}},
+				{Type: ies.ServingNetwork, Instance: 0, Payload: []byte{0x00, 0xf1, 0x10}},
+				{Type: ies.RATType, Instance: 0, Payload: []byte{0x06}},
+				{Type: ies.Indication, Instance: 0, Payload: []byte{0x00, 0x08, 0x00, 0x00}},
+				{Type: ies.FullyQualifiedTEID, Instance: 0, Payload: []byte{0x8a, 0x00, 0x00, 0xab, 0xcd, 0xc0, 0x00, 0x02, 0x01}},
+				{Type: ies.FullyQualifiedTEID, Instance: 1, Payload: []byte{0x87, 0x00, 0x00, 0x00, 0x00, 0xc0, 0x00, 0x02, 0x03}},
+				{Type: ies.AccessPointName, Instance: 0, Payload: []byte{0x08, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x65, 0x74, 0x06, 0x6d, 0x6e, 0x63, 0x30, 0x30, 0x31, 0x06, 0x6d, 0x63, 0x63, 0x30, 0x30, 0x31, 0x04, 0x67, 0x70, 0x72, 0x73}},
+				{Type: ies.SelectionMode, Instance: 0, Payload: []byte{0x00}},
+				{Type: ies.PDNType, Instance: 0, Payload: []byte{0x01}},
+				{Type: ies.PDNAddressAllocation, Instance: 0, Payload: []byte{0x01, 0x00, 0x00, 0x00, 0x00}},
+				{Type: ies.APNRestriction, Instance: 0, Payload: []byte{0x00}},
+				{Type: ies.AggregateMaximumBitRate, Instance: 0, Payload: []byte{0x00, 0x00, 0xc3, 0x50, 0x00, 0x02, 0x49, 0xf0}},
+				{Type: ies.BearerContext, Instance: 0, Children: []*ExpectedIE{
+					{Type: ies.EPSBearerID, Instance: 0, Payload: []byte{0x05}},
+					{Type: ies.BearerQoS, Instance: 0, Payload: []byte{0x65, 0x09, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}},
+				}},
+				{Type: ies.Recovery, Instance: 0, Payload: []byte{0x11}},
+				{Type: ies.UETimeZone, Instance: 0, Payload: []byte{0x63, 0x00}},
+				{Type: ies.ChargingCharacteristics, Instance: 0, Payload: []byte{0x08, 0x00}},
+			},
+			Fields: map[string]ExpectedField{
+				"IMSI":                      {ies.IMSI, 0},
+				"MSISDN":                    {ies.MSISDN, 0},
+				"MEI":                       {ies.MobileEquipmentIdentity, 0},
+				"ULI":                       {ies.UserLocationInformation, 0},
+				"ServingNetwork":            {ies.ServingNetwork, 0},
+				"RATType":                   {ies.RATType, 0},
+				"IndicationFlags":           {ies.Indication, 0},
+				"SenderFTEIDC":              {ies.FullyQualifiedTEID, 0},
+				"PGWS5S8FTEIDC":             {ies.FullyQualifiedTEID, 1},
+				"APN":                       {ies.AccessPointName, 0},
+				"SelectionMode":             {ies.SelectionMode, 0},
+				"PDNType":                   {ies.PDNType, 0},
+				"PAA":                       {ies.PDNAddressAllocation, 0},
+				"APNRestriction":            {ies.APNRestriction, 0},
+				"AMBR":                      {ies.AggregateMaximumBitRate, 0},
+				"BearerContextsToBeCreated": {ies.BearerContext, 0},
+				"Recovery":                  {ies.Recovery, 0},
+				"UETimeZone":                {ies.UETimeZone, 0},
+				"ChargingCharacteristics":   {ies.ChargingCharacteristics, 0},
+			},
+		},
+		{
+			Name:        "create-session-response-sgw",
+			Description: "Create Session Response sent by SGW with the PDN address allocated by PGW.",
+			Raw: mustDecodeHex(
+				"48 21 00 6c 00 00 ab cd 1c 2f 3a 00 02 00 02 00 " +
+					"10 00 57 00 09 00 8b 00 00 12 34 c0 00 02 02 57 " +
+					"00 09 01 87 00 00 56 78 c0 00 02 03 4f 00 05 00 " +
+					"01 0a 00 00 01 7f 00 01 00 01 5d 00 2d 00 49 00 " +
+					"01 00 05 02 00 02 00 10 00 57 00 09 00 81 00 00 " +
+					"9a bc c0 00 02 04 57 00 09 02 85 00 00 de f0 c0 " +
+					"00 02 05 5e 00 04 00 01 02 03 04 03 00 01 00 22",
+			),
+			MessageType: messages.MsgTypeCreateSessionResponse,
+			TEID:        0x0000abcd,
+			Sequence:    0x1c2f3a,
+			IEs: []*ExpectedIE{
+				{Type: ies.Cause, Instance: 0, Payload: []byte{0x10, 0x00}},
+				{Type: ies.FullyQualifiedTEID, Instance: 0, Payload: []byte{0x8b, 0x00, 0x00, 0x12, 0x34, 0xc0, 0x00, 0x02, 0x02}},
+				{Type: ies.FullyQualifiedTEID, Instance: 1, Payload: []byte{0x87, 0x00, 0x00, 0x56, 0x78, 0xc0, 0x00, 0x02, 0x03}},
+				{Type: ies.PDNAddressAllocation, Instance: 0, Payload: []byte{0x01, 0x0a, 0x00, 0x00, 0x01}},
+				{Type: ies.APNRestriction, Instance: 0, Payload: []byte{0x01}},
+				{Type: ies.BearerContext, Instance: 0, Children: []*ExpectedIE{
+					{Type: ies.EPSBearerID, Instance: 0, Payload: []byte{0x05}},
+					{Type: ies.Cause, Instance: 0, Payload: []byte{0x10, 0x00}},
+					{Type: ies.FullyQualifiedTEID, Instance: 0, Payload: []byte{0x81, 0x00, 0x00, 0x9a, 0xbc, 0xc0, 0x00, 0x02, 0x04}},
+					{Type: ies.FullyQualifiedTEID, Instance: 2, Payload: []byte{0x85, 0x00, 0x00, 0xde, 0xf0, 0xc0, 0x00, 0x02, 0x05}},
+					{Type: ies.ChargingID, Instance: 0, Payload: []byte{0x01, 0x02, 0x03, 0x04}},
+				}},
+				{Type: ies.Recovery, Instance: 0, Payload: []byte{0x22}},
+			},
+			Fields: map[string]ExpectedField{
+				"Cause":                 {ies.Cause, 0},
+				"SenderFTEIDC":          {ies.FullyQualifiedTEID, 0},
+				"PGWS5S8FTEIDC":         {ies.FullyQualifiedTEID, 1},
+				"PAA":                   {ies.PDNAddressAllocation, 0},
+				"APNRestriction":        {ies.APNRestriction, 0},
+				"BearerContextsCreated": {ies.BearerContext, 0},
+				"Recovery":              {ies.Recovery, 0},
+			},
+		},
+		{
+			Name:        "modify-bearer-request-handover",
+			Description: "Modify Bearer Request sent by MME on X2-based handover with the F-TEID of the target eNodeB.",
+			Raw: mustDecodeHex(
+				"48 22 00 2f 00 00 12 34 1c 2f 3b 00 56 00 0d 00 " +
+					"18 00 f1 10 00 01 00 f1 10 00 00 01 01 5d 00 12 " +
+					"00 49 00 01 00 05 57 00 09 00 80 00 00 01 01 c6 " +
+					"33 64 01",
+			),
+			MessageType: messages.MsgTypeModifyBearerRequest,
+			TEID:        0x00001234,
+			Sequence:    0x1c2f3b,
+			IEs: []*ExpectedIE{
+				{Type: ies.UserLocationInformation, Instance: 0, Payload: []byte{0x18, 0x00, 0xf1, 0x10, 0x00, 0x01, 0x00, 0xf1, 0x10, 0x00, 0x00, 0x01, 0x01}},
+				{Type: ies.BearerContext, Instance: 0, Children: []*ExpectedIE{
+					{Type: ies.EPSBearerID, Instance: 0, Payload: []byte{0x05}},
+					{Type: ies.FullyQualifiedTEID, Instance: 0, Payload: []byte{0x80, 0x00, 0x00, 0x01, 0x01, 0xc6, 0x33, 0x64, 0x01}},
+				}},
+			},
+			Fields: map[string]ExpectedField{
+				"ULI":                        {ies.UserLocationInformation, 0},
+				"BearerContextsToBeModified": {ies.BearerContext, 0},
+			},
+		},
+		{
+			Name:        "delete-session-request-vendor",
+			Description: "Delete Session Request sent by MME with a Private Extension of a vendor.",
+			Raw: mustDecodeHex(
+				"48 24 00 2f 00 00 12 34 1c 2f 3c 00 49 00 01 00 " +
+					"05 56 00 0d 00 18 00 f1 10 00 01 00 f1 10 00 00 " +
+					"01 01 4d 00 04 00 08 00 00 00 ff 00 05 00 12 34 " +
+					"01 02 03",
+			),
+			MessageType: messages.MsgTypeDeleteSessionRequest,
+			TEID:        0x00001234,
+			Sequence:    0x1c2f3c,
+			IEs: []*ExpectedIE{
+				{Type: ies.EPSBearerID, Instance: 0, Payload: []byte{0x05}},
+				{Type: ies.UserLocationInformation, Instance: 0, Payload: []byte{0x18, 0x00, 0xf1, 0x10, 0x00, 0x01, 0x00, 0xf1, 0x10, 0x00, 0x00, 0x01, 0x01}},
+				{Type: ies.Indication, Instance: 0, Payload: []byte{0x08, 0x00, 0x00, 0x00}},
+				{Type: ies.PrivateExtension, Instance: 0, Payload: []byte{0x12, 0x34, 0x01, 0x02, 0x03}},
+			},
+			Fields: map[string]ExpectedField{
+				"LinkedEBI":        {ies.EPSBearerID, 0},
+				"ULI":              {ies.UserLocationInformation, 0},
+				"IndicationFlags":  {ies.Indication, 0},
+				"PrivateExtension": {ies.PrivateExtension, 0},
+			},
+		},
+		{
+			Name:        "downlink-data-notification",
+			Description: "Downlink Data Notification sent by SGW with ARP of the Bearer.",
+			Raw: mustDecodeHex(
+				"48 b0 00 12 00 00 ab cd 00 00 10 00 49 00 01 00 " +
+					"05 9b 00 01 00 65",
+			),
+			MessageType: messages.MsgTypeDownlinkDataNotification,
+			TEID:        0x0000abcd,
+			Sequence:    0x000010,
+			IEs: []*ExpectedIE{
+				{Type: ies.EPSBearerID, Instance: 0, Payload: []byte{0x05}},
+				{Type: ies.AllocationRetensionPriority, Instance: 0, Payload: []byte{0x65}},
+			},
+			Fields: map[string]ExpectedField{
+				"EPSBearerID": {ies.EPSBearerID, 0},
+				"ARP":         {ies.AllocationRetensionPriority, 0},
+			},
+		},
+		{
+			Name:        "create-bearer-request-unknown-ie",
+			Description: "Create Bearer Request sent by SGW for a dedicated bearer of VoLTE, including an IE unknown to this package.",
+			Raw: mustDecodeHex(
+				"48 5f 00 45 00 00 ab cd 00 00 20 00 49 00 01 00 " +
+					"05 5d 00 2c 00 49 00 01 00 00 57 00 09 00 81 00 " +
+					"00 9a bd c0 00 02 04 50 00 16 00 08 01 00 00 00 " +
+					"00 40 00 00 00 00 40 00 00 00 00 40 00 00 00 00 " +
+					"40 fa 00 04 00 de ad be ef",
+			),
+			MessageType: messages.MsgTypeCreateBearerRequest,
+			TEID:        0x0000abcd,
+			Sequence:    0x000020,
+			IEs: []*ExpectedIE{
+				{Type: ies.EPSBearerID, Instance: 0, Payload: []byte{0x05}},
+				{Type: ies.BearerContext, Instance: 0, Children: []*ExpectedIE{
+					{Type: ies.EPSBearerID, Instance: 0, Payload: []byte{0x00}},
+					{Type: ies.FullyQualifiedTEID, Instance: 0, Payload: []byte{0x81, 0x00, 0x00, 0x9a, 0xbd, 0xc0, 0x00, 0x02, 0x04}},
+					{Type: ies.BearerQoS, Instance: 0, Payload: []byte{0x08, 0x01, 0x00, 0x00, 0x00, 0x00, 0x40, 0x00, 0x00, 0x00, 0x00, 0x40, 0x00, 0x00, 0x00, 0x00, 0x40, 0x00, 0x00, 0x00, 0x00, 0x40}},
+				}},
+				{Type: 250, Instance: 0, Payload: []byte{0xde, 0xad, 0xbe, 0xef}},
+			},
+			Fields: map[string]ExpectedField{
+				"LinkedEBI":       {ies.EPSBearerID, 0},
+				"BearerContexts":  {ies.BearerContext, 0},
+				"AdditionalIEs.0": {250, 0},
+			},
+		},
+		{
+			Name:        "release-access-bearers-request",
+			Description: "Release Access Bearers Request sent by MME on S1 release.",
+			Raw: mustDecodeHex(
+				"48 aa 00 0d 00 00 12 34 1c 2f 3d 00 87 00 01 00 " +
+					"01",
+			),
+			MessageType: messages.MsgTypeReleaseAccessBearersRequest,
+			TEID:        0x00001234,
+			Sequence:    0x1c2f3d,
+			IEs: []*ExpectedIE{
+				{Type: ies.NodeType, Instance: 0, Payload: []byte{0x01}},
+			},
+			Fields: map[string]ExpectedField{
+				"OriginatingNode": {ies.NodeType, 0},
+			},
+		},
+	}
+}
@@ -7,12 +7,16 @@ package v2
 import (
 	"crypto/rand"
 	"encoding/binary"
+	"fmt"
 	"net"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/pkg/errors"
 
+	"github.com/wmnsk/go-gtp/internal/batchio"
+	"github.com/wmnsk/go-gtp/internal/sequence"
 	"github.com/wmnsk/go-gtp/v2/ies"
 	"github.com/wmnsk/go-gtp/v2/messages"
 )
@@ -22,26 +26,150 @@ type Conn struct {
 	mu      sync.Mutex
 	pktConn net.PacketConn
 
+	// ownsPktConn is set if pktConn is created by Conn, which is closed by Close.
+	ownsPktConn bool
+
 	validationEnabled bool
+	mandatoryIERules  MandatoryIERules
+
+	// ieOrder is the order of the IEs in the messages sent, and ieOrders is the
+	// ones per message type that take precedence over it.
+	ieOrder  messages.IEOrder
+	ieOrders map[uint8]messages.IEOrder
+
+	// peerAddrs is the addresses of the peers keyed by IP address, used to
+	// check the source port of incoming messages with SourcePortPolicy.
+	peerAddrs        map[string]net.Addr
+	srcPortRule      *sourcePortRule
+	peerSrcPortRules map[string]*sourcePortRule
+
+	// respCache is to detect the retransmitted requests and re-send the response.
+	respCache *responseCache
+
+	// piggybacked is the set of messages that came in the same datagram, keyed by
+	// each of the messages in it. Entries exist only while they are being handled.
+	piggybacked sync.Map
+
+	// arrivals is the time the messages arrived, keyed by each of them. Entries exist
+	// only while they are being handled.
+	arrivals sync.Map
 
-	closeCh chan struct{}
-	errCh   chan error
+	// endpoints is the local endpoints added with AddLocalAddr, and receivedOnMap is
+	// the one that the messages arrived on, keyed by each of them. Entries of
+	// receivedOnMap exist only while they are being handled.
+	endpoints     endpoints
+	receivedOnMap sync.Map
+
+	closeCh    chan struct{}
+	errCh      chan error
+	errHandler ErrorHandler
 
 	*msgHandlerMap
+	middlewares []Middleware
 
 	// sequence is the last SequenceNumber used in the request.
 	//
 	// TS29.274 7.6  Reliable Delivery of Signalling Messages;
 	// The Sequence Number shall be unique for each outstanding Initial message sourced
 	// from the same IP/UDP endpoint(=Conn).
+	//
+	// The SequenceNumber is actually managed per Peer, and this is the last one used
+	// for any of them, which is also the initial value for a new Peer.
 	sequence uint32
 
+	// peers is the set of Peers keyed by the address in string.
+	peers map[string]*Peer
+
 	// RestartCounter is the RestartCounter value in Recovery IE, which represents how many
 	// times the GTPv2-C endpoint is restarted.
 	RestartCounter uint8
 
 	// Sessions is a set of sessions exists on the Conn with automatically-assigned IDs.
+	//
+	// Deprecated: this is modified by Conn while handling the messages, which races
+	// with the access from outside Conn. Use RangeSessions or the lookup methods such
+	// as GetSessionByIMSI to read it, and AddSession and RemoveSession to modify it.
 	Sessions []*Session
+
+	// historySize is the size of history enabled for the new Sessions.
+	historySize int
+
+	// stats is the statistics of Conn, which is nil in zero-value Conn.
+	stats *connStats
+
+	// parseLimits is the limits applied to the incoming messages.
+	parseLimits *messages.Limits
+
+	// shutdownPolicy is the way Shutdown drains the Sessions.
+	shutdownPolicy *ShutdownPolicy
+
+	// shuttingDown is set when Shutdown is called, to reject new Sessions.
+	shuttingDown bool
+
+	// echoIEs are the IEs added to the Echo Request and Response sent.
+	echoIEs []*ies.IE
+
+	// tracerFunc is called for every datagram sent and received.
+	tracerFunc TracerFunc
+
+	// parseMode is the ParseMode used to decode the incoming messages.
+	parseMode messages.ParseMode
+
+	// logger is the Logger for Conn, which is nil if the default one is used.
+	logger Logger
+
+	// sessionHooks is called on the events of the Sessions.
+	sessionHooks SessionHooks
+
+	// teidAllocator allocates the TEIDs in NewFTEID if set.
+	teidAllocator TEIDAllocator
+
+	// rejectionFn is called with the rejected responses to the requests sent.
+	rejectionFn RejectionHandler
+
+	// endMarkerFn is called when the downlink path of the Bearer is switched.
+	endMarkerFn EndMarkerFunc
+
+	// loadControlEnabled is to track the load and overload advertised by the peers,
+	// and localLoadControl is the IEs of this node attached to the outgoing messages,
+	// keyed by the message type. loadControlFn is called to generate them per message.
+	loadControlEnabled bool
+	localLoadControl   map[uint8][]*ies.IE
+	loadControlFn      LoadControlFunc
+
+	// teidLearningEnabled is to update the Sessions with the F-TEIDs in the
+	// responses received.
+	teidLearningEnabled bool
+
+	// nodeSelector is used to select the peer in CreateSessionByAPN.
+	nodeSelector NodeSelector
+
+	// ueEventFn is called when the Sessions of the UEs are added or removed.
+	ueEventFn UEEventHandler
+
+	// recoveryPolicy is how the Recovery IE is handled in the messages sent.
+	recoveryPolicy RecoveryPolicy
+
+	// admission limits the rate of incoming requests processed.
+	admission *admitter
+
+	// workers handles the incoming messages if WorkerPool is set.
+	workers *dispatcher
+
+	// sendQ writes the outgoing datagrams if SendQueue is set.
+	sendQ *sender
+
+	// subs is the channels that the received messages are delivered to.
+	subs subscriptions
+
+	// batchConn is used to read the datagrams in batches of batchSize if it is
+	// larger than 1.
+	batchConn *batchio.Conn
+	batchSize int
+
+	// versionHandlers handles the datagrams of the other GTP versions than 2, keyed
+	// by the version.
+	versionHandlers map[uint8]*versionHandler
 }
 
 // NewConn creates a new Conn over existing net.PacketConn.
@@ -55,7 +183,9 @@ func NewConn(pktConn net.PacketConn, raddr net.Addr, counter uint8, errCh chan e
 		validationEnabled: true,
 		closeCh:           make(chan struct{}),
 		errCh:             errCh,
-		msgHandlerMap:     defaultHandlerMap,
+		msgHandlerMap:     newDefaultHandlerMap(),
+		respCache:         newResponseCache(DefaultResponseCacheTTL),
+		stats:             newConnStats(),
 		sequence:          0,
 		RestartCounter:    counter,
 	}
@@ -101,15 +231,23 @@ func NewConn(pktConn net.PacketConn, raddr net.Addr, counter uint8, errCh chan e
 // exchange before returning *Conn.
 //
 // The errCh should be monitored continuously by caller after retrieving *Conn.
-// Otherwise the background process may get stuck. This error handling manner might
-// be changed in the future.
+// Otherwise the background process may get stuck. To avoid this, register an
+// ErrorHandler with SetErrorHandler; errCh can be nil in that case.
 func Dial(laddr, raddr net.Addr, counter uint8, errCh chan error) (*Conn, error) {
+	return DialWithSocketOptions(laddr, raddr, counter, errCh, nil)
+}
+
+// DialWithSocketOptions works the same as Dial, with the SocketOptions set on the
+// socket created. Giving nil as so is the same as Dial.
+func DialWithSocketOptions(laddr, raddr net.Addr, counter uint8, errCh chan error, so *SocketOptions) (*Conn, error) {
 	c := &Conn{
 		mu:                sync.Mutex{},
 		validationEnabled: true,
 		closeCh:           make(chan struct{}),
 		errCh:             errCh,
-		msgHandlerMap:     defaultHandlerMap,
+		msgHandlerMap:     newDefaultHandlerMap(),
+		respCache:         newResponseCache(DefaultResponseCacheTTL),
+		stats:             newConnStats(),
 		sequence:          0,
 		RestartCounter:    counter,
 	}
@@ -118,10 +256,11 @@ func Dial(laddr, raddr net.Addr, counter uint8, errCh chan error) (*Conn, error)
 	// not using net.Dial, as it binds src/dst IP:Port, which makes it harder to
 	// handle multiple connections with a Conn.
 	var err error
-	c.pktConn, err = net.ListenPacket(raddr.Network(), laddr.String())
+	c.pktConn, err = so.listenPacket(raddr.Network(), laddr.String())
 	if err != nil {
 		return nil, err
 	}
+	c.ownsPktConn = true
 
 	// send EchoRequest to raddr.
 	if _, err := c.EchoRequest(raddr); err != nil {
@@ -158,35 +297,69 @@ func Dial(laddr, raddr net.Addr, counter uint8, errCh chan error) (*Conn, error)
 // ListenAndServe creates a new GTPv2-C Conn and start serving background.
 //
 // The errCh should be monitored continuously by caller after retrieving *Conn.
-// Otherwise the background process may get stuck. This error handling manner might
-// be changed in the future.
+// Otherwise the background process may get stuck. To avoid this, register an
+// ErrorHandler with SetErrorHandler; errCh can be nil in that case.
 func ListenAndServe(laddr net.Addr, counter uint8, errCh chan error) (*Conn, error) {
+	return ListenAndServeWithSocketOptions(laddr, counter, errCh, nil)
+}
+
+// ListenAndServeWithSocketOptions works the same as ListenAndServe, with the
+// SocketOptions set on the socket created. Giving nil as so is the same as
+// ListenAndServe.
+func ListenAndServeWithSocketOptions(laddr net.Addr, counter uint8, errCh chan error, so *SocketOptions) (*Conn, error) {
 	c := &Conn{
 		mu:                sync.Mutex{},
 		validationEnabled: true,
 		closeCh:           make(chan struct{}),
 		errCh:             errCh,
-		msgHandlerMap:     defaultHandlerMap,
+		msgHandlerMap:     newDefaultHandlerMap(),
+		respCache:         newResponseCache(DefaultResponseCacheTTL),
+		stats:             newConnStats(),
 		sequence:          0,
 		RestartCounter:    counter,
 	}
 
 	var err error
-	c.pktConn, err = net.ListenPacket(laddr.Network(), laddr.String())
+	c.pktConn, err = so.listenPacket(laddr.Network(), laddr.String())
 	if err != nil {
 		return nil, err
 	}
+	c.ownsPktConn = true
 
 	go c.serve()
 	return c, nil
 }
 
+// Serve creates a new GTPv2-C Conn over existing net.PacketConn and start serving
+// background.
+//
+// This is the same as ListenAndServe except that the user gives the net.PacketConn,
+// and the counterpart of NewConn without the Echo exchange.
+func Serve(pktConn net.PacketConn, counter uint8, errCh chan error) *Conn {
+	c := &Conn{
+		mu:                sync.Mutex{},
+		pktConn:           pktConn,
+		validationEnabled: true,
+		closeCh:           make(chan struct{}),
+		errCh:             errCh,
+		msgHandlerMap:     newDefaultHandlerMap(),
+		respCache:         newResponseCache(DefaultResponseCacheTTL),
+		stats:             newConnStats(),
+		sequence:          0,
+		RestartCounter:    counter,
+	}
+
+	go c.serve()
+	return c
+}
+
 func (c *Conn) closed() <-chan struct{} {
 	return c.closeCh
 }
 
 func (c *Conn) serve() {
 	buf := make([]byte, 1600)
+	var batch []batchio.Message
 	for {
 		select {
 		case <-c.closed():
@@ -195,26 +368,73 @@ func (c *Conn) serve() {
 			// do nothing and go forward.
 		}
 
+		if bc, size := c.batch(); bc != nil {
+			if len(batch) != size {
+				batch = batchio.NewMessages(size, 1600)
+			}
+			c.serveBatch(bc, batch)
+			continue
+		}
+
 		n, raddr, err := c.pktConn.ReadFrom(buf)
 		if err != nil {
-			logf("error reading from conn: %s: %v", c.LocalAddr(), err)
+			select {
+			case <-c.closed():
+				return
+			default:
+			}
+			c.log().Warn("failed to read from conn", "local", c.LocalAddr().String(), "error", err)
 			continue
 		}
 
+		// not from the pool, as the messages parsed refer to it and may be retained
+		// by the HandlerFuncs.
 		raw := make([]byte, n)
 		copy(raw, buf)
-		go func() {
-			msg, err := messages.Parse(raw)
-			if err != nil {
-				logf("error parsing the message: %v, %x", err, raw)
-				return
-			}
+		c.dispatchDatagram(&datagram{raddr: raddr, raw: raw, arrivedAt: time.Now()})
+	}
+}
 
-			if err := c.handleMessage(raddr, msg); err != nil {
-				c.errCh <- err
-			}
-		}()
+// handleDatagram parses the datagram and handles the messages in it.
+func (c *Conn) handleDatagram(dg *datagram) {
+	if fn := c.versionHandler(dg.raw); fn != nil {
+		if err := fn(c, dg.raddr, dg.raw); err != nil {
+			c.notifyError(err)
+		}
+		return
+	}
+
+	msgs, err := messages.ParseMultiMessagesWithMode(dg.raw, c.limits(), c.mode())
+	c.traceIncoming(dg.raddr, dg.raw, msgs)
+	if err != nil {
+		if isLimitError(err) {
+			c.stats.limitViolation()
+		} else {
+			c.stats.parseError()
+		}
+		if fn := c.errorHandler(); fn != nil {
+			fn(errors.Wrapf(err, "failed to parse the message from %s: %x", dg.raddr, dg.raw))
+			return
+		}
+		c.log().Warn("failed to parse the message", "peer", dg.raddr.String(), "error", err, "raw", fmt.Sprintf("%x", dg.raw))
+		return
+	}
+
+	pktConn := dg.pktConn
+	if pktConn == nil {
+		pktConn = c.pktConn
 	}
+	c.learnEndpoint(dg.raddr, pktConn)
+	for _, msg := range msgs {
+		c.receivedOnMap.Store(msg, pktConn)
+	}
+	defer func() {
+		for _, msg := range msgs {
+			c.receivedOnMap.Delete(msg)
+		}
+	}()
+
+	c.handleMessages(dg.raddr, msgs, dg.arrivedAt)
 }
 
 // ReadFrom reads a packet from the connection,
@@ -236,21 +456,42 @@ func (c *Conn) ReadFrom(p []byte) (n int, addr net.Addr, err error) {
 // an Error with Timeout() == true after a fixed time limit;
 // see SetDeadline and SetWriteDeadline.
 // On packet-oriented connections, write timeouts are rare.
+//
+// If Conn has multiple local addresses added with AddLocalAddr, the one to be used
+// is chosen in the way described in AddLocalAddr.
 func (c *Conn) WriteTo(p []byte, addr net.Addr) (n int, err error) {
-	return c.pktConn.WriteTo(p, addr)
+	return c.writeTo(p, addr, nil, nil)
 }
 
 // Close closes the connection.
 // Any blocked Read or Write operations will be unblocked and return errors.
+//
+// The socket is closed only if it is created by Dial or ListenAndServe. The
+// net.PacketConn given to NewConn or Serve is left open to be closed by the caller.
 func (c *Conn) Close() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.msgHandlerMap = defaultHandlerMap
+	c.msgHandlerMap = newDefaultHandlerMap()
 	c.RestartCounter = 0
 	close(c.closeCh)
 
+	// not to wait for the workers here, as they may be waiting for c.mu.
+	if c.workers != nil {
+		go c.workers.stop()
+		c.workers = nil
+	}
+	if c.sendQ != nil {
+		go c.sendQ.stop()
+		c.sendQ = nil
+	}
+	c.subs.close()
+
 	// triggers error in blocking Read() / Write() immediately.
+	c.closeEndpoints()
+	if c.ownsPktConn {
+		return c.pktConn.Close()
+	}
 	if err := c.pktConn.SetDeadline(time.Now().Add(1 * time.Millisecond)); err != nil {
 		return err
 	}
@@ -311,6 +552,9 @@ func (c *Conn) SetWriteDeadline(t time.Time) error {
 // HandlerFuncs for EchoResponse and VersionNotSupportedIndication are registered by default.
 // These HandlerFuncs can be overwritten by specifying messages.MsgTypeEchoResponse and/or
 // messages.MsgTypeVersionNotSupportedIndication as msgType parameter.
+//
+// The HandlerFuncs added to a Session with (*Session) AddHandler take precedence over
+// the ones added here for the messages associated with the Session.
 func (c *Conn) AddHandler(msgType uint8, fn HandlerFunc) {
 	c.msgHandlerMap.store(msgType, fn)
 }
@@ -324,24 +568,206 @@ func (c *Conn) AddHandlers(funcs map[uint8]HandlerFunc) {
 	}
 }
 
+// Use adds Middlewares that wrap all the HandlerFuncs registered in *Conn, including
+// the default ones.
+//
+// The Middlewares are applied in the order they are added, i.e., the first one added
+// is called first and the HandlerFunc is called last.
+func (c *Conn) Use(mw ...Middleware) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.middlewares = append(c.middlewares, mw...)
+}
+
+func (c *Conn) wrapHandler(fn HandlerFunc) HandlerFunc {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		fn = c.middlewares[i](fn)
+	}
+	return fn
+}
+
+// handleMessages handles the messages that came in a datagram one by one in order.
+//
+// When the messages are piggybacked, the HandlerFunc for each of them can retrieve
+// the others with PiggybackedMessages while they are handled.
+func (c *Conn) handleMessages(senderAddr net.Addr, msgs []messages.Message, arrivedAt time.Time) {
+	for _, msg := range msgs {
+		c.arrivals.Store(msg, arrivedAt)
+	}
+	defer func() {
+		for _, msg := range msgs {
+			c.arrivals.Delete(msg)
+		}
+	}()
+
+	if len(msgs) > 1 {
+		for _, msg := range msgs {
+			c.piggybacked.Store(msg, msgs)
+		}
+		defer func() {
+			for _, msg := range msgs {
+				c.piggybacked.Delete(msg)
+			}
+		}()
+	}
+
+	for _, msg := range msgs {
+		if err := c.handleMessage(senderAddr, msg); err != nil {
+			c.notifyError(err)
+		}
+	}
+}
+
+// PiggybackedMessages returns the messages that came in the same datagram as msg,
+// excluding msg itself, in the order they appear in the datagram.
+//
+// This is expected to be called inside the HandlerFunc to handle the piggybacked
+// messages together, e.g., Create Session Response and Create Bearer Request.
+// It returns nil if msg is not piggybacked or after the HandlerFunc returned.
+func (c *Conn) PiggybackedMessages(msg messages.Message) []messages.Message {
+	v, ok := c.piggybacked.Load(msg)
+	if !ok {
+		return nil
+	}
+
+	var siblings []messages.Message
+	for _, m := range v.([]messages.Message) {
+		if m == msg {
+			continue
+		}
+		siblings = append(siblings, m)
+	}
+	return siblings
+}
+
 func (c *Conn) handleMessage(senderAddr net.Addr, msg messages.Message) error {
+	c.stats.messageReceived(msg.MessageType())
+	c.log().Debug("received message", msgFields(senderAddr, msg)...)
+
+	restarted, rtt, req := c.peer(senderAddr).received(msg)
+	if restarted {
+		c.log().Info("peer restarted", msgFields(senderAddr, msg)...)
+	}
+	if err := c.handleLoadControl(senderAddr, msg, restarted); err != nil {
+		c.notifyError(err)
+	}
+	if msg.MessageType() == messages.MsgTypeEchoResponse && rtt > 0 {
+		c.stats.echoRoundTrip(senderAddr, rtt)
+	}
+
+	if dup, err := c.handleRetransmission(senderAddr, msg); dup {
+		return err
+	}
+	// the request responded without the cache is handled again when retransmitted.
+	pending := false
+	defer func() {
+		if !pending && isInitialMessage(msg.MessageType()) {
+			c.responseCache().handled(senderAddr, msg)
+		}
+	}()
+
+	if !c.admit(senderAddr, msg) {
+		return nil
+	}
+
 	if c.validationEnabled {
 		if err := c.validate(senderAddr, msg); err != nil {
 			return err
 		}
+		if err := validateResponse(req, msg); err != nil {
+			return err
+		}
+	}
+	// the request is not the one msg responds to, which reaches here only without
+	// validation.
+	if req != nil && isResponseMessage(msg.MessageType()) && !isResponseTo(req.MessageType(), msg.MessageType()) {
+		req = nil
 	}
 
-	handle, ok := c.msgHandlerMap.load(msg.MessageType())
+	c.handleDataNotificationDelay(senderAddr, msg)
+	c.handleISR(senderAddr, msg)
+	if req != nil && isBearerRequest(req.MessageType()) {
+		if err := c.syncBearers(senderAddr, req, msg); err != nil {
+			c.notifyError(err)
+		}
+	}
+
+	subscribed := c.publish(senderAddr, msg)
+	if c.handleRejection(senderAddr, req, msg) {
+		c.recordMessage(nil, DirectionIncoming, senderAddr, msg)
+		return nil
+	}
+
+	handle, ok := c.handlerFor(senderAddr, msg)
 	if !ok {
+		if subscribed {
+			c.recordMessage(nil, DirectionIncoming, senderAddr, msg)
+			return nil
+		}
 		return &HandlerNotFoundError{MsgType: msg.MessageTypeName()}
 	}
-	if err := handle(c, senderAddr, msg); err != nil {
-		c.errCh <- err
+	handle = c.wrapHandler(handle)
+	c.recordMessage(nil, DirectionIncoming, senderAddr, msg)
+	if err := handle(c, senderAddr, msg); err == ErrPending {
+		pending = true
+	} else if err != nil {
+		c.notifyError(err)
 	}
 
 	return nil
 }
 
+// handlerFor returns the HandlerFunc for msg, preferring the one scoped to the
+// Session associated with the TEID in msg and senderAddr.
+func (c *Conn) handlerFor(senderAddr net.Addr, msg messages.Message) (HandlerFunc, bool) {
+	if teid := msg.TEID(); teid != 0 {
+		if sess, err := c.GetSessionByTEID(teid, senderAddr); err == nil {
+			if handle, ok := sess.handler(msg.MessageType()); ok {
+				return handle, true
+			}
+		}
+	}
+	return c.msgHandlerMap.load(msg.MessageType())
+}
+
+// SetErrorHandler registers the ErrorHandler to be called with the errors that occur
+// in the background process of Conn, including the ones returned from HandlerFuncs
+// and the failures in parsing the incoming messages.
+//
+// Once the ErrorHandler is set, the errors are no longer sent to the errCh given
+// when creating Conn, and the caller does not need to keep monitoring it.
+// Giving nil restores the errCh-based behavior.
+func (c *Conn) SetErrorHandler(fn ErrorHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.errHandler = fn
+}
+
+func (c *Conn) errorHandler() ErrorHandler {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.errHandler
+}
+
+// notifyError passes err to the ErrorHandler if registered, or to errCh otherwise.
+// If neither is available, err is just logged not to block the caller forever.
+func (c *Conn) notifyError(err error) {
+	if fn := c.errorHandler(); fn != nil {
+		fn(err)
+		return
+	}
+
+	if c.errCh == nil {
+		c.log().Error("error in background process", "error", err)
+		return
+	}
+	c.errCh <- err
+}
+
 // EnableValidation turns on automatic validation of incoming messages.
 // This is expected to be used only after DisableValidation() is used, as the validation
 // is enabled by default.
@@ -367,36 +793,82 @@ func (c *Conn) validate(senderAddr net.Addr, msg messages.Message) error {
 		}
 	}
 
+	// check if the message comes from the expected source port
+	if err := c.validateSourcePort(senderAddr); err != nil {
+		return err
+	}
+
 	// check if TEID is known or not
 	if teid := msg.TEID(); teid != 0 {
 		if _, err := c.GetSessionByTEID(teid, senderAddr); err != nil {
 			return &InvalidTEIDError{TEID: teid}
 		}
 	}
-	return nil
+
+	// check if mandatory IEs are present
+	return c.validateMandatoryIEs(senderAddr, msg)
 }
 
 // SendMessageTo sends a message to addr.
 // Unlike WriteTo, it sets the Sequence Number properly and returns the one
 // used in the message.
+//
+// The SequenceNumber is incremented per Peer(=addr), as it is required to be unique
+// only for the messages sent to the same endpoint.
 func (c *Conn) SendMessageTo(msg messages.Message, addr net.Addr) (uint32, error) {
-	seq := c.IncSequence()
+	seq, _, err := c.sendMessageTo(msg, addr, false)
+	return seq, err
+}
+
+// sendMessageTo sends a message to addr, and returns the copy of the bytes written
+// if keep is true, which can be used to retransmit the same message.
+func (c *Conn) sendMessageTo(msg messages.Message, addr net.Addr, keep bool) (uint32, []byte, error) {
+	peer := c.peer(addr)
+	seq := peer.incSequence()
 	msg.SetSequenceNumber(seq)
+	msg = c.withLocalLoadControl(msg, addr)
+	msg = c.withRecovery(msg, peer)
 
-	payload, err := messages.Marshal(msg)
+	bp, err := marshalBuffer(msg, c.ieOrderFor(msg.MessageType()))
 	if err != nil {
-		seq = c.DecSequence()
-		return seq, errors.Wrapf(err, "failed to send %T", msg)
+		seq = peer.decSequence()
+		return seq, nil, errors.Wrapf(err, "failed to send %T", msg)
 	}
 
-	if _, err := c.WriteTo(payload, addr); err != nil {
-		seq = c.DecSequence()
-		return seq, errors.Wrapf(err, "failed to send %T", msg)
+	var raw []byte
+	if keep {
+		raw = append([]byte{}, *bp...)
 	}
-	return seq, nil
+	_, err = c.writeTo(*bp, addr, nil, msg)
+	releaseBuffer(bp)
+	if err != nil {
+		peer.failed()
+		seq = peer.decSequence()
+		return seq, nil, errors.Wrapf(err, "failed to send %T", msg)
+	}
+	peer.sent(msg)
+	peer.recoveryNotified(msg)
+	c.stats.messageSent(msg.MessageType())
+	c.log().Debug("sent message", msgFields(addr, msg)...)
+	c.recordMessage(nil, DirectionOutgoing, addr, msg)
+
+	c.mu.Lock()
+	c.sequence = seq
+	c.mu.Unlock()
+
+	if ip, _ := splitAddr(addr); ip != "" {
+		if _, ok := c.knownPeerAddr(ip); !ok {
+			c.learnPeerAddr(addr)
+		}
+	}
+	return seq, raw, nil
 }
 
 // IncSequence increments the SequenceNumber associated with Conn.
+//
+// Deprecated: the SequenceNumbers of the requests are kept per Peer, and this does not
+// change the ones used in them. Use SetInitialSequenceNumber, or (*Peer)
+// SetInitialSequenceNumber for specific Peer, instead.
 func (c *Conn) IncSequence() uint32 {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -411,6 +883,10 @@ func (c *Conn) IncSequence() uint32 {
 }
 
 // DecSequence decrements the SequenceNumber associated with Conn.
+//
+// Deprecated: the SequenceNumbers of the requests are kept per Peer, and this does not
+// change the ones used in them. Use SetInitialSequenceNumber, or (*Peer)
+// SetInitialSequenceNumber for specific Peer, instead.
 func (c *Conn) DecSequence() uint32 {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -419,6 +895,37 @@ func (c *Conn) DecSequence() uint32 {
 	return c.sequence
 }
 
+// SetInitialSequenceNumber sets the SequenceNumber to be used in the next request sent
+// from Conn, and the following requests use the ones incremented from it.
+//
+// This is useful to avoid collisions with the outstanding transactions at the peers
+// when the node restarts quickly. Only the lower 3 octets of seq are used, as the
+// SequenceNumber is 3-octet long.
+//
+// This applies to all the Peers of Conn, including the ones created later. To set for
+// specific Peer, use (*Peer) SetInitialSequenceNumber instead.
+func (c *Conn) SetInitialSequenceNumber(seq uint32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// c.sequence holds the last used one.
+	c.sequence = (seq - 1) & 0xffffff
+	for _, p := range c.peers {
+		p.SetInitialSequenceNumber(seq)
+	}
+}
+
+// RandomizeSequenceNumber sets the SequenceNumber to be used in the next request sent
+// from Conn to a random value, and returns it.
+//
+// See SetInitialSequenceNumber for the detail.
+func (c *Conn) RandomizeSequenceNumber() uint32 {
+	// SequenceNumber is 3-octet long
+	seq := sequence.Random(0xffffff)
+	c.SetInitialSequenceNumber(seq)
+	return seq
+}
+
 // SequenceNumber returns the current(=last used) SequenceNumber associated with Conn.
 func (c *Conn) SequenceNumber() uint32 {
 	c.mu.Lock()
@@ -428,8 +935,10 @@ func (c *Conn) SequenceNumber() uint32 {
 }
 
 // EchoRequest sends a EchoRequest.
+//
+// The IEs set with SetEchoIEs are added after the Recovery IE.
 func (c *Conn) EchoRequest(raddr net.Addr) (uint32, error) {
-	msg := messages.NewEchoRequest(0, ies.NewRecovery(c.RestartCounter))
+	msg := messages.NewEchoRequest(0, append([]*ies.IE{ies.NewRecovery(c.RestartCounter)}, c.getEchoIEs()...)...)
 
 	seq, err := c.SendMessageTo(msg, raddr)
 	if err != nil {
@@ -439,8 +948,10 @@ func (c *Conn) EchoRequest(raddr net.Addr) (uint32, error) {
 }
 
 // EchoResponse sends a EchoResponse in response to the EchoRequest.
+//
+// The IEs set with SetEchoIEs are added after the Recovery IE.
 func (c *Conn) EchoResponse(raddr net.Addr, req messages.Message) error {
-	res := messages.NewEchoResponse(0, ies.NewRecovery(c.RestartCounter))
+	res := messages.NewEchoResponse(0, append([]*ies.IE{ies.NewRecovery(c.RestartCounter)}, c.getEchoIEs()...)...)
 
 	if err := c.RespondTo(raddr, req, res); err != nil {
 		return err
@@ -448,6 +959,26 @@ func (c *Conn) EchoResponse(raddr net.Addr, req messages.Message) error {
 	return nil
 }
 
+// SetEchoIEs sets the IEs to be added to the Echo Request and Response sent by Conn,
+// such as Sending Node Features and Private Extension, replacing the existing ones.
+// Giving nothing removes them.
+//
+// The features advertised by the peers in the same way can be retrieved with
+// Peer.NodeFeatures.
+func (c *Conn) SetEchoIEs(ie ...*ies.IE) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.echoIEs = ie
+}
+
+func (c *Conn) getEchoIEs() []*ies.IE {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.echoIEs
+}
+
 // VersionNotSupportedIndication sends VersionNotSupportedIndication message
 // in response to any kind of message.Message.
 func (c *Conn) VersionNotSupportedIndication(raddr net.Addr, req messages.Message) error {
@@ -459,18 +990,17 @@ func (c *Conn) VersionNotSupportedIndication(raddr net.Addr, req messages.Messag
 	return nil
 }
 
-// CreateSession sends a CreateSessionRequest and stores information given with IE
-// in the Session returned.
+// NewSessionFromIEs creates a new Session with the values in the IEs given, which are
+// typically the ones in Create Session Request.
 //
-// By creating a Session with this method, a Bearer named "default" is also created
-// to be used as default bearer. The default bearer can be retrieved by using
-// (*Session) GetDefaultBearer() or (*Session) LookupBearerByName("default").
-//
-// Note that this method doesn't care IEs given are sufficient or not, as the required IE
-// varies much depending on the context in which the Create Session Request is used.
-func (c *Conn) CreateSession(raddr net.Addr, ie ...*ies.IE) (*Session, uint32, error) {
-	// retrieve values from IEs given.
-	sess := NewSession(raddr, &Subscriber{Location: &Location{}})
+// The IMSI, MSISDN, MEI, Serving Network and RAT Type are set to the Subscriber, and
+// the APN and the EBI, Bearer QoS and Charging ID in the Bearer Context with instance
+// 0 are set to the default Bearer. The Charging Characteristics and APN-AMBR are set
+// to the Session. The F-TEIDs, including the ones in the Bearer Context, are added to
+// the Session.
+func NewSessionFromIEs(peerAddr net.Addr, ie ...*ies.IE) (*Session, error) {
+	sub := &Subscriber{Location: &Location{}}
+	sess := NewSession(peerAddr, sub)
 	br := sess.GetDefaultBearer()
 	var err error
 	for _, i := range ie {
@@ -479,47 +1009,62 @@ func (c *Conn) CreateSession(raddr net.Addr, ie ...*ies.IE) (*Session, uint32, e
 		}
 		switch i.Type {
 		case ies.IMSI:
-			sess.IMSI, err = i.IMSI()
+			sub.IMSI, err = i.IMSI()
 			if err != nil {
-				return nil, 0, err
+				return nil, err
 			}
 		case ies.MSISDN:
-			sess.MSISDN, err = i.MSISDN()
+			sub.MSISDN, err = i.MSISDN()
 			if err != nil {
-				return nil, 0, err
+				return nil, err
 			}
 		case ies.MobileEquipmentIdentity:
-			sess.IMEI, err = i.MobileEquipmentIdentity()
+			sub.IMEI, err = i.MobileEquipmentIdentity()
 			if err != nil {
-				return nil, 0, err
+				return nil, err
 			}
 		case ies.ServingNetwork:
-			sess.MCC, err = i.MCC()
+			sub.MCC, err = i.MCC()
 			if err != nil {
-				return nil, 0, err
+				return nil, err
 			}
-			sess.MNC, err = i.MNC()
+			sub.MNC, err = i.MNC()
 			if err != nil {
-				return nil, 0, err
+				return nil, err
 			}
 		case ies.AccessPointName:
-			br.APN, err = i.AccessPointName()
+			apn, err := i.AccessPointName()
 			if err != nil {
-				return nil, 0, err
+				return nil, err
 			}
+			br.SetAPN(apn)
 		case ies.RATType:
-			sess.RATType, err = i.RATType()
+			sub.RATType, err = i.RATType()
+			if err != nil {
+				return nil, err
+			}
+		case ies.ChargingCharacteristics:
+			sess.chargingCharacteristics, err = i.ChargingCharacteristics()
+			if err != nil {
+				return nil, err
+			}
+		case ies.AggregateMaximumBitRate:
+			sess.apnAMBRUp, err = i.AggregateMaximumBitRateUp()
 			if err != nil {
-				return nil, 0, err
+				return nil, err
+			}
+			sess.apnAMBRDown, err = i.AggregateMaximumBitRateDown()
+			if err != nil {
+				return nil, err
 			}
 		case ies.FullyQualifiedTEID:
 			it, err := i.InterfaceType()
 			if err != nil {
-				return nil, 0, err
+				return nil, err
 			}
 			teid, err := i.TEID()
 			if err != nil {
-				return nil, 0, err
+				return nil, err
 			}
 			sess.AddTEID(it, teid)
 		case ies.BearerContext:
@@ -528,48 +1073,33 @@ func (c *Conn) CreateSession(raddr net.Addr, ie ...*ies.IE) (*Session, uint32, e
 				for _, child := range i.ChildIEs {
 					switch child.Type {
 					case ies.EPSBearerID:
-						br.EBI, err = child.EPSBearerID()
+						ebi, err := child.EPSBearerID()
 						if err != nil {
-							return nil, 0, err
+							return nil, err
 						}
+						br.SetEBI(ebi)
 					case ies.BearerQoS:
-						br.PL, err = child.PriorityLevel()
-						if err != nil {
-							return nil, 0, err
-						}
-						br.QCI, err = child.QCILabel()
-						if err != nil {
-							return nil, 0, err
-						}
-						br.PCI = child.PreemptionCapability()
-						br.PVI = child.PreemptionVulnerability()
-
-						br.MBRUL, err = child.MBRForUplink()
+						qos, err := newQoSProfile(child)
 						if err != nil {
-							return nil, 0, err
-						}
-						br.MBRDL, err = child.MBRForDownlink()
-						if err != nil {
-							return nil, 0, err
-						}
-						br.GBRUL, err = child.GBRForUplink()
-						if err != nil {
-							return nil, 0, err
-						}
-						br.GBRDL, err = child.GBRForUplink()
-						if err != nil {
-							return nil, 0, err
+							return nil, err
 						}
+						br.SetQoSProfile(qos)
 					case ies.FullyQualifiedTEID:
 						it, err := child.InterfaceType()
 						if err != nil {
-							return nil, 0, err
+							return nil, err
 						}
 						teid, err := child.TEID()
 						if err != nil {
-							return nil, 0, err
+							return nil, err
 						}
 						sess.AddTEID(it, teid)
+					case ies.ChargingID:
+						id, err := child.ChargingID()
+						if err != nil {
+							return nil, err
+						}
+						br.SetChargingID(id)
 					case ies.BearerTFT:
 						// XXX - do nothing for BearerTFT?
 					}
@@ -580,6 +1110,25 @@ func (c *Conn) CreateSession(raddr net.Addr, ie ...*ies.IE) (*Session, uint32, e
 		}
 	}
 
+	return sess, nil
+}
+
+// CreateSession sends a CreateSessionRequest and stores information given with IE
+// in the Session returned.
+//
+// By creating a Session with this method, a Bearer named "default" is also created
+// to be used as default bearer. The default bearer can be retrieved by using
+// (*Session) GetDefaultBearer() or (*Session) LookupBearerByName("default").
+//
+// Note that this method doesn't care IEs given are sufficient or not, as the required IE
+// varies much depending on the context in which the Create Session Request is used.
+func (c *Conn) CreateSession(raddr net.Addr, ie ...*ies.IE) (*Session, uint32, error) {
+	// retrieve values from IEs given.
+	sess, err := NewSessionFromIEs(raddr, ie...)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	// set IEs into CreateSessionRequest.
 	msg := messages.NewCreateSessionRequest(0, 0, ie...)
 
@@ -587,6 +1136,11 @@ func (c *Conn) CreateSession(raddr net.Addr, ie ...*ies.IE) (*Session, uint32, e
 	if err != nil {
 		return nil, 0, err
 	}
+
+	if size := c.sessionHistorySize(); size > 0 {
+		sess.EnableHistory(size)
+		sess.RecordMessage(DirectionOutgoing, raddr, msg)
+	}
 	return sess, seq, nil
 }
 
@@ -599,7 +1153,7 @@ func (c *Conn) DeleteSession(teid uint32, raddr net.Addr, ie ...*ies.IE) (uint32
 
 	msg := messages.NewDeleteSessionRequest(teid, 0, ie...)
 
-	seq, err := c.SendMessageTo(msg, sess.peerAddr)
+	seq, err := c.SendMessageTo(msg, sess.PeerAddr())
 	if err != nil {
 		return 0, err
 	}
@@ -615,7 +1169,7 @@ func (c *Conn) ModifyBearer(teid uint32, raddr net.Addr, ie ...*ies.IE) (uint32,
 
 	msg := messages.NewModifyBearerRequest(teid, 0, ie...)
 
-	seq, err := c.SendMessageTo(msg, sess.peerAddr)
+	seq, err := c.SendMessageTo(msg, sess.PeerAddr())
 	if err != nil {
 		return 0, err
 	}
@@ -631,7 +1185,7 @@ func (c *Conn) DeleteBearer(teid uint32, raddr net.Addr, ie ...*ies.IE) (uint32,
 
 	msg := messages.NewDeleteBearerRequest(teid, 0, ie...)
 
-	seq, err := c.SendMessageTo(msg, sess.peerAddr)
+	seq, err := c.SendMessageTo(msg, sess.PeerAddr())
 	if err != nil {
 		return 0, err
 	}
@@ -644,27 +1198,34 @@ func (c *Conn) DeleteBearer(teid uint32, raddr net.Addr, ie ...*ies.IE) (uint32,
 // This exists to make it easier to handle SequenceNumber.
 func (c *Conn) RespondTo(raddr net.Addr, received, toBeSent messages.Message) error {
 	toBeSent.SetSequenceNumber(received.Sequence())
-	b := make([]byte, toBeSent.MarshalLen())
-
-	if err := toBeSent.MarshalTo(b); err != nil {
+	peer := c.peer(raddr)
+	toBeSent = c.withLocalLoadControl(toBeSent, raddr)
+	toBeSent = c.withRecovery(toBeSent, peer)
+	bp, err := marshalBuffer(toBeSent, c.ieOrderFor(toBeSent.MessageType()))
+	if err != nil {
 		return err
 	}
+	defer releaseBuffer(bp)
 
-	if _, err := c.WriteTo(b, raddr); err != nil {
+	if _, err := c.writeTo(*bp, raddr, received, toBeSent); err != nil {
 		return err
 	}
+
+	peer.recoveryNotified(toBeSent)
+	c.responseCache().store(raddr, received, *bp)
+	c.stats.messageSent(toBeSent.MessageType())
+	c.log().Debug("sent message", msgFields(raddr, toBeSent)...)
+	c.recordMessage(nil, DirectionOutgoing, raddr, toBeSent)
 	return nil
 }
 
 // GetSessionByTEID returns Session looked up by TEID and sender of the message.
 func (c *Conn) GetSessionByTEID(teid uint32, peer net.Addr) (*Session, error) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
 	var session *Session
-	for _, sess := range c.Sessions {
-		if peer.String() != sess.peerAddrString {
-			continue
+	peerStr := addrString(peer)
+	c.RangeSessions(func(sess *Session) bool {
+		if peerStr != sess.peerAddrStr() {
+			return true
 		}
 
 		sess.teidMap.rangeWithFunc(func(i, t interface{}) bool {
@@ -674,23 +1235,29 @@ func (c *Conn) GetSessionByTEID(teid uint32, peer net.Addr) (*Session, error) {
 			}
 			return true
 		})
-		if session != nil {
-			return session, nil
-		}
+		return session == nil
+	})
+	if session != nil {
+		return session, nil
 	}
 
 	return nil, &InvalidTEIDError{TEID: teid}
 }
 
 // GetSessionByIMSI returns Session looked up by IMSI.
+// If the UE has multiple PDN connections, it returns the one added first. Use GetUE
+// to get all of them.
 func (c *Conn) GetSessionByIMSI(imsi string) (*Session, error) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	for _, sess := range c.Sessions {
-		if imsi == sess.IMSI {
-			return sess, nil
+	var session *Session
+	c.RangeSessions(func(sess *Session) bool {
+		if imsi == sess.GetIMSI() {
+			session = sess
+			return false
 		}
+		return true
+	})
+	if session != nil {
+		return session, nil
 	}
 
 	return nil, &UnknownIMSIError{IMSI: imsi}
@@ -703,88 +1270,180 @@ func (c *Conn) GetIMSIByTEID(teid uint32, peer net.Addr) (string, error) {
 		return "", err
 	}
 
-	return sess.IMSI, nil
+	return sess.GetIMSI(), nil
 }
 
-// AddSession adds a session to c.Sessions.
-// If Session with the same IMSI already exists, it removes the old one and
-// stores the given one.
-func (c *Conn) AddSession(session *Session) {
+// GetSessionsByAPN returns the Sessions whose default Bearer has the APN given. The
+// APN is compared case-insensitively, as it is in the DNS.
+func (c *Conn) GetSessionsByAPN(apn string) []*Session {
+	var sessions []*Session
+	c.RangeSessions(func(sess *Session) bool {
+		if br := sess.GetDefaultBearer(); br != nil && strings.EqualFold(br.GetAPN(), apn) {
+			sessions = append(sessions, sess)
+		}
+		return true
+	})
+	return sessions
+}
+
+// GetSessionsByPeer returns the Sessions associated with the peer node at addr.
+func (c *Conn) GetSessionsByPeer(addr net.Addr) []*Session {
+	var sessions []*Session
+	peer := addrString(addr)
+	c.RangeSessions(func(sess *Session) bool {
+		if sess.peerAddrStr() == peer {
+			sessions = append(sessions, sess)
+		}
+		return true
+	})
+	return sessions
+}
+
+// RangeSessions calls fn for each Session in Conn in the order they are added, until
+// fn returns false.
+//
+// fn is called with the snapshot of the Sessions taken under the lock, so it is safe
+// to call the methods of Conn that add or remove the Sessions in fn. The Sessions
+// added during the iteration are not visited, and the ones removed may still be.
+func (c *Conn) RangeSessions(fn func(sess *Session) bool) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	sessions := make([]*Session, len(c.Sessions))
+	copy(sessions, c.Sessions)
+	c.mu.Unlock()
 
-	// TODO: any smarter way?
-	if len(c.Sessions) == 0 {
-		c.Sessions = []*Session{session}
-		return
+	for _, sess := range sessions {
+		if !fn(sess) {
+			return
+		}
+	}
+}
+
+// AddSession adds a session to c.Sessions.
+// If Session of the same PDN connection already exists, i.e., the one with the same
+// IMSI and the default Bearer with the same EBI, it removes the old one and stores
+// the given one. The Sessions with the same IMSI and the different default Bearers
+// are kept as the multiple PDN connections of the UE.
+func (c *Conn) AddSession(session *Session) {
+	if size := c.sessionHistorySize(); size > 0 && session.sessionHistory() == nil {
+		session.EnableHistory(size)
 	}
 
+	imsi := session.GetIMSI()
+	c.mu.Lock()
 	var (
 		newSessions []*Session
-		exists      bool
+		replaced    *Session
+		registered  = true
 	)
 	for _, oldSession := range c.Sessions {
-		if session.IMSI == oldSession.IMSI {
-			exists = true
-			newSessions = append(newSessions, session)
-			continue
+		if imsi == oldSession.GetIMSI() {
+			registered = false
+			if replaced == nil && isSamePDNConnection(session, oldSession) {
+				replaced = oldSession
+				newSessions = append(newSessions, session)
+				continue
+			}
 		}
 		newSessions = append(newSessions, oldSession)
 	}
-	if !exists {
+	if replaced == nil {
 		newSessions = append(newSessions, session)
 	}
-
 	c.Sessions = newSessions
+	c.mu.Unlock()
+
+	if replaced == session {
+		return
+	}
+	if replaced != nil {
+		c.sessionDeleted(replaced)
+		c.notifyUEEvent(imsi, replaced, UEEventSessionRemoved)
+	}
+	c.sessionCreated(session)
+	if registered {
+		c.notifyUEEvent(imsi, session, UEEventRegistered)
+	}
+	c.notifyUEEvent(imsi, session, UEEventSessionAdded)
 }
 
 // RemoveSession removes a session from c.Session.
-// The Session is identified by IMSI.
+// The Session is identified by IMSI and the EBI of the default Bearer, and the other
+// PDN connections of the same UE are kept.
 func (c *Conn) RemoveSession(session *Session) {
+	imsi := session.GetIMSI()
 	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	var newSessions []*Session
+	var (
+		newSessions []*Session
+		removed     []*Session
+	)
 	for _, sess := range c.Sessions {
-		if session.IMSI == sess.IMSI {
+		if imsi == sess.GetIMSI() && isSamePDNConnection(session, sess) {
+			removed = append(removed, sess)
 			continue
 		}
 		newSessions = append(newSessions, sess)
 	}
-
 	c.Sessions = newSessions
+	c.mu.Unlock()
+
+	c.releaseTEIDs(removed)
+	c.notifyRemoved(imsi, removed)
 }
 
-// RemoveSessionByIMSI removes a session looked up by IMSI.
+// RemoveSessionByIMSI removes all the sessions looked up by IMSI.
 func (c *Conn) RemoveSessionByIMSI(imsi string) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	var newSessions []*Session
+	var (
+		newSessions []*Session
+		removed     []*Session
+	)
 	for _, sess := range c.Sessions {
-		if imsi == sess.IMSI {
+		if imsi == sess.GetIMSI() {
+			removed = append(removed, sess)
 			continue
 		}
 		newSessions = append(newSessions, sess)
 	}
-
 	c.Sessions = newSessions
+	c.mu.Unlock()
+
+	c.releaseTEIDs(removed)
+	c.notifyRemoved(imsi, removed)
 }
 
 // NewFTEID creates a new F-TEID with random TEID value that is unique within Conn.
 // If there's a lot of Session on the Conn, it may take a long time to find unique one.
+//
+// If the TEIDAllocator is set with SetTEIDAllocator, the TEID is allocated by it
+// instead, and it is 0 if the allocation fails. Use AllocateFTEID to get the error.
 func (c *Conn) NewFTEID(ifType uint8, v4, v6 string) (fteidIE *ies.IE) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	fteid, err := c.AllocateFTEID(ifType, v4, v6)
+	if err != nil {
+		return ies.NewFullyQualifiedTEID(ifType, 0, v4, v6)
+	}
+	return fteid
+}
+
+// AllocateFTEID works the same as NewFTEID, but returns the error if the TEIDAllocator
+// fails to allocate the TEID, e.g., with ErrTEIDExhausted.
+func (c *Conn) AllocateFTEID(ifType uint8, v4, v6 string) (*ies.IE, error) {
+	if a := c.getTEIDAllocator(); a != nil {
+		teid, err := a.Allocate(ifType)
+		if err != nil {
+			return nil, err
+		}
+		return ies.NewFullyQualifiedTEID(ifType, teid, v4, v6), nil
+	}
 
 	var teids []uint32
-	for _, sess := range c.Sessions {
+	c.RangeSessions(func(sess *Session) bool {
 		if teid, ok := sess.teidMap.load(ifType); ok {
 			teids = append(teids, teid)
 		}
-	}
+		return true
+	})
 
-	return ies.NewFullyQualifiedTEID(ifType, generateUniqueUint32(teids), v4, v6)
+	return ies.NewFullyQualifiedTEID(ifType, generateUniqueUint32(teids), v4, v6), nil
 }
 
 func generateUniqueUint32(vals []uint32) uint32 {
@@ -807,15 +1466,13 @@ func generateUniqueUint32(vals []uint32) uint32 {
 //
 // This may have impact on performance in case of large number of Session exists.
 func (c *Conn) SessionCount() int {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
 	var count int
-	for _, sess := range c.Sessions {
+	c.RangeSessions(func(sess *Session) bool {
 		if sess.IsActive() {
 			count++
 		}
-	}
+		return true
+	})
 	return count
 }
 
@@ -824,14 +1481,12 @@ func (c *Conn) SessionCount() int {
 // This may have impact on performance in case of large number of Session and
 // Bearer exist.
 func (c *Conn) BearerCount() int {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
 	var count int
-	for _, sess := range c.Sessions {
+	c.RangeSessions(func(sess *Session) bool {
 		if sess.IsActive() {
 			count += sess.BearerCount()
 		}
-	}
+		return true
+	})
 	return count
 }
@@ -269,6 +269,12 @@ const (
 	RATTypeNR
 )
 
+// Secondary RAT Type definitions.
+const (
+	SecondaryRATTypeNR uint8 = iota
+	SecondaryRATTypeUnlicensedSpectrum
+)
+
 // SelectionMode definitions.
 const (
 	SelectionModeMSorNetworkProvidedAPNSubscribedVerified uint8 = iota
@@ -295,3 +301,20 @@ const (
 	DaylightSavingPlusOneHour
 	DaylightSavingPlusTwoHours
 )
+
+// Node Features definitions.
+const (
+	NodeFeaturePRN   uint8 = 0x01
+	NodeFeatureMABR  uint8 = 0x02
+	NodeFeatureNTSR  uint8 = 0x04
+	NodeFeatureCIOT  uint8 = 0x08
+	NodeFeatureS1UN  uint8 = 0x10
+	NodeFeatureETH   uint8 = 0x20
+	NodeFeatureMTEDT uint8 = 0x40
+)
+
+// Registered UDP port definitions.
+const (
+	GTPCPort = 2123
+	GTPUPort = 2152
+)
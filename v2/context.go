@@ -0,0 +1,130 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// ContextHandlerFunc is a handler for specific GTPv2-C message that receives the
+// context.Context of the message.
+//
+// The Conn, the address of the sender, the local address and the time the message
+// arrived can be retrieved from ctx with ConnFromContext, SenderAddrFromContext,
+// LocalAddrFromContext and ArrivalTimeFromContext. ctx has the deadline if the message has the Origination
+// Time Stamp and Maximum Wait Time IEs, after which the response is no longer
+// meaningful to the peer. ctx is canceled when the handler returns, so the work
+// to be continued with ErrPending should not depend on it.
+type ContextHandlerFunc func(ctx context.Context, msg messages.Message) error
+
+type contextKey int
+
+const (
+	connKey contextKey = iota
+	senderAddrKey
+	localAddrKey
+	arrivalTimeKey
+)
+
+// WithContext converts the ContextHandlerFunc into HandlerFunc, which can be used
+// wherever HandlerFunc is accepted, e.g., (*Session) AddHandler.
+func WithContext(fn ContextHandlerFunc) HandlerFunc {
+	return func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		ctx, cancel := c.messageContext(senderAddr, msg)
+		defer cancel()
+		return fn(ctx, msg)
+	}
+}
+
+// AddContextHandler adds the ContextHandlerFunc for the specific message type.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) AddContextHandler(msgType uint8, fn ContextHandlerFunc) {
+	c.AddHandler(msgType, WithContext(fn))
+}
+
+// ConnFromContext returns the Conn that received the message handled with ctx.
+func ConnFromContext(ctx context.Context) (*Conn, bool) {
+	c, ok := ctx.Value(connKey).(*Conn)
+	return c, ok
+}
+
+// SenderAddrFromContext returns the address of the sender of the message handled
+// with ctx.
+func SenderAddrFromContext(ctx context.Context) (net.Addr, bool) {
+	addr, ok := ctx.Value(senderAddrKey).(net.Addr)
+	return addr, ok
+}
+
+// LocalAddrFromContext returns the local address of Conn that the message handled with
+// ctx arrived on, which matters if Conn has multiple local addresses added with
+// AddLocalAddr.
+func LocalAddrFromContext(ctx context.Context) (net.Addr, bool) {
+	addr, ok := ctx.Value(localAddrKey).(net.Addr)
+	return addr, ok
+}
+
+// ArrivalTimeFromContext returns the time the message handled with ctx arrived at
+// Conn.
+func ArrivalTimeFromContext(ctx context.Context) (time.Time, bool) {
+	t, ok := ctx.Value(arrivalTimeKey).(time.Time)
+	return t, ok
+}
+
+// MessageDeadline returns the time by which the response to msg should be sent,
+// which is the Origination Time Stamp plus the Maximum Wait Time in msg.
+//
+// It returns false if msg does not have both of them.
+func MessageDeadline(msg messages.Message) (time.Time, bool) {
+	tsIE, err := messages.FindIE(msg, ies.MillisecondTimeStamp, 0)
+	if err != nil {
+		return time.Time{}, false
+	}
+	waitIE, err := messages.FindIE(msg, ies.IntegerNumber, 0)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	ts, err := tsIE.MillisecondTimeStamp()
+	if err != nil {
+		return time.Time{}, false
+	}
+	wait, err := waitIE.MaximumWaitTime()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return ts.Add(wait), true
+}
+
+func (c *Conn) messageContext(senderAddr net.Addr, msg messages.Message) (context.Context, context.CancelFunc) {
+	arrivedAt, ok := c.arrivalTime(msg)
+	if !ok {
+		arrivedAt = time.Now()
+	}
+
+	ctx := context.WithValue(context.Background(), connKey, c)
+	ctx = context.WithValue(ctx, senderAddrKey, senderAddr)
+	ctx = context.WithValue(ctx, localAddrKey, c.ReceivedOn(msg))
+	ctx = context.WithValue(ctx, arrivalTimeKey, arrivedAt)
+	if deadline, ok := MessageDeadline(msg); ok {
+		return context.WithDeadline(ctx, deadline)
+	}
+	return context.WithCancel(ctx)
+}
+
+// arrivalTime returns the time msg arrived, which is available only while it is
+// being handled.
+func (c *Conn) arrivalTime(msg messages.Message) (time.Time, bool) {
+	v, ok := c.arrivals.Load(msg)
+	if !ok {
+		return time.Time{}, false
+	}
+	return v.(time.Time), true
+}
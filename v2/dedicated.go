@@ -0,0 +1,450 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// DedicatedBearerName returns the name of the dedicated Bearer with EBI given, which
+// is used to register the Bearer in Session by the helpers in this file.
+func DedicatedBearerName(ebi uint8) string {
+	return fmt.Sprintf("dedicated-%d", ebi)
+}
+
+// AddDedicatedBearers adds the Bearers built from the Bearer Context IEs given to
+// Session, and returns them.
+//
+// The EBI, Bearer QoS and Charging ID in each Bearer Context are set to the Bearer,
+// and the F-TEIDs are added to Session. The Bearer Contexts with the Cause that is
+// not an acceptance, or without EBI, are ignored. A Bearer already registered with
+// the same EBI is replaced.
+//
+// This is called automatically by (*Conn) CreateBearerResponse, and when the
+// Create Bearer Response to the request sent with Conn is received.
+func (s *Session) AddDedicatedBearers(bearerContexts ...*ies.IE) ([]*Bearer, error) {
+	var brs []*Bearer
+	for _, bc := range bearerContexts {
+		if bc == nil || bc.Type != ies.BearerContext {
+			continue
+		}
+		if !isAccepted(bc.ChildIEs...) {
+			continue
+		}
+
+		br := &Bearer{QoSProfile: &QoSProfile{}}
+		if err := s.updateBearerWithContext(br, bc); err != nil {
+			return nil, err
+		}
+		ebi := br.GetEBI()
+		if ebi == 0 {
+			continue
+		}
+
+		s.RemoveBearerByEBI(ebi)
+		s.AddBearer(DedicatedBearerName(ebi), br)
+		brs = append(brs, br)
+	}
+	return brs, nil
+}
+
+// UpdateBearers updates the Bearers in Session with the Bearer Context IEs given.
+//
+// The Bearers are looked up by the EBI in each Bearer Context, and the Bearer QoS
+// and Charging ID are updated if present. It returns BearerNotFoundError if any of
+// the Bearers cannot be found.
+//
+// This is called automatically by (*Conn) UpdateBearerResponse, and when the
+// Update Bearer Response to the request sent with Conn is received.
+func (s *Session) UpdateBearers(bearerContexts ...*ies.IE) error {
+	for _, bc := range bearerContexts {
+		if bc == nil || bc.Type != ies.BearerContext {
+			continue
+		}
+
+		var ebi uint8
+		for _, child := range bc.ChildIEs {
+			if child.Type == ies.EPSBearerID {
+				ebi = child.MustEPSBearerID()
+			}
+		}
+		br, err := s.LookupBearerByEBI(ebi)
+		if err != nil {
+			return err
+		}
+		if err := s.updateBearerWithContext(br, bc); err != nil {
+			return err
+		}
+		s.notifyBearer(br, BearerChangeUpdated)
+	}
+	return nil
+}
+
+// updateBearerWithContext sets the values in the Bearer Context IE to br.
+func (s *Session) updateBearerWithContext(br *Bearer, bc *ies.IE) error {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+
+	var err error
+	for _, child := range bc.ChildIEs {
+		switch child.Type {
+		case ies.EPSBearerID:
+			br.EBI, err = child.EPSBearerID()
+			if err != nil {
+				return err
+			}
+		case ies.BearerQoS:
+			br.QoSProfile, err = newQoSProfile(child)
+			if err != nil {
+				return err
+			}
+		case ies.ChargingID:
+			br.ChargingID, err = child.ChargingID()
+			if err != nil {
+				return err
+			}
+		case ies.FullyQualifiedTEID:
+			it, err := child.InterfaceType()
+			if err != nil {
+				return err
+			}
+			teid, err := child.TEID()
+			if err != nil {
+				return err
+			}
+			s.AddTEID(it, teid)
+		}
+	}
+	return nil
+}
+
+// AddDedicatedBearer adds br to Session as the dedicated Bearer, replacing the one
+// with the same EBI if any.
+//
+// The EBI of br must be set, as the dedicated Bearers are looked up by it.
+func (s *Session) AddDedicatedBearer(br *Bearer) error {
+	if br == nil {
+		return &RequiredParameterMissingError{"EBI", "dedicated Bearer must have EBI set"}
+	}
+	ebi := br.GetEBI()
+	if ebi == 0 {
+		return &RequiredParameterMissingError{"EBI", "dedicated Bearer must have EBI set"}
+	}
+
+	s.RemoveBearerByEBI(ebi)
+	s.AddBearer(DedicatedBearerName(ebi), br)
+	return nil
+}
+
+// DedicatedBearers returns the Bearers in Session other than the default one, in
+// ascending order of EBI.
+func (s *Session) DedicatedBearers() []*Bearer {
+	def := s.GetDefaultBearer()
+
+	var brs []*Bearer
+	for _, br := range s.Bearers() {
+		if br == def {
+			continue
+		}
+		brs = append(brs, br)
+	}
+	sort.Slice(brs, func(i, j int) bool { return brs[i].GetEBI() < brs[j].GetEBI() })
+	return brs
+}
+
+// isBearerRequest reports whether the Bearers or the APN-AMBR in the Session are
+// updated with the response to the message of msgType.
+func isBearerRequest(msgType uint8) bool {
+	switch msgType {
+	case messages.MsgTypeCreateSessionRequest,
+		messages.MsgTypeModifyBearerRequest,
+		messages.MsgTypeCreateBearerRequest,
+		messages.MsgTypeUpdateBearerRequest,
+		messages.MsgTypeDeleteBearerRequest:
+		return true
+	default:
+		return false
+	}
+}
+
+// syncBearers updates the Bearers in the Session with rsp received from senderAddr
+// in response to req sent with Conn, and the F-TEIDs in it if TEID learning is
+// enabled. The response without the Session known to Conn is ignored.
+func (c *Conn) syncBearers(senderAddr net.Addr, req, rsp messages.Message) error {
+	sess, err := c.GetSessionByTEID(rsp.TEID(), senderAddr)
+	if err != nil {
+		return nil
+	}
+	if err := sess.applyBearerResponse(req, rsp); err != nil {
+		return err
+	}
+	if !c.isTEIDLearningEnabled() {
+		return nil
+	}
+	return sess.LearnTEIDs(rsp)
+}
+
+// applyBearerResponse updates the Bearers in Session with req and rsp, which are
+// the Create, Update or Delete Bearer Request and Response. Nothing is done if rsp
+// is not an acceptance, and the Bearer Contexts in rsp that are not accepted are
+// ignored.
+//
+// The APN-AMBR of Session is also updated with the Create Session Response, and the
+// Modify or Update Bearer Request that is accepted. The Charging IDs in the Create
+// Session Response are set to the Bearers.
+//
+// The responder calls this before sending rsp, as the peer may send the next request
+// on the Bearers as soon as it receives rsp.
+func (s *Session) applyBearerResponse(req, rsp messages.Message) error {
+	rspIEs, err := messageIEs(rsp)
+	if err != nil {
+		return err
+	}
+	if !isAccepted(rspIEs...) {
+		return nil
+	}
+	reqIEs, err := messageIEs(req)
+	if err != nil {
+		return err
+	}
+	reqBCs, rspBCs := bearerContextsIn(reqIEs), bearerContextsIn(rspIEs)
+
+	switch {
+	case req.MessageType() == messages.MsgTypeCreateSessionRequest && rsp.MessageType() == messages.MsgTypeCreateSessionResponse:
+		// the P-GW may modify the APN-AMBR requested.
+		if err := s.updateAPNAMBR(reqIEs...); err != nil {
+			return err
+		}
+		if err := s.updateAPNAMBR(rspIEs...); err != nil {
+			return err
+		}
+		return s.updateChargingIDs(rspBCs...)
+	case req.MessageType() == messages.MsgTypeModifyBearerRequest && rsp.MessageType() == messages.MsgTypeModifyBearerResponse:
+		return s.updateAPNAMBR(reqIEs...)
+	case req.MessageType() == messages.MsgTypeCreateBearerRequest && rsp.MessageType() == messages.MsgTypeCreateBearerResponse:
+		// the Bearer Contexts in the response are in the same order as the request,
+		// and the EBIs are assigned in the response.
+		for i, bc := range rspBCs {
+			if !isAccepted(bc.ChildIEs...) {
+				continue
+			}
+			var children []*ies.IE
+			if i < len(reqBCs) {
+				children = append(children, reqBCs[i].ChildIEs...)
+			}
+			children = append(children, bc.ChildIEs...)
+			if _, err := s.AddDedicatedBearers(ies.NewBearerContext(children...)); err != nil {
+				return err
+			}
+		}
+	case req.MessageType() == messages.MsgTypeUpdateBearerRequest && rsp.MessageType() == messages.MsgTypeUpdateBearerResponse:
+		if err := s.updateAPNAMBR(reqIEs...); err != nil {
+			return err
+		}
+		rejected := rejectedEBIs(rspBCs)
+		for _, bc := range reqBCs {
+			if rejected[ebiIn(bc)] {
+				continue
+			}
+			if err := s.UpdateBearers(bc); err != nil {
+				return err
+			}
+		}
+	case req.MessageType() == messages.MsgTypeDeleteBearerRequest && rsp.MessageType() == messages.MsgTypeDeleteBearerResponse:
+		rejected := rejectedEBIs(rspBCs)
+		for _, ie := range reqIEs {
+			// the EBIs with instance 1 are the Bearers to be deleted, and the Linked
+			// EBI with instance 0 means the whole PDN connection, which is left to
+			// the user to remove the Session.
+			if ie.Type != ies.EPSBearerID || ie.Instance() != 1 {
+				continue
+			}
+			ebi, err := ie.EPSBearerID()
+			if err != nil {
+				return err
+			}
+			if rejected[ebi] {
+				continue
+			}
+			s.RemoveBearerByEBI(ebi)
+		}
+	}
+	return nil
+}
+
+// bearerContextsIn returns the Bearer Context IEs in ie.
+func bearerContextsIn(ie []*ies.IE) []*ies.IE {
+	var bcs []*ies.IE
+	for _, i := range ie {
+		if i != nil && i.Type == ies.BearerContext {
+			bcs = append(bcs, i)
+		}
+	}
+	return bcs
+}
+
+// rejectedEBIs returns the set of EBIs in the Bearer Contexts whose Cause is not an
+// acceptance.
+func rejectedEBIs(bcs []*ies.IE) map[uint8]bool {
+	rejected := map[uint8]bool{}
+	for _, bc := range bcs {
+		if !isAccepted(bc.ChildIEs...) {
+			rejected[ebiIn(bc)] = true
+		}
+	}
+	return rejected
+}
+
+// ebiIn returns the EBI in the Bearer Context IE, or 0 if not found.
+func ebiIn(bc *ies.IE) uint8 {
+	for _, child := range bc.ChildIEs {
+		if child.Type == ies.EPSBearerID {
+			ebi, err := child.EPSBearerID()
+			if err != nil {
+				return 0
+			}
+			return ebi
+		}
+	}
+	return 0
+}
+
+// CreateBearer sends a CreateBearerRequest with TEID and IEs given.
+func (c *Conn) CreateBearer(teid uint32, raddr net.Addr, ie ...*ies.IE) (uint32, error) {
+	sess, err := c.GetSessionByTEID(teid, raddr)
+	if err != nil {
+		return 0, err
+	}
+
+	msg := messages.NewCreateBearerRequest(teid, 0, ie...)
+
+	seq, err := c.SendMessageTo(msg, sess.PeerAddr())
+	if err != nil {
+		return 0, err
+	}
+	return seq, nil
+}
+
+// CreateBearerResponse sends a CreateBearerResponse with TEID and IEs given in
+// response to the CreateBearerRequest, and adds the dedicated Bearers in the Bearer
+// Contexts given to the Session if the Cause given is an acceptance.
+//
+// The Session is looked up by the TEID in req and raddr. Each Bearer Context given
+// is combined with the one at the same position in req, so that the Bearer has the
+// Bearer QoS in req as well as the EBI given. See (*Session) AddDedicatedBearers for
+// how the Bearers are added.
+func (c *Conn) CreateBearerResponse(teid uint32, raddr net.Addr, req messages.Message, ie ...*ies.IE) error {
+	sess, err := c.GetSessionByTEID(req.TEID(), raddr)
+	if err != nil {
+		return err
+	}
+
+	rsp := messages.NewCreateBearerResponse(teid, 0, ie...)
+	if err := sess.applyBearerResponse(req, rsp); err != nil {
+		return err
+	}
+	return c.RespondTo(raddr, req, rsp)
+}
+
+// UpdateBearer sends an UpdateBearerRequest with TEID and IEs given.
+func (c *Conn) UpdateBearer(teid uint32, raddr net.Addr, ie ...*ies.IE) (uint32, error) {
+	sess, err := c.GetSessionByTEID(teid, raddr)
+	if err != nil {
+		return 0, err
+	}
+
+	msg := messages.NewUpdateBearerRequest(teid, 0, ie...)
+
+	seq, err := c.SendMessageTo(msg, sess.PeerAddr())
+	if err != nil {
+		return 0, err
+	}
+	return seq, nil
+}
+
+// UpdateBearerResponse sends an UpdateBearerResponse with TEID and IEs given in
+// response to the UpdateBearerRequest, and updates the Bearers in the Session with
+// the Bearer Contexts in req if the Cause given is an acceptance.
+//
+// The Session is looked up by the TEID in req and raddr.
+func (c *Conn) UpdateBearerResponse(teid uint32, raddr net.Addr, req messages.Message, ie ...*ies.IE) error {
+	sess, err := c.GetSessionByTEID(req.TEID(), raddr)
+	if err != nil {
+		return err
+	}
+
+	rsp := messages.NewUpdateBearerResponse(teid, 0, ie...)
+	if err := sess.applyBearerResponse(req, rsp); err != nil {
+		return err
+	}
+	return c.RespondTo(raddr, req, rsp)
+}
+
+// DeleteBearerResponse sends a DeleteBearerResponse with TEID and IEs given in
+// response to the DeleteBearerRequest, and removes the Bearers with the EBIs in req
+// from the Session if the Cause given is an acceptance.
+//
+// The Session is looked up by the TEID in req and raddr.
+func (c *Conn) DeleteBearerResponse(teid uint32, raddr net.Addr, req messages.Message, ie ...*ies.IE) error {
+	sess, err := c.GetSessionByTEID(req.TEID(), raddr)
+	if err != nil {
+		return err
+	}
+
+	rsp := messages.NewDeleteBearerResponse(teid, 0, ie...)
+	if err := sess.applyBearerResponse(req, rsp); err != nil {
+		return err
+	}
+	return c.RespondTo(raddr, req, rsp)
+}
+
+// BearerResourceCommand sends a BearerResourceCommand with TEID and IEs given.
+func (c *Conn) BearerResourceCommand(teid uint32, raddr net.Addr, ie ...*ies.IE) (uint32, error) {
+	sess, err := c.GetSessionByTEID(teid, raddr)
+	if err != nil {
+		return 0, err
+	}
+
+	msg := messages.NewBearerResourceCommand(teid, 0, ie...)
+
+	seq, err := c.SendMessageTo(msg, sess.PeerAddr())
+	if err != nil {
+		return 0, err
+	}
+	return seq, nil
+}
+
+// BearerResourceFailureIndication sends a BearerResourceFailureIndication with TEID
+// and IEs given in response to the BearerResourceCommand.
+func (c *Conn) BearerResourceFailureIndication(teid uint32, raddr net.Addr, cmd messages.Message, ie ...*ies.IE) error {
+	return c.RespondTo(raddr, cmd, messages.NewBearerResourceFailureIndication(teid, 0, ie...))
+}
+
+// DeleteBearerCommand sends a DeleteBearerCommand with TEID and IEs given.
+func (c *Conn) DeleteBearerCommand(teid uint32, raddr net.Addr, ie ...*ies.IE) (uint32, error) {
+	sess, err := c.GetSessionByTEID(teid, raddr)
+	if err != nil {
+		return 0, err
+	}
+
+	msg := messages.NewDeleteBearerCommand(teid, 0, ie...)
+
+	seq, err := c.SendMessageTo(msg, sess.PeerAddr())
+	if err != nil {
+		return 0, err
+	}
+	return seq, nil
+}
+
+// DeleteBearerFailureIndication sends a DeleteBearerFailureIndication with TEID and
+// IEs given in response to the DeleteBearerCommand.
+func (c *Conn) DeleteBearerFailureIndication(teid uint32, raddr net.Addr, cmd messages.Message, ie ...*ies.IE) error {
+	return c.RespondTo(raddr, cmd, messages.NewDeleteBearerFailureIndication(teid, 0, ie...))
+}
@@ -0,0 +1,213 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"hash/fnv"
+	"net"
+	"sync"
+	"time"
+)
+
+// DispatchOrder is the unit in which WorkerPool keeps the order of incoming messages.
+type DispatchOrder int
+
+// DispatchOrder definitions.
+const (
+	// DispatchOrderPeer passes the messages from the same peer to the HandlerFuncs in
+	// the order they arrived. This is the default.
+	DispatchOrderPeer DispatchOrder = iota
+	// DispatchOrderTEID passes the messages from the same peer with the same TEID in
+	// the header to the HandlerFuncs in the order they arrived, which allows the
+	// messages for the different Sessions on the same peer to be handled in parallel.
+	//
+	// Note that the messages with TEID=0, such as Create Session Request, may be
+	// handled in parallel with the later ones with the TEID assigned for the Session.
+	DispatchOrderTEID
+)
+
+// OverflowAction is the action taken for the incoming datagrams when the queue of
+// WorkerPool is full.
+type OverflowAction int
+
+// OverflowAction definitions.
+const (
+	// OverflowActionBlock stops reading from the socket until the queue has room,
+	// which pushes back to the socket buffer of the kernel. This is the default.
+	OverflowActionBlock OverflowAction = iota
+	// OverflowActionDrop discards the datagram, expecting the peer to retransmit it.
+	OverflowActionDrop
+)
+
+// WorkerPool is the configuration of the fixed number of goroutines that handle the
+// incoming messages, instead of the goroutine spawned for each datagram.
+//
+// The datagrams are distributed to the workers by the key given by Order, so that the
+// ones with the same key are always handled by the same worker in the order they
+// arrived.
+type WorkerPool struct {
+	// Workers is the number of goroutines. The zero value means DefaultWorkers.
+	Workers int
+	// QueueSize is the number of datagrams that can be queued for each worker. The
+	// zero value means DefaultWorkerQueueSize.
+	QueueSize int
+	// Order is the unit in which the order of the messages is kept.
+	Order DispatchOrder
+	// Overflow is the action taken when the queue of the worker is full.
+	Overflow OverflowAction
+}
+
+// Default values of WorkerPool.
+const (
+	DefaultWorkers         = 16
+	DefaultWorkerQueueSize = 128
+)
+
+// SetWorkerPool starts the WorkerPool to handle the incoming messages. Giving nil stops
+// it and makes Conn spawn a goroutine for each datagram, which is the default.
+//
+// The datagrams already queued are handled by the previous workers even after a new
+// WorkerPool is set, and thus the order is not kept across the change. The datagrams
+// discarded with OverflowActionDrop are counted as Dropped in Stats.
+func (c *Conn) SetWorkerPool(wp *WorkerPool) {
+	var d *dispatcher
+	if wp != nil {
+		d = newDispatcher(c, *wp)
+	}
+
+	c.mu.Lock()
+	prev := c.workers
+	c.workers = d
+	c.mu.Unlock()
+
+	if prev != nil {
+		prev.stop()
+	}
+}
+
+func (c *Conn) dispatcher() *dispatcher {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.workers
+}
+
+// datagram is the raw bytes received from the peer.
+type datagram struct {
+	raddr     net.Addr
+	raw       []byte
+	arrivedAt time.Time
+
+	// pktConn is the endpoint added with AddLocalAddr that the datagram arrived on,
+	// which is nil for the one given when creating Conn.
+	pktConn net.PacketConn
+}
+
+// dispatchDatagram passes the datagram to the WorkerPool, or to a new goroutine if it
+// is not set.
+func (c *Conn) dispatchDatagram(dg *datagram) {
+	if d := c.dispatcher(); d != nil && d.dispatch(dg) {
+		return
+	}
+	go c.handleDatagram(dg)
+}
+
+// dispatcher keeps the workers of WorkerPool.
+type dispatcher struct {
+	conn   *Conn
+	config WorkerPool
+	queues []chan *datagram
+
+	// mu protects queues from being closed while sending to them.
+	mu      sync.RWMutex
+	stopped bool
+	done    chan struct{}
+	once    sync.Once
+}
+
+func newDispatcher(c *Conn, wp WorkerPool) *dispatcher {
+	if wp.Workers <= 0 {
+		wp.Workers = DefaultWorkers
+	}
+	if wp.QueueSize <= 0 {
+		wp.QueueSize = DefaultWorkerQueueSize
+	}
+
+	d := &dispatcher{
+		conn:   c,
+		config: wp,
+		queues: make([]chan *datagram, wp.Workers),
+		done:   make(chan struct{}),
+	}
+	for i := range d.queues {
+		q := make(chan *datagram, wp.QueueSize)
+		d.queues[i] = q
+		go func() {
+			for dg := range q {
+				c.handleDatagram(dg)
+			}
+		}()
+	}
+	return d
+}
+
+// dispatch queues dg to the worker chosen by its key. It returns false if the
+// dispatcher is already stopped, and true otherwise even if dg is dropped.
+func (d *dispatcher) dispatch(dg *datagram) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.stopped {
+		return false
+	}
+
+	q := d.queues[d.index(dg)]
+	if d.config.Overflow == OverflowActionDrop {
+		select {
+		case q <- dg:
+		default:
+			d.conn.stats.drop()
+			d.conn.log().Debug("datagram dropped", "peer", dg.raddr.String())
+		}
+		return true
+	}
+
+	select {
+	case q <- dg:
+	case <-d.done:
+		// stopped while waiting; hand it over to the caller instead.
+		return false
+	}
+	return true
+}
+
+// index returns the index of the worker for dg.
+func (d *dispatcher) index(dg *datagram) int {
+	if len(d.queues) == 1 {
+		return 0
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(addrString(dg.raddr)))
+	if d.config.Order == DispatchOrderTEID {
+		// TEID is present in the octets 5-8 if the T flag is set.
+		if len(dg.raw) >= 8 && dg.raw[0]&0x08 != 0 {
+			_, _ = h.Write(dg.raw[4:8])
+		}
+	}
+	return int(h.Sum32() % uint32(len(d.queues)))
+}
+
+// stop stops the workers after they handle the datagrams queued.
+func (d *dispatcher) stop() {
+	d.once.Do(func() {
+		close(d.done)
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		d.stopped = true
+		for _, q := range d.queues {
+			close(q)
+		}
+	})
+}
@@ -0,0 +1,409 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultDNSTimeout is the default duration to wait for the response from the DNS
+// server in DNSNodeSelector.
+const DefaultDNSTimeout = 3 * time.Second
+
+// maxNAPTRDepth is the maximum number of the non-terminal NAPTR records followed.
+const maxNAPTRDepth = 5
+
+// DNS resource record types used in DNSNodeSelector.
+const (
+	dnsTypeA     uint16 = 1
+	dnsTypeAAAA  uint16 = 28
+	dnsTypeSRV   uint16 = 33
+	dnsTypeNAPTR uint16 = 35
+)
+
+// DNSNodeSelector is a NodeSelector that selects the nodes with the S-NAPTR procedure
+// defined in TS 29.303, using the DNS server given.
+//
+// The NAPTR records for the FQDN are filtered by the service and sorted by the order
+// and the preference. The replacement of the records with "a" flag is resolved
+// with A and AAAA queries and used with GTPCPort, and the one with "s" flag is
+// resolved with SRV query and used with the port in it. The non-terminal records
+// with empty flag are followed.
+//
+// This is a minimal DNS client that only supports UDP, and fails if the response is
+// truncated. The topology matching of the nodes (TS 29.303 5.4) is not done.
+type DNSNodeSelector struct {
+	server  string
+	timeout time.Duration
+}
+
+// NewDNSNodeSelector creates a new DNSNodeSelector that sends the queries to server,
+// which is the address of the DNS server in the form of "host:port". Giving 0 or
+// negative timeout uses DefaultDNSTimeout.
+func NewDNSNodeSelector(server string, timeout time.Duration) *DNSNodeSelector {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+	if timeout <= 0 {
+		timeout = DefaultDNSTimeout
+	}
+	return &DNSNodeSelector{server: server, timeout: timeout}
+}
+
+// Select returns the addresses of the nodes for the service in the FQDN given.
+func (d *DNSNodeSelector) Select(fqdn, service string) ([]net.Addr, error) {
+	addrs, err := d.selectNAPTR(fqdn, service, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, ErrNodeNotFound
+	}
+	return addrs, nil
+}
+
+func (d *DNSNodeSelector) selectNAPTR(name, service string, depth int) ([]net.Addr, error) {
+	rrs, err := d.query(name, dnsTypeNAPTR)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []*naptrRecord
+	for _, rr := range rrs {
+		n, err := parseNAPTR(rr)
+		if err != nil {
+			return nil, err
+		}
+		if n.provides(service) {
+			records = append(records, n)
+		}
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].order != records[j].order {
+			return records[i].order < records[j].order
+		}
+		return records[i].preference < records[j].preference
+	})
+
+	var addrs []net.Addr
+	for _, n := range records {
+		var (
+			found []net.Addr
+			err   error
+		)
+		switch strings.ToLower(n.flags) {
+		case "a":
+			found, err = d.lookupHost(n.replacement, GTPCPort)
+		case "s":
+			found, err = d.lookupSRV(n.replacement)
+		case "":
+			if depth >= maxNAPTRDepth {
+				continue
+			}
+			found, err = d.selectNAPTR(n.replacement, service, depth+1)
+		default:
+			continue
+		}
+		// the failure in one of the candidates does not prevent the others.
+		if err != nil {
+			continue
+		}
+		addrs = append(addrs, found...)
+	}
+	return addrs, nil
+}
+
+func (d *DNSNodeSelector) lookupSRV(name string) ([]net.Addr, error) {
+	rrs, err := d.query(name, dnsTypeSRV)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []*srvRecord
+	for _, rr := range rrs {
+		s, err := parseSRV(rr)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, s)
+	}
+	// the weighted selection in RFC 2782 is simplified to prefer the larger weight.
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].priority != records[j].priority {
+			return records[i].priority < records[j].priority
+		}
+		return records[i].weight > records[j].weight
+	})
+
+	var addrs []net.Addr
+	for _, s := range records {
+		found, err := d.lookupHost(s.target, int(s.port))
+		if err != nil {
+			continue
+		}
+		addrs = append(addrs, found...)
+	}
+	return addrs, nil
+}
+
+func (d *DNSNodeSelector) lookupHost(host string, port int) ([]net.Addr, error) {
+	var addrs []net.Addr
+	for _, qtype := range []uint16{dnsTypeA, dnsTypeAAAA} {
+		rrs, err := d.query(host, qtype)
+		if err != nil {
+			return nil, err
+		}
+		for _, rr := range rrs {
+			if (qtype == dnsTypeA && len(rr.data) != 4) || (qtype == dnsTypeAAAA && len(rr.data) != 16) {
+				return nil, errors.Errorf("malformed address record for %s", host)
+			}
+			ip := make(net.IP, len(rr.data))
+			copy(ip, rr.data)
+			addrs = append(addrs, &net.UDPAddr{IP: ip, Port: port})
+		}
+	}
+	return addrs, nil
+}
+
+// query sends the query for name and qtype, and returns the records of qtype in the
+// answer section. NXDOMAIN results in no records without error.
+func (d *DNSNodeSelector) query(name string, qtype uint16) ([]*dnsRR, error) {
+	id := make([]byte, 2)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	q, err := newDNSQuery(binary.BigEndian.Uint16(id), name, qtype)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.DialTimeout("udp", d.server, d.timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(d.timeout)); err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write(q); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 4096)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to query %s", name)
+		}
+		if n < 12 || binary.BigEndian.Uint16(buf[0:2]) != binary.BigEndian.Uint16(id) {
+			// not the response to the query.
+			continue
+		}
+		return parseDNSResponse(buf[:n], qtype)
+	}
+}
+
+// dnsRR is a resource record in the DNS response. msg is the whole response, which
+// is required to decode the compressed names in data.
+type dnsRR struct {
+	typ    uint16
+	data   []byte
+	msg    []byte
+	offset int
+}
+
+func newDNSQuery(id uint16, name string, qtype uint16) ([]byte, error) {
+	b := make([]byte, 12)
+	binary.BigEndian.PutUint16(b[0:2], id)
+	// RD bit set.
+	binary.BigEndian.PutUint16(b[2:4], 0x0100)
+	binary.BigEndian.PutUint16(b[4:6], 1)
+
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		if len(label) == 0 || len(label) > 63 {
+			return nil, errors.Errorf("invalid name to query: %s", name)
+		}
+		b = append(b, uint8(len(label)))
+		b = append(b, label...)
+	}
+	b = append(b, 0, 0, 0, 0, 0)
+	binary.BigEndian.PutUint16(b[len(b)-4:len(b)-2], qtype)
+	// class IN.
+	binary.BigEndian.PutUint16(b[len(b)-2:], 1)
+	return b, nil
+}
+
+func parseDNSResponse(msg []byte, qtype uint16) ([]*dnsRR, error) {
+	flags := binary.BigEndian.Uint16(msg[2:4])
+	if flags&0x0200 != 0 {
+		return nil, errors.New("DNS response is truncated")
+	}
+	switch rcode := flags & 0x000f; rcode {
+	case 0:
+	case 3:
+		// NXDOMAIN
+		return nil, nil
+	default:
+		return nil, errors.Errorf("DNS query failed with RCODE %d", rcode)
+	}
+
+	qdcount := int(binary.BigEndian.Uint16(msg[4:6]))
+	ancount := int(binary.BigEndian.Uint16(msg[6:8]))
+
+	offset := 12
+	for i := 0; i < qdcount; i++ {
+		_, n, err := readDNSName(msg, offset)
+		if err != nil {
+			return nil, err
+		}
+		offset = n + 4
+	}
+
+	var rrs []*dnsRR
+	for i := 0; i < ancount; i++ {
+		_, n, err := readDNSName(msg, offset)
+		if err != nil {
+			return nil, err
+		}
+		if n+10 > len(msg) {
+			return nil, errors.New("DNS response is too short")
+		}
+		typ := binary.BigEndian.Uint16(msg[n : n+2])
+		rdlen := int(binary.BigEndian.Uint16(msg[n+8 : n+10]))
+		start := n + 10
+		if start+rdlen > len(msg) {
+			return nil, errors.New("DNS response is too short")
+		}
+		if typ == qtype {
+			rrs = append(rrs, &dnsRR{typ: typ, data: msg[start : start+rdlen], msg: msg, offset: start})
+		}
+		offset = start + rdlen
+	}
+	return rrs, nil
+}
+
+// readDNSName reads the name at offset in msg, and returns it with the offset next to
+// the name.
+func readDNSName(msg []byte, offset int) (string, int, error) {
+	var (
+		labels []string
+		next   = -1
+	)
+	// the number of pointers followed is limited to avoid the loop.
+	for jumps := 0; jumps < 64; {
+		if offset >= len(msg) {
+			return "", 0, errors.New("DNS name is too short")
+		}
+		l := int(msg[offset])
+		switch {
+		case l == 0:
+			if next < 0 {
+				next = offset + 1
+			}
+			return strings.Join(labels, ".") + ".", next, nil
+		case l&0xc0 == 0xc0:
+			if offset+2 > len(msg) {
+				return "", 0, errors.New("DNS name is too short")
+			}
+			if next < 0 {
+				next = offset + 2
+			}
+			offset = int(binary.BigEndian.Uint16(msg[offset:offset+2]) & 0x3fff)
+			jumps++
+		default:
+			if offset+1+l > len(msg) {
+				return "", 0, errors.New("DNS name is too short")
+			}
+			labels = append(labels, string(msg[offset+1:offset+1+l]))
+			offset += 1 + l
+		}
+	}
+	return "", 0, errors.New("too many compression pointers in DNS name")
+}
+
+type naptrRecord struct {
+	order, preference uint16
+	flags, services   string
+	replacement       string
+}
+
+// provides reports whether the record provides service, which is in the form of
+// "app-service:app-protocol".
+func (n *naptrRecord) provides(service string) bool {
+	want := strings.SplitN(strings.ToLower(service), ":", 2)
+	got := strings.Split(strings.ToLower(n.services), ":")
+	if len(want) != 2 || len(got) < 2 || got[0] != want[0] {
+		return false
+	}
+	for _, proto := range got[1:] {
+		if proto == want[1] {
+			return true
+		}
+	}
+	return false
+}
+
+func parseNAPTR(rr *dnsRR) (*naptrRecord, error) {
+	b := rr.data
+	if len(b) < 4 {
+		return nil, errors.New("NAPTR record is too short")
+	}
+	n := &naptrRecord{
+		order:      binary.BigEndian.Uint16(b[0:2]),
+		preference: binary.BigEndian.Uint16(b[2:4]),
+	}
+
+	offset := 4
+	var strs []string
+	for i := 0; i < 3; i++ {
+		if offset >= len(b) {
+			return nil, errors.New("NAPTR record is too short")
+		}
+		l := int(b[offset])
+		if offset+1+l > len(b) {
+			return nil, errors.New("NAPTR record is too short")
+		}
+		strs = append(strs, string(b[offset+1:offset+1+l]))
+		offset += 1 + l
+	}
+	// regexp is not used in S-NAPTR.
+	n.flags, n.services = strs[0], strs[1]
+
+	replacement, _, err := readDNSName(rr.msg, rr.offset+offset)
+	if err != nil {
+		return nil, err
+	}
+	n.replacement = replacement
+	return n, nil
+}
+
+type srvRecord struct {
+	priority, weight, port uint16
+	target                 string
+}
+
+func parseSRV(rr *dnsRR) (*srvRecord, error) {
+	if len(rr.data) < 7 {
+		return nil, errors.New("SRV record is too short")
+	}
+	target, _, err := readDNSName(rr.msg, rr.offset+6)
+	if err != nil {
+		return nil, err
+	}
+	return &srvRecord{
+		priority: binary.BigEndian.Uint16(rr.data[0:2]),
+		weight:   binary.BigEndian.Uint16(rr.data[2:4]),
+		port:     binary.BigEndian.Uint16(rr.data[4:6]),
+		target:   target,
+	}, nil
+}
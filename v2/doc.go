@@ -52,7 +52,7 @@
 //   				return &v2.ErrCauseNotOK{
 //   					MsgType: csRsp.MessageTypeName(),
 //   					Cause:   cause,
-//   					Msg:     fmt.Sprintf("subscriber: %s", session.IMSI),
+//   					Msg:     fmt.Sprintf("subscriber: %s", session.GetIMSI()),
 //   				}
 //   			}
 //   		} else {
@@ -74,7 +74,7 @@
 //   			for _, ie := range brCtxIE.ChildIEs {
 //   				switch ie.Type {
 //   				case ies.EPSBearerID:
-//   					bearer.EBI = ie.EPSBearerID()
+//   					bearer.SetEBI(ie.MustEPSBearerID())
 //   				case ies.FullyQualifiedTEID:
 //   					if ie.Instance() != 0 {
 //   						continue
@@ -0,0 +1,155 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"encoding/binary"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// EchoRule is a rule to copy the IEs in a request to its response.
+//
+// All the IEs in the request that match From are copied to the response with the
+// type and instance of To. When To is a Bearer Context, the EPS Bearer ID is taken
+// from each of the IEs matched, which is the IE itself or the one in it if it is
+// also a Bearer Context, and put into the Bearer Context with the Cause of the
+// response.
+type EchoRule struct {
+	From MandatoryIE
+	To   MandatoryIE
+}
+
+// EchoRules is a set of EchoRules per message type.
+//
+// The key is the type of response message, and the value is the list of rules to
+// copy the IEs from the request it responds to.
+type EchoRules map[uint8][]EchoRule
+
+// DefaultEchoRules returns the rules for the IEs that are echoed in the responses
+// defined in TS 29.274 for the messages supported by this package.
+//
+// The returned value is a newly allocated one, so the caller can customize it
+// safely.
+func DefaultEchoRules() EchoRules {
+	var (
+		lbi     = MandatoryIE{ies.EPSBearerID, 0}
+		pti     = MandatoryIE{ies.ProcedureTransactionID, 0}
+		bearers = MandatoryIE{ies.BearerContext, 0}
+	)
+	return EchoRules{
+		messages.MsgTypeModifyBearerResponse: {{bearers, bearers}},
+		messages.MsgTypeDeleteBearerResponse: {
+			{lbi, lbi},
+			{MandatoryIE{ies.EPSBearerID, 1}, bearers},
+		},
+		messages.MsgTypeDeleteBearerFailureIndication:   {{bearers, bearers}},
+		messages.MsgTypeUpdateBearerResponse:            {{bearers, bearers}},
+		messages.MsgTypeBearerResourceFailureIndication: {{lbi, lbi}, {pti, pti}},
+	}
+}
+
+// EchoIEs returns rsp with the IEs copied from req by DefaultEchoRules.
+//
+// See (EchoRules) Apply for details.
+func EchoIEs(req, rsp messages.Message) (messages.Message, error) {
+	return DefaultEchoRules().Apply(req, rsp)
+}
+
+// Apply returns rsp with the IEs copied from req by the rules for the type of rsp.
+// req should be the request that rsp responds to.
+//
+// The IEs are copied only when rsp does not have any IE of the same type and
+// instance, so that the ones set explicitly in rsp are kept as they are. If nothing
+// is to be copied, rsp is returned as it is. Otherwise, the returned message is a
+// newly decoded one, and rsp is not modified.
+func (r EchoRules) Apply(req, rsp messages.Message) (messages.Message, error) {
+	rules := r[rsp.MessageType()]
+	if len(rules) == 0 {
+		return rsp, nil
+	}
+
+	present, err := presentIEs(rsp)
+	if err != nil {
+		return nil, err
+	}
+	decodedIEs, err := messageIEs(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var toAttach []*ies.IE
+	for _, rule := range rules {
+		if _, ok := present[rule.To]; ok {
+			continue
+		}
+		for _, i := range decodedIEs {
+			if i.Type != rule.From.Type || i.Instance() != rule.From.Instance {
+				continue
+			}
+			if rule.To.Type == ies.BearerContext {
+				i = echoedBearerContext(i, present[MandatoryIE{ies.Cause, 0}])
+				if i == nil {
+					continue
+				}
+			}
+			// copied not to change the instance of the IE held in req.
+			echoed := *i
+			toAttach = append(toAttach, echoed.WithInstance(rule.To.Instance))
+		}
+	}
+	if len(toAttach) == 0 {
+		return rsp, nil
+	}
+
+	return withIEs(rsp, toAttach...)
+}
+
+// echoedBearerContext returns the Bearer Context with the EPS Bearer ID taken from
+// i and the cause given. It returns nil if i does not have the EPS Bearer ID.
+func echoedBearerContext(i, cause *ies.IE) *ies.IE {
+	ebi := i
+	if i.Type == ies.BearerContext {
+		found := ies.Find(i.ChildIEs, ies.EPSBearerID, 0)
+		if len(found) == 0 {
+			return nil
+		}
+		ebi = found[0]
+	}
+	if ebi.Type != ies.EPSBearerID {
+		return nil
+	}
+
+	v, err := ebi.EPSBearerID()
+	if err != nil {
+		return nil
+	}
+	bc := []*ies.IE{ies.NewEPSBearerID(v)}
+	if cause != nil {
+		bc = append(bc, ies.New(ies.Cause, 0x00, cause.Payload))
+	}
+	return ies.NewBearerContext(bc...)
+}
+
+// withIEs returns msg with the serialized IEs appended to it, decoded again so that
+// they are set to the fields.
+func withIEs(msg messages.Message, ie ...*ies.IE) (messages.Message, error) {
+	b, err := messages.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range ie {
+		serialized, err := i.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, serialized...)
+	}
+
+	// the Message Length does not include the first 4 octets of the header.
+	binary.BigEndian.PutUint16(b[2:4], uint16(len(b)-4))
+	return messages.Parse(b)
+}
@@ -0,0 +1,357 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The types in this file give the names to the values of the constants defined in
+// constants.go, which are kept as uint8 for compatibility. Convert the value to the
+// type to get its name, e.g., Cause(ie.MustCause()).String() returns
+// "RequestAccepted". The names are the ones of the constants without prefix, and
+// the types implement encoding.TextMarshaler and encoding.TextUnmarshaler so that
+// they are shown by name in JSON as well.
+
+// InterfaceType is the value of the Interface Type in F-TEID IE.
+type InterfaceType uint8
+
+var interfaceTypeNames = map[uint8]string{
+	0:  "S1UeNodeBGTPU",
+	1:  "S1USGWGTPU",
+	2:  "S12RNCGTPU",
+	3:  "S12SGWGTPU",
+	4:  "S5S8SGWGTPU",
+	5:  "S5S8PGWGTPU",
+	6:  "S5S8SGWGTPC",
+	7:  "S5S8PGWGTPC",
+	8:  "S5S8SGWPMIPv6",
+	9:  "S5S8PGWPMIPv6",
+	10: "S11MMEGTPC",
+	11: "S11S4SGWGTPC",
+	12: "S10MMEGTPC",
+	13: "S3MMEGTPC",
+	14: "S3SGSNGTPC",
+	15: "S4SGSNGTPU",
+	16: "S4SGWGTPU",
+	17: "S4SGSNGTPC",
+	18: "S16SGSNGTPC",
+	19: "eNodeBGTPUForDL",
+	20: "eNodeBGTPUForUL",
+	21: "RNCGTPUForData",
+	22: "SGSNGTPUForData",
+	23: "SGWUPFGTPUForDL",
+	24: "SmMBMSGWGTPC",
+	25: "SnMBMSGWGTPC",
+	26: "SmMMEGTPC",
+	27: "SnSGSNGTPC",
+	28: "SGWGTPUForUL",
+	29: "SnSGSNGTPU",
+	30: "S2bePDGGTPC",
+	31: "S2bUePDGGTPU",
+	32: "S2bPGWGTPC",
+	33: "S2bUPGWGTPU",
+	34: "S2aTWANGTPU",
+	35: "S2aTWANGTPC",
+	36: "S2aPGWGTPC",
+	37: "S2aPGWGTPU",
+	38: "S11MMEGTPU",
+	39: "S11SGWGTPU",
+}
+
+// String returns the name of InterfaceType, or "InterfaceType(N)" if it is unknown.
+func (v InterfaceType) String() string {
+	return enumName(interfaceTypeNames, "InterfaceType", uint8(v))
+}
+
+// MarshalText returns the name of InterfaceType.
+func (v InterfaceType) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText sets the value of InterfaceType from the name given.
+func (v *InterfaceType) UnmarshalText(text []byte) error {
+	x, err := ParseInterfaceType(string(text))
+	if err != nil {
+		return err
+	}
+	*v = x
+	return nil
+}
+
+// ParseInterfaceType returns InterfaceType from the name given.
+//
+// The name is case-insensitive, and can be given with the prefix of the constant,
+// e.g., "S11MMEGTPC" or "IFTypeS11MMEGTPC".
+func ParseInterfaceType(name string) (InterfaceType, error) {
+	v, err := enumValue(interfaceTypeNames, "InterfaceType", "IFType", name)
+	return InterfaceType(v), err
+}
+
+// RATType is the value of RAT Type IE.
+type RATType uint8
+
+var ratTypeNames = map[uint8]string{
+	1:  "UTRAN",
+	2:  "GERAN",
+	3:  "WLAN",
+	4:  "GAN",
+	5:  "HSPAEvolution",
+	6:  "EUTRAN",
+	7:  "Virtual",
+	8:  "EUTRANNBIoT",
+	9:  "LTEM",
+	10: "NR",
+}
+
+// String returns the name of RATType, or "RATType(N)" if it is unknown.
+func (v RATType) String() string {
+	return enumName(ratTypeNames, "RATType", uint8(v))
+}
+
+// MarshalText returns the name of RATType.
+func (v RATType) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText sets the value of RATType from the name given.
+func (v *RATType) UnmarshalText(text []byte) error {
+	x, err := ParseRATType(string(text))
+	if err != nil {
+		return err
+	}
+	*v = x
+	return nil
+}
+
+// ParseRATType returns RATType from the name given.
+//
+// The name is case-insensitive, and can be given with the prefix of the constant,
+// e.g., "GERAN" or "RATTypeGERAN".
+func ParseRATType(name string) (RATType, error) {
+	v, err := enumValue(ratTypeNames, "RATType", "RATType", name)
+	return RATType(v), err
+}
+
+// Cause is the value of the Cause value in Cause IE.
+type Cause uint8
+
+var causeNames = map[uint8]string{
+	2:   "LocalDetach",
+	3:   "CompleteDetach",
+	4:   "RATChangedFrom3GPPToNon3GPP",
+	5:   "ISRDeactivation",
+	6:   "ErrorIndicationReceivedFromRNCeNodeBS4SGSNMME",
+	7:   "IMSIDetachOnly",
+	8:   "ReactivationRequested",
+	9:   "PDNReconnectionToThisAPNDisallowed",
+	10:  "AccessChangedFromNon3GPPTo3GPP",
+	11:  "PDNConnectionInactivityTimerExpires",
+	12:  "PGWNotResponding",
+	13:  "NetworkFailure",
+	14:  "QoSParameterMismatch",
+	16:  "RequestAccepted",
+	17:  "RequestAcceptedPartially",
+	18:  "NewPDNTypeDueToNetworkPreference",
+	19:  "NewPDNTypeDueToSingleAddressBearerOnly",
+	64:  "ContextNotFound",
+	65:  "InvalidMessageFormat",
+	66:  "VersionNotSupportedByNextPeer",
+	67:  "InvalidLength",
+	68:  "ServiceNotSupported",
+	69:  "MandatoryIEIncorrect",
+	70:  "MandatoryIEMissing",
+	72:  "SystemFailure",
+	73:  "NoResourcesAvailable",
+	74:  "SemanticErrorInTheTFTOperation",
+	75:  "SyntacticErrorInTheTFTOperation",
+	76:  "SemanticErrorsInPacketFilters",
+	77:  "SyntacticErrorsInPacketFilters",
+	78:  "MissingOrUnknownAPN",
+	80:  "GREKeyNotFound",
+	81:  "RelocationFailure",
+	82:  "DeniedInRAT",
+	83:  "PreferredPDNTypeNotSupported",
+	84:  "AllDynamicAddressesAreOccupied",
+	85:  "UEContextWithoutTFTAlreadyActivated",
+	86:  "ProtocolTypeNotSupported",
+	87:  "UENotResponding",
+	88:  "UERefuses",
+	89:  "ServiceDenied",
+	90:  "UnableToPageUE",
+	91:  "NoMemoryAvailable",
+	92:  "UserAuthenticationFailed",
+	93:  "APNAccessDeniedNoSubscription",
+	94:  "RequestRejectedReasonNotSpecified",
+	95:  "PTMSISignatureMismatch",
+	96:  "IMSIIMEINotKnown",
+	97:  "SemanticErrorInTheTADOperation",
+	98:  "SyntacticErrorInTheTADOperation",
+	100: "RemotePeerNotResponding",
+	101: "CollisionWithNetworkInitiatedRequest",
+	102: "UnableToPageUEDueToSuspension",
+	103: "ConditionalIEMissing",
+	104: "APNRestrictionTypeIncompatibleWithCurrentlyActivePDNConnection",
+	105: "InvalidOverallLengthOfTheTriggeredResponseMessageAndAPiggybackedInitialMessage",
+	106: "DataForwardingNotSupported",
+	107: "InvalidReplyFromRemotePeer",
+	108: "FallbackToGTPv1",
+	109: "InvalidPeer",
+	110: "TemporarilyRejectedDueToHandoverTAURAUProcedureInProgress",
+	111: "ModificationsNotLimitedToS1UBearers",
+	112: "RequestRejectedForAPMIPv6Reason",
+	113: "APNCongestion",
+	114: "BearerHandlingNotSupported",
+	115: "UEAlreadyReattached",
+	116: "MultiplePDNConnectionsForAGivenAPNNotAllowed",
+	117: "TargetAccessRestrictedForTheSubscriber",
+	119: "MMESGSNRefusesDueToVPLMNPolicy",
+	120: "GTPCEntityCongestion",
+	121: "LateOverlappingRequest",
+	122: "TimedOutRequest",
+	123: "UEIsTemporarilyNotReachableDueToPowerSaving",
+	124: "RelocationFailureDueToNASMessageRedirection",
+	125: "UENotAuthorisedByOCSOrExternalAAAServer",
+	126: "MultipleAccessesToAPDNConnectionNotAllowed",
+	127: "RequestRejectedDueToUECapability",
+	128: "S1UPathFailure",
+}
+
+// String returns the name of Cause, or "Cause(N)" if it is unknown.
+func (v Cause) String() string {
+	return enumName(causeNames, "Cause", uint8(v))
+}
+
+// MarshalText returns the name of Cause.
+func (v Cause) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText sets the value of Cause from the name given.
+func (v *Cause) UnmarshalText(text []byte) error {
+	x, err := ParseCause(string(text))
+	if err != nil {
+		return err
+	}
+	*v = x
+	return nil
+}
+
+// ParseCause returns Cause from the name given.
+//
+// The name is case-insensitive, and can be given with the prefix of the constant,
+// e.g., "RequestAccepted" or "CauseRequestAccepted".
+func ParseCause(name string) (Cause, error) {
+	v, err := enumValue(causeNames, "Cause", "Cause", name)
+	return Cause(v), err
+}
+
+// PDNType is the value of the PDN Type in PDN Type IE and PAA IE.
+type PDNType uint8
+
+var pdnTypeNames = map[uint8]string{
+	1: "IPv4",
+	2: "IPv6",
+	3: "IPv4v6",
+	4: "NonIP",
+}
+
+// String returns the name of PDNType, or "PDNType(N)" if it is unknown.
+func (v PDNType) String() string {
+	return enumName(pdnTypeNames, "PDNType", uint8(v))
+}
+
+// MarshalText returns the name of PDNType.
+func (v PDNType) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText sets the value of PDNType from the name given.
+func (v *PDNType) UnmarshalText(text []byte) error {
+	x, err := ParsePDNType(string(text))
+	if err != nil {
+		return err
+	}
+	*v = x
+	return nil
+}
+
+// ParsePDNType returns PDNType from the name given.
+//
+// The name is case-insensitive, and can be given with the prefix of the constant,
+// e.g., "IPv6" or "PDNTypeIPv6".
+func ParsePDNType(name string) (PDNType, error) {
+	v, err := enumValue(pdnTypeNames, "PDNType", "PDNType", name)
+	return PDNType(v), err
+}
+
+// APNRestriction is the value of APN Restriction IE.
+type APNRestriction uint8
+
+var apnRestrictionNames = map[uint8]string{
+	0: "NoExistingContextsorRestriction",
+	1: "Public1",
+	2: "Public2",
+	3: "Private1",
+	4: "Private2",
+}
+
+// String returns the name of APNRestriction, or "APNRestriction(N)" if it is unknown.
+func (v APNRestriction) String() string {
+	return enumName(apnRestrictionNames, "APNRestriction", uint8(v))
+}
+
+// MarshalText returns the name of APNRestriction.
+func (v APNRestriction) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText sets the value of APNRestriction from the name given.
+func (v *APNRestriction) UnmarshalText(text []byte) error {
+	x, err := ParseAPNRestriction(string(text))
+	if err != nil {
+		return err
+	}
+	*v = x
+	return nil
+}
+
+// ParseAPNRestriction returns APNRestriction from the name given.
+//
+// The name is case-insensitive, and can be given with the prefix of the constant,
+// e.g., "Public1" or "APNRestrictionPublic1".
+func ParseAPNRestriction(name string) (APNRestriction, error) {
+	v, err := enumValue(apnRestrictionNames, "APNRestriction", "APNRestriction", name)
+	return APNRestriction(v), err
+}
+
+// enumName returns the name of v in names, or "typ(v)" if it is unknown.
+func enumName(names map[uint8]string, typ string, v uint8) string {
+	if name, ok := names[v]; ok {
+		return name
+	}
+	return fmt.Sprintf("%s(%d)", typ, v)
+}
+
+// enumValue returns the value of name in names. The name can be given with prefix,
+// or in the form of "typ(N)" returned by enumName for the unknown values.
+func enumValue(names map[uint8]string, typ, prefix, name string) (uint8, error) {
+	var v uint8
+	if _, err := fmt.Sscanf(name, typ+"(%d)", &v); err == nil {
+		return v, nil
+	}
+
+	trimmed := name
+	if len(name) > len(prefix) && strings.EqualFold(name[:len(prefix)], prefix) {
+		trimmed = name[len(prefix):]
+	}
+	for v, n := range names {
+		if strings.EqualFold(n, trimmed) {
+			return v, nil
+		}
+	}
+	return 0, &UnknownNameError{Type: typ, Name: name}
+}
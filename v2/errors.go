@@ -7,7 +7,10 @@ package v2
 import (
 	"errors"
 	"fmt"
+	"net"
+	"strings"
 
+	"github.com/wmnsk/go-gtp/internal/sockopt"
 	"github.com/wmnsk/go-gtp/v2/messages"
 )
 
@@ -15,9 +18,52 @@ var (
 	// ErrTEIDNotFound indicates that TEID is not registered for the interface specified.
 	ErrTEIDNotFound = errors.New("no TEID found")
 
+	// ErrTEIDExhausted indicates that TEIDAllocator has no TEID available.
+	ErrTEIDExhausted = errors.New("no TEID available")
+
 	// ErrTimeout indicates that a handler failed to complete its work due to the
 	// absence of messages expected to come from another endpoint.
 	ErrTimeout = errors.New("timed out")
+
+	// ErrSessionSuspended indicates that the Session is already suspended.
+	ErrSessionSuspended = errors.New("session is suspended")
+
+	// ErrSessionNotSuspended indicates that the Session is not suspended.
+	ErrSessionNotSuspended = errors.New("session is not suspended")
+
+	// ErrPending is returned by HandlerFunc to indicate that the response will be
+	// sent later with the Transaction returned by (*Conn) Defer.
+	ErrPending = errors.New("response is pending")
+
+	// ErrTransactionCompleted indicates that the response is already sent with the
+	// Transaction.
+	ErrTransactionCompleted = errors.New("transaction already completed")
+
+	// ErrNotificationDelayed indicates that the Downlink Data Notification is not sent
+	// as the peer requested to delay it with Data Notification Delay.
+	ErrNotificationDelayed = errors.New("downlink data notification is delayed")
+
+	// ErrISRNotActive indicates that ISR is not activated for the Session.
+	ErrISRNotActive = errors.New("ISR is not active")
+
+	// ErrSendQueueFull indicates that the message is not sent as the SendQueue is
+	// full.
+	ErrSendQueueFull = errors.New("send queue is full")
+
+	// ErrNodeNotFound indicates that NodeSelector could not find any node for the
+	// service requested.
+	ErrNodeNotFound = errors.New("no node found")
+
+	// ErrLocalAddrNotFound indicates that the local address given is not bound to
+	// the Conn.
+	ErrLocalAddrNotFound = errors.New("local address not found")
+
+	// ErrSocketOptionUnsupported indicates that the SocketOptions given is not
+	// supported on the platform.
+	ErrSocketOptionUnsupported = sockopt.ErrUnsupported
+
+	// ErrInvalidDSCP indicates that the DSCP in SocketOptions is out of range.
+	ErrInvalidDSCP = sockopt.ErrInvalidDSCP
 )
 
 // CauseNotOKError indicates that the value in Cause IE is not OK.
@@ -29,7 +75,31 @@ type CauseNotOKError struct {
 
 //x Error returns error cause with message.
 func (e *CauseNotOKError) Error() string {
-	return fmt.Sprintf("got non-OK Cause: %d in %s; %s", e.Cause, e.MsgType, e.Msg)
+	return fmt.Sprintf("got non-OK Cause: %s(%d) in %s; %s", Cause(e.Cause), e.Cause, e.MsgType, e.Msg)
+}
+
+// CauseError indicates that the Cause in the response is not the one of acceptance.
+//
+// PCE, BCE and CS are the PDN Connection IE Error, Bearer Context IE Error and Cause
+// Source flags in the Cause IE. OffendingIEType and OffendingIEInstance are the IE
+// that caused the rejection, which are 0 if not present.
+type CauseError struct {
+	MsgType             string
+	Cause               Cause
+	PCE, BCE, CS        bool
+	OffendingIEType     uint8
+	OffendingIEInstance uint8
+}
+
+//x Error returns the Cause with the message type and the offending IE if any.
+func (e *CauseError) Error() string {
+	if e.OffendingIEType == 0 {
+		return fmt.Sprintf("got non-accept Cause: %s(%d) in %s", e.Cause, uint8(e.Cause), e.MsgType)
+	}
+	return fmt.Sprintf(
+		"got non-accept Cause: %s(%d) in %s, offending IE: type=%d, instance=%d",
+		e.Cause, uint8(e.Cause), e.MsgType, e.OffendingIEType, e.OffendingIEInstance,
+	)
 }
 
 // RequiredIEMissingError indicates that the IE required is missing.
@@ -134,6 +204,27 @@ func (e *InvalidSessionError) Error() string {
 	return fmt.Sprintf("invalid session, IMSI: %s", e.IMSI)
 }
 
+// SessionIncompleteError indicates that Session lacks the parameters required,
+// returned by (*Session) Validate.
+//
+// MissingIFTypes is the interface types whose TEIDs are not found, and MissingParams
+// is the names of the other parameters missing, e.g., "EBI".
+type SessionIncompleteError struct {
+	IMSI           string
+	MissingIFTypes []uint8
+	MissingParams  []string
+}
+
+//x Error returns the parameters missing in Session.
+func (e *SessionIncompleteError) Error() string {
+	var missing []string
+	if len(e.MissingIFTypes) > 0 {
+		missing = append(missing, fmt.Sprintf("TEIDs for interface types %v", e.MissingIFTypes))
+	}
+	missing = append(missing, e.MissingParams...)
+	return fmt.Sprintf("incomplete session, IMSI: %s, missing: %s", e.IMSI, strings.Join(missing, ", "))
+}
+
 // BearerNotFoundError indicates that no Bearer found by lookup methods.
 type BearerNotFoundError struct {
 	IMSI string
@@ -156,3 +247,68 @@ type HandlerNotFoundError struct {
 func (e *HandlerNotFoundError) Error() string {
 	return fmt.Sprintf("no handlers found for incoming message: %s, ignoring", e.MsgType)
 }
+
+// UnexpectedSourcePortError indicates that the message comes from the UDP port
+// different from the one known for the peer.
+type UnexpectedSourcePortError struct {
+	Known, Got net.Addr
+}
+
+//x Error returns the known and actual address of the peer.
+func (e *UnexpectedSourcePortError) Error() string {
+	return fmt.Sprintf("got message from unexpected port: %s, expected: %s", e.Got, e.Known)
+}
+
+// UnknownPeerError indicates that the Peer is not known by Conn.
+type UnknownPeerError struct {
+	Addr net.Addr
+}
+
+//x Error returns the address of unknown Peer.
+func (e *UnknownPeerError) Error() string {
+	return fmt.Sprintf("unknown peer: %s", e.Addr)
+}
+
+// TransactionExpiredError indicates that the Transaction is expired before the
+// response is sent.
+type TransactionExpiredError struct {
+	MsgType string
+	Seq     uint32
+	Peer    net.Addr
+}
+
+//x Error returns the request and the peer of the expired Transaction.
+func (e *TransactionExpiredError) Error() string {
+	return fmt.Sprintf("transaction expired: %s with Sequence Number %d from %s", e.MsgType, e.Seq, e.Peer)
+}
+
+// UnknownNameError indicates that the name given cannot be parsed as the value of
+// the type.
+type UnknownNameError struct {
+	Type, Name string
+}
+
+//x Error returns the type and the name that cannot be parsed.
+func (e *UnknownNameError) Error() string {
+	return fmt.Sprintf("unknown name for %s: %s", e.Type, e.Name)
+}
+
+// UnexpectedResponseError indicates that the Triggered message received does not match
+// the outstanding request sent to the peer, and is not passed to the HandlerFunc.
+//
+// ReqType is the name of the outstanding request with the same Sequence Number, which
+// is empty if no such request is found.
+type UnexpectedResponseError struct {
+	MsgType string
+	ReqType string
+	Seq     uint32
+	Reason  string
+}
+
+//x Error returns the response, the request and the reason of the mismatch.
+func (e *UnexpectedResponseError) Error() string {
+	if e.ReqType == "" {
+		return fmt.Sprintf("unexpected response: %s with Sequence Number %d: %s", e.MsgType, e.Seq, e.Reason)
+	}
+	return fmt.Sprintf("unexpected response: %s with Sequence Number %d to %s: %s", e.MsgType, e.Seq, e.ReqType, e.Reason)
+}
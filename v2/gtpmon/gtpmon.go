@@ -0,0 +1,387 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package gtpmon provides the live monitoring of GTPv2-C nodes.
+//
+// Monitor keeps the tables of peers, sessions, transactions and error rates,
+// collected from the v2.Conns attached to it and/or the packets observed on the
+// wire, e.g., the ones read from a pcap file with ReadPcap. The tables can be
+// rendered as a simple terminal UI or JSON with Run.
+//
+//	mon := gtpmon.New()
+//	mon.Attach("s11", conn)
+//	go mon.Run(ctx, os.Stdout, time.Second, gtpmon.ModeTerminal)
+package gtpmon
+
+import (
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// SourceCapture is the Source of the peers observed on the wire.
+const SourceCapture = "capture"
+
+// transactionTimeout is the duration to keep the requests observed on the wire
+// without the response.
+const transactionTimeout = 30 * time.Second
+
+// PeerStats is the statistics of a peer.
+type PeerStats struct {
+	// Source is the name given to Attach, or SourceCapture for the peers observed
+	// on the wire.
+	Source string `json:"source"`
+	Addr   string `json:"addr"`
+	// State is the PathState of the peer, which is empty for SourceCapture.
+	State string `json:"state,omitempty"`
+	// Messages is the number of messages received from the peer, and the ones sent
+	// to it as well for SourceCapture.
+	Messages uint64 `json:"messages"`
+	// Errors is the number of responses with the rejection Cause from the peer.
+	Errors uint64 `json:"errors"`
+	// Outstanding is the number of requests sent to the peer waiting for the
+	// response.
+	Outstanding int       `json:"outstanding"`
+	LastSeen    time.Time `json:"last_seen"`
+}
+
+// Snapshot is the tables kept by Monitor at a time.
+type Snapshot struct {
+	Time  time.Time    `json:"time"`
+	Peers []*PeerStats `json:"peers"`
+	// Sessions is the number of active sessions.
+	Sessions int `json:"sessions"`
+	// Transactions is the number of outstanding requests.
+	Transactions int `json:"transactions"`
+	// Messages is the number of messages sent and received.
+	Messages uint64 `json:"messages"`
+	// Errors is the number of responses with the rejection Cause and the messages
+	// failed to be parsed.
+	Errors uint64 `json:"errors"`
+	// MessageRate and ErrorRate are the numbers per second since the previous
+	// Snapshot.
+	MessageRate float64 `json:"message_rate"`
+	ErrorRate   float64 `json:"error_rate"`
+}
+
+// Monitor collects the statistics of GTPv2-C nodes.
+type Monitor struct {
+	mu    sync.Mutex
+	conns []*attachedConn
+
+	// the tables built from the packets observed on the wire.
+	peers       map[string]*PeerStats
+	pending     map[txKey]*transaction
+	sessions    map[teidKey]*session
+	messages    uint64
+	parseErrors uint64
+	lastExpire  time.Time
+
+	lastTime     time.Time
+	lastMessages uint64
+	lastErrors   uint64
+}
+
+// New creates a new Monitor.
+func New() *Monitor {
+	return &Monitor{
+		peers:    map[string]*PeerStats{},
+		pending:  map[txKey]*transaction{},
+		sessions: map[teidKey]*session{},
+	}
+}
+
+type attachedConn struct {
+	name string
+	conn *v2.Conn
+
+	mu       sync.Mutex
+	received map[string]uint64
+	errors   map[string]uint64
+	lastSeen map[string]time.Time
+}
+
+// Attach starts monitoring c with the name given, which is used as the Source of
+// PeerStats.
+//
+// The messages received are counted by the Middleware registered to c, and thus
+// only the ones passed to the HandlerFuncs are counted per peer. The others are
+// retrieved from (*v2.Conn) Peers and Stats each time Snapshot is taken.
+func (m *Monitor) Attach(name string, c *v2.Conn) {
+	a := &attachedConn{
+		name:     name,
+		conn:     c,
+		received: map[string]uint64{},
+		errors:   map[string]uint64{},
+		lastSeen: map[string]time.Time{},
+	}
+	c.Use(a.middleware)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.conns = append(m.conns, a)
+}
+
+func (a *attachedConn) middleware(next v2.HandlerFunc) v2.HandlerFunc {
+	return func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+		key := senderAddr.String()
+		a.mu.Lock()
+		a.received[key]++
+		a.lastSeen[key] = time.Now()
+		if isRejected(msg) {
+			a.errors[key]++
+		}
+		a.mu.Unlock()
+
+		return next(c, senderAddr, msg)
+	}
+}
+
+// Observe updates the tables with the GTPv2-C payload of UDP datagram sent from src
+// to dst at ts.
+func (m *Monitor) Observe(ts time.Time, src, dst net.Addr, payload []byte) {
+	msgs, err := messages.ParseMultiMessages(payload)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err != nil {
+		m.parseErrors++
+		return
+	}
+	for _, msg := range msgs {
+		m.observe(ts, src, dst, msg)
+	}
+	m.expire(ts)
+}
+
+type txKey struct {
+	src, dst string
+	seq      uint32
+}
+
+type transaction struct {
+	sentAt time.Time
+	req    messages.Message
+}
+
+type teidKey struct {
+	ip   string
+	teid uint32
+}
+
+type session struct {
+	keys []teidKey
+}
+
+func (m *Monitor) observe(ts time.Time, src, dst net.Addr, msg messages.Message) {
+	m.messages++
+	for _, addr := range []net.Addr{src, dst} {
+		p := m.capturedPeer(addr.String())
+		p.Messages++
+		p.LastSeen = ts
+	}
+
+	// the message is the response if the request with the same Sequence Number is
+	// observed in the opposite direction, and the request otherwise.
+	rspKey := txKey{dst.String(), src.String(), msg.Sequence()}
+	tx, ok := m.pending[rspKey]
+	if !ok {
+		m.pending[txKey{src.String(), dst.String(), msg.Sequence()}] = &transaction{sentAt: ts, req: msg}
+		return
+	}
+	delete(m.pending, rspKey)
+
+	if isRejected(msg) {
+		m.capturedPeer(src.String()).Errors++
+		return
+	}
+	m.trackSession(tx.req, msg, src, dst)
+}
+
+func (m *Monitor) capturedPeer(addr string) *PeerStats {
+	p, ok := m.peers[addr]
+	if !ok {
+		p = &PeerStats{Source: SourceCapture, Addr: addr}
+		m.peers[addr] = p
+	}
+	return p
+}
+
+// trackSession updates the sessions with the accepted response rsp from responder
+// to the request req.
+func (m *Monitor) trackSession(req, rsp messages.Message, responder, requester net.Addr) {
+	switch rsp.MessageType() {
+	case messages.MsgTypeCreateSessionResponse:
+		if req.MessageType() != messages.MsgTypeCreateSessionRequest {
+			return
+		}
+		s := &session{}
+		if teid, ok := senderTEID(req); ok {
+			s.keys = append(s.keys, teidKey{hostString(requester), teid})
+		}
+		if teid, ok := senderTEID(rsp); ok {
+			s.keys = append(s.keys, teidKey{hostString(responder), teid})
+		}
+		if len(s.keys) == 0 {
+			return
+		}
+		for _, key := range s.keys {
+			m.sessions[key] = s
+		}
+	case messages.MsgTypeDeleteSessionResponse:
+		if req.MessageType() != messages.MsgTypeDeleteSessionRequest {
+			return
+		}
+		s, ok := m.sessions[teidKey{hostString(responder), req.TEID()}]
+		if !ok {
+			return
+		}
+		for _, key := range s.keys {
+			delete(m.sessions, key)
+		}
+	}
+}
+
+// expire removes the requests whose response is not observed in time. It is done
+// at most once a second, not to scan all of them for each message.
+func (m *Monitor) expire(now time.Time) {
+	if now.Sub(m.lastExpire) < time.Second {
+		return
+	}
+	m.lastExpire = now
+
+	for key, tx := range m.pending {
+		if now.Sub(tx.sentAt) > transactionTimeout {
+			delete(m.pending, key)
+		}
+	}
+}
+
+// Snapshot returns the current tables.
+func (m *Monitor) Snapshot() *Snapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := &Snapshot{
+		Time:     time.Now(),
+		Messages: m.messages,
+		Errors:   m.parseErrors,
+	}
+
+	outstanding := map[string]int{}
+	for key := range m.pending {
+		outstanding[key.dst]++
+	}
+	for addr, p := range m.peers {
+		ps := *p
+		ps.Outstanding = outstanding[addr]
+		s.Peers = append(s.Peers, &ps)
+		s.Errors += ps.Errors
+	}
+	s.Transactions = len(m.pending)
+
+	sessions := map[*session]struct{}{}
+	for _, sess := range m.sessions {
+		sessions[sess] = struct{}{}
+	}
+	s.Sessions = len(sessions)
+
+	for _, a := range m.conns {
+		a.snapshot(s)
+	}
+
+	sort.Slice(s.Peers, func(i, j int) bool {
+		if s.Peers[i].Source != s.Peers[j].Source {
+			return s.Peers[i].Source < s.Peers[j].Source
+		}
+		return s.Peers[i].Addr < s.Peers[j].Addr
+	})
+
+	if !m.lastTime.IsZero() {
+		if elapsed := s.Time.Sub(m.lastTime).Seconds(); elapsed > 0 {
+			s.MessageRate = float64(s.Messages-m.lastMessages) / elapsed
+			s.ErrorRate = float64(s.Errors-m.lastErrors) / elapsed
+		}
+	}
+	m.lastTime, m.lastMessages, m.lastErrors = s.Time, s.Messages, s.Errors
+	return s
+}
+
+func (a *attachedConn) snapshot(s *Snapshot) {
+	st := a.conn.Stats()
+	for _, n := range st.MessagesSent {
+		s.Messages += n
+	}
+	for _, n := range st.MessagesReceived {
+		s.Messages += n
+	}
+	s.Errors += st.ParseErrors + st.LimitViolations
+	s.Sessions += st.Sessions
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, peer := range a.conn.Peers() {
+		key := peer.Addr().String()
+		ps := &PeerStats{
+			Source:      a.name,
+			Addr:        key,
+			State:       peer.PathState().String(),
+			Messages:    a.received[key],
+			Errors:      a.errors[key],
+			Outstanding: len(peer.OutstandingRequests()),
+			LastSeen:    peer.LastSeen(),
+		}
+		if t, ok := a.lastSeen[key]; ok && t.After(ps.LastSeen) {
+			ps.LastSeen = t
+		}
+		s.Peers = append(s.Peers, ps)
+		s.Errors += ps.Errors
+		s.Transactions += ps.Outstanding
+	}
+}
+
+// isRejected reports whether msg has the Cause that is a rejection.
+func isRejected(msg messages.Message) bool {
+	i, err := messages.FindIE(msg, ies.Cause, 0)
+	if err != nil {
+		return false
+	}
+	cause, err := i.Cause()
+	if err != nil {
+		return false
+	}
+	// TS29.274 8.4: the values from 64 are for rejection, and the ones below 16
+	// are only used in the requests.
+	return cause >= v2.CauseContextNotFound
+}
+
+// senderTEID returns the TEID in the Sender F-TEID for Control Plane in msg.
+func senderTEID(msg messages.Message) (uint32, bool) {
+	i, err := messages.FindIE(msg, ies.FullyQualifiedTEID, 0)
+	if err != nil {
+		return 0, false
+	}
+	teid, err := i.TEID()
+	if err != nil {
+		return 0, false
+	}
+	return teid, true
+}
+
+func hostString(addr net.Addr) string {
+	if u, ok := addr.(*net.UDPAddr); ok {
+		return u.IP.String()
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
@@ -0,0 +1,190 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtpmon_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/gtpmon"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+var (
+	mme = &net.UDPAddr{IP: net.IP{10, 0, 0, 1}, Port: 2123}
+	sgw = &net.UDPAddr{IP: net.IP{10, 0, 0, 2}, Port: 2123}
+)
+
+type packet struct {
+	src, dst *net.UDPAddr
+	msg      messages.Message
+}
+
+// buildPcap builds the pcap file with the Ethernet frames that carry msgs.
+func buildPcap(t *testing.T, pkts []packet) []byte {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	hdr := make([]byte, 24)
+	binary.LittleEndian.PutUint32(hdr[0:4], 0xa1b2c3d4)
+	binary.LittleEndian.PutUint16(hdr[4:6], 2)
+	binary.LittleEndian.PutUint16(hdr[6:8], 4)
+	binary.LittleEndian.PutUint32(hdr[16:20], 65535)
+	binary.LittleEndian.PutUint32(hdr[20:24], gtpmon.LinkTypeEthernet)
+	buf.Write(hdr)
+
+	for n, p := range pkts {
+		payload, err := messages.Marshal(p.msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		udp := make([]byte, 8+len(payload))
+		binary.BigEndian.PutUint16(udp[0:2], uint16(p.src.Port))
+		binary.BigEndian.PutUint16(udp[2:4], uint16(p.dst.Port))
+		binary.BigEndian.PutUint16(udp[4:6], uint16(len(udp)))
+		copy(udp[8:], payload)
+
+		ip := make([]byte, 20+len(udp))
+		ip[0] = 0x45
+		binary.BigEndian.PutUint16(ip[2:4], uint16(len(ip)))
+		ip[8] = 64
+		ip[9] = 17
+		copy(ip[12:16], p.src.IP.To4())
+		copy(ip[16:20], p.dst.IP.To4())
+		copy(ip[20:], udp)
+
+		frame := make([]byte, 14+len(ip))
+		binary.BigEndian.PutUint16(frame[12:14], 0x0800)
+		copy(frame[14:], ip)
+
+		rec := make([]byte, 16)
+		binary.LittleEndian.PutUint32(rec[0:4], uint32(1546300800+n))
+		binary.LittleEndian.PutUint32(rec[8:12], uint32(len(frame)))
+		binary.LittleEndian.PutUint32(rec[12:16], uint32(len(frame)))
+		buf.Write(rec)
+		buf.Write(frame)
+	}
+	return buf.Bytes()
+}
+
+func TestReadPcap(t *testing.T) {
+	accepted := ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil)
+	pkts := []packet{
+		{mme, sgw, messages.NewCreateSessionRequest(
+			0, 1,
+			ies.NewIMSI("123451234567890"),
+			ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 0x11111111, "10.0.0.1", ""),
+		)},
+		{sgw, mme, messages.NewCreateSessionResponse(
+			0x11111111, 1, accepted,
+			ies.NewFullyQualifiedTEID(v2.IFTypeS11S4SGWGTPC, 0x22222222, "10.0.0.2", ""),
+		)},
+		{mme, sgw, messages.NewCreateSessionRequest(
+			0, 2,
+			ies.NewIMSI("123451234567891"),
+			ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 0x33333333, "10.0.0.1", ""),
+		)},
+		{sgw, mme, messages.NewCreateSessionResponse(
+			0x33333333, 2,
+			ies.NewCause(v2.CauseNoResourcesAvailable, 0, 0, 0, nil),
+		)},
+		{mme, sgw, messages.NewEchoRequest(3, ies.NewRecovery(1))},
+	}
+
+	mon := gtpmon.New()
+	if err := mon.ReadPcap(bytes.NewReader(buildPcap(t, pkts))); err != nil {
+		t.Fatal(err)
+	}
+
+	s := mon.Snapshot()
+	if s.Messages != 5 {
+		t.Errorf("wrong number of messages: %d", s.Messages)
+	}
+	if s.Sessions != 1 {
+		t.Errorf("wrong number of sessions: %d", s.Sessions)
+	}
+	if s.Transactions != 1 {
+		t.Errorf("wrong number of transactions: %d", s.Transactions)
+	}
+	if s.Errors != 1 {
+		t.Errorf("wrong number of errors: %d", s.Errors)
+	}
+	if len(s.Peers) != 2 {
+		t.Fatalf("wrong number of peers: %d", len(s.Peers))
+	}
+	for _, p := range s.Peers {
+		if p.Source != gtpmon.SourceCapture {
+			t.Errorf("wrong source: %s", p.Source)
+		}
+		switch p.Addr {
+		case mme.String():
+			if p.Errors != 0 || p.Messages != 5 {
+				t.Errorf("wrong stats of MME: %+v", p)
+			}
+		case sgw.String():
+			if p.Errors != 1 || p.Outstanding != 1 {
+				t.Errorf("wrong stats of SGW: %+v", p)
+			}
+		default:
+			t.Errorf("unexpected peer: %s", p.Addr)
+		}
+	}
+
+	pkts = []packet{
+		{mme, sgw, messages.NewDeleteSessionRequest(0x22222222, 4)},
+		{sgw, mme, messages.NewDeleteSessionResponse(0x11111111, 4, accepted)},
+	}
+	if err := mon.ReadPcap(bytes.NewReader(buildPcap(t, pkts))); err != nil {
+		t.Fatal(err)
+	}
+	if s := mon.Snapshot(); s.Sessions != 0 {
+		t.Errorf("session should be removed: %d", s.Sessions)
+	}
+}
+
+func TestReadPcapInvalid(t *testing.T) {
+	if err := gtpmon.New().ReadPcap(bytes.NewReader(make([]byte, 24))); err != gtpmon.ErrInvalidPcap {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestOutput(t *testing.T) {
+	mon := gtpmon.New()
+	b, err := messages.Marshal(messages.NewEchoRequest(1, ies.NewRecovery(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mon.Observe(time.Now(), mme, sgw, b)
+	mon.Observe(time.Now(), mme, sgw, []byte{0xde, 0xad})
+	s := mon.Snapshot()
+
+	buf := &bytes.Buffer{}
+	if err := gtpmon.Render(buf, s); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), mme.String()) || !strings.Contains(buf.String(), "errors: 1") {
+		t.Errorf("unexpected output: %s", buf.String())
+	}
+
+	buf.Reset()
+	if err := gtpmon.WriteJSON(buf, s); err != nil {
+		t.Fatal(err)
+	}
+	got := &gtpmon.Snapshot{}
+	if err := json.Unmarshal(buf.Bytes(), got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Messages != 1 || len(got.Peers) != 2 {
+		t.Errorf("unexpected JSON: %s", buf.String())
+	}
+}
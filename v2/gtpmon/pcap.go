@@ -0,0 +1,54 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtpmon
+
+import (
+	"io"
+	"net"
+	"time"
+
+	"github.com/wmnsk/go-gtp/internal/pcap"
+)
+
+// Error definitions.
+var (
+	ErrInvalidPcap         = pcap.ErrInvalidHeader
+	ErrUnsupportedLinkType = pcap.ErrUnsupportedLinkType
+)
+
+// Link type definitions of pcap.
+const (
+	LinkTypeEthernet = pcap.LinkTypeEthernet
+	LinkTypeRaw      = pcap.LinkTypeRaw
+	LinkTypeLinuxSLL = pcap.LinkTypeLinuxSLL
+)
+
+// DefaultPorts is the UDP ports of the datagrams to be observed by ReadPcap.
+var DefaultPorts = []int{2123}
+
+// ReadPcap reads the packets in the classic pcap format from r until EOF, and
+// observes the GTPv2-C messages in the UDP datagrams over IPv4 or IPv6 whose source or
+// destination port is one of DefaultPorts.
+//
+// The link types supported are LinkTypeEthernet (with 802.1Q tags), LinkTypeRaw and
+// LinkTypeLinuxSLL. The packets that cannot be decoded are skipped. The pcapng format
+// is not supported.
+func (m *Monitor) ReadPcap(r io.Reader) error {
+	return pcap.ReadUDP(r, func(ts time.Time, src, dst *net.UDPAddr, payload []byte) {
+		if !(isMonitoredPort(src.Port) || isMonitoredPort(dst.Port)) {
+			return
+		}
+		m.Observe(ts, src, dst, payload)
+	})
+}
+
+func isMonitoredPort(port int) bool {
+	for _, p := range DefaultPorts {
+		if p == port {
+			return true
+		}
+	}
+	return false
+}
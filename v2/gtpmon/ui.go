@@ -0,0 +1,90 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gtpmon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// Mode is the output format of Run.
+type Mode int
+
+// Mode definitions.
+const (
+	// ModeTerminal redraws the tables on the terminal with the ANSI escape codes.
+	ModeTerminal Mode = iota
+	// ModeJSON writes the Snapshot in JSON per line.
+	ModeJSON
+)
+
+// clearScreen moves the cursor to the top left and clears the screen.
+const clearScreen = "\x1b[H\x1b[2J"
+
+// Run writes the Snapshot to w in the mode given every interval until ctx is done.
+func (m *Monitor) Run(ctx context.Context, w io.Writer, interval time.Duration, mode Mode) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s := m.Snapshot()
+		switch mode {
+		case ModeJSON:
+			if err := WriteJSON(w, s); err != nil {
+				return err
+			}
+		default:
+			if _, err := io.WriteString(w, clearScreen); err != nil {
+				return err
+			}
+			if err := Render(w, s); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// WriteJSON writes s to w in JSON followed by a newline.
+func WriteJSON(w io.Writer, s *Snapshot) error {
+	return json.NewEncoder(w).Encode(s)
+}
+
+// Render writes s to w as the tables in human readable format.
+func Render(w io.Writer, s *Snapshot) error {
+	if _, err := fmt.Fprintf(
+		w, "%s  messages: %d (%.1f/s)  errors: %d (%.1f/s)  sessions: %d  transactions: %d\n\n",
+		s.Time.Format(time.RFC3339), s.Messages, s.MessageRate, s.Errors, s.ErrorRate, s.Sessions, s.Transactions,
+	); err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "SOURCE\tPEER\tSTATE\tMESSAGES\tERRORS\tOUTSTANDING\tLAST SEEN")
+	for _, p := range s.Peers {
+		state := p.State
+		if state == "" {
+			state = "-"
+		}
+		lastSeen := "-"
+		if !p.LastSeen.IsZero() {
+			lastSeen = p.LastSeen.Format("15:04:05")
+		}
+		fmt.Fprintf(
+			tw, "%s\t%s\t%s\t%d\t%d\t%d\t%s\n",
+			p.Source, p.Addr, state, p.Messages, p.Errors, p.Outstanding, lastSeen,
+		)
+	}
+	return tw.Flush()
+}
@@ -8,13 +8,27 @@ import (
 	"net"
 	"sync"
 
-	"github.com/wmnsk/go-gtp/v2/ies"
 	"github.com/wmnsk/go-gtp/v2/messages"
 )
 
 // HandlerFunc is a handler for specific GTPv2-C message.
+//
+// The error returned is passed to the background error handling, except ErrPending
+// which means the response will be sent later with Transaction.
 type HandlerFunc func(c *Conn, senderAddr net.Addr, msg messages.Message) error
 
+// Middleware wraps a HandlerFunc to add some processing before and/or after it.
+//
+// It is useful for the cross-cutting concerns such as logging, metrics, rate limiting
+// and authentication of the peers. The Middleware can stop handling the message just
+// by not calling next.
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// ErrorHandler is a handler for the errors that occur in the background process
+// of Conn, such as the ones returned from HandlerFunc or the failures in parsing
+// the incoming messages.
+type ErrorHandler func(err error)
+
 type msgHandlerMap struct {
 	syncMap sync.Map
 }
@@ -23,6 +37,10 @@ func (m *msgHandlerMap) store(msgType uint8, handler HandlerFunc) {
 	m.syncMap.Store(msgType, handler)
 }
 
+func (m *msgHandlerMap) delete(msgType uint8) {
+	m.syncMap.Delete(msgType)
+}
+
 func (m *msgHandlerMap) load(msgType uint8) (HandlerFunc, bool) {
 	handler, ok := m.syncMap.Load(msgType)
 	if !ok {
@@ -41,13 +59,18 @@ func newMsgHandlerMap(m map[uint8]HandlerFunc) *msgHandlerMap {
 	return mhm
 }
 
-var defaultHandlerMap = newMsgHandlerMap(
-	map[uint8]HandlerFunc{
-		messages.MsgTypeEchoRequest:                   handleEchoRequest,
-		messages.MsgTypeEchoResponse:                  handleEchoResponse,
-		messages.MsgTypeVersionNotSupportedIndication: handleVersionNotSupportedIndication,
-	},
-)
+// newDefaultHandlerMap returns the msgHandlerMap with the default HandlerFuncs.
+// It is created per Conn, so that the handlers added to a Conn do not affect the
+// others.
+func newDefaultHandlerMap() *msgHandlerMap {
+	return newMsgHandlerMap(
+		map[uint8]HandlerFunc{
+			messages.MsgTypeEchoRequest:                   handleEchoRequest,
+			messages.MsgTypeEchoResponse:                  handleEchoResponse,
+			messages.MsgTypeVersionNotSupportedIndication: handleVersionNotSupportedIndication,
+		},
+	)
+}
 
 func handleEchoRequest(c *Conn, senderAddr net.Addr, msg messages.Message) error {
 	// this should never happen, as the type should have been assured by
@@ -57,9 +80,7 @@ func handleEchoRequest(c *Conn, senderAddr net.Addr, msg messages.Message) error
 	}
 
 	// respond with EchoResponse.
-	return c.RespondTo(
-		senderAddr, msg, messages.NewEchoResponse(0, ies.NewRecovery(c.RestartCounter)),
-	)
+	return c.EchoResponse(senderAddr, msg)
 }
 
 func handleEchoResponse(c *Conn, senderAddr net.Addr, msg messages.Message) error {
@@ -83,3 +104,53 @@ func handleVersionNotSupportedIndication(c *Conn, senderAddr net.Addr, msg messa
 	// let's just return err anyway.
 	return &InvalidVersionError{Version: msg.Version()}
 }
+
+// AddHandler adds a HandlerFunc scoped to Session for the specific message type.
+//
+// The incoming message whose TEID and sender are associated with Session is handled
+// by the HandlerFunc added here instead of the one added to Conn, if any. This is
+// useful to implement the per-procedure state machine, e.g., to wait for the Delete
+// Bearer Request for the specific Session. The Middlewares added to Conn with Use
+// wrap these HandlerFuncs as well.
+//
+// The messages without TEID, such as Echo Request, are never handled by them.
+func (s *Session) AddHandler(msgType uint8, fn HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.handlers == nil {
+		s.handlers = newMsgHandlerMap(nil)
+	}
+	s.handlers.store(msgType, fn)
+}
+
+// AddHandlers adds multiple HandlerFuncs scoped to Session at a time.
+//
+// See (*Session) AddHandler for detailed usage.
+func (s *Session) AddHandlers(funcs map[uint8]HandlerFunc) {
+	for msgType, fn := range funcs {
+		s.AddHandler(msgType, fn)
+	}
+}
+
+// RemoveHandler removes the HandlerFunc scoped to Session for the specific message
+// type, and the message of the type is handled by the one added to Conn again.
+func (s *Session) RemoveHandler(msgType uint8) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.handlers == nil {
+		return
+	}
+	s.handlers.delete(msgType)
+}
+
+func (s *Session) handler(msgType uint8) (HandlerFunc, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.handlers == nil {
+		return nil, false
+	}
+	return s.handlers.load(msgType)
+}
@@ -1,11 +1,18 @@
 package v2_test
 
 import (
+	"encoding/binary"
+	"encoding/json"
 	"net"
+	"reflect"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
 )
 
 var testConn *v2.Conn
@@ -76,7 +83,7 @@ func TestGetIMSIByTEID(t *testing.T) {
 }
 
 func TestRemoveSession(t *testing.T) {
-	conn := *testConn // copy testConn
+	conn := &v2.Conn{Sessions: testConn.Sessions} // copy testConn
 	conn.RemoveSession(testConn.Sessions[0])
 
 	if conn.SessionCount() != len(testConn.Sessions)-1 {
@@ -97,7 +104,7 @@ func TestRemoveSession(t *testing.T) {
 }
 
 func TestRemoveSessionByIMSI(t *testing.T) {
-	conn := *testConn // copy testConn
+	conn := &v2.Conn{Sessions: testConn.Sessions} // copy testConn
 	conn.RemoveSessionByIMSI("001011234567891")
 
 	if conn.SessionCount() != len(testConn.Sessions)-1 {
@@ -122,3 +129,969 @@ func TestSessionCount(t *testing.T) {
 		t.Errorf("SessionCount is invalid. want: %d, got: %d", want, got)
 	}
 }
+
+func TestSetInitialSequenceNumber(t *testing.T) {
+	peer, err := net.ListenPacket("udp", "127.0.0.100:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peer.Close()
+	conn, err := v2.ListenAndServe(&net.UDPAddr{IP: net.IPv4(127, 0, 0, 101), Port: 2123}, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// sent returns the SequenceNumber in the Echo Request sent from conn.
+	sent := func() uint32 {
+		t.Helper()
+		if _, err := conn.EchoRequest(peer.LocalAddr()); err != nil {
+			t.Fatal(err)
+		}
+		if err := peer.SetReadDeadline(time.Now().Add(3 * time.Second)); err != nil {
+			t.Fatal(err)
+		}
+		buf := make([]byte, 1500)
+		n, _, err := peer.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg, err := messages.Parse(buf[:n])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return msg.Sequence()
+	}
+
+	// the first one applies to the Peer created, and the others to the existing one.
+	for _, seq := range []uint32{0, 1, 0x123456, 0xffffff} {
+		conn.SetInitialSequenceNumber(seq)
+		if got := sent(); got != seq {
+			t.Errorf("wrong SequenceNumber. want: %#x, got: %#x", seq, got)
+		}
+	}
+	if got := sent(); got != 0 {
+		t.Errorf("SequenceNumber should wrap around. want: %#x, got: %#x", 0, got)
+	}
+
+	seq := conn.RandomizeSequenceNumber()
+	if seq > 0xffffff {
+		t.Errorf("SequenceNumber exceeds 3 octets: %#x", seq)
+	}
+	if got := sent(); got != seq {
+		t.Errorf("wrong SequenceNumber. want: %#x, got: %#x", seq, got)
+	}
+}
+
+func TestSessionHistory(t *testing.T) {
+	sess := v2.NewSession(dummyAddr, &v2.Subscriber{IMSI: "001011234567891"})
+	sess.RecordMessage(v2.DirectionIncoming, dummyAddr, messages.NewEchoRequest(0))
+	if h := sess.History(); h != nil {
+		t.Fatalf("history should be nil if not enabled: %v", h)
+	}
+
+	sess.EnableHistory(2)
+	sess.RecordMessage(v2.DirectionIncoming, dummyAddr, messages.NewCreateSessionRequest(0, 1))
+	sess.RecordMessage(v2.DirectionOutgoing, dummyAddr, messages.NewCreateSessionResponse(
+		0, 1, ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+	))
+	sess.RecordMessage(v2.DirectionIncoming, dummyAddr, messages.NewDeleteSessionRequest(0, 2))
+
+	h := sess.History()
+	if len(h) != 2 {
+		t.Fatalf("wrong length of history. want: 2, got: %d", len(h))
+	}
+	if got, want := h[0].MsgType, messages.MsgTypeCreateSessionResponse; got != want {
+		t.Errorf("wrong MsgType. want: %d, got: %d", want, got)
+	}
+	if got, want := h[0].Direction, v2.DirectionOutgoing; got != want {
+		t.Errorf("wrong Direction. want: %s, got: %s", want, got)
+	}
+	if got, want := h[0].Cause, v2.CauseRequestAccepted; got != want {
+		t.Errorf("wrong Cause. want: %d, got: %d", want, got)
+	}
+	if got, want := h[1].Sequence, uint32(2); got != want {
+		t.Errorf("wrong Sequence. want: %d, got: %d", want, got)
+	}
+}
+
+func TestSessionSuspendResume(t *testing.T) {
+	sess := v2.NewSession(dummyAddr, &v2.Subscriber{IMSI: "001011234567899"})
+	sess.AddBearer("dedicated", v2.NewBearer(6, "", nil))
+
+	if err := sess.Resume(); err != v2.ErrSessionNotSuspended {
+		t.Errorf("Resume should fail when not suspended. got: %v", err)
+	}
+
+	if err := sess.Suspend(); err != nil {
+		t.Fatal(err)
+	}
+	if !sess.IsSuspended() {
+		t.Error("Session should be suspended")
+	}
+	for _, br := range sess.Bearers() {
+		if !br.IsSuspended() {
+			t.Errorf("Bearer %d should be suspended", br.EBI)
+		}
+	}
+	if err := sess.Suspend(); err != v2.ErrSessionSuspended {
+		t.Errorf("Suspend should fail when already suspended. got: %v", err)
+	}
+
+	if err := sess.Resume(); err != nil {
+		t.Fatal(err)
+	}
+	for _, br := range sess.Bearers() {
+		if br.IsSuspended() {
+			t.Errorf("Bearer %d should be resumed", br.EBI)
+		}
+	}
+}
+
+func TestSessionDedicatedBearers(t *testing.T) {
+	sess := v2.NewSession(dummyAddr, &v2.Subscriber{IMSI: "001011234567899"})
+
+	brs, err := sess.AddDedicatedBearers(
+		ies.NewBearerContext(
+			ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+			ies.NewEPSBearerID(6),
+			ies.NewBearerQoS(1, 2, 1, 1, 0x1111, 0x2222, 0x1111, 0x2222),
+			ies.NewChargingID(0xffffffff),
+		),
+		ies.NewBearerContext(
+			ies.NewCause(v2.CauseNoResourcesAvailable, 0, 0, 0, nil),
+			ies.NewEPSBearerID(7),
+		),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(brs) != 1 {
+		t.Fatalf("only the accepted Bearer should be added, got: %d", len(brs))
+	}
+
+	br, err := sess.LookupBearerByName(v2.DedicatedBearerName(6))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !br.PCI || br.PL != 2 || br.QCI != 1 || br.GBRDL != 0x2222 || br.ChargingID != 0xffffffff {
+		t.Errorf("wrong values in Bearer: %+v", br.QoSProfile)
+	}
+	if _, err := sess.LookupBearerByEBI(7); err == nil {
+		t.Error("rejected Bearer should not be added")
+	}
+
+	if err := sess.UpdateBearers(
+		ies.NewBearerContext(
+			ies.NewEPSBearerID(6),
+			ies.NewBearerQoS(1, 2, 1, 2, 0x3333, 0x4444, 0x3333, 0x4444),
+		),
+	); err != nil {
+		t.Fatal(err)
+	}
+	if br.QCI != 2 || br.MBRUL != 0x3333 {
+		t.Errorf("Bearer not updated: %+v", br.QoSProfile)
+	}
+
+	if err := sess.UpdateBearers(ies.NewBearerContext(ies.NewEPSBearerID(8))); err == nil {
+		t.Error("updating unknown Bearer should fail")
+	}
+}
+
+func TestGetSessionByTEIDLinkLocal(t *testing.T) {
+	var lo *net.Interface
+	ifis, err := net.Interfaces()
+	if err != nil {
+		t.Skip(err)
+	}
+	for i := range ifis {
+		if ifis[i].Flags&net.FlagLoopback != 0 {
+			lo = &ifis[i]
+			break
+		}
+	}
+	if lo == nil {
+		t.Skip("no loopback interface found")
+	}
+
+	// the zone given by the index should match the one given by the name.
+	sess := v2.NewSession(
+		&net.UDPAddr{IP: net.ParseIP("fe80::1"), Port: 2123, Zone: strconv.Itoa(lo.Index)},
+		&v2.Subscriber{IMSI: "001011234567890"},
+	)
+	sess.AddTEID(v2.IFTypeS11MMEGTPC, 0x11111111)
+	c := &v2.Conn{Sessions: []*v2.Session{sess}}
+
+	got, err := c.GetSessionByTEID(0x11111111, &net.UDPAddr{IP: net.ParseIP("fe80::1"), Port: 2123, Zone: lo.Name})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != sess {
+		t.Errorf("got wrong session: %s", got.IMSI)
+	}
+
+	if _, err := c.GetSessionByTEID(0x11111111, &net.UDPAddr{IP: net.ParseIP("fe80::1"), Port: 2123}); err == nil {
+		t.Error("the address without zone should not match")
+	}
+}
+
+func TestPeerAddrFromFTEID(t *testing.T) {
+	c := &v2.Conn{}
+	raddr, err := c.PeerAddrFromFTEID(ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 1, "1.1.1.1", "2001::1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := raddr.String(), "[2001::1]:2123"; got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+
+	raddr, err = c.PeerAddrFromFTEID(ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 1, "1.1.1.1", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := raddr.String(), "1.1.1.1:2123"; got != want {
+		t.Errorf("want %s, got %s", want, got)
+	}
+}
+
+func TestSessionAccessBearers(t *testing.T) {
+	sess := v2.NewSession(dummyAddr, &v2.Subscriber{IMSI: "001011234567890"})
+	sess.GetDefaultBearer().EBI = 5
+	sess.AddBearer(v2.DedicatedBearerName(6), v2.NewBearer(6, "", &v2.QoSProfile{}))
+	sess.AddTEID(v2.IFTypeS1UeNodeBGTPU, 0x11111111)
+	sess.AddTEID(v2.IFTypeS11MMEGTPC, 0x22222222)
+
+	sess.ReleaseAccessBearers()
+	if _, err := sess.GetTEID(v2.IFTypeS1UeNodeBGTPU); err == nil {
+		t.Error("S1-U eNodeB F-TEID should be released")
+	}
+	if _, err := sess.GetTEID(v2.IFTypeS11MMEGTPC); err != nil {
+		t.Error("S11 MME F-TEID should not be released")
+	}
+	for _, br := range sess.Bearers() {
+		if !br.IsAccessReleased() {
+			t.Errorf("Bearer %d should be released", br.EBI)
+		}
+	}
+
+	if err := sess.ModifyAccessBearers(
+		ies.NewBearerContext(
+			ies.NewEPSBearerID(5),
+			ies.NewFullyQualifiedTEID(v2.IFTypeS1UeNodeBGTPU, 0x33333333, "1.1.1.1", ""),
+		),
+		ies.NewBearerContext(ies.NewEPSBearerID(6)).WithInstance(1),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	br := sess.GetDefaultBearer()
+	if br.IsAccessReleased() || br.OutgoingTEID() != 0x33333333 || br.RemoteAddress().String() != "1.1.1.1:2152" {
+		t.Errorf("Bearer not modified: %v, %x, %v", br.IsAccessReleased(), br.OutgoingTEID(), br.RemoteAddress())
+	}
+	if teid, err := sess.GetTEID(v2.IFTypeS1UeNodeBGTPU); err != nil || teid != 0x33333333 {
+		t.Errorf("wrong S1-U eNodeB F-TEID: %x, %v", teid, err)
+	}
+	if _, err := sess.LookupBearerByEBI(6); err == nil {
+		t.Error("Bearer to be removed should be removed")
+	}
+
+	if err := sess.ModifyAccessBearers(ies.NewBearerContext(ies.NewEPSBearerID(7))); err == nil {
+		t.Error("modifying unknown Bearer should fail")
+	}
+}
+
+func TestEnumNames(t *testing.T) {
+	if got := v2.Cause(v2.CauseRequestAccepted).String(); got != "RequestAccepted" {
+		t.Errorf("wrong name: %s", got)
+	}
+	if got := v2.InterfaceType(v2.IFTypeS11MMEGTPC).String(); got != "S11MMEGTPC" {
+		t.Errorf("wrong name: %s", got)
+	}
+	if got := v2.RATType(0xff).String(); got != "RATType(255)" {
+		t.Errorf("wrong name for unknown value: %s", got)
+	}
+
+	for _, name := range []string{"ipv4v6", "PDNTypeIPv4v6"} {
+		v, err := v2.ParsePDNType(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if uint8(v) != v2.PDNTypeIPv4v6 {
+			t.Errorf("wrong value for %s: %d", name, v)
+		}
+	}
+	if v, err := v2.ParseRATType("RATType(255)"); err != nil || v != 0xff {
+		t.Errorf("unknown value should be parsed: %d, %v", v, err)
+	}
+	if _, err := v2.ParseAPNRestriction("Public3"); err == nil {
+		t.Error("unknown name should not be parsed")
+	}
+
+	b, err := json.Marshal(struct{ Cause v2.Cause }{v2.Cause(v2.CauseContextNotFound)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"Cause":"ContextNotFound"}` {
+		t.Errorf("wrong JSON: %s", b)
+	}
+
+	var got struct{ Cause v2.Cause }
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if uint8(got.Cause) != v2.CauseContextNotFound {
+		t.Errorf("wrong value from JSON: %d", got.Cause)
+	}
+}
+
+func TestSessionModifyBearerQoS(t *testing.T) {
+	newSession := func() *v2.Session {
+		sess := v2.NewSession(dummyAddr, &v2.Subscriber{IMSI: "001011234567890"})
+		sess.AddBearer(v2.DedicatedBearerName(6), v2.NewBearer(6, "", &v2.QoSProfile{}))
+		return sess
+	}
+	local, remote := newSession(), newSession()
+
+	qos := &v2.QoSProfile{PCI: true, PL: 2, QCI: 1, MBRUL: 0x1111, MBRDL: 0x2222, GBRUL: 0x3333, GBRDL: 0x4444}
+	bc, err := local.ModifyBearerQoS(6, qos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	qos.QCI = 9
+
+	// the Bearer Context applied to the peer should result in the same QoSProfile.
+	if err := remote.UpdateBearers(bc); err != nil {
+		t.Fatal(err)
+	}
+	lbr, _ := local.LookupBearerByEBI(6)
+	rbr, _ := remote.LookupBearerByEBI(6)
+	if *lbr.QoSProfile != *rbr.QoSProfile {
+		t.Errorf("QoSProfile not in sync.\nlocal:  %+v\nremote: %+v", lbr.QoSProfile, rbr.QoSProfile)
+	}
+	if lbr.QCI != 1 {
+		t.Errorf("QoSProfile should be copied, got QCI: %d", lbr.QCI)
+	}
+
+	if _, err := local.ModifyBearerQoS(7, qos); err == nil {
+		t.Error("modifying unknown Bearer should fail")
+	}
+}
+
+func TestNodeSelectorFQDN(t *testing.T) {
+	cases := []struct {
+		got, want string
+	}{
+		{v2.APNFQDN("internet", "123", "45"), "internet.apn.epc.mnc045.mcc123.3gppnetwork.org"},
+		{v2.APNFQDN("Internet.mnc045.mcc123.gprs", "999", "99"), "internet.apn.epc.mnc045.mcc123.3gppnetwork.org"},
+		{v2.TAIFQDN(0x0b12, "123", "456"), "tac-lb12.tac-hb0b.tac.epc.mnc456.mcc123.3gppnetwork.org"},
+	}
+	for _, c := range cases {
+		if c.got != c.want {
+			t.Errorf("wrong FQDN. want %s, got: %s", c.want, c.got)
+		}
+	}
+}
+
+// dnsName encodes name in the DNS wire format without compression.
+func dnsName(name string) []byte {
+	var b []byte
+	for _, label := range strings.Split(strings.TrimSuffix(name, "."), ".") {
+		b = append(b, uint8(len(label)))
+		b = append(b, label...)
+	}
+	return append(b, 0)
+}
+
+func naptrRData(order, pref uint16, flags, service, replacement string) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint16(b[0:2], order)
+	binary.BigEndian.PutUint16(b[2:4], pref)
+	for _, s := range []string{flags, service, ""} {
+		b = append(b, uint8(len(s)))
+		b = append(b, s...)
+	}
+	return append(b, dnsName(replacement)...)
+}
+
+func srvRData(priority, weight, port uint16, target string) []byte {
+	b := make([]byte, 6)
+	binary.BigEndian.PutUint16(b[0:2], priority)
+	binary.BigEndian.PutUint16(b[2:4], weight)
+	binary.BigEndian.PutUint16(b[4:6], port)
+	return append(b, dnsName(target)...)
+}
+
+// serveDNS serves the records in zone, which is keyed by the name and the type, until
+// conn is closed.
+func serveDNS(conn net.PacketConn, zone map[string]map[uint16][][]byte) {
+	buf := make([]byte, 512)
+	for {
+		n, raddr, err := conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		q := buf[:n]
+
+		var labels []string
+		offset := 12
+		for q[offset] != 0 {
+			l := int(q[offset])
+			labels = append(labels, string(q[offset+1:offset+1+l]))
+			offset += 1 + l
+		}
+		qtype := binary.BigEndian.Uint16(q[offset+1 : offset+3])
+		question := q[12 : offset+5]
+
+		answers := zone[strings.Join(labels, ".")][qtype]
+		rsp := make([]byte, 12)
+		copy(rsp[0:2], q[0:2])
+		binary.BigEndian.PutUint16(rsp[2:4], 0x8180)
+		binary.BigEndian.PutUint16(rsp[4:6], 1)
+		binary.BigEndian.PutUint16(rsp[6:8], uint16(len(answers)))
+		rsp = append(rsp, question...)
+		for _, rdata := range answers {
+			rr := []byte{0xc0, 0x0c, 0, 0, 0x00, 0x01, 0x00, 0x00, 0x00, 0x3c, 0, 0}
+			binary.BigEndian.PutUint16(rr[2:4], qtype)
+			binary.BigEndian.PutUint16(rr[10:12], uint16(len(rdata)))
+			rsp = append(rsp, rr...)
+			rsp = append(rsp, rdata...)
+		}
+		if _, err := conn.WriteTo(rsp, raddr); err != nil {
+			return
+		}
+	}
+}
+
+func TestDNSNodeSelector(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	go serveDNS(conn, map[string]map[uint16][][]byte{
+		"internet.apn.epc.mnc045.mcc123.3gppnetwork.org": {
+			35: {
+				naptrRData(100, 20, "a", "x-3gpp-pgw:x-s5-gtp:x-s8-gtp", "pgw2.example.org"),
+				naptrRData(100, 10, "s", "x-3gpp-pgw:x-s5-gtp", "_gtp._udp.pgw1.example.org"),
+				naptrRData(100, 5, "a", "x-3gpp-sgw:x-s11", "sgw.example.org"),
+			},
+		},
+		"_gtp._udp.pgw1.example.org": {
+			33: {srvRData(0, 10, 2124, "pgw1.example.org")},
+		},
+		"pgw1.example.org": {
+			1: {{10, 0, 0, 1}},
+		},
+		"pgw2.example.org": {
+			1:  {{10, 0, 0, 2}},
+			28: {net.ParseIP("2001:db8::2")},
+		},
+	})
+
+	ns := v2.NewDNSNodeSelector(conn.LocalAddr().String(), time.Second)
+	addrs, err := ns.Select(v2.APNFQDN("internet", "123", "45"), v2.ServicePGWS5GTP)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, addr := range addrs {
+		got = append(got, addr.String())
+	}
+	want := []string{"10.0.0.1:2124", "10.0.0.2:2123", "[2001:db8::2]:2123"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("wrong addresses selected. want %v, got: %v", want, got)
+	}
+
+	if _, err := ns.Select(v2.APNFQDN("ims", "123", "45"), v2.ServicePGWS5GTP); err != v2.ErrNodeNotFound {
+		t.Errorf("unknown APN should result in ErrNodeNotFound, got: %v", err)
+	}
+}
+
+func TestNewSessionFromIEs(t *testing.T) {
+	sess, err := v2.NewSessionFromIEs(
+		dummyAddr,
+		ies.NewIMSI("123451234567890"),
+		ies.NewChargingCharacteristics(0x0800),
+		ies.NewAggregateMaximumBitRate(0x1111, 0x2222),
+		ies.NewBearerContext(ies.NewEPSBearerID(5), ies.NewChargingID(0xdeadbeef)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chr := sess.GetChargingCharacteristics(); chr != 0x0800 {
+		t.Errorf("wrong Charging Characteristics: %#x", chr)
+	}
+	if up, down := sess.GetAPNAMBR(); up != 0x1111 || down != 0x2222 {
+		t.Errorf("wrong APN-AMBR: %#x, %#x", up, down)
+	}
+	if id := sess.GetDefaultBearer().GetChargingID(); id != 0xdeadbeef {
+		t.Errorf("wrong Charging ID: %#x", id)
+	}
+}
+
+func TestDumpRestoreSessions(t *testing.T) {
+	src := &v2.Conn{}
+	sess := v2.NewSession(dummyAddr, &v2.Subscriber{
+		IMSI: "001011234567890", MSISDN: "819012345678",
+		Location: &v2.Location{MCC: "001", MNC: "01", TAI: 0x0001},
+	})
+	sess.AddTEID(v2.IFTypeS11MMEGTPC, 0x11111111)
+	sess.AddTEID(v2.IFTypeS11S4SGWGTPC, 0x22222222)
+	sess.SetChargingCharacteristics(0x0800)
+	sess.SetAPNAMBR(0x1111, 0x2222)
+	sess.SetISRActive(true)
+	if err := sess.Activate(); err != nil {
+		t.Fatal(err)
+	}
+
+	br := sess.GetDefaultBearer()
+	br.EBI = 5
+	br.APN = "some.apn.example"
+	br.SubscriberIP = "10.10.10.10"
+	br.QCI = 9
+	br.SetIncomingTEID(0x33333333)
+	br.SetOutgoingTEID(0x44444444)
+	br.SetRemoteAddress(&net.UDPAddr{IP: net.IP{10, 0, 0, 1}, Port: 2152})
+	sess.AddBearer("dedicated", v2.NewBearer(6, "some.apn.example", &v2.QoSProfile{QCI: 1, GBRUL: 64000}))
+	src.AddSession(sess)
+
+	b, err := src.DumpSessions()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dst := &v2.Conn{}
+	if err := dst.RestoreSessions(b); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := dst.GetSessionByTEID(0x22222222, dummyAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.IsActive() || got.MSISDN != "819012345678" || got.TAI != 0x0001 {
+		t.Errorf("Session is not restored correctly: %+v", got.Subscriber)
+	}
+	if teid, err := got.GetTEID(v2.IFTypeS11MMEGTPC); err != nil || teid != 0x11111111 {
+		t.Errorf("wrong TEID restored. want %#x, got: %#x, %v", 0x11111111, teid, err)
+	}
+	if up, down := got.GetAPNAMBR(); got.GetChargingCharacteristics() != 0x0800 || up != 0x1111 || down != 0x2222 {
+		t.Errorf("wrong charging information restored: %#x, %#x, %#x", got.GetChargingCharacteristics(), up, down)
+	}
+	if !got.IsISRActive() {
+		t.Error("ISR state is not restored")
+	}
+
+	gotBr, err := got.LookupBearerByEBI(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotBr.APN != br.APN || gotBr.SubscriberIP != br.SubscriberIP || gotBr.QCI != br.QCI {
+		t.Errorf("Bearer is not restored correctly: %+v", gotBr)
+	}
+	if gotBr.IncomingTEID() != 0x33333333 || gotBr.OutgoingTEID() != 0x44444444 {
+		t.Errorf("wrong TEIDs of Bearer restored: %#x, %#x", gotBr.IncomingTEID(), gotBr.OutgoingTEID())
+	}
+	if addr := gotBr.RemoteAddress(); addr == nil || addr.String() != "10.0.0.1:2152" {
+		t.Errorf("wrong remote address of Bearer restored: %v", addr)
+	}
+	if count := got.BearerCount(); count != 2 {
+		t.Errorf("wrong BearerCount. want %d, got: %d", 2, count)
+	}
+
+	if err := dst.RestoreSessions([]byte(`{"version": 0}`)); err == nil {
+		t.Error("RestoreSessions should fail with unknown version")
+	}
+}
+
+func TestUE(t *testing.T) {
+	c := &v2.Conn{}
+
+	var events []v2.UEEvent
+	c.SetUEEventHandler(func(ue *v2.UE, sess *v2.Session, event v2.UEEvent) {
+		events = append(events, event)
+	})
+
+	newSession := func(ebi uint8, apn string) *v2.Session {
+		sess := v2.NewSession(dummyAddr, &v2.Subscriber{IMSI: "001011234567890"})
+		br := sess.GetDefaultBearer()
+		br.EBI, br.APN = ebi, apn
+		return sess
+	}
+
+	internet := newSession(5, "internet")
+	ims := newSession(6, "ims")
+	ims.AddBearer("voice", v2.NewBearer(7, "ims", &v2.QoSProfile{QCI: 1}))
+	c.AddSession(internet)
+	c.AddSession(ims)
+
+	ue, err := c.GetUE("001011234567890")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ue.Sessions) != 2 {
+		t.Fatalf("wrong number of Sessions. want %d, got: %d", 2, len(ue.Sessions))
+	}
+	if sess, err := ue.SessionByAPN("ims"); err != nil || sess != ims {
+		t.Errorf("wrong Session looked up by APN: %v", err)
+	}
+	if sess, err := ue.SessionByEBI(7); err != nil || sess != ims {
+		t.Errorf("wrong Session looked up by EBI: %v", err)
+	}
+	if count := ue.BearerCount(); count != 3 {
+		t.Errorf("wrong BearerCount. want %d, got: %d", 3, count)
+	}
+	if sess, err := c.GetSessionByIMSI("001011234567890"); err != nil || sess != internet {
+		t.Errorf("GetSessionByIMSI should return the first PDN connection: %v", err)
+	}
+
+	// the Session of the same PDN connection replaces the existing one.
+	renewed := newSession(5, "internet")
+	c.AddSession(renewed)
+	if ue, _ := c.GetUE("001011234567890"); len(ue.Sessions) != 2 {
+		t.Errorf("wrong number of Sessions after replaced. want %d, got: %d", 2, len(ue.Sessions))
+	}
+
+	c.RemoveSession(ims)
+	if ue, _ := c.GetUE("001011234567890"); len(ue.Sessions) != 1 || ue.Sessions[0] != renewed {
+		t.Errorf("RemoveSession should keep the other PDN connection: %v", ue.Sessions)
+	}
+	c.RemoveSessionByIMSI("001011234567890")
+	if _, err := c.GetUE("001011234567890"); err == nil {
+		t.Error("GetUE should fail after all the Sessions are removed")
+	}
+
+	want := []v2.UEEvent{
+		v2.UEEventRegistered, v2.UEEventSessionAdded, v2.UEEventSessionAdded,
+		v2.UEEventSessionRemoved, v2.UEEventSessionAdded,
+		v2.UEEventSessionRemoved,
+		v2.UEEventSessionRemoved, v2.UEEventDeregistered,
+	}
+	if !reflect.DeepEqual(want, events) {
+		t.Errorf("wrong UEEvents. want %v, got: %v", want, events)
+	}
+}
+
+func TestEchoIEs(t *testing.T) {
+	req := messages.NewDeleteBearerRequest(
+		0x11111111, 0,
+		ies.NewEPSBearerID(5),
+		ies.NewEPSBearerID(6).WithInstance(1),
+	)
+	rsp, err := v2.EchoIEs(req, messages.NewDeleteBearerResponse(
+		0x22222222, 0,
+		ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dbRsp, ok := rsp.(*messages.DeleteBearerResponse)
+	if !ok {
+		t.Fatalf("got unexpected type: %T", rsp)
+	}
+	if dbRsp.TEID() != 0x22222222 {
+		t.Errorf("wrong TEID: %#x", dbRsp.TEID())
+	}
+	if dbRsp.LinkedEBI == nil || dbRsp.LinkedEBI.MustEPSBearerID() != 5 {
+		t.Errorf("wrong Linked EBI: %v", dbRsp.LinkedEBI)
+	}
+	if dbRsp.BearerContexts == nil {
+		t.Fatal("Bearer Context is not echoed")
+	}
+	bc := dbRsp.BearerContexts.ChildIEs
+	if got := ies.Find(bc, ies.EPSBearerID, 0); len(got) != 1 || got[0].MustEPSBearerID() != 6 {
+		t.Errorf("wrong EPS Bearer ID in Bearer Context: %v", got)
+	}
+	if got := ies.Find(bc, ies.Cause, 0); len(got) != 1 || got[0].MustCause() != v2.CauseRequestAccepted {
+		t.Errorf("wrong Cause in Bearer Context: %v", got)
+	}
+
+	brc := messages.NewBearerResourceCommand(
+		0, 0,
+		ies.NewEPSBearerID(5),
+		ies.NewProcedureTransactionID(1),
+	)
+	rsp, err = v2.EchoIEs(brc, messages.NewBearerResourceFailureIndication(
+		0, 0,
+		ies.NewCause(v2.CauseServiceDenied, 0, 0, 0, nil),
+		ies.NewProcedureTransactionID(2),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	brfi, ok := rsp.(*messages.BearerResourceFailureIndication)
+	if !ok {
+		t.Fatalf("got unexpected type: %T", rsp)
+	}
+	if brfi.LinkedEBI == nil || brfi.LinkedEBI.MustEPSBearerID() != 5 {
+		t.Errorf("wrong Linked EBI: %v", brfi.LinkedEBI)
+	}
+	if brfi.PTI == nil || brfi.PTI.MustProcedureTransactionID() != 2 {
+		t.Errorf("PTI set explicitly should be kept: %v", brfi.PTI)
+	}
+
+	echo := messages.NewEchoResponse(0, ies.NewRecovery(1))
+	if got, err := v2.EchoIEs(brc, echo); err != nil || got != echo {
+		t.Errorf("message without rules should be returned as it is: %v, %v", got, err)
+	}
+
+	// the IEs in the request are kept as they are even if echoed with another instance.
+	rules := v2.EchoRules{
+		messages.MsgTypeDeleteBearerResponse: {{
+			From: v2.MandatoryIE{Type: ies.EPSBearerID, Instance: 0},
+			To:   v2.MandatoryIE{Type: ies.EPSBearerID, Instance: 1},
+		}},
+	}
+	if _, err := rules.Apply(req, messages.NewDeleteBearerResponse(0, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if got := req.LinkedEBI.Instance(); got != 0 {
+		t.Errorf("instance of the IE in request changed: %d", got)
+	}
+}
+
+func TestTEIDAllocator(t *testing.T) {
+	a := v2.NewRangeTEIDAllocator(1, 3)
+	for want := uint32(1); want <= 3; want++ {
+		got, err := a.Allocate(v2.IFTypeS11MMEGTPC)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("wrong TEID. want %d, got: %d", want, got)
+		}
+	}
+	if _, err := a.Allocate(v2.IFTypeS11MMEGTPC); err != v2.ErrTEIDExhausted {
+		t.Errorf("Allocate should fail with ErrTEIDExhausted, got: %v", err)
+	}
+	if got, err := a.Allocate(v2.IFTypeS1UeNodeBGTPU); err != nil || got != 1 {
+		t.Errorf("TEIDs should be allocated per interface type, got: %d, %v", got, err)
+	}
+
+	c := &v2.Conn{}
+	c.SetTEIDAllocator(a)
+	a.Release(v2.IFTypeS11MMEGTPC, 2)
+	fteid, err := c.AllocateFTEID(v2.IFTypeS11MMEGTPC, "127.0.0.1", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fteid.MustTEID(); got != 2 {
+		t.Errorf("released TEID should be reused, got: %d", got)
+	}
+
+	// the TEIDs are released when the Session is removed.
+	sess := v2.NewSession(dummyAddr, &v2.Subscriber{IMSI: "001011234567899"})
+	sess.AddTEID(v2.IFTypeS11MMEGTPC, 2)
+	c.AddSession(sess)
+	c.RemoveSession(sess)
+	if got := c.NewFTEID(v2.IFTypeS11MMEGTPC, "127.0.0.1", "").MustTEID(); got != 2 {
+		t.Errorf("TEID of removed Session should be reused, got: %d", got)
+	}
+
+	r := v2.NewRandomTEIDAllocator()
+	seen := map[uint32]bool{}
+	for i := 0; i < 100; i++ {
+		teid, err := r.Allocate(v2.IFTypeS11MMEGTPC)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if teid == 0 || seen[teid] {
+			t.Fatalf("TEID should be unique and non-zero, got: %d", teid)
+		}
+		seen[teid] = true
+	}
+}
+
+func TestSessionHooks(t *testing.T) {
+	var events []string
+	c := &v2.Conn{}
+	c.SetSessionHooks(v2.SessionHooks{
+		OnSessionCreated:   func(sess *v2.Session) { events = append(events, "created") },
+		OnSessionActivated: func(sess *v2.Session) { events = append(events, "activated") },
+		OnSessionDeleted:   func(sess *v2.Session) { events = append(events, "deleted") },
+		OnBearerModified: func(sess *v2.Session, br *v2.Bearer, change v2.BearerChange) {
+			events = append(events, "bearer "+strconv.Itoa(int(br.EBI))+" "+strconv.Itoa(int(change)))
+		},
+	})
+
+	sess := v2.NewSession(dummyAddr, &v2.Subscriber{IMSI: "001011234567899"})
+	sess.GetDefaultBearer().EBI = 5
+	if err := sess.Activate(); err != nil {
+		t.Fatal(err)
+	}
+	c.AddSession(sess)
+	if err := sess.AddDedicatedBearer(&v2.Bearer{EBI: 6, QoSProfile: &v2.QoSProfile{}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sess.UpdateBearers(
+		ies.NewBearerContext(ies.NewEPSBearerID(6), ies.NewChargingID(1)),
+	); err != nil {
+		t.Fatal(err)
+	}
+	sess.RemoveBearerByEBI(6)
+	sess.RemoveBearerByEBI(7)
+	if err := sess.Deactivate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := sess.Activate(); err != nil {
+		t.Fatal(err)
+	}
+	c.RemoveSession(sess)
+
+	// Session removed from Conn is no longer notified.
+	sess.SetDefaultBearer(&v2.Bearer{EBI: 5, QoSProfile: &v2.QoSProfile{}})
+
+	want := []string{
+		"created",
+		"bearer 6 0",
+		"bearer 6 1",
+		"bearer 6 2",
+		"activated",
+		"deleted",
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("wrong events. want %v, got: %v", want, events)
+	}
+}
+
+func TestSessionLookup(t *testing.T) {
+	otherAddr := &net.UDPAddr{IP: net.IP{192, 168, 0, 1}, Port: 2123}
+	conn := &v2.Conn{}
+	for i, c := range []struct {
+		addr net.Addr
+		apn  string
+	}{
+		{dummyAddr, "internet"},
+		{dummyAddr, "ims"},
+		{otherAddr, "Internet"},
+	} {
+		sess := v2.NewSession(c.addr, &v2.Subscriber{IMSI: "00101123456789" + strconv.Itoa(i)})
+		sess.GetDefaultBearer().APN = c.apn
+		conn.AddSession(sess)
+	}
+
+	imsis := func(sessions []*v2.Session) []string {
+		var s []string
+		for _, sess := range sessions {
+			s = append(s, sess.IMSI)
+		}
+		return s
+	}
+	if got, want := imsis(conn.GetSessionsByAPN("internet")), []string{"001011234567890", "001011234567892"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong Sessions by APN. want %v, got: %v", want, got)
+	}
+	if got, want := imsis(conn.GetSessionsByPeer(dummyAddr)), []string{"001011234567890", "001011234567891"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong Sessions by peer. want %v, got: %v", want, got)
+	}
+	if got := conn.GetSessionsByAPN("unknown"); len(got) != 0 {
+		t.Errorf("unexpected Sessions: %v", imsis(got))
+	}
+
+	var visited []string
+	conn.RangeSessions(func(sess *v2.Session) bool {
+		visited = append(visited, sess.IMSI)
+		conn.RemoveSession(sess)
+		return len(visited) < 2
+	})
+	if want := []string{"001011234567890", "001011234567891"}; !reflect.DeepEqual(visited, want) {
+		t.Errorf("wrong Sessions visited. want %v, got: %v", want, visited)
+	}
+	if got := imsis(conn.Sessions); !reflect.DeepEqual(got, []string{"001011234567892"}) {
+		t.Errorf("wrong Sessions left: %v", got)
+	}
+}
+
+func TestSessionBearerAccessors(t *testing.T) {
+	sess := v2.NewSession(dummyAddr, &v2.Subscriber{IMSI: "001011234567891", Location: &v2.Location{MCC: "001"}})
+	br := sess.GetDefaultBearer()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			br.SetEBI(5)
+			br.SetAPN("internet")
+			br.SetChargingID(uint32(i))
+			br.SetQoSProfile(&v2.QoSProfile{QCI: 9})
+			sess.UpdatePeerAddr(dummyAddr)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		_ = br.GetEBI()
+		_ = br.GetQoSProfile()
+		_ = sess.PeerAddr()
+		_ = sess.LookupEBIByTEID(1)
+	}
+	<-done
+
+	if got := br.GetEBI(); got != 5 {
+		t.Errorf("wrong EBI: %d", got)
+	}
+	if got := br.GetAPN(); got != "internet" {
+		t.Errorf("wrong APN: %s", got)
+	}
+	qos := br.GetQoSProfile()
+	if qos == nil || qos.QCI != 9 {
+		t.Fatalf("wrong QoSProfile: %v", qos)
+	}
+	qos.QCI = 1
+	if got := br.GetQoSProfile().QCI; got != 9 {
+		t.Errorf("QoSProfile returned is not a copy: %d", got)
+	}
+
+	sub := sess.GetSubscriber()
+	sub.Location.MCC = "999"
+	if got := sess.GetSubscriber().Location.MCC; got != "001" {
+		t.Errorf("Subscriber returned is not a copy: %s", got)
+	}
+	sess.SetSubscriber(&v2.Subscriber{IMSI: "001011234567892"})
+	if got := sess.GetIMSI(); got != "001011234567892" {
+		t.Errorf("wrong IMSI: %s", got)
+	}
+}
+
+func TestSessionValidate(t *testing.T) {
+	sess := v2.NewSession(dummyAddr, &v2.Subscriber{IMSI: "001011234567891"})
+	sess.AddTEID(v2.IFTypeS11MMEGTPC, 1)
+
+	if got, ok := sess.TEID(v2.IFTypeS11MMEGTPC); !ok || got != 1 {
+		t.Errorf("wrong TEID: %d, %v", got, ok)
+	}
+	if _, ok := sess.TEID(v2.IFTypeS11S4SGWGTPC); ok {
+		t.Error("TEID found for the interface type not added")
+	}
+	if got := sess.MustTEID(v2.IFTypeS11S4SGWGTPC); got != 0 {
+		t.Errorf("wrong MustTEID: %d", got)
+	}
+
+	err := sess.Validate(v2.IFTypeS11MMEGTPC, v2.IFTypeS11S4SGWGTPC, v2.IFTypeS1USGWGTPU)
+	e, ok := err.(*v2.SessionIncompleteError)
+	if !ok {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []uint8{v2.IFTypeS11S4SGWGTPC, v2.IFTypeS1USGWGTPU}; !reflect.DeepEqual(e.MissingIFTypes, want) {
+		t.Errorf("wrong MissingIFTypes: got %v, want %v", e.MissingIFTypes, want)
+	}
+	if want := []string{"EBI"}; !reflect.DeepEqual(e.MissingParams, want) {
+		t.Errorf("wrong MissingParams: got %v, want %v", e.MissingParams, want)
+	}
+	if want := "incomplete session, IMSI: 001011234567891, missing: TEIDs for interface types [11 1], EBI"; e.Error() != want {
+		t.Errorf("wrong message: got %q, want %q", e.Error(), want)
+	}
+
+	sess.AddTEID(v2.IFTypeS11S4SGWGTPC, 2)
+	sess.AddTEID(v2.IFTypeS1USGWGTPU, 3)
+	sess.GetDefaultBearer().SetEBI(5)
+	if err := sess.Validate(v2.IFTypeS11MMEGTPC, v2.IFTypeS11S4SGWGTPC, v2.IFTypeS1USGWGTPU); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// the Subscriber is validated as well.
+	sess.SetSubscriber(&v2.Subscriber{IMSI: "00101123456789X"})
+	if err := sess.Validate(); err == nil {
+		t.Error("malformed IMSI not detected")
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// Direction is the direction of the message recorded in the history of Session.
+type Direction uint8
+
+// Direction definitions.
+const (
+	DirectionIncoming Direction = iota
+	DirectionOutgoing
+)
+
+// String returns the name of Direction.
+func (d Direction) String() string {
+	if d == DirectionOutgoing {
+		return "Outgoing"
+	}
+	return "Incoming"
+}
+
+// SessionEvent is a signaling event recorded in the history of Session.
+type SessionEvent struct {
+	Time        time.Time
+	Direction   Direction
+	Peer        net.Addr
+	MsgType     uint8
+	MsgTypeName string
+	Sequence    uint32
+	// Cause is the value in Cause IE, or 0 if the message has no Cause IE.
+	Cause uint8
+}
+
+// sessionHistory is a bounded list of SessionEvents. The oldest one is discarded
+// when the number of events exceeds size.
+type sessionHistory struct {
+	mu     sync.Mutex
+	size   int
+	events []SessionEvent
+}
+
+func (h *sessionHistory) add(ev SessionEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.events) >= h.size {
+		copy(h.events, h.events[len(h.events)-h.size+1:])
+		h.events = h.events[:h.size-1]
+	}
+	h.events = append(h.events, ev)
+}
+
+func (h *sessionHistory) list() []SessionEvent {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return append([]SessionEvent{}, h.events...)
+}
+
+// EnableHistory starts recording the signaling events of Session, keeping the latest
+// ones up to size. Giving 0 or negative value disables it and discards the history.
+//
+// The history is disabled by default. Use (*Conn) EnableSessionHistory to enable it
+// for all the Sessions on a Conn.
+func (s *Session) EnableHistory(size int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if size <= 0 {
+		s.history = nil
+		return
+	}
+
+	h := &sessionHistory{size: size}
+	if s.history != nil {
+		for _, ev := range s.history.list() {
+			h.add(ev)
+		}
+	}
+	s.history = h
+}
+
+// History returns the signaling events recorded in Session, from the oldest one.
+// It returns nil if the history is not enabled.
+func (s *Session) History() []SessionEvent {
+	h := s.sessionHistory()
+	if h == nil {
+		return nil
+	}
+	return h.list()
+}
+
+// RecordMessage records msg exchanged with peer in the history of Session.
+//
+// Conn records the messages sent and received automatically if the Session can be
+// found by TEID. This is useful to record the ones without TEID, e.g., the Create
+// Session Request received before the Session is created.
+// It does nothing if the history is not enabled.
+func (s *Session) RecordMessage(dir Direction, peer net.Addr, msg messages.Message) {
+	h := s.sessionHistory()
+	if h == nil {
+		return
+	}
+
+	h.add(SessionEvent{
+		Time:        time.Now(),
+		Direction:   dir,
+		Peer:        peer,
+		MsgType:     msg.MessageType(),
+		MsgTypeName: msg.MessageTypeName(),
+		Sequence:    msg.Sequence(),
+		Cause:       causeOf(msg),
+	})
+}
+
+func (s *Session) sessionHistory() *sessionHistory {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.history
+}
+
+// causeOf returns the value of the top-level Cause IE in msg, or 0 if not found.
+func causeOf(msg messages.Message) uint8 {
+	present, err := presentIEs(msg)
+	if err != nil {
+		return 0
+	}
+
+	if ie, ok := present[MandatoryIE{ies.Cause, 0}]; ok {
+		return ie.MustCause()
+	}
+	return 0
+}
+
+// EnableSessionHistory enables the history with size for the Sessions that are
+// created with CreateSession or added with AddSession after this is called.
+// Giving 0 or negative value stops enabling it for the new Sessions.
+//
+// See (*Session) EnableHistory for the detail.
+func (c *Conn) EnableSessionHistory(size int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.historySize = size
+}
+
+func (c *Conn) sessionHistorySize() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.historySize
+}
+
+// recordMessage records msg in the history of sess, or the Session looked up by
+// the TEID in msg if sess is nil.
+func (c *Conn) recordMessage(sess *Session, dir Direction, peer net.Addr, msg messages.Message) {
+	if sess == nil {
+		teid := msg.TEID()
+		if teid == 0 {
+			return
+		}
+
+		var err error
+		sess, err = c.GetSessionByTEID(teid, peer)
+		if err != nil {
+			return
+		}
+	}
+
+	sess.RecordMessage(dir, peer, msg)
+}
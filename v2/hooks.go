@@ -0,0 +1,104 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+// BearerChange is the kind of change of the Bearer notified to OnBearerModified.
+type BearerChange uint8
+
+// BearerChange definitions.
+const (
+	// BearerChangeAdded is notified when a Bearer is added to the Session, including
+	// the one that replaces the existing one with the same EBI.
+	BearerChangeAdded BearerChange = iota
+	// BearerChangeUpdated is notified when the QoS, the Charging ID or the F-TEID on
+	// the access side of the Bearer is updated, or the Bearer is released on the
+	// access side.
+	BearerChangeUpdated
+	// BearerChangeRemoved is notified when a Bearer is removed from the Session.
+	BearerChangeRemoved
+)
+
+// SessionHooks is a set of functions called on the lifecycle events of the Sessions
+// on Conn and their Bearers. Any of them can be nil.
+//
+// The events are notified regardless of whether the Sessions are modified by the
+// helpers of Conn, e.g., when the Bearer responses are handled, or by the user.
+// The functions are called synchronously in the goroutine that modified the Session,
+// so they should not block.
+type SessionHooks struct {
+	// OnSessionCreated is called when a Session is added to Conn with AddSession,
+	// including the one that replaces the existing one.
+	OnSessionCreated func(sess *Session)
+	// OnSessionActivated is called when a Session on Conn is activated. The Session
+	// activated before added to Conn is notified only with OnSessionCreated.
+	OnSessionActivated func(sess *Session)
+	// OnSessionDeleted is called when a Session is removed from Conn, including the
+	// one replaced by the new one.
+	OnSessionDeleted func(sess *Session)
+	// OnBearerModified is called when a Bearer in a Session on Conn is changed.
+	OnBearerModified func(sess *Session, br *Bearer, change BearerChange)
+}
+
+// SetSessionHooks sets the SessionHooks to be called on the events of the Sessions
+// on Conn. The Sessions already added to Conn are also notified of the events after
+// this is called. Giving the zero value stops the notification.
+func (c *Conn) SetSessionHooks(hooks SessionHooks) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sessionHooks = hooks
+}
+
+func (c *Conn) getSessionHooks() SessionHooks {
+	if c == nil {
+		return SessionHooks{}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.sessionHooks
+}
+
+func (c *Conn) sessionCreated(sess *Session) {
+	sess.setConn(c)
+	if fn := c.getSessionHooks().OnSessionCreated; fn != nil {
+		fn(sess)
+	}
+}
+
+func (c *Conn) sessionDeleted(sess *Session) {
+	sess.setConn(nil)
+	if fn := c.getSessionHooks().OnSessionDeleted; fn != nil {
+		fn(sess)
+	}
+}
+
+// setConn sets the Conn that Session belongs to, which is notified of the events.
+func (s *Session) setConn(c *Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.conn = c
+}
+
+func (s *Session) getConn() *Conn {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.conn
+}
+
+func (s *Session) notifyActivated() {
+	if fn := s.getConn().getSessionHooks().OnSessionActivated; fn != nil {
+		fn(s)
+	}
+}
+
+func (s *Session) notifyBearer(br *Bearer, change BearerChange) {
+	if fn := s.getConn().getSessionHooks().OnBearerModified; fn != nil {
+		fn(s, br, change)
+	}
+}
@@ -7,15 +7,19 @@ package ies
 import (
 	"encoding/binary"
 	"io"
+	"math"
 )
 
 // NewAggregateMaximumBitRate creates a new AggregateMaximumBitRate IE.
+//
+// The values are in kbps. Use NewAggregateMaximumBitRateFromBitRate to give them in
+// BitRate.
 func NewAggregateMaximumBitRate(up, down uint32) *IE {
 	return newUint64ValIE(AggregateMaximumBitRate, (uint64(up)<<32 | uint64(down)))
 }
 
-// AggregateMaximumBitRateUp returns AggregateMaximumBitRate for Uplink
-// if the type of IE matches.
+// AggregateMaximumBitRateUp returns AggregateMaximumBitRate for Uplink in kbps
+// if the type of IE matches. Use UplinkAMBR to get it in BitRate.
 func (i *IE) AggregateMaximumBitRateUp() (uint32, error) {
 	if i.Type != AggregateMaximumBitRate {
 		return 0, &InvalidTypeError{Type: i.Type}
@@ -34,8 +38,8 @@ func (i *IE) MustAggregateMaximumBitRateUp() uint32 {
 	return v
 }
 
-// AggregateMaximumBitRateDown returns AggregateMaximumBitRate for Downlink
-// if the type of IE matches.
+// AggregateMaximumBitRateDown returns AggregateMaximumBitRate for Downlink in kbps
+// if the type of IE matches. Use DownlinkAMBR to get it in BitRate.
 func (i *IE) AggregateMaximumBitRateDown() (uint32, error) {
 	if i.Type != AggregateMaximumBitRate {
 		return 0, &InvalidTypeError{Type: i.Type}
@@ -53,3 +57,35 @@ func (i *IE) MustAggregateMaximumBitRateDown() uint32 {
 	v, _ := i.AggregateMaximumBitRateDown()
 	return v
 }
+
+// NewAggregateMaximumBitRateFromBitRate creates a new AggregateMaximumBitRate IE from
+// the values in BitRate.
+//
+// The values are rounded up to kbps, and the ones larger than the maximum that
+// can be encoded are set to the maximum.
+func NewAggregateMaximumBitRateFromBitRate(up, down BitRate) *IE {
+	return NewAggregateMaximumBitRate(
+		uint32(up.kbpsWithin(math.MaxUint32)),
+		uint32(down.kbpsWithin(math.MaxUint32)),
+	)
+}
+
+// UplinkAMBR returns AggregateMaximumBitRate for Uplink in BitRate if the type of IE
+// matches.
+func (i *IE) UplinkAMBR() (BitRate, error) {
+	kbps, err := i.AggregateMaximumBitRateUp()
+	if err != nil {
+		return 0, err
+	}
+	return BitRateFromKbps(uint64(kbps)), nil
+}
+
+// DownlinkAMBR returns AggregateMaximumBitRate for Downlink in BitRate if the type of
+// IE matches.
+func (i *IE) DownlinkAMBR() (BitRate, error) {
+	kbps, err := i.AggregateMaximumBitRateDown()
+	if err != nil {
+		return 0, err
+	}
+	return BitRateFromKbps(uint64(kbps)), nil
+}
@@ -5,52 +5,71 @@
 package ies
 
 import (
+	"io"
 	"strings"
 )
 
 // NewAccessPointName creates a new AccessPointName IE.
 func NewAccessPointName(apn string) *IE {
-	i := New(AccessPointName, 0x00, make([]byte, len(apn)+1))
+	return New(AccessPointName, 0x00, encodeAPN(apn))
+}
+
+// AccessPointName returns AccessPointName in string if the type of IE matches.
+func (i *IE) AccessPointName() (string, error) {
+	switch i.Type {
+	case AccessPointName:
+		return decodeAPN(i.Payload), nil
+	case APNAndRelativeCapacity:
+		if len(i.Payload) < 2 {
+			return "", io.ErrUnexpectedEOF
+		}
+		l := int(i.Payload[1])
+		if len(i.Payload) < 2+l {
+			return "", io.ErrUnexpectedEOF
+		}
+		return decodeAPN(i.Payload[2 : 2+l]), nil
+	default:
+		return "", &InvalidTypeError{Type: i.Type}
+	}
+}
+
+// MustAccessPointName returns AccessPointName in string, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustAccessPointName() string {
+	v, _ := i.AccessPointName()
+	return v
+}
+
+func encodeAPN(apn string) []byte {
+	b := make([]byte, len(apn)+1)
 	var offset = 0
 	for _, label := range strings.Split(apn, ".") {
 		l := len(label)
-		i.Payload[offset] = uint8(l)
-		copy(i.Payload[offset+1:], []byte(label))
+		b[offset] = uint8(l)
+		copy(b[offset+1:], []byte(label))
 		offset += l + 1
 	}
 
-	return i
+	return b
 }
 
-// AccessPointName returns AccessPointName in string if the type of IE matches.
-func (i *IE) AccessPointName() (string, error) {
-	if i.Type != AccessPointName {
-		return "", &InvalidTypeError{Type: i.Type}
-	}
-
+func decodeAPN(b []byte) string {
 	var (
 		apn    []string
 		offset int
 	)
-	max := len(i.Payload)
+	max := len(b)
 	for {
 		if offset >= max {
 			break
 		}
-		l := int(i.Payload[offset])
+		l := int(b[offset])
 		if offset+l+1 > max {
 			break
 		}
-		apn = append(apn, string(i.Payload[offset+1:offset+l+1]))
+		apn = append(apn, string(b[offset+1:offset+l+1]))
 		offset += l + 1
 	}
 
-	return strings.Join(apn, "."), nil
-}
-
-// MustAccessPointName returns AccessPointName in string, ignoring errors.
-// This should only be used if it is assured to have the value.
-func (i *IE) MustAccessPointName() string {
-	v, _ := i.AccessPointName()
-	return v
+	return strings.Join(apn, ".")
 }
@@ -11,6 +11,8 @@ import (
 )
 
 // NewBearerQoS creates a new BearerQoS IE.
+//
+// The MBRs and GBRs are in kbps. Use NewBearerQoSFromBitRate to give them in BitRate.
 func NewBearerQoS(pci, pl, pvi, qci uint8, umbr, dmbr, ugbr, dgbr uint64) *IE {
 	i := New(BearerQoS, 0x00, make([]byte, 22))
 	i.Payload[0] |= (pci << 6 & 0x40) | (pl << 2 & 0x3c) | (pvi & 0x01)
@@ -40,21 +42,22 @@ func (i *IE) QCILabel() (uint8, error) {
 	}
 }
 
-// MBRForUplink returns MBRForUplink in uint64 if the type of IE matches.
+// MBRForUplink returns MBRForUplink in kbps if the type of IE matches.
+// Use UplinkMBR to get it in BitRate.
 func (i *IE) MBRForUplink() (uint64, error) {
 	switch i.Type {
 	case BearerQoS:
 		if len(i.Payload) < 7 {
 			return 0, io.ErrUnexpectedEOF
 		}
-		return utils.Uint40To64(i.Payload[3:7]), nil
+		return utils.Uint40To64(i.Payload[2:7]), nil
 	case FlowQoS:
 		if len(i.Payload) < 6 {
 			return 0, io.ErrUnexpectedEOF
 		}
-		return utils.Uint40To64(i.Payload[2:6]), nil
+		return utils.Uint40To64(i.Payload[1:6]), nil
 	default:
-		return 0, io.ErrUnexpectedEOF
+		return 0, &InvalidTypeError{Type: i.Type}
 	}
 }
 
@@ -65,7 +68,8 @@ func (i *IE) MustMBRForUplink() uint64 {
 	return v
 }
 
-// MBRForDownlink returns MBRForDownlink in uint64 if the type of IE matches.
+// MBRForDownlink returns MBRForDownlink in kbps if the type of IE matches.
+// Use DownlinkMBR to get it in BitRate.
 func (i *IE) MBRForDownlink() (uint64, error) {
 	switch i.Type {
 	case BearerQoS:
@@ -79,7 +83,7 @@ func (i *IE) MBRForDownlink() (uint64, error) {
 		}
 		return utils.Uint40To64(i.Payload[6:11]), nil
 	default:
-		return 0, io.ErrUnexpectedEOF
+		return 0, &InvalidTypeError{Type: i.Type}
 	}
 }
 
@@ -90,7 +94,8 @@ func (i *IE) MustMBRForDownlink() uint64 {
 	return v
 }
 
-// GBRForUplink returns GBRForUplink in uint64 if the type of IE matches.
+// GBRForUplink returns GBRForUplink in kbps if the type of IE matches.
+// Use UplinkGBR to get it in BitRate.
 func (i *IE) GBRForUplink() (uint64, error) {
 	switch i.Type {
 	case BearerQoS:
@@ -104,7 +109,7 @@ func (i *IE) GBRForUplink() (uint64, error) {
 		}
 		return utils.Uint40To64(i.Payload[11:16]), nil
 	default:
-		return 0, io.ErrUnexpectedEOF
+		return 0, &InvalidTypeError{Type: i.Type}
 	}
 }
 
@@ -115,7 +120,8 @@ func (i *IE) MustGBRForUplink() uint64 {
 	return v
 }
 
-// GBRForDownlink returns GBRForDownlink in uint64 if the type of IE matches.
+// GBRForDownlink returns GBRForDownlink in kbps if the type of IE matches.
+// Use DownlinkGBR to get it in BitRate.
 func (i *IE) GBRForDownlink() (uint64, error) {
 	switch i.Type {
 	case BearerQoS:
@@ -129,7 +135,7 @@ func (i *IE) GBRForDownlink() (uint64, error) {
 		}
 		return utils.Uint40To64(i.Payload[16:21]), nil
 	default:
-		return 0, io.ErrUnexpectedEOF
+		return 0, &InvalidTypeError{Type: i.Type}
 	}
 }
 
@@ -139,3 +145,43 @@ func (i *IE) MustGBRForDownlink() uint64 {
 	v, _ := i.GBRForDownlink()
 	return v
 }
+
+// NewBearerQoSFromBitRate creates a new BearerQoS IE with the MBRs and GBRs in
+// BitRate.
+//
+// The values are rounded up to kbps, and the ones larger than the maximum that
+// can be encoded are set to the maximum.
+func NewBearerQoSFromBitRate(pci, pl, pvi, qci uint8, umbr, dmbr, ugbr, dgbr BitRate) *IE {
+	return NewBearerQoS(
+		pci, pl, pvi, qci,
+		umbr.kbpsWithin(maxUint40), dmbr.kbpsWithin(maxUint40),
+		ugbr.kbpsWithin(maxUint40), dgbr.kbpsWithin(maxUint40),
+	)
+}
+
+// UplinkMBR returns MBRForUplink in BitRate if the type of IE matches.
+func (i *IE) UplinkMBR() (BitRate, error) {
+	return bitRateFromKbps(i.MBRForUplink())
+}
+
+// DownlinkMBR returns MBRForDownlink in BitRate if the type of IE matches.
+func (i *IE) DownlinkMBR() (BitRate, error) {
+	return bitRateFromKbps(i.MBRForDownlink())
+}
+
+// UplinkGBR returns GBRForUplink in BitRate if the type of IE matches.
+func (i *IE) UplinkGBR() (BitRate, error) {
+	return bitRateFromKbps(i.GBRForUplink())
+}
+
+// DownlinkGBR returns GBRForDownlink in BitRate if the type of IE matches.
+func (i *IE) DownlinkGBR() (BitRate, error) {
+	return bitRateFromKbps(i.GBRForDownlink())
+}
+
+func bitRateFromKbps(kbps uint64, err error) (BitRate, error) {
+	if err != nil {
+		return 0, err
+	}
+	return BitRateFromKbps(kbps), nil
+}
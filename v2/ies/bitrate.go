@@ -0,0 +1,43 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+// BitRate is the bit rate in bits per second.
+//
+// The bit rates in the IEs such as AggregateMaximumBitRate and BearerQoS are encoded
+// in kbps, while the policy systems such as PCRF typically give them in bps. BitRate
+// is used in the accessors and constructors that convert between them, so that the
+// value in the wrong unit is not set accidentally.
+type BitRate uint64
+
+// BitRate definitions.
+const (
+	Bps  BitRate = 1
+	Kbps         = 1000 * Bps
+	Mbps         = 1000 * Kbps
+	Gbps         = 1000 * Mbps
+)
+
+// maxUint40 is the maximum value of the 40-bit fields of MBR and GBR.
+const maxUint40 = 1<<40 - 1
+
+// BitRateFromKbps returns BitRate from the value in kbps.
+func BitRateFromKbps(kbps uint64) BitRate {
+	return BitRate(kbps) * Kbps
+}
+
+// Kbps returns the bit rate in kbps, rounding up the fraction so that the non-zero
+// bit rate does not become zero.
+func (r BitRate) Kbps() uint64 {
+	return (uint64(r) + uint64(Kbps) - 1) / uint64(Kbps)
+}
+
+// kbpsWithin returns the bit rate in kbps capped with max.
+func (r BitRate) kbpsWithin(max uint64) uint64 {
+	if kbps := r.Kbps(); kbps < max {
+		return kbps
+	}
+	return max
+}
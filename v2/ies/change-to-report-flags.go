@@ -0,0 +1,63 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import "io"
+
+// NewChangeToReportFlags creates a new ChangeToReportFlags IE.
+func NewChangeToReportFlags(sncr, tzcr uint8) *IE {
+	i := New(ChangeToReportFlags, 0x00, make([]byte, 1))
+	i.Payload[0] |= (tzcr << 1 & 0x02) | (sncr & 0x01)
+	return i
+}
+
+// ChangeToReportFlags returns ChangeToReportFlags in uint8(=as it is) if the type of IE matches.
+func (i *IE) ChangeToReportFlags() (uint8, error) {
+	if i.Type != ChangeToReportFlags {
+		return 0, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) == 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	return i.Payload[0], nil
+}
+
+// MustChangeToReportFlags returns ChangeToReportFlags in uint8, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustChangeToReportFlags() uint8 {
+	v, _ := i.ChangeToReportFlags()
+	return v
+}
+
+// ServingNetworkChangeToReport reports whether the change of Serving Network should
+// be reported by the target MME/S4-SGSN, which is indicated by the old one that has
+// ISR activated.
+func (i *IE) ServingNetworkChangeToReport() bool {
+	if len(i.Payload) == 0 {
+		return false
+	}
+	switch i.Type {
+	case ChangeToReportFlags:
+		return i.Payload[0]&0x01 == 1
+	default:
+		return false
+	}
+}
+
+// TimeZoneChangeToReport reports whether the change of UE Time Zone should be
+// reported by the target MME/S4-SGSN, which is indicated by the old one that has
+// ISR activated.
+func (i *IE) TimeZoneChangeToReport() bool {
+	if len(i.Payload) == 0 {
+		return false
+	}
+	switch i.Type {
+	case ChangeToReportFlags:
+		return i.Payload[0]>>1&0x01 == 1
+	default:
+		return false
+	}
+}
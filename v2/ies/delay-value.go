@@ -10,11 +10,22 @@ import (
 )
 
 // NewDelayValue creates a new DelayValue IE.
+//
+// The delay is encoded in multiples of 50 milliseconds, rounded down. The delay
+// longer than the maximum that can be encoded, 12.75 seconds, is set to the maximum.
 func NewDelayValue(delay time.Duration) *IE {
-	return newUint8ValIE(DelayValue, uint8(delay.Seconds()*1000/50))
+	v := delay / (50 * time.Millisecond)
+	if v > 0xff {
+		v = 0xff
+	}
+	if v < 0 {
+		v = 0
+	}
+	return newUint8ValIE(DelayValue, uint8(v))
 }
 
 // DelayValue returns DelayValue in time.Duration if the type of IE matches.
+// Use DelayValueRaw to get the value in multiples of 50 milliseconds.
 func (i *IE) DelayValue() (time.Duration, error) {
 	if i.Type != DelayValue {
 		return 0, &InvalidTypeError{Type: i.Type}
@@ -23,7 +34,7 @@ func (i *IE) DelayValue() (time.Duration, error) {
 		return 0, io.ErrUnexpectedEOF
 	}
 
-	return time.Duration(i.Payload[0]/50) * time.Millisecond, nil
+	return time.Duration(i.Payload[0]) * 50 * time.Millisecond, nil
 }
 
 // MustDelayValue returns DelayValue in time.Duration, ignoring errors.
@@ -32,3 +43,16 @@ func (i *IE) MustDelayValue() time.Duration {
 	v, _ := i.DelayValue()
 	return v
 }
+
+// DelayValueRaw returns DelayValue in multiples of 50 milliseconds if the type of IE
+// matches.
+func (i *IE) DelayValueRaw() (uint8, error) {
+	if i.Type != DelayValue {
+		return 0, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) == 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	return i.Payload[0], nil
+}
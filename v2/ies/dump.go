@@ -0,0 +1,521 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var typeNames = map[uint8]string{
+	IMSI:                                     "IMSI",
+	Cause:                                    "Cause",
+	Recovery:                                 "Recovery",
+	STNSR:                                    "STNSR",
+	AccessPointName:                          "AccessPointName",
+	AggregateMaximumBitRate:                  "AggregateMaximumBitRate",
+	EPSBearerID:                              "EPSBearerID",
+	IPAddress:                                "IPAddress",
+	MobileEquipmentIdentity:                  "MobileEquipmentIdentity",
+	MSISDN:                                   "MSISDN",
+	Indication:                               "Indication",
+	ProtocolConfigurationOptions:             "ProtocolConfigurationOptions",
+	PDNAddressAllocation:                     "PDNAddressAllocation",
+	BearerQoS:                                "BearerQoS",
+	FlowQoS:                                  "FlowQoS",
+	RATType:                                  "RATType",
+	ServingNetwork:                           "ServingNetwork",
+	BearerTFT:                                "BearerTFT",
+	TrafficAggregateDescription:              "TrafficAggregateDescription",
+	UserLocationInformation:                  "UserLocationInformation",
+	FullyQualifiedTEID:                       "FullyQualifiedTEID",
+	TMSI:                                     "TMSI",
+	GlobalCNID:                               "GlobalCNID",
+	S103PDNDataForwardingInfo:                "S103PDNDataForwardingInfo",
+	S1UDataForwarding:                        "S1UDataForwarding",
+	DelayValue:                               "DelayValue",
+	BearerContext:                            "BearerContext",
+	ChargingID:                               "ChargingID",
+	ChargingCharacteristics:                  "ChargingCharacteristics",
+	TraceInformation:                         "TraceInformation",
+	BearerFlags:                              "BearerFlags",
+	PDNType:                                  "PDNType",
+	ProcedureTransactionID:                   "ProcedureTransactionID",
+	MMContextGSMKeyAndTriplets:               "MMContextGSMKeyAndTriplets",
+	MMContextUMTSKeyUsedCipherAndQuintuplets: "MMContextUMTSKeyUsedCipherAndQuintuplets",
+	MMContextGSMKeyUsedCipherAndQuintuplets:  "MMContextGSMKeyUsedCipherAndQuintuplets",
+	MMContextUMTSKeyAndQuintuplets:           "MMContextUMTSKeyAndQuintuplets",
+	MMContextEPSSecurityContextQuadrupletsAndQuintuplets: "MMContextEPSSecurityContextQuadrupletsAndQuintuplets",
+	MMContextUMTSKeyQuadrupletsAndQuintuplets:            "MMContextUMTSKeyQuadrupletsAndQuintuplets",
+	PDNConnection:                          "PDNConnection",
+	PDUNumbers:                             "PDUNumbers",
+	PacketTMSI:                             "PacketTMSI",
+	PTMSISignature:                         "PTMSISignature",
+	HopCounter:                             "HopCounter",
+	UETimeZone:                             "UETimeZone",
+	TraceReference:                         "TraceReference",
+	CompleteRequestMessage:                 "CompleteRequestMessage",
+	GUTI:                                   "GUTI",
+	FContainer:                             "FContainer",
+	FCause:                                 "FCause",
+	PLMNID:                                 "PLMNID",
+	TargetIdentification:                   "TargetIdentification",
+	PacketFlowID:                           "PacketFlowID",
+	RABContext:                             "RABContext",
+	SourceRNCPDCPContextInfo:               "SourceRNCPDCPContextInfo",
+	PortNumber:                             "PortNumber",
+	APNRestriction:                         "APNRestriction",
+	SelectionMode:                          "SelectionMode",
+	SourceIdentification:                   "SourceIdentification",
+	Reserved:                               "Reserved",
+	ChangeReportingAction:                  "ChangeReportingAction",
+	FullyQualifiedCSID:                     "FullyQualifiedCSID",
+	ChannelNeeded:                          "ChannelNeeded",
+	EMLPPPriority:                          "EMLPPPriority",
+	NodeType:                               "NodeType",
+	FullyQualifiedDomainName:               "FullyQualifiedDomainName",
+	TI:                                     "TI",
+	MBMSSessionDuration:                    "MBMSSessionDuration",
+	MBMSServiceArea:                        "MBMSServiceArea",
+	MBMSSessionIdentifier:                  "MBMSSessionIdentifier",
+	MBMSFlowIdentifier:                     "MBMSFlowIdentifier",
+	MBMSIPMulticastDistribution:            "MBMSIPMulticastDistribution",
+	MBMSDistributionAcknowledge:            "MBMSDistributionAcknowledge",
+	RFSPIndex:                              "RFSPIndex",
+	UserCSGInformation:                     "UserCSGInformation",
+	CSGInformationReportingAction:          "CSGInformationReportingAction",
+	CSGID:                                  "CSGID",
+	CSGMembershipIndication:                "CSGMembershipIndication",
+	ServiceIndicator:                       "ServiceIndicator",
+	DetachType:                             "DetachType",
+	LocalDistinguishedName:                 "LocalDistinguishedName",
+	NodeFeatures:                           "NodeFeatures",
+	MBMSTimeToDataTransfer:                 "MBMSTimeToDataTransfer",
+	Throttling:                             "Throttling",
+	AllocationRetensionPriority:            "AllocationRetensionPriority",
+	EPCTimer:                               "EPCTimer",
+	SignallingPriorityIndication:           "SignallingPriorityIndication",
+	TMGI:                                   "TMGI",
+	AdditionalMMContextForSRVCC:            "AdditionalMMContextForSRVCC",
+	AdditionalFlagsForSRVCC:                "AdditionalFlagsForSRVCC",
+	MDTConfiguration:                       "MDTConfiguration",
+	AdditionalProtocolConfigurationOptions: "AdditionalProtocolConfigurationOptions",
+	AbsoluteTimeofMBMSDataTransfer:         "AbsoluteTimeofMBMSDataTransfer",
+	HeNBInformationReporting:               "HeNBInformationReporting",
+	IPv4ConfigurationParameters:            "IPv4ConfigurationParameters",
+	ChangeToReportFlags:                    "ChangeToReportFlags",
+	ActionIndication:                       "ActionIndication",
+	TWANIdentifier:                         "TWANIdentifier",
+	ULITimestamp:                           "ULITimestamp",
+	MBMSFlags:                              "MBMSFlags",
+	RANNASCause:                            "RANNASCause",
+	CNOperatorSelectionEntity:              "CNOperatorSelectionEntity",
+	TrustedWLANModeIndication:              "TrustedWLANModeIndication",
+	NodeNumber:                             "NodeNumber",
+	NodeIdentifier:                         "NodeIdentifier",
+	PresenceReportingAreaAction:            "PresenceReportingAreaAction",
+	PresenceReportingAreaInformation:       "PresenceReportingAreaInformation",
+	TWANIdentifierTimestamp:                "TWANIdentifierTimestamp",
+	OverloadControlInformation:             "OverloadControlInformation",
+	LoadControlInformation:                 "LoadControlInformation",
+	Metric:                                 "Metric",
+	SequenceNumber:                         "SequenceNumber",
+	APNAndRelativeCapacity:                 "APNAndRelativeCapacity",
+	WLANOffloadabilityIndication:           "WLANOffloadabilityIndication",
+	PagingAndServiceInformation:            "PagingAndServiceInformation",
+	IntegerNumber:                          "IntegerNumber",
+	MillisecondTimeStamp:                   "MillisecondTimeStamp",
+	MonitoringEventInformation:             "MonitoringEventInformation",
+	ECGIList:                               "ECGIList",
+	RemoteUEContext:                        "RemoteUEContext",
+	RemoteUserID:                           "RemoteUserID",
+	RemoteUEIPinformation:                  "RemoteUEIPinformation",
+	CIoTOptimizationsSupportIndication:     "CIoTOptimizationsSupportIndication",
+	SCEFPDNConnection:                      "SCEFPDNConnection",
+	HeaderCompressionConfiguration:         "HeaderCompressionConfiguration",
+	ExtendedProtocolConfigurationOptions:   "ExtendedProtocolConfigurationOptions",
+	ServingPLMNRateControl:                 "ServingPLMNRateControl",
+	Counter:                                "Counter",
+	MappedUEUsageType:                      "MappedUEUsageType",
+	SecondaryRATUsageDataReport:            "SecondaryRATUsageDataReport",
+	UPFunctionSelectionIndicationFlags:     "UPFunctionSelectionIndicationFlags",
+	MaximumPacketLossRate:                  "MaximumPacketLossRate",
+	APNRateControlStatus:                   "APNRateControlStatus",
+	ExtendedTraceInformation:               "ExtendedTraceInformation",
+	SpecialIETypeForIETypeExtension:        "SpecialIETypeForIETypeExtension",
+	PrivateExtension:                       "PrivateExtension",
+}
+
+// TypeName returns the name of the IE type given, which is the same as the name of
+// the constant, e.g., "BearerContext". "S101" and "S121" are returned for the types
+// reserved for those interfaces (see IsS101Type and IsS121Type), and "Unknown" for the
+// other types not defined in this package.
+func TypeName(typ uint8) string {
+	if name, ok := typeNames[typ]; ok {
+		return name
+	}
+	switch {
+	case IsS101Type(typ):
+		return "S101"
+	case IsS121Type(typ):
+		return "S121"
+	}
+	return "Unknown"
+}
+
+// Field is a value decoded from the payload of IE, used by Dump and MarshalJSON.
+type Field struct {
+	Name  string
+	Value interface{}
+}
+
+// hexValue is the value shown in hexadecimal in Dump.
+type hexValue uint32
+
+// String returns the value in hexadecimal.
+func (v hexValue) String() string {
+	return fmt.Sprintf("%#x", uint32(v))
+}
+
+// fieldsDecoder collects the Fields until the first error.
+type fieldsDecoder struct {
+	fields []Field
+	err    error
+}
+
+// add returns the function that adds the value as Field with the name if err is nil.
+// It is written this way to pass the results of the getters directly.
+func (d *fieldsDecoder) add(name string) func(v interface{}, err error) {
+	return func(v interface{}, err error) {
+		if d.err != nil {
+			return
+		}
+		if err != nil {
+			d.err = err
+			return
+		}
+
+		switch x := v.(type) {
+		case []byte:
+			v = hex.EncodeToString(x)
+		case time.Duration:
+			v = x.String()
+		case time.Time:
+			v = x.UTC().Format(time.RFC3339Nano)
+		}
+		d.fields = append(d.fields, Field{Name: name, Value: v})
+	}
+}
+
+func (d *fieldsDecoder) addBool(name string, v bool) {
+	d.add(name)(v, nil)
+}
+
+func (d *fieldsDecoder) addHex(name string) func(v uint32, err error) {
+	return func(v uint32, err error) {
+		d.add(name)(hexValue(v), err)
+	}
+}
+
+// Fields returns the values decoded from the payload of IE, in the order they appear
+// in the payload.
+//
+// Only the commonly used types of IEs are decoded. It returns nil for the grouped IEs,
+// the raw IEs (see ParseRaw), and the types that are not supported, in which case the
+// payload should be inspected directly. The error is returned if the payload cannot
+// be decoded as the type.
+func (i *IE) Fields() ([]Field, error) {
+	if i.raw || i.IsGrouped() {
+		return nil, nil
+	}
+
+	d := &fieldsDecoder{}
+	switch i.Type {
+	case IMSI:
+		d.add("IMSI")(i.IMSI())
+	case Cause:
+		d.add("Cause")(i.Cause())
+	case Recovery:
+		d.add("Restart Counter")(i.Recovery())
+	case NodeFeatures:
+		d.add("Supported-Features")(i.NodeFeatures())
+	case AccessPointName:
+		d.add("APN")(i.AccessPointName())
+	case AggregateMaximumBitRate:
+		d.add("APN-AMBR for Uplink")(i.AggregateMaximumBitRateUp())
+		d.add("APN-AMBR for Downlink")(i.AggregateMaximumBitRateDown())
+	case EPSBearerID:
+		d.add("EBI")(i.EPSBearerID())
+	case IPAddress:
+		d.add("IP Address")(i.IPAddress())
+	case MobileEquipmentIdentity:
+		d.add("MEI")(i.MobileEquipmentIdentity())
+	case MSISDN:
+		d.add("MSISDN")(i.MSISDN())
+	case AllocationRetensionPriority:
+		d.addBool("PCI", i.PreemptionCapability())
+		d.add("PL")(i.PriorityLevel())
+		d.addBool("PVI", i.PreemptionVulnerability())
+	case RATType:
+		d.add("RAT Type")(i.RATType())
+	case ServingNetwork:
+		d.add("MCC")(i.MCC())
+		d.add("MNC")(i.MNC())
+	case BearerQoS:
+		d.addBool("PCI", i.PreemptionCapability())
+		d.add("PL")(i.PriorityLevel())
+		d.addBool("PVI", i.PreemptionVulnerability())
+		d.add("QCI")(i.QCILabel())
+		d.add("MBR for Uplink")(i.MBRForUplink())
+		d.add("MBR for Downlink")(i.MBRForDownlink())
+		d.add("GBR for Uplink")(i.GBRForUplink())
+		d.add("GBR for Downlink")(i.GBRForDownlink())
+	case UserLocationInformation:
+		uli, err := i.UserLocationInformation()
+		d.add("ULI")(uli, err)
+		if err == nil {
+			d.fields = uliFields(uli)
+		}
+	case FullyQualifiedTEID:
+		d.add("Interface Type")(i.InterfaceType())
+		d.addHex("TEID/GRE Key")(i.TEID())
+		if i.HasIPv4() {
+			d.add("IPv4 Address")(i.IPAddress())
+		}
+		if i.HasIPv6() {
+			d.add("IPv6 Address")(i.IPv6Address())
+		}
+	case DelayValue:
+		d.add("Delay Value")(i.DelayValue())
+	case ChargingID:
+		d.addHex("Charging ID")(i.ChargingID())
+	case ChargingCharacteristics:
+		v, err := i.ChargingCharacteristics()
+		d.addHex("Charging Characteristics")(uint32(v), err)
+	case PDNType:
+		d.add("PDN Type")(i.PDNType())
+	case ProcedureTransactionID:
+		d.add("PTI")(i.ProcedureTransactionID())
+	case PacketTMSI:
+		d.addHex("P-TMSI")(i.PacketTMSI())
+	case PTMSISignature:
+		d.addHex("P-TMSI Signature")(i.PTMSISignature())
+	case HopCounter:
+		d.add("Hop Counter")(i.HopCounter())
+	case UETimeZone:
+		d.add("Time Zone")(i.TimeZone())
+		d.add("Daylight Saving Time")(i.DaylightSavingTime())
+	case TraceReference:
+		d.add("PLMN ID")(i.PLMNID())
+		d.addHex("Trace ID")(i.TraceID())
+	case PDNAddressAllocation:
+		d.add("PDN Type")(i.PDNType())
+		d.add("IP Address")(i.IPAddress())
+	case APNRestriction:
+		d.add("APN Restriction")(i.APNRestriction())
+	case SelectionMode:
+		d.add("Selection Mode")(i.SelectionMode())
+	case FullyQualifiedCSID:
+		d.add("Node-ID Type")(i.NodeIDType())
+		d.add("Node-ID")(i.NodeID())
+		d.add("PDN Connection Set Identifiers")(i.CSIDs())
+	case SequenceNumber:
+		d.add("Sequence Number")(i.SequenceNumber())
+	case NodeType:
+		d.add("Node Type")(i.NodeType())
+	case FullyQualifiedDomainName:
+		d.add("FQDN")(i.FullyQualifiedDomainName())
+	case PortNumber:
+		d.add("Port Number")(i.PortNumber())
+	case RFSPIndex:
+		d.add("RFSP Index")(i.RFSPIndex())
+	case TMSI:
+		d.addHex("TMSI")(i.TMSI())
+	case CSGID:
+		d.addHex("CSG ID")(i.CSGID())
+	case CSGMembershipIndication:
+		d.add("CMI")(i.CMI())
+	case ServiceIndicator:
+		d.add("Service Indicator")(i.ServiceIndicator())
+	case DetachType:
+		d.add("Detach Type")(i.DetachType())
+	case LocalDistinguishedName:
+		d.add("LDN")(i.LocalDistinguishedName())
+	case MBMSSessionDuration:
+		d.add("MBMS Session Duration")(i.MBMSSessionDuration())
+	case MBMSFlowIdentifier:
+		d.add("MBMS Flow Identifier")(i.MBMSFlowIdentifier())
+	case TMGI:
+		d.addHex("MBMS Service ID")(i.MBMSServiceID())
+		d.add("MCC")(i.MCC())
+		d.add("MNC")(i.MNC())
+	case EPCTimer:
+		d.add("Timer Value")(i.EPCTimer())
+	case ULITimestamp, TWANIdentifierTimestamp:
+		d.add("Timestamp")(i.Timestamp())
+	case TWANIdentifier:
+		twan, err := i.TWANIdentifier()
+		if err != nil {
+			d.add("SSID")(nil, err)
+			break
+		}
+		d.add("SSID")(string(twan.SSID), nil)
+		if twan.BSSID != nil {
+			d.add("BSSID")(twan.BSSID.String(), nil)
+		}
+	case TrustedWLANModeIndication:
+		d.addBool("MCM", i.MultipleConnectionMode())
+		d.addBool("SCM", i.SingleConnectionMode())
+	case ChangeToReportFlags:
+		d.addBool("SNCR", i.ServingNetworkChangeToReport())
+		d.addBool("TZCR", i.TimeZoneChangeToReport())
+	case WLANOffloadabilityIndication:
+		d.addBool("E-UTRAN Indication", i.EUTRANOffloadable())
+		d.addBool("UTRAN Indication", i.UTRANOffloadable())
+	case MMContextEPSSecurityContextQuadrupletsAndQuintuplets:
+		ctx, err := i.EPSSecurityContext()
+		if err != nil {
+			d.add("EPS Security Context")(nil, err)
+			break
+		}
+		d.add("Security Mode")(ctx.SecurityMode, nil)
+		d.add("KSI_ASME")(ctx.KSIASME, nil)
+		d.add("Quadruplets")(len(ctx.Quadruplets), nil)
+		d.add("Quintuplets")(len(ctx.Quintuplets), nil)
+		d.add("MEI")(ctx.MEI, nil)
+	case MillisecondTimeStamp:
+		d.add("Timestamp")(i.MillisecondTimeStamp())
+	case IntegerNumber:
+		d.add("Integer Number")(i.IntegerNumber())
+	case Metric:
+		d.add("Metric")(i.Metric())
+	case APNAndRelativeCapacity:
+		d.add("Relative Capacity")(i.RelativeCapacity())
+	case PrivateExtension:
+		d.add("Enterprise ID")(i.EnterpriseID())
+		d.add("Proprietary Value")(i.PrivateExtension())
+	default:
+		return nil, nil
+	}
+	return d.fields, d.err
+}
+
+func uliFields(uli *ULI) []Field {
+	plmn := func(p *PLMN) string {
+		if p == nil {
+			return ""
+		}
+		return fmt.Sprintf("MCC: %s, MNC: %s, ", p.MCC, p.MNC)
+	}
+
+	var fields []Field
+	if v := uli.CGI; v != nil {
+		fields = append(fields, Field{"CGI", fmt.Sprintf("%sLAC: %#x, CI: %#x", plmn(v.PLMN), v.LAC, v.CI)})
+	}
+	if v := uli.SAI; v != nil {
+		fields = append(fields, Field{"SAI", fmt.Sprintf("%sLAC: %#x, SAC: %#x", plmn(v.PLMN), v.LAC, v.SAC)})
+	}
+	if v := uli.RAI; v != nil {
+		fields = append(fields, Field{"RAI", fmt.Sprintf("%sLAC: %#x, RAC: %#x", plmn(v.PLMN), v.LAC, v.RAC)})
+	}
+	if v := uli.TAI; v != nil {
+		fields = append(fields, Field{"TAI", fmt.Sprintf("%sTAC: %#x", plmn(v.PLMN), v.TAC)})
+	}
+	if v := uli.ECGI; v != nil {
+		fields = append(fields, Field{"ECGI", fmt.Sprintf("%sECI: %#x", plmn(v.PLMN), v.ECI)})
+	}
+	if v := uli.LAI; v != nil {
+		fields = append(fields, Field{"LAI", fmt.Sprintf("%sLAC: %#x", plmn(v.PLMN), v.LAC)})
+	}
+	if v := uli.MENBI; v != nil {
+		fields = append(fields, Field{"Macro eNodeB ID", fmt.Sprintf("%sMacro eNodeB ID: %#x", plmn(v.PLMN), v.MENBI)})
+	}
+	if v := uli.EMENBI; v != nil {
+		fields = append(fields, Field{
+			"Extended Macro eNodeB ID",
+			fmt.Sprintf("%sExtended Macro eNodeB ID: %#x, SMeNB: %t", plmn(v.PLMN), v.EMENBI, v.SMeNB),
+		})
+	}
+	return fields
+}
+
+// Dump returns the IE in human readable format like the packet analyzers, with the
+// values decoded by Fields and the IEs in the grouped IE as the nested tree.
+//
+// The payload is shown in hexadecimal if it is not decoded. Each line is indented
+// with two spaces per depth of the IE.
+func (i *IE) Dump() string {
+	var sb strings.Builder
+	i.dumpTo(&sb, 0)
+	return sb.String()
+}
+
+func (i *IE) dumpTo(sb *strings.Builder, depth int) {
+	indent := strings.Repeat("  ", depth)
+	fmt.Fprintf(sb, "%s%s (%d), Length: %d, Instance: %d\n", indent, TypeName(i.Type), i.Type, i.Length, i.Instance())
+
+	if i.IsGrouped() && !i.raw {
+		for _, child := range i.ChildIEs {
+			child.dumpTo(sb, depth+1)
+		}
+		return
+	}
+
+	fields, err := i.Fields()
+	for _, f := range fields {
+		fmt.Fprintf(sb, "%s  %s: %v\n", indent, f.Name, f.Value)
+	}
+	if err != nil || len(fields) == 0 {
+		fmt.Fprintf(sb, "%s  Payload: %x\n", indent, i.Payload)
+	}
+	if err != nil {
+		fmt.Fprintf(sb, "%s  Error: %v\n", indent, err)
+	}
+}
+
+type ieJSON struct {
+	Type     uint8                  `json:"type"`
+	Name     string                 `json:"name"`
+	Length   uint16                 `json:"length"`
+	Instance uint8                  `json:"instance"`
+	Fields   map[string]interface{} `json:"fields,omitempty"`
+	Payload  string                 `json:"payload,omitempty"`
+	Error    string                 `json:"error,omitempty"`
+	IEs      []*IE                  `json:"ies,omitempty"`
+}
+
+// MarshalJSON returns the IE in JSON for the structured logging, with the same
+// contents as Dump. The IEs in the grouped IE are in "ies".
+func (i *IE) MarshalJSON() ([]byte, error) {
+	v := &ieJSON{
+		Type:     i.Type,
+		Name:     TypeName(i.Type),
+		Length:   i.Length,
+		Instance: i.Instance(),
+	}
+
+	if i.IsGrouped() && !i.raw {
+		v.IEs = i.ChildIEs
+		return json.Marshal(v)
+	}
+
+	fields, err := i.Fields()
+	if len(fields) > 0 {
+		v.Fields = make(map[string]interface{}, len(fields))
+		for _, f := range fields {
+			v.Fields[f.Name] = f.Value
+		}
+	}
+	if err != nil || len(fields) == 0 {
+		v.Payload = hex.EncodeToString(i.Payload)
+	}
+	if err != nil {
+		v.Error = err.Error()
+	}
+	return json.Marshal(v)
+}
@@ -14,14 +14,23 @@ func NewEPSBearerID(ebi uint8) *IE {
 
 // EPSBearerID returns EPSBearerID if the type of IE matches.
 func (i *IE) EPSBearerID() (uint8, error) {
-	if i.Type != EPSBearerID {
-		return 0, &InvalidTypeError{Type: i.Type}
-	}
 	if len(i.Payload) == 0 {
 		return 0, io.ErrUnexpectedEOF
 	}
 
-	return i.Payload[0], nil
+	switch i.Type {
+	case EPSBearerID:
+		return i.Payload[0], nil
+	case PagingAndServiceInformation:
+		return i.Payload[0] & 0x0f, nil
+	case SecondaryRATUsageDataReport:
+		if len(i.Payload) < 3 {
+			return 0, io.ErrUnexpectedEOF
+		}
+		return i.Payload[2] & 0x0f, nil
+	default:
+		return 0, &InvalidTypeError{Type: i.Type}
+	}
 }
 
 // MustEPSBearerID returns EPSBearerID in uint8, ignoring errors.
@@ -0,0 +1,89 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import (
+	"io"
+	"math"
+	"time"
+)
+
+// EPCTimerInfinite is the value of EPCTimer that represents the infinite timer.
+const EPCTimerInfinite = time.Duration(math.MaxInt64)
+
+// Timer unit definitions for EPCTimer.
+const (
+	epcTimerUnit2Sec uint8 = iota
+	epcTimerUnit1Min
+	epcTimerUnit10Min
+	epcTimerUnit1Hour
+	epcTimerUnit10Hour
+	epcTimerUnitInfinite uint8 = 7
+)
+
+var epcTimerUnits = []struct {
+	unit uint8
+	dur  time.Duration
+}{
+	{epcTimerUnit2Sec, 2 * time.Second},
+	{epcTimerUnit1Min, time.Minute},
+	{epcTimerUnit10Min, 10 * time.Minute},
+	{epcTimerUnit1Hour, time.Hour},
+	{epcTimerUnit10Hour, 10 * time.Hour},
+}
+
+// NewEPCTimer creates a new EPCTimer IE.
+//
+// The timer unit is chosen to be the smallest one that can represent the duration,
+// and the duration is rounded down to the unit. The duration that is too long to be
+// represented, e.g., EPCTimerInfinite, is encoded as the infinite timer.
+func NewEPCTimer(duration time.Duration) *IE {
+	if duration < 0 {
+		duration = 0
+	}
+	for _, u := range epcTimerUnits {
+		if v := duration / u.dur; v <= 0x1f {
+			return newUint8ValIE(EPCTimer, (u.unit<<5)|uint8(v))
+		}
+	}
+	return newUint8ValIE(EPCTimer, epcTimerUnitInfinite<<5)
+}
+
+// EPCTimer returns EPCTimer in time.Duration if the type of IE matches.
+// The infinite timer is returned as EPCTimerInfinite.
+func (i *IE) EPCTimer() (time.Duration, error) {
+	if i.Type != EPCTimer {
+		return 0, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) == 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	return decodeEPCTimer(i.Payload[0]), nil
+}
+
+// decodeEPCTimer decodes the octet of timer unit and value, which is also used in
+// Throttling IE.
+func decodeEPCTimer(b uint8) time.Duration {
+	unit := b >> 5
+	value := time.Duration(b & 0x1f)
+	if unit == epcTimerUnitInfinite {
+		return EPCTimerInfinite
+	}
+	for _, u := range epcTimerUnits {
+		if u.unit == unit {
+			return value * u.dur
+		}
+	}
+	// TS 29.274 8.87: other values shall be interpreted as multiples of 1 minute.
+	return value * time.Minute
+}
+
+// MustEPCTimer returns EPCTimer in time.Duration, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustEPCTimer() time.Duration {
+	v, _ := i.EPCTimer()
+	return v
+}
@@ -17,7 +17,8 @@ var (
 	ErrInvalidType = errors.New("invalid type")
 	ErrIENotFound  = errors.New("could not find the specified IE in a grouped IE")
 
-	ErrMalformed = errors.New("malformed IE")
+	ErrMalformed       = errors.New("malformed IE")
+	ErrFieldNotPresent = errors.New("the field is not present in the IE")
 )
 
 // InvalidTypeError indicates the type of IE is invalid.
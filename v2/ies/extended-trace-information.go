@@ -0,0 +1,110 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/wmnsk/go-gtp/utils"
+)
+
+// NewExtendedTraceInformation creates a new ExtendedTraceInformation IE.
+//
+// Unlike TraceInformation, the triggeringEvents and interfaces are encoded with
+// the length as they are given. collectionEntity is the IP address of the Trace
+// Collection Entity in string.
+func NewExtendedTraceInformation(mcc, mnc string, traceID uint32, triggeringEvents []byte, neTypes uint16, depth uint8, interfaces []byte, collectionEntity string) *IE {
+	plmn, err := utils.EncodePLMN(mcc, mnc)
+	if err != nil {
+		return nil
+	}
+	ip := parseIP(collectionEntity)
+	if ip == nil {
+		return nil
+	}
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	if len(triggeringEvents) > 0xff || len(interfaces) > 0xff {
+		return nil
+	}
+
+	i := New(ExtendedTraceInformation, 0x00, make([]byte, 13+len(triggeringEvents)+len(interfaces)+len(ip)))
+	copy(i.Payload[0:3], plmn)
+	copy(i.Payload[3:6], utils.Uint32To24(traceID))
+
+	offset := 6
+	i.Payload[offset] = uint8(len(triggeringEvents))
+	copy(i.Payload[offset+1:], triggeringEvents)
+	offset += 1 + len(triggeringEvents)
+
+	i.Payload[offset] = 2
+	binary.BigEndian.PutUint16(i.Payload[offset+1:offset+3], neTypes)
+	offset += 3
+
+	i.Payload[offset] = depth
+	offset++
+
+	i.Payload[offset] = uint8(len(interfaces))
+	copy(i.Payload[offset+1:], interfaces)
+	offset += 1 + len(interfaces)
+
+	i.Payload[offset] = uint8(len(ip))
+	copy(i.Payload[offset+1:], ip)
+
+	return i
+}
+
+// extendedTrace is the variable length fields in ExtendedTraceInformation.
+type extendedTrace struct {
+	triggeringEvents []byte
+	neTypes          []byte
+	depth            uint8
+	interfaces       []byte
+	collectionEntity []byte
+}
+
+func parseExtendedTrace(b []byte) (*extendedTrace, error) {
+	if len(b) < 6 {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	t := &extendedTrace{}
+	offset := 6
+	next := func() ([]byte, error) {
+		if len(b) <= offset {
+			return nil, io.ErrUnexpectedEOF
+		}
+		l := int(b[offset])
+		if len(b) < offset+1+l {
+			return nil, io.ErrUnexpectedEOF
+		}
+		v := b[offset+1 : offset+1+l]
+		offset += 1 + l
+		return v, nil
+	}
+
+	var err error
+	if t.triggeringEvents, err = next(); err != nil {
+		return nil, err
+	}
+	if t.neTypes, err = next(); err != nil {
+		return nil, err
+	}
+	if len(b) <= offset {
+		return nil, io.ErrUnexpectedEOF
+	}
+	t.depth = b[offset]
+	offset++
+	if t.interfaces, err = next(); err != nil {
+		return nil, err
+	}
+	if t.collectionEntity, err = next(); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
@@ -11,16 +11,22 @@ import (
 )
 
 // NewFullyQualifiedTEID creates a new FullyQualifiedTEID IE.
+//
+// The zone of the IPv6 address, e.g., "fe80::1%eth0", is ignored, as it cannot be
+// carried by F-TEID. The address given in the wrong family, e.g., IPv6 address as v4,
+// is also ignored.
 func NewFullyQualifiedTEID(ifType uint8, teid uint32, v4, v6 string) *IE {
 	i := New(FullyQualifiedTEID, 0x00, make([]byte, 5))
 	i.Payload[0] = ifType
 	binary.BigEndian.PutUint32(i.Payload[1:5], teid)
 
-	if v4addr := net.ParseIP(v4); v4addr != nil {
+	// the flags are set only when the address is in the right family, otherwise the
+	// length of the payload does not match them.
+	if v4addr := parseIP(v4).To4(); v4addr != nil {
 		i.Payload[0] |= 0x80
-		i.Payload = append(i.Payload, []byte(v4addr.To4())...)
+		i.Payload = append(i.Payload, []byte(v4addr)...)
 	}
-	if v6addr := net.ParseIP(v6); v6addr != nil {
+	if v6addr := parseIP(v6); v6addr != nil && v6addr.To4() == nil {
 		i.Payload[0] |= 0x40
 		i.Payload = append(i.Payload, []byte(v6addr.To16())...)
 	}
@@ -50,7 +56,55 @@ func (i *IE) HasIPv6() bool {
 		return false
 	}
 
-	return i.Payload[0]&0x48>>6 == 1
+	return i.Payload[0]&0x40>>6 == 1
+}
+
+// IPv6Address returns the IPv6 address in string if the type of IE matches and it
+// has IPv6 address. Unlike IPAddress, this works with the one that has both IPv4 and
+// IPv6 address, i.e., F-TEID with both addresses and PAA with PDN Type IPv4v6.
+func (i *IE) IPv6Address() (string, error) {
+	switch i.Type {
+	case FullyQualifiedTEID:
+		if !i.HasIPv6() {
+			return "", ErrFieldNotPresent
+		}
+
+		offset := 5
+		if i.HasIPv4() {
+			offset += 4
+		}
+		if len(i.Payload) < offset+16 {
+			return "", io.ErrUnexpectedEOF
+		}
+		return net.IP(i.Payload[offset : offset+16]).String(), nil
+	case PDNAddressAllocation:
+		if len(i.Payload) == 0 {
+			return "", io.ErrUnexpectedEOF
+		}
+		switch i.Payload[0] {
+		case pdnTypeIPv6, pdnTypeIPv4v6:
+			if len(i.Payload) < 18 {
+				return "", io.ErrUnexpectedEOF
+			}
+			return net.IP(i.Payload[2:18]).String(), nil
+		default:
+			return "", ErrFieldNotPresent
+		}
+	case IPAddress:
+		if len(i.Payload) != net.IPv6len {
+			return "", ErrFieldNotPresent
+		}
+		return net.IP(i.Payload).String(), nil
+	default:
+		return "", &InvalidTypeError{Type: i.Type}
+	}
+}
+
+// MustIPv6Address returns IPv6Address in string, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustIPv6Address() string {
+	v, _ := i.IPv6Address()
+	return v
 }
 
 // InterfaceType returns InterfaceType in uint8 if the type of IE matches.
@@ -9,6 +9,8 @@ import (
 )
 
 // NewFlowQoS creates a new FlowQoS IE.
+//
+// The MBRs and GBRs are in kbps. Use NewFlowQoSFromBitRate to give them in BitRate.
 func NewFlowQoS(qci uint8, umbr, dmbr, ugbr, dgbr uint64) *IE {
 	i := New(FlowQoS, 0x00, make([]byte, 21))
 	i.Payload[0] = qci
@@ -18,3 +20,15 @@ func NewFlowQoS(qci uint8, umbr, dmbr, ugbr, dgbr uint64) *IE {
 	copy(i.Payload[16:21], utils.Uint64To40(dgbr))
 	return i
 }
+
+// NewFlowQoSFromBitRate creates a new FlowQoS IE with the MBRs and GBRs in BitRate.
+//
+// The values are rounded up to kbps, and the ones larger than the maximum that
+// can be encoded are set to the maximum.
+func NewFlowQoSFromBitRate(qci uint8, umbr, dmbr, ugbr, dgbr BitRate) *IE {
+	return NewFlowQoS(
+		qci,
+		umbr.kbpsWithin(maxUint40), dmbr.kbpsWithin(maxUint40),
+		ugbr.kbpsWithin(maxUint40), dgbr.kbpsWithin(maxUint40),
+	)
+}
@@ -8,7 +8,6 @@ import (
 	"encoding/binary"
 	"encoding/hex"
 	"io"
-	"net"
 )
 
 // Node-ID Type definitions.
@@ -24,7 +23,7 @@ func NewFullyQualifiedCSID(nodeID string, csIDs ...uint16) *IE {
 		nid   []byte
 		ntype uint8
 	)
-	ip := net.ParseIP(nodeID)
+	ip := parseIP(nodeID)
 	if ip == nil {
 		var err error
 		nid, err = hex.DecodeString(nodeID)
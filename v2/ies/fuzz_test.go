@@ -0,0 +1,59 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+//go:build go1.18
+// +build go1.18
+
+package ies_test
+
+import (
+	"reflect"
+	"testing"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+)
+
+// FuzzIE decodes the input as IE and calls all the getters of it, which should not
+// panic whatever the input is. The inputs that found the bugs are kept in
+// testdata/fuzz/FuzzIE.
+func FuzzIE(f *testing.F) {
+	for _, i := range []*ies.IE{
+		ies.NewIMSI("123451234567890"),
+		ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+		ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 0xffffffff, "1.1.1.1", "2001::1"),
+		ies.NewUserLocationInformationLazy("123", "45", 1, 1, 1, 1, 1, 1, 1, 1),
+		ies.NewBearerContext(ies.NewEPSBearerID(5), ies.NewBearerQoS(1, 2, 1, 0xff, 0x11111111, 0x22222222, 0x33333333, 0x44444444)),
+		ies.NewPDNAddressAllocation("2001::1/64"),
+		ies.NewTWANIdentifier("ssid", nil),
+		ies.NewExtendedTraceInformation("123", "45", 1, []byte{1}, 2, 3, []byte{4}, "1.1.1.1"),
+	} {
+		b, err := i.Marshal()
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(b)
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		i, err := ies.Parse(b)
+		if err != nil {
+			return
+		}
+		callGetters(i)
+		for _, child := range i.ChildIEs {
+			callGetters(child)
+		}
+	})
+}
+
+// callGetters calls all the methods of IE that take no arguments.
+func callGetters(i *ies.IE) {
+	v := reflect.ValueOf(i)
+	for n := 0; n < v.NumMethod(); n++ {
+		if m := v.Method(n); m.Type().NumIn() == 0 {
+			m.Call(nil)
+		}
+	}
+}
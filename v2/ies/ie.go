@@ -64,7 +64,7 @@ const (
 	_
 	_
 	_
-	_ // 35-50:  Reserved for S101 interface
+	_ // 35-50: Reserved for S121 interface
 	STNSR
 	_
 	_
@@ -279,6 +279,9 @@ type IE struct {
 	instance uint8
 	Payload  []byte
 	ChildIEs []*IE
+
+	// raw is set if the IE is decoded with ParseRaw, to be serialized as it is.
+	raw bool
 }
 
 // New creates new IE.
@@ -323,7 +326,7 @@ func (i *IE) MarshalTo(b []byte) error {
 	b[0] = i.Type
 	binary.BigEndian.PutUint16(b[1:3], i.Length)
 	b[3] = i.instance
-	if i.IsGrouped() {
+	if i.IsGrouped() && !i.raw {
 		offset := 4
 		for _, ie := range i.ChildIEs {
 			if err := ie.MarshalTo(b[offset:]); err != nil {
@@ -349,7 +352,7 @@ func Parse(b []byte) (*IE, error) {
 // UnmarshalBinary sets the values retrieved from byte sequence in GTPv2 IE.
 func (i *IE) UnmarshalBinary(b []byte) error {
 	l := len(b)
-	if l < 5 {
+	if l < 4 {
 		return ErrTooShortToParse
 	}
 
@@ -375,7 +378,7 @@ func (i *IE) UnmarshalBinary(b []byte) error {
 
 // MarshalLen returns field length in integer.
 func (i *IE) MarshalLen() int {
-	if i.IsGrouped() {
+	if i.IsGrouped() && !i.raw {
 		l := 4
 		for _, ie := range i.ChildIEs {
 			l += ie.MarshalLen()
@@ -409,6 +412,9 @@ func (i *IE) String() string {
 
 var grouped = []uint8{
 	BearerContext,
+	OverloadControlInformation,
+	LoadControlInformation,
+	PDNConnection,
 	// TODO: add all grouped type of IEs here.
 }
 
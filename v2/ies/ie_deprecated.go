@@ -45,3 +45,11 @@ func (i *IE) Len() int {
 	log.Println("IE.Len is deprecated. use IE.MarshalLen instead")
 	return i.MarshalLen()
 }
+
+// UserLocationInfo is a getter function to parse ULI
+//
+// DEPRECATED: use IE.UserLocationInformation instead.
+func (i *IE) UserLocationInfo() (*ULI, error) {
+	log.Println("IE.UserLocationInfo is deprecated. use IE.UserLocationInformation instead")
+	return ParseULI(i.Payload)
+}
@@ -5,6 +5,8 @@
 package ies_test
 
 import (
+	"encoding/json"
+	"net"
 	"testing"
 	"time"
 
@@ -35,6 +37,10 @@ func TestIEs(t *testing.T) {
 			"Recovery",
 			ies.NewRecovery(0xff),
 			[]byte{0x03, 0x00, 0x01, 0x00, 0xff},
+		}, {
+			"NodeFeatures",
+			ies.NewNodeFeatures(v2.NodeFeaturePRN | v2.NodeFeatureMABR),
+			[]byte{0x98, 0x00, 0x01, 0x00, 0x03},
 		}, {
 			"AccessPointName",
 			ies.NewAccessPointName("some.apn.example"),
@@ -43,6 +49,10 @@ func TestIEs(t *testing.T) {
 			"AggregateMaximumBitRate",
 			ies.NewAggregateMaximumBitRate(0x11111111, 0x22222222),
 			[]byte{0x48, 0x00, 0x08, 0x00, 0x11, 0x11, 0x11, 0x11, 0x22, 0x22, 0x22, 0x22},
+		}, {
+			"AggregateMaximumBitRate/FromBitRate",
+			ies.NewAggregateMaximumBitRateFromBitRate(100*ies.Mbps, 1500*ies.Bps),
+			[]byte{0x48, 0x00, 0x08, 0x00, 0x00, 0x01, 0x86, 0xa0, 0x00, 0x00, 0x00, 0x02},
 		}, {
 			"EPSBearerID",
 			ies.NewEPSBearerID(0x05),
@@ -55,6 +65,14 @@ func TestIEs(t *testing.T) {
 			"IPAddress/v6",
 			ies.NewIPAddress("2001::1"),
 			[]byte{0x4a, 0x00, 0x10, 0x00, 0x20, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+		}, {
+			"S101IPAddress",
+			ies.NewS101IPAddress("1.1.1.1"),
+			[]byte{0x4a, 0x00, 0x04, 0x00, 0x01, 0x01, 0x01, 0x01},
+		}, {
+			"S102IPAddress",
+			ies.NewS102IPAddress("2.2.2.2"),
+			[]byte{0x4a, 0x00, 0x04, 0x01, 0x02, 0x02, 0x02, 0x02},
 		}, {
 			"MobileEquipmentIdentity",
 			ies.NewMobileEquipmentIdentity("123450123456789"),
@@ -70,6 +88,13 @@ func TestIEs(t *testing.T) {
 				1, 0, 0, 0, 1, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 1, 0, 1, 0, 0, 0,
 			),
 			[]byte{0x4d, 0x00, 0x07, 0x00, 0xa1, 0x08, 0x15, 0x10, 0x88, 0x81, 0x40},
+		}, {
+			"IndicationStruct",
+			ies.NewIndicationStruct(&ies.IndicationFlags{
+				DAF: true, HI: true, SGWCI: true, PS: true, S6AF: true, MBMDT: true, CCRSI: true,
+				PPON: true, NSI: true, PSCI: true, ROAAI: true, WPMSI: true, LTEMUI: true,
+			}),
+			[]byte{0x4d, 0x00, 0x07, 0x00, 0xa1, 0x08, 0x15, 0x10, 0x88, 0x81, 0x40},
 		}, {
 			"IndicationFromBitSequence",
 			ies.NewIndicationFromBitSequence("10100001000010000001010100010000100010001000000101000"),
@@ -112,16 +137,27 @@ func TestIEs(t *testing.T) {
 			ies.NewPDNAddressAllocation("1.1.1.1"),
 			[]byte{0x4f, 0x00, 0x05, 0x00, 0x01, 0x01, 0x01, 0x01, 0x01},
 		},
-		/* XXX - needs fix in NewPDNAddressAllocation!
 		{
 			"PDNAddressAllocation/v6",
 			ies.NewPDNAddressAllocation("2001::1"),
-			[]byte{0x4f, 0x00, 0x12, 0x00, 0x02, 0x00, 0x20, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
-		}, */
+			[]byte{0x4f, 0x00, 0x12, 0x00, 0x02, 0x40, 0x20, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+		}, {
+			"PDNAddressAllocation/v6-prefix",
+			ies.NewPDNAddressAllocation("2001:db8::/56"),
+			[]byte{0x4f, 0x00, 0x12, 0x00, 0x02, 0x38, 0x20, 0x01, 0x0d, 0xb8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		}, {
+			"PDNAddressAllocation/v4v6",
+			ies.NewPDNAddressAllocationDual("1.1.1.1", "2001::1"),
+			[]byte{0x4f, 0x00, 0x16, 0x00, 0x03, 0x40, 0x20, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x01, 0x01, 0x01, 0x01},
+		},
 		{
 			"BearerQoS",
 			ies.NewBearerQoS(1, 2, 1, 0xff, 0x1111111111, 0x2222222222, 0x1111111111, 0x2222222222),
 			[]byte{0x50, 0x00, 0x16, 0x00, 0x49, 0xff, 0x11, 0x11, 0x11, 0x11, 0x11, 0x22, 0x22, 0x22, 0x22, 0x22, 0x11, 0x11, 0x11, 0x11, 0x11, 0x22, 0x22, 0x22, 0x22, 0x22},
+		}, {
+			"BearerQoS/FromBitRate",
+			ies.NewBearerQoSFromBitRate(1, 2, 1, 0xff, ies.Gbps, 1<<62, 64*ies.Kbps, 0),
+			[]byte{0x50, 0x00, 0x16, 0x00, 0x49, 0xff, 0x00, 0x00, 0x0f, 0x42, 0x40, 0xff, 0xff, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x00, 0x40, 0x00, 0x00, 0x00, 0x00, 0x00},
 		}, {
 			"FlowQoS",
 			ies.NewFlowQoS(0xff, 0x1111111111, 0x2222222222, 0x1111111111, 0x2222222222),
@@ -226,6 +262,24 @@ func TestIEs(t *testing.T) {
 				// Extended Macro eNB ID
 				0x21, 0xf3, 0x54, 0x22, 0x22, 0x22,
 			},
+		}, {
+			"UserLocationInformation/Struct",
+			ies.NewUserLocationInformationStruct(&ies.ULI{
+				TAI:    &ies.TAI{PLMN: &ies.PLMN{MCC: "123", MNC: "45"}, TAC: 0x5555},
+				ECGI:   &ies.ECGI{PLMN: &ies.PLMN{MCC: "123", MNC: "45"}, ECI: 0x0666666},
+				EMENBI: &ies.EMENBI{PLMN: &ies.PLMN{MCC: "001", MNC: "01"}, EMENBI: 0x22222, SMeNB: true},
+			}),
+			[]byte{
+				0x56, 0x00, 0x13, 0x00,
+				// Flags
+				0x98,
+				// TAI
+				0x21, 0xf3, 0x54, 0x55, 0x55,
+				// ECGI
+				0x21, 0xf3, 0x54, 0x00, 0x66, 0x66, 0x66,
+				// Extended Macro eNB ID
+				0x00, 0xf1, 0x10, 0x82, 0x22, 0x22,
+			},
 		}, {
 			"FullyQualifiedTEID/v4",
 			ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 0xffffffff, "1.1.1.1", ""),
@@ -238,6 +292,14 @@ func TestIEs(t *testing.T) {
 			"FullyQualifiedTEID/v4v6",
 			ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 0xffffffff, "1.1.1.1", "2001::1"),
 			[]byte{0x57, 0x00, 0x19, 0x00, 0xca, 0xff, 0xff, 0xff, 0xff, 0x01, 0x01, 0x01, 0x01, 0x20, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+		}, {
+			"FullyQualifiedTEID/v6-zone",
+			ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 0xffffffff, "", "fe80::1%eth0"),
+			[]byte{0x57, 0x00, 0x15, 0x00, 0x4a, 0xff, 0xff, 0xff, 0xff, 0xfe, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+		}, {
+			"FullyQualifiedTEID/v6-given-as-v4",
+			ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 0xffffffff, "2001::1", ""),
+			[]byte{0x57, 0x00, 0x05, 0x00, 0x0a, 0xff, 0xff, 0xff, 0xff},
 		}, {
 			"TMSI",
 			ies.NewTMSI(0xffffffff),
@@ -290,6 +352,13 @@ func TestIEs(t *testing.T) {
 			"ProcedureTransactionID",
 			ies.NewProcedureTransactionID(1),
 			[]byte{0x64, 0x00, 0x01, 0x00, 0x01},
+		}, {
+			"PDNConnection",
+			ies.NewPDNConnection(ies.NewEPSBearerID(5)),
+			[]byte{
+				0x6d, 0x00, 0x05, 0x00,
+				0x49, 0x00, 0x01, 0x00, 0x05,
+			},
 		}, {
 			"PacketTMSI",
 			ies.NewPacketTMSI(0xdeadbeef),
@@ -306,6 +375,17 @@ func TestIEs(t *testing.T) {
 			"UETimeZone",
 			ies.NewUETimeZone(9*time.Hour, 0),
 			[]byte{0x72, 0x00, 0x02, 0x00, 0x63, 0x00},
+		}, {
+			"TraceInformation",
+			ies.NewTraceInformation("123", "45", 1, []byte{0x01}, 0x0003, 0x01, []byte{0xff}, "1.1.1.1"),
+			[]byte{
+				0x60, 0x00, 0x22, 0x00,
+				0x21, 0xf3, 0x54, 0x00, 0x00, 0x01,
+				0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x03, 0x01,
+				0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x01, 0x01, 0x01, 0x01,
+			},
 		}, {
 			"TraceReference",
 			ies.NewTraceReference("123", "45", 1),
@@ -394,10 +474,136 @@ func TestIEs(t *testing.T) {
 			"ULITimestamp",
 			ies.NewULITimestamp(time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)),
 			[]byte{0xaa, 0x00, 0x04, 0x00, 0xdf, 0xd5, 0x2c, 0x00},
+		}, {
+			"TWANIdentifier",
+			ies.NewTWANIdentifier("ssid", net.HardwareAddr{0x00, 0x11, 0x22, 0x33, 0x44, 0x55}),
+			[]byte{0xa9, 0x00, 0x0c, 0x00, 0x01, 0x04, 0x73, 0x73, 0x69, 0x64, 0x00, 0x11, 0x22, 0x33, 0x44, 0x55},
+		}, {
+			"TrustedWLANModeIndication",
+			ies.NewTrustedWLANModeIndication(1, 0),
+			[]byte{0xae, 0x00, 0x01, 0x00, 0x02},
+		}, {
+			"TWANIdentifierTimestamp",
+			ies.NewTWANIdentifierTimestamp(time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)),
+			[]byte{0xb3, 0x00, 0x04, 0x00, 0xdf, 0xd5, 0x2c, 0x00},
+		}, {
+			"WLANOffloadabilityIndication",
+			ies.NewWLANOffloadabilityIndication(1, 1),
+			[]byte{0xb9, 0x00, 0x01, 0x00, 0x03},
+		}, {
+			"IntegerNumber",
+			ies.NewIntegerNumber(3000),
+			[]byte{0xbb, 0x00, 0x02, 0x00, 0x0b, 0xb8},
+		}, {
+			"MillisecondTimeStamp",
+			ies.NewMillisecondTimeStamp(time.Date(2019, time.January, 1, 0, 0, 0, 500000000, time.UTC)),
+			[]byte{0xbc, 0x00, 0x06, 0x00, 0x03, 0x6a, 0x58, 0xb3, 0xe1, 0xf4},
+		}, {
+			"ServingPLMNRateControl",
+			ies.NewServingPLMNRateControl(10, 20),
+			[]byte{0xc6, 0x00, 0x04, 0x00, 0x00, 0x0a, 0x00, 0x14},
+		}, {
+			"SecondaryRATUsageDataReport",
+			ies.NewSecondaryRATUsageDataReport(
+				1, 1, v2.SecondaryRATTypeNR, 5,
+				time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2019, time.January, 1, 1, 0, 0, 0, time.UTC),
+				0xffff, 0xff,
+			),
+			[]byte{
+				0xc9, 0x00, 0x1b, 0x00,
+				0x03, 0x00, 0x05,
+				0xdf, 0xd5, 0x2c, 0x00,
+				0xdf, 0xd5, 0x3a, 0x10,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff, 0xff,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff,
+			},
+		}, {
+			"UPFunctionSelectionIndicationFlags",
+			ies.NewUPFunctionSelectionIndicationFlags(1),
+			[]byte{0xca, 0x00, 0x01, 0x00, 0x01},
+		}, {
+			"ExtendedTraceInformation",
+			ies.NewExtendedTraceInformation("123", "45", 1, []byte{0x01}, 0x0003, 0x01, []byte{0xff}, "1.1.1.1"),
+			[]byte{
+				0xcd, 0x00, 0x13, 0x00,
+				0x21, 0xf3, 0x54, 0x00, 0x00, 0x01,
+				0x01, 0x01,
+				0x02, 0x00, 0x03,
+				0x01,
+				0x01, 0xff,
+				0x04, 0x01, 0x01, 0x01, 0x01,
+			},
 		}, {
 			"MBMSFlags",
 			ies.NewMBMSFlags(1, 1),
 			[]byte{0xab, 0x00, 0x01, 0x00, 0x03},
+		}, {
+			"MBMSSessionDuration",
+			ies.NewMBMSSessionDuration(25 * time.Hour),
+			[]byte{0x8a, 0x00, 0x03, 0x00, 0x07, 0x08, 0x01},
+		}, {
+			"MBMSFlowIdentifier",
+			ies.NewMBMSFlowIdentifier(0x1234),
+			[]byte{0x8d, 0x00, 0x02, 0x00, 0x12, 0x34},
+		}, {
+			"TMGI",
+			ies.NewTMGI(0x123456, "123", "45"),
+			[]byte{0x9e, 0x00, 0x06, 0x00, 0x12, 0x34, 0x56, 0x21, 0xf3, 0x54},
+		}, {
+			"ChangeToReportFlags",
+			ies.NewChangeToReportFlags(1, 1),
+			[]byte{0xa7, 0x00, 0x01, 0x00, 0x03},
+		}, {
+			"EPCTimer",
+			ies.NewEPCTimer(10 * time.Minute),
+			[]byte{0x9c, 0x00, 0x01, 0x00, 0x2a},
+		}, {
+			"EPCTimer/Infinite",
+			ies.NewEPCTimer(ies.EPCTimerInfinite),
+			[]byte{0x9c, 0x00, 0x01, 0x00, 0xe0},
+		}, {
+			"Throttling",
+			ies.NewThrottling(10*time.Minute, 50),
+			[]byte{0x9a, 0x00, 0x02, 0x00, 0x2a, 0x32},
+		}, {
+			"OverloadControlInformation",
+			ies.NewOverloadControlInformation(1, 10, 10*time.Minute, ies.NewAccessPointName("some.apn")),
+			[]byte{
+				0xb4, 0x00, 0x1f, 0x00,
+				0xb7, 0x00, 0x04, 0x00, 0x00, 0x00, 0x00, 0x01,
+				0xb6, 0x00, 0x01, 0x00, 0x0a,
+				0x9c, 0x00, 0x01, 0x00, 0x2a,
+				0x47, 0x00, 0x09, 0x00, 0x04, 0x73, 0x6f, 0x6d, 0x65, 0x03, 0x61, 0x70, 0x6e,
+			},
+		}, {
+			"LoadControlInformation",
+			ies.NewLoadControlInformation(1, 50),
+			[]byte{
+				0xb5, 0x00, 0x0d, 0x00,
+				0xb7, 0x00, 0x04, 0x00, 0x00, 0x00, 0x00, 0x01,
+				0xb6, 0x00, 0x01, 0x00, 0x32,
+			},
+		}, {
+			"Metric",
+			ies.NewMetric(50),
+			[]byte{0xb6, 0x00, 0x01, 0x00, 0x32},
+		}, {
+			"SequenceNumber",
+			ies.NewSequenceNumber(0x11223344),
+			[]byte{0xb7, 0x00, 0x04, 0x00, 0x11, 0x22, 0x33, 0x44},
+		}, {
+			"APNAndRelativeCapacity",
+			ies.NewAPNAndRelativeCapacity(50, "some.apn"),
+			[]byte{0xb8, 0x00, 0x0b, 0x00, 0x32, 0x09, 0x04, 0x73, 0x6f, 0x6d, 0x65, 0x03, 0x61, 0x70, 0x6e},
+		}, {
+			"PagingAndServiceInformation",
+			ies.NewPagingAndServiceInformation(5, 1, 0x3f),
+			[]byte{0xba, 0x00, 0x03, 0x00, 0x05, 0x01, 0x3f},
+		}, {
+			"PagingAndServiceInformation/NoPPI",
+			ies.NewPagingAndServiceInformation(5, 0, 0x3f),
+			[]byte{0xba, 0x00, 0x02, 0x00, 0x05, 0x00},
 		}, {
 			"PrivateExtension",
 			ies.NewPrivateExtension(10415, []byte{0xde, 0xad, 0xbe, 0xef}),
@@ -430,3 +636,666 @@ func TestIEs(t *testing.T) {
 		})
 	}
 }
+
+func TestWalk(t *testing.T) {
+	ie := []*ies.IE{
+		ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 0x11111111, "127.0.0.1", ""),
+		ies.NewBearerContext(
+			ies.NewEPSBearerID(5),
+			ies.NewFullyQualifiedTEID(v2.IFTypeS1UeNodeBGTPU, 0x22222222, "127.0.0.1", ""),
+		),
+		ies.NewBearerContext(
+			ies.NewEPSBearerID(6),
+			ies.NewFullyQualifiedTEID(v2.IFTypeS1UeNodeBGTPU, 0x33333333, "127.0.0.1", ""),
+			ies.NewFullyQualifiedTEID(v2.IFTypeS5S8SGWGTPU, 0x44444444, "127.0.0.1", "").WithInstance(1),
+		),
+	}
+
+	var (
+		types  []uint8
+		depths []int
+	)
+	ies.Walk(ie, func(i *ies.IE, depth int) bool {
+		types = append(types, i.Type)
+		depths = append(depths, depth)
+		return true
+	})
+	wantTypes := []uint8{
+		ies.FullyQualifiedTEID,
+		ies.BearerContext, ies.EPSBearerID, ies.FullyQualifiedTEID,
+		ies.BearerContext, ies.EPSBearerID, ies.FullyQualifiedTEID, ies.FullyQualifiedTEID,
+	}
+	if diff := cmp.Diff(types, wantTypes); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff(depths, []int{0, 0, 1, 1, 0, 1, 1, 1}); diff != "" {
+		t.Error(diff)
+	}
+
+	var visited int
+	ies.Walk(ie, func(i *ies.IE, depth int) bool {
+		visited++
+		return i.Type != ies.EPSBearerID
+	})
+	if visited != 3 {
+		t.Errorf("Walk should stop at the first EBI. want %d visits, got: %d", 3, visited)
+	}
+
+	var teids []uint32
+	for _, fteid := range ies.Find(ie, ies.FullyQualifiedTEID, 0) {
+		teids = append(teids, fteid.MustTEID())
+	}
+	if diff := cmp.Diff(teids, []uint32{0x11111111, 0x22222222, 0x33333333}); diff != "" {
+		t.Error(diff)
+	}
+
+	fteids, err := ie[2].FindAllByType(ies.FullyQualifiedTEID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fteids) != 2 {
+		t.Errorf("wrong number of F-TEIDs. want %d, got: %d", 2, len(fteids))
+	}
+	if _, err := ie[0].FindAllByType(ies.FullyQualifiedTEID); err != ies.ErrInvalidType {
+		t.Errorf("FindAllByType on non-grouped IE should fail, got: %v", err)
+	}
+}
+
+func TestParsePCOPayload(t *testing.T) {
+	b := []byte{0x80, 0x00, 0x0d, 0x04, 0xc0, 0x00, 0x02, 0x01, 0x00, 0x10, 0x00}
+	p, err := ies.ParsePCOPayload(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := ies.NewPCOPayload(
+		0,
+		ies.NewConfigurationProtocolOption(0x000d, []byte{0xc0, 0x00, 0x02, 0x01}),
+		ies.NewConfigurationProtocolOption(0x0010, nil),
+	)
+	if diff := cmp.Diff(p, want); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestUserLocationInformation(t *testing.T) {
+	want := &ies.ULI{
+		CGI:    &ies.CGI{PLMN: &ies.PLMN{MCC: "123", MNC: "45"}, LAC: 0x1111, CI: 0x2222},
+		SAI:    &ies.SAI{PLMN: &ies.PLMN{MCC: "123", MNC: "45"}, LAC: 0x1111, SAC: 0x3333},
+		RAI:    &ies.RAI{PLMN: &ies.PLMN{MCC: "123", MNC: "45"}, LAC: 0x1111, RAC: 0x4444},
+		TAI:    &ies.TAI{PLMN: &ies.PLMN{MCC: "001", MNC: "01"}, TAC: 0x5555},
+		ECGI:   &ies.ECGI{PLMN: &ies.PLMN{MCC: "001", MNC: "01"}, ECI: 0x0abcdef1},
+		LAI:    &ies.LAI{PLMN: &ies.PLMN{MCC: "123", MNC: "456"}, LAC: 0x1111},
+		MENBI:  &ies.MENBI{PLMN: &ies.PLMN{MCC: "123", MNC: "45"}, MENBI: 0x0fffff},
+		EMENBI: &ies.EMENBI{PLMN: &ies.PLMN{MCC: "123", MNC: "45"}, EMENBI: 0x1fffff},
+	}
+
+	i := ies.NewUserLocationInformationStruct(want)
+	serialized, err := i.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ies.Parse(serialized)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := parsed.UserLocationInformation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Error(diff)
+	}
+
+	if _, err := ies.NewIMSI("123451234567890").UserLocationInformation(); err == nil {
+		t.Error("UserLocationInformation should fail with the IE of other type")
+	}
+	if ies.NewUserLocationInformationStruct(&ies.ULI{TAI: &ies.TAI{TAC: 1}}) != nil {
+		t.Error("NewUserLocationInformationStruct should fail without PLMN")
+	}
+}
+
+func TestTWANIdentifier(t *testing.T) {
+	want := &ies.TWANID{
+		SSID:              []byte("ssid"),
+		BSSID:             net.HardwareAddr{0x00, 0x11, 0x22, 0x33, 0x44, 0x55},
+		CivicAddress:      []byte{0x01, 0x02, 0x03},
+		PLMN:              &ies.PLMN{MCC: "123", MNC: "45"},
+		OperatorName:      []byte("operator"),
+		RelayIdentityType: 1,
+		RelayIdentity:     []byte("relay.example"),
+		CircuitID:         []byte{0xde, 0xad},
+	}
+
+	i := ies.NewTWANIdentifierStruct(want).WithInstance(1)
+	serialized, err := i.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ies.Parse(serialized)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := parsed.TWANIdentifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Error(diff)
+	}
+
+	if _, err := ies.ParseTWANID(serialized[4 : len(serialized)-1]); err == nil {
+		t.Error("ParseTWANID should fail with the truncated bytes")
+	}
+	if ies.NewTWANIdentifierStruct(&ies.TWANID{SSID: []byte("ssid"), BSSID: net.HardwareAddr{0x00}}) != nil {
+		t.Error("NewTWANIdentifierStruct should fail with the invalid BSSID")
+	}
+
+	twmi := ies.NewTrustedWLANModeIndication(0, 1)
+	if twmi.MultipleConnectionMode() || !twmi.SingleConnectionMode() {
+		t.Errorf("unexpected Trusted WLAN Mode: %#x", twmi.MustTrustedWLANModeIndication())
+	}
+	woi := ies.NewWLANOffloadabilityIndication(1, 0)
+	if !woi.EUTRANOffloadable() || woi.UTRANOffloadable() {
+		t.Errorf("unexpected WLAN Offloadability: %#x", woi.MustWLANOffloadabilityIndication())
+	}
+}
+
+func TestPDNAddressAllocation(t *testing.T) {
+	cases := []struct {
+		description string
+		ie          *ies.IE
+		v4, v6      string
+		prefixLen   uint8
+	}{
+		{"v4", ies.NewPDNAddressAllocation("10.0.0.1"), "10.0.0.1", "", 0},
+		{"v6", ies.NewPDNAddressAllocation("2001:db8::1"), "", "2001:db8::1", 64},
+		{"v4v6", ies.NewPDNAddressAllocationDual("10.0.0.1", "2001:db8::/48"), "10.0.0.1", "2001:db8::", 48},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			b, err := c.ie.Marshal()
+			if err != nil {
+				t.Fatal(err)
+			}
+			i, err := ies.Parse(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if c.v4 != "" {
+				if got := i.MustIPAddress(); got != c.v4 {
+					t.Errorf("wrong IPv4 address: %s", got)
+				}
+			}
+			if c.v6 == "" {
+				if _, err := i.IPv6Address(); err != ies.ErrFieldNotPresent {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if got := i.MustIPv6Address(); got != c.v6 {
+				t.Errorf("wrong IPv6 address: %s", got)
+			}
+			if got := i.MustIPv6PrefixLength(); got != c.prefixLen {
+				t.Errorf("wrong prefix length: %d", got)
+			}
+		})
+	}
+}
+
+func TestBitRate(t *testing.T) {
+	qos := ies.NewBearerQoS(0, 0, 0, 9, 1000, 2000, 0x1111111111, 1)
+	cases := []struct {
+		description string
+		get         func() (ies.BitRate, error)
+		want        ies.BitRate
+	}{
+		{"UplinkAMBR", ies.NewAggregateMaximumBitRate(0xffffffff, 0).UplinkAMBR, 0xffffffff * ies.Kbps},
+		{"DownlinkAMBR", ies.NewAggregateMaximumBitRate(0, 64).DownlinkAMBR, 64 * ies.Kbps},
+		{"UplinkMBR", qos.UplinkMBR, ies.Mbps},
+		{"DownlinkMBR", qos.DownlinkMBR, 2 * ies.Mbps},
+		{"UplinkGBR", qos.UplinkGBR, 0x1111111111 * ies.Kbps},
+		{"DownlinkGBR", qos.DownlinkGBR, ies.Kbps},
+		{"FlowQoS", ies.NewFlowQoSFromBitRate(1, ies.Mbps, 0, 0, 0).UplinkMBR, ies.Mbps},
+	}
+	for _, c := range cases {
+		got, err := c.get()
+		if err != nil {
+			t.Fatalf("%s: %v", c.description, err)
+		}
+		if got != c.want {
+			t.Errorf("%s: wrong BitRate. want %d, got: %d", c.description, c.want, got)
+		}
+	}
+
+	if _, err := ies.NewIMSI("123451234567890").UplinkMBR(); err == nil {
+		t.Error("UplinkMBR should fail with the IE of other type")
+	}
+	if got := (1500 * ies.Bps).Kbps(); got != 2 {
+		t.Errorf("Kbps should round up. want %d, got: %d", 2, got)
+	}
+}
+
+func TestTimers(t *testing.T) {
+	th := ies.NewThrottling(30*time.Second, 150)
+	if d, err := th.ThrottlingDelay(); err != nil || d != 30*time.Second {
+		t.Errorf("wrong ThrottlingDelay: %v, %v", d, err)
+	}
+	if f, err := th.ThrottlingFactor(); err != nil || f != 100 {
+		t.Errorf("wrong ThrottlingFactor: %d, %v", f, err)
+	}
+	if d, err := ies.NewThrottling(0, 10).ThrottlingDelay(); err != nil || d != 0 {
+		t.Errorf("deactivated ThrottlingDelay should be zero: %v, %v", d, err)
+	}
+
+	dv := ies.NewDelayValue(time.Minute)
+	if raw, err := dv.DelayValueRaw(); err != nil || raw != 0xff {
+		t.Errorf("DelayValue should be capped. got: %d, %v", raw, err)
+	}
+	if d, err := dv.DelayValue(); err != nil || d != 12750*time.Millisecond {
+		t.Errorf("wrong DelayValue: %v, %v", d, err)
+	}
+
+	sd := 2*24*time.Hour + 90*time.Second + 500*time.Millisecond
+	if d, err := ies.NewMBMSSessionDuration(sd).MBMSSessionDuration(); err != nil || d != 2*24*time.Hour+90*time.Second {
+		t.Errorf("wrong MBMSSessionDuration: %v, %v", d, err)
+	}
+	if d, err := ies.NewMBMSSessionDuration(30 * 24 * time.Hour).MBMSSessionDuration(); err != nil || d != 19*24*time.Hour {
+		t.Errorf("MBMSSessionDuration should be capped. got: %v, %v", d, err)
+	}
+}
+
+func TestIndication(t *testing.T) {
+	want := &ies.IndicationFlags{DAF: true, OI: true, CRSI: true, ISRAU: true, TSPCMI: true}
+	got, err := ies.NewIndicationStruct(want).Indication()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Error(diff)
+	}
+
+	ie := ies.NewIndicationFromOctets(0x88)
+	if !ie.DAF() || !ie.OI() || ie.DTF() || ie.CRSI() {
+		t.Errorf("wrong flags in %#x", ie.Payload)
+	}
+	if got := ie.MustIndication(); *got != (ies.IndicationFlags{DAF: true, OI: true}) {
+		t.Errorf("wrong flags decoded from short Indication: %+v", got)
+	}
+}
+
+func TestISRFlags(t *testing.T) {
+	ind := ies.NewIndicationFromOctets(0x02, 0x00, 0x02)
+	if !ind.ISRAI() || ind.ISRSI() || !ind.ISRAU() {
+		t.Errorf("wrong ISR flags: ISRAI=%t, ISRSI=%t, ISRAU=%t", ind.ISRAI(), ind.ISRSI(), ind.ISRAU())
+	}
+	if ies.NewIndicationFromOctets(0x04).ISRAU() {
+		t.Error("ISRAU should not be set if the octet is not present")
+	}
+
+	crf := ies.NewChangeToReportFlags(0, 1)
+	if crf.ServingNetworkChangeToReport() || !crf.TimeZoneChangeToReport() {
+		t.Errorf("wrong Change to Report Flags: %#x", crf.MustChangeToReportFlags())
+	}
+	if ies.NewRecovery(1).ISRAI() {
+		t.Error("ISRAI should not be set in non-Indication IE")
+	}
+}
+
+func TestTMGI(t *testing.T) {
+	ie := ies.NewTMGI(0xabcdef, "123", "456")
+	if id, err := ie.MBMSServiceID(); err != nil || id != 0xabcdef {
+		t.Errorf("wrong MBMSServiceID: %#x, %v", id, err)
+	}
+	if mcc, err := ie.MCC(); err != nil || mcc != "123" {
+		t.Errorf("wrong MCC: %s, %v", mcc, err)
+	}
+	if mnc, err := ie.MNC(); err != nil || mnc != "456" {
+		t.Errorf("wrong MNC: %s, %v", mnc, err)
+	}
+}
+
+func TestSecondaryRATUsageDataReport(t *testing.T) {
+	start := time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	i := ies.NewSecondaryRATUsageDataReport(0, 1, v2.SecondaryRATTypeNR, 6, start, end, 1000, 2000)
+
+	if i.IntendedReceiverSGW() || !i.IntendedReceiverPGW() {
+		t.Errorf("wrong flags: %#x", i.Payload[0])
+	}
+	if typ, err := i.SecondaryRATType(); err != nil || typ != v2.SecondaryRATTypeNR {
+		t.Errorf("wrong SecondaryRATType: %d, %v", typ, err)
+	}
+	if ebi, err := i.EPSBearerID(); err != nil || ebi != 6 {
+		t.Errorf("wrong EPSBearerID: %d, %v", ebi, err)
+	}
+	if ts, err := i.StartTimestamp(); err != nil || !ts.Equal(start) {
+		t.Errorf("wrong StartTimestamp: %v, %v", ts, err)
+	}
+	if ts, err := i.EndTimestamp(); err != nil || !ts.Equal(end) {
+		t.Errorf("wrong EndTimestamp: %v, %v", ts, err)
+	}
+	if dl, err := i.UsageDataDL(); err != nil || dl != 1000 {
+		t.Errorf("wrong UsageDataDL: %d, %v", dl, err)
+	}
+	if ul, err := i.UsageDataUL(); err != nil || ul != 2000 {
+		t.Errorf("wrong UsageDataUL: %d, %v", ul, err)
+	}
+}
+
+func TestExtendedTraceInformation(t *testing.T) {
+	i := ies.NewExtendedTraceInformation("123", "45", 1, []byte{0x01, 0x02}, 0x0003, 0x01, []byte{0xff}, "2001::1")
+
+	if mcc, err := i.MCC(); err != nil || mcc != "123" {
+		t.Errorf("wrong MCC: %s, %v", mcc, err)
+	}
+	if id, err := i.TraceID(); err != nil || id != 1 {
+		t.Errorf("wrong TraceID: %d, %v", id, err)
+	}
+	if ev, err := i.TriggeringEvents(); err != nil || len(ev) != 2 || ev[1] != 0x02 {
+		t.Errorf("wrong TriggeringEvents: %x, %v", ev, err)
+	}
+	if ne, err := i.ListOfNETypes(); err != nil || ne != 0x0003 {
+		t.Errorf("wrong ListOfNETypes: %x, %v", ne, err)
+	}
+	if d, err := i.SessionTraceDepth(); err != nil || d != 1 {
+		t.Errorf("wrong SessionTraceDepth: %d, %v", d, err)
+	}
+	if ifs, err := i.ListOfInterfaces(); err != nil || len(ifs) != 1 || ifs[0] != 0xff {
+		t.Errorf("wrong ListOfInterfaces: %x, %v", ifs, err)
+	}
+	if ip, err := i.TraceCollectionEntity(); err != nil || ip != "2001::1" {
+		t.Errorf("wrong TraceCollectionEntity: %s, %v", ip, err)
+	}
+
+	i.Payload = i.Payload[:len(i.Payload)-1]
+	if _, err := i.TraceCollectionEntity(); err == nil {
+		t.Error("truncated ExtendedTraceInformation should fail")
+	}
+}
+
+type testVendorValue struct {
+	Flag  uint8
+	Label string
+}
+
+func TestPrivateExtensionCodec(t *testing.T) {
+	const id uint16 = 0x1234
+	ies.RegisterPrivateExtensionCodec(id, ies.PrivateExtensionCodecFuncs{
+		Encode: func(v interface{}) ([]byte, error) {
+			val, ok := v.(*testVendorValue)
+			if !ok {
+				return nil, ies.ErrMalformed
+			}
+			return append([]byte{val.Flag}, val.Label...), nil
+		},
+		Decode: func(b []byte) (interface{}, error) {
+			if len(b) < 1 {
+				return nil, ies.ErrMalformed
+			}
+			return &testVendorValue{Flag: b[0], Label: string(b[1:])}, nil
+		},
+	})
+	defer ies.RegisterPrivateExtensionCodec(id, nil)
+
+	i, err := ies.NewPrivateExtensionFrom(id, &testVendorValue{Flag: 1, Label: "foo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(i.Payload, []byte{0x12, 0x34, 0x01, 'f', 'o', 'o'}); diff != "" {
+		t.Error(diff)
+	}
+
+	v, err := i.DecodePrivateExtension()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(v, &testVendorValue{Flag: 1, Label: "foo"}); diff != "" {
+		t.Error(diff)
+	}
+
+	unknown := ies.NewPrivateExtension(0x5678, []byte{0xde, 0xad})
+	if _, err := unknown.DecodePrivateExtension(); err == nil {
+		t.Error("decoding with unregistered Enterprise ID should fail")
+	} else if e, ok := err.(*ies.UnknownEnterpriseIDError); !ok || e.EnterpriseID != 0x5678 {
+		t.Errorf("UnknownEnterpriseIDError should be returned, got: %v", err)
+	}
+	if _, err := ies.NewPrivateExtensionFrom(0x5678, nil); err == nil {
+		t.Error("encoding with unregistered Enterprise ID should fail")
+	}
+}
+
+func TestParseRaw(t *testing.T) {
+	// BearerContext with the child IE whose Length exceeds the parent.
+	b := []byte{0x5d, 0x00, 0x06, 0x00, 0x49, 0x00, 0x05, 0x00, 0x05, 0x06}
+	if _, err := ies.Parse(b); err == nil {
+		t.Fatal("decoding malformed grouped IE should fail")
+	}
+
+	i, err := ies.ParseRaw(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !i.IsRaw() || i.Type != ies.BearerContext || i.ChildIEs != nil {
+		t.Errorf("unexpected IE: %v", i)
+	}
+	got, err := i.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, b); diff != "" {
+		t.Error(diff)
+	}
+
+	// truncated one is also kept as it is.
+	i, err = ies.ParseRaw(b[:7])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := i.Length, uint16(6); got != want {
+		t.Errorf("unexpected Length: got %d, want %d", got, want)
+	}
+	got, err = i.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, b[:7]); diff != "" {
+		t.Error(diff)
+	}
+
+	if _, err := ies.ParseRaw(b[:3]); err != ies.ErrTooShortToParse {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestDump(t *testing.T) {
+	bc := ies.NewBearerContext(
+		ies.NewEPSBearerID(5),
+		ies.NewFullyQualifiedTEID(v2.IFTypeS1UeNodeBGTPU, 0x11111111, "1.1.1.1", ""),
+	)
+	want := `BearerContext (93), Length: 18, Instance: 0
+  EPSBearerID (73), Length: 1, Instance: 0
+    EBI: 5
+  FullyQualifiedTEID (87), Length: 9, Instance: 0
+    Interface Type: 0
+    TEID/GRE Key: 0x11111111
+    IPv4 Address: 1.1.1.1
+`
+	if diff := cmp.Diff(bc.Dump(), want); diff != "" {
+		t.Error(diff)
+	}
+
+	b, err := json.Marshal(bc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantJSON := `{"type":93,"name":"BearerContext","length":18,"instance":0,"ies":[` +
+		`{"type":73,"name":"EPSBearerID","length":1,"instance":0,"fields":{"EBI":5}},` +
+		`{"type":87,"name":"FullyQualifiedTEID","length":9,"instance":0,` +
+		`"fields":{"IPv4 Address":"1.1.1.1","Interface Type":0,"TEID/GRE Key":286331153}}]}`
+	if diff := cmp.Diff(string(b), wantJSON); diff != "" {
+		t.Error(diff)
+	}
+
+	// the payload is shown as it is if not decoded, with the error if malformed.
+	unknown := ies.New(253, 0, []byte{0xde, 0xad})
+	if got, want := unknown.Dump(), "Unknown (253), Length: 2, Instance: 0\n  Payload: dead\n"; got != want {
+		t.Errorf("wrong dump. want %q, got: %q", want, got)
+	}
+	s121 := ies.New(35, 0, []byte{0xbe, 0xef})
+	if got, want := s121.Dump(), "S121 (35), Length: 2, Instance: 0\n  Payload: beef\n"; got != want {
+		t.Errorf("wrong dump. want %q, got: %q", want, got)
+	}
+	malformed := &ies.IE{Type: ies.IMSI}
+	if got, want := malformed.Dump(), "IMSI (1), Length: 0, Instance: 0\n  Payload: \n  Error: unexpected EOF\n"; got != want {
+		t.Errorf("wrong dump. want %q, got: %q", want, got)
+	}
+}
+
+func TestIdentityIEs(t *testing.T) {
+	i, err := ies.NewIMSIFrom("123451234567890")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := i.MustIMSI(); got != "123451234567890" {
+		t.Errorf("wrong IMSI: %s", got)
+	}
+
+	if _, err := ies.NewMSISDNFrom("81901234567a"); err == nil {
+		t.Error("malformed MSISDN is encoded")
+	}
+	if _, err := ies.NewMobileEquipmentIdentityFrom("123450123456789"); err == nil {
+		t.Error("IMEI with wrong check digit is encoded")
+	}
+}
+
+func TestPLMN(t *testing.T) {
+	for _, c := range []struct {
+		mcc, mnc string
+		ie       *ies.IE
+	}{
+		{"001", "01", ies.NewServingNetwork("001", "01")},
+		{"001", "001", ies.NewServingNetwork("001", "001")},
+		{"310", "260", ies.NewGUTI("310", "260", 1, 2, 3)},
+		{"001", "010", ies.NewUserLocationInformationLazy("001", "010", -1, -1, -1, -1, 1, 1, -1, -1)},
+	} {
+		p, err := c.ie.PLMN()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.MCC != c.mcc || p.MNC != c.mnc || p.HasThreeDigitMNC() != (len(c.mnc) == 3) {
+			t.Errorf("wrong PLMN in IE type %d: got %s, want %s%s", c.ie.Type, p, c.mcc, c.mnc)
+		}
+	}
+
+	for _, c := range [][2]string{{"01", "01"}, {"001", "1"}, {"001", "0001"}, {"0a1", "01"}} {
+		if _, err := ies.NewPLMN(c[0], c[1]); err == nil {
+			t.Errorf("malformed PLMN is accepted: %v", c)
+		}
+	}
+	if _, err := ies.ParsePLMN([]byte{0x00, 0xf1, 0xa0}); err == nil {
+		t.Error("malformed PLMN is decoded")
+	}
+}
+
+func TestUETimeZone(t *testing.T) {
+	for _, c := range []struct {
+		tz      time.Duration
+		dst     uint8
+		encoded byte
+	}{
+		{12 * time.Hour, v2.DaylightSavingNoAdjustment, 0x84},
+		{13*time.Hour + 45*time.Minute, v2.DaylightSavingPlusOneHour, 0x55},
+		{-(3*time.Hour + 30*time.Minute), v2.DaylightSavingNoAdjustment, 0x49},
+	} {
+		i := ies.NewUETimeZone(c.tz, c.dst)
+		if i == nil {
+			t.Fatalf("failed to create UETimeZone with %v", c.tz)
+		}
+		if i.Payload[0] != c.encoded {
+			t.Errorf("wrong encoding of %v: got %#x, want %#x", c.tz, i.Payload[0], c.encoded)
+		}
+		if tz, err := i.TimeZone(); err != nil || tz != c.tz {
+			t.Errorf("wrong TimeZone: %v, %v", tz, err)
+		}
+		if std, err := i.StandardTimeZone(); err != nil || std != c.tz-time.Duration(c.dst)*time.Hour {
+			t.Errorf("wrong StandardTimeZone: %v, %v", std, err)
+		}
+	}
+
+	loc, err := ies.NewUETimeZoneFromTime(time.Date(2019, 1, 1, 0, 0, 0, 0, time.FixedZone("", -3*3600-1800)), 0).TimeZoneLocation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name, offset := time.Date(2019, 1, 1, 0, 0, 0, 0, loc).Zone(); name != "UTC-03:30" || offset != -3*3600-1800 {
+		t.Errorf("wrong Location: %s, %d", name, offset)
+	}
+	if ies.NewUETimeZone(20*time.Hour, 0) != nil {
+		t.Error("TimeZone out of range is encoded")
+	}
+}
+
+func TestMaximumWaitTime(t *testing.T) {
+	if d, err := ies.NewMaximumWaitTime(1500 * time.Millisecond).MaximumWaitTime(); err != nil || d != 1500*time.Millisecond {
+		t.Errorf("wrong MaximumWaitTime: %v, %v", d, err)
+	}
+	if ies.NewMaximumWaitTime(-time.Second) != nil {
+		t.Error("negative MaximumWaitTime is encoded")
+	}
+}
+
+func TestEPSSecurityContext(t *testing.T) {
+	want := &ies.EPSSecurityContext{
+		SecurityMode:     4,
+		KSIASME:          1,
+		NASIntegrity:     2,
+		NASCipher:        1,
+		NASDownlinkCount: 0x000102,
+		NASUplinkCount:   0x000304,
+		Quadruplets: []*ies.AuthQuadruplet{{
+			XRES: []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+			AUTN: make([]byte, 16),
+		}},
+		Quintuplets: []*ies.AuthQuintuplet{{
+			XRES: []byte{0x11, 0x12, 0x13, 0x14},
+			AUTN: make([]byte, 16),
+		}},
+		DRXParameter:        []byte{0x09, 0x00},
+		NH:                  make([]byte, 32),
+		NCC:                 3,
+		SubscribedUEAMBR:    &ies.UEAMBR{UL: 100000, DL: 200000},
+		UENetworkCapability: []byte{0xe0, 0xe0},
+		MSNetworkCapability: []byte{},
+		MEI:                 "4901542032375101",
+		Extension:           []byte{0xde, 0xad},
+	}
+	want.KASME[0], want.Quadruplets[0].RAND[15], want.Quintuplets[0].IK[0] = 0xaa, 0xbb, 0xcc
+
+	i := ies.NewMMContextEPSSecurityContextQuadrupletsAndQuintuplets(want)
+	if i == nil {
+		t.Fatal("failed to create MMContextEPSSecurityContextQuadrupletsAndQuintuplets")
+	}
+	b, err := i.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := ies.Parse(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := decoded.EPSSecurityContext()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Error(diff)
+	}
+
+	if _, err := ies.ParseEPSSecurityContext(i.Payload[:60]); err == nil {
+		t.Error("truncated MM Context is decoded")
+	}
+}
@@ -20,6 +20,16 @@ func NewIMSI(imsi string) *IE {
 	return New(IMSI, 0x00, i)
 }
 
+// NewIMSIFrom creates a new IMSI IE from imsi, which returns InvalidIdentityError
+// instead of encoding it if imsi is malformed.
+func NewIMSIFrom(imsi utils.IMSI) (*IE, error) {
+	b, err := imsi.TBCD()
+	if err != nil {
+		return nil, err
+	}
+	return New(IMSI, 0x00, b), nil
+}
+
 // IMSI returns IMSI in string if the type of IE matches.
 func (i *IE) IMSI() (string, error) {
 	if i.Type != IMSI {
@@ -5,12 +5,10 @@
 package ies
 
 import (
+	"io"
 	"strconv"
 )
 
-// TODO: add getter/setters
-// TODO: consider using struct
-
 // NewIndication creates a new Indication IE.
 // Note that each parameters should be 0 if false and 1 if true. Otherwise,
 // the value won't be set as expected in the bitwise operations.
@@ -129,3 +127,445 @@ func NewIndicationFromOctets(octs ...uint8) *IE {
 	ie.SetLength()
 	return ie
 }
+
+// IndicationFlags is the set of flags in Indication IE.
+//
+// The flags are in the same order as they appear in the IE, from the most
+// significant bit of the first octet.
+type IndicationFlags struct {
+	// Octet 5
+	DAF   bool
+	DTF   bool
+	HI    bool
+	DFI   bool
+	OI    bool
+	ISRSI bool
+	ISRAI bool
+	SGWCI bool
+	// Octet 6
+	SQCI  bool
+	UIMSI bool
+	CFSI  bool
+	CRSI  bool
+	PS    bool
+	PT    bool
+	SI    bool
+	MSV   bool
+	// Octet 7
+	RetLoc bool
+	PBIC   bool
+	SRNI   bool
+	S6AF   bool
+	S4AF   bool
+	MBMDT  bool
+	ISRAU  bool
+	CCRSI  bool
+	// Octet 8
+	CPRAI bool
+	ARRL  bool
+	PPOFF bool
+	PPON  bool
+	PPSI  bool
+	CSFBI bool
+	CLII  bool
+	CPSR  bool
+	// Octet 9
+	NSI  bool
+	UASI bool
+	DTCI bool
+	BDWI bool
+	PSCI bool
+	PCRI bool
+	AOSI bool
+	AOPI bool
+	// Octet 10
+	ROAAI   bool
+	EPCOSI  bool
+	CPOPCI  bool
+	PMTMSI  bool
+	S11TF   bool
+	PNSI    bool
+	UNACCSI bool
+	WPMSI   bool
+	// Octet 11
+	EEVRSI  bool
+	LTEMUI  bool
+	LTEMPI  bool
+	ENBCRSI bool
+	TSPCMI  bool
+}
+
+// NewIndicationStruct creates a new Indication IE from IndicationFlags.
+func NewIndicationStruct(f *IndicationFlags) *IE {
+	b, err := f.Marshal()
+	if err != nil {
+		return nil
+	}
+	return New(Indication, 0x00, b)
+}
+
+// Indication returns Indication in *IndicationFlags if the type of IE matches.
+//
+// The flags in the octets that are not present in the IE are all false.
+func (i *IE) Indication() (*IndicationFlags, error) {
+	if i.Type != Indication {
+		return nil, &InvalidTypeError{Type: i.Type}
+	}
+	return ParseIndicationFlags(i.Payload)
+}
+
+// MustIndication returns Indication in *IndicationFlags, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustIndication() *IndicationFlags {
+	v, _ := i.Indication()
+	return v
+}
+
+// ParseIndicationFlags decodes IndicationFlags.
+func ParseIndicationFlags(b []byte) (*IndicationFlags, error) {
+	f := &IndicationFlags{}
+	if err := f.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// UnmarshalBinary decodes given bytes into IndicationFlags.
+func (f *IndicationFlags) UnmarshalBinary(b []byte) error {
+	if len(b) == 0 {
+		return io.ErrUnexpectedEOF
+	}
+
+	for n, octet := range f.octets() {
+		if n >= len(b) {
+			break
+		}
+		for bit, v := range octet {
+			if v != nil {
+				*v = b[n]>>uint(7-bit)&0x01 == 1
+			}
+		}
+	}
+	return nil
+}
+
+// Marshal serializes IndicationFlags.
+func (f *IndicationFlags) Marshal() ([]byte, error) {
+	b := make([]byte, f.MarshalLen())
+	if err := f.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MarshalTo serializes IndicationFlags.
+func (f *IndicationFlags) MarshalTo(b []byte) error {
+	if len(b) < f.MarshalLen() {
+		return io.ErrUnexpectedEOF
+	}
+
+	for n, octet := range f.octets() {
+		b[n] = 0
+		for bit, v := range octet {
+			if v != nil && *v {
+				b[n] |= 1 << uint(7-bit)
+			}
+		}
+	}
+	return nil
+}
+
+// MarshalLen returns the serial length of IndicationFlags in int, which is always
+// the same as the one created by NewIndication.
+func (f *IndicationFlags) MarshalLen() int {
+	return len(f.octets())
+}
+
+// octets returns the pointers to the flags in each octet, from the most significant
+// bit. The bits that have no flag defined are nil.
+func (f *IndicationFlags) octets() [][8]*bool {
+	return [][8]*bool{
+		{&f.DAF, &f.DTF, &f.HI, &f.DFI, &f.OI, &f.ISRSI, &f.ISRAI, &f.SGWCI},
+		{&f.SQCI, &f.UIMSI, &f.CFSI, &f.CRSI, &f.PS, &f.PT, &f.SI, &f.MSV},
+		{&f.RetLoc, &f.PBIC, &f.SRNI, &f.S6AF, &f.S4AF, &f.MBMDT, &f.ISRAU, &f.CCRSI},
+		{&f.CPRAI, &f.ARRL, &f.PPOFF, &f.PPON, &f.PPSI, &f.CSFBI, &f.CLII, &f.CPSR},
+		{&f.NSI, &f.UASI, &f.DTCI, &f.BDWI, &f.PSCI, &f.PCRI, &f.AOSI, &f.AOPI},
+		{&f.ROAAI, &f.EPCOSI, &f.CPOPCI, &f.PMTMSI, &f.S11TF, &f.PNSI, &f.UNACCSI, &f.WPMSI},
+		{&f.EEVRSI, &f.LTEMUI, &f.LTEMPI, &f.ENBCRSI, &f.TSPCMI},
+	}
+}
+
+// indicationFlag reports whether the bit in the octet of Indication IE is set. The
+// octet is counted from 0, and the bit is counted from 0 for the least significant
+// one. The flags in the octets that are not present are taken as not set.
+func (i *IE) indicationFlag(octet, bit int) bool {
+	if i.Type != Indication || len(i.Payload) <= octet {
+		return false
+	}
+	return i.Payload[octet]>>uint(bit)&0x01 == 1
+}
+
+// DAF reports whether the Dual Address Bearer Flag(DAF) is set in Indication IE.
+func (i *IE) DAF() bool {
+	return i.indicationFlag(0, 7)
+}
+
+// DTF reports whether the Direct Tunnel Flag(DTF) is set in Indication IE.
+func (i *IE) DTF() bool {
+	return i.indicationFlag(0, 6)
+}
+
+// HI reports whether the Handover Indication(HI) is set in Indication IE.
+func (i *IE) HI() bool {
+	return i.indicationFlag(0, 5)
+}
+
+// DFI reports whether the Direct Forwarding Indication(DFI) is set in Indication IE.
+func (i *IE) DFI() bool {
+	return i.indicationFlag(0, 4)
+}
+
+// OI reports whether the Operation Indication(OI) is set in Indication IE.
+func (i *IE) OI() bool {
+	return i.indicationFlag(0, 3)
+}
+
+// ISRSI reports whether the Idle mode Signalling Reduction Supported Indication(ISRSI) is set in Indication IE.
+func (i *IE) ISRSI() bool {
+	return i.indicationFlag(0, 2)
+}
+
+// ISRAI reports whether the Idle mode Signalling Reduction Activation Indication(ISRAI) is set in Indication IE.
+func (i *IE) ISRAI() bool {
+	return i.indicationFlag(0, 1)
+}
+
+// SGWCI reports whether the SGW Change Indication(SGWCI) is set in Indication IE.
+func (i *IE) SGWCI() bool {
+	return i.indicationFlag(0, 0)
+}
+
+// SQCI reports whether the Subscribed QoS Change Indication(SQCI) is set in Indication IE.
+func (i *IE) SQCI() bool {
+	return i.indicationFlag(1, 7)
+}
+
+// UIMSI reports whether the Unauthenticated IMSI(UIMSI) is set in Indication IE.
+func (i *IE) UIMSI() bool {
+	return i.indicationFlag(1, 6)
+}
+
+// CFSI reports whether the Change F-TEID support Indication(CFSI) is set in Indication IE.
+func (i *IE) CFSI() bool {
+	return i.indicationFlag(1, 5)
+}
+
+// CRSI reports whether the Change Reporting support Indication(CRSI) is set in Indication IE.
+func (i *IE) CRSI() bool {
+	return i.indicationFlag(1, 4)
+}
+
+// PS reports whether the Piggybacking Supported(PS) is set in Indication IE.
+func (i *IE) PS() bool {
+	return i.indicationFlag(1, 3)
+}
+
+// PT reports whether the S5/S8 Protocol Type(PT) is set in Indication IE.
+func (i *IE) PT() bool {
+	return i.indicationFlag(1, 2)
+}
+
+// SI reports whether the Scope Indication(SI) is set in Indication IE.
+func (i *IE) SI() bool {
+	return i.indicationFlag(1, 1)
+}
+
+// MSV reports whether the MS Validated(MSV) is set in Indication IE.
+func (i *IE) MSV() bool {
+	return i.indicationFlag(1, 0)
+}
+
+// RetLoc reports whether the Retrieve Location Indication Flag(RetLoc) is set in Indication IE.
+func (i *IE) RetLoc() bool {
+	return i.indicationFlag(2, 7)
+}
+
+// PBIC reports whether the Propagate BBAI Information Change(PBIC) is set in Indication IE.
+func (i *IE) PBIC() bool {
+	return i.indicationFlag(2, 6)
+}
+
+// SRNI reports whether the SGW Restoration Needed Indication(SRNI) is set in Indication IE.
+func (i *IE) SRNI() bool {
+	return i.indicationFlag(2, 5)
+}
+
+// S6AF reports whether the Static IPv6 Address Flag(S6AF) is set in Indication IE.
+func (i *IE) S6AF() bool {
+	return i.indicationFlag(2, 4)
+}
+
+// S4AF reports whether the Static IPv4 Address Flag(S4AF) is set in Indication IE.
+func (i *IE) S4AF() bool {
+	return i.indicationFlag(2, 3)
+}
+
+// MBMDT reports whether the Management Based MDT allowed flag(MBMDT) is set in Indication IE.
+func (i *IE) MBMDT() bool {
+	return i.indicationFlag(2, 2)
+}
+
+// ISRAU reports whether the ISR is activated for the UE(ISRAU) is set in Indication IE.
+func (i *IE) ISRAU() bool {
+	return i.indicationFlag(2, 1)
+}
+
+// CCRSI reports whether the CSG Change Reporting Support Indication(CCRSI) is set in Indication IE.
+func (i *IE) CCRSI() bool {
+	return i.indicationFlag(2, 0)
+}
+
+// CPRAI reports whether the Change of Presence Reporting Area information Indication(CPRAI) is set in Indication IE.
+func (i *IE) CPRAI() bool {
+	return i.indicationFlag(3, 7)
+}
+
+// ARRL reports whether the Abnormal Release of Radio Link(ARRL) is set in Indication IE.
+func (i *IE) ARRL() bool {
+	return i.indicationFlag(3, 6)
+}
+
+// PPOFF reports whether the PDN Pause Off Indication(PPOFF) is set in Indication IE.
+func (i *IE) PPOFF() bool {
+	return i.indicationFlag(3, 5)
+}
+
+// PPON reports whether the PDN Pause On Indication(PPON) is set in Indication IE.
+func (i *IE) PPON() bool {
+	return i.indicationFlag(3, 4)
+}
+
+// PPSI reports whether the PDN Pause Support Indication(PPSI) is set in Indication IE.
+func (i *IE) PPSI() bool {
+	return i.indicationFlag(3, 3)
+}
+
+// CSFBI reports whether the CSFB Indication(CSFBI) is set in Indication IE.
+func (i *IE) CSFBI() bool {
+	return i.indicationFlag(3, 2)
+}
+
+// CLII reports whether the Change of Location Information Indication(CLII) is set in Indication IE.
+func (i *IE) CLII() bool {
+	return i.indicationFlag(3, 1)
+}
+
+// CPSR reports whether the CS to PS SRVCC indication(CPSR) is set in Indication IE.
+func (i *IE) CPSR() bool {
+	return i.indicationFlag(3, 0)
+}
+
+// NSI reports whether the NBIFOM Support Indication(NSI) is set in Indication IE.
+func (i *IE) NSI() bool {
+	return i.indicationFlag(4, 7)
+}
+
+// UASI reports whether the UE Available for Signalling Indication(UASI) is set in Indication IE.
+func (i *IE) UASI() bool {
+	return i.indicationFlag(4, 6)
+}
+
+// DTCI reports whether the Delay Tolerant Connection Indication(DTCI) is set in Indication IE.
+func (i *IE) DTCI() bool {
+	return i.indicationFlag(4, 5)
+}
+
+// BDWI reports whether the Buffered DL Data Waiting Indication(BDWI) is set in Indication IE.
+func (i *IE) BDWI() bool {
+	return i.indicationFlag(4, 4)
+}
+
+// PSCI reports whether the Pending Subscription Change Indication(PSCI) is set in Indication IE.
+func (i *IE) PSCI() bool {
+	return i.indicationFlag(4, 3)
+}
+
+// PCRI reports whether the P-CSCF Restoration Indication(PCRI) is set in Indication IE.
+func (i *IE) PCRI() bool {
+	return i.indicationFlag(4, 2)
+}
+
+// AOSI reports whether the Associate OCI with SGW node's Identity(AOSI) is set in Indication IE.
+func (i *IE) AOSI() bool {
+	return i.indicationFlag(4, 1)
+}
+
+// AOPI reports whether the Associate OCI with PGW node's Identity(AOPI) is set in Indication IE.
+func (i *IE) AOPI() bool {
+	return i.indicationFlag(4, 0)
+}
+
+// ROAAI reports whether the Release Over Any Access Indication(ROAAI) is set in Indication IE.
+func (i *IE) ROAAI() bool {
+	return i.indicationFlag(5, 7)
+}
+
+// EPCOSI reports whether the Extended PCO Support Indication(EPCOSI) is set in Indication IE.
+func (i *IE) EPCOSI() bool {
+	return i.indicationFlag(5, 6)
+}
+
+// CPOPCI reports whether the Control Plane Only PDN Connection Indication(CPOPCI) is set in Indication IE.
+func (i *IE) CPOPCI() bool {
+	return i.indicationFlag(5, 5)
+}
+
+// PMTMSI reports whether the Pending MT Short Message Indication(PMTMSI) is set in Indication IE.
+func (i *IE) PMTMSI() bool {
+	return i.indicationFlag(5, 4)
+}
+
+// S11TF reports whether the S11-U Tunnel Flag(S11TF) is set in Indication IE.
+func (i *IE) S11TF() bool {
+	return i.indicationFlag(5, 3)
+}
+
+// PNSI reports whether the Pending Network Initiated PDN Connection Signalling Indication(PNSI) is set in Indication IE.
+func (i *IE) PNSI() bool {
+	return i.indicationFlag(5, 2)
+}
+
+// UNACCSI reports whether the UE Not Authorized Cause Code Support Indication(UNACCSI) is set in Indication IE.
+func (i *IE) UNACCSI() bool {
+	return i.indicationFlag(5, 1)
+}
+
+// WPMSI reports whether the WLCP PDN Connection Modification Support Indication(WPMSI) is set in Indication IE.
+func (i *IE) WPMSI() bool {
+	return i.indicationFlag(5, 0)
+}
+
+// EEVRSI reports whether the Extended EBI Value Range Support Indication(EEVRSI) is set in Indication IE.
+func (i *IE) EEVRSI() bool {
+	return i.indicationFlag(6, 7)
+}
+
+// LTEMUI reports whether the LTE-M UE Indication(LTEMUI) is set in Indication IE.
+func (i *IE) LTEMUI() bool {
+	return i.indicationFlag(6, 6)
+}
+
+// LTEMPI reports whether the LTE-M RAT type reporting to PGW Indication(LTEMPI) is set in Indication IE.
+func (i *IE) LTEMPI() bool {
+	return i.indicationFlag(6, 5)
+}
+
+// ENBCRSI reports whether the eNB Change Reporting Support Indication(ENBCRSI) is set in Indication IE.
+func (i *IE) ENBCRSI() bool {
+	return i.indicationFlag(6, 4)
+}
+
+// TSPCMI reports whether the Triggering SGSN Initiated PDP Context Creation/Modification Indication(TSPCMI) is set in Indication IE.
+func (i *IE) TSPCMI() bool {
+	return i.indicationFlag(6, 3)
+}
@@ -0,0 +1,82 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import (
+	"io"
+	"time"
+)
+
+// NewIntegerNumber creates a new IntegerNumber IE.
+//
+// The value is encoded in the minimum number of octets, which is 2 at least as it
+// is used for the Maximum Wait Time in milliseconds.
+func NewIntegerNumber(num uint32) *IE {
+	switch {
+	case num <= 0xffff:
+		return newUint16ValIE(IntegerNumber, uint16(num))
+	case num <= 0xffffff:
+		return New(IntegerNumber, 0x00, []byte{uint8(num >> 16), uint8(num >> 8), uint8(num)})
+	default:
+		return newUint32ValIE(IntegerNumber, num)
+	}
+}
+
+// IntegerNumber returns IntegerNumber in uint32 if the type of IE matches.
+//
+// The value of up to 4 octets is accepted.
+func (i *IE) IntegerNumber() (uint32, error) {
+	if i.Type != IntegerNumber {
+		return 0, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) == 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	if len(i.Payload) > 4 {
+		return 0, ErrInvalidLength
+	}
+
+	var num uint32
+	for _, b := range i.Payload {
+		num = num<<8 | uint32(b)
+	}
+	return num, nil
+}
+
+// MustIntegerNumber returns IntegerNumber in uint32, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustIntegerNumber() uint32 {
+	v, _ := i.IntegerNumber()
+	return v
+}
+
+// NewMaximumWaitTime creates a new IntegerNumber IE as the Maximum Wait Time, which
+// is d in milliseconds.
+//
+// It is used with the Origination Time Stamp, i.e., MillisecondTimeStamp, to tell
+// the time after which the response is no longer meaningful to the sender.
+func NewMaximumWaitTime(d time.Duration) *IE {
+	if d < 0 || d/time.Millisecond > 0xffffffff {
+		return nil
+	}
+	return NewIntegerNumber(uint32(d / time.Millisecond))
+}
+
+// MaximumWaitTime returns IntegerNumber as the Maximum Wait Time in time.Duration if
+// the type of IE matches.
+func (i *IE) MaximumWaitTime() (time.Duration, error) {
+	ms, err := i.IntegerNumber()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
+
+// MustMaximumWaitTime returns MaximumWaitTime in time.Duration, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustMaximumWaitTime() time.Duration {
+	v, _ := i.MaximumWaitTime()
+	return v
+}
@@ -7,11 +7,12 @@ package ies
 import (
 	"io"
 	"net"
+	"strings"
 )
 
 // NewIPAddress creates a new IPAddress IE from string.
 func NewIPAddress(addr string) *IE {
-	ip := net.ParseIP(addr)
+	ip := parseIP(addr)
 	v4 := ip.To4()
 
 	// IPv4
@@ -40,10 +41,22 @@ func (i *IE) IPAddress() (string, error) {
 			return "", err
 		}
 		switch pdnType {
-		case 0x01:
-			return net.IP(i.Payload[1:]).String(), nil
-		case 0x02:
-			return net.IP(i.Payload[2:]).String(), nil
+		case pdnTypeIPv4:
+			if len(i.Payload) < 5 {
+				return "", io.ErrUnexpectedEOF
+			}
+			return net.IP(i.Payload[1:5]).String(), nil
+		case pdnTypeIPv6:
+			if len(i.Payload) < 18 {
+				return "", io.ErrUnexpectedEOF
+			}
+			return net.IP(i.Payload[2:18]).String(), nil
+		case pdnTypeIPv4v6:
+			// the IPv4 address comes after the IPv6 prefix length and the address.
+			if len(i.Payload) < 22 {
+				return "", io.ErrUnexpectedEOF
+			}
+			return net.IP(i.Payload[18:22]).String(), nil
 		default:
 			return "", ErrMalformed
 		}
@@ -87,3 +100,12 @@ func (i *IE) MustIPAddress() string {
 	v, _ := i.IPAddress()
 	return v
 }
+
+// parseIP parses s as an IP address like net.ParseIP, ignoring the zone of IPv6
+// address if any, e.g., "fe80::1%eth0", as it is not carried by the IEs.
+func parseIP(s string) net.IP {
+	if i := strings.IndexByte(s, '%'); i >= 0 {
+		s = s[:i]
+	}
+	return net.ParseIP(s)
+}
@@ -0,0 +1,33 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import "io"
+
+// NewLoadControlInformation creates a new LoadControlInformation IE.
+//
+// The LoadControlInformation with APNAndRelativeCapacity IEs is the APN level one,
+// and the one without is the node level one.
+func NewLoadControlInformation(seq uint32, metric uint8, apnCapacities ...*IE) *IE {
+	ies := []*IE{NewSequenceNumber(seq), NewMetric(metric)}
+	for _, ie := range apnCapacities {
+		if ie != nil {
+			ies = append(ies, ie)
+		}
+	}
+	return newGroupedIE(LoadControlInformation, ies...)
+}
+
+// LoadControlInformation returns the []*IE inside LoadControlInformation IE.
+func (i *IE) LoadControlInformation() ([]*IE, error) {
+	if i.Type != LoadControlInformation {
+		return nil, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) == 0 {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	return ParseMultiIEs(i.Payload)
+}
@@ -0,0 +1,56 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import (
+	"io"
+	"time"
+)
+
+const (
+	mbmsSessionDurationMaxSeconds = 86400
+	mbmsSessionDurationMaxDays    = 18
+)
+
+// NewMBMSSessionDuration creates a new MBMSSessionDuration IE.
+//
+// The duration is encoded as the number of days and the remaining seconds, and
+// rounded down to the second. The duration longer than the maximum that can be
+// represented(18 days and 86400 seconds) is encoded as the maximum.
+func NewMBMSSessionDuration(duration time.Duration) *IE {
+	if duration < 0 {
+		duration = 0
+	}
+	days := uint32(duration / (24 * time.Hour))
+	secs := uint32((duration % (24 * time.Hour)) / time.Second)
+	if days > mbmsSessionDurationMaxDays {
+		days, secs = mbmsSessionDurationMaxDays, mbmsSessionDurationMaxSeconds
+	}
+
+	v := secs<<7 | days
+	return New(MBMSSessionDuration, 0x00, []byte{uint8(v >> 16), uint8(v >> 8), uint8(v)})
+}
+
+// MBMSSessionDuration returns MBMSSessionDuration in time.Duration if the type of IE matches.
+func (i *IE) MBMSSessionDuration() (time.Duration, error) {
+	if i.Type != MBMSSessionDuration {
+		return 0, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) < 3 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	v := uint32(i.Payload[0])<<16 | uint32(i.Payload[1])<<8 | uint32(i.Payload[2])
+	days := time.Duration(v & 0x7f)
+	secs := time.Duration(v >> 7)
+	return days*24*time.Hour + secs*time.Second, nil
+}
+
+// MustMBMSSessionDuration returns MBMSSessionDuration in time.Duration, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustMBMSSessionDuration() time.Duration {
+	v, _ := i.MBMSSessionDuration()
+	return v
+}
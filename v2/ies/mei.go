@@ -19,6 +19,16 @@ func NewMobileEquipmentIdentity(mei string) *IE {
 	return New(MobileEquipmentIdentity, 0x00, m)
 }
 
+// NewMobileEquipmentIdentityFrom creates a new MobileEquipmentIdentity IE from mei,
+// which returns InvalidIdentityError instead of encoding it if mei is malformed.
+func NewMobileEquipmentIdentityFrom(mei utils.IMEI) (*IE, error) {
+	b, err := mei.TBCD()
+	if err != nil {
+		return nil, err
+	}
+	return New(MobileEquipmentIdentity, 0x00, b), nil
+}
+
 // MobileEquipmentIdentity returns MobileEquipmentIdentity in string if the
 // type of IE matches.
 func (i *IE) MobileEquipmentIdentity() (string, error) {
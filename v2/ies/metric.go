@@ -0,0 +1,33 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import "io"
+
+// NewMetric creates a new Metric IE.
+//
+// The metric is the percentage in the range of 0 to 100.
+func NewMetric(metric uint8) *IE {
+	return newUint8ValIE(Metric, metric)
+}
+
+// Metric returns Metric in uint8 if the type of IE matches.
+func (i *IE) Metric() (uint8, error) {
+	if i.Type != Metric {
+		return 0, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) == 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	return i.Payload[0], nil
+}
+
+// MustMetric returns Metric in uint8, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustMetric() uint8 {
+	v, _ := i.Metric()
+	return v
+}
@@ -0,0 +1,55 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import (
+	"io"
+	"time"
+)
+
+// secondsFrom1900To1970 is the offset of the epoch of Millisecond Time Stamp,
+// 00:00:00 on 1 January 1900 UTC, from the Unix epoch.
+const secondsFrom1900To1970 = 2208988800
+
+// NewMillisecondTimeStamp creates a new MillisecondTimeStamp IE.
+//
+// The time is encoded as the milliseconds elapsed since 1 January 1900 in 48 bits.
+// This is also used as the Origination Time Stamp, which is the time the request is
+// sent.
+func NewMillisecondTimeStamp(ts time.Time) *IE {
+	ms := uint64(ts.Unix()+secondsFrom1900To1970)*1000 + uint64(ts.Nanosecond())/1000000
+
+	i := New(MillisecondTimeStamp, 0x00, make([]byte, 6))
+	for n := 5; n >= 0; n-- {
+		i.Payload[n] = uint8(ms)
+		ms >>= 8
+	}
+	return i
+}
+
+// MillisecondTimeStamp returns MillisecondTimeStamp in time.Time if the type of IE
+// matches.
+func (i *IE) MillisecondTimeStamp() (time.Time, error) {
+	if i.Type != MillisecondTimeStamp {
+		return time.Time{}, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) < 6 {
+		return time.Time{}, io.ErrUnexpectedEOF
+	}
+
+	var ms uint64
+	for _, b := range i.Payload[:6] {
+		ms = ms<<8 | uint64(b)
+	}
+
+	return time.Unix(int64(ms/1000)-secondsFrom1900To1970, int64(ms%1000)*1000000), nil
+}
+
+// MustMillisecondTimeStamp returns MillisecondTimeStamp in time.Time, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustMillisecondTimeStamp() time.Time {
+	v, _ := i.MillisecondTimeStamp()
+	return v
+}
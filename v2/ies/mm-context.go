@@ -0,0 +1,374 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import (
+	"encoding/binary"
+	"io"
+	"strings"
+
+	"github.com/wmnsk/go-gtp/utils"
+)
+
+// AuthQuadruplet is an Authentication Quadruplet in MM Context IE.
+type AuthQuadruplet struct {
+	RAND  [16]byte
+	XRES  []byte
+	AUTN  []byte
+	KASME [32]byte
+}
+
+// AuthQuintuplet is an Authentication Quintuplet in MM Context IE.
+type AuthQuintuplet struct {
+	RAND [16]byte
+	XRES []byte
+	CK   [16]byte
+	IK   [16]byte
+	AUTN []byte
+}
+
+// UEAMBR is the uplink and downlink UE-AMBR in kbps in MM Context IE.
+type UEAMBR struct {
+	UL, DL uint32
+}
+
+// EPSSecurityContext is the fields of MM Context (EPS Security Context and
+// Quadruplets) IE, which is sent between MMEs, e.g., in Context Response and
+// Forward Relocation Request.
+//
+// The fields that are nil are not present in the IE. NH must be 32 octets if present.
+// The fields that follow Access Restriction Data, e.g., the old EPS security context
+// indicated by OSCI and UE Radio Capability for Paging Information, are kept in
+// Extension as they are.
+type EPSSecurityContext struct {
+	SecurityMode          uint8
+	KSIASME               uint8
+	NASIntegrity          uint8
+	NASCipher             uint8
+	NASDownlinkCount      uint32
+	NASUplinkCount        uint32
+	KASME                 [32]byte
+	Quadruplets           []*AuthQuadruplet
+	Quintuplets           []*AuthQuintuplet
+	DRXParameter          []byte
+	NH                    []byte
+	NCC                   uint8
+	SubscribedUEAMBR      *UEAMBR
+	UsedUEAMBR            *UEAMBR
+	UENetworkCapability   []byte
+	MSNetworkCapability   []byte
+	MEI                   string
+	AccessRestrictionData uint8
+	OSCI                  bool
+	Extension             []byte
+}
+
+// NewMMContextEPSSecurityContextQuadrupletsAndQuintuplets creates a new
+// MMContextEPSSecurityContextQuadrupletsAndQuintuplets IE from EPSSecurityContext.
+func NewMMContextEPSSecurityContextQuadrupletsAndQuintuplets(ctx *EPSSecurityContext) *IE {
+	b, err := ctx.Marshal()
+	if err != nil {
+		return nil
+	}
+	return New(MMContextEPSSecurityContextQuadrupletsAndQuintuplets, 0x00, b)
+}
+
+// EPSSecurityContext returns MMContextEPSSecurityContextQuadrupletsAndQuintuplets in
+// EPSSecurityContext if the type of IE matches.
+func (i *IE) EPSSecurityContext() (*EPSSecurityContext, error) {
+	if i.Type != MMContextEPSSecurityContextQuadrupletsAndQuintuplets {
+		return nil, &InvalidTypeError{Type: i.Type}
+	}
+	return ParseEPSSecurityContext(i.Payload)
+}
+
+// MustEPSSecurityContext returns EPSSecurityContext in *EPSSecurityContext, ignoring
+// errors. This should only be used if it is assured to have the value.
+func (i *IE) MustEPSSecurityContext() *EPSSecurityContext {
+	v, _ := i.EPSSecurityContext()
+	return v
+}
+
+// ParseEPSSecurityContext decodes EPSSecurityContext.
+func ParseEPSSecurityContext(b []byte) (*EPSSecurityContext, error) {
+	c := &EPSSecurityContext{}
+	if err := c.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// UnmarshalBinary decodes given bytes into EPSSecurityContext.
+func (c *EPSSecurityContext) UnmarshalBinary(b []byte) error {
+	if len(b) < 41 {
+		return io.ErrUnexpectedEOF
+	}
+	c.SecurityMode = b[0] >> 5
+	nhi, drxi := b[0]&0x10 != 0, b[0]&0x08 != 0
+	c.KSIASME = b[0] & 0x07
+	nQuint, nQuad := int(b[1]>>5), int(b[1]>>2&0x07)
+	uambri := b[1]&0x02 != 0
+	c.OSCI = b[1]&0x01 != 0
+	sambri := b[2]&0x80 != 0
+	c.NASIntegrity = b[2] >> 4 & 0x07
+	c.NASCipher = b[2] & 0x0f
+	c.NASDownlinkCount = utils.Uint24To32(b[3:6])
+	c.NASUplinkCount = utils.Uint24To32(b[6:9])
+	copy(c.KASME[:], b[9:41])
+
+	// fixed and lv read the fields from b[offset:].
+	offset := 41
+	fixed := func(n int) ([]byte, error) {
+		if len(b) < offset+n {
+			return nil, io.ErrUnexpectedEOF
+		}
+		v := b[offset : offset+n]
+		offset += n
+		return v, nil
+	}
+	lv := func() ([]byte, error) {
+		l, err := fixed(1)
+		if err != nil {
+			return nil, err
+		}
+		return fixed(int(l[0]))
+	}
+
+	for n := 0; n < nQuad; n++ {
+		q := &AuthQuadruplet{}
+		v, err := fixed(16)
+		if err != nil {
+			return err
+		}
+		copy(q.RAND[:], v)
+		if q.XRES, err = lv(); err != nil {
+			return err
+		}
+		if q.AUTN, err = lv(); err != nil {
+			return err
+		}
+		if v, err = fixed(32); err != nil {
+			return err
+		}
+		copy(q.KASME[:], v)
+		c.Quadruplets = append(c.Quadruplets, q)
+	}
+	for n := 0; n < nQuint; n++ {
+		q := &AuthQuintuplet{}
+		v, err := fixed(16)
+		if err != nil {
+			return err
+		}
+		copy(q.RAND[:], v)
+		if q.XRES, err = lv(); err != nil {
+			return err
+		}
+		if v, err = fixed(32); err != nil {
+			return err
+		}
+		copy(q.CK[:], v[:16])
+		copy(q.IK[:], v[16:])
+		if q.AUTN, err = lv(); err != nil {
+			return err
+		}
+		c.Quintuplets = append(c.Quintuplets, q)
+	}
+
+	var err error
+	if drxi {
+		if c.DRXParameter, err = fixed(2); err != nil {
+			return err
+		}
+	}
+	if nhi {
+		if c.NH, err = fixed(32); err != nil {
+			return err
+		}
+		v, err := fixed(1)
+		if err != nil {
+			return err
+		}
+		c.NCC = v[0] & 0x07
+	}
+	for _, ambr := range []struct {
+		present bool
+		v       **UEAMBR
+	}{{sambri, &c.SubscribedUEAMBR}, {uambri, &c.UsedUEAMBR}} {
+		if !ambr.present {
+			continue
+		}
+		v, err := fixed(8)
+		if err != nil {
+			return err
+		}
+		*ambr.v = &UEAMBR{UL: binary.BigEndian.Uint32(v[0:4]), DL: binary.BigEndian.Uint32(v[4:8])}
+	}
+	if c.UENetworkCapability, err = lv(); err != nil {
+		return err
+	}
+	if c.MSNetworkCapability, err = lv(); err != nil {
+		return err
+	}
+	mei, err := lv()
+	if err != nil {
+		return err
+	}
+	c.MEI = strings.TrimSuffix(utils.SwappedBytesToStr(mei, false), "f")
+
+	// Access Restriction Data and the fields that follow may be absent in the older
+	// implementations.
+	if len(b) > offset {
+		c.AccessRestrictionData = b[offset]
+		offset++
+	}
+	if len(b) > offset {
+		c.Extension = b[offset:]
+	}
+	return nil
+}
+
+// Marshal serializes EPSSecurityContext.
+func (c *EPSSecurityContext) Marshal() ([]byte, error) {
+	b := make([]byte, c.MarshalLen())
+	if err := c.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MarshalTo serializes EPSSecurityContext.
+func (c *EPSSecurityContext) MarshalTo(b []byte) error {
+	if len(b) < c.MarshalLen() {
+		return io.ErrUnexpectedEOF
+	}
+	if len(c.Quadruplets) > 7 || len(c.Quintuplets) > 7 {
+		return ErrMalformed
+	}
+	if c.NH != nil && len(c.NH) != 32 {
+		return ErrMalformed
+	}
+	if c.DRXParameter != nil && len(c.DRXParameter) != 2 {
+		return ErrMalformed
+	}
+	mei, err := c.encodedMEI()
+	if err != nil {
+		return err
+	}
+
+	b[0] = (c.SecurityMode&0x07)<<5 | c.KSIASME&0x07
+	if c.NH != nil {
+		b[0] |= 0x10
+	}
+	if c.DRXParameter != nil {
+		b[0] |= 0x08
+	}
+	b[1] = uint8(len(c.Quintuplets))<<5 | uint8(len(c.Quadruplets))<<2
+	if c.UsedUEAMBR != nil {
+		b[1] |= 0x02
+	}
+	if c.OSCI {
+		b[1] |= 0x01
+	}
+	b[2] = (c.NASIntegrity&0x07)<<4 | c.NASCipher&0x0f
+	if c.SubscribedUEAMBR != nil {
+		b[2] |= 0x80
+	}
+	copy(b[3:6], utils.Uint32To24(c.NASDownlinkCount))
+	copy(b[6:9], utils.Uint32To24(c.NASUplinkCount))
+	copy(b[9:41], c.KASME[:])
+
+	offset := 41
+	put := func(v []byte) {
+		copy(b[offset:], v)
+		offset += len(v)
+	}
+	lv := func(v []byte) error {
+		if len(v) > 0xff {
+			return ErrMalformed
+		}
+		b[offset] = uint8(len(v))
+		offset++
+		put(v)
+		return nil
+	}
+
+	for _, q := range c.Quadruplets {
+		put(q.RAND[:])
+		if err := lv(q.XRES); err != nil {
+			return err
+		}
+		if err := lv(q.AUTN); err != nil {
+			return err
+		}
+		put(q.KASME[:])
+	}
+	for _, q := range c.Quintuplets {
+		put(q.RAND[:])
+		if err := lv(q.XRES); err != nil {
+			return err
+		}
+		put(q.CK[:])
+		put(q.IK[:])
+		if err := lv(q.AUTN); err != nil {
+			return err
+		}
+	}
+	if c.DRXParameter != nil {
+		put(c.DRXParameter)
+	}
+	if c.NH != nil {
+		put(c.NH)
+		put([]byte{c.NCC & 0x07})
+	}
+	for _, ambr := range []*UEAMBR{c.SubscribedUEAMBR, c.UsedUEAMBR} {
+		if ambr == nil {
+			continue
+		}
+		binary.BigEndian.PutUint32(b[offset:offset+4], ambr.UL)
+		binary.BigEndian.PutUint32(b[offset+4:offset+8], ambr.DL)
+		offset += 8
+	}
+	for _, v := range [][]byte{c.UENetworkCapability, c.MSNetworkCapability, mei} {
+		if err := lv(v); err != nil {
+			return err
+		}
+	}
+	put([]byte{c.AccessRestrictionData})
+	put(c.Extension)
+	return nil
+}
+
+// MarshalLen returns the serial length of EPSSecurityContext in int.
+func (c *EPSSecurityContext) MarshalLen() int {
+	l := 41
+	for _, q := range c.Quadruplets {
+		l += 16 + 1 + len(q.XRES) + 1 + len(q.AUTN) + 32
+	}
+	for _, q := range c.Quintuplets {
+		l += 16 + 1 + len(q.XRES) + 32 + 1 + len(q.AUTN)
+	}
+	if c.DRXParameter != nil {
+		l += len(c.DRXParameter)
+	}
+	if c.NH != nil {
+		l += len(c.NH) + 1
+	}
+	if c.SubscribedUEAMBR != nil {
+		l += 8
+	}
+	if c.UsedUEAMBR != nil {
+		l += 8
+	}
+	mei, _ := c.encodedMEI()
+	l += 1 + len(c.UENetworkCapability) + 1 + len(c.MSNetworkCapability) + 1 + len(mei)
+	return l + 1 + len(c.Extension)
+}
+
+func (c *EPSSecurityContext) encodedMEI() ([]byte, error) {
+	if c.MEI == "" {
+		return nil, nil
+	}
+	return utils.StrToSwappedBytes(c.MEI, "f")
+}
@@ -19,6 +19,16 @@ func NewMSISDN(mei string) *IE {
 	return New(MSISDN, 0x00, m)
 }
 
+// NewMSISDNFrom creates a new MSISDN IE from msisdn, which returns InvalidIdentityError
+// instead of encoding it if msisdn is malformed.
+func NewMSISDNFrom(msisdn utils.MSISDN) (*IE, error) {
+	b, err := msisdn.TBCD()
+	if err != nil {
+		return nil, err
+	}
+	return New(MSISDN, 0x00, b), nil
+}
+
 // MSISDN returns MSISDN in string if the type of IE matches.
 func (i *IE) MSISDN() (string, error) {
 	if i.Type != MSISDN {
@@ -0,0 +1,32 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import "io"
+
+// NewNodeFeatures creates a new NodeFeatures IE.
+//
+// The features are the bits of Supported-Features, e.g., v2.NodeFeaturePRN.
+func NewNodeFeatures(features uint8) *IE {
+	return newUint8ValIE(NodeFeatures, features)
+}
+
+// NodeFeatures returns Supported-Features in uint8 if the type of IE matches.
+func (i *IE) NodeFeatures() (uint8, error) {
+	if i.Type != NodeFeatures {
+		return 0, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) == 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	return i.Payload[0], nil
+}
+
+// MustNodeFeatures returns NodeFeatures in uint8, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustNodeFeatures() uint8 {
+	v, _ := i.NodeFeatures()
+	return v
+}
@@ -0,0 +1,37 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import (
+	"io"
+	"time"
+)
+
+// NewOverloadControlInformation creates a new OverloadControlInformation IE.
+//
+// The metric is the Overload Reduction Metric, and validity is the Period of
+// Validity. The OverloadControlInformation with AccessPointName IEs is the APN level
+// one, and the one without is the node level one.
+func NewOverloadControlInformation(seq uint32, metric uint8, validity time.Duration, apns ...*IE) *IE {
+	ies := []*IE{NewSequenceNumber(seq), NewMetric(metric), NewEPCTimer(validity)}
+	for _, ie := range apns {
+		if ie != nil {
+			ies = append(ies, ie)
+		}
+	}
+	return newGroupedIE(OverloadControlInformation, ies...)
+}
+
+// OverloadControlInformation returns the []*IE inside OverloadControlInformation IE.
+func (i *IE) OverloadControlInformation() ([]*IE, error) {
+	if i.Type != OverloadControlInformation {
+		return nil, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) == 0 {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	return ParseMultiIEs(i.Payload)
+}
@@ -4,7 +4,10 @@
 
 package ies
 
-import "net"
+import (
+	"io"
+	"net"
+)
 
 // PDN Type definitions.
 const (
@@ -15,12 +18,22 @@ const (
 	pdnTypeNonIP
 )
 
+// defaultIPv6PrefixLength is the length of the IPv6 prefix used when it is not given
+// with the address.
+//
+// TS 23.401 5.3.1.2.2 Allocation of IPv6 prefix via IPv6 Stateless Address
+// autoconfiguration; the PDN GW allocates a globally unique /64 IPv6 prefix.
+const defaultIPv6PrefixLength = 64
+
 // NewPDNAddressAllocation creates a new PDNAddressAllocation IE.
 //
 // The PDN Type field is automatically judged by the format of given addr,
 // If it cannot be converted as neither IPv4 nor IPv6, PDN Type will be Non-IP.
+//
+// The IPv6 address can be given with the prefix length in CIDR notation, e.g.,
+// "2001:db8::/64". Otherwise, the prefix length is 64.
 func NewPDNAddressAllocation(addr string) *IE {
-	ip := net.ParseIP(addr)
+	ip, prefixLen := parseIPv6Prefix(addr)
 	v4 := ip.To4()
 
 	// IPv4
@@ -32,11 +45,10 @@ func NewPDNAddressAllocation(addr string) *IE {
 	}
 
 	// IPv6
-	// XXX - prefix value should be handled properly.
 	if ip != nil {
 		i := New(PDNAddressAllocation, 0x00, make([]byte, 18))
 		i.Payload[0] = pdnTypeIPv6
-		i.Payload[1] = 0x00
+		i.Payload[1] = prefixLen
 		copy(i.Payload[2:], ip)
 		return i
 	}
@@ -46,25 +58,65 @@ func NewPDNAddressAllocation(addr string) *IE {
 }
 
 // NewPDNAddressAllocationDual creates a new PDNAddressAllocation IE with
-// IPv4 address and IPv6 address given.
+// IPv4 address and IPv6 address given. The IPv6 address can be given with the prefix
+// length in the same way as NewPDNAddressAllocation.
 //
 // If they cannot be converted as IPv4/IPv6, PDN Type will be Non-IP.
 func NewPDNAddressAllocationDual(v4addr, v6addr string) *IE {
-	v4 := net.ParseIP(v4addr).To4()
+	v4 := parseIP(v4addr).To4()
 	if v4 == nil {
 		return New(PDNAddressAllocation, 0x00, []byte{pdnTypeNonIP})
 	}
 
-	v6 := net.ParseIP(v6addr).To16()
+	ip, prefixLen := parseIPv6Prefix(v6addr)
+	v6 := ip.To16()
 	if v6 == nil {
 		return New(PDNAddressAllocation, 0x00, []byte{pdnTypeNonIP})
 	}
 
-	i := New(PDNAddressAllocation, 0x00, make([]byte, 23))
+	i := New(PDNAddressAllocation, 0x00, make([]byte, 22))
 	i.Payload[0] = pdnTypeIPv4v6
-	copy(i.Payload[1:5], v4)
-	i.Payload[5] = 0x00
-	copy(i.Payload[6:], v6)
+	i.Payload[1] = prefixLen
+	copy(i.Payload[2:18], v6)
+	copy(i.Payload[18:22], v4)
 
 	return i
 }
+
+// parseIPv6Prefix parses s as an IP address, optionally with the prefix length in
+// CIDR notation. The prefix length is defaultIPv6PrefixLength if not given.
+func parseIPv6Prefix(s string) (net.IP, uint8) {
+	if ip, ipnet, err := net.ParseCIDR(s); err == nil {
+		ones, _ := ipnet.Mask.Size()
+		return ip, uint8(ones)
+	}
+	return parseIP(s), defaultIPv6PrefixLength
+}
+
+// IPv6PrefixLength returns the length of the IPv6 prefix in uint8 if the type of IE
+// matches and it has IPv6 address.
+func (i *IE) IPv6PrefixLength() (uint8, error) {
+	if i.Type != PDNAddressAllocation {
+		return 0, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) == 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	switch i.Payload[0] {
+	case pdnTypeIPv6, pdnTypeIPv4v6:
+		if len(i.Payload) < 2 {
+			return 0, io.ErrUnexpectedEOF
+		}
+		return i.Payload[1], nil
+	default:
+		return 0, ErrFieldNotPresent
+	}
+}
+
+// MustIPv6PrefixLength returns IPv6PrefixLength in uint8, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustIPv6PrefixLength() uint8 {
+	v, _ := i.IPv6PrefixLength()
+	return v
+}
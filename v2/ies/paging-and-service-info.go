@@ -0,0 +1,44 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import "io"
+
+// NewPagingAndServiceInformation creates a new PagingAndServiceInformation IE.
+//
+// The Paging Policy Indication value is included only when ppiFlag is set to 1.
+func NewPagingAndServiceInformation(ebi, ppiFlag, ppi uint8) *IE {
+	if ppiFlag&0x01 != 1 {
+		return New(PagingAndServiceInformation, 0x00, []byte{ebi & 0x0f, 0x00})
+	}
+	return New(PagingAndServiceInformation, 0x00, []byte{ebi & 0x0f, 0x01, ppi & 0x3f})
+}
+
+// PagingPolicyIndication returns the Paging Policy Indication value in uint8 if the
+// type of IE matches and the PPI flag is set.
+func (i *IE) PagingPolicyIndication() (uint8, error) {
+	if i.Type != PagingAndServiceInformation {
+		return 0, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) < 2 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	if i.Payload[1]&0x01 != 1 {
+		return 0, ErrFieldNotPresent
+	}
+	if len(i.Payload) < 3 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	return i.Payload[2] & 0x3f, nil
+}
+
+// MustPagingPolicyIndication returns the Paging Policy Indication value in uint8,
+// ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustPagingPolicyIndication() uint8 {
+	v, _ := i.PagingPolicyIndication()
+	return v
+}
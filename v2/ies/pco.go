@@ -58,13 +58,17 @@ func ParseConfigurationProtocolOption(b []byte) (*ConfigurationProtocolOption, e
 
 // UnmarshalBinary decodes given bytes into ConfigurationProtocolOption.
 func (c *ConfigurationProtocolOption) UnmarshalBinary(b []byte) error {
-	if len(b) < 4 {
+	if len(b) < 3 {
 		return ErrTooShortToParse
 	}
 	c.ProtocolID = binary.BigEndian.Uint16(b[0:2])
 	c.Length = b[2]
 	if c.Length != 0 {
-		copy(c.Contents, b[3:])
+		if len(b) < 3+int(c.Length) {
+			return ErrInvalidLength
+		}
+		c.Contents = make([]byte, c.Length)
+		copy(c.Contents, b[3:3+int(c.Length)])
 	}
 
 	return nil
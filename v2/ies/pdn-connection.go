@@ -0,0 +1,30 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import "io"
+
+// NewPDNConnection creates a new PDNConnection IE.
+func NewPDNConnection(ies ...*IE) *IE {
+	var omitted []*IE
+	for _, ie := range ies {
+		if ie != nil {
+			omitted = append(omitted, ie)
+		}
+	}
+	return newGroupedIE(PDNConnection, omitted...)
+}
+
+// PDNConnection returns the []*IE inside PDNConnection IE.
+func (i *IE) PDNConnection() ([]*IE, error) {
+	if i.Type != PDNConnection {
+		return nil, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) == 0 {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	return ParseMultiIEs(i.Payload)
+}
@@ -0,0 +1,129 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import (
+	"io"
+
+	"github.com/wmnsk/go-gtp/utils"
+)
+
+// plmnLen is the serial length of PLMN, which is the same regardless of the number of
+// digits of MNC.
+const plmnLen = 3
+
+// PLMN is the MCC and MNC in string, encoded in the IEs such as Serving Network, ULI,
+// GUTI and TMGI.
+//
+// The MCC is 3 digits and the MNC is 2 or 3 digits, which are distinguished on the
+// wire by the filler in the place of the 3rd digit of MNC. The leading zeros are
+// significant, i.e., "01" and "001" are the different MNCs.
+type PLMN struct {
+	MCC string
+	MNC string
+}
+
+// NewPLMN creates a new PLMN, which returns utils.InvalidIdentityError if mcc or mnc
+// is malformed.
+func NewPLMN(mcc, mnc string) (*PLMN, error) {
+	p := &PLMN{MCC: mcc, MNC: mnc}
+	if _, err := utils.EncodePLMN(mcc, mnc); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ParsePLMN decodes PLMN.
+func ParsePLMN(b []byte) (*PLMN, error) {
+	p := &PLMN{}
+	if err := p.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// UnmarshalBinary decodes given bytes into PLMN.
+func (p *PLMN) UnmarshalBinary(b []byte) error {
+	mcc, mnc, err := utils.DecodePLMN(b)
+	if err != nil {
+		return err
+	}
+	p.MCC, p.MNC = mcc, mnc
+	return nil
+}
+
+// Marshal serializes PLMN.
+func (p *PLMN) Marshal() ([]byte, error) {
+	return utils.EncodePLMN(p.MCC, p.MNC)
+}
+
+// MarshalTo serializes PLMN.
+func (p *PLMN) MarshalTo(b []byte) error {
+	if len(b) < plmnLen {
+		return io.ErrUnexpectedEOF
+	}
+	encoded, err := p.Marshal()
+	if err != nil {
+		return err
+	}
+	copy(b[:plmnLen], encoded)
+	return nil
+}
+
+// MarshalLen returns the serial length of PLMN in int.
+func (p *PLMN) MarshalLen() int {
+	return plmnLen
+}
+
+// HasThreeDigitMNC reports whether the MNC is 3 digits.
+func (p *PLMN) HasThreeDigitMNC() bool {
+	return len(p.MNC) == 3
+}
+
+// String returns the MCC and MNC concatenated, e.g., "00101".
+func (p *PLMN) String() string {
+	return p.MCC + p.MNC
+}
+
+// PLMN returns the MCC and MNC in *PLMN if the type of IE has them.
+//
+// For User Location Information, it returns the PLMN of the first field present,
+// which is the same in all the fields in most cases.
+func (i *IE) PLMN() (*PLMN, error) {
+	switch i.Type {
+	case ServingNetwork, PLMNID, GlobalCNID, TraceReference, TraceInformation,
+		ExtendedTraceInformation, GUTI, UserCSGInformation:
+		return ParsePLMN(i.Payload)
+	case TMGI:
+		if len(i.Payload) < 6 {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return ParsePLMN(i.Payload[3:6])
+	case UserLocationInformation:
+		if len(i.Payload) < 1+plmnLen {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if i.Payload[0] == 0 {
+			return nil, ErrMalformed
+		}
+		return ParsePLMN(i.Payload[1 : 1+plmnLen])
+	default:
+		return nil, &InvalidTypeError{Type: i.Type}
+	}
+}
+
+// MustPLMN returns PLMN in *PLMN, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustPLMN() *PLMN {
+	v, _ := i.PLMN()
+	return v
+}
+
+func encodePLMN(b []byte, p *PLMN) error {
+	if p == nil {
+		return ErrMalformed
+	}
+	return p.MarshalTo(b)
+}
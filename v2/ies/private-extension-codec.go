@@ -0,0 +1,119 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import (
+	"fmt"
+	"sync"
+)
+
+// PrivateExtensionCodec encodes and decodes the Proprietary Value in PrivateExtension
+// IE of a vendor, which is identified by the Enterprise ID.
+//
+// Register it with RegisterPrivateExtensionCodec to use NewPrivateExtensionFrom and
+// DecodePrivateExtension with the Enterprise ID of the vendor.
+type PrivateExtensionCodec interface {
+	// EncodePrivateExtension returns the Proprietary Value generated from v.
+	EncodePrivateExtension(v interface{}) ([]byte, error)
+	// DecodePrivateExtension returns the value decoded from the Proprietary Value.
+	DecodePrivateExtension(b []byte) (interface{}, error)
+}
+
+// PrivateExtensionCodecFuncs is an adapter to use the pair of functions as
+// PrivateExtensionCodec.
+type PrivateExtensionCodecFuncs struct {
+	Encode func(v interface{}) ([]byte, error)
+	Decode func(b []byte) (interface{}, error)
+}
+
+// EncodePrivateExtension calls f.Encode(v).
+func (f PrivateExtensionCodecFuncs) EncodePrivateExtension(v interface{}) ([]byte, error) {
+	return f.Encode(v)
+}
+
+// DecodePrivateExtension calls f.Decode(b).
+func (f PrivateExtensionCodecFuncs) DecodePrivateExtension(b []byte) (interface{}, error) {
+	return f.Decode(b)
+}
+
+// UnknownEnterpriseIDError indicates that no PrivateExtensionCodec is registered for
+// the Enterprise ID.
+type UnknownEnterpriseIDError struct {
+	EnterpriseID uint16
+}
+
+// Error returns message with the Enterprise ID.
+func (e *UnknownEnterpriseIDError) Error() string {
+	return fmt.Sprintf("no codec registered for Enterprise ID: %d", e.EnterpriseID)
+}
+
+var privateExtensionCodecs = struct {
+	mu     sync.RWMutex
+	codecs map[uint16]PrivateExtensionCodec
+}{
+	codecs: map[uint16]PrivateExtensionCodec{},
+}
+
+// RegisterPrivateExtensionCodec registers the PrivateExtensionCodec for the Enterprise
+// ID given, replacing the existing one if any. Giving nil as codec unregisters it.
+//
+// The codecs are shared in the process, and it is safe to call this concurrently
+// with encoding and decoding.
+func RegisterPrivateExtensionCodec(id uint16, codec PrivateExtensionCodec) {
+	privateExtensionCodecs.mu.Lock()
+	defer privateExtensionCodecs.mu.Unlock()
+
+	if codec == nil {
+		delete(privateExtensionCodecs.codecs, id)
+		return
+	}
+	privateExtensionCodecs.codecs[id] = codec
+}
+
+func privateExtensionCodec(id uint16) (PrivateExtensionCodec, error) {
+	privateExtensionCodecs.mu.RLock()
+	defer privateExtensionCodecs.mu.RUnlock()
+
+	codec, ok := privateExtensionCodecs.codecs[id]
+	if !ok {
+		return nil, &UnknownEnterpriseIDError{EnterpriseID: id}
+	}
+	return codec, nil
+}
+
+// NewPrivateExtensionFrom creates a new PrivateExtension IE with the Proprietary Value
+// encoded from v by the PrivateExtensionCodec registered for the Enterprise ID.
+//
+// It returns UnknownEnterpriseIDError if no codec is registered for id.
+func NewPrivateExtensionFrom(id uint16, v interface{}) (*IE, error) {
+	codec, err := privateExtensionCodec(id)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := codec.EncodePrivateExtension(v)
+	if err != nil {
+		return nil, err
+	}
+	return NewPrivateExtension(id, b), nil
+}
+
+// DecodePrivateExtension returns the Proprietary Value decoded by the
+// PrivateExtensionCodec registered for the Enterprise ID in the IE.
+//
+// It returns UnknownEnterpriseIDError if no codec is registered for the Enterprise
+// ID. The raw value is still available with PrivateExtension in that case.
+func (i *IE) DecodePrivateExtension() (interface{}, error) {
+	id, err := i.EnterpriseID()
+	if err != nil {
+		return nil, err
+	}
+
+	codec, err := privateExtensionCodec(id)
+	if err != nil {
+		return nil, err
+	}
+	return codec.DecodePrivateExtension(i.Payload[2:])
+}
@@ -0,0 +1,38 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import "encoding/binary"
+
+// ParseRaw decodes the given bytes as an IE without decoding its Payload, e.g., the
+// child IEs of the grouped IE. If the Length exceeds the given bytes, the Payload is
+// truncated at the end of them.
+//
+// The IE returned is serialized as it is received, with the Length and Payload kept
+// as they are even if they are inconsistent. This is to retain the IEs that cannot
+// be decoded with Parse and pass them to the peers untouched.
+func ParseRaw(b []byte) (*IE, error) {
+	if len(b) < 4 {
+		return nil, ErrTooShortToParse
+	}
+
+	i := &IE{
+		Type:     b[0],
+		Length:   binary.BigEndian.Uint16(b[1:3]),
+		instance: b[3],
+		raw:      true,
+	}
+	n := 4 + int(i.Length)
+	if n > len(b) {
+		n = len(b)
+	}
+	i.Payload = b[4:n]
+	return i, nil
+}
+
+// IsRaw reports whether the IE is the one decoded with ParseRaw.
+func (i *IE) IsRaw() bool {
+	return i.raw
+}
@@ -0,0 +1,35 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+// IsS101Type reports whether typ is in the range of IE types reserved for the S101
+// interface between MME and HRPD access network, which are defined in TS 29.276.
+//
+// The IEs of these types are not decoded by this package, and kept as they are in
+// AdditionalIEs of the messages, so that the messages from the nodes interworking
+// with eHRPD can be handled without errors.
+func IsS101Type(typ uint8) bool {
+	return typ >= 4 && typ <= 34
+}
+
+// IsS121Type reports whether typ is in the range of IE types reserved for the S121
+// interface used in the interworking with HRPD access network. The IEs of these types
+// are handled in the same way as the ones with IsS101Type.
+func IsS121Type(typ uint8) bool {
+	return typ >= 35 && typ <= 50
+}
+
+// NewS101IPAddress creates a new IPAddress IE for the HRPD access node S101 IP
+// address, which is the one with instance 0 in Forward Relocation Request and Context
+// Response.
+func NewS101IPAddress(addr string) *IE {
+	return NewIPAddress(addr)
+}
+
+// NewS102IPAddress creates a new IPAddress IE for the 1xIWS S102 IP address, which is
+// the one with instance 1 in Forward Relocation Request and Context Response.
+func NewS102IPAddress(addr string) *IE {
+	return NewIPAddress(addr).WithInstance(1)
+}
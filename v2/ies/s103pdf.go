@@ -7,12 +7,11 @@ package ies
 import (
 	"encoding/binary"
 	"io"
-	"net"
 )
 
 // NewS103PDNDataForwardingInfo creates a new S103PDNDataForwardingInfo IE.
 func NewS103PDNDataForwardingInfo(hsgwAddr string, greKey uint32, ebis ...uint8) *IE {
-	addr := net.ParseIP(hsgwAddr)
+	addr := parseIP(hsgwAddr)
 	if addr == nil {
 		return nil
 	}
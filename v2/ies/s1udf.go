@@ -6,12 +6,11 @@ package ies
 
 import (
 	"encoding/binary"
-	"net"
 )
 
 // NewS1UDataForwarding creates a new S1UDataForwarding IE.
 func NewS1UDataForwarding(sgwAddr string, sgwTEID uint32) *IE {
-	addr := net.ParseIP(sgwAddr)
+	addr := parseIP(sgwAddr)
 	if addr == nil {
 		return nil
 	}
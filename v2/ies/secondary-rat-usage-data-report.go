@@ -0,0 +1,155 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import (
+	"encoding/binary"
+	"io"
+	"time"
+)
+
+// NewSecondaryRATUsageDataReport creates a new SecondaryRATUsageDataReport IE.
+//
+// irsgw and irpgw are the flags to indicate that the report is to be used by the
+// SGW and PGW respectively. start and end are the time the usage is collected,
+// and dl and ul are the data volume in octets over the secondary RAT.
+func NewSecondaryRATUsageDataReport(irsgw, irpgw, ratType, ebi uint8, start, end time.Time, dl, ul uint64) *IE {
+	i := New(SecondaryRATUsageDataReport, 0x00, make([]byte, 27))
+	i.Payload[0] = ((irsgw & 0x01) << 1) | (irpgw & 0x01)
+	i.Payload[1] = ratType
+	i.Payload[2] = ebi & 0x0f
+	binary.BigEndian.PutUint32(i.Payload[3:7], uint32(start.Unix()+secondsFrom1900To1970))
+	binary.BigEndian.PutUint32(i.Payload[7:11], uint32(end.Unix()+secondsFrom1900To1970))
+	binary.BigEndian.PutUint64(i.Payload[11:19], dl)
+	binary.BigEndian.PutUint64(i.Payload[19:27], ul)
+	return i
+}
+
+// IntendedReceiverSGW reports whether the SecondaryRATUsageDataReport is to be used
+// by the SGW.
+func (i *IE) IntendedReceiverSGW() bool {
+	if len(i.Payload) == 0 {
+		return false
+	}
+	switch i.Type {
+	case SecondaryRATUsageDataReport:
+		return i.Payload[0]&0x02 != 0
+	default:
+		return false
+	}
+}
+
+// IntendedReceiverPGW reports whether the SecondaryRATUsageDataReport is to be used
+// by the PGW.
+func (i *IE) IntendedReceiverPGW() bool {
+	if len(i.Payload) == 0 {
+		return false
+	}
+	switch i.Type {
+	case SecondaryRATUsageDataReport:
+		return i.Payload[0]&0x01 != 0
+	default:
+		return false
+	}
+}
+
+// SecondaryRATType returns SecondaryRATType in uint8 if the type of IE matches.
+func (i *IE) SecondaryRATType() (uint8, error) {
+	if i.Type != SecondaryRATUsageDataReport {
+		return 0, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) < 2 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	return i.Payload[1], nil
+}
+
+// MustSecondaryRATType returns SecondaryRATType in uint8, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustSecondaryRATType() uint8 {
+	v, _ := i.SecondaryRATType()
+	return v
+}
+
+// StartTimestamp returns StartTimestamp in time.Time if the type of IE matches.
+func (i *IE) StartTimestamp() (time.Time, error) {
+	if i.Type != SecondaryRATUsageDataReport {
+		return time.Time{}, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) < 7 {
+		return time.Time{}, io.ErrUnexpectedEOF
+	}
+
+	return time.Unix(int64(binary.BigEndian.Uint32(i.Payload[3:7]))-secondsFrom1900To1970, 0), nil
+}
+
+// MustStartTimestamp returns StartTimestamp in time.Time, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustStartTimestamp() time.Time {
+	v, _ := i.StartTimestamp()
+	return v
+}
+
+// EndTimestamp returns EndTimestamp in time.Time if the type of IE matches.
+func (i *IE) EndTimestamp() (time.Time, error) {
+	if i.Type != SecondaryRATUsageDataReport {
+		return time.Time{}, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) < 11 {
+		return time.Time{}, io.ErrUnexpectedEOF
+	}
+
+	return time.Unix(int64(binary.BigEndian.Uint32(i.Payload[7:11]))-secondsFrom1900To1970, 0), nil
+}
+
+// MustEndTimestamp returns EndTimestamp in time.Time, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustEndTimestamp() time.Time {
+	v, _ := i.EndTimestamp()
+	return v
+}
+
+// UsageDataDL returns the downlink data volume in octets in uint64 if the type of
+// IE matches.
+func (i *IE) UsageDataDL() (uint64, error) {
+	if i.Type != SecondaryRATUsageDataReport {
+		return 0, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) < 19 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	return binary.BigEndian.Uint64(i.Payload[11:19]), nil
+}
+
+// MustUsageDataDL returns the downlink data volume in octets in uint64, ignoring
+// errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustUsageDataDL() uint64 {
+	v, _ := i.UsageDataDL()
+	return v
+}
+
+// UsageDataUL returns the uplink data volume in octets in uint64 if the type of
+// IE matches.
+func (i *IE) UsageDataUL() (uint64, error) {
+	if i.Type != SecondaryRATUsageDataReport {
+		return 0, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) < 27 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	return binary.BigEndian.Uint64(i.Payload[19:27]), nil
+}
+
+// MustUsageDataUL returns the uplink data volume in octets in uint64, ignoring
+// errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustUsageDataUL() uint64 {
+	v, _ := i.UsageDataUL()
+	return v
+}
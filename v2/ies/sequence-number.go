@@ -0,0 +1,34 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// NewSequenceNumber creates a new SequenceNumber IE.
+func NewSequenceNumber(seq uint32) *IE {
+	return newUint32ValIE(SequenceNumber, seq)
+}
+
+// SequenceNumber returns SequenceNumber in uint32 if the type of IE matches.
+func (i *IE) SequenceNumber() (uint32, error) {
+	if i.Type != SequenceNumber {
+		return 0, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) < 4 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	return binary.BigEndian.Uint32(i.Payload), nil
+}
+
+// MustSequenceNumber returns SequenceNumber in uint32, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustSequenceNumber() uint32 {
+	v, _ := i.SequenceNumber()
+	return v
+}
@@ -57,12 +57,21 @@ func (i *IE) MCC() (string, error) {
 			return "", err
 		}
 		return mcc, nil
-	case GlobalCNID, TraceReference, GUTI, UserCSGInformation:
+	case GlobalCNID, TraceReference, TraceInformation, ExtendedTraceInformation, GUTI, UserCSGInformation:
 		mcc, _, err := utils.DecodePLMN(i.Payload[:3])
 		if err != nil {
 			return "", err
 		}
 		return mcc, nil
+	case TMGI:
+		if len(i.Payload) < 6 {
+			return "", io.ErrUnexpectedEOF
+		}
+		mcc, _, err := utils.DecodePLMN(i.Payload[3:6])
+		if err != nil {
+			return "", err
+		}
+		return mcc, nil
 	default:
 		return "", &InvalidTypeError{Type: i.Type}
 	}
@@ -88,12 +97,21 @@ func (i *IE) MNC() (string, error) {
 			return "", err
 		}
 		return mnc, nil
-	case GlobalCNID, TraceReference, GUTI, UserCSGInformation:
+	case GlobalCNID, TraceReference, TraceInformation, ExtendedTraceInformation, GUTI, UserCSGInformation:
 		_, mnc, err := utils.DecodePLMN(i.Payload[:3])
 		if err != nil {
 			return "", err
 		}
 		return mnc, nil
+	case TMGI:
+		if len(i.Payload) < 6 {
+			return "", io.ErrUnexpectedEOF
+		}
+		_, mnc, err := utils.DecodePLMN(i.Payload[3:6])
+		if err != nil {
+			return "", err
+		}
+		return mnc, nil
 	default:
 		return "", &InvalidTypeError{Type: i.Type}
 	}
@@ -0,0 +1,59 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// NewServingPLMNRateControl creates a new ServingPLMNRateControl IE.
+//
+// ul and dl are the maximum number of the packets per 6 minutes, defined in
+// TS 24.301 9.9.4.28.
+func NewServingPLMNRateControl(ul, dl uint16) *IE {
+	i := New(ServingPLMNRateControl, 0x00, make([]byte, 4))
+	binary.BigEndian.PutUint16(i.Payload[0:2], ul)
+	binary.BigEndian.PutUint16(i.Payload[2:4], dl)
+	return i
+}
+
+// UplinkRateLimit returns UplinkRateLimit in uint16 if the type of IE matches.
+func (i *IE) UplinkRateLimit() (uint16, error) {
+	if i.Type != ServingPLMNRateControl {
+		return 0, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) < 2 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	return binary.BigEndian.Uint16(i.Payload[0:2]), nil
+}
+
+// MustUplinkRateLimit returns UplinkRateLimit in uint16, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustUplinkRateLimit() uint16 {
+	v, _ := i.UplinkRateLimit()
+	return v
+}
+
+// DownlinkRateLimit returns DownlinkRateLimit in uint16 if the type of IE matches.
+func (i *IE) DownlinkRateLimit() (uint16, error) {
+	if i.Type != ServingPLMNRateControl {
+		return 0, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) < 4 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	return binary.BigEndian.Uint16(i.Payload[2:4]), nil
+}
+
+// MustDownlinkRateLimit returns DownlinkRateLimit in uint16, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustDownlinkRateLimit() uint16 {
+	v, _ := i.DownlinkRateLimit()
+	return v
+}
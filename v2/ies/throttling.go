@@ -0,0 +1,75 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import (
+	"io"
+	"time"
+)
+
+// NewThrottling creates a new Throttling IE.
+//
+// The delay is encoded in the same way as EPCTimer, and the factor is the percentage
+// of the messages to be throttled, from 0 to 100. The delay of zero is encoded as the
+// deactivated timer.
+func NewThrottling(delay time.Duration, factor uint8) *IE {
+	if factor > 100 {
+		factor = 100
+	}
+
+	timer := NewEPCTimer(delay).Payload[0]
+	if delay <= 0 {
+		timer = epcTimerUnitInfinite << 5
+	}
+	return New(Throttling, 0x00, []byte{timer, factor})
+}
+
+// ThrottlingDelay returns the Throttling Delay in time.Duration if the type of IE
+// matches. The deactivated timer is returned as zero.
+func (i *IE) ThrottlingDelay() (time.Duration, error) {
+	if i.Type != Throttling {
+		return 0, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) < 1 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	d := decodeEPCTimer(i.Payload[0])
+	if d == EPCTimerInfinite {
+		return 0, nil
+	}
+	return d, nil
+}
+
+// MustThrottlingDelay returns ThrottlingDelay in time.Duration, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustThrottlingDelay() time.Duration {
+	v, _ := i.ThrottlingDelay()
+	return v
+}
+
+// ThrottlingFactor returns the Throttling Factor in percentage if the type of IE
+// matches.
+func (i *IE) ThrottlingFactor() (uint8, error) {
+	if i.Type != Throttling {
+		return 0, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) < 2 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	// the values above 100 shall be interpreted as 0.
+	if f := i.Payload[1]; f <= 100 {
+		return f, nil
+	}
+	return 0, nil
+}
+
+// MustThrottlingFactor returns ThrottlingFactor in uint8, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustThrottlingFactor() uint8 {
+	v, _ := i.ThrottlingFactor()
+	return v
+}
@@ -0,0 +1,47 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import (
+	"io"
+
+	"github.com/wmnsk/go-gtp/utils"
+)
+
+// NewTMGI creates a new TMGI IE.
+//
+// The serviceID is the MBMS Service ID, whose upper 8 bits are ignored.
+func NewTMGI(serviceID uint32, mcc, mnc string) *IE {
+	plmn, err := utils.EncodePLMN(mcc, mnc)
+	if err != nil {
+		return nil
+	}
+
+	b := make([]byte, 6)
+	b[0] = uint8(serviceID >> 16)
+	b[1] = uint8(serviceID >> 8)
+	b[2] = uint8(serviceID)
+	copy(b[3:6], plmn)
+	return New(TMGI, 0x00, b)
+}
+
+// MBMSServiceID returns MBMS Service ID in uint32 if the type of IE matches.
+func (i *IE) MBMSServiceID() (uint32, error) {
+	if i.Type != TMGI {
+		return 0, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) < 3 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	return uint32(i.Payload[0])<<16 | uint32(i.Payload[1])<<8 | uint32(i.Payload[2]), nil
+}
+
+// MustMBMSServiceID returns MBMSServiceID in uint32, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustMBMSServiceID() uint32 {
+	v, _ := i.MBMSServiceID()
+	return v
+}
@@ -0,0 +1,188 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+
+	"github.com/wmnsk/go-gtp/utils"
+)
+
+// NewTraceInformation creates a new TraceInformation IE.
+//
+// triggeringEvents and interfaces are the bitmaps defined in TS 32.422, which are
+// 9 and 12 octets long respectively. The shorter ones are padded with zeros, and
+// the longer ones are truncated. collectionEntity is the IP address of the Trace
+// Collection Entity in string.
+func NewTraceInformation(mcc, mnc string, traceID uint32, triggeringEvents []byte, neTypes uint16, depth uint8, interfaces []byte, collectionEntity string) *IE {
+	plmn, err := utils.EncodePLMN(mcc, mnc)
+	if err != nil {
+		return nil
+	}
+	ip := parseIP(collectionEntity)
+	if ip == nil {
+		return nil
+	}
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+
+	i := New(TraceInformation, 0x00, make([]byte, 30+len(ip)))
+	copy(i.Payload[0:3], plmn)
+	copy(i.Payload[3:6], utils.Uint32To24(traceID))
+	copy(i.Payload[6:15], triggeringEvents)
+	binary.BigEndian.PutUint16(i.Payload[15:17], neTypes)
+	i.Payload[17] = depth
+	copy(i.Payload[18:30], interfaces)
+	copy(i.Payload[30:], ip)
+
+	return i
+}
+
+// TriggeringEvents returns TriggeringEvents in []byte if the type of IE matches.
+func (i *IE) TriggeringEvents() ([]byte, error) {
+	switch i.Type {
+	case TraceInformation:
+		if len(i.Payload) < 15 {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return i.Payload[6:15], nil
+	case ExtendedTraceInformation:
+		t, err := parseExtendedTrace(i.Payload)
+		if err != nil {
+			return nil, err
+		}
+		return t.triggeringEvents, nil
+	default:
+		return nil, &InvalidTypeError{Type: i.Type}
+	}
+}
+
+// MustTriggeringEvents returns TriggeringEvents in []byte, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustTriggeringEvents() []byte {
+	v, _ := i.TriggeringEvents()
+	return v
+}
+
+// ListOfNETypes returns ListOfNETypes in uint16 if the type of IE matches.
+//
+// For ExtendedTraceInformation, the first 2 octets of the list are returned, which
+// are all the NE types currently defined.
+func (i *IE) ListOfNETypes() (uint16, error) {
+	switch i.Type {
+	case TraceInformation:
+		if len(i.Payload) < 17 {
+			return 0, io.ErrUnexpectedEOF
+		}
+		return binary.BigEndian.Uint16(i.Payload[15:17]), nil
+	case ExtendedTraceInformation:
+		t, err := parseExtendedTrace(i.Payload)
+		if err != nil {
+			return 0, err
+		}
+		b := make([]byte, 2)
+		copy(b, t.neTypes)
+		return binary.BigEndian.Uint16(b), nil
+	default:
+		return 0, &InvalidTypeError{Type: i.Type}
+	}
+}
+
+// MustListOfNETypes returns ListOfNETypes in uint16, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustListOfNETypes() uint16 {
+	v, _ := i.ListOfNETypes()
+	return v
+}
+
+// SessionTraceDepth returns SessionTraceDepth in uint8 if the type of IE matches.
+func (i *IE) SessionTraceDepth() (uint8, error) {
+	switch i.Type {
+	case TraceInformation:
+		if len(i.Payload) < 18 {
+			return 0, io.ErrUnexpectedEOF
+		}
+		return i.Payload[17], nil
+	case ExtendedTraceInformation:
+		t, err := parseExtendedTrace(i.Payload)
+		if err != nil {
+			return 0, err
+		}
+		return t.depth, nil
+	default:
+		return 0, &InvalidTypeError{Type: i.Type}
+	}
+}
+
+// MustSessionTraceDepth returns SessionTraceDepth in uint8, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustSessionTraceDepth() uint8 {
+	v, _ := i.SessionTraceDepth()
+	return v
+}
+
+// ListOfInterfaces returns ListOfInterfaces in []byte if the type of IE matches.
+func (i *IE) ListOfInterfaces() ([]byte, error) {
+	switch i.Type {
+	case TraceInformation:
+		if len(i.Payload) < 30 {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return i.Payload[18:30], nil
+	case ExtendedTraceInformation:
+		t, err := parseExtendedTrace(i.Payload)
+		if err != nil {
+			return nil, err
+		}
+		return t.interfaces, nil
+	default:
+		return nil, &InvalidTypeError{Type: i.Type}
+	}
+}
+
+// MustListOfInterfaces returns ListOfInterfaces in []byte, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustListOfInterfaces() []byte {
+	v, _ := i.ListOfInterfaces()
+	return v
+}
+
+// TraceCollectionEntity returns the IP address of Trace Collection Entity in string
+// if the type of IE matches.
+func (i *IE) TraceCollectionEntity() (string, error) {
+	switch i.Type {
+	case TraceInformation:
+		switch len(i.Payload) {
+		case 34, 46:
+			return net.IP(i.Payload[30:]).String(), nil
+		default:
+			return "", io.ErrUnexpectedEOF
+		}
+	case ExtendedTraceInformation:
+		t, err := parseExtendedTrace(i.Payload)
+		if err != nil {
+			return "", err
+		}
+		switch len(t.collectionEntity) {
+		case net.IPv4len, net.IPv6len:
+			return net.IP(t.collectionEntity).String(), nil
+		default:
+			return "", ErrMalformed
+		}
+	default:
+		return "", &InvalidTypeError{Type: i.Type}
+	}
+}
+
+// MustTraceCollectionEntity returns the IP address of Trace Collection Entity in
+// string, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustTraceCollectionEntity() string {
+	v, _ := i.TraceCollectionEntity()
+	return v
+}
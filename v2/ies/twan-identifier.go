@@ -0,0 +1,229 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import (
+	"io"
+	"net"
+)
+
+// TWANID is the fields of TWAN Identifier IE, which is also used as WLAN Location
+// Information with instance 1.
+//
+// The fields that are nil are not present in the IE, except SSID which is always
+// present. The Relay Identity and Circuit-ID are present together if either of them
+// is not nil.
+type TWANID struct {
+	SSID              []byte
+	BSSID             net.HardwareAddr
+	CivicAddress      []byte
+	PLMN              *PLMN
+	OperatorName      []byte
+	RelayIdentityType uint8
+	RelayIdentity     []byte
+	CircuitID         []byte
+}
+
+// NewTWANIdentifier creates a new TWANIdentifier IE with SSID and BSSID. BSSID is
+// omitted if nil.
+func NewTWANIdentifier(ssid string, bssid net.HardwareAddr) *IE {
+	return NewTWANIdentifierStruct(&TWANID{SSID: []byte(ssid), BSSID: bssid})
+}
+
+// NewTWANIdentifierStruct creates a new TWANIdentifier IE from TWANID.
+func NewTWANIdentifierStruct(t *TWANID) *IE {
+	b, err := t.Marshal()
+	if err != nil {
+		return nil
+	}
+	return New(TWANIdentifier, 0x00, b)
+}
+
+// TWANIdentifier returns TWANIdentifier in TWANID if the type of IE matches.
+func (i *IE) TWANIdentifier() (*TWANID, error) {
+	if i.Type != TWANIdentifier {
+		return nil, &InvalidTypeError{Type: i.Type}
+	}
+	return ParseTWANID(i.Payload)
+}
+
+// MustTWANIdentifier returns TWANIdentifier in *TWANID, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustTWANIdentifier() *TWANID {
+	v, _ := i.TWANIdentifier()
+	return v
+}
+
+// ParseTWANID decodes TWANID.
+func ParseTWANID(b []byte) (*TWANID, error) {
+	t := &TWANID{}
+	if err := t.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// UnmarshalBinary decodes given bytes into TWANID.
+func (t *TWANID) UnmarshalBinary(b []byte) error {
+	if len(b) < 2 {
+		return io.ErrUnexpectedEOF
+	}
+	flags := b[0]
+
+	// lv reads the length and the value that follows from b[offset:].
+	offset := 1
+	lv := func() ([]byte, error) {
+		if len(b) <= offset {
+			return nil, io.ErrUnexpectedEOF
+		}
+		n := int(b[offset])
+		if len(b) < offset+1+n {
+			return nil, io.ErrUnexpectedEOF
+		}
+		v := b[offset+1 : offset+1+n]
+		offset += 1 + n
+		return v, nil
+	}
+
+	var err error
+	if t.SSID, err = lv(); err != nil {
+		return err
+	}
+	if flags&0x01 != 0 {
+		if len(b) < offset+6 {
+			return io.ErrUnexpectedEOF
+		}
+		t.BSSID = net.HardwareAddr(b[offset : offset+6])
+		offset += 6
+	}
+	if flags>>1&0x01 != 0 {
+		if t.CivicAddress, err = lv(); err != nil {
+			return err
+		}
+	}
+	if flags>>2&0x01 != 0 {
+		if len(b) < offset+plmnLen {
+			return io.ErrUnexpectedEOF
+		}
+		if t.PLMN, err = ParsePLMN(b[offset : offset+plmnLen]); err != nil {
+			return err
+		}
+		offset += plmnLen
+	}
+	if flags>>3&0x01 != 0 {
+		if t.OperatorName, err = lv(); err != nil {
+			return err
+		}
+	}
+	if flags>>4&0x01 != 0 {
+		if len(b) <= offset {
+			return io.ErrUnexpectedEOF
+		}
+		t.RelayIdentityType = b[offset]
+		offset++
+		if t.RelayIdentity, err = lv(); err != nil {
+			return err
+		}
+		if t.CircuitID, err = lv(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Marshal serializes TWANID.
+func (t *TWANID) Marshal() ([]byte, error) {
+	b := make([]byte, t.MarshalLen())
+	if err := t.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MarshalTo serializes TWANID.
+func (t *TWANID) MarshalTo(b []byte) error {
+	if len(b) < t.MarshalLen() {
+		return io.ErrUnexpectedEOF
+	}
+	for _, v := range [][]byte{t.SSID, t.CivicAddress, t.OperatorName, t.RelayIdentity, t.CircuitID} {
+		if len(v) > 0xff {
+			return ErrMalformed
+		}
+	}
+	if t.BSSID != nil && len(t.BSSID) != 6 {
+		return ErrMalformed
+	}
+	b[0] = t.flags()
+
+	offset := 1
+	lv := func(v []byte) {
+		b[offset] = uint8(len(v))
+		copy(b[offset+1:], v)
+		offset += 1 + len(v)
+	}
+
+	lv(t.SSID)
+	if t.BSSID != nil {
+		copy(b[offset:offset+6], t.BSSID)
+		offset += 6
+	}
+	if t.CivicAddress != nil {
+		lv(t.CivicAddress)
+	}
+	if t.PLMN != nil {
+		if err := encodePLMN(b[offset:], t.PLMN); err != nil {
+			return err
+		}
+		offset += plmnLen
+	}
+	if t.OperatorName != nil {
+		lv(t.OperatorName)
+	}
+	if t.hasLocalAccessLine() {
+		b[offset] = t.RelayIdentityType
+		offset++
+		lv(t.RelayIdentity)
+		lv(t.CircuitID)
+	}
+	return nil
+}
+
+// MarshalLen returns the serial length of TWANID in int.
+func (t *TWANID) MarshalLen() int {
+	l := 2 + len(t.SSID)
+	if t.BSSID != nil {
+		l += 6
+	}
+	if t.CivicAddress != nil {
+		l += 1 + len(t.CivicAddress)
+	}
+	if t.PLMN != nil {
+		l += plmnLen
+	}
+	if t.OperatorName != nil {
+		l += 1 + len(t.OperatorName)
+	}
+	if t.hasLocalAccessLine() {
+		l += 3 + len(t.RelayIdentity) + len(t.CircuitID)
+	}
+	return l
+}
+
+func (t *TWANID) hasLocalAccessLine() bool {
+	return t.RelayIdentity != nil || t.CircuitID != nil
+}
+
+func (t *TWANID) flags() uint8 {
+	var flags uint8
+	for n, present := range []bool{
+		t.BSSID != nil, t.CivicAddress != nil, t.PLMN != nil,
+		t.OperatorName != nil, t.hasLocalAccessLine(),
+	} {
+		if present {
+			flags |= 1 << uint(n)
+		}
+	}
+	return flags
+}
@@ -0,0 +1,62 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import "io"
+
+// NewTrustedWLANModeIndication creates a new TrustedWLANModeIndication IE.
+func NewTrustedWLANModeIndication(mcm, scm uint8) *IE {
+	i := New(TrustedWLANModeIndication, 0x00, make([]byte, 1))
+	i.Payload[0] |= (mcm << 1 & 0x02) | (scm & 0x01)
+	return i
+}
+
+// TrustedWLANModeIndication returns TrustedWLANModeIndication in uint8 if the type
+// of IE matches.
+func (i *IE) TrustedWLANModeIndication() (uint8, error) {
+	if i.Type != TrustedWLANModeIndication {
+		return 0, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) == 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	return i.Payload[0], nil
+}
+
+// MustTrustedWLANModeIndication returns TrustedWLANModeIndication in uint8, ignoring
+// errors. This should only be used if it is assured to have the value.
+func (i *IE) MustTrustedWLANModeIndication() uint8 {
+	v, _ := i.TrustedWLANModeIndication()
+	return v
+}
+
+// MultipleConnectionMode reports whether the Multiple-connection mode is used in the
+// Trusted WLAN.
+func (i *IE) MultipleConnectionMode() bool {
+	if len(i.Payload) == 0 {
+		return false
+	}
+	switch i.Type {
+	case TrustedWLANModeIndication:
+		return i.Payload[0]&0x02 != 0
+	default:
+		return false
+	}
+}
+
+// SingleConnectionMode reports whether the Single-connection mode is used in the
+// Trusted WLAN.
+func (i *IE) SingleConnectionMode() bool {
+	if len(i.Payload) == 0 {
+		return false
+	}
+	switch i.Type {
+	case TrustedWLANModeIndication:
+		return i.Payload[0]&0x01 != 0
+	default:
+		return false
+	}
+}
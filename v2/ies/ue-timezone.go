@@ -5,30 +5,47 @@
 package ies
 
 import (
+	"fmt"
 	"io"
-	"math"
-	"strconv"
 	"time"
 )
 
 // NewUETimeZone creates a new UETimeZone IE.
+//
+// The tz is the offset of the local time from UTC including the adjustment for the
+// daylight saving time given by daylightSaving, which is encoded in quarters of
+// an hour. It returns nil if tz is not within +/- 19 hours and 45 minutes.
 func NewUETimeZone(tz time.Duration, daylightSaving uint8) *IE {
-	i := New(UETimeZone, 0x00, make([]byte, 2))
-	min := tz.Minutes() / 15
-	absMin := int(math.Abs(min))
-	hex, err := strconv.ParseInt(strconv.Itoa(absMin%10)+strconv.Itoa(absMin/10), 16, 8)
-	if err != nil {
+	quarters := int(tz / (15 * time.Minute))
+	abs := quarters
+	if abs < 0 {
+		abs = -abs
+	}
+	// the tens digit has 3 bits, as the 4th bit is the sign.
+	if abs > 79 {
 		return nil
 	}
-	if min < 0 {
-		hex |= 0x08
+
+	i := New(UETimeZone, 0x00, make([]byte, 2))
+	i.Payload[0] = uint8(abs%10)<<4 | uint8(abs/10)
+	if quarters < 0 {
+		i.Payload[0] |= 0x08
 	}
-	i.Payload[0] = uint8(hex)
 	i.Payload[1] = daylightSaving & 0x03
 
 	return i
 }
 
+// NewUETimeZoneFromTime creates a new UETimeZone IE with the offset from UTC of the
+// location of t.
+//
+// The daylightSaving should be given by the caller, as it cannot be told from t
+// whether the offset includes the adjustment for the daylight saving time.
+func NewUETimeZoneFromTime(t time.Time, daylightSaving uint8) *IE {
+	_, offset := t.Zone()
+	return NewUETimeZone(time.Duration(offset)*time.Second, daylightSaving)
+}
+
 // TimeZone returns TimeZone in time.Duration if the type of IE matches.
 func (i *IE) TimeZone() (time.Duration, error) {
 	if i.Type != UETimeZone {
@@ -63,7 +80,7 @@ func (i *IE) DaylightSaving() (uint8, error) {
 		return 0, io.ErrUnexpectedEOF
 	}
 
-	return i.Payload[1], nil
+	return i.Payload[1] & 0x03, nil
 }
 
 // MustDaylightSaving returns DaylightSaving in uint8, ignoring errors.
@@ -72,3 +89,68 @@ func (i *IE) MustDaylightSaving() uint8 {
 	v, _ := i.DaylightSaving()
 	return v
 }
+
+// DaylightSavingTime returns the adjustment for the daylight saving time included in
+// TimeZone in time.Duration if the type of IE matches.
+func (i *IE) DaylightSavingTime() (time.Duration, error) {
+	dst, err := i.DaylightSaving()
+	if err != nil {
+		return 0, err
+	}
+	if dst > 2 {
+		return 0, ErrMalformed
+	}
+	return time.Duration(dst) * time.Hour, nil
+}
+
+// MustDaylightSavingTime returns DaylightSavingTime in time.Duration, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustDaylightSavingTime() time.Duration {
+	v, _ := i.DaylightSavingTime()
+	return v
+}
+
+// StandardTimeZone returns the offset of the standard time from UTC, which is
+// TimeZone without the adjustment for the daylight saving time, if the type of IE
+// matches.
+func (i *IE) StandardTimeZone() (time.Duration, error) {
+	tz, err := i.TimeZone()
+	if err != nil {
+		return 0, err
+	}
+	dst, err := i.DaylightSavingTime()
+	if err != nil {
+		return 0, err
+	}
+	return tz - dst, nil
+}
+
+// MustStandardTimeZone returns StandardTimeZone in time.Duration, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustStandardTimeZone() time.Duration {
+	v, _ := i.StandardTimeZone()
+	return v
+}
+
+// TimeZoneLocation returns the *time.Location with the fixed offset of TimeZone,
+// named like "UTC+09:00", if the type of IE matches.
+func (i *IE) TimeZoneLocation() (*time.Location, error) {
+	tz, err := i.TimeZone()
+	if err != nil {
+		return nil, err
+	}
+
+	sign, abs := '+', tz
+	if tz < 0 {
+		sign, abs = '-', -tz
+	}
+	name := fmt.Sprintf("UTC%c%02d:%02d", sign, int(abs.Hours()), int(abs.Minutes())%60)
+	return time.FixedZone(name, int(tz.Seconds())), nil
+}
+
+// MustTimeZoneLocation returns TimeZoneLocation in *time.Location, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustTimeZoneLocation() *time.Location {
+	v, _ := i.TimeZoneLocation()
+	return v
+}
@@ -16,6 +16,12 @@ func NewULITimestamp(ts time.Time) *IE {
 	return newUint32ValIE(ULITimestamp, uint32(u64sec))
 }
 
+// NewTWANIdentifierTimestamp creates a new TWANIdentifierTimestamp IE.
+func NewTWANIdentifierTimestamp(ts time.Time) *IE {
+	u64sec := uint64(ts.Sub(time.Date(1900, time.January, 1, 0, 0, 0, 0, time.UTC))) / 1000000000
+	return newUint32ValIE(TWANIdentifierTimestamp, uint32(u64sec))
+}
+
 // Timestamp returns Timestamp in time.Time if the type of IE matches.
 func (i *IE) Timestamp() (time.Time, error) {
 	if len(i.Payload) < 4 {
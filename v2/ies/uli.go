@@ -23,6 +23,9 @@ const (
 )
 
 // ULI IE's info fields
+//
+// The fields that are nil are not present in the IE. Each of the fields has its own
+// PLMN, which must not be nil when serialized.
 type ULI struct {
 	*CGI
 	*SAI
@@ -34,12 +37,6 @@ type ULI struct {
 	*EMENBI
 }
 
-// PLMN info field of ULI IE
-type PLMN struct {
-	MCC string
-	MNC string
-}
-
 // CGI field of ULI IE
 type CGI struct {
 	*PLMN
@@ -68,6 +65,8 @@ type TAI struct {
 }
 
 // ECGI field of ULI IE
+//
+// ECI is the E-UTRAN Cell Identifier in 28 bits.
 type ECGI struct {
 	*PLMN
 	ECI uint32
@@ -80,15 +79,21 @@ type LAI struct {
 }
 
 // MENBI field of ULI IE
+//
+// MENBI is the Macro eNodeB ID in 20 bits.
 type MENBI struct {
 	*PLMN
 	MENBI uint32
 }
 
 // EMENBI field of ULI IE
+//
+// EMENBI is the Long Macro eNodeB ID in 21 bits, or the Short Macro eNodeB ID in 18
+// bits if SMeNB is true.
 type EMENBI struct {
 	*PLMN
 	EMENBI uint32
+	SMeNB  bool
 }
 
 // NewUserLocationInformationLazy creates a new UserLocationInformation IE.
@@ -227,106 +232,224 @@ func uliPayloadLen(flags uint8) int {
 	return l
 }
 
-// UserLocationInfo is a getter function to parse ULI
-func (i *IE) UserLocationInfo() (*ULI, error) {
-	var uli ULI
-	var plmn PLMN
-	l := len(i.Payload)
-	if l == 0 {
-		return &uli, io.ErrUnexpectedEOF
+// NewUserLocationInformationStruct creates a new UserLocationInformation IE from ULI.
+func NewUserLocationInformationStruct(uli *ULI) *IE {
+	b, err := uli.Marshal()
+	if err != nil {
+		return nil
+	}
+	return New(UserLocationInformation, 0x00, b)
+}
+
+// UserLocationInformation returns UserLocationInformation in ULI if the type of IE
+// matches.
+func (i *IE) UserLocationInformation() (*ULI, error) {
+	if i.Type != UserLocationInformation {
+		return nil, &InvalidTypeError{Type: i.Type}
+	}
+	return ParseULI(i.Payload)
+}
+
+// MustUserLocationInformation returns UserLocationInformation in *ULI, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustUserLocationInformation() *ULI {
+	v, _ := i.UserLocationInformation()
+	return v
+}
+
+// ParseULI decodes ULI.
+func ParseULI(b []byte) (*ULI, error) {
+	uli := &ULI{}
+	if err := uli.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return uli, nil
+}
+
+// UnmarshalBinary decodes given bytes into ULI.
+func (u *ULI) UnmarshalBinary(b []byte) error {
+	if len(b) == 0 {
+		return io.ErrUnexpectedEOF
+	}
+	flags := b[0]
+	if len(b) < uliPayloadLen(flags) {
+		return io.ErrUnexpectedEOF
+	}
+
+	// plmn decodes the PLMN at offset, keeping the first error to return.
+	var err error
+	plmn := func(offset int) *PLMN {
+		p, e := ParsePLMN(b[offset : offset+plmnLen])
+		if e != nil && err == nil {
+			err = e
+		}
+		return p
 	}
+
 	offset := 1
-	if i.Payload[0]&0x01 == 1 {
-		if l < (offset + cgilen) {
-			return &uli, io.ErrUnexpectedEOF
+	if flags&0x01 == 1 {
+		u.CGI = &CGI{
+			PLMN: plmn(offset),
+			LAC:  binary.BigEndian.Uint16(b[offset+3 : offset+5]),
+			CI:   binary.BigEndian.Uint16(b[offset+5 : offset+7]),
 		}
-		var cgi CGI
-		uli.CGI = &cgi
-		uli.CGI.PLMN = &plmn
-		uli.CGI.PLMN.MCC, uli.CGI.PLMN.MNC, _ = utils.DecodePLMN(i.Payload[offset : offset+3])
-		uli.CGI.LAC = binary.BigEndian.Uint16(i.Payload[offset+3 : offset+5])
-		uli.CGI.CI = binary.BigEndian.Uint16(i.Payload[offset+5 : offset+7])
 		offset += cgilen
 	}
-	if i.Payload[0]>>1&0x01 == 1 {
-		if l < (offset + sailen) {
-			return &uli, io.ErrUnexpectedEOF
+	if flags>>1&0x01 == 1 {
+		u.SAI = &SAI{
+			PLMN: plmn(offset),
+			LAC:  binary.BigEndian.Uint16(b[offset+3 : offset+5]),
+			SAC:  binary.BigEndian.Uint16(b[offset+5 : offset+7]),
 		}
-		var sai SAI
-		uli.SAI = &sai
-		uli.SAI.PLMN = &plmn
-		uli.SAI.PLMN.MCC, uli.SAI.PLMN.MNC, _ = utils.DecodePLMN(i.Payload[offset : offset+3])
-		uli.SAI.LAC = binary.BigEndian.Uint16(i.Payload[offset+3 : offset+5])
-		uli.SAI.SAC = binary.BigEndian.Uint16(i.Payload[offset+5 : offset+7])
 		offset += sailen
 	}
-	if i.Payload[0]>>2&0x01 == 1 {
-		if l < (offset + railen) {
-			return &uli, io.ErrUnexpectedEOF
+	if flags>>2&0x01 == 1 {
+		u.RAI = &RAI{
+			PLMN: plmn(offset),
+			LAC:  binary.BigEndian.Uint16(b[offset+3 : offset+5]),
+			RAC:  binary.BigEndian.Uint16(b[offset+5 : offset+7]),
 		}
-		var rai RAI
-		uli.RAI = &rai
-		uli.RAI.PLMN = &plmn
-		uli.RAI.PLMN.MCC, uli.RAI.PLMN.MNC, _ = utils.DecodePLMN(i.Payload[offset : offset+3])
-		uli.RAI.LAC = binary.BigEndian.Uint16(i.Payload[offset+3 : offset+5])
-		uli.RAI.RAC = binary.BigEndian.Uint16(i.Payload[offset+5 : offset+7])
 		offset += railen
 	}
-	if i.Payload[0]>>3&0x01 == 1 {
-		if l < (offset + tailen) {
-			return &uli, io.ErrUnexpectedEOF
+	if flags>>3&0x01 == 1 {
+		u.TAI = &TAI{
+			PLMN: plmn(offset),
+			TAC:  binary.BigEndian.Uint16(b[offset+3 : offset+5]),
 		}
-		var tai TAI
-		uli.TAI = &tai
-		uli.TAI.PLMN = &plmn
-		uli.TAI.PLMN.MCC, uli.TAI.PLMN.MNC, _ = utils.DecodePLMN(i.Payload[offset : offset+3])
-		uli.TAI.TAC = binary.BigEndian.Uint16(i.Payload[offset+3 : offset+5])
 		offset += tailen
-
 	}
-	if i.Payload[0]>>4&0x01 == 1 {
-		if l < (offset + ecgilen) {
-			return &uli, io.ErrUnexpectedEOF
+	if flags>>4&0x01 == 1 {
+		u.ECGI = &ECGI{
+			PLMN: plmn(offset),
+			ECI:  binary.BigEndian.Uint32(b[offset+3:offset+7]) & 0x0fffffff,
 		}
-		var ecgi ECGI
-		uli.ECGI = &ecgi
-		uli.ECGI.PLMN = &plmn
-		uli.ECGI.PLMN.MCC, uli.ECGI.PLMN.MNC, _ = utils.DecodePLMN(i.Payload[offset : offset+3])
-		uli.ECGI.ECI = binary.BigEndian.Uint32(i.Payload[offset+3 : offset+7])
 		offset += ecgilen
+	}
+	if flags>>5&0x01 == 1 {
+		u.LAI = &LAI{
+			PLMN: plmn(offset),
+			LAC:  binary.BigEndian.Uint16(b[offset+3 : offset+5]),
+		}
+		offset += lailen
+	}
+	if flags>>6&0x01 == 1 {
+		u.MENBI = &MENBI{
+			PLMN:  plmn(offset),
+			MENBI: utils.Uint24To32(b[offset+3:offset+6]) & 0x0fffff,
+		}
+		offset += menbilen
+	}
+	if flags>>7&0x01 == 1 {
+		e := &EMENBI{
+			PLMN:  plmn(offset),
+			SMeNB: b[offset+3]&0x80 != 0,
+		}
+		e.EMENBI = utils.Uint24To32(b[offset+3:offset+6]) & 0x1fffff
+		if e.SMeNB {
+			e.EMENBI &= 0x3ffff
+		}
+		u.EMENBI = e
+	}
+	return err
+}
+
+// Marshal serializes ULI.
+func (u *ULI) Marshal() ([]byte, error) {
+	b := make([]byte, u.MarshalLen())
+	if err := u.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
 
+// MarshalTo serializes ULI.
+func (u *ULI) MarshalTo(b []byte) error {
+	flags := u.flags()
+	if len(b) < uliPayloadLen(flags) {
+		return io.ErrUnexpectedEOF
 	}
-	if i.Payload[0]>>5&0x01 == 1 {
-		if l < (offset + lailen) {
-			return &uli, io.ErrUnexpectedEOF
+	b[0] = flags
+
+	offset := 1
+	if f := u.CGI; f != nil {
+		if err := encodePLMN(b[offset:], f.PLMN); err != nil {
+			return err
 		}
-		var lai LAI
-		uli.LAI = &lai
-		uli.LAI.PLMN = &plmn
-		uli.LAI.PLMN.MCC, uli.LAI.PLMN.MNC, _ = utils.DecodePLMN(i.Payload[offset : offset+3])
-		uli.LAI.LAC = binary.BigEndian.Uint16(i.Payload[offset+3 : offset+5])
+		binary.BigEndian.PutUint16(b[offset+3:offset+5], f.LAC)
+		binary.BigEndian.PutUint16(b[offset+5:offset+7], f.CI)
+		offset += cgilen
+	}
+	if f := u.SAI; f != nil {
+		if err := encodePLMN(b[offset:], f.PLMN); err != nil {
+			return err
+		}
+		binary.BigEndian.PutUint16(b[offset+3:offset+5], f.LAC)
+		binary.BigEndian.PutUint16(b[offset+5:offset+7], f.SAC)
+		offset += sailen
+	}
+	if f := u.RAI; f != nil {
+		if err := encodePLMN(b[offset:], f.PLMN); err != nil {
+			return err
+		}
+		binary.BigEndian.PutUint16(b[offset+3:offset+5], f.LAC)
+		binary.BigEndian.PutUint16(b[offset+5:offset+7], f.RAC)
+		offset += railen
+	}
+	if f := u.TAI; f != nil {
+		if err := encodePLMN(b[offset:], f.PLMN); err != nil {
+			return err
+		}
+		binary.BigEndian.PutUint16(b[offset+3:offset+5], f.TAC)
+		offset += tailen
+	}
+	if f := u.ECGI; f != nil {
+		if err := encodePLMN(b[offset:], f.PLMN); err != nil {
+			return err
+		}
+		binary.BigEndian.PutUint32(b[offset+3:offset+7], f.ECI&0x0fffffff)
+		offset += ecgilen
+	}
+	if f := u.LAI; f != nil {
+		if err := encodePLMN(b[offset:], f.PLMN); err != nil {
+			return err
+		}
+		binary.BigEndian.PutUint16(b[offset+3:offset+5], f.LAC)
 		offset += lailen
 	}
-	if i.Payload[0]>>6&0x01 == 1 {
-		if l < (offset + menbilen) {
-			return &uli, io.ErrUnexpectedEOF
+	if f := u.MENBI; f != nil {
+		if err := encodePLMN(b[offset:], f.PLMN); err != nil {
+			return err
 		}
-		var menbi MENBI
-		uli.MENBI = &menbi
-		uli.MENBI.PLMN = &plmn
-		uli.MENBI.PLMN.MCC, uli.MENBI.PLMN.MNC, _ = utils.DecodePLMN(i.Payload[offset : offset+3])
-		uli.MENBI.MENBI = utils.Uint24To32(i.Payload[offset+3 : offset+6])
+		copy(b[offset+3:offset+6], utils.Uint32To24(f.MENBI&0x0fffff))
 		offset += menbilen
 	}
-	if i.Payload[0]>>7&0x01 == 1 {
-		if l < (offset + emenbilen) {
-			return &uli, io.ErrUnexpectedEOF
+	if f := u.EMENBI; f != nil {
+		if err := encodePLMN(b[offset:], f.PLMN); err != nil {
+			return err
+		}
+		id := f.EMENBI & 0x1fffff
+		if f.SMeNB {
+			id = f.EMENBI&0x3ffff | 0x800000
+		}
+		copy(b[offset+3:offset+6], utils.Uint32To24(id))
+	}
+	return nil
+}
+
+// MarshalLen returns the serial length of ULI in int.
+func (u *ULI) MarshalLen() int {
+	return uliPayloadLen(u.flags())
+}
+
+func (u *ULI) flags() uint8 {
+	var flags uint8
+	for n, present := range []bool{
+		u.CGI != nil, u.SAI != nil, u.RAI != nil, u.TAI != nil,
+		u.ECGI != nil, u.LAI != nil, u.MENBI != nil, u.EMENBI != nil,
+	} {
+		if present {
+			flags |= 1 << uint(n)
 		}
-		var emenbi EMENBI
-		uli.EMENBI = &emenbi
-		uli.EMENBI.PLMN = &plmn
-		uli.EMENBI.PLMN.MCC, uli.EMENBI.PLMN.MNC, _ = utils.DecodePLMN(i.Payload[offset : offset+3])
-		uli.EMENBI.EMENBI = utils.Uint24To32(i.Payload[offset+3 : offset+6])
 	}
-	return &uli, nil
+	return flags
 }
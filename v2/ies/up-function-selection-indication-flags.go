@@ -0,0 +1,47 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import "io"
+
+// NewUPFunctionSelectionIndicationFlags creates a new UPFunctionSelectionIndicationFlags IE.
+func NewUPFunctionSelectionIndicationFlags(dcnr uint8) *IE {
+	return newUint8ValIE(UPFunctionSelectionIndicationFlags, dcnr&0x01)
+}
+
+// UPFunctionSelectionIndicationFlags returns UPFunctionSelectionIndicationFlags
+// in uint8(=as it is) if the type of IE matches.
+func (i *IE) UPFunctionSelectionIndicationFlags() (uint8, error) {
+	if i.Type != UPFunctionSelectionIndicationFlags {
+		return 0, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) == 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	return i.Payload[0], nil
+}
+
+// MustUPFunctionSelectionIndicationFlags returns UPFunctionSelectionIndicationFlags
+// in uint8, ignoring errors.
+// This should only be used if it is assured to have the value.
+func (i *IE) MustUPFunctionSelectionIndicationFlags() uint8 {
+	v, _ := i.UPFunctionSelectionIndicationFlags()
+	return v
+}
+
+// DualConnectivityWithNR reports whether the UE is served with Dual Connectivity
+// with NR, i.e., the UP function that supports it should be selected.
+func (i *IE) DualConnectivityWithNR() bool {
+	if len(i.Payload) == 0 {
+		return false
+	}
+	switch i.Type {
+	case UPFunctionSelectionIndicationFlags:
+		return i.Payload[0]&0x01 != 0
+	default:
+		return false
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+// Walk calls fn for each IE in ie and the IEs inside them in depth-first order,
+// with the depth of the IE, which is 0 for the ones in ie.
+//
+// The children of the grouped IE are visited right after it. If fn returns false,
+// Walk stops the traversal.
+func Walk(ie []*IE, fn func(i *IE, depth int) bool) {
+	walk(ie, 0, fn)
+}
+
+func walk(ie []*IE, depth int, fn func(i *IE, depth int) bool) bool {
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		if !fn(i, depth) {
+			return false
+		}
+		if !i.IsGrouped() {
+			continue
+		}
+		if !walk(i.ChildIEs, depth+1, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// Find returns all the IEs with the type and instance given in ie, including the
+// ones inside the grouped IEs at any depth.
+//
+// This is useful to collect the IEs regardless of where they are, e.g., all the
+// F-TEIDs in the Bearer Contexts and in the top level of a message.
+func Find(ie []*IE, typ, instance uint8) []*IE {
+	var found []*IE
+	Walk(ie, func(i *IE, depth int) bool {
+		if i.Type == typ && i.Instance() == instance {
+			found = append(found, i)
+		}
+		return true
+	})
+	return found
+}
+
+// FindAllByType returns all the IEs in the grouped IE with the type given,
+// regardless of the instance.
+//
+// It returns ErrInvalidType if the IE is not grouped type.
+func (i *IE) FindAllByType(typ uint8) ([]*IE, error) {
+	if !i.IsGrouped() {
+		return nil, ErrInvalidType
+	}
+
+	var found []*IE
+	for _, ie := range i.ChildIEs {
+		if ie.Type == typ {
+			found = append(found, ie)
+		}
+	}
+	return found, nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package ies
+
+import "io"
+
+// NewWLANOffloadabilityIndication creates a new WLANOffloadabilityIndication IE.
+func NewWLANOffloadabilityIndication(eutran, utran uint8) *IE {
+	i := New(WLANOffloadabilityIndication, 0x00, make([]byte, 1))
+	i.Payload[0] |= (eutran << 1 & 0x02) | (utran & 0x01)
+	return i
+}
+
+// WLANOffloadabilityIndication returns WLANOffloadabilityIndication in uint8 if the
+// type of IE matches.
+func (i *IE) WLANOffloadabilityIndication() (uint8, error) {
+	if i.Type != WLANOffloadabilityIndication {
+		return 0, &InvalidTypeError{Type: i.Type}
+	}
+	if len(i.Payload) == 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	return i.Payload[0], nil
+}
+
+// MustWLANOffloadabilityIndication returns WLANOffloadabilityIndication in uint8,
+// ignoring errors. This should only be used if it is assured to have the value.
+func (i *IE) MustWLANOffloadabilityIndication() uint8 {
+	v, _ := i.WLANOffloadabilityIndication()
+	return v
+}
+
+// EUTRANOffloadable reports whether the traffic of the UE in E-UTRAN can be offloaded
+// to WLAN.
+func (i *IE) EUTRANOffloadable() bool {
+	if len(i.Payload) == 0 {
+		return false
+	}
+	switch i.Type {
+	case WLANOffloadabilityIndication:
+		return i.Payload[0]&0x02 != 0
+	default:
+		return false
+	}
+}
+
+// UTRANOffloadable reports whether the traffic of the UE in UTRAN can be offloaded
+// to WLAN.
+func (i *IE) UTRANOffloadable() bool {
+	if len(i.Payload) == 0 {
+		return false
+	}
+	switch i.Type {
+	case WLANOffloadabilityIndication:
+		return i.Payload[0]&0x01 != 0
+	default:
+		return false
+	}
+}
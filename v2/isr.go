@@ -0,0 +1,97 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// SetISRActive sets whether the Idle mode Signalling Reduction is activated for the
+// Session, i.e., the UE is registered with both MME and S4-SGSN.
+//
+// This is set automatically when Conn receives the Modify Bearer Request with the
+// Indication for the Session, by following the ISRAI flag in it, and cleared when
+// Conn receives the Delete Session or Delete Bearer Request with Cause "ISR
+// deactivation". The node that sends them should set it by itself.
+func (s *Session) SetISRActive(active bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.isISRActive = active
+}
+
+// IsISRActive reports whether the Idle mode Signalling Reduction is activated for the
+// Session.
+func (s *Session) IsISRActive() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.isISRActive
+}
+
+// StopPagingIndication sends a StopPagingIndication with TEID and IEs given.
+//
+// This is used by SGW to tell the MME or S4-SGSN that it no longer needs to page
+// the UE, as the UE has responded to the paging from the other node while ISR is
+// activated. It returns ErrISRNotActive without sending anything if ISR is not
+// activated for the Session.
+func (c *Conn) StopPagingIndication(teid uint32, raddr net.Addr, ie ...*ies.IE) (uint32, error) {
+	sess, err := c.GetSessionByTEID(teid, raddr)
+	if err != nil {
+		return 0, err
+	}
+	if !sess.IsISRActive() {
+		return 0, ErrISRNotActive
+	}
+
+	msg := messages.NewStopPagingIndication(teid, 0, ie...)
+
+	seq, err := c.SendMessageTo(msg, sess.PeerAddr())
+	if err != nil {
+		return 0, err
+	}
+	return seq, nil
+}
+
+// handleISR updates the ISR state of the Session if msg is the Modify Bearer Request
+// with Indication, or the Delete Session or Delete Bearer Request with Cause "ISR
+// deactivation".
+func (c *Conn) handleISR(senderAddr net.Addr, msg messages.Message) {
+	var active bool
+	switch m := msg.(type) {
+	case *messages.ModifyBearerRequest:
+		if m.IndicationFlags == nil {
+			return
+		}
+		active = m.IndicationFlags.ISRAI()
+	case *messages.DeleteSessionRequest:
+		if !isISRDeactivation(m.Cause) {
+			return
+		}
+	case *messages.DeleteBearerRequest:
+		if !isISRDeactivation(m.Cause) {
+			return
+		}
+	default:
+		return
+	}
+
+	sess, err := c.GetSessionByTEID(msg.TEID(), senderAddr)
+	if err != nil {
+		return
+	}
+	sess.SetISRActive(active)
+}
+
+func isISRDeactivation(cause *ies.IE) bool {
+	if cause == nil {
+		return false
+	}
+	v, err := cause.Cause()
+	return err == nil && v == CauseISRDeactivation
+}
@@ -0,0 +1,171 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// EnableTEIDLearning turns on updating the Sessions with the F-TEIDs in Create
+// Session Response and Modify Bearer Response to the requests sent with Conn, which
+// saves the HandlerFuncs from extracting them. See (*Session) LearnTEIDs for what is
+// updated.
+//
+// The Session is updated before the HandlerFunc for the response is called, and thus
+// it should be added to Conn before the response arrives. This is disabled by
+// default, as the node may relay the F-TEIDs instead of using them.
+func (c *Conn) EnableTEIDLearning() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.teidLearningEnabled = true
+}
+
+// DisableTEIDLearning turns off updating the Sessions with the F-TEIDs in the
+// responses. The ones already learned are kept in the Sessions.
+func (c *Conn) DisableTEIDLearning() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.teidLearningEnabled = false
+}
+
+func (c *Conn) isTEIDLearningEnabled() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.teidLearningEnabled
+}
+
+// isTEIDLearningResponse reports whether the F-TEIDs are learned from the response
+// of msgType.
+func isTEIDLearningResponse(msgType uint8) bool {
+	switch msgType {
+	case messages.MsgTypeCreateSessionResponse, messages.MsgTypeModifyBearerResponse:
+		return true
+	default:
+		return false
+	}
+}
+
+// LearnTEIDs updates Session with the F-TEIDs in rsp, which is the Create Session
+// Response or Modify Bearer Response received. Nothing is done if rsp is not an
+// acceptance or of the other types.
+//
+// All the F-TEIDs are added to Session by their interface types, including the ones
+// in the Bearer Contexts. The Bearers are looked up by the EBI in the Bearer Contexts
+// created or modified, and the F-TEID with the smallest instance in each of them,
+// e.g., S1-U SGW F-TEID, sets the outgoing TEID and the remote address with GTPUPort
+// of the Bearer. The Bearer Context in Create Session Response whose Bearer cannot be
+// found is for the default Bearer if it has no EBI yet, which is given the EBI. The
+// PAA in Create Session Response is set to the default Bearer as well.
+//
+// This is called automatically if (*Conn) EnableTEIDLearning is used.
+func (s *Session) LearnTEIDs(rsp messages.Message) error {
+	if rsp == nil || !isTEIDLearningResponse(rsp.MessageType()) {
+		return nil
+	}
+	rspIEs, err := messageIEs(rsp)
+	if err != nil {
+		return err
+	}
+	if !isAccepted(rspIEs...) {
+		return nil
+	}
+	isCSRsp := rsp.MessageType() == messages.MsgTypeCreateSessionResponse
+
+	for _, ie := range rspIEs {
+		if ie == nil {
+			continue
+		}
+		switch ie.Type {
+		case ies.FullyQualifiedTEID:
+			if err := s.learnTEID(ie); err != nil {
+				return err
+			}
+		case ies.PDNAddressAllocation:
+			def := s.GetDefaultBearer()
+			if !isCSRsp || def == nil {
+				continue
+			}
+			ip, err := ie.IPAddress()
+			if err != nil {
+				return err
+			}
+			def.SetSubscriberIP(ip)
+		case ies.BearerContext:
+			// the instance 1 is the Bearer Contexts marked for removal.
+			if ie.Instance() != 0 || !isAccepted(ie.ChildIEs...) {
+				continue
+			}
+			if err := s.learnBearerTEIDs(ie, isCSRsp); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// learnTEID adds the F-TEID to Session.
+func (s *Session) learnTEID(fteid *ies.IE) error {
+	it, err := fteid.InterfaceType()
+	if err != nil {
+		return err
+	}
+	teid, err := fteid.TEID()
+	if err != nil {
+		return err
+	}
+	s.AddTEID(it, teid)
+	return nil
+}
+
+// learnBearerTEIDs updates the Bearer with the F-TEIDs in the Bearer Context.
+func (s *Session) learnBearerTEIDs(bc *ies.IE, isCSRsp bool) error {
+	var primary *ies.IE
+	for _, child := range bc.ChildIEs {
+		if child.Type != ies.FullyQualifiedTEID {
+			continue
+		}
+		if err := s.learnTEID(child); err != nil {
+			return err
+		}
+		if primary == nil || child.Instance() < primary.Instance() {
+			primary = child
+		}
+	}
+
+	ebi := ebiIn(bc)
+	br, err := s.LookupBearerByEBI(ebi)
+	if err != nil {
+		if !isCSRsp || ebi == 0 {
+			return nil
+		}
+		if br = s.GetDefaultBearer(); br == nil || br.GetEBI() != 0 {
+			return nil
+		}
+		br.SetEBI(ebi)
+	}
+	if primary == nil {
+		return nil
+	}
+
+	teid, err := primary.TEID()
+	if err != nil {
+		return err
+	}
+	ip, err := primary.IPAddress()
+	if err != nil {
+		return err
+	}
+
+	br.mu.Lock()
+	br.teidOut = teid
+	br.raddr = &net.UDPAddr{IP: net.ParseIP(ip), Port: GTPUPort}
+	br.mu.Unlock()
+
+	s.notifyBearer(br, BearerChangeUpdated)
+	return nil
+}
@@ -0,0 +1,46 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// SetParseLimits sets the limits to be applied to the incoming messages before
+// decoding them, which hardens Conn against the crafted signaling floods.
+//
+// The messages exceeding the limits are discarded, the error is passed to the
+// background error handling, and they are counted as LimitViolations in Stats.
+// No limit is applied by default, and giving nil removes the limits.
+func (c *Conn) SetParseLimits(l *messages.Limits) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if l == nil {
+		c.parseLimits = nil
+		return
+	}
+	limits := *l
+	c.parseLimits = &limits
+}
+
+func (c *Conn) limits() *messages.Limits {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.parseLimits
+}
+
+// isLimitError reports whether err is caused by exceeding messages.Limits.
+func isLimitError(err error) bool {
+	switch errors.Cause(err).(type) {
+	case *messages.MessageTooLongError, *messages.TooManyIEsError, *messages.IENestingTooDeepError:
+		return true
+	default:
+		return false
+	}
+}
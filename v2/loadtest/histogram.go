@@ -0,0 +1,158 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package loadtest
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultLatencyBuckets is the upper bounds of the buckets of Histogram used if none
+// is given in Config, which covers from the responses on the same host to the ones
+// after the retransmissions with the default T3-RESPONSE.
+var DefaultLatencyBuckets = []time.Duration{
+	time.Millisecond,
+	2 * time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	20 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	200 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2 * time.Second,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// Bucket is a bucket of Histogram, which counts the latencies up to UpperBound that
+// are larger than the UpperBound of the previous one. The last bucket has the
+// UpperBound of 0, which counts the ones larger than all the others.
+type Bucket struct {
+	UpperBound time.Duration
+	Count      uint64
+}
+
+// Histogram is the distribution of the latencies. It is safe for concurrent use.
+type Histogram struct {
+	mu       sync.Mutex
+	bounds   []time.Duration
+	counts   []uint64
+	count    uint64
+	sum      time.Duration
+	min, max time.Duration
+}
+
+// NewHistogram creates a new Histogram with the buckets of the upper bounds given.
+// DefaultLatencyBuckets is used if none is given.
+func NewHistogram(bounds ...time.Duration) *Histogram {
+	if len(bounds) == 0 {
+		bounds = DefaultLatencyBuckets
+	}
+	b := append([]time.Duration{}, bounds...)
+	sort.Slice(b, func(i, j int) bool { return b[i] < b[j] })
+	return &Histogram{bounds: b, counts: make([]uint64, len(b)+1)}
+}
+
+// Observe adds the latency d to Histogram.
+func (h *Histogram) Observe(d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	n := sort.Search(len(h.bounds), func(i int) bool { return d <= h.bounds[i] })
+	h.counts[n]++
+	if h.count == 0 || d < h.min {
+		h.min = d
+	}
+	if d > h.max {
+		h.max = d
+	}
+	h.count++
+	h.sum += d
+}
+
+// Count returns the number of latencies observed.
+func (h *Histogram) Count() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.count
+}
+
+// Min returns the smallest latency observed, or 0 if none is observed.
+func (h *Histogram) Min() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.min
+}
+
+// Max returns the largest latency observed, or 0 if none is observed.
+func (h *Histogram) Max() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.max
+}
+
+// Mean returns the average of the latencies observed, or 0 if none is observed.
+func (h *Histogram) Mean() time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.count == 0 {
+		return 0
+	}
+	return h.sum / time.Duration(h.count)
+}
+
+// Quantile returns the upper bound of the bucket that the q-quantile(0 to 1) of the
+// latencies falls in, e.g., 0.99 for the 99th percentile. It returns Max if it falls
+// in the last bucket, and 0 if none is observed.
+func (h *Histogram) Quantile(q float64) time.Duration {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.count == 0 {
+		return 0
+	}
+	if q < 0 {
+		q = 0
+	}
+	rank := uint64(q*float64(h.count) + 0.5)
+	if rank == 0 {
+		rank = 1
+	}
+
+	var cum uint64
+	for n, c := range h.counts {
+		cum += c
+		if cum < rank {
+			continue
+		}
+		if n == len(h.bounds) || h.bounds[n] > h.max {
+			return h.max
+		}
+		return h.bounds[n]
+	}
+	return h.max
+}
+
+// Buckets returns the buckets of Histogram.
+func (h *Histogram) Buckets() []Bucket {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	buckets := make([]Bucket, len(h.counts))
+	for n, c := range h.counts {
+		buckets[n].Count = c
+		if n < len(h.bounds) {
+			buckets[n].UpperBound = h.bounds[n]
+		}
+	}
+	return buckets
+}
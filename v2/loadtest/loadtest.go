@@ -0,0 +1,545 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package loadtest drives v2.Conn to create, modify and delete a number of Sessions
+// at a configurable rate against a peer, e.g., S-GW or P-GW, and reports the
+// latencies of the procedures with the failures classified.
+//
+// The requests are sent with (*Conn) SendRequestTo to be retransmitted following the
+// RetransmitPolicy, and the responses are correlated by the HandlerFuncs registered
+// by New, which should not be overwritten.
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/go-gtp/internal/exchange"
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// DefaultTimeout is the duration to wait for the response to each request, which
+// covers the retransmissions with the default RetransmitPolicy.
+const DefaultTimeout = 10 * time.Second
+
+// Procedure is the procedure performed for each Session.
+type Procedure uint8
+
+// Procedure definitions.
+const (
+	ProcedureCreateSession Procedure = iota
+	ProcedureModifyBearer
+	ProcedureDeleteSession
+)
+
+// String returns the name of Procedure.
+func (p Procedure) String() string {
+	switch p {
+	case ProcedureCreateSession:
+		return "Create Session"
+	case ProcedureModifyBearer:
+		return "Modify Bearer"
+	case ProcedureDeleteSession:
+		return "Delete Session"
+	default:
+		return fmt.Sprintf("Procedure(%d)", uint8(p))
+	}
+}
+
+// Failure is the class of the reason a procedure failed.
+type Failure uint8
+
+// Failure definitions.
+const (
+	// FailureTimeout is that no response is received within Timeout.
+	FailureTimeout Failure = iota
+	// FailureRejected is that the response has the Cause other than acceptance.
+	FailureRejected
+	// FailureSendError is that the request cannot be sent.
+	FailureSendError
+	// FailureInvalidResponse is that the response cannot be handled, e.g., a
+	// mandatory IE is missing.
+	FailureInvalidResponse
+	// FailureCanceled is that the context given to Run is done in the middle.
+	FailureCanceled
+)
+
+// String returns the name of Failure.
+func (f Failure) String() string {
+	switch f {
+	case FailureTimeout:
+		return "timeout"
+	case FailureRejected:
+		return "rejected"
+	case FailureSendError:
+		return "send error"
+	case FailureInvalidResponse:
+		return "invalid response"
+	case FailureCanceled:
+		return "canceled"
+	default:
+		return fmt.Sprintf("Failure(%d)", uint8(f))
+	}
+}
+
+// Config is the parameters of the load test.
+type Config struct {
+	// Sessions is the number of Sessions to be created.
+	Sessions int
+
+	// Rate is the number of Sessions started per second. All of them are started at
+	// once if 0.
+	Rate float64
+
+	// Concurrency is the maximum number of Sessions in the procedures at the same
+	// time, including HoldTime. It is not limited if 0.
+	Concurrency int
+
+	// Timeout is the duration to wait for the response to each request, including
+	// the retransmissions. DefaultTimeout is used if 0.
+	Timeout time.Duration
+
+	// Retransmit is the way the requests not responded are retransmitted. The default
+	// values of RetransmitPolicy is used if nil.
+	Retransmit *v2.RetransmitPolicy
+
+	// PeerIFType is the interface type of the peer's F-TEID in Session, whose TEID is
+	// used in the header of Modify Bearer and Delete Session Request, e.g.,
+	// IFTypeS11S4SGWGTPC to S-GW, or IFTypeS5S8PGWGTPC to P-GW.
+	PeerIFType uint8
+
+	// CreateSessionIEs returns the IEs in Create Session Request of the n-th Session,
+	// starting from 0. The IEs should contain a unique IMSI and the Sender F-TEID
+	// created with (*Conn) NewFTEID, as well as the others required by the peer.
+	// This is mandatory.
+	CreateSessionIEs func(n int) []*ies.IE
+
+	// ModifyBearerIEs returns the IEs in Modify Bearer Request sent after the n-th
+	// Session is created, e.g., the Bearer Context with the F-TEID of eNodeB. Modify
+	// Bearer is not performed if nil.
+	ModifyBearerIEs func(n int, sess *v2.Session) []*ies.IE
+
+	// HoldTime is the duration to keep each Session before deleting it.
+	HoldTime time.Duration
+
+	// KeepSessions disables Delete Session, which leaves the Sessions created on Conn
+	// and the peer.
+	KeepSessions bool
+
+	// LatencyBuckets is the upper bounds of the buckets of the latency histograms.
+	// DefaultLatencyBuckets is used if empty.
+	LatencyBuckets []time.Duration
+}
+
+// Runner performs the load test with Config against a peer.
+type Runner struct {
+	conn     *v2.Conn
+	peerAddr net.Addr
+	cfg      Config
+	calls    *exchange.Table
+}
+
+// New creates a new Runner that performs the load test with cfg against the peer at
+// peerAddr over conn, and registers the HandlerFuncs for the responses to conn.
+func New(conn *v2.Conn, peerAddr net.Addr, cfg *Config) (*Runner, error) {
+	if cfg == nil || cfg.CreateSessionIEs == nil {
+		return nil, errors.New("CreateSessionIEs is required in Config")
+	}
+	if cfg.Sessions <= 0 || cfg.Rate < 0 || cfg.Concurrency < 0 {
+		return nil, fmt.Errorf("invalid Config: Sessions=%d, Rate=%f, Concurrency=%d", cfg.Sessions, cfg.Rate, cfg.Concurrency)
+	}
+
+	r := &Runner{
+		conn:     conn,
+		peerAddr: peerAddr,
+		cfg:      *cfg,
+		calls:    exchange.New(),
+	}
+	if r.cfg.Timeout <= 0 {
+		r.cfg.Timeout = DefaultTimeout
+	}
+
+	r.calls.Handle(
+		conn,
+		messages.MsgTypeCreateSessionResponse,
+		messages.MsgTypeModifyBearerResponse,
+		messages.MsgTypeDeleteSessionResponse,
+	)
+	return r, nil
+}
+
+// Run performs the procedures of the Sessions, and returns Report when all of them
+// are done. If ctx is done in the middle, the Sessions not started yet are skipped
+// and the Report so far is returned with ctx.Err().
+func (r *Runner) Run(ctx context.Context) (*Report, error) {
+	report := newReport(r.cfg.LatencyBuckets)
+	before := r.conn.Stats().RequestsRetransmitted
+	start := time.Now()
+
+	var ticker *time.Ticker
+	if r.cfg.Rate > 0 {
+		ticker = time.NewTicker(time.Duration(float64(time.Second) / r.cfg.Rate))
+		defer ticker.Stop()
+	}
+	var sem chan struct{}
+	if r.cfg.Concurrency > 0 {
+		sem = make(chan struct{}, r.cfg.Concurrency)
+	}
+
+	var wg sync.WaitGroup
+	var err error
+loop:
+	for n := 0; n < r.cfg.Sessions; n++ {
+		if ticker != nil && n > 0 {
+			select {
+			case <-ctx.Done():
+				err = ctx.Err()
+				break loop
+			case <-ticker.C:
+			}
+		}
+		if sem != nil {
+			select {
+			case <-ctx.Done():
+				err = ctx.Err()
+				break loop
+			case sem <- struct{}{}:
+			}
+		}
+		if ctx.Err() != nil {
+			err = ctx.Err()
+			break
+		}
+
+		report.started()
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			if sem != nil {
+				defer func() { <-sem }()
+			}
+			if r.session(ctx, n, report) {
+				report.completed()
+			}
+		}(n)
+	}
+	wg.Wait()
+
+	report.Duration = time.Since(start)
+	report.Retransmissions = r.conn.Stats().RequestsRetransmitted - before
+	return report, err
+}
+
+// session performs the procedures of the n-th Session, and reports whether all of
+// them succeeded.
+func (r *Runner) session(ctx context.Context, n int, report *Report) bool {
+	sess, ok := r.createSession(ctx, n, report)
+	if !ok {
+		return false
+	}
+
+	if r.cfg.ModifyBearerIEs != nil {
+		if !r.modifyBearer(ctx, n, sess, report) {
+			r.release(ctx, sess, report)
+			return false
+		}
+	}
+
+	if r.cfg.HoldTime > 0 {
+		select {
+		case <-ctx.Done():
+		case <-time.After(r.cfg.HoldTime):
+		}
+	}
+	if r.cfg.KeepSessions {
+		return true
+	}
+	return r.deleteSession(ctx, sess, report)
+}
+
+// release deletes the Session that failed in the middle, unless KeepSessions is set.
+func (r *Runner) release(ctx context.Context, sess *v2.Session, report *Report) {
+	if r.cfg.KeepSessions {
+		return
+	}
+	r.deleteSession(ctx, sess, report)
+}
+
+// call sends req and waits for the response, and returns the response accepted with
+// the latency, or the Failure with the error.
+func (r *Runner) call(ctx context.Context, req messages.Message) (messages.Message, time.Duration, Failure, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	rsp, err := r.calls.CallWithRetransmission(ctx, r.conn, r.peerAddr, req, r.cfg.Retransmit)
+	if err != nil {
+		return nil, 0, classify(ctx, err), err
+	}
+	latency := time.Since(start)
+	if err := v2.CheckCause(rsp); err != nil {
+		return nil, 0, classify(ctx, err), err
+	}
+	return rsp, latency, 0, nil
+}
+
+func (r *Runner) createSession(ctx context.Context, n int, report *Report) (*v2.Session, bool) {
+	ie := r.cfg.CreateSessionIEs(n)
+	sess, err := v2.NewSessionFromIEs(r.peerAddr, ie...)
+	if err != nil {
+		report.failed(ProcedureCreateSession, FailureSendError, err)
+		return nil, false
+	}
+
+	// the Session is added before the response arrives, as the response with the
+	// TEID unknown to Conn is discarded.
+	r.conn.AddSession(sess)
+	rsp, latency, f, err := r.call(ctx, messages.NewCreateSessionRequest(0, 0, ie...))
+	if err != nil {
+		report.failed(ProcedureCreateSession, f, err)
+		r.conn.RemoveSession(sess)
+		return nil, false
+	}
+
+	if err := r.completeCreateSession(sess, rsp); err != nil {
+		// the Session is left on the peer, which is deleted if it is known.
+		report.failed(ProcedureCreateSession, FailureInvalidResponse, err)
+		if _, terr := sess.GetTEID(r.cfg.PeerIFType); terr == nil {
+			r.release(ctx, sess, report)
+		} else {
+			r.conn.RemoveSession(sess)
+		}
+		return nil, false
+	}
+	report.succeeded(ProcedureCreateSession, latency)
+	return sess, true
+}
+
+func (r *Runner) completeCreateSession(sess *v2.Session, rsp messages.Message) error {
+	csRsp, ok := rsp.(*messages.CreateSessionResponse)
+	if !ok {
+		return &v2.UnexpectedTypeError{Msg: rsp}
+	}
+	if ie := csRsp.SenderFTEIDC; ie != nil {
+		if err := exchange.AddFTEID(sess, ie); err != nil {
+			return err
+		}
+	}
+	for _, bc := range messages.FindIEs(csRsp, ies.BearerContext) {
+		for _, child := range bc.ChildIEs {
+			if child.Type != ies.FullyQualifiedTEID {
+				continue
+			}
+			if err := exchange.AddFTEID(sess, child); err != nil {
+				return err
+			}
+		}
+	}
+	if _, err := sess.GetTEID(r.cfg.PeerIFType); err != nil {
+		return err
+	}
+	return sess.Activate()
+}
+
+func (r *Runner) modifyBearer(ctx context.Context, n int, sess *v2.Session, report *Report) bool {
+	teid, err := sess.GetTEID(r.cfg.PeerIFType)
+	if err != nil {
+		report.failed(ProcedureModifyBearer, FailureSendError, err)
+		return false
+	}
+	req := messages.NewModifyBearerRequest(teid, 0, r.cfg.ModifyBearerIEs(n, sess)...)
+	_, latency, f, err := r.call(ctx, req)
+	return report.record(ProcedureModifyBearer, latency, f, err)
+}
+
+// deleteSession sends Delete Session Request with the EBI of the default Bearer as
+// the Linked EBI. The Session is removed from Conn regardless of the result, as the
+// load test is over for it.
+func (r *Runner) deleteSession(ctx context.Context, sess *v2.Session, report *Report) bool {
+	defer r.conn.RemoveSession(sess)
+
+	teid, err := sess.GetTEID(r.cfg.PeerIFType)
+	if err != nil {
+		report.failed(ProcedureDeleteSession, FailureSendError, err)
+		return false
+	}
+	var ie []*ies.IE
+	if br := sess.GetDefaultBearer(); br != nil && br.GetEBI() != 0 {
+		ie = append(ie, ies.NewEPSBearerID(br.GetEBI()))
+	}
+
+	// the Sessions are deleted even after ctx given to Run is done, not to leave
+	// them on the peer.
+	if ctx.Err() != nil {
+		ctx = context.Background()
+	}
+	_, latency, f, err := r.call(ctx, messages.NewDeleteSessionRequest(teid, 0, ie...))
+	return report.record(ProcedureDeleteSession, latency, f, err)
+}
+
+// classify returns the Failure of err returned in ctx.
+func classify(ctx context.Context, err error) Failure {
+	switch err.(type) {
+	case *v2.CauseError:
+		return FailureRejected
+	case *v2.RequiredIEMissingError, *v2.UnexpectedTypeError:
+		return FailureInvalidResponse
+	}
+
+	switch err {
+	case context.DeadlineExceeded:
+		return FailureTimeout
+	case context.Canceled:
+		return FailureCanceled
+	}
+	if ctx.Err() != nil {
+		return FailureTimeout
+	}
+	return FailureSendError
+}
+
+// ProcedureStats is the statistics of a Procedure.
+type ProcedureStats struct {
+	// Attempts is the number of requests sent, and Successes is the number of them
+	// that are accepted.
+	Attempts, Successes int
+	// Failures is the number of the failed ones, keyed by the class of the reason.
+	Failures map[Failure]int
+	// Causes is the number of the rejected ones, keyed by the Cause in the response.
+	Causes map[v2.Cause]int
+	// Latency is the distribution of the time from sending the request to receiving
+	// the response of the accepted ones.
+	Latency *Histogram
+}
+
+// Report is the result of the load test.
+type Report struct {
+	mu sync.Mutex
+
+	// Started is the number of Sessions started, and Completed is the number of them
+	// that all the procedures succeeded.
+	Started, Completed int
+	// Duration is the time taken to perform all the procedures.
+	Duration time.Duration
+	// Retransmissions is the number of requests retransmitted.
+	Retransmissions uint64
+	// Procedures is the statistics of each Procedure performed.
+	Procedures map[Procedure]*ProcedureStats
+	// Errors is the first errors of each Failure, which helps to find out the reason.
+	Errors map[Failure]error
+}
+
+func newReport(buckets []time.Duration) *Report {
+	r := &Report{Procedures: map[Procedure]*ProcedureStats{}, Errors: map[Failure]error{}}
+	for _, p := range []Procedure{ProcedureCreateSession, ProcedureModifyBearer, ProcedureDeleteSession} {
+		r.Procedures[p] = &ProcedureStats{
+			Failures: map[Failure]int{},
+			Causes:   map[v2.Cause]int{},
+			Latency:  NewHistogram(buckets...),
+		}
+	}
+	return r
+}
+
+func (r *Report) started() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Started++
+}
+
+func (r *Report) completed() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Completed++
+}
+
+func (r *Report) succeeded(proc Procedure, latency time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ps := r.Procedures[proc]
+	ps.Attempts++
+	ps.Successes++
+	ps.Latency.Observe(latency)
+}
+
+// record records the result of proc returned by (*Runner) call, and reports whether
+// it succeeded.
+func (r *Report) record(proc Procedure, latency time.Duration, f Failure, err error) bool {
+	if err != nil {
+		r.failed(proc, f, err)
+		return false
+	}
+	r.succeeded(proc, latency)
+	return true
+}
+
+func (r *Report) failed(proc Procedure, f Failure, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ps := r.Procedures[proc]
+	ps.Attempts++
+	ps.Failures[f]++
+	if ce, ok := err.(*v2.CauseError); ok {
+		ps.Causes[ce.Cause]++
+	}
+	if _, ok := r.Errors[f]; !ok {
+		r.Errors[f] = err
+	}
+}
+
+// String returns the summary of Report in human readable format.
+func (r *Report) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Sessions: %d started, %d completed in %s", r.Started, r.Completed, r.Duration)
+	if r.Duration > 0 {
+		fmt.Fprintf(&sb, " (%.1f/s)", float64(r.Completed)/r.Duration.Seconds())
+	}
+	fmt.Fprintf(&sb, ", Retransmissions: %d\n", r.Retransmissions)
+
+	for _, p := range []Procedure{ProcedureCreateSession, ProcedureModifyBearer, ProcedureDeleteSession} {
+		ps := r.Procedures[p]
+		if ps.Attempts == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "  %s: %d/%d succeeded", p, ps.Successes, ps.Attempts)
+		if l := ps.Latency; l.Count() > 0 {
+			fmt.Fprintf(&sb, ", latency min/avg/p50/p99/max = %s/%s/%s/%s/%s",
+				l.Min(), l.Mean(), l.Quantile(0.5), l.Quantile(0.99), l.Max(),
+			)
+		}
+		sb.WriteString("\n")
+
+		var failures []Failure
+		for f := range ps.Failures {
+			failures = append(failures, f)
+		}
+		sort.Slice(failures, func(i, j int) bool { return failures[i] < failures[j] })
+		for _, f := range failures {
+			fmt.Fprintf(&sb, "    %s: %d\n", f, ps.Failures[f])
+		}
+
+		var causes []v2.Cause
+		for c := range ps.Causes {
+			causes = append(causes, c)
+		}
+		sort.Slice(causes, func(i, j int) bool { return causes[i] < causes[j] })
+		for _, c := range causes {
+			fmt.Fprintf(&sb, "      %s(%d): %d\n", c, uint8(c), ps.Causes[c])
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,218 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package loadtest_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/loadtest"
+	"github.com/wmnsk/go-gtp/v2/messages"
+	"github.com/wmnsk/go-gtp/v2/pgw"
+)
+
+func TestRunner(t *testing.T) {
+	errCh := make(chan error, 16)
+	listen := func(addr string) *v2.Conn {
+		t.Helper()
+		laddr, err := net.ResolveUDPAddr("udp", addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn, err := v2.ListenAndServe(laddr, 0, errCh)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return conn
+	}
+
+	var (
+		sgwConn = listen("127.0.1.11:2123")
+		pgwConn = listen("127.0.1.12:2123")
+	)
+	defer func() {
+		sgwConn.Close()
+		pgwConn.Close()
+	}()
+
+	pgw.New(pgwConn, "127.0.1.12", &pgw.Policy{
+		// the Session with the IMSI ending with 3 is rejected.
+		AuthorizeSession: func(sess *v2.Session, msg *messages.CreateSessionRequest) uint8 {
+			if sess.IMSI[len(sess.IMSI)-1] == '3' {
+				return v2.CauseUserAuthenticationFailed
+			}
+			return v2.CauseRequestAccepted
+		},
+	})
+	pgwConn.HandleModifyBearerRequest(func(c *v2.Conn, senderAddr net.Addr, msg *messages.ModifyBearerRequest) error {
+		sess, err := c.GetSessionByTEID(msg.TEID(), senderAddr)
+		if err != nil {
+			return err
+		}
+		teid, err := sess.GetTEID(v2.IFTypeS5S8SGWGTPC)
+		if err != nil {
+			return err
+		}
+		return c.RespondTo(senderAddr, msg, messages.NewModifyBearerResponse(
+			teid, 0, ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+		))
+	})
+
+	r, err := loadtest.New(sgwConn, pgwConn.LocalAddr(), &loadtest.Config{
+		Sessions:    10,
+		Rate:        200,
+		Concurrency: 4,
+		Timeout:     2 * time.Second,
+		PeerIFType:  v2.IFTypeS5S8PGWGTPC,
+		CreateSessionIEs: func(n int) []*ies.IE {
+			return []*ies.IE{
+				ies.NewIMSI(fmt.Sprintf("12345123456789%d", n)),
+				sgwConn.NewFTEID(v2.IFTypeS5S8SGWGTPC, "127.0.1.11", ""),
+				ies.NewAccessPointName("some.apn.example"),
+				ies.NewRATType(v2.RATTypeEUTRAN),
+				ies.NewBearerContext(ies.NewEPSBearerID(5), ies.NewBearerQoS(1, 2, 1, 9, 0, 0, 0, 0)),
+			}
+		},
+		ModifyBearerIEs: func(n int, sess *v2.Session) []*ies.IE {
+			return []*ies.IE{ies.NewBearerContext(
+				ies.NewEPSBearerID(5), ies.NewFullyQualifiedTEID(v2.IFTypeS5S8SGWGTPU, uint32(n+1), "127.0.1.11", ""),
+			)}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	report, err := r.Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Started != 10 || report.Completed != 9 {
+		t.Errorf("unexpected Sessions: started %d, completed %d", report.Started, report.Completed)
+	}
+	cs := report.Procedures[loadtest.ProcedureCreateSession]
+	if cs.Attempts != 10 || cs.Successes != 9 || cs.Failures[loadtest.FailureRejected] != 1 {
+		t.Errorf("unexpected Create Session: %+v", cs)
+	}
+	if got := cs.Causes[v2.Cause(v2.CauseUserAuthenticationFailed)]; got != 1 {
+		t.Errorf("unexpected Causes: %v", cs.Causes)
+	}
+	if got := cs.Latency.Count(); got != 9 {
+		t.Errorf("unexpected number of latencies: %d", got)
+	}
+	for _, p := range []loadtest.Procedure{loadtest.ProcedureModifyBearer, loadtest.ProcedureDeleteSession} {
+		if ps := report.Procedures[p]; ps.Attempts != 9 || ps.Successes != 9 {
+			t.Errorf("unexpected %s: %+v, errors: %v", p, ps, report.Errors)
+		}
+	}
+	for _, conn := range []*v2.Conn{sgwConn, pgwConn} {
+		if n := conn.SessionCount(); n != 0 {
+			t.Errorf("Sessions remaining on %s: %d", conn.LocalAddr(), n)
+		}
+	}
+
+	select {
+	case err := <-errCh:
+		t.Error(err)
+	default:
+	}
+}
+
+func TestRunnerTimeout(t *testing.T) {
+	laddr := &net.UDPAddr{IP: net.IPv4(127, 0, 1, 13), Port: 2123}
+	conn, err := v2.ListenAndServe(laddr, 0, make(chan error, 16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// the peer that never responds.
+	peer, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 1, 14), Port: 2123})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peer.Close()
+
+	r, err := loadtest.New(conn, peer.LocalAddr(), &loadtest.Config{
+		Sessions:   2,
+		Timeout:    250 * time.Millisecond,
+		Retransmit: &v2.RetransmitPolicy{Interval: 100 * time.Millisecond, MaxRetransmissions: 1},
+		PeerIFType: v2.IFTypeS11S4SGWGTPC,
+		CreateSessionIEs: func(n int) []*ies.IE {
+			return []*ies.IE{
+				ies.NewIMSI(fmt.Sprintf("12345123456789%d", n)),
+				conn.NewFTEID(v2.IFTypeS11MMEGTPC, "127.0.1.13", ""),
+			}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := r.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs := report.Procedures[loadtest.ProcedureCreateSession]
+	if cs.Attempts != 2 || cs.Failures[loadtest.FailureTimeout] != 2 {
+		t.Errorf("unexpected Create Session: %+v", cs)
+	}
+	if report.Retransmissions != 2 {
+		t.Errorf("unexpected Retransmissions: %d", report.Retransmissions)
+	}
+	if n := conn.SessionCount(); n != 0 {
+		t.Errorf("Sessions remaining: %d", n)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	h := loadtest.NewHistogram(10*time.Millisecond, time.Millisecond, 100*time.Millisecond)
+	for _, d := range []time.Duration{
+		500 * time.Microsecond, 2 * time.Millisecond, 3 * time.Millisecond, 50 * time.Millisecond, time.Second,
+	} {
+		h.Observe(d)
+	}
+
+	if h.Count() != 5 || h.Min() != 500*time.Microsecond || h.Max() != time.Second {
+		t.Errorf("unexpected Histogram: count=%d, min=%s, max=%s", h.Count(), h.Min(), h.Max())
+	}
+	if got, want := h.Mean(), (1055500*time.Microsecond)/5; got != want {
+		t.Errorf("unexpected Mean: got %s, want %s", got, want)
+	}
+	for _, c := range []struct {
+		q    float64
+		want time.Duration
+	}{
+		{0, time.Millisecond},
+		{0.5, 10 * time.Millisecond},
+		{0.8, 100 * time.Millisecond},
+		{1, time.Second},
+	} {
+		if got := h.Quantile(c.q); got != c.want {
+			t.Errorf("unexpected Quantile(%v): got %s, want %s", c.q, got, c.want)
+		}
+	}
+
+	want := []loadtest.Bucket{
+		{time.Millisecond, 1}, {10 * time.Millisecond, 2}, {100 * time.Millisecond, 1}, {0, 1},
+	}
+	got := h.Buckets()
+	if len(got) != len(want) {
+		t.Fatalf("unexpected Buckets: %v", got)
+	}
+	for n := range want {
+		if got[n] != want[n] {
+			t.Errorf("unexpected Bucket #%d: got %v, want %v", n, got[n], want[n])
+		}
+	}
+}
@@ -5,10 +5,15 @@
 package v2
 
 import (
+	"fmt"
 	"io/ioutil"
 	"log"
+	"net"
 	"os"
+	"strings"
 	"sync"
+
+	"github.com/wmnsk/go-gtp/v2/messages"
 )
 
 var (
@@ -35,9 +40,6 @@ func SetLogger(l *log.Logger) {
 //
 // See also: SetLogger.
 func EnableLogging(l *log.Logger) {
-	logMu.Lock()
-	defer logMu.Unlock()
-
 	setLogger(l)
 }
 
@@ -66,3 +68,113 @@ func logf(format string, v ...interface{}) {
 
 	logger.Printf(format, v...)
 }
+
+// Logger is a structured logger with levels, which can be set per Conn with
+// (*Conn) SetLogger.
+//
+// keysAndValues are the alternating keys and values that give the context of the
+// event, e.g., "peer", "127.0.0.1:2123", "seq", 1. The events on messages always
+// have "msg_type", "peer", "teid" and "seq".
+type Logger interface {
+	Debug(msg string, keysAndValues ...interface{})
+	Info(msg string, keysAndValues ...interface{})
+	Warn(msg string, keysAndValues ...interface{})
+	Error(msg string, keysAndValues ...interface{})
+}
+
+// stdLogger is the Logger used by default, which writes to the *log.Logger
+// configured with SetLogger. The events at Debug level are discarded.
+type stdLogger struct{}
+
+func (stdLogger) Debug(msg string, keysAndValues ...interface{}) {}
+
+func (stdLogger) Info(msg string, keysAndValues ...interface{}) {
+	logf("[INFO] %s", formatKV(msg, keysAndValues))
+}
+
+func (stdLogger) Warn(msg string, keysAndValues ...interface{}) {
+	logf("[WARN] %s", formatKV(msg, keysAndValues))
+}
+
+func (stdLogger) Error(msg string, keysAndValues ...interface{}) {
+	logf("[ERROR] %s", formatKV(msg, keysAndValues))
+}
+
+func formatKV(msg string, keysAndValues []interface{}) string {
+	var b strings.Builder
+	b.WriteString(msg)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		if i+1 < len(keysAndValues) {
+			fmt.Fprintf(&b, " %v=%v", keysAndValues[i], keysAndValues[i+1])
+			continue
+		}
+		fmt.Fprintf(&b, " %v=(MISSING)", keysAndValues[i])
+	}
+	return b.String()
+}
+
+// SugaredLogger is the set of methods of *zap.SugaredLogger used by the Logger
+// returned from NewZapLogger.
+type SugaredLogger interface {
+	Debugw(msg string, keysAndValues ...interface{})
+	Infow(msg string, keysAndValues ...interface{})
+	Warnw(msg string, keysAndValues ...interface{})
+	Errorw(msg string, keysAndValues ...interface{})
+}
+
+type zapLogger struct {
+	l SugaredLogger
+}
+
+// NewZapLogger returns a Logger that writes to l, which is typically the
+// *zap.SugaredLogger retrieved by (*zap.Logger) Sugar.
+func NewZapLogger(l SugaredLogger) Logger {
+	return &zapLogger{l: l}
+}
+
+func (z *zapLogger) Debug(msg string, keysAndValues ...interface{}) {
+	z.l.Debugw(msg, keysAndValues...)
+}
+
+func (z *zapLogger) Info(msg string, keysAndValues ...interface{}) {
+	z.l.Infow(msg, keysAndValues...)
+}
+
+func (z *zapLogger) Warn(msg string, keysAndValues ...interface{}) {
+	z.l.Warnw(msg, keysAndValues...)
+}
+
+func (z *zapLogger) Error(msg string, keysAndValues ...interface{}) {
+	z.l.Errorw(msg, keysAndValues...)
+}
+
+// SetLogger sets the Logger to be used by Conn.
+//
+// By default, Conn writes the events at Info level or above to the *log.Logger
+// configured with the package-level SetLogger. Giving nil restores the default.
+func (c *Conn) SetLogger(l Logger) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.logger = l
+}
+
+func (c *Conn) log() Logger {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.logger == nil {
+		return stdLogger{}
+	}
+	return c.logger
+}
+
+// msgFields returns the keys and values that describe msg exchanged with peer.
+func msgFields(peer net.Addr, msg messages.Message, keysAndValues ...interface{}) []interface{} {
+	return append([]interface{}{
+		"msg_type", msg.MessageTypeName(),
+		"peer", peer.String(),
+		"teid", fmt.Sprintf("%#08x", msg.TEID()),
+		"seq", msg.Sequence(),
+	}, keysAndValues...)
+}
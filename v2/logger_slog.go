@@ -0,0 +1,35 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+//go:build go1.21
+// +build go1.21
+
+package v2
+
+import "log/slog"
+
+type slogLogger struct {
+	l *slog.Logger
+}
+
+// NewSlogLogger returns a Logger that writes to l.
+func NewSlogLogger(l *slog.Logger) Logger {
+	return &slogLogger{l: l}
+}
+
+func (s *slogLogger) Debug(msg string, keysAndValues ...interface{}) {
+	s.l.Debug(msg, keysAndValues...)
+}
+
+func (s *slogLogger) Info(msg string, keysAndValues ...interface{}) {
+	s.l.Info(msg, keysAndValues...)
+}
+
+func (s *slogLogger) Warn(msg string, keysAndValues ...interface{}) {
+	s.l.Warn(msg, keysAndValues...)
+}
+
+func (s *slogLogger) Error(msg string, keysAndValues ...interface{}) {
+	s.l.Error(msg, keysAndValues...)
+}
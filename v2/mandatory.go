@@ -0,0 +1,324 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// MandatoryIE represents an IE that must be present in a message, identified by
+// its type and instance.
+type MandatoryIE struct {
+	Type     uint8
+	Instance uint8
+}
+
+// MandatoryIERules is a set of MandatoryIEs per message type.
+//
+// The key is the type of message, and the value is the list of IEs that must be
+// present in the message of that type.
+type MandatoryIERules map[uint8][]MandatoryIE
+
+// DefaultMandatoryIERules returns the rules for the mandatory IEs defined in
+// TS 29.274 for the messages supported by this package.
+//
+// Only the IEs with presence requirement "M" are included. The returned value is
+// a newly allocated one, so the caller can customize it safely before passing
+// it to (*Conn) SetMandatoryIERules.
+func DefaultMandatoryIERules() MandatoryIERules {
+	return MandatoryIERules{
+		messages.MsgTypeEchoRequest:  {{ies.Recovery, 0}},
+		messages.MsgTypeEchoResponse: {{ies.Recovery, 0}},
+		messages.MsgTypeCreateSessionRequest: {
+			{ies.RATType, 0},
+			{ies.FullyQualifiedTEID, 0},
+			{ies.AccessPointName, 0},
+			{ies.BearerContext, 0},
+		},
+		messages.MsgTypeCreateSessionResponse:          {{ies.Cause, 0}},
+		messages.MsgTypeModifyBearerResponse:           {{ies.Cause, 0}},
+		messages.MsgTypeDeleteSessionResponse:          {{ies.Cause, 0}},
+		messages.MsgTypeChangeNotificationRequest:      {{ies.RATType, 0}},
+		messages.MsgTypeChangeNotificationResponse:     {{ies.Cause, 0}},
+		messages.MsgTypeModifyBearerCommand:            {{ies.AggregateMaximumBitRate, 0}, {ies.BearerContext, 0}},
+		messages.MsgTypeModifyBearerFailureIndication:  {{ies.Cause, 0}},
+		messages.MsgTypeDeleteBearerCommand:            {{ies.BearerContext, 0}},
+		messages.MsgTypeDeleteBearerFailureIndication:  {{ies.Cause, 0}},
+		messages.MsgTypeCreateBearerRequest:            {{ies.EPSBearerID, 0}, {ies.BearerContext, 0}},
+		messages.MsgTypeCreateBearerResponse:           {{ies.Cause, 0}, {ies.BearerContext, 0}},
+		messages.MsgTypeDeleteBearerResponse:           {{ies.Cause, 0}},
+		messages.MsgTypeDeletePDNConnectionSetResponse: {{ies.Cause, 0}},
+		messages.MsgTypeUpdateBearerRequest:            {{ies.BearerContext, 0}, {ies.AggregateMaximumBitRate, 0}},
+		messages.MsgTypeUpdateBearerResponse:           {{ies.Cause, 0}, {ies.BearerContext, 0}},
+		messages.MsgTypeBearerResourceCommand: {
+			{ies.EPSBearerID, 0},
+			{ies.ProcedureTransactionID, 0},
+			{ies.TrafficAggregateDescription, 0},
+		},
+		messages.MsgTypeBearerResourceFailureIndication: {
+			{ies.Cause, 0},
+			{ies.EPSBearerID, 0},
+			{ies.ProcedureTransactionID, 0},
+		},
+		messages.MsgTypeContextResponse:                            {{ies.Cause, 0}},
+		messages.MsgTypeContextAcknowledge:                         {{ies.Cause, 0}},
+		messages.MsgTypeIdentificationResponse:                     {{ies.Cause, 0}},
+		messages.MsgTypeForwardRelocationRequest:                   {{ies.FullyQualifiedTEID, 0}},
+		messages.MsgTypeForwardRelocationResponse:                  {{ies.Cause, 0}},
+		messages.MsgTypeForwardRelocationCompleteAcknowledge:       {{ies.Cause, 0}},
+		messages.MsgTypeCreateIndirectDataForwardingTunnelRequest:  {{ies.BearerContext, 0}},
+		messages.MsgTypeCreateIndirectDataForwardingTunnelResponse: {{ies.Cause, 0}},
+		messages.MsgTypeDeleteIndirectDataForwardingTunnelResponse: {{ies.Cause, 0}},
+		messages.MsgTypeReleaseAccessBearersResponse:               {{ies.Cause, 0}},
+		messages.MsgTypeModifyAccessBearersResponse:                {{ies.Cause, 0}},
+		messages.MsgTypeDownlinkDataNotificationAcknowledge:        {{ies.Cause, 0}},
+		messages.MsgTypeDownlinkDataNotificationFailureIndication:  {{ies.Cause, 0}},
+		messages.MsgTypeTraceSessionActivation:                     {{ies.TraceInformation, 0}},
+		messages.MsgTypeTraceSessionDeactivation:                   {{ies.TraceReference, 0}},
+		messages.MsgTypeSuspendAcknowledge:                         {{ies.Cause, 0}},
+		messages.MsgTypeResumeNotification:                         {{ies.IMSI, 0}},
+		messages.MsgTypeResumeAcknowledge:                          {{ies.Cause, 0}},
+		messages.MsgTypePGWRestartNotification:                     {{ies.IPAddress, 0}, {ies.IPAddress, 1}},
+		messages.MsgTypePGWRestartNotificationAcknowledge:          {{ies.Cause, 0}},
+		messages.MsgTypeMBMSSessionStartRequest: {
+			{ies.FullyQualifiedTEID, 0},
+			{ies.TMGI, 0},
+			{ies.MBMSSessionDuration, 0},
+			{ies.MBMSServiceArea, 0},
+			{ies.BearerQoS, 0},
+			{ies.MBMSIPMulticastDistribution, 0},
+		},
+		messages.MsgTypeMBMSSessionStartResponse:  {{ies.Cause, 0}},
+		messages.MsgTypeMBMSSessionUpdateRequest:  {{ies.TMGI, 0}, {ies.MBMSSessionDuration, 0}, {ies.BearerQoS, 0}},
+		messages.MsgTypeMBMSSessionUpdateResponse: {{ies.Cause, 0}},
+		messages.MsgTypeMBMSSessionStopResponse:   {{ies.Cause, 0}},
+	}
+}
+
+// SetMandatoryIERules sets the rules to be used to validate the presence of the
+// mandatory IEs in the incoming messages.
+//
+// When an incoming request lacks any of the mandatory IEs, Conn automatically
+// responds to it with Cause "Mandatory IE missing" that contains the type of the
+// offending IE, and the HandlerFunc for the message is not called.
+// When it is not a request, the message is just discarded.
+// In both cases, RequiredIEMissingError is passed to the background error handling.
+//
+// The validation of mandatory IEs is disabled by default, and giving nil disables it.
+// DefaultMandatoryIERules is a good starting point to build the rules.
+func (c *Conn) SetMandatoryIERules(rules MandatoryIERules) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if rules == nil {
+		c.mandatoryIERules = nil
+		return
+	}
+
+	c.mandatoryIERules = MandatoryIERules{}
+	for msgType, mandatoryIEs := range rules {
+		c.mandatoryIERules[msgType] = append([]MandatoryIE{}, mandatoryIEs...)
+	}
+}
+
+func (c *Conn) mandatoryIEsFor(msgType uint8) []MandatoryIE {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.mandatoryIERules[msgType]
+}
+
+// validateMandatoryIEs checks if the msg contains all the mandatory IEs, and
+// responds with Cause "Mandatory IE missing" on failure if msg is a request.
+func (c *Conn) validateMandatoryIEs(senderAddr net.Addr, msg messages.Message) error {
+	rules := c.mandatoryIEsFor(msg.MessageType())
+	if len(rules) == 0 {
+		return nil
+	}
+
+	present, err := presentIEs(msg)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range rules {
+		if _, ok := present[m]; ok {
+			continue
+		}
+
+		if res := c.newMandatoryIEMissingResponse(senderAddr, msg, present, m); res != nil {
+			if err := c.RespondTo(senderAddr, msg, res); err != nil {
+				return err
+			}
+		}
+		return &RequiredIEMissingError{Type: m.Type}
+	}
+
+	return nil
+}
+
+// presentIEs returns the top-level IEs in msg with the key of type and instance.
+func presentIEs(msg messages.Message) (map[MandatoryIE]*ies.IE, error) {
+	decodedIEs, err := messageIEs(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	present := map[MandatoryIE]*ies.IE{}
+	for _, i := range decodedIEs {
+		key := MandatoryIE{i.Type, i.Instance()}
+		if _, ok := present[key]; ok {
+			continue
+		}
+		present[key] = i
+	}
+	return present, nil
+}
+
+// messageIEs returns all the top-level IEs in msg in the order they are serialized.
+//
+// The IEs already held in the decoded msg are returned, and msg is serialized and
+// parsed again only if it has none of them, e.g., the Message implemented outside
+// messages package without the fields of IEs.
+func messageIEs(msg messages.Message) ([]*ies.IE, error) {
+	if decoded := messages.IEs(msg); decoded != nil {
+		return decoded, nil
+	}
+
+	b, err := messages.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	h, err := messages.ParseHeader(b)
+	if err != nil {
+		return nil, err
+	}
+
+	return ies.ParseMultiIEs(h.Payload)
+}
+
+// newMandatoryIEMissingResponse creates the response to req with Cause "Mandatory IE
+// missing". It returns nil if req is not a request that this package can respond to.
+func (c *Conn) newMandatoryIEMissingResponse(senderAddr net.Addr, req messages.Message, present map[MandatoryIE]*ies.IE, missing MandatoryIE) messages.Message {
+	cause := ies.NewCause(CauseMandatoryIEMissing, 0, 0, 0, ies.New(missing.Type, missing.Instance, nil))
+	return c.newRejectResponse(senderAddr, req, present, cause)
+}
+
+// newRejectResponse creates the response to req with the cause given. It returns nil
+// if req is not a request that this package can respond to.
+//
+// The TEID in the response is taken from Sender F-TEID for Control Plane(=F-TEID
+// with instance 0) in req if available. Otherwise it is the control plane TEID of
+// the peer in the Session that req is sent to, or left 0 as specified in TS 29.274
+// 5.5.2 if no Session is found.
+func (c *Conn) newRejectResponse(senderAddr net.Addr, req messages.Message, present map[MandatoryIE]*ies.IE, cause *ies.IE) messages.Message {
+	var teid uint32
+	if fteid, ok := present[MandatoryIE{ies.FullyQualifiedTEID, 0}]; ok {
+		teid = fteid.MustTEID()
+	} else if req.TEID() != 0 {
+		teid = c.peerCPlaneTEID(senderAddr, req.TEID())
+	}
+
+	switch req.MessageType() {
+	case messages.MsgTypeCreateSessionRequest:
+		return messages.NewCreateSessionResponse(teid, 0, cause)
+	case messages.MsgTypeModifyBearerRequest:
+		return messages.NewModifyBearerResponse(teid, 0, cause)
+	case messages.MsgTypeDeleteSessionRequest:
+		return messages.NewDeleteSessionResponse(teid, 0, cause)
+	case messages.MsgTypeChangeNotificationRequest:
+		return messages.NewChangeNotificationResponse(teid, 0, cause)
+	case messages.MsgTypeModifyBearerCommand:
+		return messages.NewModifyBearerFailureIndication(teid, 0, cause)
+	case messages.MsgTypeDeleteBearerCommand:
+		return messages.NewDeleteBearerFailureIndication(teid, 0, cause)
+	case messages.MsgTypeCreateBearerRequest:
+		return messages.NewCreateBearerResponse(teid, 0, cause)
+	case messages.MsgTypeDeleteBearerRequest:
+		return messages.NewDeleteBearerResponse(teid, 0, cause)
+	case messages.MsgTypeUpdateBearerRequest:
+		return messages.NewUpdateBearerResponse(teid, 0, cause)
+	case messages.MsgTypeBearerResourceCommand:
+		return messages.NewBearerResourceFailureIndication(teid, 0, cause)
+	case messages.MsgTypeContextRequest:
+		return messages.NewContextResponse(teid, 0, cause)
+	case messages.MsgTypeIdentificationRequest:
+		return messages.NewIdentificationResponse(teid, 0, cause)
+	case messages.MsgTypeForwardRelocationRequest:
+		return messages.NewForwardRelocationResponse(teid, 0, cause)
+	case messages.MsgTypeForwardRelocationCompleteNotification:
+		return messages.NewForwardRelocationCompleteAcknowledge(teid, 0, cause)
+	case messages.MsgTypeCreateIndirectDataForwardingTunnelRequest:
+		return messages.NewCreateIndirectDataForwardingTunnelResponse(teid, 0, cause)
+	case messages.MsgTypeReleaseAccessBearersRequest:
+		return messages.NewReleaseAccessBearersResponse(teid, 0, cause)
+	case messages.MsgTypeModifyAccessBearersRequest:
+		return messages.NewModifyAccessBearersResponse(teid, 0, cause)
+	case messages.MsgTypeDownlinkDataNotification:
+		return messages.NewDownlinkDataNotificationAcknowledge(teid, 0, cause)
+	case messages.MsgTypeSuspendNotification:
+		return messages.NewSuspendAcknowledge(teid, 0, cause)
+	case messages.MsgTypeResumeNotification:
+		return messages.NewResumeAcknowledge(teid, 0, cause)
+	case messages.MsgTypePGWRestartNotification:
+		return messages.NewPGWRestartNotificationAcknowledge(teid, 0, cause)
+	case messages.MsgTypeMBMSSessionStartRequest:
+		return messages.NewMBMSSessionStartResponse(teid, 0, cause)
+	case messages.MsgTypeMBMSSessionUpdateRequest:
+		return messages.NewMBMSSessionUpdateResponse(teid, 0, cause)
+	case messages.MsgTypeMBMSSessionStopRequest:
+		return messages.NewMBMSSessionStopResponse(teid, 0, cause)
+	default:
+		return nil
+	}
+}
+
+// peerCPlaneIFTypes is the list of the interface types of the peer's control plane
+// F-TEID on the same interface as the key.
+var peerCPlaneIFTypes = map[uint8][]uint8{
+	IFTypeS11MMEGTPC:   {IFTypeS11S4SGWGTPC},
+	IFTypeS11S4SGWGTPC: {IFTypeS11MMEGTPC, IFTypeS4SGSNGTPC},
+	IFTypeS4SGSNGTPC:   {IFTypeS11S4SGWGTPC},
+	IFTypeS5S8SGWGTPC:  {IFTypeS5S8PGWGTPC},
+	IFTypeS5S8PGWGTPC:  {IFTypeS5S8SGWGTPC},
+	IFTypeS3MMEGTPC:    {IFTypeS3SGSNGTPC},
+	IFTypeS3SGSNGTPC:   {IFTypeS3MMEGTPC},
+	IFTypeSmMMEGTPC:    {IFTypeSmMBMSGWGTPC},
+	IFTypeSmMBMSGWGTPC: {IFTypeSmMMEGTPC},
+	IFTypeSnSGSNGTPC:   {IFTypeSnMBMSGWGTPC},
+	IFTypeSnMBMSGWGTPC: {IFTypeSnSGSNGTPC},
+	IFTypeS2bePDGGTPC:  {IFTypeS2bPGWGTPC},
+	IFTypeS2bPGWGTPC:   {IFTypeS2bePDGGTPC},
+	IFTypeS2aTWANGTPC:  {IFTypeS2aPGWGTPC},
+	IFTypeS2aPGWGTPC:   {IFTypeS2aTWANGTPC},
+}
+
+// peerCPlaneTEID returns the control plane TEID of the peer at senderAddr in the
+// Session that has the local TEID given, or 0 if not found.
+func (c *Conn) peerCPlaneTEID(senderAddr net.Addr, local uint32) uint32 {
+	sess, err := c.GetSessionByTEID(local, senderAddr)
+	if err != nil {
+		return 0
+	}
+
+	var peer uint32
+	sess.teidMap.rangeWithFunc(func(i, t interface{}) bool {
+		if t.(uint32) != local {
+			return true
+		}
+		for _, ifType := range peerCPlaneIFTypes[i.(uint8)] {
+			if teid, err := sess.GetTEID(ifType); err == nil {
+				peer = teid
+				return false
+			}
+		}
+		return true
+	})
+	return peer
+}
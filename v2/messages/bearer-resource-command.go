@@ -0,0 +1,437 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import "github.com/wmnsk/go-gtp/v2/ies"
+
+// BearerResourceCommand is a BearerResourceCommand Header and its IEs above.
+type BearerResourceCommand struct {
+	*Header
+	LinkedEBI                         *ies.IE
+	EBI                               *ies.IE
+	PTI                               *ies.IE
+	FlowQoS                           *ies.IE
+	TAD                               *ies.IE
+	RATType                           *ies.IE
+	ServingNetwork                    *ies.IE
+	ULI                               *ies.IE
+	IndicationFlags                   *ies.IE
+	S4USGSNFTEID                      *ies.IE
+	S12RNCFTEID                       *ies.IE
+	SenderFTEIDC                      *ies.IE
+	PCO                               *ies.IE
+	SignallingPriorityIndication      *ies.IE
+	MMESGSNOverloadControlInformation *ies.IE
+	SGWOverloadControlInformation     *ies.IE
+	NBIFOMContainer                   *ies.IE
+	EPCO                              *ies.IE
+	PrivateExtension                  *ies.IE
+	AdditionalIEs                     []*ies.IE
+}
+
+// NewBearerResourceCommand creates a new BearerResourceCommand.
+func NewBearerResourceCommand(teid, seq uint32, ie ...*ies.IE) *BearerResourceCommand {
+	r := &BearerResourceCommand{
+		Header: NewHeader(
+			NewHeaderFlags(2, 0, 1),
+			MsgTypeBearerResourceCommand, teid, seq, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.EPSBearerID:
+			switch i.Instance() {
+			case 0:
+				r.LinkedEBI = i
+			case 1:
+				r.EBI = i
+			default:
+				r.AdditionalIEs = append(r.AdditionalIEs, i)
+			}
+		case ies.ProcedureTransactionID:
+			r.PTI = i
+		case ies.FlowQoS:
+			r.FlowQoS = i
+		case ies.TrafficAggregateDescription:
+			r.TAD = i
+		case ies.RATType:
+			r.RATType = i
+		case ies.ServingNetwork:
+			r.ServingNetwork = i
+		case ies.UserLocationInformation:
+			r.ULI = i
+		case ies.Indication:
+			r.IndicationFlags = i
+		case ies.FullyQualifiedTEID:
+			switch i.Instance() {
+			case 0:
+				r.S4USGSNFTEID = i
+			case 1:
+				r.S12RNCFTEID = i
+			case 2:
+				r.SenderFTEIDC = i
+			default:
+				r.AdditionalIEs = append(r.AdditionalIEs, i)
+			}
+		case ies.ProtocolConfigurationOptions:
+			r.PCO = i
+		case ies.SignallingPriorityIndication:
+			r.SignallingPriorityIndication = i
+		case ies.OverloadControlInformation:
+			switch i.Instance() {
+			case 0:
+				r.MMESGSNOverloadControlInformation = i
+			case 1:
+				r.SGWOverloadControlInformation = i
+			default:
+				r.AdditionalIEs = append(r.AdditionalIEs, i)
+			}
+		case ies.FContainer:
+			r.NBIFOMContainer = i
+		case ies.ExtendedProtocolConfigurationOptions:
+			r.EPCO = i
+		case ies.PrivateExtension:
+			if r.PrivateExtension == nil {
+				r.PrivateExtension = i
+			} else {
+				r.AdditionalIEs = append(r.AdditionalIEs, i)
+			}
+		default:
+			r.AdditionalIEs = append(r.AdditionalIEs, i)
+		}
+	}
+
+	r.SetLength()
+	return r
+}
+
+// Marshal serializes BearerResourceCommand into bytes.
+func (r *BearerResourceCommand) Marshal() ([]byte, error) {
+	b := make([]byte, r.MarshalLen())
+	if err := r.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MarshalTo serializes BearerResourceCommand into bytes.
+func (r *BearerResourceCommand) MarshalTo(b []byte) error {
+	if r.Header.Payload != nil {
+		r.Header.Payload = nil
+	}
+	r.Header.Payload = make([]byte, r.MarshalLen()-r.Header.MarshalLen())
+
+	offset := 0
+	if ie := r.LinkedEBI; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.EBI; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.PTI; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.FlowQoS; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.TAD; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.RATType; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.ServingNetwork; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.ULI; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.IndicationFlags; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.S4USGSNFTEID; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.S12RNCFTEID; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.SenderFTEIDC; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.PCO; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.SignallingPriorityIndication; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.MMESGSNOverloadControlInformation; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.SGWOverloadControlInformation; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.NBIFOMContainer; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.EPCO; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.PrivateExtension; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	for _, ie := range r.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(r.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	r.Header.SetLength()
+	return r.Header.MarshalTo(b)
+}
+
+// ParseBearerResourceCommand decodes given bytes as BearerResourceCommand.
+func ParseBearerResourceCommand(b []byte) (*BearerResourceCommand, error) {
+	r := &BearerResourceCommand{}
+	if err := r.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// UnmarshalBinary decodes given bytes as BearerResourceCommand.
+func (r *BearerResourceCommand) UnmarshalBinary(b []byte) error {
+	var err error
+	r.Header, err = ParseHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(r.Header.Payload) < 2 {
+		return nil
+	}
+
+	decodedIEs, err := ies.ParseMultiIEs(r.Header.Payload)
+	if err != nil {
+		return err
+	}
+	for _, i := range decodedIEs {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.EPSBearerID:
+			switch i.Instance() {
+			case 0:
+				r.LinkedEBI = i
+			case 1:
+				r.EBI = i
+			default:
+				r.AdditionalIEs = append(r.AdditionalIEs, i)
+			}
+		case ies.ProcedureTransactionID:
+			r.PTI = i
+		case ies.FlowQoS:
+			r.FlowQoS = i
+		case ies.TrafficAggregateDescription:
+			r.TAD = i
+		case ies.RATType:
+			r.RATType = i
+		case ies.ServingNetwork:
+			r.ServingNetwork = i
+		case ies.UserLocationInformation:
+			r.ULI = i
+		case ies.Indication:
+			r.IndicationFlags = i
+		case ies.FullyQualifiedTEID:
+			switch i.Instance() {
+			case 0:
+				r.S4USGSNFTEID = i
+			case 1:
+				r.S12RNCFTEID = i
+			case 2:
+				r.SenderFTEIDC = i
+			default:
+				r.AdditionalIEs = append(r.AdditionalIEs, i)
+			}
+		case ies.ProtocolConfigurationOptions:
+			r.PCO = i
+		case ies.SignallingPriorityIndication:
+			r.SignallingPriorityIndication = i
+		case ies.OverloadControlInformation:
+			switch i.Instance() {
+			case 0:
+				r.MMESGSNOverloadControlInformation = i
+			case 1:
+				r.SGWOverloadControlInformation = i
+			default:
+				r.AdditionalIEs = append(r.AdditionalIEs, i)
+			}
+		case ies.FContainer:
+			r.NBIFOMContainer = i
+		case ies.ExtendedProtocolConfigurationOptions:
+			r.EPCO = i
+		case ies.PrivateExtension:
+			if r.PrivateExtension == nil {
+				r.PrivateExtension = i
+			} else {
+				r.AdditionalIEs = append(r.AdditionalIEs, i)
+			}
+		default:
+			r.AdditionalIEs = append(r.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length in int.
+func (r *BearerResourceCommand) MarshalLen() int {
+	l := r.Header.MarshalLen() - len(r.Header.Payload)
+	if ie := r.LinkedEBI; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.EBI; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.PTI; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.FlowQoS; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.TAD; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.RATType; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.ServingNetwork; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.ULI; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.IndicationFlags; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.S4USGSNFTEID; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.S12RNCFTEID; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.SenderFTEIDC; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.PCO; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.SignallingPriorityIndication; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.MMESGSNOverloadControlInformation; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.SGWOverloadControlInformation; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.NBIFOMContainer; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.EPCO; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.PrivateExtension; ie != nil {
+		l += ie.MarshalLen()
+	}
+
+	for _, ie := range r.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (r *BearerResourceCommand) SetLength() {
+	r.Header.Length = uint16(r.MarshalLen() - 4)
+}
+
+// MessageTypeName returns the name of protocol.
+func (r *BearerResourceCommand) MessageTypeName() string {
+	return "Bearer Resource Command"
+}
+
+// TEID returns the TEID in uint32.
+func (r *BearerResourceCommand) TEID() uint32 {
+	return r.Header.teid()
+}
@@ -0,0 +1,46 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+	"github.com/wmnsk/go-gtp/v2/testutils"
+)
+
+func TestBearerResourceCommand(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "Normal",
+			Structured: messages.NewBearerResourceCommand(
+				testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+				ies.NewEPSBearerID(0x05),
+				ies.NewProcedureTransactionID(0x01),
+				ies.NewEPSBearerID(0x06).WithInstance(1),
+			),
+			Serialized: []byte{
+				// Header
+				0x48, 0x44, 0x00, 0x17, 0x11, 0x22, 0x33, 0x44, 0x00, 0x00, 0x01, 0x00,
+				// Linked EBI
+				0x49, 0x00, 0x01, 0x00, 0x05,
+				// EBI
+				0x49, 0x00, 0x01, 0x01, 0x06,
+				// PTI
+				0x64, 0x00, 0x01, 0x00, 0x01,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializable, error) {
+		v, err := messages.ParseBearerResourceCommand(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
@@ -0,0 +1,47 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+	"github.com/wmnsk/go-gtp/v2/testutils"
+)
+
+func TestBearerResourceFailureIndication(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "Normal",
+			Structured: messages.NewBearerResourceFailureIndication(
+				testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+				ies.NewCause(v2.CauseServiceDenied, 0, 0, 0, nil),
+				ies.NewEPSBearerID(0x05),
+				ies.NewProcedureTransactionID(0x01),
+			),
+			Serialized: []byte{
+				// Header
+				0x48, 0x45, 0x00, 0x18, 0x11, 0x22, 0x33, 0x44, 0x00, 0x00, 0x01, 0x00,
+				// Cause
+				0x02, 0x00, 0x02, 0x00, 0x59, 0x00,
+				// Linked EBI
+				0x49, 0x00, 0x01, 0x00, 0x05,
+				// PTI
+				0x64, 0x00, 0x01, 0x00, 0x01,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializable, error) {
+		v, err := messages.ParseBearerResourceFailureIndication(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
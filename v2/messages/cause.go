@@ -0,0 +1,19 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import "github.com/wmnsk/go-gtp/v2/ies"
+
+// CauseFrom returns the Cause value in the Cause IE at the top level of msg, which is
+// the one with instance 0.
+//
+// It returns ies.ErrIENotFound if msg has no Cause IE.
+func CauseFrom(msg Message) (uint8, error) {
+	i, err := FindIE(msg, ies.Cause, 0)
+	if err != nil {
+		return 0, err
+	}
+	return i.Cause()
+}
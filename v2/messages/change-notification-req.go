@@ -0,0 +1,319 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import "github.com/wmnsk/go-gtp/v2/ies"
+
+// ChangeNotificationRequest is a ChangeNotificationRequest Header and its IEs above.
+type ChangeNotificationRequest struct {
+	*Header
+	IMSI                             *ies.IE
+	MEI                              *ies.IE
+	IndicationFlags                  *ies.IE
+	RATType                          *ies.IE
+	UserLocationInformation          *ies.IE
+	UCI                              *ies.IE
+	PGWS5S8IPAddressForControlPlane  *ies.IE
+	LinkedEBI                        *ies.IE
+	PresenceReportingAreaInformation *ies.IE
+	MOExceptionDataCounter           *ies.IE
+	SecondaryRATUsageDataReport      *ies.IE
+	PrivateExtension                 *ies.IE
+	AdditionalIEs                    []*ies.IE
+}
+
+// NewChangeNotificationRequest creates a new ChangeNotificationRequest.
+func NewChangeNotificationRequest(teid, seq uint32, ie ...*ies.IE) *ChangeNotificationRequest {
+	c := &ChangeNotificationRequest{
+		Header: NewHeader(
+			NewHeaderFlags(2, 0, 1),
+			MsgTypeChangeNotificationRequest, teid, seq, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.IMSI:
+			c.IMSI = i
+		case ies.MobileEquipmentIdentity:
+			c.MEI = i
+		case ies.Indication:
+			c.IndicationFlags = i
+		case ies.RATType:
+			c.RATType = i
+		case ies.UserLocationInformation:
+			c.UserLocationInformation = i
+		case ies.UserCSGInformation:
+			c.UCI = i
+		case ies.IPAddress:
+			switch i.Instance() {
+			case 0:
+				c.PGWS5S8IPAddressForControlPlane = i
+			default:
+				c.AdditionalIEs = append(c.AdditionalIEs, i)
+			}
+		case ies.EPSBearerID:
+			c.LinkedEBI = i
+		case ies.PresenceReportingAreaInformation:
+			c.PresenceReportingAreaInformation = i
+		case ies.Counter:
+			c.MOExceptionDataCounter = i
+		case ies.SecondaryRATUsageDataReport:
+			c.SecondaryRATUsageDataReport = i
+		case ies.PrivateExtension:
+			if c.PrivateExtension == nil {
+				c.PrivateExtension = i
+			} else {
+				c.AdditionalIEs = append(c.AdditionalIEs, i)
+			}
+		default:
+			c.AdditionalIEs = append(c.AdditionalIEs, i)
+		}
+	}
+
+	c.SetLength()
+	return c
+}
+
+// Marshal serializes ChangeNotificationRequest into bytes.
+func (c *ChangeNotificationRequest) Marshal() ([]byte, error) {
+	b := make([]byte, c.MarshalLen())
+	if err := c.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MarshalTo serializes ChangeNotificationRequest into bytes.
+func (c *ChangeNotificationRequest) MarshalTo(b []byte) error {
+	if c.Header.Payload != nil {
+		c.Header.Payload = nil
+	}
+	c.Header.Payload = make([]byte, c.MarshalLen()-c.Header.MarshalLen())
+
+	offset := 0
+	if ie := c.IMSI; ie != nil {
+		if err := ie.MarshalTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := c.MEI; ie != nil {
+		if err := ie.MarshalTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := c.IndicationFlags; ie != nil {
+		if err := ie.MarshalTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := c.RATType; ie != nil {
+		if err := ie.MarshalTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := c.UserLocationInformation; ie != nil {
+		if err := ie.MarshalTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := c.UCI; ie != nil {
+		if err := ie.MarshalTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := c.PGWS5S8IPAddressForControlPlane; ie != nil {
+		if err := ie.MarshalTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := c.LinkedEBI; ie != nil {
+		if err := ie.MarshalTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := c.PresenceReportingAreaInformation; ie != nil {
+		if err := ie.MarshalTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := c.MOExceptionDataCounter; ie != nil {
+		if err := ie.MarshalTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := c.SecondaryRATUsageDataReport; ie != nil {
+		if err := ie.MarshalTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := c.PrivateExtension; ie != nil {
+		if err := ie.MarshalTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	for _, ie := range c.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(c.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	c.Header.SetLength()
+	return c.Header.MarshalTo(b)
+}
+
+// ParseChangeNotificationRequest decodes given bytes as ChangeNotificationRequest.
+func ParseChangeNotificationRequest(b []byte) (*ChangeNotificationRequest, error) {
+	c := &ChangeNotificationRequest{}
+	if err := c.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// UnmarshalBinary decodes given bytes as ChangeNotificationRequest.
+func (c *ChangeNotificationRequest) UnmarshalBinary(b []byte) error {
+	var err error
+	c.Header, err = ParseHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(c.Header.Payload) < 2 {
+		return nil
+	}
+
+	decodedIEs, err := ies.ParseMultiIEs(c.Header.Payload)
+	if err != nil {
+		return err
+	}
+	for _, i := range decodedIEs {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.IMSI:
+			c.IMSI = i
+		case ies.MobileEquipmentIdentity:
+			c.MEI = i
+		case ies.Indication:
+			c.IndicationFlags = i
+		case ies.RATType:
+			c.RATType = i
+		case ies.UserLocationInformation:
+			c.UserLocationInformation = i
+		case ies.UserCSGInformation:
+			c.UCI = i
+		case ies.IPAddress:
+			switch i.Instance() {
+			case 0:
+				c.PGWS5S8IPAddressForControlPlane = i
+			default:
+				c.AdditionalIEs = append(c.AdditionalIEs, i)
+			}
+		case ies.EPSBearerID:
+			c.LinkedEBI = i
+		case ies.PresenceReportingAreaInformation:
+			c.PresenceReportingAreaInformation = i
+		case ies.Counter:
+			c.MOExceptionDataCounter = i
+		case ies.SecondaryRATUsageDataReport:
+			c.SecondaryRATUsageDataReport = i
+		case ies.PrivateExtension:
+			if c.PrivateExtension == nil {
+				c.PrivateExtension = i
+			} else {
+				c.AdditionalIEs = append(c.AdditionalIEs, i)
+			}
+		default:
+			c.AdditionalIEs = append(c.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length in int.
+func (c *ChangeNotificationRequest) MarshalLen() int {
+	l := c.Header.MarshalLen() - len(c.Header.Payload)
+	if ie := c.IMSI; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := c.MEI; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := c.IndicationFlags; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := c.RATType; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := c.UserLocationInformation; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := c.UCI; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := c.PGWS5S8IPAddressForControlPlane; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := c.LinkedEBI; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := c.PresenceReportingAreaInformation; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := c.MOExceptionDataCounter; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := c.SecondaryRATUsageDataReport; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := c.PrivateExtension; ie != nil {
+		l += ie.MarshalLen()
+	}
+
+	for _, ie := range c.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (c *ChangeNotificationRequest) SetLength() {
+	c.Header.Length = uint16(c.MarshalLen() - 4)
+}
+
+// MessageTypeName returns the name of protocol.
+func (c *ChangeNotificationRequest) MessageTypeName() string {
+	return "Change Notification Request"
+}
+
+// TEID returns the TEID in uint32.
+func (c *ChangeNotificationRequest) TEID() uint32 {
+	return c.Header.teid()
+}
@@ -0,0 +1,50 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+	"github.com/wmnsk/go-gtp/v2/testutils"
+)
+
+func TestChangeNotificationRequest(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "Normal",
+			Structured: messages.NewChangeNotificationRequest(
+				testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+				ies.NewIMSI("123451234567890"),
+				ies.NewRATType(v2.RATTypeEUTRAN),
+				ies.NewUserLocationInformationLazy("123", "45", -1, -1, -1, -1, 0x0001, 0x00000101, -1, -1),
+				ies.NewEPSBearerID(0x05),
+			),
+			Serialized: []byte{
+				// Header
+				0x48, 0x26, 0x00, 0x2f, 0x11, 0x22, 0x33, 0x44, 0x00, 0x00, 0x01, 0x00,
+				// IMSI
+				0x01, 0x00, 0x08, 0x00, 0x21, 0x43, 0x15, 0x32, 0x54, 0x76, 0x98, 0xf0,
+				// RAT Type
+				0x52, 0x00, 0x01, 0x00, 0x06,
+				// User Location Information
+				0x56, 0x00, 0x0d, 0x00, 0x18, 0x21, 0xf3, 0x54, 0x00, 0x01, 0x21, 0xf3, 0x54, 0x00, 0x00, 0x01, 0x01,
+				// LBI
+				0x49, 0x00, 0x01, 0x00, 0x05,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializable, error) {
+		v, err := messages.ParseChangeNotificationRequest(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
@@ -0,0 +1,239 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import "github.com/wmnsk/go-gtp/v2/ies"
+
+// ChangeNotificationResponse is a ChangeNotificationResponse Header and its IEs above.
+type ChangeNotificationResponse struct {
+	*Header
+	IMSI                          *ies.IE
+	MEI                           *ies.IE
+	Cause                         *ies.IE
+	ChangeReportingAction         *ies.IE
+	CSGInformationReportingAction *ies.IE
+	PresenceReportingAreaAction   *ies.IE
+	PrivateExtension              *ies.IE
+	AdditionalIEs                 []*ies.IE
+}
+
+// NewChangeNotificationResponse creates a new ChangeNotificationResponse.
+func NewChangeNotificationResponse(teid, seq uint32, ie ...*ies.IE) *ChangeNotificationResponse {
+	c := &ChangeNotificationResponse{
+		Header: NewHeader(
+			NewHeaderFlags(2, 0, 1),
+			MsgTypeChangeNotificationResponse, teid, seq, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.IMSI:
+			c.IMSI = i
+		case ies.MobileEquipmentIdentity:
+			c.MEI = i
+		case ies.Cause:
+			c.Cause = i
+		case ies.ChangeReportingAction:
+			c.ChangeReportingAction = i
+		case ies.CSGInformationReportingAction:
+			c.CSGInformationReportingAction = i
+		case ies.PresenceReportingAreaAction:
+			c.PresenceReportingAreaAction = i
+		case ies.PrivateExtension:
+			if c.PrivateExtension == nil {
+				c.PrivateExtension = i
+			} else {
+				c.AdditionalIEs = append(c.AdditionalIEs, i)
+			}
+		default:
+			c.AdditionalIEs = append(c.AdditionalIEs, i)
+		}
+	}
+
+	c.SetLength()
+	return c
+}
+
+// Marshal serializes ChangeNotificationResponse into bytes.
+func (c *ChangeNotificationResponse) Marshal() ([]byte, error) {
+	b := make([]byte, c.MarshalLen())
+	if err := c.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MarshalTo serializes ChangeNotificationResponse into bytes.
+func (c *ChangeNotificationResponse) MarshalTo(b []byte) error {
+	if c.Header.Payload != nil {
+		c.Header.Payload = nil
+	}
+	c.Header.Payload = make([]byte, c.MarshalLen()-c.Header.MarshalLen())
+
+	offset := 0
+	if ie := c.IMSI; ie != nil {
+		if err := ie.MarshalTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := c.MEI; ie != nil {
+		if err := ie.MarshalTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := c.Cause; ie != nil {
+		if err := ie.MarshalTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := c.ChangeReportingAction; ie != nil {
+		if err := ie.MarshalTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := c.CSGInformationReportingAction; ie != nil {
+		if err := ie.MarshalTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := c.PresenceReportingAreaAction; ie != nil {
+		if err := ie.MarshalTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := c.PrivateExtension; ie != nil {
+		if err := ie.MarshalTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	for _, ie := range c.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(c.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	c.Header.SetLength()
+	return c.Header.MarshalTo(b)
+}
+
+// ParseChangeNotificationResponse decodes given bytes as ChangeNotificationResponse.
+func ParseChangeNotificationResponse(b []byte) (*ChangeNotificationResponse, error) {
+	c := &ChangeNotificationResponse{}
+	if err := c.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// UnmarshalBinary decodes given bytes as ChangeNotificationResponse.
+func (c *ChangeNotificationResponse) UnmarshalBinary(b []byte) error {
+	var err error
+	c.Header, err = ParseHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(c.Header.Payload) < 2 {
+		return nil
+	}
+
+	decodedIEs, err := ies.ParseMultiIEs(c.Header.Payload)
+	if err != nil {
+		return err
+	}
+	for _, i := range decodedIEs {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.IMSI:
+			c.IMSI = i
+		case ies.MobileEquipmentIdentity:
+			c.MEI = i
+		case ies.Cause:
+			c.Cause = i
+		case ies.ChangeReportingAction:
+			c.ChangeReportingAction = i
+		case ies.CSGInformationReportingAction:
+			c.CSGInformationReportingAction = i
+		case ies.PresenceReportingAreaAction:
+			c.PresenceReportingAreaAction = i
+		case ies.PrivateExtension:
+			if c.PrivateExtension == nil {
+				c.PrivateExtension = i
+			} else {
+				c.AdditionalIEs = append(c.AdditionalIEs, i)
+			}
+		default:
+			c.AdditionalIEs = append(c.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length in int.
+func (c *ChangeNotificationResponse) MarshalLen() int {
+	l := c.Header.MarshalLen() - len(c.Header.Payload)
+	if ie := c.IMSI; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := c.MEI; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := c.Cause; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := c.ChangeReportingAction; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := c.CSGInformationReportingAction; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := c.PresenceReportingAreaAction; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := c.PrivateExtension; ie != nil {
+		l += ie.MarshalLen()
+	}
+
+	for _, ie := range c.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (c *ChangeNotificationResponse) SetLength() {
+	c.Header.Length = uint16(c.MarshalLen() - 4)
+}
+
+// MessageTypeName returns the name of protocol.
+func (c *ChangeNotificationResponse) MessageTypeName() string {
+	return "Change Notification Response"
+}
+
+// TEID returns the TEID in uint32.
+func (c *ChangeNotificationResponse) TEID() uint32 {
+	return c.Header.teid()
+}
@@ -0,0 +1,47 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+	"github.com/wmnsk/go-gtp/v2/testutils"
+)
+
+func TestChangeNotificationResponse(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "Normal",
+			Structured: messages.NewChangeNotificationResponse(
+				testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+				ies.NewIMSI("123451234567890"),
+				ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+				ies.New(ies.ChangeReportingAction, 0x00, []byte{0x00}),
+			),
+			Serialized: []byte{
+				// Header
+				0x48, 0x27, 0x00, 0x1f, 0x11, 0x22, 0x33, 0x44, 0x00, 0x00, 0x01, 0x00,
+				// IMSI
+				0x01, 0x00, 0x08, 0x00, 0x21, 0x43, 0x15, 0x32, 0x54, 0x76, 0x98, 0xf0,
+				// Cause
+				0x02, 0x00, 0x02, 0x00, 0x10, 0x00,
+				// Change Reporting Action
+				0x83, 0x00, 0x01, 0x00, 0x00,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializable, error) {
+		v, err := messages.ParseChangeNotificationResponse(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
@@ -64,7 +64,11 @@ func NewContextAcknowledge(teid, seq uint32, ie ...*ies.IE) *ContextAcknowledge
 				c.AdditionalIEs = append(c.AdditionalIEs, i)
 			}
 		case ies.PrivateExtension:
-			c.PrivateExtension = i
+			if c.PrivateExtension == nil {
+				c.PrivateExtension = i
+			} else {
+				c.AdditionalIEs = append(c.AdditionalIEs, i)
+			}
 		default:
 			c.AdditionalIEs = append(c.AdditionalIEs, i)
 		}
@@ -216,7 +220,11 @@ func (c *ContextAcknowledge) UnmarshalBinary(b []byte) error {
 				c.AdditionalIEs = append(c.AdditionalIEs, i)
 			}
 		case ies.PrivateExtension:
-			c.PrivateExtension = i
+			if c.PrivateExtension == nil {
+				c.PrivateExtension = i
+			} else {
+				c.AdditionalIEs = append(c.AdditionalIEs, i)
+			}
 		default:
 			c.AdditionalIEs = append(c.AdditionalIEs, i)
 		}
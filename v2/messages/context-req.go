@@ -97,7 +97,11 @@ func NewContextRequest(teid, seq uint32, ie ...*ies.IE) *ContextRequest {
 		case ies.CIoTOptimizationsSupportIndication:
 			c.CIoTOptimizationsSupportIndication = i
 		case ies.PrivateExtension:
-			c.PrivateExtension = i
+			if c.PrivateExtension == nil {
+				c.PrivateExtension = i
+			} else {
+				c.AdditionalIEs = append(c.AdditionalIEs, i)
+			}
 		default:
 			c.AdditionalIEs = append(c.AdditionalIEs, i)
 		}
@@ -337,7 +341,11 @@ func (c *ContextRequest) UnmarshalBinary(b []byte) error {
 		case ies.CIoTOptimizationsSupportIndication:
 			c.CIoTOptimizationsSupportIndication = i
 		case ies.PrivateExtension:
-			c.PrivateExtension = i
+			if c.PrivateExtension == nil {
+				c.PrivateExtension = i
+			} else {
+				c.AdditionalIEs = append(c.AdditionalIEs, i)
+			}
 		default:
 			c.AdditionalIEs = append(c.AdditionalIEs, i)
 		}
@@ -64,7 +64,7 @@ func NewContextResponse(teid, seq uint32, ie ...*ies.IE) *ContextResponse {
 			ies.MMContextGSMKeyAndTriplets, ies.MMContextGSMKeyUsedCipherAndQuintuplets,
 			ies.MMContextUMTSKeyAndQuintuplets, ies.MMContextUMTSKeyQuadrupletsAndQuintuplets,
 			ies.MMContextUMTSKeyUsedCipherAndQuintuplets:
-			if c.UEMMContext != nil {
+			if c.UEMMContext == nil {
 				c.UEMMContext = i
 			} else {
 				c.AdditionalIEs = append(c.AdditionalIEs, i)
@@ -143,7 +143,11 @@ func NewContextResponse(teid, seq uint32, ie ...*ies.IE) *ContextResponse {
 		case ies.ExtendedTraceInformation:
 			c.ExtendedTraceInformation = i
 		case ies.PrivateExtension:
-			c.PrivateExtension = i
+			if c.PrivateExtension == nil {
+				c.PrivateExtension = i
+			} else {
+				c.AdditionalIEs = append(c.AdditionalIEs, i)
+			}
 		default:
 			c.AdditionalIEs = append(c.AdditionalIEs, i)
 		}
@@ -390,7 +394,7 @@ func (c *ContextResponse) UnmarshalBinary(b []byte) error {
 			ies.MMContextGSMKeyAndTriplets, ies.MMContextGSMKeyUsedCipherAndQuintuplets,
 			ies.MMContextUMTSKeyAndQuintuplets, ies.MMContextUMTSKeyQuadrupletsAndQuintuplets,
 			ies.MMContextUMTSKeyUsedCipherAndQuintuplets:
-			if c.UEMMContext != nil {
+			if c.UEMMContext == nil {
 				c.UEMMContext = i
 			} else {
 				c.AdditionalIEs = append(c.AdditionalIEs, i)
@@ -469,7 +473,11 @@ func (c *ContextResponse) UnmarshalBinary(b []byte) error {
 		case ies.ExtendedTraceInformation:
 			c.ExtendedTraceInformation = i
 		case ies.PrivateExtension:
-			c.PrivateExtension = i
+			if c.PrivateExtension == nil {
+				c.PrivateExtension = i
+			} else {
+				c.AdditionalIEs = append(c.AdditionalIEs, i)
+			}
 		default:
 			c.AdditionalIEs = append(c.AdditionalIEs, i)
 		}
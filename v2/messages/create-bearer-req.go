@@ -96,7 +96,11 @@ func NewCreateBearerRequest(teid, seq uint32, ie ...*ies.IE) *CreateBearerReques
 		case ies.FContainer:
 			c.NBIFOMContainer = i
 		case ies.PrivateExtension:
-			c.PrivateExtension = i
+			if c.PrivateExtension == nil {
+				c.PrivateExtension = i
+			} else {
+				c.AdditionalIEs = append(c.AdditionalIEs, i)
+			}
 		default:
 			c.AdditionalIEs = append(c.AdditionalIEs, i)
 		}
@@ -322,7 +326,11 @@ func (c *CreateBearerRequest) UnmarshalBinary(b []byte) error {
 		case ies.FContainer:
 			c.NBIFOMContainer = i
 		case ies.PrivateExtension:
-			c.PrivateExtension = i
+			if c.PrivateExtension == nil {
+				c.PrivateExtension = i
+			} else {
+				c.AdditionalIEs = append(c.AdditionalIEs, i)
+			}
 		default:
 			c.AdditionalIEs = append(c.AdditionalIEs, i)
 		}
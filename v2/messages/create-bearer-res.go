@@ -127,7 +127,11 @@ func NewCreateBearerResponse(teid, seq uint32, ie ...*ies.IE) *CreateBearerRespo
 		case ies.FContainer:
 			c.NBIFOMContainer = i
 		case ies.PrivateExtension:
-			c.PrivateExtension = i
+			if c.PrivateExtension == nil {
+				c.PrivateExtension = i
+			} else {
+				c.AdditionalIEs = append(c.AdditionalIEs, i)
+			}
 		default:
 			c.AdditionalIEs = append(c.AdditionalIEs, i)
 		}
@@ -408,7 +412,11 @@ func (c *CreateBearerResponse) UnmarshalBinary(b []byte) error {
 		case ies.FContainer:
 			c.NBIFOMContainer = i
 		case ies.PrivateExtension:
-			c.PrivateExtension = i
+			if c.PrivateExtension == nil {
+				c.PrivateExtension = i
+			} else {
+				c.AdditionalIEs = append(c.AdditionalIEs, i)
+			}
 		default:
 			c.AdditionalIEs = append(c.AdditionalIEs, i)
 		}
@@ -0,0 +1,239 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import "github.com/wmnsk/go-gtp/v2/ies"
+
+// CreateIndirectDataForwardingTunnelRequest is a CreateIndirectDataForwardingTunnelRequest Header and its IEs above.
+type CreateIndirectDataForwardingTunnelRequest struct {
+	*Header
+	IMSI             *ies.IE
+	MEI              *ies.IE
+	IndicationFlags  *ies.IE
+	SenderFTEIDC     *ies.IE
+	BearerContexts   *ies.IE
+	Recovery         *ies.IE
+	PrivateExtension *ies.IE
+	AdditionalIEs    []*ies.IE
+}
+
+// NewCreateIndirectDataForwardingTunnelRequest creates a new CreateIndirectDataForwardingTunnelRequest.
+func NewCreateIndirectDataForwardingTunnelRequest(teid, seq uint32, ie ...*ies.IE) *CreateIndirectDataForwardingTunnelRequest {
+	c := &CreateIndirectDataForwardingTunnelRequest{
+		Header: NewHeader(
+			NewHeaderFlags(2, 0, 1),
+			MsgTypeCreateIndirectDataForwardingTunnelRequest, teid, seq, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.IMSI:
+			c.IMSI = i
+		case ies.MobileEquipmentIdentity:
+			c.MEI = i
+		case ies.Indication:
+			c.IndicationFlags = i
+		case ies.FullyQualifiedTEID:
+			c.SenderFTEIDC = i
+		case ies.BearerContext:
+			c.BearerContexts = i
+		case ies.Recovery:
+			c.Recovery = i
+		case ies.PrivateExtension:
+			if c.PrivateExtension == nil {
+				c.PrivateExtension = i
+			} else {
+				c.AdditionalIEs = append(c.AdditionalIEs, i)
+			}
+		default:
+			c.AdditionalIEs = append(c.AdditionalIEs, i)
+		}
+	}
+
+	c.SetLength()
+	return c
+}
+
+// Marshal serializes CreateIndirectDataForwardingTunnelRequest into bytes.
+func (c *CreateIndirectDataForwardingTunnelRequest) Marshal() ([]byte, error) {
+	b := make([]byte, c.MarshalLen())
+	if err := c.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MarshalTo serializes CreateIndirectDataForwardingTunnelRequest into bytes.
+func (c *CreateIndirectDataForwardingTunnelRequest) MarshalTo(b []byte) error {
+	if c.Header.Payload != nil {
+		c.Header.Payload = nil
+	}
+	c.Header.Payload = make([]byte, c.MarshalLen()-c.Header.MarshalLen())
+
+	offset := 0
+	if ie := c.IMSI; ie != nil {
+		if err := ie.MarshalTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := c.MEI; ie != nil {
+		if err := ie.MarshalTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := c.IndicationFlags; ie != nil {
+		if err := ie.MarshalTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := c.SenderFTEIDC; ie != nil {
+		if err := ie.MarshalTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := c.BearerContexts; ie != nil {
+		if err := ie.MarshalTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := c.Recovery; ie != nil {
+		if err := ie.MarshalTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := c.PrivateExtension; ie != nil {
+		if err := ie.MarshalTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	for _, ie := range c.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(c.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	c.Header.SetLength()
+	return c.Header.MarshalTo(b)
+}
+
+// ParseCreateIndirectDataForwardingTunnelRequest decodes given bytes as CreateIndirectDataForwardingTunnelRequest.
+func ParseCreateIndirectDataForwardingTunnelRequest(b []byte) (*CreateIndirectDataForwardingTunnelRequest, error) {
+	c := &CreateIndirectDataForwardingTunnelRequest{}
+	if err := c.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// UnmarshalBinary decodes given bytes as CreateIndirectDataForwardingTunnelRequest.
+func (c *CreateIndirectDataForwardingTunnelRequest) UnmarshalBinary(b []byte) error {
+	var err error
+	c.Header, err = ParseHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(c.Header.Payload) < 2 {
+		return nil
+	}
+
+	decodedIEs, err := ies.ParseMultiIEs(c.Header.Payload)
+	if err != nil {
+		return err
+	}
+	for _, i := range decodedIEs {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.IMSI:
+			c.IMSI = i
+		case ies.MobileEquipmentIdentity:
+			c.MEI = i
+		case ies.Indication:
+			c.IndicationFlags = i
+		case ies.FullyQualifiedTEID:
+			c.SenderFTEIDC = i
+		case ies.BearerContext:
+			c.BearerContexts = i
+		case ies.Recovery:
+			c.Recovery = i
+		case ies.PrivateExtension:
+			if c.PrivateExtension == nil {
+				c.PrivateExtension = i
+			} else {
+				c.AdditionalIEs = append(c.AdditionalIEs, i)
+			}
+		default:
+			c.AdditionalIEs = append(c.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length in int.
+func (c *CreateIndirectDataForwardingTunnelRequest) MarshalLen() int {
+	l := c.Header.MarshalLen() - len(c.Header.Payload)
+	if ie := c.IMSI; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := c.MEI; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := c.IndicationFlags; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := c.SenderFTEIDC; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := c.BearerContexts; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := c.Recovery; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := c.PrivateExtension; ie != nil {
+		l += ie.MarshalLen()
+	}
+
+	for _, ie := range c.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (c *CreateIndirectDataForwardingTunnelRequest) SetLength() {
+	c.Header.Length = uint16(c.MarshalLen() - 4)
+}
+
+// MessageTypeName returns the name of protocol.
+func (c *CreateIndirectDataForwardingTunnelRequest) MessageTypeName() string {
+	return "Create Indirect Data Forwarding Tunnel Request"
+}
+
+// TEID returns the TEID in uint32.
+func (c *CreateIndirectDataForwardingTunnelRequest) TEID() uint32 {
+	return c.Header.teid()
+}
@@ -0,0 +1,70 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+	"github.com/wmnsk/go-gtp/v2/testutils"
+)
+
+func TestCreateIndirectDataForwardingTunnelRequest(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "Normal",
+			Structured: messages.NewCreateIndirectDataForwardingTunnelRequest(
+				testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+				ies.NewIMSI("123451234567890"),
+				ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 0xffffffff, "1.1.1.1", ""),
+				ies.NewBearerContext(ies.NewEPSBearerID(0x05), ies.NewFullyQualifiedTEID(v2.IFTypeS1UeNodeBGTPU, 0x11111111, "1.1.1.2", "")),
+			),
+			Serialized: []byte{
+				// Header
+				0x48, 0xa6, 0x00, 0x37, 0x11, 0x22, 0x33, 0x44, 0x00, 0x00, 0x01, 0x00,
+				// IMSI
+				0x01, 0x00, 0x08, 0x00, 0x21, 0x43, 0x15, 0x32, 0x54, 0x76, 0x98, 0xf0,
+				// Sender F-TEID for Control Plane
+				0x57, 0x00, 0x09, 0x00, 0x8a, 0xff, 0xff, 0xff, 0xff, 0x01, 0x01, 0x01, 0x01,
+				// Bearer Context
+				0x5d, 0x00, 0x12, 0x00, 0x49, 0x00, 0x01, 0x00, 0x05, 0x57, 0x00, 0x09, 0x00, 0x80, 0x11, 0x11, 0x11, 0x11, 0x01, 0x01, 0x01, 0x02,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializable, error) {
+		v, err := messages.ParseCreateIndirectDataForwardingTunnelRequest(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
+
+func TestParseIndirectForwardingBearerContext(t *testing.T) {
+	enb := ies.NewFullyQualifiedTEID(v2.IFTypeeNodeBGTPUForDL, 0x11111111, "1.1.1.2", "")
+	sgw := ies.NewFullyQualifiedTEID(v2.IFTypeSGWUPFGTPUForDL, 0x22222222, "1.1.1.3", "").WithInstance(1)
+	ul := ies.NewFullyQualifiedTEID(v2.IFTypeeNodeBGTPUForUL, 0x33333333, "1.1.1.2", "").WithInstance(4)
+
+	bc, err := messages.ParseIndirectForwardingBearerContext(
+		ies.NewBearerContext(ies.NewEPSBearerID(0x05), enb, sgw, ul),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bc.EBI.MustEPSBearerID() != 0x05 {
+		t.Errorf("wrong EBI: %v", bc.EBI)
+	}
+	if bc.ENBFTEIDForDL != enb || bc.SGWFTEIDForDL != sgw || bc.ENBFTEIDForUL != ul {
+		t.Errorf("F-TEIDs are not looked up by instance: %+v", bc)
+	}
+
+	if _, err := messages.ParseIndirectForwardingBearerContext(ies.NewEPSBearerID(0x05)); err == nil {
+		t.Error("non-Bearer Context IE should not be parsed")
+	}
+}
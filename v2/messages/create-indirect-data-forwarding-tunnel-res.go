@@ -0,0 +1,211 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import "github.com/wmnsk/go-gtp/v2/ies"
+
+// CreateIndirectDataForwardingTunnelResponse is a CreateIndirectDataForwardingTunnelResponse Header and its IEs above.
+type CreateIndirectDataForwardingTunnelResponse struct {
+	*Header
+	Cause            *ies.IE
+	SenderFTEIDC     *ies.IE
+	BearerContexts   *ies.IE
+	Recovery         *ies.IE
+	PrivateExtension *ies.IE
+	AdditionalIEs    []*ies.IE
+}
+
+// NewCreateIndirectDataForwardingTunnelResponse creates a new CreateIndirectDataForwardingTunnelResponse.
+func NewCreateIndirectDataForwardingTunnelResponse(teid, seq uint32, ie ...*ies.IE) *CreateIndirectDataForwardingTunnelResponse {
+	c := &CreateIndirectDataForwardingTunnelResponse{
+		Header: NewHeader(
+			NewHeaderFlags(2, 0, 1),
+			MsgTypeCreateIndirectDataForwardingTunnelResponse, teid, seq, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			c.Cause = i
+		case ies.FullyQualifiedTEID:
+			c.SenderFTEIDC = i
+		case ies.BearerContext:
+			c.BearerContexts = i
+		case ies.Recovery:
+			c.Recovery = i
+		case ies.PrivateExtension:
+			if c.PrivateExtension == nil {
+				c.PrivateExtension = i
+			} else {
+				c.AdditionalIEs = append(c.AdditionalIEs, i)
+			}
+		default:
+			c.AdditionalIEs = append(c.AdditionalIEs, i)
+		}
+	}
+
+	c.SetLength()
+	return c
+}
+
+// Marshal serializes CreateIndirectDataForwardingTunnelResponse into bytes.
+func (c *CreateIndirectDataForwardingTunnelResponse) Marshal() ([]byte, error) {
+	b := make([]byte, c.MarshalLen())
+	if err := c.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MarshalTo serializes CreateIndirectDataForwardingTunnelResponse into bytes.
+func (c *CreateIndirectDataForwardingTunnelResponse) MarshalTo(b []byte) error {
+	if c.Header.Payload != nil {
+		c.Header.Payload = nil
+	}
+	c.Header.Payload = make([]byte, c.MarshalLen()-c.Header.MarshalLen())
+
+	offset := 0
+	if ie := c.Cause; ie != nil {
+		if err := ie.MarshalTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := c.SenderFTEIDC; ie != nil {
+		if err := ie.MarshalTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := c.BearerContexts; ie != nil {
+		if err := ie.MarshalTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := c.Recovery; ie != nil {
+		if err := ie.MarshalTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := c.PrivateExtension; ie != nil {
+		if err := ie.MarshalTo(c.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	for _, ie := range c.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(c.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	c.Header.SetLength()
+	return c.Header.MarshalTo(b)
+}
+
+// ParseCreateIndirectDataForwardingTunnelResponse decodes given bytes as CreateIndirectDataForwardingTunnelResponse.
+func ParseCreateIndirectDataForwardingTunnelResponse(b []byte) (*CreateIndirectDataForwardingTunnelResponse, error) {
+	c := &CreateIndirectDataForwardingTunnelResponse{}
+	if err := c.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// UnmarshalBinary decodes given bytes as CreateIndirectDataForwardingTunnelResponse.
+func (c *CreateIndirectDataForwardingTunnelResponse) UnmarshalBinary(b []byte) error {
+	var err error
+	c.Header, err = ParseHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(c.Header.Payload) < 2 {
+		return nil
+	}
+
+	decodedIEs, err := ies.ParseMultiIEs(c.Header.Payload)
+	if err != nil {
+		return err
+	}
+	for _, i := range decodedIEs {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			c.Cause = i
+		case ies.FullyQualifiedTEID:
+			c.SenderFTEIDC = i
+		case ies.BearerContext:
+			c.BearerContexts = i
+		case ies.Recovery:
+			c.Recovery = i
+		case ies.PrivateExtension:
+			if c.PrivateExtension == nil {
+				c.PrivateExtension = i
+			} else {
+				c.AdditionalIEs = append(c.AdditionalIEs, i)
+			}
+		default:
+			c.AdditionalIEs = append(c.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length in int.
+func (c *CreateIndirectDataForwardingTunnelResponse) MarshalLen() int {
+	l := c.Header.MarshalLen() - len(c.Header.Payload)
+	if ie := c.Cause; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := c.SenderFTEIDC; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := c.BearerContexts; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := c.Recovery; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := c.PrivateExtension; ie != nil {
+		l += ie.MarshalLen()
+	}
+
+	for _, ie := range c.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (c *CreateIndirectDataForwardingTunnelResponse) SetLength() {
+	c.Header.Length = uint16(c.MarshalLen() - 4)
+}
+
+// MessageTypeName returns the name of protocol.
+func (c *CreateIndirectDataForwardingTunnelResponse) MessageTypeName() string {
+	return "Create Indirect Data Forwarding Tunnel Response"
+}
+
+// TEID returns the TEID in uint32.
+func (c *CreateIndirectDataForwardingTunnelResponse) TEID() uint32 {
+	return c.Header.teid()
+}
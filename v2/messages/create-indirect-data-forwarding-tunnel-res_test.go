@@ -0,0 +1,44 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+	"github.com/wmnsk/go-gtp/v2/testutils"
+)
+
+func TestCreateIndirectDataForwardingTunnelResponse(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "Normal",
+			Structured: messages.NewCreateIndirectDataForwardingTunnelResponse(
+				testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+				ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+				ies.NewBearerContext(ies.NewEPSBearerID(0x05), ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil), ies.NewFullyQualifiedTEID(v2.IFTypeSGWUPFGTPUForDL, 0x22222222, "1.1.1.3", "").WithInstance(1)),
+			),
+			Serialized: []byte{
+				// Header
+				0x48, 0xa7, 0x00, 0x2a, 0x11, 0x22, 0x33, 0x44, 0x00, 0x00, 0x01, 0x00,
+				// Cause
+				0x02, 0x00, 0x02, 0x00, 0x10, 0x00,
+				// Bearer Context
+				0x5d, 0x00, 0x18, 0x00, 0x49, 0x00, 0x01, 0x00, 0x05, 0x02, 0x00, 0x02, 0x00, 0x10, 0x00, 0x57, 0x00, 0x09, 0x01, 0x97, 0x22, 0x22, 0x22, 0x22, 0x01, 0x01, 0x01, 0x03,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializable, error) {
+		v, err := messages.ParseCreateIndirectDataForwardingTunnelResponse(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
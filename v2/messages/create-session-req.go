@@ -220,6 +220,8 @@ func NewCreateSessionRequest(teid, seq uint32, ie ...*ies.IE) *CreateSessionRequ
 				c.TWANIdentifier = i
 			case 1:
 				c.WLANLocationInformation = i
+			default:
+				c.AdditionalIEs = append(c.AdditionalIEs, i)
 			}
 		case ies.CNOperatorSelectionEntity:
 			c.CNOperatorSelectionEntity = i
@@ -265,7 +267,11 @@ func NewCreateSessionRequest(teid, seq uint32, ie ...*ies.IE) *CreateSessionRequ
 		case ies.APNRateControlStatus:
 			c.APNRateControlStatus = i
 		case ies.PrivateExtension:
-			c.PrivateExtension = i
+			if c.PrivateExtension == nil {
+				c.PrivateExtension = i
+			} else {
+				c.AdditionalIEs = append(c.AdditionalIEs, i)
+			}
 		default:
 			c.AdditionalIEs = append(c.AdditionalIEs, i)
 		}
@@ -900,7 +906,11 @@ func (c *CreateSessionRequest) UnmarshalBinary(b []byte) error {
 		case ies.APNRateControlStatus:
 			c.APNRateControlStatus = i
 		case ies.PrivateExtension:
-			c.PrivateExtension = i
+			if c.PrivateExtension == nil {
+				c.PrivateExtension = i
+			} else {
+				c.AdditionalIEs = append(c.AdditionalIEs, i)
+			}
 		default:
 			c.AdditionalIEs = append(c.AdditionalIEs, i)
 		}
@@ -5,7 +5,9 @@
 package messages_test
 
 import (
+	"net"
 	"testing"
+	"time"
 
 	v2 "github.com/wmnsk/go-gtp/v2"
 	"github.com/wmnsk/go-gtp/v2/ies"
@@ -86,6 +88,140 @@ func TestCreateSessionRequest(t *testing.T) {
 				0x11, 0x11, 0x11, 0x11, 0x11, 0x22, 0x22, 0x22, 0x22, 0x22,
 				0x11, 0x11, 0x11, 0x11, 0x11, 0x22, 0x22, 0x22, 0x22, 0x22,
 			},
+		}, {
+			Description: "Normal/FromePDGtoPGW",
+			Structured: messages.NewCreateSessionRequest(
+				testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+				ies.NewIMSI("123451234567890"),
+				ies.NewRATType(v2.RATTypeWLAN),
+				ies.NewFullyQualifiedTEID(v2.IFTypeS2bePDGGTPC, 0xffffffff, "1.1.1.1", ""),
+				ies.NewAccessPointName("some.apn.example"),
+				ies.NewPDNType(v2.PDNTypeIPv4),
+				ies.NewBearerContext(
+					ies.NewEPSBearerID(0x05),
+					ies.NewBearerQoS(1, 2, 1, 0xff, 0, 0, 0, 0),
+				),
+				ies.NewIPAddress("2.2.2.2"),
+				ies.NewPortNumber(4500),
+				ies.NewIPAddress("1.1.1.1").WithInstance(3),
+				ies.NewTWANIdentifier("ssid", net.HardwareAddr{0x00, 0x11, 0x22, 0x33, 0x44, 0x55}).WithInstance(1),
+				ies.NewTWANIdentifierTimestamp(time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)),
+			),
+			Serialized: []byte{
+				// Header
+				0x48, 0x20, 0x00, 0x91, 0x11, 0x22, 0x33, 0x44, 0x00, 0x00, 0x01, 0x00,
+				// IMSI
+				0x01, 0x00, 0x08, 0x00, 0x21, 0x43, 0x15, 0x32, 0x54, 0x76, 0x98, 0xf0,
+				// RATType
+				0x52, 0x00, 0x01, 0x00, 0x03,
+				// F-TEID S2b ePDG
+				0x57, 0x00, 0x09, 0x00, 0x9e, 0xff, 0xff, 0xff, 0xff, 0x01, 0x01, 0x01, 0x01,
+				// APN
+				0x47, 0x00, 0x11, 0x00, 0x04, 0x73, 0x6f, 0x6d, 0x65, 0x03, 0x61, 0x70, 0x6e, 0x07, 0x65, 0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65,
+				// PDNType
+				0x63, 0x00, 0x01, 0x00, 0x01,
+				// BearerContext
+				0x5d, 0x00, 0x1f, 0x00,
+				//   EBI
+				0x49, 0x00, 0x01, 0x00, 0x05,
+				//   BearerQoS
+				0x50, 0x00, 0x16, 0x00, 0x49, 0xff,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				// UE Local IP Address
+				0x4a, 0x00, 0x04, 0x00, 0x02, 0x02, 0x02, 0x02,
+				// UE UDP Port
+				0x7e, 0x00, 0x02, 0x00, 0x11, 0x94,
+				// ePDG IP Address
+				0x4a, 0x00, 0x04, 0x03, 0x01, 0x01, 0x01, 0x01,
+				// WLAN Location Information
+				0xa9, 0x00, 0x0c, 0x01, 0x01, 0x04, 0x73, 0x73, 0x69, 0x64, 0x00, 0x11, 0x22, 0x33, 0x44, 0x55,
+				// WLAN Location Timestamp
+				0xb3, 0x00, 0x04, 0x00, 0xdf, 0xd5, 0x2c, 0x00,
+			},
+		}, {
+			Description: "Normal/FromTWANtoPGW",
+			Structured: messages.NewCreateSessionRequest(
+				testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+				ies.NewIMSI("123451234567890"),
+				ies.NewRATType(v2.RATTypeWLAN),
+				ies.NewFullyQualifiedTEID(v2.IFTypeS2aTWANGTPC, 0xffffffff, "1.1.1.1", ""),
+				ies.NewAccessPointName("some.apn.example"),
+				ies.NewPDNType(v2.PDNTypeIPv4),
+				ies.NewTrustedWLANModeIndication(0, 1),
+				ies.NewBearerContext(
+					ies.NewEPSBearerID(0x05),
+					ies.NewBearerQoS(1, 2, 1, 0xff, 0, 0, 0, 0),
+				),
+				ies.NewTWANIdentifier("ssid", nil),
+			),
+			Serialized: []byte{
+				// Header
+				0x48, 0x20, 0x00, 0x72, 0x11, 0x22, 0x33, 0x44, 0x00, 0x00, 0x01, 0x00,
+				// IMSI
+				0x01, 0x00, 0x08, 0x00, 0x21, 0x43, 0x15, 0x32, 0x54, 0x76, 0x98, 0xf0,
+				// RATType
+				0x52, 0x00, 0x01, 0x00, 0x03,
+				// F-TEID S2a TWAN
+				0x57, 0x00, 0x09, 0x00, 0xa3, 0xff, 0xff, 0xff, 0xff, 0x01, 0x01, 0x01, 0x01,
+				// APN
+				0x47, 0x00, 0x11, 0x00, 0x04, 0x73, 0x6f, 0x6d, 0x65, 0x03, 0x61, 0x70, 0x6e, 0x07, 0x65, 0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65,
+				// PDNType
+				0x63, 0x00, 0x01, 0x00, 0x01,
+				// TWMI
+				0xae, 0x00, 0x01, 0x00, 0x01,
+				// BearerContext
+				0x5d, 0x00, 0x1f, 0x00,
+				//   EBI
+				0x49, 0x00, 0x01, 0x00, 0x05,
+				//   BearerQoS
+				0x50, 0x00, 0x16, 0x00, 0x49, 0xff,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				// TWAN Identifier
+				0xa9, 0x00, 0x06, 0x00, 0x00, 0x04, 0x73, 0x73, 0x69, 0x64,
+			},
+		}, {
+			Description: "Interworking/FromHRPD",
+			Structured: messages.NewCreateSessionRequest(
+				testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+				ies.NewIMSI("123451234567890"),
+				ies.NewRATType(v2.RATTypeEUTRAN),
+				ies.NewFullyQualifiedTEID(v2.IFTypeS5S8SGWGTPC, 0xffffffff, "1.1.1.1", ""),
+				ies.NewAccessPointName("some.apn.example"),
+				ies.NewBearerContext(ies.NewEPSBearerID(0x05)),
+				ies.New(4, 0x00, []byte{0x01, 0x02}),
+				ies.New(35, 0x00, []byte{0x03}),
+				ies.NewRFSPIndex(1),
+				ies.NewCSGID(0x00ffffff),
+				ies.NewCSGMembershipIndication(1),
+			),
+			Serialized: []byte{
+				// Header
+				0x48, 0x20, 0x00, 0x61, 0x11, 0x22, 0x33, 0x44, 0x00, 0x00, 0x01, 0x00,
+				// IMSI
+				0x01, 0x00, 0x08, 0x00, 0x21, 0x43, 0x15, 0x32, 0x54, 0x76, 0x98, 0xf0,
+				// RATType
+				0x52, 0x00, 0x01, 0x00, 0x06,
+				// F-TEID S5/S8 SGW
+				0x57, 0x00, 0x09, 0x00, 0x86, 0xff, 0xff, 0xff, 0xff, 0x01, 0x01, 0x01, 0x01,
+				// APN
+				0x47, 0x00, 0x11, 0x00, 0x04, 0x73, 0x6f, 0x6d, 0x65, 0x03, 0x61, 0x70, 0x6e, 0x07, 0x65, 0x78, 0x61, 0x6d, 0x70, 0x6c, 0x65,
+				// BearerContext
+				0x5d, 0x00, 0x05, 0x00,
+				//   EBI
+				0x49, 0x00, 0x01, 0x00, 0x05,
+				// IE reserved for S101
+				0x04, 0x00, 0x02, 0x00, 0x01, 0x02,
+				// IE reserved for S121
+				0x23, 0x00, 0x01, 0x00, 0x03,
+				// RFSP Index
+				0x90, 0x00, 0x01, 0x00, 0x01,
+				// CSG ID
+				0x93, 0x00, 0x04, 0x00, 0x00, 0xff, 0xff, 0xff,
+				// CMI
+				0x94, 0x00, 0x01, 0x00, 0x01,
+			},
 		},
 	}
 
@@ -155,7 +155,11 @@ func NewCreateSessionResponse(teid, seq uint32, ie ...*ies.IE) *CreateSessionRes
 		case ies.ExtendedProtocolConfigurationOptions:
 			c.EPCO = i
 		case ies.PrivateExtension:
-			c.PrivateExtension = i
+			if c.PrivateExtension == nil {
+				c.PrivateExtension = i
+			} else {
+				c.AdditionalIEs = append(c.AdditionalIEs, i)
+			}
 		default:
 			c.AdditionalIEs = append(c.AdditionalIEs, i)
 		}
@@ -520,7 +524,11 @@ func (c *CreateSessionResponse) UnmarshalBinary(b []byte) error {
 		case ies.ExtendedProtocolConfigurationOptions:
 			c.EPCO = i
 		case ies.PrivateExtension:
-			c.PrivateExtension = i
+			if c.PrivateExtension == nil {
+				c.PrivateExtension = i
+			} else {
+				c.AdditionalIEs = append(c.AdditionalIEs, i)
+			}
 		default:
 			c.AdditionalIEs = append(c.AdditionalIEs, i)
 		}
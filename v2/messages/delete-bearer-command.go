@@ -58,7 +58,11 @@ func NewDeleteBearerCommand(teid, seq uint32, ie ...*ies.IE) *DeleteBearerComman
 		case ies.SecondaryRATUsageDataReport:
 			d.SecondaryRATDataUsageReport = i
 		case ies.PrivateExtension:
-			d.PrivateExtension = i
+			if d.PrivateExtension == nil {
+				d.PrivateExtension = i
+			} else {
+				d.AdditionalIEs = append(d.AdditionalIEs, i)
+			}
 		default:
 			d.AdditionalIEs = append(d.AdditionalIEs, i)
 		}
@@ -211,7 +215,11 @@ func (d *DeleteBearerCommand) UnmarshalBinary(b []byte) error {
 		case ies.SecondaryRATUsageDataReport:
 			d.SecondaryRATDataUsageReport = i
 		case ies.PrivateExtension:
-			d.PrivateExtension = i
+			if d.PrivateExtension == nil {
+				d.PrivateExtension = i
+			} else {
+				d.AdditionalIEs = append(d.AdditionalIEs, i)
+			}
 		default:
 			d.AdditionalIEs = append(d.AdditionalIEs, i)
 		}
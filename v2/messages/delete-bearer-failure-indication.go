@@ -49,7 +49,11 @@ func NewDeleteBearerFailureIndication(teid, seq uint32, ie ...*ies.IE) *DeleteBe
 				d.SGWOverloadControlInformation = i
 			}
 		case ies.PrivateExtension:
-			d.PrivateExtension = i
+			if d.PrivateExtension == nil {
+				d.PrivateExtension = i
+			} else {
+				d.AdditionalIEs = append(d.AdditionalIEs, i)
+			}
 		default:
 			d.AdditionalIEs = append(d.AdditionalIEs, i)
 		}
@@ -178,7 +182,11 @@ func (d *DeleteBearerFailureIndication) UnmarshalBinary(b []byte) error {
 				d.SGWOverloadControlInformation = i
 			}
 		case ies.PrivateExtension:
-			d.PrivateExtension = i
+			if d.PrivateExtension == nil {
+				d.PrivateExtension = i
+			} else {
+				d.AdditionalIEs = append(d.AdditionalIEs, i)
+			}
 		default:
 			d.AdditionalIEs = append(d.AdditionalIEs, i)
 		}
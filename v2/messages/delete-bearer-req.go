@@ -101,7 +101,11 @@ func NewDeleteBearerRequest(teid, seq uint32, ie ...*ies.IE) *DeleteBearerReques
 		case ies.ExtendedProtocolConfigurationOptions:
 			d.EPCO = i
 		case ies.PrivateExtension:
-			d.PrivateExtension = i
+			if d.PrivateExtension == nil {
+				d.PrivateExtension = i
+			} else {
+				d.AdditionalIEs = append(d.AdditionalIEs, i)
+			}
 		default:
 			d.AdditionalIEs = append(d.AdditionalIEs, i)
 		}
@@ -336,7 +340,11 @@ func (d *DeleteBearerRequest) UnmarshalBinary(b []byte) error {
 		case ies.ExtendedProtocolConfigurationOptions:
 			d.EPCO = i
 		case ies.PrivateExtension:
-			d.PrivateExtension = i
+			if d.PrivateExtension == nil {
+				d.PrivateExtension = i
+			} else {
+				d.AdditionalIEs = append(d.AdditionalIEs, i)
+			}
 		default:
 			d.AdditionalIEs = append(d.AdditionalIEs, i)
 		}
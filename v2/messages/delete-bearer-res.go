@@ -135,7 +135,11 @@ func NewDeleteBearerResponse(teid, seq uint32, ie ...*ies.IE) *DeleteBearerRespo
 		case ies.SecondaryRATUsageDataReport:
 			d.SecondaryRATUsageDataReport = i
 		case ies.PrivateExtension:
-			d.PrivateExtension = i
+			if d.PrivateExtension == nil {
+				d.PrivateExtension = i
+			} else {
+				d.AdditionalIEs = append(d.AdditionalIEs, i)
+			}
 		default:
 			d.AdditionalIEs = append(d.AdditionalIEs, i)
 		}
@@ -444,7 +448,11 @@ func (d *DeleteBearerResponse) UnmarshalBinary(b []byte) error {
 		case ies.SecondaryRATUsageDataReport:
 			d.SecondaryRATUsageDataReport = i
 		case ies.PrivateExtension:
-			d.PrivateExtension = i
+			if d.PrivateExtension == nil {
+				d.PrivateExtension = i
+			} else {
+				d.AdditionalIEs = append(d.AdditionalIEs, i)
+			}
 		default:
 			d.AdditionalIEs = append(d.AdditionalIEs, i)
 		}
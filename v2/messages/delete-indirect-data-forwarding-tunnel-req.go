@@ -0,0 +1,155 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import "github.com/wmnsk/go-gtp/v2/ies"
+
+// DeleteIndirectDataForwardingTunnelRequest is a DeleteIndirectDataForwardingTunnelRequest Header and its IEs above.
+type DeleteIndirectDataForwardingTunnelRequest struct {
+	*Header
+	PrivateExtension *ies.IE
+	AdditionalIEs    []*ies.IE
+}
+
+// NewDeleteIndirectDataForwardingTunnelRequest creates a new DeleteIndirectDataForwardingTunnelRequest.
+func NewDeleteIndirectDataForwardingTunnelRequest(teid, seq uint32, ie ...*ies.IE) *DeleteIndirectDataForwardingTunnelRequest {
+	d := &DeleteIndirectDataForwardingTunnelRequest{
+		Header: NewHeader(
+			NewHeaderFlags(2, 0, 1),
+			MsgTypeDeleteIndirectDataForwardingTunnelRequest, teid, seq, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.PrivateExtension:
+			if d.PrivateExtension == nil {
+				d.PrivateExtension = i
+			} else {
+				d.AdditionalIEs = append(d.AdditionalIEs, i)
+			}
+		default:
+			d.AdditionalIEs = append(d.AdditionalIEs, i)
+		}
+	}
+
+	d.SetLength()
+	return d
+}
+
+// Marshal serializes DeleteIndirectDataForwardingTunnelRequest into bytes.
+func (d *DeleteIndirectDataForwardingTunnelRequest) Marshal() ([]byte, error) {
+	b := make([]byte, d.MarshalLen())
+	if err := d.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MarshalTo serializes DeleteIndirectDataForwardingTunnelRequest into bytes.
+func (d *DeleteIndirectDataForwardingTunnelRequest) MarshalTo(b []byte) error {
+	if d.Header.Payload != nil {
+		d.Header.Payload = nil
+	}
+	d.Header.Payload = make([]byte, d.MarshalLen()-d.Header.MarshalLen())
+
+	offset := 0
+	if ie := d.PrivateExtension; ie != nil {
+		if err := ie.MarshalTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	for _, ie := range d.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(d.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	d.Header.SetLength()
+	return d.Header.MarshalTo(b)
+}
+
+// ParseDeleteIndirectDataForwardingTunnelRequest decodes given bytes as DeleteIndirectDataForwardingTunnelRequest.
+func ParseDeleteIndirectDataForwardingTunnelRequest(b []byte) (*DeleteIndirectDataForwardingTunnelRequest, error) {
+	d := &DeleteIndirectDataForwardingTunnelRequest{}
+	if err := d.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// UnmarshalBinary decodes given bytes as DeleteIndirectDataForwardingTunnelRequest.
+func (d *DeleteIndirectDataForwardingTunnelRequest) UnmarshalBinary(b []byte) error {
+	var err error
+	d.Header, err = ParseHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(d.Header.Payload) < 2 {
+		return nil
+	}
+
+	decodedIEs, err := ies.ParseMultiIEs(d.Header.Payload)
+	if err != nil {
+		return err
+	}
+	for _, i := range decodedIEs {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.PrivateExtension:
+			if d.PrivateExtension == nil {
+				d.PrivateExtension = i
+			} else {
+				d.AdditionalIEs = append(d.AdditionalIEs, i)
+			}
+		default:
+			d.AdditionalIEs = append(d.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length in int.
+func (d *DeleteIndirectDataForwardingTunnelRequest) MarshalLen() int {
+	l := d.Header.MarshalLen() - len(d.Header.Payload)
+	if ie := d.PrivateExtension; ie != nil {
+		l += ie.MarshalLen()
+	}
+
+	for _, ie := range d.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (d *DeleteIndirectDataForwardingTunnelRequest) SetLength() {
+	d.Header.Length = uint16(d.MarshalLen() - 4)
+}
+
+// MessageTypeName returns the name of protocol.
+func (d *DeleteIndirectDataForwardingTunnelRequest) MessageTypeName() string {
+	return "Delete Indirect Data Forwarding Tunnel Request"
+}
+
+// TEID returns the TEID in uint32.
+func (d *DeleteIndirectDataForwardingTunnelRequest) TEID() uint32 {
+	return d.Header.teid()
+}
@@ -0,0 +1,235 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import "github.com/wmnsk/go-gtp/v2/ies"
+
+// DeletePDNConnectionSetRequest is a DeletePDNConnectionSetRequest Header and its IEs above.
+type DeletePDNConnectionSetRequest struct {
+	*Header
+	MMEFQCSID        *ies.IE
+	SGWFQCSID        *ies.IE
+	PGWFQCSID        *ies.IE
+	EPDGFQCSID       *ies.IE
+	TWANFQCSID       *ies.IE
+	PrivateExtension *ies.IE
+	AdditionalIEs    []*ies.IE
+}
+
+// NewDeletePDNConnectionSetRequest creates a new DeletePDNConnectionSetRequest.
+func NewDeletePDNConnectionSetRequest(teid, seq uint32, ie ...*ies.IE) *DeletePDNConnectionSetRequest {
+	d := &DeletePDNConnectionSetRequest{
+		Header: NewHeader(
+			NewHeaderFlags(2, 0, 1),
+			MsgTypeDeletePDNConnectionSetRequest, teid, seq, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.FullyQualifiedCSID:
+			switch i.Instance() {
+			case 0:
+				d.MMEFQCSID = i
+			case 1:
+				d.SGWFQCSID = i
+			case 2:
+				d.PGWFQCSID = i
+			case 3:
+				d.EPDGFQCSID = i
+			case 4:
+				d.TWANFQCSID = i
+			default:
+				d.AdditionalIEs = append(d.AdditionalIEs, i)
+			}
+		case ies.PrivateExtension:
+			if d.PrivateExtension == nil {
+				d.PrivateExtension = i
+			} else {
+				d.AdditionalIEs = append(d.AdditionalIEs, i)
+			}
+		default:
+			d.AdditionalIEs = append(d.AdditionalIEs, i)
+		}
+	}
+
+	d.SetLength()
+	return d
+}
+
+// Marshal serializes DeletePDNConnectionSetRequest into bytes.
+func (d *DeletePDNConnectionSetRequest) Marshal() ([]byte, error) {
+	b := make([]byte, d.MarshalLen())
+	if err := d.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MarshalTo serializes DeletePDNConnectionSetRequest into bytes.
+func (d *DeletePDNConnectionSetRequest) MarshalTo(b []byte) error {
+	if d.Header.Payload != nil {
+		d.Header.Payload = nil
+	}
+	d.Header.Payload = make([]byte, d.MarshalLen()-d.Header.MarshalLen())
+
+	offset := 0
+	if ie := d.MMEFQCSID; ie != nil {
+		if err := ie.MarshalTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := d.SGWFQCSID; ie != nil {
+		if err := ie.MarshalTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := d.PGWFQCSID; ie != nil {
+		if err := ie.MarshalTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := d.EPDGFQCSID; ie != nil {
+		if err := ie.MarshalTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := d.TWANFQCSID; ie != nil {
+		if err := ie.MarshalTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := d.PrivateExtension; ie != nil {
+		if err := ie.MarshalTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	for _, ie := range d.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(d.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	d.Header.SetLength()
+	return d.Header.MarshalTo(b)
+}
+
+// ParseDeletePDNConnectionSetRequest decodes given bytes as DeletePDNConnectionSetRequest.
+func ParseDeletePDNConnectionSetRequest(b []byte) (*DeletePDNConnectionSetRequest, error) {
+	d := &DeletePDNConnectionSetRequest{}
+	if err := d.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// UnmarshalBinary decodes given bytes as DeletePDNConnectionSetRequest.
+func (d *DeletePDNConnectionSetRequest) UnmarshalBinary(b []byte) error {
+	var err error
+	d.Header, err = ParseHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(d.Header.Payload) < 2 {
+		return nil
+	}
+
+	decodedIEs, err := ies.ParseMultiIEs(d.Header.Payload)
+	if err != nil {
+		return err
+	}
+	for _, i := range decodedIEs {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.FullyQualifiedCSID:
+			switch i.Instance() {
+			case 0:
+				d.MMEFQCSID = i
+			case 1:
+				d.SGWFQCSID = i
+			case 2:
+				d.PGWFQCSID = i
+			case 3:
+				d.EPDGFQCSID = i
+			case 4:
+				d.TWANFQCSID = i
+			default:
+				d.AdditionalIEs = append(d.AdditionalIEs, i)
+			}
+		case ies.PrivateExtension:
+			if d.PrivateExtension == nil {
+				d.PrivateExtension = i
+			} else {
+				d.AdditionalIEs = append(d.AdditionalIEs, i)
+			}
+		default:
+			d.AdditionalIEs = append(d.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length in int.
+func (d *DeletePDNConnectionSetRequest) MarshalLen() int {
+	l := d.Header.MarshalLen() - len(d.Header.Payload)
+	if ie := d.MMEFQCSID; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := d.SGWFQCSID; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := d.PGWFQCSID; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := d.EPDGFQCSID; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := d.TWANFQCSID; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := d.PrivateExtension; ie != nil {
+		l += ie.MarshalLen()
+	}
+
+	for _, ie := range d.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (d *DeletePDNConnectionSetRequest) SetLength() {
+	d.Header.Length = uint16(d.MarshalLen() - 4)
+}
+
+// MessageTypeName returns the name of protocol.
+func (d *DeletePDNConnectionSetRequest) MessageTypeName() string {
+	return "Delete PDN Connection Set Request"
+}
+
+// TEID returns the TEID in uint32.
+func (d *DeletePDNConnectionSetRequest) TEID() uint32 {
+	return d.Header.teid()
+}
@@ -0,0 +1,43 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+	"github.com/wmnsk/go-gtp/v2/testutils"
+)
+
+func TestDeletePDNConnectionSetRequest(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "Normal",
+			Structured: messages.NewDeletePDNConnectionSetRequest(
+				testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+				ies.NewFullyQualifiedCSID("1.1.1.1", 1),
+				ies.NewFullyQualifiedCSID("1.1.1.2", 1, 2).WithInstance(1),
+			),
+			Serialized: []byte{
+				// Header
+				0x48, 0x65, 0x00, 0x20, 0x11, 0x22, 0x33, 0x44, 0x00, 0x00, 0x01, 0x00,
+				// MME FQ-CSID
+				0x84, 0x00, 0x07, 0x00, 0x01, 0x01, 0x01, 0x01, 0x01, 0x00, 0x01,
+				// SGW FQ-CSID
+				0x84, 0x00, 0x09, 0x01, 0x02, 0x01, 0x01, 0x01, 0x02, 0x00, 0x01, 0x00, 0x02,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializable, error) {
+		v, err := messages.ParseDeletePDNConnectionSetRequest(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
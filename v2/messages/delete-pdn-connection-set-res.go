@@ -0,0 +1,183 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import "github.com/wmnsk/go-gtp/v2/ies"
+
+// DeletePDNConnectionSetResponse is a DeletePDNConnectionSetResponse Header and its IEs above.
+type DeletePDNConnectionSetResponse struct {
+	*Header
+	Cause            *ies.IE
+	Recovery         *ies.IE
+	PrivateExtension *ies.IE
+	AdditionalIEs    []*ies.IE
+}
+
+// NewDeletePDNConnectionSetResponse creates a new DeletePDNConnectionSetResponse.
+func NewDeletePDNConnectionSetResponse(teid, seq uint32, ie ...*ies.IE) *DeletePDNConnectionSetResponse {
+	d := &DeletePDNConnectionSetResponse{
+		Header: NewHeader(
+			NewHeaderFlags(2, 0, 1),
+			MsgTypeDeletePDNConnectionSetResponse, teid, seq, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			d.Cause = i
+		case ies.Recovery:
+			d.Recovery = i
+		case ies.PrivateExtension:
+			if d.PrivateExtension == nil {
+				d.PrivateExtension = i
+			} else {
+				d.AdditionalIEs = append(d.AdditionalIEs, i)
+			}
+		default:
+			d.AdditionalIEs = append(d.AdditionalIEs, i)
+		}
+	}
+
+	d.SetLength()
+	return d
+}
+
+// Marshal serializes DeletePDNConnectionSetResponse into bytes.
+func (d *DeletePDNConnectionSetResponse) Marshal() ([]byte, error) {
+	b := make([]byte, d.MarshalLen())
+	if err := d.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MarshalTo serializes DeletePDNConnectionSetResponse into bytes.
+func (d *DeletePDNConnectionSetResponse) MarshalTo(b []byte) error {
+	if d.Header.Payload != nil {
+		d.Header.Payload = nil
+	}
+	d.Header.Payload = make([]byte, d.MarshalLen()-d.Header.MarshalLen())
+
+	offset := 0
+	if ie := d.Cause; ie != nil {
+		if err := ie.MarshalTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := d.Recovery; ie != nil {
+		if err := ie.MarshalTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := d.PrivateExtension; ie != nil {
+		if err := ie.MarshalTo(d.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	for _, ie := range d.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(d.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	d.Header.SetLength()
+	return d.Header.MarshalTo(b)
+}
+
+// ParseDeletePDNConnectionSetResponse decodes given bytes as DeletePDNConnectionSetResponse.
+func ParseDeletePDNConnectionSetResponse(b []byte) (*DeletePDNConnectionSetResponse, error) {
+	d := &DeletePDNConnectionSetResponse{}
+	if err := d.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// UnmarshalBinary decodes given bytes as DeletePDNConnectionSetResponse.
+func (d *DeletePDNConnectionSetResponse) UnmarshalBinary(b []byte) error {
+	var err error
+	d.Header, err = ParseHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(d.Header.Payload) < 2 {
+		return nil
+	}
+
+	decodedIEs, err := ies.ParseMultiIEs(d.Header.Payload)
+	if err != nil {
+		return err
+	}
+	for _, i := range decodedIEs {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			d.Cause = i
+		case ies.Recovery:
+			d.Recovery = i
+		case ies.PrivateExtension:
+			if d.PrivateExtension == nil {
+				d.PrivateExtension = i
+			} else {
+				d.AdditionalIEs = append(d.AdditionalIEs, i)
+			}
+		default:
+			d.AdditionalIEs = append(d.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length in int.
+func (d *DeletePDNConnectionSetResponse) MarshalLen() int {
+	l := d.Header.MarshalLen() - len(d.Header.Payload)
+	if ie := d.Cause; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := d.Recovery; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := d.PrivateExtension; ie != nil {
+		l += ie.MarshalLen()
+	}
+
+	for _, ie := range d.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (d *DeletePDNConnectionSetResponse) SetLength() {
+	d.Header.Length = uint16(d.MarshalLen() - 4)
+}
+
+// MessageTypeName returns the name of protocol.
+func (d *DeletePDNConnectionSetResponse) MessageTypeName() string {
+	return "Delete PDN Connection Set Response"
+}
+
+// TEID returns the TEID in uint32.
+func (d *DeletePDNConnectionSetResponse) TEID() uint32 {
+	return d.Header.teid()
+}
@@ -116,7 +116,11 @@ func NewDeleteSessionRequest(teid, seq uint32, ie ...*ies.IE) *DeleteSessionRequ
 		case ies.SecondaryRATUsageDataReport:
 			d.SecondaryRATUsageDataReport = i
 		case ies.PrivateExtension:
-			d.PrivateExtension = i
+			if d.PrivateExtension == nil {
+				d.PrivateExtension = i
+			} else {
+				d.AdditionalIEs = append(d.AdditionalIEs, i)
+			}
 		default:
 			d.AdditionalIEs = append(d.AdditionalIEs, i)
 		}
@@ -391,7 +395,11 @@ func (d *DeleteSessionRequest) UnmarshalBinary(b []byte) error {
 		case ies.SecondaryRATUsageDataReport:
 			d.SecondaryRATUsageDataReport = i
 		case ies.PrivateExtension:
-			d.PrivateExtension = i
+			if d.PrivateExtension == nil {
+				d.PrivateExtension = i
+			} else {
+				d.AdditionalIEs = append(d.AdditionalIEs, i)
+			}
 		default:
 			d.AdditionalIEs = append(d.AdditionalIEs, i)
 		}
@@ -73,7 +73,11 @@ func NewDeleteSessionResponse(teid, seq uint32, ie ...*ies.IE) *DeleteSessionRes
 		case ies.APNRateControlStatus:
 			d.APNRateControlStatus = i
 		case ies.PrivateExtension:
-			d.PrivateExtension = i
+			if d.PrivateExtension == nil {
+				d.PrivateExtension = i
+			} else {
+				d.AdditionalIEs = append(d.AdditionalIEs, i)
+			}
 		default:
 			d.AdditionalIEs = append(d.AdditionalIEs, i)
 		}
@@ -250,7 +254,11 @@ func (d *DeleteSessionResponse) UnmarshalBinary(b []byte) error {
 		case ies.APNRateControlStatus:
 			d.APNRateControlStatus = i
 		case ies.PrivateExtension:
-			d.PrivateExtension = i
+			if d.PrivateExtension == nil {
+				d.PrivateExtension = i
+			} else {
+				d.AdditionalIEs = append(d.AdditionalIEs, i)
+			}
 		default:
 			d.AdditionalIEs = append(d.AdditionalIEs, i)
 		}
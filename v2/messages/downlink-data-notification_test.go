@@ -0,0 +1,46 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+	"github.com/wmnsk/go-gtp/v2/testutils"
+)
+
+func TestDownlinkDataNotification(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "Normal",
+			Structured: messages.NewDownlinkDataNotification(
+				testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+				ies.NewEPSBearerID(0x05),
+				ies.NewAllocationRetensionPriority(1, 2, 1),
+				ies.NewPagingAndServiceInformation(5, 1, 0x0a),
+			),
+			Serialized: []byte{
+				// Header
+				0x48, 0xb0, 0x00, 0x19, 0x11, 0x22, 0x33, 0x44, 0x00, 0x00, 0x01, 0x00,
+				// EBI
+				0x49, 0x00, 0x01, 0x00, 0x05,
+				// ARP
+				0x9b, 0x00, 0x01, 0x00, 0x49,
+				// Paging and Service Information
+				0xba, 0x00, 0x03, 0x00, 0x05, 0x01, 0x0a,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializable, error) {
+		v, err := messages.ParseDownlinkDataNotification(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
@@ -0,0 +1,88 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+)
+
+// headerFlags is implemented by the messages that embed Header.
+type headerFlags interface {
+	IsPiggybacking() bool
+	HasMessagePriority() bool
+}
+
+// Dump returns the message in human readable format like the packet analyzers: the
+// message type, the flags, the TEID and the sequence number in the header, followed
+// by the IEs in the order they are serialized. See (*ies.IE) Dump for the format of
+// the IEs.
+func Dump(msg Message) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s (%d), Length: %d\n", msg.MessageTypeName(), msg.MessageType(), msg.MarshalLen()-4)
+
+	if h, ok := msg.(headerFlags); ok {
+		fmt.Fprintf(&sb, "  Version: %d, Piggybacking: %t, TEID Flag: %t, Message Priority Flag: %t\n",
+			msg.Version(), h.IsPiggybacking(), msg.HasTEID(), h.HasMessagePriority(),
+		)
+		if msg.HasTEID() {
+			fmt.Fprintf(&sb, "  TEID: %#x\n", msg.TEID())
+		}
+		fmt.Fprintf(&sb, "  Sequence Number: %#x\n", msg.Sequence())
+		if mp, ok := msg.Priority(); ok {
+			fmt.Fprintf(&sb, "  Message Priority: %d\n", mp)
+		}
+	}
+
+	for _, i := range IEs(msg) {
+		for _, line := range strings.SplitAfter(i.Dump(), "\n") {
+			if line != "" {
+				sb.WriteString("  " + line)
+			}
+		}
+	}
+	return sb.String()
+}
+
+type messageJSON struct {
+	Version         int       `json:"version"`
+	Type            uint8     `json:"type"`
+	Name            string    `json:"name"`
+	Length          int       `json:"length"`
+	Piggybacking    bool      `json:"piggybacking"`
+	TEID            *uint32   `json:"teid,omitempty"`
+	Sequence        uint32    `json:"sequence"`
+	MessagePriority *uint8    `json:"message_priority,omitempty"`
+	IEs             []*ies.IE `json:"ies,omitempty"`
+}
+
+// MarshalJSON returns the message in JSON for the structured logging, with the same
+// contents as Dump. "teid" and "message_priority" are omitted if the flags in the
+// header is not set.
+func MarshalJSON(msg Message) ([]byte, error) {
+	v := &messageJSON{
+		Version:  msg.Version(),
+		Type:     msg.MessageType(),
+		Name:     msg.MessageTypeName(),
+		Length:   msg.MarshalLen() - 4,
+		Sequence: msg.Sequence(),
+		IEs:      IEs(msg),
+	}
+
+	if h, ok := msg.(headerFlags); ok {
+		v.Piggybacking = h.IsPiggybacking()
+		if msg.HasTEID() {
+			teid := msg.TEID()
+			v.TEID = &teid
+		}
+		if mp, ok := msg.Priority(); ok {
+			v.MessagePriority = &mp
+		}
+	}
+	return json.Marshal(v)
+}
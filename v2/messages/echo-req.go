@@ -11,9 +11,10 @@ import (
 // EchoRequest is a EchoRequest Header and its IEs above.
 type EchoRequest struct {
 	*Header
-	Recovery         *ies.IE
-	PrivateExtension *ies.IE
-	AdditionalIEs    []*ies.IE
+	Recovery            *ies.IE
+	SendingNodeFeatures *ies.IE
+	PrivateExtension    *ies.IE
+	AdditionalIEs       []*ies.IE
 }
 
 // NewEchoRequest creates a new EchoRequest.
@@ -32,8 +33,14 @@ func NewEchoRequest(seq uint32, ie ...*ies.IE) *EchoRequest {
 		switch i.Type {
 		case ies.Recovery:
 			e.Recovery = i
+		case ies.NodeFeatures:
+			e.SendingNodeFeatures = i
 		case ies.PrivateExtension:
-			e.PrivateExtension = i
+			if e.PrivateExtension == nil {
+				e.PrivateExtension = i
+			} else {
+				e.AdditionalIEs = append(e.AdditionalIEs, i)
+			}
 		default:
 			e.AdditionalIEs = append(e.AdditionalIEs, i)
 		}
@@ -67,8 +74,14 @@ func (e *EchoRequest) MarshalTo(b []byte) error {
 		}
 		offset += ie.MarshalLen()
 	}
+	if ie := e.SendingNodeFeatures; ie != nil {
+		if err := ie.MarshalTo(e.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
 	if ie := e.PrivateExtension; ie != nil {
-		if err := ie.MarshalTo(e.Header.Payload); err != nil {
+		if err := ie.MarshalTo(e.Header.Payload[offset:]); err != nil {
 			return err
 		}
 		offset += ie.MarshalLen()
@@ -117,8 +130,14 @@ func (e *EchoRequest) UnmarshalBinary(b []byte) error {
 		switch i.Type {
 		case ies.Recovery:
 			e.Recovery = i
+		case ies.NodeFeatures:
+			e.SendingNodeFeatures = i
 		case ies.PrivateExtension:
-			e.PrivateExtension = i
+			if e.PrivateExtension == nil {
+				e.PrivateExtension = i
+			} else {
+				e.AdditionalIEs = append(e.AdditionalIEs, i)
+			}
 		default:
 			e.AdditionalIEs = append(e.AdditionalIEs, i)
 		}
@@ -134,6 +153,12 @@ func (e *EchoRequest) MarshalLen() int {
 	if ie := e.Recovery; ie != nil {
 		l += ie.MarshalLen()
 	}
+	if ie := e.SendingNodeFeatures; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := e.PrivateExtension; ie != nil {
+		l += ie.MarshalLen()
+	}
 
 	for _, ie := range e.AdditionalIEs {
 		l += ie.MarshalLen()
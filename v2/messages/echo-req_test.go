@@ -7,6 +7,7 @@ package messages_test
 import (
 	"testing"
 
+	v2 "github.com/wmnsk/go-gtp/v2"
 	"github.com/wmnsk/go-gtp/v2/ies"
 	"github.com/wmnsk/go-gtp/v2/messages"
 	"github.com/wmnsk/go-gtp/v2/testutils"
@@ -21,6 +22,22 @@ func TestEchoRequest(t *testing.T) {
 				0x40, 0x01, 0x00, 0x09, 0x00, 0x00, 0x00, 0x00,
 				0x03, 0x00, 0x01, 0x00, 0x80,
 			},
+		}, {
+			Description: "WithPrivateExtension",
+			Structured:  messages.NewEchoRequest(0, ies.NewRecovery(0x80), ies.NewPrivateExtension(0x0080, []byte{0xde, 0xad})),
+			Serialized: []byte{
+				0x40, 0x01, 0x00, 0x11, 0x00, 0x00, 0x00, 0x00,
+				0x03, 0x00, 0x01, 0x00, 0x80,
+				0xff, 0x00, 0x04, 0x00, 0x00, 0x80, 0xde, 0xad,
+			},
+		}, {
+			Description: "WithNodeFeatures",
+			Structured:  messages.NewEchoRequest(0, ies.NewRecovery(0x80), ies.NewNodeFeatures(v2.NodeFeaturePRN|v2.NodeFeatureNTSR)),
+			Serialized: []byte{
+				0x40, 0x01, 0x00, 0x0e, 0x00, 0x00, 0x00, 0x00,
+				0x03, 0x00, 0x01, 0x00, 0x80,
+				0x98, 0x00, 0x01, 0x00, 0x05,
+			},
 		},
 	}
 
@@ -11,9 +11,10 @@ import (
 // EchoResponse is a EchoResponse Header and its IEs above.
 type EchoResponse struct {
 	*Header
-	Recovery         *ies.IE
-	PrivateExtension *ies.IE
-	AdditionalIEs    []*ies.IE
+	Recovery            *ies.IE
+	SendingNodeFeatures *ies.IE
+	PrivateExtension    *ies.IE
+	AdditionalIEs       []*ies.IE
 }
 
 // NewEchoResponse creates a new EchoResponse.
@@ -32,8 +33,14 @@ func NewEchoResponse(seq uint32, ie ...*ies.IE) *EchoResponse {
 		switch i.Type {
 		case ies.Recovery:
 			e.Recovery = i
+		case ies.NodeFeatures:
+			e.SendingNodeFeatures = i
 		case ies.PrivateExtension:
-			e.PrivateExtension = i
+			if e.PrivateExtension == nil {
+				e.PrivateExtension = i
+			} else {
+				e.AdditionalIEs = append(e.AdditionalIEs, i)
+			}
 		default:
 			e.AdditionalIEs = append(e.AdditionalIEs, i)
 		}
@@ -67,8 +74,14 @@ func (e *EchoResponse) MarshalTo(b []byte) error {
 		}
 		offset += ie.MarshalLen()
 	}
+	if ie := e.SendingNodeFeatures; ie != nil {
+		if err := ie.MarshalTo(e.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
 	if ie := e.PrivateExtension; ie != nil {
-		if err := ie.MarshalTo(e.Header.Payload); err != nil {
+		if err := ie.MarshalTo(e.Header.Payload[offset:]); err != nil {
 			return err
 		}
 		offset += ie.MarshalLen()
@@ -120,8 +133,14 @@ func (e *EchoResponse) UnmarshalBinary(b []byte) error {
 		switch i.Type {
 		case ies.Recovery:
 			e.Recovery = i
+		case ies.NodeFeatures:
+			e.SendingNodeFeatures = i
 		case ies.PrivateExtension:
-			e.PrivateExtension = i
+			if e.PrivateExtension == nil {
+				e.PrivateExtension = i
+			} else {
+				e.AdditionalIEs = append(e.AdditionalIEs, i)
+			}
 		default:
 			e.AdditionalIEs = append(e.AdditionalIEs, i)
 		}
@@ -137,6 +156,9 @@ func (e *EchoResponse) MarshalLen() int {
 	if ie := e.Recovery; ie != nil {
 		l += ie.MarshalLen()
 	}
+	if ie := e.SendingNodeFeatures; ie != nil {
+		l += ie.MarshalLen()
+	}
 	if ie := e.PrivateExtension; ie != nil {
 		l += ie.MarshalLen()
 	}
@@ -4,10 +4,85 @@
 
 package messages
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // Error definitions.
 var (
 	ErrInvalidLength   = errors.New("length value is invalid")
 	ErrTooShortToParse = errors.New("too short to decode as GTP")
+	ErrTypeMismatch    = errors.New("message type mismatch")
+	ErrNoMessages      = errors.New("no messages given")
+
+	ErrInvalidPriority     = errors.New("message priority should be 0 to 15")
+	ErrPriorityWithoutTEID = errors.New("message priority cannot be set without TEID field")
 )
+
+// MessageTooLongError indicates that the message exceeds the length limit.
+type MessageTooLongError struct {
+	Length, Max int
+}
+
+// Error returns the length of message and the limit.
+func (e *MessageTooLongError) Error() string {
+	return fmt.Sprintf("message too long: %d bytes, limit: %d", e.Length, e.Max)
+}
+
+// TooManyIEsError indicates that the message contains more IEs than the limit.
+type TooManyIEsError struct {
+	Max int
+}
+
+// Error returns the limit of the number of IEs.
+func (e *TooManyIEsError) Error() string {
+	return fmt.Sprintf("too many IEs in a message, limit: %d", e.Max)
+}
+
+// IENestingTooDeepError indicates that the grouped IEs are nested deeper than the limit.
+type IENestingTooDeepError struct {
+	Max int
+}
+
+// Error returns the limit of the nesting depth of grouped IEs.
+func (e *IENestingTooDeepError) Error() string {
+	return fmt.Sprintf("grouped IEs nested too deep, limit: %d", e.Max)
+}
+
+// DuplicateIEError indicates that the IE that cannot be repeated appears more than
+// once in a message.
+type DuplicateIEError struct {
+	Type, Instance uint8
+}
+
+// Error returns the type and instance of the duplicated IE.
+func (e *DuplicateIEError) Error() string {
+	return fmt.Sprintf("duplicated IE: type=%d, instance=%d", e.Type, e.Instance)
+}
+
+// MalformedIEError indicates that the IE at Offset from the beginning of a message
+// cannot be decoded. The IEs inside the grouped IEs are also reported with the offset
+// from the beginning of the message.
+type MalformedIEError struct {
+	Offset         int
+	Type, Instance uint8
+	Err            error
+}
+
+// Error returns the offset, type and instance of the malformed IE with the reason.
+func (e *MalformedIEError) Error() string {
+	return fmt.Sprintf("malformed IE at offset %d: type=%d, instance=%d: %v", e.Offset, e.Type, e.Instance, e.Err)
+}
+
+// UnknownIEError indicates that the IE at Offset from the beginning of a message is
+// not defined in the type of message.
+type UnknownIEError struct {
+	Offset         int
+	Type, Instance uint8
+}
+
+// Error returns the offset, type and instance of the unknown IE.
+func (e *UnknownIEError) Error() string {
+	return fmt.Sprintf("unknown IE at offset %d: type=%d, instance=%d", e.Offset, e.Type, e.Instance)
+}
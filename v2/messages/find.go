@@ -0,0 +1,88 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"reflect"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+)
+
+var (
+	ieType      = reflect.TypeOf((*ies.IE)(nil))
+	ieSliceType = reflect.TypeOf([]*ies.IE(nil))
+)
+
+// IEs returns all the top-level IEs in msg, in the order they are serialized.
+//
+// This works for any type of Message without knowing the names of the fields. The
+// IEs returned are the ones held in msg, not the copies, so modifying them affects
+// msg. It returns nil if msg is not the one defined in this package.
+//
+// For Lazy, the IEs are decoded each time, and nil is returned if it fails.
+func IEs(msg Message) []*ies.IE {
+	if l, ok := msg.(*Lazy); ok {
+		if l == nil || l.Header == nil {
+			return nil
+		}
+		ie, err := l.IEs()
+		if err != nil {
+			return nil
+		}
+		return ie
+	}
+
+	v := reflect.ValueOf(msg)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return nil
+	}
+	v = v.Elem()
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var ie []*ies.IE
+	for n := 0; n < v.NumField(); n++ {
+		f := v.Field(n)
+		switch f.Type() {
+		case ieType:
+			if i := f.Interface().(*ies.IE); i != nil {
+				ie = append(ie, i)
+			}
+		case ieSliceType:
+			for _, i := range f.Interface().([]*ies.IE) {
+				if i != nil {
+					ie = append(ie, i)
+				}
+			}
+		}
+	}
+	return ie
+}
+
+// FindIE returns the top-level IE in msg looked up by type and instance.
+//
+// It returns ies.ErrIENotFound if no such IE exists in msg. Use ies.Find to look
+// up the IEs inside the grouped IEs as well.
+func FindIE(msg Message, typ, instance uint8) (*ies.IE, error) {
+	for _, i := range IEs(msg) {
+		if i.Type == typ && i.Instance() == instance {
+			return i, nil
+		}
+	}
+	return nil, ies.ErrIENotFound
+}
+
+// FindIEs returns all the top-level IEs in msg with the type given, regardless of
+// the instance.
+func FindIEs(msg Message, typ uint8) []*ies.IE {
+	var found []*ies.IE
+	for _, i := range IEs(msg) {
+		if i.Type == typ {
+			found = append(found, i)
+		}
+	}
+	return found
+}
@@ -0,0 +1,169 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import "github.com/wmnsk/go-gtp/v2/ies"
+
+// ForwardRelocationCompleteNotification is a ForwardRelocationCompleteNotification Header and its IEs above.
+type ForwardRelocationCompleteNotification struct {
+	*Header
+	IndicationFlags  *ies.IE
+	PrivateExtension *ies.IE
+	AdditionalIEs    []*ies.IE
+}
+
+// NewForwardRelocationCompleteNotification creates a new ForwardRelocationCompleteNotification.
+func NewForwardRelocationCompleteNotification(teid, seq uint32, ie ...*ies.IE) *ForwardRelocationCompleteNotification {
+	f := &ForwardRelocationCompleteNotification{
+		Header: NewHeader(
+			NewHeaderFlags(2, 0, 1),
+			MsgTypeForwardRelocationCompleteNotification, teid, seq, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Indication:
+			f.IndicationFlags = i
+		case ies.PrivateExtension:
+			if f.PrivateExtension == nil {
+				f.PrivateExtension = i
+			} else {
+				f.AdditionalIEs = append(f.AdditionalIEs, i)
+			}
+		default:
+			f.AdditionalIEs = append(f.AdditionalIEs, i)
+		}
+	}
+
+	f.SetLength()
+	return f
+}
+
+// Marshal serializes ForwardRelocationCompleteNotification into bytes.
+func (f *ForwardRelocationCompleteNotification) Marshal() ([]byte, error) {
+	b := make([]byte, f.MarshalLen())
+	if err := f.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MarshalTo serializes ForwardRelocationCompleteNotification into bytes.
+func (f *ForwardRelocationCompleteNotification) MarshalTo(b []byte) error {
+	if f.Header.Payload != nil {
+		f.Header.Payload = nil
+	}
+	f.Header.Payload = make([]byte, f.MarshalLen()-f.Header.MarshalLen())
+
+	offset := 0
+	if ie := f.IndicationFlags; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.PrivateExtension; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	for _, ie := range f.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(f.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	f.Header.SetLength()
+	return f.Header.MarshalTo(b)
+}
+
+// ParseForwardRelocationCompleteNotification decodes given bytes as ForwardRelocationCompleteNotification.
+func ParseForwardRelocationCompleteNotification(b []byte) (*ForwardRelocationCompleteNotification, error) {
+	f := &ForwardRelocationCompleteNotification{}
+	if err := f.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// UnmarshalBinary decodes given bytes as ForwardRelocationCompleteNotification.
+func (f *ForwardRelocationCompleteNotification) UnmarshalBinary(b []byte) error {
+	var err error
+	f.Header, err = ParseHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(f.Header.Payload) < 2 {
+		return nil
+	}
+
+	decodedIEs, err := ies.ParseMultiIEs(f.Header.Payload)
+	if err != nil {
+		return err
+	}
+	for _, i := range decodedIEs {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Indication:
+			f.IndicationFlags = i
+		case ies.PrivateExtension:
+			if f.PrivateExtension == nil {
+				f.PrivateExtension = i
+			} else {
+				f.AdditionalIEs = append(f.AdditionalIEs, i)
+			}
+		default:
+			f.AdditionalIEs = append(f.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length in int.
+func (f *ForwardRelocationCompleteNotification) MarshalLen() int {
+	l := f.Header.MarshalLen() - len(f.Header.Payload)
+	if ie := f.IndicationFlags; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.PrivateExtension; ie != nil {
+		l += ie.MarshalLen()
+	}
+
+	for _, ie := range f.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (f *ForwardRelocationCompleteNotification) SetLength() {
+	f.Header.Length = uint16(f.MarshalLen() - 4)
+}
+
+// MessageTypeName returns the name of protocol.
+func (f *ForwardRelocationCompleteNotification) MessageTypeName() string {
+	return "Forward Relocation Complete Notification"
+}
+
+// TEID returns the TEID in uint32.
+func (f *ForwardRelocationCompleteNotification) TEID() uint32 {
+	return f.Header.teid()
+}
@@ -0,0 +1,823 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import "github.com/wmnsk/go-gtp/v2/ies"
+
+// ForwardRelocationRequest is a ForwardRelocationRequest Header and its IEs above.
+type ForwardRelocationRequest struct {
+	*Header
+	IMSI                            *ies.IE
+	SenderFTEIDC                    *ies.IE
+	PDNConnections                  *ies.IE
+	SGWS11S4FTEIDC                  *ies.IE
+	SGWNodeName                     *ies.IE
+	UEMMContext                     *ies.IE
+	IndicationFlags                 *ies.IE
+	EUTRANTransparentContainer      *ies.IE
+	UTRANTransparentContainer       *ies.IE
+	BSSContainer                    *ies.IE
+	TargetIdentification            *ies.IE
+	S101IPAddress                   *ies.IE
+	S102IPAddress                   *ies.IE
+	S1APCause                       *ies.IE
+	RANAPCause                      *ies.IE
+	BSSGPCause                      *ies.IE
+	SourceIdentification            *ies.IE
+	SelectedPLMNID                  *ies.IE
+	Recovery                        *ies.IE
+	TraceInformation                *ies.IE
+	SubscribedRFSPIndex             *ies.IE
+	RFSPIndexInUse                  *ies.IE
+	CSGID                           *ies.IE
+	CSGMembershipIndication         *ies.IE
+	UETimeZone                      *ies.IE
+	ServingNetwork                  *ies.IE
+	MMESGSNLDN                      *ies.IE
+	AdditionalMMContextForSRVCC     *ies.IE
+	AdditionalFlagsForSRVCC         *ies.IE
+	STNSR                           *ies.IE
+	CMSISDN                         *ies.IE
+	MDTConfiguration                *ies.IE
+	SGSNNodeName                    *ies.IE
+	MMENodeName                     *ies.IE
+	UCI                             *ies.IE
+	MonitoringEventInformation      *ies.IE
+	UEUsageType                     *ies.IE
+	SCEFPDNConnection               *ies.IE
+	SourceUDPPortNumber             *ies.IE
+	ServingPLMNRateControl          *ies.IE
+	ExtendedTraceInformation        *ies.IE
+	RemainingRunningServiceGapTimer *ies.IE
+	PrivateExtension                *ies.IE
+	AdditionalIEs                   []*ies.IE
+}
+
+// NewForwardRelocationRequest creates a new ForwardRelocationRequest.
+func NewForwardRelocationRequest(teid, seq uint32, ie ...*ies.IE) *ForwardRelocationRequest {
+	f := &ForwardRelocationRequest{
+		Header: NewHeader(
+			NewHeaderFlags(2, 0, 1),
+			MsgTypeForwardRelocationRequest, teid, seq, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.IMSI:
+			f.IMSI = i
+		case ies.FullyQualifiedTEID:
+			switch i.Instance() {
+			case 0:
+				f.SenderFTEIDC = i
+			case 1:
+				f.SGWS11S4FTEIDC = i
+			default:
+				f.AdditionalIEs = append(f.AdditionalIEs, i)
+			}
+		case ies.PDNConnection:
+			f.PDNConnections = i
+		case ies.FullyQualifiedDomainName:
+			switch i.Instance() {
+			case 0:
+				f.SGWNodeName = i
+			case 1:
+				f.SGSNNodeName = i
+			case 2:
+				f.MMENodeName = i
+			default:
+				f.AdditionalIEs = append(f.AdditionalIEs, i)
+			}
+		case ies.MMContextEPSSecurityContextQuadrupletsAndQuintuplets,
+			ies.MMContextGSMKeyAndTriplets,
+			ies.MMContextGSMKeyUsedCipherAndQuintuplets,
+			ies.MMContextUMTSKeyAndQuintuplets,
+			ies.MMContextUMTSKeyQuadrupletsAndQuintuplets,
+			ies.MMContextUMTSKeyUsedCipherAndQuintuplets:
+			f.UEMMContext = i
+		case ies.Indication:
+			f.IndicationFlags = i
+		case ies.FContainer:
+			switch i.Instance() {
+			case 0:
+				f.EUTRANTransparentContainer = i
+			case 1:
+				f.UTRANTransparentContainer = i
+			case 2:
+				f.BSSContainer = i
+			default:
+				f.AdditionalIEs = append(f.AdditionalIEs, i)
+			}
+		case ies.TargetIdentification:
+			f.TargetIdentification = i
+		case ies.IPAddress:
+			switch i.Instance() {
+			case 0:
+				f.S101IPAddress = i
+			case 1:
+				f.S102IPAddress = i
+			default:
+				f.AdditionalIEs = append(f.AdditionalIEs, i)
+			}
+		case ies.FCause:
+			switch i.Instance() {
+			case 0:
+				f.S1APCause = i
+			case 1:
+				f.RANAPCause = i
+			case 2:
+				f.BSSGPCause = i
+			default:
+				f.AdditionalIEs = append(f.AdditionalIEs, i)
+			}
+		case ies.SourceIdentification:
+			f.SourceIdentification = i
+		case ies.PLMNID:
+			f.SelectedPLMNID = i
+		case ies.Recovery:
+			f.Recovery = i
+		case ies.TraceInformation:
+			f.TraceInformation = i
+		case ies.RFSPIndex:
+			switch i.Instance() {
+			case 0:
+				f.SubscribedRFSPIndex = i
+			case 1:
+				f.RFSPIndexInUse = i
+			default:
+				f.AdditionalIEs = append(f.AdditionalIEs, i)
+			}
+		case ies.CSGID:
+			f.CSGID = i
+		case ies.CSGMembershipIndication:
+			f.CSGMembershipIndication = i
+		case ies.UETimeZone:
+			f.UETimeZone = i
+		case ies.ServingNetwork:
+			f.ServingNetwork = i
+		case ies.LocalDistinguishedName:
+			f.MMESGSNLDN = i
+		case ies.AdditionalMMContextForSRVCC:
+			f.AdditionalMMContextForSRVCC = i
+		case ies.AdditionalFlagsForSRVCC:
+			f.AdditionalFlagsForSRVCC = i
+		case ies.STNSR:
+			f.STNSR = i
+		case ies.MSISDN:
+			f.CMSISDN = i
+		case ies.MDTConfiguration:
+			f.MDTConfiguration = i
+		case ies.UserCSGInformation:
+			f.UCI = i
+		case ies.MonitoringEventInformation:
+			f.MonitoringEventInformation = i
+		case ies.IntegerNumber:
+			switch i.Instance() {
+			case 0:
+				f.UEUsageType = i
+			case 1:
+				f.RemainingRunningServiceGapTimer = i
+			default:
+				f.AdditionalIEs = append(f.AdditionalIEs, i)
+			}
+		case ies.SCEFPDNConnection:
+			f.SCEFPDNConnection = i
+		case ies.PortNumber:
+			f.SourceUDPPortNumber = i
+		case ies.ServingPLMNRateControl:
+			f.ServingPLMNRateControl = i
+		case ies.ExtendedTraceInformation:
+			f.ExtendedTraceInformation = i
+		case ies.PrivateExtension:
+			if f.PrivateExtension == nil {
+				f.PrivateExtension = i
+			} else {
+				f.AdditionalIEs = append(f.AdditionalIEs, i)
+			}
+		default:
+			f.AdditionalIEs = append(f.AdditionalIEs, i)
+		}
+	}
+
+	f.SetLength()
+	return f
+}
+
+// Marshal serializes ForwardRelocationRequest into bytes.
+func (f *ForwardRelocationRequest) Marshal() ([]byte, error) {
+	b := make([]byte, f.MarshalLen())
+	if err := f.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MarshalTo serializes ForwardRelocationRequest into bytes.
+func (f *ForwardRelocationRequest) MarshalTo(b []byte) error {
+	if f.Header.Payload != nil {
+		f.Header.Payload = nil
+	}
+	f.Header.Payload = make([]byte, f.MarshalLen()-f.Header.MarshalLen())
+
+	offset := 0
+	if ie := f.IMSI; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.SenderFTEIDC; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.PDNConnections; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.SGWS11S4FTEIDC; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.SGWNodeName; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.UEMMContext; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.IndicationFlags; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.EUTRANTransparentContainer; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.UTRANTransparentContainer; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.BSSContainer; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.TargetIdentification; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.S101IPAddress; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.S102IPAddress; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.S1APCause; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.RANAPCause; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.BSSGPCause; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.SourceIdentification; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.SelectedPLMNID; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.Recovery; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.TraceInformation; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.SubscribedRFSPIndex; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.RFSPIndexInUse; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.CSGID; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.CSGMembershipIndication; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.UETimeZone; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.ServingNetwork; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.MMESGSNLDN; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.AdditionalMMContextForSRVCC; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.AdditionalFlagsForSRVCC; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.STNSR; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.CMSISDN; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.MDTConfiguration; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.SGSNNodeName; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.MMENodeName; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.UCI; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.MonitoringEventInformation; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.UEUsageType; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.SCEFPDNConnection; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.SourceUDPPortNumber; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.ServingPLMNRateControl; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.ExtendedTraceInformation; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.RemainingRunningServiceGapTimer; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := f.PrivateExtension; ie != nil {
+		if err := ie.MarshalTo(f.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	for _, ie := range f.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(f.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	f.Header.SetLength()
+	return f.Header.MarshalTo(b)
+}
+
+// ParseForwardRelocationRequest decodes given bytes as ForwardRelocationRequest.
+func ParseForwardRelocationRequest(b []byte) (*ForwardRelocationRequest, error) {
+	f := &ForwardRelocationRequest{}
+	if err := f.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// UnmarshalBinary decodes given bytes as ForwardRelocationRequest.
+func (f *ForwardRelocationRequest) UnmarshalBinary(b []byte) error {
+	var err error
+	f.Header, err = ParseHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(f.Header.Payload) < 2 {
+		return nil
+	}
+
+	decodedIEs, err := ies.ParseMultiIEs(f.Header.Payload)
+	if err != nil {
+		return err
+	}
+	for _, i := range decodedIEs {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.IMSI:
+			f.IMSI = i
+		case ies.FullyQualifiedTEID:
+			switch i.Instance() {
+			case 0:
+				f.SenderFTEIDC = i
+			case 1:
+				f.SGWS11S4FTEIDC = i
+			default:
+				f.AdditionalIEs = append(f.AdditionalIEs, i)
+			}
+		case ies.PDNConnection:
+			f.PDNConnections = i
+		case ies.FullyQualifiedDomainName:
+			switch i.Instance() {
+			case 0:
+				f.SGWNodeName = i
+			case 1:
+				f.SGSNNodeName = i
+			case 2:
+				f.MMENodeName = i
+			default:
+				f.AdditionalIEs = append(f.AdditionalIEs, i)
+			}
+		case ies.MMContextEPSSecurityContextQuadrupletsAndQuintuplets,
+			ies.MMContextGSMKeyAndTriplets,
+			ies.MMContextGSMKeyUsedCipherAndQuintuplets,
+			ies.MMContextUMTSKeyAndQuintuplets,
+			ies.MMContextUMTSKeyQuadrupletsAndQuintuplets,
+			ies.MMContextUMTSKeyUsedCipherAndQuintuplets:
+			f.UEMMContext = i
+		case ies.Indication:
+			f.IndicationFlags = i
+		case ies.FContainer:
+			switch i.Instance() {
+			case 0:
+				f.EUTRANTransparentContainer = i
+			case 1:
+				f.UTRANTransparentContainer = i
+			case 2:
+				f.BSSContainer = i
+			default:
+				f.AdditionalIEs = append(f.AdditionalIEs, i)
+			}
+		case ies.TargetIdentification:
+			f.TargetIdentification = i
+		case ies.IPAddress:
+			switch i.Instance() {
+			case 0:
+				f.S101IPAddress = i
+			case 1:
+				f.S102IPAddress = i
+			default:
+				f.AdditionalIEs = append(f.AdditionalIEs, i)
+			}
+		case ies.FCause:
+			switch i.Instance() {
+			case 0:
+				f.S1APCause = i
+			case 1:
+				f.RANAPCause = i
+			case 2:
+				f.BSSGPCause = i
+			default:
+				f.AdditionalIEs = append(f.AdditionalIEs, i)
+			}
+		case ies.SourceIdentification:
+			f.SourceIdentification = i
+		case ies.PLMNID:
+			f.SelectedPLMNID = i
+		case ies.Recovery:
+			f.Recovery = i
+		case ies.TraceInformation:
+			f.TraceInformation = i
+		case ies.RFSPIndex:
+			switch i.Instance() {
+			case 0:
+				f.SubscribedRFSPIndex = i
+			case 1:
+				f.RFSPIndexInUse = i
+			default:
+				f.AdditionalIEs = append(f.AdditionalIEs, i)
+			}
+		case ies.CSGID:
+			f.CSGID = i
+		case ies.CSGMembershipIndication:
+			f.CSGMembershipIndication = i
+		case ies.UETimeZone:
+			f.UETimeZone = i
+		case ies.ServingNetwork:
+			f.ServingNetwork = i
+		case ies.LocalDistinguishedName:
+			f.MMESGSNLDN = i
+		case ies.AdditionalMMContextForSRVCC:
+			f.AdditionalMMContextForSRVCC = i
+		case ies.AdditionalFlagsForSRVCC:
+			f.AdditionalFlagsForSRVCC = i
+		case ies.STNSR:
+			f.STNSR = i
+		case ies.MSISDN:
+			f.CMSISDN = i
+		case ies.MDTConfiguration:
+			f.MDTConfiguration = i
+		case ies.UserCSGInformation:
+			f.UCI = i
+		case ies.MonitoringEventInformation:
+			f.MonitoringEventInformation = i
+		case ies.IntegerNumber:
+			switch i.Instance() {
+			case 0:
+				f.UEUsageType = i
+			case 1:
+				f.RemainingRunningServiceGapTimer = i
+			default:
+				f.AdditionalIEs = append(f.AdditionalIEs, i)
+			}
+		case ies.SCEFPDNConnection:
+			f.SCEFPDNConnection = i
+		case ies.PortNumber:
+			f.SourceUDPPortNumber = i
+		case ies.ServingPLMNRateControl:
+			f.ServingPLMNRateControl = i
+		case ies.ExtendedTraceInformation:
+			f.ExtendedTraceInformation = i
+		case ies.PrivateExtension:
+			if f.PrivateExtension == nil {
+				f.PrivateExtension = i
+			} else {
+				f.AdditionalIEs = append(f.AdditionalIEs, i)
+			}
+		default:
+			f.AdditionalIEs = append(f.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length in int.
+func (f *ForwardRelocationRequest) MarshalLen() int {
+	l := f.Header.MarshalLen() - len(f.Header.Payload)
+	if ie := f.IMSI; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.SenderFTEIDC; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.PDNConnections; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.SGWS11S4FTEIDC; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.SGWNodeName; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.UEMMContext; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.IndicationFlags; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.EUTRANTransparentContainer; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.UTRANTransparentContainer; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.BSSContainer; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.TargetIdentification; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.S101IPAddress; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.S102IPAddress; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.S1APCause; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.RANAPCause; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.BSSGPCause; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.SourceIdentification; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.SelectedPLMNID; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.Recovery; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.TraceInformation; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.SubscribedRFSPIndex; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.RFSPIndexInUse; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.CSGID; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.CSGMembershipIndication; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.UETimeZone; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.ServingNetwork; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.MMESGSNLDN; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.AdditionalMMContextForSRVCC; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.AdditionalFlagsForSRVCC; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.STNSR; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.CMSISDN; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.MDTConfiguration; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.SGSNNodeName; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.MMENodeName; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.UCI; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.MonitoringEventInformation; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.UEUsageType; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.SCEFPDNConnection; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.SourceUDPPortNumber; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.ServingPLMNRateControl; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.ExtendedTraceInformation; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.RemainingRunningServiceGapTimer; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := f.PrivateExtension; ie != nil {
+		l += ie.MarshalLen()
+	}
+
+	for _, ie := range f.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (f *ForwardRelocationRequest) SetLength() {
+	f.Header.Length = uint16(f.MarshalLen() - 4)
+}
+
+// MessageTypeName returns the name of protocol.
+func (f *ForwardRelocationRequest) MessageTypeName() string {
+	return "Forward Relocation Request"
+}
+
+// TEID returns the TEID in uint32.
+func (f *ForwardRelocationRequest) TEID() uint32 {
+	return f.Header.teid()
+}
@@ -0,0 +1,50 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+	"github.com/wmnsk/go-gtp/v2/testutils"
+)
+
+func TestForwardRelocationRequest(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "Normal",
+			Structured: messages.NewForwardRelocationRequest(
+				testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+				ies.NewIMSI("123451234567890"),
+				ies.NewFullyQualifiedTEID(v2.IFTypeS10MMEGTPC, 0xffffffff, "1.1.1.1", ""),
+				ies.NewRecovery(0x80),
+				ies.NewServingNetwork("123", "45"),
+			),
+			Serialized: []byte{
+				// Header
+				0x48, 0x85, 0x00, 0x2d, 0x11, 0x22, 0x33, 0x44, 0x00, 0x00, 0x01, 0x00,
+				// IMSI
+				0x01, 0x00, 0x08, 0x00, 0x21, 0x43, 0x15, 0x32, 0x54, 0x76, 0x98, 0xf0,
+				// Sender F-TEID for Control Plane
+				0x57, 0x00, 0x09, 0x00, 0x8c, 0xff, 0xff, 0xff, 0xff, 0x01, 0x01, 0x01, 0x01,
+				// Recovery
+				0x03, 0x00, 0x01, 0x00, 0x80,
+				// Serving Network
+				0x53, 0x00, 0x03, 0x00, 0x21, 0xf3, 0x54,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializable, error) {
+		v, err := messages.ParseForwardRelocationRequest(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
@@ -0,0 +1,258 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import "github.com/wmnsk/go-gtp/v2/ies"
+
+// This file provides the constructors of the responses derived from the requests,
+// including the Failure Indications to the Commands and the Acknowledges to the
+// Notifications.
+//
+// The Sequence Number is copied from the request. The TEID is taken from the F-TEID
+// of the sender in the request if the request has one. Otherwise it is 0, and it
+// should be set with SetTEID to the TEID of the peer known from the Session, except
+// for the messages that are not associated with any Session.
+//
+// The IEs to be echoed back are copied from the request, unless an IE with the same
+// type and instance is given. The Recovery IE is not added here, as Conn adds it
+// following the RecoveryPolicy when the response is sent.
+
+// teidFrom returns the TEID in the F-TEID given, or 0 if it is nil or malformed.
+func teidFrom(fteid *ies.IE) uint32 {
+	if fteid == nil {
+		return 0
+	}
+	teid, err := fteid.TEID()
+	if err != nil {
+		return 0
+	}
+	return teid
+}
+
+// withEchoed returns ie with the IEs in echoed appended, except the nil ones and the
+// ones of the type and instance already in ie.
+func withEchoed(ie []*ies.IE, echoed ...*ies.IE) []*ies.IE {
+	for _, e := range echoed {
+		if e == nil {
+			continue
+		}
+		found := false
+		for _, i := range ie {
+			if i != nil && i.Type == e.Type && i.Instance() == e.Instance() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			ie = append(ie, e)
+		}
+	}
+	return ie
+}
+
+// NewEchoResponseFromRequest creates a new EchoResponse to req with the Recovery IE
+// generated with the restartCounter given, which is mandatory in Echo Response.
+func NewEchoResponseFromRequest(req *EchoRequest, restartCounter uint8, ie ...*ies.IE) *EchoResponse {
+	return NewEchoResponse(req.Sequence(), append([]*ies.IE{ies.NewRecovery(restartCounter)}, ie...)...)
+}
+
+// NewCreateSessionResponseFromRequest creates a new CreateSessionResponse to req
+// with the Cause and IEs given.
+//
+// The TEID is taken from Sender F-TEID for Control Plane in req.
+func NewCreateSessionResponseFromRequest(req *CreateSessionRequest, cause *ies.IE, ie ...*ies.IE) *CreateSessionResponse {
+	return NewCreateSessionResponse(teidFrom(req.SenderFTEIDC), req.Sequence(), append([]*ies.IE{cause}, ie...)...)
+}
+
+// NewModifyBearerResponseFromRequest creates a new ModifyBearerResponse to req with
+// the Cause and IEs given.
+//
+// The TEID is taken from Sender F-TEID for Control Plane in req.
+func NewModifyBearerResponseFromRequest(req *ModifyBearerRequest, cause *ies.IE, ie ...*ies.IE) *ModifyBearerResponse {
+	return NewModifyBearerResponse(teidFrom(req.SenderFTEIDC), req.Sequence(), append([]*ies.IE{cause}, ie...)...)
+}
+
+// NewDeleteSessionResponseFromRequest creates a new DeleteSessionResponse to req
+// with the Cause and IEs given.
+//
+// The TEID is taken from Sender F-TEID for Control Plane in req.
+func NewDeleteSessionResponseFromRequest(req *DeleteSessionRequest, cause *ies.IE, ie ...*ies.IE) *DeleteSessionResponse {
+	return NewDeleteSessionResponse(teidFrom(req.SenderFTEIDC), req.Sequence(), append([]*ies.IE{cause}, ie...)...)
+}
+
+// NewChangeNotificationResponseFromRequest creates a new ChangeNotificationResponse
+// to req with the Cause and IEs given.
+//
+// IMSI and MEI in req are echoed back.
+func NewChangeNotificationResponseFromRequest(req *ChangeNotificationRequest, cause *ies.IE, ie ...*ies.IE) *ChangeNotificationResponse {
+	return NewChangeNotificationResponse(0, req.Sequence(), withEchoed(append([]*ies.IE{cause}, ie...), req.IMSI, req.MEI)...)
+}
+
+// NewCreateBearerResponseFromRequest creates a new CreateBearerResponse to req with
+// the Cause and IEs given.
+func NewCreateBearerResponseFromRequest(req *CreateBearerRequest, cause *ies.IE, ie ...*ies.IE) *CreateBearerResponse {
+	return NewCreateBearerResponse(0, req.Sequence(), append([]*ies.IE{cause}, ie...)...)
+}
+
+// NewUpdateBearerResponseFromRequest creates a new UpdateBearerResponse to req with
+// the Cause and IEs given.
+func NewUpdateBearerResponseFromRequest(req *UpdateBearerRequest, cause *ies.IE, ie ...*ies.IE) *UpdateBearerResponse {
+	return NewUpdateBearerResponse(0, req.Sequence(), append([]*ies.IE{cause}, ie...)...)
+}
+
+// NewDeleteBearerResponseFromRequest creates a new DeleteBearerResponse to req with
+// the Cause and IEs given.
+//
+// Linked EBI in req is echoed back.
+func NewDeleteBearerResponseFromRequest(req *DeleteBearerRequest, cause *ies.IE, ie ...*ies.IE) *DeleteBearerResponse {
+	return NewDeleteBearerResponse(0, req.Sequence(), withEchoed(append([]*ies.IE{cause}, ie...), req.LinkedEBI)...)
+}
+
+// NewModifyBearerFailureIndicationFromCommand creates a new
+// ModifyBearerFailureIndication to req with the Cause and IEs given.
+//
+// The TEID is taken from Sender F-TEID for Control Plane in req.
+func NewModifyBearerFailureIndicationFromCommand(req *ModifyBearerCommand, cause *ies.IE, ie ...*ies.IE) *ModifyBearerFailureIndication {
+	return NewModifyBearerFailureIndication(teidFrom(req.SenderFTEIDC), req.Sequence(), append([]*ies.IE{cause}, ie...)...)
+}
+
+// NewDeleteBearerFailureIndicationFromCommand creates a new
+// DeleteBearerFailureIndication to req with the Cause and IEs given.
+//
+// The TEID is taken from Sender F-TEID for Control Plane in req.
+func NewDeleteBearerFailureIndicationFromCommand(req *DeleteBearerCommand, cause *ies.IE, ie ...*ies.IE) *DeleteBearerFailureIndication {
+	return NewDeleteBearerFailureIndication(teidFrom(req.SenderFTEIDC), req.Sequence(), append([]*ies.IE{cause}, ie...)...)
+}
+
+// NewBearerResourceFailureIndicationFromCommand creates a new
+// BearerResourceFailureIndication to req with the Cause and IEs given.
+//
+// The TEID is taken from Sender F-TEID for Control Plane in req. Linked EBI and PTI
+// in req are echoed back.
+func NewBearerResourceFailureIndicationFromCommand(req *BearerResourceCommand, cause *ies.IE, ie ...*ies.IE) *BearerResourceFailureIndication {
+	return NewBearerResourceFailureIndication(teidFrom(req.SenderFTEIDC), req.Sequence(), withEchoed(append([]*ies.IE{cause}, ie...), req.LinkedEBI, req.PTI)...)
+}
+
+// NewContextResponseFromRequest creates a new ContextResponse to req with the Cause
+// and IEs given.
+//
+// The TEID is taken from Address and TEID for Control Plane in req.
+func NewContextResponseFromRequest(req *ContextRequest, cause *ies.IE, ie ...*ies.IE) *ContextResponse {
+	return NewContextResponse(teidFrom(req.AddressAndTEIDForCPlane), req.Sequence(), append([]*ies.IE{cause}, ie...)...)
+}
+
+// NewContextAcknowledgeFromResponse creates a new ContextAcknowledge to req with the
+// Cause and IEs given.
+//
+// The TEID is taken from Sender F-TEID in req.
+func NewContextAcknowledgeFromResponse(req *ContextResponse, cause *ies.IE, ie ...*ies.IE) *ContextAcknowledge {
+	return NewContextAcknowledge(teidFrom(req.SenderFTEID), req.Sequence(), append([]*ies.IE{cause}, ie...)...)
+}
+
+// NewIdentificationResponseFromRequest creates a new IdentificationResponse to req
+// with the Cause and IEs given.
+func NewIdentificationResponseFromRequest(req *IdentificationRequest, cause *ies.IE, ie ...*ies.IE) *IdentificationResponse {
+	return NewIdentificationResponse(0, req.Sequence(), append([]*ies.IE{cause}, ie...)...)
+}
+
+// NewForwardRelocationResponseFromRequest creates a new ForwardRelocationResponse to
+// req with the Cause and IEs given.
+//
+// The TEID is taken from Sender F-TEID for Control Plane in req.
+func NewForwardRelocationResponseFromRequest(req *ForwardRelocationRequest, cause *ies.IE, ie ...*ies.IE) *ForwardRelocationResponse {
+	return NewForwardRelocationResponse(teidFrom(req.SenderFTEIDC), req.Sequence(), append([]*ies.IE{cause}, ie...)...)
+}
+
+// NewForwardRelocationCompleteAcknowledgeFromNotification creates a new
+// ForwardRelocationCompleteAcknowledge to req with the Cause and IEs given.
+func NewForwardRelocationCompleteAcknowledgeFromNotification(req *ForwardRelocationCompleteNotification, cause *ies.IE, ie ...*ies.IE) *ForwardRelocationCompleteAcknowledge {
+	return NewForwardRelocationCompleteAcknowledge(0, req.Sequence(), append([]*ies.IE{cause}, ie...)...)
+}
+
+// NewCreateIndirectDataForwardingTunnelResponseFromRequest creates a new
+// CreateIndirectDataForwardingTunnelResponse to req with the Cause and IEs given.
+//
+// The TEID is taken from Sender F-TEID for Control Plane in req.
+func NewCreateIndirectDataForwardingTunnelResponseFromRequest(req *CreateIndirectDataForwardingTunnelRequest, cause *ies.IE, ie ...*ies.IE) *CreateIndirectDataForwardingTunnelResponse {
+	return NewCreateIndirectDataForwardingTunnelResponse(teidFrom(req.SenderFTEIDC), req.Sequence(), append([]*ies.IE{cause}, ie...)...)
+}
+
+// NewDeleteIndirectDataForwardingTunnelResponseFromRequest creates a new
+// DeleteIndirectDataForwardingTunnelResponse to req with the Cause and IEs given.
+func NewDeleteIndirectDataForwardingTunnelResponseFromRequest(req *DeleteIndirectDataForwardingTunnelRequest, cause *ies.IE, ie ...*ies.IE) *DeleteIndirectDataForwardingTunnelResponse {
+	return NewDeleteIndirectDataForwardingTunnelResponse(0, req.Sequence(), append([]*ies.IE{cause}, ie...)...)
+}
+
+// NewReleaseAccessBearersResponseFromRequest creates a new
+// ReleaseAccessBearersResponse to req with the Cause and IEs given.
+func NewReleaseAccessBearersResponseFromRequest(req *ReleaseAccessBearersRequest, cause *ies.IE, ie ...*ies.IE) *ReleaseAccessBearersResponse {
+	return NewReleaseAccessBearersResponse(0, req.Sequence(), append([]*ies.IE{cause}, ie...)...)
+}
+
+// NewModifyAccessBearersResponseFromRequest creates a new
+// ModifyAccessBearersResponse to req with the Cause and IEs given.
+//
+// The TEID is taken from Sender F-TEID for Control Plane in req.
+func NewModifyAccessBearersResponseFromRequest(req *ModifyAccessBearersRequest, cause *ies.IE, ie ...*ies.IE) *ModifyAccessBearersResponse {
+	return NewModifyAccessBearersResponse(teidFrom(req.SenderFTEIDC), req.Sequence(), append([]*ies.IE{cause}, ie...)...)
+}
+
+// NewDownlinkDataNotificationAcknowledgeFromNotification creates a new
+// DownlinkDataNotificationAcknowledge to req with the Cause and IEs given.
+//
+// The TEID is taken from Sender F-TEID for Control Plane in req.
+func NewDownlinkDataNotificationAcknowledgeFromNotification(req *DownlinkDataNotification, cause *ies.IE, ie ...*ies.IE) *DownlinkDataNotificationAcknowledge {
+	return NewDownlinkDataNotificationAcknowledge(teidFrom(req.SenderFTEIDC), req.Sequence(), append([]*ies.IE{cause}, ie...)...)
+}
+
+// NewSuspendAcknowledgeFromNotification creates a new SuspendAcknowledge to req with
+// the Cause and IEs given.
+//
+// The TEID is taken from Sender F-TEID for Control Plane in req.
+func NewSuspendAcknowledgeFromNotification(req *SuspendNotification, cause *ies.IE, ie ...*ies.IE) *SuspendAcknowledge {
+	return NewSuspendAcknowledge(teidFrom(req.SenderFTEIDC), req.Sequence(), append([]*ies.IE{cause}, ie...)...)
+}
+
+// NewResumeAcknowledgeFromNotification creates a new ResumeAcknowledge to req with
+// the Cause and IEs given.
+//
+// The TEID is taken from Sender F-TEID for Control Plane in req.
+func NewResumeAcknowledgeFromNotification(req *ResumeNotification, cause *ies.IE, ie ...*ies.IE) *ResumeAcknowledge {
+	return NewResumeAcknowledge(teidFrom(req.SenderFTEIDC), req.Sequence(), append([]*ies.IE{cause}, ie...)...)
+}
+
+// NewPGWRestartNotificationAcknowledgeFromNotification creates a new
+// PGWRestartNotificationAcknowledge to req with the Cause and IEs given.
+func NewPGWRestartNotificationAcknowledgeFromNotification(req *PGWRestartNotification, cause *ies.IE, ie ...*ies.IE) *PGWRestartNotificationAcknowledge {
+	return NewPGWRestartNotificationAcknowledge(0, req.Sequence(), append([]*ies.IE{cause}, ie...)...)
+}
+
+// NewDeletePDNConnectionSetResponseFromRequest creates a new
+// DeletePDNConnectionSetResponse to req with the Cause and IEs given.
+func NewDeletePDNConnectionSetResponseFromRequest(req *DeletePDNConnectionSetRequest, cause *ies.IE, ie ...*ies.IE) *DeletePDNConnectionSetResponse {
+	return NewDeletePDNConnectionSetResponse(0, req.Sequence(), append([]*ies.IE{cause}, ie...)...)
+}
+
+// NewMBMSSessionStartResponseFromRequest creates a new MBMSSessionStartResponse to
+// req with the Cause and IEs given.
+//
+// The TEID is taken from Sender F-TEID for Control Plane in req.
+func NewMBMSSessionStartResponseFromRequest(req *MBMSSessionStartRequest, cause *ies.IE, ie ...*ies.IE) *MBMSSessionStartResponse {
+	return NewMBMSSessionStartResponse(teidFrom(req.SenderFTEIDC), req.Sequence(), append([]*ies.IE{cause}, ie...)...)
+}
+
+// NewMBMSSessionUpdateResponseFromRequest creates a new MBMSSessionUpdateResponse to
+// req with the Cause and IEs given.
+//
+// The TEID is taken from Sender F-TEID for Control Plane in req if present.
+func NewMBMSSessionUpdateResponseFromRequest(req *MBMSSessionUpdateRequest, cause *ies.IE, ie ...*ies.IE) *MBMSSessionUpdateResponse {
+	return NewMBMSSessionUpdateResponse(teidFrom(req.SenderFTEIDC), req.Sequence(), append([]*ies.IE{cause}, ie...)...)
+}
+
+// NewMBMSSessionStopResponseFromRequest creates a new MBMSSessionStopResponse to req
+// with the Cause and IEs given.
+func NewMBMSSessionStopResponseFromRequest(req *MBMSSessionStopRequest, cause *ies.IE, ie ...*ies.IE) *MBMSSessionStopResponse {
+	return NewMBMSSessionStopResponse(0, req.Sequence(), append([]*ies.IE{cause}, ie...)...)
+}
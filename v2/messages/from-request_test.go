@@ -0,0 +1,73 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+func TestResponsesFromRequests(t *testing.T) {
+	cause := ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil)
+
+	t.Run("TEIDFromFTEID", func(t *testing.T) {
+		req := messages.NewCreateSessionRequest(
+			0, 0x112233,
+			ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 0xdeadbeef, "127.0.0.1", ""),
+		)
+		res := messages.NewCreateSessionResponseFromRequest(req, cause)
+		if got, want := res.TEID(), uint32(0xdeadbeef); got != want {
+			t.Errorf("wrong TEID. want %#x, got: %#x", want, got)
+		}
+		if got, want := res.Sequence(), uint32(0x112233); got != want {
+			t.Errorf("wrong Sequence Number. want %#x, got: %#x", want, got)
+		}
+		if res.Cause != cause {
+			t.Errorf("wrong Cause: %v", res.Cause)
+		}
+	})
+
+	t.Run("MBMSSessionStart", func(t *testing.T) {
+		req := messages.NewMBMSSessionStartRequest(
+			0, 1, ies.NewFullyQualifiedTEID(v2.IFTypeSmMBMSGWGTPC, 0xdeadbeef, "127.0.0.1", ""),
+		)
+		if got, want := messages.NewMBMSSessionStartResponseFromRequest(req, cause).TEID(), uint32(0xdeadbeef); got != want {
+			t.Errorf("wrong TEID. want %#x, got: %#x", want, got)
+		}
+	})
+
+	t.Run("WithoutFTEID", func(t *testing.T) {
+		req := messages.NewIdentificationRequest(0x11111111, 1)
+		if got := messages.NewIdentificationResponseFromRequest(req, cause).TEID(); got != 0 {
+			t.Errorf("wrong TEID. want 0, got: %#x", got)
+		}
+	})
+
+	t.Run("Echoed", func(t *testing.T) {
+		req := messages.NewChangeNotificationRequest(
+			0, 1, ies.NewIMSI("123451234567890"), ies.NewMobileEquipmentIdentity("123450123456789"),
+		)
+		res := messages.NewChangeNotificationResponseFromRequest(req, cause)
+		if res.IMSI != req.IMSI || res.MEI != req.MEI {
+			t.Errorf("IMSI and MEI are not echoed: %v, %v", res.IMSI, res.MEI)
+		}
+
+		imsi := ies.NewIMSI("123451234567891")
+		res = messages.NewChangeNotificationResponseFromRequest(req, cause, imsi)
+		if res.IMSI != imsi || len(res.AdditionalIEs) != 0 {
+			t.Errorf("IMSI given is not preferred: %v, %v", res.IMSI, res.AdditionalIEs)
+		}
+	})
+
+	t.Run("Echo", func(t *testing.T) {
+		res := messages.NewEchoResponseFromRequest(messages.NewEchoRequest(5), 3)
+		if res.Sequence() != 5 || res.Recovery.MustRecovery() != 3 {
+			t.Errorf("wrong Echo Response: %v", res)
+		}
+	})
+}
@@ -0,0 +1,61 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+//go:build go1.18
+// +build go1.18
+
+package messages_test
+
+import (
+	"testing"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// FuzzParse decodes the input as Message in all the ParseModes and serializes it
+// again, which should not panic whatever the input is. The inputs that found the
+// bugs are kept in testdata/fuzz/FuzzParse.
+func FuzzParse(f *testing.F) {
+	for _, m := range []messages.Message{
+		messages.NewEchoRequest(0, ies.NewRecovery(0x80)),
+		messages.NewCreateSessionRequest(
+			0, 0, ies.NewIMSI("123451234567890"),
+			ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 1, "1.1.1.1", ""),
+			ies.NewBearerContext(ies.NewEPSBearerID(5)),
+		),
+		messages.NewCreateSessionResponse(
+			1, 1, ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+			ies.NewBearerContext(ies.NewEPSBearerID(5), ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil)),
+		),
+		messages.NewDeleteSessionRequest(1, 1, ies.NewEPSBearerID(5)),
+		messages.NewModifyBearerRequest(1, 1, ies.NewBearerContext(ies.NewEPSBearerID(5))),
+		messages.NewGeneric(0xff, 1, 1, ies.NewRecovery(1)),
+	} {
+		b, err := messages.Marshal(m)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(b)
+	}
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		for _, mode := range []messages.ParseMode{
+			messages.ParseModeDefault, messages.ParseModeLenient, messages.ParseModeStrict,
+		} {
+			m, err := messages.ParseWithMode(b, mode)
+			if err != nil {
+				continue
+			}
+			if _, err := messages.Marshal(m); err != nil {
+				continue
+			}
+			_ = messages.Dump(m)
+			for _, i := range messages.IEs(m) {
+				_, _ = i.Fields()
+			}
+		}
+	})
+}
@@ -82,13 +82,16 @@ func ParseHeader(b []byte) (*Header, error) {
 // UnmarshalBinary sets the values retrieved from byte sequence in GTPv2 header.
 func (h *Header) UnmarshalBinary(b []byte) error {
 	l := len(b)
-	if l < 12 {
+	if l < 8 {
 		return ErrTooShortToParse
 	}
 	h.Flags = b[0]
 	h.Type = b[1]
 	h.Length = binary.BigEndian.Uint16(b[2:4])
 	if h.HasTEID() {
+		if l < 12 {
+			return ErrTooShortToParse
+		}
 		h.TEID = binary.BigEndian.Uint32(b[4:8])
 		h.SequenceNumber = utils.Uint24To32(b[8:11])
 		h.Spare = b[11]
@@ -173,6 +176,17 @@ func (h *Header) SetTEID(teid uint32) {
 	h.TEID = teid
 }
 
+// ClearTEID sets the TEIDFlag to 0 to remove the TEID field from the header, which
+// is the case for the messages not associated with any Session(e.g., Echo Request).
+//
+// The Message Priority is also removed, as it is in the octet that exists only with
+// the TEID field.
+func (h *Header) ClearTEID() {
+	h.Flags &^= (1 << 3)
+	h.TEID = 0
+	h.ClearMessagePriority()
+}
+
 // Sequence returns SequenceNumber in uint32.
 func (h *Header) Sequence() uint32 {
 	return h.SequenceNumber
@@ -198,11 +212,45 @@ func (h *Header) SetMessagePriority(mp uint8) {
 // MessagePriority returns the value of MessagePriority.
 //
 // Note that this returns the value set in the field even if the MessagePriorityFlag
-// is not set to 1.
+// is not set to 1, and the value is in the upper 4 bits as it is on the wire. Use
+// Priority to get the value from 0 to 15.
 func (h *Header) MessagePriority() uint8 {
 	return h.Spare & 0xf0
 }
 
+// Priority returns the Message Priority from 0(the highest) to 15(the lowest), and
+// reports whether it is present, i.e., both the TEIDFlag and MessagePriorityFlag are
+// set to 1.
+func (h *Header) Priority() (uint8, bool) {
+	if !h.HasTEID() || !h.HasMessagePriority() {
+		return 0, false
+	}
+	return h.Spare >> 4, true
+}
+
+// SetPriority sets the MessagePriorityFlag to 1 and puts the Message Priority from
+// 0(the highest) to 15(the lowest) into MessagePriority field.
+//
+// It returns ErrInvalidPriority if p is larger than 15, and ErrPriorityWithoutTEID
+// if the header has no TEID field to carry the Message Priority.
+func (h *Header) SetPriority(p uint8) error {
+	if p > 15 {
+		return ErrInvalidPriority
+	}
+	if !h.HasTEID() {
+		return ErrPriorityWithoutTEID
+	}
+	h.SetMessagePriority(p << 4)
+	return nil
+}
+
+// ClearMessagePriority sets the MessagePriorityFlag to 0 and clears the
+// MessagePriority field.
+func (h *Header) ClearMessagePriority() {
+	h.Flags &^= (1 << 2)
+	h.Spare &= 0x0f
+}
+
 // Version returns the GTP version.
 func (h *Header) Version() int {
 	return 2
@@ -7,6 +7,7 @@ package messages_test
 import (
 	"testing"
 
+	"github.com/wmnsk/go-gtp/v2/ies"
 	"github.com/wmnsk/go-gtp/v2/messages"
 	"github.com/wmnsk/go-gtp/v2/testutils"
 )
@@ -40,3 +41,75 @@ func TestHeader(t *testing.T) {
 		return v, nil
 	})
 }
+
+func TestHeaderFlags(t *testing.T) {
+	t.Run("Priority", func(t *testing.T) {
+		msg := messages.NewDeleteSessionRequest(0x11111111, 0xdadada, ies.NewEPSBearerID(5))
+		if _, ok := msg.Priority(); ok {
+			t.Fatal("Priority should not be present by default")
+		}
+		if err := msg.SetPriority(16); err != messages.ErrInvalidPriority {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if err := msg.SetPriority(3); err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := msg.Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b[0] != 0x4c || b[11] != 0x30 {
+			t.Errorf("unexpected header: %x", b[:12])
+		}
+		parsed, err := messages.Parse(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mp, ok := parsed.Priority(); !ok || mp != 3 {
+			t.Errorf("unexpected priority: %d, %t", mp, ok)
+		}
+	})
+
+	t.Run("WithoutTEID", func(t *testing.T) {
+		msg := messages.NewDeleteSessionRequest(0x11111111, 0xdadada, ies.NewEPSBearerID(5))
+		if err := msg.SetPriority(3); err != nil {
+			t.Fatal(err)
+		}
+		msg.ClearTEID()
+		if err := msg.SetPriority(3); err != messages.ErrPriorityWithoutTEID {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		b, err := msg.Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(b) != 8+5 || b[0] != 0x40 {
+			t.Errorf("unexpected message: %x", b)
+		}
+		parsed, err := messages.Parse(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if parsed.HasTEID() || parsed.TEID() != 0 {
+			t.Errorf("TEID should not be present: %#x", parsed.TEID())
+		}
+		if _, ok := parsed.Priority(); ok {
+			t.Error("Priority should not be present")
+		}
+	})
+
+	t.Run("ShortWithoutTEID", func(t *testing.T) {
+		h, err := messages.ParseHeader([]byte{0x40, 0x01, 0x00, 0x04, 0x00, 0x00, 0x01, 0x00})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h.HasTEID() || h.Sequence() != 1 {
+			t.Errorf("unexpected header: %v", h)
+		}
+		if _, err := messages.ParseHeader([]byte{0x48, 0x01, 0x00, 0x04, 0x00, 0x00, 0x01, 0x00}); err != messages.ErrTooShortToParse {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,281 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import "github.com/wmnsk/go-gtp/v2/ies"
+
+// IdentificationRequest is a IdentificationRequest Header and its IEs above.
+type IdentificationRequest struct {
+	*Header
+	GUTI                         *ies.IE
+	RAI                          *ies.IE
+	PTMSI                        *ies.IE
+	PTMSISignature               *ies.IE
+	CompleteAttachRequestMessage *ies.IE
+	AddressForControlPlane       *ies.IE
+	UDPSourcePortNumber          *ies.IE
+	HopCounter                   *ies.IE
+	TargetPLMNID                 *ies.IE
+	PrivateExtension             *ies.IE
+	AdditionalIEs                []*ies.IE
+}
+
+// NewIdentificationRequest creates a new IdentificationRequest.
+func NewIdentificationRequest(teid, seq uint32, ie ...*ies.IE) *IdentificationRequest {
+	r := &IdentificationRequest{
+		Header: NewHeader(
+			NewHeaderFlags(2, 0, 1),
+			MsgTypeIdentificationRequest, teid, seq, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.GUTI:
+			r.GUTI = i
+		case ies.UserLocationInformation:
+			r.RAI = i
+		case ies.PacketTMSI:
+			r.PTMSI = i
+		case ies.PTMSISignature:
+			r.PTMSISignature = i
+		case ies.CompleteRequestMessage:
+			r.CompleteAttachRequestMessage = i
+		case ies.IPAddress:
+			r.AddressForControlPlane = i
+		case ies.PortNumber:
+			r.UDPSourcePortNumber = i
+		case ies.HopCounter:
+			r.HopCounter = i
+		case ies.ServingNetwork:
+			r.TargetPLMNID = i
+		case ies.PrivateExtension:
+			if r.PrivateExtension == nil {
+				r.PrivateExtension = i
+			} else {
+				r.AdditionalIEs = append(r.AdditionalIEs, i)
+			}
+		default:
+			r.AdditionalIEs = append(r.AdditionalIEs, i)
+		}
+	}
+
+	r.SetLength()
+	return r
+}
+
+// Marshal serializes IdentificationRequest into bytes.
+func (r *IdentificationRequest) Marshal() ([]byte, error) {
+	b := make([]byte, r.MarshalLen())
+	if err := r.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MarshalTo serializes IdentificationRequest into bytes.
+func (r *IdentificationRequest) MarshalTo(b []byte) error {
+	if r.Header.Payload != nil {
+		r.Header.Payload = nil
+	}
+	r.Header.Payload = make([]byte, r.MarshalLen()-r.Header.MarshalLen())
+
+	offset := 0
+	if ie := r.GUTI; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.RAI; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.PTMSI; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.PTMSISignature; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.CompleteAttachRequestMessage; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.AddressForControlPlane; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.UDPSourcePortNumber; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.HopCounter; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.TargetPLMNID; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.PrivateExtension; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	for _, ie := range r.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(r.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	r.Header.SetLength()
+	return r.Header.MarshalTo(b)
+}
+
+// ParseIdentificationRequest decodes given bytes as IdentificationRequest.
+func ParseIdentificationRequest(b []byte) (*IdentificationRequest, error) {
+	r := &IdentificationRequest{}
+	if err := r.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// UnmarshalBinary decodes given bytes as IdentificationRequest.
+func (r *IdentificationRequest) UnmarshalBinary(b []byte) error {
+	var err error
+	r.Header, err = ParseHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(r.Header.Payload) < 2 {
+		return nil
+	}
+
+	decodedIEs, err := ies.ParseMultiIEs(r.Header.Payload)
+	if err != nil {
+		return err
+	}
+	for _, i := range decodedIEs {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.GUTI:
+			r.GUTI = i
+		case ies.UserLocationInformation:
+			r.RAI = i
+		case ies.PacketTMSI:
+			r.PTMSI = i
+		case ies.PTMSISignature:
+			r.PTMSISignature = i
+		case ies.CompleteRequestMessage:
+			r.CompleteAttachRequestMessage = i
+		case ies.IPAddress:
+			r.AddressForControlPlane = i
+		case ies.PortNumber:
+			r.UDPSourcePortNumber = i
+		case ies.HopCounter:
+			r.HopCounter = i
+		case ies.ServingNetwork:
+			r.TargetPLMNID = i
+		case ies.PrivateExtension:
+			if r.PrivateExtension == nil {
+				r.PrivateExtension = i
+			} else {
+				r.AdditionalIEs = append(r.AdditionalIEs, i)
+			}
+		default:
+			r.AdditionalIEs = append(r.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length in int.
+func (r *IdentificationRequest) MarshalLen() int {
+	l := r.Header.MarshalLen() - len(r.Header.Payload)
+	if ie := r.GUTI; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.RAI; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.PTMSI; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.PTMSISignature; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.CompleteAttachRequestMessage; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.AddressForControlPlane; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.UDPSourcePortNumber; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.HopCounter; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.TargetPLMNID; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.PrivateExtension; ie != nil {
+		l += ie.MarshalLen()
+	}
+
+	for _, ie := range r.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (r *IdentificationRequest) SetLength() {
+	r.Header.Length = uint16(r.MarshalLen() - 4)
+}
+
+// MessageTypeName returns the name of protocol.
+func (r *IdentificationRequest) MessageTypeName() string {
+	return "Identification Request"
+}
+
+// TEID returns the TEID in uint32.
+func (r *IdentificationRequest) TEID() uint32 {
+	return r.Header.teid()
+}
@@ -0,0 +1,46 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+	"github.com/wmnsk/go-gtp/v2/testutils"
+)
+
+func TestIdentificationRequest(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "Normal",
+			Structured: messages.NewIdentificationRequest(
+				testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+				ies.NewGUTI("123", "45", 0x0123, 0x45, 0xdeadbeef),
+				ies.NewPTMSISignature(0xbeebee),
+				ies.NewIPAddress("1.1.1.1"),
+			),
+			Serialized: []byte{
+				// Header
+				0x48, 0x80, 0x00, 0x25, 0x11, 0x22, 0x33, 0x44, 0x00, 0x00, 0x01, 0x00,
+				// GUTI
+				0x75, 0x00, 0x0a, 0x00, 0x21, 0xf3, 0x54, 0x01, 0x23, 0x45, 0xde, 0xad, 0xbe, 0xef,
+				// P-TMSI Signature
+				0x70, 0x00, 0x03, 0x00, 0xbe, 0xeb, 0xee,
+				// Address for Control Plane
+				0x4a, 0x00, 0x04, 0x00, 0x01, 0x01, 0x01, 0x01,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializable, error) {
+		v, err := messages.ParseIdentificationRequest(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
@@ -0,0 +1,263 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import "github.com/wmnsk/go-gtp/v2/ies"
+
+// IdentificationResponse is a IdentificationResponse Header and its IEs above.
+type IdentificationResponse struct {
+	*Header
+	Cause                      *ies.IE
+	IMSI                       *ies.IE
+	UEMMContext                *ies.IE
+	TraceInformation           *ies.IE
+	UEUsageType                *ies.IE
+	MonitoringEventInformation *ies.IE
+	ExtendedTraceInformation   *ies.IE
+	PrivateExtension           *ies.IE
+	AdditionalIEs              []*ies.IE
+}
+
+// NewIdentificationResponse creates a new IdentificationResponse.
+func NewIdentificationResponse(teid, seq uint32, ie ...*ies.IE) *IdentificationResponse {
+	r := &IdentificationResponse{
+		Header: NewHeader(
+			NewHeaderFlags(2, 0, 1),
+			MsgTypeIdentificationResponse, teid, seq, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			r.Cause = i
+		case ies.IMSI:
+			r.IMSI = i
+		case ies.MMContextEPSSecurityContextQuadrupletsAndQuintuplets,
+			ies.MMContextGSMKeyAndTriplets,
+			ies.MMContextGSMKeyUsedCipherAndQuintuplets,
+			ies.MMContextUMTSKeyAndQuintuplets,
+			ies.MMContextUMTSKeyQuadrupletsAndQuintuplets,
+			ies.MMContextUMTSKeyUsedCipherAndQuintuplets:
+			r.UEMMContext = i
+		case ies.TraceInformation:
+			r.TraceInformation = i
+		case ies.IntegerNumber:
+			r.UEUsageType = i
+		case ies.MonitoringEventInformation:
+			r.MonitoringEventInformation = i
+		case ies.ExtendedTraceInformation:
+			r.ExtendedTraceInformation = i
+		case ies.PrivateExtension:
+			if r.PrivateExtension == nil {
+				r.PrivateExtension = i
+			} else {
+				r.AdditionalIEs = append(r.AdditionalIEs, i)
+			}
+		default:
+			r.AdditionalIEs = append(r.AdditionalIEs, i)
+		}
+	}
+
+	r.SetLength()
+	return r
+}
+
+// Marshal serializes IdentificationResponse into bytes.
+func (r *IdentificationResponse) Marshal() ([]byte, error) {
+	b := make([]byte, r.MarshalLen())
+	if err := r.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MarshalTo serializes IdentificationResponse into bytes.
+func (r *IdentificationResponse) MarshalTo(b []byte) error {
+	if r.Header.Payload != nil {
+		r.Header.Payload = nil
+	}
+	r.Header.Payload = make([]byte, r.MarshalLen()-r.Header.MarshalLen())
+
+	offset := 0
+	if ie := r.Cause; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.IMSI; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.UEMMContext; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.TraceInformation; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.UEUsageType; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.MonitoringEventInformation; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.ExtendedTraceInformation; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.PrivateExtension; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	for _, ie := range r.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(r.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	r.Header.SetLength()
+	return r.Header.MarshalTo(b)
+}
+
+// ParseIdentificationResponse decodes given bytes as IdentificationResponse.
+func ParseIdentificationResponse(b []byte) (*IdentificationResponse, error) {
+	r := &IdentificationResponse{}
+	if err := r.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// UnmarshalBinary decodes given bytes as IdentificationResponse.
+func (r *IdentificationResponse) UnmarshalBinary(b []byte) error {
+	var err error
+	r.Header, err = ParseHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(r.Header.Payload) < 2 {
+		return nil
+	}
+
+	decodedIEs, err := ies.ParseMultiIEs(r.Header.Payload)
+	if err != nil {
+		return err
+	}
+	for _, i := range decodedIEs {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			r.Cause = i
+		case ies.IMSI:
+			r.IMSI = i
+		case ies.MMContextEPSSecurityContextQuadrupletsAndQuintuplets,
+			ies.MMContextGSMKeyAndTriplets,
+			ies.MMContextGSMKeyUsedCipherAndQuintuplets,
+			ies.MMContextUMTSKeyAndQuintuplets,
+			ies.MMContextUMTSKeyQuadrupletsAndQuintuplets,
+			ies.MMContextUMTSKeyUsedCipherAndQuintuplets:
+			r.UEMMContext = i
+		case ies.TraceInformation:
+			r.TraceInformation = i
+		case ies.IntegerNumber:
+			r.UEUsageType = i
+		case ies.MonitoringEventInformation:
+			r.MonitoringEventInformation = i
+		case ies.ExtendedTraceInformation:
+			r.ExtendedTraceInformation = i
+		case ies.PrivateExtension:
+			if r.PrivateExtension == nil {
+				r.PrivateExtension = i
+			} else {
+				r.AdditionalIEs = append(r.AdditionalIEs, i)
+			}
+		default:
+			r.AdditionalIEs = append(r.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length in int.
+func (r *IdentificationResponse) MarshalLen() int {
+	l := r.Header.MarshalLen() - len(r.Header.Payload)
+	if ie := r.Cause; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.IMSI; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.UEMMContext; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.TraceInformation; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.UEUsageType; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.MonitoringEventInformation; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.ExtendedTraceInformation; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.PrivateExtension; ie != nil {
+		l += ie.MarshalLen()
+	}
+
+	for _, ie := range r.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (r *IdentificationResponse) SetLength() {
+	r.Header.Length = uint16(r.MarshalLen() - 4)
+}
+
+// MessageTypeName returns the name of protocol.
+func (r *IdentificationResponse) MessageTypeName() string {
+	return "Identification Response"
+}
+
+// TEID returns the TEID in uint32.
+func (r *IdentificationResponse) TEID() uint32 {
+	return r.Header.teid()
+}
@@ -0,0 +1,74 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import "github.com/wmnsk/go-gtp/v2/ies"
+
+// IndirectForwardingBearerContext is the IEs in the Bearer Context IE in Create
+// Indirect Data Forwarding Tunnel Request and Response, looked up by their instances.
+//
+// The names of the F-TEIDs are the ones in the request (TS 29.274 Table 7.2.18-2).
+// The response uses the same instances for the F-TEIDs of SGW allocated for the
+// forwarding, e.g., SGWFTEIDForDL is the S1-U SGW F-TEID for DL data forwarding.
+type IndirectForwardingBearerContext struct {
+	EBI   *ies.IE
+	Cause *ies.IE
+
+	ENBFTEIDForDL  *ies.IE
+	SGWFTEIDForDL  *ies.IE
+	SGSNFTEIDForDL *ies.IE
+	RNCFTEIDForDL  *ies.IE
+	ENBFTEIDForUL  *ies.IE
+	SGWFTEIDForUL  *ies.IE
+	MMEFTEIDForDL  *ies.IE
+
+	AdditionalIEs []*ies.IE
+}
+
+// ParseIndirectForwardingBearerContext decodes the Bearer Context IE given as
+// IndirectForwardingBearerContext.
+func ParseIndirectForwardingBearerContext(ie *ies.IE) (*IndirectForwardingBearerContext, error) {
+	if ie == nil {
+		return nil, ErrTooShortToParse
+	}
+	if ie.Type != ies.BearerContext {
+		return nil, &ies.InvalidTypeError{Type: ie.Type}
+	}
+
+	bc := &IndirectForwardingBearerContext{}
+	for _, i := range ie.ChildIEs {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.EPSBearerID:
+			bc.EBI = i
+		case ies.Cause:
+			bc.Cause = i
+		case ies.FullyQualifiedTEID:
+			switch i.Instance() {
+			case 0:
+				bc.ENBFTEIDForDL = i
+			case 1:
+				bc.SGWFTEIDForDL = i
+			case 2:
+				bc.SGSNFTEIDForDL = i
+			case 3:
+				bc.RNCFTEIDForDL = i
+			case 4:
+				bc.ENBFTEIDForUL = i
+			case 5:
+				bc.SGWFTEIDForUL = i
+			case 6:
+				bc.MMEFTEIDForDL = i
+			default:
+				bc.AdditionalIEs = append(bc.AdditionalIEs, i)
+			}
+		default:
+			bc.AdditionalIEs = append(bc.AdditionalIEs, i)
+		}
+	}
+	return bc, nil
+}
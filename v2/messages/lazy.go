@@ -0,0 +1,94 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+)
+
+// Lazy is a Message of any type whose IEs are not decoded until they are needed.
+//
+// This is for the nodes that only look at the header to route the messages, e.g.,
+// by TEID or message type, without paying the cost of decoding all the IEs. The IEs
+// are kept as the raw bytes in Header.Payload, and can be looked up one by one with
+// FindIE or decoded at once with IEs or Decode.
+type Lazy struct {
+	*Header
+}
+
+// ParseLazy decodes the header of the given bytes as Lazy, leaving the IEs undecoded.
+//
+// Note that the Lazy returned refers to b, and thus b must not be modified while it
+// is in use.
+func ParseLazy(b []byte) (*Lazy, error) {
+	l := &Lazy{}
+	if err := l.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// UnmarshalBinary decodes the header of a given byte sequence as a Lazy.
+func (l *Lazy) UnmarshalBinary(b []byte) error {
+	var err error
+	l.Header, err = ParseHeader(b)
+	return err
+}
+
+// MessageTypeName returns the name of the message type in the header.
+func (l *Lazy) MessageTypeName() string {
+	m := newMessage(l.Header.Type)
+	if _, ok := m.(*Generic); ok {
+		return fmt.Sprintf("Unknown (%d)", l.Header.Type)
+	}
+	return m.MessageTypeName()
+}
+
+// TEID returns the TEID in uint32.
+func (l *Lazy) TEID() uint32 {
+	return l.Header.teid()
+}
+
+// IEs decodes all the top-level IEs in the payload.
+func (l *Lazy) IEs() ([]*ies.IE, error) {
+	return ies.ParseMultiIEs(l.Header.Payload)
+}
+
+// FindIE decodes only the first top-level IE in the payload that matches the type
+// and instance given, skipping the others without decoding them.
+//
+// It returns ies.ErrIENotFound if no such IE exists.
+func (l *Lazy) FindIE(typ, instance uint8) (*ies.IE, error) {
+	b := l.Header.Payload
+	for len(b) > 0 {
+		if len(b) < 4 {
+			return nil, ErrTooShortToParse
+		}
+		n := 4 + int(binary.BigEndian.Uint16(b[1:3]))
+		if n > len(b) {
+			return nil, ErrInvalidLength
+		}
+		if b[0] == typ && b[3]&0x0f == instance {
+			return ies.Parse(b[:n])
+		}
+		b = b[n:]
+	}
+	return nil, ies.ErrIENotFound
+}
+
+// Decode decodes the Lazy as the Message of its type, as Parse does.
+//
+// The changes made to the header with SetTEID, SetSequenceNumber, etc. are reflected
+// in the Message returned.
+func (l *Lazy) Decode() (Message, error) {
+	b, err := l.Header.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	return Parse(b)
+}
@@ -0,0 +1,122 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+	"github.com/wmnsk/go-gtp/v2/testutils"
+)
+
+func TestParseLazy(t *testing.T) {
+	pgwFTEID := ies.NewFullyQualifiedTEID(v2.IFTypeS5S8PGWGTPC, 0x22222222, "127.0.0.1", "").WithInstance(1)
+	b, err := messages.NewCreateSessionRequest(
+		testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+		ies.NewIMSI("123451234567890"),
+		ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 0x11111111, "127.0.0.1", ""),
+		pgwFTEID,
+	).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := messages.ParseLazy(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := l.MessageType(); got != messages.MsgTypeCreateSessionRequest {
+		t.Errorf("wrong message type: %d", got)
+	}
+	if got := l.MessageTypeName(); got != "Create Session Request" {
+		t.Errorf("wrong message type name: %s", got)
+	}
+	if got := l.TEID(); got != testutils.TestBearerInfo.TEID {
+		t.Errorf("wrong TEID: %#x", got)
+	}
+
+	t.Run("FindIE", func(t *testing.T) {
+		got, err := l.FindIE(ies.FullyQualifiedTEID, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(got.Payload, pgwFTEID.Payload); diff != "" {
+			t.Error(diff)
+		}
+		if _, err := l.FindIE(ies.Recovery, 0); err != ies.ErrIENotFound {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if len(messages.FindIEs(l, ies.FullyQualifiedTEID)) != 2 {
+			t.Error("messages.FindIEs should work with Lazy")
+		}
+	})
+
+	t.Run("Decode", func(t *testing.T) {
+		l.SetTEID(0x33333333)
+		m, err := l.Decode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		csReq, ok := m.(*messages.CreateSessionRequest)
+		if !ok {
+			t.Fatalf("wrong type: %T", m)
+		}
+		if csReq.TEID() != 0x33333333 {
+			t.Errorf("TEID should be updated: %#x", csReq.TEID())
+		}
+		if got := csReq.IMSI.MustIMSI(); got != "123451234567890" {
+			t.Errorf("wrong IMSI: %s", got)
+		}
+	})
+
+	t.Run("Marshal", func(t *testing.T) {
+		l, err := messages.ParseLazy(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := messages.Marshal(l)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(got, b); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
+
+func BenchmarkParseLazy(b *testing.B) {
+	raw, err := messages.NewCreateSessionRequest(
+		0, 1,
+		ies.NewIMSI("123451234567890"),
+		ies.NewMSISDN("123450123456789"),
+		ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 0x11111111, "127.0.0.1", ""),
+		ies.NewAccessPointName("some.apn.example"),
+		ies.NewBearerContext(ies.NewEPSBearerID(0x05)),
+	).Marshal()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("Parse", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := messages.Parse(raw); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("ParseLazy", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := messages.ParseLazy(raw); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,97 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"encoding/binary"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+)
+
+// Limits is a set of limits applied before decoding the messages, to protect the
+// node from the crafted messages that cost much to be decoded.
+//
+// The zero value of each field means no limit.
+type Limits struct {
+	// MaxMessageLength is the maximum length of a message including its header.
+	MaxMessageLength int
+	// MaxIEs is the maximum number of IEs in a message, including the ones in
+	// the grouped IEs.
+	MaxIEs int
+	// MaxGroupedIEDepth is the maximum depth of nested grouped IEs. The IEs at the
+	// top level of a message are at depth 1.
+	MaxGroupedIEDepth int
+}
+
+// Check checks if the given bytes of a message are within the limits.
+//
+// It returns MessageTooLongError, TooManyIEsError or IENestingTooDeepError if the
+// message exceeds any of the limits. Malformed IEs are not reported here and left to
+// be detected when decoding the message.
+func (l *Limits) Check(b []byte) error {
+	if l == nil {
+		return nil
+	}
+
+	if l.MaxMessageLength > 0 && len(b) > l.MaxMessageLength {
+		return &MessageTooLongError{Length: len(b), Max: l.MaxMessageLength}
+	}
+	if l.MaxIEs <= 0 && l.MaxGroupedIEDepth <= 0 {
+		return nil
+	}
+
+	offset := 8
+	if len(b) > 0 && (b[0]>>3)&0x01 == 1 {
+		offset = 12
+	}
+	if len(b) < offset {
+		return nil
+	}
+
+	count := 0
+	return l.checkIEs(b[offset:], 1, &count)
+}
+
+func (l *Limits) checkIEs(b []byte, depth int, count *int) error {
+	if l.MaxGroupedIEDepth > 0 && depth > l.MaxGroupedIEDepth {
+		return &IENestingTooDeepError{Max: l.MaxGroupedIEDepth}
+	}
+
+	for len(b) >= 4 {
+		*count++
+		if l.MaxIEs > 0 && *count > l.MaxIEs {
+			return &TooManyIEsError{Max: l.MaxIEs}
+		}
+
+		n := int(binary.BigEndian.Uint16(b[1:3])) + 4
+		if n > len(b) {
+			return nil
+		}
+
+		if (&ies.IE{Type: b[0]}).IsGrouped() {
+			if err := l.checkIEs(b[4:n], depth+1, count); err != nil {
+				return err
+			}
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+// ParseWithLimits decodes the given bytes as Message after checking them with the
+// limits. Giving nil limits works the same as Parse.
+func ParseWithLimits(b []byte, l *Limits) (Message, error) {
+	if err := l.Check(b); err != nil {
+		return nil, err
+	}
+	return Parse(b)
+}
+
+func parseWithLimitsAndMode(b []byte, l *Limits, mode ParseMode) (Message, error) {
+	if err := l.Check(b); err != nil {
+		return nil, err
+	}
+	return ParseWithMode(b, mode)
+}
@@ -0,0 +1,52 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+	"github.com/wmnsk/go-gtp/v2/testutils"
+)
+
+func TestParseWithLimits(t *testing.T) {
+	nested := ies.NewBearerContext(ies.NewBearerContext(ies.NewEPSBearerID(0x05)))
+	b, err := messages.NewCreateBearerRequest(
+		testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+		ies.NewEPSBearerID(0x05), nested,
+	).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		description string
+		limits      *messages.Limits
+		wantErr     string
+	}{
+		{"NoLimits", nil, ""},
+		{"WithinLimits", &messages.Limits{MaxMessageLength: len(b), MaxIEs: 4, MaxGroupedIEDepth: 3}, ""},
+		{"TooLong", &messages.Limits{MaxMessageLength: len(b) - 1}, "*messages.MessageTooLongError"},
+		{"TooManyIEs", &messages.Limits{MaxIEs: 3}, "*messages.TooManyIEsError"},
+		{"TooDeep", &messages.Limits{MaxGroupedIEDepth: 2}, "*messages.IENestingTooDeepError"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			_, err := messages.ParseWithLimits(b, c.limits)
+			if c.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if got := fmt.Sprintf("%T", err); got != c.wantErr {
+				t.Errorf("wrong error. want: %s, got: %s(%v)", c.wantErr, got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+	"github.com/wmnsk/go-gtp/v2/testutils"
+)
+
+func TestMBMSSessionStartRequest(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "Normal",
+			Structured: messages.NewMBMSSessionStartRequest(
+				0, testutils.TestBearerInfo.Seq,
+				ies.NewFullyQualifiedTEID(v2.IFTypeSmMBMSGWGTPC, 0xffffffff, "1.1.1.1", ""),
+				ies.NewTMGI(0x123456, "123", "45"),
+				ies.NewMBMSSessionDuration(time.Hour),
+				ies.New(ies.MBMSServiceArea, 0, []byte{0x00, 0x00, 0x01}),
+				ies.NewMBMSFlowIdentifier(1),
+				ies.NewBearerQoS(0, 2, 0, 0xff, 0, 0, 0, 0),
+				ies.New(ies.MBMSIPMulticastDistribution, 0, []byte{
+					0x11, 0x22, 0x33, 0x44, 0x04, 0xef, 0x00, 0x00, 0x01, 0x04, 0x0a, 0x00, 0x00, 0x01, 0x00,
+				}),
+				ies.NewMBMSFlags(0, 1),
+			),
+			Serialized: []byte{
+				// Header
+				0x48, 0xe7, 0x00, 0x65, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00,
+				// Sender F-TEID for Control Plane
+				0x57, 0x00, 0x09, 0x00, 0x98, 0xff, 0xff, 0xff, 0xff, 0x01, 0x01, 0x01, 0x01,
+				// TMGI
+				0x9e, 0x00, 0x06, 0x00, 0x12, 0x34, 0x56, 0x21, 0xf3, 0x54,
+				// MBMS Session Duration
+				0x8a, 0x00, 0x03, 0x00, 0x07, 0x08, 0x00,
+				// MBMS Service Area
+				0x8b, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01,
+				// MBMS Flow Identifier
+				0x8d, 0x00, 0x02, 0x00, 0x00, 0x01,
+				// QoS Profile
+				0x50, 0x00, 0x16, 0x00, 0x08, 0xff,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				// MBMS IP Multicast Distribution
+				0x8e, 0x00, 0x0f, 0x00,
+				0x11, 0x22, 0x33, 0x44, 0x04, 0xef, 0x00, 0x00, 0x01, 0x04, 0x0a, 0x00, 0x00, 0x01, 0x00,
+				// MBMS Flags
+				0xab, 0x00, 0x01, 0x00, 0x01,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializable, error) {
+		v, err := messages.ParseMBMSSessionStartRequest(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
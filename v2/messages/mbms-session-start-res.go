@@ -0,0 +1,235 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import "github.com/wmnsk/go-gtp/v2/ies"
+
+// MBMSSessionStartResponse is a MBMSSessionStartResponse Header and its IEs above.
+type MBMSSessionStartResponse struct {
+	*Header
+	Cause                       *ies.IE
+	SenderFTEIDC                *ies.IE
+	MBMSDistributionAcknowledge *ies.IE
+	SnUSGSNFTEID                *ies.IE
+	Recovery                    *ies.IE
+	PrivateExtension            *ies.IE
+	AdditionalIEs               []*ies.IE
+}
+
+// NewMBMSSessionStartResponse creates a new MBMSSessionStartResponse.
+func NewMBMSSessionStartResponse(teid, seq uint32, ie ...*ies.IE) *MBMSSessionStartResponse {
+	m := &MBMSSessionStartResponse{
+		Header: NewHeader(
+			NewHeaderFlags(2, 0, 1),
+			MsgTypeMBMSSessionStartResponse, teid, seq, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			m.Cause = i
+		case ies.FullyQualifiedTEID:
+			switch i.Instance() {
+			case 0:
+				m.SenderFTEIDC = i
+			case 1:
+				m.SnUSGSNFTEID = i
+			default:
+				m.AdditionalIEs = append(m.AdditionalIEs, i)
+			}
+		case ies.MBMSDistributionAcknowledge:
+			m.MBMSDistributionAcknowledge = i
+		case ies.Recovery:
+			m.Recovery = i
+		case ies.PrivateExtension:
+			if m.PrivateExtension == nil {
+				m.PrivateExtension = i
+			} else {
+				m.AdditionalIEs = append(m.AdditionalIEs, i)
+			}
+		default:
+			m.AdditionalIEs = append(m.AdditionalIEs, i)
+		}
+	}
+
+	m.SetLength()
+	return m
+}
+
+// Marshal serializes MBMSSessionStartResponse into bytes.
+func (m *MBMSSessionStartResponse) Marshal() ([]byte, error) {
+	b := make([]byte, m.MarshalLen())
+	if err := m.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MarshalTo serializes MBMSSessionStartResponse into bytes.
+func (m *MBMSSessionStartResponse) MarshalTo(b []byte) error {
+	if m.Header.Payload != nil {
+		m.Header.Payload = nil
+	}
+	m.Header.Payload = make([]byte, m.MarshalLen()-m.Header.MarshalLen())
+
+	offset := 0
+	if ie := m.Cause; ie != nil {
+		if err := ie.MarshalTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := m.SenderFTEIDC; ie != nil {
+		if err := ie.MarshalTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := m.MBMSDistributionAcknowledge; ie != nil {
+		if err := ie.MarshalTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := m.SnUSGSNFTEID; ie != nil {
+		if err := ie.MarshalTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := m.Recovery; ie != nil {
+		if err := ie.MarshalTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := m.PrivateExtension; ie != nil {
+		if err := ie.MarshalTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	for _, ie := range m.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(m.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	m.Header.SetLength()
+	return m.Header.MarshalTo(b)
+}
+
+// ParseMBMSSessionStartResponse decodes given bytes as MBMSSessionStartResponse.
+func ParseMBMSSessionStartResponse(b []byte) (*MBMSSessionStartResponse, error) {
+	m := &MBMSSessionStartResponse{}
+	if err := m.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// UnmarshalBinary decodes given bytes as MBMSSessionStartResponse.
+func (m *MBMSSessionStartResponse) UnmarshalBinary(b []byte) error {
+	var err error
+	m.Header, err = ParseHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(m.Header.Payload) < 2 {
+		return nil
+	}
+
+	decodedIEs, err := ies.ParseMultiIEs(m.Header.Payload)
+	if err != nil {
+		return err
+	}
+	for _, i := range decodedIEs {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			m.Cause = i
+		case ies.FullyQualifiedTEID:
+			switch i.Instance() {
+			case 0:
+				m.SenderFTEIDC = i
+			case 1:
+				m.SnUSGSNFTEID = i
+			default:
+				m.AdditionalIEs = append(m.AdditionalIEs, i)
+			}
+		case ies.MBMSDistributionAcknowledge:
+			m.MBMSDistributionAcknowledge = i
+		case ies.Recovery:
+			m.Recovery = i
+		case ies.PrivateExtension:
+			if m.PrivateExtension == nil {
+				m.PrivateExtension = i
+			} else {
+				m.AdditionalIEs = append(m.AdditionalIEs, i)
+			}
+		default:
+			m.AdditionalIEs = append(m.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length in int.
+func (m *MBMSSessionStartResponse) MarshalLen() int {
+	l := m.Header.MarshalLen() - len(m.Header.Payload)
+	if ie := m.Cause; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := m.SenderFTEIDC; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := m.MBMSDistributionAcknowledge; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := m.SnUSGSNFTEID; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := m.Recovery; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := m.PrivateExtension; ie != nil {
+		l += ie.MarshalLen()
+	}
+
+	for _, ie := range m.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (m *MBMSSessionStartResponse) SetLength() {
+	m.Header.Length = uint16(m.MarshalLen() - 4)
+}
+
+// MessageTypeName returns the name of protocol.
+func (m *MBMSSessionStartResponse) MessageTypeName() string {
+	return "MBMS Session Start Response"
+}
+
+// TEID returns the TEID in uint32.
+func (m *MBMSSessionStartResponse) TEID() uint32 {
+	return m.Header.teid()
+}
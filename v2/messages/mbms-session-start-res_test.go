@@ -0,0 +1,47 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+	"github.com/wmnsk/go-gtp/v2/testutils"
+)
+
+func TestMBMSSessionStartResponse(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "Normal",
+			Structured: messages.NewMBMSSessionStartResponse(
+				testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+				ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+				ies.NewFullyQualifiedTEID(v2.IFTypeSnMBMSGWGTPC, 0xffffffff, "1.1.1.2", ""),
+				ies.NewFullyQualifiedTEID(v2.IFTypeS4SGSNGTPU, 0xffffffff, "1.1.1.3", "").WithInstance(1),
+			),
+			Serialized: []byte{
+				// Header
+				0x48, 0xe8, 0x00, 0x28, 0x11, 0x22, 0x33, 0x44, 0x00, 0x00, 0x01, 0x00,
+				// Cause
+				0x02, 0x00, 0x02, 0x00, 0x10, 0x00,
+				// Sender F-TEID for Control Plane
+				0x57, 0x00, 0x09, 0x00, 0x99, 0xff, 0xff, 0xff, 0xff, 0x01, 0x01, 0x01, 0x02,
+				// Sn-U SGSN F-TEID
+				0x57, 0x00, 0x09, 0x01, 0x8f, 0xff, 0xff, 0xff, 0xff, 0x01, 0x01, 0x01, 0x03,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializable, error) {
+		v, err := messages.ParseMBMSSessionStartResponse(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
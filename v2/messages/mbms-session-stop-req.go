@@ -0,0 +1,197 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import "github.com/wmnsk/go-gtp/v2/ies"
+
+// MBMSSessionStopRequest is a MBMSSessionStopRequest Header and its IEs above.
+type MBMSSessionStopRequest struct {
+	*Header
+	MBMSFlowIdentifier   *ies.IE
+	MBMSDataTransferStop *ies.IE
+	MBMSFlags            *ies.IE
+	PrivateExtension     *ies.IE
+	AdditionalIEs        []*ies.IE
+}
+
+// NewMBMSSessionStopRequest creates a new MBMSSessionStopRequest.
+func NewMBMSSessionStopRequest(teid, seq uint32, ie ...*ies.IE) *MBMSSessionStopRequest {
+	m := &MBMSSessionStopRequest{
+		Header: NewHeader(
+			NewHeaderFlags(2, 0, 1),
+			MsgTypeMBMSSessionStopRequest, teid, seq, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.MBMSFlowIdentifier:
+			m.MBMSFlowIdentifier = i
+		case ies.AbsoluteTimeofMBMSDataTransfer:
+			m.MBMSDataTransferStop = i
+		case ies.MBMSFlags:
+			m.MBMSFlags = i
+		case ies.PrivateExtension:
+			if m.PrivateExtension == nil {
+				m.PrivateExtension = i
+			} else {
+				m.AdditionalIEs = append(m.AdditionalIEs, i)
+			}
+		default:
+			m.AdditionalIEs = append(m.AdditionalIEs, i)
+		}
+	}
+
+	m.SetLength()
+	return m
+}
+
+// Marshal serializes MBMSSessionStopRequest into bytes.
+func (m *MBMSSessionStopRequest) Marshal() ([]byte, error) {
+	b := make([]byte, m.MarshalLen())
+	if err := m.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MarshalTo serializes MBMSSessionStopRequest into bytes.
+func (m *MBMSSessionStopRequest) MarshalTo(b []byte) error {
+	if m.Header.Payload != nil {
+		m.Header.Payload = nil
+	}
+	m.Header.Payload = make([]byte, m.MarshalLen()-m.Header.MarshalLen())
+
+	offset := 0
+	if ie := m.MBMSFlowIdentifier; ie != nil {
+		if err := ie.MarshalTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := m.MBMSDataTransferStop; ie != nil {
+		if err := ie.MarshalTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := m.MBMSFlags; ie != nil {
+		if err := ie.MarshalTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := m.PrivateExtension; ie != nil {
+		if err := ie.MarshalTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	for _, ie := range m.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(m.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	m.Header.SetLength()
+	return m.Header.MarshalTo(b)
+}
+
+// ParseMBMSSessionStopRequest decodes given bytes as MBMSSessionStopRequest.
+func ParseMBMSSessionStopRequest(b []byte) (*MBMSSessionStopRequest, error) {
+	m := &MBMSSessionStopRequest{}
+	if err := m.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// UnmarshalBinary decodes given bytes as MBMSSessionStopRequest.
+func (m *MBMSSessionStopRequest) UnmarshalBinary(b []byte) error {
+	var err error
+	m.Header, err = ParseHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(m.Header.Payload) < 2 {
+		return nil
+	}
+
+	decodedIEs, err := ies.ParseMultiIEs(m.Header.Payload)
+	if err != nil {
+		return err
+	}
+	for _, i := range decodedIEs {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.MBMSFlowIdentifier:
+			m.MBMSFlowIdentifier = i
+		case ies.AbsoluteTimeofMBMSDataTransfer:
+			m.MBMSDataTransferStop = i
+		case ies.MBMSFlags:
+			m.MBMSFlags = i
+		case ies.PrivateExtension:
+			if m.PrivateExtension == nil {
+				m.PrivateExtension = i
+			} else {
+				m.AdditionalIEs = append(m.AdditionalIEs, i)
+			}
+		default:
+			m.AdditionalIEs = append(m.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length in int.
+func (m *MBMSSessionStopRequest) MarshalLen() int {
+	l := m.Header.MarshalLen() - len(m.Header.Payload)
+	if ie := m.MBMSFlowIdentifier; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := m.MBMSDataTransferStop; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := m.MBMSFlags; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := m.PrivateExtension; ie != nil {
+		l += ie.MarshalLen()
+	}
+
+	for _, ie := range m.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (m *MBMSSessionStopRequest) SetLength() {
+	m.Header.Length = uint16(m.MarshalLen() - 4)
+}
+
+// MessageTypeName returns the name of protocol.
+func (m *MBMSSessionStopRequest) MessageTypeName() string {
+	return "MBMS Session Stop Request"
+}
+
+// TEID returns the TEID in uint32.
+func (m *MBMSSessionStopRequest) TEID() uint32 {
+	return m.Header.teid()
+}
@@ -0,0 +1,183 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import "github.com/wmnsk/go-gtp/v2/ies"
+
+// MBMSSessionStopResponse is a MBMSSessionStopResponse Header and its IEs above.
+type MBMSSessionStopResponse struct {
+	*Header
+	Cause            *ies.IE
+	Recovery         *ies.IE
+	PrivateExtension *ies.IE
+	AdditionalIEs    []*ies.IE
+}
+
+// NewMBMSSessionStopResponse creates a new MBMSSessionStopResponse.
+func NewMBMSSessionStopResponse(teid, seq uint32, ie ...*ies.IE) *MBMSSessionStopResponse {
+	m := &MBMSSessionStopResponse{
+		Header: NewHeader(
+			NewHeaderFlags(2, 0, 1),
+			MsgTypeMBMSSessionStopResponse, teid, seq, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			m.Cause = i
+		case ies.Recovery:
+			m.Recovery = i
+		case ies.PrivateExtension:
+			if m.PrivateExtension == nil {
+				m.PrivateExtension = i
+			} else {
+				m.AdditionalIEs = append(m.AdditionalIEs, i)
+			}
+		default:
+			m.AdditionalIEs = append(m.AdditionalIEs, i)
+		}
+	}
+
+	m.SetLength()
+	return m
+}
+
+// Marshal serializes MBMSSessionStopResponse into bytes.
+func (m *MBMSSessionStopResponse) Marshal() ([]byte, error) {
+	b := make([]byte, m.MarshalLen())
+	if err := m.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MarshalTo serializes MBMSSessionStopResponse into bytes.
+func (m *MBMSSessionStopResponse) MarshalTo(b []byte) error {
+	if m.Header.Payload != nil {
+		m.Header.Payload = nil
+	}
+	m.Header.Payload = make([]byte, m.MarshalLen()-m.Header.MarshalLen())
+
+	offset := 0
+	if ie := m.Cause; ie != nil {
+		if err := ie.MarshalTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := m.Recovery; ie != nil {
+		if err := ie.MarshalTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := m.PrivateExtension; ie != nil {
+		if err := ie.MarshalTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	for _, ie := range m.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(m.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	m.Header.SetLength()
+	return m.Header.MarshalTo(b)
+}
+
+// ParseMBMSSessionStopResponse decodes given bytes as MBMSSessionStopResponse.
+func ParseMBMSSessionStopResponse(b []byte) (*MBMSSessionStopResponse, error) {
+	m := &MBMSSessionStopResponse{}
+	if err := m.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// UnmarshalBinary decodes given bytes as MBMSSessionStopResponse.
+func (m *MBMSSessionStopResponse) UnmarshalBinary(b []byte) error {
+	var err error
+	m.Header, err = ParseHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(m.Header.Payload) < 2 {
+		return nil
+	}
+
+	decodedIEs, err := ies.ParseMultiIEs(m.Header.Payload)
+	if err != nil {
+		return err
+	}
+	for _, i := range decodedIEs {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			m.Cause = i
+		case ies.Recovery:
+			m.Recovery = i
+		case ies.PrivateExtension:
+			if m.PrivateExtension == nil {
+				m.PrivateExtension = i
+			} else {
+				m.AdditionalIEs = append(m.AdditionalIEs, i)
+			}
+		default:
+			m.AdditionalIEs = append(m.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length in int.
+func (m *MBMSSessionStopResponse) MarshalLen() int {
+	l := m.Header.MarshalLen() - len(m.Header.Payload)
+	if ie := m.Cause; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := m.Recovery; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := m.PrivateExtension; ie != nil {
+		l += ie.MarshalLen()
+	}
+
+	for _, ie := range m.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (m *MBMSSessionStopResponse) SetLength() {
+	m.Header.Length = uint16(m.MarshalLen() - 4)
+}
+
+// MessageTypeName returns the name of protocol.
+func (m *MBMSSessionStopResponse) MessageTypeName() string {
+	return "MBMS Session Stop Response"
+}
+
+// TEID returns the TEID in uint32.
+func (m *MBMSSessionStopResponse) TEID() uint32 {
+	return m.Header.teid()
+}
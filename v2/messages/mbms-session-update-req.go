@@ -0,0 +1,305 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import "github.com/wmnsk/go-gtp/v2/ies"
+
+// MBMSSessionUpdateRequest is a MBMSSessionUpdateRequest Header and its IEs above.
+type MBMSSessionUpdateRequest struct {
+	*Header
+	MBMSServiceArea        *ies.IE
+	TMGI                   *ies.IE
+	SenderFTEIDC           *ies.IE
+	MBMSSessionDuration    *ies.IE
+	QoSProfile             *ies.IE
+	MBMSSessionIdentifier  *ies.IE
+	MBMSFlowIdentifier     *ies.IE
+	MBMSTimeToDataTransfer *ies.IE
+	MBMSDataTransferStart  *ies.IE
+	MBMSCellList           *ies.IE
+	PrivateExtension       *ies.IE
+	AdditionalIEs          []*ies.IE
+}
+
+// NewMBMSSessionUpdateRequest creates a new MBMSSessionUpdateRequest.
+func NewMBMSSessionUpdateRequest(teid, seq uint32, ie ...*ies.IE) *MBMSSessionUpdateRequest {
+	m := &MBMSSessionUpdateRequest{
+		Header: NewHeader(
+			NewHeaderFlags(2, 0, 1),
+			MsgTypeMBMSSessionUpdateRequest, teid, seq, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.MBMSServiceArea:
+			m.MBMSServiceArea = i
+		case ies.TMGI:
+			m.TMGI = i
+		case ies.FullyQualifiedTEID:
+			switch i.Instance() {
+			case 0:
+				m.SenderFTEIDC = i
+			default:
+				m.AdditionalIEs = append(m.AdditionalIEs, i)
+			}
+		case ies.MBMSSessionDuration:
+			m.MBMSSessionDuration = i
+		case ies.BearerQoS:
+			m.QoSProfile = i
+		case ies.MBMSSessionIdentifier:
+			m.MBMSSessionIdentifier = i
+		case ies.MBMSFlowIdentifier:
+			m.MBMSFlowIdentifier = i
+		case ies.MBMSTimeToDataTransfer:
+			m.MBMSTimeToDataTransfer = i
+		case ies.AbsoluteTimeofMBMSDataTransfer:
+			m.MBMSDataTransferStart = i
+		case ies.ECGIList:
+			m.MBMSCellList = i
+		case ies.PrivateExtension:
+			if m.PrivateExtension == nil {
+				m.PrivateExtension = i
+			} else {
+				m.AdditionalIEs = append(m.AdditionalIEs, i)
+			}
+		default:
+			m.AdditionalIEs = append(m.AdditionalIEs, i)
+		}
+	}
+
+	m.SetLength()
+	return m
+}
+
+// Marshal serializes MBMSSessionUpdateRequest into bytes.
+func (m *MBMSSessionUpdateRequest) Marshal() ([]byte, error) {
+	b := make([]byte, m.MarshalLen())
+	if err := m.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MarshalTo serializes MBMSSessionUpdateRequest into bytes.
+func (m *MBMSSessionUpdateRequest) MarshalTo(b []byte) error {
+	if m.Header.Payload != nil {
+		m.Header.Payload = nil
+	}
+	m.Header.Payload = make([]byte, m.MarshalLen()-m.Header.MarshalLen())
+
+	offset := 0
+	if ie := m.MBMSServiceArea; ie != nil {
+		if err := ie.MarshalTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := m.TMGI; ie != nil {
+		if err := ie.MarshalTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := m.SenderFTEIDC; ie != nil {
+		if err := ie.MarshalTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := m.MBMSSessionDuration; ie != nil {
+		if err := ie.MarshalTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := m.QoSProfile; ie != nil {
+		if err := ie.MarshalTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := m.MBMSSessionIdentifier; ie != nil {
+		if err := ie.MarshalTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := m.MBMSFlowIdentifier; ie != nil {
+		if err := ie.MarshalTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := m.MBMSTimeToDataTransfer; ie != nil {
+		if err := ie.MarshalTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := m.MBMSDataTransferStart; ie != nil {
+		if err := ie.MarshalTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := m.MBMSCellList; ie != nil {
+		if err := ie.MarshalTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := m.PrivateExtension; ie != nil {
+		if err := ie.MarshalTo(m.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	for _, ie := range m.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(m.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	m.Header.SetLength()
+	return m.Header.MarshalTo(b)
+}
+
+// ParseMBMSSessionUpdateRequest decodes given bytes as MBMSSessionUpdateRequest.
+func ParseMBMSSessionUpdateRequest(b []byte) (*MBMSSessionUpdateRequest, error) {
+	m := &MBMSSessionUpdateRequest{}
+	if err := m.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// UnmarshalBinary decodes given bytes as MBMSSessionUpdateRequest.
+func (m *MBMSSessionUpdateRequest) UnmarshalBinary(b []byte) error {
+	var err error
+	m.Header, err = ParseHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(m.Header.Payload) < 2 {
+		return nil
+	}
+
+	decodedIEs, err := ies.ParseMultiIEs(m.Header.Payload)
+	if err != nil {
+		return err
+	}
+	for _, i := range decodedIEs {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.MBMSServiceArea:
+			m.MBMSServiceArea = i
+		case ies.TMGI:
+			m.TMGI = i
+		case ies.FullyQualifiedTEID:
+			switch i.Instance() {
+			case 0:
+				m.SenderFTEIDC = i
+			default:
+				m.AdditionalIEs = append(m.AdditionalIEs, i)
+			}
+		case ies.MBMSSessionDuration:
+			m.MBMSSessionDuration = i
+		case ies.BearerQoS:
+			m.QoSProfile = i
+		case ies.MBMSSessionIdentifier:
+			m.MBMSSessionIdentifier = i
+		case ies.MBMSFlowIdentifier:
+			m.MBMSFlowIdentifier = i
+		case ies.MBMSTimeToDataTransfer:
+			m.MBMSTimeToDataTransfer = i
+		case ies.AbsoluteTimeofMBMSDataTransfer:
+			m.MBMSDataTransferStart = i
+		case ies.ECGIList:
+			m.MBMSCellList = i
+		case ies.PrivateExtension:
+			if m.PrivateExtension == nil {
+				m.PrivateExtension = i
+			} else {
+				m.AdditionalIEs = append(m.AdditionalIEs, i)
+			}
+		default:
+			m.AdditionalIEs = append(m.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length in int.
+func (m *MBMSSessionUpdateRequest) MarshalLen() int {
+	l := m.Header.MarshalLen() - len(m.Header.Payload)
+	if ie := m.MBMSServiceArea; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := m.TMGI; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := m.SenderFTEIDC; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := m.MBMSSessionDuration; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := m.QoSProfile; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := m.MBMSSessionIdentifier; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := m.MBMSFlowIdentifier; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := m.MBMSTimeToDataTransfer; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := m.MBMSDataTransferStart; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := m.MBMSCellList; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := m.PrivateExtension; ie != nil {
+		l += ie.MarshalLen()
+	}
+
+	for _, ie := range m.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (m *MBMSSessionUpdateRequest) SetLength() {
+	m.Header.Length = uint16(m.MarshalLen() - 4)
+}
+
+// MessageTypeName returns the name of protocol.
+func (m *MBMSSessionUpdateRequest) MessageTypeName() string {
+	return "MBMS Session Update Request"
+}
+
+// TEID returns the TEID in uint32.
+func (m *MBMSSessionUpdateRequest) TEID() uint32 {
+	return m.Header.teid()
+}
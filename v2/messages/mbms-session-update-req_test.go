@@ -0,0 +1,55 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+	"github.com/wmnsk/go-gtp/v2/testutils"
+)
+
+func TestMBMSSessionUpdateRequest(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "Normal",
+			Structured: messages.NewMBMSSessionUpdateRequest(
+				testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+				ies.New(ies.MBMSServiceArea, 0, []byte{0x00, 0x00, 0x01}),
+				ies.NewTMGI(0x123456, "123", "45"),
+				ies.NewMBMSSessionDuration(2*time.Hour),
+				ies.NewBearerQoS(0, 2, 0, 0xff, 0, 0, 0, 0),
+				ies.NewMBMSFlowIdentifier(1),
+			),
+			Serialized: []byte{
+				// Header
+				0x48, 0xe9, 0x00, 0x40, 0x11, 0x22, 0x33, 0x44, 0x00, 0x00, 0x01, 0x00,
+				// MBMS Service Area
+				0x8b, 0x00, 0x03, 0x00, 0x00, 0x00, 0x01,
+				// TMGI
+				0x9e, 0x00, 0x06, 0x00, 0x12, 0x34, 0x56, 0x21, 0xf3, 0x54,
+				// MBMS Session Duration
+				0x8a, 0x00, 0x03, 0x00, 0x0e, 0x10, 0x00,
+				// QoS Profile
+				0x50, 0x00, 0x16, 0x00, 0x08, 0xff,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				// MBMS Flow Identifier
+				0x8d, 0x00, 0x02, 0x00, 0x00, 0x01,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializable, error) {
+		v, err := messages.ParseMBMSSessionUpdateRequest(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
@@ -8,6 +8,8 @@ Package messages provides encoding/decoding feature of GTPv2 protocol.
 package messages
 
 import (
+	"encoding/binary"
+
 	"github.com/pkg/errors"
 )
 
@@ -279,8 +281,12 @@ type Message interface {
 	Version() int
 	MessageType() uint8
 	MessageTypeName() string
+	HasTEID() bool
 	TEID() uint32
 	SetTEID(uint32)
+	ClearTEID()
+	Priority() (uint8, bool)
+	SetPriority(uint8) error
 	Sequence() uint32
 	SetSequenceNumber(uint32)
 
@@ -301,10 +307,29 @@ func Marshal(m Message) ([]byte, error) {
 }
 
 // Parse decodes the given bytes as Message.
+//
+// Parse never panics on the truncated or corrupted bytes. If the IEs are the cause of
+// the failure, the error is caused by MalformedIEError, which has the offset of the
+// first IE that cannot be decoded.
 func Parse(b []byte) (Message, error) {
+	if len(b) < 2 {
+		return nil, ErrTooShortToParse
+	}
+	m := newMessage(b[1])
+	if err := m.UnmarshalBinary(b); err != nil {
+		if merr := locateMalformedIE(b); merr != nil {
+			err = merr
+		}
+		return nil, errors.Wrap(err, "failed to decode GTPv2 Message")
+	}
+	return m, nil
+}
+
+// newMessage returns the empty Message of msgType, or Generic if it is not supported.
+func newMessage(msgType uint8) Message {
 	var m Message
 
-	switch b[1] {
+	switch msgType {
 	case MsgTypeEchoRequest:
 		m = &EchoRequest{}
 	case MsgTypeEchoResponse:
@@ -319,6 +344,10 @@ func Parse(b []byte) (Message, error) {
 		m = &DeleteSessionRequest{}
 	case MsgTypeDeleteSessionResponse:
 		m = &DeleteSessionResponse{}
+	case MsgTypeChangeNotificationRequest:
+		m = &ChangeNotificationRequest{}
+	case MsgTypeChangeNotificationResponse:
+		m = &ChangeNotificationResponse{}
 	case MsgTypeModifyBearerCommand:
 		m = &ModifyBearerCommand{}
 	case MsgTypeModifyBearerFailureIndication:
@@ -327,14 +356,32 @@ func Parse(b []byte) (Message, error) {
 		m = &DeleteBearerCommand{}
 	case MsgTypeDeleteBearerFailureIndication:
 		m = &DeleteBearerFailureIndication{}
+	case MsgTypeBearerResourceCommand:
+		m = &BearerResourceCommand{}
+	case MsgTypeBearerResourceFailureIndication:
+		m = &BearerResourceFailureIndication{}
+	case MsgTypeDownlinkDataNotificationFailureIndication:
+		m = &DownlinkDataNotificationFailureIndication{}
+	case MsgTypeTraceSessionActivation:
+		m = &TraceSessionActivation{}
+	case MsgTypeTraceSessionDeactivation:
+		m = &TraceSessionDeactivation{}
 	case MsgTypeDeleteBearerRequest:
 		m = &DeleteBearerRequest{}
 	case MsgTypeCreateBearerRequest:
 		m = &CreateBearerRequest{}
 	case MsgTypeCreateBearerResponse:
 		m = &CreateBearerResponse{}
+	case MsgTypeUpdateBearerRequest:
+		m = &UpdateBearerRequest{}
+	case MsgTypeUpdateBearerResponse:
+		m = &UpdateBearerResponse{}
 	case MsgTypeDeleteBearerResponse:
 		m = &DeleteBearerResponse{}
+	case MsgTypeDeletePDNConnectionSetRequest:
+		m = &DeletePDNConnectionSetRequest{}
+	case MsgTypeDeletePDNConnectionSetResponse:
+		m = &DeletePDNConnectionSetResponse{}
 	case MsgTypeModifyBearerRequest:
 		m = &ModifyBearerRequest{}
 	case MsgTypeModifyBearerResponse:
@@ -349,18 +396,151 @@ func Parse(b []byte) (Message, error) {
 		m = &ReleaseAccessBearersRequest{}
 	case MsgTypeReleaseAccessBearersResponse:
 		m = &ReleaseAccessBearersResponse{}
+	case MsgTypeCreateIndirectDataForwardingTunnelRequest:
+		m = &CreateIndirectDataForwardingTunnelRequest{}
+	case MsgTypeCreateIndirectDataForwardingTunnelResponse:
+		m = &CreateIndirectDataForwardingTunnelResponse{}
+	case MsgTypeDeleteIndirectDataForwardingTunnelRequest:
+		m = &DeleteIndirectDataForwardingTunnelRequest{}
+	case MsgTypeDeleteIndirectDataForwardingTunnelResponse:
+		m = &DeleteIndirectDataForwardingTunnelResponse{}
+	case MsgTypeDownlinkDataNotification:
+		m = &DownlinkDataNotification{}
+	case MsgTypeDownlinkDataNotificationAcknowledge:
+		m = &DownlinkDataNotificationAcknowledge{}
 	case MsgTypeStopPagingIndication:
 		m = &StopPagingIndication{}
 	case MsgTypeModifyAccessBearersRequest:
 		m = &ModifyAccessBearersRequest{}
 	case MsgTypeModifyAccessBearersResponse:
 		m = &ModifyAccessBearersResponse{}
+	case MsgTypeIdentificationRequest:
+		m = &IdentificationRequest{}
+	case MsgTypeIdentificationResponse:
+		m = &IdentificationResponse{}
+	case MsgTypeForwardRelocationRequest:
+		m = &ForwardRelocationRequest{}
+	case MsgTypeForwardRelocationResponse:
+		m = &ForwardRelocationResponse{}
+	case MsgTypeForwardRelocationCompleteNotification:
+		m = &ForwardRelocationCompleteNotification{}
+	case MsgTypeForwardRelocationCompleteAcknowledge:
+		m = &ForwardRelocationCompleteAcknowledge{}
+	case MsgTypeSuspendNotification:
+		m = &SuspendNotification{}
+	case MsgTypeSuspendAcknowledge:
+		m = &SuspendAcknowledge{}
+	case MsgTypeResumeNotification:
+		m = &ResumeNotification{}
+	case MsgTypeResumeAcknowledge:
+		m = &ResumeAcknowledge{}
+	case MsgTypePGWRestartNotification:
+		m = &PGWRestartNotification{}
+	case MsgTypePGWRestartNotificationAcknowledge:
+		m = &PGWRestartNotificationAcknowledge{}
+	case MsgTypeMBMSSessionStartRequest:
+		m = &MBMSSessionStartRequest{}
+	case MsgTypeMBMSSessionStartResponse:
+		m = &MBMSSessionStartResponse{}
+	case MsgTypeMBMSSessionUpdateRequest:
+		m = &MBMSSessionUpdateRequest{}
+	case MsgTypeMBMSSessionUpdateResponse:
+		m = &MBMSSessionUpdateResponse{}
+	case MsgTypeMBMSSessionStopRequest:
+		m = &MBMSSessionStopRequest{}
+	case MsgTypeMBMSSessionStopResponse:
+		m = &MBMSSessionStopResponse{}
 	default:
 		m = &Generic{}
 	}
+	return m
+}
 
-	if err := m.UnmarshalBinary(b); err != nil {
-		return nil, errors.Wrap(err, "failed to decode GTPv2 Message")
+// ParseMultiMessages decodes the given bytes as one or more Messages.
+//
+// TS29.274 5.5.1 allows multiple messages to be carried in a datagram by setting the
+// Piggybacking flag in the header of the first message. This returns the messages in
+// the order they appear in the given bytes. If the Piggybacking flag is not set, this
+// works the same as Parse and the returned slice always contains one Message.
+func ParseMultiMessages(b []byte) ([]Message, error) {
+	return ParseMultiMessagesWithLimits(b, nil)
+}
+
+// ParseMultiMessagesWithLimits works the same as ParseMultiMessages, but checks each
+// message with the limits before decoding it. Giving nil limits disables the check.
+func ParseMultiMessagesWithLimits(b []byte, l *Limits) ([]Message, error) {
+	return ParseMultiMessagesWithMode(b, l, ParseModeDefault)
+}
+
+// ParseMultiMessagesWithMode works the same as ParseMultiMessagesWithLimits, but
+// decodes each message in the ParseMode given.
+func ParseMultiMessagesWithMode(b []byte, l *Limits, mode ParseMode) ([]Message, error) {
+	var msgs []Message
+	for {
+		if len(b) < 4 {
+			return nil, ErrTooShortToParse
+		}
+
+		// the trailing bytes are all taken as the last message if not piggybacking.
+		if (b[0]>>4)&0x01 != 1 {
+			m, err := parseWithLimitsAndMode(b, l, mode)
+			if err != nil {
+				return nil, err
+			}
+			return append(msgs, m), nil
+		}
+
+		n := int(binary.BigEndian.Uint16(b[2:4])) + 4
+		if n > len(b) {
+			return nil, ErrInvalidLength
+		}
+
+		m, err := parseWithLimitsAndMode(b[:n], l, mode)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, m)
+
+		b = b[n:]
+		if len(b) == 0 {
+			return msgs, nil
+		}
 	}
-	return m, nil
+}
+
+// MarshalMultiMessages returns the byte sequence of the messages concatenated in the
+// order given, with the Piggybacking flag set in the header of all the messages but
+// the last one, which is the counterpart of ParseMultiMessages.
+//
+// TS29.274 5.5.1 allows an initial message to be piggybacked on the triggered
+// response, e.g., Create Bearer Request on Create Session Response. Note that the
+// Piggybacking flag of the messages given is overwritten.
+func MarshalMultiMessages(msgs ...Message) ([]byte, error) {
+	if len(msgs) == 0 {
+		return nil, ErrNoMessages
+	}
+
+	l := 0
+	for i, m := range msgs {
+		p, ok := m.(interface{ SetPiggybacking(uint8) })
+		if !ok {
+			return nil, errors.Errorf("cannot piggyback %T", m)
+		}
+		if i == len(msgs)-1 {
+			p.SetPiggybacking(0)
+		} else {
+			p.SetPiggybacking(1)
+		}
+		l += m.MarshalLen()
+	}
+
+	b := make([]byte, l)
+	offset := 0
+	for _, m := range msgs {
+		if err := m.MarshalTo(b[offset:]); err != nil {
+			return nil, err
+		}
+		offset += m.MarshalLen()
+	}
+	return b, nil
 }
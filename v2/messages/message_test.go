@@ -0,0 +1,374 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+	"github.com/wmnsk/go-gtp/v2/testutils"
+)
+
+func TestParseMultiMessages(t *testing.T) {
+	csRsp := messages.NewCreateSessionResponse(
+		testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+		ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+	)
+	csRsp.SetPiggybacking(1)
+	cbReq := messages.NewCreateBearerRequest(
+		testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq+1,
+		ies.NewEPSBearerID(0x05),
+	)
+
+	first, err := csRsp.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := cbReq.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Piggybacked", func(t *testing.T) {
+		msgs, err := messages.ParseMultiMessages(append(first, second...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(msgs) != 2 {
+			t.Fatalf("wrong number of messages. want: 2, got: %d", len(msgs))
+		}
+		if got, want := msgs[0].MessageType(), messages.MsgTypeCreateSessionResponse; got != want {
+			t.Errorf("wrong type of first message. want: %d, got: %d", want, got)
+		}
+		if got, want := msgs[1].MessageType(), messages.MsgTypeCreateBearerRequest; got != want {
+			t.Errorf("wrong type of second message. want: %d, got: %d", want, got)
+		}
+		if got, want := msgs[1].Sequence(), testutils.TestBearerInfo.Seq+1; got != want {
+			t.Errorf("wrong sequence of second message. want: %d, got: %d", want, got)
+		}
+	})
+
+	t.Run("Single", func(t *testing.T) {
+		msgs, err := messages.ParseMultiMessages(second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(msgs) != 1 {
+			t.Fatalf("wrong number of messages. want: 1, got: %d", len(msgs))
+		}
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		b := append(first, second...)
+		if _, err := messages.ParseMultiMessages(b[:len(first)-1]); err == nil {
+			t.Error("should fail with truncated message")
+		}
+	})
+}
+
+func TestMarshalMultiMessages(t *testing.T) {
+	csRsp := messages.NewCreateSessionResponse(
+		testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+		ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+	)
+	cbReq := messages.NewCreateBearerRequest(
+		testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq+1,
+		ies.NewEPSBearerID(0x05),
+	)
+	// the flag of the last message is cleared.
+	cbReq.SetPiggybacking(1)
+
+	b, err := messages.MarshalMultiMessages(csRsp, cbReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(b), csRsp.MarshalLen()+cbReq.MarshalLen(); got != want {
+		t.Errorf("wrong length. want: %d, got: %d", want, got)
+	}
+	if !csRsp.IsPiggybacking() || cbReq.IsPiggybacking() {
+		t.Errorf("wrong Piggybacking flags: %v, %v", csRsp.IsPiggybacking(), cbReq.IsPiggybacking())
+	}
+
+	msgs, err := messages.ParseMultiMessages(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("wrong number of messages. want: 2, got: %d", len(msgs))
+	}
+	if got, want := msgs[1].MessageType(), messages.MsgTypeCreateBearerRequest; got != want {
+		t.Errorf("wrong type of second message. want: %d, got: %d", want, got)
+	}
+
+	if _, err := messages.MarshalMultiMessages(); err != messages.ErrNoMessages {
+		t.Errorf("unexpected error with no messages: %v", err)
+	}
+}
+
+func TestFindIE(t *testing.T) {
+	fteid := ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 0x11111111, "127.0.0.1", "")
+	pgwFTEID := ies.NewFullyQualifiedTEID(v2.IFTypeS5S8PGWGTPC, 0x22222222, "127.0.0.1", "").WithInstance(1)
+	unknown := ies.New(250, 0, []byte{0x01})
+	csReq := messages.NewCreateSessionRequest(
+		testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+		ies.NewIMSI("123451234567890"), fteid, pgwFTEID, unknown,
+	)
+
+	all := messages.IEs(csReq)
+	if len(all) != 4 || all[0].Type != ies.IMSI || all[3] != unknown {
+		t.Errorf("wrong IEs: %v", all)
+	}
+
+	got, err := messages.FindIE(csReq, ies.FullyQualifiedTEID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != pgwFTEID {
+		t.Errorf("wrong IE found: %v", got)
+	}
+	if _, err := messages.FindIE(csReq, ies.Cause, 0); err != ies.ErrIENotFound {
+		t.Errorf("FindIE should fail with ErrIENotFound, got: %v", err)
+	}
+	if n := len(messages.FindIEs(csReq, ies.FullyQualifiedTEID)); n != 2 {
+		t.Errorf("wrong number of F-TEIDs. want %d, got: %d", 2, n)
+	}
+
+	// Generic works in the same way.
+	g := messages.NewGeneric(messages.MsgTypeCreateSessionRequest, 0, 0, fteid, pgwFTEID)
+	if n := len(messages.FindIEs(g, ies.FullyQualifiedTEID)); n != 2 {
+		t.Errorf("wrong number of F-TEIDs in Generic. want %d, got: %d", 2, n)
+	}
+}
+
+func TestCauseFrom(t *testing.T) {
+	csRsp := messages.NewCreateSessionResponse(
+		testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+		ies.NewCause(v2.CauseMissingOrUnknownAPN, 0, 0, 1, nil),
+	)
+	got, err := messages.CauseFrom(csRsp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != v2.CauseMissingOrUnknownAPN {
+		t.Errorf("wrong Cause. want %d, got: %d", v2.CauseMissingOrUnknownAPN, got)
+	}
+
+	if _, err := messages.CauseFrom(messages.NewEchoRequest(0)); err != ies.ErrIENotFound {
+		t.Errorf("CauseFrom should fail with ErrIENotFound, got: %v", err)
+	}
+}
+
+func TestPrivateExtensions(t *testing.T) {
+	orig, err := messages.NewEchoRequest(
+		testutils.TestBearerInfo.Seq,
+		ies.NewRecovery(1),
+		ies.NewPrivateExtension(10415, []byte{0x01}),
+		ies.New(250, 0, []byte{0xde, 0xad}),
+		ies.NewPrivateExtension(0x1234, []byte{0x02, 0x03}),
+	).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg, err := messages.Parse(orig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pe := messages.FindIEs(msg, ies.PrivateExtension)
+	if len(pe) != 2 || pe[0].MustEnterpriseID() != 10415 || pe[1].MustEnterpriseID() != 0x1234 {
+		t.Fatalf("Private Extensions are not preserved: %v", pe)
+	}
+
+	// modifying the known IE should not affect the proprietary ones.
+	msg.(*messages.EchoRequest).Recovery = ies.NewRecovery(2)
+	got, err := messages.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := make([]byte, len(orig))
+	copy(want, orig)
+	want[12] = 2
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestParseInto(t *testing.T) {
+	first, err := messages.NewEchoRequest(1, ies.NewRecovery(1), ies.NewPrivateExtension(10415, []byte{0x01})).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := messages.NewEchoRequest(2, ies.NewRecovery(2)).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := &messages.EchoRequest{}
+	if err := messages.ParseInto(first, m); err != nil {
+		t.Fatal(err)
+	}
+	if m.PrivateExtension == nil {
+		t.Fatal("PrivateExtension should be decoded")
+	}
+	if err := messages.ParseInto(second, m); err != nil {
+		t.Fatal(err)
+	}
+	if m.PrivateExtension != nil {
+		t.Error("PrivateExtension should be reset")
+	}
+	if got := m.Sequence(); got != 2 {
+		t.Errorf("wrong sequence: %d", got)
+	}
+
+	csRsp, err := messages.NewCreateSessionResponse(0, 3, ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil)).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := messages.ParseInto(csRsp, m); err != messages.ErrTypeMismatch {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := messages.ParseInto(csRsp, &messages.Generic{}); err != nil {
+		t.Errorf("Generic should accept any type: %v", err)
+	}
+}
+
+func TestPool(t *testing.T) {
+	b, err := messages.NewEchoRequest(1, ies.NewRecovery(1)).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &messages.Pool{}
+	for n := 0; n < 3; n++ {
+		got, err := p.Parse(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		serialized, err := messages.Marshal(got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(serialized, b); diff != "" {
+			t.Error(diff)
+		}
+		p.Put(got)
+	}
+
+	if _, err := p.Parse(b[:3]); err != messages.ErrTooShortToParse {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	raw, err := messages.NewModifyBearerRequest(0x11111111, 1, ies.NewIndicationFromOctets(0x01)).Marshal()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("Parse", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := messages.Parse(raw); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Pool", func(b *testing.B) {
+		p := &messages.Pool{}
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			m, err := p.Parse(raw)
+			if err != nil {
+				b.Fatal(err)
+			}
+			p.Put(m)
+		}
+	})
+}
+
+func TestDump(t *testing.T) {
+	msg := messages.NewDeleteSessionRequest(
+		0x11223344, 0x000001,
+		ies.NewEPSBearerID(5),
+	)
+	want := `Delete Session Request (36), Length: 13
+  Version: 2, Piggybacking: false, TEID Flag: true, Message Priority Flag: false
+  TEID: 0x11223344
+  Sequence Number: 0x1
+  EPSBearerID (73), Length: 1, Instance: 0
+    EBI: 5
+`
+	if diff := cmp.Diff(messages.Dump(msg), want); diff != "" {
+		t.Error(diff)
+	}
+
+	b, err := messages.MarshalJSON(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantJSON := `{"version":2,"type":36,"name":"Delete Session Request","length":13,` +
+		`"piggybacking":false,"teid":287454020,"sequence":1,` +
+		`"ies":[{"type":73,"name":"EPSBearerID","length":1,"instance":0,"fields":{"EBI":5}}]}`
+	if diff := cmp.Diff(string(b), wantJSON); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestReplayPcap(t *testing.T) {
+	f, err := ioutil.TempFile("", "gtp-*.pcap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	w, err := testutils.NewPcapWriter(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv, err := testutils.ListenRecording(&net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)}, w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+	cli, err := testutils.ListenRecording(&net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)}, w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cli.Close()
+
+	b, err := messages.Marshal(messages.NewEchoRequest(1, ies.NewRecovery(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cli.WriteTo(b, srv.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.SetReadDeadline(time.Now().Add(3 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := srv.ReadFrom(make([]byte, 1500)); err != nil {
+		t.Fatal(err)
+	}
+
+	// both the written and the read ones are recorded.
+	port := srv.LocalAddr().(*net.UDPAddr).Port
+	datagrams, err := testutils.LoadPcap(f.Name(), port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(datagrams) != 2 {
+		t.Fatalf("wrong number of datagrams recorded: %d", len(datagrams))
+	}
+	testutils.ReplayPcap(t, f.Name(), port)
+}
@@ -36,7 +36,7 @@ func NewModifyAccessBearersRequest(teid, seq uint32, ie ...*ies.IE) *ModifyAcces
 		switch i.Type {
 		case ies.Indication:
 			m.IndicationFlags = i
-		case ies.NodeType:
+		case ies.FullyQualifiedTEID:
 			m.SenderFTEIDC = i
 		case ies.DelayValue:
 			m.DelayDownlinkPacketNotificationRequest = i
@@ -52,7 +52,11 @@ func NewModifyAccessBearersRequest(teid, seq uint32, ie ...*ies.IE) *ModifyAcces
 		case ies.SecondaryRATUsageDataReport:
 			m.SecondaryRATUsageDataReport = i
 		case ies.PrivateExtension:
-			m.PrivateExtension = i
+			if m.PrivateExtension == nil {
+				m.PrivateExtension = i
+			} else {
+				m.AdditionalIEs = append(m.AdditionalIEs, i)
+			}
 		default:
 			m.AdditionalIEs = append(m.AdditionalIEs, i)
 		}
@@ -173,7 +177,7 @@ func (m *ModifyAccessBearersRequest) UnmarshalBinary(b []byte) error {
 		switch i.Type {
 		case ies.Indication:
 			m.IndicationFlags = i
-		case ies.NodeType:
+		case ies.FullyQualifiedTEID:
 			m.SenderFTEIDC = i
 		case ies.DelayValue:
 			m.DelayDownlinkPacketNotificationRequest = i
@@ -189,7 +193,11 @@ func (m *ModifyAccessBearersRequest) UnmarshalBinary(b []byte) error {
 		case ies.SecondaryRATUsageDataReport:
 			m.SecondaryRATUsageDataReport = i
 		case ies.PrivateExtension:
-			m.PrivateExtension = i
+			if m.PrivateExtension == nil {
+				m.PrivateExtension = i
+			} else {
+				m.AdditionalIEs = append(m.AdditionalIEs, i)
+			}
 		default:
 			m.AdditionalIEs = append(m.AdditionalIEs, i)
 		}
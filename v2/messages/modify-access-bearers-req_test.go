@@ -7,6 +7,7 @@ package messages_test
 import (
 	"testing"
 
+	v2 "github.com/wmnsk/go-gtp/v2"
 	"github.com/wmnsk/go-gtp/v2/ies"
 	"github.com/wmnsk/go-gtp/v2/messages"
 	"github.com/wmnsk/go-gtp/v2/testutils"
@@ -33,6 +34,33 @@ func TestModifyAccessBearersRequest(t *testing.T) {
 				// Indication
 				0x4d, 0x00, 0x07, 0x00, 0xa1, 0x08, 0x15, 0x10, 0x88, 0x81, 0x40,
 			},
+		}, {
+			Description: "Normal/WithBearerContexts",
+			Structured: messages.NewModifyAccessBearersRequest(
+				testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+				ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 0xffffffff, "1.1.1.1", ""),
+				ies.NewBearerContext(
+					ies.NewEPSBearerID(0x05),
+					ies.NewFullyQualifiedTEID(v2.IFTypeS1UeNodeBGTPU, 0x11111111, "1.1.1.2", ""),
+				),
+				ies.NewBearerContext(ies.NewEPSBearerID(0x06)).WithInstance(1),
+			),
+			Serialized: []byte{
+				// Header
+				0x48, 0xd3, 0x00, 0x34, 0x11, 0x22, 0x33, 0x44, 0x00, 0x00, 0x01, 0x00,
+				// Sender F-TEID for Control Plane
+				0x57, 0x00, 0x09, 0x00, 0x8a, 0xff, 0xff, 0xff, 0xff, 0x01, 0x01, 0x01, 0x01,
+				// Bearer Contexts to be modified
+				0x5d, 0x00, 0x12, 0x00,
+				//   EBI
+				0x49, 0x00, 0x01, 0x00, 0x05,
+				//   S1-U eNodeB F-TEID
+				0x57, 0x00, 0x09, 0x00, 0x80, 0x11, 0x11, 0x11, 0x11, 0x01, 0x01, 0x01, 0x02,
+				// Bearer Contexts to be removed
+				0x5d, 0x00, 0x05, 0x01,
+				//   EBI
+				0x49, 0x00, 0x01, 0x00, 0x06,
+			},
 		},
 	}
 
@@ -49,7 +49,11 @@ func NewModifyAccessBearersResponse(teid, seq uint32, ie ...*ies.IE) *ModifyAcce
 		case ies.LoadControlInformation:
 			m.SGWNodeLoadControlInformation = i
 		case ies.PrivateExtension:
-			m.PrivateExtension = i
+			if m.PrivateExtension == nil {
+				m.PrivateExtension = i
+			} else {
+				m.AdditionalIEs = append(m.AdditionalIEs, i)
+			}
 		default:
 			m.AdditionalIEs = append(m.AdditionalIEs, i)
 		}
@@ -178,7 +182,11 @@ func (m *ModifyAccessBearersResponse) UnmarshalBinary(b []byte) error {
 		case ies.LoadControlInformation:
 			m.SGWNodeLoadControlInformation = i
 		case ies.PrivateExtension:
-			m.PrivateExtension = i
+			if m.PrivateExtension == nil {
+				m.PrivateExtension = i
+			} else {
+				m.AdditionalIEs = append(m.AdditionalIEs, i)
+			}
 		default:
 			m.AdditionalIEs = append(m.AdditionalIEs, i)
 		}
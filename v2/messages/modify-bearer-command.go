@@ -49,7 +49,11 @@ func NewModifyBearerCommand(teid, seq uint32, ie ...*ies.IE) *ModifyBearerComman
 		case ies.FullyQualifiedTEID:
 			m.SenderFTEIDC = i
 		case ies.PrivateExtension:
-			m.PrivateExtension = i
+			if m.PrivateExtension == nil {
+				m.PrivateExtension = i
+			} else {
+				m.AdditionalIEs = append(m.AdditionalIEs, i)
+			}
 		default:
 			m.AdditionalIEs = append(m.AdditionalIEs, i)
 		}
@@ -178,7 +182,11 @@ func (m *ModifyBearerCommand) UnmarshalBinary(b []byte) error {
 		case ies.FullyQualifiedTEID:
 			m.SenderFTEIDC = i
 		case ies.PrivateExtension:
-			m.PrivateExtension = i
+			if m.PrivateExtension == nil {
+				m.PrivateExtension = i
+			} else {
+				m.AdditionalIEs = append(m.AdditionalIEs, i)
+			}
 		default:
 			m.AdditionalIEs = append(m.AdditionalIEs, i)
 		}
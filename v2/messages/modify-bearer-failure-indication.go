@@ -46,7 +46,11 @@ func NewModifyBearerFailureIndication(teid, seq uint32, ie ...*ies.IE) *ModifyBe
 				m.SGWOverloadControlInformation = i
 			}
 		case ies.PrivateExtension:
-			m.PrivateExtension = i
+			if m.PrivateExtension == nil {
+				m.PrivateExtension = i
+			} else {
+				m.AdditionalIEs = append(m.AdditionalIEs, i)
+			}
 		default:
 			m.AdditionalIEs = append(m.AdditionalIEs, i)
 		}
@@ -167,7 +171,11 @@ func (m *ModifyBearerFailureIndication) UnmarshalBinary(b []byte) error {
 				m.SGWOverloadControlInformation = i
 			}
 		case ies.PrivateExtension:
-			m.PrivateExtension = i
+			if m.PrivateExtension == nil {
+				m.PrivateExtension = i
+			} else {
+				m.AdditionalIEs = append(m.AdditionalIEs, i)
+			}
 		default:
 			m.AdditionalIEs = append(m.AdditionalIEs, i)
 		}
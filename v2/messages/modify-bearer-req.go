@@ -167,7 +167,11 @@ func NewModifyBearerRequest(teid, seq uint32, ie ...*ies.IE) *ModifyBearerReques
 		case ies.SecondaryRATUsageDataReport:
 			m.SecondaryRATUsageDataReport = i
 		case ies.PrivateExtension:
-			m.PrivateExtension = i
+			if m.PrivateExtension == nil {
+				m.PrivateExtension = i
+			} else {
+				m.AdditionalIEs = append(m.AdditionalIEs, i)
+			}
 		default:
 			m.AdditionalIEs = append(m.AdditionalIEs, i)
 		}
@@ -553,7 +557,11 @@ func (m *ModifyBearerRequest) UnmarshalBinary(b []byte) error {
 		case ies.SecondaryRATUsageDataReport:
 			m.SecondaryRATUsageDataReport = i
 		case ies.PrivateExtension:
-			m.PrivateExtension = i
+			if m.PrivateExtension == nil {
+				m.PrivateExtension = i
+			} else {
+				m.AdditionalIEs = append(m.AdditionalIEs, i)
+			}
 		default:
 			m.AdditionalIEs = append(m.AdditionalIEs, i)
 		}
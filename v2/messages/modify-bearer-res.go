@@ -130,7 +130,11 @@ func NewModifyBearerResponse(teid, seq uint32, ie ...*ies.IE) *ModifyBearerRespo
 		case ies.ChargingID:
 			m.PDNConnectionChargingID = i
 		case ies.PrivateExtension:
-			m.PrivateExtension = i
+			if m.PrivateExtension == nil {
+				m.PrivateExtension = i
+			} else {
+				m.AdditionalIEs = append(m.AdditionalIEs, i)
+			}
 		default:
 			m.AdditionalIEs = append(m.AdditionalIEs, i)
 		}
@@ -434,7 +438,11 @@ func (m *ModifyBearerResponse) UnmarshalBinary(b []byte) error {
 		case ies.ChargingID:
 			m.PDNConnectionChargingID = i
 		case ies.PrivateExtension:
-			m.PrivateExtension = i
+			if m.PrivateExtension == nil {
+				m.PrivateExtension = i
+			} else {
+				m.AdditionalIEs = append(m.AdditionalIEs, i)
+			}
 		default:
 			m.AdditionalIEs = append(m.AdditionalIEs, i)
 		}
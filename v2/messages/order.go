@@ -0,0 +1,283 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"encoding/binary"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/wmnsk/go-gtp/v2/ies"
+)
+
+// repeatableIEs is the set of IE types that can appear more than once with the same
+// instance in a message or a grouped IE, e.g., Bearer Contexts in Create Session
+// Request, or EPS Bearer IDs in Delete Bearer Request. Private Extension is also
+// repeatable, as the IEs of the different vendors can be present at the same time.
+var repeatableIEs = map[uint8]bool{
+	ies.EPSBearerID:                 true,
+	ies.BearerContext:               true,
+	ies.PDNConnection:               true,
+	ies.FullyQualifiedCSID:          true,
+	ies.LoadControlInformation:      true,
+	ies.APNAndRelativeCapacity:      true,
+	ies.RemoteUEContext:             true,
+	ies.SCEFPDNConnection:           true,
+	ies.SecondaryRATUsageDataReport: true,
+	ies.PrivateExtension:            true,
+}
+
+// IsRepeatableIE reports whether the IE with typ can appear more than once with
+// the same instance in a message.
+func IsRepeatableIE(typ uint8) bool {
+	return repeatableIEs[typ]
+}
+
+// CheckDuplicateIEs checks if the given bytes of a message contain the same IE more
+// than once, and returns DuplicateIEError for the first one found.
+//
+// IEs are identified by type and instance, and the ones that can be repeated (see
+// IsRepeatableIE) are not reported. The IEs in the grouped IEs are checked as well.
+//
+// TS 29.274 7.7.8 requires the receiver to handle only the first one of the repeated
+// IEs, while the structured messages in this package hold the last one. Use this
+// before Parse to detect such messages. To check a structured Message, give the bytes
+// from Marshal.
+//
+// Private Extensions are not reported, as the structured messages hold the first one
+// in the PrivateExtension field and the rest in AdditionalIEs.
+func CheckDuplicateIEs(b []byte) error {
+	offset, ok := payloadOffset(b)
+	if !ok {
+		return ErrTooShortToParse
+	}
+	return checkDuplicateIEs(b[offset:])
+}
+
+func checkDuplicateIEs(b []byte) error {
+	seen := map[uint16]bool{}
+	for len(b) >= 4 {
+		n := int(binary.BigEndian.Uint16(b[1:3])) + 4
+		if n > len(b) {
+			return ErrInvalidLength
+		}
+
+		typ, ins := b[0], b[3]&0x0f
+		key := uint16(typ)<<8 | uint16(ins)
+		if seen[key] && !IsRepeatableIE(typ) {
+			return &DuplicateIEError{Type: typ, Instance: ins}
+		}
+		seen[key] = true
+
+		if (&ies.IE{Type: typ}).IsGrouped() {
+			if err := checkDuplicateIEs(b[4:n]); err != nil {
+				return err
+			}
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+// IEOrder is the way to arrange the IEs in the serialized message.
+type IEOrder uint8
+
+// IEOrder definitions.
+const (
+	// IEOrderAsIs keeps the IEs in the order of the fields of the structured message,
+	// followed by AdditionalIEs in the order they are given, and the IEs in the grouped
+	// IEs in the order given to the constructors. This is the default.
+	IEOrderAsIs IEOrder = iota
+
+	// IEOrderByType sorts the IEs by type and instance in ascending order, in the same
+	// way as MarshalOrdered.
+	IEOrderByType
+
+	// IEOrderSpec arranges the IEs in the order defined in the tables of TS 29.274
+	// clause 7, which begin with the mandatory ones in most of the messages. The IEs
+	// not defined for the message type follow the defined ones in the order given,
+	// and the Private Extensions are placed at the end. The IEs in Bearer Contexts
+	// are arranged in the same way.
+	IEOrderSpec
+)
+
+// String returns the name of IEOrder.
+func (o IEOrder) String() string {
+	switch o {
+	case IEOrderAsIs:
+		return "AsIs"
+	case IEOrderByType:
+		return "ByType"
+	case IEOrderSpec:
+		return "Spec"
+	default:
+		return "Unknown"
+	}
+}
+
+// MarshalOrdered serializes Message into bytes with the IEs sorted by type and
+// instance, in ascending order.
+//
+// The order of the IEs with the same type and instance is kept as it is, and the
+// IEs in the grouped IEs are sorted in the same way. This is useful to get the
+// deterministic output regardless of the order of the IEs given to the constructors
+// or appended to AdditionalIEs.
+func MarshalOrdered(m Message) ([]byte, error) {
+	return MarshalWithIEOrder(m, IEOrderByType)
+}
+
+// MarshalWithIEOrder serializes Message into bytes with the IEs arranged in order.
+func MarshalWithIEOrder(m Message, order IEOrder) ([]byte, error) {
+	b, err := Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	if err := ArrangeIEs(b, order); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// ArrangeIEs arranges the IEs in the serialized message b in order, in place.
+//
+// Only the message at the beginning of b is arranged, as the length is taken from
+// the header, so that the piggybacked messages can be arranged one by one.
+func ArrangeIEs(b []byte, order IEOrder) error {
+	if order == IEOrderAsIs {
+		return nil
+	}
+
+	offset, ok := payloadOffset(b)
+	if !ok {
+		return ErrTooShortToParse
+	}
+	end := int(binary.BigEndian.Uint16(b[2:4])) + 4
+	if end < offset || end > len(b) {
+		return ErrInvalidLength
+	}
+
+	switch order {
+	case IEOrderByType:
+		return sortIEs(b[offset:end])
+	case IEOrderSpec:
+		return arrangeIEs(b[offset:end], specIEOrders[b[1]])
+	default:
+		return errors.Errorf("unknown IEOrder: %d", order)
+	}
+}
+
+// splitIEs returns the copies of the IEs in b.
+func splitIEs(b []byte) ([][]byte, error) {
+	var chunks [][]byte
+	for len(b) >= 4 {
+		n := int(binary.BigEndian.Uint16(b[1:3])) + 4
+		if n > len(b) {
+			return nil, ErrInvalidLength
+		}
+
+		chunk := make([]byte, n)
+		copy(chunk, b[:n])
+		chunks = append(chunks, chunk)
+		b = b[n:]
+	}
+	return chunks, nil
+}
+
+// sortIEs sorts the IEs in b in place.
+func sortIEs(b []byte) error {
+	chunks, err := splitIEs(b)
+	if err != nil {
+		return err
+	}
+	for _, chunk := range chunks {
+		if (&ies.IE{Type: chunk[0]}).IsGrouped() {
+			if err := sortIEs(chunk[4:]); err != nil {
+				return err
+			}
+		}
+	}
+
+	sort.SliceStable(chunks, func(i, j int) bool {
+		if chunks[i][0] != chunks[j][0] {
+			return chunks[i][0] < chunks[j][0]
+		}
+		return chunks[i][3]&0x0f < chunks[j][3]&0x0f
+	})
+
+	offset := 0
+	for _, chunk := range chunks {
+		offset += copy(b[offset:], chunk)
+	}
+	return nil
+}
+
+// arrangeIEs arranges the IEs in b in place in the order of keys, and the IEs in the
+// grouped IEs in the order defined for them.
+func arrangeIEs(b []byte, keys []ieKey) error {
+	chunks, err := splitIEs(b)
+	if err != nil {
+		return err
+	}
+
+	rank := map[ieKey]int{}
+	for n, k := range keys {
+		if _, ok := rank[k]; !ok {
+			rank[k] = n
+		}
+	}
+
+	type rankedIE struct {
+		b    []byte
+		rank int
+	}
+	ranked := make([]rankedIE, len(chunks))
+	for n, chunk := range chunks {
+		typ := chunk[0]
+		if (&ies.IE{Type: typ}).IsGrouped() {
+			var inner []ieKey
+			if typ == ies.BearerContext {
+				inner = bearerContextIEOrder
+			}
+			if err := arrangeIEs(chunk[4:], inner); err != nil {
+				return err
+			}
+		}
+
+		r, ok := rank[ieKey{typ, chunk[3] & 0x0f}]
+		switch {
+		case typ == ies.PrivateExtension:
+			r = len(keys) + 1
+		case !ok:
+			r = len(keys)
+		}
+		ranked[n] = rankedIE{chunk, r}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].rank < ranked[j].rank
+	})
+
+	offset := 0
+	for _, r := range ranked {
+		offset += copy(b[offset:], r.b)
+	}
+	return nil
+}
+
+// payloadOffset returns the offset of the first IE in the given bytes of a message.
+func payloadOffset(b []byte) (int, bool) {
+	if len(b) < 4 {
+		return 0, false
+	}
+
+	offset := 8
+	if (b[0]>>3)&0x01 == 1 {
+		offset = 12
+	}
+	if len(b) < offset {
+		return 0, false
+	}
+	return offset, true
+}
@@ -0,0 +1,187 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+	"github.com/wmnsk/go-gtp/v2/testutils"
+)
+
+func TestCheckDuplicateIEs(t *testing.T) {
+	cases := []struct {
+		description string
+		msg         messages.Message
+		wantErr     bool
+	}{
+		{
+			"NoDuplicate",
+			messages.NewDeleteBearerRequest(
+				testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+				ies.NewEPSBearerID(0x05).WithInstance(1),
+				ies.NewEPSBearerID(0x06).WithInstance(1),
+			),
+			false,
+		}, {
+			"RepeatableIE",
+			&messages.DeleteSessionRequest{
+				Header: messages.NewHeader(
+					messages.NewHeaderFlags(2, 0, 1), messages.MsgTypeDeleteSessionRequest,
+					testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq, nil,
+				),
+				LinkedEBI:     ies.NewEPSBearerID(0x05),
+				AdditionalIEs: []*ies.IE{ies.NewEPSBearerID(0x06).WithInstance(0), ies.NewRecovery(1)},
+			},
+			false,
+		}, {
+			"PrivateExtensions",
+			messages.NewEchoRequest(
+				testutils.TestBearerInfo.Seq,
+				ies.NewPrivateExtension(10415, []byte{0x01}),
+				ies.NewPrivateExtension(0x1234, []byte{0x02}),
+			),
+			false,
+		}, {
+			"DuplicateInGroupedIE",
+			messages.NewCreateBearerRequest(
+				testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+				ies.NewEPSBearerID(0x05),
+				ies.NewBearerContext(ies.NewChargingID(1), ies.NewChargingID(2)),
+			),
+			true,
+		}, {
+			"DuplicateAtTopLevel",
+			&messages.DeleteSessionRequest{
+				Header: messages.NewHeader(
+					messages.NewHeaderFlags(2, 0, 1), messages.MsgTypeDeleteSessionRequest,
+					testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq, nil,
+				),
+				Cause:         ies.NewCause(16, 0, 0, 0, nil),
+				AdditionalIEs: []*ies.IE{ies.NewCause(64, 0, 0, 0, nil)},
+			},
+			true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			b, err := messages.Marshal(c.msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = messages.CheckDuplicateIEs(b)
+			if !c.wantErr {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if _, ok := err.(*messages.DuplicateIEError); !ok {
+				t.Errorf("DuplicateIEError should be returned, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestMarshalOrdered(t *testing.T) {
+	newMsg := func(ie ...*ies.IE) messages.Message {
+		return messages.NewGeneric(
+			messages.MsgTypeCreateBearerRequest,
+			testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq, ie...,
+		)
+	}
+
+	ebi5, ebi6 := ies.NewEPSBearerID(0x05), ies.NewEPSBearerID(0x06)
+	got, err := messages.MarshalOrdered(newMsg(
+		ies.NewBearerContext(ies.NewChargingID(1), ebi6),
+		ies.NewRecovery(1).WithInstance(1),
+		ebi5,
+		ies.NewRecovery(2),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := messages.Marshal(newMsg(
+		ies.NewRecovery(2),
+		ies.NewRecovery(1).WithInstance(1),
+		ebi5,
+		ies.NewBearerContext(ebi6, ies.NewChargingID(1)),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("wrong order.\nwant: %x\ngot:  %x", want, got)
+	}
+}
+
+func TestMarshalWithIEOrder(t *testing.T) {
+	newMsg := func(ie ...*ies.IE) messages.Message {
+		return messages.NewGeneric(
+			messages.MsgTypeCreateSessionResponse,
+			testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq, ie...,
+		)
+	}
+	var (
+		cause    = ies.NewCause(16, 0, 0, 0, nil)
+		sgwFTEID = ies.NewFullyQualifiedTEID(10, 0xffffffff, "1.1.1.1", "")
+		pgwFTEID = ies.NewFullyQualifiedTEID(7, 0xeeeeeeee, "2.2.2.2", "").WithInstance(1)
+		ebi      = ies.NewEPSBearerID(0x05)
+		chargeID = ies.NewChargingID(1)
+		recovery = ies.NewRecovery(1)
+		msisdn   = ies.NewMSISDN("819012345678")
+		privExt  = ies.NewPrivateExtension(10415, []byte{0x01})
+	)
+	given := newMsg(
+		privExt, ies.NewBearerContext(chargeID, cause, ebi), recovery, msisdn, cause, pgwFTEID, sgwFTEID,
+	)
+
+	cases := []struct {
+		description string
+		order       messages.IEOrder
+		want        messages.Message
+	}{
+		{
+			"AsIs",
+			messages.IEOrderAsIs,
+			given,
+		}, {
+			"ByType",
+			messages.IEOrderByType,
+			newMsg(
+				cause, recovery, msisdn, sgwFTEID, pgwFTEID, ies.NewBearerContext(cause, ebi, chargeID), privExt,
+			),
+		}, {
+			"Spec",
+			messages.IEOrderSpec,
+			newMsg(
+				cause, sgwFTEID, pgwFTEID, ies.NewBearerContext(ebi, cause, chargeID), recovery, msisdn, privExt,
+			),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			got, err := messages.MarshalWithIEOrder(given, c.order)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := messages.Marshal(c.want)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(got, want) {
+				t.Errorf("wrong order.\nwant: %x\ngot:  %x", want, got)
+			}
+		})
+	}
+}
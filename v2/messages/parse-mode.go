@@ -0,0 +1,198 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"encoding/binary"
+	"reflect"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+)
+
+// ParseMode is the way to handle the IEs that are malformed or not defined in the
+// message when decoding it.
+type ParseMode uint8
+
+// ParseMode definitions.
+const (
+	// ParseModeDefault fails to decode the message that contains any malformed IE,
+	// and keeps the IEs not defined in the message in AdditionalIEs. This is the
+	// same as Parse.
+	ParseModeDefault ParseMode = iota
+	// ParseModeLenient keeps the malformed IEs at the top level of the message in
+	// AdditionalIEs as the raw IEs (see ies.ParseRaw) instead of failing, so that
+	// they are serialized as they are received. The trailing bytes that are too
+	// short to be an IE are discarded.
+	ParseModeLenient
+	// ParseModeStrict fails to decode the message with MalformedIEError if any IE
+	// including the ones in the grouped IEs is malformed, or with UnknownIEError if
+	// any IE except PrivateExtension is not defined in the message. The duplicated
+	// IEs are not reported, which can be checked with CheckDuplicateIEs.
+	ParseModeStrict
+)
+
+// ParseWithMode decodes the given bytes as Message in the ParseMode given.
+func ParseWithMode(b []byte, mode ParseMode) (Message, error) {
+	switch mode {
+	case ParseModeLenient:
+		return parseLenient(b)
+	case ParseModeStrict:
+		return parseStrict(b)
+	default:
+		return Parse(b)
+	}
+}
+
+func parseLenient(b []byte) (Message, error) {
+	offset, ok := payloadOffset(b)
+	if !ok {
+		return Parse(b)
+	}
+
+	var raw []*ies.IE
+	valid := make([]byte, offset, len(b))
+	copy(valid, b[:offset])
+	rest := b[offset:]
+	for len(rest) >= 4 {
+		n := int(binary.BigEndian.Uint16(rest[1:3])) + 4
+		if n > len(rest) {
+			n = len(rest)
+		} else if _, err := ies.Parse(rest[:n]); err == nil {
+			valid = append(valid, rest[:n]...)
+			rest = rest[n:]
+			continue
+		}
+
+		i, err := ies.ParseRaw(rest[:n])
+		if err != nil {
+			return nil, err
+		}
+		raw = append(raw, i)
+		rest = rest[n:]
+	}
+	if len(raw) == 0 && len(rest) == 0 {
+		return Parse(b)
+	}
+
+	binary.BigEndian.PutUint16(valid[2:4], uint16(len(valid)-4))
+	m, err := Parse(valid)
+	if err != nil {
+		return nil, err
+	}
+
+	if g, ok := m.(*Generic); ok {
+		g.IEs = append(g.IEs, raw...)
+		return m, nil
+	}
+	if f := additionalIEsOf(m); f.IsValid() {
+		f.Set(reflect.AppendSlice(f, reflect.ValueOf(raw)))
+	}
+	return m, nil
+}
+
+func parseStrict(b []byte) (Message, error) {
+	if err := locateMalformedIE(b); err != nil {
+		return nil, err
+	}
+
+	m, err := Parse(b)
+	if err != nil {
+		return nil, err
+	}
+	offset, _ := payloadOffset(b)
+	if err := checkUnknownIEs(m, b[offset:], offset); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// locateMalformedIE returns MalformedIEError for the first IE in the message b whose
+// length does not fit, or nil if there is no such IE.
+func locateMalformedIE(b []byte) error {
+	offset, ok := payloadOffset(b)
+	if !ok {
+		return nil
+	}
+	return checkMalformedIEs(b[offset:], offset)
+}
+
+// checkMalformedIEs checks the IEs in b recursively, with base as the offset of b from
+// the beginning of the message.
+func checkMalformedIEs(b []byte, base int) error {
+	offset := 0
+	for offset < len(b) {
+		rest := b[offset:]
+		if len(rest) < 4 {
+			return &MalformedIEError{Offset: base + offset, Type: rest[0], Err: ies.ErrTooShortToParse}
+		}
+
+		n := int(binary.BigEndian.Uint16(rest[1:3])) + 4
+		if n > len(rest) {
+			return &MalformedIEError{
+				Offset: base + offset, Type: rest[0], Instance: rest[3] & 0x0f, Err: ies.ErrInvalidLength,
+			}
+		}
+		if (&ies.IE{Type: rest[0]}).IsGrouped() {
+			if err := checkMalformedIEs(rest[4:n], base+offset+4); err != nil {
+				return err
+			}
+		}
+		offset += n
+	}
+	return nil
+}
+
+// checkUnknownIEs checks if m has the IEs in AdditionalIEs that are not defined in m,
+// and returns UnknownIEError with the offset of the first one found in b.
+func checkUnknownIEs(m Message, b []byte, base int) error {
+	f := additionalIEsOf(m)
+	if !f.IsValid() || f.Len() == 0 {
+		return nil
+	}
+
+	// the type and instance that also appear in the other fields are the duplicated
+	// ones, not the unknown ones.
+	count := map[uint16]int{}
+	for _, i := range IEs(m) {
+		count[uint16(i.Type)<<8|uint16(i.Instance())]++
+	}
+	unknown := map[uint16]bool{}
+	for _, i := range f.Interface().([]*ies.IE) {
+		count[uint16(i.Type)<<8|uint16(i.Instance())]--
+	}
+	for _, i := range f.Interface().([]*ies.IE) {
+		key := uint16(i.Type)<<8 | uint16(i.Instance())
+		if i.Type != ies.PrivateExtension && count[key] == 0 {
+			unknown[key] = true
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	offset := 0
+	for offset+4 <= len(b) {
+		typ, ins := b[offset], b[offset+3]&0x0f
+		if unknown[uint16(typ)<<8|uint16(ins)] {
+			return &UnknownIEError{Offset: base + offset, Type: typ, Instance: ins}
+		}
+		offset += int(binary.BigEndian.Uint16(b[offset+1:offset+3])) + 4
+	}
+	return nil
+}
+
+// additionalIEsOf returns the AdditionalIEs field of m, or zero Value if m has no such
+// field.
+func additionalIEsOf(m Message) reflect.Value {
+	v := reflect.ValueOf(m)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+	f := v.Elem().FieldByName("AdditionalIEs")
+	if !f.IsValid() || f.Type() != ieSliceType {
+		return reflect.Value{}
+	}
+	return f
+}
@@ -0,0 +1,85 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+	"github.com/wmnsk/go-gtp/v2/testutils"
+)
+
+func TestParseWithMode(t *testing.T) {
+	base, err := messages.NewCreateBearerRequest(
+		testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+		ies.NewEPSBearerID(0x05),
+		ies.NewBearerContext(ies.NewEPSBearerID(0x06)),
+		ies.New(250, 0, []byte{0xde, 0xad}),
+	).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// BearerContext with the child IE whose Length exceeds the parent.
+	malformed := append(base, 0x5d, 0x00, 0x06, 0x00, 0x49, 0x00, 0x05, 0x00, 0x05, 0x06)
+	binary.BigEndian.PutUint16(malformed[2:4], uint16(len(malformed)-4))
+
+	t.Run("Default", func(t *testing.T) {
+		_, err := messages.ParseWithMode(malformed, messages.ParseModeDefault)
+		e, ok := errors.Cause(err).(*messages.MalformedIEError)
+		if !ok {
+			t.Fatalf("MalformedIEError should be the cause, got: %v", err)
+		}
+		if e.Offset != len(base)+4 || e.Type != ies.EPSBearerID {
+			t.Errorf("unexpected error: %v", e)
+		}
+	})
+
+	t.Run("Lenient", func(t *testing.T) {
+		msg, err := messages.ParseWithMode(malformed, messages.ParseModeLenient)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cbr := msg.(*messages.CreateBearerRequest)
+		if cbr.BearerContexts == nil || len(cbr.AdditionalIEs) != 2 || !cbr.AdditionalIEs[1].IsRaw() {
+			t.Fatalf("unexpected IEs: %v, %v", cbr.BearerContexts, cbr.AdditionalIEs)
+		}
+
+		got, err := messages.Marshal(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(got, malformed); diff != "" {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("StrictMalformed", func(t *testing.T) {
+		_, err := messages.ParseWithMode(malformed, messages.ParseModeStrict)
+		e, ok := err.(*messages.MalformedIEError)
+		if !ok {
+			t.Fatalf("MalformedIEError should be returned, got: %v", err)
+		}
+		want := &messages.MalformedIEError{Offset: len(base) + 4, Type: ies.EPSBearerID, Err: ies.ErrInvalidLength}
+		if e.Offset != want.Offset || e.Type != want.Type || e.Instance != want.Instance || e.Err != want.Err {
+			t.Errorf("unexpected error: got %v, want %v", e, want)
+		}
+	})
+
+	t.Run("StrictUnknown", func(t *testing.T) {
+		_, err := messages.ParseWithMode(base, messages.ParseModeStrict)
+		e, ok := err.(*messages.UnknownIEError)
+		if !ok {
+			t.Fatalf("UnknownIEError should be returned, got: %v", err)
+		}
+		if diff := cmp.Diff(e, &messages.UnknownIEError{Offset: len(base) - 6, Type: 250}); diff != "" {
+			t.Error(diff)
+		}
+	})
+}
@@ -0,0 +1,169 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import "github.com/wmnsk/go-gtp/v2/ies"
+
+// PGWRestartNotificationAcknowledge is a PGWRestartNotificationAcknowledge Header and its IEs above.
+type PGWRestartNotificationAcknowledge struct {
+	*Header
+	Cause            *ies.IE
+	PrivateExtension *ies.IE
+	AdditionalIEs    []*ies.IE
+}
+
+// NewPGWRestartNotificationAcknowledge creates a new PGWRestartNotificationAcknowledge.
+func NewPGWRestartNotificationAcknowledge(teid, seq uint32, ie ...*ies.IE) *PGWRestartNotificationAcknowledge {
+	p := &PGWRestartNotificationAcknowledge{
+		Header: NewHeader(
+			NewHeaderFlags(2, 0, 1),
+			MsgTypePGWRestartNotificationAcknowledge, teid, seq, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			p.Cause = i
+		case ies.PrivateExtension:
+			if p.PrivateExtension == nil {
+				p.PrivateExtension = i
+			} else {
+				p.AdditionalIEs = append(p.AdditionalIEs, i)
+			}
+		default:
+			p.AdditionalIEs = append(p.AdditionalIEs, i)
+		}
+	}
+
+	p.SetLength()
+	return p
+}
+
+// Marshal serializes PGWRestartNotificationAcknowledge into bytes.
+func (p *PGWRestartNotificationAcknowledge) Marshal() ([]byte, error) {
+	b := make([]byte, p.MarshalLen())
+	if err := p.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MarshalTo serializes PGWRestartNotificationAcknowledge into bytes.
+func (p *PGWRestartNotificationAcknowledge) MarshalTo(b []byte) error {
+	if p.Header.Payload != nil {
+		p.Header.Payload = nil
+	}
+	p.Header.Payload = make([]byte, p.MarshalLen()-p.Header.MarshalLen())
+
+	offset := 0
+	if ie := p.Cause; ie != nil {
+		if err := ie.MarshalTo(p.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := p.PrivateExtension; ie != nil {
+		if err := ie.MarshalTo(p.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	for _, ie := range p.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(p.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	p.Header.SetLength()
+	return p.Header.MarshalTo(b)
+}
+
+// ParsePGWRestartNotificationAcknowledge decodes given bytes as PGWRestartNotificationAcknowledge.
+func ParsePGWRestartNotificationAcknowledge(b []byte) (*PGWRestartNotificationAcknowledge, error) {
+	p := &PGWRestartNotificationAcknowledge{}
+	if err := p.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// UnmarshalBinary decodes given bytes as PGWRestartNotificationAcknowledge.
+func (p *PGWRestartNotificationAcknowledge) UnmarshalBinary(b []byte) error {
+	var err error
+	p.Header, err = ParseHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(p.Header.Payload) < 2 {
+		return nil
+	}
+
+	decodedIEs, err := ies.ParseMultiIEs(p.Header.Payload)
+	if err != nil {
+		return err
+	}
+	for _, i := range decodedIEs {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			p.Cause = i
+		case ies.PrivateExtension:
+			if p.PrivateExtension == nil {
+				p.PrivateExtension = i
+			} else {
+				p.AdditionalIEs = append(p.AdditionalIEs, i)
+			}
+		default:
+			p.AdditionalIEs = append(p.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length in int.
+func (p *PGWRestartNotificationAcknowledge) MarshalLen() int {
+	l := p.Header.MarshalLen() - len(p.Header.Payload)
+	if ie := p.Cause; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := p.PrivateExtension; ie != nil {
+		l += ie.MarshalLen()
+	}
+
+	for _, ie := range p.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (p *PGWRestartNotificationAcknowledge) SetLength() {
+	p.Header.Length = uint16(p.MarshalLen() - 4)
+}
+
+// MessageTypeName returns the name of protocol.
+func (p *PGWRestartNotificationAcknowledge) MessageTypeName() string {
+	return "PGW Restart Notification Acknowledge"
+}
+
+// TEID returns the TEID in uint32.
+func (p *PGWRestartNotificationAcknowledge) TEID() uint32 {
+	return p.Header.teid()
+}
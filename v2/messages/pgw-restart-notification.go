@@ -0,0 +1,207 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import "github.com/wmnsk/go-gtp/v2/ies"
+
+// PGWRestartNotification is a PGWRestartNotification Header and its IEs above.
+type PGWRestartNotification struct {
+	*Header
+	PGWS5S8IPAddressForControlPlane  *ies.IE
+	SGWS11S4IPAddressForControlPlane *ies.IE
+	Cause                            *ies.IE
+	PrivateExtension                 *ies.IE
+	AdditionalIEs                    []*ies.IE
+}
+
+// NewPGWRestartNotification creates a new PGWRestartNotification.
+func NewPGWRestartNotification(teid, seq uint32, ie ...*ies.IE) *PGWRestartNotification {
+	p := &PGWRestartNotification{
+		Header: NewHeader(
+			NewHeaderFlags(2, 0, 1),
+			MsgTypePGWRestartNotification, teid, seq, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.IPAddress:
+			switch i.Instance() {
+			case 0:
+				p.PGWS5S8IPAddressForControlPlane = i
+			case 1:
+				p.SGWS11S4IPAddressForControlPlane = i
+			default:
+				p.AdditionalIEs = append(p.AdditionalIEs, i)
+			}
+		case ies.Cause:
+			p.Cause = i
+		case ies.PrivateExtension:
+			if p.PrivateExtension == nil {
+				p.PrivateExtension = i
+			} else {
+				p.AdditionalIEs = append(p.AdditionalIEs, i)
+			}
+		default:
+			p.AdditionalIEs = append(p.AdditionalIEs, i)
+		}
+	}
+
+	p.SetLength()
+	return p
+}
+
+// Marshal serializes PGWRestartNotification into bytes.
+func (p *PGWRestartNotification) Marshal() ([]byte, error) {
+	b := make([]byte, p.MarshalLen())
+	if err := p.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MarshalTo serializes PGWRestartNotification into bytes.
+func (p *PGWRestartNotification) MarshalTo(b []byte) error {
+	if p.Header.Payload != nil {
+		p.Header.Payload = nil
+	}
+	p.Header.Payload = make([]byte, p.MarshalLen()-p.Header.MarshalLen())
+
+	offset := 0
+	if ie := p.PGWS5S8IPAddressForControlPlane; ie != nil {
+		if err := ie.MarshalTo(p.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := p.SGWS11S4IPAddressForControlPlane; ie != nil {
+		if err := ie.MarshalTo(p.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := p.Cause; ie != nil {
+		if err := ie.MarshalTo(p.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := p.PrivateExtension; ie != nil {
+		if err := ie.MarshalTo(p.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	for _, ie := range p.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(p.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	p.Header.SetLength()
+	return p.Header.MarshalTo(b)
+}
+
+// ParsePGWRestartNotification decodes given bytes as PGWRestartNotification.
+func ParsePGWRestartNotification(b []byte) (*PGWRestartNotification, error) {
+	p := &PGWRestartNotification{}
+	if err := p.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// UnmarshalBinary decodes given bytes as PGWRestartNotification.
+func (p *PGWRestartNotification) UnmarshalBinary(b []byte) error {
+	var err error
+	p.Header, err = ParseHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(p.Header.Payload) < 2 {
+		return nil
+	}
+
+	decodedIEs, err := ies.ParseMultiIEs(p.Header.Payload)
+	if err != nil {
+		return err
+	}
+	for _, i := range decodedIEs {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.IPAddress:
+			switch i.Instance() {
+			case 0:
+				p.PGWS5S8IPAddressForControlPlane = i
+			case 1:
+				p.SGWS11S4IPAddressForControlPlane = i
+			default:
+				p.AdditionalIEs = append(p.AdditionalIEs, i)
+			}
+		case ies.Cause:
+			p.Cause = i
+		case ies.PrivateExtension:
+			if p.PrivateExtension == nil {
+				p.PrivateExtension = i
+			} else {
+				p.AdditionalIEs = append(p.AdditionalIEs, i)
+			}
+		default:
+			p.AdditionalIEs = append(p.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length in int.
+func (p *PGWRestartNotification) MarshalLen() int {
+	l := p.Header.MarshalLen() - len(p.Header.Payload)
+	if ie := p.PGWS5S8IPAddressForControlPlane; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := p.SGWS11S4IPAddressForControlPlane; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := p.Cause; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := p.PrivateExtension; ie != nil {
+		l += ie.MarshalLen()
+	}
+
+	for _, ie := range p.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (p *PGWRestartNotification) SetLength() {
+	p.Header.Length = uint16(p.MarshalLen() - 4)
+}
+
+// MessageTypeName returns the name of protocol.
+func (p *PGWRestartNotification) MessageTypeName() string {
+	return "PGW Restart Notification"
+}
+
+// TEID returns the TEID in uint32.
+func (p *PGWRestartNotification) TEID() uint32 {
+	return p.Header.teid()
+}
@@ -0,0 +1,47 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages_test
+
+import (
+	"testing"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+	"github.com/wmnsk/go-gtp/v2/testutils"
+)
+
+func TestPGWRestartNotification(t *testing.T) {
+	cases := []testutils.TestCase{
+		{
+			Description: "Normal",
+			Structured: messages.NewPGWRestartNotification(
+				testutils.TestBearerInfo.TEID, testutils.TestBearerInfo.Seq,
+				ies.NewIPAddress("1.1.1.1"),
+				ies.NewIPAddress("1.1.1.2").WithInstance(1),
+				ies.NewCause(v2.CausePGWNotResponding, 0, 0, 0, nil),
+			),
+			Serialized: []byte{
+				// Header
+				0x48, 0xb3, 0x00, 0x1e, 0x11, 0x22, 0x33, 0x44, 0x00, 0x00, 0x01, 0x00,
+				// PGW S5/S8 IP Address for Control Plane
+				0x4a, 0x00, 0x04, 0x00, 0x01, 0x01, 0x01, 0x01,
+				// SGW S11/S4 IP Address for Control Plane
+				0x4a, 0x00, 0x04, 0x01, 0x01, 0x01, 0x01, 0x02,
+				// Cause
+				0x02, 0x00, 0x02, 0x00, 0x0c, 0x00,
+			},
+		},
+	}
+
+	testutils.Run(t, cases, func(b []byte) (testutils.Serializable, error) {
+		v, err := messages.ParsePGWRestartNotification(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+}
@@ -0,0 +1,103 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// messageTypes is the message type of each type of Message except Generic.
+var messageTypes = map[reflect.Type]uint8{}
+
+func init() {
+	generic := reflect.TypeOf(&Generic{})
+	for i := 0; i < 256; i++ {
+		if t := reflect.TypeOf(newMessage(uint8(i))); t != generic {
+			messageTypes[t] = uint8(i)
+		}
+	}
+}
+
+// ParseInto decodes the given bytes into m, which is reset before decoding. This is
+// to reuse the Message of the same type instead of allocating a new one with Parse
+// each time, and the IEs are still allocated.
+//
+// It returns ErrTypeMismatch if the message type in b is not the type of m, except
+// for *Generic and *Lazy which accept any type. Note that m refers to b after decoding
+// as the one returned by Parse does, and thus b must not be modified while m is in use.
+func ParseInto(b []byte, m Message) error {
+	if len(b) < 4 {
+		return ErrTooShortToParse
+	}
+
+	v := reflect.ValueOf(m)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return ErrTypeMismatch
+	}
+	switch m.(type) {
+	case *Generic, *Lazy:
+	default:
+		if t, ok := messageTypes[v.Type()]; !ok || t != b[1] {
+			return ErrTypeMismatch
+		}
+	}
+
+	v.Elem().Set(reflect.Zero(v.Elem().Type()))
+	if err := m.UnmarshalBinary(b); err != nil {
+		return errors.Wrap(err, "failed to decode GTPv2 Message")
+	}
+	return nil
+}
+
+// Pool is a set of Messages to be reused when decoding, which reduces the allocations
+// on the nodes that handle the large number of messages and discard them soon.
+//
+// The zero value is ready to use.
+type Pool struct {
+	pools [256]sync.Pool
+}
+
+// Parse decodes the given bytes as Message like Parse, reusing the one put back to the
+// pool with Put if available.
+func (p *Pool) Parse(b []byte) (Message, error) {
+	if len(b) < 4 {
+		return nil, ErrTooShortToParse
+	}
+
+	m, ok := p.pools[b[1]].Get().(Message)
+	if !ok {
+		m = newMessage(b[1])
+	}
+	if err := ParseInto(b, m); err != nil {
+		p.pools[b[1]].Put(m)
+		return nil, err
+	}
+	return m, nil
+}
+
+// Put puts m returned by (*Pool) Parse back to the pool. m and the IEs in it must not
+// be used after calling this.
+func (p *Pool) Put(m Message) {
+	if m == nil {
+		return
+	}
+
+	v := reflect.ValueOf(m)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return
+	}
+	t, ok := messageTypes[v.Type()]
+	if !ok {
+		g, ok := m.(*Generic)
+		if !ok || g.Header == nil {
+			return
+		}
+		t = g.MessageType()
+	}
+	p.pools[t].Put(m)
+}
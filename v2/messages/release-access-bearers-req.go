@@ -40,7 +40,11 @@ func NewReleaseAccessBearersRequest(teid, seq uint32, ie ...*ies.IE) *ReleaseAcc
 		case ies.SecondaryRATUsageDataReport:
 			r.SecondaryRATUsageDataReport = i
 		case ies.PrivateExtension:
-			r.PrivateExtension = i
+			if r.PrivateExtension == nil {
+				r.PrivateExtension = i
+			} else {
+				r.AdditionalIEs = append(r.AdditionalIEs, i)
+			}
 		default:
 			r.AdditionalIEs = append(r.AdditionalIEs, i)
 		}
@@ -150,7 +154,11 @@ func (r *ReleaseAccessBearersRequest) UnmarshalBinary(b []byte) error {
 		case ies.SecondaryRATUsageDataReport:
 			r.SecondaryRATUsageDataReport = i
 		case ies.PrivateExtension:
-			r.PrivateExtension = i
+			if r.PrivateExtension == nil {
+				r.PrivateExtension = i
+			} else {
+				r.AdditionalIEs = append(r.AdditionalIEs, i)
+			}
 		default:
 			r.AdditionalIEs = append(r.AdditionalIEs, i)
 		}
@@ -40,7 +40,11 @@ func NewReleaseAccessBearersResponse(teid, seq uint32, ie ...*ies.IE) *ReleaseAc
 		case ies.OverloadControlInformation:
 			r.SGWOverloadControlInformation = i
 		case ies.PrivateExtension:
-			r.PrivateExtension = i
+			if r.PrivateExtension == nil {
+				r.PrivateExtension = i
+			} else {
+				r.AdditionalIEs = append(r.AdditionalIEs, i)
+			}
 		default:
 			r.AdditionalIEs = append(r.AdditionalIEs, i)
 		}
@@ -150,7 +154,11 @@ func (r *ReleaseAccessBearersResponse) UnmarshalBinary(b []byte) error {
 		case ies.OverloadControlInformation:
 			r.SGWOverloadControlInformation = i
 		case ies.PrivateExtension:
-			r.PrivateExtension = i
+			if r.PrivateExtension == nil {
+				r.PrivateExtension = i
+			} else {
+				r.AdditionalIEs = append(r.AdditionalIEs, i)
+			}
 		default:
 			r.AdditionalIEs = append(r.AdditionalIEs, i)
 		}
@@ -0,0 +1,169 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import "github.com/wmnsk/go-gtp/v2/ies"
+
+// ResumeAcknowledge is a ResumeAcknowledge Header and its IEs above.
+type ResumeAcknowledge struct {
+	*Header
+	Cause            *ies.IE
+	PrivateExtension *ies.IE
+	AdditionalIEs    []*ies.IE
+}
+
+// NewResumeAcknowledge creates a new ResumeAcknowledge.
+func NewResumeAcknowledge(teid, seq uint32, ie ...*ies.IE) *ResumeAcknowledge {
+	r := &ResumeAcknowledge{
+		Header: NewHeader(
+			NewHeaderFlags(2, 0, 1),
+			MsgTypeResumeAcknowledge, teid, seq, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			r.Cause = i
+		case ies.PrivateExtension:
+			if r.PrivateExtension == nil {
+				r.PrivateExtension = i
+			} else {
+				r.AdditionalIEs = append(r.AdditionalIEs, i)
+			}
+		default:
+			r.AdditionalIEs = append(r.AdditionalIEs, i)
+		}
+	}
+
+	r.SetLength()
+	return r
+}
+
+// Marshal serializes ResumeAcknowledge into bytes.
+func (r *ResumeAcknowledge) Marshal() ([]byte, error) {
+	b := make([]byte, r.MarshalLen())
+	if err := r.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MarshalTo serializes ResumeAcknowledge into bytes.
+func (r *ResumeAcknowledge) MarshalTo(b []byte) error {
+	if r.Header.Payload != nil {
+		r.Header.Payload = nil
+	}
+	r.Header.Payload = make([]byte, r.MarshalLen()-r.Header.MarshalLen())
+
+	offset := 0
+	if ie := r.Cause; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.PrivateExtension; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	for _, ie := range r.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(r.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	r.Header.SetLength()
+	return r.Header.MarshalTo(b)
+}
+
+// ParseResumeAcknowledge decodes given bytes as ResumeAcknowledge.
+func ParseResumeAcknowledge(b []byte) (*ResumeAcknowledge, error) {
+	r := &ResumeAcknowledge{}
+	if err := r.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// UnmarshalBinary decodes given bytes as ResumeAcknowledge.
+func (r *ResumeAcknowledge) UnmarshalBinary(b []byte) error {
+	var err error
+	r.Header, err = ParseHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(r.Header.Payload) < 2 {
+		return nil
+	}
+
+	decodedIEs, err := ies.ParseMultiIEs(r.Header.Payload)
+	if err != nil {
+		return err
+	}
+	for _, i := range decodedIEs {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			r.Cause = i
+		case ies.PrivateExtension:
+			if r.PrivateExtension == nil {
+				r.PrivateExtension = i
+			} else {
+				r.AdditionalIEs = append(r.AdditionalIEs, i)
+			}
+		default:
+			r.AdditionalIEs = append(r.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length in int.
+func (r *ResumeAcknowledge) MarshalLen() int {
+	l := r.Header.MarshalLen() - len(r.Header.Payload)
+	if ie := r.Cause; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.PrivateExtension; ie != nil {
+		l += ie.MarshalLen()
+	}
+
+	for _, ie := range r.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (r *ResumeAcknowledge) SetLength() {
+	r.Header.Length = uint16(r.MarshalLen() - 4)
+}
+
+// MessageTypeName returns the name of protocol.
+func (r *ResumeAcknowledge) MessageTypeName() string {
+	return "Resume Acknowledge"
+}
+
+// TEID returns the TEID in uint32.
+func (r *ResumeAcknowledge) TEID() uint32 {
+	return r.Header.teid()
+}
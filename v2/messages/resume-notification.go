@@ -0,0 +1,211 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import "github.com/wmnsk/go-gtp/v2/ies"
+
+// ResumeNotification is a ResumeNotification Header and its IEs above.
+type ResumeNotification struct {
+	*Header
+	IMSI             *ies.IE
+	LinkedEBI        *ies.IE
+	OriginatingNode  *ies.IE
+	SenderFTEIDC     *ies.IE
+	PrivateExtension *ies.IE
+	AdditionalIEs    []*ies.IE
+}
+
+// NewResumeNotification creates a new ResumeNotification.
+func NewResumeNotification(teid, seq uint32, ie ...*ies.IE) *ResumeNotification {
+	r := &ResumeNotification{
+		Header: NewHeader(
+			NewHeaderFlags(2, 0, 1),
+			MsgTypeResumeNotification, teid, seq, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.IMSI:
+			r.IMSI = i
+		case ies.EPSBearerID:
+			r.LinkedEBI = i
+		case ies.NodeType:
+			r.OriginatingNode = i
+		case ies.FullyQualifiedTEID:
+			r.SenderFTEIDC = i
+		case ies.PrivateExtension:
+			if r.PrivateExtension == nil {
+				r.PrivateExtension = i
+			} else {
+				r.AdditionalIEs = append(r.AdditionalIEs, i)
+			}
+		default:
+			r.AdditionalIEs = append(r.AdditionalIEs, i)
+		}
+	}
+
+	r.SetLength()
+	return r
+}
+
+// Marshal serializes ResumeNotification into bytes.
+func (r *ResumeNotification) Marshal() ([]byte, error) {
+	b := make([]byte, r.MarshalLen())
+	if err := r.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MarshalTo serializes ResumeNotification into bytes.
+func (r *ResumeNotification) MarshalTo(b []byte) error {
+	if r.Header.Payload != nil {
+		r.Header.Payload = nil
+	}
+	r.Header.Payload = make([]byte, r.MarshalLen()-r.Header.MarshalLen())
+
+	offset := 0
+	if ie := r.IMSI; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.LinkedEBI; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.OriginatingNode; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.SenderFTEIDC; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := r.PrivateExtension; ie != nil {
+		if err := ie.MarshalTo(r.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	for _, ie := range r.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(r.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	r.Header.SetLength()
+	return r.Header.MarshalTo(b)
+}
+
+// ParseResumeNotification decodes given bytes as ResumeNotification.
+func ParseResumeNotification(b []byte) (*ResumeNotification, error) {
+	r := &ResumeNotification{}
+	if err := r.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// UnmarshalBinary decodes given bytes as ResumeNotification.
+func (r *ResumeNotification) UnmarshalBinary(b []byte) error {
+	var err error
+	r.Header, err = ParseHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(r.Header.Payload) < 2 {
+		return nil
+	}
+
+	decodedIEs, err := ies.ParseMultiIEs(r.Header.Payload)
+	if err != nil {
+		return err
+	}
+	for _, i := range decodedIEs {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.IMSI:
+			r.IMSI = i
+		case ies.EPSBearerID:
+			r.LinkedEBI = i
+		case ies.NodeType:
+			r.OriginatingNode = i
+		case ies.FullyQualifiedTEID:
+			r.SenderFTEIDC = i
+		case ies.PrivateExtension:
+			if r.PrivateExtension == nil {
+				r.PrivateExtension = i
+			} else {
+				r.AdditionalIEs = append(r.AdditionalIEs, i)
+			}
+		default:
+			r.AdditionalIEs = append(r.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length in int.
+func (r *ResumeNotification) MarshalLen() int {
+	l := r.Header.MarshalLen() - len(r.Header.Payload)
+	if ie := r.IMSI; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.LinkedEBI; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.OriginatingNode; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.SenderFTEIDC; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := r.PrivateExtension; ie != nil {
+		l += ie.MarshalLen()
+	}
+
+	for _, ie := range r.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (r *ResumeNotification) SetLength() {
+	r.Header.Length = uint16(r.MarshalLen() - 4)
+}
+
+// MessageTypeName returns the name of protocol.
+func (r *ResumeNotification) MessageTypeName() string {
+	return "Resume Notification"
+}
+
+// TEID returns the TEID in uint32.
+func (r *ResumeNotification) TEID() uint32 {
+	return r.Header.teid()
+}
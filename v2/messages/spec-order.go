@@ -0,0 +1,420 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import "github.com/wmnsk/go-gtp/v2/ies"
+
+// ieKey identifies an IE in a message by its type and instance.
+type ieKey struct {
+	typ, ins uint8
+}
+
+// specIEOrders is the order of the IEs in each message defined in the tables of
+// TS 29.274 clause 7, which is the same as the order of the fields in the structs.
+//
+// Private Extension is not listed, as it is always placed at the end.
+var specIEOrders = map[uint8][]ieKey{
+	MsgTypeEchoRequest: {
+		{ies.Recovery, 0}, {ies.NodeFeatures, 0},
+	},
+	MsgTypeEchoResponse: {
+		{ies.Recovery, 0}, {ies.NodeFeatures, 0},
+	},
+	MsgTypeCreateSessionRequest: {
+		{ies.IMSI, 0}, {ies.MSISDN, 0}, {ies.MobileEquipmentIdentity, 0},
+		{ies.UserLocationInformation, 0}, {ies.ServingNetwork, 0}, {ies.RATType, 0},
+		{ies.Indication, 0}, {ies.FullyQualifiedTEID, 0}, {ies.FullyQualifiedTEID, 1},
+		{ies.AccessPointName, 0}, {ies.SelectionMode, 0}, {ies.PDNType, 0},
+		{ies.PDNAddressAllocation, 0}, {ies.APNRestriction, 0},
+		{ies.AggregateMaximumBitRate, 0}, {ies.EPSBearerID, 0},
+		{ies.TrustedWLANModeIndication, 0}, {ies.ProtocolConfigurationOptions, 0},
+		{ies.BearerContext, 0}, {ies.BearerContext, 1}, {ies.TraceInformation, 0},
+		{ies.Recovery, 0}, {ies.FullyQualifiedCSID, 0}, {ies.FullyQualifiedCSID, 1},
+		{ies.FullyQualifiedCSID, 2}, {ies.FullyQualifiedCSID, 3}, {ies.UETimeZone, 0},
+		{ies.UserCSGInformation, 0}, {ies.ChargingCharacteristics, 0},
+		{ies.LocalDistinguishedName, 0}, {ies.LocalDistinguishedName, 1},
+		{ies.LocalDistinguishedName, 2}, {ies.LocalDistinguishedName, 3},
+		{ies.SignallingPriorityIndication, 0}, {ies.IPAddress, 0}, {ies.PortNumber, 0},
+		{ies.AdditionalProtocolConfigurationOptions, 0}, {ies.IPAddress, 1},
+		{ies.PortNumber, 1}, {ies.IPAddress, 2}, {ies.TWANIdentifier, 0}, {ies.IPAddress, 3},
+		{ies.CNOperatorSelectionEntity, 0}, {ies.PresenceReportingAreaInformation, 0},
+		{ies.OverloadControlInformation, 0}, {ies.OverloadControlInformation, 1},
+		{ies.OverloadControlInformation, 2}, {ies.MillisecondTimeStamp, 0},
+		{ies.IntegerNumber, 0}, {ies.TWANIdentifier, 1}, {ies.TWANIdentifierTimestamp, 0},
+		{ies.FContainer, 0}, {ies.RemoteUEContext, 0}, {ies.NodeIdentifier, 0},
+		{ies.ExtendedProtocolConfigurationOptions, 0}, {ies.ServingPLMNRateControl, 0},
+		{ies.Counter, 0}, {ies.PortNumber, 2}, {ies.MappedUEUsageType, 0},
+		{ies.UserLocationInformation, 1}, {ies.FullyQualifiedDomainName, 0},
+		{ies.SecondaryRATUsageDataReport, 0}, {ies.UPFunctionSelectionIndicationFlags, 0},
+		{ies.APNRateControlStatus, 0},
+	},
+	MsgTypeCreateSessionResponse: {
+		{ies.Cause, 0}, {ies.ChangeReportingAction, 0}, {ies.HeNBInformationReporting, 0},
+		{ies.FullyQualifiedTEID, 0}, {ies.FullyQualifiedTEID, 1},
+		{ies.PDNAddressAllocation, 0}, {ies.APNRestriction, 0},
+		{ies.AggregateMaximumBitRate, 0}, {ies.EPSBearerID, 0},
+		{ies.ProtocolConfigurationOptions, 0}, {ies.BearerContext, 0}, {ies.BearerContext, 1},
+		{ies.Recovery, 0}, {ies.FullyQualifiedDomainName, 0}, {ies.IPAddress, 0},
+		{ies.FullyQualifiedCSID, 0}, {ies.FullyQualifiedCSID, 1},
+		{ies.LocalDistinguishedName, 0}, {ies.LocalDistinguishedName, 1}, {ies.EPCTimer, 0},
+		{ies.AdditionalProtocolConfigurationOptions, 0}, {ies.IPv4ConfigurationParameters, 0},
+		{ies.Indication, 0}, {ies.PresenceReportingAreaAction, 0},
+		{ies.LoadControlInformation, 0}, {ies.LoadControlInformation, 1},
+		{ies.LoadControlInformation, 2}, {ies.OverloadControlInformation, 0},
+		{ies.OverloadControlInformation, 1}, {ies.FContainer, 0}, {ies.ChargingID, 0},
+		{ies.ExtendedProtocolConfigurationOptions, 0},
+	},
+	MsgTypeModifyBearerRequest: {
+		{ies.MobileEquipmentIdentity, 0}, {ies.UserLocationInformation, 0},
+		{ies.ServingNetwork, 0}, {ies.RATType, 0}, {ies.Indication, 0},
+		{ies.FullyQualifiedTEID, 0}, {ies.AggregateMaximumBitRate, 0}, {ies.DelayValue, 0},
+		{ies.BearerContext, 0}, {ies.BearerContext, 1}, {ies.Recovery, 0}, {ies.UETimeZone, 0},
+		{ies.FullyQualifiedCSID, 0}, {ies.FullyQualifiedCSID, 1}, {ies.UserCSGInformation, 0},
+		{ies.IPAddress, 0}, {ies.PortNumber, 0}, {ies.LocalDistinguishedName, 0},
+		{ies.LocalDistinguishedName, 1}, {ies.IPAddress, 1}, {ies.PortNumber, 1},
+		{ies.PortNumber, 2}, {ies.CNOperatorSelectionEntity, 0},
+		{ies.PresenceReportingAreaInformation, 0}, {ies.OverloadControlInformation, 0},
+		{ies.OverloadControlInformation, 1}, {ies.OverloadControlInformation, 2},
+		{ies.ServingPLMNRateControl, 0}, {ies.Counter, 0}, {ies.IMSI, 0},
+		{ies.UserLocationInformation, 1}, {ies.TWANIdentifier, 0},
+		{ies.TWANIdentifierTimestamp, 0}, {ies.SecondaryRATUsageDataReport, 0},
+	},
+	MsgTypeModifyBearerResponse: {
+		{ies.Cause, 0}, {ies.MSISDN, 0}, {ies.EPSBearerID, 0}, {ies.APNRestriction, 0},
+		{ies.ProtocolConfigurationOptions, 0}, {ies.BearerContext, 0}, {ies.BearerContext, 1},
+		{ies.ChangeReportingAction, 0}, {ies.CSGInformationReportingAction, 0},
+		{ies.HeNBInformationReporting, 0}, {ies.FullyQualifiedDomainName, 0},
+		{ies.IPAddress, 0}, {ies.FullyQualifiedCSID, 0}, {ies.FullyQualifiedCSID, 1},
+		{ies.Recovery, 0}, {ies.LocalDistinguishedName, 0}, {ies.LocalDistinguishedName, 1},
+		{ies.Indication, 0}, {ies.PresenceReportingAreaAction, 0},
+		{ies.LoadControlInformation, 0}, {ies.LoadControlInformation, 1},
+		{ies.OverloadControlInformation, 0}, {ies.OverloadControlInformation, 1},
+		{ies.OverloadControlInformation, 2}, {ies.ChargingID, 0},
+	},
+	MsgTypeDeleteSessionRequest: {
+		{ies.Cause, 0}, {ies.EPSBearerID, 0}, {ies.UserLocationInformation, 0},
+		{ies.Indication, 0}, {ies.ProtocolConfigurationOptions, 0}, {ies.NodeType, 0},
+		{ies.FullyQualifiedTEID, 0}, {ies.UETimeZone, 0}, {ies.ULITimestamp, 0},
+		{ies.RANNASCause, 0}, {ies.TWANIdentifier, 0}, {ies.TWANIdentifierTimestamp, 0},
+		{ies.OverloadControlInformation, 0}, {ies.OverloadControlInformation, 1},
+		{ies.OverloadControlInformation, 2}, {ies.TWANIdentifier, 1},
+		{ies.TWANIdentifierTimestamp, 1}, {ies.IPAddress, 0}, {ies.PortNumber, 0},
+		{ies.ExtendedProtocolConfigurationOptions, 0}, {ies.PortNumber, 1},
+		{ies.SecondaryRATUsageDataReport, 0},
+	},
+	MsgTypeDeleteSessionResponse: {
+		{ies.Cause, 0}, {ies.Recovery, 0}, {ies.ProtocolConfigurationOptions, 0},
+		{ies.Indication, 0}, {ies.LoadControlInformation, 1}, {ies.LoadControlInformation, 2},
+		{ies.LoadControlInformation, 3}, {ies.OverloadControlInformation, 1},
+		{ies.OverloadControlInformation, 2}, {ies.ExtendedProtocolConfigurationOptions, 0},
+		{ies.APNRateControlStatus, 0},
+	},
+	MsgTypeChangeNotificationRequest: {
+		{ies.IMSI, 0}, {ies.MobileEquipmentIdentity, 0}, {ies.Indication, 0}, {ies.RATType, 0},
+		{ies.UserLocationInformation, 0}, {ies.UserCSGInformation, 0}, {ies.IPAddress, 0},
+		{ies.EPSBearerID, 0}, {ies.PresenceReportingAreaInformation, 0}, {ies.Counter, 0},
+		{ies.SecondaryRATUsageDataReport, 0},
+	},
+	MsgTypeChangeNotificationResponse: {
+		{ies.IMSI, 0}, {ies.MobileEquipmentIdentity, 0}, {ies.Cause, 0},
+		{ies.ChangeReportingAction, 0}, {ies.CSGInformationReportingAction, 0},
+		{ies.PresenceReportingAreaAction, 0},
+	},
+	MsgTypeModifyBearerCommand: {
+		{ies.AggregateMaximumBitRate, 0}, {ies.BearerContext, 0},
+		{ies.OverloadControlInformation, 0}, {ies.OverloadControlInformation, 1},
+		{ies.OverloadControlInformation, 2}, {ies.FullyQualifiedTEID, 0},
+	},
+	MsgTypeModifyBearerFailureIndication: {
+		{ies.Cause, 0}, {ies.Recovery, 0}, {ies.Indication, 0},
+		{ies.OverloadControlInformation, 0}, {ies.OverloadControlInformation, 1},
+	},
+	MsgTypeDeleteBearerCommand: {
+		{ies.BearerContext, 0}, {ies.UserLocationInformation, 0}, {ies.ULITimestamp, 0},
+		{ies.UETimeZone, 0}, {ies.OverloadControlInformation, 0},
+		{ies.OverloadControlInformation, 1}, {ies.OverloadControlInformation, 2},
+		{ies.FullyQualifiedTEID, 0}, {ies.SecondaryRATUsageDataReport, 0},
+	},
+	MsgTypeDeleteBearerFailureIndication: {
+		{ies.Cause, 0}, {ies.BearerContext, 0}, {ies.Recovery, 0}, {ies.Indication, 0},
+		{ies.OverloadControlInformation, 0}, {ies.OverloadControlInformation, 1},
+	},
+	MsgTypeBearerResourceCommand: {
+		{ies.EPSBearerID, 0}, {ies.EPSBearerID, 1}, {ies.ProcedureTransactionID, 0},
+		{ies.FlowQoS, 0}, {ies.TrafficAggregateDescription, 0}, {ies.RATType, 0},
+		{ies.ServingNetwork, 0}, {ies.UserLocationInformation, 0}, {ies.Indication, 0},
+		{ies.FullyQualifiedTEID, 0}, {ies.FullyQualifiedTEID, 1}, {ies.FullyQualifiedTEID, 2},
+		{ies.ProtocolConfigurationOptions, 0}, {ies.SignallingPriorityIndication, 0},
+		{ies.OverloadControlInformation, 0}, {ies.OverloadControlInformation, 1},
+		{ies.FContainer, 0}, {ies.ExtendedProtocolConfigurationOptions, 0},
+	},
+	MsgTypeBearerResourceFailureIndication: {
+		{ies.Cause, 0}, {ies.EPSBearerID, 0}, {ies.ProcedureTransactionID, 0},
+		{ies.Indication, 0}, {ies.OverloadControlInformation, 0},
+		{ies.OverloadControlInformation, 1}, {ies.Recovery, 0}, {ies.FContainer, 0},
+	},
+	MsgTypeDownlinkDataNotificationFailureIndication: {
+		{ies.Cause, 0}, {ies.NodeType, 0}, {ies.IMSI, 0},
+	},
+	MsgTypeTraceSessionActivation: {
+		{ies.IMSI, 0}, {ies.TraceInformation, 0}, {ies.MobileEquipmentIdentity, 0},
+	},
+	MsgTypeTraceSessionDeactivation: {
+		{ies.TraceReference, 0},
+	},
+	MsgTypeStopPagingIndication: {
+		{ies.IMSI, 0},
+	},
+	MsgTypeCreateBearerRequest: {
+		{ies.ProcedureTransactionID, 0}, {ies.EPSBearerID, 0},
+		{ies.ProtocolConfigurationOptions, 0}, {ies.BearerContext, 0},
+		{ies.FullyQualifiedCSID, 0}, {ies.FullyQualifiedCSID, 1},
+		{ies.ChangeReportingAction, 0}, {ies.CSGInformationReportingAction, 0},
+		{ies.HeNBInformationReporting, 0}, {ies.PresenceReportingAreaAction, 0},
+		{ies.Indication, 0}, {ies.LoadControlInformation, 0}, {ies.LoadControlInformation, 1},
+		{ies.LoadControlInformation, 2}, {ies.OverloadControlInformation, 0},
+		{ies.OverloadControlInformation, 1}, {ies.FContainer, 0},
+	},
+	MsgTypeCreateBearerResponse: {
+		{ies.Cause, 0}, {ies.BearerContext, 0}, {ies.Recovery, 0}, {ies.FullyQualifiedCSID, 0},
+		{ies.FullyQualifiedCSID, 1}, {ies.FullyQualifiedCSID, 2}, {ies.FullyQualifiedCSID, 3},
+		{ies.ProtocolConfigurationOptions, 0}, {ies.UETimeZone, 0},
+		{ies.UserLocationInformation, 0}, {ies.TWANIdentifier, 0},
+		{ies.TWANIdentifierTimestamp, 0}, {ies.OverloadControlInformation, 0},
+		{ies.OverloadControlInformation, 1}, {ies.PresenceReportingAreaAction, 0},
+		{ies.IPAddress, 0}, {ies.OverloadControlInformation, 2}, {ies.TWANIdentifier, 1},
+		{ies.TWANIdentifierTimestamp, 1}, {ies.IPAddress, 1}, {ies.PortNumber, 0},
+		{ies.FContainer, 0}, {ies.PortNumber, 1},
+	},
+	MsgTypeUpdateBearerRequest: {
+		{ies.BearerContext, 0}, {ies.ProcedureTransactionID, 0},
+		{ies.ProtocolConfigurationOptions, 0}, {ies.AggregateMaximumBitRate, 0},
+		{ies.ChangeReportingAction, 0}, {ies.CSGInformationReportingAction, 0},
+		{ies.HeNBInformationReporting, 0}, {ies.Indication, 0}, {ies.FullyQualifiedCSID, 0},
+		{ies.FullyQualifiedCSID, 1}, {ies.PresenceReportingAreaAction, 0},
+		{ies.LoadControlInformation, 0}, {ies.LoadControlInformation, 1},
+		{ies.LoadControlInformation, 2}, {ies.OverloadControlInformation, 0},
+		{ies.OverloadControlInformation, 1}, {ies.FContainer, 0},
+	},
+	MsgTypeUpdateBearerResponse: {
+		{ies.Cause, 0}, {ies.BearerContext, 0}, {ies.ProtocolConfigurationOptions, 0},
+		{ies.Recovery, 0}, {ies.FullyQualifiedCSID, 0}, {ies.FullyQualifiedCSID, 1},
+		{ies.FullyQualifiedCSID, 2}, {ies.FullyQualifiedCSID, 3}, {ies.Indication, 0},
+		{ies.UETimeZone, 0}, {ies.UserLocationInformation, 0}, {ies.TWANIdentifier, 0},
+		{ies.TWANIdentifier, 1}, {ies.OverloadControlInformation, 0},
+		{ies.OverloadControlInformation, 1}, {ies.OverloadControlInformation, 2},
+		{ies.PresenceReportingAreaInformation, 0}, {ies.IPAddress, 0}, {ies.IPAddress, 1},
+		{ies.TWANIdentifierTimestamp, 0}, {ies.TWANIdentifierTimestamp, 1},
+		{ies.PortNumber, 0}, {ies.PortNumber, 1}, {ies.FContainer, 0},
+	},
+	MsgTypeDeleteBearerRequest: {
+		{ies.EPSBearerID, 0}, {ies.EPSBearerID, 1}, {ies.BearerContext, 0},
+		{ies.ProcedureTransactionID, 0}, {ies.ProtocolConfigurationOptions, 0},
+		{ies.FullyQualifiedCSID, 0}, {ies.FullyQualifiedCSID, 1}, {ies.Cause, 0},
+		{ies.Indication, 0}, {ies.LoadControlInformation, 0}, {ies.LoadControlInformation, 1},
+		{ies.LoadControlInformation, 2}, {ies.OverloadControlInformation, 0},
+		{ies.OverloadControlInformation, 1}, {ies.FContainer, 0},
+		{ies.APNRateControlStatus, 0}, {ies.ExtendedProtocolConfigurationOptions, 0},
+	},
+	MsgTypeDeleteBearerResponse: {
+		{ies.Cause, 0}, {ies.EPSBearerID, 0}, {ies.BearerContext, 0}, {ies.Recovery, 0},
+		{ies.FullyQualifiedCSID, 0}, {ies.FullyQualifiedCSID, 1}, {ies.FullyQualifiedCSID, 2},
+		{ies.FullyQualifiedCSID, 3}, {ies.ProtocolConfigurationOptions, 0},
+		{ies.UETimeZone, 0}, {ies.UserLocationInformation, 0}, {ies.ULITimestamp, 0},
+		{ies.TWANIdentifier, 0}, {ies.TWANIdentifierTimestamp, 0},
+		{ies.OverloadControlInformation, 0}, {ies.OverloadControlInformation, 1},
+		{ies.IPAddress, 0}, {ies.OverloadControlInformation, 2}, {ies.TWANIdentifier, 1},
+		{ies.TWANIdentifierTimestamp, 1}, {ies.IPAddress, 1}, {ies.PortNumber, 0},
+		{ies.FContainer, 0}, {ies.PortNumber, 1}, {ies.SecondaryRATUsageDataReport, 0},
+	},
+	MsgTypeDeletePDNConnectionSetRequest: {
+		{ies.FullyQualifiedCSID, 0}, {ies.FullyQualifiedCSID, 1}, {ies.FullyQualifiedCSID, 2},
+		{ies.FullyQualifiedCSID, 3}, {ies.FullyQualifiedCSID, 4},
+	},
+	MsgTypeDeletePDNConnectionSetResponse: {
+		{ies.Cause, 0}, {ies.Recovery, 0},
+	},
+	MsgTypeIdentificationRequest: {
+		{ies.GUTI, 0}, {ies.UserLocationInformation, 0}, {ies.PacketTMSI, 0},
+		{ies.PTMSISignature, 0}, {ies.CompleteRequestMessage, 0}, {ies.IPAddress, 0},
+		{ies.PortNumber, 0}, {ies.HopCounter, 0}, {ies.ServingNetwork, 0},
+	},
+	MsgTypeIdentificationResponse: {
+		{ies.Cause, 0}, {ies.IMSI, 0},
+		{ies.MMContextEPSSecurityContextQuadrupletsAndQuintuplets, 0},
+		{ies.MMContextGSMKeyAndTriplets, 0}, {ies.MMContextGSMKeyUsedCipherAndQuintuplets, 0},
+		{ies.MMContextUMTSKeyAndQuintuplets, 0},
+		{ies.MMContextUMTSKeyQuadrupletsAndQuintuplets, 0},
+		{ies.MMContextUMTSKeyUsedCipherAndQuintuplets, 0}, {ies.TraceInformation, 0},
+		{ies.IntegerNumber, 0}, {ies.MonitoringEventInformation, 0},
+		{ies.ExtendedTraceInformation, 0},
+	},
+	MsgTypeContextRequest: {
+		{ies.IMSI, 0}, {ies.GUTI, 0}, {ies.UserLocationInformation, 0}, {ies.PacketTMSI, 0},
+		{ies.PTMSISignature, 0}, {ies.CompleteRequestMessage, 0}, {ies.FullyQualifiedTEID, 0},
+		{ies.PortNumber, 0}, {ies.RATType, 0}, {ies.Indication, 0}, {ies.HopCounter, 0},
+		{ies.ServingNetwork, 0}, {ies.LocalDistinguishedName, 0},
+		{ies.FullyQualifiedDomainName, 0}, {ies.FullyQualifiedDomainName, 1},
+		{ies.NodeNumber, 0}, {ies.NodeIdentifier, 0}, {ies.NodeIdentifier, 1},
+		{ies.CIoTOptimizationsSupportIndication, 0},
+	},
+	MsgTypeContextResponse: {
+		{ies.Cause, 0}, {ies.IMSI, 0},
+		{ies.MMContextEPSSecurityContextQuadrupletsAndQuintuplets, 0},
+		{ies.MMContextGSMKeyAndTriplets, 0}, {ies.MMContextGSMKeyUsedCipherAndQuintuplets, 0},
+		{ies.MMContextUMTSKeyAndQuintuplets, 0},
+		{ies.MMContextUMTSKeyQuadrupletsAndQuintuplets, 0},
+		{ies.MMContextUMTSKeyUsedCipherAndQuintuplets, 0}, {ies.PDNConnection, 0},
+		{ies.FullyQualifiedTEID, 0}, {ies.FullyQualifiedTEID, 1},
+		{ies.FullyQualifiedDomainName, 0}, {ies.Indication, 0}, {ies.TraceInformation, 0},
+		{ies.IPAddress, 0}, {ies.IPAddress, 1}, {ies.RFSPIndex, 0}, {ies.RFSPIndex, 1},
+		{ies.UETimeZone, 0}, {ies.LocalDistinguishedName, 0}, {ies.MDTConfiguration, 0},
+		{ies.FullyQualifiedDomainName, 1}, {ies.FullyQualifiedDomainName, 2},
+		{ies.UserCSGInformation, 0}, {ies.MonitoringEventInformation, 0},
+		{ies.IntegerNumber, 0}, {ies.SCEFPDNConnection, 0}, {ies.RATType, 0},
+		{ies.ServingPLMNRateControl, 0}, {ies.Counter, 0}, {ies.IntegerNumber, 1},
+		{ies.ExtendedTraceInformation, 0},
+	},
+	MsgTypeContextAcknowledge: {
+		{ies.Cause, 0}, {ies.Indication, 0}, {ies.FullyQualifiedTEID, 0},
+		{ies.BearerContext, 0}, {ies.NodeNumber, 0}, {ies.NodeNumber, 1},
+		{ies.NodeIdentifier, 0}, {ies.NodeIdentifier, 1},
+	},
+	MsgTypeForwardRelocationRequest: {
+		{ies.IMSI, 0}, {ies.FullyQualifiedTEID, 0}, {ies.PDNConnection, 0},
+		{ies.FullyQualifiedTEID, 1}, {ies.FullyQualifiedDomainName, 0},
+		{ies.MMContextEPSSecurityContextQuadrupletsAndQuintuplets, 0},
+		{ies.MMContextGSMKeyAndTriplets, 0}, {ies.MMContextGSMKeyUsedCipherAndQuintuplets, 0},
+		{ies.MMContextUMTSKeyAndQuintuplets, 0},
+		{ies.MMContextUMTSKeyQuadrupletsAndQuintuplets, 0},
+		{ies.MMContextUMTSKeyUsedCipherAndQuintuplets, 0}, {ies.Indication, 0},
+		{ies.FContainer, 0}, {ies.FContainer, 1}, {ies.FContainer, 2},
+		{ies.TargetIdentification, 0}, {ies.IPAddress, 0}, {ies.IPAddress, 1}, {ies.FCause, 0},
+		{ies.FCause, 1}, {ies.FCause, 2}, {ies.SourceIdentification, 0}, {ies.PLMNID, 0},
+		{ies.Recovery, 0}, {ies.TraceInformation, 0}, {ies.RFSPIndex, 0}, {ies.RFSPIndex, 1},
+		{ies.CSGID, 0}, {ies.CSGMembershipIndication, 0}, {ies.UETimeZone, 0},
+		{ies.ServingNetwork, 0}, {ies.LocalDistinguishedName, 0},
+		{ies.AdditionalMMContextForSRVCC, 0}, {ies.AdditionalFlagsForSRVCC, 0}, {ies.STNSR, 0},
+		{ies.MSISDN, 0}, {ies.MDTConfiguration, 0}, {ies.FullyQualifiedDomainName, 1},
+		{ies.FullyQualifiedDomainName, 2}, {ies.UserCSGInformation, 0},
+		{ies.MonitoringEventInformation, 0}, {ies.IntegerNumber, 0},
+		{ies.SCEFPDNConnection, 0}, {ies.PortNumber, 0}, {ies.ServingPLMNRateControl, 0},
+		{ies.ExtendedTraceInformation, 0}, {ies.IntegerNumber, 1},
+	},
+	MsgTypeForwardRelocationResponse: {
+		{ies.Cause, 0}, {ies.FullyQualifiedTEID, 0}, {ies.Indication, 0},
+		{ies.BearerContext, 0}, {ies.BearerContext, 1}, {ies.BearerContext, 2},
+		{ies.FCause, 0}, {ies.FCause, 1}, {ies.FCause, 2}, {ies.FContainer, 0},
+		{ies.FContainer, 1}, {ies.FContainer, 2}, {ies.ChangeToReportFlags, 0},
+		{ies.LocalDistinguishedName, 0}, {ies.FullyQualifiedDomainName, 0},
+		{ies.FullyQualifiedDomainName, 1}, {ies.NodeNumber, 0}, {ies.NodeNumber, 1},
+		{ies.NodeIdentifier, 0}, {ies.NodeIdentifier, 1}, {ies.NodeIdentifier, 2},
+		{ies.NodeIdentifier, 3},
+	},
+	MsgTypeForwardRelocationCompleteNotification: {
+		{ies.Indication, 0},
+	},
+	MsgTypeForwardRelocationCompleteAcknowledge: {
+		{ies.Cause, 0}, {ies.Recovery, 0},
+	},
+	MsgTypeSuspendNotification: {
+		{ies.IMSI, 0}, {ies.UserLocationInformation, 0}, {ies.EPSBearerID, 0},
+		{ies.PacketTMSI, 0}, {ies.NodeType, 0}, {ies.IPAddress, 0}, {ies.PortNumber, 0},
+		{ies.HopCounter, 0}, {ies.FullyQualifiedTEID, 0},
+	},
+	MsgTypeSuspendAcknowledge: {
+		{ies.Cause, 0},
+	},
+	MsgTypeResumeNotification: {
+		{ies.IMSI, 0}, {ies.EPSBearerID, 0}, {ies.NodeType, 0}, {ies.FullyQualifiedTEID, 0},
+	},
+	MsgTypeResumeAcknowledge: {
+		{ies.Cause, 0},
+	},
+	MsgTypeCreateIndirectDataForwardingTunnelRequest: {
+		{ies.IMSI, 0}, {ies.MobileEquipmentIdentity, 0}, {ies.Indication, 0},
+		{ies.FullyQualifiedTEID, 0}, {ies.BearerContext, 0}, {ies.Recovery, 0},
+	},
+	MsgTypeCreateIndirectDataForwardingTunnelResponse: {
+		{ies.Cause, 0}, {ies.FullyQualifiedTEID, 0}, {ies.BearerContext, 0}, {ies.Recovery, 0},
+	},
+	MsgTypeDeleteIndirectDataForwardingTunnelResponse: {
+		{ies.Cause, 0}, {ies.Recovery, 0},
+	},
+	MsgTypeReleaseAccessBearersRequest: {
+		{ies.EPSBearerID, 0}, {ies.NodeType, 0}, {ies.Indication, 0},
+		{ies.SecondaryRATUsageDataReport, 0},
+	},
+	MsgTypeReleaseAccessBearersResponse: {
+		{ies.Cause, 0}, {ies.Indication, 0}, {ies.LoadControlInformation, 0},
+		{ies.OverloadControlInformation, 0},
+	},
+	MsgTypeDownlinkDataNotification: {
+		{ies.Cause, 0}, {ies.EPSBearerID, 0}, {ies.AllocationRetensionPriority, 0},
+		{ies.IMSI, 0}, {ies.FullyQualifiedTEID, 0}, {ies.Indication, 0},
+		{ies.LoadControlInformation, 0}, {ies.OverloadControlInformation, 0},
+		{ies.PagingAndServiceInformation, 0}, {ies.IntegerNumber, 0},
+	},
+	MsgTypeDownlinkDataNotificationAcknowledge: {
+		{ies.Cause, 0}, {ies.DelayValue, 0}, {ies.Recovery, 0}, {ies.Throttling, 0},
+		{ies.IMSI, 0}, {ies.EPCTimer, 0}, {ies.IntegerNumber, 0},
+	},
+	MsgTypePGWRestartNotification: {
+		{ies.IPAddress, 0}, {ies.IPAddress, 1}, {ies.Cause, 0},
+	},
+	MsgTypePGWRestartNotificationAcknowledge: {
+		{ies.Cause, 0},
+	},
+	MsgTypeModifyAccessBearersRequest: {
+		{ies.Indication, 0}, {ies.FullyQualifiedTEID, 0}, {ies.DelayValue, 0},
+		{ies.BearerContext, 0}, {ies.BearerContext, 1}, {ies.Recovery, 0},
+		{ies.SecondaryRATUsageDataReport, 0},
+	},
+	MsgTypeModifyAccessBearersResponse: {
+		{ies.Cause, 0}, {ies.BearerContext, 0}, {ies.BearerContext, 1}, {ies.Recovery, 0},
+		{ies.Indication, 0}, {ies.LoadControlInformation, 0},
+	},
+	MsgTypeMBMSSessionStartRequest: {
+		{ies.FullyQualifiedTEID, 0}, {ies.TMGI, 0}, {ies.MBMSSessionDuration, 0},
+		{ies.MBMSServiceArea, 0}, {ies.MBMSSessionIdentifier, 0}, {ies.MBMSFlowIdentifier, 0},
+		{ies.BearerQoS, 0}, {ies.MBMSIPMulticastDistribution, 0}, {ies.Recovery, 0},
+		{ies.MBMSTimeToDataTransfer, 0}, {ies.AbsoluteTimeofMBMSDataTransfer, 0},
+		{ies.MBMSFlags, 0}, {ies.MBMSIPMulticastDistribution, 1}, {ies.ECGIList, 0},
+	},
+	MsgTypeMBMSSessionStartResponse: {
+		{ies.Cause, 0}, {ies.FullyQualifiedTEID, 0}, {ies.MBMSDistributionAcknowledge, 0},
+		{ies.FullyQualifiedTEID, 1}, {ies.Recovery, 0},
+	},
+	MsgTypeMBMSSessionUpdateRequest: {
+		{ies.MBMSServiceArea, 0}, {ies.TMGI, 0}, {ies.FullyQualifiedTEID, 0},
+		{ies.MBMSSessionDuration, 0}, {ies.BearerQoS, 0}, {ies.MBMSSessionIdentifier, 0},
+		{ies.MBMSFlowIdentifier, 0}, {ies.MBMSTimeToDataTransfer, 0},
+		{ies.AbsoluteTimeofMBMSDataTransfer, 0}, {ies.ECGIList, 0},
+	},
+	MsgTypeMBMSSessionUpdateResponse: {
+		{ies.Cause, 0}, {ies.MBMSDistributionAcknowledge, 0}, {ies.FullyQualifiedTEID, 1},
+		{ies.Recovery, 0},
+	},
+	MsgTypeMBMSSessionStopRequest: {
+		{ies.MBMSFlowIdentifier, 0}, {ies.AbsoluteTimeofMBMSDataTransfer, 0},
+		{ies.MBMSFlags, 0},
+	},
+	MsgTypeMBMSSessionStopResponse: {
+		{ies.Cause, 0}, {ies.Recovery, 0},
+	},
+}
+
+// bearerContextIEOrder is the order of the IEs in Bearer Context. The IEs present
+// differ by message, but the order among them is common in the tables of TS 29.274.
+var bearerContextIEOrder = []ieKey{
+	{ies.EPSBearerID, 0}, {ies.Cause, 0}, {ies.BearerTFT, 0},
+	{ies.FullyQualifiedTEID, 0}, {ies.FullyQualifiedTEID, 1}, {ies.FullyQualifiedTEID, 2},
+	{ies.FullyQualifiedTEID, 3}, {ies.FullyQualifiedTEID, 4}, {ies.FullyQualifiedTEID, 5},
+	{ies.FullyQualifiedTEID, 6}, {ies.FullyQualifiedTEID, 7}, {ies.FullyQualifiedTEID, 8},
+	{ies.FullyQualifiedTEID, 9}, {ies.FullyQualifiedTEID, 10}, {ies.FullyQualifiedTEID, 11},
+	{ies.BearerQoS, 0}, {ies.ChargingID, 0}, {ies.BearerFlags, 0},
+	{ies.ProtocolConfigurationOptions, 0}, {ies.RANNASCause, 0},
+	{ies.ExtendedProtocolConfigurationOptions, 0}, {ies.MaximumPacketLossRate, 0},
+}
@@ -31,7 +31,11 @@ func NewStopPagingIndication(teid, seq uint32, ie ...*ies.IE) *StopPagingIndicat
 		case ies.IMSI:
 			s.IMSI = i
 		case ies.PrivateExtension:
-			s.PrivateExtension = i
+			if s.PrivateExtension == nil {
+				s.PrivateExtension = i
+			} else {
+				s.AdditionalIEs = append(s.AdditionalIEs, i)
+			}
 		default:
 			s.AdditionalIEs = append(s.AdditionalIEs, i)
 		}
@@ -117,7 +121,11 @@ func (s *StopPagingIndication) UnmarshalBinary(b []byte) error {
 		case ies.IMSI:
 			s.IMSI = i
 		case ies.PrivateExtension:
-			s.PrivateExtension = i
+			if s.PrivateExtension == nil {
+				s.PrivateExtension = i
+			} else {
+				s.AdditionalIEs = append(s.AdditionalIEs, i)
+			}
 		default:
 			s.AdditionalIEs = append(s.AdditionalIEs, i)
 		}
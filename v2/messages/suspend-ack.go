@@ -0,0 +1,169 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import "github.com/wmnsk/go-gtp/v2/ies"
+
+// SuspendAcknowledge is a SuspendAcknowledge Header and its IEs above.
+type SuspendAcknowledge struct {
+	*Header
+	Cause            *ies.IE
+	PrivateExtension *ies.IE
+	AdditionalIEs    []*ies.IE
+}
+
+// NewSuspendAcknowledge creates a new SuspendAcknowledge.
+func NewSuspendAcknowledge(teid, seq uint32, ie ...*ies.IE) *SuspendAcknowledge {
+	s := &SuspendAcknowledge{
+		Header: NewHeader(
+			NewHeaderFlags(2, 0, 1),
+			MsgTypeSuspendAcknowledge, teid, seq, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			s.Cause = i
+		case ies.PrivateExtension:
+			if s.PrivateExtension == nil {
+				s.PrivateExtension = i
+			} else {
+				s.AdditionalIEs = append(s.AdditionalIEs, i)
+			}
+		default:
+			s.AdditionalIEs = append(s.AdditionalIEs, i)
+		}
+	}
+
+	s.SetLength()
+	return s
+}
+
+// Marshal serializes SuspendAcknowledge into bytes.
+func (s *SuspendAcknowledge) Marshal() ([]byte, error) {
+	b := make([]byte, s.MarshalLen())
+	if err := s.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MarshalTo serializes SuspendAcknowledge into bytes.
+func (s *SuspendAcknowledge) MarshalTo(b []byte) error {
+	if s.Header.Payload != nil {
+		s.Header.Payload = nil
+	}
+	s.Header.Payload = make([]byte, s.MarshalLen()-s.Header.MarshalLen())
+
+	offset := 0
+	if ie := s.Cause; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.PrivateExtension; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	for _, ie := range s.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(s.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	s.Header.SetLength()
+	return s.Header.MarshalTo(b)
+}
+
+// ParseSuspendAcknowledge decodes given bytes as SuspendAcknowledge.
+func ParseSuspendAcknowledge(b []byte) (*SuspendAcknowledge, error) {
+	s := &SuspendAcknowledge{}
+	if err := s.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// UnmarshalBinary decodes given bytes as SuspendAcknowledge.
+func (s *SuspendAcknowledge) UnmarshalBinary(b []byte) error {
+	var err error
+	s.Header, err = ParseHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(s.Header.Payload) < 2 {
+		return nil
+	}
+
+	decodedIEs, err := ies.ParseMultiIEs(s.Header.Payload)
+	if err != nil {
+		return err
+	}
+	for _, i := range decodedIEs {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			s.Cause = i
+		case ies.PrivateExtension:
+			if s.PrivateExtension == nil {
+				s.PrivateExtension = i
+			} else {
+				s.AdditionalIEs = append(s.AdditionalIEs, i)
+			}
+		default:
+			s.AdditionalIEs = append(s.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length in int.
+func (s *SuspendAcknowledge) MarshalLen() int {
+	l := s.Header.MarshalLen() - len(s.Header.Payload)
+	if ie := s.Cause; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.PrivateExtension; ie != nil {
+		l += ie.MarshalLen()
+	}
+
+	for _, ie := range s.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (s *SuspendAcknowledge) SetLength() {
+	s.Header.Length = uint16(s.MarshalLen() - 4)
+}
+
+// MessageTypeName returns the name of protocol.
+func (s *SuspendAcknowledge) MessageTypeName() string {
+	return "Suspend Acknowledge"
+}
+
+// TEID returns the TEID in uint32.
+func (s *SuspendAcknowledge) TEID() uint32 {
+	return s.Header.teid()
+}
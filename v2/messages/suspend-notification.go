@@ -0,0 +1,281 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import "github.com/wmnsk/go-gtp/v2/ies"
+
+// SuspendNotification is a SuspendNotification Header and its IEs above.
+type SuspendNotification struct {
+	*Header
+	IMSI                   *ies.IE
+	RAI                    *ies.IE
+	LinkedEBI              *ies.IE
+	PTMSI                  *ies.IE
+	OriginatingNode        *ies.IE
+	AddressForControlPlane *ies.IE
+	UDPSourcePortNumber    *ies.IE
+	HopCounter             *ies.IE
+	SenderFTEIDC           *ies.IE
+	PrivateExtension       *ies.IE
+	AdditionalIEs          []*ies.IE
+}
+
+// NewSuspendNotification creates a new SuspendNotification.
+func NewSuspendNotification(teid, seq uint32, ie ...*ies.IE) *SuspendNotification {
+	s := &SuspendNotification{
+		Header: NewHeader(
+			NewHeaderFlags(2, 0, 1),
+			MsgTypeSuspendNotification, teid, seq, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.IMSI:
+			s.IMSI = i
+		case ies.UserLocationInformation:
+			s.RAI = i
+		case ies.EPSBearerID:
+			s.LinkedEBI = i
+		case ies.PacketTMSI:
+			s.PTMSI = i
+		case ies.NodeType:
+			s.OriginatingNode = i
+		case ies.IPAddress:
+			s.AddressForControlPlane = i
+		case ies.PortNumber:
+			s.UDPSourcePortNumber = i
+		case ies.HopCounter:
+			s.HopCounter = i
+		case ies.FullyQualifiedTEID:
+			s.SenderFTEIDC = i
+		case ies.PrivateExtension:
+			if s.PrivateExtension == nil {
+				s.PrivateExtension = i
+			} else {
+				s.AdditionalIEs = append(s.AdditionalIEs, i)
+			}
+		default:
+			s.AdditionalIEs = append(s.AdditionalIEs, i)
+		}
+	}
+
+	s.SetLength()
+	return s
+}
+
+// Marshal serializes SuspendNotification into bytes.
+func (s *SuspendNotification) Marshal() ([]byte, error) {
+	b := make([]byte, s.MarshalLen())
+	if err := s.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MarshalTo serializes SuspendNotification into bytes.
+func (s *SuspendNotification) MarshalTo(b []byte) error {
+	if s.Header.Payload != nil {
+		s.Header.Payload = nil
+	}
+	s.Header.Payload = make([]byte, s.MarshalLen()-s.Header.MarshalLen())
+
+	offset := 0
+	if ie := s.IMSI; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.RAI; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.LinkedEBI; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.PTMSI; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.OriginatingNode; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.AddressForControlPlane; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.UDPSourcePortNumber; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.HopCounter; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.SenderFTEIDC; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := s.PrivateExtension; ie != nil {
+		if err := ie.MarshalTo(s.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	for _, ie := range s.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(s.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	s.Header.SetLength()
+	return s.Header.MarshalTo(b)
+}
+
+// ParseSuspendNotification decodes given bytes as SuspendNotification.
+func ParseSuspendNotification(b []byte) (*SuspendNotification, error) {
+	s := &SuspendNotification{}
+	if err := s.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// UnmarshalBinary decodes given bytes as SuspendNotification.
+func (s *SuspendNotification) UnmarshalBinary(b []byte) error {
+	var err error
+	s.Header, err = ParseHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(s.Header.Payload) < 2 {
+		return nil
+	}
+
+	decodedIEs, err := ies.ParseMultiIEs(s.Header.Payload)
+	if err != nil {
+		return err
+	}
+	for _, i := range decodedIEs {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.IMSI:
+			s.IMSI = i
+		case ies.UserLocationInformation:
+			s.RAI = i
+		case ies.EPSBearerID:
+			s.LinkedEBI = i
+		case ies.PacketTMSI:
+			s.PTMSI = i
+		case ies.NodeType:
+			s.OriginatingNode = i
+		case ies.IPAddress:
+			s.AddressForControlPlane = i
+		case ies.PortNumber:
+			s.UDPSourcePortNumber = i
+		case ies.HopCounter:
+			s.HopCounter = i
+		case ies.FullyQualifiedTEID:
+			s.SenderFTEIDC = i
+		case ies.PrivateExtension:
+			if s.PrivateExtension == nil {
+				s.PrivateExtension = i
+			} else {
+				s.AdditionalIEs = append(s.AdditionalIEs, i)
+			}
+		default:
+			s.AdditionalIEs = append(s.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length in int.
+func (s *SuspendNotification) MarshalLen() int {
+	l := s.Header.MarshalLen() - len(s.Header.Payload)
+	if ie := s.IMSI; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.RAI; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.LinkedEBI; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.PTMSI; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.OriginatingNode; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.AddressForControlPlane; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.UDPSourcePortNumber; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.HopCounter; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.SenderFTEIDC; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := s.PrivateExtension; ie != nil {
+		l += ie.MarshalLen()
+	}
+
+	for _, ie := range s.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (s *SuspendNotification) SetLength() {
+	s.Header.Length = uint16(s.MarshalLen() - 4)
+}
+
+// MessageTypeName returns the name of protocol.
+func (s *SuspendNotification) MessageTypeName() string {
+	return "Suspend Notification"
+}
+
+// TEID returns the TEID in uint32.
+func (s *SuspendNotification) TEID() uint32 {
+	return s.Header.teid()
+}
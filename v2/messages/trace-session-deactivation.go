@@ -0,0 +1,147 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import "github.com/wmnsk/go-gtp/v2/ies"
+
+// TraceSessionDeactivation is a TraceSessionDeactivation Header and its IEs above.
+type TraceSessionDeactivation struct {
+	*Header
+	TraceReference *ies.IE
+	AdditionalIEs  []*ies.IE
+}
+
+// NewTraceSessionDeactivation creates a new TraceSessionDeactivation.
+func NewTraceSessionDeactivation(teid, seq uint32, ie ...*ies.IE) *TraceSessionDeactivation {
+	t := &TraceSessionDeactivation{
+		Header: NewHeader(
+			NewHeaderFlags(2, 0, 1),
+			MsgTypeTraceSessionDeactivation, teid, seq, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.TraceReference:
+			t.TraceReference = i
+		default:
+			t.AdditionalIEs = append(t.AdditionalIEs, i)
+		}
+	}
+
+	t.SetLength()
+	return t
+}
+
+// Marshal serializes TraceSessionDeactivation into bytes.
+func (t *TraceSessionDeactivation) Marshal() ([]byte, error) {
+	b := make([]byte, t.MarshalLen())
+	if err := t.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MarshalTo serializes TraceSessionDeactivation into bytes.
+func (t *TraceSessionDeactivation) MarshalTo(b []byte) error {
+	if t.Header.Payload != nil {
+		t.Header.Payload = nil
+	}
+	t.Header.Payload = make([]byte, t.MarshalLen()-t.Header.MarshalLen())
+
+	offset := 0
+	if ie := t.TraceReference; ie != nil {
+		if err := ie.MarshalTo(t.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	for _, ie := range t.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(t.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	t.Header.SetLength()
+	return t.Header.MarshalTo(b)
+}
+
+// ParseTraceSessionDeactivation decodes given bytes as TraceSessionDeactivation.
+func ParseTraceSessionDeactivation(b []byte) (*TraceSessionDeactivation, error) {
+	t := &TraceSessionDeactivation{}
+	if err := t.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// UnmarshalBinary decodes given bytes as TraceSessionDeactivation.
+func (t *TraceSessionDeactivation) UnmarshalBinary(b []byte) error {
+	var err error
+	t.Header, err = ParseHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(t.Header.Payload) < 2 {
+		return nil
+	}
+
+	decodedIEs, err := ies.ParseMultiIEs(t.Header.Payload)
+	if err != nil {
+		return err
+	}
+	for _, i := range decodedIEs {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.TraceReference:
+			t.TraceReference = i
+		default:
+			t.AdditionalIEs = append(t.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length in int.
+func (t *TraceSessionDeactivation) MarshalLen() int {
+	l := t.Header.MarshalLen() - len(t.Header.Payload)
+	if ie := t.TraceReference; ie != nil {
+		l += ie.MarshalLen()
+	}
+
+	for _, ie := range t.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (t *TraceSessionDeactivation) SetLength() {
+	t.Header.Length = uint16(t.MarshalLen() - 4)
+}
+
+// MessageTypeName returns the name of protocol.
+func (t *TraceSessionDeactivation) MessageTypeName() string {
+	return "Trace Session Deactivation"
+}
+
+// TEID returns the TEID in uint32.
+func (t *TraceSessionDeactivation) TEID() uint32 {
+	return t.Header.teid()
+}
@@ -0,0 +1,423 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import "github.com/wmnsk/go-gtp/v2/ies"
+
+// UpdateBearerRequest is a UpdateBearerRequest Header and its IEs above.
+type UpdateBearerRequest struct {
+	*Header
+	BearerContexts                *ies.IE
+	PTI                           *ies.IE
+	PCO                           *ies.IE
+	APNAMBR                       *ies.IE
+	ChangeReportingAction         *ies.IE
+	CSGInformationReportingAction *ies.IE
+	HeNBInformationReporting      *ies.IE
+	IndicationFlags               *ies.IE
+	PGWFQCSID                     *ies.IE
+	SGWFQCSID                     *ies.IE
+	PresenceReportingAreaAction   *ies.IE
+	PGWNodeLoadControlInformation *ies.IE
+	PGWAPNLoadControlInformation  *ies.IE
+	SGWNodeLoadControlInformation *ies.IE
+	PGWOverloadControlInformation *ies.IE
+	SGWOverloadControlInformation *ies.IE
+	NBIFOMContainer               *ies.IE
+	PrivateExtension              *ies.IE
+	AdditionalIEs                 []*ies.IE
+}
+
+// NewUpdateBearerRequest creates a new UpdateBearerRequest.
+func NewUpdateBearerRequest(teid, seq uint32, ie ...*ies.IE) *UpdateBearerRequest {
+	u := &UpdateBearerRequest{
+		Header: NewHeader(
+			NewHeaderFlags(2, 0, 1),
+			MsgTypeUpdateBearerRequest, teid, seq, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.BearerContext:
+			u.BearerContexts = i
+		case ies.ProcedureTransactionID:
+			u.PTI = i
+		case ies.ProtocolConfigurationOptions:
+			u.PCO = i
+		case ies.AggregateMaximumBitRate:
+			u.APNAMBR = i
+		case ies.ChangeReportingAction:
+			u.ChangeReportingAction = i
+		case ies.CSGInformationReportingAction:
+			u.CSGInformationReportingAction = i
+		case ies.HeNBInformationReporting:
+			u.HeNBInformationReporting = i
+		case ies.Indication:
+			u.IndicationFlags = i
+		case ies.FullyQualifiedCSID:
+			switch i.Instance() {
+			case 0:
+				u.PGWFQCSID = i
+			case 1:
+				u.SGWFQCSID = i
+			default:
+				u.AdditionalIEs = append(u.AdditionalIEs, i)
+			}
+		case ies.PresenceReportingAreaAction:
+			u.PresenceReportingAreaAction = i
+		case ies.LoadControlInformation:
+			switch i.Instance() {
+			case 0:
+				u.PGWNodeLoadControlInformation = i
+			case 1:
+				u.PGWAPNLoadControlInformation = i
+			case 2:
+				u.SGWNodeLoadControlInformation = i
+			default:
+				u.AdditionalIEs = append(u.AdditionalIEs, i)
+			}
+		case ies.OverloadControlInformation:
+			switch i.Instance() {
+			case 0:
+				u.PGWOverloadControlInformation = i
+			case 1:
+				u.SGWOverloadControlInformation = i
+			default:
+				u.AdditionalIEs = append(u.AdditionalIEs, i)
+			}
+		case ies.FContainer:
+			u.NBIFOMContainer = i
+		case ies.PrivateExtension:
+			if u.PrivateExtension == nil {
+				u.PrivateExtension = i
+			} else {
+				u.AdditionalIEs = append(u.AdditionalIEs, i)
+			}
+		default:
+			u.AdditionalIEs = append(u.AdditionalIEs, i)
+		}
+	}
+
+	u.SetLength()
+	return u
+}
+
+// Marshal serializes UpdateBearerRequest into bytes.
+func (u *UpdateBearerRequest) Marshal() ([]byte, error) {
+	b := make([]byte, u.MarshalLen())
+	if err := u.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MarshalTo serializes UpdateBearerRequest into bytes.
+func (u *UpdateBearerRequest) MarshalTo(b []byte) error {
+	if u.Header.Payload != nil {
+		u.Header.Payload = nil
+	}
+	u.Header.Payload = make([]byte, u.MarshalLen()-u.Header.MarshalLen())
+
+	offset := 0
+	if ie := u.BearerContexts; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.PTI; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.PCO; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.APNAMBR; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.ChangeReportingAction; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.CSGInformationReportingAction; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.HeNBInformationReporting; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.IndicationFlags; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.PGWFQCSID; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.SGWFQCSID; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.PresenceReportingAreaAction; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.PGWNodeLoadControlInformation; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.PGWAPNLoadControlInformation; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.SGWNodeLoadControlInformation; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.PGWOverloadControlInformation; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.SGWOverloadControlInformation; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.NBIFOMContainer; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.PrivateExtension; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	for _, ie := range u.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(u.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	u.Header.SetLength()
+	return u.Header.MarshalTo(b)
+}
+
+// ParseUpdateBearerRequest decodes given bytes as UpdateBearerRequest.
+func ParseUpdateBearerRequest(b []byte) (*UpdateBearerRequest, error) {
+	u := &UpdateBearerRequest{}
+	if err := u.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// UnmarshalBinary decodes given bytes as UpdateBearerRequest.
+func (u *UpdateBearerRequest) UnmarshalBinary(b []byte) error {
+	var err error
+	u.Header, err = ParseHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(u.Header.Payload) < 2 {
+		return nil
+	}
+
+	decodedIEs, err := ies.ParseMultiIEs(u.Header.Payload)
+	if err != nil {
+		return err
+	}
+	for _, i := range decodedIEs {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.BearerContext:
+			u.BearerContexts = i
+		case ies.ProcedureTransactionID:
+			u.PTI = i
+		case ies.ProtocolConfigurationOptions:
+			u.PCO = i
+		case ies.AggregateMaximumBitRate:
+			u.APNAMBR = i
+		case ies.ChangeReportingAction:
+			u.ChangeReportingAction = i
+		case ies.CSGInformationReportingAction:
+			u.CSGInformationReportingAction = i
+		case ies.HeNBInformationReporting:
+			u.HeNBInformationReporting = i
+		case ies.Indication:
+			u.IndicationFlags = i
+		case ies.FullyQualifiedCSID:
+			switch i.Instance() {
+			case 0:
+				u.PGWFQCSID = i
+			case 1:
+				u.SGWFQCSID = i
+			default:
+				u.AdditionalIEs = append(u.AdditionalIEs, i)
+			}
+		case ies.PresenceReportingAreaAction:
+			u.PresenceReportingAreaAction = i
+		case ies.LoadControlInformation:
+			switch i.Instance() {
+			case 0:
+				u.PGWNodeLoadControlInformation = i
+			case 1:
+				u.PGWAPNLoadControlInformation = i
+			case 2:
+				u.SGWNodeLoadControlInformation = i
+			default:
+				u.AdditionalIEs = append(u.AdditionalIEs, i)
+			}
+		case ies.OverloadControlInformation:
+			switch i.Instance() {
+			case 0:
+				u.PGWOverloadControlInformation = i
+			case 1:
+				u.SGWOverloadControlInformation = i
+			default:
+				u.AdditionalIEs = append(u.AdditionalIEs, i)
+			}
+		case ies.FContainer:
+			u.NBIFOMContainer = i
+		case ies.PrivateExtension:
+			if u.PrivateExtension == nil {
+				u.PrivateExtension = i
+			} else {
+				u.AdditionalIEs = append(u.AdditionalIEs, i)
+			}
+		default:
+			u.AdditionalIEs = append(u.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length in int.
+func (u *UpdateBearerRequest) MarshalLen() int {
+	l := u.Header.MarshalLen() - len(u.Header.Payload)
+	if ie := u.BearerContexts; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.PTI; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.PCO; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.APNAMBR; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.ChangeReportingAction; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.CSGInformationReportingAction; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.HeNBInformationReporting; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.IndicationFlags; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.PGWFQCSID; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.SGWFQCSID; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.PresenceReportingAreaAction; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.PGWNodeLoadControlInformation; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.PGWAPNLoadControlInformation; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.SGWNodeLoadControlInformation; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.PGWOverloadControlInformation; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.SGWOverloadControlInformation; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.NBIFOMContainer; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.PrivateExtension; ie != nil {
+		l += ie.MarshalLen()
+	}
+
+	for _, ie := range u.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (u *UpdateBearerRequest) SetLength() {
+	u.Header.Length = uint16(u.MarshalLen() - 4)
+}
+
+// MessageTypeName returns the name of protocol.
+func (u *UpdateBearerRequest) MessageTypeName() string {
+	return "Update Bearer Request"
+}
+
+// TEID returns the TEID in uint32.
+func (u *UpdateBearerRequest) TEID() uint32 {
+	return u.Header.teid()
+}
@@ -0,0 +1,551 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package messages
+
+import "github.com/wmnsk/go-gtp/v2/ies"
+
+// UpdateBearerResponse is a UpdateBearerResponse Header and its IEs above.
+type UpdateBearerResponse struct {
+	*Header
+	Cause                              *ies.IE
+	BearerContexts                     *ies.IE
+	PCO                                *ies.IE
+	Recovery                           *ies.IE
+	MMEFQCSID                          *ies.IE
+	SGWFQCSID                          *ies.IE
+	EPDGFQCSID                         *ies.IE
+	TWANFQCSID                         *ies.IE
+	IndicationFlags                    *ies.IE
+	UETimeZone                         *ies.IE
+	ULI                                *ies.IE
+	TWANIdentifier                     *ies.IE
+	WLANLocationInformation            *ies.IE
+	MMEOverloadControlInformation      *ies.IE
+	SGWOverloadControlInformation      *ies.IE
+	TWANePDGOverloadControlInformation *ies.IE
+	PresenceReportingAreaInformation   *ies.IE
+	MMESGSNIdentifier                  *ies.IE
+	UELocalIPAddress                   *ies.IE
+	TWANIdentifierTimestamp            *ies.IE
+	WLANLocationTimestamp              *ies.IE
+	UEUDPPort                          *ies.IE
+	UETCPPort                          *ies.IE
+	NBIFOMContainer                    *ies.IE
+	PrivateExtension                   *ies.IE
+	AdditionalIEs                      []*ies.IE
+}
+
+// NewUpdateBearerResponse creates a new UpdateBearerResponse.
+func NewUpdateBearerResponse(teid, seq uint32, ie ...*ies.IE) *UpdateBearerResponse {
+	u := &UpdateBearerResponse{
+		Header: NewHeader(
+			NewHeaderFlags(2, 0, 1),
+			MsgTypeUpdateBearerResponse, teid, seq, nil,
+		),
+	}
+
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			u.Cause = i
+		case ies.BearerContext:
+			u.BearerContexts = i
+		case ies.ProtocolConfigurationOptions:
+			u.PCO = i
+		case ies.Recovery:
+			u.Recovery = i
+		case ies.FullyQualifiedCSID:
+			switch i.Instance() {
+			case 0:
+				u.MMEFQCSID = i
+			case 1:
+				u.SGWFQCSID = i
+			case 2:
+				u.EPDGFQCSID = i
+			case 3:
+				u.TWANFQCSID = i
+			default:
+				u.AdditionalIEs = append(u.AdditionalIEs, i)
+			}
+		case ies.Indication:
+			u.IndicationFlags = i
+		case ies.UETimeZone:
+			u.UETimeZone = i
+		case ies.UserLocationInformation:
+			u.ULI = i
+		case ies.TWANIdentifier:
+			switch i.Instance() {
+			case 0:
+				u.TWANIdentifier = i
+			case 1:
+				u.WLANLocationInformation = i
+			default:
+				u.AdditionalIEs = append(u.AdditionalIEs, i)
+			}
+		case ies.OverloadControlInformation:
+			switch i.Instance() {
+			case 0:
+				u.MMEOverloadControlInformation = i
+			case 1:
+				u.SGWOverloadControlInformation = i
+			case 2:
+				u.TWANePDGOverloadControlInformation = i
+			default:
+				u.AdditionalIEs = append(u.AdditionalIEs, i)
+			}
+		case ies.PresenceReportingAreaInformation:
+			u.PresenceReportingAreaInformation = i
+		case ies.IPAddress:
+			switch i.Instance() {
+			case 0:
+				u.MMESGSNIdentifier = i
+			case 1:
+				u.UELocalIPAddress = i
+			default:
+				u.AdditionalIEs = append(u.AdditionalIEs, i)
+			}
+		case ies.TWANIdentifierTimestamp:
+			switch i.Instance() {
+			case 0:
+				u.TWANIdentifierTimestamp = i
+			case 1:
+				u.WLANLocationTimestamp = i
+			default:
+				u.AdditionalIEs = append(u.AdditionalIEs, i)
+			}
+		case ies.PortNumber:
+			switch i.Instance() {
+			case 0:
+				u.UEUDPPort = i
+			case 1:
+				u.UETCPPort = i
+			default:
+				u.AdditionalIEs = append(u.AdditionalIEs, i)
+			}
+		case ies.FContainer:
+			u.NBIFOMContainer = i
+		case ies.PrivateExtension:
+			if u.PrivateExtension == nil {
+				u.PrivateExtension = i
+			} else {
+				u.AdditionalIEs = append(u.AdditionalIEs, i)
+			}
+		default:
+			u.AdditionalIEs = append(u.AdditionalIEs, i)
+		}
+	}
+
+	u.SetLength()
+	return u
+}
+
+// Marshal serializes UpdateBearerResponse into bytes.
+func (u *UpdateBearerResponse) Marshal() ([]byte, error) {
+	b := make([]byte, u.MarshalLen())
+	if err := u.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MarshalTo serializes UpdateBearerResponse into bytes.
+func (u *UpdateBearerResponse) MarshalTo(b []byte) error {
+	if u.Header.Payload != nil {
+		u.Header.Payload = nil
+	}
+	u.Header.Payload = make([]byte, u.MarshalLen()-u.Header.MarshalLen())
+
+	offset := 0
+	if ie := u.Cause; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.BearerContexts; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.PCO; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.Recovery; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.MMEFQCSID; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.SGWFQCSID; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.EPDGFQCSID; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.TWANFQCSID; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.IndicationFlags; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.UETimeZone; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.ULI; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.TWANIdentifier; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.WLANLocationInformation; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.MMEOverloadControlInformation; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.SGWOverloadControlInformation; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.TWANePDGOverloadControlInformation; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.PresenceReportingAreaInformation; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.MMESGSNIdentifier; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.UELocalIPAddress; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.TWANIdentifierTimestamp; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.WLANLocationTimestamp; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.UEUDPPort; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.UETCPPort; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.NBIFOMContainer; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+	if ie := u.PrivateExtension; ie != nil {
+		if err := ie.MarshalTo(u.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	for _, ie := range u.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		if err := ie.MarshalTo(u.Header.Payload[offset:]); err != nil {
+			return err
+		}
+		offset += ie.MarshalLen()
+	}
+
+	u.Header.SetLength()
+	return u.Header.MarshalTo(b)
+}
+
+// ParseUpdateBearerResponse decodes given bytes as UpdateBearerResponse.
+func ParseUpdateBearerResponse(b []byte) (*UpdateBearerResponse, error) {
+	u := &UpdateBearerResponse{}
+	if err := u.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// UnmarshalBinary decodes given bytes as UpdateBearerResponse.
+func (u *UpdateBearerResponse) UnmarshalBinary(b []byte) error {
+	var err error
+	u.Header, err = ParseHeader(b)
+	if err != nil {
+		return err
+	}
+	if len(u.Header.Payload) < 2 {
+		return nil
+	}
+
+	decodedIEs, err := ies.ParseMultiIEs(u.Header.Payload)
+	if err != nil {
+		return err
+	}
+	for _, i := range decodedIEs {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.Cause:
+			u.Cause = i
+		case ies.BearerContext:
+			u.BearerContexts = i
+		case ies.ProtocolConfigurationOptions:
+			u.PCO = i
+		case ies.Recovery:
+			u.Recovery = i
+		case ies.FullyQualifiedCSID:
+			switch i.Instance() {
+			case 0:
+				u.MMEFQCSID = i
+			case 1:
+				u.SGWFQCSID = i
+			case 2:
+				u.EPDGFQCSID = i
+			case 3:
+				u.TWANFQCSID = i
+			default:
+				u.AdditionalIEs = append(u.AdditionalIEs, i)
+			}
+		case ies.Indication:
+			u.IndicationFlags = i
+		case ies.UETimeZone:
+			u.UETimeZone = i
+		case ies.UserLocationInformation:
+			u.ULI = i
+		case ies.TWANIdentifier:
+			switch i.Instance() {
+			case 0:
+				u.TWANIdentifier = i
+			case 1:
+				u.WLANLocationInformation = i
+			default:
+				u.AdditionalIEs = append(u.AdditionalIEs, i)
+			}
+		case ies.OverloadControlInformation:
+			switch i.Instance() {
+			case 0:
+				u.MMEOverloadControlInformation = i
+			case 1:
+				u.SGWOverloadControlInformation = i
+			case 2:
+				u.TWANePDGOverloadControlInformation = i
+			default:
+				u.AdditionalIEs = append(u.AdditionalIEs, i)
+			}
+		case ies.PresenceReportingAreaInformation:
+			u.PresenceReportingAreaInformation = i
+		case ies.IPAddress:
+			switch i.Instance() {
+			case 0:
+				u.MMESGSNIdentifier = i
+			case 1:
+				u.UELocalIPAddress = i
+			default:
+				u.AdditionalIEs = append(u.AdditionalIEs, i)
+			}
+		case ies.TWANIdentifierTimestamp:
+			switch i.Instance() {
+			case 0:
+				u.TWANIdentifierTimestamp = i
+			case 1:
+				u.WLANLocationTimestamp = i
+			default:
+				u.AdditionalIEs = append(u.AdditionalIEs, i)
+			}
+		case ies.PortNumber:
+			switch i.Instance() {
+			case 0:
+				u.UEUDPPort = i
+			case 1:
+				u.UETCPPort = i
+			default:
+				u.AdditionalIEs = append(u.AdditionalIEs, i)
+			}
+		case ies.FContainer:
+			u.NBIFOMContainer = i
+		case ies.PrivateExtension:
+			if u.PrivateExtension == nil {
+				u.PrivateExtension = i
+			} else {
+				u.AdditionalIEs = append(u.AdditionalIEs, i)
+			}
+		default:
+			u.AdditionalIEs = append(u.AdditionalIEs, i)
+		}
+	}
+
+	return nil
+}
+
+// MarshalLen returns the serial length in int.
+func (u *UpdateBearerResponse) MarshalLen() int {
+	l := u.Header.MarshalLen() - len(u.Header.Payload)
+	if ie := u.Cause; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.BearerContexts; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.PCO; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.Recovery; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.MMEFQCSID; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.SGWFQCSID; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.EPDGFQCSID; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.TWANFQCSID; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.IndicationFlags; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.UETimeZone; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.ULI; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.TWANIdentifier; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.WLANLocationInformation; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.MMEOverloadControlInformation; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.SGWOverloadControlInformation; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.TWANePDGOverloadControlInformation; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.PresenceReportingAreaInformation; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.MMESGSNIdentifier; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.UELocalIPAddress; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.TWANIdentifierTimestamp; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.WLANLocationTimestamp; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.UEUDPPort; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.UETCPPort; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.NBIFOMContainer; ie != nil {
+		l += ie.MarshalLen()
+	}
+	if ie := u.PrivateExtension; ie != nil {
+		l += ie.MarshalLen()
+	}
+
+	for _, ie := range u.AdditionalIEs {
+		if ie == nil {
+			continue
+		}
+		l += ie.MarshalLen()
+	}
+	return l
+}
+
+// SetLength sets the length in Length field.
+func (u *UpdateBearerResponse) SetLength() {
+	u.Header.Length = uint16(u.MarshalLen() - 4)
+}
+
+// MessageTypeName returns the name of protocol.
+func (u *UpdateBearerResponse) MessageTypeName() string {
+	return "Update Bearer Response"
+}
+
+// TEID returns the TEID in uint32.
+func (u *UpdateBearerResponse) TEID() uint32 {
+	return u.Header.teid()
+}
@@ -0,0 +1,256 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MetricsCollector receives the events that occur on Conn, so that they can be
+// exported to the monitoring systems such as Prometheus.
+//
+// The methods are called synchronously in the goroutines that send and receive
+// the messages, and thus they should return quickly.
+type MetricsCollector interface {
+	// MessageSent is called when a message is sent.
+	MessageSent(msgType uint8)
+	// MessageReceived is called when a message is received and parsed.
+	MessageReceived(msgType uint8)
+	// ParseError is called when the received bytes cannot be parsed.
+	ParseError()
+	// LimitViolation is called when the received message exceeds the limits set
+	// with (*Conn) SetParseLimits.
+	LimitViolation()
+	// Retransmission is called when a retransmitted request is received.
+	Retransmission(msgType uint8)
+	// EchoRTT is called when an Echo Response is received from peer.
+	EchoRTT(peer net.Addr, rtt time.Duration)
+}
+
+// Stats is a snapshot of the statistics of Conn.
+type Stats struct {
+	// MessagesSent is the number of messages sent, keyed by message type.
+	MessagesSent map[uint8]uint64
+	// MessagesReceived is the number of messages received, keyed by message type.
+	MessagesReceived map[uint8]uint64
+	// ParseErrors is the number of received datagrams that failed to be parsed.
+	ParseErrors uint64
+	// LimitViolations is the number of received datagrams that exceeded the limits.
+	LimitViolations uint64
+	// Retransmissions is the number of retransmitted requests received.
+	Retransmissions uint64
+	// Throttled is the number of requests that are not admitted by AdmissionControl.
+	Throttled uint64
+	// Dropped is the number of datagrams discarded because the queue of WorkerPool
+	// is full.
+	Dropped uint64
+	// SendDropped is the number of outgoing datagrams not sent because the
+	// SendQueue is full, and SendQueueDepth is the number of datagrams in it.
+	SendDropped    uint64
+	SendQueueDepth int
+	// SubscriptionDropped is the number of received messages not delivered to the
+	// channel returned by Subscribe because it is full.
+	SubscriptionDropped uint64
+	// RequestsRetransmitted is the number of requests retransmitted by SendRequestTo
+	// as they are not responded in time.
+	RequestsRetransmitted uint64
+	// Sessions is the number of active Sessions.
+	Sessions int
+	// Bearers is the number of Bearers in all the Sessions.
+	Bearers int
+	// EchoRTT is the last round-trip time of Echo, keyed by the address of peer.
+	EchoRTT map[string]time.Duration
+}
+
+type collectorHolder struct {
+	MetricsCollector
+}
+
+// connStats is the statistics of Conn. The counters are placed first to be
+// 64-bit aligned for atomic operations.
+type connStats struct {
+	sent            [256]uint64
+	received        [256]uint64
+	parseErrors     uint64
+	limitViolations uint64
+	retransmissions uint64
+	throttled       uint64
+	dropped         uint64
+	sendDropped     uint64
+	subDropped      uint64
+	reqRetransmits  uint64
+
+	collector atomic.Value
+
+	mu      sync.Mutex
+	echoRTT map[string]time.Duration
+}
+
+func newConnStats() *connStats {
+	return &connStats{echoRTT: map[string]time.Duration{}}
+}
+
+func (s *connStats) metricsCollector() MetricsCollector {
+	if h, ok := s.collector.Load().(collectorHolder); ok {
+		return h.MetricsCollector
+	}
+	return nil
+}
+
+func (s *connStats) messageSent(msgType uint8) {
+	if s == nil {
+		return
+	}
+	atomic.AddUint64(&s.sent[msgType], 1)
+	if mc := s.metricsCollector(); mc != nil {
+		mc.MessageSent(msgType)
+	}
+}
+
+func (s *connStats) messageReceived(msgType uint8) {
+	if s == nil {
+		return
+	}
+	atomic.AddUint64(&s.received[msgType], 1)
+	if mc := s.metricsCollector(); mc != nil {
+		mc.MessageReceived(msgType)
+	}
+}
+
+func (s *connStats) parseError() {
+	if s == nil {
+		return
+	}
+	atomic.AddUint64(&s.parseErrors, 1)
+	if mc := s.metricsCollector(); mc != nil {
+		mc.ParseError()
+	}
+}
+
+func (s *connStats) limitViolation() {
+	if s == nil {
+		return
+	}
+	atomic.AddUint64(&s.limitViolations, 1)
+	if mc := s.metricsCollector(); mc != nil {
+		mc.LimitViolation()
+	}
+}
+
+func (s *connStats) retransmission(msgType uint8) {
+	if s == nil {
+		return
+	}
+	atomic.AddUint64(&s.retransmissions, 1)
+	if mc := s.metricsCollector(); mc != nil {
+		mc.Retransmission(msgType)
+	}
+}
+
+func (s *connStats) throttle() {
+	if s == nil {
+		return
+	}
+	atomic.AddUint64(&s.throttled, 1)
+}
+
+func (s *connStats) drop() {
+	if s == nil {
+		return
+	}
+	atomic.AddUint64(&s.dropped, 1)
+}
+
+func (s *connStats) sendDrop() {
+	if s == nil {
+		return
+	}
+	atomic.AddUint64(&s.sendDropped, 1)
+}
+
+func (s *connStats) subscriptionDrop() {
+	if s == nil {
+		return
+	}
+	atomic.AddUint64(&s.subDropped, 1)
+}
+
+func (s *connStats) requestRetransmission() {
+	if s == nil {
+		return
+	}
+	atomic.AddUint64(&s.reqRetransmits, 1)
+}
+
+func (s *connStats) echoRoundTrip(peer net.Addr, rtt time.Duration) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.echoRTT[addrString(peer)] = rtt
+	s.mu.Unlock()
+
+	if mc := s.metricsCollector(); mc != nil {
+		mc.EchoRTT(peer, rtt)
+	}
+}
+
+// SetMetricsCollector registers the MetricsCollector to be notified of the events on
+// Conn. Giving nil unregisters it.
+//
+// The statistics are always counted regardless of MetricsCollector and can be
+// retrieved with Stats.
+func (c *Conn) SetMetricsCollector(mc MetricsCollector) {
+	if c.stats == nil {
+		return
+	}
+	c.stats.collector.Store(collectorHolder{mc})
+}
+
+// Stats returns the snapshot of the statistics of Conn.
+func (c *Conn) Stats() *Stats {
+	st := &Stats{
+		MessagesSent:     map[uint8]uint64{},
+		MessagesReceived: map[uint8]uint64{},
+		EchoRTT:          map[string]time.Duration{},
+		Sessions:         c.SessionCount(),
+		Bearers:          c.BearerCount(),
+	}
+	if sq := c.sender(); sq != nil {
+		st.SendQueueDepth = sq.depth()
+	}
+
+	s := c.stats
+	if s == nil {
+		return st
+	}
+
+	for i := range s.sent {
+		if n := atomic.LoadUint64(&s.sent[i]); n != 0 {
+			st.MessagesSent[uint8(i)] = n
+		}
+		if n := atomic.LoadUint64(&s.received[i]); n != 0 {
+			st.MessagesReceived[uint8(i)] = n
+		}
+	}
+	st.ParseErrors = atomic.LoadUint64(&s.parseErrors)
+	st.LimitViolations = atomic.LoadUint64(&s.limitViolations)
+	st.Retransmissions = atomic.LoadUint64(&s.retransmissions)
+	st.Throttled = atomic.LoadUint64(&s.throttled)
+	st.Dropped = atomic.LoadUint64(&s.dropped)
+	st.SendDropped = atomic.LoadUint64(&s.sendDropped)
+	st.SubscriptionDropped = atomic.LoadUint64(&s.subDropped)
+	st.RequestsRetransmitted = atomic.LoadUint64(&s.reqRetransmits)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for peer, rtt := range s.echoRTT {
+		st.EchoRTT[peer] = rtt
+	}
+	return st
+}
@@ -0,0 +1,347 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package mme provides the S11 interface of MME built on v2.Conn, which performs the
+// standard call flows with the S-GW: attach, detach, handover and the activation and
+// deactivation of the dedicated bearers requested by the network.
+//
+// The requests are sent once and the responses are waited for until the context is
+// done, so the context should have the deadline that covers the retransmissions
+// expected from the peer. The responses are correlated by the HandlerFuncs registered
+// by New, which should not be overwritten, and the RejectionHandler should not be
+// set on the Conn.
+package mme
+
+import (
+	"context"
+	"net"
+
+	"github.com/wmnsk/go-gtp/internal/exchange"
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// Policy is a set of functions to make the decisions in the call flows initiated by
+// the S-GW. Any of them can be nil.
+type Policy struct {
+	// AuthorizeBearer returns the Cause to the dedicated Bearer requested with the
+	// Bearer Context in Create Bearer Request. All of them are accepted if nil.
+	AuthorizeBearer func(sess *v2.Session, bc *ies.IE) uint8
+
+	// AccessFTEID returns the F-TEID of the access side, e.g., S1-U eNodeB, of the
+	// dedicated Bearer with ebi, which is set in Create Bearer Response. It is omitted
+	// if nil is returned.
+	AccessFTEID func(sess *v2.Session, ebi uint8) *ies.IE
+
+	// OnBearerDeleted is called when the Bearers are deleted by Delete Bearer Request.
+	// The ebis are the Linked EBI if the whole PDN connection is deleted, in which
+	// case the Session is removed from Conn.
+	OnBearerDeleted func(sess *v2.Session, ebis []uint8)
+}
+
+// MME is the S11 interface of MME.
+type MME struct {
+	conn    *v2.Conn
+	sgwAddr net.Addr
+	policy  Policy
+	calls   *exchange.Table
+}
+
+// New creates a new MME that performs the call flows with the S-GW at sgwAddr over
+// conn, and registers the HandlerFuncs for them to conn.
+func New(conn *v2.Conn, sgwAddr net.Addr, policy *Policy) *MME {
+	m := &MME{
+		conn:    conn,
+		sgwAddr: sgwAddr,
+		calls:   exchange.New(),
+	}
+	if policy != nil {
+		m.policy = *policy
+	}
+
+	m.calls.Handle(
+		conn,
+		messages.MsgTypeCreateSessionResponse,
+		messages.MsgTypeModifyBearerResponse,
+		messages.MsgTypeDeleteSessionResponse,
+	)
+	conn.HandleCreateBearerRequest(m.handleCreateBearerRequest)
+	conn.HandleDeleteBearerRequest(m.handleDeleteBearerRequest)
+	return m
+}
+
+// Conn returns the Conn that MME uses.
+func (m *MME) Conn() *v2.Conn {
+	return m.conn
+}
+
+// Attach sends Create Session Request with the IEs given, and waits for the response.
+//
+// The IEs should contain the Sender F-TEID of S11 MME and the Bearer Context of the
+// default Bearer, as well as the other ones required, such as IMSI. When the request
+// is accepted, the F-TEIDs and the PDN Address Allocation in the response are set to
+// the Session, which is activated. The Session is added to Conn before the request
+// is sent, and removed if it fails.
+//
+// It returns CauseError if the request is rejected.
+func (m *MME) Attach(ctx context.Context, ie ...*ies.IE) (*v2.Session, error) {
+	sess, seq, err := exchange.CreateSession(m.conn, m.sgwAddr, ie...)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.completeAttach(ctx, sess, seq); err != nil {
+		m.conn.RemoveSession(sess)
+		return nil, err
+	}
+	return sess, nil
+}
+
+func (m *MME) completeAttach(ctx context.Context, sess *v2.Session, seq uint32) error {
+	rsp, err := m.calls.Wait(ctx, m.sgwAddr, seq)
+	if err != nil {
+		return err
+	}
+	csRsp, ok := rsp.(*messages.CreateSessionResponse)
+	if !ok {
+		return &v2.UnexpectedTypeError{Msg: rsp}
+	}
+	if err := v2.CheckCause(csRsp); err != nil {
+		return err
+	}
+
+	br := sess.GetDefaultBearer()
+	if ie := csRsp.SenderFTEIDC; ie != nil {
+		if err := exchange.AddFTEID(sess, ie); err != nil {
+			return err
+		}
+	}
+	if ie := csRsp.PAA; ie != nil {
+		ip, err := ie.IPAddress()
+		if err != nil {
+			return err
+		}
+		br.SetSubscriberIP(ip)
+	}
+	for _, bc := range messages.FindIEs(csRsp, ies.BearerContext) {
+		for _, child := range bc.ChildIEs {
+			switch child.Type {
+			case ies.FullyQualifiedTEID:
+				if err := exchange.AddFTEID(sess, child); err != nil {
+					return err
+				}
+			case ies.ChargingID:
+				id, err := child.ChargingID()
+				if err != nil {
+					return err
+				}
+				br.SetChargingID(id)
+			}
+		}
+	}
+
+	return sess.Activate()
+}
+
+// ModifyBearer sends Modify Bearer Request with the F-TEID of the access side of the
+// Bearer with ebi, e.g., after the Initial Context Setup with eNodeB, and waits for
+// the response. The F-TEID is set to the Session and the Bearer when it is accepted.
+//
+// It returns CauseError if the request is rejected.
+func (m *MME) ModifyBearer(ctx context.Context, sess *v2.Session, ebi uint8, accessFTEID *ies.IE, ie ...*ies.IE) error {
+	teid, err := sess.GetTEID(v2.IFTypeS11S4SGWGTPC)
+	if err != nil {
+		return err
+	}
+
+	bc := ies.NewBearerContext(ies.NewEPSBearerID(ebi), accessFTEID)
+	req := messages.NewModifyBearerRequest(teid, 0, append([]*ies.IE{bc}, ie...)...)
+	rsp, err := m.calls.Call(ctx, m.conn, sess.PeerAddr(), req)
+	if err != nil {
+		return err
+	}
+	if err := v2.CheckCause(rsp); err != nil {
+		return err
+	}
+	return sess.ModifyAccessBearers(bc)
+}
+
+// Handover switches the downlink path of the Bearer with ebi to the one given by
+// newAccessFTEID with (*Session) SwitchDownlinkPath, and waits for the response.
+//
+// It returns CauseError if the request is rejected, in which case the Bearer is left
+// with the new F-TEID.
+func (m *MME) Handover(ctx context.Context, sess *v2.Session, ebi uint8, newAccessFTEID *ies.IE, ie ...*ies.IE) error {
+	seq, err := sess.SwitchDownlinkPath(m.conn, ebi, newAccessFTEID, ie...)
+	if err != nil {
+		return err
+	}
+	rsp, err := m.calls.Wait(ctx, sess.PeerAddr(), seq)
+	if err != nil {
+		return err
+	}
+	return v2.CheckCause(rsp)
+}
+
+// Detach sends Delete Session Request for the PDN connection of Session, and waits
+// for the response. The EBI of the default Bearer is set as the Linked EBI if not
+// given in ie.
+//
+// The Session is removed from Conn when the request is accepted. It returns
+// CauseError if the request is rejected.
+func (m *MME) Detach(ctx context.Context, sess *v2.Session, ie ...*ies.IE) error {
+	teid, err := sess.GetTEID(v2.IFTypeS11S4SGWGTPC)
+	if err != nil {
+		return err
+	}
+
+	req := messages.NewDeleteSessionRequest(teid, 0, ie...)
+	if req.LinkedEBI == nil {
+		if br := sess.GetDefaultBearer(); br != nil {
+			req.LinkedEBI = ies.NewEPSBearerID(br.GetEBI())
+			req.SetLength()
+		}
+	}
+	rsp, err := m.calls.Call(ctx, m.conn, sess.PeerAddr(), req)
+	if err != nil {
+		return err
+	}
+	if err := v2.CheckCause(rsp); err != nil {
+		return err
+	}
+
+	m.conn.RemoveSession(sess)
+	return nil
+}
+
+func (m *MME) handleCreateBearerRequest(c *v2.Conn, senderAddr net.Addr, msg *messages.CreateBearerRequest) error {
+	sess, err := c.GetSessionByTEID(msg.TEID(), senderAddr)
+	if err != nil {
+		return c.RespondTo(senderAddr, msg, messages.NewCreateBearerResponse(
+			0, 0, ies.NewCause(v2.CauseContextNotFound, 0, 0, 0, nil),
+		))
+	}
+	teid, err := sess.GetTEID(v2.IFTypeS11S4SGWGTPC)
+	if err != nil {
+		return err
+	}
+
+	var (
+		rspBCs   []*ies.IE
+		accepted bool
+		used     = usedEBIs(sess)
+	)
+	for _, bc := range messages.FindIEs(msg, ies.BearerContext) {
+		cause := v2.CauseRequestAccepted
+		if fn := m.policy.AuthorizeBearer; fn != nil {
+			cause = fn(sess, bc)
+		}
+
+		var ebi uint8
+		if cause == v2.CauseRequestAccepted {
+			if ebi = nextEBI(used); ebi == 0 {
+				cause = v2.CauseNoResourcesAvailable
+			}
+		}
+		children := []*ies.IE{
+			ies.NewEPSBearerID(ebi),
+			ies.NewCause(cause, 0, 0, 0, nil),
+		}
+		if cause == v2.CauseRequestAccepted {
+			accepted = true
+			used[ebi] = true
+			if fn := m.policy.AccessFTEID; fn != nil {
+				if fteid := fn(sess, ebi); fteid != nil {
+					children = append(children, fteid)
+				}
+			}
+			// the S1-U S-GW F-TEID in the request is instance 1 in the response.
+			for _, child := range bc.ChildIEs {
+				if child.Type == ies.FullyQualifiedTEID && child.Instance() == 0 {
+					children = append(children, ies.New(child.Type, 1, child.Payload))
+				}
+			}
+		}
+		rspBCs = append(rspBCs, ies.NewBearerContext(children...))
+	}
+
+	cause := v2.CauseRequestAccepted
+	if !accepted {
+		cause = v2.CauseRequestRejectedReasonNotSpecified
+	}
+	return c.CreateBearerResponse(
+		teid, senderAddr, msg,
+		append([]*ies.IE{ies.NewCause(cause, 0, 0, 0, nil)}, rspBCs...)...,
+	)
+}
+
+func (m *MME) handleDeleteBearerRequest(c *v2.Conn, senderAddr net.Addr, msg *messages.DeleteBearerRequest) error {
+	sess, err := c.GetSessionByTEID(msg.TEID(), senderAddr)
+	if err != nil {
+		return c.RespondTo(senderAddr, msg, messages.NewDeleteBearerResponse(
+			0, 0, ies.NewCause(v2.CauseContextNotFound, 0, 0, 0, nil),
+		))
+	}
+	teid, err := sess.GetTEID(v2.IFTypeS11S4SGWGTPC)
+	if err != nil {
+		return err
+	}
+
+	var (
+		ebis   []uint8
+		rspIEs = []*ies.IE{ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil)}
+	)
+	if ie := msg.LinkedEBI; ie != nil {
+		ebi, err := ie.EPSBearerID()
+		if err != nil {
+			return err
+		}
+		ebis = append(ebis, ebi)
+		rspIEs = append(rspIEs, ies.NewEPSBearerID(ebi))
+	}
+	for _, ie := range messages.FindIEs(msg, ies.EPSBearerID) {
+		if ie.Instance() != 1 {
+			continue
+		}
+		ebi, err := ie.EPSBearerID()
+		if err != nil {
+			return err
+		}
+		ebis = append(ebis, ebi)
+		rspIEs = append(rspIEs, ies.NewBearerContext(
+			ies.NewEPSBearerID(ebi), ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+		))
+	}
+
+	if err := c.DeleteBearerResponse(teid, senderAddr, msg, rspIEs...); err != nil {
+		return err
+	}
+	if msg.LinkedEBI != nil {
+		c.RemoveSession(sess)
+	}
+	if fn := m.policy.OnBearerDeleted; fn != nil {
+		fn(sess, ebis)
+	}
+	return nil
+}
+
+// usedEBIs returns the EBIs of the Bearers in Session.
+func usedEBIs(sess *v2.Session) map[uint8]bool {
+	used := map[uint8]bool{}
+	for _, br := range sess.Bearers() {
+		used[br.GetEBI()] = true
+	}
+	return used
+}
+
+// nextEBI returns the smallest EBI not in used, or 0 if all of them are used. The
+// EBIs from 5 to 15 are available for the EPS Bearers (TS 24.007 11.2.3.1.5).
+func nextEBI(used map[uint8]bool) uint8 {
+	for ebi := uint8(5); ebi <= 15; ebi++ {
+		if !used[ebi] {
+			return ebi
+		}
+	}
+	return 0
+}
@@ -0,0 +1,308 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package mme_test
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+	"github.com/wmnsk/go-gtp/v2/mme"
+)
+
+const imsi = "123451234567890"
+
+func listen(t *testing.T, addr string, errCh chan error) *v2.Conn {
+	t.Helper()
+	laddr, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := v2.ListenAndServe(laddr, 0, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn
+}
+
+// serveSGW makes conn respond to Create Session Request with cause as soon as it is
+// received, adding the Session on the S-GW side when it is accepted. Nothing is
+// responded if cause is 0. The other responses received are sent to rspCh.
+func serveSGW(conn *v2.Conn, cause uint8, rspCh chan messages.Message) {
+	conn.HandleCreateSessionRequest(func(c *v2.Conn, senderAddr net.Addr, msg *messages.CreateSessionRequest) error {
+		if cause == 0 {
+			return nil
+		}
+		sess, err := v2.NewSessionFromIEs(senderAddr, messages.IEs(msg)...)
+		if err != nil {
+			return err
+		}
+		mmeTEID, err := sess.GetTEID(v2.IFTypeS11MMEGTPC)
+		if err != nil {
+			return err
+		}
+		if cause != v2.CauseRequestAccepted {
+			return c.RespondTo(senderAddr, msg, messages.NewCreateSessionResponse(
+				mmeTEID, 0, ies.NewCause(cause, 0, 0, 0, nil),
+			))
+		}
+
+		fteid := c.NewFTEID(v2.IFTypeS11S4SGWGTPC, "127.0.1.21", "")
+		sess.AddTEID(v2.IFTypeS11S4SGWGTPC, fteid.MustTEID())
+		if err := sess.Activate(); err != nil {
+			return err
+		}
+		c.AddSession(sess)
+		return c.RespondTo(senderAddr, msg, messages.NewCreateSessionResponse(
+			mmeTEID, 0,
+			ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+			fteid,
+			ies.NewPDNAddressAllocation("10.0.0.1"),
+			ies.NewBearerContext(
+				ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+				ies.NewEPSBearerID(5),
+				ies.NewFullyQualifiedTEID(v2.IFTypeS1USGWGTPU, 0x11111111, "127.0.1.21", ""),
+				ies.NewChargingID(1),
+			),
+		))
+	})
+
+	deliver := func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+		rspCh <- msg
+		return nil
+	}
+	conn.AddHandlers(map[uint8]v2.HandlerFunc{
+		messages.MsgTypeCreateBearerResponse: deliver,
+		messages.MsgTypeDeleteBearerResponse: deliver,
+	})
+}
+
+func attach(ctx context.Context, m *mme.MME) (*v2.Session, error) {
+	return m.Attach(
+		ctx,
+		ies.NewIMSI(imsi),
+		m.Conn().NewFTEID(v2.IFTypeS11MMEGTPC, "127.0.1.20", ""),
+		ies.NewAccessPointName("some.apn.example"),
+		ies.NewRATType(v2.RATTypeEUTRAN),
+		ies.NewBearerContext(ies.NewEPSBearerID(5), ies.NewBearerQoS(1, 2, 1, 9, 0, 0, 0, 0)),
+	)
+}
+
+func TestBearerRequests(t *testing.T) {
+	errCh := make(chan error, 16)
+	mmeConn := listen(t, "127.0.1.20:2123", errCh)
+	defer mmeConn.Close()
+	sgwConn := listen(t, "127.0.1.21:2123", errCh)
+	defer sgwConn.Close()
+
+	rspCh := make(chan messages.Message, 1)
+	serveSGW(sgwConn, v2.CauseRequestAccepted, rspCh)
+
+	deletedCh := make(chan []uint8, 1)
+	m := mme.New(mmeConn, sgwConn.LocalAddr(), &mme.Policy{
+		// the dedicated Bearers with QCI 9 are rejected.
+		AuthorizeBearer: func(sess *v2.Session, bc *ies.IE) uint8 {
+			for _, child := range bc.ChildIEs {
+				if child.Type != ies.BearerQoS {
+					continue
+				}
+				if qci, err := child.QCILabel(); err == nil && qci == 9 {
+					return v2.CauseServiceDenied
+				}
+			}
+			return v2.CauseRequestAccepted
+		},
+		AccessFTEID: func(sess *v2.Session, ebi uint8) *ies.IE {
+			return ies.NewFullyQualifiedTEID(v2.IFTypeS1UeNodeBGTPU, uint32(ebi), "127.0.1.22", "")
+		},
+		OnBearerDeleted: func(sess *v2.Session, ebis []uint8) {
+			deletedCh <- ebis
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sess, err := attach(ctx, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sess.GetDefaultBearer().GetSubscriberIP(); got != "10.0.0.1" {
+		t.Errorf("unexpected subscriber IP: got %s, want 10.0.0.1", got)
+	}
+	mmeTEID, err := sess.GetTEID(v2.IFTypeS11MMEGTPC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	request := func(req messages.Message) messages.Message {
+		t.Helper()
+		if _, err := sgwConn.SendMessageTo(req, mmeConn.LocalAddr()); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case rsp := <-rspCh:
+			return rsp
+		case err := <-errCh:
+			t.Fatal(err)
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		}
+		return nil
+	}
+	createBearer := func(qci uint8) messages.Message {
+		t.Helper()
+		return request(messages.NewCreateBearerRequest(
+			mmeTEID, 0,
+			ies.NewEPSBearerID(5),
+			ies.NewBearerContext(
+				ies.NewEPSBearerID(0),
+				ies.NewBearerQoS(0, 2, 0, qci, 0, 0, 64, 64),
+				ies.NewFullyQualifiedTEID(v2.IFTypeS1USGWGTPU, 0x22222222, "127.0.1.21", ""),
+			),
+		))
+	}
+
+	t.Run("CreateBearer/accepted", func(t *testing.T) {
+		rsp := createBearer(1)
+		if err := v2.CheckCause(rsp); err != nil {
+			t.Fatal(err)
+		}
+		bc, err := messages.FindIE(rsp, ies.BearerContext, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ebi uint8
+		for _, child := range bc.ChildIEs {
+			if child.Type == ies.EPSBearerID {
+				ebi = child.MustEPSBearerID()
+			}
+		}
+		if ebi != 6 {
+			t.Errorf("unexpected EBI: got %d, want 6", ebi)
+		}
+		if _, err := sess.LookupBearerByEBI(6); err != nil {
+			t.Errorf("dedicated Bearer is not added: %v", err)
+		}
+	})
+
+	t.Run("CreateBearer/rejected", func(t *testing.T) {
+		rsp := createBearer(9)
+		cerr, ok := v2.CheckCause(rsp).(*v2.CauseError)
+		if !ok {
+			t.Fatalf("unexpected error: %v", v2.CheckCause(rsp))
+		}
+		if uint8(cerr.Cause) != v2.CauseRequestRejectedReasonNotSpecified {
+			t.Errorf("unexpected Cause: got %d, want %d", cerr.Cause, v2.CauseRequestRejectedReasonNotSpecified)
+		}
+		if n := len(sess.Bearers()); n != 2 {
+			t.Errorf("unexpected number of Bearers: got %d, want 2", n)
+		}
+	})
+
+	t.Run("DeleteBearer", func(t *testing.T) {
+		rsp := request(messages.NewDeleteBearerRequest(mmeTEID, 0, ies.NewEPSBearerID(6).WithInstance(1)))
+		if err := v2.CheckCause(rsp); err != nil {
+			t.Fatal(err)
+		}
+		if got := <-deletedCh; !reflect.DeepEqual(got, []uint8{6}) {
+			t.Errorf("unexpected EBIs deleted: got %v, want [6]", got)
+		}
+		if _, err := sess.LookupBearerByEBI(6); err == nil {
+			t.Error("dedicated Bearer is not removed")
+		}
+		if n := mmeConn.SessionCount(); n != 1 {
+			t.Errorf("unexpected SessionCount: got %d, want 1", n)
+		}
+	})
+
+	t.Run("DeleteBearer/LinkedEBI", func(t *testing.T) {
+		rsp := request(messages.NewDeleteBearerRequest(mmeTEID, 0, ies.NewEPSBearerID(5)))
+		if err := v2.CheckCause(rsp); err != nil {
+			t.Fatal(err)
+		}
+		if got := <-deletedCh; !reflect.DeepEqual(got, []uint8{5}) {
+			t.Errorf("unexpected EBIs deleted: got %v, want [5]", got)
+		}
+		if n := mmeConn.SessionCount(); n != 0 {
+			t.Errorf("Session is not removed: %d", n)
+		}
+	})
+
+	select {
+	case err := <-errCh:
+		t.Error(err)
+	default:
+	}
+}
+
+func TestAttachFailure(t *testing.T) {
+	cases := []struct {
+		description string
+		mmeAddr     string
+		sgwAddr     string
+		cause       uint8
+		check       func(t *testing.T, err error)
+	}{
+		{
+			"rejected",
+			"127.0.1.20:2124", "127.0.1.21:2124",
+			v2.CauseUserAuthenticationFailed,
+			func(t *testing.T, err error) {
+				cerr, ok := err.(*v2.CauseError)
+				if !ok {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if uint8(cerr.Cause) != v2.CauseUserAuthenticationFailed {
+					t.Errorf("unexpected Cause: got %d, want %d", cerr.Cause, v2.CauseUserAuthenticationFailed)
+				}
+			},
+		}, {
+			"timeout",
+			"127.0.1.20:2125", "127.0.1.21:2125",
+			0,
+			func(t *testing.T, err error) {
+				if err != context.DeadlineExceeded {
+					t.Errorf("unexpected error: %v", err)
+				}
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			errCh := make(chan error, 16)
+			mmeConn := listen(t, c.mmeAddr, errCh)
+			defer mmeConn.Close()
+			sgwConn := listen(t, c.sgwAddr, errCh)
+			defer sgwConn.Close()
+
+			serveSGW(sgwConn, c.cause, nil)
+			m := mme.New(mmeConn, sgwConn.LocalAddr(), nil)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+			if _, err := attach(ctx, m); err == nil {
+				t.Fatal("Attach should fail")
+			} else {
+				c.check(t, err)
+			}
+			if n := mmeConn.SessionCount(); n != 0 {
+				t.Errorf("Session is not removed: %d", n)
+			}
+
+			select {
+			case err := <-errCh:
+				t.Error(err)
+			default:
+			}
+		})
+	}
+}
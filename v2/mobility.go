@@ -0,0 +1,71 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// This file provides the helpers for the mobility management messages exchanged
+// between MMEs/SGSNs on S3, S10 and S16.
+//
+// Unlike the ones for the session management, they are not bound to the Sessions
+// on Conn, as the UE context is typically not known by the receiver of the request
+// yet. The TEID given is used as it is, and the message is sent to raddr directly.
+
+// ContextRequest sends a ContextRequest with TEID and IEs given to raddr.
+func (c *Conn) ContextRequest(teid uint32, raddr net.Addr, ie ...*ies.IE) (uint32, error) {
+	return c.SendMessageTo(messages.NewContextRequest(teid, 0, ie...), raddr)
+}
+
+// ContextResponse sends a ContextResponse with TEID and IEs given in response to
+// the ContextRequest.
+func (c *Conn) ContextResponse(teid uint32, raddr net.Addr, req messages.Message, ie ...*ies.IE) error {
+	return c.RespondTo(raddr, req, messages.NewContextResponse(teid, 0, ie...))
+}
+
+// ContextAcknowledge sends a ContextAcknowledge with TEID and IEs given in response
+// to the ContextResponse.
+func (c *Conn) ContextAcknowledge(teid uint32, raddr net.Addr, res messages.Message, ie ...*ies.IE) error {
+	return c.RespondTo(raddr, res, messages.NewContextAcknowledge(teid, 0, ie...))
+}
+
+// IdentificationRequest sends an IdentificationRequest with TEID and IEs given to raddr.
+func (c *Conn) IdentificationRequest(teid uint32, raddr net.Addr, ie ...*ies.IE) (uint32, error) {
+	return c.SendMessageTo(messages.NewIdentificationRequest(teid, 0, ie...), raddr)
+}
+
+// IdentificationResponse sends an IdentificationResponse with TEID and IEs given in
+// response to the IdentificationRequest.
+func (c *Conn) IdentificationResponse(teid uint32, raddr net.Addr, req messages.Message, ie ...*ies.IE) error {
+	return c.RespondTo(raddr, req, messages.NewIdentificationResponse(teid, 0, ie...))
+}
+
+// ForwardRelocationRequest sends a ForwardRelocationRequest with TEID and IEs given
+// to raddr.
+func (c *Conn) ForwardRelocationRequest(teid uint32, raddr net.Addr, ie ...*ies.IE) (uint32, error) {
+	return c.SendMessageTo(messages.NewForwardRelocationRequest(teid, 0, ie...), raddr)
+}
+
+// ForwardRelocationResponse sends a ForwardRelocationResponse with TEID and IEs
+// given in response to the ForwardRelocationRequest.
+func (c *Conn) ForwardRelocationResponse(teid uint32, raddr net.Addr, req messages.Message, ie ...*ies.IE) error {
+	return c.RespondTo(raddr, req, messages.NewForwardRelocationResponse(teid, 0, ie...))
+}
+
+// ForwardRelocationCompleteNotification sends a ForwardRelocationCompleteNotification
+// with TEID and IEs given to raddr.
+func (c *Conn) ForwardRelocationCompleteNotification(teid uint32, raddr net.Addr, ie ...*ies.IE) (uint32, error) {
+	return c.SendMessageTo(messages.NewForwardRelocationCompleteNotification(teid, 0, ie...), raddr)
+}
+
+// ForwardRelocationCompleteAcknowledge sends a ForwardRelocationCompleteAcknowledge
+// with TEID and IEs given in response to the ForwardRelocationCompleteNotification.
+func (c *Conn) ForwardRelocationCompleteAcknowledge(teid uint32, raddr net.Addr, req messages.Message, ie ...*ies.IE) error {
+	return c.RespondTo(raddr, req, messages.NewForwardRelocationCompleteAcknowledge(teid, 0, ie...))
+}
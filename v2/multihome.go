@@ -0,0 +1,273 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// endpoints is the local endpoints added to Conn with AddLocalAddr, and the rules to
+// choose which of them is used to send the messages.
+//
+// The endpoint created with Conn is not kept here, and used when none of the rules
+// matches.
+type endpoints struct {
+	mu sync.RWMutex
+
+	// conns is the endpoints keyed by addrString of the local address.
+	conns map[string]net.PacketConn
+
+	// byIfType and byPeer are the endpoints set with SetInterfaceLocalAddr and
+	// SetPeerLocalAddr, and learned is the one that each peer sent the last message
+	// to. byPeer and learned are keyed by addrString of the peer.
+	byIfType map[uint8]net.PacketConn
+	byPeer   map[string]net.PacketConn
+	learned  map[string]net.PacketConn
+}
+
+// init creates the maps if not yet. It must be called with mu locked.
+func (e *endpoints) init() {
+	if e.conns != nil {
+		return
+	}
+	e.conns = map[string]net.PacketConn{}
+	e.byIfType = map[uint8]net.PacketConn{}
+	e.byPeer = map[string]net.PacketConn{}
+	e.learned = map[string]net.PacketConn{}
+}
+
+// AddLocalAddr binds the local address laddr to Conn in addition to the one given
+// when creating Conn, and starts serving on it in background. The SocketOptions so
+// can be nil.
+//
+// This lets a Conn be used on several interfaces with different addresses, e.g., S11
+// and S5/S8 of S-GW. The messages received on any of the local addresses are handled
+// in the same way with the same HandlerFuncs and Sessions, and the local address each
+// of them arrived on can be retrieved with ReceivedOn. The local address used to send
+// the messages is chosen in the following order.
+//
+//  1. The one that the request arrived on, for the response to it.
+//  2. The one set with SetInterfaceLocalAddr for the interface type of the sender
+//     F-TEID in the message.
+//  3. The one set with SetPeerLocalAddr for the peer.
+//  4. The one that the peer sent the last message to.
+//  5. The one given when creating Conn.
+//
+// The SetBatchSize is not applied to the local addresses added here.
+func (c *Conn) AddLocalAddr(laddr net.Addr, so *SocketOptions) error {
+	pktConn, err := so.listenPacket(laddr.Network(), laddr.String())
+	if err != nil {
+		return err
+	}
+
+	c.endpoints.mu.Lock()
+	c.endpoints.init()
+	c.endpoints.conns[addrString(pktConn.LocalAddr())] = pktConn
+	c.endpoints.mu.Unlock()
+
+	go c.serveOn(pktConn)
+	return nil
+}
+
+// LocalAddrs returns the local addresses of Conn, the one given when creating Conn
+// first and the ones added with AddLocalAddr after it in no particular order.
+func (c *Conn) LocalAddrs() []net.Addr {
+	c.endpoints.mu.RLock()
+	defer c.endpoints.mu.RUnlock()
+
+	laddrs := []net.Addr{c.LocalAddr()}
+	for _, pktConn := range c.endpoints.conns {
+		laddrs = append(laddrs, pktConn.LocalAddr())
+	}
+	return laddrs
+}
+
+// SetInterfaceLocalAddr sets the local address used to send the messages that have
+// the sender F-TEID with the interface type given, e.g., v2.IFTypeS5S8SGWGTPC for
+// the messages to P-GW. Giving nil as laddr unsets it.
+//
+// laddr should be the one given when creating Conn or added with AddLocalAddr.
+// Otherwise it returns ErrLocalAddrNotFound.
+func (c *Conn) SetInterfaceLocalAddr(ifType uint8, laddr net.Addr) error {
+	c.endpoints.mu.Lock()
+	defer c.endpoints.mu.Unlock()
+	c.endpoints.init()
+
+	if laddr == nil {
+		delete(c.endpoints.byIfType, ifType)
+		return nil
+	}
+	pktConn, err := c.endpointByAddr(laddr)
+	if err != nil {
+		return err
+	}
+	c.endpoints.byIfType[ifType] = pktConn
+	return nil
+}
+
+// SetPeerLocalAddr sets the local address used to send the messages to raddr.
+// Giving nil as laddr unsets it.
+//
+// laddr should be the one given when creating Conn or added with AddLocalAddr.
+// Otherwise it returns ErrLocalAddrNotFound.
+func (c *Conn) SetPeerLocalAddr(raddr, laddr net.Addr) error {
+	c.endpoints.mu.Lock()
+	defer c.endpoints.mu.Unlock()
+	c.endpoints.init()
+
+	if laddr == nil {
+		delete(c.endpoints.byPeer, addrString(raddr))
+		return nil
+	}
+	pktConn, err := c.endpointByAddr(laddr)
+	if err != nil {
+		return err
+	}
+	c.endpoints.byPeer[addrString(raddr)] = pktConn
+	return nil
+}
+
+// ReceivedOn returns the local address that msg arrived on.
+//
+// This is expected to be called inside the HandlerFunc, and returns the one given when
+// creating Conn for the message that is not received by Conn or after the HandlerFunc
+// returned.
+func (c *Conn) ReceivedOn(msg messages.Message) net.Addr {
+	if pktConn, ok := c.receivedOn(msg); ok {
+		return pktConn.LocalAddr()
+	}
+	return c.LocalAddr()
+}
+
+// endpointByAddr returns the endpoint bound to laddr. It must be called with
+// c.endpoints.mu locked.
+func (c *Conn) endpointByAddr(laddr net.Addr) (net.PacketConn, error) {
+	key := addrString(laddr)
+	if key == addrString(c.LocalAddr()) {
+		return c.pktConn, nil
+	}
+	if pktConn, ok := c.endpoints.conns[key]; ok {
+		return pktConn, nil
+	}
+	return nil, ErrLocalAddrNotFound
+}
+
+func (c *Conn) receivedOn(msg messages.Message) (net.PacketConn, bool) {
+	if msg == nil {
+		return nil, false
+	}
+	v, ok := c.receivedOnMap.Load(msg)
+	if !ok {
+		return nil, false
+	}
+	return v.(net.PacketConn), true
+}
+
+// endpointFor chooses the endpoint to send to raddr, with the message received if
+// it is the response, and the message to be sent if available.
+func (c *Conn) endpointFor(raddr net.Addr, received, toBeSent messages.Message) net.PacketConn {
+	if pktConn, ok := c.receivedOn(received); ok {
+		return pktConn
+	}
+
+	c.endpoints.mu.RLock()
+	defer c.endpoints.mu.RUnlock()
+
+	if len(c.endpoints.conns) == 0 {
+		return c.pktConn
+	}
+	if toBeSent != nil && len(c.endpoints.byIfType) != 0 {
+		if fteid, err := messages.FindIE(toBeSent, ies.FullyQualifiedTEID, 0); err == nil {
+			if pktConn, ok := c.endpoints.byIfType[fteid.MustInterfaceType()]; ok {
+				return pktConn
+			}
+		}
+	}
+	key := addrString(raddr)
+	if pktConn, ok := c.endpoints.byPeer[key]; ok {
+		return pktConn
+	}
+	if pktConn, ok := c.endpoints.learned[key]; ok {
+		return pktConn
+	}
+	return c.pktConn
+}
+
+// learnEndpoint keeps the endpoint that raddr sent the message to, if Conn has
+// multiple endpoints.
+func (c *Conn) learnEndpoint(raddr net.Addr, pktConn net.PacketConn) {
+	c.endpoints.mu.RLock()
+	multi := len(c.endpoints.conns) != 0
+	key := addrString(raddr)
+	known, ok := c.endpoints.learned[key]
+	c.endpoints.mu.RUnlock()
+	if !multi || (ok && known == pktConn) {
+		return
+	}
+
+	c.endpoints.mu.Lock()
+	c.endpoints.learned[key] = pktConn
+	c.endpoints.mu.Unlock()
+}
+
+// writeTo writes p to raddr from the endpoint chosen by endpointFor, or queues it if
+// SendQueue is set.
+func (c *Conn) writeTo(p []byte, raddr net.Addr, received, toBeSent messages.Message) (int, error) {
+	pktConn := c.endpointFor(raddr, received, toBeSent)
+	if s := c.sender(); s != nil {
+		if queued, err := s.enqueue(pktConn, p, raddr, toBeSent); queued {
+			if err != nil {
+				return 0, err
+			}
+			return len(p), nil
+		}
+	}
+
+	n, err := pktConn.WriteTo(p, raddr)
+	if err != nil {
+		return n, err
+	}
+
+	c.traceOutgoing(raddr, p, toBeSent)
+	return n, nil
+}
+
+// serveOn reads the datagrams from the endpoint added with AddLocalAddr and
+// dispatches them, until Conn is closed.
+func (c *Conn) serveOn(pktConn net.PacketConn) {
+	buf := make([]byte, 1600)
+	for {
+		n, raddr, err := pktConn.ReadFrom(buf)
+		if err != nil {
+			select {
+			case <-c.closed():
+				return
+			default:
+			}
+			c.log().Warn("failed to read from conn", "local", pktConn.LocalAddr().String(), "error", err)
+			continue
+		}
+
+		raw := make([]byte, n)
+		copy(raw, buf)
+		c.dispatchDatagram(&datagram{raddr: raddr, raw: raw, arrivedAt: time.Now(), pktConn: pktConn})
+	}
+}
+
+// closeEndpoints closes all the endpoints added with AddLocalAddr.
+func (c *Conn) closeEndpoints() {
+	c.endpoints.mu.Lock()
+	defer c.endpoints.mu.Unlock()
+
+	for key, pktConn := range c.endpoints.conns {
+		_ = pktConn.Close()
+		delete(c.endpoints.conns, key)
+	}
+}
@@ -5,14 +5,23 @@
 package v2_test
 
 import (
+	"bytes"
+	"context"
+	"fmt"
 	"net"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/pkg/errors"
+	v1 "github.com/wmnsk/go-gtp/v1"
+	v1msg "github.com/wmnsk/go-gtp/v1/messages"
 	v2 "github.com/wmnsk/go-gtp/v2"
 	"github.com/wmnsk/go-gtp/v2/ies"
 	"github.com/wmnsk/go-gtp/v2/messages"
+	"github.com/wmnsk/go-gtp/v2/testutils"
 )
 
 func setup(doneCh chan struct{}, errCh chan error) (cliConn, srvConn *v2.Conn, err error) {
@@ -160,6 +169,112 @@ func TestCreateSession(t *testing.T) {
 			if count := srvConn.BearerCount(); count != 1 {
 				t.Errorf("wrong BearerCount in srvConn. want %d, got: %d", 1, count)
 			}
+
+			peer, err := cliConn.GetPeer(srvConn.LocalAddr())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if state := peer.PathState(); state != v2.PathStateUp {
+				t.Errorf("wrong PathState of peer. want %s, got: %s", v2.PathStateUp, state)
+			}
+			if _, ok := peer.RestartCounter(); !ok {
+				t.Error("RestartCounter of peer should be known after Echo exchange")
+			}
+			if seq := peer.SequenceNumber(); seq != cliConn.SequenceNumber() {
+				t.Errorf("wrong SequenceNumber of peer. want %d, got: %d", cliConn.SequenceNumber(), seq)
+			}
+			return
+		case <-time.After(3 * time.Second):
+			t.Fatal("timed out while waiting for validating Create Session Response")
+		}
+	case err := <-errCh:
+		t.Fatal(err)
+	case <-time.After(1 * time.Second):
+		t.Fatal("timed out while waiting for Create Session Response")
+	}
+}
+
+func TestCloseSocket(t *testing.T) {
+	addr := &net.UDPAddr{IP: net.ParseIP("127.0.1.19"), Port: 2123}
+
+	// the socket created by ListenAndServe is released by Close.
+	conn, err := v2.ListenAndServe(addr, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Close(); err != nil {
+		t.Fatal(err)
+	}
+	pktConn, err := net.ListenPacket("udp", addr.String())
+	if err != nil {
+		t.Fatalf("socket not closed by Close: %v", err)
+	}
+	defer pktConn.Close()
+
+	// the one given to Serve is left open for the caller.
+	conn = v2.Serve(pktConn, 0, nil)
+	if err := conn.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := pktConn.SetDeadline(time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pktConn.WriteTo([]byte{0}, addr); err != nil {
+		t.Errorf("socket given to Serve closed by Close: %v", err)
+	}
+}
+
+func TestCreateSessionByAPN(t *testing.T) {
+	var (
+		rspSent = make(chan struct{})
+		rspOK   = make(chan struct{})
+		errCh   = make(chan error)
+	)
+	cliConn, srvConn, err := setup(rspSent, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { cliConn.Close(); srvConn.Close() }()
+
+	if _, _, err := cliConn.CreateSessionByAPN(v2.ServicePGWS5GTP, ies.NewIMSI("123451234567890")); err == nil {
+		t.Fatal("CreateSessionByAPN should fail without NodeSelector")
+	}
+
+	cliConn.SetNodeSelector(v2.NodeSelectorFunc(func(fqdn, service string) ([]net.Addr, error) {
+		if want := "some.apn.example.apn.epc.mnc045.mcc123.3gppnetwork.org"; fqdn != want {
+			t.Errorf("wrong FQDN. want %s, got: %s", want, fqdn)
+		}
+		if service != v2.ServicePGWS5GTP {
+			t.Errorf("wrong service. want %s, got: %s", v2.ServicePGWS5GTP, service)
+		}
+		return []net.Addr{srvConn.LocalAddr()}, nil
+	}))
+	cliConn.AddHandler(
+		messages.MsgTypeCreateSessionResponse,
+		func(c *v2.Conn, srvAddr net.Addr, msg messages.Message) error {
+			if srvAddr.String() != "127.0.0.2:2123" {
+				t.Errorf("invalid server address: %s", srvAddr)
+			}
+			rspOK <- struct{}{}
+			return nil
+		},
+	)
+
+	session, _, err := cliConn.CreateSessionByAPN(
+		v2.ServicePGWS5GTP,
+		ies.NewIMSI("123451234567890"),
+		ies.NewAccessPointName("some.apn.example"),
+		ies.NewServingNetwork("123", "45"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cliConn.AddSession(session)
+
+	select {
+	case <-rspSent:
+		select {
+		case <-rspOK:
 			return
 		case <-time.After(3 * time.Second):
 			t.Fatal("timed out while waiting for validating Create Session Response")
@@ -170,3 +285,3017 @@ func TestCreateSession(t *testing.T) {
 		t.Fatal("timed out while waiting for Create Session Response")
 	}
 }
+
+func TestMandatoryIEMissing(t *testing.T) {
+	var (
+		rspSent = make(chan struct{})
+		causeCh = make(chan uint8)
+		errCh   = make(chan error)
+	)
+	cliConn, srvConn, err := setup(rspSent, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { cliConn.Close(); srvConn.Close() }()
+	srvConn.SetMandatoryIERules(v2.DefaultMandatoryIERules())
+
+	cliConn.HandleCreateSessionResponse(
+		func(c *v2.Conn, srvAddr net.Addr, csRsp *messages.CreateSessionResponse) error {
+			if csRsp.Cause == nil {
+				return &v2.RequiredIEMissingError{Type: ies.Cause}
+			}
+			cause, err := csRsp.Cause.Cause()
+			if err != nil {
+				return err
+			}
+			causeCh <- cause
+			return nil
+		},
+	)
+
+	// Create Session Request without RAT Type, Sender F-TEID, APN and Bearer Context.
+	if _, _, err := cliConn.CreateSession(srvConn.LocalAddr(), ies.NewIMSI("123451234567890")); err != nil {
+		t.Fatal(err)
+	}
+
+	var gotErr, gotCause bool
+	for !gotErr || !gotCause {
+		select {
+		case <-rspSent:
+			t.Fatal("handler should not be called for the request without mandatory IEs")
+		case cause := <-causeCh:
+			if cause != v2.CauseMandatoryIEMissing {
+				t.Errorf("wrong Cause. want: %d, got: %d", v2.CauseMandatoryIEMissing, cause)
+			}
+			gotCause = true
+		case err := <-errCh:
+			if _, ok := err.(*v2.RequiredIEMissingError); !ok {
+				t.Fatal(err)
+			}
+			gotErr = true
+		case <-time.After(3 * time.Second):
+			t.Fatal("timed out while waiting for Create Session Response")
+		}
+	}
+}
+
+func TestMandatoryIEMissingInSession(t *testing.T) {
+	peer, err := net.ListenPacket("udp", "127.0.0.103:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peer.Close()
+	errCh := make(chan error, 1)
+	conn, err := v2.ListenAndServe(&net.UDPAddr{IP: net.IPv4(127, 0, 0, 104), Port: 2123}, 0, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetMandatoryIERules(v2.DefaultMandatoryIERules())
+
+	sess := v2.NewSession(peer.LocalAddr(), &v2.Subscriber{IMSI: "123451234567890"})
+	sess.AddTEID(v2.IFTypeS11MMEGTPC, 0x11111111)
+	sess.AddTEID(v2.IFTypeS11S4SGWGTPC, 0x22222222)
+	if err := sess.Activate(); err != nil {
+		t.Fatal(err)
+	}
+	conn.AddSession(sess)
+
+	// Create Bearer Request without EPS Bearer ID and Bearer Context.
+	b, err := messages.Marshal(messages.NewCreateBearerRequest(0x11111111, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := peer.WriteTo(b, conn.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := peer.SetReadDeadline(time.Now().Add(3 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1500)
+	n, _, err := peer.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := messages.Parse(buf[:n])
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp, ok := msg.(*messages.CreateBearerResponse)
+	if !ok {
+		t.Fatalf("unexpected message: %s", msg.MessageTypeName())
+	}
+	if rsp.TEID() != 0x22222222 {
+		t.Errorf("wrong TEID. want: %#x, got: %#x", 0x22222222, rsp.TEID())
+	}
+	if rsp.Cause == nil {
+		t.Fatal("Cause is missing")
+	}
+	if cause, err := rsp.Cause.Cause(); err != nil {
+		t.Fatal(err)
+	} else if cause != v2.CauseMandatoryIEMissing {
+		t.Errorf("wrong Cause. want: %d, got: %d", v2.CauseMandatoryIEMissing, cause)
+	}
+
+	select {
+	case err := <-errCh:
+		if _, ok := err.(*v2.RequiredIEMissingError); !ok {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out while waiting for RequiredIEMissingError")
+	}
+}
+
+// sourcePortPeer sends Echo Requests to Conn from the port 2123 known by the
+// Session and another ephemeral port.
+type sourcePortPeer struct {
+	t       *testing.T
+	srvConn *v2.Conn
+	sess    *v2.Session
+	known   net.PacketConn
+	other   net.PacketConn
+	seq     uint32
+}
+
+// newSourcePortPeer serves Conn on srvIP for the peer with the Session.
+func newSourcePortPeer(t *testing.T, srvIP string, errCh chan error) *sourcePortPeer {
+	t.Helper()
+
+	p := &sourcePortPeer{t: t}
+	var err error
+	p.srvConn, err = v2.ListenAndServe(&net.UDPAddr{IP: net.ParseIP(srvIP), Port: 2123}, 0, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.known, err = net.ListenPacket("udp", "127.0.1.18:2123"); err != nil {
+		p.close()
+		t.Fatal(err)
+	}
+	if p.other, err = net.ListenPacket("udp", "127.0.1.18:0"); err != nil {
+		p.close()
+		t.Fatal(err)
+	}
+
+	p.sess = v2.NewSession(p.known.LocalAddr(), &v2.Subscriber{IMSI: "123451234567890"})
+	p.srvConn.AddSession(p.sess)
+	return p
+}
+
+func (p *sourcePortPeer) close() {
+	p.srvConn.Close()
+	if p.known != nil {
+		p.known.Close()
+	}
+	if p.other != nil {
+		p.other.Close()
+	}
+}
+
+// echo sends Echo Request from pc and reports whether Echo Response is received.
+func (p *sourcePortPeer) echo(pc net.PacketConn) bool {
+	p.t.Helper()
+
+	p.seq++
+	b, err := messages.NewEchoRequest(p.seq, ies.NewRecovery(0)).Marshal()
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	if _, err := pc.WriteTo(b, p.srvConn.LocalAddr()); err != nil {
+		p.t.Fatal(err)
+	}
+
+	buf := make([]byte, 1500)
+	if err := pc.SetReadDeadline(time.Now().Add(100 * time.Millisecond)); err != nil {
+		p.t.Fatal(err)
+	}
+	n, _, err := pc.ReadFrom(buf)
+	if err != nil {
+		return false
+	}
+	msg, err := messages.Parse(buf[:n])
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	return msg.MessageType() == messages.MsgTypeEchoResponse && msg.Sequence() == p.seq
+}
+
+// expectUnexpectedSourcePort checks that the message from the other port is notified
+// as UnexpectedSourcePortError.
+func (p *sourcePortPeer) expectUnexpectedSourcePort(errCh chan error, known, got net.PacketConn) {
+	p.t.Helper()
+
+	select {
+	case err := <-errCh:
+		e, ok := err.(*v2.UnexpectedSourcePortError)
+		if !ok {
+			p.t.Fatalf("unexpected error: %v", err)
+		}
+		if e.Known.String() != known.LocalAddr().String() || e.Got.String() != got.LocalAddr().String() {
+			p.t.Errorf("unexpected addresses: known %s, got %s", e.Known, e.Got)
+		}
+	case <-time.After(time.Second):
+		p.t.Fatal("UnexpectedSourcePortError not notified")
+	}
+}
+
+func TestSourcePortPolicy(t *testing.T) {
+	t.Run("strict", func(t *testing.T) {
+		errCh := make(chan error, 10)
+		p := newSourcePortPeer(t, "127.0.1.15", errCh)
+		defer p.close()
+		p.srvConn.SetSourcePortPolicy(v2.SourcePortPolicyStrict, nil)
+
+		if !p.echo(p.known) {
+			t.Fatal("Echo Response not received from the known port")
+		}
+		if p.echo(p.other) {
+			t.Error("Echo Response received from the unexpected port")
+		}
+		p.expectUnexpectedSourcePort(errCh, p.known, p.other)
+
+		// the known port is kept.
+		if !p.echo(p.known) {
+			t.Error("Echo Response not received from the known port after dropping")
+		}
+		if got := p.sess.PeerAddr().String(); got != p.known.LocalAddr().String() {
+			t.Errorf("unexpected PeerAddr: got %s, want %s", got, p.known.LocalAddr())
+		}
+	})
+
+	t.Run("lenient", func(t *testing.T) {
+		errCh := make(chan error, 10)
+		p := newSourcePortPeer(t, "127.0.1.16", errCh)
+		defer p.close()
+		p.srvConn.SetSourcePortPolicy(v2.SourcePortPolicyLenient, nil)
+
+		if !p.echo(p.known) {
+			t.Fatal("Echo Response not received from the known port")
+		}
+		if !p.echo(p.other) {
+			t.Fatal("Echo Response not received from the new port")
+		}
+		if got := p.sess.PeerAddr().String(); got != p.other.LocalAddr().String() {
+			t.Errorf("PeerAddr not updated: got %s, want %s", got, p.other.LocalAddr())
+		}
+
+		// the new port is the one known now, and the old one is unexpected.
+		p.srvConn.SetSourcePortPolicy(v2.SourcePortPolicyStrict, nil)
+		if !p.echo(p.other) {
+			t.Error("Echo Response not received from the learned port")
+		}
+		if p.echo(p.known) {
+			t.Error("Echo Response received from the old port")
+		}
+		p.expectUnexpectedSourcePort(errCh, p.other, p.known)
+	})
+
+	t.Run("callback", func(t *testing.T) {
+		errCh := make(chan error, 10)
+		p := newSourcePortPeer(t, "127.0.1.17", errCh)
+		defer p.close()
+
+		var (
+			mu       sync.Mutex
+			calls    []string
+			decision = v2.SourcePortPolicyStrict
+		)
+		p.srvConn.SetSourcePortPolicy(v2.SourcePortPolicyCallback, func(known, got net.Addr) v2.SourcePortPolicy {
+			mu.Lock()
+			defer mu.Unlock()
+			calls = append(calls, known.String()+">"+got.String())
+			return decision
+		})
+		wantCall := p.known.LocalAddr().String() + ">" + p.other.LocalAddr().String()
+
+		if !p.echo(p.known) {
+			t.Fatal("Echo Response not received from the known port")
+		}
+
+		// rejected by the SourcePortFunc.
+		if p.echo(p.other) {
+			t.Error("Echo Response received after rejected")
+		}
+		p.expectUnexpectedSourcePort(errCh, p.known, p.other)
+		if got := p.sess.PeerAddr().String(); got != p.known.LocalAddr().String() {
+			t.Errorf("PeerAddr updated after rejected: got %s", got)
+		}
+
+		// accepted by the SourcePortFunc.
+		mu.Lock()
+		decision = v2.SourcePortPolicyLenient
+		mu.Unlock()
+		if !p.echo(p.other) {
+			t.Error("Echo Response not received after accepted")
+		}
+		if got := p.sess.PeerAddr().String(); got != p.other.LocalAddr().String() {
+			t.Errorf("PeerAddr not updated after accepted: got %s, want %s", got, p.other.LocalAddr())
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if len(calls) != 2 || calls[0] != wantCall || calls[1] != wantCall {
+			t.Errorf("unexpected calls to SourcePortFunc: got %v, want %s twice", calls, wantCall)
+		}
+	})
+}
+
+func TestRetransmittedRequest(t *testing.T) {
+	var (
+		rspSent = make(chan struct{})
+		rspGot  = make(chan struct{})
+		errCh   = make(chan error)
+	)
+	cliConn, srvConn, err := setup(rspSent, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { cliConn.Close(); srvConn.Close() }()
+
+	cliConn.AddHandler(
+		messages.MsgTypeCreateSessionResponse,
+		func(c *v2.Conn, srvAddr net.Addr, msg messages.Message) error {
+			rspGot <- struct{}{}
+			return nil
+		},
+	)
+
+	// send the same request twice without changing Sequence Number.
+	b, err := messages.NewCreateSessionRequest(0, 0x100, ies.NewIMSI("123451234567890")).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := cliConn.WriteTo(b, srvConn.LocalAddr()); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	var handled, responses int
+	for responses < 2 || handled < 1 {
+		select {
+		case <-rspSent:
+			handled++
+		case <-rspGot:
+			responses++
+		case err := <-errCh:
+			t.Fatal(err)
+		case <-time.After(3 * time.Second):
+			t.Fatalf("timed out while waiting for Create Session Response, got %d", responses)
+		}
+	}
+
+	// make sure that the retransmitted one is not handled.
+	select {
+	case <-rspSent:
+		handled++
+	case <-time.After(200 * time.Millisecond):
+	}
+	if handled != 1 {
+		t.Errorf("handler should be called only once, but called %d times", handled)
+	}
+}
+
+func TestRetransmittedRequestNotCached(t *testing.T) {
+	cliConn, err := net.ListenPacket("udp", "127.0.0.105:2123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cliConn.Close()
+	errCh := make(chan error, 1)
+	srvConn, err := v2.ListenAndServe(&net.UDPAddr{IP: net.IPv4(127, 0, 0, 106), Port: 2123}, 0, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+	srvConn.DisableValidation()
+
+	// Delete Session Request is responded with WriteTo, which is not cached.
+	handled := make(chan struct{}, 3)
+	srvConn.AddHandler(messages.MsgTypeDeleteSessionRequest, func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+		handled <- struct{}{}
+		b, err := messages.NewDeleteSessionResponse(
+			0, msg.Sequence(), ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+		).Marshal()
+		if err != nil {
+			return err
+		}
+		_, err = c.WriteTo(b, senderAddr)
+		return err
+	})
+	// Modify Bearer Request is deferred with ErrPending and left in progress.
+	txCh := make(chan *v2.Transaction, 2)
+	srvConn.AddHandler(messages.MsgTypeModifyBearerRequest, func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+		txCh <- c.Defer(senderAddr, msg, time.Second)
+		return v2.ErrPending
+	})
+
+	b, err := messages.NewDeleteSessionRequest(0, 0x100, ies.NewEPSBearerID(5)).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1500)
+	for i := 0; i < 2; i++ {
+		if _, err := cliConn.WriteTo(b, srvConn.LocalAddr()); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case <-handled:
+		case err := <-errCh:
+			t.Fatal(err)
+		case <-time.After(time.Second):
+			t.Fatalf("request not handled, sent %d times", i+1)
+		}
+
+		if err := cliConn.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
+			t.Fatal(err)
+		}
+		n, _, err := cliConn.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg, err := messages.Parse(buf[:n])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if msg.MessageType() != messages.MsgTypeDeleteSessionResponse {
+			t.Fatalf("unexpected message: %s", msg.MessageTypeName())
+		}
+		// waiting for the HandlerFunc to return.
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	b, err = messages.NewModifyBearerRequest(0, 0x200, ies.NewEPSBearerID(5)).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cliConn.WriteTo(b, srvConn.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+	var tx *v2.Transaction
+	select {
+	case tx = <-txCh:
+	case <-time.After(time.Second):
+		t.Fatal("Modify Bearer Request not handled")
+	}
+	if _, err := cliConn.WriteTo(b, srvConn.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-txCh:
+		t.Error("retransmitted request handled while the response is pending")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if err := tx.Respond(messages.NewModifyBearerResponse(0, 0, ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil))); err != nil {
+		t.Fatal(err)
+	}
+	if err := cliConn.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	n, _, err := cliConn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := messages.Parse(buf[:n])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.MessageType() != messages.MsgTypeModifyBearerResponse {
+		t.Fatalf("unexpected message: %s", msg.MessageTypeName())
+	}
+	select {
+	case err := <-errCh:
+		t.Error(err)
+	default:
+	}
+}
+
+func TestPeerLimit(t *testing.T) {
+	conn, err := v2.ListenAndServe(&net.UDPAddr{IP: net.IPv4(127, 0, 0, 102), Port: 2123}, 0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// each Echo Request from a new address creates a Peer.
+	b, err := messages.NewEchoRequest(0, ies.NewRecovery(0)).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addrOf := func(i int) *net.UDPAddr {
+		return &net.UDPAddr{IP: net.IPv4(127, 2, byte(i>>8), byte(i)), Port: 2123}
+	}
+	waitPeer := func(i int) {
+		t.Helper()
+		deadline := time.Now().Add(3 * time.Second)
+		for {
+			if _, err := conn.GetPeer(addrOf(i)); err == nil {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("timed out while waiting for the Echo Request to be handled")
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	const n = 5000
+	for i := 0; i < n; i++ {
+		pc, err := net.ListenPacket("udp", addrOf(i).String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = pc.WriteTo(b, conn.LocalAddr())
+		pc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// not to overflow the socket buffer.
+		if i%128 == 127 || i == n-1 {
+			waitPeer(i)
+		}
+	}
+
+	if got := len(conn.Peers()); got > 4096 {
+		t.Errorf("Peers grow without limit: %d", got)
+	}
+	if _, err := conn.GetPeer(addrOf(0)); err == nil {
+		t.Error("the least recently active Peer is not removed")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var (
+		rspSent = make(chan struct{})
+		errCh   = make(chan error)
+		calls   = make(chan string, 4)
+	)
+	cliConn, srvConn, err := setup(rspSent, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { cliConn.Close(); srvConn.Close() }()
+
+	for _, name := range []string{"first", "second"} {
+		name := name
+		srvConn.Use(func(next v2.HandlerFunc) v2.HandlerFunc {
+			return func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+				if msg.MessageType() == messages.MsgTypeCreateSessionRequest {
+					calls <- name
+				}
+				return next(c, senderAddr, msg)
+			}
+		})
+	}
+
+	if _, _, err := cliConn.CreateSession(srvConn.LocalAddr(), ies.NewIMSI("123451234567890")); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-rspSent:
+		for _, want := range []string{"first", "second"} {
+			if got := <-calls; got != want {
+				t.Errorf("wrong order of Middleware. want: %s, got: %s", want, got)
+			}
+		}
+	case err := <-errCh:
+		t.Fatal(err)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out while waiting for Create Session Request to be handled")
+	}
+}
+
+func TestDeferredResponse(t *testing.T) {
+	var (
+		rspSent = make(chan struct{})
+		rspGot  = make(chan struct{})
+		errCh   = make(chan error)
+	)
+	cliConn, srvConn, err := setup(rspSent, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { cliConn.Close(); srvConn.Close() }()
+
+	txCh := make(chan *v2.Transaction, 1)
+	srvConn.AddHandler(
+		messages.MsgTypeDeleteSessionRequest,
+		func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			txCh <- c.Defer(senderAddr, msg, time.Second)
+			return v2.ErrPending
+		},
+	)
+	cliConn.AddHandler(
+		messages.MsgTypeDeleteSessionResponse,
+		func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			rspGot <- struct{}{}
+			return nil
+		},
+	)
+
+	if _, err := cliConn.SendMessageTo(messages.NewDeleteSessionRequest(0, 0), srvConn.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+
+	var tx *v2.Transaction
+	select {
+	case tx = <-txCh:
+	case err := <-errCh:
+		t.Fatal(err)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out while waiting for Delete Session Request to be handled")
+	}
+
+	res := messages.NewDeleteSessionResponse(0, 0, ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil))
+	if err := tx.Respond(res); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Respond(res); err != v2.ErrTransactionCompleted {
+		t.Errorf("second Respond should fail with ErrTransactionCompleted, got: %v", err)
+	}
+
+	select {
+	case <-rspGot:
+	case err := <-errCh:
+		t.Fatal(err)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out while waiting for Delete Session Response")
+	}
+
+	// the one not responded in time is expired and the late response is suppressed.
+	if _, err := cliConn.SendMessageTo(messages.NewDeleteSessionRequest(0, 0), srvConn.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+	tx = <-txCh
+	select {
+	case err := <-errCh:
+		if _, ok := err.(*v2.TransactionExpiredError); !ok {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out while waiting for Transaction to be expired")
+	}
+	if err := tx.Respond(res); err == nil {
+		t.Error("Respond should fail after Transaction is expired")
+	}
+}
+
+func TestStats(t *testing.T) {
+	var (
+		rspSent = make(chan struct{})
+		errCh   = make(chan error)
+	)
+	cliConn, srvConn, err := setup(rspSent, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { cliConn.Close(); srvConn.Close() }()
+
+	if _, _, err := cliConn.CreateSession(srvConn.LocalAddr(), ies.NewIMSI("123451234567890")); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-rspSent:
+	case err := <-errCh:
+		t.Fatal(err)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out while waiting for Create Session Request to be handled")
+	}
+
+	cliStats := cliConn.Stats()
+	if n := cliStats.MessagesSent[messages.MsgTypeCreateSessionRequest]; n != 1 {
+		t.Errorf("wrong number of Create Session Request sent. want: 1, got: %d", n)
+	}
+	if _, ok := cliStats.EchoRTT[srvConn.LocalAddr().String()]; !ok {
+		t.Error("EchoRTT should be measured with Echo in Dial")
+	}
+
+	srvStats := srvConn.Stats()
+	if n := srvStats.MessagesReceived[messages.MsgTypeCreateSessionRequest]; n != 1 {
+		t.Errorf("wrong number of Create Session Request received. want: 1, got: %d", n)
+	}
+	if n := srvStats.MessagesSent[messages.MsgTypeCreateSessionResponse]; n != 1 {
+		t.Errorf("wrong number of Create Session Response sent. want: 1, got: %d", n)
+	}
+	if srvStats.Sessions != 1 {
+		t.Errorf("wrong number of Sessions. want: 1, got: %d", srvStats.Sessions)
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	var (
+		rspSent = make(chan struct{})
+		errCh   = make(chan error)
+	)
+	cliConn, srvConn, err := setup(rspSent, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { cliConn.Close(); srvConn.Close() }()
+
+	parseErrCh := make(chan error, 1)
+	srvConn.SetErrorHandler(func(err error) {
+		parseErrCh <- err
+	})
+	srvConn.SetParseLimits(&messages.Limits{MaxIEs: 1})
+
+	if _, _, err := cliConn.CreateSession(
+		srvConn.LocalAddr(), ies.NewIMSI("123451234567890"), ies.NewMSISDN("123450123456789"),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-parseErrCh:
+		if _, ok := errors.Cause(err).(*messages.TooManyIEsError); !ok {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-rspSent:
+		t.Fatal("message exceeding the limits should not be handled")
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out while waiting for the message to be discarded")
+	}
+
+	if n := srvConn.Stats().LimitViolations; n != 1 {
+		t.Errorf("wrong number of LimitViolations. want: 1, got: %d", n)
+	}
+}
+
+type testLogger struct {
+	mu      sync.Mutex
+	entries []string
+}
+
+func (l *testLogger) add(level, msg string, keysAndValues []interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = append(l.entries, fmt.Sprint(level, " ", msg, " ", keysAndValues))
+}
+
+func (l *testLogger) Debug(msg string, kv ...interface{}) { l.add("DEBUG", msg, kv) }
+func (l *testLogger) Info(msg string, kv ...interface{})  { l.add("INFO", msg, kv) }
+func (l *testLogger) Warn(msg string, kv ...interface{})  { l.add("WARN", msg, kv) }
+func (l *testLogger) Error(msg string, kv ...interface{}) { l.add("ERROR", msg, kv) }
+
+func (l *testLogger) contains(s string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, e := range l.entries {
+		if strings.Contains(e, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestLogger(t *testing.T) {
+	var (
+		rspSent = make(chan struct{})
+		errCh   = make(chan error)
+	)
+	cliConn, srvConn, err := setup(rspSent, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { cliConn.Close(); srvConn.Close() }()
+
+	l := &testLogger{}
+	srvConn.SetLogger(l)
+
+	if _, _, err := cliConn.CreateSession(srvConn.LocalAddr(), ies.NewIMSI("123451234567890")); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-rspSent:
+	case err := <-errCh:
+		t.Fatal(err)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out while waiting for Create Session Request to be handled")
+	}
+
+	for _, want := range []string{
+		"DEBUG received message [msg_type Create Session Request peer 127.0.0.1:2123 teid 0x00000000 seq",
+		"DEBUG sent message [msg_type Create Session Response peer 127.0.0.1:2123",
+	} {
+		if !l.contains(want) {
+			t.Errorf("log not found: %s", want)
+		}
+	}
+}
+
+func TestIdentification(t *testing.T) {
+	var (
+		rspSent = make(chan struct{})
+		rspGot  = make(chan string)
+		errCh   = make(chan error)
+	)
+	cliConn, srvConn, err := setup(rspSent, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { cliConn.Close(); srvConn.Close() }()
+
+	srvConn.AddHandler(
+		messages.MsgTypeIdentificationRequest,
+		func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			return c.IdentificationResponse(
+				msg.TEID(), senderAddr, msg,
+				ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+				ies.NewIMSI("123451234567890"),
+			)
+		},
+	)
+	cliConn.AddHandler(
+		messages.MsgTypeIdentificationResponse,
+		func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			res := msg.(*messages.IdentificationResponse)
+			imsi, err := res.IMSI.IMSI()
+			if err != nil {
+				return err
+			}
+			rspGot <- imsi
+			return nil
+		},
+	)
+
+	if _, err := cliConn.IdentificationRequest(
+		0, srvConn.LocalAddr(),
+		ies.NewGUTI("123", "45", 0x0123, 0x45, 0xdeadbeef),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case imsi := <-rspGot:
+		if imsi != "123451234567890" {
+			t.Errorf("wrong IMSI: got %s", imsi)
+		}
+	case err := <-errCh:
+		t.Fatal(err)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out while waiting for Identification Response")
+	}
+}
+
+func TestRejectionHandler(t *testing.T) {
+	var (
+		rspSent  = make(chan struct{})
+		rejected = make(chan *v2.CauseError, 1)
+		errCh    = make(chan error)
+	)
+	cliConn, srvConn, err := setup(rspSent, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { cliConn.Close(); srvConn.Close() }()
+
+	srvConn.AddHandler(
+		messages.MsgTypeIdentificationRequest,
+		func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			return c.IdentificationResponse(
+				msg.TEID(), senderAddr, msg,
+				ies.NewCause(v2.CauseContextNotFound, 0, 1, 1, ies.NewGUTI("123", "45", 0x0123, 0x45, 0xdeadbeef)),
+			)
+		},
+	)
+	cliConn.AddHandler(
+		messages.MsgTypeIdentificationResponse,
+		func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			t.Error("rejected response should not be passed to the HandlerFunc")
+			return nil
+		},
+	)
+	cliConn.SetRejectionHandler(func(c *v2.Conn, senderAddr net.Addr, req, rsp messages.Message, err *v2.CauseError) error {
+		if req.MessageType() != messages.MsgTypeIdentificationRequest || req.Sequence() != rsp.Sequence() {
+			t.Errorf("unexpected request: %v", req)
+		}
+		rejected <- err
+		return nil
+	})
+
+	if _, err := cliConn.IdentificationRequest(
+		0, srvConn.LocalAddr(),
+		ies.NewGUTI("123", "45", 0x0123, 0x45, 0xdeadbeef),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-rejected:
+		want := &v2.CauseError{
+			MsgType:         "Identification Response",
+			Cause:           v2.Cause(v2.CauseContextNotFound),
+			BCE:             true,
+			CS:              true,
+			OffendingIEType: ies.GUTI,
+		}
+		if *err != *want {
+			t.Errorf("unexpected CauseError: got %+v, want %+v", err, want)
+		}
+	case err := <-errCh:
+		t.Fatal(err)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out while waiting for the rejection")
+	}
+}
+
+func TestCheckCause(t *testing.T) {
+	accepted := messages.NewIdentificationResponse(0, 1, ies.NewCause(v2.CauseRequestAcceptedPartially, 0, 0, 0, nil))
+	if err := v2.CheckCause(accepted); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	rejected := messages.NewIdentificationResponse(0, 1, ies.NewCause(v2.CauseSystemFailure, 1, 0, 0, nil))
+	err := v2.CheckCause(rejected)
+	if e, ok := err.(*v2.CauseError); !ok || e.Cause != v2.Cause(v2.CauseSystemFailure) || !e.PCE || e.BCE || e.CS {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if _, ok := v2.CheckCause(messages.NewEchoResponse(1)).(*v2.RequiredIEMissingError); !ok {
+		t.Error("RequiredIEMissingError should be returned for the message without Cause")
+	}
+}
+
+func TestSessionHandler(t *testing.T) {
+	var (
+		rspSent = make(chan struct{})
+		errCh   = make(chan error)
+		handled = make(chan string)
+	)
+	cliConn, srvConn, err := setup(rspSent, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { cliConn.Close(); srvConn.Close() }()
+
+	sess := v2.NewSession(srvConn.LocalAddr(), &v2.Subscriber{IMSI: "123451234567890"})
+	sess.AddTEID(v2.IFTypeS11MMEGTPC, 0x11111111)
+	cliConn.AddSession(sess)
+	other := v2.NewSession(srvConn.LocalAddr(), &v2.Subscriber{IMSI: "123451234567891"})
+	other.AddTEID(v2.IFTypeS11MMEGTPC, 0x22222222)
+	cliConn.AddSession(other)
+
+	cliConn.AddHandler(
+		messages.MsgTypeDeleteBearerRequest,
+		func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			handled <- "conn"
+			return nil
+		},
+	)
+	sess.AddHandler(
+		messages.MsgTypeDeleteBearerRequest,
+		func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			handled <- "session"
+			return nil
+		},
+	)
+
+	for _, c := range []struct {
+		description string
+		teid        uint32
+		removed     bool
+		want        string
+	}{
+		{"Session", 0x11111111, false, "session"},
+		{"OtherSession", 0x22222222, false, "conn"},
+		{"Removed", 0x11111111, true, "conn"},
+	} {
+		if c.removed {
+			sess.RemoveHandler(messages.MsgTypeDeleteBearerRequest)
+		}
+		if _, err := srvConn.SendMessageTo(
+			messages.NewDeleteBearerRequest(c.teid, 0, ies.NewEPSBearerID(5)), cliConn.LocalAddr(),
+		); err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case got := <-handled:
+			if got != c.want {
+				t.Errorf("%s: handled by wrong HandlerFunc. want: %s, got: %s", c.description, c.want, got)
+			}
+		case err := <-errCh:
+			t.Fatal(err)
+		case <-time.After(3 * time.Second):
+			t.Fatalf("%s: timed out while waiting for Delete Bearer Request to be handled", c.description)
+		}
+	}
+}
+
+func TestDownlinkDataNotificationDelay(t *testing.T) {
+	var (
+		rspSent = make(chan struct{})
+		ackGot  = make(chan struct{})
+		errCh   = make(chan error)
+	)
+	cliConn, srvConn, err := setup(rspSent, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { cliConn.Close(); srvConn.Close() }()
+
+	// the same TEID is used on both sides for simplicity.
+	for _, c := range []*v2.Conn{cliConn, srvConn} {
+		peer := srvConn.LocalAddr()
+		if c == srvConn {
+			peer = cliConn.LocalAddr()
+		}
+		sess := v2.NewSession(peer, &v2.Subscriber{IMSI: "123451234567890"})
+		sess.AddTEID(v2.IFTypeS11S4SGWGTPC, 0x11111111)
+		c.AddSession(sess)
+	}
+
+	srvConn.AddHandler(
+		messages.MsgTypeDownlinkDataNotification,
+		func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			ddn := msg.(*messages.DownlinkDataNotification)
+			if ppi, err := ddn.PagingAndServiceInformation.PagingPolicyIndication(); err != nil || ppi != 0x0a {
+				return errors.Errorf("unexpected PPI: %d, %v", ppi, err)
+			}
+			return c.DownlinkDataNotificationAcknowledge(
+				msg.TEID(), senderAddr, msg,
+				ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+				ies.NewDelayValue(time.Second),
+			)
+		},
+	)
+	cliConn.AddHandler(
+		messages.MsgTypeDownlinkDataNotificationAcknowledge,
+		func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			ackGot <- struct{}{}
+			return nil
+		},
+	)
+
+	if _, err := cliConn.DownlinkDataNotification(
+		0x11111111, srvConn.LocalAddr(),
+		ies.NewEPSBearerID(5),
+		ies.NewAllocationRetensionPriority(1, 2, 1),
+		ies.NewPagingAndServiceInformation(5, 1, 0x0a),
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ackGot:
+	case err := <-errCh:
+		t.Fatal(err)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out while waiting for Downlink Data Notification Acknowledge")
+	}
+
+	sess, err := cliConn.GetSessionByTEID(0x11111111, srvConn.LocalAddr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := sess.DownlinkDataNotificationDelay(); d <= 0 || d > time.Second {
+		t.Errorf("wrong delay: %s", d)
+	}
+	if _, err := cliConn.DownlinkDataNotification(0x11111111, srvConn.LocalAddr()); err != v2.ErrNotificationDelayed {
+		t.Errorf("notification should be delayed, got: %v", err)
+	}
+}
+
+func TestProbePathMTU(t *testing.T) {
+	var (
+		rspSent = make(chan struct{})
+		errCh   = make(chan error)
+	)
+	cliConn, srvConn, err := setup(rspSent, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { cliConn.Close(); srvConn.Close() }()
+
+	got, err := cliConn.ProbePathMTU(srvConn.LocalAddr(), 0, 548, 1472)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 1472 {
+		t.Errorf("wrong path MTU. want: 1472, got: %d", got)
+	}
+
+	peer, err := cliConn.GetPeer(srvConn.LocalAddr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mtu, ok := peer.PathMTU(); !ok || mtu != 1472 {
+		t.Errorf("wrong path MTU in Peer. want: 1472, got: %d, %v", mtu, ok)
+	}
+}
+
+func TestHandleSuspendResume(t *testing.T) {
+	var (
+		rspSent  = make(chan struct{})
+		changed  = make(chan bool)
+		causeGot = make(chan uint8)
+		errCh    = make(chan error)
+		cliTEID  = uint32(0x11111111)
+		srvTEID  = uint32(0x22222222)
+	)
+	cliConn, srvConn, err := setup(rspSent, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { cliConn.Close(); srvConn.Close() }()
+
+	cliSess := v2.NewSession(srvConn.LocalAddr(), &v2.Subscriber{IMSI: "123451234567890"})
+	cliSess.AddTEID(v2.IFTypeS11S4SGWGTPC, srvTEID)
+	cliSess.AddTEID(v2.IFTypeS11MMEGTPC, cliTEID)
+	cliConn.AddSession(cliSess)
+
+	srvSess := v2.NewSession(cliConn.LocalAddr(), &v2.Subscriber{IMSI: "123451234567890"})
+	srvSess.AddTEID(v2.IFTypeS11S4SGWGTPC, srvTEID)
+	srvSess.AddTEID(v2.IFTypeS11MMEGTPC, cliTEID)
+	srvConn.AddSession(srvSess)
+
+	srvConn.HandleSuspendResume(v2.IFTypeS11MMEGTPC, func(sess *v2.Session, suspended bool) {
+		changed <- suspended
+	})
+	cliConn.HandleSuspendAcknowledge(func(c *v2.Conn, senderAddr net.Addr, msg *messages.SuspendAcknowledge) error {
+		if msg.TEID() != cliTEID {
+			return errors.Errorf("unexpected TEID: %#x", msg.TEID())
+		}
+		causeGot <- msg.Cause.MustCause()
+		return nil
+	})
+	cliConn.HandleResumeAcknowledge(func(c *v2.Conn, senderAddr net.Addr, msg *messages.ResumeAcknowledge) error {
+		return nil
+	})
+
+	if _, err := cliConn.SuspendNotification(srvTEID, srvConn.LocalAddr(), ies.NewEPSBearerID(5)); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case suspended := <-changed:
+		if !suspended || !srvSess.IsSuspended() {
+			t.Error("Session should be suspended")
+		}
+	case err := <-errCh:
+		t.Fatal(err)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out while waiting for Suspend Notification to be handled")
+	}
+	select {
+	case cause := <-causeGot:
+		if cause != v2.CauseRequestAccepted {
+			t.Errorf("wrong Cause: %d", cause)
+		}
+	case err := <-errCh:
+		t.Fatal(err)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out while waiting for Suspend Acknowledge")
+	}
+
+	if _, err := cliConn.ResumeNotification(srvTEID, srvConn.LocalAddr(), ies.NewEPSBearerID(5)); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case suspended := <-changed:
+		if suspended || srvSess.IsSuspended() {
+			t.Error("Session should be resumed")
+		}
+	case err := <-errCh:
+		t.Fatal(err)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out while waiting for Resume Notification to be handled")
+	}
+}
+
+func TestISR(t *testing.T) {
+	var (
+		rspSent = make(chan struct{})
+		isrGot  = make(chan bool)
+		stopped = make(chan string)
+		errCh   = make(chan error)
+		cliTEID = uint32(0x11111111)
+		srvTEID = uint32(0x22222222)
+	)
+	cliConn, srvConn, err := setup(rspSent, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { cliConn.Close(); srvConn.Close() }()
+
+	cliSess := v2.NewSession(srvConn.LocalAddr(), &v2.Subscriber{IMSI: "123451234567890"})
+	cliSess.AddTEID(v2.IFTypeS11S4SGWGTPC, srvTEID)
+	cliSess.AddTEID(v2.IFTypeS11MMEGTPC, cliTEID)
+	cliConn.AddSession(cliSess)
+
+	srvSess := v2.NewSession(cliConn.LocalAddr(), &v2.Subscriber{IMSI: "123451234567890"})
+	srvSess.AddTEID(v2.IFTypeS11S4SGWGTPC, srvTEID)
+	srvSess.AddTEID(v2.IFTypeS11MMEGTPC, cliTEID)
+	srvConn.AddSession(srvSess)
+
+	srvConn.HandleModifyBearerRequest(func(c *v2.Conn, senderAddr net.Addr, msg *messages.ModifyBearerRequest) error {
+		isrGot <- srvSess.IsISRActive()
+		return c.RespondTo(senderAddr, msg, messages.NewModifyBearerResponse(
+			cliTEID, 0, ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+		))
+	})
+	cliConn.HandleModifyBearerResponse(func(c *v2.Conn, senderAddr net.Addr, msg *messages.ModifyBearerResponse) error {
+		return nil
+	})
+	cliConn.HandleStopPagingIndication(func(c *v2.Conn, senderAddr net.Addr, msg *messages.StopPagingIndication) error {
+		stopped <- msg.IMSI.MustIMSI()
+		return nil
+	})
+
+	if _, err := srvConn.StopPagingIndication(cliTEID, cliConn.LocalAddr()); err != v2.ErrISRNotActive {
+		t.Fatalf("StopPagingIndication should fail when ISR is not active. got: %v", err)
+	}
+
+	for _, israi := range []uint8{1, 0} {
+		ind := ies.NewIndicationFromOctets(israi<<1, 0x00)
+		if _, err := cliConn.ModifyBearer(srvTEID, srvConn.LocalAddr(), ind); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case active := <-isrGot:
+			if active != (israi == 1) {
+				t.Errorf("wrong ISR state with ISRAI=%d: %t", israi, active)
+			}
+		case err := <-errCh:
+			t.Fatal(err)
+		case <-time.After(3 * time.Second):
+			t.Fatal("timed out while waiting for Modify Bearer Request to be handled")
+		}
+
+		if israi == 0 {
+			break
+		}
+		if _, err := srvConn.StopPagingIndication(cliTEID, cliConn.LocalAddr(), ies.NewIMSI("123451234567890")); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case imsi := <-stopped:
+			if imsi != "123451234567890" {
+				t.Errorf("wrong IMSI in Stop Paging Indication: %s", imsi)
+			}
+		case err := <-errCh:
+			t.Fatal(err)
+		case <-time.After(3 * time.Second):
+			t.Fatal("timed out while waiting for Stop Paging Indication")
+		}
+	}
+}
+
+func TestSwitchDownlinkPath(t *testing.T) {
+	var (
+		rspSent = make(chan struct{})
+		fteidCh = make(chan *ies.IE)
+		errCh   = make(chan error)
+		cliTEID = uint32(0x11111111)
+		srvTEID = uint32(0x22222222)
+	)
+	cliConn, srvConn, err := setup(rspSent, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { cliConn.Close(); srvConn.Close() }()
+
+	cliSess := v2.NewSession(srvConn.LocalAddr(), &v2.Subscriber{IMSI: "123451234567890"})
+	cliSess.AddTEID(v2.IFTypeS11S4SGWGTPC, srvTEID)
+	cliSess.AddTEID(v2.IFTypeS11MMEGTPC, cliTEID)
+	br := cliSess.GetDefaultBearer()
+	br.EBI = 5
+	br.SetOutgoingTEID(0x33333333)
+	br.SetRemoteAddress(&net.UDPAddr{IP: net.IPv4(1, 1, 1, 1), Port: v2.GTPUPort})
+	cliConn.AddSession(cliSess)
+
+	srvSess := v2.NewSession(cliConn.LocalAddr(), &v2.Subscriber{IMSI: "123451234567890"})
+	srvSess.AddTEID(v2.IFTypeS11S4SGWGTPC, srvTEID)
+	srvSess.AddTEID(v2.IFTypeS11MMEGTPC, cliTEID)
+	srvConn.AddSession(srvSess)
+
+	srvConn.HandleModifyBearerRequest(func(c *v2.Conn, senderAddr net.Addr, msg *messages.ModifyBearerRequest) error {
+		if msg.TEID() != srvTEID {
+			return errors.Errorf("unexpected TEID: %#x", msg.TEID())
+		}
+		for _, ie := range msg.BearerContextsToBeModified.ChildIEs {
+			if ie.Type == ies.FullyQualifiedTEID {
+				fteidCh <- ie
+			}
+		}
+		return c.RespondTo(senderAddr, msg, messages.NewModifyBearerResponse(
+			cliTEID, 0, ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+		))
+	})
+	cliConn.HandleModifyBearerResponse(func(c *v2.Conn, senderAddr net.Addr, msg *messages.ModifyBearerResponse) error {
+		return nil
+	})
+
+	type oldPath struct {
+		addr string
+		teid uint32
+	}
+	endMarkerCh := make(chan oldPath, 1)
+	cliConn.SetEndMarkerFunc(func(sess *v2.Session, br *v2.Bearer, oldAddr net.Addr, oldTEID uint32) error {
+		endMarkerCh <- oldPath{oldAddr.String(), oldTEID}
+		return nil
+	})
+
+	newFTEID := ies.NewFullyQualifiedTEID(v2.IFTypeS1UeNodeBGTPU, 0x44444444, "2.2.2.2", "")
+	if _, err := cliSess.SwitchDownlinkPath(cliConn, 5, newFTEID); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case old := <-endMarkerCh:
+		if old.addr != "1.1.1.1:2152" || old.teid != 0x33333333 {
+			t.Errorf("wrong old path: %+v", old)
+		}
+	default:
+		t.Error("EndMarkerFunc should be called")
+	}
+	if br.OutgoingTEID() != 0x44444444 || br.RemoteAddress().String() != "2.2.2.2:2152" {
+		t.Errorf("Bearer not switched: %x, %v", br.OutgoingTEID(), br.RemoteAddress())
+	}
+	if teid, err := cliSess.GetTEID(v2.IFTypeS1UeNodeBGTPU); err != nil || teid != 0x44444444 {
+		t.Errorf("wrong S1-U eNodeB F-TEID: %x, %v", teid, err)
+	}
+
+	select {
+	case fteid := <-fteidCh:
+		if teid := fteid.MustTEID(); teid != 0x44444444 {
+			t.Errorf("wrong F-TEID sent: %#x", teid)
+		}
+	case err := <-errCh:
+		t.Fatal(err)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out while waiting for Modify Bearer Request")
+	}
+
+	if _, err := cliSess.SwitchDownlinkPath(
+		cliConn, 5, ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 0x55555555, "2.2.2.2", ""),
+	); err == nil {
+		t.Error("F-TEID not on the access side should be rejected")
+	}
+}
+
+func TestLoadControl(t *testing.T) {
+	var (
+		rspSent = make(chan struct{})
+		rspGot  = make(chan struct{})
+		errCh   = make(chan error)
+	)
+	cliConn, srvConn, err := setup(rspSent, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { cliConn.Close(); srvConn.Close() }()
+
+	cliConn.EnableLoadControl()
+	srvConn.SetLocalLoadControlInformation(
+		[]uint8{messages.MsgTypeIdentificationResponse},
+		ies.NewLoadControlInformation(1, 80),
+		ies.NewOverloadControlInformation(1, 50, time.Minute),
+		ies.NewOverloadControlInformation(1, 100, time.Minute, ies.NewAccessPointName("some.apn")).WithInstance(1),
+	)
+
+	srvConn.AddHandler(
+		messages.MsgTypeIdentificationRequest,
+		func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			return c.IdentificationResponse(
+				msg.TEID(), senderAddr, msg,
+				ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+			)
+		},
+	)
+	cliConn.AddHandler(
+		messages.MsgTypeIdentificationResponse,
+		func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			rspGot <- struct{}{}
+			return nil
+		},
+	)
+
+	if _, err := cliConn.IdentificationRequest(0, srvConn.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-rspGot:
+	case err := <-errCh:
+		t.Fatal(err)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out while waiting for Identification Response")
+	}
+
+	peer, err := cliConn.GetPeer(srvConn.LocalAddr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metric, ok := peer.LoadMetric("some.apn"); !ok || metric != 80 {
+		t.Errorf("wrong Load Metric: %d, %v", metric, ok)
+	}
+
+	cases := []struct {
+		apn      string
+		priority uint8
+		throttle bool
+	}{
+		{"", 0, false},
+		{"", 7, false},
+		{"", 8, true},
+		{"", 15, true},
+		{"other.apn", 7, false},
+		{"some.apn", 0, true},
+	}
+	for _, c := range cases {
+		if got := cliConn.ShouldThrottle(srvConn.LocalAddr(), c.apn, c.priority); got != c.throttle {
+			t.Errorf("wrong decision for %q with priority %d: %v", c.apn, c.priority, got)
+		}
+	}
+
+	if srvConn.ShouldThrottle(cliConn.LocalAddr(), "", 15) {
+		t.Error("should not throttle the peer not in overload")
+	}
+}
+
+func TestLoadControlFunc(t *testing.T) {
+	var (
+		rspSent = make(chan struct{})
+		rspGot  = make(chan struct{})
+		errCh   = make(chan error)
+	)
+	cliConn, srvConn, err := setup(rspSent, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { cliConn.Close(); srvConn.Close() }()
+
+	cliConn.EnableLoadControl()
+	srvConn.SetLocalLoadControlInformation(
+		[]uint8{messages.MsgTypeIdentificationResponse},
+		ies.NewOverloadControlInformation(1, 50, time.Minute),
+	)
+
+	var seq uint32
+	srvConn.SetLoadControlFunc(func(raddr net.Addr, msg messages.Message) []*ies.IE {
+		if msg.MessageType() != messages.MsgTypeIdentificationResponse {
+			return nil
+		}
+		if raddr.String() != cliConn.LocalAddr().String() {
+			errCh <- fmt.Errorf("wrong raddr: %s", raddr)
+		}
+		seq++
+		return []*ies.IE{
+			ies.NewLoadControlInformation(seq, uint8(10*seq)),
+			// ignored, as the one set statically is attached.
+			ies.NewOverloadControlInformation(seq, 100, time.Minute),
+		}
+	})
+
+	srvConn.AddHandler(
+		messages.MsgTypeIdentificationRequest,
+		func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			return c.IdentificationResponse(
+				msg.TEID(), senderAddr, msg,
+				ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+			)
+		},
+	)
+	cliConn.AddHandler(
+		messages.MsgTypeIdentificationResponse,
+		func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			rspGot <- struct{}{}
+			return nil
+		},
+	)
+
+	for i := 0; i < 2; i++ {
+		if _, err := cliConn.IdentificationRequest(0, srvConn.LocalAddr()); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case <-rspGot:
+		case err := <-errCh:
+			t.Fatal(err)
+		case <-time.After(3 * time.Second):
+			t.Fatal("timed out while waiting for Identification Response")
+		}
+	}
+
+	peer, err := cliConn.GetPeer(srvConn.LocalAddr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metric, ok := peer.LoadMetric(""); !ok || metric != 20 {
+		t.Errorf("wrong Load Metric: %d, %v", metric, ok)
+	}
+	if metric, ok := peer.OverloadReductionMetric(""); !ok || metric != 50 {
+		t.Errorf("wrong Overload Reduction Metric: %d, %v", metric, ok)
+	}
+}
+
+func TestAdmissionControl(t *testing.T) {
+	var (
+		rspSent = make(chan struct{})
+		causes  = make(chan uint8)
+		errCh   = make(chan error)
+	)
+	cliConn, srvConn, err := setup(rspSent, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { cliConn.Close(); srvConn.Close() }()
+
+	srvConn.SetAdmissionControl(&v2.AdmissionControl{PeerRate: 0.001, PeerBurst: 1})
+	srvConn.AddHandler(
+		messages.MsgTypeIdentificationRequest,
+		func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			return c.IdentificationResponse(
+				msg.TEID(), senderAddr, msg,
+				ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+			)
+		},
+	)
+	cliConn.AddHandler(
+		messages.MsgTypeIdentificationResponse,
+		func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			rsp, ok := msg.(*messages.IdentificationResponse)
+			if !ok {
+				return &v2.UnexpectedTypeError{Msg: msg}
+			}
+			causes <- rsp.Cause.MustCause()
+			return nil
+		},
+	)
+
+	for _, want := range []uint8{v2.CauseRequestAccepted, v2.CauseNoResourcesAvailable} {
+		if _, err := cliConn.IdentificationRequest(0, srvConn.LocalAddr()); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case got := <-causes:
+			if got != want {
+				t.Errorf("wrong Cause. want: %d, got: %d", want, got)
+			}
+		case err := <-errCh:
+			t.Fatal(err)
+		case <-time.After(3 * time.Second):
+			t.Fatal("timed out while waiting for Identification Response")
+		}
+	}
+
+	srvConn.SetAdmissionControl(&v2.AdmissionControl{Rate: 0.001, Action: v2.AdmissionActionDiscard})
+	for n := 0; n < 2; n++ {
+		if _, err := cliConn.IdentificationRequest(0, srvConn.LocalAddr()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	select {
+	case got := <-causes:
+		if got != v2.CauseRequestAccepted {
+			t.Errorf("wrong Cause: %d", got)
+		}
+	case err := <-errCh:
+		t.Fatal(err)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out while waiting for Identification Response")
+	}
+	select {
+	case got := <-causes:
+		t.Errorf("request over the limit should be discarded, got Cause: %d", got)
+	case <-time.After(500 * time.Millisecond):
+	}
+
+	if n := srvConn.Stats().Throttled; n != 2 {
+		t.Errorf("wrong number of throttled requests: %d", n)
+	}
+}
+
+func TestWorkerPool(t *testing.T) {
+	var (
+		rspSent = make(chan struct{})
+		doneCh  = make(chan struct{})
+		errCh   = make(chan error)
+	)
+	cliConn, srvConn, err := setup(rspSent, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { cliConn.Close(); srvConn.Close() }()
+
+	const count = 50
+	var (
+		mu   sync.Mutex
+		seqs []uint32
+	)
+	srvConn.SetWorkerPool(&v2.WorkerPool{Workers: 4})
+	srvConn.AddHandler(
+		messages.MsgTypeIdentificationRequest,
+		func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			mu.Lock()
+			seqs = append(seqs, msg.Sequence())
+			if len(seqs) == count {
+				close(doneCh)
+			}
+			mu.Unlock()
+
+			return c.IdentificationResponse(
+				msg.TEID(), senderAddr, msg,
+				ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+			)
+		},
+	)
+	cliConn.AddHandler(
+		messages.MsgTypeIdentificationResponse,
+		func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			return nil
+		},
+	)
+
+	for n := 0; n < count; n++ {
+		if _, err := cliConn.IdentificationRequest(0, srvConn.LocalAddr()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	select {
+	case <-doneCh:
+	case err := <-errCh:
+		t.Fatal(err)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out while waiting for Identification Requests")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for n := 1; n < len(seqs); n++ {
+		if seqs[n] <= seqs[n-1] {
+			t.Fatalf("messages handled out of order: %v", seqs)
+		}
+	}
+}
+
+func TestBatchSize(t *testing.T) {
+	var (
+		rspSent = make(chan struct{})
+		causes  = make(chan uint8)
+		errCh   = make(chan error)
+	)
+	cliConn, srvConn, err := setup(rspSent, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { cliConn.Close(); srvConn.Close() }()
+
+	srvConn.SetBatchSize(8)
+	cliConn.SetBatchSize(8)
+	srvConn.AddHandler(
+		messages.MsgTypeIdentificationRequest,
+		func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			return c.IdentificationResponse(
+				msg.TEID(), senderAddr, msg,
+				ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+			)
+		},
+	)
+	cliConn.AddHandler(
+		messages.MsgTypeIdentificationResponse,
+		func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			rsp, ok := msg.(*messages.IdentificationResponse)
+			if !ok {
+				return &v2.UnexpectedTypeError{Msg: msg}
+			}
+			causes <- rsp.Cause.MustCause()
+			return nil
+		},
+	)
+
+	const count = 20
+	for n := 0; n < count; n++ {
+		if _, err := cliConn.IdentificationRequest(0, srvConn.LocalAddr()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for n := 0; n < count; n++ {
+		select {
+		case got := <-causes:
+			if got != v2.CauseRequestAccepted {
+				t.Errorf("wrong Cause: %d", got)
+			}
+		case err := <-errCh:
+			t.Fatal(err)
+		case <-time.After(3 * time.Second):
+			t.Fatalf("timed out while waiting for Identification Response: %d/%d", n, count)
+		}
+	}
+}
+
+func TestImpairedTransport(t *testing.T) {
+	errCh := make(chan error, 10)
+
+	srvPC, err := testutils.ListenImpaired(&net.UDPAddr{IP: net.IPv4(127, 0, 0, 2)}, testutils.Impairment{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srvConn := v2.Serve(srvPC, 0, errCh)
+	defer srvConn.Close()
+
+	cliPC, err := testutils.ListenImpaired(&net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)}, testutils.Impairment{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cliConn, err := v2.NewConn(cliPC, srvConn.LocalAddr(), 0, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cliConn.Close()
+
+	waitFor := func(desc string, cond func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for !cond() {
+			select {
+			case err := <-errCh:
+				t.Fatal(err)
+			default:
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out while waiting for %s", desc)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	// the duplicated request should be detected as the retransmission.
+	cliPC.SetImpairment(testutils.Impairment{Duplicate: 1, Latency: 10 * time.Millisecond})
+	if _, err := cliConn.EchoRequest(srvConn.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+	waitFor("the duplicated Echo Request", func() bool {
+		return srvConn.Stats().Retransmissions == 1
+	})
+
+	// the request dropped should never reach the server.
+	cliPC.SetImpairment(testutils.Impairment{})
+	cliPC.SetDropFunc(func(p []byte, addr net.Addr) bool {
+		return p[1] == messages.MsgTypeEchoRequest
+	})
+	received := srvConn.Stats().MessagesReceived[messages.MsgTypeEchoRequest]
+	if _, err := cliConn.EchoRequest(srvConn.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := srvConn.Stats().MessagesReceived[messages.MsgTypeEchoRequest]; got != received {
+		t.Errorf("dropped Echo Request should not be received. want %d, got: %d", received, got)
+	}
+
+	stats := cliPC.Stats()
+	if stats.Duplicated != 1 || stats.Dropped != 1 {
+		t.Errorf("wrong ImpairmentStats: %+v", stats)
+	}
+}
+
+func TestBearerSync(t *testing.T) {
+	var (
+		rspSent = make(chan struct{})
+		rspGot  = make(chan uint8)
+		errCh   = make(chan error)
+		cliTEID = uint32(0x11111111)
+		srvTEID = uint32(0x22222222)
+	)
+	cliConn, srvConn, err := setup(rspSent, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { cliConn.Close(); srvConn.Close() }()
+
+	// srvConn acts as SGW that requests the changes on the Bearers, and cliConn acts
+	// as MME that accepts them.
+	cliSess := v2.NewSession(srvConn.LocalAddr(), &v2.Subscriber{IMSI: "123451234567890"})
+	cliSess.AddTEID(v2.IFTypeS11S4SGWGTPC, srvTEID)
+	cliSess.AddTEID(v2.IFTypeS11MMEGTPC, cliTEID)
+	cliConn.AddSession(cliSess)
+
+	srvSess := v2.NewSession(cliConn.LocalAddr(), &v2.Subscriber{IMSI: "123451234567890"})
+	srvSess.AddTEID(v2.IFTypeS11S4SGWGTPC, srvTEID)
+	srvSess.AddTEID(v2.IFTypeS11MMEGTPC, cliTEID)
+	srvConn.AddSession(srvSess)
+
+	accepted := ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil)
+	cliConn.HandleCreateBearerRequest(func(c *v2.Conn, senderAddr net.Addr, msg *messages.CreateBearerRequest) error {
+		return c.CreateBearerResponse(
+			srvTEID, senderAddr, msg, accepted,
+			ies.NewBearerContext(accepted, ies.NewEPSBearerID(6)),
+		)
+	})
+	cliConn.HandleUpdateBearerRequest(func(c *v2.Conn, senderAddr net.Addr, msg *messages.UpdateBearerRequest) error {
+		return c.UpdateBearerResponse(
+			srvTEID, senderAddr, msg, accepted,
+			ies.NewBearerContext(accepted, ies.NewEPSBearerID(6)),
+		)
+	})
+	cliConn.HandleDeleteBearerRequest(func(c *v2.Conn, senderAddr net.Addr, msg *messages.DeleteBearerRequest) error {
+		return c.DeleteBearerResponse(srvTEID, senderAddr, msg, accepted)
+	})
+	cliConn.HandleModifyBearerRequest(func(c *v2.Conn, senderAddr net.Addr, msg *messages.ModifyBearerRequest) error {
+		return c.ModifyBearerResponse(srvTEID, senderAddr, msg, accepted)
+	})
+	srvConn.AddHandler(messages.MsgTypeModifyBearerResponse, func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+		rspGot <- msg.MessageType()
+		return nil
+	})
+	srvConn.AddHandler(messages.MsgTypeCreateBearerResponse, func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+		rspGot <- msg.MessageType()
+		return nil
+	})
+	srvConn.AddHandler(messages.MsgTypeUpdateBearerResponse, func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+		rspGot <- msg.MessageType()
+		return nil
+	})
+	srvConn.AddHandler(messages.MsgTypeDeleteBearerResponse, func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+		rspGot <- msg.MessageType()
+		return nil
+	})
+
+	waitResponse := func(want uint8) {
+		t.Helper()
+		select {
+		case got := <-rspGot:
+			if got != want {
+				t.Fatalf("unexpected message: %d", got)
+			}
+		case err := <-errCh:
+			t.Fatal(err)
+		case <-time.After(3 * time.Second):
+			t.Fatalf("timed out while waiting for message: %d", want)
+		}
+	}
+	qciOf := func(sess *v2.Session) uint8 {
+		t.Helper()
+		br, err := sess.LookupBearerByEBI(6)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return br.QCI
+	}
+
+	if _, err := srvConn.CreateBearer(
+		cliTEID, cliConn.LocalAddr(), ies.NewEPSBearerID(5),
+		ies.NewBearerContext(ies.NewEPSBearerID(0), ies.NewBearerQoS(1, 2, 1, 1, 0, 0, 64000, 64000)),
+	); err != nil {
+		t.Fatal(err)
+	}
+	waitResponse(messages.MsgTypeCreateBearerResponse)
+	for _, sess := range []*v2.Session{cliSess, srvSess} {
+		if brs := sess.DedicatedBearers(); len(brs) != 1 || brs[0].EBI != 6 {
+			t.Fatalf("wrong dedicated Bearers: %v", brs)
+		}
+		if qci := qciOf(sess); qci != 1 {
+			t.Errorf("wrong QCI of dedicated Bearer. want %d, got: %d", 1, qci)
+		}
+	}
+
+	if _, err := srvConn.UpdateBearer(
+		cliTEID, cliConn.LocalAddr(), ies.NewAggregateMaximumBitRate(0x1111, 0x2222),
+		ies.NewBearerContext(ies.NewEPSBearerID(6), ies.NewBearerQoS(1, 2, 1, 2, 0, 0, 128000, 128000)),
+	); err != nil {
+		t.Fatal(err)
+	}
+	waitResponse(messages.MsgTypeUpdateBearerResponse)
+	for _, sess := range []*v2.Session{cliSess, srvSess} {
+		if qci := qciOf(sess); qci != 2 {
+			t.Errorf("wrong QCI of updated Bearer. want %d, got: %d", 2, qci)
+		}
+		if up, down := sess.GetAPNAMBR(); up != 0x1111 || down != 0x2222 {
+			t.Errorf("wrong APN-AMBR updated: %#x, %#x", up, down)
+		}
+	}
+
+	if _, err := srvConn.ModifyBearer(cliTEID, cliConn.LocalAddr(), ies.NewAggregateMaximumBitRate(0x3333, 0x4444)); err != nil {
+		t.Fatal(err)
+	}
+	waitResponse(messages.MsgTypeModifyBearerResponse)
+	for _, sess := range []*v2.Session{cliSess, srvSess} {
+		if up, down := sess.GetAPNAMBR(); up != 0x3333 || down != 0x4444 {
+			t.Errorf("wrong APN-AMBR modified: %#x, %#x", up, down)
+		}
+	}
+
+	if _, err := srvConn.DeleteBearer(
+		cliTEID, cliConn.LocalAddr(), ies.NewEPSBearerID(6).WithInstance(1),
+	); err != nil {
+		t.Fatal(err)
+	}
+	waitResponse(messages.MsgTypeDeleteBearerResponse)
+	for _, sess := range []*v2.Session{cliSess, srvSess} {
+		if brs := sess.DedicatedBearers(); len(brs) != 0 {
+			t.Errorf("dedicated Bearer should be removed, got: %v", brs)
+		}
+		if count := sess.BearerCount(); count != 1 {
+			t.Errorf("default Bearer should remain. want %d, got: %d", 1, count)
+		}
+	}
+}
+
+func TestRecoveryPolicy(t *testing.T) {
+	var (
+		rspSent = make(chan struct{})
+		reqGot  = make(chan *ies.IE)
+		errCh   = make(chan error)
+	)
+	cliConn, srvConn, err := setup(rspSent, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { cliConn.Close(); srvConn.Close() }()
+
+	cliConn.RestartCounter = 3
+	cliConn.SetRecoveryPolicy(v2.RecoveryPolicyFirstContact)
+
+	srvConn.AddHandler(
+		messages.MsgTypeModifyBearerRequest,
+		func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			reqGot <- msg.(*messages.ModifyBearerRequest).Recovery
+			return c.RespondTo(
+				senderAddr, msg,
+				messages.NewModifyBearerResponse(0, 0, ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil)),
+			)
+		},
+	)
+	cliConn.AddHandler(
+		messages.MsgTypeModifyBearerResponse,
+		func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			return nil
+		},
+	)
+
+	cases := []struct {
+		description string
+		ie          []*ies.IE
+		want        bool
+	}{
+		{"first contact", nil, true},
+		{"explicitly given", []*ies.IE{ies.NewRecovery(3)}, false},
+		{"not given", nil, false},
+	}
+	for _, c := range cases {
+		msg := messages.NewModifyBearerRequest(0, 0, c.ie...)
+		if _, err := cliConn.SendMessageTo(msg, srvConn.LocalAddr()); err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case ie := <-reqGot:
+			if got := ie != nil; got != c.want {
+				t.Fatalf("%s: Recovery IE should be included: %v, got: %v", c.description, c.want, got)
+			}
+			if ie == nil {
+				continue
+			}
+			if counter, err := ie.Recovery(); err != nil || counter != 3 {
+				t.Errorf("%s: wrong RestartCounter: %d, %v", c.description, counter, err)
+			}
+		case err := <-errCh:
+			t.Fatal(err)
+		case <-time.After(3 * time.Second):
+			t.Fatalf("%s: timed out while waiting for Modify Bearer Request", c.description)
+		}
+	}
+}
+
+func TestContextHandler(t *testing.T) {
+	type result struct {
+		deadline time.Time
+		err      error
+		arrived  time.Time
+	}
+	var (
+		rspSent  = make(chan struct{})
+		resultCh = make(chan result)
+		errCh    = make(chan error)
+	)
+	cliConn, srvConn, err := setup(rspSent, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { cliConn.Close(); srvConn.Close() }()
+
+	srvConn.AddContextHandler(
+		messages.MsgTypeCreateSessionRequest,
+		func(ctx context.Context, msg messages.Message) error {
+			if c, ok := v2.ConnFromContext(ctx); !ok || c != srvConn {
+				return errors.New("no Conn in context")
+			}
+			if addr, ok := v2.SenderAddrFromContext(ctx); !ok || addr.String() != cliConn.LocalAddr().String() {
+				return errors.Errorf("wrong sender in context: %v", addr)
+			}
+			if addr, ok := v2.LocalAddrFromContext(ctx); !ok || addr.String() != srvConn.LocalAddr().String() {
+				return errors.Errorf("wrong local address in context: %v", addr)
+			}
+			arrived, _ := v2.ArrivalTimeFromContext(ctx)
+			deadline, _ := ctx.Deadline()
+			resultCh <- result{deadline, ctx.Err(), arrived}
+			return nil
+		},
+	)
+
+	now := time.Now()
+	cases := []struct {
+		description string
+		ie          []*ies.IE
+		hasDeadline bool
+		expired     bool
+	}{
+		{"no deadline", nil, false, false},
+		{
+			"in time",
+			[]*ies.IE{ies.NewMillisecondTimeStamp(now), ies.NewIntegerNumber(60000)},
+			true, false,
+		}, {
+			"expired",
+			[]*ies.IE{ies.NewMillisecondTimeStamp(now.Add(-time.Minute)), ies.NewIntegerNumber(1000)},
+			true, true,
+		},
+	}
+	for _, c := range cases {
+		msg := messages.NewCreateSessionRequest(0, 0, c.ie...)
+		if _, err := cliConn.SendMessageTo(msg, srvConn.LocalAddr()); err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case got := <-resultCh:
+			if got.arrived.Before(now) {
+				t.Errorf("%s: wrong arrival time: %v", c.description, got.arrived)
+			}
+			if hasDeadline := !got.deadline.IsZero(); hasDeadline != c.hasDeadline {
+				t.Errorf("%s: deadline should be set: %v, got: %v", c.description, c.hasDeadline, got.deadline)
+			}
+			if expired := got.err == context.DeadlineExceeded; expired != c.expired {
+				t.Errorf("%s: context should be expired: %v, got: %v", c.description, c.expired, got.err)
+			}
+		case err := <-errCh:
+			t.Fatal(err)
+		case <-time.After(3 * time.Second):
+			t.Fatalf("%s: timed out while waiting for Create Session Request", c.description)
+		}
+	}
+}
+
+func TestSocketOptions(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("socket options are supported only on Linux")
+	}
+
+	cliAddr := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 3), Port: 2123}
+	srvAddr := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 4), Port: 2123}
+	errCh := make(chan error, 1)
+	so := &v2.SocketOptions{DSCP: 46, ReusePort: true, WriteBuffer: 1 << 20}
+
+	srvConn, err := v2.ListenAndServeWithSocketOptions(srvAddr, 0, errCh, so)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+
+	// the Echo is exchanged over the sockets with the options set.
+	cliConn, err := v2.DialWithSocketOptions(cliAddr, srvAddr, 0, errCh, so)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cliConn.Close()
+
+	// another Conn can bind the same address with ReusePort.
+	anotherConn, err := v2.ListenAndServeWithSocketOptions(srvAddr, 0, errCh, so)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer anotherConn.Close()
+
+	if _, err := v2.ListenAndServeWithSocketOptions(srvAddr, 0, errCh, &v2.SocketOptions{DSCP: 64}); err != v2.ErrInvalidDSCP {
+		t.Errorf("unexpected error with invalid DSCP: %v", err)
+	}
+}
+
+func TestMultiHomed(t *testing.T) {
+	var (
+		s11Addr  = &net.UDPAddr{IP: net.IPv4(127, 0, 0, 5), Port: 2123}
+		s5Addr   = &net.UDPAddr{IP: net.IPv4(127, 0, 0, 6), Port: 2123}
+		mmeAddr  = &net.UDPAddr{IP: net.IPv4(127, 0, 0, 7), Port: 2123}
+		pgwAddr  = &net.UDPAddr{IP: net.IPv4(127, 0, 0, 8), Port: 2123}
+		errCh    = make(chan error, 1)
+		received = make(chan [2]string)
+	)
+
+	srvConn, err := v2.ListenAndServe(s11Addr, 0, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+	if err := srvConn.AddLocalAddr(s5Addr, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(srvConn.LocalAddrs()); got != 2 {
+		t.Errorf("wrong number of local addresses: %d", got)
+	}
+
+	// the responses and the requests received by the peers report the addresses
+	// of both ends.
+	onIdentification := func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+		received <- [2]string{senderAddr.String(), c.ReceivedOn(msg).String()}
+		if msg.MessageType() == messages.MsgTypeIdentificationRequest {
+			return c.IdentificationResponse(
+				msg.TEID(), senderAddr, msg,
+				ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+			)
+		}
+		return nil
+	}
+	srvConn.AddHandler(messages.MsgTypeIdentificationRequest, onIdentification)
+	srvConn.AddHandler(messages.MsgTypeIdentificationResponse, onIdentification)
+
+	var peers []*v2.Conn
+	for _, laddr := range []net.Addr{mmeAddr, pgwAddr} {
+		c, err := v2.ListenAndServe(laddr, 0, errCh)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer c.Close()
+		c.AddHandler(messages.MsgTypeIdentificationRequest, onIdentification)
+		c.AddHandler(messages.MsgTypeIdentificationResponse, onIdentification)
+		peers = append(peers, c)
+	}
+	mmeConn, pgwConn := peers[0], peers[1]
+
+	wait := func(from, on net.Addr) {
+		t.Helper()
+		select {
+		case got := <-received:
+			if want := [2]string{from.String(), on.String()}; got != want {
+				t.Errorf("wrong addresses. want: %v, got: %v", want, got)
+			}
+		case err := <-errCh:
+			t.Fatal(err)
+		case <-time.After(3 * time.Second):
+			t.Fatal("timed out while waiting for Identification")
+		}
+	}
+
+	// the responses are sent from the local address that the requests arrived on.
+	if _, err := mmeConn.IdentificationRequest(0, s11Addr); err != nil {
+		t.Fatal(err)
+	}
+	wait(mmeAddr, s11Addr)
+	wait(s11Addr, mmeAddr)
+
+	if _, err := pgwConn.IdentificationRequest(0, s5Addr); err != nil {
+		t.Fatal(err)
+	}
+	wait(pgwAddr, s5Addr)
+	wait(s5Addr, pgwAddr)
+
+	// the requests are sent from the local address that the peer sent to, unless
+	// it is set explicitly.
+	if _, err := srvConn.IdentificationRequest(0, pgwAddr); err != nil {
+		t.Fatal(err)
+	}
+	wait(s5Addr, pgwAddr)
+	wait(pgwAddr, s5Addr)
+
+	if err := srvConn.SetPeerLocalAddr(mmeAddr, s5Addr); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := srvConn.IdentificationRequest(0, mmeAddr); err != nil {
+		t.Fatal(err)
+	}
+	wait(s5Addr, mmeAddr)
+	wait(mmeAddr, s5Addr)
+
+	if err := srvConn.SetPeerLocalAddr(mmeAddr, pgwAddr); err != v2.ErrLocalAddrNotFound {
+		t.Errorf("unexpected error with unknown local address: %v", err)
+	}
+}
+
+func TestPiggybacking(t *testing.T) {
+	var (
+		rspSent  = make(chan struct{})
+		errCh    = make(chan error)
+		received = make(chan []uint8)
+	)
+	cliConn, srvConn, err := setup(rspSent, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { cliConn.Close(); srvConn.Close() }()
+
+	srvConn.AddHandler(
+		messages.MsgTypeCreateSessionRequest,
+		func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			_, err := c.RespondWithPiggybacked(
+				senderAddr, msg,
+				messages.NewCreateSessionResponse(0, 0, ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil)),
+				messages.NewCreateBearerRequest(
+					0, 0, ies.NewEPSBearerID(5), ies.NewBearerContext(ies.NewEPSBearerID(6)),
+				),
+			)
+			return err
+		},
+	)
+	cliConn.AddHandler(
+		messages.MsgTypeCreateSessionResponse,
+		func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			types := []uint8{msg.MessageType()}
+			for _, m := range c.PiggybackedMessages(msg) {
+				types = append(types, m.MessageType())
+			}
+			received <- types
+			return nil
+		},
+	)
+	cliConn.AddHandler(
+		messages.MsgTypeCreateBearerRequest,
+		func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			received <- []uint8{msg.MessageType()}
+			return nil
+		},
+	)
+
+	if _, err := cliConn.SendMessageTo(messages.NewCreateSessionRequest(0, 0), srvConn.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+
+	// both of the messages are dispatched to their handlers, and the piggybacked one
+	// can be seen from the handler of the other.
+	var gotRsp, gotReq bool
+	for !gotRsp || !gotReq {
+		select {
+		case types := <-received:
+			switch types[0] {
+			case messages.MsgTypeCreateSessionResponse:
+				gotRsp = true
+				if len(types) != 2 || types[1] != messages.MsgTypeCreateBearerRequest {
+					t.Errorf("wrong piggybacked messages: %v", types[1:])
+				}
+			case messages.MsgTypeCreateBearerRequest:
+				gotReq = true
+			}
+		case err := <-errCh:
+			t.Fatal(err)
+		case <-time.After(3 * time.Second):
+			t.Fatal("timed out while waiting for the piggybacked messages")
+		}
+	}
+}
+
+func TestTracer(t *testing.T) {
+	var (
+		rspSent = make(chan struct{})
+		errCh   = make(chan error)
+		traced  = make(chan string, 10)
+	)
+	cliConn, srvConn, err := setup(rspSent, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { cliConn.Close(); srvConn.Close() }()
+
+	cliConn.SetTracer(func(dir v2.Direction, raddr net.Addr, raw []byte, msg messages.Message) {
+		if msg == nil || len(raw) != msg.MarshalLen() {
+			traced <- "invalid"
+			return
+		}
+		traced <- dir.String() + " " + msg.MessageTypeName()
+	})
+
+	if _, err := cliConn.EchoRequest(srvConn.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"Outgoing Echo Request", "Incoming Echo Response"} {
+		select {
+		case got := <-traced:
+			if got != want {
+				t.Errorf("wrong datagram traced. want %s, got: %s", want, got)
+			}
+		case err := <-errCh:
+			t.Fatal(err)
+		case <-time.After(3 * time.Second):
+			t.Fatalf("timed out while waiting for %s", want)
+		}
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	var (
+		rspSent = make(chan struct{})
+		errCh   = make(chan error)
+		reqs    = make(chan uint32, 10)
+	)
+	cliConn, srvConn, err := setup(rspSent, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+
+	srvSess := v2.NewSession(cliConn.LocalAddr(), &v2.Subscriber{IMSI: "123451234567890"})
+	srvSess.AddTEID(v2.IFTypeS11S4SGWGTPC, 0x11111111)
+	srvConn.AddSession(srvSess)
+
+	// the response is delayed so that the request is retransmitted, which is not
+	// handled again as it is the duplicated one.
+	srvConn.AddHandler(
+		messages.MsgTypeDeleteSessionRequest,
+		func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			reqs <- msg.TEID()
+			time.Sleep(300 * time.Millisecond)
+			return c.RespondTo(
+				senderAddr, msg,
+				messages.NewDeleteSessionResponse(0, 0, ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil)),
+			)
+		},
+	)
+	cliConn.AddHandler(
+		messages.MsgTypeDeleteSessionResponse,
+		func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			return nil
+		},
+	)
+
+	sess := v2.NewSession(srvConn.LocalAddr(), &v2.Subscriber{IMSI: "123451234567890"})
+	sess.AddTEID(v2.IFTypeS11S4SGWGTPC, 0x11111111)
+	sess.GetDefaultBearer().EBI = 5
+	if err := sess.Activate(); err != nil {
+		t.Fatal(err)
+	}
+	cliConn.AddSession(sess)
+
+	cliConn.SetShutdownPolicy(&v2.ShutdownPolicy{
+		DeleteSessions:     true,
+		PeerIFType:         v2.IFTypeS11S4SGWGTPC,
+		RetransmitInterval: 200 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := cliConn.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := len(reqs); n != 1 {
+		t.Errorf("wrong number of Delete Session Requests: %d", n)
+	}
+	for len(reqs) > 0 {
+		if teid := <-reqs; teid != 0x11111111 {
+			t.Errorf("wrong TEID in Delete Session Request: %#x", teid)
+		}
+	}
+	if n := cliConn.SessionCount(); n != 0 {
+		t.Errorf("Session not removed after Shutdown: %d", n)
+	}
+}
+
+func TestEchoNodeFeatures(t *testing.T) {
+	var (
+		rspSent = make(chan struct{})
+		errCh   = make(chan error)
+		echoed  = make(chan struct{})
+	)
+	cliConn, srvConn, err := setup(rspSent, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { cliConn.Close(); srvConn.Close() }()
+
+	srvConn.SetEchoIEs(ies.NewNodeFeatures(v2.NodeFeaturePRN | v2.NodeFeatureNTSR))
+	cliConn.AddHandler(
+		messages.MsgTypeEchoResponse,
+		func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			echoed <- struct{}{}
+			return nil
+		},
+	)
+
+	if _, err := cliConn.EchoRequest(srvConn.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-echoed:
+	case err := <-errCh:
+		t.Fatal(err)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out while waiting for Echo Response")
+	}
+
+	peer, err := cliConn.GetPeer(srvConn.LocalAddr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if features, ok := peer.NodeFeatures(); !ok || features != v2.NodeFeaturePRN|v2.NodeFeatureNTSR {
+		t.Errorf("wrong Node Features: %#x, %v", features, ok)
+	}
+	if !peer.SupportsNodeFeature(v2.NodeFeatureNTSR) || peer.SupportsNodeFeature(v2.NodeFeatureMABR) {
+		t.Error("wrong result of SupportsNodeFeature")
+	}
+}
+
+// blockingPacketConn blocks WriteTo until release is closed.
+type blockingPacketConn struct {
+	net.PacketConn
+	release chan struct{}
+}
+
+func (c *blockingPacketConn) WriteTo(p []byte, addr net.Addr) (int, error) {
+	<-c.release
+	return c.PacketConn.WriteTo(p, addr)
+}
+
+func TestSendQueue(t *testing.T) {
+	errCh := make(chan error, 10)
+	srvConn, err := v2.ListenAndServe(&net.UDPAddr{IP: net.IPv4(127, 0, 0, 91), Port: 2123}, 0, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+
+	newConn := func(ip net.IP, sq *v2.SendQueue) (*v2.Conn, chan struct{}) {
+		t.Helper()
+		pc, err := net.ListenPacket("udp", (&net.UDPAddr{IP: ip, Port: 2123}).String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		release := make(chan struct{})
+		conn := v2.Serve(&blockingPacketConn{pc, release}, 0, errCh)
+		conn.SetSendQueue(sq)
+		return conn, release
+	}
+
+	// the writer is blocked with the first one, and the second one fills the queue.
+	dropConn, release := newConn(net.IPv4(127, 0, 0, 92), &v2.SendQueue{Size: 1, Overflow: v2.OverflowActionDrop})
+	defer dropConn.Close()
+	sent := 0
+	for ; sent < 3; sent++ {
+		if _, err = dropConn.EchoRequest(srvConn.LocalAddr()); err != nil {
+			break
+		}
+		// let the writer pick up the first one.
+		time.Sleep(10 * time.Millisecond)
+	}
+	if errors.Cause(err) != v2.ErrSendQueueFull {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if st := dropConn.Stats(); st.SendDropped != 1 || st.SendQueueDepth != 1 {
+		t.Errorf("unexpected Stats: SendDropped=%d, SendQueueDepth=%d", st.SendDropped, st.SendQueueDepth)
+	}
+
+	// the queue is full and the flush cannot even be queued.
+	shortCtx, shortCancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer shortCancel()
+	if err := dropConn.FlushSendQueue(shortCtx); err != context.DeadlineExceeded {
+		t.Errorf("unexpected error from FlushSendQueue on full queue: %v", err)
+	}
+
+	close(release)
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	if err := dropConn.FlushSendQueue(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if n := dropConn.Stats().SendQueueDepth; n != 0 {
+		t.Errorf("SendQueueDepth should be 0 after flush: %d", n)
+	}
+	deadline := time.Now().Add(3 * time.Second)
+	for srvConn.Stats().MessagesReceived[messages.MsgTypeEchoRequest] != uint64(sent) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Echo Requests queued are not received: %d", sent)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// the sender is blocked until BlockTimeout.
+	blockConn, release := newConn(net.IPv4(127, 0, 0, 93), &v2.SendQueue{Size: 1, BlockTimeout: 50 * time.Millisecond})
+	defer blockConn.Close()
+	for sent = 0; sent < 3; sent++ {
+		if _, err = blockConn.EchoRequest(srvConn.LocalAddr()); err != nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if errors.Cause(err) != v2.ErrSendQueueFull {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	close(release)
+
+	// the datagrams are written synchronously after the queue is stopped.
+	blockConn.SetSendQueue(nil)
+	if _, err := blockConn.EchoRequest(srvConn.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-errCh:
+		t.Fatal(err)
+	default:
+	}
+}
+
+func TestResponseValidation(t *testing.T) {
+	errCh := make(chan error, 10)
+	cliAddr := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 94), Port: 2123}
+	srvConn, err := v2.ListenAndServe(&net.UDPAddr{IP: net.IPv4(127, 0, 0, 95), Port: 2123}, 0, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srvConn.Close()
+	cliConn, err := v2.ListenAndServe(cliAddr, 0, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cliConn.Close()
+
+	// the responses are sent in this order to the requests.
+	fteid := ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 0x11111111, "127.0.0.94", "")
+	responses := make(chan messages.Message, 4)
+	responses <- messages.NewModifyBearerResponse(0x11111111, 0, ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil))
+	responses <- messages.NewCreateSessionResponse(0x22222222, 0, ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil))
+	responses <- messages.NewCreateSessionResponse(0x11111111, 0, ies.NewCause(v2.CauseLocalDetach, 0, 0, 0, nil))
+	responses <- messages.NewCreateSessionResponse(0x11111111, 0, ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil))
+	srvConn.AddHandler(
+		messages.MsgTypeCreateSessionRequest,
+		func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			return c.RespondTo(senderAddr, msg, <-responses)
+		},
+	)
+
+	handled := make(chan struct{}, 1)
+	cliConn.AddHandler(
+		messages.MsgTypeCreateSessionResponse,
+		func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+			handled <- struct{}{}
+			return nil
+		},
+	)
+
+	// the Sessions are added first so that the TEIDs are known.
+	for imsi, ie := range map[string]*ies.IE{
+		"123451234567890": fteid,
+		"123451234567891": ies.NewFullyQualifiedTEID(v2.IFTypeS11MMEGTPC, 0x22222222, "127.0.0.94", ""),
+	} {
+		sess, err := v2.NewSessionFromIEs(srvConn.LocalAddr(), ies.NewIMSI(imsi), ie)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cliConn.AddSession(sess)
+	}
+
+	for _, reason := range []string{"message type", "TEID", "Cause", ""} {
+		if _, err := cliConn.SendMessageTo(
+			messages.NewCreateSessionRequest(0, 0, ies.NewIMSI("123451234567890"), fteid),
+			srvConn.LocalAddr(),
+		); err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case <-handled:
+			if reason != "" {
+				t.Errorf("the response with wrong %s should not be handled", reason)
+			}
+		case err := <-errCh:
+			e, ok := err.(*v2.UnexpectedResponseError)
+			if !ok || reason == "" || !strings.Contains(e.Reason, reason) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if e.ReqType != "Create Session Request" {
+				t.Errorf("wrong ReqType: %s", e.ReqType)
+			}
+		case <-time.After(3 * time.Second):
+			t.Fatalf("timed out while waiting for the response with wrong %s", reason)
+		}
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	errCh := make(chan error, 10)
+	srvConn, err := v2.ListenAndServe(&net.UDPAddr{IP: net.IPv4(127, 0, 0, 96), Port: 2123}, 0, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cliConn, err := v2.ListenAndServe(&net.UDPAddr{IP: net.IPv4(127, 0, 0, 97), Port: 2123}, 0, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cliConn.Close()
+
+	// Create Session Request has no HandlerFunc on srvConn.
+	csReqCh := srvConn.Subscribe(messages.MsgTypeCreateSessionRequest)
+	allCh := srvConn.Subscribe()
+	if _, err := cliConn.SendMessageTo(
+		messages.NewCreateSessionRequest(0, 0, ies.NewIMSI("123451234567890")), srvConn.LocalAddr(),
+	); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cliConn.EchoRequest(srvConn.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the messages may be handled in the different order from the one they are sent.
+	for _, want := range []struct {
+		ch       <-chan v2.IncomingMessage
+		msgTypes []uint8
+	}{
+		{csReqCh, []uint8{messages.MsgTypeCreateSessionRequest}},
+		{allCh, []uint8{messages.MsgTypeCreateSessionRequest, messages.MsgTypeEchoRequest}},
+	} {
+		got := map[uint8]bool{}
+		for range want.msgTypes {
+			select {
+			case in := <-want.ch:
+				got[in.Message.MessageType()] = true
+				if in.SenderAddr.String() != cliConn.LocalAddr().String() || in.ArrivedAt.IsZero() {
+					t.Errorf("unexpected IncomingMessage: %+v", in)
+				}
+			case err := <-errCh:
+				t.Fatal(err)
+			case <-time.After(3 * time.Second):
+				t.Fatalf("timed out while waiting for message types %v", want.msgTypes)
+			}
+		}
+		for _, msgType := range want.msgTypes {
+			if !got[msgType] {
+				t.Errorf("message type %d is not delivered", msgType)
+			}
+		}
+	}
+
+	srvConn.Unsubscribe(csReqCh)
+	if _, ok := <-csReqCh; ok {
+		t.Error("channel is not closed by Unsubscribe")
+	}
+	srvConn.Close()
+	for range allCh {
+	}
+	if _, ok := <-srvConn.Subscribe(); ok {
+		t.Error("channel is not closed after Conn is closed")
+	}
+}
+
+func TestSendRequestTo(t *testing.T) {
+	errCh := make(chan error, 10)
+	cliConn, err := v2.ListenAndServe(&net.UDPAddr{IP: net.IPv4(127, 0, 0, 98), Port: 2123}, 0, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cliConn.Close()
+
+	// the peer that never responds, to see the retransmissions of the same bytes.
+	peer, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 99), Port: 2123})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer peer.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	policy := &v2.RetransmitPolicy{Interval: 50 * time.Millisecond, MaxRetransmissions: 2}
+	if _, err := cliConn.SendRequestTo(
+		ctx, messages.NewDeleteSessionRequest(0x11111111, 0, ies.NewEPSBearerID(5)), peer.LocalAddr(), policy,
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 1500)
+	var first []byte
+	for n := 0; n < 3; n++ {
+		if err := peer.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
+			t.Fatal(err)
+		}
+		l, _, err := peer.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("request #%d not received: %v", n, err)
+		}
+		if first == nil {
+			first = append([]byte{}, buf[:l]...)
+			continue
+		}
+		if !bytes.Equal(buf[:l], first) {
+			t.Errorf("retransmitted request differs: got %x, want %x", buf[:l], first)
+		}
+	}
+
+	// no more retransmission after MaxRetransmissions.
+	if err := peer.SetReadDeadline(time.Now().Add(200 * time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := peer.ReadFrom(buf); err == nil {
+		t.Error("request retransmitted more than MaxRetransmissions")
+	}
+	if got := cliConn.Stats().RequestsRetransmitted; got != 2 {
+		t.Errorf("unexpected RequestsRetransmitted: got %d, want 2", got)
+	}
+
+	select {
+	case err := <-errCh:
+		t.Error(err)
+	default:
+	}
+}
+
+func TestPipeConn(t *testing.T) {
+	errCh := make(chan error, 10)
+	srvPC, cliPC := testutils.NewPipeConn()
+
+	srvConn := v2.Serve(srvPC, 0, errCh)
+	defer srvConn.Close()
+	srvConn.AddHandler(messages.MsgTypeDeleteSessionRequest, func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+		return c.RespondTo(senderAddr, msg, messages.NewDeleteSessionResponse(
+			0, 0, ies.NewCause(v2.CauseContextNotFound, 0, 0, 0, nil),
+		))
+	})
+
+	// NewConn exchanges Echo with the deadline set on the pipe.
+	cliConn, err := v2.NewConn(cliPC, srvPC.LocalAddr(), 0, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cliConn.Close()
+	testutils.ExpectSent(t, srvPC, messages.MsgTypeEchoResponse, time.Second)
+	cliConn.AddHandler(messages.MsgTypeDeleteSessionResponse, func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+		return nil
+	})
+
+	seq, err := cliConn.SendMessageTo(messages.NewDeleteSessionRequest(0, 0, ies.NewEPSBearerID(5)), srvPC.LocalAddr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := testutils.ExpectSent(t, srvPC, messages.MsgTypeDeleteSessionResponse, time.Second)
+	if msg.Sequence() != seq {
+		t.Errorf("unexpected Sequence: got %d, want %d", msg.Sequence(), seq)
+	}
+	if err := v2.CheckCause(msg); err == nil {
+		t.Error("Cause in Delete Session Response not checked")
+	}
+
+	// the ones to the addresses other than the peer are not delivered.
+	if _, err := cliConn.SendMessageTo(messages.NewEchoRequest(0), &net.UDPAddr{IP: net.IPv4(127, 0, 0, 3), Port: 2123}); err != nil {
+		t.Fatal(err)
+	}
+	testutils.ExpectNotSent(t, srvPC, messages.MsgTypeEchoRequest, 50*time.Millisecond)
+	if got := len(srvPC.Sent()); got != 2 {
+		t.Errorf("unexpected number of messages sent from server: got %d, want 2", got)
+	}
+	select {
+	case err := <-errCh:
+		t.Error(err)
+	default:
+	}
+}
+
+func TestIEOrder(t *testing.T) {
+	errCh := make(chan error, 10)
+	srvPC, cliPC := testutils.NewPipeConn()
+	srvConn := v2.Serve(srvPC, 0, errCh)
+	defer srvConn.Close()
+
+	srvConn.SetIEOrder(messages.IEOrderSpec)
+	srvConn.SetIEOrderFor(messages.MsgTypeDeleteSessionResponse, messages.IEOrderAsIs)
+	srvConn.AddHandler(messages.MsgTypeCreateSessionRequest, func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+		return c.RespondTo(senderAddr, msg, messages.NewGeneric(
+			messages.MsgTypeCreateSessionResponse, 0, 0,
+			ies.NewPrivateExtension(10415, []byte{0x01}),
+			ies.NewBearerContext(ies.NewChargingID(1), ies.NewEPSBearerID(5)),
+			ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+		))
+	})
+	srvConn.AddHandler(messages.MsgTypeDeleteSessionRequest, func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+		return c.RespondTo(senderAddr, msg, messages.NewGeneric(
+			messages.MsgTypeDeleteSessionResponse, 0, 0,
+			ies.NewRecovery(0), ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+		))
+	})
+
+	types := func(b []byte) []uint8 {
+		t.Helper()
+		h, err := messages.ParseHeader(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := ies.ParseMultiIEs(h.Payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var typs []uint8
+		for _, i := range decoded {
+			typs = append(typs, i.Type)
+			for _, child := range i.ChildIEs {
+				typs = append(typs, child.Type)
+			}
+		}
+		return typs
+	}
+
+	for _, c := range []struct {
+		req  messages.Message
+		res  uint8
+		want []uint8
+	}{
+		{
+			messages.NewCreateSessionRequest(0, 0, ies.NewIMSI("123451234567890")),
+			messages.MsgTypeCreateSessionResponse,
+			[]uint8{ies.Cause, ies.BearerContext, ies.EPSBearerID, ies.ChargingID, ies.PrivateExtension},
+		}, {
+			messages.NewDeleteSessionRequest(0, 0, ies.NewEPSBearerID(5)),
+			messages.MsgTypeDeleteSessionResponse,
+			[]uint8{ies.Recovery, ies.Cause},
+		},
+	} {
+		if err := cliPC.WriteMessage(c.req); err != nil {
+			t.Fatal(err)
+		}
+		sent := srvPC.WaitSent(c.res, time.Second)
+		if sent == nil {
+			t.Fatalf("message type %d not sent", c.res)
+		}
+		got := types(sent.Raw)
+		if fmt.Sprint(got) != fmt.Sprint(c.want) {
+			t.Errorf("unexpected order of IEs in message type %d: got %v, want %v", c.res, got, c.want)
+		}
+	}
+
+	select {
+	case err := <-errCh:
+		t.Error(err)
+	default:
+	}
+}
+
+func TestVersionConn(t *testing.T) {
+	errCh := make(chan error, 10)
+	srvPC, cliPC := testutils.NewPipeConn()
+
+	srvConn := v2.Serve(srvPC, 0, errCh)
+	defer srvConn.Close()
+
+	// GTPv1-C is served with the handlers of v1 on the same socket as GTPv2-C.
+	sgsnConn := v1.NewCPlaneConn(srvConn.VersionConn(1), 0, errCh)
+
+	cliConn := v1.NewCPlaneConn(cliPC, 0, errCh)
+	defer cliConn.Close()
+	echoCh := make(chan uint16, 1)
+	cliConn.AddHandler(v1msg.MsgTypeEchoResponse, func(c v1.Conn, senderAddr net.Addr, msg v1msg.Message) error {
+		echoCh <- msg.Sequence()
+		return nil
+	})
+
+	seq, err := cliConn.SendMessageTo(v1msg.NewEchoRequest(0), srvPC.LocalAddr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-echoCh:
+		if got != seq {
+			t.Errorf("unexpected Sequence: got %d, want %d", got, seq)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("GTPv1-C Echo Response not received")
+	}
+	testutils.ExpectNotSent(t, srvPC, messages.MsgTypeVersionNotSupportedIndication, 50*time.Millisecond)
+
+	// GTPv2-C is still handled by Conn.
+	if err := cliPC.WriteMessage(messages.NewEchoRequest(0)); err != nil {
+		t.Fatal(err)
+	}
+	testutils.ExpectSent(t, srvPC, messages.MsgTypeEchoResponse, time.Second)
+
+	// closing the v1 side leaves Conn open, which no longer hands off GTPv1-C.
+	if err := sgsnConn.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cliConn.SendMessageTo(v1msg.NewEchoRequest(0), srvPC.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-echoCh:
+		t.Error("GTPv1-C Echo Response received after closing")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if _, err := srvConn.EchoRequest(cliPC.LocalAddr()); err != nil {
+		t.Errorf("Conn closed with VersionConn: %v", err)
+	}
+}
+
+func TestTEIDLearning(t *testing.T) {
+	errCh := make(chan error, 10)
+	sgwPC, mmePC := testutils.NewPipeConn()
+	mmeConn := v2.Serve(mmePC, 0, errCh)
+	defer mmeConn.Close()
+	mmeConn.EnableTEIDLearning()
+
+	rspCh := make(chan *v2.Session, 1)
+	handleRsp := func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+		sess, err := c.GetSessionByTEID(msg.TEID(), senderAddr)
+		if err != nil {
+			return err
+		}
+		rspCh <- sess
+		return nil
+	}
+	mmeConn.AddHandler(messages.MsgTypeCreateSessionResponse, handleRsp)
+	mmeConn.AddHandler(messages.MsgTypeModifyBearerResponse, handleRsp)
+	waitRsp := func() *v2.Session {
+		t.Helper()
+		select {
+		case sess := <-rspCh:
+			return sess
+		case err := <-errCh:
+			t.Fatal(err)
+		case <-time.After(time.Second):
+			t.Fatal("timed out while waiting for the response")
+		}
+		return nil
+	}
+
+	sess, seq, err := mmeConn.CreateSession(sgwPC.LocalAddr(),
+		ies.NewIMSI("123451234567890"),
+		mmeConn.NewFTEID(v2.IFTypeS11MMEGTPC, "127.0.0.2", ""),
+		ies.NewBearerContext(ies.NewEPSBearerID(5)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mmeConn.AddSession(sess)
+	mmeTEID, err := sess.GetTEID(v2.IFTypeS11MMEGTPC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	csRsp := messages.NewCreateSessionResponse(mmeTEID, seq,
+		ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+		ies.NewFullyQualifiedTEID(v2.IFTypeS11S4SGWGTPC, 0x11111111, "127.0.0.1", ""),
+		ies.NewFullyQualifiedTEID(v2.IFTypeS5S8PGWGTPC, 0x22222222, "127.0.0.3", "").WithInstance(1),
+		ies.NewPDNAddressAllocation("10.0.0.1"),
+		ies.NewBearerContext(
+			ies.NewEPSBearerID(5),
+			ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+			ies.NewFullyQualifiedTEID(v2.IFTypeS5S8PGWGTPU, 0x44444444, "127.0.0.3", "").WithInstance(2),
+			ies.NewFullyQualifiedTEID(v2.IFTypeS1USGWGTPU, 0x33333333, "127.0.0.1", ""),
+		),
+	)
+	if err := sgwPC.WriteMessage(csRsp); err != nil {
+		t.Fatal(err)
+	}
+	if got := waitRsp(); got != sess {
+		t.Fatal("unexpected Session")
+	}
+
+	for it, want := range map[uint8]uint32{
+		v2.IFTypeS11S4SGWGTPC: 0x11111111,
+		v2.IFTypeS5S8PGWGTPC:  0x22222222,
+		v2.IFTypeS1USGWGTPU:   0x33333333,
+		v2.IFTypeS5S8PGWGTPU:  0x44444444,
+	} {
+		if got, err := sess.GetTEID(it); err != nil || got != want {
+			t.Errorf("unexpected TEID for interface type %d: got %#x, %v, want %#x", it, got, err, want)
+		}
+	}
+	br := sess.GetDefaultBearer()
+	if got := br.GetSubscriberIP(); got != "10.0.0.1" {
+		t.Errorf("unexpected SubscriberIP: %s", got)
+	}
+	if got := br.OutgoingTEID(); got != 0x33333333 {
+		t.Errorf("unexpected OutgoingTEID: %#x", got)
+	}
+	if got := br.RemoteAddress().String(); got != "127.0.0.1:2152" {
+		t.Errorf("unexpected RemoteAddress: %s", got)
+	}
+
+	// the S1-U SGW F-TEID is changed with the one in Modify Bearer Response.
+	seq, err = mmeConn.ModifyBearer(mmeTEID, sgwPC.LocalAddr(), ies.NewBearerContext(
+		ies.NewEPSBearerID(5), ies.NewFullyQualifiedTEID(v2.IFTypeS1UeNodeBGTPU, 0x55555555, "127.0.0.2", ""),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mbRsp := messages.NewModifyBearerResponse(mmeTEID, seq,
+		ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+		ies.NewBearerContext(
+			ies.NewEPSBearerID(5),
+			ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+			ies.NewFullyQualifiedTEID(v2.IFTypeS1USGWGTPU, 0x66666666, "127.0.0.4", ""),
+		),
+	)
+	if err := sgwPC.WriteMessage(mbRsp); err != nil {
+		t.Fatal(err)
+	}
+	waitRsp()
+
+	if got, err := sess.GetTEID(v2.IFTypeS1USGWGTPU); err != nil || got != 0x66666666 {
+		t.Errorf("unexpected S1-U SGW TEID: got %#x, %v", got, err)
+	}
+	if got := br.OutgoingTEID(); got != 0x66666666 {
+		t.Errorf("unexpected OutgoingTEID: %#x", got)
+	}
+	if got := br.RemoteAddress().String(); got != "127.0.0.4:2152" {
+		t.Errorf("unexpected RemoteAddress: %s", got)
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import "github.com/wmnsk/go-gtp/v2/messages"
+
+// SetIEOrder sets the order of the IEs in all the messages sent from Conn, which is
+// applied when they are serialized. Some implementations reject the messages with
+// the IEs not in the order of TS 29.274; messages.IEOrderSpec arranges them so.
+//
+// The default is messages.IEOrderAsIs, which serializes the IEs in the order given.
+// The orders set with SetIEOrderFor take precedence over this.
+func (c *Conn) SetIEOrder(order messages.IEOrder) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ieOrder = order
+}
+
+// SetIEOrderFor sets the order of the IEs in the messages of msgType sent from Conn,
+// instead of the one set with SetIEOrder.
+func (c *Conn) SetIEOrderFor(msgType uint8, order messages.IEOrder) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ieOrders == nil {
+		c.ieOrders = map[uint8]messages.IEOrder{}
+	}
+	c.ieOrders[msgType] = order
+}
+
+// ieOrderFor returns the order of the IEs for the messages of msgType.
+func (c *Conn) ieOrderFor(msgType uint8) messages.IEOrder {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if order, ok := c.ieOrders[msgType]; ok {
+		return order
+	}
+	return c.ieOrder
+}
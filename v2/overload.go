@@ -0,0 +1,397 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+	"time"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// loadControl is the Load Control Information and Overload Control Information
+// advertised by the Peer (TS 29.274 12.2 and 12.3).
+//
+// The information is keyed by APN, and the empty string is used for the node level
+// one.
+type loadControl struct {
+	loads     map[string]*loadInfo
+	overloads map[string]*overloadInfo
+}
+
+type loadInfo struct {
+	seq    uint32
+	metric uint8
+}
+
+type overloadInfo struct {
+	seq    uint32
+	metric uint8
+	expiry time.Time
+}
+
+func newLoadControl() *loadControl {
+	return &loadControl{
+		loads:     map[string]*loadInfo{},
+		overloads: map[string]*overloadInfo{},
+	}
+}
+
+// updateLoad updates the load with Load Control Information IE. The one with the
+// Sequence Number not greater than the known one is ignored.
+func (l *loadControl) updateLoad(ie *ies.IE) error {
+	seq, metric, err := seqAndMetric(ie)
+	if err != nil {
+		return err
+	}
+
+	var apns []string
+	for _, child := range ie.ChildIEs {
+		if child.Type != ies.APNAndRelativeCapacity {
+			continue
+		}
+		apn, err := child.AccessPointName()
+		if err != nil {
+			return err
+		}
+		apns = append(apns, apn)
+	}
+	if len(apns) == 0 {
+		apns = []string{""}
+	}
+
+	for _, apn := range apns {
+		if known, ok := l.loads[apn]; ok && seq <= known.seq {
+			continue
+		}
+		l.loads[apn] = &loadInfo{seq: seq, metric: metric}
+	}
+	return nil
+}
+
+// updateOverload updates the overload with Overload Control Information IE. The one
+// with the Sequence Number not greater than the known one is ignored.
+func (l *loadControl) updateOverload(ie *ies.IE, now time.Time) error {
+	seq, metric, err := seqAndMetric(ie)
+	if err != nil {
+		return err
+	}
+
+	var (
+		validity time.Duration
+		apns     []string
+	)
+	for _, child := range ie.ChildIEs {
+		switch child.Type {
+		case ies.EPCTimer:
+			if validity, err = child.EPCTimer(); err != nil {
+				return err
+			}
+		case ies.AccessPointName:
+			apn, err := child.AccessPointName()
+			if err != nil {
+				return err
+			}
+			apns = append(apns, apn)
+		}
+	}
+	if len(apns) == 0 {
+		apns = []string{""}
+	}
+
+	expiry := time.Time{}
+	if validity != ies.EPCTimerInfinite {
+		expiry = now.Add(validity)
+	}
+	for _, apn := range apns {
+		if known, ok := l.overloads[apn]; ok && seq <= known.seq {
+			continue
+		}
+		l.overloads[apn] = &overloadInfo{seq: seq, metric: metric, expiry: expiry}
+	}
+	return nil
+}
+
+func seqAndMetric(ie *ies.IE) (seq uint32, metric uint8, err error) {
+	for _, child := range ie.ChildIEs {
+		switch child.Type {
+		case ies.SequenceNumber:
+			if seq, err = child.SequenceNumber(); err != nil {
+				return 0, 0, err
+			}
+		case ies.Metric:
+			if metric, err = child.Metric(); err != nil {
+				return 0, 0, err
+			}
+		}
+	}
+	return seq, metric, nil
+}
+
+// LoadMetric returns the Load Metric advertised by the Peer for apn. The node level
+// one is returned if the APN level one is not advertised for apn, or apn is empty.
+// The second returned value is false if none of them is advertised.
+//
+// This is only available after (*Conn) EnableLoadControl is called.
+func (p *Peer) LoadMetric(apn string) (uint8, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.loadControl == nil {
+		return 0, false
+	}
+	if l, ok := p.loadControl.loads[apn]; ok {
+		return l.metric, true
+	}
+	if l, ok := p.loadControl.loads[""]; ok {
+		return l.metric, true
+	}
+	return 0, false
+}
+
+// OverloadReductionMetric returns the Overload Reduction Metric advertised by the
+// Peer for apn, which is the larger one of the node level one and the APN level one
+// for apn. The overload whose Period of Validity is expired is ignored.
+// The second returned value is false if the Peer is not in overload.
+//
+// This is only available after (*Conn) EnableLoadControl is called.
+func (p *Peer) OverloadReductionMetric(apn string) (uint8, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.loadControl == nil {
+		return 0, false
+	}
+
+	var (
+		metric uint8
+		found  bool
+		now    = time.Now()
+	)
+	keys := []string{""}
+	if apn != "" {
+		keys = append(keys, apn)
+	}
+	for _, key := range keys {
+		o, ok := p.loadControl.overloads[key]
+		if !ok || (!o.expiry.IsZero() && now.After(o.expiry)) {
+			continue
+		}
+		found = true
+		if o.metric > metric {
+			metric = o.metric
+		}
+	}
+	return metric, found
+}
+
+// updateLoadControl updates the Peer with the Load Control Information and Overload
+// Control Information IEs in decodedIEs.
+func (p *Peer) updateLoadControl(decodedIEs []*ies.IE, restarted bool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// the Sequence Numbers are not kept over the restart of the Peer.
+	if p.loadControl == nil || restarted {
+		p.loadControl = newLoadControl()
+	}
+
+	now := time.Now()
+	for _, ie := range decodedIEs {
+		switch ie.Type {
+		case ies.LoadControlInformation:
+			if err := p.loadControl.updateLoad(ie); err != nil {
+				return err
+			}
+		case ies.OverloadControlInformation:
+			if err := p.loadControl.updateOverload(ie, now); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// EnableLoadControl turns on tracking of the Load Control Information and Overload
+// Control Information IEs in the incoming messages.
+//
+// The information is kept per Peer and APN, and can be retrieved with (*Peer)
+// LoadMetric and (*Peer) OverloadReductionMetric, or used with ShouldThrottle. Note
+// that the information is kept in the Peer that sent the message, even if it is
+// the one of the other node relayed by the Peer, e.g., the PGW's one sent by SGW.
+//
+// This is disabled by default, as it requires decoding all the IEs in the messages.
+func (c *Conn) EnableLoadControl() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.loadControlEnabled = true
+}
+
+// DisableLoadControl turns off tracking of the Load Control Information and
+// Overload Control Information IEs in the incoming messages. The information kept
+// in the Peers is not cleared.
+func (c *Conn) DisableLoadControl() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.loadControlEnabled = false
+}
+
+func (c *Conn) isLoadControlEnabled() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.loadControlEnabled
+}
+
+// handleLoadControl updates the Peer of senderAddr with the Load Control Information
+// and Overload Control Information IEs in msg if enabled.
+func (c *Conn) handleLoadControl(senderAddr net.Addr, msg messages.Message, restarted bool) error {
+	if !c.isLoadControlEnabled() {
+		return nil
+	}
+
+	decodedIEs, err := messageIEs(msg)
+	if err != nil {
+		return err
+	}
+	return c.peer(senderAddr).updateLoadControl(decodedIEs, restarted)
+}
+
+// ShouldThrottle reports whether the message with priority for apn should not be
+// sent to the peer at raddr, based on the Overload Reduction Metric advertised by it.
+//
+// priority is the one in the range of 0 to 15 as in the Message Priority in the
+// header, where the lower value means the higher priority. When the Peer is in
+// overload with the Overload Reduction Metric of N percent, the messages with the
+// lowest priorities that correspond to N percent of the range are throttled, i.e.,
+// none of them are throttled with 0, and all of them are throttled with 100.
+// Give empty apn to check only the node level overload.
+//
+// It always returns false if the peer is unknown or (*Conn) EnableLoadControl is not
+// called.
+func (c *Conn) ShouldThrottle(raddr net.Addr, apn string, priority uint8) bool {
+	peer, err := c.GetPeer(raddr)
+	if err != nil {
+		return false
+	}
+	metric, ok := peer.OverloadReductionMetric(apn)
+	if !ok {
+		return false
+	}
+	return shouldThrottle(metric, priority)
+}
+
+func shouldThrottle(metric, priority uint8) bool {
+	if metric > 100 {
+		metric = 100
+	}
+	if priority > 15 {
+		priority = 15
+	}
+
+	// the number of priorities to be throttled, rounded up.
+	n := (16*int(metric) + 99) / 100
+	return int(priority) >= 16-n
+}
+
+// SetLocalLoadControlInformation sets the Load Control Information and Overload
+// Control Information IEs of this node, which are attached to the outgoing messages
+// of msgTypes automatically. Giving no IEs stops attaching them.
+//
+// The IEs should have the instance for this node, e.g., 2 for the SGW's node level
+// Load Control Information in Create Session Response. The IE is not attached if msg
+// already has the IE of the same type and instance. To advertise the new load, call
+// this again with the IEs that have the incremented Sequence Number.
+func (c *Conn) SetLocalLoadControlInformation(msgTypes []uint8, ie ...*ies.IE) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(ie) == 0 {
+		c.localLoadControl = nil
+		return
+	}
+
+	c.localLoadControl = map[uint8][]*ies.IE{}
+	for _, msgType := range msgTypes {
+		c.localLoadControl[msgType] = append([]*ies.IE{}, ie...)
+	}
+}
+
+// LoadControlFunc is a function that returns the Load Control Information and
+// Overload Control Information IEs of this node to be attached to msg sent to raddr.
+//
+// It is called for every message sent with SendMessageTo and RespondTo, including
+// the ones sent by the helpers, and should return nil for the messages that should
+// not carry them. The rules for the IEs are the same as the ones given to
+// SetLocalLoadControlInformation.
+type LoadControlFunc func(raddr net.Addr, msg messages.Message) []*ies.IE
+
+// SetLoadControlFunc sets the LoadControlFunc, which is used to attach the Load
+// Control Information and Overload Control Information IEs that reflect the current
+// load of this node, e.g., the ones with the Sequence Number incremented whenever the
+// metric changes (TS 29.274 12.2.5.1.2.3 and 12.3.5.1.2.3). Giving nil stops calling
+// it.
+//
+// The IEs returned are attached after the ones set by SetLocalLoadControlInformation,
+// and are skipped if the same type and instance is already attached.
+func (c *Conn) SetLoadControlFunc(fn LoadControlFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.loadControlFn = fn
+}
+
+func (c *Conn) localLoadControlFor(raddr net.Addr, msg messages.Message) []*ies.IE {
+	c.mu.Lock()
+	toAttach := c.localLoadControl[msg.MessageType()]
+	fn := c.loadControlFn
+	c.mu.Unlock()
+
+	if fn == nil {
+		return toAttach
+	}
+
+	// copy not to modify the ones kept in Conn.
+	toAttach = append([]*ies.IE{}, toAttach...)
+	for _, ie := range fn(raddr, msg) {
+		if ie != nil {
+			toAttach = append(toAttach, ie)
+		}
+	}
+	return toAttach
+}
+
+// withLocalLoadControl returns the message with the IEs set by
+// SetLocalLoadControlInformation and returned by LoadControlFunc attached. It returns
+// msg as it is if nothing is to be attached or it fails to attach them.
+func (c *Conn) withLocalLoadControl(msg messages.Message, raddr net.Addr) messages.Message {
+	toAttach := c.localLoadControlFor(raddr, msg)
+	if len(toAttach) == 0 {
+		return msg
+	}
+
+	present, err := presentIEs(msg)
+	if err != nil {
+		return msg
+	}
+
+	var attached []*ies.IE
+	for _, ie := range toAttach {
+		key := MandatoryIE{ie.Type, ie.Instance()}
+		if _, ok := present[key]; ok {
+			continue
+		}
+		attached = append(attached, ie)
+		present[key] = ie
+	}
+	if len(attached) == 0 {
+		return msg
+	}
+
+	m, err := withIEs(msg, attached...)
+	if err != nil {
+		return msg
+	}
+	return m
+}
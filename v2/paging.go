@@ -0,0 +1,116 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+	"time"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// SetDownlinkDataNotificationDelay stops sending the Downlink Data Notification for
+// the Session with (*Conn) DownlinkDataNotification for the duration given.
+// Giving 0 or negative value clears the delay.
+//
+// This is set automatically when Conn receives the Downlink Data Notification
+// Acknowledge with Data Notification Delay for the Session.
+func (s *Session) SetDownlinkDataNotificationDelay(delay time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if delay <= 0 {
+		s.ddnDelayedUntil = time.Time{}
+		return
+	}
+	s.ddnDelayedUntil = time.Now().Add(delay)
+}
+
+// DownlinkDataNotificationDelay returns the remaining duration that the Downlink Data
+// Notification for the Session should be delayed, or 0 if not delayed.
+func (s *Session) DownlinkDataNotificationDelay() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if d := time.Until(s.ddnDelayedUntil); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// DownlinkDataNotification sends a DownlinkDataNotification with TEID and IEs given.
+//
+// This is typically used by SGW to trigger the paging of the UE in idle mode. Use
+// ies.NewAllocationRetensionPriority and ies.NewPagingAndServiceInformation to give
+// the ARP and the Paging Policy Indication of the bearer that received the downlink
+// data.
+//
+// TS 29.274 7.2.11.2: the MME may request SGW to delay the Downlink Data Notification
+// with Data Notification Delay. While it is delayed, this returns ErrNotificationDelayed
+// without sending anything. The remaining duration can be retrieved with
+// (*Session) DownlinkDataNotificationDelay to schedule the later attempt.
+func (c *Conn) DownlinkDataNotification(teid uint32, raddr net.Addr, ie ...*ies.IE) (uint32, error) {
+	sess, err := c.GetSessionByTEID(teid, raddr)
+	if err != nil {
+		return 0, err
+	}
+	if sess.DownlinkDataNotificationDelay() > 0 {
+		return 0, ErrNotificationDelayed
+	}
+
+	msg := messages.NewDownlinkDataNotification(teid, 0, ie...)
+
+	seq, err := c.SendMessageTo(msg, sess.PeerAddr())
+	if err != nil {
+		return 0, err
+	}
+	return seq, nil
+}
+
+// DownlinkDataNotificationAcknowledge sends a DownlinkDataNotificationAcknowledge with
+// TEID and IEs given in response to the DownlinkDataNotification.
+//
+// Give ies.NewDelayValue to request the sender to delay the subsequent notifications.
+func (c *Conn) DownlinkDataNotificationAcknowledge(teid uint32, raddr net.Addr, req messages.Message, ie ...*ies.IE) error {
+	return c.RespondTo(raddr, req, messages.NewDownlinkDataNotificationAcknowledge(teid, 0, ie...))
+}
+
+// DownlinkDataNotificationFailureIndication sends a
+// DownlinkDataNotificationFailureIndication with TEID and IEs given, which is used by
+// MME to tell SGW that the paging of the UE has failed.
+func (c *Conn) DownlinkDataNotificationFailureIndication(teid uint32, raddr net.Addr, ie ...*ies.IE) (uint32, error) {
+	sess, err := c.GetSessionByTEID(teid, raddr)
+	if err != nil {
+		return 0, err
+	}
+
+	msg := messages.NewDownlinkDataNotificationFailureIndication(teid, 0, ie...)
+
+	seq, err := c.SendMessageTo(msg, sess.PeerAddr())
+	if err != nil {
+		return 0, err
+	}
+	return seq, nil
+}
+
+// handleDataNotificationDelay sets the delay to the Session if msg is the Downlink Data
+// Notification Acknowledge with Data Notification Delay.
+func (c *Conn) handleDataNotificationDelay(senderAddr net.Addr, msg messages.Message) {
+	ack, ok := msg.(*messages.DownlinkDataNotificationAcknowledge)
+	if !ok || ack.DataNotificationDelay == nil {
+		return
+	}
+
+	delay, err := ack.DataNotificationDelay.DelayValue()
+	if err != nil {
+		return
+	}
+	sess, err := c.GetSessionByTEID(ack.TEID(), senderAddr)
+	if err != nil {
+		return
+	}
+	sess.SetDownlinkDataNotificationDelay(delay)
+}
@@ -0,0 +1,28 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import "github.com/wmnsk/go-gtp/v2/messages"
+
+// SetParseMode sets the ParseMode used to decode the incoming messages.
+//
+// With messages.ParseModeLenient, the messages with malformed IEs are passed to the
+// HandlerFuncs with those IEs kept as raw ones in AdditionalIEs. With
+// messages.ParseModeStrict, the messages with malformed or unknown IEs are discarded
+// and the MalformedIEError or UnknownIEError is passed to the background error
+// handling, and they are counted as ParseErrors in Stats.
+func (c *Conn) SetParseMode(mode messages.ParseMode) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.parseMode = mode
+}
+
+func (c *Conn) mode() messages.ParseMode {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.parseMode
+}
@@ -0,0 +1,109 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// EndMarkerFunc is a function to send the End Marker packets on the old downlink
+// path of the Bearer, which is called by (*Session) SwitchDownlinkPath.
+//
+// oldAddr and oldTEID are the remote address and TEID of the Bearer before the
+// switch. As GTPv2-C does not handle the user plane, the function is expected to send
+// the End Markers with the U-plane implementation used along with Conn, e.g., the
+// one in v1 package.
+type EndMarkerFunc func(sess *Session, br *Bearer, oldAddr net.Addr, oldTEID uint32) error
+
+// SetEndMarkerFunc registers the EndMarkerFunc to be called when the downlink path of
+// the Bearer is switched by (*Session) SwitchDownlinkPath.
+//
+// The End Markers are not sent by default, and giving nil disables it again.
+func (c *Conn) SetEndMarkerFunc(fn EndMarkerFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.endMarkerFn = fn
+}
+
+func (c *Conn) endMarkerFunc() EndMarkerFunc {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.endMarkerFn
+}
+
+// SwitchDownlinkPath switches the downlink path of the Bearer with ebi to the one
+// given by newENBFTEID, which is the typical procedure of X2/S1-based handover.
+//
+// It sends a ModifyBearerRequest with the Bearer Context that has the EBI and the new
+// F-TEID, together with the IEs given, to the peer of Session using the TEID of
+// S11/S4 SGW. After the request is sent, the F-TEID is set to the Session and the
+// Bearer in the same way as (*Session) ModifyAccessBearers, and the EndMarkerFunc
+// registered with (*Conn) SetEndMarkerFunc is called with the old path of the Bearer.
+//
+// newENBFTEID must have the InterfaceType of the access side, e.g.,
+// IFTypeS1UeNodeBGTPU. It returns BearerNotFoundError if the Bearer cannot be found.
+func (s *Session) SwitchDownlinkPath(c *Conn, ebi uint8, newENBFTEID *ies.IE, ie ...*ies.IE) (uint32, error) {
+	br, err := s.LookupBearerByEBI(ebi)
+	if err != nil {
+		return 0, err
+	}
+	if err := validateAccessFTEID(newENBFTEID); err != nil {
+		return 0, err
+	}
+	teid, err := s.GetTEID(IFTypeS11S4SGWGTPC)
+	if err != nil {
+		return 0, err
+	}
+
+	br.mu.Lock()
+	oldAddr, oldTEID := br.raddr, br.teidOut
+	br.mu.Unlock()
+
+	msg := messages.NewModifyBearerRequest(
+		teid, 0,
+		append([]*ies.IE{ies.NewBearerContext(ies.NewEPSBearerID(ebi), newENBFTEID)}, ie...)...,
+	)
+	seq, err := c.SendMessageTo(msg, s.PeerAddr())
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.modifyAccessBearer(br, newENBFTEID); err != nil {
+		return 0, err
+	}
+
+	fn := c.endMarkerFunc()
+	if fn == nil || oldAddr == nil {
+		return seq, nil
+	}
+	if err := fn(s, br, oldAddr, oldTEID); err != nil {
+		return 0, err
+	}
+	return seq, nil
+}
+
+// validateAccessFTEID checks if fteid is the valid F-TEID on the access side.
+func validateAccessFTEID(fteid *ies.IE) error {
+	if fteid == nil || fteid.Type != ies.FullyQualifiedTEID {
+		return &RequiredParameterMissingError{"F-TEID", "F-TEID of the new downlink path must be given"}
+	}
+	it, err := fteid.InterfaceType()
+	if err != nil {
+		return err
+	}
+	if !isAccessIFType(it) {
+		return &RequiredParameterMissingError{"F-TEID", "F-TEID must be the one on the access side"}
+	}
+	if _, err := fteid.TEID(); err != nil {
+		return err
+	}
+	if _, err := fteid.IPAddress(); err != nil {
+		return err
+	}
+	return nil
+}
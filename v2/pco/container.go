@@ -0,0 +1,176 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package pco
+
+import (
+	"encoding/binary"
+	"net"
+)
+
+// Protocol ID definitions.
+const (
+	ProtocolIDLCP  uint16 = 0xc021
+	ProtocolIDPAP  uint16 = 0xc023
+	ProtocolIDCHAP uint16 = 0xc223
+	ProtocolIDIPCP uint16 = 0x8021
+)
+
+// Container ID definitions.
+//
+// The same ID is used for the request in MS to network direction and the response to
+// it in network to MS direction, e.g., ContainerIDDNSServerIPv4Address is the DNS
+// Server IPv4 Address Request without contents from the UE, and the DNS Server IPv4
+// Address with an address from the network.
+const (
+	ContainerIDPCSCFIPv6Address                   uint16 = 0x0001
+	ContainerIDIMCNSubsystemSignalingFlag         uint16 = 0x0002
+	ContainerIDDNSServerIPv6Address               uint16 = 0x0003
+	ContainerIDPolicyControlRejectionCode         uint16 = 0x0004
+	ContainerIDSelectedBearerControlMode          uint16 = 0x0005
+	ContainerIDIPAddressAllocationViaNASSignaling uint16 = 0x000a
+	ContainerIDIPv4AddressAllocationViaDHCPv4     uint16 = 0x000b
+	ContainerIDPCSCFIPv4Address                   uint16 = 0x000c
+	ContainerIDDNSServerIPv4Address               uint16 = 0x000d
+	ContainerIDMSISDN                             uint16 = 0x000e
+	ContainerIDIPv4LinkMTU                        uint16 = 0x0010
+	ContainerIDLocalAddressInTFTIndicator         uint16 = 0x0011
+	ContainerIDPCSCFReselectionSupport            uint16 = 0x0012
+	ContainerIDNonIPLinkMTU                       uint16 = 0x0015
+)
+
+// Bearer Control Mode definitions.
+const (
+	BearerControlModeMSOnly uint8 = iota + 1
+	BearerControlModeMSAndNetwork
+)
+
+// Container is a configuration protocol option or additional parameter in PCO,
+// identified by the Protocol ID or Container ID.
+type Container struct {
+	ID       uint16
+	Contents []byte
+}
+
+// NewContainer creates a new Container.
+func NewContainer(id uint16, contents []byte) *Container {
+	return &Container{ID: id, Contents: contents}
+}
+
+// NewRequest creates a new Container without contents, which is used by the UE to
+// request the configuration identified by id.
+func NewRequest(id uint16) *Container {
+	return NewContainer(id, nil)
+}
+
+// NewDNSServerIPv4Address creates a new DNS Server IPv4 Address Container.
+func NewDNSServerIPv4Address(ip net.IP) *Container {
+	return NewContainer(ContainerIDDNSServerIPv4Address, ip.To4())
+}
+
+// NewDNSServerIPv6Address creates a new DNS Server IPv6 Address Container.
+func NewDNSServerIPv6Address(ip net.IP) *Container {
+	return NewContainer(ContainerIDDNSServerIPv6Address, ip.To16())
+}
+
+// NewPCSCFIPv4Address creates a new P-CSCF IPv4 Address Container.
+func NewPCSCFIPv4Address(ip net.IP) *Container {
+	return NewContainer(ContainerIDPCSCFIPv4Address, ip.To4())
+}
+
+// NewPCSCFIPv6Address creates a new P-CSCF IPv6 Address Container.
+func NewPCSCFIPv6Address(ip net.IP) *Container {
+	return NewContainer(ContainerIDPCSCFIPv6Address, ip.To16())
+}
+
+// NewIPv4LinkMTU creates a new IPv4 Link MTU Container.
+func NewIPv4LinkMTU(mtu uint16) *Container {
+	b := make([]byte, 2)
+	binary.BigEndian.PutUint16(b, mtu)
+	return NewContainer(ContainerIDIPv4LinkMTU, b)
+}
+
+// NewSelectedBearerControlMode creates a new Selected Bearer Control Mode Container.
+func NewSelectedBearerControlMode(mode uint8) *Container {
+	return NewContainer(ContainerIDSelectedBearerControlMode, []byte{mode})
+}
+
+// NewIPCPContainer creates a new Container of IPCP with the packet given.
+func NewIPCPContainer(ipcp *IPCP) *Container {
+	b, err := ipcp.Marshal()
+	if err != nil {
+		return nil
+	}
+	return NewContainer(ProtocolIDIPCP, b)
+}
+
+// IsRequest reports whether the Container has no contents, i.e., it is the request
+// from the UE.
+func (c *Container) IsRequest() bool {
+	return len(c.Contents) == 0
+}
+
+// IP returns the address in the Container of DNS Server or P-CSCF.
+func (c *Container) IP() (net.IP, error) {
+	switch c.ID {
+	case ContainerIDDNSServerIPv4Address, ContainerIDPCSCFIPv4Address:
+		if len(c.Contents) != net.IPv4len {
+			return nil, ErrInvalidLength
+		}
+	case ContainerIDDNSServerIPv6Address, ContainerIDPCSCFIPv6Address:
+		if len(c.Contents) != net.IPv6len {
+			return nil, ErrInvalidLength
+		}
+	default:
+		return nil, ErrContainerNotFound
+	}
+	return net.IP(c.Contents), nil
+}
+
+// MTU returns the MTU in the Container of IPv4 Link MTU or Non-IP Link MTU.
+func (c *Container) MTU() (uint16, error) {
+	switch c.ID {
+	case ContainerIDIPv4LinkMTU, ContainerIDNonIPLinkMTU:
+	default:
+		return 0, ErrContainerNotFound
+	}
+	if len(c.Contents) != 2 {
+		return 0, ErrInvalidLength
+	}
+	return binary.BigEndian.Uint16(c.Contents), nil
+}
+
+// IPCP returns the IPCP packet in the Container of IPCP.
+func (c *Container) IPCP() (*IPCP, error) {
+	if c.ID != ProtocolIDIPCP {
+		return nil, ErrContainerNotFound
+	}
+	return ParseIPCP(c.Contents)
+}
+
+func (c *Container) marshalTo(b []byte, extended bool) error {
+	if len(b) < c.marshalLen(extended) {
+		return ErrTooShortToParse
+	}
+
+	binary.BigEndian.PutUint16(b[0:2], c.ID)
+	if extended {
+		binary.BigEndian.PutUint16(b[2:4], uint16(len(c.Contents)))
+		copy(b[4:], c.Contents)
+		return nil
+	}
+	if len(c.Contents) > 0xff {
+		return ErrInvalidLength
+	}
+	b[2] = uint8(len(c.Contents))
+	copy(b[3:], c.Contents)
+	return nil
+}
+
+func (c *Container) marshalLen(extended bool) int {
+	if extended {
+		return 4 + len(c.Contents)
+	}
+	return 3 + len(c.Contents)
+}
@@ -0,0 +1,156 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package pco
+
+import (
+	"encoding/binary"
+	"net"
+)
+
+// IPCP Code definitions.
+const (
+	IPCPCodeConfigureRequest uint8 = iota + 1
+	IPCPCodeConfigureAck
+	IPCPCodeConfigureNak
+	IPCPCodeConfigureReject
+)
+
+// IPCP Option Type definitions.
+// See RFC 1332 and RFC 1877.
+const (
+	IPCPOptionIPAddress           uint8 = 3
+	IPCPOptionPrimaryDNSServer    uint8 = 129
+	IPCPOptionPrimaryNBNSServer   uint8 = 130
+	IPCPOptionSecondaryDNSServer  uint8 = 131
+	IPCPOptionSecondaryNBNSServer uint8 = 132
+)
+
+// IPCP is the packet of the PPP Internet Protocol Control Protocol in the Container
+// of IPCP defined in RFC 1332.
+type IPCP struct {
+	Code       uint8
+	Identifier uint8
+	Options    []*IPCPOption
+}
+
+// IPCPOption is a configuration option in IPCP.
+type IPCPOption struct {
+	Type  uint8
+	Value []byte
+}
+
+// NewIPCP creates a new IPCP.
+func NewIPCP(code, identifier uint8, opts ...*IPCPOption) *IPCP {
+	return &IPCP{Code: code, Identifier: identifier, Options: opts}
+}
+
+// NewIPCPOption creates a new IPCPOption with IPv4 address, which is the value of
+// all the options defined.
+func NewIPCPOption(typ uint8, ip net.IP) *IPCPOption {
+	return &IPCPOption{Type: typ, Value: ip.To4()}
+}
+
+// ParseIPCP decodes the given bytes as IPCP.
+func ParseIPCP(b []byte) (*IPCP, error) {
+	i := &IPCP{}
+	if err := i.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+// UnmarshalBinary decodes given bytes into IPCP.
+func (i *IPCP) UnmarshalBinary(b []byte) error {
+	if len(b) < 4 {
+		return ErrTooShortToParse
+	}
+	i.Code = b[0]
+	i.Identifier = b[1]
+
+	l := int(binary.BigEndian.Uint16(b[2:4]))
+	if l < 4 || len(b) < l {
+		return ErrInvalidLength
+	}
+
+	i.Options = nil
+	offset := 4
+	for offset < l {
+		if l-offset < 2 {
+			return ErrTooShortToParse
+		}
+		// the Length of option includes the Type and Length fields.
+		ol := int(b[offset+1])
+		if ol < 2 || l-offset < ol {
+			return ErrInvalidLength
+		}
+		opt := &IPCPOption{Type: b[offset]}
+		if ol > 2 {
+			opt.Value = make([]byte, ol-2)
+			copy(opt.Value, b[offset+2:offset+ol])
+		}
+		i.Options = append(i.Options, opt)
+		offset += ol
+	}
+	return nil
+}
+
+// Marshal serializes IPCP.
+func (i *IPCP) Marshal() ([]byte, error) {
+	b := make([]byte, i.MarshalLen())
+	if err := i.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MarshalTo serializes IPCP.
+func (i *IPCP) MarshalTo(b []byte) error {
+	l := i.MarshalLen()
+	if len(b) < l {
+		return ErrTooShortToParse
+	}
+
+	b[0] = i.Code
+	b[1] = i.Identifier
+	binary.BigEndian.PutUint16(b[2:4], uint16(l))
+	offset := 4
+	for _, opt := range i.Options {
+		if len(opt.Value) > 0xff-2 {
+			return ErrInvalidLength
+		}
+		b[offset] = opt.Type
+		b[offset+1] = uint8(2 + len(opt.Value))
+		copy(b[offset+2:], opt.Value)
+		offset += 2 + len(opt.Value)
+	}
+	return nil
+}
+
+// MarshalLen returns the serial length of IPCP in int.
+func (i *IPCP) MarshalLen() int {
+	l := 4
+	for _, opt := range i.Options {
+		l += 2 + len(opt.Value)
+	}
+	return l
+}
+
+// Option returns the first option with the type given.
+func (i *IPCP) Option(typ uint8) (*IPCPOption, error) {
+	for _, opt := range i.Options {
+		if opt.Type == typ {
+			return opt, nil
+		}
+	}
+	return nil, ErrContainerNotFound
+}
+
+// IP returns the IPv4 address in the option.
+func (o *IPCPOption) IP() (net.IP, error) {
+	if len(o.Value) != net.IPv4len {
+		return nil, ErrInvalidLength
+	}
+	return net.IP(o.Value), nil
+}
@@ -0,0 +1,279 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package pco provides the structured encoders and decoders of the Protocol
+// Configuration Options (PCO) defined in TS 24.008 10.5.6.3 and the Extended
+// Protocol Configuration Options (ePCO) defined in TS 24.301 9.9.4.26, which are
+// carried opaquely in the IEs of GTPv2-C.
+//
+// PGW typically decodes the PCO in Create Session Request, and responds with the
+// one built by Respond with the configurations requested by the UE.
+//
+//	req, err := pco.FromIE(csReq.ProtocolConfigurationOptions)
+//	if err != nil {
+//		// ...
+//	}
+//	rsp := pco.Respond(req, &pco.Offer{
+//		DNSServers:  []net.IP{net.ParseIP("8.8.8.8"), net.ParseIP("2001:4860:4860::8888")},
+//		IPv4LinkMTU: 1400,
+//	})
+//	csRsp := messages.NewCreateSessionResponse(teid, 0, rsp.IE(), ...)
+package pco
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+)
+
+// Error definitions.
+var (
+	ErrTooShortToParse   = errors.New("too short to decode as PCO")
+	ErrInvalidLength     = errors.New("length value is invalid")
+	ErrContainerNotFound = errors.New("could not find the specified container in PCO")
+)
+
+// ConfigProtocolPPPWithIP is the Configuration protocol that is the only one defined.
+const ConfigProtocolPPPWithIP uint8 = 0
+
+// PCO is the Protocol Configuration Options, or the Extended Protocol Configuration
+// Options if Extended is true.
+//
+// They are the same except that the length of each container is 1 octet in PCO and 2
+// octets in ePCO.
+type PCO struct {
+	Extended              bool
+	ConfigurationProtocol uint8
+	Containers            []*Container
+}
+
+// New creates a new PCO with the containers given.
+func New(containers ...*Container) *PCO {
+	return &PCO{
+		ConfigurationProtocol: ConfigProtocolPPPWithIP,
+		Containers:            containers,
+	}
+}
+
+// NewExtended creates a new ePCO with the containers given.
+func NewExtended(containers ...*Container) *PCO {
+	p := New(containers...)
+	p.Extended = true
+	return p
+}
+
+// Parse decodes the given bytes as PCO.
+func Parse(b []byte) (*PCO, error) {
+	p := &PCO{}
+	if err := p.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ParseExtended decodes the given bytes as ePCO.
+func ParseExtended(b []byte) (*PCO, error) {
+	p := &PCO{Extended: true}
+	if err := p.UnmarshalBinary(b); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// FromIE decodes the payload of the ProtocolConfigurationOptions,
+// AdditionalProtocolConfigurationOptions or ExtendedProtocolConfigurationOptions IE.
+func FromIE(i *ies.IE) (*PCO, error) {
+	if i == nil {
+		return nil, ies.ErrIENotFound
+	}
+
+	switch i.Type {
+	case ies.ProtocolConfigurationOptions, ies.AdditionalProtocolConfigurationOptions:
+		return Parse(i.Payload)
+	case ies.ExtendedProtocolConfigurationOptions:
+		return ParseExtended(i.Payload)
+	default:
+		return nil, &ies.InvalidTypeError{Type: i.Type}
+	}
+}
+
+// UnmarshalBinary decodes given bytes into PCO. Whether the length of the containers
+// is 1 or 2 octets depends on Extended.
+func (p *PCO) UnmarshalBinary(b []byte) error {
+	if len(b) == 0 {
+		return ErrTooShortToParse
+	}
+	p.ConfigurationProtocol = b[0] & 0x07
+
+	p.Containers = nil
+	offset := 1
+	for offset < len(b) {
+		c, n, err := parseContainer(b[offset:], p.Extended)
+		if err != nil {
+			return err
+		}
+		p.Containers = append(p.Containers, c)
+		offset += n
+	}
+	return nil
+}
+
+// Marshal serializes PCO.
+func (p *PCO) Marshal() ([]byte, error) {
+	b := make([]byte, p.MarshalLen())
+	if err := p.MarshalTo(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MarshalTo serializes PCO.
+func (p *PCO) MarshalTo(b []byte) error {
+	if len(b) < p.MarshalLen() {
+		return ErrTooShortToParse
+	}
+
+	// the extension bit is always set.
+	b[0] = (p.ConfigurationProtocol & 0x07) | 0x80
+	offset := 1
+	for _, c := range p.Containers {
+		if err := c.marshalTo(b[offset:], p.Extended); err != nil {
+			return err
+		}
+		offset += c.marshalLen(p.Extended)
+	}
+	return nil
+}
+
+// MarshalLen returns the serial length of PCO in int.
+func (p *PCO) MarshalLen() int {
+	l := 1
+	for _, c := range p.Containers {
+		l += c.marshalLen(p.Extended)
+	}
+	return l
+}
+
+// IE returns the ProtocolConfigurationOptions IE with PCO, or the
+// ExtendedProtocolConfigurationOptions IE if Extended is true.
+func (p *PCO) IE() *ies.IE {
+	b, err := p.Marshal()
+	if err != nil {
+		return nil
+	}
+
+	if p.Extended {
+		return ies.New(ies.ExtendedProtocolConfigurationOptions, 0x00, b)
+	}
+	return ies.New(ies.ProtocolConfigurationOptions, 0x00, b)
+}
+
+// Container returns the first container with the ID given.
+func (p *PCO) Container(id uint16) (*Container, error) {
+	for _, c := range p.Containers {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return nil, ErrContainerNotFound
+}
+
+// ContainersByID returns all the containers with the ID given.
+func (p *PCO) ContainersByID(id uint16) []*Container {
+	var cs []*Container
+	for _, c := range p.Containers {
+		if c.ID == id {
+			cs = append(cs, c)
+		}
+	}
+	return cs
+}
+
+// Has reports whether PCO has the container with the ID given, which is typically
+// used to check if the UE requests the configuration.
+func (p *PCO) Has(id uint16) bool {
+	_, err := p.Container(id)
+	return err == nil
+}
+
+func parseContainer(b []byte, extended bool) (*Container, int, error) {
+	hlen := 3
+	if extended {
+		hlen = 4
+	}
+	if len(b) < hlen {
+		return nil, 0, ErrTooShortToParse
+	}
+
+	c := &Container{ID: binary.BigEndian.Uint16(b[0:2])}
+	l := int(b[2])
+	if extended {
+		l = int(binary.BigEndian.Uint16(b[2:4]))
+	}
+	if len(b) < hlen+l {
+		return nil, 0, ErrInvalidLength
+	}
+	if l != 0 {
+		c.Contents = make([]byte, l)
+		copy(c.Contents, b[hlen:hlen+l])
+	}
+	return c, hlen + l, nil
+}
+
+// DNSServers returns the addresses of the DNS servers in PCO, including the ones in
+// the IPCP options of Configure-Ack and Configure-Nak.
+func (p *PCO) DNSServers() []net.IP {
+	var ips []net.IP
+	for _, c := range p.Containers {
+		switch c.ID {
+		case ContainerIDDNSServerIPv4Address, ContainerIDDNSServerIPv6Address:
+			if ip, err := c.IP(); err == nil {
+				ips = append(ips, ip)
+			}
+		case ProtocolIDIPCP:
+			ipcp, err := c.IPCP()
+			if err != nil {
+				continue
+			}
+			if ipcp.Code != IPCPCodeConfigureAck && ipcp.Code != IPCPCodeConfigureNak {
+				continue
+			}
+			for _, typ := range []uint8{IPCPOptionPrimaryDNSServer, IPCPOptionSecondaryDNSServer} {
+				opt, err := ipcp.Option(typ)
+				if err != nil {
+					continue
+				}
+				if ip, err := opt.IP(); err == nil {
+					ips = append(ips, ip)
+				}
+			}
+		}
+	}
+	return ips
+}
+
+// PCSCFAddresses returns the addresses of the P-CSCFs in PCO.
+func (p *PCO) PCSCFAddresses() []net.IP {
+	var ips []net.IP
+	for _, c := range p.Containers {
+		switch c.ID {
+		case ContainerIDPCSCFIPv4Address, ContainerIDPCSCFIPv6Address:
+			if ip, err := c.IP(); err == nil {
+				ips = append(ips, ip)
+			}
+		}
+	}
+	return ips
+}
+
+// IPv4LinkMTU returns the MTU in the IPv4 Link MTU container in PCO.
+func (p *PCO) IPv4LinkMTU() (uint16, error) {
+	c, err := p.Container(ContainerIDIPv4LinkMTU)
+	if err != nil {
+		return 0, err
+	}
+	return c.MTU()
+}
@@ -0,0 +1,134 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package pco_test
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/pascaldekloe/goe/verify"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/pco"
+)
+
+var request = []byte{
+	// ConfigurationProtocol
+	0x80,
+	// IPCP Configure-Request with Primary and Secondary DNS Server
+	0x80, 0x21, 0x10, 0x01, 0x00, 0x00, 0x10, 0x81, 0x06, 0x00, 0x00, 0x00, 0x00, 0x83, 0x06, 0x00, 0x00, 0x00, 0x00,
+	// MS Support of Network Requested Bearer Control indicator
+	0x00, 0x05, 0x00,
+	// DNS Server IPv4 Address Request
+	0x00, 0x0d, 0x00,
+	// DNS Server IPv6 Address Request
+	0x00, 0x03, 0x00,
+	// IPv4 Link MTU Request
+	0x00, 0x10, 0x00,
+}
+
+func TestParse(t *testing.T) {
+	p, err := pco.Parse(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := pco.New(
+		pco.NewIPCPContainer(pco.NewIPCP(
+			pco.IPCPCodeConfigureRequest, 0,
+			pco.NewIPCPOption(pco.IPCPOptionPrimaryDNSServer, net.IPv4zero),
+			pco.NewIPCPOption(pco.IPCPOptionSecondaryDNSServer, net.IPv4zero),
+		)),
+		pco.NewRequest(pco.ContainerIDSelectedBearerControlMode),
+		pco.NewRequest(pco.ContainerIDDNSServerIPv4Address),
+		pco.NewRequest(pco.ContainerIDDNSServerIPv6Address),
+		pco.NewRequest(pco.ContainerIDIPv4LinkMTU),
+	)
+	if !verify.Values(t, "", p, want) {
+		t.Fail()
+	}
+
+	b, err := p.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, request) {
+		t.Errorf("wrong bytes marshaled. want %x, got: %x", request, b)
+	}
+}
+
+func TestExtended(t *testing.T) {
+	p := pco.NewExtended(
+		pco.NewDNSServerIPv4Address(net.ParseIP("192.0.2.1")),
+		pco.NewIPv4LinkMTU(1400),
+	)
+	i := p.IE()
+	if i.Type != ies.ExtendedProtocolConfigurationOptions {
+		t.Fatalf("wrong IE type: %d", i.Type)
+	}
+
+	want := []byte{
+		0x80,
+		0x00, 0x0d, 0x00, 0x04, 0xc0, 0x00, 0x02, 0x01,
+		0x00, 0x10, 0x00, 0x02, 0x05, 0x78,
+	}
+	if !bytes.Equal(i.Payload, want) {
+		t.Errorf("wrong payload. want %x, got: %x", want, i.Payload)
+	}
+
+	got, err := pco.FromIE(i)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !verify.Values(t, "", got, p) {
+		t.Fail()
+	}
+
+	if _, err := pco.Parse([]byte{0x80, 0x00, 0x0d, 0x04, 0xc0}); err == nil {
+		t.Error("Parse should fail with container shorter than its length")
+	}
+}
+
+func TestRespond(t *testing.T) {
+	req, err := pco.FromIE(ies.New(ies.ProtocolConfigurationOptions, 0x00, request))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rsp := pco.Respond(req, &pco.Offer{
+		DNSServers: []net.IP{
+			net.ParseIP("192.0.2.1"), net.ParseIP("192.0.2.2"), net.ParseIP("2001:db8::1"),
+		},
+		PCSCFServers:      []net.IP{net.ParseIP("192.0.2.10")},
+		IPv4LinkMTU:       1400,
+		BearerControlMode: pco.BearerControlModeMSAndNetwork,
+	})
+
+	want := pco.New(
+		pco.NewIPCPContainer(pco.NewIPCP(
+			pco.IPCPCodeConfigureNak, 0,
+			pco.NewIPCPOption(pco.IPCPOptionPrimaryDNSServer, net.ParseIP("192.0.2.1")),
+			pco.NewIPCPOption(pco.IPCPOptionSecondaryDNSServer, net.ParseIP("192.0.2.2")),
+		)),
+		pco.NewSelectedBearerControlMode(pco.BearerControlModeMSAndNetwork),
+		pco.NewDNSServerIPv4Address(net.ParseIP("192.0.2.1")),
+		pco.NewDNSServerIPv4Address(net.ParseIP("192.0.2.2")),
+		pco.NewDNSServerIPv6Address(net.ParseIP("2001:db8::1")),
+		pco.NewIPv4LinkMTU(1400),
+	)
+	if !verify.Values(t, "", rsp, want) {
+		t.Fail()
+	}
+
+	if got := rsp.PCSCFAddresses(); len(got) != 0 {
+		t.Errorf("P-CSCF should not be given without request: %v", got)
+	}
+	if got := rsp.DNSServers(); len(got) != 5 {
+		t.Errorf("wrong number of DNS servers. want %d, got: %v", 5, got)
+	}
+	if mtu, err := rsp.IPv4LinkMTU(); err != nil || mtu != 1400 {
+		t.Errorf("wrong IPv4 Link MTU: %d, %v", mtu, err)
+	}
+}
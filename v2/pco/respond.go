@@ -0,0 +1,106 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package pco
+
+import "net"
+
+// Offer is the set of configurations that the network can give to the UE.
+type Offer struct {
+	// DNSServers and PCSCFServers can contain both IPv4 and IPv6 addresses, which are
+	// given to the UE in the containers of the corresponding address family.
+	DNSServers   []net.IP
+	PCSCFServers []net.IP
+
+	// IPv4LinkMTU is not given if it is zero.
+	IPv4LinkMTU uint16
+
+	// BearerControlMode is not given if it is zero.
+	BearerControlMode uint8
+}
+
+// Respond returns the PCO in network to MS direction with the configurations in offer
+// that are requested in req. The returned PCO is ePCO if req is ePCO.
+//
+// The IPCP Configure-Request for the DNS servers is responded with Configure-Nak
+// with the first two IPv4 addresses in DNSServers.
+func Respond(req *PCO, offer *Offer) *PCO {
+	rsp := New()
+	rsp.Extended = req.Extended
+
+	v4DNS, v6DNS := splitFamily(offer.DNSServers)
+	v4PCSCF, v6PCSCF := splitFamily(offer.PCSCFServers)
+
+	for _, c := range req.Containers {
+		switch c.ID {
+		case ContainerIDDNSServerIPv4Address:
+			for _, ip := range v4DNS {
+				rsp.Containers = append(rsp.Containers, NewDNSServerIPv4Address(ip))
+			}
+		case ContainerIDDNSServerIPv6Address:
+			for _, ip := range v6DNS {
+				rsp.Containers = append(rsp.Containers, NewDNSServerIPv6Address(ip))
+			}
+		case ContainerIDPCSCFIPv4Address:
+			for _, ip := range v4PCSCF {
+				rsp.Containers = append(rsp.Containers, NewPCSCFIPv4Address(ip))
+			}
+		case ContainerIDPCSCFIPv6Address:
+			for _, ip := range v6PCSCF {
+				rsp.Containers = append(rsp.Containers, NewPCSCFIPv6Address(ip))
+			}
+		case ContainerIDIPv4LinkMTU:
+			if offer.IPv4LinkMTU != 0 {
+				rsp.Containers = append(rsp.Containers, NewIPv4LinkMTU(offer.IPv4LinkMTU))
+			}
+		case ContainerIDSelectedBearerControlMode:
+			if offer.BearerControlMode != 0 {
+				rsp.Containers = append(rsp.Containers, NewSelectedBearerControlMode(offer.BearerControlMode))
+			}
+		case ProtocolIDIPCP:
+			if nak := respondIPCP(c, v4DNS); nak != nil {
+				rsp.Containers = append(rsp.Containers, nak)
+			}
+		}
+	}
+	return rsp
+}
+
+func respondIPCP(c *Container, dns []net.IP) *Container {
+	ipcp, err := c.IPCP()
+	if err != nil || ipcp.Code != IPCPCodeConfigureRequest {
+		return nil
+	}
+
+	nak := NewIPCP(IPCPCodeConfigureNak, ipcp.Identifier)
+	for _, opt := range ipcp.Options {
+		var idx int
+		switch opt.Type {
+		case IPCPOptionPrimaryDNSServer:
+			idx = 0
+		case IPCPOptionSecondaryDNSServer:
+			idx = 1
+		default:
+			continue
+		}
+		if idx < len(dns) {
+			nak.Options = append(nak.Options, NewIPCPOption(opt.Type, dns[idx]))
+		}
+	}
+	if len(nak.Options) == 0 {
+		return nil
+	}
+	return NewIPCPContainer(nak)
+}
+
+func splitFamily(ips []net.IP) (v4, v6 []net.IP) {
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			v4 = append(v4, ip)
+			continue
+		}
+		v6 = append(v6, ip)
+	}
+	return v4, v6
+}
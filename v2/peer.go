@@ -0,0 +1,490 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/go-gtp/internal/sequence"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// PathState is the state of the path between Conn and the Peer.
+type PathState int
+
+// PathState definitions.
+const (
+	// PathStateUnknown means no message has been exchanged with the Peer yet.
+	PathStateUnknown PathState = iota
+	// PathStateUp means a message has been received from the Peer.
+	PathStateUp
+	// PathStateDown means Conn failed to send a message to the Peer.
+	PathStateDown
+)
+
+// String returns the name of PathState.
+func (p PathState) String() string {
+	switch p {
+	case PathStateUp:
+		return "Up"
+	case PathStateDown:
+		return "Down"
+	default:
+		return "Unknown"
+	}
+}
+
+// outstandingTimeout is the duration to keep the outstanding transactions without
+// the response from the Peer.
+const outstandingTimeout = 30 * time.Second
+
+// maxPeers is the number of the Peers kept in Conn, so that the senders cannot grow
+// them without limit. When it is reached, the quarter of them least recently active
+// are removed, except the ones with the outstanding requests. The same applies to
+// the addresses of the peers learned for SourcePortPolicy.
+const maxPeers = 4096
+
+type transaction struct {
+	msgType uint8
+	sentAt  time.Time
+
+	// req is the request itself, which is used to update the Bearers in the Session
+	// and passed to the RejectionHandler with the response to it.
+	req messages.Message
+}
+
+// Peer represents a remote GTPv2-C endpoint that Conn communicates with.
+//
+// TS29.274 7.6 Reliable Delivery of Signalling Messages;
+// The Sequence Number shall be unique for each outstanding Initial message sourced
+// from the same IP/UDP endpoint. Peer owns the SequenceNumber used in the requests
+// sent to it, together with its RestartCounter, the state of the path and the
+// outstanding transactions.
+type Peer struct {
+	mu   sync.Mutex
+	addr net.Addr
+
+	// sequence is the last SequenceNumber used in the request sent to the Peer.
+	sequence uint32
+
+	restartCounter    uint8
+	hasRestartCounter bool
+
+	// nodeFeatures is the Sending Node Features in the Echo messages from the Peer.
+	nodeFeatures    uint8
+	hasNodeFeatures bool
+
+	// notified is whether the Recovery IE of Conn has been sent to the Peer since
+	// either of them is (re)started.
+	notified bool
+
+	pathState PathState
+	lastSeen  time.Time
+
+	// lastActive is the time when the last message was exchanged with the Peer.
+	lastActive time.Time
+
+	outstanding map[uint32]*transaction
+
+	// pathMTU is the result of ProbePathMTU, and probes is the outstanding probes.
+	pathMTU int
+	probes  map[uint32]*pathMTUProbe
+
+	// loadControl is the load and overload advertised by the Peer, which is nil
+	// until any of them is received with the load control enabled.
+	loadControl *loadControl
+}
+
+func newPeer(addr net.Addr, seq uint32) *Peer {
+	return &Peer{
+		addr:        addr,
+		sequence:    seq,
+		lastActive:  time.Now(),
+		outstanding: map[uint32]*transaction{},
+	}
+}
+
+// Addr returns the address of the Peer.
+func (p *Peer) Addr() net.Addr {
+	return p.addr
+}
+
+// SequenceNumber returns the last SequenceNumber used in the request sent to the Peer.
+func (p *Peer) SequenceNumber() uint32 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.sequence
+}
+
+// SetInitialSequenceNumber sets the SequenceNumber to be used in the next request
+// sent to the Peer.
+func (p *Peer) SetInitialSequenceNumber(seq uint32) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.sequence = (seq - 1) & 0xffffff
+}
+
+// RandomizeSequenceNumber sets the SequenceNumber to be used in the next request
+// sent to the Peer to a random value, and returns it.
+func (p *Peer) RandomizeSequenceNumber() uint32 {
+	seq := sequence.Random(0xffffff)
+	p.SetInitialSequenceNumber(seq)
+	return seq
+}
+
+func (p *Peer) incSequence() uint32 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.sequence++
+	// SequenceNumber is 3-octet long
+	if p.sequence > 0xffffff {
+		p.sequence = 0
+	}
+	p.lastActive = time.Now()
+	return p.sequence
+}
+
+func (p *Peer) decSequence() uint32 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.sequence = (p.sequence - 1) & 0xffffff
+	return p.sequence
+}
+
+// RestartCounter returns the RestartCounter of the Peer, retrieved from the Recovery
+// IE in the messages received. The second returned value is false if it has not been
+// received yet.
+func (p *Peer) RestartCounter() (uint8, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.restartCounter, p.hasRestartCounter
+}
+
+// NodeFeatures returns the features supported by the Peer, retrieved from the Sending
+// Node Features IE in the Echo Request or Response received. The second returned value
+// is false if it has not been received yet.
+//
+// The features are the bits of Supported-Features, e.g., NodeFeaturePRN.
+func (p *Peer) NodeFeatures() (uint8, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.nodeFeatures, p.hasNodeFeatures
+}
+
+// SupportsNodeFeature reports whether the Peer has advertised the feature given.
+func (p *Peer) SupportsNodeFeature(feature uint8) bool {
+	features, ok := p.NodeFeatures()
+	return ok && features&feature == feature
+}
+
+// PathState returns the state of the path to the Peer.
+func (p *Peer) PathState() PathState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.pathState
+}
+
+// LastSeen returns the time when the last message was received from the Peer.
+// It returns zero time.Time if nothing has been received.
+func (p *Peer) LastSeen() time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.lastSeen
+}
+
+// OutstandingRequests returns the SequenceNumbers of the requests sent to the Peer
+// that are not responded yet.
+func (p *Peer) OutstandingRequests() []uint32 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.expireOutstanding(time.Now())
+
+	var seqs []uint32
+	for seq := range p.outstanding {
+		seqs = append(seqs, seq)
+	}
+	return seqs
+}
+
+// IsOutstanding reports whether the request with seq is sent to the Peer and not
+// responded yet.
+func (p *Peer) IsOutstanding(seq uint32) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, ok := p.outstanding[seq]
+	return ok
+}
+
+func (p *Peer) expireOutstanding(now time.Time) {
+	for seq, tx := range p.outstanding {
+		if now.Sub(tx.sentAt) > outstandingTimeout {
+			delete(p.outstanding, seq)
+		}
+	}
+}
+
+// sent updates the Peer with the message sent to it.
+func (p *Peer) sent(msg messages.Message) {
+	if !isInitialMessage(msg.MessageType()) {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	p.expireOutstanding(now)
+	p.outstanding[msg.Sequence()] = &transaction{msgType: msg.MessageType(), sentAt: now, req: msg}
+}
+
+// recoveryNotified marks the Peer notified of the RestartCounter of Conn if msg
+// sent to it contains the Recovery IE. Echo Request and Response are not counted.
+func (p *Peer) recoveryNotified(msg messages.Message) {
+	switch msg.MessageType() {
+	case messages.MsgTypeEchoRequest, messages.MsgTypeEchoResponse:
+		return
+	}
+	if recoveryIE(msg) == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.notified = true
+}
+
+func (p *Peer) isRecoveryNotified() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.notified
+}
+
+// failed marks the path to the Peer down.
+func (p *Peer) failed() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.pathState = PathStateDown
+}
+
+// received updates the Peer with the message received from it.
+// It returns true if the RestartCounter of Peer is changed, and the round-trip time
+// if msg is the response to the outstanding request. req is the request kept in the
+// outstanding one with the same Sequence Number, if any, which is kept outstanding
+// if msg is not the response to it.
+func (p *Peer) received(msg messages.Message) (restarted bool, rtt time.Duration, req messages.Message) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.pathState = PathStateUp
+	p.lastSeen = time.Now()
+	p.lastActive = p.lastSeen
+
+	if msg.MessageType() == messages.MsgTypeEchoResponse {
+		p.probeResponded(msg.Sequence())
+	}
+	if ie := nodeFeaturesIE(msg); ie != nil {
+		if features, err := ie.NodeFeatures(); err == nil {
+			p.nodeFeatures = features
+			p.hasNodeFeatures = true
+		}
+	}
+	if !isInitialMessage(msg.MessageType()) {
+		if tx, ok := p.outstanding[msg.Sequence()]; ok {
+			req = tx.req
+			// keep the request for the right response if msg is not the one.
+			if isResponseTo(tx.msgType, msg.MessageType()) {
+				rtt = p.lastSeen.Sub(tx.sentAt)
+				delete(p.outstanding, msg.Sequence())
+			}
+		}
+	}
+
+	if ie := recoveryIE(msg); ie != nil {
+		counter, err := ie.Recovery()
+		if err != nil {
+			return false, rtt, req
+		}
+		restarted = p.hasRestartCounter && p.restartCounter != counter
+		p.restartCounter = counter
+		p.hasRestartCounter = true
+		if restarted {
+			p.notified = false
+		}
+	}
+	return restarted, rtt, req
+}
+
+// nodeFeaturesIE returns the Sending Node Features IE in msg if any.
+func nodeFeaturesIE(msg messages.Message) *ies.IE {
+	switch m := msg.(type) {
+	case *messages.EchoRequest:
+		return m.SendingNodeFeatures
+	case *messages.EchoResponse:
+		return m.SendingNodeFeatures
+	}
+	return nil
+}
+
+// recoveryIE returns the Recovery IE in msg if any.
+func recoveryIE(msg messages.Message) *ies.IE {
+	switch m := msg.(type) {
+	case *messages.EchoRequest:
+		return m.Recovery
+	case *messages.EchoResponse:
+		return m.Recovery
+	case *messages.CreateSessionRequest:
+		return m.Recovery
+	case *messages.CreateSessionResponse:
+		return m.Recovery
+	case *messages.ModifyBearerRequest:
+		return m.Recovery
+	case *messages.ModifyBearerResponse:
+		return m.Recovery
+	case *messages.DeleteSessionResponse:
+		return m.Recovery
+	case *messages.CreateBearerResponse:
+		return m.Recovery
+	case *messages.DeleteBearerResponse:
+		return m.Recovery
+	case *messages.DeletePDNConnectionSetResponse:
+		return m.Recovery
+	case *messages.UpdateBearerResponse:
+		return m.Recovery
+	case *messages.BearerResourceFailureIndication:
+		return m.Recovery
+	case *messages.ModifyBearerFailureIndication:
+		return m.Recovery
+	case *messages.DeleteBearerFailureIndication:
+		return m.Recovery
+	case *messages.CreateIndirectDataForwardingTunnelRequest:
+		return m.Recovery
+	case *messages.CreateIndirectDataForwardingTunnelResponse:
+		return m.Recovery
+	case *messages.DeleteIndirectDataForwardingTunnelResponse:
+		return m.Recovery
+	case *messages.ModifyAccessBearersRequest:
+		return m.Recovery
+	case *messages.ModifyAccessBearersResponse:
+		return m.Recovery
+	case *messages.DownlinkDataNotificationAcknowledge:
+		return m.Recovery
+	case *messages.ForwardRelocationRequest:
+		return m.Recovery
+	case *messages.ForwardRelocationCompleteAcknowledge:
+		return m.Recovery
+	default:
+		return nil
+	}
+}
+
+// peer returns the Peer with addr, creating a new one if it does not exist.
+func (c *Conn) peer(addr net.Addr) *Peer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.peers == nil {
+		c.peers = map[string]*Peer{}
+	}
+
+	key := addrString(addr)
+	if p, ok := c.peers[key]; ok {
+		return p
+	}
+
+	if len(c.peers) >= maxPeers {
+		c.evictPeers()
+	}
+
+	// the SequenceNumber of a new Peer starts from the one of Conn.
+	p := newPeer(addr, c.sequence)
+	c.peers[key] = p
+	return p
+}
+
+// evictPeers removes the quarter of the Peers least recently active, except the ones
+// with the outstanding requests, together with the addresses learned for them.
+// c.mu should be held by the caller.
+func (c *Conn) evictPeers() {
+	type candidate struct {
+		key        string
+		p          *Peer
+		lastActive time.Time
+	}
+
+	now := time.Now()
+	var cands []candidate
+	for key, p := range c.peers {
+		p.mu.Lock()
+		p.expireOutstanding(now)
+		if len(p.outstanding) == 0 {
+			cands = append(cands, candidate{key, p, p.lastActive})
+		}
+		p.mu.Unlock()
+	}
+	sort.Slice(cands, func(i, j int) bool {
+		return cands[i].lastActive.Before(cands[j].lastActive)
+	})
+
+	n := len(c.peers) / 4
+	if n > len(cands) {
+		n = len(cands)
+	}
+	for _, cand := range cands[:n] {
+		delete(c.peers, cand.key)
+		ip, _ := splitAddr(cand.p.addr)
+		if addr, ok := c.peerAddrs[ip]; ok && addrString(addr) == cand.key {
+			delete(c.peerAddrs, ip)
+		}
+	}
+}
+
+// GetPeer returns the Peer looked up by its address.
+func (c *Conn) GetPeer(addr net.Addr) (*Peer, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if p, ok := c.peers[addrString(addr)]; ok {
+		return p, nil
+	}
+	return nil, &UnknownPeerError{Addr: addr}
+}
+
+// Peers returns all the Peers that Conn has communicated with.
+func (c *Conn) Peers() []*Peer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var ps []*Peer
+	for _, p := range c.peers {
+		ps = append(ps, p)
+	}
+	return ps
+}
+
+// RemovePeer removes the Peer with addr from Conn.
+//
+// The next message exchanged with addr creates a new Peer, whose SequenceNumber
+// starts from the one of Conn.
+func (c *Conn) RemovePeer(addr net.Addr) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.peers, addrString(addr))
+}
@@ -0,0 +1,224 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"encoding/json"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// sessionsDumpVersion is the version of the format of DumpSessions, which is
+// incremented when the format is changed incompatibly.
+const sessionsDumpVersion = 1
+
+type sessionsDump struct {
+	Version  int              `json:"version"`
+	Sessions []*sessionRecord `json:"sessions"`
+}
+
+type sessionRecord struct {
+	IMSI     string    `json:"imsi"`
+	MSISDN   string    `json:"msisdn,omitempty"`
+	IMEI     string    `json:"imei,omitempty"`
+	Location *Location `json:"location,omitempty"`
+
+	Peer            string    `json:"peer"`
+	Active          bool      `json:"active"`
+	Suspended       bool      `json:"suspended,omitempty"`
+	ISRActive       bool      `json:"isrActive,omitempty"`
+	DDNDelayedUntil time.Time `json:"ddnDelayedUntil"`
+
+	ChargingCharacteristics uint16 `json:"chargingCharacteristics,omitempty"`
+	APNAMBRUp               uint32 `json:"apnAMBRUp,omitempty"`
+	APNAMBRDown             uint32 `json:"apnAMBRDown,omitempty"`
+
+	TEIDs   map[uint8]uint32         `json:"teids"`
+	Bearers map[string]*bearerRecord `json:"bearers"`
+}
+
+type bearerRecord struct {
+	EBI          uint8       `json:"ebi"`
+	SubscriberIP string      `json:"subscriberIP,omitempty"`
+	APN          string      `json:"apn,omitempty"`
+	ChargingID   uint32      `json:"chargingID,omitempty"`
+	QoSProfile   *QoSProfile `json:"qos,omitempty"`
+
+	RemoteAddress  string `json:"remoteAddress,omitempty"`
+	IncomingTEID   uint32 `json:"incomingTEID,omitempty"`
+	OutgoingTEID   uint32 `json:"outgoingTEID,omitempty"`
+	Suspended      bool   `json:"suspended,omitempty"`
+	AccessReleased bool   `json:"accessReleased,omitempty"`
+}
+
+// DumpSessions serializes all the Sessions on Conn into JSON, including the
+// Subscribers, TEIDs and Bearers of them.
+//
+// The result can be given to RestoreSessions on the new Conn to resume serving the
+// existing subscribers without re-attach, e.g., after the process is restarted. The
+// message queue, the history and the HandlerFuncs scoped to the Sessions are not
+// included. RestartCounter is not included either; it is up to the caller to keep
+// it and increment it on the new Conn as required by TS 23.007.
+func (c *Conn) DumpSessions() ([]byte, error) {
+	dump := &sessionsDump{Version: sessionsDumpVersion}
+	c.RangeSessions(func(sess *Session) bool {
+		dump.Sessions = append(dump.Sessions, sess.record())
+		return true
+	})
+	return json.Marshal(dump)
+}
+
+// RestoreSessions restores the Sessions serialized by DumpSessions and adds them to
+// Conn. The Session of the same PDN connection as the existing one replaces it.
+//
+// It returns error without adding any Session if b is malformed.
+func (c *Conn) RestoreSessions(b []byte) error {
+	dump := &sessionsDump{}
+	if err := json.Unmarshal(b, dump); err != nil {
+		return errors.Wrap(err, "failed to decode sessions")
+	}
+	if dump.Version != sessionsDumpVersion {
+		return errors.Errorf("unsupported version of sessions: %d", dump.Version)
+	}
+
+	var sessions []*Session
+	for _, rec := range dump.Sessions {
+		sess, err := rec.session()
+		if err != nil {
+			return errors.Wrapf(err, "failed to restore session of %s", rec.IMSI)
+		}
+		sessions = append(sessions, sess)
+	}
+
+	for _, sess := range sessions {
+		c.AddSession(sess)
+	}
+	return nil
+}
+
+func (s *Session) record() *sessionRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec := &sessionRecord{
+		Peer:            s.peerAddrString,
+		Active:          s.isActive,
+		Suspended:       s.isSuspended,
+		ISRActive:       s.isISRActive,
+		DDNDelayedUntil: s.ddnDelayedUntil,
+		TEIDs:           map[uint8]uint32{},
+		Bearers:         map[string]*bearerRecord{},
+
+		ChargingCharacteristics: s.chargingCharacteristics,
+		APNAMBRUp:               s.apnAMBRUp,
+		APNAMBRDown:             s.apnAMBRDown,
+	}
+	if sub := s.Subscriber; sub != nil {
+		rec.IMSI, rec.MSISDN, rec.IMEI = sub.IMSI, sub.MSISDN, sub.IMEI
+		if sub.Location != nil {
+			loc := *sub.Location
+			rec.Location = &loc
+		}
+	}
+
+	s.teidMap.rangeWithFunc(func(k, v interface{}) bool {
+		rec.TEIDs[k.(uint8)] = v.(uint32)
+		return true
+	})
+	s.bearerMap.rangeWithFunc(func(k, v interface{}) bool {
+		rec.Bearers[k.(string)] = v.(*Bearer).record()
+		return true
+	})
+	return rec
+}
+
+func (r *sessionRecord) session() (*Session, error) {
+	raddr, err := resolveRecordAddr(r.Peer)
+	if err != nil {
+		return nil, err
+	}
+	var peer net.Addr
+	if raddr != nil {
+		peer = raddr
+	}
+
+	sess := NewSession(peer, &Subscriber{
+		IMSI: r.IMSI, MSISDN: r.MSISDN, IMEI: r.IMEI, Location: r.Location,
+	})
+	sess.isActive = r.Active
+	sess.isSuspended = r.Suspended
+	sess.isISRActive = r.ISRActive
+	sess.chargingCharacteristics = r.ChargingCharacteristics
+	sess.apnAMBRUp, sess.apnAMBRDown = r.APNAMBRUp, r.APNAMBRDown
+	sess.ddnDelayedUntil = r.DDNDelayedUntil
+
+	for ifType, teid := range r.TEIDs {
+		sess.AddTEID(ifType, teid)
+	}
+
+	// the default Bearer created by NewSession is replaced if it is in the record.
+	for name, rec := range r.Bearers {
+		br, err := rec.bearer()
+		if err != nil {
+			return nil, err
+		}
+		sess.AddBearer(name, br)
+	}
+	return sess, nil
+}
+
+func (b *Bearer) record() *bearerRecord {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	rec := &bearerRecord{
+		EBI:            b.EBI,
+		SubscriberIP:   b.SubscriberIP,
+		APN:            b.APN,
+		ChargingID:     b.ChargingID,
+		RemoteAddress:  addrString(b.raddr),
+		IncomingTEID:   b.teidIn,
+		OutgoingTEID:   b.teidOut,
+		Suspended:      b.suspended,
+		AccessReleased: b.accessReleased,
+	}
+	if b.QoSProfile != nil {
+		qos := *b.QoSProfile
+		rec.QoSProfile = &qos
+	}
+	return rec
+}
+
+func (r *bearerRecord) bearer() (*Bearer, error) {
+	raddr, err := resolveRecordAddr(r.RemoteAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	qos := r.QoSProfile
+	if qos == nil {
+		qos = &QoSProfile{}
+	}
+	br := NewBearer(r.EBI, r.APN, qos)
+	br.SubscriberIP = r.SubscriberIP
+	br.ChargingID = r.ChargingID
+	br.teidIn, br.teidOut = r.IncomingTEID, r.OutgoingTEID
+	br.suspended, br.accessReleased = r.Suspended, r.AccessReleased
+	if raddr != nil {
+		br.raddr = raddr
+	}
+	return br, nil
+}
+
+// resolveRecordAddr returns the *net.UDPAddr of addr in the record, or nil if it
+// is empty.
+func resolveRecordAddr(addr string) (*net.UDPAddr, error) {
+	if addr == "" {
+		return nil, nil
+	}
+	return net.ResolveUDPAddr("udp", addr)
+}
@@ -0,0 +1,237 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package pgw provides the S5/S8 interface of P-GW built on v2.Conn, which accepts
+// and deletes the PDN connections requested by the S-GW, and activates and
+// deactivates the dedicated bearers on the network side.
+//
+// The requests are sent once and the responses are waited for until the context is
+// done, so the context should have the deadline that covers the retransmissions
+// expected from the peer. The responses are correlated by the HandlerFuncs registered
+// by New, which should not be overwritten.
+package pgw
+
+import (
+	"context"
+	"net"
+
+	"github.com/wmnsk/go-gtp/internal/exchange"
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// Policy is a set of functions to make the decisions in the call flows initiated by
+// the S-GW. Any of them can be nil.
+type Policy struct {
+	// AuthorizeSession returns the Cause to Create Session Request, with the Session
+	// created from the IEs in it. All of them are accepted if nil.
+	AuthorizeSession func(sess *v2.Session, req *messages.CreateSessionRequest) uint8
+
+	// AllocateIP returns the IP address of the UE, which is set in PDN Address
+	// Allocation of Create Session Response. The request is rejected with "No
+	// resources available" if it returns error. The one in the request, if any, is
+	// used as it is if nil.
+	AllocateIP func(sess *v2.Session) (string, error)
+
+	// OnSessionDeleted is called when the Session is deleted by Delete Session
+	// Request, after it is removed from Conn.
+	OnSessionDeleted func(sess *v2.Session)
+}
+
+// PGW is the S5/S8 interface of P-GW.
+type PGW struct {
+	conn   *v2.Conn
+	s5uIP  string
+	policy Policy
+	calls  *exchange.Table
+}
+
+// New creates a new PGW that performs the call flows over conn, and registers the
+// HandlerFuncs for them to conn. The s5uIP is the IP address set in the F-TEIDs of
+// S5/S8-U P-GW.
+func New(conn *v2.Conn, s5uIP string, policy *Policy) *PGW {
+	p := &PGW{
+		conn:  conn,
+		s5uIP: s5uIP,
+		calls: exchange.New(),
+	}
+	if policy != nil {
+		p.policy = *policy
+	}
+
+	p.calls.Handle(
+		conn,
+		messages.MsgTypeCreateBearerResponse,
+		messages.MsgTypeDeleteBearerResponse,
+	)
+	conn.HandleCreateSessionRequest(p.handleCreateSessionRequest)
+	conn.HandleDeleteSessionRequest(p.handleDeleteSessionRequest)
+	return p
+}
+
+// Conn returns the Conn that PGW uses.
+func (p *PGW) Conn() *v2.Conn {
+	return p.conn
+}
+
+// ActivateDedicatedBearer sends Create Bearer Request for a dedicated Bearer with qos
+// in the PDN connection of Session, and waits for the response. The bearerIEs, e.g.,
+// TFT, are added to the Bearer Context in the request.
+//
+// The Bearer accepted is added to the Session with the EBI assigned by the peer,
+// and returned. It returns CauseError if the request is rejected.
+func (p *PGW) ActivateDedicatedBearer(ctx context.Context, sess *v2.Session, qos *v2.QoSProfile, bearerIEs ...*ies.IE) (*v2.Bearer, error) {
+	teid, err := sess.GetTEID(v2.IFTypeS5S8SGWGTPC)
+	if err != nil {
+		return nil, err
+	}
+	var pci, pvi uint8
+	if qos.PCI {
+		pci = 1
+	}
+	if qos.PVI {
+		pvi = 1
+	}
+
+	children := []*ies.IE{
+		ies.NewEPSBearerID(0),
+		ies.NewBearerQoS(pci, qos.PL, pvi, qos.QCI, qos.MBRUL, qos.MBRDL, qos.GBRUL, qos.GBRDL),
+		exchange.NewFTEID(p.conn, v2.IFTypeS5S8PGWGTPU, p.s5uIP).WithInstance(1),
+	}
+	req := messages.NewCreateBearerRequest(
+		teid, 0,
+		ies.NewEPSBearerID(sess.GetDefaultBearer().GetEBI()),
+		ies.NewBearerContext(append(children, bearerIEs...)...),
+	)
+	rsp, err := p.calls.Call(ctx, p.conn, sess.PeerAddr(), req)
+	if err != nil {
+		return nil, err
+	}
+	if err := v2.CheckCause(rsp); err != nil {
+		return nil, err
+	}
+
+	// the Bearer is added to the Session when the response is received.
+	for _, bc := range messages.FindIEs(rsp, ies.BearerContext) {
+		for _, child := range bc.ChildIEs {
+			if child.Type != ies.EPSBearerID {
+				continue
+			}
+			ebi, err := child.EPSBearerID()
+			if err != nil {
+				return nil, err
+			}
+			return sess.LookupBearerByEBI(ebi)
+		}
+	}
+	return nil, &v2.RequiredIEMissingError{Type: ies.EPSBearerID}
+}
+
+// DeactivateBearers sends Delete Bearer Request for the Bearers with ebis in Session,
+// and waits for the response. The Bearers deleted are removed from the Session.
+//
+// It returns CauseError if the request is rejected.
+func (p *PGW) DeactivateBearers(ctx context.Context, sess *v2.Session, ebis ...uint8) error {
+	teid, err := sess.GetTEID(v2.IFTypeS5S8SGWGTPC)
+	if err != nil {
+		return err
+	}
+
+	var ie []*ies.IE
+	for _, ebi := range ebis {
+		ie = append(ie, ies.NewEPSBearerID(ebi).WithInstance(1))
+	}
+	rsp, err := p.calls.Call(ctx, p.conn, sess.PeerAddr(), messages.NewDeleteBearerRequest(teid, 0, ie...))
+	if err != nil {
+		return err
+	}
+	return v2.CheckCause(rsp)
+}
+
+func (p *PGW) handleCreateSessionRequest(c *v2.Conn, senderAddr net.Addr, msg *messages.CreateSessionRequest) error {
+	sess, err := v2.NewSessionFromIEs(senderAddr, messages.IEs(msg)...)
+	if err != nil {
+		return err
+	}
+	sgwTEID, err := sess.GetTEID(v2.IFTypeS5S8SGWGTPC)
+	if err != nil {
+		return c.RespondTo(senderAddr, msg, messages.NewCreateSessionResponse(
+			0, 0, ies.NewCause(v2.CauseMandatoryIEMissing, 0, 0, 0, nil),
+		))
+	}
+	br := sess.GetDefaultBearer()
+
+	cause := v2.CauseRequestAccepted
+	if fn := p.policy.AuthorizeSession; fn != nil {
+		cause = fn(sess, msg)
+	}
+	var ip string
+	if ie := msg.PAA; ie != nil {
+		if ip, err = ie.IPAddress(); err != nil {
+			ip = ""
+		}
+	}
+	if fn := p.policy.AllocateIP; fn != nil && cause == v2.CauseRequestAccepted {
+		if ip, err = fn(sess); err != nil {
+			cause = v2.CauseNoResourcesAvailable
+		}
+	}
+	if cause != v2.CauseRequestAccepted {
+		return c.RespondTo(senderAddr, msg, messages.NewCreateSessionResponse(
+			sgwTEID, 0, ies.NewCause(cause, 0, 0, 0, nil),
+		))
+	}
+
+	s5cFTEID := exchange.NewFTEID(c, v2.IFTypeS5S8PGWGTPC, exchange.LocalIP(c))
+	s5uFTEID := exchange.NewFTEID(c, v2.IFTypeS5S8PGWGTPU, p.s5uIP).WithInstance(2)
+	if err := exchange.AddFTEID(sess, s5cFTEID); err != nil {
+		return err
+	}
+	if err := exchange.AddFTEID(sess, s5uFTEID); err != nil {
+		return err
+	}
+	rspIEs := []*ies.IE{ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil), s5cFTEID}
+	if ip != "" {
+		br.SetSubscriberIP(ip)
+		rspIEs = append(rspIEs, ies.NewPDNAddressAllocation(ip))
+	}
+	rspIEs = append(rspIEs, ies.NewBearerContext(
+		ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+		ies.NewEPSBearerID(br.GetEBI()),
+		s5uFTEID,
+	))
+
+	// the Session is added before responding, as the S-GW may send the next request
+	// on it as soon as it receives the response.
+	if err := sess.Activate(); err != nil {
+		return err
+	}
+	c.AddSession(sess)
+	return c.RespondTo(senderAddr, msg, messages.NewCreateSessionResponse(sgwTEID, 0, rspIEs...))
+}
+
+func (p *PGW) handleDeleteSessionRequest(c *v2.Conn, senderAddr net.Addr, msg *messages.DeleteSessionRequest) error {
+	sess, err := c.GetSessionByTEID(msg.TEID(), senderAddr)
+	if err != nil {
+		return c.RespondTo(senderAddr, msg, messages.NewDeleteSessionResponse(
+			0, 0, ies.NewCause(v2.CauseContextNotFound, 0, 0, 0, nil),
+		))
+	}
+	teid, err := sess.GetTEID(v2.IFTypeS5S8SGWGTPC)
+	if err != nil {
+		return err
+	}
+
+	if err := c.RespondTo(senderAddr, msg, messages.NewDeleteSessionResponse(
+		teid, 0, ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+	)); err != nil {
+		return err
+	}
+	c.RemoveSession(sess)
+	if fn := p.policy.OnSessionDeleted; fn != nil {
+		fn(sess)
+	}
+	return nil
+}
@@ -0,0 +1,339 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package pgw_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/wmnsk/go-gtp/internal/exchange"
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+	"github.com/wmnsk/go-gtp/v2/pgw"
+)
+
+func listen(t *testing.T, addr string, errCh chan error) *v2.Conn {
+	t.Helper()
+	laddr, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := v2.ListenAndServe(laddr, 0, errCh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn
+}
+
+// sgwPeer is the S-GW side of S5/S8 that sends the requests to PGW and receives the
+// responses on rspCh.
+type sgwPeer struct {
+	t       *testing.T
+	conn    *v2.Conn
+	pgwAddr net.Addr
+	rspCh   chan messages.Message
+}
+
+func newSGWPeer(t *testing.T, conn *v2.Conn, pgwAddr net.Addr) *sgwPeer {
+	s := &sgwPeer{t: t, conn: conn, pgwAddr: pgwAddr, rspCh: make(chan messages.Message, 1)}
+	deliver := func(c *v2.Conn, senderAddr net.Addr, msg messages.Message) error {
+		s.rspCh <- msg
+		return nil
+	}
+	conn.AddHandlers(map[uint8]v2.HandlerFunc{
+		messages.MsgTypeCreateSessionResponse: deliver,
+		messages.MsgTypeDeleteSessionResponse: deliver,
+	})
+	return s
+}
+
+// createSession sends Create Session Request for imsi and apn, and returns the
+// Session on the S-GW side with the response.
+func (s *sgwPeer) createSession(ctx context.Context, imsi, apn string) (*v2.Session, *messages.CreateSessionResponse) {
+	s.t.Helper()
+	sess, _, err := exchange.CreateSession(
+		s.conn, s.pgwAddr,
+		ies.NewIMSI(imsi),
+		s.conn.NewFTEID(v2.IFTypeS5S8SGWGTPC, "127.0.1.25", ""),
+		ies.NewAccessPointName(apn),
+		ies.NewRATType(v2.RATTypeEUTRAN),
+		ies.NewBearerContext(
+			ies.NewEPSBearerID(5),
+			s.conn.NewFTEID(v2.IFTypeS5S8SGWGTPU, "127.0.1.25", "").WithInstance(2),
+			ies.NewBearerQoS(1, 2, 1, 9, 0, 0, 0, 0),
+		),
+	)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	rsp, ok := s.wait(ctx).(*messages.CreateSessionResponse)
+	if !ok {
+		s.t.Fatal("unexpected response to Create Session Request")
+	}
+	if ie := rsp.SenderFTEIDC; ie != nil {
+		if err := exchange.AddFTEID(sess, ie); err != nil {
+			s.t.Fatal(err)
+		}
+	}
+	return sess, rsp
+}
+
+func (s *sgwPeer) wait(ctx context.Context) messages.Message {
+	s.t.Helper()
+	select {
+	case rsp := <-s.rspCh:
+		return rsp
+	case <-ctx.Done():
+		s.t.Fatal(ctx.Err())
+	}
+	return nil
+}
+
+func causeOf(t *testing.T, rsp messages.Message) uint8 {
+	t.Helper()
+	ie, err := messages.FindIE(rsp, ies.Cause, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ie.MustCause()
+}
+
+func TestSessionRequests(t *testing.T) {
+	errCh := make(chan error, 16)
+	pgwConn := listen(t, "127.0.1.24:2123", errCh)
+	defer pgwConn.Close()
+	sgwConn := listen(t, "127.0.1.25:2123", errCh)
+	defer sgwConn.Close()
+
+	deletedCh := make(chan *v2.Session, 1)
+	pgw.New(pgwConn, "127.0.1.24", &pgw.Policy{
+		AuthorizeSession: func(sess *v2.Session, req *messages.CreateSessionRequest) uint8 {
+			if sess.GetDefaultBearer().GetAPN() == "denied.example" {
+				return v2.CauseMissingOrUnknownAPN
+			}
+			return v2.CauseRequestAccepted
+		},
+		AllocateIP: func(sess *v2.Session) (string, error) {
+			if sess.GetIMSI() == "123451234567899" {
+				return "", errors.New("no more addresses")
+			}
+			return "10.0.0.1", nil
+		},
+		OnSessionDeleted: func(sess *v2.Session) {
+			deletedCh <- sess
+		},
+	})
+	s := newSGWPeer(t, sgwConn, pgwConn.LocalAddr())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	t.Run("CreateSession/rejected", func(t *testing.T) {
+		cases := []struct {
+			description string
+			imsi, apn   string
+			cause       uint8
+		}{
+			{"AuthorizeSession", "123451234567891", "denied.example", v2.CauseMissingOrUnknownAPN},
+			{"AllocateIP", "123451234567899", "some.apn.example", v2.CauseNoResourcesAvailable},
+		}
+		for _, c := range cases {
+			sess, rsp := s.createSession(ctx, c.imsi, c.apn)
+			sgwConn.RemoveSession(sess)
+			if got := causeOf(t, rsp); got != c.cause {
+				t.Errorf("%s: unexpected Cause: got %d, want %d", c.description, got, c.cause)
+			}
+		}
+		if n := pgwConn.SessionCount(); n != 0 {
+			t.Errorf("Sessions added on rejection: %d", n)
+		}
+	})
+
+	var sgwSess *v2.Session
+	t.Run("CreateSession/accepted", func(t *testing.T) {
+		var rsp *messages.CreateSessionResponse
+		sgwSess, rsp = s.createSession(ctx, "123451234567890", "some.apn.example")
+		if got := causeOf(t, rsp); got != v2.CauseRequestAccepted {
+			t.Fatalf("unexpected Cause: got %d", got)
+		}
+		if ip, err := rsp.PAA.IPAddress(); err != nil || ip != "10.0.0.1" {
+			t.Errorf("unexpected PAA: got %s, %v", ip, err)
+		}
+		sess, err := pgwConn.GetSessionByIMSI("123451234567890")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !sess.IsActive() {
+			t.Error("Session is not activated")
+		}
+	})
+	if sgwSess == nil {
+		t.FailNow()
+	}
+
+	t.Run("DeleteSession", func(t *testing.T) {
+		teid, err := sgwSess.GetTEID(v2.IFTypeS5S8PGWGTPC)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req := messages.NewDeleteSessionRequest(teid, 0, ies.NewEPSBearerID(5))
+		if _, err := sgwConn.SendMessageTo(req, pgwConn.LocalAddr()); err != nil {
+			t.Fatal(err)
+		}
+		if got := causeOf(t, s.wait(ctx)); got != v2.CauseRequestAccepted {
+			t.Errorf("unexpected Cause: got %d", got)
+		}
+		if got := <-deletedCh; got.GetIMSI() != "123451234567890" {
+			t.Errorf("unexpected Session deleted: %s", got.GetIMSI())
+		}
+		if n := pgwConn.SessionCount(); n != 0 {
+			t.Errorf("Session is not removed: %d", n)
+		}
+	})
+
+	select {
+	case err := <-errCh:
+		t.Error(err)
+	default:
+	}
+}
+
+func TestBearerRequests(t *testing.T) {
+	errCh := make(chan error, 16)
+	pgwConn := listen(t, "127.0.1.24:2124", errCh)
+	defer pgwConn.Close()
+	sgwConn := listen(t, "127.0.1.25:2124", errCh)
+	defer sgwConn.Close()
+
+	p := pgw.New(pgwConn, "127.0.1.24", nil)
+	s := newSGWPeer(t, sgwConn, pgwConn.LocalAddr())
+
+	// the S-GW accepts the requests with QCI 1 and EBI 6, rejects the ones with QCI 2
+	// and EBI 7, and does not respond to the others.
+	sgwConn.HandleCreateBearerRequest(func(c *v2.Conn, senderAddr net.Addr, msg *messages.CreateBearerRequest) error {
+		sess, err := c.GetSessionByTEID(msg.TEID(), senderAddr)
+		if err != nil {
+			return err
+		}
+		teid, err := sess.GetTEID(v2.IFTypeS5S8PGWGTPC)
+		if err != nil {
+			return err
+		}
+		var qci uint8
+		for _, child := range msg.BearerContexts.ChildIEs {
+			if child.Type != ies.BearerQoS {
+				continue
+			}
+			if qci, err = child.QCILabel(); err != nil {
+				return err
+			}
+		}
+		switch qci {
+		case 1:
+			return c.CreateBearerResponse(
+				teid, senderAddr, msg,
+				ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+				ies.NewBearerContext(
+					ies.NewEPSBearerID(6),
+					ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+					c.NewFTEID(v2.IFTypeS5S8SGWGTPU, "127.0.1.25", "").WithInstance(2),
+				),
+			)
+		case 2:
+			return c.CreateBearerResponse(
+				teid, senderAddr, msg,
+				ies.NewCause(v2.CauseServiceDenied, 0, 0, 0, nil),
+			)
+		}
+		return nil
+	})
+	sgwConn.HandleDeleteBearerRequest(func(c *v2.Conn, senderAddr net.Addr, msg *messages.DeleteBearerRequest) error {
+		sess, err := c.GetSessionByTEID(msg.TEID(), senderAddr)
+		if err != nil {
+			return err
+		}
+		teid, err := sess.GetTEID(v2.IFTypeS5S8PGWGTPC)
+		if err != nil {
+			return err
+		}
+		cause := v2.CauseRequestAccepted
+		if msg.EBI.MustEPSBearerID() == 7 {
+			cause = v2.CauseRequestRejectedReasonNotSpecified
+		}
+		return c.DeleteBearerResponse(teid, senderAddr, msg, ies.NewCause(cause, 0, 0, 0, nil))
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	s.createSession(ctx, "123451234567890", "some.apn.example")
+	sess, err := pgwConn.GetSessionByIMSI("123451234567890")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("ActivateDedicatedBearer/accepted", func(t *testing.T) {
+		br, err := p.ActivateDedicatedBearer(ctx, sess, &v2.QoSProfile{PL: 2, QCI: 1, GBRUL: 64, GBRDL: 64})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := br.GetEBI(); got != 6 {
+			t.Errorf("unexpected EBI: got %d, want 6", got)
+		}
+	})
+
+	t.Run("ActivateDedicatedBearer/rejected", func(t *testing.T) {
+		_, err := p.ActivateDedicatedBearer(ctx, sess, &v2.QoSProfile{PL: 2, QCI: 2, GBRUL: 64, GBRDL: 64})
+		cerr, ok := err.(*v2.CauseError)
+		if !ok {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if uint8(cerr.Cause) != v2.CauseServiceDenied {
+			t.Errorf("unexpected Cause: got %d, want %d", cerr.Cause, v2.CauseServiceDenied)
+		}
+	})
+
+	t.Run("ActivateDedicatedBearer/timeout", func(t *testing.T) {
+		shortCtx, shortCancel := context.WithTimeout(ctx, 200*time.Millisecond)
+		defer shortCancel()
+		_, err := p.ActivateDedicatedBearer(shortCtx, sess, &v2.QoSProfile{PL: 2, QCI: 3, GBRUL: 64, GBRDL: 64})
+		if err != context.DeadlineExceeded {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("DeactivateBearers/rejected", func(t *testing.T) {
+		err := p.DeactivateBearers(ctx, sess, 7)
+		cerr, ok := err.(*v2.CauseError)
+		if !ok {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if uint8(cerr.Cause) != v2.CauseRequestRejectedReasonNotSpecified {
+			t.Errorf("unexpected Cause: got %d, want %d", cerr.Cause, v2.CauseRequestRejectedReasonNotSpecified)
+		}
+		if _, err := sess.LookupBearerByEBI(6); err != nil {
+			t.Errorf("dedicated Bearer is removed on rejection: %v", err)
+		}
+	})
+
+	t.Run("DeactivateBearers/accepted", func(t *testing.T) {
+		if err := p.DeactivateBearers(ctx, sess, 6); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := sess.LookupBearerByEBI(6); err == nil {
+			t.Error("dedicated Bearer is not removed")
+		}
+	})
+
+	select {
+	case err := <-errCh:
+		t.Error(err)
+	default:
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// RespondWithPiggybacked sends a message(specified with "toBeSent" param) in response
+// to a message(specified with "received" param), with the initial message(specified
+// with "piggybacked" param) piggybacked on it in the same datagram, e.g., Create Bearer
+// Request on Create Session Response. It returns the SequenceNumber used in the
+// piggybacked message, which is set in the same way as SendMessageTo.
+//
+// If the path MTU to raddr is known by ProbePathMTU and the messages do not fit in it,
+// they are sent separately with RespondTo and SendMessageTo instead.
+//
+// The retransmitted request is responded with both of the messages, as the peer
+// expects them to be in the same datagram.
+func (c *Conn) RespondWithPiggybacked(raddr net.Addr, received, toBeSent, piggybacked messages.Message) (uint32, error) {
+	peer := c.peer(raddr)
+	if mtu, ok := peer.PathMTU(); ok && toBeSent.MarshalLen()+piggybacked.MarshalLen() > mtu {
+		if err := c.RespondTo(raddr, received, toBeSent); err != nil {
+			return 0, err
+		}
+		return c.SendMessageTo(piggybacked, raddr)
+	}
+
+	toBeSent.SetSequenceNumber(received.Sequence())
+	toBeSent = c.withLocalLoadControl(toBeSent, raddr)
+	toBeSent = c.withRecovery(toBeSent, peer)
+
+	seq := peer.incSequence()
+	piggybacked.SetSequenceNumber(seq)
+	piggybacked = c.withLocalLoadControl(piggybacked, raddr)
+
+	b, err := messages.MarshalMultiMessages(toBeSent, piggybacked)
+	if err == nil {
+		err = messages.ArrangeIEs(b, c.ieOrderFor(toBeSent.MessageType()))
+	}
+	if err == nil {
+		err = messages.ArrangeIEs(b[toBeSent.MarshalLen():], c.ieOrderFor(piggybacked.MessageType()))
+	}
+	if err != nil {
+		seq = peer.decSequence()
+		return seq, errors.Wrapf(err, "failed to send %T", piggybacked)
+	}
+	if _, err := c.writeTo(b, raddr, received, toBeSent); err != nil {
+		peer.failed()
+		seq = peer.decSequence()
+		return seq, errors.Wrapf(err, "failed to send %T", piggybacked)
+	}
+
+	peer.sent(piggybacked)
+	peer.recoveryNotified(toBeSent)
+	c.responseCache().store(raddr, received, b)
+	for _, msg := range []messages.Message{toBeSent, piggybacked} {
+		c.stats.messageSent(msg.MessageType())
+		c.log().Debug("sent message", msgFields(raddr, msg)...)
+		c.recordMessage(nil, DirectionOutgoing, raddr, msg)
+	}
+
+	c.mu.Lock()
+	c.sequence = seq
+	c.mu.Unlock()
+	return seq, nil
+}
@@ -0,0 +1,181 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// DefaultPathMTUProbeSizes is the sizes of the probes sent by ProbePathMTU if none
+// is given, which correspond to the UDP payload that fits in the IP packets of 1500 bytes
+// with IPv4 and IPv6, of 1400 bytes with IPv4, and of the minimum MTU of IPv6 and
+// IPv4, respectively.
+var DefaultPathMTUProbeSizes = []int{1472, 1452, 1372, 1232, 548}
+
+// DefaultPathMTUProbeTimeout is the default duration to wait for the Echo Response
+// to each probe sent by ProbePathMTU.
+const DefaultPathMTUProbeTimeout = time.Second
+
+// PathMTUProbeEnterpriseID is the Enterprise ID set in the Private Extension IE
+// used to pad the probes. The receiver ignores Private Extension with the unknown
+// Enterprise ID, and responds to the Echo Request as usual.
+const PathMTUProbeEnterpriseID uint16 = 0
+
+// minPathMTUProbeSize is the size of Echo Request with Recovery and empty Private
+// Extension, which is the smallest probe possible.
+var minPathMTUProbeSize = newPathMTUProbe(0, 0, 0).MarshalLen()
+
+// ProbePathMTU estimates the path MTU toward raddr by sending the Echo Requests
+// padded with Private Extension IE to the sizes given, and returns the largest size
+// that the Echo Response is received for within timeout.
+//
+// The sizes are the length of the GTPv2-C message, i.e., the UDP payload, and tried
+// from the largest one. Giving no sizes uses DefaultPathMTUProbeSizes. The result is
+// also kept in the Peer and can be retrieved with (*Peer) PathMTU later, to decide
+// whether to piggyback the messages or to send them separately. It returns ErrTimeout
+// if none of the probes is responded. Giving 0 or negative timeout uses
+// DefaultPathMTUProbeTimeout.
+//
+// The probes are not retransmitted and do not change PathState of the Peer, as the
+// ones larger than the path MTU are expected to be lost. Note that the result is
+// accurate only if the IP packets are not fragmented on the path, which depends on
+// the setting of the socket (e.g., IP_MTU_DISCOVER on Linux). The Conns in this
+// package cannot receive the probes larger than 1600 bytes.
+func (c *Conn) ProbePathMTU(raddr net.Addr, timeout time.Duration, sizes ...int) (int, error) {
+	if timeout <= 0 {
+		timeout = DefaultPathMTUProbeTimeout
+	}
+	if len(sizes) == 0 {
+		sizes = DefaultPathMTUProbeSizes
+	}
+	sorted := make([]int, len(sizes))
+	copy(sorted, sizes)
+	sort.Sort(sort.Reverse(sort.IntSlice(sorted)))
+
+	peer := c.peer(raddr)
+	for _, size := range sorted {
+		if size < minPathMTUProbeSize {
+			continue
+		}
+
+		probe, err := c.sendPathMTUProbe(peer, raddr, size)
+		if err != nil {
+			// the probe too large to be sent from the local interface.
+			continue
+		}
+
+		timer := time.NewTimer(timeout)
+		select {
+		case <-probe.done:
+			timer.Stop()
+			peer.setPathMTU(probe.size)
+			return probe.size, nil
+		case <-timer.C:
+			peer.removeProbe(probe.seq)
+		case <-c.closed():
+			timer.Stop()
+			peer.removeProbe(probe.seq)
+			return 0, errors.New("conn closed while probing path MTU")
+		}
+	}
+	return 0, ErrTimeout
+}
+
+// sendPathMTUProbe sends the Echo Request in size to raddr.
+func (c *Conn) sendPathMTUProbe(peer *Peer, raddr net.Addr, size int) (*pathMTUProbe, error) {
+	seq := peer.incSequence()
+	msg := newPathMTUProbe(seq, c.RestartCounter, size)
+
+	bp, err := marshalBuffer(msg, c.ieOrderFor(msg.MessageType()))
+	if err != nil {
+		peer.decSequence()
+		return nil, err
+	}
+	defer releaseBuffer(bp)
+
+	probe := peer.addProbe(seq, len(*bp))
+	if _, err := c.WriteTo(*bp, raddr); err != nil {
+		peer.removeProbe(seq)
+		peer.decSequence()
+		return nil, err
+	}
+	c.stats.messageSent(msg.MessageType())
+	c.log().Debug("sent message", msgFields(raddr, msg)...)
+	c.recordMessage(nil, DirectionOutgoing, raddr, msg)
+	return probe, nil
+}
+
+// newPathMTUProbe creates an Echo Request padded to size. The padding is omitted if
+// size is smaller than minPathMTUProbeSize.
+func newPathMTUProbe(seq uint32, restartCounter uint8, size int) *messages.EchoRequest {
+	rec := ies.NewRecovery(restartCounter)
+
+	// 8 for the header, 6 for the header and Enterprise ID of Private Extension.
+	padLen := size - 8 - rec.MarshalLen() - 6
+	if padLen < 0 {
+		padLen = 0
+	}
+	return messages.NewEchoRequest(
+		seq, rec, ies.NewPrivateExtension(PathMTUProbeEnterpriseID, make([]byte, padLen)),
+	)
+}
+
+// pathMTUProbe is an outstanding Echo Request sent by ProbePathMTU.
+type pathMTUProbe struct {
+	seq  uint32
+	size int
+	done chan struct{}
+}
+
+// PathMTU returns the path MTU toward the Peer estimated by (*Conn) ProbePathMTU,
+// in the length of GTPv2-C message. The second returned value is false if it has
+// not been probed successfully yet.
+func (p *Peer) PathMTU() (int, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.pathMTU, p.pathMTU > 0
+}
+
+func (p *Peer) setPathMTU(size int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.pathMTU = size
+}
+
+func (p *Peer) addProbe(seq uint32, size int) *pathMTUProbe {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.probes == nil {
+		p.probes = map[uint32]*pathMTUProbe{}
+	}
+	probe := &pathMTUProbe{seq: seq, size: size, done: make(chan struct{})}
+	p.probes[seq] = probe
+	return probe
+}
+
+func (p *Peer) removeProbe(seq uint32) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.probes, seq)
+}
+
+// probeResponded notifies the probe with seq that the Echo Response is received.
+// This must be called with p.mu held.
+func (p *Peer) probeResponded(seq uint32) {
+	if probe, ok := p.probes[seq]; ok {
+		close(probe.done)
+		delete(p.probes, seq)
+	}
+}
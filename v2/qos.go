@@ -0,0 +1,131 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// ModifyBearerQoS sets qos to the Bearer with ebi in Session, and returns the
+// Bearer Context IE with the EBI and the Bearer QoS built from qos, which is to be
+// sent to the peer to apply the same change.
+//
+// qos is copied, so modifying it after calling this does not affect the Bearer. It
+// returns BearerNotFoundError if the Bearer cannot be found.
+func (s *Session) ModifyBearerQoS(ebi uint8, qos *QoSProfile) (*ies.IE, error) {
+	br, err := s.LookupBearerByEBI(ebi)
+	if err != nil {
+		return nil, err
+	}
+
+	q := *qos
+	br.SetQoSProfile(&q)
+
+	s.notifyBearer(br, BearerChangeUpdated)
+
+	return ies.NewBearerContext(ies.NewEPSBearerID(ebi), newBearerQoS(&q)), nil
+}
+
+// newBearerQoS creates a Bearer QoS IE from qos.
+func newBearerQoS(qos *QoSProfile) *ies.IE {
+	var pci, pvi uint8
+	if qos.PCI {
+		pci = 1
+	}
+	if qos.PVI {
+		pvi = 1
+	}
+	return ies.NewBearerQoS(pci, qos.PL, pvi, qos.QCI, qos.MBRUL, qos.MBRDL, qos.GBRUL, qos.GBRDL)
+}
+
+// newQoSProfile creates a QoSProfile from the Bearer QoS IE.
+func newQoSProfile(ie *ies.IE) (*QoSProfile, error) {
+	qos := &QoSProfile{
+		PCI: ie.PreemptionCapability(),
+		PVI: ie.PreemptionVulnerability(),
+	}
+
+	var err error
+	qos.PL, err = ie.PriorityLevel()
+	if err != nil {
+		return nil, err
+	}
+	qos.QCI, err = ie.QCILabel()
+	if err != nil {
+		return nil, err
+	}
+	qos.MBRUL, err = ie.MBRForUplink()
+	if err != nil {
+		return nil, err
+	}
+	qos.MBRDL, err = ie.MBRForDownlink()
+	if err != nil {
+		return nil, err
+	}
+	qos.GBRUL, err = ie.GBRForUplink()
+	if err != nil {
+		return nil, err
+	}
+	qos.GBRDL, err = ie.GBRForDownlink()
+	if err != nil {
+		return nil, err
+	}
+	return qos, nil
+}
+
+// UpdateBearerQoS sets qos to the Bearer with ebi and sends an UpdateBearerRequest
+// with the Bearer Context built from it, together with TEID and IEs given.
+//
+// The Bearer is updated before the request is sent, so that the Bearer in Session
+// is always the one sent to the peer. If the peer rejects the request, call
+// (*Session) ModifyBearerQoS with the previous QoSProfile to revert it. The receiver
+// can apply the change with (*Conn) UpdateBearerResponse.
+func (c *Conn) UpdateBearerQoS(teid uint32, raddr net.Addr, ebi uint8, qos *QoSProfile, ie ...*ies.IE) (uint32, error) {
+	sess, err := c.GetSessionByTEID(teid, raddr)
+	if err != nil {
+		return 0, err
+	}
+
+	bc, err := sess.ModifyBearerQoS(ebi, qos)
+	if err != nil {
+		return 0, err
+	}
+	msg := messages.NewUpdateBearerRequest(teid, 0, append([]*ies.IE{bc}, ie...)...)
+
+	seq, err := c.SendMessageTo(msg, sess.PeerAddr())
+	if err != nil {
+		return 0, err
+	}
+	return seq, nil
+}
+
+// ModifyBearerCommandQoS sets qos to the Bearer with ebi and sends a
+// ModifyBearerCommand with the Bearer Context built from it, together with TEID and
+// IEs given.
+//
+// This is used by MME or SGSN to request the modification of the QoS, e.g., when
+// the subscribed QoS is changed by HSS. As with UpdateBearerQoS, the Bearer is
+// updated before the command is sent.
+func (c *Conn) ModifyBearerCommandQoS(teid uint32, raddr net.Addr, ebi uint8, qos *QoSProfile, ie ...*ies.IE) (uint32, error) {
+	sess, err := c.GetSessionByTEID(teid, raddr)
+	if err != nil {
+		return 0, err
+	}
+
+	bc, err := sess.ModifyBearerQoS(ebi, qos)
+	if err != nil {
+		return 0, err
+	}
+	msg := messages.NewModifyBearerCommand(teid, 0, append([]*ies.IE{bc}, ie...)...)
+
+	seq, err := c.SendMessageTo(msg, sess.PeerAddr())
+	if err != nil {
+		return 0, err
+	}
+	return seq, nil
+}
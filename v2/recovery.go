@@ -0,0 +1,112 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// RecoveryPolicy is a policy to handle the Recovery IE in the messages sent from
+// Conn.
+//
+// TS23.007 18 GTPv2-C based interfaces;
+// The Recovery IE is included in the first message sent to the peer after the node
+// or the peer is (re)started, so that the peer can detect the restart. It is not
+// required in the following messages.
+type RecoveryPolicy int
+
+// RecoveryPolicy definitions.
+const (
+	// RecoveryPolicyNone sends the messages with or without Recovery IE as they are
+	// given. This is the default.
+	RecoveryPolicyNone RecoveryPolicy = iota
+	// RecoveryPolicyFirstContact adds the Recovery IE with the RestartCounter of Conn
+	// to the first message sent to the Peer that can contain it, and removes it from
+	// the following ones. The Peer is contacted for the first time again when its
+	// RestartCounter is changed. Echo Request and Response are sent as they are.
+	RecoveryPolicyFirstContact
+)
+
+// SetRecoveryPolicy sets the RecoveryPolicy to be applied to the messages sent with
+// SendMessageTo and RespondTo, including the ones sent by the helpers.
+func (c *Conn) SetRecoveryPolicy(policy RecoveryPolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.recoveryPolicy = policy
+}
+
+func (c *Conn) getRecoveryPolicy() RecoveryPolicy {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.recoveryPolicy
+}
+
+// withRecovery returns the message with the Recovery IE added or removed by the
+// RecoveryPolicy toward the Peer. It returns msg as it is if nothing is to be changed
+// or it fails to change it.
+func (c *Conn) withRecovery(msg messages.Message, peer *Peer) messages.Message {
+	if c.getRecoveryPolicy() != RecoveryPolicyFirstContact {
+		return msg
+	}
+	switch msg.MessageType() {
+	case messages.MsgTypeEchoRequest, messages.MsgTypeEchoResponse:
+		return msg
+	}
+
+	want := !peer.isRecoveryNotified()
+	if has := recoveryIE(msg) != nil; has == want {
+		return msg
+	}
+
+	b, err := messages.Marshal(msg)
+	if err != nil {
+		return msg
+	}
+	h, err := messages.ParseHeader(b)
+	if err != nil {
+		return msg
+	}
+	decodedIEs, err := ies.ParseMultiIEs(h.Payload)
+	if err != nil {
+		return msg
+	}
+
+	var toBeSent []*ies.IE
+	for _, i := range decodedIEs {
+		if i.Type == ies.Recovery && i.Instance() == 0 {
+			continue
+		}
+		toBeSent = append(toBeSent, i)
+	}
+	if want {
+		toBeSent = append(toBeSent, ies.NewRecovery(c.RestartCounter))
+	}
+
+	h.Payload = nil
+	for _, i := range toBeSent {
+		serialized, err := i.Marshal()
+		if err != nil {
+			return msg
+		}
+		h.Payload = append(h.Payload, serialized...)
+	}
+	h.SetLength()
+
+	b, err = h.Marshal()
+	if err != nil {
+		return msg
+	}
+	m, err := messages.Parse(b)
+	if err != nil {
+		return msg
+	}
+
+	// the message without the field for Recovery IE has it in AdditionalIEs.
+	if has := recoveryIE(m) != nil; has != want {
+		return msg
+	}
+	return m
+}
@@ -0,0 +1,90 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// isResponseMessage reports whether the type of message is the Triggered message
+// that is sent in response to an Initial message, which is always the type next to
+// the one of the Initial message, e.g., Create Session Response to Create Session
+// Request and Modify Bearer Failure Indication to Modify Bearer Command.
+func isResponseMessage(msgType uint8) bool {
+	return msgType != 0 && isInitialMessage(msgType-1)
+}
+
+// isResponseTo reports whether the message with rspType is the response to the one
+// with reqType.
+func isResponseTo(reqType, rspType uint8) bool {
+	return isInitialMessage(reqType) && reqType+1 == rspType
+}
+
+// validateResponse checks msg against req, the outstanding request sent to the peer
+// with the same Sequence Number, if msg is the response to an Initial message.
+//
+// The msg is checked if the following is true, and UnexpectedResponseError is
+// returned otherwise.
+//
+//   - The type of msg is the response to the one of req.
+//   - The TEID in the header is the one in Sender F-TEID for Control Plane in req, or
+//     0 if msg rejects req, as the peer may not be able to decode the F-TEID.
+//   - The Cause at the top level, if any, is the one allowed in the responses.
+//
+// The responses without the outstanding request, e.g., the ones to the requests
+// written with WriteTo or already expired, are not checked.
+func validateResponse(req, msg messages.Message) error {
+	msgType := msg.MessageType()
+	if !isResponseMessage(msgType) {
+		return nil
+	}
+
+	mismatch := func(format string, a ...interface{}) error {
+		e := &UnexpectedResponseError{
+			MsgType: msg.MessageTypeName(),
+			Seq:     msg.Sequence(),
+			Reason:  fmt.Sprintf(format, a...),
+		}
+		if req != nil {
+			e.ReqType = req.MessageTypeName()
+		}
+		return e
+	}
+
+	if req == nil {
+		return nil
+	}
+	if !isResponseTo(req.MessageType(), msgType) {
+		return mismatch("message type does not match")
+	}
+
+	cause, _ := messages.FindIE(msg, ies.Cause, 0)
+	if cause != nil {
+		v, err := cause.Cause()
+		if err != nil {
+			return mismatch("malformed Cause")
+		}
+		// TS29.274 8.4: the values below 16 are used only in the requests.
+		if v < CauseRequestAccepted {
+			return mismatch("Cause %s(%d) is not allowed in response", Cause(v), v)
+		}
+	}
+
+	fteid, err := messages.FindIE(req, ies.FullyQualifiedTEID, 0)
+	if err != nil {
+		return nil
+	}
+	teid, err := fteid.TEID()
+	if err != nil {
+		return nil
+	}
+	if got := msg.TEID(); got != teid && !(got == 0 && cause != nil && !isAccepted(cause)) {
+		return mismatch("TEID %#08x does not match Sender F-TEID %#08x", got, teid)
+	}
+	return nil
+}
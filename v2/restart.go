@@ -0,0 +1,72 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// This file provides the helpers for Change Notification and the messages used to
+// handle the restart and the partial failure of the peer nodes (TS 23.007).
+//
+// PGW Restart Notification and Delete PDN Connection Set are not bound to the
+// Sessions on Conn, as they affect the set of PDN connections identified by the IP
+// address of the node or FQ-CSIDs. The TEID is always zero and the message is sent
+// to raddr directly.
+
+// ChangeNotificationRequest sends a ChangeNotificationRequest with TEID and IEs
+// given.
+//
+// This is used by MME or SGSN to report the change of the location or the RAT type
+// of the UE to PGW through SGW, when it is requested by ChangeReportingAction.
+func (c *Conn) ChangeNotificationRequest(teid uint32, raddr net.Addr, ie ...*ies.IE) (uint32, error) {
+	sess, err := c.GetSessionByTEID(teid, raddr)
+	if err != nil {
+		return 0, err
+	}
+
+	return c.SendMessageTo(messages.NewChangeNotificationRequest(teid, 0, ie...), sess.PeerAddr())
+}
+
+// ChangeNotificationResponse sends a ChangeNotificationResponse with TEID and IEs
+// given in response to the ChangeNotificationRequest.
+func (c *Conn) ChangeNotificationResponse(teid uint32, raddr net.Addr, req messages.Message, ie ...*ies.IE) error {
+	return c.RespondTo(raddr, req, messages.NewChangeNotificationResponse(teid, 0, ie...))
+}
+
+// PGWRestartNotification sends a PGWRestartNotification with IEs given to raddr.
+//
+// This is used by SGW to notify MME or SGSN that PGW has restarted or stopped, so
+// that the PDN connections toward the PGW can be released. Use ies.NewIPAddress
+// with instance 0 and 1 to give the PGW S5/S8 and the SGW S11/S4 IP address for the
+// control plane.
+func (c *Conn) PGWRestartNotification(raddr net.Addr, ie ...*ies.IE) (uint32, error) {
+	return c.SendMessageTo(messages.NewPGWRestartNotification(0, 0, ie...), raddr)
+}
+
+// PGWRestartNotificationAcknowledge sends a PGWRestartNotificationAcknowledge with
+// IEs given in response to the PGWRestartNotification.
+func (c *Conn) PGWRestartNotificationAcknowledge(raddr net.Addr, req messages.Message, ie ...*ies.IE) error {
+	return c.RespondTo(raddr, req, messages.NewPGWRestartNotificationAcknowledge(0, 0, ie...))
+}
+
+// DeletePDNConnectionSetRequest sends a DeletePDNConnectionSetRequest with IEs given
+// to raddr.
+//
+// This is used by the node that detected the partial failure of the peer to request
+// the deletion of all the PDN connections associated with the FQ-CSIDs given. Use
+// ies.NewFullyQualifiedCSID with the instance set to the one of the node type.
+func (c *Conn) DeletePDNConnectionSetRequest(raddr net.Addr, ie ...*ies.IE) (uint32, error) {
+	return c.SendMessageTo(messages.NewDeletePDNConnectionSetRequest(0, 0, ie...), raddr)
+}
+
+// DeletePDNConnectionSetResponse sends a DeletePDNConnectionSetResponse with IEs
+// given in response to the DeletePDNConnectionSetRequest.
+func (c *Conn) DeletePDNConnectionSetResponse(raddr net.Addr, req messages.Message, ie ...*ies.IE) error {
+	return c.RespondTo(raddr, req, messages.NewDeletePDNConnectionSetResponse(0, 0, ie...))
+}
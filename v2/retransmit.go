@@ -0,0 +1,98 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// Default values of RetransmitPolicy.
+//
+// TS29.274 7.6 Reliable Delivery of Signalling Messages; the request is retransmitted
+// N3-REQUESTS times at the interval of T3-RESPONSE if no response is received.
+const (
+	DefaultRetransmitInterval = 3 * time.Second
+	DefaultMaxRetransmissions = 2
+)
+
+// RetransmitPolicy is the way SendRequestTo retransmits the request not responded.
+type RetransmitPolicy struct {
+	// Interval is T3-RESPONSE, the interval to retransmit the request that is not
+	// responded. DefaultRetransmitInterval is used if 0.
+	Interval time.Duration
+
+	// MaxRetransmissions is N3-REQUESTS, the number of retransmissions before giving
+	// up the request. DefaultMaxRetransmissions is used if 0; give negative value to
+	// disable retransmission.
+	MaxRetransmissions int
+}
+
+func (p *RetransmitPolicy) values() (time.Duration, int) {
+	interval, maxRetries := DefaultRetransmitInterval, DefaultMaxRetransmissions
+	if p == nil {
+		return interval, maxRetries
+	}
+	if p.Interval > 0 {
+		interval = p.Interval
+	}
+	if p.MaxRetransmissions != 0 {
+		maxRetries = p.MaxRetransmissions
+	}
+	return interval, maxRetries
+}
+
+// SendRequestTo sends req to addr in the same way as SendMessageTo, and retransmits
+// the same bytes following policy in background until the response is received. nil
+// policy uses the default values.
+//
+// The retransmission also stops when ctx is done or Conn is closed, so ctx should be
+// canceled when the response is no longer waited for. The response is handled by the
+// HandlerFunc as usual, and the retransmissions are counted as RequestsRetransmitted
+// in Stats.
+func (c *Conn) SendRequestTo(ctx context.Context, req messages.Message, addr net.Addr, policy *RetransmitPolicy) (uint32, error) {
+	seq, raw, err := c.sendMessageTo(req, addr, true)
+	if err != nil {
+		return seq, err
+	}
+
+	interval, maxRetries := policy.values()
+	if maxRetries > 0 {
+		go c.retransmit(ctx, addr, seq, raw, interval, maxRetries)
+	}
+	return seq, nil
+}
+
+// retransmit writes raw to raddr at interval up to maxRetries times while the request
+// with seq is outstanding.
+func (c *Conn) retransmit(ctx context.Context, raddr net.Addr, seq uint32, raw []byte, interval time.Duration, maxRetries int) {
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	peer := c.peer(raddr)
+	for retries := 0; retries < maxRetries; retries++ {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.closed():
+			return
+		case <-timer.C:
+		}
+
+		if !peer.IsOutstanding(seq) {
+			return
+		}
+		if _, err := c.writeTo(raw, raddr, nil, nil); err != nil {
+			c.notifyError(err)
+			return
+		}
+		c.stats.requestRetransmission()
+		c.log().Debug("retransmitted request", "peer", raddr.String(), "seq", seq, "retries", retries+1)
+		timer.Reset(interval)
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+)
+
+// Service definitions used to select the nodes with NodeSelector (TS 29.303 19.4.3).
+//
+// The values are in the form of "app-service:app-protocol".
+const (
+	ServicePGWS5GTP = "x-3gpp-pgw:x-s5-gtp"
+	ServicePGWS8GTP = "x-3gpp-pgw:x-s8-gtp"
+	ServiceSGWS11   = "x-3gpp-sgw:x-s11"
+	ServiceSGWS5GTP = "x-3gpp-sgw:x-s5-gtp"
+	ServiceSGWS8GTP = "x-3gpp-sgw:x-s8-gtp"
+	ServiceSGWS4    = "x-3gpp-sgw:x-s4"
+)
+
+// NodeSelector selects the nodes that provide the service for the FQDN given, e.g.,
+// the PGWs that serve the APN or the SGWs that serve the TAI.
+//
+// Select returns the addresses of the candidates in the order of preference, or
+// ErrNodeNotFound if there is none. Use APNFQDN and TAIFQDN to build the FQDN.
+type NodeSelector interface {
+	Select(fqdn, service string) ([]net.Addr, error)
+}
+
+// NodeSelectorFunc is a function that implements NodeSelector, which is useful to
+// select the nodes statically e.g., in testing.
+type NodeSelectorFunc func(fqdn, service string) ([]net.Addr, error)
+
+// Select calls f(fqdn, service).
+func (f NodeSelectorFunc) Select(fqdn, service string) ([]net.Addr, error) {
+	return f(fqdn, service)
+}
+
+// APNFQDN returns the APN-FQDN used to select the PGW for apn (TS 29.303 4.3.2).
+//
+// If apn has the Operator Identifier, e.g., "internet.mnc045.mcc123.gprs", it is
+// converted to the one in the domain "3gppnetwork.org". Otherwise, the Operator
+// Identifier built from mcc and mnc is appended to apn.
+func APNFQDN(apn, mcc, mnc string) string {
+	apn = strings.ToLower(strings.TrimSuffix(apn, "."))
+	if strings.HasSuffix(apn, ".gprs") {
+		labels := strings.Split(strings.TrimSuffix(apn, ".gprs"), ".")
+		if n := len(labels); n > 2 && strings.HasPrefix(labels[n-2], "mnc") && strings.HasPrefix(labels[n-1], "mcc") {
+			return fmt.Sprintf(
+				"%s.apn.epc.%s.%s.3gppnetwork.org",
+				strings.Join(labels[:n-2], "."), labels[n-2], labels[n-1],
+			)
+		}
+	}
+	return fmt.Sprintf("%s.apn.epc.mnc%s.mcc%s.3gppnetwork.org", apn, padMNC(mnc), mcc)
+}
+
+// TAIFQDN returns the TAI-FQDN used to select the SGW for the TAI (TS 29.303 19.4.2.3).
+func TAIFQDN(tac uint16, mcc, mnc string) string {
+	return fmt.Sprintf(
+		"tac-lb%02x.tac-hb%02x.tac.epc.mnc%s.mcc%s.3gppnetwork.org",
+		tac&0xff, tac>>8, padMNC(mnc), mcc,
+	)
+}
+
+// padMNC returns mnc in 3 digits, as the MNC in the FQDNs is always 3 digits long.
+func padMNC(mnc string) string {
+	if len(mnc) >= 3 {
+		return mnc
+	}
+	return strings.Repeat("0", 3-len(mnc)) + mnc
+}
+
+// SetNodeSelector sets the NodeSelector used to select the peer in
+// CreateSessionByAPN. Giving nil removes it.
+func (c *Conn) SetNodeSelector(ns NodeSelector) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nodeSelector = ns
+}
+
+func (c *Conn) getNodeSelector() NodeSelector {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.nodeSelector
+}
+
+// CreateSessionByAPN sends a CreateSessionRequest to the node selected for the APN in
+// the IEs given, instead of the raddr given to CreateSession.
+//
+// The APN-FQDN is built from the Access Point Name and the Serving Network IEs, and
+// the node that is the most preferred by the NodeSelector set with SetNodeSelector
+// for service is used, e.g., ServicePGWS5GTP for SGW to select PGW. As with
+// CreateSession, it returns the Session and the SequenceNumber of the request.
+func (c *Conn) CreateSessionByAPN(service string, ie ...*ies.IE) (*Session, uint32, error) {
+	ns := c.getNodeSelector()
+	if ns == nil {
+		return nil, 0, &RequiredParameterMissingError{"NodeSelector", "NodeSelector must be set with SetNodeSelector"}
+	}
+
+	var (
+		apn, mcc, mnc string
+		err           error
+	)
+	for _, i := range ie {
+		if i == nil {
+			continue
+		}
+		switch i.Type {
+		case ies.AccessPointName:
+			if apn, err = i.AccessPointName(); err != nil {
+				return nil, 0, err
+			}
+		case ies.ServingNetwork:
+			if mcc, err = i.MCC(); err != nil {
+				return nil, 0, err
+			}
+			if mnc, err = i.MNC(); err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+	if apn == "" {
+		return nil, 0, &RequiredParameterMissingError{"APN", "Access Point Name IE must be given"}
+	}
+
+	addrs, err := ns.Select(APNFQDN(apn, mcc, mnc), service)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(addrs) == 0 {
+		return nil, 0, ErrNodeNotFound
+	}
+	return c.CreateSession(addrs[0], ie...)
+}
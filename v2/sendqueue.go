@@ -0,0 +1,239 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// SendQueue is the configuration of the bounded queue of the datagrams to be sent,
+// which are written to the socket by a goroutine, so that the HandlerFuncs are not
+// stalled by a slow or blocked socket.
+//
+// The datagrams are written in the order they are queued. As they are written
+// asynchronously, the errors in writing them are notified via ErrorHandler or errCh
+// instead of being returned from SendMessageTo, RespondTo or WriteTo.
+type SendQueue struct {
+	// Size is the number of datagrams that can be queued. The zero value means
+	// DefaultSendQueueSize.
+	Size int
+	// Overflow is the action taken when the queue is full. OverflowActionBlock blocks
+	// the sender until the queue has room, which pushes back to the callers, and
+	// OverflowActionDrop returns ErrSendQueueFull immediately.
+	Overflow OverflowAction
+	// BlockTimeout is the maximum duration to block with OverflowActionBlock, after
+	// which ErrSendQueueFull is returned. The zero value means blocking until the
+	// queue has room.
+	BlockTimeout time.Duration
+}
+
+// DefaultSendQueueSize is the default number of datagrams that can be queued in
+// SendQueue.
+const DefaultSendQueueSize = 256
+
+// SetSendQueue starts the SendQueue to write the outgoing datagrams asynchronously.
+// Giving nil stops it and makes Conn write them synchronously, which is the default.
+//
+// The datagrams already queued are written before the previous queue is stopped,
+// which can be waited for with FlushSendQueue beforehand. The datagrams not queued
+// because the queue is full are counted as SendDropped in Stats.
+func (c *Conn) SetSendQueue(sq *SendQueue) {
+	var s *sender
+	if sq != nil {
+		s = newSender(c, *sq)
+	}
+
+	c.mu.Lock()
+	prev := c.sendQ
+	c.sendQ = s
+	c.mu.Unlock()
+
+	if prev != nil {
+		prev.stop()
+	}
+}
+
+// FlushSendQueue waits until all the datagrams queued before it is called are written
+// to the socket, or ctx is done. It returns immediately if SendQueue is not set.
+//
+// ctx.Err() is returned if ctx is done first, including while waiting for the room in
+// the queue that is full. nil is returned if SendQueue is stopped meanwhile, as the
+// datagrams queued are written before it stops.
+func (c *Conn) FlushSendQueue(ctx context.Context) error {
+	s := c.sender()
+	if s == nil {
+		return nil
+	}
+
+	flushed := make(chan struct{})
+	switch err := s.push(ctx, &outgoing{flushed: flushed}); err {
+	case nil:
+	case errSenderStopped:
+		// the datagrams queued are being written anyway.
+		return nil
+	default:
+		return err
+	}
+	select {
+	case <-flushed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c *Conn) sender() *sender {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sendQ
+}
+
+// outgoing is the datagram queued in SendQueue, or the marker of FlushSendQueue if
+// flushed is not nil.
+type outgoing struct {
+	pktConn  net.PacketConn
+	raddr    net.Addr
+	p        []byte
+	toBeSent messages.Message
+	flushed  chan struct{}
+}
+
+// sender keeps the queue and the goroutine of SendQueue.
+type sender struct {
+	conn   *Conn
+	config SendQueue
+	queue  chan *outgoing
+
+	// mu protects queue from being closed while sending to it.
+	mu      sync.RWMutex
+	stopped bool
+	done    chan struct{}
+	once    sync.Once
+}
+
+func newSender(c *Conn, sq SendQueue) *sender {
+	if sq.Size <= 0 {
+		sq.Size = DefaultSendQueueSize
+	}
+
+	s := &sender{
+		conn:   c,
+		config: sq,
+		queue:  make(chan *outgoing, sq.Size),
+		done:   make(chan struct{}),
+	}
+	go s.serve()
+	return s
+}
+
+func (s *sender) serve() {
+	for o := range s.queue {
+		if o.flushed != nil {
+			close(o.flushed)
+			continue
+		}
+
+		select {
+		case <-s.conn.closed():
+			// the socket is already closed and nobody may be watching the errors.
+			continue
+		default:
+		}
+		if _, err := o.pktConn.WriteTo(o.p, o.raddr); err != nil {
+			s.conn.notifyError(errors.Wrapf(err, "failed to send queued datagram to %s", o.raddr))
+			continue
+		}
+		s.conn.traceOutgoing(o.raddr, o.p, o.toBeSent)
+	}
+}
+
+// enqueue queues the copy of p to be written to raddr from pktConn. It returns false
+// if the sender is already stopped, and true otherwise even if it fails to queue.
+func (s *sender) enqueue(pktConn net.PacketConn, p []byte, raddr net.Addr, toBeSent messages.Message) (bool, error) {
+	o := &outgoing{
+		pktConn:  pktConn,
+		raddr:    raddr,
+		p:        append([]byte(nil), p...),
+		toBeSent: toBeSent,
+	}
+
+	ctx := context.Background()
+	if s.config.Overflow == OverflowActionBlock && s.config.BlockTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.config.BlockTimeout)
+		defer cancel()
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.stopped {
+		return false, nil
+	}
+	if s.config.Overflow == OverflowActionDrop {
+		select {
+		case s.queue <- o:
+			return true, nil
+		default:
+			s.conn.stats.sendDrop()
+			return true, ErrSendQueueFull
+		}
+	}
+
+	select {
+	case s.queue <- o:
+		return true, nil
+	case <-ctx.Done():
+		s.conn.stats.sendDrop()
+		return true, ErrSendQueueFull
+	case <-s.done:
+		// stopped while waiting; let the caller write it instead.
+		return false, nil
+	}
+}
+
+var errSenderStopped = errors.New("send queue stopped")
+
+// push queues o regardless of Overflow, waiting until ctx is done. It returns
+// ctx.Err() if ctx is done before o is queued, or errSenderStopped if the sender is
+// stopped.
+func (s *sender) push(ctx context.Context, o *outgoing) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.stopped {
+		return errSenderStopped
+	}
+
+	select {
+	case s.queue <- o:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.done:
+		return errSenderStopped
+	}
+}
+
+// depth returns the number of datagrams in the queue.
+func (s *sender) depth() int {
+	return len(s.queue)
+}
+
+// stop stops the goroutine after it writes the datagrams queued.
+func (s *sender) stop() {
+	s.once.Do(func() {
+		close(s.done)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.stopped = true
+		close(s.queue)
+	})
+}
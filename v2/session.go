@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/wmnsk/go-gtp/utils"
 	"github.com/wmnsk/go-gtp/v2/messages"
 )
 
@@ -21,15 +22,54 @@ type Location struct {
 }
 
 // Subscriber is a subscriber that belongs to a GTPv2 session.
+//
+// The Subscriber associated with Session is accessed by Conn while handling the
+// messages, and should be read and replaced with (*Session) GetSubscriber and
+// SetSubscriber instead of modifying the fields of it.
 type Subscriber struct {
 	IMSI, MSISDN, IMEI string
 	*Location
 }
 
+// Validate returns utils.InvalidIdentityError if any of IMSI, MSISDN and IMEI is
+// malformed. The ones that are empty are not validated.
+//
+// This is not done when the Session is created or activated, as the values from
+// the peer are not always strictly valid, e.g., IMEI with the check digit set to 0.
+func (s *Subscriber) Validate() error {
+	if s.IMSI != "" {
+		if err := utils.IMSI(s.IMSI).Validate(); err != nil {
+			return err
+		}
+	}
+	if s.MSISDN != "" {
+		if err := utils.MSISDN(s.MSISDN).Validate(); err != nil {
+			return err
+		}
+	}
+	if s.IMEI != "" {
+		if err := utils.IMEI(s.IMEI).Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Session is a GTPv2 Session.
 type Session struct {
 	mu       sync.Mutex
 	isActive bool
+	// isSuspended is set between Suspend and Resume.
+	isSuspended bool
+	// isISRActive is set while ISR is activated for the UE.
+	isISRActive bool
+	// chargingCharacteristics and apnAMBR* are the ones of the PDN connection, which
+	// are 0 if not known.
+	chargingCharacteristics uint16
+	apnAMBRUp, apnAMBRDown  uint32
+	// ddnDelayedUntil is the time until when the Downlink Data Notification should
+	// not be sent, requested by the peer with Data Notification Delay.
+	ddnDelayedUntil time.Time
 	*teidMap
 	*bearerMap
 
@@ -37,12 +77,25 @@ type Session struct {
 	msgQueue chan messages.Message
 
 	// peerAddr is a net.Addr of the peer associated with Session.
-	// To avoid calling String() many times, peerAddrString is set when NewSession
+	// To avoid calling addrString() many times, peerAddrString is set when NewSession
 	// and UpdatePeerAddr is called.
 	peerAddr       net.Addr
 	peerAddrString string
 
+	// history is the signaling events of Session, which is nil if not enabled.
+	history *sessionHistory
+
+	// handlers is the HandlerFuncs scoped to Session, which is nil until any of
+	// them is added.
+	handlers *msgHandlerMap
+
+	// conn is the Conn that Session is added to, which is notified of the events.
+	conn *Conn
+
 	// Subscriber is a Subscriber associated with Session.
+	//
+	// Deprecated: the fields promoted from this are read and written without the lock
+	// of Session. Use GetIMSI, GetSubscriber and SetSubscriber instead.
 	*Subscriber
 }
 
@@ -54,7 +107,7 @@ func NewSession(peerAddr net.Addr, sub *Subscriber) *Session {
 	s := &Session{
 		mu:             sync.Mutex{},
 		peerAddr:       peerAddr,
-		peerAddrString: peerAddr.String(),
+		peerAddrString: addrString(peerAddr),
 		teidMap:        newTeidMap(),
 		bearerMap:      newBearerMap("default", &Bearer{QoSProfile: &QoSProfile{}}),
 		Subscriber:     sub,
@@ -65,18 +118,63 @@ func NewSession(peerAddr net.Addr, sub *Subscriber) *Session {
 }
 
 // Activate marks a Session active.
+//
+// Only IMSI is checked here. Use Validate beforehand to check if Session has all the
+// TEIDs and parameters the node requires.
 func (s *Session) Activate() error {
 	s.mu.Lock()
-	defer s.mu.Unlock()
 
 	if s.IMSI == "" {
+		s.mu.Unlock()
 		return &RequiredParameterMissingError{"IMSI", "Session must have IMSI set"}
 	}
 
+	activated := !s.isActive
 	s.isActive = true
+	s.mu.Unlock()
+
+	if activated {
+		s.notifyActivated()
+	}
 	return nil
 }
 
+// Validate checks if Session has all the parameters required to be activated by the
+// node, and returns SessionIncompleteError listing the ones missing. This is useful
+// to find out why the call flow fails before Activate.
+//
+// The TEIDs of requiredIFTypes must be present, e.g., IFTypeS11MMEGTPC and
+// IFTypeS11S4SGWGTPC for MME, as well as IMSI and the default Bearer with EBI. The
+// Subscriber is also validated with (*Subscriber) Validate, whose error is returned
+// as it is.
+func (s *Session) Validate(requiredIFTypes ...uint8) error {
+	if sub := s.GetSubscriber(); sub != nil {
+		if err := sub.Validate(); err != nil {
+			return err
+		}
+	}
+
+	e := &SessionIncompleteError{IMSI: s.GetIMSI()}
+	if e.IMSI == "" {
+		e.MissingParams = append(e.MissingParams, "IMSI")
+	}
+	if br := s.GetDefaultBearer(); br == nil {
+		e.MissingParams = append(e.MissingParams, "default Bearer")
+	} else if br.GetEBI() == 0 {
+		e.MissingParams = append(e.MissingParams, "EBI")
+	}
+	for _, it := range requiredIFTypes {
+		if _, ok := s.TEID(it); !ok {
+			e.MissingIFTypes = append(e.MissingIFTypes, it)
+		}
+	}
+
+	if len(e.MissingParams) == 0 && len(e.MissingIFTypes) == 0 {
+		return nil
+	}
+	return e
+}
+
 // Deactivate marks a Session inactive.
 func (s *Session) Deactivate() error {
 	s.mu.Lock()
@@ -96,13 +194,72 @@ func (s *Session) IsActive() bool {
 
 // PeerAddr returns the address of the peer node associated with Session.
 func (s *Session) PeerAddr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	return s.peerAddr
 }
 
 // UpdatePeerAddr updates the address of the peer node associated with Session.
 func (s *Session) UpdatePeerAddr(peer net.Addr) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	s.peerAddr = peer
-	s.peerAddrString = peer.String()
+	s.peerAddrString = addrString(peer)
+}
+
+func (s *Session) peerAddrStr() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.peerAddrString
+}
+
+// GetIMSI returns the IMSI of the Subscriber associated with Session.
+func (s *Session) GetIMSI() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.Subscriber == nil {
+		return ""
+	}
+	return s.IMSI
+}
+
+// GetSubscriber returns the copy of the Subscriber associated with Session, including
+// the Location.
+func (s *Session) GetSubscriber() *Subscriber {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.Subscriber == nil {
+		return nil
+	}
+	sub := *s.Subscriber
+	if sub.Location != nil {
+		loc := *sub.Location
+		sub.Location = &loc
+	}
+	return &sub
+}
+
+// SetSubscriber replaces the Subscriber associated with Session with the copy of sub,
+// so modifying it after calling this does not affect Session.
+func (s *Session) SetSubscriber(sub *Subscriber) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sub == nil {
+		s.Subscriber = nil
+		return
+	}
+	cp := *sub
+	if cp.Location != nil {
+		loc := *cp.Location
+		cp.Location = &loc
+	}
+	s.Subscriber = &cp
 }
 
 // AddTEID adds TEID to session with InterfaceType.
@@ -118,6 +275,17 @@ func (s *Session) GetTEID(ifType uint8) (uint32, error) {
 	return 0, ErrTEIDNotFound
 }
 
+// TEID returns TEID associated with InterfaceType given, and whether it is found.
+func (s *Session) TEID(ifType uint8) (uint32, bool) {
+	return s.teidMap.load(ifType)
+}
+
+// MustTEID returns TEID associated with InterfaceType given, or 0 if not found.
+func (s *Session) MustTEID(ifType uint8) uint32 {
+	teid, _ := s.teidMap.load(ifType)
+	return teid
+}
+
 // PassMessageTo passes the message (typically "triggerred message") to the session
 // expecting to receive it.
 //
@@ -142,7 +310,7 @@ func (s *Session) WaitMessage(seq uint32, timeout time.Duration) (messages.Messa
 	select {
 	case msg, ok := <-s.msgQueue:
 		if !ok {
-			return nil, &InvalidSessionError{s.IMSI}
+			return nil, &InvalidSessionError{s.GetIMSI()}
 		}
 
 		if seqGot := msg.Sequence(); seqGot != seq {
@@ -160,11 +328,17 @@ func (s *Session) WaitMessage(seq uint32, timeout time.Duration) (messages.Messa
 // always available after created a Session.
 func (s *Session) AddBearer(name string, br *Bearer) {
 	s.bearerMap.store(name, br)
+	s.notifyBearer(br, BearerChangeAdded)
 }
 
 // RemoveBearer removes a Bearer looked up by name.
 func (s *Session) RemoveBearer(name string) {
+	br, ok := s.bearerMap.load(name)
+	if !ok {
+		return
+	}
 	s.bearerMap.delete(name)
+	s.notifyBearer(br, BearerChangeRemoved)
 }
 
 // RemoveBearerByEBI removes a Bearer looked up by name.
@@ -173,7 +347,7 @@ func (s *Session) RemoveBearerByEBI(ebi uint8) {
 	if err != nil {
 		return
 	}
-	s.bearerMap.delete(name)
+	s.RemoveBearer(name)
 }
 
 // GetDefaultBearer returns the default bearer.
@@ -191,6 +365,7 @@ func (s *Session) GetDefaultBearer() *Bearer {
 func (s *Session) SetDefaultBearer(bearer *Bearer) {
 	// it is not expected that the default bearer cannot be found.
 	s.bearerMap.store("default", bearer)
+	s.notifyBearer(bearer, BearerChangeAdded)
 }
 
 // LookupBearerByName looks up Bearer registered in Session by name.
@@ -199,7 +374,7 @@ func (s *Session) LookupBearerByName(name string) (*Bearer, error) {
 		return br, nil
 	}
 
-	return nil, &BearerNotFoundError{IMSI: s.IMSI}
+	return nil, &BearerNotFoundError{IMSI: s.GetIMSI()}
 }
 
 // LookupBearerByEBI looks up Bearer registered in Session by EBI.
@@ -207,7 +382,7 @@ func (s *Session) LookupBearerByEBI(ebi uint8) (*Bearer, error) {
 	var bearer *Bearer
 	s.bearerMap.rangeWithFunc(func(name, br interface{}) bool {
 		b := br.(*Bearer)
-		if ebi == b.EBI {
+		if ebi == b.GetEBI() {
 			bearer = b
 			return false
 		}
@@ -215,7 +390,7 @@ func (s *Session) LookupBearerByEBI(ebi uint8) (*Bearer, error) {
 	})
 
 	if bearer == nil {
-		return nil, &BearerNotFoundError{IMSI: s.IMSI}
+		return nil, &BearerNotFoundError{IMSI: s.GetIMSI()}
 
 	}
 	return bearer, nil
@@ -227,7 +402,7 @@ func (s *Session) LookupBearerNameByEBI(ebi uint8) (string, error) {
 	var name string
 	s.bearerMap.rangeWithFunc(func(n, br interface{}) bool {
 		bearer := br.(*Bearer)
-		if ebi == bearer.EBI {
+		if ebi == bearer.GetEBI() {
 			name = n.(string)
 			return false
 		}
@@ -235,7 +410,7 @@ func (s *Session) LookupBearerNameByEBI(ebi uint8) (string, error) {
 	})
 
 	if name == "" {
-		return "", &BearerNotFoundError{IMSI: s.IMSI}
+		return "", &BearerNotFoundError{IMSI: s.GetIMSI()}
 
 	}
 	return name, nil
@@ -246,7 +421,7 @@ func (s *Session) LookupBearerNameByEBI(ebi uint8) (string, error) {
 // If no EBI found, it returns 0(=invalid value for EBI).
 func (s *Session) LookupEBIByName(name string) uint8 {
 	if br, ok := s.bearerMap.load(name); ok {
-		return br.EBI
+		return br.GetEBI()
 	}
 
 	return 0
@@ -259,8 +434,8 @@ func (s *Session) LookupEBIByTEID(teid uint32) uint8 {
 	var ebi uint8
 	s.bearerMap.rangeWithFunc(func(name, bearer interface{}) bool {
 		br := bearer.(*Bearer)
-		if teid == br.teidIn || teid == br.teidOut {
-			ebi = br.EBI
+		if br.hasTEID(teid) {
+			ebi = br.GetEBI()
 			return false
 		}
 		return true
@@ -290,6 +465,10 @@ func (t *teidMap) load(ifType uint8) (uint32, bool) {
 	return teid.(uint32), true
 }
 
+func (t *teidMap) delete(ifType uint8) {
+	t.syncMap.Delete(ifType)
+}
+
 func (t *teidMap) rangeWithFunc(fn func(ifType, teid interface{}) bool) {
 	t.syncMap.Range(fn)
 }
@@ -0,0 +1,530 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+// Package sgw provides S-GW built on a pair of v2.Conn for S11 and S5/S8, which
+// relays the standard call flows between the MME and the P-GW: the creation and
+// deletion of the PDN connections, and the activation and deactivation of the
+// dedicated bearers requested by the P-GW. Modify Bearer Request is handled locally,
+// as the user plane is terminated at S-GW.
+//
+// A Session is created on each Conn for a PDN connection, and they are linked with
+// each other. The responses are correlated by the HandlerFuncs registered by New,
+// which should not be overwritten.
+package sgw
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/go-gtp/internal/exchange"
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// Policy is a set of functions and parameters to make the decisions in relaying the
+// call flows. Any of them can be zero.
+type Policy struct {
+	// SelectPGW returns the address of P-GW to which Create Session Request is
+	// relayed. The request is rejected with "No resources available" if it returns
+	// error. If nil, the address in the PGW S5/S8 Address for Control Plane in the
+	// request is used with v2.GTPCPort.
+	SelectPGW func(req *messages.CreateSessionRequest) (net.Addr, error)
+
+	// Timeout is the duration to wait for the response to the request relayed. The
+	// request is rejected with "Remote peer not responding" when it is exceeded. If
+	// 0, v2.DefaultTransactionTimeout is used.
+	Timeout time.Duration
+}
+
+// SGW is S-GW that relays the call flows between S11 and S5/S8.
+type SGW struct {
+	s11, s5        *v2.Conn
+	s1uIP, s5uIP   string
+	policy         Policy
+	s11Calls       *exchange.Table
+	s5Calls        *exchange.Table
+	mu             sync.Mutex
+	linkedSessions map[*v2.Session]*v2.Session
+}
+
+// New creates a new SGW that relays the call flows between s11 and s5, and registers
+// the HandlerFuncs for them to the Conns. The s1uIP and s5uIP are the IP addresses
+// set in the F-TEIDs of S1-U S-GW and S5/S8-U S-GW respectively.
+func New(s11, s5 *v2.Conn, s1uIP, s5uIP string, policy *Policy) *SGW {
+	s := &SGW{
+		s11:            s11,
+		s5:             s5,
+		s1uIP:          s1uIP,
+		s5uIP:          s5uIP,
+		s11Calls:       exchange.New(),
+		s5Calls:        exchange.New(),
+		linkedSessions: map[*v2.Session]*v2.Session{},
+	}
+	if policy != nil {
+		s.policy = *policy
+	}
+	if s.policy.Timeout <= 0 {
+		s.policy.Timeout = v2.DefaultTransactionTimeout
+	}
+
+	s.s11Calls.Handle(
+		s11,
+		messages.MsgTypeCreateBearerResponse,
+		messages.MsgTypeDeleteBearerResponse,
+	)
+	s11.HandleCreateSessionRequest(s.handleCreateSessionRequest)
+	s11.HandleModifyBearerRequest(s.handleModifyBearerRequest)
+	s11.HandleDeleteSessionRequest(s.handleDeleteSessionRequest)
+
+	s.s5Calls.Handle(
+		s5,
+		messages.MsgTypeCreateSessionResponse,
+		messages.MsgTypeDeleteSessionResponse,
+	)
+	s5.HandleCreateBearerRequest(s.handleCreateBearerRequest)
+	s5.HandleDeleteBearerRequest(s.handleDeleteBearerRequest)
+	return s
+}
+
+// S11Conn returns the Conn that SGW uses for S11.
+func (s *SGW) S11Conn() *v2.Conn {
+	return s.s11
+}
+
+// S5Conn returns the Conn that SGW uses for S5/S8.
+func (s *SGW) S5Conn() *v2.Conn {
+	return s.s5
+}
+
+// LinkedSession returns the Session on the other Conn linked with sess, i.e., the one
+// on S5/S8 for the Session on S11, and vice versa.
+func (s *SGW) LinkedSession(sess *v2.Session) (*v2.Session, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	linked, ok := s.linkedSessions[sess]
+	return linked, ok
+}
+
+func (s *SGW) link(s11Sess, s5Sess *v2.Session) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.linkedSessions[s11Sess] = s5Sess
+	s.linkedSessions[s5Sess] = s11Sess
+}
+
+// remove removes s11Sess and the Session linked with it from the Conns.
+func (s *SGW) remove(s11Sess *v2.Session) {
+	s.mu.Lock()
+	s5Sess := s.linkedSessions[s11Sess]
+	delete(s.linkedSessions, s11Sess)
+	delete(s.linkedSessions, s5Sess)
+	s.mu.Unlock()
+
+	s.s11.RemoveSession(s11Sess)
+	if s5Sess != nil {
+		s.s5.RemoveSession(s5Sess)
+	}
+}
+
+// relay defers the response to req from raddr on c, and responds with the message
+// returned by fn in another goroutine. The Transaction is kept for twice as long as
+// the Timeout, so that fn can respond after giving up on the peer.
+func (s *SGW) relay(c *v2.Conn, raddr net.Addr, req messages.Message, fn func(ctx context.Context) messages.Message) error {
+	tx := c.Defer(raddr, req, 2*s.policy.Timeout)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), s.policy.Timeout)
+		defer cancel()
+
+		// the error is passed to the background error handling by Transaction.
+		_ = tx.Respond(fn(ctx))
+	}()
+	return v2.ErrPending
+}
+
+// causeOf returns the Cause IE to reject the request relayed with err.
+func causeOf(err error) *ies.IE {
+	switch e := err.(type) {
+	case *v2.CauseError:
+		return ies.NewCause(uint8(e.Cause), 0, 0, 0, nil)
+	case *v2.UnexpectedTypeError:
+		return ies.NewCause(v2.CauseRequestRejectedReasonNotSpecified, 0, 0, 0, nil)
+	default:
+		return ies.NewCause(v2.CauseRemotePeerNotResponding, 0, 0, 0, nil)
+	}
+}
+
+func (s *SGW) selectPGW(req *messages.CreateSessionRequest) (net.Addr, error) {
+	if fn := s.policy.SelectPGW; fn != nil {
+		return fn(req)
+	}
+	if req.PGWS5S8FTEIDC == nil {
+		return nil, &v2.RequiredIEMissingError{Type: ies.FullyQualifiedTEID}
+	}
+	ip, err := req.PGWS5S8FTEIDC.IPAddress()
+	if err != nil {
+		return nil, err
+	}
+	return &net.UDPAddr{IP: net.ParseIP(ip), Port: v2.GTPCPort}, nil
+}
+
+func (s *SGW) handleCreateSessionRequest(c *v2.Conn, senderAddr net.Addr, msg *messages.CreateSessionRequest) error {
+	s11Sess, err := v2.NewSessionFromIEs(senderAddr, messages.IEs(msg)...)
+	if err != nil {
+		return err
+	}
+	mmeTEID, err := s11Sess.GetTEID(v2.IFTypeS11MMEGTPC)
+	if err != nil {
+		return c.RespondTo(senderAddr, msg, messages.NewCreateSessionResponse(
+			0, 0, ies.NewCause(v2.CauseMandatoryIEMissing, 0, 0, 0, nil),
+		))
+	}
+	pgwAddr, err := s.selectPGW(msg)
+	if err != nil {
+		return c.RespondTo(senderAddr, msg, messages.NewCreateSessionResponse(
+			mmeTEID, 0, ies.NewCause(v2.CauseNoResourcesAvailable, 0, 0, 0, nil),
+		))
+	}
+
+	return s.relay(c, senderAddr, msg, func(ctx context.Context) messages.Message {
+		s5Sess, rsp, err := s.createS5Session(ctx, pgwAddr, msg)
+		if err != nil {
+			return messages.NewCreateSessionResponse(mmeTEID, 0, causeOf(err))
+		}
+
+		s11FTEID := exchange.NewFTEID(s.s11, v2.IFTypeS11S4SGWGTPC, exchange.LocalIP(s.s11))
+		s1uFTEID := exchange.NewFTEID(s.s11, v2.IFTypeS1USGWGTPU, s.s1uIP)
+		for _, fteid := range []*ies.IE{s11FTEID, s1uFTEID} {
+			if err := exchange.AddFTEID(s11Sess, fteid); err != nil {
+				return messages.NewCreateSessionResponse(mmeTEID, 0, causeOf(err))
+			}
+		}
+
+		br := s11Sess.GetDefaultBearer()
+		rspIEs := []*ies.IE{ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil), s11FTEID}
+		if ie := rsp.SenderFTEIDC; ie != nil {
+			rspIEs = append(rspIEs, ies.New(ie.Type, 1, ie.Payload))
+		}
+		if ie := rsp.PAA; ie != nil {
+			br.SetSubscriberIP(s5Sess.GetDefaultBearer().GetSubscriberIP())
+			rspIEs = append(rspIEs, ie)
+		}
+		children := []*ies.IE{
+			ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+			ies.NewEPSBearerID(br.GetEBI()),
+			s1uFTEID,
+		}
+		for _, bc := range messages.FindIEs(rsp, ies.BearerContext) {
+			for _, child := range bc.ChildIEs {
+				if child.Type == ies.ChargingID {
+					br.SetChargingID(child.MustChargingID())
+					children = append(children, child)
+				}
+			}
+		}
+		rspIEs = append(rspIEs, ies.NewBearerContext(children...))
+
+		if err := s11Sess.Activate(); err != nil {
+			return messages.NewCreateSessionResponse(mmeTEID, 0, causeOf(err))
+		}
+		s.s11.AddSession(s11Sess)
+		s.link(s11Sess, s5Sess)
+		return messages.NewCreateSessionResponse(mmeTEID, 0, rspIEs...)
+	})
+}
+
+// createS5Session relays Create Session Request received on S11 to the P-GW at
+// pgwAddr with the F-TEIDs of S-GW, and returns the Session created on S5/S8 if it is
+// accepted.
+func (s *SGW) createS5Session(ctx context.Context, pgwAddr net.Addr, req *messages.CreateSessionRequest) (*v2.Session, *messages.CreateSessionResponse, error) {
+	ie := []*ies.IE{exchange.NewFTEID(s.s5, v2.IFTypeS5S8SGWGTPC, exchange.LocalIP(s.s5))}
+	for _, i := range messages.IEs(req) {
+		switch {
+		case i.Type == ies.FullyQualifiedTEID:
+			// the F-TEIDs of S11 are replaced with the ones of S5/S8.
+			continue
+		case i.Type == ies.BearerContext:
+			children := []*ies.IE{exchange.NewFTEID(s.s5, v2.IFTypeS5S8SGWGTPU, s.s5uIP).WithInstance(2)}
+			for _, child := range i.ChildIEs {
+				if child.Type != ies.FullyQualifiedTEID {
+					children = append(children, child)
+				}
+			}
+			i = ies.NewBearerContext(children...).WithInstance(i.Instance())
+		}
+		ie = append(ie, i)
+	}
+
+	s5Sess, seq, err := exchange.CreateSession(s.s5, pgwAddr, ie...)
+	if err != nil {
+		return nil, nil, err
+	}
+	rsp, err := s.completeS5Session(ctx, s5Sess, pgwAddr, seq)
+	if err != nil {
+		s.s5.RemoveSession(s5Sess)
+		return nil, nil, err
+	}
+	return s5Sess, rsp, nil
+}
+
+func (s *SGW) completeS5Session(ctx context.Context, s5Sess *v2.Session, pgwAddr net.Addr, seq uint32) (*messages.CreateSessionResponse, error) {
+	msg, err := s.s5Calls.Wait(ctx, pgwAddr, seq)
+	if err != nil {
+		return nil, err
+	}
+	rsp, ok := msg.(*messages.CreateSessionResponse)
+	if !ok {
+		return nil, &v2.UnexpectedTypeError{Msg: msg}
+	}
+	if err := v2.CheckCause(rsp); err != nil {
+		return nil, err
+	}
+
+	if ie := rsp.SenderFTEIDC; ie != nil {
+		if err := exchange.AddFTEID(s5Sess, ie); err != nil {
+			return nil, err
+		}
+	}
+	if ie := rsp.PAA; ie != nil {
+		ip, err := ie.IPAddress()
+		if err != nil {
+			return nil, err
+		}
+		s5Sess.GetDefaultBearer().SetSubscriberIP(ip)
+	}
+	for _, bc := range messages.FindIEs(rsp, ies.BearerContext) {
+		for _, child := range bc.ChildIEs {
+			if child.Type != ies.FullyQualifiedTEID {
+				continue
+			}
+			if err := exchange.AddFTEID(s5Sess, child); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := s5Sess.Activate(); err != nil {
+		return nil, err
+	}
+	return rsp, nil
+}
+
+func (s *SGW) handleModifyBearerRequest(c *v2.Conn, senderAddr net.Addr, msg *messages.ModifyBearerRequest) error {
+	sess, err := c.GetSessionByTEID(msg.TEID(), senderAddr)
+	if err != nil {
+		return c.RespondTo(senderAddr, msg, messages.NewModifyBearerResponse(
+			0, 0, ies.NewCause(v2.CauseContextNotFound, 0, 0, 0, nil),
+		))
+	}
+	mmeTEID, err := sess.GetTEID(v2.IFTypeS11MMEGTPC)
+	if err != nil {
+		return err
+	}
+
+	bcs := messages.FindIEs(msg, ies.BearerContext)
+	if err := sess.ModifyAccessBearers(bcs...); err != nil {
+		return c.RespondTo(senderAddr, msg, messages.NewModifyBearerResponse(
+			mmeTEID, 0, ies.NewCause(v2.CauseContextNotFound, 0, 0, 0, nil),
+		))
+	}
+
+	rspIEs := []*ies.IE{ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil)}
+	for _, bc := range bcs {
+		for _, child := range bc.ChildIEs {
+			if child.Type == ies.EPSBearerID {
+				rspIEs = append(rspIEs, ies.NewBearerContext(
+					ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil), child,
+				))
+			}
+		}
+	}
+	return c.RespondTo(senderAddr, msg, messages.NewModifyBearerResponse(mmeTEID, 0, rspIEs...))
+}
+
+func (s *SGW) handleDeleteSessionRequest(c *v2.Conn, senderAddr net.Addr, msg *messages.DeleteSessionRequest) error {
+	s11Sess, err := c.GetSessionByTEID(msg.TEID(), senderAddr)
+	if err != nil {
+		return c.RespondTo(senderAddr, msg, messages.NewDeleteSessionResponse(
+			0, 0, ies.NewCause(v2.CauseContextNotFound, 0, 0, 0, nil),
+		))
+	}
+	mmeTEID, err := s11Sess.GetTEID(v2.IFTypeS11MMEGTPC)
+	if err != nil {
+		return err
+	}
+	s5Sess, ok := s.LinkedSession(s11Sess)
+	if !ok {
+		s.remove(s11Sess)
+		return c.RespondTo(senderAddr, msg, messages.NewDeleteSessionResponse(
+			mmeTEID, 0, ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil),
+		))
+	}
+	pgwTEID, err := s5Sess.GetTEID(v2.IFTypeS5S8PGWGTPC)
+	if err != nil {
+		return err
+	}
+
+	return s.relay(c, senderAddr, msg, func(ctx context.Context) messages.Message {
+		var ie []*ies.IE
+		if msg.LinkedEBI != nil {
+			ie = append(ie, msg.LinkedEBI)
+		}
+		rsp, err := s.s5Calls.Call(ctx, s.s5, s5Sess.PeerAddr(), messages.NewDeleteSessionRequest(pgwTEID, 0, ie...))
+		if err != nil {
+			return messages.NewDeleteSessionResponse(mmeTEID, 0, causeOf(err))
+		}
+		if err := v2.CheckCause(rsp); err != nil {
+			return messages.NewDeleteSessionResponse(mmeTEID, 0, causeOf(err))
+		}
+
+		s.remove(s11Sess)
+		return messages.NewDeleteSessionResponse(mmeTEID, 0, ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil))
+	})
+}
+
+func (s *SGW) handleCreateBearerRequest(c *v2.Conn, senderAddr net.Addr, msg *messages.CreateBearerRequest) error {
+	s5Sess, err := c.GetSessionByTEID(msg.TEID(), senderAddr)
+	if err != nil {
+		return c.RespondTo(senderAddr, msg, messages.NewCreateBearerResponse(
+			0, 0, ies.NewCause(v2.CauseContextNotFound, 0, 0, 0, nil),
+		))
+	}
+	pgwTEID, err := s5Sess.GetTEID(v2.IFTypeS5S8PGWGTPC)
+	if err != nil {
+		return err
+	}
+	s11Sess, ok := s.LinkedSession(s5Sess)
+	if !ok {
+		return c.RespondTo(senderAddr, msg, messages.NewCreateBearerResponse(
+			pgwTEID, 0, ies.NewCause(v2.CauseContextNotFound, 0, 0, 0, nil),
+		))
+	}
+	mmeTEID, err := s11Sess.GetTEID(v2.IFTypeS11MMEGTPC)
+	if err != nil {
+		return err
+	}
+
+	return s.relay(c, senderAddr, msg, func(ctx context.Context) messages.Message {
+		var (
+			reqIEs  []*ies.IE
+			s5uSGW  []*ies.IE
+			s5uPGW  []*ies.IE
+			s11BCs  []*ies.IE
+			rspBCs  []*ies.IE
+			reqBCs  = messages.FindIEs(msg, ies.BearerContext)
+			linked  = msg.LinkedEBI
+			s5Cause = ies.NewCause(v2.CauseRequestAccepted, 0, 0, 0, nil)
+		)
+		if linked != nil {
+			reqIEs = append(reqIEs, linked)
+		}
+		for _, bc := range reqBCs {
+			// the S5/S8-U P-GW F-TEID with instance 1 is replaced with the S1-U S-GW
+			// F-TEID with instance 0 on S11.
+			children := []*ies.IE{exchange.NewFTEID(s.s11, v2.IFTypeS1USGWGTPU, s.s1uIP)}
+			var pgwFTEID *ies.IE
+			for _, child := range bc.ChildIEs {
+				if child.Type == ies.FullyQualifiedTEID {
+					if child.Instance() == 1 {
+						pgwFTEID = ies.New(child.Type, 3, child.Payload)
+					}
+					continue
+				}
+				children = append(children, child)
+			}
+			s11BC := ies.NewBearerContext(children...)
+			s11BCs = append(s11BCs, s11BC)
+			reqIEs = append(reqIEs, s11BC)
+			s5uSGW = append(s5uSGW, exchange.NewFTEID(s.s5, v2.IFTypeS5S8SGWGTPU, s.s5uIP).WithInstance(2))
+			s5uPGW = append(s5uPGW, pgwFTEID)
+		}
+
+		// the Bearers accepted are added to the Session on S11 when the response is
+		// received.
+		rsp, err := s.s11Calls.Call(ctx, s.s11, s11Sess.PeerAddr(), messages.NewCreateBearerRequest(mmeTEID, 0, reqIEs...))
+		if err != nil {
+			return messages.NewCreateBearerResponse(pgwTEID, 0, causeOf(err))
+		}
+		if err := v2.CheckCause(rsp); err != nil {
+			s5Cause = causeOf(err)
+		}
+		for i, bc := range messages.FindIEs(rsp, ies.BearerContext) {
+			children := []*ies.IE{}
+			for _, child := range bc.ChildIEs {
+				if child.Type != ies.FullyQualifiedTEID {
+					children = append(children, child)
+				}
+			}
+			if i < len(reqBCs) {
+				children = append(children, s5uSGW[i])
+				if s5uPGW[i] != nil {
+					children = append(children, s5uPGW[i])
+				}
+			}
+			rspBC := ies.NewBearerContext(children...)
+			rspBCs = append(rspBCs, rspBC)
+
+			// the Bearer on S5/S8 is created with the Bearer QoS in the request and
+			// the EBI in the response.
+			if i < len(reqBCs) {
+				merged := append(append([]*ies.IE{}, reqBCs[i].ChildIEs...), children...)
+				if _, err := s5Sess.AddDedicatedBearers(ies.NewBearerContext(merged...)); err != nil {
+					return messages.NewCreateBearerResponse(pgwTEID, 0, causeOf(err))
+				}
+			}
+		}
+		return messages.NewCreateBearerResponse(pgwTEID, 0, append([]*ies.IE{s5Cause}, rspBCs...)...)
+	})
+}
+
+func (s *SGW) handleDeleteBearerRequest(c *v2.Conn, senderAddr net.Addr, msg *messages.DeleteBearerRequest) error {
+	s5Sess, err := c.GetSessionByTEID(msg.TEID(), senderAddr)
+	if err != nil {
+		return c.RespondTo(senderAddr, msg, messages.NewDeleteBearerResponse(
+			0, 0, ies.NewCause(v2.CauseContextNotFound, 0, 0, 0, nil),
+		))
+	}
+	pgwTEID, err := s5Sess.GetTEID(v2.IFTypeS5S8PGWGTPC)
+	if err != nil {
+		return err
+	}
+	s11Sess, ok := s.LinkedSession(s5Sess)
+	if !ok {
+		return c.RespondTo(senderAddr, msg, messages.NewDeleteBearerResponse(
+			pgwTEID, 0, ies.NewCause(v2.CauseContextNotFound, 0, 0, 0, nil),
+		))
+	}
+	mmeTEID, err := s11Sess.GetTEID(v2.IFTypeS11MMEGTPC)
+	if err != nil {
+		return err
+	}
+
+	return s.relay(c, senderAddr, msg, func(ctx context.Context) messages.Message {
+		// the Bearers deleted are removed from the Session on S11 when the response
+		// is received.
+		req := messages.NewDeleteBearerRequest(mmeTEID, 0, messages.IEs(msg)...)
+		rsp, err := s.s11Calls.Call(ctx, s.s11, s11Sess.PeerAddr(), req)
+		if err != nil {
+			return messages.NewDeleteBearerResponse(pgwTEID, 0, causeOf(err))
+		}
+		if err := v2.CheckCause(rsp); err != nil {
+			return messages.NewDeleteBearerResponse(pgwTEID, 0, causeOf(err))
+		}
+
+		if msg.LinkedEBI != nil {
+			s.remove(s11Sess)
+		} else {
+			for _, ie := range messages.FindIEs(msg, ies.EPSBearerID) {
+				if ie.Instance() == 1 {
+					s5Sess.RemoveBearerByEBI(ie.MustEPSBearerID())
+				}
+			}
+		}
+		return messages.NewDeleteBearerResponse(pgwTEID, 0, messages.IEs(rsp)...)
+	})
+}
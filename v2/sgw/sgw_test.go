@@ -0,0 +1,131 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package sgw_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	v2 "github.com/wmnsk/go-gtp/v2"
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/mme"
+	"github.com/wmnsk/go-gtp/v2/pgw"
+	"github.com/wmnsk/go-gtp/v2/sgw"
+)
+
+func TestCallFlows(t *testing.T) {
+	errCh := make(chan error, 16)
+	listen := func(addr string) *v2.Conn {
+		t.Helper()
+		laddr, err := net.ResolveUDPAddr("udp", addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		conn, err := v2.ListenAndServe(laddr, 0, errCh)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return conn
+	}
+
+	var (
+		mmeConn = listen("127.0.1.1:2123")
+		s11Conn = listen("127.0.1.2:2123")
+		s5Conn  = listen("127.0.1.3:2123")
+		pgwConn = listen("127.0.1.4:2123")
+	)
+	defer func() {
+		mmeConn.Close()
+		s11Conn.Close()
+		s5Conn.Close()
+		pgwConn.Close()
+	}()
+
+	p := pgw.New(pgwConn, "127.0.1.4", &pgw.Policy{
+		AllocateIP: func(sess *v2.Session) (string, error) {
+			return "10.0.0.1", nil
+		},
+	})
+	sgw.New(s11Conn, s5Conn, "127.0.1.2", "127.0.1.3", nil)
+	m := mme.New(mmeConn, s11Conn.LocalAddr(), &mme.Policy{
+		AccessFTEID: func(sess *v2.Session, ebi uint8) *ies.IE {
+			return ies.NewFullyQualifiedTEID(v2.IFTypeS1UeNodeBGTPU, uint32(ebi), "127.0.1.5", "")
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sess, err := m.Attach(
+		ctx,
+		ies.NewIMSI("123451234567890"),
+		mmeConn.NewFTEID(v2.IFTypeS11MMEGTPC, "127.0.1.1", ""),
+		ies.NewFullyQualifiedTEID(v2.IFTypeS5S8PGWGTPC, 0, "127.0.1.4", "").WithInstance(1),
+		ies.NewAccessPointName("some.apn.example"),
+		ies.NewRATType(v2.RATTypeEUTRAN),
+		ies.NewBearerContext(ies.NewEPSBearerID(5), ies.NewBearerQoS(1, 2, 1, 9, 0, 0, 0, 0)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sess.GetDefaultBearer().GetSubscriberIP(); got != "10.0.0.1" {
+		t.Errorf("unexpected subscriber IP: got %s, want 10.0.0.1", got)
+	}
+	if _, err := sess.GetTEID(v2.IFTypeS1USGWGTPU); err != nil {
+		t.Error(err)
+	}
+
+	enbFTEID := ies.NewFullyQualifiedTEID(v2.IFTypeS1UeNodeBGTPU, 0x11111111, "127.0.1.5", "")
+	if err := m.ModifyBearer(ctx, sess, 5, enbFTEID); err != nil {
+		t.Fatal(err)
+	}
+
+	pgwSess, err := pgwConn.GetSessionByIMSI("123451234567890")
+	if err != nil {
+		t.Fatal(err)
+	}
+	br, err := p.ActivateDedicatedBearer(ctx, pgwSess, &v2.QoSProfile{PL: 2, QCI: 1, GBRUL: 64, GBRDL: 64})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := br.GetEBI(); got != 6 {
+		t.Errorf("unexpected EBI: got %d, want 6", got)
+	}
+	if _, err := sess.LookupBearerByEBI(6); err != nil {
+		t.Errorf("dedicated Bearer is not added on MME: %v", err)
+	}
+
+	if err := p.DeactivateBearers(ctx, pgwSess, 6); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sess.LookupBearerByEBI(6); err == nil {
+		t.Error("dedicated Bearer is not removed on MME")
+	}
+	if _, err := pgwSess.LookupBearerByEBI(6); err == nil {
+		t.Error("dedicated Bearer is not removed on P-GW")
+	}
+
+	newENBFTEID := ies.NewFullyQualifiedTEID(v2.IFTypeS1UeNodeBGTPU, 0x22222222, "127.0.1.6", "")
+	if err := m.Handover(ctx, sess, 5, newENBFTEID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Detach(ctx, sess); err != nil {
+		t.Fatal(err)
+	}
+	for _, conn := range []*v2.Conn{mmeConn, s11Conn, s5Conn, pgwConn} {
+		if n := conn.SessionCount(); n != 0 {
+			t.Errorf("Sessions remaining on %s: %d", conn.LocalAddr(), n)
+		}
+	}
+
+	select {
+	case err := <-errCh:
+		t.Error(err)
+	default:
+	}
+}
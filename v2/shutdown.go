@@ -0,0 +1,191 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// Default values of ShutdownPolicy.
+//
+// TS29.274 7.6 Reliable Delivery of Signalling Messages; the request is retransmitted
+// N3-REQUESTS times at the interval of T3-RESPONSE if no response is received.
+const (
+	DefaultShutdownRetransmitInterval = 3 * time.Second
+	DefaultShutdownMaxRetransmissions = 2
+)
+
+// ShutdownPolicy is the way Shutdown drains the Sessions before closing Conn.
+type ShutdownPolicy struct {
+	// DeleteSessions enables sending Delete Session Request for each active Session
+	// in Conn. The Session is removed from Conn when the response is received.
+	DeleteSessions bool
+
+	// PeerIFType is the interface type of the peer's F-TEID in Session, whose TEID is
+	// used in the header of Delete Session Request, e.g., IFTypeS11S4SGWGTPC on MME,
+	// or IFTypeS5S8PGWGTPC on SGW. The Sessions without it are skipped.
+	PeerIFType uint8
+
+	// IEs returns the IEs to be contained in the Delete Session Request for sess. If
+	// nil, the EBI of the default Bearer is set as Linked EPS Bearer ID.
+	IEs func(sess *Session) []*ies.IE
+
+	// RetransmitInterval is T3-RESPONSE, the interval to retransmit the request that
+	// is not responded. DefaultShutdownRetransmitInterval is used if 0.
+	RetransmitInterval time.Duration
+
+	// MaxRetransmissions is N3-REQUESTS, the number of retransmissions before giving
+	// up the request. DefaultShutdownMaxRetransmissions is used if 0; give negative
+	// value to disable retransmission.
+	MaxRetransmissions int
+}
+
+// SetShutdownPolicy sets the ShutdownPolicy used by Shutdown. Giving nil is the same
+// as the default, which closes Conn without sending any request.
+func (c *Conn) SetShutdownPolicy(p *ShutdownPolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.shutdownPolicy = p
+}
+
+func (c *Conn) isShuttingDown() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.shuttingDown
+}
+
+// Shutdown closes Conn gracefully for the maintenance restarts.
+//
+// It stops accepting new Sessions by rejecting the incoming Create Session Requests
+// with Cause "No resources available", and sends Delete Session Request for each
+// active Session if ShutdownPolicy set with SetShutdownPolicy says so. Then it waits
+// for the responses with the retransmissions until all of them are responded or given
+// up, or ctx is done, and closes Conn.
+//
+// Conn is closed even if ctx is done before completing, in which case ctx.Err() is
+// returned. Shutdown must not be called together with Close.
+func (c *Conn) Shutdown(ctx context.Context) error {
+	c.mu.Lock()
+	c.shuttingDown = true
+	policy := c.shutdownPolicy
+	c.mu.Unlock()
+
+	var err error
+	if policy != nil && policy.DeleteSessions {
+		err = c.deleteSessions(ctx, policy)
+	}
+
+	if cerr := c.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// pendingDeletion is the Delete Session Request sent by Shutdown, waiting for the
+// response.
+type pendingDeletion struct {
+	sess    *Session
+	raddr   net.Addr
+	seq     uint32
+	raw     []byte
+	sentAt  time.Time
+	retries int
+}
+
+// deleteSessions sends Delete Session Request for each active Session and waits for
+// the responses.
+func (c *Conn) deleteSessions(ctx context.Context, policy *ShutdownPolicy) error {
+	interval := policy.RetransmitInterval
+	if interval <= 0 {
+		interval = DefaultShutdownRetransmitInterval
+	}
+	maxRetries := policy.MaxRetransmissions
+	if maxRetries == 0 {
+		maxRetries = DefaultShutdownMaxRetransmissions
+	}
+
+	var sessions []*Session
+	c.RangeSessions(func(sess *Session) bool {
+		sessions = append(sessions, sess)
+		return true
+	})
+
+	var pending []*pendingDeletion
+	for _, sess := range sessions {
+		if !sess.IsActive() {
+			continue
+		}
+		teid, err := sess.GetTEID(policy.PeerIFType)
+		if err != nil {
+			continue
+		}
+
+		var ie []*ies.IE
+		if policy.IEs != nil {
+			ie = policy.IEs(sess)
+		} else if br := sess.GetDefaultBearer(); br != nil && br.GetEBI() != 0 {
+			ie = []*ies.IE{ies.NewEPSBearerID(br.GetEBI())}
+		}
+
+		seq, raw, err := c.sendMessageTo(messages.NewDeleteSessionRequest(teid, 0, ie...), sess.PeerAddr(), true)
+		if err != nil {
+			c.notifyError(err)
+			continue
+		}
+		pending = append(pending, &pendingDeletion{
+			sess: sess, raddr: sess.PeerAddr(), seq: seq, raw: raw, sentAt: time.Now(),
+		})
+	}
+
+	// the responses are detected as the requests are no longer outstanding, which
+	// is checked more often than the retransmissions.
+	tick := interval / 10
+	if tick > 100*time.Millisecond {
+		tick = 100 * time.Millisecond
+	}
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	for len(pending) > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		now := time.Now()
+		var next []*pendingDeletion
+		for _, p := range pending {
+			if !c.peer(p.raddr).IsOutstanding(p.seq) {
+				c.RemoveSession(p.sess)
+				continue
+			}
+			if now.Sub(p.sentAt) < interval {
+				next = append(next, p)
+				continue
+			}
+			if p.retries >= maxRetries {
+				c.log().Warn("no response to Delete Session Request", "peer", p.raddr.String(), "seq", p.seq)
+				continue
+			}
+
+			if _, err := c.writeTo(p.raw, p.raddr, nil, nil); err != nil {
+				c.notifyError(err)
+			}
+			p.retries++
+			p.sentAt = now
+			next = append(next, p)
+		}
+		pending = next
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+	"strings"
+)
+
+// SourcePortPolicy is a policy to handle the messages that come from the UDP port
+// different from the one known for the peer.
+//
+// Some implementations send responses from ephemeral ports instead of the port that
+// the request is sent to, which makes it impossible to find the Session by TEID and
+// the address of the sender.
+type SourcePortPolicy int
+
+// SourcePortPolicy definitions.
+const (
+	// SourcePortPolicyNone does not check the source port at all. This is the default.
+	SourcePortPolicyNone SourcePortPolicy = iota
+	// SourcePortPolicyStrict drops the messages from unexpected source ports.
+	SourcePortPolicyStrict
+	// SourcePortPolicyLenient accepts the messages from unexpected source ports and
+	// learns the new port as the one of the peer, including the Sessions with it.
+	SourcePortPolicyLenient
+	// SourcePortPolicyCallback lets the SourcePortFunc decide which of
+	// SourcePortPolicyStrict or SourcePortPolicyLenient is applied.
+	SourcePortPolicyCallback
+)
+
+// SourcePortFunc decides how to handle the message from the unexpected source port.
+//
+// known is the address known for the peer, and got is the actual source address of
+// the message. The returned value should be SourcePortPolicyStrict or
+// SourcePortPolicyLenient; the others are treated as SourcePortPolicyStrict.
+type SourcePortFunc func(known, got net.Addr) SourcePortPolicy
+
+type sourcePortRule struct {
+	policy SourcePortPolicy
+	fn     SourcePortFunc
+}
+
+// SetSourcePortPolicy sets the SourcePortPolicy to be applied to all the peers.
+//
+// fn is used only when policy is SourcePortPolicyCallback, and can be nil otherwise.
+// The policy for specific peer can be set with SetPeerSourcePortPolicy.
+func (c *Conn) SetSourcePortPolicy(policy SourcePortPolicy, fn SourcePortFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.srcPortRule = &sourcePortRule{policy: policy, fn: fn}
+}
+
+// SetPeerSourcePortPolicy sets the SourcePortPolicy to be applied to the peer
+// specified by IP address, which overrides the one set by SetSourcePortPolicy.
+//
+// fn is used only when policy is SourcePortPolicyCallback, and can be nil otherwise.
+func (c *Conn) SetPeerSourcePortPolicy(peerIP net.IP, policy SourcePortPolicy, fn SourcePortFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.peerSrcPortRules == nil {
+		c.peerSrcPortRules = map[string]*sourcePortRule{}
+	}
+	c.peerSrcPortRules[peerIP.String()] = &sourcePortRule{policy: policy, fn: fn}
+}
+
+func (c *Conn) sourcePortRuleFor(ip string) *sourcePortRule {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if r, ok := c.peerSrcPortRules[ip]; ok {
+		return r
+	}
+	// the rules are set by IP address without zone.
+	if i := strings.IndexByte(ip, '%'); i >= 0 {
+		if r, ok := c.peerSrcPortRules[ip[:i]]; ok {
+			return r
+		}
+	}
+	return c.srcPortRule
+}
+
+// learnPeerAddr stores addr as the address of the peer with the IP address of addr.
+func (c *Conn) learnPeerAddr(addr net.Addr) {
+	ip, _ := splitAddr(addr)
+	if ip == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.peerAddrs == nil {
+		c.peerAddrs = map[string]net.Addr{}
+	}
+	if _, ok := c.peerAddrs[ip]; !ok && len(c.peerAddrs) >= maxPeers {
+		c.evictPeerAddrs()
+	}
+	c.peerAddrs[ip] = addr
+}
+
+// evictPeerAddrs removes the addresses learned for the peers that have no Peer in
+// Conn, and then the others at random until three quarters of maxPeers are left.
+// c.mu should be held by the caller.
+func (c *Conn) evictPeerAddrs() {
+	for ip, addr := range c.peerAddrs {
+		if _, ok := c.peers[addrString(addr)]; !ok {
+			delete(c.peerAddrs, ip)
+		}
+	}
+
+	n := len(c.peerAddrs) - maxPeers*3/4
+	for ip := range c.peerAddrs {
+		if n <= 0 {
+			break
+		}
+		delete(c.peerAddrs, ip)
+		n--
+	}
+}
+
+func (c *Conn) knownPeerAddr(ip string) (net.Addr, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	addr, ok := c.peerAddrs[ip]
+	return addr, ok
+}
+
+// validateSourcePort checks the source port of the message from senderAddr with the
+// SourcePortPolicy configured.
+func (c *Conn) validateSourcePort(senderAddr net.Addr) error {
+	ip, port := splitAddr(senderAddr)
+	if ip == "" {
+		return nil
+	}
+
+	rule := c.sourcePortRuleFor(ip)
+	if rule == nil || rule.policy == SourcePortPolicyNone {
+		return nil
+	}
+
+	known, ok := c.knownPeerAddr(ip)
+	if !ok {
+		// first contact from the peer.
+		c.learnPeerAddr(senderAddr)
+		return nil
+	}
+	if _, knownPort := splitAddr(known); knownPort == port {
+		return nil
+	}
+
+	policy := rule.policy
+	if policy == SourcePortPolicyCallback {
+		policy = SourcePortPolicyStrict
+		if rule.fn != nil {
+			policy = rule.fn(known, senderAddr)
+		}
+	}
+
+	switch policy {
+	case SourcePortPolicyLenient:
+		c.learnPeerAddr(senderAddr)
+		c.updateSessionPeerAddr(known, senderAddr)
+		return nil
+	default:
+		return &UnexpectedSourcePortError{Known: known, Got: senderAddr}
+	}
+}
+
+// updateSessionPeerAddr replaces the peer address of the Sessions with oldAddr by newAddr.
+func (c *Conn) updateSessionPeerAddr(oldAddr, newAddr net.Addr) {
+	for _, sess := range c.GetSessionsByPeer(oldAddr) {
+		sess.UpdatePeerAddr(newAddr)
+	}
+}
+
+// splitAddr returns IP and port of addr in string. It returns empty strings if
+// addr cannot be split into IP and port. The IP contains the zone if any, in the
+// same form as addrString.
+func splitAddr(addr net.Addr) (ip, port string) {
+	if addr == nil {
+		return "", ""
+	}
+	host, port, err := net.SplitHostPort(addrString(addr))
+	if err != nil {
+		return "", ""
+	}
+	return host, port
+}
@@ -0,0 +1,165 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// DefaultSubscriptionSize is the number of IncomingMessages that can be buffered in
+// the channel returned by Subscribe.
+const DefaultSubscriptionSize = 64
+
+// IncomingMessage is the message received by Conn, delivered to the channel returned
+// by Subscribe.
+type IncomingMessage struct {
+	Message    messages.Message
+	SenderAddr net.Addr
+	// LocalAddr is the local address that the message arrived on, which matters if
+	// Conn has multiple local addresses added with AddLocalAddr.
+	LocalAddr net.Addr
+	ArrivedAt time.Time
+}
+
+// Subscribe returns the channel that the received messages with msgTypes are delivered
+// to, in addition to the HandlerFuncs. All the messages are delivered if no msgTypes
+// are given.
+//
+// The messages are delivered after they are validated, and thus the retransmitted
+// requests and the ones rejected by the validation are not. The messages are also
+// delivered if no HandlerFunc is registered for them, in which case the error is not
+// notified. The channel is buffered with DefaultSubscriptionSize, and the messages
+// that come when it is full are discarded and counted as SubscriptionDropped in
+// Stats, not to block handling the other messages.
+//
+// The channel is closed when Unsubscribe is called with it or Conn is closed.
+func (c *Conn) Subscribe(msgTypes ...uint8) <-chan IncomingMessage {
+	sub := &subscription{ch: make(chan IncomingMessage, DefaultSubscriptionSize)}
+	if len(msgTypes) > 0 {
+		sub.msgTypes = map[uint8]struct{}{}
+		for _, t := range msgTypes {
+			sub.msgTypes[t] = struct{}{}
+		}
+	}
+
+	c.subs.add(sub)
+	return sub.ch
+}
+
+// Unsubscribe stops delivering the messages to ch returned by Subscribe, and closes
+// it. It does nothing if ch is already unsubscribed.
+func (c *Conn) Unsubscribe(ch <-chan IncomingMessage) {
+	c.subs.remove(ch)
+}
+
+type subscription struct {
+	ch       chan IncomingMessage
+	msgTypes map[uint8]struct{}
+}
+
+func (s *subscription) wants(msgType uint8) bool {
+	if s.msgTypes == nil {
+		return true
+	}
+	_, ok := s.msgTypes[msgType]
+	return ok
+}
+
+// subscriptions is the set of subscriptions on Conn. The zero value is ready to use.
+type subscriptions struct {
+	mu     sync.Mutex
+	subs   []*subscription
+	closed bool
+}
+
+func (s *subscriptions) add(sub *subscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		close(sub.ch)
+		return
+	}
+	s.subs = append(s.subs, sub)
+}
+
+func (s *subscriptions) remove(ch <-chan IncomingMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for n, sub := range s.subs {
+		if (<-chan IncomingMessage)(sub.ch) != ch {
+			continue
+		}
+		close(sub.ch)
+		s.subs = append(s.subs[:n], s.subs[n+1:]...)
+		return
+	}
+}
+
+// publish delivers in to the subscriptions that want it, and reports whether there is
+// any of them and the number of the ones whose channel is full.
+func (s *subscriptions) publish(in IncomingMessage) (subscribed bool, dropped int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, sub := range s.subs {
+		if !sub.wants(in.Message.MessageType()) {
+			continue
+		}
+		subscribed = true
+		select {
+		case sub.ch <- in:
+		default:
+			dropped++
+		}
+	}
+	return subscribed, dropped
+}
+
+func (s *subscriptions) empty() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.subs) == 0
+}
+
+func (s *subscriptions) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, sub := range s.subs {
+		close(sub.ch)
+	}
+	s.subs = nil
+	s.closed = true
+}
+
+// publish delivers msg to the subscriptions, and reports whether there is any of them
+// that wants msg.
+func (c *Conn) publish(senderAddr net.Addr, msg messages.Message) bool {
+	if c.subs.empty() {
+		return false
+	}
+
+	arrivedAt, ok := c.arrivalTime(msg)
+	if !ok {
+		arrivedAt = time.Now()
+	}
+	subscribed, dropped := c.subs.publish(IncomingMessage{
+		Message:    msg,
+		SenderAddr: senderAddr,
+		LocalAddr:  c.ReceivedOn(msg),
+		ArrivedAt:  arrivedAt,
+	})
+	for n := 0; n < dropped; n++ {
+		c.stats.subscriptionDrop()
+	}
+	return subscribed
+}
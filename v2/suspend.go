@@ -0,0 +1,225 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// Suspend marks the Session and all the Bearers in it suspended.
+//
+// This is typically used when the UE moves to CS domain by CS Fallback or SRVCC and
+// the packet services are suspended. Only the bookkeeping of the Session is done, and
+// the messages for SRVCC on Sv interface are not implemented in this package. It
+// returns ErrSessionSuspended if the Session is already suspended.
+func (s *Session) Suspend() error {
+	return s.setSuspended(true)
+}
+
+// Resume clears the suspended state of the Session and all the Bearers in it.
+//
+// It returns ErrSessionNotSuspended if the Session is not suspended.
+func (s *Session) Resume() error {
+	return s.setSuspended(false)
+}
+
+// IsSuspended reports whether the Session is suspended or not.
+func (s *Session) IsSuspended() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.isSuspended
+}
+
+func (s *Session) setSuspended(suspended bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.isSuspended == suspended {
+		if suspended {
+			return ErrSessionSuspended
+		}
+		return ErrSessionNotSuspended
+	}
+
+	s.isSuspended = suspended
+	s.bearerMap.rangeWithFunc(func(k, v interface{}) bool {
+		v.(*Bearer).setSuspended(suspended)
+		return true
+	})
+	return nil
+}
+
+// SuspendNotification sends a SuspendNotification with TEID and IEs given, and
+// marks the Session suspended.
+//
+// It returns ErrSessionSuspended without sending anything if the Session is already
+// suspended. If the peer rejects the notification, call (*Session) Resume to revert
+// the state.
+func (c *Conn) SuspendNotification(teid uint32, raddr net.Addr, ie ...*ies.IE) (uint32, error) {
+	sess, err := c.GetSessionByTEID(teid, raddr)
+	if err != nil {
+		return 0, err
+	}
+	if sess.IsSuspended() {
+		return 0, ErrSessionSuspended
+	}
+
+	msg := messages.NewSuspendNotification(teid, 0, ie...)
+
+	seq, err := c.SendMessageTo(msg, sess.PeerAddr())
+	if err != nil {
+		return 0, err
+	}
+	return seq, sess.Suspend()
+}
+
+// ResumeNotification sends a ResumeNotification with TEID and IEs given, and
+// resumes the Session.
+//
+// It returns ErrSessionNotSuspended without sending anything if the Session is not
+// suspended.
+func (c *Conn) ResumeNotification(teid uint32, raddr net.Addr, ie ...*ies.IE) (uint32, error) {
+	sess, err := c.GetSessionByTEID(teid, raddr)
+	if err != nil {
+		return 0, err
+	}
+	if !sess.IsSuspended() {
+		return 0, ErrSessionNotSuspended
+	}
+
+	msg := messages.NewResumeNotification(teid, 0, ie...)
+
+	seq, err := c.SendMessageTo(msg, sess.PeerAddr())
+	if err != nil {
+		return 0, err
+	}
+	return seq, sess.Resume()
+}
+
+// SuspendAcknowledge sends a SuspendAcknowledge with TEID and IEs given in response
+// to the SuspendNotification, and marks the Session suspended if the Cause given is
+// an acceptance.
+//
+// The Session is looked up by the TEID in req and raddr. The response is sent even
+// if the Session is already suspended.
+func (c *Conn) SuspendAcknowledge(teid uint32, raddr net.Addr, req messages.Message, ie ...*ies.IE) error {
+	return c.acknowledgeSuspendResume(teid, raddr, req, true, ie...)
+}
+
+// ResumeAcknowledge sends a ResumeAcknowledge with TEID and IEs given in response
+// to the ResumeNotification, and resumes the Session if the Cause given is an
+// acceptance.
+//
+// The Session is looked up by the TEID in req and raddr. The response is sent even
+// if the Session is not suspended.
+func (c *Conn) ResumeAcknowledge(teid uint32, raddr net.Addr, req messages.Message, ie ...*ies.IE) error {
+	return c.acknowledgeSuspendResume(teid, raddr, req, false, ie...)
+}
+
+func (c *Conn) acknowledgeSuspendResume(teid uint32, raddr net.Addr, req messages.Message, suspend bool, ie ...*ies.IE) error {
+	sess, err := c.GetSessionByTEID(req.TEID(), raddr)
+	if err != nil {
+		return err
+	}
+
+	if err := c.RespondTo(raddr, req, newSuspendResumeAcknowledge(teid, suspend, ie...)); err != nil {
+		return err
+	}
+
+	if !isAccepted(ie...) {
+		return nil
+	}
+	// the error is ignored as it only means the state is already updated.
+	_ = sess.setSuspended(suspend)
+	return nil
+}
+
+// HandleSuspendResume registers the HandlerFuncs that acknowledge the Suspend
+// Notification and Resume Notification, and update the state of the Session.
+//
+// The Session is looked up by the TEID and sender of the notification, or by the IMSI
+// in it if the TEID is 0. The TEID in the acknowledgement is the one in the Sender
+// F-TEID for Control Plane in the notification if present, or the one registered in
+// the Session with ifType otherwise, e.g., IFTypeS11MMEGTPC for SGW. If the Session
+// cannot be found, the notification is rejected with Context Not Found.
+//
+// onChange is called after the acknowledgement is sent with the Session and whether
+// it is suspended, which can be nil. The HandlerFuncs registered here can be
+// overwritten by AddHandler or the type-specific Handle methods as usual.
+func (c *Conn) HandleSuspendResume(ifType uint8, onChange func(sess *Session, suspended bool)) {
+	c.HandleSuspendNotification(func(c *Conn, senderAddr net.Addr, msg *messages.SuspendNotification) error {
+		return c.handleSuspendResume(senderAddr, msg, msg.IMSI, msg.SenderFTEIDC, true, ifType, onChange)
+	})
+	c.HandleResumeNotification(func(c *Conn, senderAddr net.Addr, msg *messages.ResumeNotification) error {
+		return c.handleSuspendResume(senderAddr, msg, msg.IMSI, msg.SenderFTEIDC, false, ifType, onChange)
+	})
+}
+
+func (c *Conn) handleSuspendResume(
+	senderAddr net.Addr, msg messages.Message, imsiIE, fteidIE *ies.IE,
+	suspend bool, ifType uint8, onChange func(sess *Session, suspended bool),
+) error {
+	var sess *Session
+	var err error
+	if teid := msg.TEID(); teid != 0 || imsiIE == nil {
+		sess, err = c.GetSessionByTEID(teid, senderAddr)
+	} else {
+		sess, err = c.GetSessionByIMSI(imsiIE.MustIMSI())
+	}
+
+	var teid uint32
+	if fteidIE != nil {
+		teid = fteidIE.MustTEID()
+	} else if sess != nil {
+		teid, _ = sess.GetTEID(ifType)
+	}
+
+	if err != nil {
+		cause := ies.NewCause(CauseContextNotFound, 0, 0, 0, nil)
+		if rerr := c.RespondTo(senderAddr, msg, newSuspendResumeAcknowledge(teid, suspend, cause)); rerr != nil {
+			return rerr
+		}
+		return err
+	}
+
+	cause := ies.NewCause(CauseRequestAccepted, 0, 0, 0, nil)
+	if err := c.RespondTo(senderAddr, msg, newSuspendResumeAcknowledge(teid, suspend, cause)); err != nil {
+		return err
+	}
+	// the error is ignored as it only means the state is already updated.
+	_ = sess.setSuspended(suspend)
+
+	if onChange != nil {
+		onChange(sess, suspend)
+	}
+	return nil
+}
+
+func newSuspendResumeAcknowledge(teid uint32, suspend bool, ie ...*ies.IE) messages.Message {
+	if suspend {
+		return messages.NewSuspendAcknowledge(teid, 0, ie...)
+	}
+	return messages.NewResumeAcknowledge(teid, 0, ie...)
+}
+
+// isAccepted reports whether the Cause in ie is an acceptance, or no Cause is given.
+func isAccepted(ie ...*ies.IE) bool {
+	for _, i := range ie {
+		if i == nil || i.Type != ies.Cause {
+			continue
+		}
+		cause, err := i.Cause()
+		if err != nil {
+			return false
+		}
+		// TS29.274 8.4: the values from 16 to 63 are for acceptance.
+		return cause >= CauseRequestAccepted && cause < CauseContextNotFound
+	}
+	return true
+}
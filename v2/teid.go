@@ -0,0 +1,196 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"sync"
+)
+
+// TEIDAllocator allocates the TEIDs for the F-TEIDs created with NewFTEID.
+//
+// The TEIDs are allocated per interface type, as they are unique within the same
+// type of interface. The implementations should be safe for concurrent use.
+type TEIDAllocator interface {
+	// Allocate returns a TEID not in use for the interface type given.
+	Allocate(ifType uint8) (uint32, error)
+	// Release makes the TEID available again for the interface type given.
+	// Releasing the TEID that is not allocated should do nothing.
+	Release(ifType uint8, teid uint32)
+}
+
+type teidKey struct {
+	ifType uint8
+	teid   uint32
+}
+
+// maxRandomAttempts is the number of random values tried before RandomTEIDAllocator
+// gives up finding the unused TEID.
+const maxRandomAttempts = 1 << 16
+
+// RandomTEIDAllocator is a TEIDAllocator that allocates the random TEIDs, keeping
+// track of the ones in use instead of scanning the Sessions.
+type RandomTEIDAllocator struct {
+	mu   sync.Mutex
+	used map[teidKey]struct{}
+}
+
+// NewRandomTEIDAllocator creates a new RandomTEIDAllocator.
+func NewRandomTEIDAllocator() *RandomTEIDAllocator {
+	return &RandomTEIDAllocator{used: map[teidKey]struct{}{}}
+}
+
+// Allocate returns a random TEID that is neither 0 nor in use for ifType.
+//
+// It returns ErrTEIDExhausted if no unused TEID is found after a number of attempts,
+// which happens only when most of the TEIDs are in use.
+func (a *RandomTEIDAllocator) Allocate(ifType uint8) (uint32, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	b := make([]byte, 4)
+	for i := 0; i < maxRandomAttempts; i++ {
+		if _, err := rand.Read(b); err != nil {
+			return 0, err
+		}
+
+		key := teidKey{ifType, binary.BigEndian.Uint32(b)}
+		if key.teid == 0 {
+			continue
+		}
+		if _, ok := a.used[key]; ok {
+			continue
+		}
+		a.used[key] = struct{}{}
+		return key.teid, nil
+	}
+	return 0, ErrTEIDExhausted
+}
+
+// Release makes the TEID available again for ifType.
+func (a *RandomTEIDAllocator) Release(ifType uint8, teid uint32) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	delete(a.used, teidKey{ifType, teid})
+}
+
+// RangeTEIDAllocator is a TEIDAllocator that allocates the TEIDs sequentially from
+// the range given, and reuses the released ones after reaching the end of the range.
+//
+// This is deterministic, and the TEID space can be partitioned by giving the distinct
+// ranges to the allocators, e.g., of the Conns in the different processes of a node.
+type RangeTEIDAllocator struct {
+	mu          sync.Mutex
+	first, last uint32
+	next        map[uint8]uint32
+	count       map[uint8]uint64
+	used        map[teidKey]struct{}
+}
+
+// NewRangeTEIDAllocator creates a new RangeTEIDAllocator that allocates the TEIDs
+// from first to last, both inclusive. 0 is never allocated even if it is in the range.
+func NewRangeTEIDAllocator(first, last uint32) *RangeTEIDAllocator {
+	if first == 0 {
+		first = 1
+	}
+	if last < first {
+		last = first
+	}
+	return &RangeTEIDAllocator{
+		first: first,
+		last:  last,
+		next:  map[uint8]uint32{},
+		count: map[uint8]uint64{},
+		used:  map[teidKey]struct{}{},
+	}
+}
+
+// NewSequentialTEIDAllocator creates a new RangeTEIDAllocator that allocates the
+// TEIDs sequentially from 1 over the whole range of TEID.
+func NewSequentialTEIDAllocator() *RangeTEIDAllocator {
+	return NewRangeTEIDAllocator(1, 0xffffffff)
+}
+
+// Allocate returns the next TEID not in use for ifType in the range.
+//
+// It returns ErrTEIDExhausted if all the TEIDs in the range are in use.
+func (a *RangeTEIDAllocator) Allocate(ifType uint8) (uint32, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.count[ifType] > uint64(a.last-a.first) {
+		return 0, ErrTEIDExhausted
+	}
+
+	teid, ok := a.next[ifType]
+	if !ok {
+		teid = a.first
+	}
+	for {
+		key := teidKey{ifType, teid}
+		if teid == a.last {
+			teid = a.first
+		} else {
+			teid++
+		}
+		if _, ok := a.used[key]; ok {
+			continue
+		}
+
+		a.used[key] = struct{}{}
+		a.count[ifType]++
+		a.next[ifType] = teid
+		return key.teid, nil
+	}
+}
+
+// Release makes the TEID available again for ifType.
+func (a *RangeTEIDAllocator) Release(ifType uint8, teid uint32) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	key := teidKey{ifType, teid}
+	if _, ok := a.used[key]; !ok {
+		return
+	}
+	delete(a.used, key)
+	a.count[ifType]--
+}
+
+// SetTEIDAllocator sets the TEIDAllocator used by NewFTEID and AllocateFTEID. The
+// TEIDs of the Sessions are released to it when the Sessions are removed from Conn.
+//
+// By default, or if nil is given, NewFTEID generates the random TEID that is not
+// used by any Session in Conn, which takes longer as the number of Sessions grows.
+func (c *Conn) SetTEIDAllocator(a TEIDAllocator) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.teidAllocator = a
+}
+
+func (c *Conn) getTEIDAllocator() TEIDAllocator {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.teidAllocator
+}
+
+// releaseTEIDs releases the TEIDs of the Sessions removed to the TEIDAllocator.
+func (c *Conn) releaseTEIDs(removed []*Session) {
+	a := c.getTEIDAllocator()
+	if a == nil {
+		return
+	}
+
+	for _, sess := range removed {
+		sess.teidMap.rangeWithFunc(func(ifType, teid interface{}) bool {
+			a.Release(ifType.(uint8), teid.(uint32))
+			return true
+		})
+	}
+}
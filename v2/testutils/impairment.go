@@ -0,0 +1,239 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package testutils
+
+import (
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+)
+
+// DefaultReorderTimeout is the default duration to hold the packet to be reordered
+// when no other packet is written after it.
+const DefaultReorderTimeout = 100 * time.Millisecond
+
+// Impairment is the set of impairments applied to the packets written to the
+// ImpairedPacketConn.
+//
+// The rates are the probabilities in the range of 0 to 1. The decisions are made with
+// the pseudo-random numbers generated from Seed, so the same sequence of the packets
+// is always impaired in the same way.
+type Impairment struct {
+	// Loss is the rate of the packets to be dropped.
+	Loss float64
+	// Duplicate is the rate of the packets to be sent twice.
+	Duplicate float64
+	// Reorder is the rate of the packets to be held and sent after the next one.
+	Reorder float64
+	// ReorderTimeout is the duration to hold the packet to be reordered if no other
+	// packet is written. DefaultReorderTimeout is used if it is zero.
+	ReorderTimeout time.Duration
+
+	// Latency is added to all the packets, and the random value up to Jitter is added
+	// on top of it.
+	Latency time.Duration
+	Jitter  time.Duration
+
+	Seed int64
+}
+
+// ImpairmentStats is the number of the packets impaired by the ImpairedPacketConn.
+type ImpairmentStats struct {
+	Written    uint64
+	Dropped    uint64
+	Duplicated uint64
+	Reordered  uint64
+}
+
+// DropFunc reports whether the packet p written to addr should be dropped.
+type DropFunc func(p []byte, addr net.Addr) bool
+
+// ImpairedPacketConn is a net.PacketConn that injects the loss, duplication,
+// reordering and latency into the packets written to it.
+//
+// It is to validate the behavior of Conn on the unreliable transport, by giving it
+// to v2.NewConn or v2.Serve. Only the packets written are impaired, so wrap both
+// sides to impair both directions.
+type ImpairedPacketConn struct {
+	net.PacketConn
+
+	mu      sync.Mutex
+	imp     Impairment
+	rand    *rand.Rand
+	dropFn  DropFunc
+	held    *heldPacket
+	stats   ImpairmentStats
+	closed  bool
+	pending sync.WaitGroup
+}
+
+type heldPacket struct {
+	p     []byte
+	addr  net.Addr
+	timer *time.Timer
+}
+
+// NewImpairedPacketConn wraps pktConn with the impairment given.
+func NewImpairedPacketConn(pktConn net.PacketConn, imp Impairment) *ImpairedPacketConn {
+	return &ImpairedPacketConn{
+		PacketConn: pktConn,
+		imp:        imp,
+		rand:       rand.New(rand.NewSource(imp.Seed)),
+	}
+}
+
+// ListenImpaired listens on laddr and returns the ImpairedPacketConn with the
+// impairment given.
+func ListenImpaired(laddr net.Addr, imp Impairment) (*ImpairedPacketConn, error) {
+	pktConn, err := net.ListenPacket(laddr.Network(), laddr.String())
+	if err != nil {
+		return nil, err
+	}
+	return NewImpairedPacketConn(pktConn, imp), nil
+}
+
+// SetImpairment replaces the impairment. The pseudo-random number generator is
+// re-seeded with the Seed in imp.
+func (c *ImpairedPacketConn) SetImpairment(imp Impairment) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.imp = imp
+	c.rand = rand.New(rand.NewSource(imp.Seed))
+}
+
+// SetDropFunc registers the DropFunc to drop the specific packets deterministically,
+// which is evaluated before the Loss rate. Giving nil removes it.
+func (c *ImpairedPacketConn) SetDropFunc(fn DropFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.dropFn = fn
+}
+
+// Stats returns the number of the packets impaired so far.
+func (c *ImpairedPacketConn) Stats() ImpairmentStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// WriteTo writes a packet with payload p to addr with the impairment applied.
+//
+// It always returns len(p) without error for the packets dropped or delayed, as
+// the sender on the real network cannot know their fate either.
+func (c *ImpairedPacketConn) WriteTo(p []byte, addr net.Addr) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return c.PacketConn.WriteTo(p, addr)
+	}
+	c.stats.Written++
+
+	if fn := c.dropFn; fn != nil && fn(p, addr) {
+		c.stats.Dropped++
+		return len(p), nil
+	}
+	if c.hit(c.imp.Loss) {
+		c.stats.Dropped++
+		return len(p), nil
+	}
+
+	b := make([]byte, len(p))
+	copy(b, p)
+
+	copies := 1
+	if c.hit(c.imp.Duplicate) {
+		c.stats.Duplicated++
+		copies = 2
+	}
+
+	// the packet held previously goes after this one.
+	held := c.held
+	c.held = nil
+	if held != nil {
+		held.timer.Stop()
+	}
+
+	if held == nil && c.hit(c.imp.Reorder) {
+		c.stats.Reordered++
+		c.hold(b, addr, copies)
+		return len(p), nil
+	}
+
+	for i := 0; i < copies; i++ {
+		c.send(b, addr)
+	}
+	if held != nil {
+		c.send(held.p, held.addr)
+	}
+	return len(p), nil
+}
+
+// Close waits for the delayed packets to be sent and closes the connection.
+func (c *ImpairedPacketConn) Close() error {
+	c.mu.Lock()
+	c.closed = true
+	if held := c.held; held != nil {
+		held.timer.Stop()
+		c.held = nil
+	}
+	c.mu.Unlock()
+
+	c.pending.Wait()
+	return c.PacketConn.Close()
+}
+
+// hold keeps the packet to be sent after the next one, or after ReorderTimeout.
+// Must be called with c.mu held.
+func (c *ImpairedPacketConn) hold(p []byte, addr net.Addr, copies int) {
+	timeout := c.imp.ReorderTimeout
+	if timeout == 0 {
+		timeout = DefaultReorderTimeout
+	}
+
+	h := &heldPacket{p: p, addr: addr}
+	h.timer = time.AfterFunc(timeout, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.held != h {
+			return
+		}
+		c.held = nil
+		c.send(h.p, h.addr)
+	})
+	c.held = h
+
+	// the duplicate is not held, so that it arrives before the original one.
+	for i := 1; i < copies; i++ {
+		c.send(p, addr)
+	}
+}
+
+// send writes p to addr after the latency. Must be called with c.mu held.
+func (c *ImpairedPacketConn) send(p []byte, addr net.Addr) {
+	delay := c.imp.Latency
+	if c.imp.Jitter > 0 {
+		delay += time.Duration(c.rand.Int63n(int64(c.imp.Jitter)))
+	}
+	if delay == 0 {
+		_, _ = c.PacketConn.WriteTo(p, addr)
+		return
+	}
+
+	c.pending.Add(1)
+	time.AfterFunc(delay, func() {
+		defer c.pending.Done()
+		_, _ = c.PacketConn.WriteTo(p, addr)
+	})
+}
+
+// hit reports whether the event with rate happens. Must be called with c.mu held.
+func (c *ImpairedPacketConn) hit(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	return c.rand.Float64() < rate
+}
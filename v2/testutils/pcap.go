@@ -0,0 +1,175 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package testutils
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/wmnsk/go-gtp/internal/pcap"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// PcapDatagram is a UDP datagram read from pcap.
+type PcapDatagram struct {
+	Time     time.Time
+	Src, Dst *net.UDPAddr
+	Payload  []byte
+}
+
+// ReadPcap reads the UDP datagrams in the classic pcap format from r, whose source or
+// destination port is one of ports, or 2123 if no port is given.
+//
+// The link types supported are Ethernet (with 802.1Q tags), Raw IP and Linux cooked
+// capture. The packets that cannot be decoded are skipped.
+func ReadPcap(r io.Reader, ports ...int) ([]*PcapDatagram, error) {
+	if len(ports) == 0 {
+		ports = []int{2123}
+	}
+	match := func(port int) bool {
+		for _, p := range ports {
+			if p == port {
+				return true
+			}
+		}
+		return false
+	}
+
+	var datagrams []*PcapDatagram
+	err := pcap.ReadUDP(r, func(ts time.Time, src, dst *net.UDPAddr, payload []byte) {
+		if !match(src.Port) && !match(dst.Port) {
+			return
+		}
+		datagrams = append(datagrams, &PcapDatagram{Time: ts, Src: src, Dst: dst, Payload: payload})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return datagrams, nil
+}
+
+// LoadPcap reads the UDP datagrams from the pcap file at path. See ReadPcap for ports.
+func LoadPcap(path string, ports ...int) ([]*PcapDatagram, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadPcap(f, ports...)
+}
+
+// ReplayPcap decodes each GTPv2-C datagram in the pcap file at path with
+// messages.ParseMultiMessages and checks if the messages are marshaled back into the
+// same bytes, to build the regression tests from the real captures.
+//
+// The datagrams that fail are reported with t.Errorf with their index in the file,
+// and the messages are dumped with messages.Dump when the bytes differ. See ReadPcap
+// for ports.
+func ReplayPcap(t *testing.T, path string, ports ...int) {
+	t.Helper()
+
+	datagrams, err := LoadPcap(path, ports...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for n, d := range datagrams {
+		msgs, err := messages.ParseMultiMessages(d.Payload)
+		if err != nil {
+			t.Errorf("datagram #%d from %s: failed to parse: %v", n, d.Src, err)
+			continue
+		}
+
+		b, err := messages.MarshalMultiMessages(msgs...)
+		if err != nil {
+			t.Errorf("datagram #%d from %s: failed to marshal: %v", n, d.Src, err)
+			continue
+		}
+		if !bytes.Equal(b, d.Payload) {
+			var dump string
+			for _, m := range msgs {
+				dump += messages.Dump(m)
+			}
+			t.Errorf("datagram #%d from %s: marshaled bytes differ\n got: %x\nwant: %x\n%s", n, d.Src, b, d.Payload, dump)
+		}
+	}
+}
+
+// PcapWriter writes the UDP datagrams in the classic pcap format with Raw IP link type.
+// It is safe for concurrent use.
+type PcapWriter struct {
+	w *pcap.Writer
+}
+
+// NewPcapWriter writes the pcap header to w and returns the PcapWriter.
+func NewPcapWriter(w io.Writer) (*PcapWriter, error) {
+	pw, err := pcap.NewWriter(w)
+	if err != nil {
+		return nil, err
+	}
+	return &PcapWriter{w: pw}, nil
+}
+
+// WriteDatagram writes the UDP datagram sent from src to dst at ts, with the IP and
+// UDP headers built from the addresses. The addresses other than *net.UDPAddr are
+// written as the unspecified address.
+func (p *PcapWriter) WriteDatagram(ts time.Time, src, dst net.Addr, payload []byte) error {
+	return p.w.WriteUDP(ts, udpAddr(src), udpAddr(dst), payload)
+}
+
+func udpAddr(addr net.Addr) *net.UDPAddr {
+	if a, ok := addr.(*net.UDPAddr); ok && a != nil {
+		return a
+	}
+	return &net.UDPAddr{}
+}
+
+// RecordingPacketConn is a net.PacketConn that records the packets read and written
+// to pcap, which can be given to the Conn with v2.NewConn or v2.Serve.
+//
+// The recording is best-effort; the errors in writing pcap are ignored.
+type RecordingPacketConn struct {
+	net.PacketConn
+	w *PcapWriter
+}
+
+// NewRecordingPacketConn creates a new RecordingPacketConn over pktConn that records
+// the packets with w.
+func NewRecordingPacketConn(pktConn net.PacketConn, w *PcapWriter) *RecordingPacketConn {
+	return &RecordingPacketConn{PacketConn: pktConn, w: w}
+}
+
+// ListenRecording listens on laddr and returns the RecordingPacketConn that records
+// the packets with w.
+func ListenRecording(laddr net.Addr, w *PcapWriter) (*RecordingPacketConn, error) {
+	pktConn, err := net.ListenPacket(laddr.Network(), laddr.String())
+	if err != nil {
+		return nil, err
+	}
+	return NewRecordingPacketConn(pktConn, w), nil
+}
+
+// ReadFrom reads a packet and records it as sent from addr to the local address.
+func (c *RecordingPacketConn) ReadFrom(p []byte) (int, net.Addr, error) {
+	n, addr, err := c.PacketConn.ReadFrom(p)
+	if err == nil {
+		_ = c.w.WriteDatagram(time.Now(), addr, c.LocalAddr(), p[:n])
+	}
+	return n, addr, err
+}
+
+// WriteTo writes a packet to addr and records it as sent from the local address.
+func (c *RecordingPacketConn) WriteTo(p []byte, addr net.Addr) (int, error) {
+	n, err := c.PacketConn.WriteTo(p, addr)
+	if err == nil {
+		_ = c.w.WriteDatagram(time.Now(), c.LocalAddr(), addr, p[:n])
+	}
+	return n, err
+}
@@ -0,0 +1,280 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package testutils
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// DefaultPipeQueueSize is the number of the datagrams that can be queued in the
+// PipeConn not read yet. The ones written when it is full are dropped, as UDP does.
+const DefaultPipeQueueSize = 1024
+
+// default addresses of the PipeConns created by NewPipeConn.
+var (
+	defaultPipeAddr1 = &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 2123}
+	defaultPipeAddr2 = &net.UDPAddr{IP: net.IPv4(127, 0, 0, 2), Port: 2123}
+)
+
+var errPipeClosed = errors.New("use of closed pipe")
+
+// pipeTimeoutError is returned when the deadline of PipeConn is exceeded.
+type pipeTimeoutError struct{}
+
+func (pipeTimeoutError) Error() string   { return "i/o timeout" }
+func (pipeTimeoutError) Timeout() bool   { return true }
+func (pipeTimeoutError) Temporary() bool { return true }
+
+// PipeMessage is a datagram written to PipeConn.
+type PipeMessage struct {
+	From, To net.Addr
+	Raw      []byte
+	// Message is the GTPv2-C message parsed from Raw, which is nil if it cannot be
+	// parsed.
+	Message messages.Message
+	At      time.Time
+}
+
+type pipeDatagram struct {
+	raddr net.Addr
+	b     []byte
+}
+
+// PipeConn is a net.PacketConn connected to the other one in memory, which can be
+// given to v2.NewConn or v2.Serve to test the HandlerFuncs and the nodes without
+// binding UDP ports.
+//
+// The datagrams written to the address of the other end are delivered to it, and
+// the ones to the other addresses are discarded as if nobody listens on them. All
+// the datagrams written are kept to be examined with Sent and ExpectSent.
+type PipeConn struct {
+	laddr net.Addr
+	peer  *PipeConn
+	rx    chan *pipeDatagram
+
+	closeOnce sync.Once
+	done      chan struct{}
+
+	mu           sync.Mutex
+	readDeadline time.Time
+	wake         chan struct{}
+	sent         []*PipeMessage
+	sentCh       chan struct{}
+}
+
+// NewPipeConn creates a pair of PipeConns connected to each other, with the local
+// addresses of 127.0.0.1:2123 and 127.0.0.2:2123 respectively.
+func NewPipeConn() (*PipeConn, *PipeConn) {
+	return NewPipeConnWithAddrs(defaultPipeAddr1, defaultPipeAddr2)
+}
+
+// NewPipeConnWithAddrs creates a pair of PipeConns connected to each other, with the
+// local addresses given. The addresses should be *net.UDPAddr, as Conn expects.
+func NewPipeConnWithAddrs(addr1, addr2 net.Addr) (*PipeConn, *PipeConn) {
+	c1, c2 := newPipeConn(addr1), newPipeConn(addr2)
+	c1.peer, c2.peer = c2, c1
+	return c1, c2
+}
+
+func newPipeConn(laddr net.Addr) *PipeConn {
+	return &PipeConn{
+		laddr:  laddr,
+		rx:     make(chan *pipeDatagram, DefaultPipeQueueSize),
+		done:   make(chan struct{}),
+		wake:   make(chan struct{}),
+		sentCh: make(chan struct{}),
+	}
+}
+
+// ReadFrom reads a datagram written by the other end.
+func (c *PipeConn) ReadFrom(p []byte) (int, net.Addr, error) {
+	for {
+		c.mu.Lock()
+		deadline, wake := c.readDeadline, c.wake
+		c.mu.Unlock()
+
+		var timeout <-chan time.Time
+		if !deadline.IsZero() {
+			d := time.Until(deadline)
+			if d <= 0 {
+				return 0, nil, c.opError("read", pipeTimeoutError{})
+			}
+			timer := time.NewTimer(d)
+			defer timer.Stop()
+			timeout = timer.C
+		}
+
+		select {
+		case <-c.done:
+			return 0, nil, c.opError("read", errPipeClosed)
+		default:
+		}
+		select {
+		case dg := <-c.rx:
+			return copy(p, dg.b), dg.raddr, nil
+		case <-c.done:
+			return 0, nil, c.opError("read", errPipeClosed)
+		case <-timeout:
+			return 0, nil, c.opError("read", pipeTimeoutError{})
+		case <-wake:
+			// the deadline is changed.
+		}
+	}
+}
+
+// WriteTo writes a datagram with payload p to addr. It is delivered to the other end
+// if addr is its local address.
+func (c *PipeConn) WriteTo(p []byte, addr net.Addr) (int, error) {
+	select {
+	case <-c.done:
+		return 0, c.opError("write", errPipeClosed)
+	default:
+	}
+
+	b := make([]byte, len(p))
+	copy(b, p)
+	c.record(addr, b)
+
+	if addr == nil || addr.String() != c.peer.laddr.String() {
+		return len(p), nil
+	}
+	select {
+	case <-c.peer.done:
+	case c.peer.rx <- &pipeDatagram{raddr: c.laddr, b: b}:
+	default:
+		// the queue of the other end is full.
+	}
+	return len(p), nil
+}
+
+// WriteMessage serializes msg and writes it to the other end, which is useful to
+// inject the messages to the Conn over the other end.
+func (c *PipeConn) WriteMessage(msg messages.Message) error {
+	b, err := messages.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = c.WriteTo(b, c.peer.laddr)
+	return err
+}
+
+// Close closes PipeConn. The datagrams written to it afterwards are discarded.
+func (c *PipeConn) Close() error {
+	c.closeOnce.Do(func() { close(c.done) })
+	return nil
+}
+
+// LocalAddr returns the local address of PipeConn.
+func (c *PipeConn) LocalAddr() net.Addr {
+	return c.laddr
+}
+
+// RemoteAddr returns the local address of the other end.
+func (c *PipeConn) RemoteAddr() net.Addr {
+	return c.peer.laddr
+}
+
+// SetDeadline sets the read deadline, as writing never blocks.
+func (c *PipeConn) SetDeadline(t time.Time) error {
+	return c.SetReadDeadline(t)
+}
+
+// SetReadDeadline sets the deadline for ReadFrom. Zero value means no deadline.
+func (c *PipeConn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.readDeadline = t
+	close(c.wake)
+	c.wake = make(chan struct{})
+	return nil
+}
+
+// SetWriteDeadline does nothing, as writing never blocks.
+func (c *PipeConn) SetWriteDeadline(t time.Time) error {
+	return nil
+}
+
+// Sent returns the datagrams written to PipeConn so far in the order they are
+// written.
+func (c *PipeConn) Sent() []*PipeMessage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]*PipeMessage{}, c.sent...)
+}
+
+// WaitSent waits for the GTPv2-C message of msgType to be written to PipeConn until
+// timeout, and returns the first one including the ones written before it is called.
+// It returns nil if not found.
+func (c *PipeConn) WaitSent(msgType uint8, timeout time.Duration) *PipeMessage {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		c.mu.Lock()
+		sentCh := c.sentCh
+		for _, m := range c.sent {
+			if m.Message != nil && m.Message.MessageType() == msgType {
+				c.mu.Unlock()
+				return m
+			}
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-sentCh:
+		case <-timer.C:
+			return nil
+		}
+	}
+}
+
+func (c *PipeConn) record(addr net.Addr, b []byte) {
+	m := &PipeMessage{From: c.laddr, To: addr, Raw: b, At: time.Now()}
+	if msg, err := messages.Parse(b); err == nil {
+		m.Message = msg
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sent = append(c.sent, m)
+	close(c.sentCh)
+	c.sentCh = make(chan struct{})
+}
+
+func (c *PipeConn) opError(op string, err error) error {
+	return &net.OpError{Op: op, Net: "pipe", Addr: c.laddr, Err: err}
+}
+
+// ExpectSent fails t if the GTPv2-C message of msgType is not written to c within
+// timeout, and returns the message otherwise.
+func ExpectSent(t testing.TB, c *PipeConn, msgType uint8, timeout time.Duration) messages.Message {
+	t.Helper()
+
+	m := c.WaitSent(msgType, timeout)
+	if m == nil {
+		t.Fatalf("message type %d is not sent from %s within %s", msgType, c.LocalAddr(), timeout)
+		return nil
+	}
+	return m.Message
+}
+
+// ExpectNotSent fails t if the GTPv2-C message of msgType is written to c within
+// timeout.
+func ExpectNotSent(t testing.TB, c *PipeConn, msgType uint8, timeout time.Duration) {
+	t.Helper()
+
+	if m := c.WaitSent(msgType, timeout); m != nil {
+		t.Fatalf("message type %d is unexpectedly sent from %s to %s", msgType, m.From, m.To)
+	}
+}
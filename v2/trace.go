@@ -0,0 +1,39 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+
+	"github.com/wmnsk/go-gtp/v2/ies"
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// TraceSessionActivation sends a TraceSessionActivation with TEID and IEs given.
+//
+// This is used by MME or SGSN to activate the trace session for the UE in SGW and
+// PGW. Use ies.NewTraceInformation to give the Trace Information. No response is
+// defined for this message.
+func (c *Conn) TraceSessionActivation(teid uint32, raddr net.Addr, ie ...*ies.IE) (uint32, error) {
+	sess, err := c.GetSessionByTEID(teid, raddr)
+	if err != nil {
+		return 0, err
+	}
+
+	return c.SendMessageTo(messages.NewTraceSessionActivation(teid, 0, ie...), sess.PeerAddr())
+}
+
+// TraceSessionDeactivation sends a TraceSessionDeactivation with TEID and IEs given.
+//
+// Use ies.NewTraceReference with the Trace ID given in the Trace Session Activation
+// to deactivate the trace session. No response is defined for this message.
+func (c *Conn) TraceSessionDeactivation(teid uint32, raddr net.Addr, ie ...*ies.IE) (uint32, error) {
+	sess, err := c.GetSessionByTEID(teid, raddr)
+	if err != nil {
+		return 0, err
+	}
+
+	return c.SendMessageTo(messages.NewTraceSessionDeactivation(teid, 0, ie...), sess.PeerAddr())
+}
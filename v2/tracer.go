@@ -0,0 +1,80 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// TracerFunc is called for every datagram sent and received by Conn, with the raw
+// bytes on the wire and the message decoded from or encoded into them.
+//
+// raw is valid only until the function returns, as the buffer may be reused; copy it
+// to retain. msg is nil if the datagram received cannot be decoded. It must not be
+// modified, as it is the one being handled or sent.
+type TracerFunc func(dir Direction, raddr net.Addr, raw []byte, msg messages.Message)
+
+// SetTracer sets the TracerFunc, which can be used for the wire-level logging,
+// writing pcap, or mirroring the traffic without another socket. Giving nil removes
+// it.
+//
+// The datagram is traced once for each message if multiple messages are piggybacked
+// in it. The incoming datagram is traced after it is decoded and before it is
+// handled, and the outgoing datagram is traced after it is written successfully,
+// including the ones written with WriteTo and the cached responses sent again for
+// the retransmitted requests. The function is called synchronously, so it should not
+// block.
+func (c *Conn) SetTracer(fn TracerFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.tracerFunc = fn
+}
+
+func (c *Conn) tracer() TracerFunc {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.tracerFunc
+}
+
+// traceIncoming calls the TracerFunc with the messages decoded from raw, or with nil
+// if it cannot be decoded.
+func (c *Conn) traceIncoming(raddr net.Addr, raw []byte, msgs []messages.Message) {
+	fn := c.tracer()
+	if fn == nil {
+		return
+	}
+
+	if len(msgs) == 0 {
+		fn(DirectionIncoming, raddr, raw, nil)
+		return
+	}
+	for _, msg := range msgs {
+		fn(DirectionIncoming, raddr, raw, msg)
+	}
+}
+
+// traceOutgoing calls the TracerFunc with msg written as raw. If msg is not known,
+// e.g., written with WriteTo, or raw has the piggybacked message, raw is decoded to
+// get the messages.
+func (c *Conn) traceOutgoing(raddr net.Addr, raw []byte, msg messages.Message) {
+	fn := c.tracer()
+	if fn == nil {
+		return
+	}
+
+	if msg == nil || (len(raw) > 0 && raw[0]&0x10 != 0) {
+		if msgs, err := messages.ParseMultiMessages(raw); err == nil && len(msgs) > 0 {
+			for _, m := range msgs {
+				fn(DirectionOutgoing, raddr, raw, m)
+			}
+			return
+		}
+	}
+	fn(DirectionOutgoing, raddr, raw, msg)
+}
@@ -0,0 +1,150 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// DefaultTransactionTimeout is the default duration to wait for the deferred response
+// to be sent with Transaction.
+const DefaultTransactionTimeout = 3 * time.Second
+
+// Transaction is a handle to respond to a request out of the HandlerFunc.
+//
+// This is useful when the response depends on the result of something that takes
+// time, e.g., the query to HSS or PCRF. Call (*Conn) Defer in the HandlerFunc to
+// get a Transaction, return ErrPending from the HandlerFunc, and then call Respond
+// when the response is ready.
+//
+// The retransmissions of the request are discarded while the Transaction is pending.
+// When it is expired, the response sent late is suppressed and the retransmitted
+// request is handled again as a new one.
+type Transaction struct {
+	mu   sync.Mutex
+	conn *Conn
+
+	peer net.Addr
+	req  messages.Message
+
+	timer   *time.Timer
+	doneCh  chan struct{}
+	done    bool
+	expired bool
+
+	onExpire func(tx *Transaction)
+}
+
+// Defer creates a Transaction to respond to req from raddr later.
+//
+// The Transaction is expired if Respond is not called within timeout, and the
+// TransactionExpiredError is passed to the background error handling. Giving 0 or
+// negative value uses DefaultTransactionTimeout.
+func (c *Conn) Defer(raddr net.Addr, req messages.Message, timeout time.Duration) *Transaction {
+	if timeout <= 0 {
+		timeout = DefaultTransactionTimeout
+	}
+
+	tx := &Transaction{
+		conn:   c,
+		peer:   raddr,
+		req:    req,
+		doneCh: make(chan struct{}),
+	}
+
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	tx.timer = time.AfterFunc(timeout, tx.expire)
+
+	return tx
+}
+
+// Request returns the request to be responded with Transaction.
+func (t *Transaction) Request() messages.Message {
+	return t.req
+}
+
+// Peer returns the address of the peer that sent the request.
+func (t *Transaction) Peer() net.Addr {
+	return t.peer
+}
+
+// Done returns a channel that is closed when the response is sent or the
+// Transaction is expired.
+func (t *Transaction) Done() <-chan struct{} {
+	return t.doneCh
+}
+
+// Expired reports whether the Transaction is expired before the response is sent.
+func (t *Transaction) Expired() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.expired
+}
+
+// OnExpire registers fn to be called when the Transaction is expired, in addition to
+// passing TransactionExpiredError to the background error handling.
+func (t *Transaction) OnExpire(fn func(tx *Transaction)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.onExpire = fn
+}
+
+// Respond sends res in response to the request of Transaction.
+//
+// It can be called only once. It returns ErrTransactionCompleted if the response is
+// already sent, and TransactionExpiredError without sending res if the Transaction
+// is expired.
+func (t *Transaction) Respond(res messages.Message) error {
+	t.mu.Lock()
+	if t.expired {
+		t.mu.Unlock()
+		return t.expiredError()
+	}
+	if t.done {
+		t.mu.Unlock()
+		return ErrTransactionCompleted
+	}
+	t.done = true
+	t.timer.Stop()
+	t.mu.Unlock()
+
+	defer close(t.doneCh)
+	return t.conn.RespondTo(t.peer, t.req, res)
+}
+
+func (t *Transaction) expire() {
+	t.mu.Lock()
+	if t.done {
+		t.mu.Unlock()
+		return
+	}
+	t.done = true
+	t.expired = true
+	fn := t.onExpire
+	t.mu.Unlock()
+
+	close(t.doneCh)
+	t.conn.responseCache().forget(t.peer, t.req)
+
+	if fn != nil {
+		fn(t)
+	}
+	t.conn.notifyError(t.expiredError())
+}
+
+func (t *Transaction) expiredError() error {
+	return &TransactionExpiredError{
+		MsgType: t.req.MessageTypeName(),
+		Seq:     t.req.Sequence(),
+		Peer:    t.peer,
+	}
+}
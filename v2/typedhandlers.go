@@ -0,0 +1,797 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"net"
+
+	"github.com/wmnsk/go-gtp/v2/messages"
+)
+
+// This file provides the type-specific versions of AddHandler.
+//
+// The HandlerFuncs registered with them receive the concrete type of message, so
+// that the type assertion is not required in the handlers, and registering a
+// handler for the wrong type of message is detected at compile time.
+
+// HandleBearerResourceCommand registers fn as the handler for Bearer Resource Command.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleBearerResourceCommand(fn func(c *Conn, senderAddr net.Addr, msg *messages.BearerResourceCommand) error) {
+	c.AddHandler(messages.MsgTypeBearerResourceCommand, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.BearerResourceCommand)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleBearerResourceFailureIndication registers fn as the handler for Bearer Resource Failure Indication.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleBearerResourceFailureIndication(fn func(c *Conn, senderAddr net.Addr, msg *messages.BearerResourceFailureIndication) error) {
+	c.AddHandler(messages.MsgTypeBearerResourceFailureIndication, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.BearerResourceFailureIndication)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleChangeNotificationRequest registers fn as the handler for Change Notification Request.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleChangeNotificationRequest(fn func(c *Conn, senderAddr net.Addr, msg *messages.ChangeNotificationRequest) error) {
+	c.AddHandler(messages.MsgTypeChangeNotificationRequest, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.ChangeNotificationRequest)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleChangeNotificationResponse registers fn as the handler for Change Notification Response.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleChangeNotificationResponse(fn func(c *Conn, senderAddr net.Addr, msg *messages.ChangeNotificationResponse) error) {
+	c.AddHandler(messages.MsgTypeChangeNotificationResponse, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.ChangeNotificationResponse)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleContextAcknowledge registers fn as the handler for Context Acknowledge.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleContextAcknowledge(fn func(c *Conn, senderAddr net.Addr, msg *messages.ContextAcknowledge) error) {
+	c.AddHandler(messages.MsgTypeContextAcknowledge, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.ContextAcknowledge)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleContextRequest registers fn as the handler for Context Request.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleContextRequest(fn func(c *Conn, senderAddr net.Addr, msg *messages.ContextRequest) error) {
+	c.AddHandler(messages.MsgTypeContextRequest, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.ContextRequest)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleContextResponse registers fn as the handler for Context Response.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleContextResponse(fn func(c *Conn, senderAddr net.Addr, msg *messages.ContextResponse) error) {
+	c.AddHandler(messages.MsgTypeContextResponse, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.ContextResponse)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleCreateBearerRequest registers fn as the handler for Create Bearer Request.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleCreateBearerRequest(fn func(c *Conn, senderAddr net.Addr, msg *messages.CreateBearerRequest) error) {
+	c.AddHandler(messages.MsgTypeCreateBearerRequest, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.CreateBearerRequest)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleCreateBearerResponse registers fn as the handler for Create Bearer Response.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleCreateBearerResponse(fn func(c *Conn, senderAddr net.Addr, msg *messages.CreateBearerResponse) error) {
+	c.AddHandler(messages.MsgTypeCreateBearerResponse, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.CreateBearerResponse)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleCreateIndirectDataForwardingTunnelRequest registers fn as the handler for Create Indirect Data Forwarding Tunnel Request.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleCreateIndirectDataForwardingTunnelRequest(fn func(c *Conn, senderAddr net.Addr, msg *messages.CreateIndirectDataForwardingTunnelRequest) error) {
+	c.AddHandler(messages.MsgTypeCreateIndirectDataForwardingTunnelRequest, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.CreateIndirectDataForwardingTunnelRequest)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleCreateIndirectDataForwardingTunnelResponse registers fn as the handler for Create Indirect Data Forwarding Tunnel Response.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleCreateIndirectDataForwardingTunnelResponse(fn func(c *Conn, senderAddr net.Addr, msg *messages.CreateIndirectDataForwardingTunnelResponse) error) {
+	c.AddHandler(messages.MsgTypeCreateIndirectDataForwardingTunnelResponse, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.CreateIndirectDataForwardingTunnelResponse)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleCreateSessionRequest registers fn as the handler for Create Session Request.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleCreateSessionRequest(fn func(c *Conn, senderAddr net.Addr, msg *messages.CreateSessionRequest) error) {
+	c.AddHandler(messages.MsgTypeCreateSessionRequest, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.CreateSessionRequest)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleCreateSessionResponse registers fn as the handler for Create Session Response.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleCreateSessionResponse(fn func(c *Conn, senderAddr net.Addr, msg *messages.CreateSessionResponse) error) {
+	c.AddHandler(messages.MsgTypeCreateSessionResponse, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.CreateSessionResponse)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleDeleteBearerCommand registers fn as the handler for Delete Bearer Command.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleDeleteBearerCommand(fn func(c *Conn, senderAddr net.Addr, msg *messages.DeleteBearerCommand) error) {
+	c.AddHandler(messages.MsgTypeDeleteBearerCommand, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.DeleteBearerCommand)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleDeleteBearerFailureIndication registers fn as the handler for Delete Bearer Failure Indication.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleDeleteBearerFailureIndication(fn func(c *Conn, senderAddr net.Addr, msg *messages.DeleteBearerFailureIndication) error) {
+	c.AddHandler(messages.MsgTypeDeleteBearerFailureIndication, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.DeleteBearerFailureIndication)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleDeleteBearerRequest registers fn as the handler for Delete Bearer Request.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleDeleteBearerRequest(fn func(c *Conn, senderAddr net.Addr, msg *messages.DeleteBearerRequest) error) {
+	c.AddHandler(messages.MsgTypeDeleteBearerRequest, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.DeleteBearerRequest)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleDeleteBearerResponse registers fn as the handler for Delete Bearer Response.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleDeleteBearerResponse(fn func(c *Conn, senderAddr net.Addr, msg *messages.DeleteBearerResponse) error) {
+	c.AddHandler(messages.MsgTypeDeleteBearerResponse, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.DeleteBearerResponse)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleDeleteIndirectDataForwardingTunnelRequest registers fn as the handler for Delete Indirect Data Forwarding Tunnel Request.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleDeleteIndirectDataForwardingTunnelRequest(fn func(c *Conn, senderAddr net.Addr, msg *messages.DeleteIndirectDataForwardingTunnelRequest) error) {
+	c.AddHandler(messages.MsgTypeDeleteIndirectDataForwardingTunnelRequest, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.DeleteIndirectDataForwardingTunnelRequest)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleDeleteIndirectDataForwardingTunnelResponse registers fn as the handler for Delete Indirect Data Forwarding Tunnel Response.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleDeleteIndirectDataForwardingTunnelResponse(fn func(c *Conn, senderAddr net.Addr, msg *messages.DeleteIndirectDataForwardingTunnelResponse) error) {
+	c.AddHandler(messages.MsgTypeDeleteIndirectDataForwardingTunnelResponse, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.DeleteIndirectDataForwardingTunnelResponse)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleDeletePDNConnectionSetRequest registers fn as the handler for Delete PDN Connection Set Request.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleDeletePDNConnectionSetRequest(fn func(c *Conn, senderAddr net.Addr, msg *messages.DeletePDNConnectionSetRequest) error) {
+	c.AddHandler(messages.MsgTypeDeletePDNConnectionSetRequest, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.DeletePDNConnectionSetRequest)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleDeletePDNConnectionSetResponse registers fn as the handler for Delete PDN Connection Set Response.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleDeletePDNConnectionSetResponse(fn func(c *Conn, senderAddr net.Addr, msg *messages.DeletePDNConnectionSetResponse) error) {
+	c.AddHandler(messages.MsgTypeDeletePDNConnectionSetResponse, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.DeletePDNConnectionSetResponse)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleDeleteSessionRequest registers fn as the handler for Delete Session Request.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleDeleteSessionRequest(fn func(c *Conn, senderAddr net.Addr, msg *messages.DeleteSessionRequest) error) {
+	c.AddHandler(messages.MsgTypeDeleteSessionRequest, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.DeleteSessionRequest)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleDeleteSessionResponse registers fn as the handler for Delete Session Response.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleDeleteSessionResponse(fn func(c *Conn, senderAddr net.Addr, msg *messages.DeleteSessionResponse) error) {
+	c.AddHandler(messages.MsgTypeDeleteSessionResponse, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.DeleteSessionResponse)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleDownlinkDataNotification registers fn as the handler for Downlink Data Notification.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleDownlinkDataNotification(fn func(c *Conn, senderAddr net.Addr, msg *messages.DownlinkDataNotification) error) {
+	c.AddHandler(messages.MsgTypeDownlinkDataNotification, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.DownlinkDataNotification)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleDownlinkDataNotificationAcknowledge registers fn as the handler for Downlink Data Notification Acknowledge.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleDownlinkDataNotificationAcknowledge(fn func(c *Conn, senderAddr net.Addr, msg *messages.DownlinkDataNotificationAcknowledge) error) {
+	c.AddHandler(messages.MsgTypeDownlinkDataNotificationAcknowledge, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.DownlinkDataNotificationAcknowledge)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleDownlinkDataNotificationFailureIndication registers fn as the handler for Downlink Data Notification Failure Indication.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleDownlinkDataNotificationFailureIndication(fn func(c *Conn, senderAddr net.Addr, msg *messages.DownlinkDataNotificationFailureIndication) error) {
+	c.AddHandler(messages.MsgTypeDownlinkDataNotificationFailureIndication, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.DownlinkDataNotificationFailureIndication)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleEchoRequest registers fn as the handler for Echo Request.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleEchoRequest(fn func(c *Conn, senderAddr net.Addr, msg *messages.EchoRequest) error) {
+	c.AddHandler(messages.MsgTypeEchoRequest, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.EchoRequest)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleEchoResponse registers fn as the handler for Echo Response.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleEchoResponse(fn func(c *Conn, senderAddr net.Addr, msg *messages.EchoResponse) error) {
+	c.AddHandler(messages.MsgTypeEchoResponse, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.EchoResponse)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleForwardRelocationCompleteAcknowledge registers fn as the handler for Forward Relocation Complete Acknowledge.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleForwardRelocationCompleteAcknowledge(fn func(c *Conn, senderAddr net.Addr, msg *messages.ForwardRelocationCompleteAcknowledge) error) {
+	c.AddHandler(messages.MsgTypeForwardRelocationCompleteAcknowledge, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.ForwardRelocationCompleteAcknowledge)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleForwardRelocationCompleteNotification registers fn as the handler for Forward Relocation Complete Notification.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleForwardRelocationCompleteNotification(fn func(c *Conn, senderAddr net.Addr, msg *messages.ForwardRelocationCompleteNotification) error) {
+	c.AddHandler(messages.MsgTypeForwardRelocationCompleteNotification, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.ForwardRelocationCompleteNotification)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleForwardRelocationRequest registers fn as the handler for Forward Relocation Request.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleForwardRelocationRequest(fn func(c *Conn, senderAddr net.Addr, msg *messages.ForwardRelocationRequest) error) {
+	c.AddHandler(messages.MsgTypeForwardRelocationRequest, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.ForwardRelocationRequest)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleForwardRelocationResponse registers fn as the handler for Forward Relocation Response.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleForwardRelocationResponse(fn func(c *Conn, senderAddr net.Addr, msg *messages.ForwardRelocationResponse) error) {
+	c.AddHandler(messages.MsgTypeForwardRelocationResponse, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.ForwardRelocationResponse)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleIdentificationRequest registers fn as the handler for Identification Request.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleIdentificationRequest(fn func(c *Conn, senderAddr net.Addr, msg *messages.IdentificationRequest) error) {
+	c.AddHandler(messages.MsgTypeIdentificationRequest, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.IdentificationRequest)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleIdentificationResponse registers fn as the handler for Identification Response.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleIdentificationResponse(fn func(c *Conn, senderAddr net.Addr, msg *messages.IdentificationResponse) error) {
+	c.AddHandler(messages.MsgTypeIdentificationResponse, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.IdentificationResponse)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleMBMSSessionStartRequest registers fn as the handler for MBMS Session Start Request.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleMBMSSessionStartRequest(fn func(c *Conn, senderAddr net.Addr, msg *messages.MBMSSessionStartRequest) error) {
+	c.AddHandler(messages.MsgTypeMBMSSessionStartRequest, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.MBMSSessionStartRequest)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleMBMSSessionStartResponse registers fn as the handler for MBMS Session Start Response.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleMBMSSessionStartResponse(fn func(c *Conn, senderAddr net.Addr, msg *messages.MBMSSessionStartResponse) error) {
+	c.AddHandler(messages.MsgTypeMBMSSessionStartResponse, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.MBMSSessionStartResponse)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleMBMSSessionUpdateRequest registers fn as the handler for MBMS Session Update Request.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleMBMSSessionUpdateRequest(fn func(c *Conn, senderAddr net.Addr, msg *messages.MBMSSessionUpdateRequest) error) {
+	c.AddHandler(messages.MsgTypeMBMSSessionUpdateRequest, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.MBMSSessionUpdateRequest)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleMBMSSessionUpdateResponse registers fn as the handler for MBMS Session Update Response.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleMBMSSessionUpdateResponse(fn func(c *Conn, senderAddr net.Addr, msg *messages.MBMSSessionUpdateResponse) error) {
+	c.AddHandler(messages.MsgTypeMBMSSessionUpdateResponse, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.MBMSSessionUpdateResponse)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleMBMSSessionStopRequest registers fn as the handler for MBMS Session Stop Request.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleMBMSSessionStopRequest(fn func(c *Conn, senderAddr net.Addr, msg *messages.MBMSSessionStopRequest) error) {
+	c.AddHandler(messages.MsgTypeMBMSSessionStopRequest, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.MBMSSessionStopRequest)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleMBMSSessionStopResponse registers fn as the handler for MBMS Session Stop Response.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleMBMSSessionStopResponse(fn func(c *Conn, senderAddr net.Addr, msg *messages.MBMSSessionStopResponse) error) {
+	c.AddHandler(messages.MsgTypeMBMSSessionStopResponse, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.MBMSSessionStopResponse)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleModifyAccessBearersRequest registers fn as the handler for Modify Access Bearers Request.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleModifyAccessBearersRequest(fn func(c *Conn, senderAddr net.Addr, msg *messages.ModifyAccessBearersRequest) error) {
+	c.AddHandler(messages.MsgTypeModifyAccessBearersRequest, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.ModifyAccessBearersRequest)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleModifyAccessBearersResponse registers fn as the handler for Modify Access Bearers Response.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleModifyAccessBearersResponse(fn func(c *Conn, senderAddr net.Addr, msg *messages.ModifyAccessBearersResponse) error) {
+	c.AddHandler(messages.MsgTypeModifyAccessBearersResponse, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.ModifyAccessBearersResponse)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleModifyBearerCommand registers fn as the handler for Modify Bearer Command.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleModifyBearerCommand(fn func(c *Conn, senderAddr net.Addr, msg *messages.ModifyBearerCommand) error) {
+	c.AddHandler(messages.MsgTypeModifyBearerCommand, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.ModifyBearerCommand)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleModifyBearerFailureIndication registers fn as the handler for Modify Bearer Failure Indication.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleModifyBearerFailureIndication(fn func(c *Conn, senderAddr net.Addr, msg *messages.ModifyBearerFailureIndication) error) {
+	c.AddHandler(messages.MsgTypeModifyBearerFailureIndication, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.ModifyBearerFailureIndication)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleModifyBearerRequest registers fn as the handler for Modify Bearer Request.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleModifyBearerRequest(fn func(c *Conn, senderAddr net.Addr, msg *messages.ModifyBearerRequest) error) {
+	c.AddHandler(messages.MsgTypeModifyBearerRequest, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.ModifyBearerRequest)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleModifyBearerResponse registers fn as the handler for Modify Bearer Response.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleModifyBearerResponse(fn func(c *Conn, senderAddr net.Addr, msg *messages.ModifyBearerResponse) error) {
+	c.AddHandler(messages.MsgTypeModifyBearerResponse, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.ModifyBearerResponse)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandlePGWRestartNotification registers fn as the handler for PGW Restart Notification.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandlePGWRestartNotification(fn func(c *Conn, senderAddr net.Addr, msg *messages.PGWRestartNotification) error) {
+	c.AddHandler(messages.MsgTypePGWRestartNotification, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.PGWRestartNotification)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandlePGWRestartNotificationAcknowledge registers fn as the handler for PGW Restart Notification Acknowledge.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandlePGWRestartNotificationAcknowledge(fn func(c *Conn, senderAddr net.Addr, msg *messages.PGWRestartNotificationAcknowledge) error) {
+	c.AddHandler(messages.MsgTypePGWRestartNotificationAcknowledge, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.PGWRestartNotificationAcknowledge)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleReleaseAccessBearersRequest registers fn as the handler for Release Access Bearers Request.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleReleaseAccessBearersRequest(fn func(c *Conn, senderAddr net.Addr, msg *messages.ReleaseAccessBearersRequest) error) {
+	c.AddHandler(messages.MsgTypeReleaseAccessBearersRequest, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.ReleaseAccessBearersRequest)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleReleaseAccessBearersResponse registers fn as the handler for Release Access Bearers Response.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleReleaseAccessBearersResponse(fn func(c *Conn, senderAddr net.Addr, msg *messages.ReleaseAccessBearersResponse) error) {
+	c.AddHandler(messages.MsgTypeReleaseAccessBearersResponse, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.ReleaseAccessBearersResponse)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleResumeAcknowledge registers fn as the handler for Resume Acknowledge.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleResumeAcknowledge(fn func(c *Conn, senderAddr net.Addr, msg *messages.ResumeAcknowledge) error) {
+	c.AddHandler(messages.MsgTypeResumeAcknowledge, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.ResumeAcknowledge)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleResumeNotification registers fn as the handler for Resume Notification.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleResumeNotification(fn func(c *Conn, senderAddr net.Addr, msg *messages.ResumeNotification) error) {
+	c.AddHandler(messages.MsgTypeResumeNotification, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.ResumeNotification)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleStopPagingIndication registers fn as the handler for Stop Paging Indication.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleStopPagingIndication(fn func(c *Conn, senderAddr net.Addr, msg *messages.StopPagingIndication) error) {
+	c.AddHandler(messages.MsgTypeStopPagingIndication, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.StopPagingIndication)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleSuspendAcknowledge registers fn as the handler for Suspend Acknowledge.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleSuspendAcknowledge(fn func(c *Conn, senderAddr net.Addr, msg *messages.SuspendAcknowledge) error) {
+	c.AddHandler(messages.MsgTypeSuspendAcknowledge, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.SuspendAcknowledge)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleSuspendNotification registers fn as the handler for Suspend Notification.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleSuspendNotification(fn func(c *Conn, senderAddr net.Addr, msg *messages.SuspendNotification) error) {
+	c.AddHandler(messages.MsgTypeSuspendNotification, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.SuspendNotification)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleTraceSessionActivation registers fn as the handler for Trace Session Activation.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleTraceSessionActivation(fn func(c *Conn, senderAddr net.Addr, msg *messages.TraceSessionActivation) error) {
+	c.AddHandler(messages.MsgTypeTraceSessionActivation, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.TraceSessionActivation)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleTraceSessionDeactivation registers fn as the handler for Trace Session Deactivation.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleTraceSessionDeactivation(fn func(c *Conn, senderAddr net.Addr, msg *messages.TraceSessionDeactivation) error) {
+	c.AddHandler(messages.MsgTypeTraceSessionDeactivation, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.TraceSessionDeactivation)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleUpdateBearerRequest registers fn as the handler for Update Bearer Request.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleUpdateBearerRequest(fn func(c *Conn, senderAddr net.Addr, msg *messages.UpdateBearerRequest) error) {
+	c.AddHandler(messages.MsgTypeUpdateBearerRequest, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.UpdateBearerRequest)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleUpdateBearerResponse registers fn as the handler for Update Bearer Response.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleUpdateBearerResponse(fn func(c *Conn, senderAddr net.Addr, msg *messages.UpdateBearerResponse) error) {
+	c.AddHandler(messages.MsgTypeUpdateBearerResponse, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.UpdateBearerResponse)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
+
+// HandleVersionNotSupportedIndication registers fn as the handler for Version Not Supported Indication.
+//
+// See AddHandler for detailed usage.
+func (c *Conn) HandleVersionNotSupportedIndication(fn func(c *Conn, senderAddr net.Addr, msg *messages.VersionNotSupportedIndication) error) {
+	c.AddHandler(messages.MsgTypeVersionNotSupportedIndication, func(c *Conn, senderAddr net.Addr, msg messages.Message) error {
+		m, ok := msg.(*messages.VersionNotSupportedIndication)
+		if !ok {
+			return &UnexpectedTypeError{Msg: msg}
+		}
+		return fn(c, senderAddr, m)
+	})
+}
@@ -0,0 +1,168 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+// UEEvent is the event in the lifecycle of the UE on Conn.
+type UEEvent uint8
+
+// UEEvent definitions.
+const (
+	// UEEventRegistered is notified when the first Session of the UE is added.
+	UEEventRegistered UEEvent = iota
+	// UEEventSessionAdded is notified when a Session is added to the UE, including
+	// the first one and the one that replaces the existing one.
+	UEEventSessionAdded
+	// UEEventSessionRemoved is notified when a Session is removed from the UE,
+	// including the one replaced by the new one.
+	UEEventSessionRemoved
+	// UEEventDeregistered is notified when the last Session of the UE is removed.
+	UEEventDeregistered
+)
+
+// UEEventHandler is a function called on the UEEvent with the Session added or
+// removed. ue is the state after the event, which has no Sessions on
+// UEEventDeregistered.
+type UEEventHandler func(ue *UE, sess *Session, event UEEvent)
+
+// UE is the set of the Sessions on Conn that belong to the same subscriber, i.e.,
+// the PDN connections of the UE identified by IMSI.
+//
+// UE is a snapshot of the Sessions at the time it is retrieved, and it is not
+// updated by the Sessions added or removed afterwards.
+type UE struct {
+	IMSI     string
+	Sessions []*Session
+}
+
+// SessionByAPN returns the Session whose default Bearer has the APN given.
+func (u *UE) SessionByAPN(apn string) (*Session, error) {
+	for _, sess := range u.Sessions {
+		if br := sess.GetDefaultBearer(); br != nil && br.GetAPN() == apn {
+			return sess, nil
+		}
+	}
+	return nil, &InvalidSessionError{IMSI: u.IMSI}
+}
+
+// SessionByEBI returns the Session that has the Bearer with EBI given, which can be
+// either the default Bearer or the dedicated one.
+func (u *UE) SessionByEBI(ebi uint8) (*Session, error) {
+	for _, sess := range u.Sessions {
+		if _, err := sess.LookupBearerByEBI(ebi); err == nil {
+			return sess, nil
+		}
+	}
+	return nil, &BearerNotFoundError{IMSI: u.IMSI}
+}
+
+// Bearers returns all the Bearers in the Sessions of the UE.
+func (u *UE) Bearers() []*Bearer {
+	var brs []*Bearer
+	for _, sess := range u.Sessions {
+		brs = append(brs, sess.Bearers()...)
+	}
+	return brs
+}
+
+// BearerCount returns the number of Bearers in the Sessions of the UE.
+func (u *UE) BearerCount() int {
+	var count int
+	for _, sess := range u.Sessions {
+		count += sess.BearerCount()
+	}
+	return count
+}
+
+// GetUE returns the UE with the Sessions looked up by IMSI.
+func (c *Conn) GetUE(imsi string) (*UE, error) {
+	ue := c.ue(imsi)
+	if len(ue.Sessions) == 0 {
+		return nil, &UnknownIMSIError{IMSI: imsi}
+	}
+	return ue, nil
+}
+
+// UEs returns all the UEs on Conn, in the order the first Session of each UE is
+// added.
+func (c *Conn) UEs() []*UE {
+	var (
+		ues   []*UE
+		index = map[string]*UE{}
+	)
+	c.RangeSessions(func(sess *Session) bool {
+		imsi := sess.GetIMSI()
+		ue, ok := index[imsi]
+		if !ok {
+			ue = &UE{IMSI: imsi}
+			index[imsi] = ue
+			ues = append(ues, ue)
+		}
+		ue.Sessions = append(ue.Sessions, sess)
+		return true
+	})
+	return ues
+}
+
+// SetUEEventHandler registers the UEEventHandler to be notified of the Sessions
+// added to and removed from the UEs with AddSession, RemoveSession and
+// RemoveSessionByIMSI. Giving nil stops the notification.
+//
+// The handler is called synchronously in the goroutine that modified the Sessions,
+// so it should not block.
+func (c *Conn) SetUEEventHandler(fn UEEventHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ueEventFn = fn
+}
+
+func (c *Conn) ueEventHandler() UEEventHandler {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ueEventFn
+}
+
+func (c *Conn) ue(imsi string) *UE {
+	ue := &UE{IMSI: imsi}
+	c.RangeSessions(func(sess *Session) bool {
+		if sess.GetIMSI() == imsi {
+			ue.Sessions = append(ue.Sessions, sess)
+		}
+		return true
+	})
+	return ue
+}
+
+func (c *Conn) notifyUEEvent(imsi string, sess *Session, event UEEvent) {
+	fn := c.ueEventHandler()
+	if fn == nil {
+		return
+	}
+	fn(c.ue(imsi), sess, event)
+}
+
+// notifyRemoved notifies the removal of the Sessions of the UE, and the
+// deregistration of it if no Session is left.
+func (c *Conn) notifyRemoved(imsi string, removed []*Session) {
+	if len(removed) == 0 {
+		return
+	}
+	for _, sess := range removed {
+		c.sessionDeleted(sess)
+		c.notifyUEEvent(imsi, sess, UEEventSessionRemoved)
+	}
+	if len(c.ue(imsi).Sessions) == 0 {
+		c.notifyUEEvent(imsi, removed[len(removed)-1], UEEventDeregistered)
+	}
+}
+
+// isSamePDNConnection reports whether a and b are the Sessions of the same PDN
+// connection, which is identified by the EBI of the default Bearer.
+func isSamePDNConnection(a, b *Session) bool {
+	brA, brB := a.GetDefaultBearer(), b.GetDefaultBearer()
+	if brA == nil || brB == nil {
+		return brA == brB
+	}
+	return brA.GetEBI() == brB.GetEBI()
+}
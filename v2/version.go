@@ -0,0 +1,223 @@
+// Copyright 2019 go-gtp authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package v2
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+// DatagramHandlerFunc handles the raw datagram of the other GTP version than 2
+// received by Conn, registered with HandleVersion.
+type DatagramHandlerFunc func(c *Conn, senderAddr net.Addr, b []byte) error
+
+// DefaultVersionConnQueueSize is the number of the datagrams that can be queued in
+// the net.PacketConn returned by VersionConn not read yet.
+const DefaultVersionConnQueueSize = 128
+
+// HandleVersion registers fn to handle the datagrams with the version in the GTP
+// header, which are otherwise discarded as parse errors or responded to with Version
+// Not Supported Indication. This allows
+// GTPv1-C and GTPv2-C to be served on the same socket, as both of them use the port
+// 2123. Giving nil as fn removes the one registered.
+//
+// fn is called with the raw datagram before it is parsed as GTPv2-C, and thus none
+// of the validation, the Middlewares and Subscribe applies to it. The error returned
+// from fn is notified in the same way as the ones from HandlerFuncs. The version 2 is
+// always handled by Conn itself, and fn for it is ignored.
+func (c *Conn) HandleVersion(version uint8, fn DatagramHandlerFunc) {
+	var h *versionHandler
+	if fn != nil {
+		h = &versionHandler{fn: fn}
+	}
+	c.setVersionHandler(version, h)
+}
+
+// versionHandler wraps DatagramHandlerFunc to be identified by the pointer, as the
+// funcs cannot be compared.
+type versionHandler struct {
+	fn DatagramHandlerFunc
+}
+
+func (c *Conn) setVersionHandler(version uint8, h *versionHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if h == nil {
+		delete(c.versionHandlers, version)
+		return
+	}
+	if c.versionHandlers == nil {
+		c.versionHandlers = map[uint8]*versionHandler{}
+	}
+	c.versionHandlers[version] = h
+}
+
+// versionHandler returns the DatagramHandlerFunc for the version of b, or nil if b
+// is GTPv2-C or none is registered for it.
+func (c *Conn) versionHandler(b []byte) DatagramHandlerFunc {
+	if len(b) == 0 {
+		return nil
+	}
+	version := b[0] >> 5
+	if version == 2 {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if h, ok := c.versionHandlers[version]; ok {
+		return h.fn
+	}
+	return nil
+}
+
+// VersionConn returns the net.PacketConn that reads the datagrams with the version
+// in the GTP header received by Conn, and writes to the socket of Conn. It can be
+// given to v1.NewCPlaneConn to serve GTPv1-C with its handlers on the same socket as
+// Conn, e.g., to emulate both an SGSN and an MME in the interworking tests.
+//
+// It replaces the DatagramHandlerFunc registered with HandleVersion for the version,
+// and closing it removes the one it has registered without closing Conn. The
+// datagrams that come when DefaultVersionConnQueueSize of them are not read yet are
+// discarded and counted as Dropped in Stats.
+func (c *Conn) VersionConn(version uint8) net.PacketConn {
+	vc := &versionConn{
+		conn:    c,
+		version: version,
+		rx:      make(chan *datagram, DefaultVersionConnQueueSize),
+		done:    make(chan struct{}),
+		wake:    make(chan struct{}),
+	}
+	vc.handler = &versionHandler{fn: vc.handle}
+	c.setVersionHandler(version, vc.handler)
+	return vc
+}
+
+var errVersionConnClosed = errors.New("use of closed connection")
+
+// versionTimeoutError is returned when the deadline of versionConn is exceeded.
+type versionTimeoutError struct{}
+
+func (versionTimeoutError) Error() string   { return "i/o timeout" }
+func (versionTimeoutError) Timeout() bool   { return true }
+func (versionTimeoutError) Temporary() bool { return true }
+
+// versionConn is the net.PacketConn returned by VersionConn.
+type versionConn struct {
+	conn    *Conn
+	version uint8
+	handler *versionHandler
+	rx      chan *datagram
+
+	closeOnce sync.Once
+	done      chan struct{}
+
+	mu           sync.Mutex
+	readDeadline time.Time
+	wake         chan struct{}
+}
+
+// handle is the DatagramHandlerFunc that queues the datagram to be read.
+func (vc *versionConn) handle(c *Conn, senderAddr net.Addr, b []byte) error {
+	select {
+	case vc.rx <- &datagram{raddr: senderAddr, raw: b}:
+	default:
+		c.stats.drop()
+		c.log().Debug("datagram dropped", "peer", senderAddr.String(), "version", vc.version)
+	}
+	return nil
+}
+
+// ReadFrom reads a datagram of the version received by Conn.
+func (vc *versionConn) ReadFrom(p []byte) (int, net.Addr, error) {
+	for {
+		vc.mu.Lock()
+		deadline, wake := vc.readDeadline, vc.wake
+		vc.mu.Unlock()
+
+		var timeout <-chan time.Time
+		if !deadline.IsZero() {
+			d := time.Until(deadline)
+			if d <= 0 {
+				return 0, nil, vc.opError("read", versionTimeoutError{})
+			}
+			timer := time.NewTimer(d)
+			defer timer.Stop()
+			timeout = timer.C
+		}
+
+		select {
+		case dg := <-vc.rx:
+			return copy(p, dg.raw), dg.raddr, nil
+		case <-vc.done:
+			return 0, nil, vc.opError("read", errVersionConnClosed)
+		case <-vc.conn.closed():
+			return 0, nil, vc.opError("read", errVersionConnClosed)
+		case <-timeout:
+			return 0, nil, vc.opError("read", versionTimeoutError{})
+		case <-wake:
+			// the deadline is changed.
+		}
+	}
+}
+
+// WriteTo writes a datagram to addr from the socket of Conn.
+func (vc *versionConn) WriteTo(p []byte, addr net.Addr) (int, error) {
+	select {
+	case <-vc.done:
+		return 0, vc.opError("write", errVersionConnClosed)
+	default:
+	}
+	return vc.conn.WriteTo(p, addr)
+}
+
+// Close stops reading the datagrams of the version, leaving Conn open.
+func (vc *versionConn) Close() error {
+	vc.closeOnce.Do(func() {
+		close(vc.done)
+
+		c := vc.conn
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		// the handler may be replaced by another one after VersionConn.
+		if c.versionHandlers[vc.version] == vc.handler {
+			delete(c.versionHandlers, vc.version)
+		}
+	})
+	return nil
+}
+
+// LocalAddr returns the local address of Conn.
+func (vc *versionConn) LocalAddr() net.Addr {
+	return vc.conn.LocalAddr()
+}
+
+// SetDeadline sets the read deadline, as writing is done by Conn.
+func (vc *versionConn) SetDeadline(t time.Time) error {
+	return vc.SetReadDeadline(t)
+}
+
+// SetReadDeadline sets the deadline for ReadFrom. Zero value means no deadline.
+func (vc *versionConn) SetReadDeadline(t time.Time) error {
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
+
+	vc.readDeadline = t
+	close(vc.wake)
+	vc.wake = make(chan struct{})
+	return nil
+}
+
+// SetWriteDeadline does nothing, as writing is done by Conn.
+func (vc *versionConn) SetWriteDeadline(t time.Time) error {
+	return nil
+}
+
+func (vc *versionConn) opError(op string, err error) error {
+	return &net.OpError{Op: op, Net: "udp", Addr: vc.LocalAddr(), Err: err}
+}